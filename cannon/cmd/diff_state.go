@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/versions"
+)
+
+var ErrStatesDiffer = errors.New("states differ")
+
+func DiffState(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("expected exactly two state files")
+	}
+	var states [2]*versions.VersionedState
+	for i, path := range ctx.Args().Slice() {
+		state, err := versions.LoadStateFromFile(path)
+		if err != nil {
+			return fmt.Errorf("invalid input state (%v): %w", path, err)
+		}
+		states[i] = state
+	}
+	diffs := versions.Diff(states[0], states[1])
+	if len(diffs) == 0 {
+		fmt.Println("States are identical")
+		return nil
+	}
+	for _, diff := range diffs {
+		fmt.Println(diff)
+	}
+	return ErrStatesDiffer
+}
+
+var DiffStateCommand = &cli.Command{
+	Name:      "diff-state",
+	Usage:     "Compare two Cannon states",
+	ArgsUsage: "<state-a> <state-b>",
+	Description: "Compare two Cannon states and report the differing metadata and registers, and the first differing memory page. " +
+		"Exits with a non-zero status if the states differ.",
+	Action: DiffState,
+}
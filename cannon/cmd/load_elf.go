@@ -7,8 +7,10 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/riscv"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/singlethreaded"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/versions"
 	"github.com/ethereum-optimism/optimism/cannon/serialize"
@@ -19,13 +21,13 @@ import (
 var (
 	LoadELFVMTypeFlag = &cli.StringFlag{
 		Name:     "type",
-		Usage:    "VM type to create state for. Options are 'cannon' (default), 'cannon-mt'",
+		Usage:    "VM type to create state for. Options are 'cannon' (default), 'cannon-mt', or 'cannon-mt64' and 'rv64' when built with the cannon64 tag",
 		Value:    "cannon",
 		Required: false,
 	}
 	LoadELFPathFlag = &cli.PathFlag{
 		Name:      "path",
-		Usage:     "Path to 32-bit big-endian MIPS ELF file",
+		Usage:     "Path to 32-bit big-endian MIPS ELF file, or 64-bit big-endian MIPS or 64-bit RISC-V ELF file when built with the cannon64 tag",
 		TakesFile: true,
 		Required:  true,
 	}
@@ -41,6 +43,11 @@ var (
 		Value:    "meta.json",
 		Required: false,
 	}
+	LoadELFProgramHeaderAuxvFlag = &cli.BoolFlag{
+		Name:     "auxv-phdr",
+		Usage:    "Add the program headers and entry point to the auxiliary vector on the initial stack, as expected by C runtimes and static-PIE executables. Changes the initial stack layout.",
+		Required: false,
+	}
 )
 
 type VMType string
@@ -48,16 +55,33 @@ type VMType string
 var (
 	cannonVMType VMType = "cannon"
 	mtVMType     VMType = "cannon-mt"
+	mt64VMType   VMType = "cannon-mt64"
+	rv64VMType   VMType = "rv64"
 )
 
 func vmTypeFromString(ctx *cli.Context) (VMType, error) {
-	if vmTypeStr := ctx.String(LoadELFVMTypeFlag.Name); vmTypeStr == string(cannonVMType) {
-		return cannonVMType, nil
-	} else if vmTypeStr == string(mtVMType) {
-		return mtVMType, nil
-	} else {
+	vmTypeStr := ctx.String(LoadELFVMTypeFlag.Name)
+	var vmType VMType
+	switch vmTypeStr {
+	case string(cannonVMType):
+		vmType = cannonVMType
+	case string(mtVMType):
+		vmType = mtVMType
+	case string(mt64VMType):
+		vmType = mt64VMType
+	case string(rv64VMType):
+		vmType = rv64VMType
+	default:
 		return "", fmt.Errorf("unknown VM type %q", vmTypeStr)
 	}
+	is64 := vmType == mt64VMType || vmType == rv64VMType
+	if is64 && arch.IsMips32 {
+		return "", fmt.Errorf("VM type %q requires cannon to be built with the cannon64 tag", vmTypeStr)
+	}
+	if !is64 && !arch.IsMips32 {
+		return "", fmt.Errorf("VM type %q is not supported when cannon is built with the cannon64 tag", vmTypeStr)
+	}
+	return vmType, nil
 }
 
 func LoadELF(ctx *cli.Context) error {
@@ -66,16 +90,32 @@ func LoadELF(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to open ELF file %q: %w", elfPath, err)
 	}
-	if elfProgram.Machine != elf.EM_MIPS {
+	vmType, err := vmTypeFromString(ctx)
+	if err != nil {
+		return err
+	}
+	if vmType == rv64VMType {
+		if elfProgram.Machine != elf.EM_RISCV {
+			return fmt.Errorf("ELF is not RISC-V, but got %q", elfProgram.Machine.String())
+		}
+	} else if elfProgram.Machine != elf.EM_MIPS {
 		return fmt.Errorf("ELF is not big-endian MIPS R3000, but got %q", elfProgram.Machine.String())
 	}
 
+	var auxv []program.AuxEntry
+	if ctx.Bool(LoadELFProgramHeaderAuxvFlag.Name) {
+		auxv, err = program.ProgramHeaderAuxv(elfProgram)
+		if err != nil {
+			return fmt.Errorf("failed to create auxiliary vector: %w", err)
+		}
+	}
+
 	var createInitialState func(f *elf.File) (mipsevm.FPVMState, error)
 
-	var patcher = program.PatchStack
-	if vmType, err := vmTypeFromString(ctx); err != nil {
-		return err
-	} else if vmType == cannonVMType {
+	var patcher = func(state mipsevm.FPVMState) error {
+		return program.PatchStackAuxv(state, auxv)
+	}
+	if vmType == cannonVMType {
 		createInitialState = func(f *elf.File) (mipsevm.FPVMState, error) {
 			return program.LoadELF(f, singlethreaded.CreateInitialState)
 		}
@@ -84,12 +124,19 @@ func LoadELF(ctx *cli.Context) error {
 			if err != nil {
 				return err
 			}
-			return program.PatchStack(state)
+			return program.PatchStackAuxv(state, auxv)
 		}
-	} else if vmType == mtVMType {
+	} else if vmType == mtVMType || vmType == mt64VMType {
 		createInitialState = func(f *elf.File) (mipsevm.FPVMState, error) {
 			return program.LoadELF(f, multithreaded.CreateInitialState)
 		}
+	} else if vmType == rv64VMType {
+		createInitialState = func(f *elf.File) (mipsevm.FPVMState, error) {
+			return program.LoadELF(f, riscv.CreateInitialState)
+		}
+		patcher = func(state mipsevm.FPVMState) error {
+			return riscv.PatchStackAuxv(state, auxv)
+		}
 	} else {
 		return fmt.Errorf("invalid VM type: %q", vmType)
 	}
@@ -128,5 +175,6 @@ var LoadELFCommand = &cli.Command{
 		LoadELFPathFlag,
 		LoadELFOutFlag,
 		LoadELFMetaFlag,
+		LoadELFProgramHeaderAuxvFlag,
 	},
 }
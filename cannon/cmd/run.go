@@ -21,7 +21,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/riscv"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
 )
@@ -62,7 +64,7 @@ var (
 	}
 	RunSnapshotFmtFlag = &cli.StringFlag{
 		Name:     "snapshot-fmt",
-		Usage:    "format for snapshot output file names.",
+		Usage:    "format for snapshot output file names. Compact snapshots, which store unchanged memory pages only once, are written if the name ends in " + versions.CompactSnapshotSuffix,
 		Value:    "state-%d.json",
 		Required: false,
 	}
@@ -113,6 +115,36 @@ var (
 		TakesFile: true,
 		Required:  false,
 	}
+	RunProfileFlag = &cli.PathFlag{
+		Name:      "profile",
+		Usage:     "path to write a JSON report of the steps spent in each guest function and the syscalls made by the guest",
+		TakesFile: true,
+		Required:  false,
+	}
+	RunProfilePProfFlag = &cli.PathFlag{
+		Name:      "profile.pprof",
+		Usage:     "path to write a pprof profile of the steps and syscalls at each guest PC. Requires --meta.",
+		TakesFile: true,
+		Required:  false,
+	}
+	RunTraceAtFlag = &cli.Uint64Flag{
+		Name:     "trace-at",
+		Usage:    "step to trace execution around, recording the pc, instruction, changed registers and memory accesses of each step",
+		Required: false,
+	}
+	RunTraceWindowFlag = &cli.Uint64Flag{
+		Name:     "trace-window",
+		Usage:    "number of steps before and after --trace-at to trace",
+		Value:    100,
+		Required: false,
+	}
+	RunTraceOutFlag = &cli.PathFlag{
+		Name:      "trace-out",
+		Usage:     "path to write the JSON execution trace to. Use - to write to stdout.",
+		Value:     "-",
+		TakesFile: true,
+		Required:  false,
+	}
 
 	OutFilePerm = os.FileMode(0o755)
 )
@@ -128,7 +160,7 @@ type Proof struct {
 
 	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
 	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
-	OracleOffset uint32        `json:"oracle-offset,omitempty"`
+	OracleOffset arch.Word     `json:"oracle-offset,omitempty"`
 }
 
 type rawHint string
@@ -288,7 +320,7 @@ func Run(ctx *cli.Context) error {
 
 	stopAtAnyPreimage := false
 	var stopAtPreimageKeyPrefix []byte
-	stopAtPreimageOffset := uint32(0)
+	stopAtPreimageOffset := arch.Word(0)
 	if ctx.IsSet(RunStopAtPreimageFlag.Name) {
 		val := ctx.String(RunStopAtPreimageFlag.Name)
 		parts := strings.Split(val, "@")
@@ -297,11 +329,11 @@ func Run(ctx *cli.Context) error {
 		}
 		stopAtPreimageKeyPrefix = common.FromHex(parts[0])
 		if len(parts) == 2 {
-			x, err := strconv.ParseUint(parts[1], 10, 32)
+			x, err := strconv.ParseUint(parts[1], 10, arch.WordSize)
 			if err != nil {
 				return fmt.Errorf("invalid preimage offset: %w", err)
 			}
-			stopAtPreimageOffset = uint32(x)
+			stopAtPreimageOffset = arch.Word(x)
 		}
 	} else {
 		switch ctx.String(RunStopAtPreimageTypeFlag.Name) {
@@ -369,11 +401,28 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
+	profilePath := ctx.Path(RunProfileFlag.Name)
+	pprofPath := ctx.Path(RunProfilePProfFlag.Name)
+	var profiler *program.Profiler
+	if profilePath != "" || pprofPath != "" {
+		if pprofPath != "" && len(meta.Symbols) == 0 {
+			return errors.New("cannot write pprof profile without symbols from a metadata file")
+		}
+		profiler = program.NewProfiler(meta)
+	}
+	var tracer *program.Tracer
+	if ctx.IsSet(RunTraceAtFlag.Name) {
+		tracer = program.NewTracer(meta, ctx.Uint64(RunTraceAtFlag.Name), ctx.Uint64(RunTraceWindowFlag.Name))
+	}
+
 	state, err := versions.LoadStateFromFile(ctx.Path(RunInputFlag.Name))
 	if err != nil {
 		return fmt.Errorf("failed to load state: %w", err)
 	}
 	vm := state.CreateVM(l, po, outLog, errLog, meta)
+	if err := versions.ApplyAccelerationHooks(vm, state.Version); err != nil {
+		return fmt.Errorf("failed to apply acceleration hooks: %w", err)
+	}
 	debugProgram := ctx.Bool(RunDebugFlag.Name)
 	if debugProgram {
 		if metaPath := ctx.Path(RunMetaFlag.Name); metaPath == "" {
@@ -386,6 +435,23 @@ func Run(ctx *cli.Context) error {
 
 	proofFmt := ctx.String(RunProofFmtFlag.Name)
 	snapshotFmt := ctx.String(RunSnapshotFmtFlag.Name)
+	var compactSnapshots *versions.CompactSnapshotWriter
+	if versions.IsCompactSnapshot(snapshotFmt) {
+		compactSnapshots = versions.NewCompactSnapshotWriter()
+	}
+	// Snapshots are written in the background while execution continues, one at a time.
+	var snapshotDone chan error
+	waitForSnapshot := func() error {
+		if snapshotDone == nil {
+			return nil
+		}
+		err := <-snapshotDone
+		snapshotDone = nil
+		return err
+	}
+	defer func() {
+		_ = waitForSnapshot()
+	}()
 
 	stepFn := vm.Step
 	if po.cmd != nil {
@@ -408,8 +474,8 @@ func Run(ctx *cli.Context) error {
 			delta := time.Since(start)
 			l.Info("processing",
 				"step", step,
-				"pc", mipsevm.HexU32(state.GetPC()),
-				"insn", mipsevm.HexU32(state.GetMemory().GetMemory(state.GetPC())),
+				"pc", mipsevm.HexWord(state.GetPC()),
+				"insn", mipsevm.HexU32(instructionAt(state.FPVMState)),
 				"ips", float64(step-startStep)/(float64(delta)/float64(time.Second)),
 				"pages", state.GetMemory().PageCount(),
 				"mem", state.GetMemory().Usage(),
@@ -428,9 +494,29 @@ func Run(ctx *cli.Context) error {
 		}
 
 		if snapshotAt(state) {
-			if err := serialize.Write(fmt.Sprintf(snapshotFmt, step), state, OutFilePerm); err != nil {
+			if err := waitForSnapshot(); err != nil {
 				return fmt.Errorf("failed to write state snapshot: %w", err)
 			}
+			snapshot, err := state.Snapshot()
+			if err != nil {
+				return fmt.Errorf("failed to snapshot state: %w", err)
+			}
+			snapshotPath := fmt.Sprintf(snapshotFmt, step)
+			snapshotDone = make(chan error, 1)
+			go func(done chan<- error) {
+				if compactSnapshots != nil {
+					done <- compactSnapshots.Write(snapshotPath, snapshot)
+				} else {
+					done <- serialize.Write(snapshotPath, snapshot, OutFilePerm)
+				}
+			}(snapshotDone)
+		}
+
+		if profiler != nil {
+			profiler.Record(state.FPVMState)
+		}
+		if tracer != nil {
+			tracer.Before(state.FPVMState)
 		}
 
 		if proofAt(state) {
@@ -460,9 +546,12 @@ func Run(ctx *cli.Context) error {
 				return fmt.Errorf("failed at step %d (PC: %08x): %w", step, state.GetPC(), err)
 			}
 		}
+		if tracer != nil {
+			tracer.After(state.FPVMState)
+		}
 
 		lastPreimageKey, lastPreimageValue, lastPreimageOffset := vm.LastPreimage()
-		if lastPreimageOffset != ^uint32(0) {
+		if lastPreimageOffset != ^arch.Word(0) {
 			if stopAtAnyPreimage {
 				l.Info("Stopping at preimage read")
 				break
@@ -481,6 +570,9 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 	l.Info("Execution stopped", "exited", state.GetExited(), "code", state.GetExitCode())
+	if err := waitForSnapshot(); err != nil {
+		return fmt.Errorf("failed to write state snapshot: %w", err)
+	}
 	if debugProgram {
 		vm.Traceback()
 	}
@@ -493,9 +585,46 @@ func Run(ctx *cli.Context) error {
 			return fmt.Errorf("failed to write benchmark data: %w", err)
 		}
 	}
+	if profilePath != "" {
+		if err := jsonutil.WriteJSON(profiler.Report(), ioutil.ToStdOutOrFileOrNoop(profilePath, OutFilePerm)); err != nil {
+			return fmt.Errorf("failed to write profile report: %w", err)
+		}
+	}
+	if pprofPath != "" {
+		if err := writePProf(profiler, pprofPath); err != nil {
+			return fmt.Errorf("failed to write pprof profile: %w", err)
+		}
+	}
+	if tracer != nil {
+		if err := jsonutil.WriteJSON(tracer.Trace(), ioutil.ToStdOutOrFileOrNoop(ctx.Path(RunTraceOutFlag.Name), OutFilePerm)); err != nil {
+			return fmt.Errorf("failed to write execution trace: %w", err)
+		}
+	}
 	return nil
 }
 
+// instructionAt returns the raw instruction at the current PC, for logging.
+func instructionAt(state mipsevm.FPVMState) uint32 {
+	if s, ok := state.(*riscv.State); ok {
+		return s.InstructionAt(s.GetPC())
+	}
+	return state.GetMemory().GetUint32(state.GetPC())
+}
+
+func writePProf(profiler *program.Profiler, path string) error {
+	f, err := ioutil.NewAtomicWriter(path, OutFilePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Abort()
+	}()
+	if err := profiler.WritePProf(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 var RunCommand = &cli.Command{
 	Name:        "run",
 	Usage:       "Run VM step(s) and generate proof data to replicate onchain.",
@@ -517,5 +646,10 @@ var RunCommand = &cli.Command{
 		RunPProfCPU,
 		RunDebugFlag,
 		RunDebugInfoFlag,
+		RunProfileFlag,
+		RunProfilePProfFlag,
+		RunTraceAtFlag,
+		RunTraceWindowFlag,
+		RunTraceOutFlag,
 	},
 }
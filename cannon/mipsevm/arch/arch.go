@@ -0,0 +1,11 @@
+// Package arch defines the word size, memory layout and syscall ABI of the MIPS architecture the VM is built for.
+// MIPS32 is used by default. Building with the cannon64 tag selects MIPS64.
+package arch
+
+// UndefinedSysNr is the syscall number of syscalls that are not available on the selected architecture.
+const UndefinedSysNr = ^Word(0)
+
+// WordToBytes encodes a word in big-endian byte order.
+func WordToBytes(w Word) []byte {
+	return ByteOrderWord.AppendWord(make([]byte, 0, WordSizeBytes), w)
+}
@@ -3,16 +3,17 @@ package exec
 import (
 	"fmt"
 
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
 )
 
 type MemTracker interface {
-	TrackMemAccess(addr uint32)
+	TrackMemAccess(addr Word)
 }
 
 type MemoryTrackerImpl struct {
 	memory          *memory.Memory
-	lastMemAccess   uint32
+	lastMemAccess   Word
 	memProofEnabled bool
 	// proof of first unique memory access
 	memProof [memory.MEM_PROOF_SIZE]byte
@@ -24,9 +25,9 @@ func NewMemoryTracker(memory *memory.Memory) *MemoryTrackerImpl {
 	return &MemoryTrackerImpl{memory: memory}
 }
 
-func (m *MemoryTrackerImpl) TrackMemAccess(effAddr uint32) {
+func (m *MemoryTrackerImpl) TrackMemAccess(effAddr Word) {
 	if m.memProofEnabled && m.lastMemAccess != effAddr {
-		if m.lastMemAccess != ^uint32(0) {
+		if m.lastMemAccess != ^Word(0) {
 			panic(fmt.Errorf("unexpected different mem access at %08x, already have access at %08x buffered", effAddr, m.lastMemAccess))
 		}
 		m.lastMemAccess = effAddr
@@ -36,8 +37,8 @@ func (m *MemoryTrackerImpl) TrackMemAccess(effAddr uint32) {
 
 // TrackMemAccess2 creates a proof for a memory access following a call to TrackMemAccess
 // This is used to generate proofs for contiguous memory accesses within the same step
-func (m *MemoryTrackerImpl) TrackMemAccess2(effAddr uint32) {
-	if m.memProofEnabled && m.lastMemAccess+4 != effAddr {
+func (m *MemoryTrackerImpl) TrackMemAccess2(effAddr Word) {
+	if m.memProofEnabled && m.lastMemAccess+arch.WordSizeBytes != effAddr {
 		panic(fmt.Errorf("unexpected disjointed mem access at %08x, last memory access is at %08x buffered", effAddr, m.lastMemAccess))
 	}
 	m.lastMemAccess = effAddr
@@ -46,7 +47,7 @@ func (m *MemoryTrackerImpl) TrackMemAccess2(effAddr uint32) {
 
 func (m *MemoryTrackerImpl) Reset(enableProof bool) {
 	m.memProofEnabled = enableProof
-	m.lastMemAccess = ^uint32(0)
+	m.lastMemAccess = ^Word(0)
 }
 
 func (m *MemoryTrackerImpl) MemProof() [memory.MEM_PROOF_SIZE]byte {
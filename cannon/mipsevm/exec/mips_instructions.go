@@ -1,58 +1,71 @@
 package exec
 
 import (
+	"fmt"
+	"math/bits"
+
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
 )
 
+type Word = arch.Word
+
 const (
-	OpLoadLinked       = 0x30
-	OpStoreConditional = 0x38
+	OpLoadLinked         = 0x30
+	OpStoreConditional   = 0x38
+	OpLoadLinked64       = 0x34
+	OpStoreConditional64 = 0x3c
 )
 
-func GetInstructionDetails(pc uint32, memory *memory.Memory) (insn, opcode, fun uint32) {
-	insn = memory.GetMemory(pc)
+func GetInstructionDetails(pc Word, memory *memory.Memory) (insn, opcode, fun uint32) {
+	insn = memory.GetUint32(pc)
 	opcode = insn >> 26 // First 6-bits
 	fun = insn & 0x3f   // Last 6-bits
 
 	return insn, opcode, fun
 }
 
-func ExecMipsCoreStepLogic(cpu *mipsevm.CpuScalars, registers *[32]uint32, memory *memory.Memory, insn, opcode, fun uint32, memTracker MemTracker, stackTracker StackTracker) (memUpdated bool, memAddr uint32, err error) {
+func ExecMipsCoreStepLogic(cpu *mipsevm.CpuScalars, registers *[32]Word, memory *memory.Memory, insn, opcode, fun uint32, memTracker MemTracker, stackTracker StackTracker) (memUpdated bool, memAddr Word, err error) {
 	// j-type j/jal
 	if opcode == 2 || opcode == 3 {
-		linkReg := uint32(0)
+		linkReg := Word(0)
 		if opcode == 3 {
 			linkReg = 31
 		}
-		// Take top 4 bits of the next PC (its 256 MB region), and concatenate with the 26-bit offset
-		target := (cpu.NextPC & 0xF0000000) | ((insn & 0x03FFFFFF) << 2)
+		// Take the top bits of the next PC (its 256 MB region), and concatenate with the 26-bit offset
+		target := (cpu.NextPC & SignExtend(0xF0000000, 32)) | Word((insn&0x03FFFFFF)<<2)
 		stackTracker.PushStack(cpu.PC, target)
 		err = HandleJump(cpu, registers, linkReg, target)
 		return
 	}
 
 	// register fetch
-	rs := uint32(0) // source register 1 value
-	rt := uint32(0) // source register 2 / temp value
-	rtReg := (insn >> 16) & 0x1F
+	rs := Word(0) // source register 1 value
+	rt := Word(0) // source register 2 / temp value
+	rtReg := Word((insn >> 16) & 0x1F)
 
 	// R-type or I-type (stores rt)
 	rs = registers[(insn>>21)&0x1F]
 	rdReg := rtReg
-	if opcode == 0 || opcode == 0x1c {
+	if opcode == 0x27 || opcode == 0x1A || opcode == 0x1B { // 64-bit opcodes lwu, ldl, ldr
+		assertMips64(insn)
+		// store actual rt with lwu, ldl and ldr
+		rt = registers[rtReg]
+		rdReg = rtReg
+	} else if opcode == 0 || opcode == 0x1c {
 		// R-type (stores rd)
 		rt = registers[rtReg]
-		rdReg = (insn >> 11) & 0x1F
+		rdReg = Word((insn >> 11) & 0x1F)
 	} else if opcode < 0x20 {
 		// rt is SignExtImm
 		// don't sign extend for andi, ori, xori
 		if opcode == 0xC || opcode == 0xD || opcode == 0xe {
 			// ZeroExtImm
-			rt = insn & 0xFFFF
+			rt = Word(insn & 0xFFFF)
 		} else {
 			// SignExtImm
-			rt = SignExtend(insn&0xFFFF, 16)
+			rt = SignExtendImmediate(insn)
 		}
 	} else if opcode >= 0x28 || opcode == 0x22 || opcode == 0x26 {
 		// store rt value with store
@@ -67,17 +80,18 @@ func ExecMipsCoreStepLogic(cpu *mipsevm.CpuScalars, registers *[32]uint32, memor
 		return
 	}
 
-	storeAddr := uint32(0xFF_FF_FF_FF)
+	storeAddr := ^Word(0)
 	// memory fetch (all I-type)
 	// we do the load for stores also
-	mem := uint32(0)
-	if opcode >= 0x20 {
+	mem := Word(0)
+	if opcode >= 0x20 || opcode == 0x1A || opcode == 0x1B {
 		// M[R[rs]+SignExtImm]
-		rs += SignExtend(insn&0xFFFF, 16)
-		addr := rs & 0xFFFFFFFC
+		rs += SignExtendImmediate(insn)
+		addr := rs & arch.AddressMask
 		memTracker.TrackMemAccess(addr)
 		mem = memory.GetMemory(addr)
-		if opcode >= 0x28 {
+		// ld (0x37) is the only load with an opcode >= 0x28
+		if opcode >= 0x28 && opcode != 0x37 {
 			// store
 			storeAddr = addr
 			// store opcodes don't write back to a register
@@ -88,9 +102,14 @@ func ExecMipsCoreStepLogic(cpu *mipsevm.CpuScalars, registers *[32]uint32, memor
 	// ALU
 	val := ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem)
 
-	if opcode == 0 && fun >= 8 && fun < 0x1c {
+	// MIPS64 adds dmult, dmultu, ddiv and ddivu to the hi/lo functs
+	hiLoEnd := uint32(0x1c)
+	if !arch.IsMips32 {
+		hiLoEnd = 0x20
+	}
+	if opcode == 0 && fun >= 8 && fun < hiLoEnd {
 		if fun == 8 || fun == 9 { // jr/jalr
-			linkReg := uint32(0)
+			linkReg := Word(0)
 			if fun == 9 {
 				linkReg = rdReg
 				stackTracker.PushStack(cpu.PC, rs)
@@ -112,14 +131,14 @@ func ExecMipsCoreStepLogic(cpu *mipsevm.CpuScalars, registers *[32]uint32, memor
 
 		// lo and hi registers
 		// can write back
-		if fun >= 0x10 && fun < 0x1c {
+		if (fun >= 0x10 && fun < 0x14) || (fun >= 0x18 && fun < hiLoEnd) {
 			err = HandleHiLo(cpu, registers, fun, rs, rt, rdReg)
 			return
 		}
 	}
 
 	// write memory
-	if storeAddr != 0xFF_FF_FF_FF {
+	if storeAddr != ^Word(0) {
 		memTracker.TrackMemAccess(storeAddr)
 		memory.SetMemory(storeAddr, val)
 		memUpdated = true
@@ -131,12 +150,18 @@ func ExecMipsCoreStepLogic(cpu *mipsevm.CpuScalars, registers *[32]uint32, memor
 	return
 }
 
-func SignExtendImmediate(insn uint32) uint32 {
-	return SignExtend(insn&0xFFFF, 16)
+func SignExtendImmediate(insn uint32) Word {
+	return SignExtend(Word(insn&0xFFFF), 16)
 }
 
-func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
-	if opcode == 0 || (opcode >= 8 && opcode < 0xF) {
+func assertMips64(insn uint32) {
+	if arch.IsMips32 {
+		panic(fmt.Sprintf("invalid instruction: %x", insn))
+	}
+}
+
+func ExecuteMipsInstruction(insn, opcode, fun uint32, rs, rt, mem Word) Word {
+	if opcode == 0 || (opcode >= 8 && opcode < 0xF) || opcode == 0x18 || opcode == 0x19 {
 		// transform ArithLogI to SPECIAL
 		switch opcode {
 		case 8:
@@ -153,23 +178,28 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 			fun = 0x25 // ori
 		case 0xE:
 			fun = 0x26 // xori
+		case 0x18:
+			fun = 0x2C // daddi
+		case 0x19:
+			fun = 0x2D // daddiu
 		}
 
+		// 32-bit operations only use the lower 32 bits of the operands and sign-extend the result on MIPS64.
 		switch fun {
 		case 0x00: // sll
-			return rt << ((insn >> 6) & 0x1F)
+			return SignExtend((rt<<((insn>>6)&0x1F))&0xFFFFFFFF, 32)
 		case 0x02: // srl
-			return rt >> ((insn >> 6) & 0x1F)
+			return SignExtend((rt&0xFFFFFFFF)>>((insn>>6)&0x1F), 32)
 		case 0x03: // sra
-			shamt := (insn >> 6) & 0x1F
-			return SignExtend(rt>>shamt, 32-shamt)
+			shamt := Word((insn >> 6) & 0x1F)
+			return SignExtend((rt&0xFFFFFFFF)>>shamt, 32-shamt)
 		case 0x04: // sllv
-			return rt << (rs & 0x1F)
+			return SignExtend((rt<<(rs&0x1F))&0xFFFFFFFF, 32)
 		case 0x06: // srlv
-			return rt >> (rs & 0x1F)
+			return SignExtend((rt&0xFFFFFFFF)>>(rs&0x1F), 32)
 		case 0x07: // srav
 			shamt := rs & 0x1F
-			return SignExtend(rt>>shamt, 32-shamt)
+			return SignExtend((rt&0xFFFFFFFF)>>shamt, 32-shamt)
 		// functs in range [0x8, 0x1b] are handled specially by other functions
 		case 0x08: // jr
 			return rs
@@ -192,6 +222,15 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 			return rs
 		case 0x13: // mtlo
 			return rs
+		case 0x14: // dsllv
+			assertMips64(insn)
+			return rt << (rs & 0x3F)
+		case 0x16: // dsrlv
+			assertMips64(insn)
+			return rt >> (rs & 0x3F)
+		case 0x17: // dsrav
+			assertMips64(insn)
+			return Word(arch.SignedInteger(rt) >> (rs & 0x3F))
 		case 0x18: // mult
 			return rs
 		case 0x19: // multu
@@ -200,15 +239,27 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 			return rs
 		case 0x1b: // divu
 			return rs
+		case 0x1c: // dmult
+			assertMips64(insn)
+			return rs
+		case 0x1d: // dmultu
+			assertMips64(insn)
+			return rs
+		case 0x1e: // ddiv
+			assertMips64(insn)
+			return rs
+		case 0x1f: // ddivu
+			assertMips64(insn)
+			return rs
 		// The rest includes transformed R-type arith imm instructions
 		case 0x20: // add
-			return rs + rt
+			return SignExtend(Word(int32(rs)+int32(rt)), 32)
 		case 0x21: // addu
-			return rs + rt
+			return SignExtend(Word(uint32(rs)+uint32(rt)), 32)
 		case 0x22: // sub
-			return rs - rt
+			return SignExtend(Word(int32(rs)-int32(rt)), 32)
 		case 0x23: // subu
-			return rs - rt
+			return SignExtend(Word(uint32(rs)-uint32(rt)), 32)
 		case 0x24: // and
 			return rs & rt
 		case 0x25: // or
@@ -218,7 +269,7 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 		case 0x27: // nor
 			return ^(rs | rt)
 		case 0x2a: // slti
-			if int32(rs) < int32(rt) {
+			if arch.SignedInteger(rs) < arch.SignedInteger(rt) {
 				return 1
 			}
 			return 0
@@ -227,6 +278,36 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 				return 1
 			}
 			return 0
+		case 0x2c: // dadd
+			assertMips64(insn)
+			return rs + rt
+		case 0x2d: // daddu
+			assertMips64(insn)
+			return rs + rt
+		case 0x2e: // dsub
+			assertMips64(insn)
+			return rs - rt
+		case 0x2f: // dsubu
+			assertMips64(insn)
+			return rs - rt
+		case 0x38: // dsll
+			assertMips64(insn)
+			return rt << ((insn >> 6) & 0x1F)
+		case 0x3a: // dsrl
+			assertMips64(insn)
+			return rt >> ((insn >> 6) & 0x1F)
+		case 0x3b: // dsra
+			assertMips64(insn)
+			return Word(arch.SignedInteger(rt) >> ((insn >> 6) & 0x1F))
+		case 0x3c: // dsll32
+			assertMips64(insn)
+			return rt << (((insn >> 6) & 0x1F) + 32)
+		case 0x3e: // dsrl32
+			assertMips64(insn)
+			return rt >> (((insn >> 6) & 0x1F) + 32)
+		case 0x3f: // dsra32
+			assertMips64(insn)
+			return Word(arch.SignedInteger(rt) >> (((insn >> 6) & 0x1F) + 32))
 		default:
 			panic("invalid instruction")
 		}
@@ -236,55 +317,113 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 		case 0x1C:
 			switch fun {
 			case 0x2: // mul
-				return uint32(int32(rs) * int32(rt))
+				return SignExtend(Word(int32(rs)*int32(rt)), 32)
 			case 0x20, 0x21: // clz, clo
 				if fun == 0x20 {
 					rs = ^rs
 				}
-				i := uint32(0)
+				i := Word(0)
 				for ; rs&0x80000000 != 0; i++ {
 					rs <<= 1
 				}
 				return i
+			case 0x24, 0x25: // dclz, dclo
+				assertMips64(insn)
+				if fun == 0x24 {
+					rs = ^rs
+				}
+				return Word(bits.LeadingZeros64(^uint64(rs)))
 			}
 		case 0x0F: // lui
-			return rt << 16
+			return SignExtend(rt<<16, 32)
 		case 0x20: // lb
-			return SignExtend((mem>>(24-(rs&3)*8))&0xFF, 8)
+			msb := Word(arch.WordSize - 8) // 24 for MIPS32, 56 for MIPS64
+			return SignExtend((mem>>(msb-(rs&arch.ExtMask)*8))&0xFF, 8)
 		case 0x21: // lh
-			return SignExtend((mem>>(16-(rs&2)*8))&0xFFFF, 16)
+			msb := Word(arch.WordSize - 16) // 16 for MIPS32, 48 for MIPS64
+			return SignExtend((mem>>(msb-(rs&(arch.ExtMask-1))*8))&0xFFFF, 16)
 		case 0x22: // lwl
-			val := mem << ((rs & 3) * 8)
-			mask := uint32(0xFFFFFFFF) << ((rs & 3) * 8)
-			return (rt & ^mask) | val
+			// loads are constrained to the 4-byte word containing the address
+			w := SelectSubWord(rs, mem, 4, false)
+			val := w << ((rs & 3) * 8)
+			mask := Word(0xFFFFFFFF) << ((rs & 3) * 8)
+			return SignExtend(((rt & ^mask)|val)&0xFFFFFFFF, 32)
 		case 0x23: // lw
-			return mem
+			return SelectSubWord(rs, mem, 4, true)
 		case 0x24: // lbu
-			return (mem >> (24 - (rs&3)*8)) & 0xFF
+			msb := Word(arch.WordSize - 8)
+			return (mem >> (msb - (rs&arch.ExtMask)*8)) & 0xFF
 		case 0x25: //  lhu
-			return (mem >> (16 - (rs&2)*8)) & 0xFFFF
+			msb := Word(arch.WordSize - 16)
+			return (mem >> (msb - (rs&(arch.ExtMask-1))*8)) & 0xFFFF
 		case 0x26: //  lwr
-			val := mem >> (24 - (rs&3)*8)
-			mask := uint32(0xFFFFFFFF) >> (24 - (rs&3)*8)
-			return (rt & ^mask) | val
+			w := SelectSubWord(rs, mem, 4, false)
+			val := w >> (24 - (rs&3)*8)
+			mask := Word(0xFFFFFFFF) >> (24 - (rs&3)*8)
+			result := ((rt & 0xFFFFFFFF) & ^mask) | val
+			if arch.IsMips32 || rs&3 == 3 {
+				// bit 31 was loaded
+				return SignExtend(result, 32)
+			}
+			// the upper half of the register is left untouched on MIPS64 when bit 31 is not loaded
+			return (rt &^ 0xFFFFFFFF) | result
+		case 0x27: // lwu
+			assertMips64(insn)
+			return SelectSubWord(rs, mem, 4, false)
 		case 0x28: //  sb
-			val := (rt & 0xFF) << (24 - (rs&3)*8)
-			mask := 0xFFFFFFFF ^ uint32(0xFF<<(24-(rs&3)*8))
+			msb := Word(arch.WordSize - 8)
+			shift := msb - (rs&arch.ExtMask)*8
+			val := (rt & 0xFF) << shift
+			mask := ^(Word(0xFF) << shift)
 			return (mem & mask) | val
 		case 0x29: //  sh
-			val := (rt & 0xFFFF) << (16 - (rs&2)*8)
-			mask := 0xFFFFFFFF ^ uint32(0xFFFF<<(16-(rs&2)*8))
+			msb := Word(arch.WordSize - 16)
+			shift := msb - (rs&(arch.ExtMask-1))*8
+			val := (rt & 0xFFFF) << shift
+			mask := ^(Word(0xFFFF) << shift)
 			return (mem & mask) | val
 		case 0x2a: //  swl
-			val := rt >> ((rs & 3) * 8)
-			mask := uint32(0xFFFFFFFF) >> ((rs & 3) * 8)
-			return (mem & ^mask) | val
+			w := SelectSubWord(rs, mem, 4, false)
+			val := (rt & 0xFFFFFFFF) >> ((rs & 3) * 8)
+			mask := Word(0xFFFFFFFF) >> ((rs & 3) * 8)
+			return UpdateSubWord(rs, mem, 4, (w & ^mask)|val)
 		case 0x2b: //  sw
-			return rt
+			return UpdateSubWord(rs, mem, 4, rt)
 		case 0x2e: //  swr
+			w := SelectSubWord(rs, mem, 4, false)
 			val := rt << (24 - (rs&3)*8)
-			mask := uint32(0xFFFFFFFF) << (24 - (rs&3)*8)
-			return (mem & ^mask) | val
+			mask := Word(0xFFFFFFFF) << (24 - (rs&3)*8)
+			return UpdateSubWord(rs, mem, 4, (w & ^mask)|(val&0xFFFFFFFF))
+		case 0x1A: // ldl
+			assertMips64(insn)
+			sl := (rs & 0x7) << 3
+			val := mem << sl
+			mask := ^Word(0) << sl
+			return val | (rt & ^mask)
+		case 0x1B: // ldr
+			assertMips64(insn)
+			sr := 56 - ((rs & 0x7) << 3)
+			val := mem >> sr
+			mask := ^Word(0) >> sr
+			return val | (rt & ^mask)
+		case 0x2c: // sdl
+			assertMips64(insn)
+			sr := (rs & 0x7) << 3
+			val := rt >> sr
+			mask := ^Word(0) >> sr
+			return val | (mem & ^mask)
+		case 0x2d: // sdr
+			assertMips64(insn)
+			sl := 56 - ((rs & 0x7) << 3)
+			val := rt << sl
+			mask := ^Word(0) << sl
+			return val | (mem & ^mask)
+		case 0x37: // ld
+			assertMips64(insn)
+			return mem
+		case 0x3f: // sd
+			assertMips64(insn)
+			return rt
 		default:
 			panic("invalid instruction")
 		}
@@ -292,10 +431,10 @@ func ExecuteMipsInstruction(insn, opcode, fun, rs, rt, mem uint32) uint32 {
 	panic("invalid instruction")
 }
 
-func SignExtend(dat uint32, idx uint32) uint32 {
-	isSigned := (dat >> (idx - 1)) != 0
-	signed := ((uint32(1) << (32 - idx)) - 1) << idx
-	mask := (uint32(1) << idx) - 1
+func SignExtend(dat Word, idx Word) Word {
+	isSigned := (dat>>(idx-1))&1 != 0
+	signed := ((Word(1) << (arch.WordSize - idx)) - 1) << idx
+	mask := (Word(1) << idx) - 1
 	if isSigned {
 		return dat&mask | signed
 	} else {
@@ -303,7 +442,32 @@ func SignExtend(dat uint32, idx uint32) uint32 {
 	}
 }
 
-func HandleBranch(cpu *mipsevm.CpuScalars, registers *[32]uint32, opcode uint32, insn uint32, rtReg uint32, rs uint32) error {
+// SelectSubWord returns the byteLength bytes of memWord that contain addr, optionally sign-extended.
+func SelectSubWord(addr Word, memWord Word, byteLength Word, signExtend bool) Word {
+	dataMask, bitOffset, bitLength := calculateSubWordMaskAndOffset(addr, byteLength)
+	val := (memWord >> bitOffset) & dataMask
+	if signExtend {
+		val = SignExtend(val, bitLength)
+	}
+	return val
+}
+
+// UpdateSubWord returns memWord with the byteLength bytes containing addr replaced by the low bytes of value.
+func UpdateSubWord(addr Word, memWord Word, byteLength Word, value Word) Word {
+	dataMask, bitOffset, _ := calculateSubWordMaskAndOffset(addr, byteLength)
+	return (value&dataMask)<<bitOffset | memWord&^(dataMask<<bitOffset)
+}
+
+func calculateSubWordMaskAndOffset(addr Word, byteLength Word) (dataMask, bitOffset, bitLength Word) {
+	bitLength = byteLength << 3
+	dataMask = ^Word(0) >> (arch.WordSize - bitLength)
+	// memory is big-endian, so the sub-word at the lowest address is in the most significant bits
+	byteIndex := addr & arch.ExtMask & ^(byteLength - 1)
+	bitOffset = (arch.WordSizeBytes - byteLength - byteIndex) << 3
+	return dataMask, bitOffset, bitLength
+}
+
+func HandleBranch(cpu *mipsevm.CpuScalars, registers *[32]Word, opcode uint32, insn uint32, rtReg Word, rs Word) error {
 	if cpu.NextPC != cpu.PC+4 {
 		panic("branch in delay slot")
 	}
@@ -313,32 +477,32 @@ func HandleBranch(cpu *mipsevm.CpuScalars, registers *[32]uint32, opcode uint32,
 		rt := registers[rtReg]
 		shouldBranch = (rs == rt && opcode == 4) || (rs != rt && opcode == 5)
 	} else if opcode == 6 {
-		shouldBranch = int32(rs) <= 0 // blez
+		shouldBranch = arch.SignedInteger(rs) <= 0 // blez
 	} else if opcode == 7 {
-		shouldBranch = int32(rs) > 0 // bgtz
+		shouldBranch = arch.SignedInteger(rs) > 0 // bgtz
 	} else if opcode == 1 {
 		// regimm
 		rtv := (insn >> 16) & 0x1F
 		if rtv == 0 { // bltz
-			shouldBranch = int32(rs) < 0
+			shouldBranch = arch.SignedInteger(rs) < 0
 		}
 		if rtv == 1 { // bgez
-			shouldBranch = int32(rs) >= 0
+			shouldBranch = arch.SignedInteger(rs) >= 0
 		}
 	}
 
 	prevPC := cpu.PC
 	cpu.PC = cpu.NextPC // execute the delay slot first
 	if shouldBranch {
-		cpu.NextPC = prevPC + 4 + (SignExtend(insn&0xFFFF, 16) << 2) // then continue with the instruction the branch jumps to.
+		cpu.NextPC = prevPC + 4 + (SignExtendImmediate(insn) << 2) // then continue with the instruction the branch jumps to.
 	} else {
 		cpu.NextPC = cpu.NextPC + 4 // branch not taken
 	}
 	return nil
 }
 
-func HandleHiLo(cpu *mipsevm.CpuScalars, registers *[32]uint32, fun uint32, rs uint32, rt uint32, storeReg uint32) error {
-	val := uint32(0)
+func HandleHiLo(cpu *mipsevm.CpuScalars, registers *[32]Word, fun uint32, rs Word, rt Word, storeReg Word) error {
+	val := Word(0)
 	switch fun {
 	case 0x10: // mfhi
 		val = cpu.HI
@@ -350,16 +514,37 @@ func HandleHiLo(cpu *mipsevm.CpuScalars, registers *[32]uint32, fun uint32, rs u
 		cpu.LO = rs
 	case 0x18: // mult
 		acc := uint64(int64(int32(rs)) * int64(int32(rt)))
-		cpu.HI = uint32(acc >> 32)
-		cpu.LO = uint32(acc)
+		cpu.HI = SignExtend(Word(acc>>32), 32)
+		cpu.LO = SignExtend(Word(uint32(acc)), 32)
 	case 0x19: // multu
-		acc := uint64(uint64(rs) * uint64(rt))
-		cpu.HI = uint32(acc >> 32)
-		cpu.LO = uint32(acc)
+		acc := uint64(uint32(rs)) * uint64(uint32(rt))
+		cpu.HI = SignExtend(Word(acc>>32), 32)
+		cpu.LO = SignExtend(Word(uint32(acc)), 32)
 	case 0x1a: // div
-		cpu.HI = uint32(int32(rs) % int32(rt))
-		cpu.LO = uint32(int32(rs) / int32(rt))
+		cpu.HI = SignExtend(Word(int32(rs)%int32(rt)), 32)
+		cpu.LO = SignExtend(Word(int32(rs)/int32(rt)), 32)
 	case 0x1b: // divu
+		cpu.HI = SignExtend(Word(uint32(rs)%uint32(rt)), 32)
+		cpu.LO = SignExtend(Word(uint32(rs)/uint32(rt)), 32)
+	case 0x1c: // dmult
+		hi, lo := bits.Mul64(uint64(rs), uint64(rt))
+		// convert the unsigned high word to the signed product
+		if int64(rs) < 0 {
+			hi -= uint64(rt)
+		}
+		if int64(rt) < 0 {
+			hi -= uint64(rs)
+		}
+		cpu.HI = Word(hi)
+		cpu.LO = Word(lo)
+	case 0x1d: // dmultu
+		hi, lo := bits.Mul64(uint64(rs), uint64(rt))
+		cpu.HI = Word(hi)
+		cpu.LO = Word(lo)
+	case 0x1e: // ddiv
+		cpu.HI = Word(arch.SignedInteger(rs) % arch.SignedInteger(rt))
+		cpu.LO = Word(arch.SignedInteger(rs) / arch.SignedInteger(rt))
+	case 0x1f: // ddivu
 		cpu.HI = rs % rt
 		cpu.LO = rs / rt
 	}
@@ -373,7 +558,7 @@ func HandleHiLo(cpu *mipsevm.CpuScalars, registers *[32]uint32, fun uint32, rs u
 	return nil
 }
 
-func HandleJump(cpu *mipsevm.CpuScalars, registers *[32]uint32, linkReg uint32, dest uint32) error {
+func HandleJump(cpu *mipsevm.CpuScalars, registers *[32]Word, linkReg Word, dest Word) error {
 	if cpu.NextPC != cpu.PC+4 {
 		panic("jump in delay slot")
 	}
@@ -386,7 +571,7 @@ func HandleJump(cpu *mipsevm.CpuScalars, registers *[32]uint32, linkReg uint32,
 	return nil
 }
 
-func HandleRd(cpu *mipsevm.CpuScalars, registers *[32]uint32, storeReg uint32, val uint32, conditional bool) error {
+func HandleRd(cpu *mipsevm.CpuScalars, registers *[32]Word, storeReg Word, val Word, conditional bool) error {
 	if storeReg >= 32 {
 		panic("invalid register")
 	}
@@ -0,0 +1,82 @@
+//go:build cannon64
+// +build cannon64
+
+package exec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteMipsInstruction64(t *testing.T) {
+	rType := func(fun uint32, shamt uint32) uint32 {
+		return shamt<<6 | fun
+	}
+	cases := []struct {
+		name   string
+		opcode uint32
+		insn   uint32
+		rs     Word
+		rt     Word
+		mem    Word
+		expect Word
+	}{
+		{name: "addu sign-extends", insn: rType(0x21, 0), rs: 0x7FFF_FFFF, rt: 1, expect: 0xFFFF_FFFF_8000_0000},
+		{name: "sll sign-extends", insn: rType(0x00, 4), rt: 0x0800_0000, expect: 0xFFFF_FFFF_8000_0000},
+		{name: "daddu", insn: rType(0x2d, 0), rs: 0x7FFF_FFFF, rt: 1, expect: 0x8000_0000},
+		{name: "dsubu", insn: rType(0x2f, 0), rs: 0, rt: 1, expect: 0xFFFF_FFFF_FFFF_FFFF},
+		{name: "dsll", insn: rType(0x38, 8), rt: 0x1122_3344_5566_7788, expect: 0x2233_4455_6677_8800},
+		{name: "dsll32", insn: rType(0x3c, 4), rt: 0x1234_5678, expect: 0x2345_6780_0000_0000},
+		{name: "dsrl32", insn: rType(0x3e, 0), rt: 0x8000_0000_0000_0000, expect: 0x8000_0000},
+		{name: "dsra32", insn: rType(0x3f, 0), rt: 0x8000_0000_0000_0000, expect: 0xFFFF_FFFF_8000_0000},
+		{name: "dsrav", insn: rType(0x17, 0), rs: 68, rt: 0x8000_0000_0000_0000, expect: 0xF800_0000_0000_0000},
+		{name: "daddiu", opcode: 0x19, rs: 10, rt: SignExtendImmediate(0xFFFF), expect: 9},
+		{name: "dclz", opcode: 0x1C, insn: 0x24, rs: 0x0000_0001_0000_0000, expect: 31},
+		{name: "dclo", opcode: 0x1C, insn: 0x25, rs: 0xFFFF_0000_0000_0000, expect: 16},
+		{name: "lw sign-extends", opcode: 0x23, rs: 0x1004, mem: 0x1111_1111_8000_0001, expect: 0xFFFF_FFFF_8000_0001},
+		{name: "lwu", opcode: 0x27, rs: 0x1004, mem: 0x1111_1111_8000_0001, expect: 0x8000_0001},
+		{name: "lb", opcode: 0x20, rs: 0x1006, mem: 0x1122_3344_5566_8788, expect: 0xFFFF_FFFF_FFFF_FF87},
+		{name: "ld", opcode: 0x37, rs: 0x1000, mem: 0x1122_3344_5566_7788, expect: 0x1122_3344_5566_7788},
+		{name: "sd", opcode: 0x3f, rs: 0x1000, rt: 0xAABB_CCDD_EEFF_0011, mem: 0x1122_3344_5566_7788, expect: 0xAABB_CCDD_EEFF_0011},
+		{name: "sw", opcode: 0x2b, rs: 0x1004, rt: 0xAABB_CCDD, mem: 0x1122_3344_5566_7788, expect: 0x1122_3344_AABB_CCDD},
+		{name: "ldl", opcode: 0x1A, rs: 0x1003, rt: 0xAAAA_AAAA_AAAA_AAAA, mem: 0x1122_3344_5566_7788, expect: 0x4455_6677_88AA_AAAA},
+		{name: "ldr", opcode: 0x1B, rs: 0x1003, rt: 0xAAAA_AAAA_AAAA_AAAA, mem: 0x1122_3344_5566_7788, expect: 0xAAAA_AAAA_1122_3344},
+		{name: "sdl", opcode: 0x2c, rs: 0x1003, rt: 0xAABB_CCDD_EEFF_0011, mem: 0x1122_3344_5566_7788, expect: 0x1122_33AA_BBCC_DDEE},
+		{name: "sdr", opcode: 0x2d, rs: 0x1003, rt: 0xAABB_CCDD_EEFF_0011, mem: 0x1122_3344_5566_7788, expect: 0xEEFF_0011_5566_7788},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			insn := c.opcode<<26 | c.insn
+			res := ExecuteMipsInstruction(insn, c.opcode, insn&0x3F, c.rs, c.rt, c.mem)
+			require.Equal(t, c.expect, res, "expected %x, got %x", c.expect, res)
+		})
+	}
+}
+
+func TestSubWord64(t *testing.T) {
+	const memWord = Word(0x1122_3344_5566_7788)
+	cases := []struct {
+		addr       Word
+		byteLength Word
+		expect     Word
+	}{
+		{addr: 0x1000, byteLength: 8, expect: 0x1122_3344_5566_7788},
+		{addr: 0x1000, byteLength: 4, expect: 0x1122_3344},
+		{addr: 0x1006, byteLength: 4, expect: 0x5566_7788},
+		{addr: 0x1002, byteLength: 2, expect: 0x3344},
+		{addr: 0x1007, byteLength: 1, expect: 0x88},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("addr=%x,len=%d", c.addr, c.byteLength), func(t *testing.T) {
+			require.Equal(t, c.expect, SelectSubWord(c.addr, memWord, c.byteLength, false))
+
+			updated := UpdateSubWord(c.addr, memWord, c.byteLength, 0)
+			require.Equal(t, Word(0), SelectSubWord(c.addr, updated, c.byteLength, false))
+			require.Equal(t, memWord, UpdateSubWord(c.addr, updated, c.byteLength, c.expect))
+		})
+	}
+
+	require.Equal(t, Word(0xFFFF_FFFF_8000_0000), SelectSubWord(0x1000, 0x8000_0000_0000_0000, 4, true))
+}
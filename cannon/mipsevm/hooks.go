@@ -0,0 +1,85 @@
+package mipsevm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+)
+
+var ErrHookNotProvable = errors.New("acceleration hook step can not be proven")
+
+// AccelerationHook replaces the execution of an expensive guest function, such as a keccak permutation, an
+// ecrecover or a bn256 pairing, with a native implementation to reduce the number of steps of a program.
+//
+// The guest calls the hook with a syscall, using the syscall number of the hook, in place of the function. The hook
+// is executed in the single step of the syscall: it reads its arguments from $a0-$a3, and its results are returned in
+// $v0 and $v1 following the syscall convention. As the syscall number is part of the state, the on-chain VM executes
+// the same hook at the same step, so steps executed by a hook remain provable.
+//
+// To fit the witness of a single step, a hook may access at most two words of memory, the second of which must follow
+// the first, and read at most one chunk of pre-image data. Results that are too expensive to compute on-chain must be
+// read from the pre-image oracle with a precompile key, following the pattern of the precompiles accelerated by the
+// op-program client, so that the on-chain pre-image oracle verifies them instead.
+//
+// A hook changes the steps executed by the VM, so the on-chain VM must implement the same hook for the steps to
+// remain provable. The hooks supported by each state version are therefore fixed by the state version.
+type AccelerationHook interface {
+	// Syscall returns the syscall number the guest uses to call the hook.
+	// It must not be the number of a syscall handled by the VM.
+	Syscall() arch.Word
+
+	// Name returns the name of the hook, used to identify it in errors.
+	Name() string
+
+	// Call executes the hook and returns the values of $v0 and $v1.
+	// An error stops the VM, as the hook could not be executed.
+	Call(env HookEnv) (v0 arch.Word, v1 arch.Word, err error)
+}
+
+// HookEnv is the guest environment available to an AccelerationHook.
+// Accesses that exceed what can be proven in a single step fail the hook with ErrHookNotProvable.
+type HookEnv interface {
+	// Args returns the syscall arguments in $a0-$a3.
+	Args() (a0, a1, a2, a3 arch.Word)
+
+	// GetMemory reads the word at the aligned address of the guest memory.
+	GetMemory(addr arch.Word) arch.Word
+
+	// SetMemory writes the word at the aligned address of the guest memory.
+	SetMemory(addr arch.Word, v arch.Word)
+
+	// ReadPreimage reads up to 32 bytes of the length prefixed pre-image of key, starting at offset.
+	ReadPreimage(key [32]byte, offset arch.Word) (dat [32]byte, datLen arch.Word)
+}
+
+// Hooks are the acceleration hooks of a program, by the syscall number that calls the hook.
+type Hooks map[arch.Word]AccelerationHook
+
+// NewHooks indexes the hooks by their syscall number.
+func NewHooks(hooks ...AccelerationHook) (Hooks, error) {
+	out := make(Hooks, len(hooks))
+	for _, hook := range hooks {
+		if hook == nil {
+			return nil, errors.New("nil acceleration hook")
+		}
+		if existing, ok := out[hook.Syscall()]; ok {
+			return nil, fmt.Errorf("acceleration hooks %v and %v use the same syscall %d", existing.Name(), hook.Name(), hook.Syscall())
+		}
+		out[hook.Syscall()] = hook
+	}
+	return out, nil
+}
+
+// Lookup returns the hook called by the given syscall number, or nil if the syscall doesn't call a hook.
+func (h Hooks) Lookup(syscallNum arch.Word) AccelerationHook {
+	return h[syscallNum]
+}
+
+// HookableFPVM is a FPVM that supports acceleration hooks.
+type HookableFPVM interface {
+	FPVM
+
+	// SetHooks sets the acceleration hooks to call when the program makes the syscall of a hook.
+	SetHooks(hooks Hooks)
+}
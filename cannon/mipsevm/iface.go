@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
 )
 
@@ -17,22 +18,22 @@ type FPVMState interface {
 	GetMemory() *memory.Memory
 
 	// GetHeap returns the current memory address at the top of the heap
-	GetHeap() uint32
+	GetHeap() arch.Word
 
 	// GetPreimageKey returns the most recently accessed preimage key
 	GetPreimageKey() common.Hash
 
 	// GetPreimageOffset returns the current offset into the current preimage
-	GetPreimageOffset() uint32
+	GetPreimageOffset() arch.Word
 
 	// GetPC returns the currently executing program counter
-	GetPC() uint32
+	GetPC() arch.Word
 
 	// GetCpu returns the currently active cpu scalars, including the program counter
 	GetCpu() CpuScalars
 
 	// GetRegistersRef returns a pointer to the currently active registers
-	GetRegistersRef() *[32]uint32
+	GetRegistersRef() *[32]arch.Word
 
 	// GetStep returns the current VM step
 	GetStep() uint64
@@ -60,10 +61,10 @@ type FPVMState interface {
 	CreateVM(logger log.Logger, po PreimageOracle, stdOut, stdErr io.Writer, meta Metadata) FPVM
 }
 
-type SymbolMatcher func(addr uint32) bool
+type SymbolMatcher func(addr arch.Word) bool
 
 type Metadata interface {
-	LookupSymbol(addr uint32) string
+	LookupSymbol(addr arch.Word) string
 	CreateSymbolMatcher(name string) SymbolMatcher
 }
 
@@ -78,7 +79,7 @@ type FPVM interface {
 	CheckInfiniteLoop() bool
 
 	// LastPreimage returns the last preimage accessed by the VM
-	LastPreimage() (preimageKey [32]byte, preimage []byte, preimageOffset uint32)
+	LastPreimage() (preimageKey [32]byte, preimage []byte, preimageOffset arch.Word)
 
 	// Traceback prints a traceback of the program to the console
 	Traceback()
@@ -91,5 +92,5 @@ type FPVM interface {
 
 	// LookupSymbol returns the symbol located at the specified address.
 	// May return an empty string if there's no symbol table available.
-	LookupSymbol(addr uint32) string
+	LookupSymbol(addr arch.Word) string
 }
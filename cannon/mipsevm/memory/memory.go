@@ -9,19 +9,28 @@ import (
 	"sort"
 
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 )
 
 // Note: 2**12 = 4 KiB, the min phys page size in the Go runtime.
 const (
-	PageAddrSize = 12
-	PageKeySize  = 32 - PageAddrSize
+	PageAddrSize = arch.PageAddrSize
+	PageKeySize  = arch.PageKeySize
 	PageSize     = 1 << PageAddrSize
 	PageAddrMask = PageSize - 1
 	MaxPageCount = 1 << PageKeySize
 	PageKeyMask  = MaxPageCount - 1
 )
 
-const MEM_PROOF_SIZE = 28 * 32
+const MEM_PROOF_SIZE = arch.MemProofSize
+
+// maxCachedPages limits the number of pages that keep their intermediate merkle nodes, to bound the memory used to
+// generate proofs to 16 MiB rather than doubling the size of the VM memory. Other pages only keep their merkle root and
+// recompute the intermediate nodes when they are needed for a proof.
+const maxCachedPages = 1 << 12
+
+type Word = arch.Word
 
 func HashPair(left, right [32]byte) [32]byte {
 	out := crypto.Keccak256Hash(left[:], right[:])
@@ -43,22 +52,107 @@ type Memory struct {
 	nodes map[uint64]*[32]byte
 
 	// pageIndex -> cached page
-	pages map[uint32]*CachedPage
+	pages map[Word]*CachedPage
 
 	// Note: since we don't de-alloc pages, we don't do ref-counting.
 	// Once a page exists, it doesn't leave memory
 
 	// two caches: we often read instructions from one page, and do memory things with another page.
 	// this prevents map lookups each instruction
-	lastPageKeys [2]uint32
+	lastPageKeys [2]Word
 	lastPage     [2]*CachedPage
+
+	// ring buffer of the pages most recently given a cache of intermediate merkle nodes
+	cachedPages    []*CachedPage
+	nextCachedPage int
+
+	// optional hook called on each word accessed through GetMemory and SetMemory, for tracing
+	accessHook AccessHook
 }
 
+// AccessHook is called with the address and value of each word read or written.
+// For writes, value is the newly written word.
+type AccessHook func(addr Word, value Word, write bool)
+
 func NewMemory() *Memory {
 	return &Memory{
 		nodes:        make(map[uint64]*[32]byte),
-		pages:        make(map[uint32]*CachedPage),
-		lastPageKeys: [2]uint32{^uint32(0), ^uint32(0)}, // default to invalid keys, to not match any pages
+		pages:        make(map[Word]*CachedPage),
+		lastPageKeys: [2]Word{^Word(0), ^Word(0)}, // default to invalid keys, to not match any pages
+	}
+}
+
+// Snapshot returns a copy of the memory that is not affected by later modifications.
+// Page data is shared copy-on-write between the memory and the snapshot. Taking a snapshot copies the page table and
+// merkle nodes in full, so its cost is linear in the number of allocated pages, but the contents of a page are only
+// copied when it is next modified.
+// The snapshot may be read concurrently with further use of the memory.
+func (m *Memory) Snapshot() *Memory {
+	out := &Memory{
+		nodes:        make(map[uint64]*[32]byte, len(m.nodes)),
+		pages:        make(map[Word]*CachedPage, len(m.pages)),
+		lastPageKeys: [2]Word{^Word(0), ^Word(0)},
+	}
+	// Merkle nodes are never modified in place, only replaced, so can be shared.
+	for k, n := range m.nodes {
+		out.nodes[k] = n
+	}
+	for k, p := range m.pages {
+		if !p.shared {
+			// Complete the merkle root so it is never written once shared.
+			p.MerkleRoot()
+			p.shared = true
+		}
+		if p.cache != nil {
+			// Intermediate nodes are recomputed by whichever memory needs them, see cachingPage.
+			p.dropCache()
+		}
+		out.pages[k] = p
+	}
+	return out
+}
+
+// writablePage returns the page at pageIndex, copying it first if it is shared with a snapshot.
+func (m *Memory) writablePage(pageIndex Word) (*CachedPage, bool) {
+	p, ok := m.pageLookup(pageIndex)
+	if !ok || !p.shared {
+		return p, ok
+	}
+	data := new(Page)
+	*data = *p.Data
+	cp := &CachedPage{Data: data, root: p.root, rootOk: p.rootOk}
+	m.replacePage(pageIndex, cp)
+	return cp, true
+}
+
+// cachingPage prepares the page at pageIndex to hold a cache of intermediate merkle nodes, dropping the cache of the
+// least recently cached page if maxCachedPages is reached.
+// A page shared with a snapshot is replaced by a new page sharing the same data first, so that the cache is never
+// written to a page that may be read concurrently.
+func (m *Memory) cachingPage(pageIndex Word, p *CachedPage) *CachedPage {
+	if p.shared {
+		p = &CachedPage{Data: p.Data, root: p.root, rootOk: p.rootOk, shared: true}
+		m.replacePage(pageIndex, p)
+	}
+	if len(m.cachedPages) < maxCachedPages {
+		m.cachedPages = append(m.cachedPages, p)
+	} else {
+		if evicted := m.cachedPages[m.nextCachedPage]; evicted.cache != nil {
+			evicted.dropCache()
+		}
+		m.cachedPages[m.nextCachedPage] = p
+		m.nextCachedPage = (m.nextCachedPage + 1) % maxCachedPages
+	}
+	p.cache = new(pageCache)
+	return p
+}
+
+func (m *Memory) replacePage(pageIndex Word, p *CachedPage) {
+	m.pages[pageIndex] = p
+	for i := range m.lastPageKeys {
+		if m.lastPageKeys[i] == pageIndex {
+			m.lastPage[i] = p
+		}
 	}
 }
 
@@ -66,7 +160,7 @@ func (m *Memory) PageCount() int {
 	return len(m.pages)
 }
 
-func (m *Memory) ForEachPage(fn func(pageIndex uint32, page *Page) error) error {
+func (m *Memory) ForEachPage(fn func(pageIndex Word, page *Page) error) error {
 	for pageIndex, cachedPage := range m.pages {
 		if err := fn(pageIndex, cachedPage.Data); err != nil {
 			return err
@@ -75,15 +169,35 @@ func (m *Memory) ForEachPage(fn func(pageIndex uint32, page *Page) error) error
 	return nil
 }
 
-func (m *Memory) Invalidate(addr uint32) {
-	// addr must be aligned to 4 bytes
-	if addr&0x3 != 0 {
+// DiffPages returns the indices of the pages with different contents in a and b, in ascending order.
+// A page that is only allocated in one of the memories differs if it is not all zeros.
+func DiffPages(a, b *Memory) []Word {
+	var zero Page
+	var out []Word
+	for pageIndex, pa := range a.pages {
+		pb, ok := b.pages[pageIndex]
+		if (ok && *pa.Data != *pb.Data) || (!ok && *pa.Data != zero) {
+			out = append(out, pageIndex)
+		}
+	}
+	for pageIndex, pb := range b.pages {
+		if _, ok := a.pages[pageIndex]; !ok && *pb.Data != zero {
+			out = append(out, pageIndex)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func (m *Memory) Invalidate(addr Word) {
+	// addr must be aligned to the word size
+	if addr&arch.ExtMask != 0 {
 		panic(fmt.Errorf("unaligned memory access: %x", addr))
 	}
 
 	// find page, and invalidate addr within it
-	if p, ok := m.pageLookup(addr >> PageAddrSize); ok {
-		prevValid := p.Ok[1]
+	if p, ok := m.writablePage(addr >> PageAddrSize); ok {
+		prevValid := p.rootOk
 		p.Invalidate(addr & PageAddrMask)
 		if !prevValid { // if the page was already invalid before, then nodes to mem-root will also still be.
 			return
@@ -93,7 +207,7 @@ func (m *Memory) Invalidate(addr uint32) {
 	}
 
 	// find the gindex of the first page covering the address
-	gindex := ((uint64(1) << 32) | uint64(addr)) >> PageAddrSize
+	gindex := (uint64(1) << (arch.WordSize - PageAddrSize)) | uint64(addr>>PageAddrSize)
 
 	for gindex > 0 {
 		m.nodes[gindex] = nil
@@ -103,23 +217,27 @@ func (m *Memory) Invalidate(addr uint32) {
 
 func (m *Memory) MerkleizeSubtree(gindex uint64) [32]byte {
 	l := uint64(bits.Len64(gindex))
-	if l > 28 {
+	if l > arch.MemProofLeafCount {
 		panic("gindex too deep")
 	}
 	if l > PageKeySize {
 		depthIntoPage := l - 1 - PageKeySize
 		pageIndex := (gindex >> depthIntoPage) & PageKeyMask
-		if p, ok := m.pages[uint32(pageIndex)]; ok {
+		if p, ok := m.pages[Word(pageIndex)]; ok {
 			pageGindex := (1 << depthIntoPage) | (gindex & ((1 << depthIntoPage) - 1))
+			// Leaf nodes and a valid page root can be read without the intermediate nodes.
+			if p.cache == nil && pageGindex < PageSize/32 && !(pageGindex == 1 && p.rootOk) {
+				p = m.cachingPage(Word(pageIndex), p)
+			}
 			return p.MerkleizeSubtree(pageGindex)
 		} else {
-			return zeroHashes[28-l] // page does not exist
+			return zeroHashes[arch.MemProofLeafCount-l] // page does not exist
 		}
 	}
 	n, ok := m.nodes[gindex]
 	if !ok {
 		// if the node doesn't exist, the whole sub-tree is zeroed
-		return zeroHashes[28-l]
+		return zeroHashes[arch.MemProofLeafCount-l]
 	}
 	if n != nil {
 		return *n
@@ -131,27 +249,27 @@ func (m *Memory) MerkleizeSubtree(gindex uint64) [32]byte {
 	return r
 }
 
-func (m *Memory) MerkleProof(addr uint32) (out [MEM_PROOF_SIZE]byte) {
+func (m *Memory) MerkleProof(addr Word) (out [MEM_PROOF_SIZE]byte) {
 	proof := m.traverseBranch(1, addr, 0)
 	// encode the proof
-	for i := 0; i < 28; i++ {
+	for i := 0; i < arch.MemProofLeafCount; i++ {
 		copy(out[i*32:(i+1)*32], proof[i][:])
 	}
 	return out
 }
 
-func (m *Memory) traverseBranch(parent uint64, addr uint32, depth uint8) (proof [][32]byte) {
-	if depth == 32-5 {
-		proof = make([][32]byte, 0, 32-5+1)
+func (m *Memory) traverseBranch(parent uint64, addr Word, depth uint8) (proof [][32]byte) {
+	if depth == arch.WordSize-5 {
+		proof = make([][32]byte, 0, arch.WordSize-5+1)
 		proof = append(proof, m.MerkleizeSubtree(parent))
 		return
 	}
-	if depth > 32-5 {
+	if depth > arch.WordSize-5 {
 		panic("traversed too deep")
 	}
 	self := parent << 1
 	sibling := self | 1
-	if addr&(1<<(31-depth)) != 0 {
+	if addr&(1<<((arch.WordSize-1)-depth)) != 0 {
 		self, sibling = sibling, self
 	}
 	proof = m.traverseBranch(self, addr, depth+1)
@@ -164,7 +282,7 @@ func (m *Memory) MerkleRoot() [32]byte {
 	return m.MerkleizeSubtree(1)
 }
 
-func (m *Memory) pageLookup(pageIndex uint32) (*CachedPage, bool) {
+func (m *Memory) pageLookup(pageIndex Word) (*CachedPage, bool) {
 	// hit caches
 	if pageIndex == m.lastPageKeys[0] {
 		return m.lastPage[0], true
@@ -185,15 +303,24 @@ func (m *Memory) pageLookup(pageIndex uint32) (*CachedPage, bool) {
 	return p, ok
 }
 
-func (m *Memory) SetMemory(addr uint32, v uint32) {
-	// addr must be aligned to 4 bytes
-	if addr&0x3 != 0 {
+// SetAccessHook sets a hook that is called on each word read or written by GetMemory and SetMemory.
+// Pass nil to remove the hook. The hook is not carried over to snapshots or copies of the memory.
+func (m *Memory) SetAccessHook(hook AccessHook) {
+	m.accessHook = hook
+}
+
+func (m *Memory) SetMemory(addr Word, v Word) {
+	// addr must be aligned to the word size
+	if addr&arch.ExtMask != 0 {
 		panic(fmt.Errorf("unaligned memory access: %x", addr))
 	}
+	if m.accessHook != nil {
+		m.accessHook(addr, v, true)
+	}
 
 	pageIndex := addr >> PageAddrSize
 	pageAddr := addr & PageAddrMask
-	p, ok := m.pageLookup(pageIndex)
+	p, ok := m.writablePage(pageIndex)
 	if !ok {
 		// allocate the page if we have not already.
 		// Go may mmap relatively large ranges, but we only allocate the pages just in time.
@@ -201,11 +328,32 @@ func (m *Memory) SetMemory(addr uint32, v uint32) {
 	} else {
 		m.Invalidate(addr) // invalidate this branch of memory, now that the value changed
 	}
-	binary.BigEndian.PutUint32(p.Data[pageAddr:pageAddr+4], v)
+	arch.ByteOrderWord.PutWord(p.Data[pageAddr:pageAddr+arch.WordSizeBytes], v)
+}
+
+func (m *Memory) GetMemory(addr Word) Word {
+	// addr must be aligned to the word size
+	if addr&arch.ExtMask != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+	p, ok := m.pageLookup(addr >> PageAddrSize)
+	if !ok {
+		if m.accessHook != nil {
+			m.accessHook(addr, 0, false)
+		}
+		return 0
+	}
+	pageAddr := addr & PageAddrMask
+	v := arch.ByteOrderWord.Word(p.Data[pageAddr : pageAddr+arch.WordSizeBytes])
+	if m.accessHook != nil {
+		m.accessHook(addr, v, false)
+	}
+	return v
 }
 
-func (m *Memory) GetMemory(addr uint32) uint32 {
-	// addr must be aligned to 4 bytes
+// GetUint32 reads the 4-byte value at addr, which must be 4-byte aligned.
+// Instructions are 32 bits wide on both MIPS32 and MIPS64, so this is used to fetch them.
+func (m *Memory) GetUint32(addr Word) uint32 {
 	if addr&0x3 != 0 {
 		panic(fmt.Errorf("unaligned memory access: %x", addr))
 	}
@@ -217,7 +365,7 @@ func (m *Memory) GetMemory(addr uint32) uint32 {
 	return binary.BigEndian.Uint32(p.Data[pageAddr : pageAddr+4])
 }
 
-func (m *Memory) AllocPage(pageIndex uint32) *CachedPage {
+func (m *Memory) AllocPage(pageIndex Word) *CachedPage {
 	p := &CachedPage{Data: new(Page)}
 	m.pages[pageIndex] = p
 	// make nodes to root
@@ -230,8 +378,8 @@ func (m *Memory) AllocPage(pageIndex uint32) *CachedPage {
 }
 
 type pageEntry struct {
-	Index uint32 `json:"index"`
-	Data  *Page  `json:"data"`
+	Index Word  `json:"index"`
+	Data  *Page `json:"data"`
 }
 
 func (m *Memory) MarshalJSON() ([]byte, error) { // nosemgrep
@@ -254,8 +402,8 @@ func (m *Memory) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	m.nodes = make(map[uint64]*[32]byte)
-	m.pages = make(map[uint32]*CachedPage)
-	m.lastPageKeys = [2]uint32{^uint32(0), ^uint32(0)}
+	m.pages = make(map[Word]*CachedPage)
+	m.lastPageKeys = [2]Word{^Word(0), ^Word(0)}
 	m.lastPage = [2]*CachedPage{nil, nil}
 	for i, p := range pages {
 		if _, ok := m.pages[p.Index]; ok {
@@ -266,11 +414,11 @@ func (m *Memory) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (m *Memory) SetMemoryRange(addr uint32, r io.Reader) error {
+func (m *Memory) SetMemoryRange(addr Word, r io.Reader) error {
 	for {
 		pageIndex := addr >> PageAddrSize
 		pageAddr := addr & PageAddrMask
-		p, ok := m.pageLookup(pageIndex)
+		p, ok := m.writablePage(pageIndex)
 		if !ok {
 			p = m.AllocPage(pageIndex)
 		}
@@ -282,7 +430,7 @@ func (m *Memory) SetMemoryRange(addr uint32, r io.Reader) error {
 			}
 			return err
 		}
-		addr += uint32(n)
+		addr += Word(n)
 	}
 }
 
@@ -293,7 +441,7 @@ func (m *Memory) SetMemoryRange(addr uint32, r io.Reader) error {
 // len(PageCount)    uint32
 // For each page (order is arbitrary):
 //
-//	page index          uint32
+//	page index          Word (uint32 for MIPS32, uint64 for MIPS64)
 //	page Data           [PageSize]byte
 func (m *Memory) Serialize(out io.Writer) error {
 	if err := binary.Write(out, binary.BigEndian, uint32(m.PageCount())); err != nil {
@@ -316,7 +464,7 @@ func (m *Memory) Deserialize(in io.Reader) error {
 		return err
 	}
 	for i := uint32(0); i < pageCount; i++ {
-		var pageIndex uint32
+		var pageIndex Word
 		if err := binary.Read(in, binary.BigEndian, &pageIndex); err != nil {
 			return err
 		}
@@ -331,8 +479,8 @@ func (m *Memory) Deserialize(in io.Reader) error {
 func (m *Memory) Copy() *Memory {
 	out := NewMemory()
 	out.nodes = make(map[uint64]*[32]byte)
-	out.pages = make(map[uint32]*CachedPage)
-	out.lastPageKeys = [2]uint32{^uint32(0), ^uint32(0)}
+	out.pages = make(map[Word]*CachedPage)
+	out.lastPageKeys = [2]Word{^Word(0), ^Word(0)}
 	out.lastPage = [2]*CachedPage{nil, nil}
 	for k, page := range m.pages {
 		data := new(Page)
@@ -344,8 +492,8 @@ func (m *Memory) Copy() *Memory {
 
 type memReader struct {
 	m     *Memory
-	addr  uint32
-	count uint32
+	addr  Word
+	count Word
 }
 
 func (r *memReader) Read(dest []byte) (n int, err error) {
@@ -359,7 +507,7 @@ func (r *memReader) Read(dest []byte) (n int, err error) {
 
 	pageIndex := r.addr >> PageAddrSize
 	start := r.addr & PageAddrMask
-	end := uint32(PageSize)
+	end := Word(PageSize)
 
 	if pageIndex == (endAddr >> PageAddrSize) {
 		end = endAddr & PageAddrMask
@@ -370,12 +518,12 @@ func (r *memReader) Read(dest []byte) (n int, err error) {
 	} else {
 		n = copy(dest, make([]byte, end-start)) // default to zeroes
 	}
-	r.addr += uint32(n)
-	r.count -= uint32(n)
+	r.addr += Word(n)
+	r.count -= Word(n)
 	return n, nil
 }
 
-func (m *Memory) ReadMemoryRange(addr uint32, count uint32) io.Reader {
+func (m *Memory) ReadMemoryRange(addr Word, count Word) io.Reader {
 	return &memReader{m: m, addr: addr, count: count}
 }
 
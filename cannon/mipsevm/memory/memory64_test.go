@@ -0,0 +1,134 @@
+//go:build cannon64
+// +build cannon64
+
+package memory
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory64MerkleProof(t *testing.T) {
+	t.Run("nearly empty tree", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0x10000, 0xAABBCCDD_EEFF1122)
+		proof := m.MerkleProof(0x10000)
+		require.Equal(t, uint64(0xAABBCCDD_EEFF1122), binary.BigEndian.Uint64(proof[:8]))
+		for i := 0; i < 64-5; i++ {
+			require.Equal(t, zeroHashes[i][:], proof[32+i*32:32+i*32+32], "empty siblings")
+		}
+	})
+	t.Run("fuller tree", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0x10000, 0xaabbccdd)
+		m.SetMemory(0x80008, 42)
+		m.SetMemory(0x13370000, 123)
+		m.SetMemory(0x7F_FF_FF_FF_D0_00_00_08, 456)
+		root := m.MerkleRoot()
+		proof := m.MerkleProof(0x80008)
+		require.Equal(t, uint64(42), binary.BigEndian.Uint64(proof[8:16]))
+		node := *(*[32]byte)(proof[:32])
+		path := uint64(0x80008) >> 5
+		for i := 32; i < len(proof); i += 32 {
+			sib := *(*[32]byte)(proof[i : i+32])
+			if path&1 != 0 {
+				node = HashPair(sib, node)
+			} else {
+				node = HashPair(node, sib)
+			}
+			path >>= 1
+		}
+		require.Equal(t, root, node, "proof must verify")
+	})
+}
+
+func TestMemory64MerkleRoot(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		m := NewMemory()
+		root := m.MerkleRoot()
+		require.Equal(t, zeroHashes[64-5], root, "fully zeroed memory should have expected zero hash")
+	})
+	t.Run("empty page", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0xF000, 0)
+		root := m.MerkleRoot()
+		require.Equal(t, zeroHashes[64-5], root, "fully zeroed memory should have expected zero hash")
+	})
+	t.Run("single page", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0xF000, 1)
+		root := m.MerkleRoot()
+		require.NotEqual(t, zeroHashes[64-5], root, "non-zero memory")
+	})
+	t.Run("repeat zero", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0xF000, 0)
+		m.SetMemory(0xF008, 0)
+		root := m.MerkleRoot()
+		require.Equal(t, zeroHashes[64-5], root, "zero still")
+	})
+}
+
+func TestMemory64ReadWrite(t *testing.T) {
+	t.Run("large random range", func(t *testing.T) {
+		m := NewMemory()
+		data := make([]byte, 20_000)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		require.NoError(t, m.SetMemoryRange(0, bytes.NewReader(data)))
+		for _, i := range []Word{0, 8, 1000, 20_000 - 8} {
+			v := m.GetMemory(i)
+			expected := binary.BigEndian.Uint64(data[i : i+8])
+			require.Equal(t, expected, v)
+		}
+		res, err := io.ReadAll(m.ReadMemoryRange(0, Word(len(data))))
+		require.NoError(t, err)
+		require.Equal(t, data, res)
+	})
+
+	t.Run("high address", func(t *testing.T) {
+		m := NewMemory()
+		const addr = Word(0x7F_FF_FF_FF_D0_00_00_10)
+		m.SetMemory(addr, 0x01020304_05060708)
+		require.Equal(t, Word(0x01020304_05060708), m.GetMemory(addr))
+		require.Equal(t, uint32(0x01020304), m.GetUint32(addr))
+		require.Equal(t, uint32(0x05060708), m.GetUint32(addr+4))
+	})
+
+	t.Run("unaligned read", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(16, 0xAABBCCDD_EEFF1122)
+		require.Panics(t, func() {
+			m.GetMemory(17)
+		})
+		require.Panics(t, func() {
+			m.GetMemory(20)
+		})
+	})
+
+	t.Run("unaligned write", func(t *testing.T) {
+		m := NewMemory()
+		require.Panics(t, func() {
+			m.SetMemory(17, 0xAABBCCDD)
+		})
+		require.Panics(t, func() {
+			m.SetMemory(12, 0xAABBCCDD)
+		})
+	})
+}
+
+func TestMemory64JSON(t *testing.T) {
+	m := NewMemory()
+	m.SetMemory(8, 0xAABBCCDD_EEFF1122)
+	dat, err := json.Marshal(m)
+	require.NoError(t, err)
+	var res Memory
+	require.NoError(t, json.Unmarshal(dat, &res))
+	require.Equal(t, Word(0xAABBCCDD_EEFF1122), res.GetMemory(8))
+}
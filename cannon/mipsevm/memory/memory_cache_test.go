@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBoundedMerkleCache(t *testing.T) {
+	m := NewMemory()
+	pageCount := maxCachedPages + 10
+	for i := 0; i < pageCount; i++ {
+		m.SetMemory(Word(i)<<PageAddrSize, Word(i+1))
+	}
+	require.Equal(t, requireCopyRoot(t, m), m.MerkleRoot())
+
+	cached := 0
+	for _, p := range m.pages {
+		if p.cache != nil {
+			cached++
+		}
+	}
+	require.Equal(t, maxCachedPages, cached)
+
+	// The first pages merkleized have their intermediate nodes dropped, but proofs must still verify.
+	require.Nil(t, m.pages[0].cache)
+	requireValidProof(t, m, 0)
+	require.NotNil(t, m.pages[0].cache)
+
+	m.SetMemory(1<<PageAddrSize|8, 42)
+	require.Equal(t, requireCopyRoot(t, m), m.MerkleRoot())
+	requireValidProof(t, m, 1<<PageAddrSize|8)
+}
+
+func TestMemoryProofOfSharedPage(t *testing.T) {
+	m := NewMemory()
+	m.SetMemory(0x1000, 1)
+	snap := m.Snapshot()
+	shared := snap.pages[1]
+
+	requireValidProof(t, m, 0x1000)
+	require.NotSame(t, shared, m.pages[1], "proof should not write a cache to the shared page")
+	require.Nil(t, shared.cache)
+	require.Same(t, shared.Data, m.pages[1].Data, "page data should remain shared")
+
+	requireValidProof(t, snap, 0x1000)
+	require.NotSame(t, m.pages[1], snap.pages[1])
+}
+
+func requireValidProof(t *testing.T, m *Memory, addr Word) {
+	root := m.MerkleRoot()
+	proof := m.MerkleProof(addr)
+	node := *(*[32]byte)(proof[:32])
+	path := addr >> 5
+	for i := 32; i < len(proof); i += 32 {
+		sib := *(*[32]byte)(proof[i : i+32])
+		if path&1 != 0 {
+			node = HashPair(sib, node)
+		} else {
+			node = HashPair(node, sib)
+		}
+		path >>= 1
+	}
+	require.Equal(t, root, node, "proof must verify")
+}
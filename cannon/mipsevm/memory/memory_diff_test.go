@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPages(t *testing.T) {
+	a := NewMemory()
+	b := NewMemory()
+	require.Empty(t, DiffPages(a, b))
+
+	a.SetMemory(0x3000, 1)
+	b.SetMemory(0x3000, 1)
+	// Allocated in only one memory but all zeros
+	a.SetMemory(0x5000, 0)
+	require.Empty(t, DiffPages(a, b))
+
+	b.SetMemory(0x3008, 2)
+	a.SetMemory(0x9000, 3)
+	b.SetMemory(0x1000, 4)
+	require.Equal(t, []Word{0x1, 0x3, 0x9}, DiffPages(a, b))
+	require.Equal(t, []Word{0x1, 0x3, 0x9}, DiffPages(b, a))
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySnapshot(t *testing.T) {
+	t.Run("UnaffectedByLaterWrites", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0x1000, 1)
+		m.SetMemory(0x8000, 2)
+		expectedRoot := m.MerkleRoot()
+
+		snap := m.Snapshot()
+		m.SetMemory(0x1000, 3)
+		m.SetMemory(0x20000, 4)
+		require.NoError(t, m.SetMemoryRange(0x30000, bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})))
+
+		require.Equal(t, expectedRoot, snap.MerkleRoot())
+		require.Equal(t, Word(1), snap.GetMemory(0x1000))
+		require.Equal(t, Word(2), snap.GetMemory(0x8000))
+		require.Equal(t, 2, snap.PageCount())
+
+		require.Equal(t, Word(3), m.GetMemory(0x1000))
+		require.Equal(t, Word(4), m.GetMemory(0x20000))
+		require.Equal(t, 4, m.PageCount())
+		require.Equal(t, requireCopyRoot(t, m), m.MerkleRoot())
+	})
+
+	t.Run("WritesToSnapshotDoNotAffectMemory", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0x1000, 1)
+		snap := m.Snapshot()
+		snap.SetMemory(0x1000, 2)
+		require.Equal(t, Word(1), m.GetMemory(0x1000))
+		require.Equal(t, Word(2), snap.GetMemory(0x1000))
+	})
+
+	t.Run("SharesUnmodifiedPages", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0x1000, 1)
+		m.SetMemory(0x2000, 2)
+		snap := m.Snapshot()
+		m.SetMemory(0x1000, 3)
+		require.NotSame(t, snap.pages[1], m.pages[1])
+		require.Same(t, snap.pages[2], m.pages[2])
+
+		// Only the first write to a page after a snapshot copies it
+		p := m.pages[1]
+		m.SetMemory(0x1008, 4)
+		require.Same(t, p, m.pages[1])
+	})
+
+	t.Run("RepeatedSnapshots", func(t *testing.T) {
+		m := NewMemory()
+		var snaps []*Memory
+		var roots [][32]byte
+		for i := 0; i < 5; i++ {
+			m.SetMemory(0x1000, Word(i))
+			m.SetMemory(Word(0x10000*(i+1)), Word(i))
+			roots = append(roots, m.MerkleRoot())
+			snaps = append(snaps, m.Snapshot())
+		}
+		for i, snap := range snaps {
+			require.Equal(t, roots[i], snap.MerkleRoot())
+			require.Equal(t, Word(i), snap.GetMemory(0x1000))
+			require.Equal(t, i+2, snap.PageCount())
+		}
+	})
+
+	t.Run("SnapshotOfSnapshot", func(t *testing.T) {
+		m := NewMemory()
+		m.SetMemory(0x1000, 1)
+		snap := m.Snapshot()
+		snap.SetMemory(0x1000, 2)
+		snap2 := snap.Snapshot()
+		snap.SetMemory(0x1000, 3)
+		m.SetMemory(0x1000, 4)
+		require.Equal(t, Word(2), snap2.GetMemory(0x1000))
+		require.Equal(t, Word(3), snap.GetMemory(0x1000))
+		require.Equal(t, Word(4), m.GetMemory(0x1000))
+	})
+}
+
+// requireCopyRoot returns the merkle root of a full copy of m, which shares no pages or caches with m.
+func requireCopyRoot(t *testing.T, m *Memory) [32]byte {
+	cp := m.Copy()
+	require.Equal(t, m.PageCount(), cp.PageCount())
+	return cp.MerkleRoot()
+}
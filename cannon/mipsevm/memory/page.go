@@ -64,56 +64,85 @@ func (p *Page) UnmarshalText(dat []byte) error {
 
 type CachedPage struct {
 	Data *Page
+	// intermediate nodes of the page, or nil if not cached.
+	// Memory bounds the number of pages holding a cache, see maxCachedPages.
+	cache *pageCache
+	// merkle root of the page, valid if rootOk is true
+	root   [32]byte
+	rootOk bool
+	// true if the page is shared with a snapshot and must be copied before it is modified, see Memory.Snapshot
+	shared bool
+}
+
+type pageCache struct {
 	// intermediate nodes only
-	Cache [PageSize / 32][32]byte
+	nodes [PageSize / 32][32]byte
 	// true if the intermediate node is valid
-	Ok [PageSize / 32]bool
+	ok [PageSize / 32]bool
 }
 
-func (p *CachedPage) Invalidate(pageAddr uint32) {
+func (p *CachedPage) Invalidate(pageAddr Word) {
 	if pageAddr >= PageSize {
 		panic("invalid page addr")
 	}
+	p.rootOk = false
+	if p.cache == nil {
+		return
+	}
 	k := (1 << PageAddrSize) | pageAddr
 	// first cache layer caches nodes that has two 32 byte leaf nodes.
 	k >>= 5 + 1
 	for k > 0 {
-		p.Ok[k] = false
+		p.cache.ok[k] = false
 		k >>= 1
 	}
 }
 
 func (p *CachedPage) InvalidateFull() {
-	p.Ok = [PageSize / 32]bool{} // reset everything to false
+	p.rootOk = false
+	p.cache = nil
+}
+
+// dropCache releases the intermediate nodes of the page, keeping the root if it is valid.
+func (p *CachedPage) dropCache() {
+	p.cache = nil
 }
 
 func (p *CachedPage) MerkleRoot() [32]byte {
+	if p.rootOk {
+		return p.root
+	}
+	if p.cache == nil {
+		p.cache = new(pageCache)
+	}
+	c := p.cache
 	// hash the bottom layer
 	for i := uint64(0); i < PageSize; i += 64 {
 		j := PageSize/32/2 + i/64
-		if p.Ok[j] {
+		if c.ok[j] {
 			continue
 		}
-		p.Cache[j] = crypto.Keccak256Hash(p.Data[i : i+64])
-		//fmt.Printf("0x%x 0x%x -> 0x%x\n", p.Data[i:i+32], p.Data[i+32:i+64], p.Cache[j])
-		p.Ok[j] = true
+		c.nodes[j] = crypto.Keccak256Hash(p.Data[i : i+64])
+		//fmt.Printf("0x%x 0x%x -> 0x%x\n", p.Data[i:i+32], p.Data[i+32:i+64], c.nodes[j])
+		c.ok[j] = true
 	}
 
 	// hash the cache layers
 	for i := PageSize/32 - 2; i > 0; i -= 2 {
 		j := i >> 1
-		if p.Ok[j] {
+		if c.ok[j] {
 			continue
 		}
-		p.Cache[j] = HashPair(p.Cache[i], p.Cache[i+1])
-		p.Ok[j] = true
+		c.nodes[j] = HashPair(c.nodes[i], c.nodes[i+1])
+		c.ok[j] = true
 	}
 
-	return p.Cache[1]
+	p.root = c.nodes[1]
+	p.rootOk = true
+	return p.root
 }
 
 func (p *CachedPage) MerkleizeSubtree(gindex uint64) [32]byte {
-	_ = p.MerkleRoot() // fill cache
 	if gindex >= PageSize/32 {
 		if gindex >= PageSize/32*2 {
 			panic("gindex too deep")
@@ -122,5 +151,13 @@ func (p *CachedPage) MerkleizeSubtree(gindex uint64) [32]byte {
 		nodeIndex := gindex & (PageAddrMask >> 5)
 		return *(*[32]byte)(p.Data[nodeIndex*32 : nodeIndex*32+32])
 	}
-	return p.Cache[gindex]
+	if gindex == 1 {
+		return p.MerkleRoot()
+	}
+	if p.cache == nil || !p.cache.ok[1] {
+		// The root may be valid without the intermediate nodes, so recompute them.
+		p.rootOk = false
+	}
+	_ = p.MerkleRoot() // fill cache
+	return p.cache.nodes[gindex]
 }
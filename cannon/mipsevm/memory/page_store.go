@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const packFileSuffix = ".pack"
+
+// PageRef identifies the content of the page at Index by the merkle root of the page data.
+type PageRef struct {
+	Index Word
+	Hash  common.Hash
+}
+
+type pageLocation struct {
+	pack   string
+	offset int64
+	length uint32
+}
+
+// PageStore is a content addressed store of memory pages on disk.
+// Pages are identified by their merkle root, so a page that is present in many memory snapshots is only stored once.
+//
+// Pages are compressed and appended to immutable pack files, with one pack file written for each call to Store.
+// Each pack file is a concatenation of records:
+//
+//	page hash           [32]byte
+//	compressed length   uint32
+//	compressed data     [compressed length]byte
+//
+// Pack files are named by the keccak256 hash of their content.
+type PageStore struct {
+	dir   string
+	index map[common.Hash]pageLocation
+}
+
+func NewPageStore(dir string) *PageStore {
+	return &PageStore{dir: dir}
+}
+
+// Store writes the pages of m that are not yet in the store and returns references to all pages of m,
+// sorted by page index.
+func (s *PageStore) Store(m *Memory) ([]PageRef, error) {
+	if err := s.loadIndex(); err != nil {
+		return nil, err
+	}
+	refs := make([]PageRef, 0, len(m.pages))
+	var pack *packWriter
+	defer func() {
+		if pack != nil {
+			pack.abort()
+		}
+	}()
+	added := make(map[common.Hash]pageLocation)
+	for pageIndex, page := range m.pages {
+		hash := common.Hash(page.MerkleRoot())
+		refs = append(refs, PageRef{Index: pageIndex, Hash: hash})
+		if _, ok := s.index[hash]; ok {
+			continue
+		}
+		if _, ok := added[hash]; ok {
+			continue
+		}
+		if pack == nil {
+			var err error
+			pack, err = newPackWriter(s.dir)
+			if err != nil {
+				return nil, err
+			}
+		}
+		loc, err := pack.writePage(hash, page.Data)
+		if err != nil {
+			return nil, err
+		}
+		added[hash] = loc
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Index < refs[j].Index
+	})
+	if pack == nil {
+		return refs, nil
+	}
+	name, err := pack.finish()
+	if err != nil {
+		return nil, err
+	}
+	pack = nil
+	for hash, loc := range added {
+		loc.pack = name
+		s.index[hash] = loc
+	}
+	return refs, nil
+}
+
+// Load reads the referenced pages from the store into m.
+// The content of each page is verified against its reference.
+func (s *PageStore) Load(m *Memory, refs []PageRef) error {
+	if err := s.loadIndex(); err != nil {
+		return err
+	}
+	packs := make(map[string]*os.File)
+	defer func() {
+		for _, f := range packs {
+			_ = f.Close()
+		}
+	}()
+	compressed := make([]byte, 0, PageSize)
+	for _, ref := range refs {
+		loc, ok := s.index[ref.Hash]
+		if !ok {
+			return fmt.Errorf("page %x with hash %v not found in store %v", ref.Index, ref.Hash, s.dir)
+		}
+		f, ok := packs[loc.pack]
+		if !ok {
+			var err error
+			f, err = os.Open(filepath.Join(s.dir, loc.pack))
+			if err != nil {
+				return fmt.Errorf("failed to open page pack: %w", err)
+			}
+			packs[loc.pack] = f
+		}
+		compressed = compressed[:0]
+		if cap(compressed) < int(loc.length) {
+			compressed = make([]byte, 0, loc.length)
+		}
+		compressed = compressed[:loc.length]
+		if _, err := f.ReadAt(compressed, loc.offset); err != nil {
+			return fmt.Errorf("failed to read page %x from %v: %w", ref.Index, loc.pack, err)
+		}
+		page := m.AllocPage(ref.Index)
+		r := flate.NewReader(bytes.NewReader(compressed))
+		if _, err := io.ReadFull(r, page.Data[:]); err != nil {
+			return fmt.Errorf("failed to decompress page %x from %v: %w", ref.Index, loc.pack, err)
+		}
+		if actual := common.Hash(page.MerkleRoot()); actual != ref.Hash {
+			return fmt.Errorf("page %x content mismatch, expected hash %v but was %v", ref.Index, ref.Hash, actual)
+		}
+	}
+	return nil
+}
+
+// RemoveUnreferenced removes the pack files that contain none of the referenced pages and returns the total size of
+// the removed files. Packs modified after keepAfter are retained as they may hold the pages of a snapshot that is
+// still being written.
+func (s *PageStore) RemoveUnreferenced(refs map[common.Hash]bool, keepAfter time.Time) (uint64, error) {
+	if err := s.loadIndex(); err != nil {
+		return 0, err
+	}
+	used := make(map[string]bool)
+	for hash, loc := range s.index {
+		if refs[hash] {
+			used[loc.pack] = true
+		}
+	}
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to list page store %v: %w", s.dir, err)
+	}
+	var freed uint64
+	removed := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, packFileSuffix) || used[name] {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return freed, fmt.Errorf("failed to read page pack %v: %w", name, err)
+		}
+		if info.ModTime().After(keepAfter) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return freed, fmt.Errorf("failed to remove page pack %v: %w", name, err)
+		}
+		removed[name] = true
+		freed += uint64(info.Size())
+	}
+	for hash, loc := range s.index {
+		if removed[loc.pack] {
+			delete(s.index, hash)
+		}
+	}
+	return freed, nil
+}
+
+// loadIndex reads the page locations from the existing pack files, if not already loaded.
+func (s *PageStore) loadIndex() error {
+	if s.index != nil {
+		return nil
+	}
+	index := make(map[common.Hash]pageLocation)
+	entries, err := os.ReadDir(s.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to list page store %v: %w", s.dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), packFileSuffix) {
+			continue
+		}
+		if err := indexPack(s.dir, entry.Name(), index); err != nil {
+			return err
+		}
+	}
+	s.index = index
+	return nil
+}
+
+func indexPack(dir string, name string, index map[common.Hash]pageLocation) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to open page pack: %w", err)
+	}
+	defer f.Close()
+	var header [36]byte
+	offset := int64(0)
+	for {
+		if _, err := io.ReadFull(f, header[:]); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read page pack %v: %w", name, err)
+		}
+		length := binary.BigEndian.Uint32(header[32:])
+		offset += int64(len(header))
+		index[common.Hash(header[:32])] = pageLocation{pack: name, offset: offset, length: length}
+		offset, err = f.Seek(int64(length), io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to read page pack %v: %w", name, err)
+		}
+	}
+}
+
+// packWriter writes a new pack file to a temporary file, which is renamed to its final name when finished.
+type packWriter struct {
+	dir        string
+	file       *os.File
+	out        *bufio.Writer
+	hasher     crypto.KeccakState
+	offset     int64
+	compressed bytes.Buffer
+	compressor *flate.Writer
+}
+
+func newPackWriter(dir string) (*packWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create page store %v: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page pack: %w", err)
+	}
+	w := &packWriter{
+		dir:    dir,
+		file:   f,
+		hasher: crypto.NewKeccakState(),
+	}
+	if err := f.Chmod(0o644); err != nil {
+		w.abort()
+		return nil, fmt.Errorf("failed to create page pack: %w", err)
+	}
+	w.out = bufio.NewWriter(io.MultiWriter(f, w.hasher))
+	w.compressor, err = flate.NewWriter(&w.compressed, flate.BestSpeed)
+	if err != nil {
+		w.abort()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *packWriter) writePage(hash common.Hash, data *Page) (pageLocation, error) {
+	w.compressed.Reset()
+	w.compressor.Reset(&w.compressed)
+	if _, err := w.compressor.Write(data[:]); err != nil {
+		return pageLocation{}, err
+	}
+	if err := w.compressor.Close(); err != nil {
+		return pageLocation{}, err
+	}
+	var header [36]byte
+	copy(header[:32], hash[:])
+	binary.BigEndian.PutUint32(header[32:], uint32(w.compressed.Len()))
+	if _, err := w.out.Write(header[:]); err != nil {
+		return pageLocation{}, fmt.Errorf("failed to write page pack: %w", err)
+	}
+	loc := pageLocation{offset: w.offset + int64(len(header)), length: uint32(w.compressed.Len())}
+	if _, err := w.out.Write(w.compressed.Bytes()); err != nil {
+		return pageLocation{}, fmt.Errorf("failed to write page pack: %w", err)
+	}
+	w.offset = loc.offset + int64(loc.length)
+	return loc, nil
+}
+
+// finish closes the pack file and moves it to its final name, which is returned.
+func (w *packWriter) finish() (string, error) {
+	defer w.abort()
+	if err := w.out.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write page pack: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write page pack: %w", err)
+	}
+	name := common.BytesToHash(w.hasher.Sum(nil)).Hex()[2:] + packFileSuffix
+	if err := os.Rename(w.file.Name(), filepath.Join(w.dir, name)); err != nil {
+		return "", fmt.Errorf("failed to write page pack: %w", err)
+	}
+	return name, nil
+}
+
+// abort removes the temporary pack file. It is a no-op once the pack has been renamed.
+func (w *packWriter) abort() {
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
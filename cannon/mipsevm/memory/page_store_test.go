@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageStore(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		dir := t.TempDir()
+		m := NewMemory()
+		m.SetMemory(0x1000, 0xaabbccdd)
+		m.SetMemory(0x8000, 42)
+		m.AllocPage(0x30)
+
+		refs, err := NewPageStore(dir).Store(m)
+		require.NoError(t, err)
+		require.Len(t, refs, 3)
+		require.Equal(t, []Word{0x1, 0x8, 0x30}, []Word{refs[0].Index, refs[1].Index, refs[2].Index})
+
+		restored := NewMemory()
+		require.NoError(t, NewPageStore(dir).Load(restored, refs))
+		require.Equal(t, m.MerkleRoot(), restored.MerkleRoot())
+		require.Equal(t, Word(0xaabbccdd), restored.GetMemory(0x1000))
+		require.Equal(t, Word(42), restored.GetMemory(0x8000))
+	})
+
+	t.Run("DeduplicatePages", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewPageStore(dir)
+		m := NewMemory()
+		// Identical pages are only stored once
+		m.SetMemory(0x1000, 5)
+		m.SetMemory(0x2000, 5)
+		m.SetMemory(0x3000, 6)
+		refs, err := store.Store(m)
+		require.NoError(t, err)
+		require.Equal(t, refs[0].Hash, refs[1].Hash)
+		require.Equal(t, 1, countPacks(t, dir))
+		require.Equal(t, 2, countRecords(t, dir))
+
+		// Storing unchanged memory doesn't write a new pack
+		_, err = store.Store(m)
+		require.NoError(t, err)
+		require.Equal(t, 1, countPacks(t, dir))
+
+		// Only changed pages are written, including when using a new store for the same directory
+		m.SetMemory(0x3000, 7)
+		refs2, err := NewPageStore(dir).Store(m)
+		require.NoError(t, err)
+		require.Equal(t, 2, countPacks(t, dir))
+		require.Equal(t, 3, countRecords(t, dir))
+
+		for _, r := range [][]PageRef{refs, refs2} {
+			restored := NewMemory()
+			require.NoError(t, NewPageStore(dir).Load(restored, r))
+		}
+	})
+
+	t.Run("MissingPage", func(t *testing.T) {
+		dir := t.TempDir()
+		err := NewPageStore(dir).Load(NewMemory(), []PageRef{{Index: 1, Hash: common.Hash{0xaa}}})
+		require.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("CorruptPage", func(t *testing.T) {
+		dir := t.TempDir()
+		m := NewMemory()
+		m.SetMemory(0x1000, 5)
+		refs, err := NewPageStore(dir).Store(m)
+		require.NoError(t, err)
+
+		// Replace the stored page with a different page, keeping the original hash
+		other := NewMemory()
+		other.SetMemory(0x1000, 6)
+		otherDir := t.TempDir()
+		_, err = NewPageStore(otherDir).Store(other)
+		require.NoError(t, err)
+		data := readOnlyPack(t, otherDir)
+		copy(data[:32], refs[0].Hash[:])
+		packs, err := filepath.Glob(filepath.Join(dir, "*"+packFileSuffix))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(packs[0], data, 0o644))
+
+		err = NewPageStore(dir).Load(NewMemory(), refs)
+		require.ErrorContains(t, err, "content mismatch")
+	})
+}
+
+func countPacks(t *testing.T, dir string) int {
+	packs, err := filepath.Glob(filepath.Join(dir, "*"+packFileSuffix))
+	require.NoError(t, err)
+	return len(packs)
+}
+
+func countRecords(t *testing.T, dir string) int {
+	index := make(map[common.Hash]pageLocation)
+	packs, err := filepath.Glob(filepath.Join(dir, "*"+packFileSuffix))
+	require.NoError(t, err)
+	for _, pack := range packs {
+		require.NoError(t, indexPack(dir, filepath.Base(pack), index))
+	}
+	return len(index)
+}
+
+func readOnlyPack(t *testing.T, dir string) []byte {
+	packs, err := filepath.Glob(filepath.Join(dir, "*"+packFileSuffix))
+	require.NoError(t, err)
+	require.Len(t, packs, 1)
+	data, err := os.ReadFile(packs[0])
+	require.NoError(t, err)
+	return bytes.Clone(data)
+}
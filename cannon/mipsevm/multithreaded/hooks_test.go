@@ -0,0 +1,165 @@
+package multithreaded
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/testutil"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+const doubleSyscall = arch.Word(9000)
+
+// doubleHook doubles the $a1 words starting at the address in $a0 and returns the previous first word in $v0.
+// If $a2 is set, the length of the first chunk of the pre-image is added to each word instead.
+type doubleHook struct {
+	preimageKey [32]byte
+	err         error
+}
+
+func (h *doubleHook) Syscall() arch.Word {
+	return doubleSyscall
+}
+
+func (h *doubleHook) Name() string {
+	return "double"
+}
+
+func (h *doubleHook) Call(env mipsevm.HookEnv) (arch.Word, arch.Word, error) {
+	if h.err != nil {
+		return 0, 0, h.err
+	}
+	addr, count, readPreimage, _ := env.Args()
+	first := env.GetMemory(addr)
+	for i := arch.Word(0); i < count; i++ {
+		wordAddr := addr + i*arch.WordSizeBytes
+		v := env.GetMemory(wordAddr)
+		if readPreimage != 0 {
+			_, datLen := env.ReadPreimage(h.preimageKey, 0)
+			v += datLen
+		} else {
+			v *= 2
+		}
+		env.SetMemory(wordAddr, v)
+	}
+	return first, 0, nil
+}
+
+func TestAccelerationHooks(t *testing.T) {
+	const pc = arch.Word(0x1000)
+	const addr = arch.Word(0x8000)
+	preimageData := []byte{1, 2, 3, 4}
+	preimageKey := preimage.Keccak256Key(crypto.Keccak256Hash(preimageData)).PreimageKey()
+
+	setup := func(t *testing.T, hook *doubleHook, count arch.Word, readPreimage arch.Word) (*State, *InstrumentedState) {
+		hook.preimageKey = preimageKey
+		state := CreateEmptyState()
+		state.GetCurrentThread().Cpu.PC = pc
+		state.GetCurrentThread().Cpu.NextPC = pc + 4
+		require.NoError(t, state.Memory.SetMemoryRange(pc, bytes.NewReader([]byte{0x00, 0x00, 0x00, 0x0c})))
+		state.GetRegistersRef()[2] = doubleSyscall
+		state.GetRegistersRef()[4] = addr
+		state.GetRegistersRef()[5] = count
+		state.GetRegistersRef()[6] = readPreimage
+		state.Memory.SetMemory(addr, 21)
+		state.Memory.SetMemory(addr+arch.WordSizeBytes, 5)
+		state.Memory.SetMemory(addr+2*arch.WordSizeBytes, 7)
+		hooks, err := mipsevm.NewHooks(hook)
+		require.NoError(t, err)
+		oracle := testutil.StaticOracle(t, preimageData)
+		vm := NewInstrumentedState(state, oracle, nil, nil, testutil.CreateLogger(), nil)
+		vm.SetHooks(hooks)
+		return state, vm
+	}
+
+	t.Run("Call", func(t *testing.T) {
+		state, vm := setup(t, &doubleHook{}, 2, 0)
+		_, err := vm.Step(false)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), state.GetStep())
+		require.Equal(t, pc+4, state.GetPC())
+		require.Equal(t, pc+8, state.GetCpu().NextPC)
+		require.Equal(t, arch.Word(21), state.GetRegistersRef()[2])
+		require.Equal(t, arch.Word(0), state.GetRegistersRef()[7])
+		require.Equal(t, arch.Word(42), state.Memory.GetMemory(addr))
+		require.Equal(t, arch.Word(10), state.Memory.GetMemory(addr+arch.WordSizeBytes))
+	})
+
+	t.Run("Witness", func(t *testing.T) {
+		state, vm := setup(t, &doubleHook{}, 2, 0)
+		expectedMemProof := state.Memory.MerkleProof(addr)
+		// The second proof is taken when the hook accesses the second word, after it has updated the first word
+		state.Memory.SetMemory(addr, 42)
+		expectedMemProof2 := state.Memory.MerkleProof(addr + arch.WordSizeBytes)
+		state.Memory.SetMemory(addr, 21)
+		wit, err := vm.Step(true)
+		require.NoError(t, err)
+		proofs := wit.ProofData[len(wit.ProofData)-2*memory.MEM_PROOF_SIZE:]
+		require.Equal(t, expectedMemProof[:], proofs[:memory.MEM_PROOF_SIZE])
+		require.Equal(t, expectedMemProof2[:], proofs[memory.MEM_PROOF_SIZE:])
+		require.Equal(t, arch.Word(42), state.Memory.GetMemory(addr))
+	})
+
+	t.Run("WitnessIncludesPreimage", func(t *testing.T) {
+		state, vm := setup(t, &doubleHook{}, 1, 1)
+		wit, err := vm.Step(true)
+		require.NoError(t, err)
+		require.Equal(t, preimageKey, [32]byte(wit.PreimageKey))
+		require.Equal(t, arch.Word(0), wit.PreimageOffset)
+		require.Len(t, wit.PreimageValue, 8+len(preimageData))
+		// The chunk includes the 8 byte length prefix
+		require.Equal(t, arch.Word(21+8+len(preimageData)), state.Memory.GetMemory(addr))
+	})
+
+	t.Run("UnprovableMemoryAccess", func(t *testing.T) {
+		for _, proof := range []bool{false, true} {
+			_, vm := setup(t, &doubleHook{}, 3, 0)
+			_, err := vm.Step(proof)
+			require.ErrorIs(t, err, mipsevm.ErrHookNotProvable)
+		}
+	})
+
+	t.Run("UnprovablePreimageReads", func(t *testing.T) {
+		_, vm := setup(t, &doubleHook{}, 2, 1)
+		_, err := vm.Step(true)
+		require.ErrorIs(t, err, mipsevm.ErrHookNotProvable)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		state, vm := setup(t, &doubleHook{err: errors.New("boom")}, 1, 0)
+		_, err := vm.Step(false)
+		require.ErrorContains(t, err, "double")
+		require.Equal(t, pc, state.GetPC())
+	})
+
+	t.Run("OtherSyscalls", func(t *testing.T) {
+		state, vm := setup(t, &doubleHook{}, 1, 0)
+		state.GetRegistersRef()[2] = arch.SysGetpid
+		_, err := vm.Step(false)
+		require.NoError(t, err)
+		require.Equal(t, arch.Word(21), state.Memory.GetMemory(addr))
+	})
+
+	t.Run("NoHooks", func(t *testing.T) {
+		hooks, err := mipsevm.NewHooks()
+		require.NoError(t, err)
+		require.Nil(t, hooks.Lookup(doubleSyscall))
+	})
+
+	t.Run("NilHook", func(t *testing.T) {
+		_, err := mipsevm.NewHooks(nil)
+		require.ErrorContains(t, err, "nil acceleration hook")
+	})
+
+	t.Run("DuplicateSyscall", func(t *testing.T) {
+		_, err := mipsevm.NewHooks(&doubleHook{}, &doubleHook{})
+		require.ErrorContains(t, err, "same syscall")
+	})
+}
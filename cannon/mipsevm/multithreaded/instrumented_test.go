@@ -1,3 +1,6 @@
+//go:build !cannon64
+// +build !cannon64
+
 package multithreaded
 
 import (
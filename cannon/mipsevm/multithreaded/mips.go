@@ -3,12 +3,14 @@ package multithreaded
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
 )
@@ -17,18 +19,21 @@ func (m *InstrumentedState) handleSyscall() error {
 	thread := m.state.GetCurrentThread()
 
 	syscallNum, a0, a1, a2, a3 := exec.GetSyscallArgs(m.state.GetRegistersRef())
-	v0 := uint32(0)
-	v1 := uint32(0)
+	if hook := m.hooks.Lookup(syscallNum); hook != nil {
+		return m.callHook(thread, hook)
+	}
+	v0 := Word(0)
+	v1 := Word(0)
 
 	//fmt.Printf("syscall: %d\n", syscallNum)
 	switch syscallNum {
-	case exec.SysMmap:
-		var newHeap uint32
+	case arch.SysMmap:
+		var newHeap Word
 		v0, v1, newHeap = exec.HandleSysMmap(a0, a1, m.state.Heap)
 		m.state.Heap = newHeap
-	case exec.SysBrk:
+	case arch.SysBrk:
 		v0 = program.PROGRAM_BREAK
-	case exec.SysClone: // clone
+	case arch.SysClone: // clone
 		// a0 = flag bitmask, a1 = stack pointer
 		if exec.ValidCloneFlags != a0 {
 			m.state.Exited = true
@@ -69,33 +74,33 @@ func (m *InstrumentedState) handleSyscall() error {
 		// to ensure we are tracking in the context of the new thread
 		m.stackTracker.PushStack(stackCaller, stackTarget)
 		return nil
-	case exec.SysExitGroup:
+	case arch.SysExitGroup:
 		m.state.Exited = true
 		m.state.ExitCode = uint8(a0)
 		return nil
-	case exec.SysRead:
-		var newPreimageOffset uint32
+	case arch.SysRead:
+		var newPreimageOffset Word
 		var memUpdated bool
-		var memAddr uint32
+		var memAddr Word
 		v0, v1, newPreimageOffset, memUpdated, memAddr = exec.HandleSysRead(a0, a1, a2, m.state.PreimageKey, m.state.PreimageOffset, m.preimageOracle, m.state.Memory, m.memoryTracker)
 		m.state.PreimageOffset = newPreimageOffset
 		if memUpdated {
 			m.handleMemoryUpdate(memAddr)
 		}
-	case exec.SysWrite:
+	case arch.SysWrite:
 		var newLastHint hexutil.Bytes
 		var newPreimageKey common.Hash
-		var newPreimageOffset uint32
+		var newPreimageOffset Word
 		v0, v1, newLastHint, newPreimageKey, newPreimageOffset = exec.HandleSysWrite(a0, a1, a2, m.state.LastHint, m.state.PreimageKey, m.state.PreimageOffset, m.preimageOracle, m.state.Memory, m.memoryTracker, m.stdOut, m.stdErr)
 		m.state.LastHint = newLastHint
 		m.state.PreimageKey = newPreimageKey
 		m.state.PreimageOffset = newPreimageOffset
-	case exec.SysFcntl:
+	case arch.SysFcntl:
 		v0, v1 = exec.HandleSysFcntl(a0, a1)
-	case exec.SysGetTID:
+	case arch.SysGetTID:
 		v0 = thread.ThreadId
 		v1 = 0
-	case exec.SysExit:
+	case arch.SysExit:
 		thread.Exited = true
 		thread.ExitCode = uint8(a0)
 		if m.lastThreadRemaining() {
@@ -103,19 +108,20 @@ func (m *InstrumentedState) handleSyscall() error {
 			m.state.ExitCode = uint8(a0)
 		}
 		return nil
-	case exec.SysFutex:
+	case arch.SysFutex:
 		// args: a0 = addr, a1 = op, a2 = val, a3 = timeout
-		effAddr := a0 & 0xFFffFFfc
+		// futex words are 32 bits wide on both MIPS32 and MIPS64
+		effFutexAddr := a0 & ^Word(0x3)
 		switch a1 {
 		case exec.FutexWaitPrivate:
-			m.memoryTracker.TrackMemAccess(effAddr)
-			mem := m.state.Memory.GetMemory(effAddr)
-			if mem != a2 {
+			futexVal := m.loadFutexValue(effFutexAddr)
+			targetVal := Word(uint32(a2))
+			if futexVal != targetVal {
 				v0 = exec.SysErrorSignal
 				v1 = exec.MipsEAGAIN
 			} else {
-				thread.FutexAddr = effAddr
-				thread.FutexVal = a2
+				thread.FutexAddr = effFutexAddr
+				thread.FutexVal = targetVal
 				if a3 == 0 {
 					thread.FutexTimeoutStep = exec.FutexNoTimeout
 				} else {
@@ -127,7 +133,7 @@ func (m *InstrumentedState) handleSyscall() error {
 		case exec.FutexWakePrivate:
 			// Trigger thread traversal starting from the left stack until we find one waiting on the wakeup
 			// address
-			m.state.Wakeup = effAddr
+			m.state.Wakeup = effFutexAddr
 			// Don't indicate to the program that we've woken up a waiting thread, as there are no guarantees.
 			// The woken up thread should indicate this in userspace.
 			v0 = 0
@@ -140,74 +146,74 @@ func (m *InstrumentedState) handleSyscall() error {
 			v0 = exec.SysErrorSignal
 			v1 = exec.MipsEINVAL
 		}
-	case exec.SysSchedYield, exec.SysNanosleep:
+	case arch.SysSchedYield, arch.SysNanosleep:
 		v0 = 0
 		v1 = 0
 		exec.HandleSyscallUpdates(&thread.Cpu, &thread.Registers, v0, v1)
 		m.preemptThread(thread)
 		return nil
-	case exec.SysOpen:
+	case arch.SysOpen:
 		v0 = exec.SysErrorSignal
 		v1 = exec.MipsEBADF
-	case exec.SysClockGetTime:
+	case arch.SysClockGetTime:
 		switch a0 {
 		case exec.ClockGettimeRealtimeFlag, exec.ClockGettimeMonotonicFlag:
 			v0, v1 = 0, 0
-			var secs, nsecs uint32
+			var secs, nsecs Word
 			if a0 == exec.ClockGettimeMonotonicFlag {
 				// monotonic clock_gettime is used by Go guest programs for goroutine scheduling and to implement
 				// `time.Sleep` (and other sleep related operations).
-				secs = uint32(m.state.Step / exec.HZ)
-				nsecs = uint32((m.state.Step % exec.HZ) * (1_000_000_000 / exec.HZ))
+				secs = Word(m.state.Step / exec.HZ)
+				nsecs = Word((m.state.Step % exec.HZ) * (1_000_000_000 / exec.HZ))
 			} // else realtime set to Unix Epoch
 
-			effAddr := a1 & 0xFFffFFfc
+			// the timespec fields are word sized
+			effAddr := a1 & arch.AddressMask
 			m.memoryTracker.TrackMemAccess(effAddr)
 			m.state.Memory.SetMemory(effAddr, secs)
 			m.handleMemoryUpdate(effAddr)
-			m.memoryTracker.TrackMemAccess2(effAddr + 4)
-			m.state.Memory.SetMemory(effAddr+4, nsecs)
-			m.handleMemoryUpdate(effAddr + 4)
+			m.memoryTracker.TrackMemAccess2(effAddr + arch.WordSizeBytes)
+			m.state.Memory.SetMemory(effAddr+arch.WordSizeBytes, nsecs)
+			m.handleMemoryUpdate(effAddr + arch.WordSizeBytes)
 		default:
 			v0 = exec.SysErrorSignal
 			v1 = exec.MipsEINVAL
 		}
-	case exec.SysGetpid:
+	case arch.SysGetpid:
 		v0 = 0
 		v1 = 0
-	case exec.SysMunmap:
-	case exec.SysGetAffinity:
-	case exec.SysMadvise:
-	case exec.SysRtSigprocmask:
-	case exec.SysSigaltstack:
-	case exec.SysRtSigaction:
-	case exec.SysPrlimit64:
-	case exec.SysClose:
-	case exec.SysPread64:
-	case exec.SysFstat64:
-	case exec.SysOpenAt:
-	case exec.SysReadlink:
-	case exec.SysReadlinkAt:
-	case exec.SysIoctl:
-	case exec.SysEpollCreate1:
-	case exec.SysPipe2:
-	case exec.SysEpollCtl:
-	case exec.SysEpollPwait:
-	case exec.SysGetRandom:
-	case exec.SysUname:
-	case exec.SysStat64:
-	case exec.SysGetuid:
-	case exec.SysGetgid:
-	case exec.SysLlseek:
-	case exec.SysMinCore:
-	case exec.SysTgkill:
-	case exec.SysSetITimer:
-	case exec.SysTimerCreate:
-	case exec.SysTimerSetTime:
-	case exec.SysTimerDelete:
+	case arch.SysMunmap:
+	case arch.SysGetAffinity:
+	case arch.SysMadvise:
+	case arch.SysRtSigprocmask:
+	case arch.SysSigaltstack:
+	case arch.SysRtSigaction:
+	case arch.SysPrlimit64:
+	case arch.SysClose:
+	case arch.SysPread64:
+	case arch.SysOpenAt:
+	case arch.SysReadlink:
+	case arch.SysReadlinkAt:
+	case arch.SysIoctl:
+	case arch.SysEpollCreate1:
+	case arch.SysPipe2:
+	case arch.SysEpollCtl:
+	case arch.SysEpollPwait:
+	case arch.SysGetRandom:
+	case arch.SysUname:
+	case arch.SysGetuid:
+	case arch.SysGetgid:
+	case arch.SysMinCore:
+	case arch.SysTgkill:
+	case arch.SysSetITimer:
+	case arch.SysTimerCreate:
+	case arch.SysTimerSetTime:
+	case arch.SysTimerDelete:
 	default:
-		m.Traceback()
-		panic(fmt.Sprintf("unrecognized syscall: %d", syscallNum))
+		if !isArchSpecificNoopSyscall(syscallNum) {
+			m.Traceback()
+			panic(fmt.Sprintf("unrecognized syscall: %d", syscallNum))
+		}
 	}
 
 	exec.HandleSyscallUpdates(&thread.Cpu, &thread.Registers, v0, v1)
@@ -256,10 +262,7 @@ func (m *InstrumentedState) mipsStep() error {
 			m.onWaitComplete(thread, true)
 			return nil
 		} else {
-			effAddr := thread.FutexAddr & 0xFFffFFfc
-			m.memoryTracker.TrackMemAccess(effAddr)
-			mem := m.state.Memory.GetMemory(effAddr)
-			if thread.FutexVal == mem {
+			if thread.FutexVal == m.loadFutexValue(thread.FutexAddr) {
 				// still got expected value, continue sleeping, try next thread.
 				m.preemptThread(thread)
 				return nil
@@ -299,6 +302,9 @@ func (m *InstrumentedState) mipsStep() error {
 	if opcode == exec.OpLoadLinked || opcode == exec.OpStoreConditional {
 		return m.handleRMWOps(insn, opcode)
 	}
+	if !arch.IsMips32 && (opcode == exec.OpLoadLinked64 || opcode == exec.OpStoreConditional64) {
+		return m.handleRMWOps(insn, opcode)
+	}
 
 	// Exec the rest of the step logic
 	memUpdated, memAddr, err := exec.ExecMipsCoreStepLogic(m.state.getCpuRef(), m.state.GetRegistersRef(), m.state.Memory, insn, opcode, fun, m.memoryTracker, m.stackTracker)
@@ -312,8 +318,90 @@ func (m *InstrumentedState) mipsStep() error {
 	return nil
 }
 
-func (m *InstrumentedState) handleMemoryUpdate(memAddr uint32) {
-	if memAddr == m.state.LLAddress {
+// callHook executes the acceleration hook called by the current syscall.
+func (m *InstrumentedState) callHook(thread *ThreadState, hook mipsevm.AccelerationHook) error {
+	env := &hookEnv{m: m, thread: thread}
+	v0, v1, err := hook.Call(env)
+	if err == nil {
+		err = env.err
+	}
+	if err != nil {
+		return fmt.Errorf("acceleration hook %v failed: %w", hook.Name(), err)
+	}
+	exec.HandleSyscallUpdates(&thread.Cpu, &thread.Registers, v0, v1)
+	return nil
+}
+
+// hookEnv restricts the accesses of an acceleration hook to those that can be proven in a single step.
+type hookEnv struct {
+	m      *InstrumentedState
+	thread *ThreadState
+
+	// memAccesses are the addresses of the memory words accessed by the hook.
+	memAccesses []Word
+	// preimageRead is set once the hook has read pre-image data.
+	preimageRead bool
+	// err records the first access that could not be proven.
+	err error
+}
+
+func (e *hookEnv) Args() (a0, a1, a2, a3 Word) {
+	_, a0, a1, a2, a3 = exec.GetSyscallArgs(&e.thread.Registers)
+	return
+}
+
+func (e *hookEnv) GetMemory(addr Word) Word {
+	effAddr := addr & arch.AddressMask
+	if !e.trackMemAccess(effAddr) {
+		return 0
+	}
+	return e.m.state.Memory.GetMemory(effAddr)
+}
+
+func (e *hookEnv) SetMemory(addr Word, v Word) {
+	effAddr := addr & arch.AddressMask
+	if !e.trackMemAccess(effAddr) {
+		return
+	}
+	e.m.state.Memory.SetMemory(effAddr, v)
+	e.m.handleMemoryUpdate(effAddr)
+}
+
+func (e *hookEnv) ReadPreimage(key [32]byte, offset Word) (dat [32]byte, datLen Word) {
+	if e.preimageRead {
+		e.fail(fmt.Errorf("%w: multiple pre-image reads", mipsevm.ErrHookNotProvable))
+		return
+	}
+	e.preimageRead = true
+	return e.m.preimageOracle.ReadPreimage(key, offset)
+}
+
+// trackMemAccess records the access to the memory word at effAddr for the step witness.
+// Returns false if the access can't be proven, as the witness only holds proofs for two consecutive words.
+func (e *hookEnv) trackMemAccess(effAddr Word) bool {
+	switch {
+	case slices.Contains(e.memAccesses, effAddr):
+		return true
+	case len(e.memAccesses) == 0:
+		e.m.memoryTracker.TrackMemAccess(effAddr)
+	case len(e.memAccesses) == 1 && effAddr == e.memAccesses[0]+arch.WordSizeBytes:
+		e.m.memoryTracker.TrackMemAccess2(effAddr)
+	default:
+		e.fail(fmt.Errorf("%w: memory access at %x after accesses at %x", mipsevm.ErrHookNotProvable, effAddr, e.memAccesses))
+		return false
+	}
+	e.memAccesses = append(e.memAccesses, effAddr)
+	return true
+}
+
+func (e *hookEnv) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (m *InstrumentedState) handleMemoryUpdate(memAddr Word) {
+	if memAddr == m.state.LLAddress&arch.AddressMask {
 		// Reserved address was modified, clear the reservation
 		m.clearLLMemoryReservation()
 	}
@@ -329,27 +417,33 @@ func (m *InstrumentedState) clearLLMemoryReservation() {
 func (m *InstrumentedState) handleRMWOps(insn, opcode uint32) error {
 	baseReg := (insn >> 21) & 0x1F
 	base := m.state.GetRegistersRef()[baseReg]
-	rtReg := (insn >> 16) & 0x1F
+	rtReg := Word((insn >> 16) & 0x1F)
 	offset := exec.SignExtendImmediate(insn)
 
-	effAddr := (base + offset) & 0xFFFFFFFC
-	m.memoryTracker.TrackMemAccess(effAddr)
-	mem := m.state.Memory.GetMemory(effAddr)
+	// ll and sc operate on 4 bytes, lld and scd on 8 bytes
+	byteLength := Word(4)
+	if opcode == exec.OpLoadLinked64 || opcode == exec.OpStoreConditional64 {
+		byteLength = 8
+	}
+	effAddr := (base + offset) & ^(byteLength - 1)
+	wordAddr := effAddr & arch.AddressMask
+	m.memoryTracker.TrackMemAccess(wordAddr)
+	mem := m.state.Memory.GetMemory(wordAddr)
 
-	var retVal uint32
+	var retVal Word
 	threadId := m.state.GetCurrentThread().ThreadId
-	if opcode == exec.OpLoadLinked {
-		retVal = mem
+	if opcode == exec.OpLoadLinked || opcode == exec.OpLoadLinked64 {
+		retVal = exec.SelectSubWord(effAddr, mem, byteLength, true)
 		m.state.LLReservationActive = true
 		m.state.LLAddress = effAddr
 		m.state.LLOwnerThread = threadId
-	} else if opcode == exec.OpStoreConditional {
+	} else if opcode == exec.OpStoreConditional || opcode == exec.OpStoreConditional64 {
 		// Check if our memory reservation is still intact
 		if m.state.LLReservationActive && m.state.LLOwnerThread == threadId && m.state.LLAddress == effAddr {
 			// Complete atomic update: set memory and return 1 for success
 			m.clearLLMemoryReservation()
 			rt := m.state.GetRegistersRef()[rtReg]
-			m.state.Memory.SetMemory(effAddr, rt)
+			m.state.Memory.SetMemory(wordAddr, exec.UpdateSubWord(effAddr, mem, byteLength, rt))
 			retVal = 1
 		} else {
 			// Atomic update failed, return 0 for failure
@@ -362,6 +456,14 @@ func (m *InstrumentedState) handleRMWOps(insn, opcode uint32) error {
 	return exec.HandleRd(m.state.getCpuRef(), m.state.GetRegistersRef(), rtReg, retVal, true)
 }
 
+// loadFutexValue reads the 32-bit futex value at the 4-byte aligned addr.
+func (m *InstrumentedState) loadFutexValue(addr Word) Word {
+	wordAddr := addr & arch.AddressMask
+	m.memoryTracker.TrackMemAccess(wordAddr)
+	mem := m.state.Memory.GetMemory(wordAddr)
+	return exec.SelectSubWord(addr, mem, 4, false)
+}
+
 func (m *InstrumentedState) onWaitComplete(thread *ThreadState, isTimedOut bool) {
 	// Clear the futex state
 	thread.FutexAddr = exec.FutexEmptyAddr
@@ -369,8 +471,8 @@ func (m *InstrumentedState) onWaitComplete(thread *ThreadState, isTimedOut bool)
 	thread.FutexTimeoutStep = 0
 
 	// Complete the FUTEX_WAIT syscall
-	v0 := uint32(0)
-	v1 := uint32(0)
+	v0 := Word(0)
+	v1 := Word(0)
 	if isTimedOut {
 		v0 = exec.SysErrorSignal
 		v1 = exec.MipsETIMEDOUT
@@ -436,3 +538,18 @@ func (m *InstrumentedState) popThread() {
 func (m *InstrumentedState) lastThreadRemaining() bool {
 	return m.state.ThreadCount() == 1
 }
+
+// archSpecificNoopSyscalls are no-op syscalls that only exist on one of MIPS32 or MIPS64.
+// They are arch.UndefinedSysNr on the other architecture so can't be used as switch cases.
+var archSpecificNoopSyscalls = []Word{
+	arch.SysFstat64,
+	arch.SysStat64,
+	arch.SysLlseek,
+	arch.SysFstat,
+	arch.SysGetRLimit,
+	arch.SysLseek,
+}
+
+func isArchSpecificNoopSyscall(syscallNum Word) bool {
+	return syscallNum != arch.UndefinedSysNr && slices.Contains(archSpecificNoopSyscalls, syscallNum)
+}
@@ -4,12 +4,13 @@ import (
 	"errors"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
 )
 
 type ThreadedStackTracker interface {
 	exec.TraceableStackTracker
-	DropThread(threadId uint32)
+	DropThread(threadId arch.Word)
 }
 
 type NoopThreadedStackTracker struct {
@@ -18,12 +19,12 @@ type NoopThreadedStackTracker struct {
 
 var _ ThreadedStackTracker = (*ThreadedStackTrackerImpl)(nil)
 
-func (n *NoopThreadedStackTracker) DropThread(threadId uint32) {}
+func (n *NoopThreadedStackTracker) DropThread(threadId arch.Word) {}
 
 type ThreadedStackTrackerImpl struct {
 	meta               mipsevm.Metadata
 	state              *State
-	trackersByThreadId map[uint32]exec.TraceableStackTracker
+	trackersByThreadId map[arch.Word]exec.TraceableStackTracker
 }
 
 var _ ThreadedStackTracker = (*ThreadedStackTrackerImpl)(nil)
@@ -36,11 +37,11 @@ func NewThreadedStackTracker(state *State, meta mipsevm.Metadata) (*ThreadedStac
 	return &ThreadedStackTrackerImpl{
 		state:              state,
 		meta:               meta,
-		trackersByThreadId: make(map[uint32]exec.TraceableStackTracker),
+		trackersByThreadId: make(map[arch.Word]exec.TraceableStackTracker),
 	}, nil
 }
 
-func (t *ThreadedStackTrackerImpl) PushStack(caller uint32, target uint32) {
+func (t *ThreadedStackTrackerImpl) PushStack(caller arch.Word, target arch.Word) {
 	t.getCurrentTracker().PushStack(caller, target)
 }
 
@@ -62,6 +63,6 @@ func (t *ThreadedStackTrackerImpl) getCurrentTracker() exec.TraceableStackTracke
 	return tracker
 }
 
-func (t *ThreadedStackTrackerImpl) DropThread(threadId uint32) {
+func (t *ThreadedStackTrackerImpl) DropThread(threadId arch.Word) {
 	delete(t.trackersByThreadId, threadId)
 }
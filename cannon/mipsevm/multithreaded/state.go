@@ -11,27 +11,30 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
 	"github.com/ethereum-optimism/optimism/cannon/serialize"
 )
 
+type Word = arch.Word
+
 // STATE_WITNESS_SIZE is the size of the state witness encoding in bytes.
-const STATE_WITNESS_SIZE = 172
+const STATE_WITNESS_SIZE = 148 + 6*arch.WordSizeBytes
 const (
 	MEMROOT_WITNESS_OFFSET                    = 0
 	PREIMAGE_KEY_WITNESS_OFFSET               = MEMROOT_WITNESS_OFFSET + 32
 	PREIMAGE_OFFSET_WITNESS_OFFSET            = PREIMAGE_KEY_WITNESS_OFFSET + 32
-	HEAP_WITNESS_OFFSET                       = PREIMAGE_OFFSET_WITNESS_OFFSET + 4
-	LL_RESERVATION_ACTIVE_OFFSET              = HEAP_WITNESS_OFFSET + 4
+	HEAP_WITNESS_OFFSET                       = PREIMAGE_OFFSET_WITNESS_OFFSET + arch.WordSizeBytes
+	LL_RESERVATION_ACTIVE_OFFSET              = HEAP_WITNESS_OFFSET + arch.WordSizeBytes
 	LL_ADDRESS_OFFSET                         = LL_RESERVATION_ACTIVE_OFFSET + 1
-	LL_OWNER_THREAD_OFFSET                    = LL_ADDRESS_OFFSET + 4
-	EXITCODE_WITNESS_OFFSET                   = LL_OWNER_THREAD_OFFSET + 4
+	LL_OWNER_THREAD_OFFSET                    = LL_ADDRESS_OFFSET + arch.WordSizeBytes
+	EXITCODE_WITNESS_OFFSET                   = LL_OWNER_THREAD_OFFSET + arch.WordSizeBytes
 	EXITED_WITNESS_OFFSET                     = EXITCODE_WITNESS_OFFSET + 1
 	STEP_WITNESS_OFFSET                       = EXITED_WITNESS_OFFSET + 1
 	STEPS_SINCE_CONTEXT_SWITCH_WITNESS_OFFSET = STEP_WITNESS_OFFSET + 8
 	WAKEUP_WITNESS_OFFSET                     = STEPS_SINCE_CONTEXT_SWITCH_WITNESS_OFFSET + 8
-	TRAVERSE_RIGHT_WITNESS_OFFSET             = WAKEUP_WITNESS_OFFSET + 4
+	TRAVERSE_RIGHT_WITNESS_OFFSET             = WAKEUP_WITNESS_OFFSET + arch.WordSizeBytes
 	LEFT_THREADS_ROOT_WITNESS_OFFSET          = TRAVERSE_RIGHT_WITNESS_OFFSET + 1
 	RIGHT_THREADS_ROOT_WITNESS_OFFSET         = LEFT_THREADS_ROOT_WITNESS_OFFSET + 32
 	THREAD_ID_WITNESS_OFFSET                  = RIGHT_THREADS_ROOT_WITNESS_OFFSET + 32
@@ -41,24 +44,24 @@ type State struct {
 	Memory *memory.Memory
 
 	PreimageKey    common.Hash
-	PreimageOffset uint32 // note that the offset includes the 8-byte length prefix
+	PreimageOffset arch.Word // note that the offset includes the 8-byte length prefix
 
-	Heap                uint32 // to handle mmap growth
-	LLReservationActive bool   // Whether there is an active memory reservation initiated via the LL (load linked) op
-	LLAddress           uint32 // The "linked" memory address reserved via the LL (load linked) op
-	LLOwnerThread       uint32 // The id of the thread that holds the reservation on LLAddress
+	Heap                arch.Word // to handle mmap growth
+	LLReservationActive bool      // Whether there is an active memory reservation initiated via the LL (load linked) op
+	LLAddress           arch.Word // The "linked" memory address reserved via the LL (load linked) op
+	LLOwnerThread       arch.Word // The id of the thread that holds the reservation on LLAddress
 
 	ExitCode uint8
 	Exited   bool
 
 	Step                        uint64
 	StepsSinceLastContextSwitch uint64
-	Wakeup                      uint32
+	Wakeup                      arch.Word
 
 	TraverseRight    bool
 	LeftThreadStack  []*ThreadState
 	RightThreadStack []*ThreadState
-	NextThreadId     uint32
+	NextThreadId     arch.Word
 
 	// LastHint is optional metadata, and not part of the VM state itself.
 	LastHint hexutil.Bytes
@@ -86,7 +89,7 @@ func CreateEmptyState() *State {
 	}
 }
 
-func CreateInitialState(pc, heapStart uint32) *State {
+func CreateInitialState(pc, heapStart arch.Word) *State {
 	state := CreateEmptyState()
 	currentThread := state.GetCurrentThread()
 	currentThread.Cpu.PC = pc
@@ -140,7 +143,7 @@ func (s *State) calculateThreadStackRoot(stack []*ThreadState) common.Hash {
 	return curRoot
 }
 
-func (s *State) GetPC() uint32 {
+func (s *State) GetPC() arch.Word {
 	activeThread := s.GetCurrentThread()
 	return activeThread.Cpu.PC
 }
@@ -154,7 +157,7 @@ func (s *State) getCpuRef() *mipsevm.CpuScalars {
 	return &s.GetCurrentThread().Cpu
 }
 
-func (s *State) GetRegistersRef() *[32]uint32 {
+func (s *State) GetRegistersRef() *[32]arch.Word {
 	activeThread := s.GetCurrentThread()
 	return &activeThread.Registers
 }
@@ -177,7 +180,7 @@ func (s *State) GetMemory() *memory.Memory {
 	return s.Memory
 }
 
-func (s *State) GetHeap() uint32 {
+func (s *State) GetHeap() arch.Word {
 	return s.Heap
 }
 
@@ -185,7 +188,7 @@ func (s *State) GetPreimageKey() common.Hash {
 	return s.PreimageKey
 }
 
-func (s *State) GetPreimageOffset() uint32 {
+func (s *State) GetPreimageOffset() arch.Word {
 	return s.PreimageOffset
 }
 
@@ -194,24 +197,24 @@ func (s *State) EncodeWitness() ([]byte, common.Hash) {
 	memRoot := s.Memory.MerkleRoot()
 	out = append(out, memRoot[:]...)
 	out = append(out, s.PreimageKey[:]...)
-	out = binary.BigEndian.AppendUint32(out, s.PreimageOffset)
-	out = binary.BigEndian.AppendUint32(out, s.Heap)
+	out = arch.ByteOrderWord.AppendWord(out, s.PreimageOffset)
+	out = arch.ByteOrderWord.AppendWord(out, s.Heap)
 	out = mipsevm.AppendBoolToWitness(out, s.LLReservationActive)
-	out = binary.BigEndian.AppendUint32(out, s.LLAddress)
-	out = binary.BigEndian.AppendUint32(out, s.LLOwnerThread)
+	out = arch.ByteOrderWord.AppendWord(out, s.LLAddress)
+	out = arch.ByteOrderWord.AppendWord(out, s.LLOwnerThread)
 	out = append(out, s.ExitCode)
 	out = mipsevm.AppendBoolToWitness(out, s.Exited)
 
 	out = binary.BigEndian.AppendUint64(out, s.Step)
 	out = binary.BigEndian.AppendUint64(out, s.StepsSinceLastContextSwitch)
-	out = binary.BigEndian.AppendUint32(out, s.Wakeup)
+	out = arch.ByteOrderWord.AppendWord(out, s.Wakeup)
 
 	leftStackRoot := s.getLeftThreadStackRoot()
 	rightStackRoot := s.getRightThreadStackRoot()
 	out = mipsevm.AppendBoolToWitness(out, s.TraverseRight)
 	out = append(out, (leftStackRoot)[:]...)
 	out = append(out, (rightStackRoot)[:]...)
-	out = binary.BigEndian.AppendUint32(out, s.NextThreadId)
+	out = arch.ByteOrderWord.AppendWord(out, s.NextThreadId)
 
 	return out, stateHashFromWitness(out)
 }
@@ -243,18 +246,21 @@ func (s *State) ThreadCount() int {
 // The format is a simple concatenation of fields, with prefixed item count for repeating items and using big endian
 // encoding for numbers.
 //
-// StateVersion                uint8(1)
+// StateVersion                uint8(1) for MIPS32, uint8(2) for MIPS64
 // Memory                      As per Memory.Serialize
 // PreimageKey                 [32]byte
-// PreimageOffset              uint32
-// Heap                        uint32
+// PreimageOffset              Word
+// Heap                        Word
+// LLReservationActive         uint8 - 0 for false, 1 for true
+// LLAddress                   Word
+// LLOwnerThread               Word
 // ExitCode                    uint8
 // Exited                      uint8 - 0 for false, 1 for true
 // Step                        uint64
 // StepsSinceLastContextSwitch uint64
-// Wakeup                      uint32
+// Wakeup                      Word
 // TraverseRight               uint8 - 0 for false, 1 for true
-// NextThreadId                uint32
+// NextThreadId                Word
 // len(LeftThreadStack)        uint32
 // LeftThreadStack entries     as per ThreadState.Serialize
 // len(RightThreadStack)       uint32
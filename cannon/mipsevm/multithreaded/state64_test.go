@@ -0,0 +1,101 @@
+//go:build cannon64
+// +build cannon64
+
+package multithreaded
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+)
+
+func TestState64_EncodeWitness(t *testing.T) {
+	state := CreateEmptyState()
+	state.Heap = 0x10_00_00_00_00_00_00_00
+	state.LLAddress = 0x7F_FF_FF_FF_D0_00_00_08
+	state.Exited = true
+	state.ExitCode = 1
+
+	witness, hash := state.EncodeWitness()
+	require.Len(t, witness, STATE_WITNESS_SIZE)
+	require.Equal(t, 196, STATE_WITNESS_SIZE)
+	require.Equal(t, uint8(mipsevm.VMStatusInvalid), hash[0])
+	require.Equal(t, hexutil.Bytes{0x10, 0, 0, 0, 0, 0, 0, 0}, hexutil.Bytes(witness[HEAP_WITNESS_OFFSET:HEAP_WITNESS_OFFSET+8]))
+	require.Equal(t, uint8(1), witness[EXITCODE_WITNESS_OFFSET])
+	require.Equal(t, uint8(1), witness[EXITED_WITNESS_OFFSET])
+
+	threadWitness := state.GetCurrentThread().serializeThread()
+	require.Len(t, threadWitness, SERIALIZED_THREAD_SIZE)
+	require.Len(t, state.EncodeThreadProof(), THREAD_WITNESS_SIZE)
+}
+
+func TestSerializeStateRoundTrip64(t *testing.T) {
+	mem := memory.NewMemory()
+	mem.AllocPage(5)
+	p := mem.AllocPage(0x7F_FF_FF_FF_D0_00_0)
+	p.Data[2] = 0x01
+	state := &State{
+		Memory:                      mem,
+		PreimageKey:                 common.Hash{0xFF},
+		PreimageOffset:              5,
+		Heap:                        0x10_00_00_00_00_c0_ff_ee,
+		LLReservationActive:         true,
+		LLAddress:                   0x12345678_9abcdef0,
+		LLOwnerThread:               0x02,
+		ExitCode:                    1,
+		Exited:                      true,
+		Step:                        0xdeadbeef,
+		StepsSinceLastContextSwitch: 334,
+		Wakeup:                      0xFFFF_FFFF_FFFF_FFFF,
+		TraverseRight:               true,
+		LeftThreadStack: []*ThreadState{
+			{
+				ThreadId:         45,
+				ExitCode:         46,
+				Exited:           true,
+				FutexAddr:        0x7F_FF_FF_FF_D0_00_00_40,
+				FutexVal:         48,
+				FutexTimeoutStep: 49,
+				Cpu: mipsevm.CpuScalars{
+					PC:     0x1_0000_00FF,
+					NextPC: 0x1_0000_00FF + 4,
+					LO:     0xbeef_0000_0000,
+					HI:     0xbabe,
+				},
+				Registers: [32]uint64{
+					0xdeadbeef_deadbeef,
+					0xc0ffee,
+					0xbeefbabe_00000000,
+				},
+			},
+		},
+		RightThreadStack: []*ThreadState{
+			{
+				ThreadId: 55,
+				Cpu: mipsevm.CpuScalars{
+					PC:     0xaa,
+					NextPC: 0xaa + 4,
+				},
+				Registers: [32]uint64{
+					0xFFFF_FFFF_FFFF_FFFF,
+				},
+			},
+		},
+		NextThreadId: 489,
+		LastHint:     hexutil.Bytes{1, 2, 3, 4, 5},
+	}
+
+	ser := new(bytes.Buffer)
+	err := state.Serialize(ser)
+	require.NoError(t, err, "must serialize state")
+	state2 := &State{}
+	err = state2.Deserialize(ser)
+	require.NoError(t, err, "must deserialize state")
+	require.Equal(t, state, state2, "must roundtrip state")
+}
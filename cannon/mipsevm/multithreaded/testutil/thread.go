@@ -1,6 +1,7 @@
 package testutil
 
 import (
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/testutil"
 )
@@ -14,8 +15,8 @@ func RandomThread(randSeed int64) *multithreaded.ThreadState {
 	thread.Registers = *r.RandRegisters()
 	thread.Cpu.PC = pc
 	thread.Cpu.NextPC = pc + 4
-	thread.Cpu.HI = r.Uint32()
-	thread.Cpu.LO = r.Uint32()
+	thread.Cpu.HI = r.Word()
+	thread.Cpu.LO = r.Word()
 
 	return thread
 }
@@ -37,7 +38,7 @@ func InitializeSingleThread(randSeed int, state *multithreaded.State, traverseRi
 func SetupThreads(randomSeed int64, state *multithreaded.State, traverseRight bool, activeStackSize, otherStackSize int) {
 	var activeStack, otherStack []*multithreaded.ThreadState
 
-	tid := uint32(0)
+	tid := arch.Word(0)
 	for i := 0; i < activeStackSize; i++ {
 		thread := RandomThread(randomSeed + int64(i))
 		thread.ThreadId = tid
@@ -129,13 +130,13 @@ func FindNextThreadFiltered(state *multithreaded.State, filter ThreadFilter) *mu
 	return nil
 }
 
-func FindNextThreadExcluding(state *multithreaded.State, threadId uint32) *multithreaded.ThreadState {
+func FindNextThreadExcluding(state *multithreaded.State, threadId arch.Word) *multithreaded.ThreadState {
 	return FindNextThreadFiltered(state, func(t *multithreaded.ThreadState) bool {
 		return t.ThreadId != threadId
 	})
 }
 
-func FindThread(state *multithreaded.State, threadId uint32) *multithreaded.ThreadState {
+func FindThread(state *multithreaded.State, threadId arch.Word) *multithreaded.ThreadState {
 	for _, t := range GetAllThreads(state) {
 		if t.ThreadId == threadId {
 			return t
@@ -8,11 +8,12 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
 )
 
 // SERIALIZED_THREAD_SIZE is the size of a serialized ThreadState object
-const SERIALIZED_THREAD_SIZE = 166
+const SERIALIZED_THREAD_SIZE = 10 + 39*arch.WordSizeBytes
 
 // THREAD_WITNESS_SIZE is the size of a thread witness encoded in bytes.
 //
@@ -24,18 +25,18 @@ const THREAD_WITNESS_SIZE = SERIALIZED_THREAD_SIZE + 32
 var EmptyThreadsRoot common.Hash = common.HexToHash("0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5")
 
 type ThreadState struct {
-	ThreadId         uint32             `json:"threadId"`
+	ThreadId         arch.Word          `json:"threadId"`
 	ExitCode         uint8              `json:"exit"`
 	Exited           bool               `json:"exited"`
-	FutexAddr        uint32             `json:"futexAddr"`
-	FutexVal         uint32             `json:"futexVal"`
+	FutexAddr        arch.Word          `json:"futexAddr"`
+	FutexVal         arch.Word          `json:"futexVal"`
 	FutexTimeoutStep uint64             `json:"futexTimeoutStep"`
 	Cpu              mipsevm.CpuScalars `json:"cpu"`
-	Registers        [32]uint32         `json:"registers"`
+	Registers        [32]arch.Word      `json:"registers"`
 }
 
 func CreateEmptyThread() *ThreadState {
-	initThreadId := uint32(0)
+	initThreadId := arch.Word(0)
 	return &ThreadState{
 		ThreadId: initThreadId,
 		ExitCode: 0,
@@ -49,27 +50,27 @@ func CreateEmptyThread() *ThreadState {
 		FutexAddr:        exec.FutexEmptyAddr,
 		FutexVal:         0,
 		FutexTimeoutStep: 0,
-		Registers:        [32]uint32{},
+		Registers:        [32]arch.Word{},
 	}
 }
 
 func (t *ThreadState) serializeThread() []byte {
 	out := make([]byte, 0, SERIALIZED_THREAD_SIZE)
 
-	out = binary.BigEndian.AppendUint32(out, t.ThreadId)
+	out = arch.ByteOrderWord.AppendWord(out, t.ThreadId)
 	out = append(out, t.ExitCode)
 	out = mipsevm.AppendBoolToWitness(out, t.Exited)
-	out = binary.BigEndian.AppendUint32(out, t.FutexAddr)
-	out = binary.BigEndian.AppendUint32(out, t.FutexVal)
+	out = arch.ByteOrderWord.AppendWord(out, t.FutexAddr)
+	out = arch.ByteOrderWord.AppendWord(out, t.FutexVal)
 	out = binary.BigEndian.AppendUint64(out, t.FutexTimeoutStep)
 
-	out = binary.BigEndian.AppendUint32(out, t.Cpu.PC)
-	out = binary.BigEndian.AppendUint32(out, t.Cpu.NextPC)
-	out = binary.BigEndian.AppendUint32(out, t.Cpu.LO)
-	out = binary.BigEndian.AppendUint32(out, t.Cpu.HI)
+	out = arch.ByteOrderWord.AppendWord(out, t.Cpu.PC)
+	out = arch.ByteOrderWord.AppendWord(out, t.Cpu.NextPC)
+	out = arch.ByteOrderWord.AppendWord(out, t.Cpu.LO)
+	out = arch.ByteOrderWord.AppendWord(out, t.Cpu.HI)
 
 	for _, r := range t.Registers {
-		out = binary.BigEndian.AppendUint32(out, r)
+		out = arch.ByteOrderWord.AppendWord(out, r)
 	}
 
 	return out
@@ -115,7 +116,7 @@ func (t *ThreadState) Deserialize(in io.Reader) error {
 	if err := binary.Read(in, binary.BigEndian, &t.Cpu.HI); err != nil {
 		return err
 	}
-	// Read the registers as big endian uint32s
+	// Read the registers as big endian words
 	for i := range t.Registers {
 		if err := binary.Read(in, binary.BigEndian, &t.Registers[i]); err != nil {
 			return err
@@ -3,52 +3,213 @@ package program
 import (
 	"bytes"
 	"debug/elf"
+	"encoding/binary"
 	"fmt"
 	"io"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 )
 
 const (
-	HEAP_START    = 0x05_00_00_00
-	HEAP_END      = 0x60_00_00_00
-	PROGRAM_BREAK = 0x40_00_00_00
+	HEAP_START    = arch.HeapStart
+	HEAP_END      = arch.HeapEnd
+	PROGRAM_BREAK = arch.ProgramBreak
 )
 
-type CreateInitialFPVMState[T mipsevm.FPVMState] func(pc, heapStart uint32) T
+type CreateInitialFPVMState[T mipsevm.FPVMState] func(pc, heapStart arch.Word) T
 
+// LoadELF loads the PT_LOAD segments of a statically linked executable into a new state.
+// Static-PIE executables are loaded at their link-time addresses, i.e. with a load bias of zero.
+// Their relative relocations are applied at that bias, which also makes any self-relocation
+// done by the program's startup code a no-op.
 func LoadELF[T mipsevm.FPVMState](f *elf.File, initState CreateInitialFPVMState[T]) (T, error) {
 	var empty T
-	s := initState(uint32(f.Entry), HEAP_START)
+	if expected := elfClass(); f.Class != expected {
+		return empty, fmt.Errorf("unsupported ELF class %v, this build of cannon requires %v", f.Class, expected)
+	}
+	if f.Type != elf.ET_EXEC && f.Type != elf.ET_DYN {
+		return empty, fmt.Errorf("unsupported ELF type %v, must be an executable", f.Type)
+	}
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_INTERP {
+			return empty, fmt.Errorf("dynamically linked ELF files are not supported, the program must be statically linked")
+		}
+	}
+	s := initState(arch.Word(f.Entry), HEAP_START)
 
 	for i, prog := range f.Progs {
-		if prog.Type == 0x70000003 { // MIPS_ABIFLAGS
+		// Other segments, such as PT_TLS, PT_PHDR or PT_GNU_RELRO, describe data within the PT_LOAD segments
+		// or are only hints to the loader.
+		if prog.Type != elf.PT_LOAD || prog.Memsz == 0 {
 			continue
 		}
 
 		r := io.Reader(io.NewSectionReader(prog, 0, int64(prog.Filesz)))
-		if prog.Filesz != prog.Memsz {
-			if prog.Type == elf.PT_LOAD {
-				if prog.Filesz < prog.Memsz {
-					r = io.MultiReader(r, bytes.NewReader(make([]byte, prog.Memsz-prog.Filesz)))
-				} else {
-					return empty, fmt.Errorf("invalid PT_LOAD program segment %d, file size (%d) > mem size (%d)", i, prog.Filesz, prog.Memsz)
-				}
-			} else {
-				return empty, fmt.Errorf("program segment %d has different file size (%d) than mem size (%d): filling for non PT_LOAD segments is not supported", i, prog.Filesz, prog.Memsz)
-			}
+		if prog.Filesz < prog.Memsz {
+			r = io.MultiReader(r, bytes.NewReader(make([]byte, prog.Memsz-prog.Filesz)))
+		} else if prog.Filesz > prog.Memsz {
+			return empty, fmt.Errorf("invalid PT_LOAD program segment %d, file size (%d) > mem size (%d)", i, prog.Filesz, prog.Memsz)
 		}
 
-		if prog.Vaddr+prog.Memsz >= uint64(1<<32) {
+		if arch.IsMips32 && prog.Vaddr+prog.Memsz >= uint64(1<<32) {
 			return empty, fmt.Errorf("program %d out of 32-bit mem range: %x - %x (size: %x)", i, prog.Vaddr, prog.Vaddr+prog.Memsz, prog.Memsz)
 		}
 		if prog.Vaddr+prog.Memsz >= HEAP_START {
 			return empty, fmt.Errorf("program %d overlaps with heap: %x - %x (size: %x). The heap start offset must be reconfigured", i, prog.Vaddr, prog.Vaddr+prog.Memsz, prog.Memsz)
 		}
-		if err := s.GetMemory().SetMemoryRange(uint32(prog.Vaddr), r); err != nil {
+		if err := s.GetMemory().SetMemoryRange(arch.Word(prog.Vaddr), r); err != nil {
 			return empty, fmt.Errorf("failed to read program segment %d: %w", i, err)
 		}
 	}
 
+	if f.Type == elf.ET_DYN {
+		if err := applyRelocations(f, s); err != nil {
+			return empty, err
+		}
+	}
 	return s, nil
 }
+
+// applyRelocations applies the dynamic relocations of a static-PIE executable loaded with a load bias of zero.
+// Only relative relocations are supported, as there is no dynamic linker to resolve symbols.
+// Relocations with implicit addends (REL and RELR) already hold the relocated value, so only the explicit
+// addends of RELA relocations are written.
+func applyRelocations(f *elf.File, s mipsevm.FPVMState) error {
+	dyn, err := dynamicTable(f)
+	if err != nil {
+		return err
+	}
+	if dyn == nil {
+		return nil
+	}
+	wordSize := uint64(4)
+	if f.Class == elf.ELFCLASS64 {
+		wordSize = 8
+	}
+	tables := []struct {
+		addr, size elf.DynTag
+		rela       bool
+	}{
+		{elf.DT_REL, elf.DT_RELSZ, false},
+		{elf.DT_RELA, elf.DT_RELASZ, true},
+		{elf.DT_JMPREL, elf.DT_PLTRELSZ, dyn[elf.DT_PLTREL] == uint64(elf.DT_RELA)},
+	}
+	for _, table := range tables {
+		addr, ok := dyn[table.addr]
+		if !ok {
+			continue
+		}
+		data, err := readVirtual(f, addr, dyn[table.size])
+		if err != nil {
+			return fmt.Errorf("failed to read %v relocations: %w", table.addr, err)
+		}
+		entSize := 2 * wordSize
+		if table.rela {
+			entSize = 3 * wordSize
+		}
+		for off := uint64(0); off+entSize <= uint64(len(data)); off += entSize {
+			offset := readWord(f, data[off:], wordSize)
+			info := readWord(f, data[off+wordSize:], wordSize)
+			typ, sym := relocationInfo(f, info)
+			if !isRelativeRelocation(f.Machine, typ, sym) {
+				return fmt.Errorf("unsupported relocation type %d at %x, only relative relocations are supported", typ, offset)
+			}
+			if !table.rela || typ == 0 {
+				continue
+			}
+			addend := data[off+2*wordSize : off+3*wordSize]
+			if err := s.GetMemory().SetMemoryRange(arch.Word(offset), bytes.NewReader(addend)); err != nil {
+				return fmt.Errorf("failed to apply relocation at %x: %w", offset, err)
+			}
+		}
+	}
+	return nil
+}
+
+// dynamicTable returns the entries of the PT_DYNAMIC segment, or nil if there is no such segment.
+func dynamicTable(f *elf.File) (map[elf.DynTag]uint64, error) {
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_DYNAMIC {
+			continue
+		}
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return nil, fmt.Errorf("failed to read dynamic segment: %w", err)
+		}
+		wordSize := uint64(4)
+		if f.Class == elf.ELFCLASS64 {
+			wordSize = 8
+		}
+		dyn := make(map[elf.DynTag]uint64)
+		for off := uint64(0); off+2*wordSize <= uint64(len(data)); off += 2 * wordSize {
+			tag := elf.DynTag(readWord(f, data[off:], wordSize))
+			if tag == elf.DT_NULL {
+				break
+			}
+			dyn[tag] = readWord(f, data[off+wordSize:], wordSize)
+		}
+		return dyn, nil
+	}
+	return nil, nil
+}
+
+// readVirtual reads size bytes at the virtual address addr from the PT_LOAD segment containing them.
+func readVirtual(f *elf.File, addr, size uint64) ([]byte, error) {
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_LOAD || addr < prog.Vaddr || addr+size > prog.Vaddr+prog.Filesz {
+			continue
+		}
+		data := make([]byte, size)
+		if _, err := prog.ReadAt(data, int64(addr-prog.Vaddr)); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("range %x - %x is not in a loaded segment", addr, addr+size)
+}
+
+func readWord(f *elf.File, data []byte, wordSize uint64) uint64 {
+	if wordSize == 4 {
+		return uint64(f.ByteOrder.Uint32(data))
+	}
+	return f.ByteOrder.Uint64(data)
+}
+
+// relocationInfo splits the info field of a relocation into its type and symbol index.
+func relocationInfo(f *elf.File, info uint64) (typ uint32, sym uint32) {
+	if f.Class == elf.ELFCLASS32 {
+		return uint32(info & 0xff), uint32(info >> 8)
+	}
+	if f.Machine == elf.EM_MIPS {
+		// MIPS64 packs up to three relocation types into the low bytes, the first of which is in the lowest byte
+		// on big-endian targets and in the highest byte on little-endian targets.
+		if f.ByteOrder == binary.BigEndian {
+			return uint32(info & 0xff), uint32(info >> 32)
+		}
+		return uint32(info >> 56), uint32(info & 0xffffffff)
+	}
+	return uint32(info & 0xffffffff), uint32(info >> 32)
+}
+
+// rRISCVIRelative is the R_RISCV_IRELATIVE relocation type, which is not defined by debug/elf.
+const rRISCVIRelative = 58
+
+func isRelativeRelocation(machine elf.Machine, typ uint32, sym uint32) bool {
+	switch machine {
+	case elf.EM_MIPS:
+		return typ == uint32(elf.R_MIPS_NONE) || (typ == uint32(elf.R_MIPS_REL32) && sym == 0)
+	case elf.EM_RISCV:
+		// IRELATIVE relocations are resolved by the startup code of the program itself.
+		return typ == uint32(elf.R_RISCV_NONE) || typ == uint32(elf.R_RISCV_RELATIVE) || typ == rRISCVIRelative
+	default:
+		return false
+	}
+}
+
+func elfClass() elf.Class {
+	if arch.IsMips32 {
+		return elf.ELFCLASS32
+	}
+	return elf.ELFCLASS64
+}
@@ -0,0 +1,240 @@
+package program_test
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+)
+
+func TestLoadELF(t *testing.T) {
+	t.Run("LoadSegments", func(t *testing.T) {
+		f := buildELF(t, elf.ET_EXEC, []segment{
+			{typ: elf.PT_LOAD, vaddr: 0x1000, data: []byte("abcd"), memsz: 8},
+			// TLS segments describe data within a PT_LOAD segment, including zero-filled .tbss.
+			{typ: elf.PT_TLS, vaddr: 0x1000, data: []byte("abcd"), memsz: 16},
+		})
+		state, err := program.LoadELF(f, multithreaded.CreateInitialState)
+		require.NoError(t, err)
+		require.Equal(t, arch.Word(0x1000), state.GetPC())
+		require.Equal(t, []byte("abcd\x00\x00\x00\x00"), readMemory(t, state.GetMemory().ReadMemoryRange(0x1000, 8)))
+	})
+
+	t.Run("RejectDynamicallyLinked", func(t *testing.T) {
+		f := buildELF(t, elf.ET_EXEC, []segment{
+			{typ: elf.PT_INTERP, vaddr: 0x1000, data: []byte("/lib/ld.so.1\x00")},
+			{typ: elf.PT_LOAD, vaddr: 0x1000, data: []byte("abcd")},
+		})
+		_, err := program.LoadELF(f, multithreaded.CreateInitialState)
+		require.ErrorContains(t, err, "must be statically linked")
+	})
+
+	t.Run("RejectRelocatable", func(t *testing.T) {
+		f := buildELF(t, elf.ET_REL, nil)
+		_, err := program.LoadELF(f, multithreaded.CreateInitialState)
+		require.ErrorContains(t, err, "unsupported ELF type")
+	})
+
+	t.Run("StaticPIE", func(t *testing.T) {
+		f := buildELF(t, elf.ET_DYN, staticPIESegments(relocationInfo(0, uint32(elf.R_MIPS_REL32))))
+		state, err := program.LoadELF(f, multithreaded.CreateInitialState)
+		require.NoError(t, err)
+		require.Equal(t, arch.Word(0x1234), state.GetMemory().GetMemory(0x3000))
+	})
+
+	t.Run("RejectSymbolRelocation", func(t *testing.T) {
+		f := buildELF(t, elf.ET_DYN, staticPIESegments(relocationInfo(1, uint32(elf.R_MIPS_REL32))))
+		_, err := program.LoadELF(f, multithreaded.CreateInitialState)
+		require.ErrorContains(t, err, "only relative relocations are supported")
+	})
+}
+
+func TestProgramHeaderAuxv(t *testing.T) {
+	t.Run("ProgramHeaderSegment", func(t *testing.T) {
+		f := buildELF(t, elf.ET_EXEC, []segment{
+			{typ: elf.PT_PHDR, vaddr: 0x1234},
+			{typ: elf.PT_LOAD, vaddr: 0x1000, data: []byte("abcd")},
+		})
+		auxv, err := program.ProgramHeaderAuxv(f)
+		require.NoError(t, err)
+		require.Equal(t, []program.AuxEntry{
+			{Key: program.AtPhdr, Value: 0x1234},
+			{Key: program.AtPhent, Value: arch.Word(phdrSize())},
+			{Key: program.AtPhnum, Value: 2},
+			{Key: program.AtEntry, Value: 0x1000},
+		}, auxv)
+	})
+
+	t.Run("LoadedFromFileStart", func(t *testing.T) {
+		f := buildELF(t, elf.ET_EXEC, []segment{
+			{typ: elf.PT_LOAD, vaddr: 0x400000, fromStart: true},
+		})
+		auxv, err := program.ProgramHeaderAuxv(f)
+		require.NoError(t, err)
+		require.Equal(t, program.AuxEntry{Key: program.AtPhdr, Value: arch.Word(0x400000 + headerSize())}, auxv[0])
+	})
+
+	t.Run("NotLoaded", func(t *testing.T) {
+		f := buildELF(t, elf.ET_EXEC, []segment{
+			{typ: elf.PT_LOAD, vaddr: 0x1000, data: []byte("abcd")},
+		})
+		_, err := program.ProgramHeaderAuxv(f)
+		require.ErrorContains(t, err, "program headers are not in a loaded segment")
+	})
+}
+
+func TestPatchStack(t *testing.T) {
+	const ws = arch.WordSizeBytes
+	sp := arch.Word(arch.HighMemoryStart)
+
+	t.Run("Default", func(t *testing.T) {
+		state := multithreaded.CreateInitialState(0, program.HEAP_START)
+		require.NoError(t, program.PatchStack(state))
+		mem := state.GetMemory()
+		require.Equal(t, sp, state.GetRegistersRef()[29])
+		require.Equal(t, sp+ws*21, mem.GetMemory(sp+ws*1))
+		require.Equal(t, arch.Word(program.AtRandom), mem.GetMemory(sp+ws*7))
+		require.Equal(t, sp+ws*10, mem.GetMemory(sp+ws*8))
+		require.Equal(t, arch.Word(0), mem.GetMemory(sp+ws*9))
+		require.Equal(t, []byte("op-program\x00"), readMemory(t, mem.ReadMemoryRange(sp+ws*21, 11)))
+	})
+
+	t.Run("ExtraAuxv", func(t *testing.T) {
+		state := multithreaded.CreateInitialState(0, program.HEAP_START)
+		auxv := []program.AuxEntry{{Key: program.AtEntry, Value: 0x1000}}
+		require.NoError(t, program.PatchStackAuxv(state, auxv))
+		mem := state.GetMemory()
+		require.Equal(t, sp+ws*23, mem.GetMemory(sp+ws*1))
+		require.Equal(t, sp+ws*16, mem.GetMemory(sp+ws*3))
+		require.Equal(t, sp+ws*12, mem.GetMemory(sp+ws*8))
+		require.Equal(t, arch.Word(program.AtEntry), mem.GetMemory(sp+ws*9))
+		require.Equal(t, arch.Word(0x1000), mem.GetMemory(sp+ws*10))
+		require.Equal(t, arch.Word(0), mem.GetMemory(sp+ws*11))
+		require.Equal(t, []byte("4;byfairdiceroll"), readMemory(t, mem.ReadMemoryRange(sp+ws*12, 16)))
+		require.Equal(t, []byte("op-program\x00"), readMemory(t, mem.ReadMemoryRange(sp+ws*23, 11)))
+	})
+}
+
+type segment struct {
+	typ   elf.ProgType
+	vaddr uint64
+	data  []byte
+	memsz uint64
+	// fromStart places the segment at file offset 0, covering the ELF and program headers.
+	fromStart bool
+}
+
+// staticPIESegments returns the segments of a static-PIE executable with a single RELA relocation of
+// the word at 0x3000 to 0x1234.
+func staticPIESegments(info uint64) []segment {
+	rela := append(appendWord(appendWord(nil, 0x3000), info), appendWord(nil, 0x1234)...)
+	var dyn []byte
+	for _, entry := range [][2]uint64{
+		{uint64(elf.DT_RELA), 0x2000},
+		{uint64(elf.DT_RELASZ), uint64(len(rela))},
+		{uint64(elf.DT_RELAENT), uint64(len(rela))},
+		{uint64(elf.DT_NULL), 0},
+	} {
+		dyn = appendWord(appendWord(dyn, entry[0]), entry[1])
+	}
+	return []segment{
+		{typ: elf.PT_LOAD, vaddr: 0x1000, data: []byte("abcd")},
+		{typ: elf.PT_LOAD, vaddr: 0x2000, data: rela},
+		{typ: elf.PT_LOAD, vaddr: 0x3000, data: make([]byte, arch.WordSizeBytes)},
+		{typ: elf.PT_DYNAMIC, vaddr: 0x2100, data: dyn},
+	}
+}
+
+func relocationInfo(sym uint32, typ uint32) uint64 {
+	if arch.IsMips32 {
+		return uint64(sym)<<8 | uint64(typ)
+	}
+	return uint64(sym)<<32 | uint64(typ)
+}
+
+// buildELF encodes a big-endian MIPS ELF file for the current architecture, with the given segments and no sections.
+func buildELF(t *testing.T, typ elf.Type, segments []segment) *elf.File {
+	var phdrs, data bytes.Buffer
+	dataOff := uint64(headerSize() + phdrSize()*len(segments))
+	for _, seg := range segments {
+		off, filesz := dataOff+uint64(data.Len()), uint64(len(seg.data))
+		if seg.fromStart {
+			off, filesz = 0, dataOff
+		} else {
+			data.Write(seg.data)
+		}
+		memsz := max(seg.memsz, filesz)
+		if arch.IsMips32 {
+			require.NoError(t, binary.Write(&phdrs, binary.BigEndian, elf.Prog32{
+				Type: uint32(seg.typ), Off: uint32(off), Vaddr: uint32(seg.vaddr), Paddr: uint32(seg.vaddr),
+				Filesz: uint32(filesz), Memsz: uint32(memsz), Align: 4,
+			}))
+		} else {
+			require.NoError(t, binary.Write(&phdrs, binary.BigEndian, elf.Prog64{
+				Type: uint32(seg.typ), Off: off, Vaddr: seg.vaddr, Paddr: seg.vaddr,
+				Filesz: filesz, Memsz: memsz, Align: 8,
+			}))
+		}
+	}
+
+	var out bytes.Buffer
+	var ident [elf.EI_NIDENT]byte
+	copy(ident[:], elf.ELFMAG)
+	ident[elf.EI_DATA] = byte(elf.ELFDATA2MSB)
+	ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	if arch.IsMips32 {
+		ident[elf.EI_CLASS] = byte(elf.ELFCLASS32)
+		require.NoError(t, binary.Write(&out, binary.BigEndian, elf.Header32{
+			Ident: ident, Type: uint16(typ), Machine: uint16(elf.EM_MIPS), Version: uint32(elf.EV_CURRENT),
+			Entry: 0x1000, Phoff: uint32(headerSize()), Ehsize: uint16(headerSize()),
+			Phentsize: uint16(phdrSize()), Phnum: uint16(len(segments)), Shentsize: 40,
+		}))
+	} else {
+		ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+		require.NoError(t, binary.Write(&out, binary.BigEndian, elf.Header64{
+			Ident: ident, Type: uint16(typ), Machine: uint16(elf.EM_MIPS), Version: uint32(elf.EV_CURRENT),
+			Entry: 0x1000, Phoff: uint64(headerSize()), Ehsize: uint16(headerSize()),
+			Phentsize: uint16(phdrSize()), Phnum: uint16(len(segments)), Shentsize: 64,
+		}))
+	}
+	out.Write(phdrs.Bytes())
+	out.Write(data.Bytes())
+
+	f, err := elf.NewFile(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	return f
+}
+
+func headerSize() int {
+	if arch.IsMips32 {
+		return 52
+	}
+	return 64
+}
+
+func phdrSize() int {
+	if arch.IsMips32 {
+		return 32
+	}
+	return 56
+}
+
+func appendWord(b []byte, v uint64) []byte {
+	if arch.IsMips32 {
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(b, v)
+}
+
+func readMemory(t *testing.T, r io.Reader) []byte {
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return data
+}
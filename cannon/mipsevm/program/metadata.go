@@ -6,12 +6,13 @@ import (
 	"sort"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 )
 
 type Symbol struct {
-	Name  string `json:"name"`
-	Start uint32 `json:"start"`
-	Size  uint32 `json:"size"`
+	Name  string    `json:"name"`
+	Start arch.Word `json:"start"`
+	Size  arch.Word `json:"size"`
 }
 
 type Metadata struct {
@@ -31,12 +32,12 @@ func MakeMetadata(elfProgram *elf.File) (*Metadata, error) {
 	})
 	out := &Metadata{Symbols: make([]Symbol, len(syms))}
 	for i, s := range syms {
-		out.Symbols[i] = Symbol{Name: s.Name, Start: uint32(s.Value), Size: uint32(s.Size)}
+		out.Symbols[i] = Symbol{Name: s.Name, Start: arch.Word(s.Value), Size: arch.Word(s.Size)}
 	}
 	return out, nil
 }
 
-func (m *Metadata) LookupSymbol(addr uint32) string {
+func (m *Metadata) LookupSymbol(addr arch.Word) string {
 	if len(m.Symbols) == 0 {
 		return "!unknown"
 	}
@@ -59,12 +60,12 @@ func (m *Metadata) CreateSymbolMatcher(name string) mipsevm.SymbolMatcher {
 		if s.Name == name {
 			start := s.Start
 			end := s.Start + s.Size
-			return func(addr uint32) bool {
+			return func(addr arch.Word) bool {
 				return addr >= start && addr < end
 			}
 		}
 	}
-	return func(addr uint32) bool {
+	return func(addr arch.Word) bool {
 		return false
 	}
 }
@@ -0,0 +1,55 @@
+package program
+
+// Classes of instructions that steps are attributed to in the profile report.
+const (
+	OpClassALU     = "alu"
+	OpClassMulDiv  = "muldiv"
+	OpClassLoad    = "load"
+	OpClassStore   = "store"
+	OpClassAtomic  = "atomic"
+	OpClassBranch  = "branch"
+	OpClassJump    = "jump"
+	OpClassSyscall = "syscall"
+	OpClassFloat   = "float"
+	OpClassOther   = "other"
+)
+
+// MIPSOpcodeClass returns the class of the MIPS32/MIPS64 instruction insn.
+func MIPSOpcodeClass(insn uint32) string {
+	opcode := insn >> 26
+	fun := insn & 0x3f
+	switch opcode {
+	case 0x00: // SPECIAL
+		switch {
+		case fun == 0x0C:
+			return OpClassSyscall
+		case fun == 0x08 || fun == 0x09: // jr, jalr
+			return OpClassJump
+		case fun >= 0x10 && fun <= 0x13, fun >= 0x18 && fun <= 0x1F: // mfhi/mthi/mflo/mtlo, (d)mult(u), (d)div(u)
+			return OpClassMulDiv
+		case fun == 0x0F: // sync
+			return OpClassOther
+		default:
+			return OpClassALU
+		}
+	case 0x1C: // SPECIAL2
+		if fun == 0x02 { // mul
+			return OpClassMulDiv
+		}
+		return OpClassALU
+	case 0x02, 0x03: // j, jal
+		return OpClassJump
+	case 0x01, 0x04, 0x05, 0x06, 0x07: // REGIMM, beq, bne, blez, bgtz
+		return OpClassBranch
+	case 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x18, 0x19, 0x1F: // immediate arithmetic, lui, SPECIAL3
+		return OpClassALU
+	case 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x1A, 0x1B, 0x37: // lb ... lwu, ldl, ldr, ld
+		return OpClassLoad
+	case 0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x3F: // sb ... swr, sd
+		return OpClassStore
+	case 0x30, 0x34, 0x38, 0x3C: // ll, lld, sc, scd
+		return OpClassAtomic
+	default:
+		return OpClassOther
+	}
+}
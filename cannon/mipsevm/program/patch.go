@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
 )
 
@@ -39,10 +40,10 @@ func PatchGoGC(f *elf.File, st mipsevm.FPVMState) error {
 			"flag.init",
 			// We need to patch this out, we don't pass float64nan because we don't support floats
 			"runtime.check":
-			// MIPS32 patch: ret (pseudo instruction)
+			// MIPS patch: ret (pseudo instruction)
 			// 03e00008 = jr $ra = ret (pseudo instruction)
 			// 00000000 = nop (executes with delay-slot, but does nothing)
-			if err := st.GetMemory().SetMemoryRange(uint32(s.Value), bytes.NewReader([]byte{
+			if err := st.GetMemory().SetMemoryRange(arch.Word(s.Value), bytes.NewReader([]byte{
 				0x03, 0xe0, 0x00, 0x08,
 				0, 0, 0, 0,
 			})); err != nil {
@@ -53,44 +54,138 @@ func PatchGoGC(f *elf.File, st mipsevm.FPVMState) error {
 	return nil
 }
 
+// Keys of auxiliary vector entries, see getauxval(3).
+const (
+	AtPhdr   = 3
+	AtPhent  = 4
+	AtPhnum  = 5
+	AtPagesz = 6
+	AtEntry  = 9
+	AtRandom = 25
+)
+
+// AuxEntry is an entry of the auxiliary vector passed to the program on its initial stack.
+type AuxEntry struct {
+	Key   arch.Word
+	Value arch.Word
+}
+
+// ProgramHeaderAuxv returns the auxiliary vector entries that describe the loaded program headers and entry point.
+// Go programs do not need these, but C runtimes use them to locate the TLS segment and static-PIE executables use
+// them to relocate themselves.
+func ProgramHeaderAuxv(f *elf.File) ([]AuxEntry, error) {
+	phdr, err := programHeaderAddr(f)
+	if err != nil {
+		return nil, err
+	}
+	phent := arch.Word(32)
+	if f.Class == elf.ELFCLASS64 {
+		phent = 56
+	}
+	return []AuxEntry{
+		{Key: AtPhdr, Value: phdr},
+		{Key: AtPhent, Value: phent},
+		{Key: AtPhnum, Value: arch.Word(len(f.Progs))},
+		{Key: AtEntry, Value: arch.Word(f.Entry)},
+	}, nil
+}
+
+// programHeaderAddr returns the virtual address of the program headers, which must be in a loaded segment.
+func programHeaderAddr(f *elf.File) (arch.Word, error) {
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_PHDR {
+			return arch.Word(prog.Vaddr), nil
+		}
+	}
+	// Without a PT_PHDR segment, the program headers are loaded if the segment at file offset 0 includes them.
+	// The ELF header is not exposed by debug/elf, so read the program header offset from the segment.
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_LOAD || prog.Off != 0 {
+			continue
+		}
+		var phoff uint64
+		if f.Class == elf.ELFCLASS64 {
+			var buf [8]byte
+			if _, err := prog.ReadAt(buf[:], 0x20); err != nil {
+				return 0, fmt.Errorf("failed to read program header offset: %w", err)
+			}
+			phoff = f.ByteOrder.Uint64(buf[:])
+		} else {
+			var buf [4]byte
+			if _, err := prog.ReadAt(buf[:], 0x1c); err != nil {
+				return 0, fmt.Errorf("failed to read program header offset: %w", err)
+			}
+			phoff = uint64(f.ByteOrder.Uint32(buf[:]))
+		}
+		if phoff < prog.Filesz {
+			return arch.Word(prog.Vaddr + phoff), nil
+		}
+	}
+	return 0, errors.New("program headers are not in a loaded segment")
+}
+
 // PatchStack sets up the program's initial stack frame and stack pointer
 func PatchStack(st mipsevm.FPVMState) error {
+	return PatchStackAuxv(st, nil)
+}
+
+// PatchStackAuxv is PatchStack with additional auxiliary vector entries, such as those from ProgramHeaderAuxv.
+func PatchStackAuxv(st mipsevm.FPVMState, auxv []AuxEntry) error {
+	return PatchStackWith(st, binary.BigEndian, 29, auxv)
+}
+
+// PatchStackWith sets up the program's initial stack frame, storing words in the given byte order and
+// setting the stack pointer in register spReg.
+// Additional auxiliary vector entries follow the page size and random entries, moving the data after them.
+func PatchStackWith(st mipsevm.FPVMState, order binary.ByteOrder, spReg int, auxv []AuxEntry) error {
 	// setup stack pointer
-	sp := uint32(0x7f_ff_d0_00)
+	sp := arch.Word(arch.HighMemoryStart)
 	// allocate 1 page for the initial stack data, and 16KB = 4 pages for the stack to grow
 	if err := st.GetMemory().SetMemoryRange(sp-4*memory.PageSize, bytes.NewReader(make([]byte, 5*memory.PageSize))); err != nil {
 		return errors.New("failed to allocate page for stack content")
 	}
-	st.GetRegistersRef()[29] = sp
+	st.GetRegistersRef()[spReg] = sp
 
-	storeMem := func(addr uint32, v uint32) {
-		var dat [4]byte
-		binary.BigEndian.PutUint32(dat[:], v)
-		_ = st.GetMemory().SetMemoryRange(addr, bytes.NewReader(dat[:]))
+	storeMem := func(addr arch.Word, v arch.Word) {
+		buf := make([]byte, arch.WordSizeBytes)
+		if arch.WordSizeBytes == 4 {
+			order.PutUint32(buf, uint32(v))
+		} else {
+			order.PutUint64(buf, uint64(v))
+		}
+		_ = st.GetMemory().SetMemoryRange(addr, bytes.NewReader(buf))
 	}
 
+	// Each additional auxv entry takes two words
+	const ws = arch.WordSizeBytes
+	extra := arch.Word(2 * len(auxv))
+
 	// init argc, argv, aux on stack
-	storeMem(sp+4*0, 1)       // argc = 1 (argument count)
-	storeMem(sp+4*1, sp+4*21) // argv[0]
-	storeMem(sp+4*2, 0)       // argv[1] = terminating
-	storeMem(sp+4*3, sp+4*14) // envp[0] = x (offset to first env var)
-	storeMem(sp+4*4, 0)       // envp[1] = terminating
-	storeMem(sp+4*5, 6)       // auxv[0] = _AT_PAGESZ = 6 (key)
-	storeMem(sp+4*6, 4096)    // auxv[1] = page size of 4 KiB (value) - (== minPhysPageSize)
-	storeMem(sp+4*7, 25)      // auxv[2] = AT_RANDOM
-	storeMem(sp+4*8, sp+4*10) // auxv[3] = address of 16 bytes containing random value
-	storeMem(sp+4*9, 0)       // auxv[term] = 0
-
-	_ = st.GetMemory().SetMemoryRange(sp+4*10, bytes.NewReader([]byte("4;byfairdiceroll"))) // 16 bytes of "randomness"
+	storeMem(sp+ws*0, 1)                // argc = 1 (argument count)
+	storeMem(sp+ws*1, sp+ws*(21+extra)) // argv[0]
+	storeMem(sp+ws*2, 0)                // argv[1] = terminating
+	storeMem(sp+ws*3, sp+ws*(14+extra)) // envp[0] = x (offset to first env var)
+	storeMem(sp+ws*4, 0)                // envp[1] = terminating
+	storeMem(sp+ws*5, AtPagesz)         // auxv[0] = _AT_PAGESZ = 6 (key)
+	storeMem(sp+ws*6, 4096)             // auxv[1] = page size of 4 KiB (value) - (== minPhysPageSize)
+	storeMem(sp+ws*7, AtRandom)         // auxv[2] = AT_RANDOM
+	storeMem(sp+ws*8, sp+ws*(10+extra)) // auxv[3] = address of 16 bytes containing random value
+	for i, entry := range auxv {
+		storeMem(sp+ws*arch.Word(9+2*i), entry.Key)
+		storeMem(sp+ws*arch.Word(10+2*i), entry.Value)
+	}
+	storeMem(sp+ws*(9+extra), 0) // auxv[term] = 0
+
+	_ = st.GetMemory().SetMemoryRange(sp+ws*(10+extra), bytes.NewReader([]byte("4;byfairdiceroll"))) // 16 bytes of "randomness"
 
 	// append 4 extra zero bytes to end at 4-byte alignment
 	envar := append([]byte("GODEBUG=memprofilerate=0"), 0x0, 0x0, 0x0, 0x0)
-	_ = st.GetMemory().SetMemoryRange(sp+4*14, bytes.NewReader(envar))
+	_ = st.GetMemory().SetMemoryRange(sp+ws*(14+extra), bytes.NewReader(envar))
 
 	// 24 bytes for GODEBUG=memprofilerate=0 + 4 null bytes
 	// Then append program name + 2 null bytes for 4-byte alignment
 	programName := append([]byte("op-program"), 0x0, 0x0)
-	_ = st.GetMemory().SetMemoryRange(sp+4*21, bytes.NewReader(programName))
+	_ = st.GetMemory().SetMemoryRange(sp+ws*(21+extra), bytes.NewReader(programName))
 
 	return nil
 }
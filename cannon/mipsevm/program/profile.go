@@ -0,0 +1,289 @@
+package program
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+)
+
+var ErrNoSymbols = errors.New("no symbols available")
+
+// Profiler records how many steps are spent at each PC of the guest program, in each class of instruction
+// and how often each syscall is made.
+type Profiler struct {
+	meta *Metadata
+
+	steps       uint64
+	pcs         map[arch.Word]*pcProfile
+	syscalls    map[arch.Word]uint64
+	opClasses   map[string]uint64
+	syscallName func(num arch.Word) string
+}
+
+type pcProfile struct {
+	steps    uint64
+	syscalls uint64
+}
+
+// ProfileReport summarises the steps spent in each function and instruction class and the syscalls made by the
+// guest program. Each syscall takes a single step, so the count of a syscall is also the steps attributable to it.
+type ProfileReport struct {
+	Steps         uint64             `json:"steps"`
+	Functions     []FunctionSteps    `json:"functions"`
+	Syscalls      []SyscallCount     `json:"syscalls"`
+	OpcodeClasses []OpcodeClassSteps `json:"opcodeClasses"`
+}
+
+type FunctionSteps struct {
+	Name  string `json:"name"`
+	Steps uint64 `json:"steps"`
+}
+
+type SyscallCount struct {
+	Num   arch.Word `json:"num"`
+	Name  string    `json:"name"`
+	Count uint64    `json:"count"`
+}
+
+type OpcodeClassSteps struct {
+	Class string `json:"class"`
+	Steps uint64 `json:"steps"`
+}
+
+func NewProfiler(meta *Metadata) *Profiler {
+	return &Profiler{
+		meta:        meta,
+		pcs:         make(map[arch.Word]*pcProfile),
+		syscalls:    make(map[arch.Word]uint64),
+		opClasses:   make(map[string]uint64),
+		syscallName: SyscallName,
+	}
+}
+
+// Record attributes the next step of state to the PC of the current thread.
+// It must be called before the step is executed.
+func (p *Profiler) Record(state mipsevm.FPVMState) {
+	pc := state.GetPC()
+	entry, ok := p.pcs[pc]
+	if !ok {
+		entry = &pcProfile{}
+		p.pcs[pc] = entry
+	}
+	p.steps++
+	entry.steps++
+	if d, ok := state.(syscallDecoder); ok {
+		p.syscallName = d.SyscallName
+	}
+	if syscallNum, ok := pendingSyscall(state); ok {
+		entry.syscalls++
+		p.syscalls[syscallNum]++
+	}
+	p.opClasses[opcodeClass(state)]++
+}
+
+// syscallDecoder is implemented by states of non-MIPS VMs, which have their own syscall instruction and numbering.
+type syscallDecoder interface {
+	PendingSyscall() (arch.Word, bool)
+	SyscallName(num arch.Word) string
+}
+
+// opcodeClassifier is implemented by states of non-MIPS VMs, which have their own instruction encoding.
+type opcodeClassifier interface {
+	OpcodeClass(pc arch.Word) string
+}
+
+func opcodeClass(state mipsevm.FPVMState) string {
+	if c, ok := state.(opcodeClassifier); ok {
+		return c.OpcodeClass(state.GetPC())
+	}
+	return MIPSOpcodeClass(state.GetMemory().GetUint32(state.GetPC()))
+}
+
+func pendingSyscall(state mipsevm.FPVMState) (arch.Word, bool) {
+	if d, ok := state.(syscallDecoder); ok {
+		return d.PendingSyscall()
+	}
+	insn := state.GetMemory().GetUint32(state.GetPC())
+	opcode := insn >> 26
+	fun := insn & 0x3f
+	if opcode == 0 && fun == 0xC { // syscall
+		return state.GetRegistersRef()[2], true // syscall number in $v0
+	}
+	return 0, false
+}
+
+// Report returns the steps spent in each function and instruction class and the syscall frequencies,
+// all sorted by most frequent first.
+func (p *Profiler) Report() *ProfileReport {
+	functions := make(map[string]uint64)
+	for pc, entry := range p.pcs {
+		functions[p.lookupSymbol(pc)] += entry.steps
+	}
+	report := &ProfileReport{
+		Steps:         p.steps,
+		Functions:     make([]FunctionSteps, 0, len(functions)),
+		Syscalls:      make([]SyscallCount, 0, len(p.syscalls)),
+		OpcodeClasses: make([]OpcodeClassSteps, 0, len(p.opClasses)),
+	}
+	for name, steps := range functions {
+		report.Functions = append(report.Functions, FunctionSteps{Name: name, Steps: steps})
+	}
+	sort.Slice(report.Functions, func(i, j int) bool {
+		a, b := report.Functions[i], report.Functions[j]
+		return a.Steps > b.Steps || (a.Steps == b.Steps && a.Name < b.Name)
+	})
+	for num, count := range p.syscalls {
+		report.Syscalls = append(report.Syscalls, SyscallCount{Num: num, Name: p.syscallName(num), Count: count})
+	}
+	sort.Slice(report.Syscalls, func(i, j int) bool {
+		a, b := report.Syscalls[i], report.Syscalls[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Num < b.Num)
+	})
+	for class, steps := range p.opClasses {
+		report.OpcodeClasses = append(report.OpcodeClasses, OpcodeClassSteps{Class: class, Steps: steps})
+	}
+	sort.Slice(report.OpcodeClasses, func(i, j int) bool {
+		a, b := report.OpcodeClasses[i], report.OpcodeClasses[j]
+		return a.Steps > b.Steps || (a.Steps == b.Steps && a.Class < b.Class)
+	})
+	return report
+}
+
+// WritePProf writes the recorded steps and syscalls per PC as a gzipped pprof profile.
+// Requires the metadata to include symbols so that PCs can be resolved to functions.
+func (p *Profiler) WritePProf(w io.Writer) error {
+	if p.meta == nil || len(p.meta.Symbols) == 0 {
+		return ErrNoSymbols
+	}
+	mapping := &profile.Mapping{
+		ID:           1,
+		Start:        0,
+		Limit:        ^uint64(0),
+		File:         "guest",
+		HasFunctions: true,
+	}
+	prof := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "steps", Unit: "count"},
+			{Type: "syscalls", Unit: "count"},
+		},
+		DefaultSampleType: "steps",
+		PeriodType:        &profile.ValueType{Type: "steps", Unit: "count"},
+		Period:            1,
+		Mapping:           []*profile.Mapping{mapping},
+	}
+	pcs := make([]arch.Word, 0, len(p.pcs))
+	for pc := range p.pcs {
+		pcs = append(pcs, pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	functions := make(map[string]*profile.Function)
+	for _, pc := range pcs {
+		name := p.lookupSymbol(pc)
+		fn, ok := functions[name]
+		if !ok {
+			fn = &profile.Function{ID: uint64(len(prof.Function) + 1), Name: name, SystemName: name}
+			functions[name] = fn
+			prof.Function = append(prof.Function, fn)
+		}
+		loc := &profile.Location{
+			ID:      uint64(len(prof.Location) + 1),
+			Mapping: mapping,
+			Address: uint64(pc),
+			Line:    []profile.Line{{Function: fn}},
+		}
+		prof.Location = append(prof.Location, loc)
+		entry := p.pcs[pc]
+		prof.Sample = append(prof.Sample, &profile.Sample{
+			Location: []*profile.Location{loc},
+			Value:    []int64{int64(entry.steps), int64(entry.syscalls)},
+		})
+	}
+	if err := prof.CheckValid(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+	return prof.Write(w)
+}
+
+func (p *Profiler) lookupSymbol(pc arch.Word) string {
+	if p.meta == nil {
+		return "!unknown"
+	}
+	return p.meta.LookupSymbol(pc)
+}
+
+var syscallNames = map[arch.Word]string{}
+
+func init() {
+	for _, sys := range []struct {
+		num  arch.Word
+		name string
+	}{
+		{arch.SysMmap, "mmap"},
+		{arch.SysBrk, "brk"},
+		{arch.SysClone, "clone"},
+		{arch.SysExitGroup, "exit_group"},
+		{arch.SysRead, "read"},
+		{arch.SysWrite, "write"},
+		{arch.SysFcntl, "fcntl"},
+		{arch.SysExit, "exit"},
+		{arch.SysSchedYield, "sched_yield"},
+		{arch.SysGetTID, "gettid"},
+		{arch.SysFutex, "futex"},
+		{arch.SysOpen, "open"},
+		{arch.SysNanosleep, "nanosleep"},
+		{arch.SysClockGetTime, "clock_gettime"},
+		{arch.SysGetpid, "getpid"},
+		{arch.SysMunmap, "munmap"},
+		{arch.SysGetAffinity, "sched_getaffinity"},
+		{arch.SysMadvise, "madvise"},
+		{arch.SysRtSigprocmask, "rt_sigprocmask"},
+		{arch.SysSigaltstack, "sigaltstack"},
+		{arch.SysRtSigaction, "rt_sigaction"},
+		{arch.SysPrlimit64, "prlimit64"},
+		{arch.SysClose, "close"},
+		{arch.SysPread64, "pread64"},
+		{arch.SysFstat, "fstat"},
+		{arch.SysFstat64, "fstat64"},
+		{arch.SysOpenAt, "openat"},
+		{arch.SysReadlink, "readlink"},
+		{arch.SysReadlinkAt, "readlinkat"},
+		{arch.SysIoctl, "ioctl"},
+		{arch.SysEpollCreate1, "epoll_create1"},
+		{arch.SysPipe2, "pipe2"},
+		{arch.SysEpollCtl, "epoll_ctl"},
+		{arch.SysEpollPwait, "epoll_pwait"},
+		{arch.SysGetRandom, "getrandom"},
+		{arch.SysUname, "uname"},
+		{arch.SysStat64, "stat64"},
+		{arch.SysGetuid, "getuid"},
+		{arch.SysGetgid, "getgid"},
+		{arch.SysLlseek, "_llseek"},
+		{arch.SysMinCore, "mincore"},
+		{arch.SysTgkill, "tgkill"},
+		{arch.SysGetRLimit, "getrlimit"},
+		{arch.SysLseek, "lseek"},
+		{arch.SysSetITimer, "setitimer"},
+		{arch.SysTimerCreate, "timer_create"},
+		{arch.SysTimerSetTime, "timer_settime"},
+		{arch.SysTimerDelete, "timer_delete"},
+	} {
+		if sys.num != arch.UndefinedSysNr {
+			syscallNames[sys.num] = sys.name
+		}
+	}
+}
+
+// SyscallName returns the name of the syscall with number num, or the number if it is not a known syscall.
+func SyscallName(num arch.Word) string {
+	if name, ok := syscallNames[num]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", num)
+}
@@ -0,0 +1,106 @@
+package program_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+)
+
+func TestProfiler(t *testing.T) {
+	meta := &program.Metadata{Symbols: []program.Symbol{
+		{Name: "main.loop", Start: 0x1000, Size: 0x100},
+		{Name: "runtime.write", Start: 0x2000, Size: 0x100},
+	}}
+	state := multithreaded.CreateEmptyState()
+	state.Memory.SetMemory(0x2008, arch.Word(0x0000000c)<<(arch.WordSize-32)) // syscall
+	state.GetRegistersRef()[2] = arch.SysWrite
+
+	profiler := program.NewProfiler(meta)
+	record := func(pc arch.Word, times int) {
+		state.GetCurrentThread().Cpu.PC = pc
+		for i := 0; i < times; i++ {
+			profiler.Record(state)
+		}
+	}
+	record(0x1000, 5)
+	record(0x1004, 3)
+	record(0x2004, 2)
+	record(0x2008, 2)
+
+	t.Run("Report", func(t *testing.T) {
+		report := profiler.Report()
+		require.EqualValues(t, 12, report.Steps)
+		require.Equal(t, []program.FunctionSteps{
+			{Name: "main.loop", Steps: 8},
+			{Name: "runtime.write", Steps: 4},
+		}, report.Functions)
+		require.Equal(t, []program.SyscallCount{
+			{Num: arch.SysWrite, Name: "write", Count: 2},
+		}, report.Syscalls)
+		require.Equal(t, []program.OpcodeClassSteps{
+			{Class: program.OpClassALU, Steps: 10},
+			{Class: program.OpClassSyscall, Steps: 2},
+		}, report.OpcodeClasses)
+	})
+
+	t.Run("PProf", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, profiler.WritePProf(&out))
+		prof, err := profile.Parse(&out)
+		require.NoError(t, err)
+		require.Len(t, prof.Sample, 4)
+		steps := make(map[string]int64)
+		syscalls := make(map[string]int64)
+		for _, sample := range prof.Sample {
+			name := sample.Location[0].Line[0].Function.Name
+			steps[name] += sample.Value[0]
+			syscalls[name] += sample.Value[1]
+		}
+		require.Equal(t, map[string]int64{"main.loop": 8, "runtime.write": 4}, steps)
+		require.Equal(t, map[string]int64{"main.loop": 0, "runtime.write": 2}, syscalls)
+	})
+
+	t.Run("PProfRequiresSymbols", func(t *testing.T) {
+		err := program.NewProfiler(&program.Metadata{}).WritePProf(&bytes.Buffer{})
+		require.ErrorIs(t, err, program.ErrNoSymbols)
+	})
+}
+
+func TestSyscallName(t *testing.T) {
+	require.Equal(t, "futex", program.SyscallName(arch.SysFutex))
+	require.Equal(t, "123456", program.SyscallName(123456))
+}
+
+func TestMIPSOpcodeClass(t *testing.T) {
+	tests := []struct {
+		insn  uint32
+		class string
+	}{
+		{0x00851021, program.OpClassALU},     // addu $v0, $a0, $a1
+		{0x24420001, program.OpClassALU},     // addiu $v0, $v0, 1
+		{0x00850018, program.OpClassMulDiv},  // mult $a0, $a1
+		{0x00001012, program.OpClassMulDiv},  // mflo $v0
+		{0x70851002, program.OpClassMulDiv},  // mul $v0, $a0, $a1
+		{0x8c820000, program.OpClassLoad},    // lw $v0, 0($a0)
+		{0xdc820000, program.OpClassLoad},    // ld $v0, 0($a0)
+		{0xac820000, program.OpClassStore},   // sw $v0, 0($a0)
+		{0xfc820000, program.OpClassStore},   // sd $v0, 0($a0)
+		{0xc0820000, program.OpClassAtomic},  // ll $v0, 0($a0)
+		{0xe0820000, program.OpClassAtomic},  // sc $v0, 0($a0)
+		{0x10850004, program.OpClassBranch},  // beq $a0, $a1, 4
+		{0x04810004, program.OpClassBranch},  // bgez $a0, 4
+		{0x0c000400, program.OpClassJump},    // jal 0x1000
+		{0x03e00008, program.OpClassJump},    // jr $ra
+		{0x0000000c, program.OpClassSyscall}, // syscall
+		{0x0000000f, program.OpClassOther},   // sync
+	}
+	for _, test := range tests {
+		require.Equalf(t, test.class, program.MIPSOpcodeClass(test.insn), "insn %08x", test.insn)
+	}
+}
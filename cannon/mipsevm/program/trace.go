@@ -0,0 +1,143 @@
+package program
+
+import (
+	"strconv"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+)
+
+// Tracer records the instructions executed within a window of steps around a target step,
+// including the registers each instruction changed and the memory it accessed.
+// It is intended for debugging divergences between the guest program and the VM without custom builds.
+type Tracer struct {
+	meta  *Metadata
+	at    uint64
+	first uint64
+	last  uint64
+
+	entries []TraceEntry
+	// the entry for the step currently being executed, or nil when outside of the window
+	current *TraceEntry
+	regs    [32]arch.Word
+	cpu     mipsevm.CpuScalars
+}
+
+// ExecutionTrace is the list of instructions executed in the traced window.
+type ExecutionTrace struct {
+	At      uint64       `json:"at"`
+	Entries []TraceEntry `json:"entries"`
+}
+
+type TraceEntry struct {
+	Step      uint64           `json:"step"`
+	PC        mipsevm.HexWord  `json:"pc"`
+	Insn      mipsevm.HexU32   `json:"insn"`
+	Symbol    string           `json:"symbol,omitempty"`
+	Registers []RegisterChange `json:"registers,omitempty"`
+	Memory    []MemoryAccess   `json:"memory,omitempty"`
+}
+
+// RegisterChange is a register whose value was changed by an instruction.
+// Reg is the register number, or "hi"/"lo" for the MIPS multiply/divide registers.
+type RegisterChange struct {
+	Reg    string          `json:"reg"`
+	Before mipsevm.HexWord `json:"before"`
+	After  mipsevm.HexWord `json:"after"`
+}
+
+// MemoryAccess is a word of memory read or written by an instruction, with the value read or written.
+type MemoryAccess struct {
+	Addr  mipsevm.HexWord `json:"addr"`
+	Value mipsevm.HexWord `json:"value"`
+	Write bool            `json:"write"`
+}
+
+// NewTracer creates a tracer recording the steps from at-window to at+window inclusive.
+func NewTracer(meta *Metadata, at uint64, window uint64) *Tracer {
+	first := uint64(0)
+	if at > window {
+		first = at - window
+	}
+	last := at + window
+	if last < at { // overflow
+		last = ^uint64(0)
+	}
+	return &Tracer{meta: meta, at: at, first: first, last: last}
+}
+
+// Before captures the state prior to executing the next step, if the step is within the window.
+// It must be called before the step is executed and followed by a call to After once the step completes.
+func (t *Tracer) Before(state mipsevm.FPVMState) {
+	step := state.GetStep()
+	if step < t.first || step > t.last {
+		return
+	}
+	pc := state.GetPC()
+	t.entries = append(t.entries, TraceEntry{
+		Step:   step,
+		PC:     mipsevm.HexWord(pc),
+		Insn:   mipsevm.HexU32(instructionAt(state)),
+		Symbol: t.lookupSymbol(pc),
+	})
+	t.current = &t.entries[len(t.entries)-1]
+	t.regs = *state.GetRegistersRef()
+	t.cpu = state.GetCpu()
+	state.GetMemory().SetAccessHook(func(addr arch.Word, value arch.Word, write bool) {
+		t.current.Memory = append(t.current.Memory, MemoryAccess{
+			Addr:  mipsevm.HexWord(addr),
+			Value: mipsevm.HexWord(value),
+			Write: write,
+		})
+	})
+}
+
+// After records the registers changed by the step captured in Before.
+func (t *Tracer) After(state mipsevm.FPVMState) {
+	if t.current == nil {
+		return
+	}
+	state.GetMemory().SetAccessHook(nil)
+	regs := state.GetRegistersRef()
+	for i := range regs {
+		if regs[i] != t.regs[i] {
+			t.current.Registers = append(t.current.Registers, RegisterChange{
+				Reg:    strconv.Itoa(i),
+				Before: mipsevm.HexWord(t.regs[i]),
+				After:  mipsevm.HexWord(regs[i]),
+			})
+		}
+	}
+	cpu := state.GetCpu()
+	if cpu.HI != t.cpu.HI {
+		t.current.Registers = append(t.current.Registers, RegisterChange{Reg: "hi", Before: mipsevm.HexWord(t.cpu.HI), After: mipsevm.HexWord(cpu.HI)})
+	}
+	if cpu.LO != t.cpu.LO {
+		t.current.Registers = append(t.current.Registers, RegisterChange{Reg: "lo", Before: mipsevm.HexWord(t.cpu.LO), After: mipsevm.HexWord(cpu.LO)})
+	}
+	t.current = nil
+}
+
+// Trace returns the recorded instructions, ordered by step.
+func (t *Tracer) Trace() *ExecutionTrace {
+	return &ExecutionTrace{At: t.at, Entries: t.entries}
+}
+
+func (t *Tracer) lookupSymbol(pc arch.Word) string {
+	if t.meta == nil {
+		return ""
+	}
+	return t.meta.LookupSymbol(pc)
+}
+
+// instructionDecoder is implemented by states of non-MIPS VMs, which fetch instructions differently.
+type instructionDecoder interface {
+	InstructionAt(pc arch.Word) uint32
+}
+
+func instructionAt(state mipsevm.FPVMState) uint32 {
+	if d, ok := state.(instructionDecoder); ok {
+		return d.InstructionAt(state.GetPC())
+	}
+	return state.GetMemory().GetUint32(state.GetPC())
+}
@@ -0,0 +1,64 @@
+package program_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+)
+
+func TestTracer(t *testing.T) {
+	meta := &program.Metadata{Symbols: []program.Symbol{
+		{Name: "main.loop", Start: 0x1000, Size: 0x100},
+	}}
+	state := multithreaded.CreateEmptyState()
+	state.Memory.SetMemory(0x1000, arch.Word(0x24420001)<<(arch.WordSize-32)) // addiu $v0, $v0, 1
+
+	tracer := program.NewTracer(meta, 5, 2)
+	for step := uint64(0); step < 10; step++ {
+		state.Step = step
+		state.GetCurrentThread().Cpu.PC = 0x1000
+		tracer.Before(state)
+		// Simulate an instruction that changes a register and accesses memory.
+		state.GetRegistersRef()[2] = arch.Word(step + 1)
+		state.Memory.GetMemory(0x2000)
+		state.Memory.SetMemory(0x2000, arch.Word(step))
+		tracer.After(state)
+	}
+	// Accesses outside the window are not recorded.
+	state.Memory.SetMemory(0x3000, 1)
+
+	trace := tracer.Trace()
+	require.EqualValues(t, 5, trace.At)
+	require.Len(t, trace.Entries, 5)
+	for i, entry := range trace.Entries {
+		step := uint64(3 + i)
+		require.Equal(t, step, entry.Step)
+		require.Equal(t, mipsevm.HexWord(0x1000), entry.PC)
+		require.Equal(t, mipsevm.HexU32(0x24420001), entry.Insn)
+		require.Equal(t, "main.loop", entry.Symbol)
+		require.Equal(t, []program.RegisterChange{
+			{Reg: "2", Before: mipsevm.HexWord(step), After: mipsevm.HexWord(step + 1)},
+		}, entry.Registers)
+		require.Equal(t, []program.MemoryAccess{
+			{Addr: 0x2000, Value: mipsevm.HexWord(step - 1), Write: false},
+			{Addr: 0x2000, Value: mipsevm.HexWord(step), Write: true},
+		}, entry.Memory)
+	}
+}
+
+func TestTracerWindowAtStart(t *testing.T) {
+	state := multithreaded.CreateEmptyState()
+	tracer := program.NewTracer(nil, 1, 5)
+	for step := uint64(0); step < 10; step++ {
+		state.Step = step
+		tracer.Before(state)
+		tracer.After(state)
+	}
+	require.Len(t, tracer.Trace().Entries, 7)
+	require.Empty(t, tracer.Trace().Entries[0].Symbol)
+}
@@ -0,0 +1,191 @@
+package riscv
+
+// Instruction encoders for the base formats, used to expand compressed instructions.
+
+func encR(opcode, rd, funct3, rs1, rs2, funct7 uint32) uint32 {
+	return funct7<<25 | rs2<<20 | rs1<<15 | funct3<<12 | rd<<7 | opcode
+}
+
+func encI(opcode, rd, funct3, rs1 uint32, imm int32) uint32 {
+	return uint32(imm)<<20 | rs1<<15 | funct3<<12 | rd<<7 | opcode
+}
+
+func encS(opcode, funct3, rs1, rs2 uint32, imm int32) uint32 {
+	u := uint32(imm)
+	return (u>>5)<<25 | rs2<<20 | rs1<<15 | funct3<<12 | (u&0x1f)<<7 | opcode
+}
+
+func encB(funct3, rs1, rs2 uint32, imm int32) uint32 {
+	u := uint32(imm)
+	return ((u>>12)&1)<<31 | ((u>>5)&0x3f)<<25 | rs2<<20 | rs1<<15 | funct3<<12 | ((u>>1)&0xf)<<8 | ((u>>11)&1)<<7 | OpBranch
+}
+
+func encU(opcode, rd uint32, imm int32) uint32 {
+	return uint32(imm)&0xfffff000 | rd<<7 | opcode
+}
+
+func encJ(rd uint32, imm int32) uint32 {
+	u := uint32(imm)
+	return ((u>>20)&1)<<31 | ((u>>1)&0x3ff)<<21 | ((u>>11)&1)<<20 | ((u>>12)&0xff)<<12 | rd<<7 | OpJal
+}
+
+// bit returns bit i of c, shifted to position at.
+func bit(c uint32, i, at uint32) uint32 {
+	return ((c >> i) & 1) << at
+}
+
+// signExtendBits sign-extends the low n bits of v.
+func signExtendBits(v uint32, n uint32) int32 {
+	return int32(v<<(32-n)) >> (32 - n)
+}
+
+// expandCompressed expands a 16-bit RV64C instruction into the equivalent 32-bit instruction.
+// It returns false for reserved and illegal encodings, including the all-zero instruction.
+func expandCompressed(c uint32) (uint32, bool) {
+	funct3 := (c >> 13) & 0x7
+	rdFull := (c >> 7) & 0x1f  // rd/rs1 in CR and CI formats
+	rs2Full := (c >> 2) & 0x1f // rs2 in CR and CSS formats
+	rdPrime := 8 + (c>>2)&0x7  // rd' in CIW and CL formats, rs2' in CS and CA formats
+	rs1Prime := 8 + (c>>7)&0x7 // rs1' in CL, CS, CA and CB formats
+	// immediates shared by the CL/CS formats of 8-byte accesses, and the CI format
+	uimmD := bit(c, 10, 3) | bit(c, 11, 4) | bit(c, 12, 5) | bit(c, 5, 6) | bit(c, 6, 7)
+	uimmW := bit(c, 6, 2) | bit(c, 10, 3) | bit(c, 11, 4) | bit(c, 12, 5) | bit(c, 5, 6)
+	immCI := signExtendBits(bit(c, 12, 5)|(c>>2)&0x1f, 6)
+	shamt := bit(c, 12, 5) | (c>>2)&0x1f
+
+	switch c & 0x3 {
+	case 0:
+		switch funct3 {
+		case 0: // c.addi4spn
+			nzuimm := bit(c, 6, 2) | bit(c, 5, 3) | bit(c, 11, 4) | bit(c, 12, 5) | ((c>>7)&0xf)<<6
+			if nzuimm == 0 {
+				return 0, false
+			}
+			return encI(OpImm, rdPrime, 0, RegSP, int32(nzuimm)), true
+		case 1: // c.fld
+			return encI(OpLoadFP, rdPrime, 3, rs1Prime, int32(uimmD)), true
+		case 2: // c.lw
+			return encI(OpLoad, rdPrime, 2, rs1Prime, int32(uimmW)), true
+		case 3: // c.ld
+			return encI(OpLoad, rdPrime, 3, rs1Prime, int32(uimmD)), true
+		case 5: // c.fsd
+			return encS(OpStoreFP, 3, rs1Prime, rdPrime, int32(uimmD)), true
+		case 6: // c.sw
+			return encS(OpStore, 2, rs1Prime, rdPrime, int32(uimmW)), true
+		case 7: // c.sd
+			return encS(OpStore, 3, rs1Prime, rdPrime, int32(uimmD)), true
+		}
+	case 1:
+		switch funct3 {
+		case 0: // c.addi, c.nop
+			return encI(OpImm, rdFull, 0, rdFull, immCI), true
+		case 1: // c.addiw
+			if rdFull == 0 {
+				return 0, false
+			}
+			return encI(OpImm32, rdFull, 0, rdFull, immCI), true
+		case 2: // c.li
+			return encI(OpImm, rdFull, 0, 0, immCI), true
+		case 3:
+			if rdFull == RegSP { // c.addi16sp
+				nzimm := signExtendBits(bit(c, 6, 4)|bit(c, 2, 5)|bit(c, 5, 6)|bit(c, 3, 7)|bit(c, 4, 8)|bit(c, 12, 9), 10)
+				if nzimm == 0 {
+					return 0, false
+				}
+				return encI(OpImm, RegSP, 0, RegSP, nzimm), true
+			}
+			// c.lui
+			if immCI == 0 {
+				return 0, false
+			}
+			return encU(OpLui, rdFull, immCI<<12), true
+		case 4:
+			switch (c >> 10) & 0x3 {
+			case 0: // c.srli
+				return encI(OpImm, rs1Prime, 5, rs1Prime, int32(shamt)), true
+			case 1: // c.srai
+				return encI(OpImm, rs1Prime, 5, rs1Prime, int32(shamt|0x400)), true
+			case 2: // c.andi
+				return encI(OpImm, rs1Prime, 7, rs1Prime, immCI), true
+			default:
+				funct2 := (c >> 5) & 0x3
+				if c&(1<<12) == 0 {
+					switch funct2 {
+					case 0: // c.sub
+						return encR(OpOp, rs1Prime, 0, rs1Prime, rdPrime, 0x20), true
+					case 1: // c.xor
+						return encR(OpOp, rs1Prime, 4, rs1Prime, rdPrime, 0), true
+					case 2: // c.or
+						return encR(OpOp, rs1Prime, 6, rs1Prime, rdPrime, 0), true
+					default: // c.and
+						return encR(OpOp, rs1Prime, 7, rs1Prime, rdPrime, 0), true
+					}
+				}
+				switch funct2 {
+				case 0: // c.subw
+					return encR(OpOp32, rs1Prime, 0, rs1Prime, rdPrime, 0x20), true
+				case 1: // c.addw
+					return encR(OpOp32, rs1Prime, 0, rs1Prime, rdPrime, 0), true
+				}
+			}
+		case 5: // c.j
+			offset := signExtendBits(bit(c, 3, 1)|bit(c, 4, 2)|bit(c, 5, 3)|bit(c, 11, 4)|bit(c, 2, 5)|
+				bit(c, 7, 6)|bit(c, 6, 7)|bit(c, 9, 8)|bit(c, 10, 9)|bit(c, 8, 10)|bit(c, 12, 11), 12)
+			return encJ(0, offset), true
+		case 6, 7: // c.beqz, c.bnez
+			offset := signExtendBits(bit(c, 3, 1)|bit(c, 4, 2)|bit(c, 10, 3)|bit(c, 11, 4)|bit(c, 2, 5)|
+				bit(c, 5, 6)|bit(c, 6, 7)|bit(c, 12, 8), 9)
+			return encB(funct3-6, rs1Prime, 0, offset), true
+		}
+	case 2:
+		switch funct3 {
+		case 0: // c.slli
+			return encI(OpImm, rdFull, 1, rdFull, int32(shamt)), true
+		case 1: // c.fldsp
+			uimm := bit(c, 5, 3) | bit(c, 6, 4) | bit(c, 12, 5) | ((c>>2)&0x7)<<6
+			return encI(OpLoadFP, rdFull, 3, RegSP, int32(uimm)), true
+		case 2: // c.lwsp
+			if rdFull == 0 {
+				return 0, false
+			}
+			uimm := ((c>>4)&0x7)<<2 | bit(c, 12, 5) | ((c>>2)&0x3)<<6
+			return encI(OpLoad, rdFull, 2, RegSP, int32(uimm)), true
+		case 3: // c.ldsp
+			if rdFull == 0 {
+				return 0, false
+			}
+			uimm := bit(c, 5, 3) | bit(c, 6, 4) | bit(c, 12, 5) | ((c>>2)&0x7)<<6
+			return encI(OpLoad, rdFull, 3, RegSP, int32(uimm)), true
+		case 4:
+			if c&(1<<12) == 0 {
+				if rs2Full == 0 { // c.jr
+					if rdFull == 0 {
+						return 0, false
+					}
+					return encI(OpJalr, 0, 0, rdFull, 0), true
+				}
+				// c.mv
+				return encR(OpOp, rdFull, 0, 0, rs2Full, 0), true
+			}
+			if rs2Full == 0 {
+				if rdFull == 0 { // c.ebreak
+					return InsnEbreak, true
+				}
+				// c.jalr
+				return encI(OpJalr, RegRA, 0, rdFull, 0), true
+			}
+			// c.add
+			return encR(OpOp, rdFull, 0, rdFull, rs2Full, 0), true
+		case 5: // c.fsdsp
+			uimm := ((c>>10)&0x7)<<3 | ((c>>7)&0x7)<<6
+			return encS(OpStoreFP, 3, RegSP, rs2Full, int32(uimm)), true
+		case 6: // c.swsp
+			uimm := ((c>>9)&0xf)<<2 | ((c>>7)&0x3)<<6
+			return encS(OpStore, 2, RegSP, rs2Full, int32(uimm)), true
+		case 7: // c.sdsp
+			uimm := ((c>>10)&0x7)<<3 | ((c>>7)&0x7)<<6
+			return encS(OpStore, 3, RegSP, rs2Full, int32(uimm)), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,154 @@
+package riscv
+
+import "github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+
+type Word = arch.Word
+
+// Registers used by the RISC-V Linux ABI
+const (
+	RegRA = 1
+	RegSP = 2
+	RegTP = 4
+	RegA0 = 10
+	RegA1 = 11
+	RegA2 = 12
+	RegA3 = 13
+	RegA4 = 14
+	RegA5 = 15
+	RegA7 = 17
+)
+
+// rv64 Linux syscall codes, as per the asm-generic syscall table
+const (
+	SysIoGetEvents      = 4
+	SysEpollCreate1     = 20
+	SysEpollCtl         = 21
+	SysEpollPwait       = 22
+	SysFcntl            = 25
+	SysIoctl            = 29
+	SysOpenAt           = 56
+	SysClose            = 57
+	SysPipe2            = 59
+	SysLseek            = 62
+	SysRead             = 63
+	SysWrite            = 64
+	SysPread64          = 67
+	SysReadlinkAt       = 78
+	SysFstatAt          = 79
+	SysFstat            = 80
+	SysExit             = 93
+	SysExitGroup        = 94
+	SysSetTidAddress    = 96
+	SysFutex            = 98
+	SysSetRobustList    = 99
+	SysNanosleep        = 101
+	SysSetITimer        = 103
+	SysTimerCreate      = 107
+	SysTimerSetTime     = 110
+	SysTimerDelete      = 111
+	SysClockGetTime     = 113
+	SysSchedGetAffinity = 123
+	SysSchedYield       = 124
+	SysKill             = 129
+	SysTgkill           = 131
+	SysSigaltstack      = 132
+	SysRtSigaction      = 134
+	SysRtSigprocmask    = 135
+	SysUname            = 160
+	SysGetRLimit        = 163
+	SysPrctl            = 167
+	SysGetpid           = 172
+	SysGetppid          = 173
+	SysGetuid           = 174
+	SysGeteuid          = 175
+	SysGetgid           = 176
+	SysGetegid          = 177
+	SysGetTID           = 178
+	SysBrk              = 214
+	SysMunmap           = 215
+	SysClone            = 220
+	SysMmap             = 222
+	SysMprotect         = 226
+	SysMinCore          = 232
+	SysMadvise          = 233
+	SysRiscvHwProbe     = 258
+	SysPrlimit64        = 261
+	SysGetRandom        = 278
+)
+
+var syscallNames = map[Word]string{
+	SysIoGetEvents:      "io_getevents",
+	SysEpollCreate1:     "epoll_create1",
+	SysEpollCtl:         "epoll_ctl",
+	SysEpollPwait:       "epoll_pwait",
+	SysFcntl:            "fcntl",
+	SysIoctl:            "ioctl",
+	SysOpenAt:           "openat",
+	SysClose:            "close",
+	SysPipe2:            "pipe2",
+	SysLseek:            "lseek",
+	SysRead:             "read",
+	SysWrite:            "write",
+	SysPread64:          "pread64",
+	SysReadlinkAt:       "readlinkat",
+	SysFstatAt:          "newfstatat",
+	SysFstat:            "fstat",
+	SysExit:             "exit",
+	SysExitGroup:        "exit_group",
+	SysSetTidAddress:    "set_tid_address",
+	SysFutex:            "futex",
+	SysSetRobustList:    "set_robust_list",
+	SysNanosleep:        "nanosleep",
+	SysSetITimer:        "setitimer",
+	SysTimerCreate:      "timer_create",
+	SysTimerSetTime:     "timer_settime",
+	SysTimerDelete:      "timer_delete",
+	SysClockGetTime:     "clock_gettime",
+	SysSchedGetAffinity: "sched_getaffinity",
+	SysSchedYield:       "sched_yield",
+	SysKill:             "kill",
+	SysTgkill:           "tgkill",
+	SysSigaltstack:      "sigaltstack",
+	SysRtSigaction:      "rt_sigaction",
+	SysRtSigprocmask:    "rt_sigprocmask",
+	SysUname:            "uname",
+	SysGetRLimit:        "getrlimit",
+	SysPrctl:            "prctl",
+	SysGetpid:           "getpid",
+	SysGetppid:          "getppid",
+	SysGetuid:           "getuid",
+	SysGeteuid:          "geteuid",
+	SysGetgid:           "getgid",
+	SysGetegid:          "getegid",
+	SysGetTID:           "gettid",
+	SysBrk:              "brk",
+	SysMunmap:           "munmap",
+	SysClone:            "clone",
+	SysMmap:             "mmap",
+	SysMprotect:         "mprotect",
+	SysMinCore:          "mincore",
+	SysMadvise:          "madvise",
+	SysRiscvHwProbe:     "riscv_hwprobe",
+	SysPrlimit64:        "prlimit64",
+	SysGetRandom:        "getrandom",
+}
+
+// Linux error codes, returned negated in a0
+const (
+	ENOENT    = 2
+	EBADF     = 9
+	EAGAIN    = 11
+	EINVAL    = 22
+	ENOSYS    = 38
+	ETIMEDOUT = 110
+)
+
+// Control and status registers
+const (
+	CsrFflags  = 0x001
+	CsrFrm     = 0x002
+	CsrFcsr    = 0x003
+	CsrCycle   = 0xC00
+	CsrTime    = 0xC01
+	CsrInstret = 0xC02
+)
@@ -0,0 +1,528 @@
+package riscv
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Rounding modes, as encoded in the rm field of floating point instructions and the frm CSR
+const (
+	RoundNearestEven = 0
+	RoundTowardZero  = 1
+	RoundDown        = 2
+	RoundUp          = 3
+	RoundNearestMax  = 4
+	RoundDynamic     = 7
+)
+
+const (
+	canonicalNaN32 = 0x7fc00000
+	canonicalNaN64 = 0x7ff8000000000000
+	nanBoxMask     = 0xffffffff00000000
+)
+
+// unboxSingle returns the single precision value of a NaN-boxed register, or the canonical NaN if the value is not
+// properly boxed.
+func unboxSingle(v uint64) uint32 {
+	if v&nanBoxMask != nanBoxMask {
+		return canonicalNaN32
+	}
+	return uint32(v)
+}
+
+func boxSingle(v uint32) uint64 {
+	return nanBoxMask | uint64(v)
+}
+
+// canonicalizeSingle replaces any NaN result with the canonical NaN, so results don't depend on the host's NaN
+// propagation.
+func canonicalizeSingle(f float32) uint64 {
+	if f != f {
+		return boxSingle(canonicalNaN32)
+	}
+	return boxSingle(math.Float32bits(f))
+}
+
+func canonicalizeDouble(f float64) uint64 {
+	if math.IsNaN(f) {
+		return canonicalNaN64
+	}
+	return math.Float64bits(f)
+}
+
+// execFloat executes the F and D extension instructions.
+// Rounding arithmetic results only supports round-to-nearest-even, the rounding mode used by Go and C programs.
+// Conversions to integers support all rounding modes. Accrued exception flags are not tracked.
+func (m *InstrumentedState) execFloat(thread *ThreadState, d decoded) error {
+	f := &thread.FRegisters
+	x := func(r uint32) uint64 { return uint64(thread.Registers[r]) }
+	setX := func(r uint32, v uint64) {
+		if r != 0 {
+			thread.Registers[r] = Word(v)
+		}
+	}
+	rm := d.funct3
+	if rm == RoundDynamic {
+		rm = (thread.FCSR >> 5) & 0x7
+	}
+	illegal := func() error { return illegalInstruction(thread.PC, d.insn) }
+	requireRNE := func() error {
+		if rm != RoundNearestEven {
+			return fmt.Errorf("unsupported rounding mode %d for instruction %08x at pc %x", rm, d.insn, thread.PC)
+		}
+		return nil
+	}
+
+	switch d.opcode {
+	case OpLoadFP:
+		addr := x(d.rs1) + d.immI()
+		switch d.funct3 {
+		case 2: // flw
+			f[d.rd] = boxSingle(uint32(m.loadLE(addr, 4)))
+		case 3: // fld
+			f[d.rd] = m.loadLE(addr, 8)
+		default:
+			return illegal()
+		}
+		return nil
+	case OpStoreFP:
+		addr := x(d.rs1) + d.immS()
+		var size uint64
+		switch d.funct3 {
+		case 2: // fsw
+			size = 4
+		case 3: // fsd
+			size = 8
+		default:
+			return illegal()
+		}
+		m.storeLE(addr, size, f[d.rs2])
+		m.handleMemoryUpdate(Word(addr), Word(size))
+		return nil
+	case OpMadd, OpMsub, OpNmsub, OpNmadd:
+		if err := requireRNE(); err != nil {
+			return err
+		}
+		rs3 := d.insn >> 27
+		negProduct := d.opcode == OpNmsub || d.opcode == OpNmadd
+		negAddend := d.opcode == OpMsub || d.opcode == OpNmadd
+		switch d.funct7 & 3 {
+		case 0:
+			a := math.Float32frombits(unboxSingle(f[d.rs1]))
+			b := math.Float32frombits(unboxSingle(f[d.rs2]))
+			c := math.Float32frombits(unboxSingle(f[rs3]))
+			if negProduct {
+				a = -a
+			}
+			if negAddend {
+				c = -c
+			}
+			f[d.rd] = canonicalizeSingle(fma32(a, b, c))
+		case 1:
+			a := math.Float64frombits(f[d.rs1])
+			b := math.Float64frombits(f[d.rs2])
+			c := math.Float64frombits(f[rs3])
+			if negProduct {
+				a = -a
+			}
+			if negAddend {
+				c = -c
+			}
+			f[d.rd] = canonicalizeDouble(math.FMA(a, b, c))
+		default:
+			return illegal()
+		}
+		return nil
+	case OpOpFP:
+	default:
+		return illegal()
+	}
+
+	funct5 := d.funct7 >> 2
+	switch d.funct7 & 3 {
+	case 0: // single precision
+		a := math.Float32frombits(unboxSingle(f[d.rs1]))
+		b := math.Float32frombits(unboxSingle(f[d.rs2]))
+		switch funct5 {
+		case 0x00, 0x01, 0x02, 0x03, 0x0b:
+			if err := requireRNE(); err != nil {
+				return err
+			}
+			var r float32
+			switch funct5 {
+			case 0x00:
+				r = a + b
+			case 0x01:
+				r = a - b
+			case 0x02:
+				r = a * b
+			case 0x03:
+				r = a / b
+			default:
+				if d.rs2 != 0 {
+					return illegal()
+				}
+				// the double precision square root of a single is correctly rounded once narrowed
+				r = float32(math.Sqrt(float64(a)))
+			}
+			f[d.rd] = canonicalizeSingle(r)
+		case 0x04: // fsgnj.s, fsgnjn.s, fsgnjx.s
+			v, ok := signInject(uint64(unboxSingle(f[d.rs1])), uint64(unboxSingle(f[d.rs2])), 1<<31, d.funct3)
+			if !ok {
+				return illegal()
+			}
+			f[d.rd] = boxSingle(uint32(v))
+		case 0x05: // fmin.s, fmax.s
+			if d.funct3 > 1 {
+				return illegal()
+			}
+			f[d.rd] = boxSingle(uint32(minMax(float64(a), float64(b), d.funct3 == 1, true)))
+		case 0x08: // fcvt.s.d
+			if d.rs2 != 1 {
+				return illegal()
+			}
+			v := math.Float64frombits(f[d.rs1])
+			r := float32(v)
+			// the rounding mode only matters when narrowing is inexact
+			if err := requireRNE(); err != nil && float64(r) != v && !math.IsNaN(v) {
+				return err
+			}
+			f[d.rd] = canonicalizeSingle(r)
+		case 0x14: // feq.s, flt.s, fle.s
+			v, ok := compare(float64(a), float64(b), d.funct3)
+			if !ok {
+				return illegal()
+			}
+			setX(d.rd, v)
+		case 0x18: // fcvt.{w,wu,l,lu}.s
+			v, err := floatToInt(float64(a), d.rs2, rm)
+			if err != nil {
+				return illegal()
+			}
+			setX(d.rd, v)
+		case 0x1a: // fcvt.s.{w,wu,l,lu}
+			var r float32
+			switch d.rs2 {
+			case 0:
+				r = float32(int32(x(d.rs1)))
+			case 1:
+				r = float32(uint32(x(d.rs1)))
+			case 2:
+				r = float32(int64(x(d.rs1)))
+			case 3:
+				r = float32(x(d.rs1))
+			default:
+				return illegal()
+			}
+			if err := requireRNE(); err != nil && !isExactIntConversion(x(d.rs1), d.rs2, float64(r)) {
+				return err
+			}
+			f[d.rd] = boxSingle(math.Float32bits(r))
+		case 0x1c:
+			switch {
+			case d.rs2 == 0 && d.funct3 == 0: // fmv.x.w
+				setX(d.rd, signExtend32(f[d.rs1]))
+			case d.rs2 == 0 && d.funct3 == 1: // fclass.s
+				setX(d.rd, classify(float64(a), isSubnormal32(unboxSingle(f[d.rs1])), isSignalingNaN32(unboxSingle(f[d.rs1]))))
+			default:
+				return illegal()
+			}
+		case 0x1e: // fmv.w.x
+			if d.rs2 != 0 || d.funct3 != 0 {
+				return illegal()
+			}
+			f[d.rd] = boxSingle(uint32(x(d.rs1)))
+		default:
+			return illegal()
+		}
+	case 1: // double precision
+		a := math.Float64frombits(f[d.rs1])
+		b := math.Float64frombits(f[d.rs2])
+		switch funct5 {
+		case 0x00, 0x01, 0x02, 0x03, 0x0b:
+			if err := requireRNE(); err != nil {
+				return err
+			}
+			var r float64
+			switch funct5 {
+			case 0x00:
+				r = a + b
+			case 0x01:
+				r = a - b
+			case 0x02:
+				r = a * b
+			case 0x03:
+				r = a / b
+			default:
+				if d.rs2 != 0 {
+					return illegal()
+				}
+				r = math.Sqrt(a)
+			}
+			f[d.rd] = canonicalizeDouble(r)
+		case 0x04: // fsgnj.d, fsgnjn.d, fsgnjx.d
+			v, ok := signInject(f[d.rs1], f[d.rs2], 1<<63, d.funct3)
+			if !ok {
+				return illegal()
+			}
+			f[d.rd] = v
+		case 0x05: // fmin.d, fmax.d
+			if d.funct3 > 1 {
+				return illegal()
+			}
+			f[d.rd] = minMax(a, b, d.funct3 == 1, false)
+		case 0x08: // fcvt.d.s
+			if d.rs2 != 0 {
+				return illegal()
+			}
+			f[d.rd] = canonicalizeDouble(float64(math.Float32frombits(unboxSingle(f[d.rs1]))))
+		case 0x14: // feq.d, flt.d, fle.d
+			v, ok := compare(a, b, d.funct3)
+			if !ok {
+				return illegal()
+			}
+			setX(d.rd, v)
+		case 0x18: // fcvt.{w,wu,l,lu}.d
+			v, err := floatToInt(a, d.rs2, rm)
+			if err != nil {
+				return illegal()
+			}
+			setX(d.rd, v)
+		case 0x1a: // fcvt.d.{w,wu,l,lu}
+			var r float64
+			switch d.rs2 {
+			case 0:
+				r = float64(int32(x(d.rs1)))
+			case 1:
+				r = float64(uint32(x(d.rs1)))
+			case 2:
+				r = float64(int64(x(d.rs1)))
+			case 3:
+				r = float64(x(d.rs1))
+			default:
+				return illegal()
+			}
+			if err := requireRNE(); err != nil && !isExactIntConversion(x(d.rs1), d.rs2, r) {
+				return err
+			}
+			f[d.rd] = math.Float64bits(r)
+		case 0x1c:
+			switch {
+			case d.rs2 == 0 && d.funct3 == 0: // fmv.x.d
+				setX(d.rd, f[d.rs1])
+			case d.rs2 == 0 && d.funct3 == 1: // fclass.d
+				setX(d.rd, classify(a, isSubnormal64(f[d.rs1]), isSignalingNaN64(f[d.rs1])))
+			default:
+				return illegal()
+			}
+		case 0x1e: // fmv.d.x
+			if d.rs2 != 0 || d.funct3 != 0 {
+				return illegal()
+			}
+			f[d.rd] = x(d.rs1)
+		default:
+			return illegal()
+		}
+	default:
+		return illegal()
+	}
+	return nil
+}
+
+// fma32 computes a*b+c with a single rounding to single precision.
+func fma32(a, b, c float32) float32 {
+	// the product of two singles is exact as a double
+	p := float64(a) * float64(b)
+	if math.IsNaN(p) || math.IsInf(p, 0) || c != c || math.IsInf(float64(c), 0) || p == 0 || c == 0 {
+		// special values and zeros are exact in double precision, and the sum rounds once
+		return float32(p + float64(c))
+	}
+	// the exact sum of a product of singles and a single fits well within 1024 bits of mantissa
+	sum := new(big.Float).SetPrec(1024).SetFloat64(p)
+	sum.Add(sum, new(big.Float).SetFloat64(float64(c)))
+	r, _ := sum.Float32()
+	return r
+}
+
+// signInject implements fsgnj, fsgnjn and fsgnjx, selected by funct3, on raw values with the given sign bit.
+func signInject(a, b, signBit uint64, funct3 uint32) (uint64, bool) {
+	var sign uint64
+	switch funct3 {
+	case 0:
+		sign = b & signBit
+	case 1:
+		sign = ^b & signBit
+	case 2:
+		sign = (a ^ b) & signBit
+	default:
+		return 0, false
+	}
+	return a&^signBit | sign, true
+}
+
+// minMax implements fmin and fmax as per IEEE 754-2008 minNum/maxNum, returning raw bits of the requested precision.
+func minMax(a, b float64, isMax bool, single bool) uint64 {
+	bitsOf := func(v float64) uint64 {
+		if single {
+			return uint64(math.Float32bits(float32(v)))
+		}
+		return math.Float64bits(v)
+	}
+	switch {
+	case math.IsNaN(a) && math.IsNaN(b):
+		if single {
+			return canonicalNaN32
+		}
+		return canonicalNaN64
+	case math.IsNaN(a):
+		return bitsOf(b)
+	case math.IsNaN(b):
+		return bitsOf(a)
+	case a == b:
+		// -0.0 is less than +0.0
+		if math.Signbit(a) == isMax {
+			return bitsOf(b)
+		}
+		return bitsOf(a)
+	case (a < b) != isMax:
+		return bitsOf(a)
+	default:
+		return bitsOf(b)
+	}
+}
+
+// compare implements feq, flt and fle, selected by funct3. Comparisons with NaN are false.
+func compare(a, b float64, funct3 uint32) (uint64, bool) {
+	switch funct3 {
+	case 0:
+		return boolToUint64(a <= b), true
+	case 1:
+		return boolToUint64(a < b), true
+	case 2:
+		return boolToUint64(a == b), true
+	}
+	return 0, false
+}
+
+// isExactIntConversion reports whether r is exactly the integer conversion source v, selected by the rs2 field.
+func isExactIntConversion(v uint64, kind uint32, r float64) bool {
+	exact := new(big.Float)
+	switch kind {
+	case 0:
+		exact.SetInt64(int64(int32(v)))
+	case 1:
+		exact.SetUint64(uint64(uint32(v)))
+	case 2:
+		exact.SetInt64(int64(v))
+	default:
+		exact.SetUint64(v)
+	}
+	return exact.Cmp(new(big.Float).SetFloat64(r)) == 0
+}
+
+// floatToInt implements fcvt to w, wu, l and lu, selected by kind, with the given rounding mode.
+// Out of range values and NaN saturate, as per the RISC-V specification. 32-bit results are sign-extended.
+func floatToInt(v float64, kind uint32, rm uint32) (uint64, error) {
+	var r float64
+	switch rm {
+	case RoundNearestEven:
+		r = math.RoundToEven(v)
+	case RoundTowardZero:
+		r = math.Trunc(v)
+	case RoundDown:
+		r = math.Floor(v)
+	case RoundUp:
+		r = math.Ceil(v)
+	case RoundNearestMax:
+		r = math.Round(v)
+	default:
+		return 0, fmt.Errorf("invalid rounding mode %d", rm)
+	}
+	nan := math.IsNaN(v)
+	switch kind {
+	case 0: // w
+		switch {
+		case nan || r > math.MaxInt32:
+			return uint64(math.MaxInt32), nil
+		case r < math.MinInt32:
+			return signExtend32(uint64(1) << 31), nil
+		}
+		return uint64(int64(r)), nil
+	case 1: // wu
+		switch {
+		case nan || r > math.MaxUint32:
+			return ^uint64(0), nil
+		case r <= 0:
+			return 0, nil
+		}
+		return signExtend32(uint64(r)), nil
+	case 2: // l
+		switch {
+		case nan || r >= 1<<63:
+			return math.MaxInt64, nil
+		case r < -(1 << 63):
+			return 1 << 63, nil
+		}
+		return uint64(int64(r)), nil
+	case 3: // lu
+		switch {
+		case nan || r >= 1<<64:
+			return ^uint64(0), nil
+		case r <= 0:
+			return 0, nil
+		}
+		return uint64(r), nil
+	}
+	return 0, fmt.Errorf("invalid conversion type %d", kind)
+}
+
+// classify implements fclass, returning a mask with a single bit set for the class of v.
+func classify(v float64, subnormal, signaling bool) uint64 {
+	neg := math.Signbit(v)
+	var bit uint
+	switch {
+	case math.IsNaN(v):
+		bit = 9
+		if signaling {
+			bit = 8
+		}
+	case math.IsInf(v, 0):
+		bit = 7
+		if neg {
+			bit = 0
+		}
+	case v == 0:
+		bit = 4
+		if neg {
+			bit = 3
+		}
+	case subnormal:
+		bit = 5
+		if neg {
+			bit = 2
+		}
+	default:
+		bit = 6
+		if neg {
+			bit = 1
+		}
+	}
+	return 1 << bit
+}
+
+func isSubnormal32(v uint32) bool {
+	return v&0x7f800000 == 0 && v&0x007fffff != 0
+}
+
+func isSignalingNaN32(v uint32) bool {
+	return v&0x7f800000 == 0x7f800000 && v&0x007fffff != 0 && v&0x00400000 == 0
+}
+
+func isSubnormal64(v uint64) bool {
+	return v&0x7ff0000000000000 == 0 && v&0x000fffffffffffff != 0
+}
+
+func isSignalingNaN64(v uint64) bool {
+	return v&0x7ff0000000000000 == 0x7ff0000000000000 && v&0x000fffffffffffff != 0 && v&0x0008000000000000 == 0
+}
@@ -0,0 +1,574 @@
+package riscv
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Major opcodes, as per the RISC-V unprivileged ISA base opcode map
+const (
+	OpLoad     = 0x03
+	OpLoadFP   = 0x07
+	OpMiscMem  = 0x0f
+	OpImm      = 0x13
+	OpAuipc    = 0x17
+	OpImm32    = 0x1b
+	OpStore    = 0x23
+	OpStoreFP  = 0x27
+	OpAmo      = 0x2f
+	OpOp       = 0x33
+	OpLui      = 0x37
+	OpOp32     = 0x3b
+	OpMadd     = 0x43
+	OpMsub     = 0x47
+	OpNmsub    = 0x4b
+	OpNmadd    = 0x4f
+	OpOpFP     = 0x53
+	OpBranch   = 0x63
+	OpJalr     = 0x67
+	OpJal      = 0x6f
+	OpSystem   = 0x73
+	InsnEcall  = 0x00000073
+	InsnEbreak = 0x00100073
+)
+
+// decoded holds the fields of an instruction. Not every field is meaningful for every format.
+type decoded struct {
+	insn   uint32
+	opcode uint32
+	rd     uint32
+	funct3 uint32
+	rs1    uint32
+	rs2    uint32
+	funct7 uint32
+}
+
+func decode(insn uint32) decoded {
+	return decoded{
+		insn:   insn,
+		opcode: insn & 0x7f,
+		rd:     (insn >> 7) & 0x1f,
+		funct3: (insn >> 12) & 0x7,
+		rs1:    (insn >> 15) & 0x1f,
+		rs2:    (insn >> 20) & 0x1f,
+		funct7: insn >> 25,
+	}
+}
+
+func (d decoded) immI() uint64 {
+	return uint64(int64(int32(d.insn) >> 20))
+}
+
+func (d decoded) immS() uint64 {
+	return uint64(int64(int32(d.insn&0xfe000000)>>20)) | uint64((d.insn>>7)&0x1f)
+}
+
+func (d decoded) immB() uint64 {
+	imm := int64(int32(d.insn&0x80000000)>>19) | // imm[12]
+		int64((d.insn&0x80)<<4) | // imm[11]
+		int64((d.insn>>20)&0x7e0) | // imm[10:5]
+		int64((d.insn>>7)&0x1e) // imm[4:1]
+	return uint64(imm)
+}
+
+func (d decoded) immU() uint64 {
+	return uint64(int64(int32(d.insn & 0xfffff000)))
+}
+
+func (d decoded) immJ() uint64 {
+	imm := int64(int32(d.insn&0x80000000)>>11) | // imm[20]
+		int64(d.insn&0xff000) | // imm[19:12]
+		int64((d.insn>>9)&0x800) | // imm[11]
+		int64((d.insn>>20)&0x7fe) // imm[10:1]
+	return uint64(imm)
+}
+
+func signExtend32(v uint64) uint64 {
+	return uint64(int64(int32(v)))
+}
+
+func illegalInstruction(pc Word, insn uint32) error {
+	return fmt.Errorf("invalid instruction %08x at pc %x", insn, pc)
+}
+
+// execInstruction executes the instruction at the current thread's pc.
+func (m *InstrumentedState) execInstruction(thread *ThreadState) error {
+	pc := uint64(thread.PC)
+	insn, length, err := m.fetchInstruction(thread.PC)
+	if err != nil {
+		return err
+	}
+	d := decode(insn)
+	x := func(r uint32) uint64 { return uint64(thread.Registers[r]) }
+	setX := func(r uint32, v uint64) {
+		if r != 0 { // x0 is hardwired to zero
+			thread.Registers[r] = Word(v)
+		}
+	}
+	nextPC := pc + length
+
+	switch d.opcode {
+	case OpLui:
+		setX(d.rd, d.immU())
+	case OpAuipc:
+		setX(d.rd, pc+d.immU())
+	case OpJal:
+		nextPC = pc + d.immJ()
+		setX(d.rd, pc+length)
+		if d.rd == RegRA {
+			m.stackTracker.PushStack(Word(pc), Word(nextPC))
+		}
+	case OpJalr:
+		if d.funct3 != 0 {
+			return illegalInstruction(thread.PC, insn)
+		}
+		nextPC = (x(d.rs1) + d.immI()) &^ 1
+		setX(d.rd, pc+length)
+		if d.rd == RegRA {
+			m.stackTracker.PushStack(Word(pc), Word(nextPC))
+		} else if d.rd == 0 && d.rs1 == RegRA {
+			m.stackTracker.PopStack()
+		}
+	case OpBranch:
+		a, b := x(d.rs1), x(d.rs2)
+		var taken bool
+		switch d.funct3 {
+		case 0: // beq
+			taken = a == b
+		case 1: // bne
+			taken = a != b
+		case 4: // blt
+			taken = int64(a) < int64(b)
+		case 5: // bge
+			taken = int64(a) >= int64(b)
+		case 6: // bltu
+			taken = a < b
+		case 7: // bgeu
+			taken = a >= b
+		default:
+			return illegalInstruction(thread.PC, insn)
+		}
+		if taken {
+			nextPC = pc + d.immB()
+		}
+	case OpLoad:
+		addr := x(d.rs1) + d.immI()
+		var v uint64
+		switch d.funct3 {
+		case 0: // lb
+			v = uint64(int64(int8(m.loadLE(addr, 1))))
+		case 1: // lh
+			v = uint64(int64(int16(m.loadLE(addr, 2))))
+		case 2: // lw
+			v = signExtend32(m.loadLE(addr, 4))
+		case 3: // ld
+			v = m.loadLE(addr, 8)
+		case 4: // lbu
+			v = m.loadLE(addr, 1)
+		case 5: // lhu
+			v = m.loadLE(addr, 2)
+		case 6: // lwu
+			v = m.loadLE(addr, 4)
+		default:
+			return illegalInstruction(thread.PC, insn)
+		}
+		setX(d.rd, v)
+	case OpStore:
+		if d.funct3 > 3 {
+			return illegalInstruction(thread.PC, insn)
+		}
+		addr := x(d.rs1) + d.immS()
+		size := uint64(1) << d.funct3
+		m.storeLE(addr, size, x(d.rs2))
+		m.handleMemoryUpdate(Word(addr), Word(size))
+	case OpImm:
+		v, ok := execOpImm(d, x(d.rs1))
+		if !ok {
+			return illegalInstruction(thread.PC, insn)
+		}
+		setX(d.rd, v)
+	case OpImm32:
+		v, ok := execOpImm32(d, x(d.rs1))
+		if !ok {
+			return illegalInstruction(thread.PC, insn)
+		}
+		setX(d.rd, v)
+	case OpOp:
+		v, ok := execOp(d, x(d.rs1), x(d.rs2))
+		if !ok {
+			return illegalInstruction(thread.PC, insn)
+		}
+		setX(d.rd, v)
+	case OpOp32:
+		v, ok := execOp32(d, x(d.rs1), x(d.rs2))
+		if !ok {
+			return illegalInstruction(thread.PC, insn)
+		}
+		setX(d.rd, v)
+	case OpMiscMem:
+		// fence and fence.i: memory is sequentially consistent and instructions are never cached
+	case OpAmo:
+		v, err := m.execAmo(thread, d)
+		if err != nil {
+			return err
+		}
+		setX(d.rd, v)
+	case OpSystem:
+		switch {
+		case insn == InsnEcall && length == 4:
+			return m.handleSyscall(thread)
+		case insn == InsnEbreak:
+			return fmt.Errorf("ebreak at pc %x", thread.PC)
+		case d.funct3 != 0 && d.funct3 != 4:
+			v, ok := m.execCsr(thread, d)
+			if !ok {
+				return illegalInstruction(thread.PC, insn)
+			}
+			setX(d.rd, v)
+		default:
+			return illegalInstruction(thread.PC, insn)
+		}
+	case OpLoadFP, OpStoreFP, OpMadd, OpMsub, OpNmsub, OpNmadd, OpOpFP:
+		if err := m.execFloat(thread, d); err != nil {
+			return err
+		}
+	default:
+		return illegalInstruction(thread.PC, insn)
+	}
+
+	thread.PC = Word(nextPC)
+	return nil
+}
+
+func execOpImm(d decoded, a uint64) (uint64, bool) {
+	imm := d.immI()
+	shamt := (d.insn >> 20) & 0x3f
+	switch d.funct3 {
+	case 0: // addi
+		return a + imm, true
+	case 1: // slli
+		if d.insn>>26 != 0 {
+			return 0, false
+		}
+		return a << shamt, true
+	case 2: // slti
+		return boolToUint64(int64(a) < int64(imm)), true
+	case 3: // sltiu
+		return boolToUint64(a < imm), true
+	case 4: // xori
+		return a ^ imm, true
+	case 5:
+		switch d.insn >> 26 {
+		case 0x00: // srli
+			return a >> shamt, true
+		case 0x10: // srai
+			return uint64(int64(a) >> shamt), true
+		}
+	case 6: // ori
+		return a | imm, true
+	case 7: // andi
+		return a & imm, true
+	}
+	return 0, false
+}
+
+func execOpImm32(d decoded, a uint64) (uint64, bool) {
+	shamt := d.rs2
+	switch d.funct3 {
+	case 0: // addiw
+		return signExtend32(a + d.immI()), true
+	case 1: // slliw
+		if d.funct7 == 0 {
+			return signExtend32(a << shamt), true
+		}
+	case 5:
+		switch d.funct7 {
+		case 0x00: // srliw
+			return signExtend32(uint64(uint32(a) >> shamt)), true
+		case 0x20: // sraiw
+			return uint64(int64(int32(a) >> shamt)), true
+		}
+	}
+	return 0, false
+}
+
+func execOp(d decoded, a, b uint64) (uint64, bool) {
+	switch d.funct7 {
+	case 0x00:
+		switch d.funct3 {
+		case 0: // add
+			return a + b, true
+		case 1: // sll
+			return a << (b & 0x3f), true
+		case 2: // slt
+			return boolToUint64(int64(a) < int64(b)), true
+		case 3: // sltu
+			return boolToUint64(a < b), true
+		case 4: // xor
+			return a ^ b, true
+		case 5: // srl
+			return a >> (b & 0x3f), true
+		case 6: // or
+			return a | b, true
+		case 7: // and
+			return a & b, true
+		}
+	case 0x20:
+		switch d.funct3 {
+		case 0: // sub
+			return a - b, true
+		case 5: // sra
+			return uint64(int64(a) >> (b & 0x3f)), true
+		}
+	case 0x01:
+		return execMul(d.funct3, a, b), true
+	}
+	return 0, false
+}
+
+// execMul executes the M extension operation selected by funct3 on 64-bit operands.
+func execMul(funct3 uint32, a, b uint64) uint64 {
+	switch funct3 {
+	case 0: // mul
+		return a * b
+	case 1: // mulh
+		hi, _ := bits.Mul64(a, b)
+		if int64(a) < 0 {
+			hi -= b
+		}
+		if int64(b) < 0 {
+			hi -= a
+		}
+		return hi
+	case 2: // mulhsu
+		hi, _ := bits.Mul64(a, b)
+		if int64(a) < 0 {
+			hi -= b
+		}
+		return hi
+	case 3: // mulhu
+		hi, _ := bits.Mul64(a, b)
+		return hi
+	case 4: // div
+		if b == 0 {
+			return ^uint64(0)
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			return a
+		}
+		return uint64(int64(a) / int64(b))
+	case 5: // divu
+		if b == 0 {
+			return ^uint64(0)
+		}
+		return a / b
+	case 6: // rem
+		if b == 0 {
+			return a
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			return 0
+		}
+		return uint64(int64(a) % int64(b))
+	default: // remu
+		if b == 0 {
+			return a
+		}
+		return a % b
+	}
+}
+
+func execOp32(d decoded, a, b uint64) (uint64, bool) {
+	switch d.funct7 {
+	case 0x00:
+		switch d.funct3 {
+		case 0: // addw
+			return signExtend32(a + b), true
+		case 1: // sllw
+			return signExtend32(a << (b & 0x1f)), true
+		case 5: // srlw
+			return signExtend32(uint64(uint32(a) >> (b & 0x1f))), true
+		}
+	case 0x20:
+		switch d.funct3 {
+		case 0: // subw
+			return signExtend32(a - b), true
+		case 5: // sraw
+			return uint64(int64(int32(a) >> (b & 0x1f))), true
+		}
+	case 0x01:
+		sa, sb := int32(a), int32(b)
+		ua, ub := uint32(a), uint32(b)
+		switch d.funct3 {
+		case 0: // mulw
+			return signExtend32(uint64(ua * ub)), true
+		case 4: // divw
+			if sb == 0 {
+				return ^uint64(0), true
+			}
+			if sa == math.MinInt32 && sb == -1 {
+				return uint64(int64(sa)), true
+			}
+			return uint64(int64(sa / sb)), true
+		case 5: // divuw
+			if ub == 0 {
+				return ^uint64(0), true
+			}
+			return signExtend32(uint64(ua / ub)), true
+		case 6: // remw
+			if sb == 0 {
+				return uint64(int64(sa)), true
+			}
+			if sa == math.MinInt32 && sb == -1 {
+				return 0, true
+			}
+			return uint64(int64(sa % sb)), true
+		case 7: // remuw
+			if ub == 0 {
+				return signExtend32(uint64(ua)), true
+			}
+			return signExtend32(uint64(ua % ub)), true
+		}
+	}
+	return 0, false
+}
+
+// execAmo executes the A extension instructions: load-reserved/store-conditional and atomic memory operations.
+// Threads are never preempted mid-instruction, so every AMO is trivially atomic.
+func (m *InstrumentedState) execAmo(thread *ThreadState, d decoded) (uint64, error) {
+	var size uint64
+	switch d.funct3 {
+	case 2:
+		size = 4
+	case 3:
+		size = 8
+	default:
+		return 0, illegalInstruction(thread.PC, d.insn)
+	}
+	addr := uint64(thread.Registers[d.rs1])
+	if addr&(size-1) != 0 {
+		return 0, fmt.Errorf("misaligned atomic access at %x, pc %x", addr, thread.PC)
+	}
+	extend := func(v uint64) uint64 {
+		if size == 4 {
+			return signExtend32(v)
+		}
+		return v
+	}
+
+	funct5 := d.insn >> 27
+	switch funct5 {
+	case 0x02: // lr
+		if d.rs2 != 0 {
+			return 0, illegalInstruction(thread.PC, d.insn)
+		}
+		m.state.ReservationActive = true
+		m.state.ReservationAddress = Word(addr)
+		m.state.ReservationOwner = thread.ThreadId
+		return extend(m.loadLE(addr, size)), nil
+	case 0x03: // sc
+		ok := m.state.ReservationActive && m.state.ReservationOwner == thread.ThreadId && m.state.ReservationAddress == Word(addr)
+		// sc always invalidates the reservation, whether or not it succeeds
+		m.clearReservation()
+		if !ok {
+			return 1, nil
+		}
+		m.storeLE(addr, size, uint64(thread.Registers[d.rs2]))
+		return 0, nil
+	}
+
+	old := extend(m.loadLE(addr, size))
+	src := extend(uint64(thread.Registers[d.rs2]))
+	var v uint64
+	switch funct5 {
+	case 0x00: // amoadd
+		v = old + src
+	case 0x01: // amoswap
+		v = src
+	case 0x04: // amoxor
+		v = old ^ src
+	case 0x08: // amoor
+		v = old | src
+	case 0x0c: // amoand
+		v = old & src
+	case 0x10: // amomin
+		v = old
+		if int64(src) < int64(old) {
+			v = src
+		}
+	case 0x14: // amomax
+		v = old
+		if int64(src) > int64(old) {
+			v = src
+		}
+	case 0x18: // amominu
+		v = min(old, src)
+	case 0x1c: // amomaxu
+		v = max(old, src)
+	default:
+		return 0, illegalInstruction(thread.PC, d.insn)
+	}
+	m.storeLE(addr, size, v)
+	m.handleMemoryUpdate(Word(addr), Word(size))
+	return old, nil
+}
+
+// execCsr executes the Zicsr instructions. Only the floating point CSRs are writable.
+// The cycle, time and instret counters all read the step counter.
+func (m *InstrumentedState) execCsr(thread *ThreadState, d decoded) (uint64, bool) {
+	csr := d.insn >> 20
+	var old uint64
+	switch csr {
+	case CsrFflags:
+		old = uint64(thread.FCSR & 0x1f)
+	case CsrFrm:
+		old = uint64(thread.FCSR>>5) & 0x7
+	case CsrFcsr:
+		old = uint64(thread.FCSR & 0xff)
+	case CsrCycle, CsrTime, CsrInstret:
+		old = m.state.Step
+	default:
+		return 0, false
+	}
+
+	src := uint64(d.rs1) // the immediate forms use rs1 as a 5-bit zero-extended immediate
+	if d.funct3 < 4 {
+		src = uint64(thread.Registers[d.rs1])
+	}
+	var v uint64
+	switch d.funct3 & 3 {
+	case 1: // csrrw
+		v = src
+	case 2: // csrrs
+		v = old | src
+	case 3: // csrrc
+		v = old &^ src
+	default:
+		return 0, false
+	}
+	// csrrs and csrrc with a zero rs1 do not write the CSR
+	if d.funct3&3 != 1 && d.rs1 == 0 {
+		return old, true
+	}
+
+	switch csr {
+	case CsrFflags:
+		thread.FCSR = thread.FCSR&^0x1f | uint32(v&0x1f)
+	case CsrFrm:
+		thread.FCSR = thread.FCSR&^0xe0 | uint32(v&0x7)<<5
+	case CsrFcsr:
+		thread.FCSR = uint32(v & 0xff)
+	default:
+		// writes to the read-only counters are illegal
+		return 0, false
+	}
+	return old, true
+}
+
+func boolToUint64(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package riscv
+
+import (
+	"io"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
+)
+
+type InstrumentedState struct {
+	state *State
+
+	log    log.Logger
+	stdOut io.Writer
+	stdErr io.Writer
+
+	memoryTracker *memoryTracker
+	stackTracker  ThreadedStackTracker
+
+	preimageOracle *exec.TrackingPreimageOracleReader
+	meta           mipsevm.Metadata
+}
+
+var _ mipsevm.FPVM = (*InstrumentedState)(nil)
+
+func NewInstrumentedState(state *State, po mipsevm.PreimageOracle, stdOut, stdErr io.Writer, log log.Logger, meta mipsevm.Metadata) *InstrumentedState {
+	return &InstrumentedState{
+		state:          state,
+		log:            log,
+		stdOut:         stdOut,
+		stdErr:         stdErr,
+		memoryTracker:  newMemoryTracker(state.Memory),
+		stackTracker:   &NoopThreadedStackTracker{},
+		preimageOracle: exec.NewTrackingPreimageOracleReader(po),
+		meta:           meta,
+	}
+}
+
+func (m *InstrumentedState) InitDebug() error {
+	stackTracker, err := NewThreadedStackTracker(m.state, m.meta)
+	if err != nil {
+		return err
+	}
+	m.stackTracker = stackTracker
+	return nil
+}
+
+// Step executes a single step. When proof is true the witness includes, in order, the serialized current thread,
+// the memory proofs of the words holding the first and last parcel of the instruction (which differ only when the
+// instruction spans a word boundary), and the memory proofs of up to two words accessed by the instruction.
+func (m *InstrumentedState) Step(proof bool) (wit *mipsevm.StepWitness, err error) {
+	m.preimageOracle.Reset()
+	m.memoryTracker.Reset(proof)
+
+	if proof {
+		proofData := make([]byte, 0)
+		threadProof := m.state.EncodeThreadProof()
+		insnProof := m.state.Memory.MerkleProof(m.state.GetPC())
+		insnProof2 := m.state.Memory.MerkleProof(m.state.GetPC() + 2)
+		proofData = append(proofData, threadProof[:]...)
+		proofData = append(proofData, insnProof[:]...)
+		proofData = append(proofData, insnProof2[:]...)
+
+		encodedWitness, stateHash := m.state.EncodeWitness()
+		wit = &mipsevm.StepWitness{
+			State:     encodedWitness,
+			StateHash: stateHash,
+			ProofData: proofData,
+		}
+	}
+	err = m.rvStep()
+	if err != nil {
+		return nil, err
+	}
+
+	if proof {
+		memProof := m.memoryTracker.MemProof()
+		memProof2 := m.memoryTracker.MemProof2()
+		wit.ProofData = append(wit.ProofData, memProof[:]...)
+		wit.ProofData = append(wit.ProofData, memProof2[:]...)
+		lastPreimageKey, lastPreimage, lastPreimageOffset := m.preimageOracle.LastPreimage()
+		if lastPreimageOffset != ^arch.Word(0) {
+			wit.PreimageOffset = lastPreimageOffset
+			wit.PreimageKey = lastPreimageKey
+			wit.PreimageValue = lastPreimage
+		}
+	}
+	return
+}
+
+func (m *InstrumentedState) CheckInfiniteLoop() bool {
+	return false
+}
+
+func (m *InstrumentedState) LastPreimage() ([32]byte, []byte, arch.Word) {
+	return m.preimageOracle.LastPreimage()
+}
+
+func (m *InstrumentedState) GetState() mipsevm.FPVMState {
+	return m.state
+}
+
+func (m *InstrumentedState) GetDebugInfo() *mipsevm.DebugInfo {
+	return &mipsevm.DebugInfo{
+		Pages:               m.state.Memory.PageCount(),
+		MemoryUsed:          hexutil.Uint64(m.state.Memory.UsageRaw()),
+		NumPreimageRequests: m.preimageOracle.NumPreimageRequests(),
+		TotalPreimageSize:   m.preimageOracle.TotalPreimageSize(),
+	}
+}
+
+func (m *InstrumentedState) Traceback() {
+	m.stackTracker.Traceback()
+}
+
+func (m *InstrumentedState) LookupSymbol(addr arch.Word) string {
+	if m.meta == nil {
+		return ""
+	}
+	return m.meta.LookupSymbol(addr)
+}
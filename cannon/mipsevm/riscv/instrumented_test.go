@@ -0,0 +1,425 @@
+//go:build cannon64
+// +build cannon64
+
+package riscv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/testutil"
+)
+
+const (
+	testPC   = 0x1000
+	testData = 0x8000
+)
+
+// newTestVM creates a VM with the little-endian encoded program at testPC. Parcels are 16-bit, so compressed and
+// 32-bit instructions can be mixed.
+func newTestVM(t *testing.T, parcels ...uint16) (*InstrumentedState, *State, *bytes.Buffer) {
+	state := CreateInitialState(testPC, 0x10_0000)
+	var code []byte
+	for _, p := range parcels {
+		code = binary.LittleEndian.AppendUint16(code, p)
+	}
+	require.NoError(t, state.Memory.SetMemoryRange(testPC, bytes.NewReader(code)))
+	var stdOut bytes.Buffer
+	vm := NewInstrumentedState(state, testutil.StaticOracle(t, []byte{}), &stdOut, &stdOut, testutil.CreateLogger(), nil)
+	return vm, state, &stdOut
+}
+
+// insns splits 32-bit instructions into their 16-bit parcels.
+func insns(instructions ...uint32) []uint16 {
+	var out []uint16
+	for _, insn := range instructions {
+		out = append(out, uint16(insn), uint16(insn>>16))
+	}
+	return out
+}
+
+func addi(rd, rs1 uint32, imm int32) uint32 { return encI(OpImm, rd, 0, rs1, imm) }
+
+func TestInstrumentedState_IntegerOps(t *testing.T) {
+	cases := []struct {
+		name     string
+		insn     uint32
+		rs1, rs2 uint64
+		expected uint64
+	}{
+		{"addi", addi(5, 6, -1), 10, 0, 9},
+		{"lui", encU(OpLui, 5, -0x1000), 0, 0, 0xffff_ffff_ffff_f000},
+		{"sub", encR(OpOp, 5, 0, 6, 7, 0x20), 3, 5, 0xffff_ffff_ffff_fffe},
+		{"slt", encR(OpOp, 5, 2, 6, 7, 0), 0xffff_ffff_ffff_ffff, 0, 1},
+		{"sltu", encR(OpOp, 5, 3, 6, 7, 0), 0xffff_ffff_ffff_ffff, 0, 0},
+		{"sra", encR(OpOp, 5, 5, 6, 7, 0x20), 0x8000_0000_0000_0000, 63, 0xffff_ffff_ffff_ffff},
+		{"srai", encI(OpImm, 5, 5, 6, 0x400|4), 0x8000_0000_0000_0000, 0, 0xf800_0000_0000_0000},
+		{"addw", encR(OpOp32, 5, 0, 6, 7, 0), 0x7fff_ffff, 1, 0xffff_ffff_8000_0000},
+		{"srliw", encI(OpImm32, 5, 5, 6, 1), 0xffff_ffff_8000_0000, 0, 0x4000_0000},
+		{"sraiw", encI(OpImm32, 5, 5, 6, 0x400|1), 0x8000_0000, 0, 0xffff_ffff_c000_0000},
+		{"mul", encR(OpOp, 5, 0, 6, 7, 1), 0xffff_ffff_ffff_ffff, 3, 0xffff_ffff_ffff_fffd},
+		{"mulh", encR(OpOp, 5, 1, 6, 7, 1), 0xffff_ffff_ffff_ffff, 3, 0xffff_ffff_ffff_ffff},
+		{"mulhsu", encR(OpOp, 5, 2, 6, 7, 1), 0xffff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff},
+		{"mulhu", encR(OpOp, 5, 3, 6, 7, 1), 0xffff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff, 0xffff_ffff_ffff_fffe},
+		{"div", encR(OpOp, 5, 4, 6, 7, 1), 0xffff_ffff_ffff_fff9, 2, 0xffff_ffff_ffff_fffd},
+		{"div by zero", encR(OpOp, 5, 4, 6, 7, 1), 7, 0, 0xffff_ffff_ffff_ffff},
+		{"div overflow", encR(OpOp, 5, 4, 6, 7, 1), 0x8000_0000_0000_0000, 0xffff_ffff_ffff_ffff, 0x8000_0000_0000_0000},
+		{"rem overflow", encR(OpOp, 5, 6, 6, 7, 1), 0x8000_0000_0000_0000, 0xffff_ffff_ffff_ffff, 0},
+		{"remu by zero", encR(OpOp, 5, 7, 6, 7, 1), 7, 0, 7},
+		{"divw", encR(OpOp32, 5, 4, 6, 7, 1), 0x1_ffff_fff9, 2, 0xffff_ffff_ffff_fffd},
+		{"divuw by zero", encR(OpOp32, 5, 5, 6, 7, 1), 7, 0, 0xffff_ffff_ffff_ffff},
+		{"remw", encR(OpOp32, 5, 6, 6, 7, 1), 0xffff_fff9, 2, 0xffff_ffff_ffff_ffff},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			vm, state, _ := newTestVM(t, insns(c.insn)...)
+			thread := state.GetCurrentThread()
+			thread.Registers[6] = Word(c.rs1)
+			thread.Registers[7] = Word(c.rs2)
+			_, err := vm.Step(false)
+			require.NoError(t, err)
+			require.Equal(t, Word(c.expected), thread.Registers[5])
+			require.Equal(t, Word(testPC+4), thread.PC)
+		})
+	}
+}
+
+func TestInstrumentedState_ZeroRegister(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(addi(0, 0, 5))...)
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(0), state.GetCurrentThread().Registers[0])
+}
+
+func TestInstrumentedState_LoadStore(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(
+		encS(OpStore, 3, 6, 7, 4), // sd x7, 4(x6): spans two memory words
+		encI(OpLoad, 5, 2, 6, 8),  // lw x5, 8(x6)
+		encI(OpLoad, 8, 6, 6, 8),  // lwu x8, 8(x6)
+		encI(OpLoad, 9, 0, 6, 11), // lb x9, 11(x6)
+	)...)
+	thread := state.GetCurrentThread()
+	thread.Registers[6] = testData
+	thread.Registers[7] = 0x8877_6655_4433_2211
+	for i := 0; i < 4; i++ {
+		_, err := vm.Step(false)
+		require.NoError(t, err)
+	}
+	// memory words are big-endian, so the little-endian value is byte-reversed within them
+	require.Equal(t, Word(0x0000_0000_1122_3344), state.Memory.GetMemory(testData))
+	require.Equal(t, Word(0x5566_7788_0000_0000), state.Memory.GetMemory(testData+8))
+	require.Equal(t, Word(0xffff_ffff_8877_6655), thread.Registers[5])
+	require.Equal(t, Word(0x8877_6655), thread.Registers[8])
+	require.Equal(t, Word(0xffff_ffff_ffff_ff88), thread.Registers[9])
+}
+
+func TestInstrumentedState_JumpAndBranch(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(
+		encJ(RegRA, 8),            // jal ra, +8
+		addi(5, 0, 1),             // skipped
+		encB(1, 6, 0, -8),         // bne x6, x0, -8
+		encI(OpJalr, 0, 0, 7, 16), // jalr x0, 16(x7)
+	)...)
+	thread := state.GetCurrentThread()
+	thread.Registers[7] = testPC
+
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(testPC+8), thread.PC)
+	require.Equal(t, Word(testPC+4), thread.Registers[RegRA])
+
+	_, err = vm.Step(false) // not taken
+	require.NoError(t, err)
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(testPC+16), thread.PC)
+	require.Equal(t, Word(0), thread.Registers[5])
+}
+
+func TestInstrumentedState_Compressed(t *testing.T) {
+	vm, state, _ := newTestVM(t,
+		0x4515, // c.li a0, 5
+		0x0505, // c.addi a0, 1
+		0x6502, // c.ld a0, 0(sp)
+	)
+	thread := state.GetCurrentThread()
+	thread.Registers[RegSP] = testData
+	state.Memory.SetMemory(testData, 0x2a00_0000_0000_0000)
+
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(5), thread.Registers[RegA0])
+	require.Equal(t, Word(testPC+2), thread.PC)
+
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(6), thread.Registers[RegA0])
+
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(0x2a), thread.Registers[RegA0])
+	require.Equal(t, Word(testPC+6), thread.PC)
+}
+
+func TestExpandCompressed(t *testing.T) {
+	cases := []struct {
+		name       string
+		compressed uint32
+		expected   uint32
+	}{
+		{"c.ld", 0x6502, encI(OpLoad, RegA0, 3, RegSP, 0)},
+		{"c.sdsp", 0xe406, encS(OpStore, 3, RegSP, RegRA, 8)},
+		{"c.addi16sp", 0x7179, encI(OpImm, RegSP, 0, RegSP, -48)},
+		{"c.j", 0xbfd5, encJ(0, -12)},
+		{"c.beqz", 0xc119, encB(0, RegA0, 0, 6)},
+		{"c.jr", 0x8082, encI(OpJalr, 0, 0, RegRA, 0)},
+		{"c.mv", 0x852e, encR(OpOp, RegA0, 0, 0, RegA1, 0)},
+		{"c.lui", 0x6785, encU(OpLui, 15, 0x1000)},
+		{"c.srai", 0x8505, encI(OpImm, RegA0, 5, RegA0, 0x400|1)},
+		{"c.subw", 0x9d0d, encR(OpOp32, RegA0, 0, RegA0, RegA1, 0x20)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, ok := expandCompressed(c.compressed)
+			require.True(t, ok)
+			require.Equalf(t, c.expected, actual, "expected %08x, got %08x", c.expected, actual)
+		})
+	}
+
+	_, ok := expandCompressed(0)
+	require.False(t, ok, "all-zero instruction is illegal")
+}
+
+func TestInstrumentedState_ReservedLoadStore(t *testing.T) {
+	lrd := encR(OpAmo, 5, 3, 6, 0, 0x02<<2)
+	scd := encR(OpAmo, 8, 3, 6, 7, 0x03<<2)
+	vm, state, _ := newTestVM(t, insns(lrd, scd, scd)...)
+	thread := state.GetCurrentThread()
+	thread.Registers[6] = testData
+	thread.Registers[7] = 0x1234
+	state.Memory.SetMemory(testData, 0x0100_0000_0000_0000)
+
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(1), thread.Registers[5])
+	require.True(t, state.ReservationActive)
+
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(0), thread.Registers[8], "sc must succeed")
+	require.Equal(t, Word(0x3412_0000_0000_0000), state.Memory.GetMemory(testData))
+	require.False(t, state.ReservationActive)
+
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(1), thread.Registers[8], "sc without reservation must fail")
+}
+
+func TestInstrumentedState_AtomicAddWord(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(encR(OpAmo, 5, 2, 6, 7, 0x00<<2))...)
+	thread := state.GetCurrentThread()
+	thread.Registers[6] = testData + 4
+	thread.Registers[7] = 1
+	state.Memory.SetMemory(testData, 0x0000_0000_0000_0080)
+
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(0xffff_ffff_8000_0000), thread.Registers[5], "old value is sign-extended")
+	require.Equal(t, Word(0x0000_0000_0100_0080), state.Memory.GetMemory(testData))
+}
+
+func TestInstrumentedState_Float(t *testing.T) {
+	fp := func(funct5, fmt, rm, rd, rs1, rs2 uint32) uint32 {
+		return encR(OpOpFP, rd, rm, rs1, rs2, funct5<<2|fmt)
+	}
+	f64 := math.Float64bits
+	cases := []struct {
+		name    string
+		insn    uint32
+		f1, f2  uint64
+		x1      uint64
+		expectF *uint64
+		expectX *uint64
+	}{
+		{name: "fadd.d", insn: fp(0x00, 1, 7, 3, 1, 2), f1: f64(1.5), f2: f64(2.25), expectF: ptr(f64(3.75))},
+		{name: "fdiv.d nan is canonical", insn: fp(0x03, 1, 0, 3, 1, 2), f1: f64(0), f2: f64(0), expectF: ptr(uint64(canonicalNaN64))},
+		{name: "fmul.s", insn: fp(0x02, 0, 0, 3, 1, 2), f1: boxSingle(math.Float32bits(1.5)), f2: boxSingle(math.Float32bits(-2)), expectF: ptr(boxSingle(math.Float32bits(-3)))},
+		{name: "unboxed single is nan", insn: fp(0x00, 0, 0, 3, 1, 2), f1: uint64(math.Float32bits(1)), f2: boxSingle(math.Float32bits(1)), expectF: ptr(boxSingle(canonicalNaN32))},
+		{name: "fmin.d of zeros", insn: fp(0x05, 1, 0, 3, 1, 2), f1: f64(0), f2: f64(math.Copysign(0, -1)), expectF: ptr(f64(math.Copysign(0, -1)))},
+		{name: "fmax.d with nan", insn: fp(0x05, 1, 1, 3, 1, 2), f1: canonicalNaN64, f2: f64(-1), expectF: ptr(f64(-1))},
+		{name: "fsgnjn.d", insn: fp(0x04, 1, 1, 3, 1, 2), f1: f64(2), f2: f64(2), expectF: ptr(f64(-2))},
+		{name: "flt.d", insn: fp(0x14, 1, 1, 5, 1, 2), f1: f64(1), f2: f64(2), expectX: ptr(uint64(1))},
+		{name: "feq.d nan", insn: fp(0x14, 1, 2, 5, 1, 1), f1: canonicalNaN64, expectX: ptr(uint64(0))},
+		{name: "fcvt.l.d rtz", insn: fp(0x18, 1, RoundTowardZero, 5, 1, 2), f1: f64(-2.7), expectX: ptr(uint64(0xffff_ffff_ffff_fffe))},
+		{name: "fcvt.l.d rdn", insn: fp(0x18, 1, RoundDown, 5, 1, 2), f1: f64(-2.2), expectX: ptr(uint64(0xffff_ffff_ffff_fffd))},
+		{name: "fcvt.w.d rne", insn: fp(0x18, 1, RoundNearestEven, 5, 1, 0), f1: f64(2.5), expectX: ptr(uint64(2))},
+		{name: "fcvt.w.d nan saturates", insn: fp(0x18, 1, RoundTowardZero, 5, 1, 0), f1: canonicalNaN64, expectX: ptr(uint64(math.MaxInt32))},
+		{name: "fcvt.wu.d negative saturates", insn: fp(0x18, 1, RoundTowardZero, 5, 1, 1), f1: f64(-5), expectX: ptr(uint64(0))},
+		{name: "fcvt.lu.d overflow saturates", insn: fp(0x18, 1, RoundTowardZero, 5, 1, 3), f1: f64(1e30), expectX: ptr(uint64(math.MaxUint64))},
+		{name: "fcvt.d.l", insn: fp(0x1a, 1, 7, 3, 6, 2), x1: 0xffff_ffff_ffff_fffd, expectF: ptr(f64(-3))},
+		{name: "fcvt.s.d", insn: fp(0x08, 0, 7, 3, 1, 1), f1: f64(0.5), expectF: ptr(boxSingle(math.Float32bits(0.5)))},
+		{name: "fmv.x.w", insn: fp(0x1c, 0, 0, 5, 1, 0), f1: boxSingle(0x8000_0000), expectX: ptr(uint64(0xffff_ffff_8000_0000))},
+		{name: "fmv.d.x", insn: fp(0x1e, 1, 0, 3, 6, 0), x1: f64(4), expectF: ptr(f64(4))},
+		{name: "fclass.d", insn: fp(0x1c, 1, 1, 5, 1, 0), f1: f64(math.Inf(-1)), expectX: ptr(uint64(1))},
+		{name: "fmadd.d", insn: encR(OpMadd, 3, 0, 1, 2, 1|2<<2), f1: f64(2), f2: f64(3), expectF: ptr(f64(9))}, // rs3 = f2
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			vm, state, _ := newTestVM(t, insns(c.insn)...)
+			thread := state.GetCurrentThread()
+			thread.FRegisters[1] = c.f1
+			thread.FRegisters[2] = c.f2
+			thread.Registers[6] = Word(c.x1)
+			_, err := vm.Step(false)
+			require.NoError(t, err)
+			if c.expectF != nil {
+				require.Equalf(t, *c.expectF, thread.FRegisters[3], "expected %x, got %x", *c.expectF, thread.FRegisters[3])
+			}
+			if c.expectX != nil {
+				require.Equal(t, Word(*c.expectX), thread.Registers[5])
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestInstrumentedState_FloatLoadStoreBoxing(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(
+		encI(OpLoadFP, 1, 2, 6, 0),  // flw f1, 0(x6)
+		encS(OpStoreFP, 3, 6, 1, 8), // fsd f1, 8(x6)
+	)...)
+	thread := state.GetCurrentThread()
+	thread.Registers[6] = testData
+	state.Memory.SetMemory(testData, 0x0000_803f_0000_0000) // 1.0f little-endian
+
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, boxSingle(math.Float32bits(1)), thread.FRegisters[1])
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(0x0000_803f_ffff_ffff), state.Memory.GetMemory(testData+8))
+}
+
+func TestInstrumentedState_Csr(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(
+		encI(OpSystem, 0, 5, 0, CsrFrm),  // csrrwi x0, frm, 0
+		encI(OpSystem, 5, 2, 0, CsrTime), // csrrs x5, time, x0
+		0xc0001073,                       // unimp: csrrw x0, cycle, x0
+	)...)
+	state.GetCurrentThread().FCSR = 0x3 << 5
+	_, err := vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), state.GetCurrentThread().FCSR)
+
+	_, err = vm.Step(false)
+	require.NoError(t, err)
+	require.Equal(t, Word(2), state.GetCurrentThread().Registers[5])
+
+	_, err = vm.Step(false)
+	require.ErrorContains(t, err, "invalid instruction")
+}
+
+// newSyscallVM creates a VM that makes the syscall num with the given arguments in its first two steps.
+func newSyscallVM(t *testing.T, num uint32, args ...Word) (*InstrumentedState, *State, *bytes.Buffer) {
+	vm, state, stdOut := newTestVM(t, insns(addi(RegA7, 0, int32(num)), InsnEcall, InsnEcall)...)
+	copy(state.GetCurrentThread().Registers[RegA0:], args)
+	return vm, state, stdOut
+}
+
+func runSteps(t *testing.T, vm *InstrumentedState, n int) {
+	for i := 0; i < n; i++ {
+		_, err := vm.Step(false)
+		require.NoError(t, err)
+	}
+}
+
+func TestInstrumentedState_SysWrite(t *testing.T) {
+	vm, state, stdOut := newSyscallVM(t, SysWrite, exec.FdStdout, testData, 5)
+	require.NoError(t, state.Memory.SetMemoryRange(testData, bytes.NewReader([]byte("hello"))))
+	runSteps(t, vm, 2)
+	require.Equal(t, "hello", stdOut.String())
+	require.Equal(t, Word(5), state.GetCurrentThread().Registers[RegA0])
+	require.Equal(t, Word(testPC+8), state.GetPC())
+}
+
+func TestInstrumentedState_SysErrorsAreNegated(t *testing.T) {
+	vm, state, _ := newSyscallVM(t, SysRead, 42, testData, 5)
+	runSteps(t, vm, 2)
+	errno := Word(EBADF)
+	require.Equal(t, -errno, state.GetCurrentThread().Registers[RegA0])
+}
+
+func TestInstrumentedState_SysExitGroup(t *testing.T) {
+	vm, state, _ := newSyscallVM(t, SysExitGroup, 3)
+	runSteps(t, vm, 2)
+	require.True(t, state.Exited)
+	require.Equal(t, uint8(3), state.ExitCode)
+
+	// exited states don't step any further
+	step := state.Step
+	runSteps(t, vm, 1)
+	require.Equal(t, step, state.Step)
+}
+
+func TestInstrumentedState_SysClockGetTime(t *testing.T) {
+	vm, state, _ := newSyscallVM(t, SysClockGetTime, exec.ClockGettimeMonotonicFlag, testData)
+	state.Step = 2*exec.HZ + 10
+	runSteps(t, vm, 2)
+	require.Equal(t, Word(0), state.GetCurrentThread().Registers[RegA0])
+	nsecs := uint64(12) * (1_000_000_000 / exec.HZ)
+	require.Equal(t, Word(0x0200_0000_0000_0000), state.Memory.GetMemory(testData))
+	require.Equal(t, Word(binary.BigEndian.Uint64(binary.LittleEndian.AppendUint64(nil, nsecs))), state.Memory.GetMemory(testData+8))
+}
+
+func TestInstrumentedState_CloneAndFutex(t *testing.T) {
+	vm, state, _ := newSyscallVM(t, SysClone, exec.ValidCloneFlags, 0x100)
+	runSteps(t, vm, 2)
+	require.Len(t, state.Threads, 2)
+	parent, child := state.Threads[0], state.Threads[1]
+	require.Equal(t, Word(1), state.CurrentThread, "switches to the new thread")
+	require.Equal(t, Word(1), parent.Registers[RegA0])
+	require.Equal(t, Word(0), child.Registers[RegA0])
+	require.Equal(t, Word(0x100), child.Registers[RegSP])
+	require.Equal(t, Word(testPC+8), child.PC)
+	require.Equal(t, parent.PC, child.PC)
+
+	// the child blocks on a futex, and is preempted in favour of the parent while the value is unchanged
+	child.Registers[RegA7] = SysFutex
+	copy(child.Registers[RegA0:], []Word{testData, exec.FutexWaitPrivate, 0, 0})
+	runSteps(t, vm, 1)
+	require.Equal(t, Word(testData), child.FutexAddr)
+	require.Equal(t, Word(testPC+8), child.PC)
+	runSteps(t, vm, 1)
+	require.Equal(t, Word(0), state.CurrentThread)
+
+	// changing the futex value wakes the child when it is next scheduled
+	state.Memory.SetMemory(testData, 0x0100_0000_0000_0000)
+	state.StepsSinceLastContextSwitch = exec.SchedQuantum
+	runSteps(t, vm, 2)
+	require.Equal(t, Word(1), state.CurrentThread)
+	require.Equal(t, exec.FutexEmptyAddr, child.FutexAddr)
+	require.Equal(t, Word(0), child.Registers[RegA0])
+	require.Equal(t, Word(testPC+12), child.PC)
+}
+
+func TestInstrumentedState_StepProof(t *testing.T) {
+	vm, state, _ := newTestVM(t, insns(encS(OpStore, 3, 6, 7, 4))...)
+	state.GetCurrentThread().Registers[6] = testData
+	preState, preHash := state.EncodeWitness()
+
+	wit, err := vm.Step(true)
+	require.NoError(t, err)
+	require.Equal(t, preState, []byte(wit.State))
+	require.Equal(t, preHash, wit.StateHash)
+	require.Len(t, wit.ProofData, SERIALIZED_THREAD_SIZE+4*memory.MEM_PROOF_SIZE)
+	require.Equal(t, state.Memory.MerkleProof(testData+8), [memory.MEM_PROOF_SIZE]byte(wit.ProofData[SERIALIZED_THREAD_SIZE+3*memory.MEM_PROOF_SIZE:]))
+}
@@ -0,0 +1,91 @@
+package riscv
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+)
+
+// memoryTracker records proofs for the memory words accessed during a step.
+// A step may access at most two words, which must be contiguous when the step spans a word boundary.
+type memoryTracker struct {
+	*exec.MemoryTrackerImpl
+	accessed [2]Word
+	count    int
+}
+
+func newMemoryTracker(mem *memory.Memory) *memoryTracker {
+	return &memoryTracker{MemoryTrackerImpl: exec.NewMemoryTracker(mem)}
+}
+
+func (t *memoryTracker) Reset(enableProof bool) {
+	t.MemoryTrackerImpl.Reset(enableProof)
+	t.count = 0
+}
+
+func (t *memoryTracker) TrackMemAccess(addr Word) {
+	for i := 0; i < t.count; i++ {
+		if t.accessed[i] == addr {
+			return
+		}
+	}
+	switch t.count {
+	case 0:
+		t.MemoryTrackerImpl.TrackMemAccess(addr)
+	case 1:
+		t.MemoryTrackerImpl.TrackMemAccess2(addr)
+	default:
+		panic(fmt.Errorf("unexpected third memory access at %x in a single step", addr))
+	}
+	t.accessed[t.count] = addr
+	t.count++
+}
+
+// readParcel reads the 16-bit little-endian instruction parcel at the 2-byte aligned addr.
+func readParcel(mem *memory.Memory, addr Word) uint32 {
+	word := mem.GetMemory(addr & arch.AddressMask)
+	shift := (arch.WordSizeBytes - 2 - addr&arch.ExtMask) * 8
+	return uint32(bits.ReverseBytes16(uint16(word >> shift)))
+}
+
+// loadLE reads size bytes at addr as a little-endian value. The access may span two memory words.
+func (m *InstrumentedState) loadLE(addr uint64, size uint64) uint64 {
+	var buf [2 * arch.WordSizeBytes]byte
+	wordAddr := Word(addr) & arch.AddressMask
+	offset := addr & arch.ExtMask
+	m.memoryTracker.TrackMemAccess(wordAddr)
+	arch.ByteOrderWord.PutWord(buf[:arch.WordSizeBytes], m.state.Memory.GetMemory(wordAddr))
+	if offset+size > arch.WordSizeBytes {
+		m.memoryTracker.TrackMemAccess(wordAddr + arch.WordSizeBytes)
+		arch.ByteOrderWord.PutWord(buf[arch.WordSizeBytes:], m.state.Memory.GetMemory(wordAddr+arch.WordSizeBytes))
+	}
+	var v uint64
+	for i := size; i > 0; i-- {
+		v = v<<8 | uint64(buf[offset+i-1])
+	}
+	return v
+}
+
+// storeLE writes the low size bytes of v to addr in little-endian order. The access may span two memory words.
+func (m *InstrumentedState) storeLE(addr uint64, size uint64, v uint64) {
+	var buf [2 * arch.WordSizeBytes]byte
+	wordAddr := Word(addr) & arch.AddressMask
+	offset := addr & arch.ExtMask
+	spans := offset+size > arch.WordSizeBytes
+	m.memoryTracker.TrackMemAccess(wordAddr)
+	arch.ByteOrderWord.PutWord(buf[:arch.WordSizeBytes], m.state.Memory.GetMemory(wordAddr))
+	if spans {
+		m.memoryTracker.TrackMemAccess(wordAddr + arch.WordSizeBytes)
+		arch.ByteOrderWord.PutWord(buf[arch.WordSizeBytes:], m.state.Memory.GetMemory(wordAddr+arch.WordSizeBytes))
+	}
+	for i := uint64(0); i < size; i++ {
+		buf[offset+i] = byte(v >> (8 * i))
+	}
+	m.state.Memory.SetMemory(wordAddr, arch.ByteOrderWord.Word(buf[:arch.WordSizeBytes]))
+	if spans {
+		m.state.Memory.SetMemory(wordAddr+arch.WordSizeBytes, arch.ByteOrderWord.Word(buf[arch.WordSizeBytes:]))
+	}
+}
@@ -0,0 +1,19 @@
+package riscv
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+)
+
+// PatchStack sets up the program's initial stack frame and stack pointer.
+// The layout matches program.PatchStack, but words are stored little-endian and the stack pointer is x2.
+func PatchStack(st mipsevm.FPVMState) error {
+	return PatchStackAuxv(st, nil)
+}
+
+// PatchStackAuxv is PatchStack with additional auxiliary vector entries, such as those from program.ProgramHeaderAuxv.
+func PatchStackAuxv(st mipsevm.FPVMState, auxv []program.AuxEntry) error {
+	return program.PatchStackWith(st, binary.LittleEndian, RegSP, auxv)
+}
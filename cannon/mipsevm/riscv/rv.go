@@ -0,0 +1,328 @@
+package riscv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+)
+
+func (m *InstrumentedState) rvStep() error {
+	if m.state.Exited {
+		return nil
+	}
+	m.state.Step += 1
+	thread := m.state.GetCurrentThread()
+
+	if thread.Exited {
+		m.removeCurrentThread()
+		m.stackTracker.DropThread(thread.ThreadId)
+		return nil
+	}
+
+	// check if thread is blocked on a futex
+	if thread.FutexAddr != exec.FutexEmptyAddr {
+		if m.state.Step > thread.FutexTimeoutStep {
+			// timeout! Allow execution
+			m.onWaitComplete(thread, true)
+		} else if thread.FutexVal == m.loadFutexValue(thread.FutexAddr) {
+			// still got expected value, continue sleeping, try next thread.
+			m.preemptThread()
+		} else {
+			// wake thread up, the value at its address changed!
+			m.onWaitComplete(thread, false)
+		}
+		return nil
+	}
+
+	if m.state.StepsSinceLastContextSwitch >= exec.SchedQuantum {
+		// Force a context switch as this thread has been active too long
+		if m.state.ThreadCount() > 1 && m.log.Enabled(context.Background(), log.LevelTrace) {
+			msg := fmt.Sprintf("Thread has reached maximum execution steps (%v) - preempting.", exec.SchedQuantum)
+			m.log.Trace(msg, "threadId", thread.ThreadId, "threadCount", m.state.ThreadCount(), "pc", thread.PC)
+		}
+		m.preemptThread()
+		return nil
+	}
+	m.state.StepsSinceLastContextSwitch += 1
+
+	return m.execInstruction(thread)
+}
+
+// fetchInstruction reads the instruction at pc, expanding compressed instructions to their 32-bit equivalent.
+// It returns the instruction and its length in bytes.
+func (m *InstrumentedState) fetchInstruction(pc Word) (uint32, uint64, error) {
+	if pc&1 != 0 {
+		return 0, 0, fmt.Errorf("misaligned instruction fetch at %x", pc)
+	}
+	insn := m.state.InstructionAt(pc)
+	if insn&0x3 != 0x3 {
+		expanded, ok := expandCompressed(insn)
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid compressed instruction %04x at pc %x", insn, pc)
+		}
+		return expanded, 2, nil
+	}
+	return insn, 4, nil
+}
+
+func (m *InstrumentedState) handleSyscall(thread *ThreadState) error {
+	regs := &thread.Registers
+	syscallNum := regs[RegA7]
+	a0, a1, a2, a3 := regs[RegA0], regs[RegA1], regs[RegA2], regs[RegA3]
+	var ret Word
+	var errno Word
+
+	switch syscallNum {
+	case SysMmap:
+		var newHeap Word
+		ret, errno, newHeap = exec.HandleSysMmap(a0, a1, m.state.Heap)
+		m.state.Heap = newHeap
+	case SysBrk:
+		ret = program.PROGRAM_BREAK
+	case SysClone:
+		// a0 = flag bitmask, a1 = stack pointer
+		if exec.ValidCloneFlags != a0 {
+			m.state.Exited = true
+			m.state.ExitCode = mipsevm.VMStatusPanic
+			return nil
+		}
+		newThread := &ThreadState{
+			ThreadId:  m.state.NextThreadId,
+			FutexAddr: exec.FutexEmptyAddr,
+			PC:        thread.PC + 4,
+			Registers: thread.Registers,
+		}
+		// the child will perceive a 0 value as returned value instead
+		newThread.Registers[RegSP] = a1
+		newThread.Registers[RegA0] = 0
+		m.state.NextThreadId++
+
+		stackCaller := thread.PC
+		stackTarget := thread.PC + 4
+		completeSyscall(thread, newThread.ThreadId, 0)
+		m.pushThread(newThread)
+		// Note: We need to call stackTracker after pushThread
+		// to ensure we are tracking in the context of the new thread
+		m.stackTracker.PushStack(stackCaller, stackTarget)
+		return nil
+	case SysExitGroup:
+		m.state.Exited = true
+		m.state.ExitCode = uint8(a0)
+		return nil
+	case SysExit:
+		thread.Exited = true
+		thread.ExitCode = uint8(a0)
+		if m.state.ThreadCount() == 1 {
+			m.state.Exited = true
+			m.state.ExitCode = uint8(a0)
+		}
+		return nil
+	case SysRead:
+		var newPreimageOffset Word
+		var memUpdated bool
+		var memAddr Word
+		ret, errno, newPreimageOffset, memUpdated, memAddr = exec.HandleSysRead(a0, a1, a2, m.state.PreimageKey, m.state.PreimageOffset, m.preimageOracle, m.state.Memory, m.memoryTracker)
+		m.state.PreimageOffset = newPreimageOffset
+		if memUpdated {
+			m.handleMemoryUpdate(memAddr, arch.WordSizeBytes)
+		}
+	case SysWrite:
+		var newLastHint hexutil.Bytes
+		var newPreimageKey common.Hash
+		var newPreimageOffset Word
+		ret, errno, newLastHint, newPreimageKey, newPreimageOffset = exec.HandleSysWrite(a0, a1, a2, m.state.LastHint, m.state.PreimageKey, m.state.PreimageOffset, m.preimageOracle, m.state.Memory, m.memoryTracker, m.stdOut, m.stdErr)
+		m.state.LastHint = newLastHint
+		m.state.PreimageKey = newPreimageKey
+		m.state.PreimageOffset = newPreimageOffset
+	case SysFcntl:
+		ret, errno = handleSysFcntl(a0, a1)
+	case SysGetTID, SysSetTidAddress:
+		ret = thread.ThreadId
+	case SysFutex:
+		// args: a0 = addr, a1 = op, a2 = val, a3 = timeout
+		effFutexAddr := a0 & ^Word(0x3)
+		switch a1 {
+		case exec.FutexWaitPrivate:
+			targetVal := Word(uint32(a2))
+			if m.loadFutexValue(effFutexAddr) != targetVal {
+				errno = EAGAIN
+			} else {
+				thread.FutexAddr = effFutexAddr
+				thread.FutexVal = targetVal
+				if a3 == 0 {
+					thread.FutexTimeoutStep = exec.FutexNoTimeout
+				} else {
+					thread.FutexTimeoutStep = m.state.Step + exec.FutexTimeoutSteps
+				}
+				// Leave the pc as-is. This instruction will be completed by `onWaitComplete`
+				return nil
+			}
+		case exec.FutexWakePrivate:
+			// Waiting threads notice the changed futex value when they are next scheduled.
+			// Don't indicate to the program that we've woken up a waiting thread, as there are no guarantees.
+			completeSyscall(thread, 0, 0)
+			m.preemptThread()
+			return nil
+		default:
+			errno = EINVAL
+		}
+	case SysSchedYield, SysNanosleep:
+		completeSyscall(thread, 0, 0)
+		m.preemptThread()
+		return nil
+	case SysOpenAt:
+		errno = ENOENT
+	case SysRiscvHwProbe, SysGetRandom:
+		// report no optional extensions, and no entropy source, so the guest falls back to portable code
+		errno = ENOSYS
+	case SysClockGetTime:
+		switch a0 {
+		case exec.ClockGettimeRealtimeFlag, exec.ClockGettimeMonotonicFlag:
+			var secs, nsecs uint64
+			if a0 == exec.ClockGettimeMonotonicFlag {
+				// monotonic clock_gettime is used by Go guest programs for goroutine scheduling and to implement
+				// `time.Sleep` (and other sleep related operations).
+				secs = m.state.Step / exec.HZ
+				nsecs = (m.state.Step % exec.HZ) * (1_000_000_000 / exec.HZ)
+			} // else realtime set to Unix Epoch
+
+			// the timespec is two little-endian 64-bit words; an aligned timespec spans two memory words
+			if a1&7 != 0 {
+				errno = EINVAL
+				break
+			}
+			m.storeLE(uint64(a1), 8, secs)
+			m.storeLE(uint64(a1)+8, 8, nsecs)
+			m.handleMemoryUpdate(a1, 16)
+		default:
+			errno = EINVAL
+		}
+	case SysGetpid, SysGetppid, SysGetuid, SysGeteuid, SysGetgid, SysGetegid:
+	case SysMunmap:
+	case SysMprotect:
+	case SysMadvise:
+	case SysMinCore:
+	case SysSchedGetAffinity:
+	case SysRtSigprocmask:
+	case SysSigaltstack:
+	case SysRtSigaction:
+	case SysPrlimit64:
+	case SysGetRLimit:
+	case SysPrctl:
+	case SysSetRobustList:
+	case SysClose:
+	case SysPread64:
+	case SysLseek:
+	case SysFstat:
+	case SysFstatAt:
+	case SysReadlinkAt:
+	case SysIoctl:
+	case SysIoGetEvents:
+	case SysEpollCreate1:
+	case SysPipe2:
+	case SysEpollCtl:
+	case SysEpollPwait:
+	case SysUname:
+	case SysKill:
+	case SysTgkill:
+	case SysSetITimer:
+	case SysTimerCreate:
+	case SysTimerSetTime:
+	case SysTimerDelete:
+	default:
+		m.Traceback()
+		panic(fmt.Sprintf("unrecognized syscall: %d", syscallNum))
+	}
+
+	completeSyscall(thread, ret, errno)
+	return nil
+}
+
+// handleSysFcntl extends exec.HandleSysFcntl with F_GETFD, which the Go runtime uses to check the standard fds.
+func handleSysFcntl(fd, cmd Word) (ret, errno Word) {
+	if cmd != 1 { // F_GETFD: get file descriptor flags
+		return exec.HandleSysFcntl(fd, cmd)
+	}
+	switch fd {
+	case exec.FdStdin, exec.FdStdout, exec.FdStderr, exec.FdHintRead, exec.FdHintWrite, exec.FdPreimageRead, exec.FdPreimageWrite:
+		return 0, 0
+	default:
+		return 0, EBADF
+	}
+}
+
+// completeSyscall sets the syscall result and moves past the ecall.
+// Errors are returned to the guest as a negated errno, as per the Linux RISC-V syscall ABI.
+func completeSyscall(thread *ThreadState, ret, errno Word) {
+	if errno != 0 {
+		ret = -errno
+	}
+	thread.Registers[RegA0] = ret
+	thread.PC += 4
+}
+
+// handleMemoryUpdate clears the LR reservation when a write of size bytes at addr overlaps the reserved address.
+func (m *InstrumentedState) handleMemoryUpdate(addr Word, size Word) {
+	if m.state.ReservationActive && m.state.ReservationAddress < addr+size && addr < m.state.ReservationAddress+8 {
+		m.clearReservation()
+	}
+}
+
+func (m *InstrumentedState) clearReservation() {
+	m.state.ReservationActive = false
+	m.state.ReservationAddress = 0
+	m.state.ReservationOwner = 0
+}
+
+// loadFutexValue reads the 32-bit futex value at the 4-byte aligned addr.
+func (m *InstrumentedState) loadFutexValue(addr Word) Word {
+	return Word(m.loadLE(uint64(addr), 4))
+}
+
+func (m *InstrumentedState) onWaitComplete(thread *ThreadState, isTimedOut bool) {
+	// Clear the futex state
+	thread.FutexAddr = exec.FutexEmptyAddr
+	thread.FutexVal = 0
+	thread.FutexTimeoutStep = 0
+
+	// Complete the FUTEX_WAIT syscall
+	var errno Word
+	if isTimedOut {
+		errno = ETIMEDOUT
+	}
+	completeSyscall(thread, 0, errno)
+}
+
+// preemptThread switches to the next thread in round-robin order.
+func (m *InstrumentedState) preemptThread() {
+	m.state.CurrentThread = (m.state.CurrentThread + 1) % Word(m.state.ThreadCount())
+	m.state.StepsSinceLastContextSwitch = 0
+}
+
+// pushThread inserts a new thread after the current one and switches to it.
+func (m *InstrumentedState) pushThread(thread *ThreadState) {
+	i := m.state.CurrentThread + 1
+	m.state.Threads = append(m.state.Threads, nil)
+	copy(m.state.Threads[i+1:], m.state.Threads[i:])
+	m.state.Threads[i] = thread
+	m.state.CurrentThread = i
+	m.state.StepsSinceLastContextSwitch = 0
+}
+
+// removeCurrentThread drops the current thread and switches to the thread that followed it.
+func (m *InstrumentedState) removeCurrentThread() {
+	i := m.state.CurrentThread
+	m.state.Threads = append(m.state.Threads[:i], m.state.Threads[i+1:]...)
+	if i >= Word(m.state.ThreadCount()) {
+		m.state.CurrentThread = 0
+	}
+	m.state.StepsSinceLastContextSwitch = 0
+}
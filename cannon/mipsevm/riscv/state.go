@@ -0,0 +1,412 @@
+// Package riscv implements a 64-bit RISC-V (RV64IMAFD) FPVM that shares cannon's memory, preimage oracle and
+// witness plumbing. Guest threads created with clone are scheduled round-robin, as in the multithreaded MIPS VM.
+// The VM requires 64-bit words so is only supported when cannon is built with the cannon64 tag.
+package riscv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+// STATE_WITNESS_SIZE is the size of the state witness encoding in bytes.
+const STATE_WITNESS_SIZE = 115 + 6*arch.WordSizeBytes
+const (
+	MEMROOT_WITNESS_OFFSET                    = 0
+	PREIMAGE_KEY_WITNESS_OFFSET               = MEMROOT_WITNESS_OFFSET + 32
+	PREIMAGE_OFFSET_WITNESS_OFFSET            = PREIMAGE_KEY_WITNESS_OFFSET + 32
+	HEAP_WITNESS_OFFSET                       = PREIMAGE_OFFSET_WITNESS_OFFSET + arch.WordSizeBytes
+	RESERVATION_ACTIVE_WITNESS_OFFSET         = HEAP_WITNESS_OFFSET + arch.WordSizeBytes
+	RESERVATION_ADDRESS_WITNESS_OFFSET        = RESERVATION_ACTIVE_WITNESS_OFFSET + 1
+	RESERVATION_OWNER_WITNESS_OFFSET          = RESERVATION_ADDRESS_WITNESS_OFFSET + arch.WordSizeBytes
+	EXITCODE_WITNESS_OFFSET                   = RESERVATION_OWNER_WITNESS_OFFSET + arch.WordSizeBytes
+	EXITED_WITNESS_OFFSET                     = EXITCODE_WITNESS_OFFSET + 1
+	STEP_WITNESS_OFFSET                       = EXITED_WITNESS_OFFSET + 1
+	STEPS_SINCE_CONTEXT_SWITCH_WITNESS_OFFSET = STEP_WITNESS_OFFSET + 8
+	CURRENT_THREAD_WITNESS_OFFSET             = STEPS_SINCE_CONTEXT_SWITCH_WITNESS_OFFSET + 8
+	THREADS_ROOT_WITNESS_OFFSET               = CURRENT_THREAD_WITNESS_OFFSET + arch.WordSizeBytes
+	NEXT_THREAD_ID_WITNESS_OFFSET             = THREADS_ROOT_WITNESS_OFFSET + 32
+)
+
+type State struct {
+	Memory *memory.Memory
+
+	PreimageKey    common.Hash
+	PreimageOffset Word // note that the offset includes the 8-byte length prefix
+
+	Heap Word // to handle mmap growth
+
+	ReservationActive  bool // Whether there is an active reservation initiated via a LR (load reserved) op
+	ReservationAddress Word // The address reserved via the LR op
+	ReservationOwner   Word // The id of the thread that holds the reservation
+
+	ExitCode uint8
+	Exited   bool
+
+	Step                        uint64
+	StepsSinceLastContextSwitch uint64
+
+	// Threads are scheduled round-robin, starting from the thread at index CurrentThread
+	Threads       []*ThreadState
+	CurrentThread Word
+	NextThreadId  Word
+
+	// LastHint is optional metadata, and not part of the VM state itself.
+	LastHint hexutil.Bytes
+}
+
+var _ mipsevm.FPVMState = (*State)(nil)
+
+func CreateEmptyState() *State {
+	initThread := CreateEmptyThread()
+	return &State{
+		Memory:       memory.NewMemory(),
+		Threads:      []*ThreadState{initThread},
+		NextThreadId: initThread.ThreadId + 1,
+	}
+}
+
+func CreateInitialState(pc, heapStart Word) *State {
+	state := CreateEmptyState()
+	state.GetCurrentThread().PC = pc
+	state.Heap = heapStart
+	return state
+}
+
+func (s *State) CreateVM(logger log.Logger, po mipsevm.PreimageOracle, stdOut, stdErr io.Writer, meta mipsevm.Metadata) mipsevm.FPVM {
+	logger.Info("Using cannon RISC-V VM")
+	return NewInstrumentedState(s, po, stdOut, stdErr, logger, meta)
+}
+
+func (s *State) GetCurrentThread() *ThreadState {
+	if s.CurrentThread >= Word(len(s.Threads)) {
+		panic(fmt.Sprintf("current thread %d out of range of %d threads", s.CurrentThread, len(s.Threads)))
+	}
+	return s.Threads[s.CurrentThread]
+}
+
+func (s *State) GetPC() Word {
+	return s.GetCurrentThread().PC
+}
+
+// GetCpu returns the program counter of the current thread. RISC-V has no branch delay slots or HI/LO registers
+// so NextPC is always the following instruction.
+func (s *State) GetCpu() mipsevm.CpuScalars {
+	pc := s.GetPC()
+	return mipsevm.CpuScalars{PC: pc, NextPC: pc + 4}
+}
+
+// InstructionAt returns the raw instruction at the 2-byte aligned pc.
+// Compressed instructions are returned in the low 16 bits. A 32-bit instruction may span two memory words.
+func (s *State) InstructionAt(pc Word) uint32 {
+	lo := readParcel(s.Memory, pc)
+	if lo&0x3 != 0x3 {
+		return lo
+	}
+	return lo | readParcel(s.Memory, pc+2)<<16
+}
+
+// PendingSyscall returns the syscall number when the next instruction of the current thread is an ecall.
+func (s *State) PendingSyscall() (Word, bool) {
+	pc := s.GetPC()
+	if pc&1 != 0 || s.InstructionAt(pc) != InsnEcall {
+		return 0, false
+	}
+	return s.GetCurrentThread().Registers[RegA7], true
+}
+
+// OpcodeClass returns the class of the instruction at pc, as attributed in profile reports.
+func (s *State) OpcodeClass(pc Word) string {
+	insn := s.InstructionAt(pc)
+	if insn&0x3 != 0x3 {
+		expanded, ok := expandCompressed(insn)
+		if !ok {
+			return program.OpClassOther
+		}
+		insn = expanded
+	}
+	d := decode(insn)
+	switch d.opcode {
+	case OpLui, OpAuipc, OpImm, OpImm32:
+		return program.OpClassALU
+	case OpOp, OpOp32:
+		if d.funct7 == 0x01 { // M extension
+			return program.OpClassMulDiv
+		}
+		return program.OpClassALU
+	case OpLoad:
+		return program.OpClassLoad
+	case OpStore:
+		return program.OpClassStore
+	case OpAmo:
+		return program.OpClassAtomic
+	case OpBranch:
+		return program.OpClassBranch
+	case OpJal, OpJalr:
+		return program.OpClassJump
+	case OpSystem:
+		if insn == InsnEcall {
+			return program.OpClassSyscall
+		}
+		return program.OpClassOther
+	case OpLoadFP, OpStoreFP, OpMadd, OpMsub, OpNmsub, OpNmadd, OpOpFP:
+		return program.OpClassFloat
+	default:
+		return program.OpClassOther
+	}
+}
+
+// SyscallName returns the name of the rv64 syscall with number num, or the number if it is not a known syscall.
+func (s *State) SyscallName(num Word) string {
+	if name, ok := syscallNames[num]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", num)
+}
+
+func (s *State) GetRegistersRef() *[32]Word {
+	return &s.GetCurrentThread().Registers
+}
+
+func (s *State) GetExitCode() uint8 { return s.ExitCode }
+
+func (s *State) GetExited() bool { return s.Exited }
+
+func (s *State) GetStep() uint64 { return s.Step }
+
+func (s *State) GetLastHint() hexutil.Bytes {
+	return s.LastHint
+}
+
+func (s *State) VMStatus() uint8 {
+	return mipsevm.VmStatus(s.Exited, s.ExitCode)
+}
+
+func (s *State) GetMemory() *memory.Memory {
+	return s.Memory
+}
+
+func (s *State) GetHeap() Word {
+	return s.Heap
+}
+
+func (s *State) GetPreimageKey() common.Hash {
+	return s.PreimageKey
+}
+
+func (s *State) GetPreimageOffset() Word {
+	return s.PreimageOffset
+}
+
+func (s *State) ThreadCount() int {
+	return len(s.Threads)
+}
+
+func (s *State) threadsRoot() common.Hash {
+	root := EmptyThreadsRoot
+	for _, thread := range s.Threads {
+		root = computeThreadRoot(root, thread)
+	}
+	return root
+}
+
+func (s *State) EncodeWitness() ([]byte, common.Hash) {
+	out := make([]byte, 0, STATE_WITNESS_SIZE)
+	memRoot := s.Memory.MerkleRoot()
+	out = append(out, memRoot[:]...)
+	out = append(out, s.PreimageKey[:]...)
+	out = arch.ByteOrderWord.AppendWord(out, s.PreimageOffset)
+	out = arch.ByteOrderWord.AppendWord(out, s.Heap)
+	out = mipsevm.AppendBoolToWitness(out, s.ReservationActive)
+	out = arch.ByteOrderWord.AppendWord(out, s.ReservationAddress)
+	out = arch.ByteOrderWord.AppendWord(out, s.ReservationOwner)
+	out = append(out, s.ExitCode)
+	out = mipsevm.AppendBoolToWitness(out, s.Exited)
+	out = binary.BigEndian.AppendUint64(out, s.Step)
+	out = binary.BigEndian.AppendUint64(out, s.StepsSinceLastContextSwitch)
+	out = arch.ByteOrderWord.AppendWord(out, s.CurrentThread)
+	threadsRoot := s.threadsRoot()
+	out = append(out, threadsRoot[:]...)
+	out = arch.ByteOrderWord.AppendWord(out, s.NextThreadId)
+	return out, stateHashFromWitness(out)
+}
+
+// EncodeThreadProof returns the serialized current thread.
+func (s *State) EncodeThreadProof() []byte {
+	return s.GetCurrentThread().serializeThread()
+}
+
+// Serialize writes the state in a simple binary format which can be read again using Deserialize
+// The format is a simple concatenation of fields, with prefixed item count for repeating items and using big endian
+// encoding for numbers.
+//
+// StateVersion                uint8(3)
+// Memory                      As per Memory.Serialize
+// PreimageKey                 [32]byte
+// PreimageOffset              Word
+// Heap                        Word
+// ReservationActive           uint8 - 0 for false, 1 for true
+// ReservationAddress          Word
+// ReservationOwner            Word
+// ExitCode                    uint8
+// Exited                      uint8 - 0 for false, 1 for true
+// Step                        uint64
+// StepsSinceLastContextSwitch uint64
+// CurrentThread               Word
+// NextThreadId                Word
+// len(Threads)                uint32
+// Threads entries             as per ThreadState.Serialize
+// len(LastHint)               uint32 (0 when LastHint is nil)
+// LastHint                    []byte
+func (s *State) Serialize(out io.Writer) error {
+	bout := serialize.NewBinaryWriter(out)
+
+	if err := s.Memory.Serialize(out); err != nil {
+		return err
+	}
+	if err := bout.WriteHash(s.PreimageKey); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.PreimageOffset); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.Heap); err != nil {
+		return err
+	}
+	if err := bout.WriteBool(s.ReservationActive); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.ReservationAddress); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.ReservationOwner); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.ExitCode); err != nil {
+		return err
+	}
+	if err := bout.WriteBool(s.Exited); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.Step); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.StepsSinceLastContextSwitch); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.CurrentThread); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(s.NextThreadId); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(uint32(len(s.Threads))); err != nil {
+		return err
+	}
+	for _, thread := range s.Threads {
+		if err := thread.Serialize(out); err != nil {
+			return err
+		}
+	}
+	return bout.WriteBytes(s.LastHint)
+}
+
+func (s *State) Deserialize(in io.Reader) error {
+	bin := serialize.NewBinaryReader(in)
+	s.Memory = memory.NewMemory()
+	if err := s.Memory.Deserialize(in); err != nil {
+		return err
+	}
+	if err := bin.ReadHash(&s.PreimageKey); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.PreimageOffset); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.Heap); err != nil {
+		return err
+	}
+	if err := bin.ReadBool(&s.ReservationActive); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.ReservationAddress); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.ReservationOwner); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.ExitCode); err != nil {
+		return err
+	}
+	if err := bin.ReadBool(&s.Exited); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.Step); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.StepsSinceLastContextSwitch); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.CurrentThread); err != nil {
+		return err
+	}
+	if err := bin.ReadUInt(&s.NextThreadId); err != nil {
+		return err
+	}
+	var threadCount uint32
+	if err := bin.ReadUInt(&threadCount); err != nil {
+		return err
+	}
+	if threadCount == 0 {
+		return fmt.Errorf("state has no threads")
+	}
+	s.Threads = make([]*ThreadState, threadCount)
+	for i := range s.Threads {
+		s.Threads[i] = &ThreadState{}
+		if err := s.Threads[i].Deserialize(in); err != nil {
+			return err
+		}
+	}
+	if s.CurrentThread >= Word(threadCount) {
+		return fmt.Errorf("current thread %d out of range of %d threads", s.CurrentThread, threadCount)
+	}
+	return bin.ReadBytes((*[]byte)(&s.LastHint))
+}
+
+type StateWitness []byte
+
+func (sw StateWitness) StateHash() (common.Hash, error) {
+	if len(sw) != STATE_WITNESS_SIZE {
+		return common.Hash{}, fmt.Errorf("Invalid witness length. Got %d, expected %d", len(sw), STATE_WITNESS_SIZE)
+	}
+	return stateHashFromWitness(sw), nil
+}
+
+func GetStateHashFn() mipsevm.HashFn {
+	return func(sw []byte) (common.Hash, error) {
+		return StateWitness(sw).StateHash()
+	}
+}
+
+func stateHashFromWitness(sw []byte) common.Hash {
+	if len(sw) != STATE_WITNESS_SIZE {
+		panic("Invalid witness length")
+	}
+	hash := crypto.Keccak256Hash(sw)
+	exitCode := sw[EXITCODE_WITNESS_OFFSET]
+	exited := sw[EXITED_WITNESS_OFFSET]
+	status := mipsevm.VmStatus(exited == 1, exitCode)
+	hash[0] = status
+	return hash
+}
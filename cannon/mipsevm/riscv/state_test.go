@@ -0,0 +1,135 @@
+//go:build cannon64
+// +build cannon64
+
+package riscv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/program"
+)
+
+func TestState_EncodeWitness(t *testing.T) {
+	state := CreateEmptyState()
+	state.Heap = 0x10_00_00_00_00_00_00_00
+	state.ReservationAddress = 0x7F_FF_FF_FF_D0_00_00_08
+	state.Exited = true
+	state.ExitCode = 1
+
+	witness, hash := state.EncodeWitness()
+	require.Len(t, witness, STATE_WITNESS_SIZE)
+	require.Equal(t, 163, STATE_WITNESS_SIZE)
+	require.Equal(t, uint8(mipsevm.VMStatusInvalid), hash[0])
+	require.Equal(t, hexutil.Bytes{0x10, 0, 0, 0, 0, 0, 0, 0}, hexutil.Bytes(witness[HEAP_WITNESS_OFFSET:HEAP_WITNESS_OFFSET+8]))
+	require.Equal(t, uint8(1), witness[EXITCODE_WITNESS_OFFSET])
+	require.Equal(t, uint8(1), witness[EXITED_WITNESS_OFFSET])
+	require.Len(t, state.EncodeThreadProof(), SERIALIZED_THREAD_SIZE)
+
+	hashFn := GetStateHashFn()
+	actual, err := hashFn(witness)
+	require.NoError(t, err)
+	require.Equal(t, hash, actual)
+}
+
+func TestState_ThreadsRootChangesWithThreads(t *testing.T) {
+	state := CreateEmptyState()
+	_, hash := state.EncodeWitness()
+
+	state.Threads = append(state.Threads, &ThreadState{ThreadId: 1})
+	_, hashWithThread := state.EncodeWitness()
+	require.NotEqual(t, hash, hashWithThread)
+
+	state.Threads[1].FRegisters[3] = 1
+	_, hashWithFloat := state.EncodeWitness()
+	require.NotEqual(t, hashWithThread, hashWithFloat)
+}
+
+func TestSerializeStateRoundTrip(t *testing.T) {
+	mem := memory.NewMemory()
+	mem.AllocPage(5)
+	p := mem.AllocPage(0x7F_FF_FF_FF_D0_00_0)
+	p.Data[2] = 0x01
+	state := &State{
+		Memory:                      mem,
+		PreimageKey:                 common.Hash{0xFF},
+		PreimageOffset:              5,
+		Heap:                        0x10_00_00_00_00_c0_ff_ee,
+		ReservationActive:           true,
+		ReservationAddress:          0x12345678_9abcdef0,
+		ReservationOwner:            0x02,
+		ExitCode:                    1,
+		Exited:                      true,
+		Step:                        0xdeadbeef,
+		StepsSinceLastContextSwitch: 334,
+		Threads: []*ThreadState{
+			{
+				ThreadId:         45,
+				ExitCode:         46,
+				Exited:           true,
+				FutexAddr:        0x7F_FF_FF_FF_D0_00_00_40,
+				FutexVal:         48,
+				FutexTimeoutStep: 49,
+				PC:               0x1_0000_00FE,
+				Registers:        [32]Word{0xdeadbeef_deadbeef, 0xc0ffee, 0xbeefbabe_00000000},
+				FRegisters:       [32]uint64{0x3ff0000000000000, 0xffffffff_3f800000},
+				FCSR:             0xe1,
+			},
+			{
+				ThreadId:  55,
+				FutexAddr: 0xFFFF_FFFF_FFFF_FFFF,
+				PC:        0x8_0000_0000,
+			},
+		},
+		CurrentThread: 1,
+		NextThreadId:  56,
+		LastHint:      []byte{1, 2, 3},
+	}
+
+	ser := new(bytes.Buffer)
+	require.NoError(t, state.Serialize(ser))
+
+	actual := &State{}
+	require.NoError(t, actual.Deserialize(bytes.NewReader(ser.Bytes())))
+	require.Equal(t, state, actual)
+}
+
+func TestState_DeserializeRejectsInvalidCurrentThread(t *testing.T) {
+	state := CreateEmptyState()
+	state.CurrentThread = 1
+	ser := new(bytes.Buffer)
+	require.NoError(t, state.Serialize(ser))
+
+	err := (&State{}).Deserialize(bytes.NewReader(ser.Bytes()))
+	require.ErrorContains(t, err, "out of range")
+}
+
+func TestState_OpcodeClass(t *testing.T) {
+	tests := []struct {
+		insn  []byte
+		class string
+	}{
+		{[]byte{0xb3, 0x02, 0x73, 0x00}, program.OpClassALU},     // add t0, t1, t2
+		{[]byte{0xb3, 0x02, 0x73, 0x02}, program.OpClassMulDiv},  // mul t0, t1, t2
+		{[]byte{0x83, 0x32, 0x03, 0x00}, program.OpClassLoad},    // ld t0, 0(t1)
+		{[]byte{0x23, 0x30, 0x53, 0x00}, program.OpClassStore},   // sd t0, 0(t1)
+		{[]byte{0xaf, 0x32, 0x03, 0x10}, program.OpClassAtomic},  // lr.d t0, (t1)
+		{[]byte{0x63, 0x04, 0x73, 0x00}, program.OpClassBranch},  // beq t1, t2, 8
+		{[]byte{0xef, 0x00, 0x00, 0x00}, program.OpClassJump},    // jal ra, 0
+		{[]byte{0x73, 0x00, 0x00, 0x00}, program.OpClassSyscall}, // ecall
+		{[]byte{0x53, 0x00, 0x00, 0x00}, program.OpClassFloat},   // fadd.s f0, f0, f0
+		{[]byte{0x82, 0x80}, program.OpClassJump},                // c.jr ra
+		{[]byte{0x05, 0x04}, program.OpClassALU},                 // c.addi s0, 1
+	}
+	for _, test := range tests {
+		state := CreateEmptyState()
+		require.NoError(t, state.Memory.SetMemoryRange(0x1000, bytes.NewReader(test.insn)))
+		require.Equalf(t, test.class, state.OpcodeClass(0x1000), "insn %x", test.insn)
+	}
+}
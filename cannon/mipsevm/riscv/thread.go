@@ -0,0 +1,106 @@
+package riscv
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
+)
+
+// SERIALIZED_THREAD_SIZE is the size of a serialized ThreadState object
+const SERIALIZED_THREAD_SIZE = 14 + 36*arch.WordSizeBytes + 32*8
+
+// EmptyThreadsRoot is the root of an empty list of threads
+var EmptyThreadsRoot = common.Hash{}
+
+type ThreadState struct {
+	ThreadId         Word     `json:"threadId"`
+	ExitCode         uint8    `json:"exit"`
+	Exited           bool     `json:"exited"`
+	FutexAddr        Word     `json:"futexAddr"`
+	FutexVal         Word     `json:"futexVal"`
+	FutexTimeoutStep uint64   `json:"futexTimeoutStep"`
+	PC               Word     `json:"pc"`
+	Registers        [32]Word `json:"registers"`
+	// FRegisters are the floating point registers. Single precision values are NaN-boxed.
+	FRegisters [32]uint64 `json:"fregisters"`
+	// FCSR is the floating point control and status register, holding the accrued exceptions and rounding mode
+	FCSR uint32 `json:"fcsr"`
+}
+
+func CreateEmptyThread() *ThreadState {
+	return &ThreadState{
+		ThreadId:  0,
+		FutexAddr: exec.FutexEmptyAddr,
+	}
+}
+
+func (t *ThreadState) serializeThread() []byte {
+	out := make([]byte, 0, SERIALIZED_THREAD_SIZE)
+
+	out = arch.ByteOrderWord.AppendWord(out, t.ThreadId)
+	out = append(out, t.ExitCode)
+	out = mipsevm.AppendBoolToWitness(out, t.Exited)
+	out = arch.ByteOrderWord.AppendWord(out, t.FutexAddr)
+	out = arch.ByteOrderWord.AppendWord(out, t.FutexVal)
+	out = binary.BigEndian.AppendUint64(out, t.FutexTimeoutStep)
+	out = arch.ByteOrderWord.AppendWord(out, t.PC)
+	for _, r := range t.Registers {
+		out = arch.ByteOrderWord.AppendWord(out, r)
+	}
+	for _, r := range t.FRegisters {
+		out = binary.BigEndian.AppendUint64(out, r)
+	}
+	out = binary.BigEndian.AppendUint32(out, t.FCSR)
+	return out
+}
+
+// Serialize writes the ThreadState in a simple binary format which can be read again using Deserialize
+// The format exactly matches the serialization generated by serializeThread used for thread proofs.
+func (t *ThreadState) Serialize(out io.Writer) error {
+	_, err := out.Write(t.serializeThread())
+	return err
+}
+
+func (t *ThreadState) Deserialize(in io.Reader) error {
+	if err := binary.Read(in, binary.BigEndian, &t.ThreadId); err != nil {
+		return err
+	}
+	if err := binary.Read(in, binary.BigEndian, &t.ExitCode); err != nil {
+		return err
+	}
+	var exited uint8
+	if err := binary.Read(in, binary.BigEndian, &exited); err != nil {
+		return err
+	}
+	t.Exited = exited != 0
+	if err := binary.Read(in, binary.BigEndian, &t.FutexAddr); err != nil {
+		return err
+	}
+	if err := binary.Read(in, binary.BigEndian, &t.FutexVal); err != nil {
+		return err
+	}
+	if err := binary.Read(in, binary.BigEndian, &t.FutexTimeoutStep); err != nil {
+		return err
+	}
+	if err := binary.Read(in, binary.BigEndian, &t.PC); err != nil {
+		return err
+	}
+	if err := binary.Read(in, binary.BigEndian, &t.Registers); err != nil {
+		return err
+	}
+	if err := binary.Read(in, binary.BigEndian, &t.FRegisters); err != nil {
+		return err
+	}
+	return binary.Read(in, binary.BigEndian, &t.FCSR)
+}
+
+func computeThreadRoot(prevRoot common.Hash, thread *ThreadState) common.Hash {
+	hashedThread := crypto.Keccak256Hash(thread.serializeThread())
+	return crypto.Keccak256Hash(prevRoot[:], hashedThread[:])
+}
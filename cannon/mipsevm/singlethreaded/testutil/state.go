@@ -4,6 +4,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/singlethreaded"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/testutil"
 )
@@ -19,12 +20,12 @@ func (m *StateMutatorSingleThreaded) Randomize(randSeed int64) {
 	step := r.RandStep()
 
 	m.state.PreimageKey = r.RandHash()
-	m.state.PreimageOffset = r.Uint32()
+	m.state.PreimageOffset = r.Word()
 	m.state.Cpu.PC = pc
 	m.state.Cpu.NextPC = pc + 4
-	m.state.Cpu.HI = r.Uint32()
-	m.state.Cpu.LO = r.Uint32()
-	m.state.Heap = r.Uint32()
+	m.state.Cpu.HI = r.Word()
+	m.state.Cpu.LO = r.Word()
+	m.state.Heap = r.Word()
 	m.state.Step = step
 	m.state.LastHint = r.RandHint()
 	m.state.Registers = *r.RandRegisters()
@@ -36,23 +37,23 @@ func NewStateMutatorSingleThreaded(state *singlethreaded.State) testutil.StateMu
 	return &StateMutatorSingleThreaded{state: state}
 }
 
-func (m *StateMutatorSingleThreaded) SetPC(val uint32) {
+func (m *StateMutatorSingleThreaded) SetPC(val arch.Word) {
 	m.state.Cpu.PC = val
 }
 
-func (m *StateMutatorSingleThreaded) SetNextPC(val uint32) {
+func (m *StateMutatorSingleThreaded) SetNextPC(val arch.Word) {
 	m.state.Cpu.NextPC = val
 }
 
-func (m *StateMutatorSingleThreaded) SetHI(val uint32) {
+func (m *StateMutatorSingleThreaded) SetHI(val arch.Word) {
 	m.state.Cpu.HI = val
 }
 
-func (m *StateMutatorSingleThreaded) SetLO(val uint32) {
+func (m *StateMutatorSingleThreaded) SetLO(val arch.Word) {
 	m.state.Cpu.LO = val
 }
 
-func (m *StateMutatorSingleThreaded) SetHeap(val uint32) {
+func (m *StateMutatorSingleThreaded) SetHeap(val arch.Word) {
 	m.state.Heap = val
 }
 
@@ -72,7 +73,7 @@ func (m *StateMutatorSingleThreaded) SetPreimageKey(val common.Hash) {
 	m.state.PreimageKey = val
 }
 
-func (m *StateMutatorSingleThreaded) SetPreimageOffset(val uint32) {
+func (m *StateMutatorSingleThreaded) SetPreimageOffset(val arch.Word) {
 	m.state.PreimageOffset = val
 }
 
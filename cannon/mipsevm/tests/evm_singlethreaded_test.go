@@ -1,3 +1,6 @@
+//go:build !cannon64
+// +build !cannon64
+
 package tests
 
 import (
@@ -9,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/exec"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/testutil"
@@ -165,7 +169,7 @@ func TestEVM_SysRead_Preimage(t *testing.T) {
 			step := state.GetStep()
 
 			// Set up state
-			state.GetRegistersRef()[2] = exec.SysRead
+			state.GetRegistersRef()[2] = arch.SysRead
 			state.GetRegistersRef()[4] = exec.FdPreimageRead
 			state.GetRegistersRef()[5] = c.addr
 			state.GetRegistersRef()[6] = c.count
@@ -6,6 +6,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 )
 
 type RandHelper struct {
@@ -17,6 +19,13 @@ func NewRandHelper(seed int64) *RandHelper {
 	return &RandHelper{r: r}
 }
 
+func (h *RandHelper) Word() arch.Word {
+	if arch.IsMips32 {
+		return arch.Word(h.r.Uint32())
+	}
+	return arch.Word(h.r.Uint64())
+}
+
 func (h *RandHelper) Uint32() uint32 {
 	return h.r.Uint32()
 }
@@ -57,10 +66,10 @@ func (h *RandHelper) RandHint() []byte {
 	return bytes
 }
 
-func (h *RandHelper) RandRegisters() *[32]uint32 {
-	registers := new([32]uint32)
+func (h *RandHelper) RandRegisters() *[32]arch.Word {
+	registers := new([32]arch.Word)
 	for i := 0; i < 32; i++ {
-		registers[i] = h.r.Uint32()
+		registers[i] = h.Word()
 	}
 	return registers
 }
@@ -73,8 +82,8 @@ func (h *RandHelper) RandomBytes(t require.TestingT, length int) []byte {
 	return randBytes
 }
 
-func (h *RandHelper) RandPC() uint32 {
-	return AlignPC(h.r.Uint32())
+func (h *RandHelper) RandPC() arch.Word {
+	return AlignPC(h.Word())
 }
 
 func (h *RandHelper) RandStep() uint64 {
@@ -0,0 +1,74 @@
+package versions
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+)
+
+// Difference is a part of the VM state that differs between two states.
+type Difference struct {
+	Field string
+	A     string
+	B     string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%v: %v != %v", d.Field, d.A, d.B)
+}
+
+type threadCounter interface {
+	ThreadCount() int
+}
+
+// Diff compares two states and returns their differences, starting with the state metadata, followed by the
+// registers of the current thread and the memory.
+// Only the first differing memory page is reported, with its first differing word and the number of differing pages.
+func Diff(a, b *VersionedState) []Difference {
+	var diffs []Difference
+	check := func(field string, va, vb any) {
+		if va != vb {
+			diffs = append(diffs, Difference{Field: field, A: fmt.Sprint(va), B: fmt.Sprint(vb)})
+		}
+	}
+	hex := func(w arch.Word) string {
+		return fmt.Sprintf("0x%x", w)
+	}
+
+	check("version", a.Version, b.Version)
+	check("step", a.GetStep(), b.GetStep())
+	check("exited", a.GetExited(), b.GetExited())
+	check("exitCode", a.GetExitCode(), b.GetExitCode())
+	ca, cb := a.GetCpu(), b.GetCpu()
+	check("pc", hex(ca.PC), hex(cb.PC))
+	check("nextPC", hex(ca.NextPC), hex(cb.NextPC))
+	check("lo", hex(ca.LO), hex(cb.LO))
+	check("hi", hex(ca.HI), hex(cb.HI))
+	check("heap", hex(a.GetHeap()), hex(b.GetHeap()))
+	check("preimageKey", a.GetPreimageKey(), b.GetPreimageKey())
+	check("preimageOffset", a.GetPreimageOffset(), b.GetPreimageOffset())
+	check("lastHint", a.GetLastHint().String(), b.GetLastHint().String())
+	if ta, ok := a.FPVMState.(threadCounter); ok {
+		if tb, ok := b.FPVMState.(threadCounter); ok {
+			check("threadCount", ta.ThreadCount(), tb.ThreadCount())
+		}
+	}
+
+	ra, rb := a.GetRegistersRef(), b.GetRegistersRef()
+	for i := range ra {
+		check(fmt.Sprintf("registers[%d]", i), hex(ra[i]), hex(rb[i]))
+	}
+
+	ma, mb := a.GetMemory(), b.GetMemory()
+	if pages := memory.DiffPages(ma, mb); len(pages) > 0 {
+		pageAddr := pages[0] << memory.PageAddrSize
+		for addr := pageAddr; addr < pageAddr+memory.PageSize; addr += arch.WordSizeBytes {
+			if wa, wb := ma.GetMemory(addr), mb.GetMemory(addr); wa != wb {
+				check(fmt.Sprintf("memory[%v] (page %v, %d pages differ)", hex(addr), hex(pages[0]), len(pages)), hex(wa), hex(wb))
+				break
+			}
+		}
+	}
+	return diffs
+}
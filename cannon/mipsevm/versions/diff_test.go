@@ -0,0 +1,39 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+)
+
+func TestDiff(t *testing.T) {
+	newState := func() *VersionedState {
+		state, err := NewFromState(multithreaded.CreateInitialState(0x1000, 0x4000_0000))
+		require.NoError(t, err)
+		return state
+	}
+
+	t.Run("Identical", func(t *testing.T) {
+		a, b := newState(), newState()
+		a.GetMemory().SetMemory(0x2000, 5)
+		b.GetMemory().SetMemory(0x2000, 5)
+		require.Empty(t, Diff(a, b))
+	})
+
+	t.Run("Differences", func(t *testing.T) {
+		a, b := newState(), newState()
+		b.FPVMState.(*multithreaded.State).Step = 10
+		b.GetRegistersRef()[4] = 0x42
+		a.GetMemory().SetMemory(0x2008, 1)
+		b.GetMemory().SetMemory(0x2010, 2)
+		b.GetMemory().SetMemory(0x7000, 3)
+
+		require.Equal(t, []Difference{
+			{Field: "step", A: "0", B: "10"},
+			{Field: "registers[4]", A: "0x0", B: "0x42"},
+			{Field: "memory[0x2008] (page 0x2, 2 pages differ)", A: "0x1", B: "0x0"},
+		}, Diff(a, b))
+	})
+}
@@ -0,0 +1,38 @@
+package versions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+var ErrHooksNotSupported = errors.New("acceleration hooks are not supported by the vm")
+
+// accelerationHooks are the acceleration hooks supported by each state version.
+// A hook must be implemented identically by the on-chain VM of a state version, so hooks are only ever added with a
+// new state version. No state version supports acceleration hooks yet.
+var accelerationHooks = map[StateVersion][]mipsevm.AccelerationHook{}
+
+// AccelerationHooks returns the acceleration hooks supported by states of the given version.
+func AccelerationHooks(version StateVersion) []mipsevm.AccelerationHook {
+	return accelerationHooks[version]
+}
+
+// ApplyAccelerationHooks sets the acceleration hooks supported by the state version on the VM.
+func ApplyAccelerationHooks(vm mipsevm.FPVM, version StateVersion) error {
+	hooks := AccelerationHooks(version)
+	if len(hooks) == 0 {
+		return nil
+	}
+	hookable, ok := vm.(mipsevm.HookableFPVM)
+	if !ok {
+		return fmt.Errorf("%w: state version %d", ErrHooksNotSupported, version)
+	}
+	resolved, err := mipsevm.NewHooks(hooks...)
+	if err != nil {
+		return err
+	}
+	hookable.SetHooks(resolved)
+	return nil
+}
@@ -0,0 +1,254 @@
+package versions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/riscv"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/singlethreaded"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// CompactSnapshotSuffix is the file name suffix of compact snapshots.
+	CompactSnapshotSuffix = ".snap.gz"
+	// CompactSnapshotPagesDir is the directory, relative to the compact snapshots, where their memory pages are stored.
+	CompactSnapshotPagesDir = "pages"
+
+	compactSnapshotMagic   = uint32(0x534e4150) // "SNAP"
+	compactSnapshotVersion = uint8(1)
+)
+
+var ErrInvalidCompactSnapshot = errors.New("invalid compact snapshot")
+
+// IsCompactSnapshot returns true if path is a compact snapshot.
+func IsCompactSnapshot(path string) bool {
+	return strings.HasSuffix(path, CompactSnapshotSuffix)
+}
+
+// CompactSnapshotWriter writes compact snapshots.
+// A compact snapshot stores the state separately from its memory. Memory pages are kept in a page store that is shared
+// by all compact snapshots in the same directory, so pages that are unchanged between snapshots are only stored once.
+//
+// A compact snapshot can be loaded with LoadStateFromFile as long as the pages directory next to it is retained.
+type CompactSnapshotWriter struct {
+	stores map[string]*memory.PageStore
+}
+
+func NewCompactSnapshotWriter() *CompactSnapshotWriter {
+	return &CompactSnapshotWriter{stores: make(map[string]*memory.PageStore)}
+}
+
+// Write writes state as a compact snapshot to path.
+//
+// The snapshot is gzip compressed and uses a simple binary format:
+//
+//	magic                   uint32
+//	snapshot version        uint8
+//	state without memory    []byte (uint32 length prefixed VersionedState encoding)
+//	page count              uint64
+//	For each page (ordered by page index):
+//
+//		page index          Word (uint32 for MIPS32, uint64 for MIPS64)
+//		page merkle root    [32]byte
+func (w *CompactSnapshotWriter) Write(path string, state *VersionedState) error {
+	store := w.pageStore(path)
+	refs, err := store.Store(state.GetMemory())
+	if err != nil {
+		return fmt.Errorf("failed to store memory pages: %w", err)
+	}
+	stateOnly, err := withMemory(state, memory.NewMemory())
+	if err != nil {
+		return err
+	}
+	var stateData bytes.Buffer
+	if err := stateOnly.Serialize(&stateData); err != nil {
+		return fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	out, err := ioutil.NewAtomicWriterCompressed(path, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() {
+		_ = out.Abort()
+	}()
+	bout := serialize.NewBinaryWriter(out)
+	if err := bout.WriteUInt(compactSnapshotMagic); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(compactSnapshotVersion); err != nil {
+		return err
+	}
+	if err := bout.WriteBytes(stateData.Bytes()); err != nil {
+		return err
+	}
+	if err := bout.WriteUInt(uint64(len(refs))); err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if err := bout.WriteUInt(ref.Index); err != nil {
+			return err
+		}
+		if err := bout.WriteHash(ref.Hash); err != nil {
+			return err
+		}
+	}
+	return out.Close()
+}
+
+func (w *CompactSnapshotWriter) pageStore(path string) *memory.PageStore {
+	dir := compactSnapshotPagesDir(path)
+	store, ok := w.stores[dir]
+	if !ok {
+		store = memory.NewPageStore(dir)
+		w.stores[dir] = store
+	}
+	return store
+}
+
+// LoadCompactSnapshot reads the compact snapshot at path and restores its memory from the shared page store.
+func LoadCompactSnapshot(path string) (*VersionedState, error) {
+	state, refs, err := readCompactSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	store := memory.NewPageStore(compactSnapshotPagesDir(path))
+	if err := store.Load(state.GetMemory(), refs); err != nil {
+		return nil, fmt.Errorf("failed to load memory pages: %w", err)
+	}
+	return state, nil
+}
+
+// RemoveUnreferencedPages removes the memory pages stored for the compact snapshots in dir that are no longer
+// referenced by any of them, and returns the number of bytes freed.
+// Pages written after keepAfter are retained so that pages written for a snapshot that is still in progress are kept.
+func RemoveUnreferencedPages(dir string, keepAfter time.Time) (uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to list snapshots in %v: %w", dir, err)
+	}
+	refs := make(map[common.Hash]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !IsCompactSnapshot(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		_, snapshotRefs, err := readCompactSnapshot(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			// Keep all pages rather than risk removing the pages of a snapshot that can't be read.
+			return 0, fmt.Errorf("failed to read snapshot %v: %w", path, err)
+		}
+		for _, ref := range snapshotRefs {
+			refs[ref.Hash] = true
+		}
+	}
+	return memory.NewPageStore(filepath.Join(dir, CompactSnapshotPagesDir)).RemoveUnreferenced(refs, keepAfter)
+}
+
+// readCompactSnapshot reads the state without memory and the memory page references from the compact snapshot at path.
+func readCompactSnapshot(path string) (*VersionedState, []memory.PageRef, error) {
+	in, err := ioutil.OpenDecompressed(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file %q: %w", path, err)
+	}
+	defer in.Close()
+	bin := serialize.NewBinaryReader(in)
+	var magic uint32
+	if err := bin.ReadUInt(&magic); err != nil {
+		return nil, nil, err
+	}
+	if magic != compactSnapshotMagic {
+		return nil, nil, fmt.Errorf("%w: incorrect magic %x", ErrInvalidCompactSnapshot, magic)
+	}
+	var version uint8
+	if err := bin.ReadUInt(&version); err != nil {
+		return nil, nil, err
+	}
+	if version != compactSnapshotVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCompactSnapshot, version)
+	}
+	var stateData []byte
+	if err := bin.ReadBytes(&stateData); err != nil {
+		return nil, nil, err
+	}
+	state := &VersionedState{}
+	if err := state.Deserialize(bytes.NewReader(stateData)); err != nil {
+		return nil, nil, err
+	}
+	var pageCount uint64
+	if err := bin.ReadUInt(&pageCount); err != nil {
+		return nil, nil, err
+	}
+	var refs []memory.PageRef
+	for i := uint64(0); i < pageCount; i++ {
+		var ref memory.PageRef
+		if err := bin.ReadUInt(&ref.Index); err != nil {
+			return nil, nil, err
+		}
+		if err := bin.ReadHash(&ref.Hash); err != nil {
+			return nil, nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return state, refs, nil
+}
+
+func compactSnapshotPagesDir(path string) string {
+	return filepath.Join(filepath.Dir(path), CompactSnapshotPagesDir)
+}
+
+// Snapshot returns a copy of the state that is not affected by further execution of the state.
+// Page data is shared copy-on-write with the state (see memory.Memory.Snapshot). Taking a snapshot still copies the
+// page table and merkle nodes, so its cost grows with the number of allocated pages, but page contents are only copied
+// when the state modifies them afterwards.
+// The returned state may be written out concurrently with further execution of the state.
+func (s *VersionedState) Snapshot() (*VersionedState, error) {
+	stateOnly, err := withMemory(s, memory.NewMemory())
+	if err != nil {
+		return nil, err
+	}
+	// Round trip through the serialized form to deep copy the rest of the state, which is small.
+	var data bytes.Buffer
+	if err := stateOnly.Serialize(&data); err != nil {
+		return nil, fmt.Errorf("failed to serialize state: %w", err)
+	}
+	cp := &VersionedState{}
+	if err := cp.Deserialize(&data); err != nil {
+		return nil, fmt.Errorf("failed to deserialize state: %w", err)
+	}
+	return withMemory(cp, s.GetMemory().Snapshot())
+}
+
+// withMemory returns a copy of state that shares everything except the memory, which is replaced by mem.
+func withMemory(state *VersionedState, mem *memory.Memory) (*VersionedState, error) {
+	switch s := state.FPVMState.(type) {
+	case *singlethreaded.State:
+		cp := *s
+		cp.Memory = mem
+		return &VersionedState{Version: state.Version, FPVMState: &cp}, nil
+	case *multithreaded.State:
+		cp := *s
+		cp.Memory = mem
+		return &VersionedState{Version: state.Version, FPVMState: &cp}, nil
+	case *riscv.State:
+		cp := *s
+		cp.Memory = mem
+		return &VersionedState{Version: state.Version, FPVMState: &cp}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownVersion, state.FPVMState)
+	}
+}
@@ -0,0 +1,161 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+func TestCompactSnapshot(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		dir := t.TempDir()
+		state := createCompactSnapshotTestState(t)
+		path := filepath.Join(dir, "100"+CompactSnapshotSuffix)
+		require.NoError(t, NewCompactSnapshotWriter().Write(path, state))
+
+		loaded, err := LoadStateFromFile(path)
+		require.NoError(t, err)
+		requireSameState(t, state, loaded)
+	})
+
+	t.Run("RestoreFromAnySnapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		writer := NewCompactSnapshotWriter()
+		state := createCompactSnapshotTestState(t)
+		mtState := state.FPVMState.(*multithreaded.State)
+
+		var expected []*VersionedState
+		var paths []string
+		for i := 0; i < 3; i++ {
+			mtState.Step = uint64(i * 100)
+			mtState.Memory.SetMemory(0x2000, memory.Word(i))
+			path := filepath.Join(dir, fmt.Sprintf("%d%v", mtState.Step, CompactSnapshotSuffix))
+			require.NoError(t, writer.Write(path, state))
+
+			// Keep a copy of the state at this step to compare against later
+			cp, err := withMemory(state, mtState.Memory.Copy())
+			require.NoError(t, err)
+			expected = append(expected, cp)
+			paths = append(paths, path)
+		}
+
+		for i, path := range paths {
+			loaded, err := LoadStateFromFile(path)
+			require.NoError(t, err)
+			requireSameState(t, expected[i], loaded)
+		}
+	})
+
+	t.Run("RemoveUnreferencedPages", func(t *testing.T) {
+		dir := t.TempDir()
+		writer := NewCompactSnapshotWriter()
+		state := createCompactSnapshotTestState(t)
+		mtState := state.FPVMState.(*multithreaded.State)
+		var paths []string
+		for i := 0; i < 3; i++ {
+			mtState.Step = uint64(i * 100)
+			mtState.Memory.SetMemory(0x2000, memory.Word(i))
+			path := filepath.Join(dir, fmt.Sprintf("%d%v", mtState.Step, CompactSnapshotSuffix))
+			require.NoError(t, writer.Write(path, state))
+			paths = append(paths, path)
+		}
+		packs := func() []string {
+			packs, err := filepath.Glob(filepath.Join(dir, CompactSnapshotPagesDir, "*.pack"))
+			require.NoError(t, err)
+			return packs
+		}
+		require.Len(t, packs(), 3)
+
+		// All pages are referenced
+		freed, err := RemoveUnreferencedPages(dir, time.Now())
+		require.NoError(t, err)
+		require.Zero(t, freed)
+		require.Len(t, packs(), 3)
+
+		// The page only used by the removed snapshot is no longer referenced
+		require.NoError(t, os.Remove(paths[1]))
+		// Pages written after keepAfter are retained
+		freed, err = RemoveUnreferencedPages(dir, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, freed)
+		require.Len(t, packs(), 3)
+
+		freed, err = RemoveUnreferencedPages(dir, time.Now())
+		require.NoError(t, err)
+		require.NotZero(t, freed)
+		require.Len(t, packs(), 2)
+		for _, path := range []string{paths[0], paths[2]} {
+			_, err := LoadStateFromFile(path)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("InvalidMagic", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "1"+CompactSnapshotSuffix)
+		require.NoError(t, ioutil.WriteCompressedBytes(path, []byte{1, 2, 3, 4, 5}, os.O_WRONLY|os.O_CREATE, 0o644))
+		_, err := LoadStateFromFile(path)
+		require.ErrorIs(t, err, ErrInvalidCompactSnapshot)
+	})
+
+	t.Run("MissingPages", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "1"+CompactSnapshotSuffix)
+		require.NoError(t, NewCompactSnapshotWriter().Write(path, createCompactSnapshotTestState(t)))
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, CompactSnapshotPagesDir)))
+		_, err := LoadStateFromFile(path)
+		require.ErrorContains(t, err, "not found")
+	})
+}
+
+func TestVersionedStateSnapshot(t *testing.T) {
+	state := createCompactSnapshotTestState(t)
+	mtState := state.FPVMState.(*multithreaded.State)
+	_, expectedHash := state.EncodeWitness()
+
+	snap, err := state.Snapshot()
+	require.NoError(t, err)
+	requireSameState(t, state, snap)
+
+	// Further changes to the state don't affect the snapshot
+	mtState.Step = 500
+	mtState.Memory.SetMemory(0x1000, 0x55)
+	mtState.Memory.SetMemory(0x8000, 0x66)
+	mtState.GetCurrentThread().Registers[5] = 0x5678
+	mtState.LastHint = append(mtState.LastHint[:0], 9)
+
+	_, actualHash := snap.EncodeWitness()
+	require.Equal(t, expectedHash, actualHash)
+	require.Equal(t, 2, snap.GetMemory().PageCount())
+	require.Equal(t, []byte{1, 2, 3}, []byte(snap.GetLastHint()))
+	require.Equal(t, memory.Word(0xaabb), snap.GetMemory().GetMemory(0x1000))
+}
+
+func createCompactSnapshotTestState(t *testing.T) *VersionedState {
+	mtState := multithreaded.CreateEmptyState()
+	mtState.Heap = 0x4000
+	mtState.LastHint = []byte{1, 2, 3}
+	mtState.Memory.SetMemory(0x1000, 0xaabb)
+	mtState.Memory.SetMemory(0x2000, 0xccdd)
+	mtState.GetCurrentThread().Registers[5] = 0x1234
+	state, err := NewFromState(mtState)
+	require.NoError(t, err)
+	return state
+}
+
+func requireSameState(t *testing.T, expected *VersionedState, actual *VersionedState) {
+	require.Equal(t, expected.Version, actual.Version)
+	_, expectedHash := expected.EncodeWitness()
+	_, actualHash := actual.EncodeWitness()
+	require.Equal(t, expectedHash, actualHash)
+	require.Equal(t, expected.GetMemory().PageCount(), actual.GetMemory().PageCount())
+	require.Equal(t, expected.GetLastHint(), actual.GetLastHint())
+}
@@ -7,7 +7,9 @@ import (
 	"io"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/riscv"
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm/singlethreaded"
 	"github.com/ethereum-optimism/optimism/cannon/serialize"
 	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
@@ -18,14 +20,34 @@ type StateVersion uint8
 const (
 	VersionSingleThreaded StateVersion = iota
 	VersionMultiThreaded
+	VersionMultiThreaded64
+	VersionRISCV64
 )
 
 var (
-	ErrUnknownVersion   = errors.New("unknown version")
-	ErrJsonNotSupported = errors.New("json not supported")
+	ErrUnknownVersion      = errors.New("unknown version")
+	ErrJsonNotSupported    = errors.New("json not supported")
+	ErrUnsupportedMipsArch = errors.New("mips architecture is not supported")
 )
 
+// IsSupported returns true if states of the given version can be executed by this build of cannon.
+// MIPS64 and RISC-V states require cannon to be built with the cannon64 build tag, MIPS32 states require it to be
+// built without.
+func IsSupported(version StateVersion) bool {
+	switch version {
+	case VersionSingleThreaded, VersionMultiThreaded:
+		return arch.IsMips32
+	case VersionMultiThreaded64, VersionRISCV64:
+		return !arch.IsMips32
+	default:
+		return false
+	}
+}
+
 func LoadStateFromFile(path string) (*VersionedState, error) {
+	if IsCompactSnapshot(path) {
+		return LoadCompactSnapshot(path)
+	}
 	if !serialize.IsBinaryFile(path) {
 		// Always use singlethreaded for JSON states
 		state, err := jsonutil.LoadJSON[singlethreaded.State](path)
@@ -40,13 +62,28 @@ func LoadStateFromFile(path string) (*VersionedState, error) {
 func NewFromState(state mipsevm.FPVMState) (*VersionedState, error) {
 	switch state := state.(type) {
 	case *singlethreaded.State:
+		if !arch.IsMips32 {
+			return nil, fmt.Errorf("%w: singlethreaded states are only supported on MIPS32", ErrUnsupportedMipsArch)
+		}
 		return &VersionedState{
 			Version:   VersionSingleThreaded,
 			FPVMState: state,
 		}, nil
 	case *multithreaded.State:
+		version := VersionMultiThreaded
+		if !arch.IsMips32 {
+			version = VersionMultiThreaded64
+		}
 		return &VersionedState{
-			Version:   VersionMultiThreaded,
+			Version:   version,
+			FPVMState: state,
+		}, nil
+	case *riscv.State:
+		if arch.IsMips32 {
+			return nil, fmt.Errorf("%w: RISC-V states require 64-bit words", ErrUnsupportedMipsArch)
+		}
+		return &VersionedState{
+			Version:   VersionRISCV64,
 			FPVMState: state,
 		}, nil
 	default:
@@ -74,6 +111,9 @@ func (s *VersionedState) Deserialize(in io.Reader) error {
 	if err := bin.ReadUInt(&s.Version); err != nil {
 		return err
 	}
+	if s.Version <= VersionRISCV64 && !IsSupported(s.Version) {
+		return fmt.Errorf("%w: state version %d", ErrUnsupportedMipsArch, s.Version)
+	}
 
 	switch s.Version {
 	case VersionSingleThreaded:
@@ -83,13 +123,20 @@ func (s *VersionedState) Deserialize(in io.Reader) error {
 		}
 		s.FPVMState = state
 		return nil
-	case VersionMultiThreaded:
+	case VersionMultiThreaded, VersionMultiThreaded64:
 		state := &multithreaded.State{}
 		if err := state.Deserialize(in); err != nil {
 			return err
 		}
 		s.FPVMState = state
 		return nil
+	case VersionRISCV64:
+		state := &riscv.State{}
+		if err := state.Deserialize(in); err != nil {
+			return err
+		}
+		s.FPVMState = state
+		return nil
 	default:
 		return fmt.Errorf("%w: %d", ErrUnknownVersion, s.Version)
 	}
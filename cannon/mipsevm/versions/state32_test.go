@@ -0,0 +1,75 @@
+//go:build !cannon64
+// +build !cannon64
+
+package versions
+
+import (
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/riscv"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/singlethreaded"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromState(t *testing.T) {
+	t.Run("singlethreaded", func(t *testing.T) {
+		actual, err := NewFromState(singlethreaded.CreateEmptyState())
+		require.NoError(t, err)
+		require.IsType(t, &singlethreaded.State{}, actual.FPVMState)
+		require.Equal(t, VersionSingleThreaded, actual.Version)
+	})
+
+	t.Run("multithreaded", func(t *testing.T) {
+		actual, err := NewFromState(multithreaded.CreateEmptyState())
+		require.NoError(t, err)
+		require.IsType(t, &multithreaded.State{}, actual.FPVMState)
+		require.Equal(t, VersionMultiThreaded, actual.Version)
+	})
+
+	t.Run("riscv", func(t *testing.T) {
+		_, err := NewFromState(riscv.CreateEmptyState())
+		require.ErrorIs(t, err, ErrUnsupportedMipsArch)
+	})
+}
+
+func TestLoadStateFromFile(t *testing.T) {
+	t.Run("SinglethreadedFromJSON", func(t *testing.T) {
+		expected, err := NewFromState(singlethreaded.CreateEmptyState())
+		require.NoError(t, err)
+
+		path := writeToFile(t, "state.json", expected)
+		actual, err := LoadStateFromFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("SinglethreadedFromBinary", func(t *testing.T) {
+		expected, err := NewFromState(singlethreaded.CreateEmptyState())
+		require.NoError(t, err)
+
+		path := writeToFile(t, "state.bin.gz", expected)
+		actual, err := LoadStateFromFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("MultithreadedFromBinary", func(t *testing.T) {
+		expected, err := NewFromState(multithreaded.CreateEmptyState())
+		require.NoError(t, err)
+
+		path := writeToFile(t, "state.bin.gz", expected)
+		actual, err := LoadStateFromFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestLoadStateFromFile_RejectsMips64States(t *testing.T) {
+	for _, version := range []StateVersion{VersionMultiThreaded64, VersionRISCV64} {
+		state := &VersionedState{Version: version, FPVMState: multithreaded.CreateEmptyState()}
+		path := writeToFile(t, "state.bin.gz", state)
+		_, err := LoadStateFromFile(path)
+		require.ErrorIs(t, err, ErrUnsupportedMipsArch)
+	}
+}
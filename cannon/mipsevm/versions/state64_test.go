@@ -0,0 +1,65 @@
+//go:build cannon64
+// +build cannon64
+
+package versions
+
+import (
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/riscv"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/singlethreaded"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromState64(t *testing.T) {
+	t.Run("singlethreaded", func(t *testing.T) {
+		_, err := NewFromState(singlethreaded.CreateEmptyState())
+		require.ErrorIs(t, err, ErrUnsupportedMipsArch)
+	})
+
+	t.Run("multithreaded", func(t *testing.T) {
+		actual, err := NewFromState(multithreaded.CreateEmptyState())
+		require.NoError(t, err)
+		require.IsType(t, &multithreaded.State{}, actual.FPVMState)
+		require.Equal(t, VersionMultiThreaded64, actual.Version)
+	})
+
+	t.Run("riscv", func(t *testing.T) {
+		actual, err := NewFromState(riscv.CreateEmptyState())
+		require.NoError(t, err)
+		require.IsType(t, &riscv.State{}, actual.FPVMState)
+		require.Equal(t, VersionRISCV64, actual.Version)
+	})
+}
+
+func TestLoadStateFromFile64(t *testing.T) {
+	t.Run("MultithreadedFromBinary", func(t *testing.T) {
+		expected, err := NewFromState(multithreaded.CreateEmptyState())
+		require.NoError(t, err)
+
+		path := writeToFile(t, "state.bin.gz", expected)
+		actual, err := LoadStateFromFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("RISCVFromBinary", func(t *testing.T) {
+		expected, err := NewFromState(riscv.CreateInitialState(0x1000, 0x2000))
+		require.NoError(t, err)
+
+		path := writeToFile(t, "state.bin.gz", expected)
+		actual, err := LoadStateFromFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("RejectsMips32States", func(t *testing.T) {
+		for _, version := range []StateVersion{VersionSingleThreaded, VersionMultiThreaded} {
+			state := &VersionedState{Version: version, FPVMState: multithreaded.CreateEmptyState()}
+			path := writeToFile(t, "state.bin.gz", state)
+			_, err := LoadStateFromFile(path)
+			require.ErrorIs(t, err, ErrUnsupportedMipsArch)
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package mipsevm
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/arch"
+)
 
 type LocalContext common.Hash
 
@@ -13,7 +17,7 @@ type StepWitness struct {
 
 	PreimageKey    [32]byte // zeroed when no pre-image is accessed
 	PreimageValue  []byte   // including the 8-byte length prefix
-	PreimageOffset uint32
+	PreimageOffset arch.Word
 }
 
 func (wit *StepWitness) HasPreimage() bool {
@@ -74,6 +74,11 @@ type RollupClient interface {
 	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
 }
 
+// ConductorClient reports whether the op-conductor paired with the sequencer is the cluster leader.
+type ConductorClient interface {
+	Leader(ctx context.Context) (bool, error)
+}
+
 // DriverSetup is the collection of input/output interfaces and configuration that the driver operates on.
 type DriverSetup struct {
 	Log              log.Logger
@@ -85,6 +90,8 @@ type DriverSetup struct {
 	EndpointProvider dial.L2EndpointProvider
 	ChannelConfig    ChannelConfigProvider
 	AltDA            *altda.DAClient
+	// Conductor, if set, gates batch submission on the leadership of the paired sequencer.
+	Conductor ConductorClient
 }
 
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
@@ -110,6 +117,9 @@ type BatchSubmitter struct {
 	lastStoredBlock eth.BlockID
 	lastL1Tip       eth.L1BlockRef
 
+	// conductorLeader is the last observed leadership of the paired sequencer. Only used by the loop.
+	conductorLeader bool
+
 	state *channelManager
 }
 
@@ -136,6 +146,7 @@ func (l *BatchSubmitter) StartBatchSubmitting() error {
 	l.killCtx, l.cancelKillCtx = context.WithCancel(context.Background())
 	l.clearState(l.shutdownCtx)
 	l.lastStoredBlock = eth.BlockID{}
+	l.conductorLeader = false
 
 	if l.Config.WaitNodeSync {
 		err := l.waitNodeSync()
@@ -387,6 +398,9 @@ func (l *BatchSubmitter) loop() {
 	for {
 		select {
 		case <-ticker.C:
+			if !l.checkLeadership(l.shutdownCtx) {
+				continue
+			}
 			if !l.checkTxpool(queue, receiptsCh) {
 				continue
 			}
@@ -411,6 +425,10 @@ func (l *BatchSubmitter) loop() {
 				l.Log.Info("Txmgr is closed, remaining channel data won't be sent")
 				return
 			}
+			if l.Conductor != nil && !l.conductorLeader {
+				l.Log.Info("Paired sequencer is not the leader, remaining channel data won't be sent")
+				return
+			}
 			// This removes any never-submitted pending channels, so these do not have to be drained with transactions.
 			// Any remaining unfinished channel is terminated, so its data gets submitted.
 			err := l.state.Close()
@@ -428,6 +446,36 @@ func (l *BatchSubmitter) loop() {
 	}
 }
 
+// checkLeadership reports whether batches may be submitted: either no conductor is configured,
+// or the conductor paired with the sequencer is the cluster leader. Errors are treated as not
+// being the leader, so that two batchers never submit at the same time during a failover.
+// When leadership is gained, the local state is cleared, so that batching continues from the
+// safe head that the previous leader's batcher has left behind.
+func (l *BatchSubmitter) checkLeadership(ctx context.Context) bool {
+	if l.Conductor == nil {
+		return true
+	}
+	cCtx, cancel := context.WithTimeout(ctx, l.Config.NetworkTimeout)
+	defer cancel()
+	leader, err := l.Conductor.Leader(cCtx)
+	if err != nil {
+		l.Log.Warn("Failed to query conductor leadership, not submitting batches", "err", err)
+		leader = false
+	}
+	if leader == l.conductorLeader {
+		return leader
+	}
+	l.conductorLeader = leader
+	if leader {
+		l.Log.Info("Paired sequencer became leader, resuming batch submission")
+		l.clearState(ctx)
+		l.lastStoredBlock = eth.BlockID{}
+	} else {
+		l.Log.Warn("Paired sequencer is no longer leader, pausing batch submission")
+	}
+	return leader
+}
+
 // waitNodeSync Check to see if there was a batcher tx sent recently that
 // still needs more block confirmations before being considered finalized
 func (l *BatchSubmitter) waitNodeSync() error {
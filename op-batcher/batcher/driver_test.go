@@ -117,3 +117,46 @@ func TestBatchSubmitter_SafeL1Origin_FailsToResolveRollupClient(t *testing.T) {
 	_, err := bs.safeL1Origin(context.Background())
 	require.Error(t, err)
 }
+
+type mockConductor struct {
+	leader bool
+	err    error
+}
+
+func (c *mockConductor) Leader(context.Context) (bool, error) {
+	return c.leader, c.err
+}
+
+func TestBatchSubmitter_CheckLeadership(t *testing.T) {
+	bs, ep := setup(t)
+	require.True(t, bs.checkLeadership(context.Background()), "no conductor configured")
+
+	conductor := &mockConductor{}
+	bs.Conductor = conductor
+	require.False(t, bs.checkLeadership(context.Background()))
+
+	// gaining leadership resets the state to continue from the safe head
+	bs.lastStoredBlock = eth.BlockID{Number: 10}
+	ep.rollupClient.ExpectSyncStatus(&eth.SyncStatus{
+		SafeL2: eth.L2BlockRef{L1Origin: eth.BlockID{Number: 999}},
+	}, nil)
+	conductor.leader = true
+	require.True(t, bs.checkLeadership(context.Background()))
+	require.Equal(t, eth.BlockID{}, bs.lastStoredBlock)
+	require.Equal(t, uint64(999), bs.state.l1OriginLastClosedChannel.Number)
+	ep.rollupClient.AssertExpectations(t)
+
+	// remaining leader does not reset the state again
+	bs.lastStoredBlock = eth.BlockID{Number: 12}
+	require.True(t, bs.checkLeadership(context.Background()))
+	require.Equal(t, uint64(12), bs.lastStoredBlock.Number)
+
+	// errors are treated as not being the leader
+	conductor.err = errors.New("conductor unavailable")
+	require.False(t, bs.checkLeadership(context.Background()))
+	require.False(t, bs.conductorLeader)
+
+	conductor.err = nil
+	conductor.leader = false
+	require.False(t, bs.checkLeadership(context.Background()))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/flags"
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
 	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	conductorrpc "github.com/ethereum-optimism/optimism/op-conductor/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/params"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
@@ -55,6 +56,8 @@ type BatcherService struct {
 	EndpointProvider dial.L2EndpointProvider
 	TxManager        txmgr.TxManager
 	AltDA            *altda.DAClient
+	// Conductor is the client of the op-conductor paired with the sequencer, nil if not configured.
+	Conductor *conductorrpc.APIClient
 
 	BatcherConfig
 
@@ -152,6 +155,14 @@ func (bs *BatcherService) initRPCClients(ctx context.Context, cfg *CLIConfig) er
 	}
 	bs.EndpointProvider = endpointProvider
 
+	if cfg.ConductorRpc != "" {
+		conductorClient, err := dial.DialRPCClientWithTimeout(ctx, dial.DefaultDialTimeout, bs.Log, cfg.ConductorRpc)
+		if err != nil {
+			return fmt.Errorf("failed to dial conductor RPC: %w", err)
+		}
+		bs.Conductor = conductorrpc.NewAPIClient(conductorClient)
+	}
+
 	return nil
 }
 
@@ -316,6 +327,10 @@ func (bs *BatcherService) initMetricsServer(cfg *CLIConfig) error {
 }
 
 func (bs *BatcherService) initDriver() {
+	var conductor ConductorClient
+	if bs.Conductor != nil {
+		conductor = bs.Conductor
+	}
 	bs.driver = NewBatchSubmitter(DriverSetup{
 		Log:              bs.Log,
 		Metr:             bs.Metrics,
@@ -326,6 +341,7 @@ func (bs *BatcherService) initDriver() {
 		EndpointProvider: bs.EndpointProvider,
 		ChannelConfig:    bs.ChannelConfig,
 		AltDA:            bs.AltDA,
+		Conductor:        conductor,
 	})
 }
 
@@ -434,6 +450,9 @@ func (bs *BatcherService) Stop(ctx context.Context) error {
 	if bs.EndpointProvider != nil {
 		bs.EndpointProvider.Close()
 	}
+	if bs.Conductor != nil {
+		bs.Conductor.Close()
+	}
 
 	if result == nil {
 		bs.stopped.Store(true)
@@ -156,6 +156,12 @@ var (
 		Value:   false,
 		EnvVars: prefixEnvVars("WAIT_NODE_SYNC"),
 	}
+	ConductorRpcFlag = &cli.StringFlag{
+		Name: "conductor-rpc",
+		Usage: "HTTP provider URL for the op-conductor paired with the sequencer. If set, the batcher only loads and " +
+			"submits batches while that conductor is the cluster leader, to avoid conflicting batches during failovers.",
+		EnvVars: prefixEnvVars("CONDUCTOR_RPC"),
+	}
 	// Legacy Flags
 	SequencerHDPathFlag = txmgr.SequencerHDPathFlag
 )
@@ -184,6 +190,7 @@ var optionalFlags = []cli.Flag{
 	DataAvailabilityTypeFlag,
 	ActiveSequencerCheckDurationFlag,
 	CompressionAlgoFlag,
+	ConductorRpcFlag,
 }
 
 func init() {
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-chain-ops/cmd/check-interop/migrate"
+	"github.com/ethereum-optimism/optimism/op-chain-ops/foundry"
+	op_service "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum-optimism/optimism/op-wheel/cheat"
+)
+
+var (
+	prefix              = "CHECK_INTEROP"
+	ReferenceAllocsFlag = &cli.PathFlag{
+		Name:     "reference-allocs",
+		Usage:    "L2 allocs generated with interop enabled, to take the expected interop predeploys state from",
+		EnvVars:  op_service.PrefixEnvVar(prefix, "REFERENCE_ALLOCS"),
+		Required: true,
+	}
+	InteropTimeFlag = &cli.Uint64Flag{
+		Name:    "interop-time",
+		Usage:   "Timestamp to activate interop at in the chain config. The chain config is not checked if unset.",
+		EnvVars: op_service.PrefixEnvVar(prefix, "INTEROP_TIME"),
+	}
+	DataDirFlag = &cli.PathFlag{
+		Name:     "datadir",
+		Usage:    "Geth data directory of the chain",
+		EnvVars:  op_service.PrefixEnvVar(prefix, "DATADIR"),
+		Required: true,
+	}
+	ApplyFlag = &cli.BoolFlag{
+		Name: "apply",
+		Usage: "Apply the changes to the head state of the database, instead of a dry-run. The head block is patched, " +
+			"so the chain diverges from any node that did not apply the same changes.",
+		EnvVars: op_service.PrefixEnvVar(prefix, "APPLY"),
+	}
+	GenesisFlag = &cli.PathFlag{
+		Name:     "genesis",
+		Usage:    "L2 genesis of the chain",
+		EnvVars:  op_service.PrefixEnvVar(prefix, "GENESIS"),
+		Required: true,
+	}
+	OutFlag = &cli.PathFlag{
+		Name:    "out",
+		Usage:   "Path to write the migrated L2 genesis to, instead of a dry-run",
+		EnvVars: op_service.PrefixEnvVar(prefix, "OUT"),
+	}
+)
+
+func interopTime(c *cli.Context) *uint64 {
+	if !c.IsSet(InteropTimeFlag.Name) {
+		return nil
+	}
+	t := c.Uint64(InteropTimeFlag.Name)
+	return &t
+}
+
+func printChanges(w io.Writer, changes []migrate.Change) {
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(w, "interop state is up to date")
+		return
+	}
+	for _, change := range changes {
+		_, _ = fmt.Fprintln(w, change)
+	}
+}
+
+func checkDataDir(c *cli.Context) error {
+	ref, err := foundry.LoadForgeAllocs(c.Path(ReferenceAllocsFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to load reference allocs: %w", err)
+	}
+	apply := c.Bool(ApplyFlag.Name)
+	ch, err := cheat.OpenGethDB(c.Path(DataDirFlag.Name), !apply)
+	if err != nil {
+		return fmt.Errorf("failed to open geth db: %w", err)
+	}
+	genesisHash := rawdb.ReadCanonicalHash(ch.DB, 0)
+	cfg := rawdb.ReadChainConfig(ch.DB, genesisHash)
+	if cfg == nil {
+		_ = ch.Close()
+		return errors.New("chain config not found")
+	}
+	changes := migrate.ConfigDiff(cfg, interopTime(c))
+	return ch.RunAndClose(func(_ *types.Header, headState *state.StateDB) error {
+		stateChanges, err := migrate.Diff(headState, ref.Accounts)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, stateChanges...)
+		printChanges(c.App.Writer, changes)
+		if !apply || len(changes) == 0 {
+			// leave the head block untouched
+			ch.ReadOnly = true
+			return nil
+		}
+		if t := interopTime(c); t != nil {
+			cfg.InteropTime = t
+			rawdb.WriteChainConfig(ch.DB, genesisHash, cfg)
+		}
+		return migrate.Apply(headState, ref.Accounts)
+	})
+}
+
+func checkGenesis(c *cli.Context) error {
+	ref, err := foundry.LoadForgeAllocs(c.Path(ReferenceAllocsFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to load reference allocs: %w", err)
+	}
+	genesis, err := jsonutil.LoadJSON[core.Genesis](c.Path(GenesisFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to load genesis: %w", err)
+	}
+	if genesis.Config == nil {
+		return errors.New("genesis has no chain config")
+	}
+	changes := migrate.ConfigDiff(genesis.Config, interopTime(c))
+	stateChanges, err := migrate.Diff(migrate.AllocState(genesis.Alloc), ref.Accounts)
+	if err != nil {
+		return err
+	}
+	changes = append(changes, stateChanges...)
+	printChanges(c.App.Writer, changes)
+
+	out := c.Path(OutFlag.Name)
+	if out == "" {
+		return nil
+	}
+	if t := interopTime(c); t != nil {
+		genesis.Config.InteropTime = t
+	}
+	if genesis.Alloc == nil {
+		genesis.Alloc = make(types.GenesisAlloc)
+	}
+	if err := migrate.Apply(migrate.AllocState(genesis.Alloc), ref.Accounts); err != nil {
+		return err
+	}
+	return jsonutil.WriteJSON(genesis, ioutil.ToAtomicFile(out, 0o644))
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "check-interop"
+	app.Usage = "Check and migrate the L2 state of an existing chain for the interop fork."
+	app.Description = "Compares the interop predeploys, their implementations and the chain config against allocs " +
+		"generated with interop enabled, and prints the differences. Changes are only applied with --apply or --out."
+	app.Action = func(c *cli.Context) error {
+		return errors.New("see sub-commands")
+	}
+	app.Writer = os.Stdout
+	app.ErrWriter = os.Stderr
+	app.Commands = []*cli.Command{
+		{
+			Name:   "datadir",
+			Usage:  "Check and migrate the head state of a geth data directory",
+			Flags:  []cli.Flag{ReferenceAllocsFlag, InteropTimeFlag, DataDirFlag, ApplyFlag},
+			Action: checkDataDir,
+		},
+		{
+			Name:   "genesis",
+			Usage:  "Check and migrate an L2 genesis, to regenerate a chain with interop",
+			Flags:  []cli.Flag{ReferenceAllocsFlag, InteropTimeFlag, GenesisFlag, OutFlag},
+			Action: checkGenesis,
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Application failed: %v\n", err)
+		os.Exit(1)
+	}
+}
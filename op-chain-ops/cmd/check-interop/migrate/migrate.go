@@ -0,0 +1,229 @@
+// Package migrate checks and migrates the L2 state of an existing chain for the interop fork.
+// The expected state is taken from reference allocs, generated with interop enabled.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+
+	"github.com/ethereum-optimism/optimism/op-service/predeploys"
+)
+
+// Predeploys are the L2 predeploys introduced with the interop fork.
+var Predeploys = map[common.Address]string{
+	predeploys.CrossL2InboxAddr:               "CrossL2Inbox",
+	predeploys.L2toL2CrossDomainMessengerAddr: "L2toL2CrossDomainMessenger",
+	predeploys.SuperchainWETHAddr:             "SuperchainWETH",
+	predeploys.ETHLiquidityAddr:               "ETHLiquidity",
+}
+
+// implementationSlot is the EIP-1967 storage slot of the implementation of a proxy.
+var implementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// State is the state of a chain, as read from a database or from genesis allocs.
+type State interface {
+	GetCode(addr common.Address) []byte
+	GetBalance(addr common.Address) *uint256.Int
+	GetNonce(addr common.Address) uint64
+	GetState(addr common.Address, key common.Hash) common.Hash
+}
+
+// MutableState is a State that can be migrated.
+type MutableState interface {
+	State
+	SetCode(addr common.Address, code []byte)
+	SetBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason)
+	SetNonce(addr common.Address, nonce uint64)
+	SetState(addr common.Address, key, value common.Hash)
+}
+
+// Change is a difference between the state of a chain and the expected interop state.
+type Change struct {
+	Address common.Address
+	Name    string
+	Field   string
+	From    string
+	To      string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s (%s) %s: %s -> %s", c.Address, c.Name, c.Field, c.From, c.To)
+}
+
+// Accounts returns the interop predeploys and their implementations, by address, as named in the diff.
+// The implementations are found through the EIP-1967 implementation slot of the proxies in the reference.
+func Accounts(ref types.GenesisAlloc) (map[common.Address]string, error) {
+	out := make(map[common.Address]string)
+	for addr, name := range Predeploys {
+		acc, ok := ref[addr]
+		if !ok || len(acc.Code) == 0 {
+			return nil, fmt.Errorf("predeploy %s (%s) is missing from the reference allocs", addr, name)
+		}
+		out[addr] = name
+		if impl, ok := acc.Storage[implementationSlot]; ok {
+			implAddr := common.BytesToAddress(impl.Bytes())
+			if _, ok := ref[implAddr]; !ok {
+				return nil, fmt.Errorf("implementation %s of predeploy %s is missing from the reference allocs", implAddr, name)
+			}
+			out[implAddr] = name + " implementation"
+		}
+	}
+	return out, nil
+}
+
+// Diff returns the changes needed for the state to match the reference, for the interop accounts.
+// Only the storage slots set in the reference are compared.
+func Diff(st State, ref types.GenesisAlloc) ([]Change, error) {
+	accounts, err := Accounts(ref)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, addr := range sortedAddresses(accounts) {
+		name := accounts[addr]
+		want := ref[addr]
+		change := func(field, from, to string) {
+			changes = append(changes, Change{Address: addr, Name: name, Field: field, From: from, To: to})
+		}
+		if code := st.GetCode(addr); !bytes.Equal(code, want.Code) {
+			change("code", codeString(code), codeString(want.Code))
+		}
+		if balance, wantBalance := st.GetBalance(addr), balanceOf(want); balance.Cmp(wantBalance) != 0 {
+			change("balance", balance.String(), wantBalance.String())
+		}
+		if nonce := st.GetNonce(addr); nonce != want.Nonce {
+			change("nonce", fmt.Sprint(nonce), fmt.Sprint(want.Nonce))
+		}
+		for _, key := range sortedHashes(want.Storage) {
+			if value := st.GetState(addr, key); value != want.Storage[key] {
+				change("storage "+key.Hex(), value.Hex(), want.Storage[key].Hex())
+			}
+		}
+	}
+	return changes, nil
+}
+
+// Apply migrates the interop accounts of the state to match the reference.
+func Apply(st MutableState, ref types.GenesisAlloc) error {
+	accounts, err := Accounts(ref)
+	if err != nil {
+		return err
+	}
+	for _, addr := range sortedAddresses(accounts) {
+		want := ref[addr]
+		st.SetCode(addr, want.Code)
+		st.SetBalance(addr, balanceOf(want), tracing.BalanceChangeUnspecified)
+		st.SetNonce(addr, want.Nonce)
+		for _, key := range sortedHashes(want.Storage) {
+			st.SetState(addr, key, want.Storage[key])
+		}
+	}
+	return nil
+}
+
+// ConfigDiff returns the change needed for the chain config to activate interop at the given time.
+func ConfigDiff(cfg *params.ChainConfig, interopTime *uint64) []Change {
+	if interopTime == nil || (cfg.InteropTime != nil && *cfg.InteropTime == *interopTime) {
+		return nil
+	}
+	from := "unset"
+	if cfg.InteropTime != nil {
+		from = fmt.Sprint(*cfg.InteropTime)
+	}
+	return []Change{{Name: "chain config", Field: "interopTime", From: from, To: fmt.Sprint(*interopTime)}}
+}
+
+// AllocState is a State backed by genesis allocs.
+type AllocState types.GenesisAlloc
+
+var _ MutableState = AllocState(nil)
+
+func (a AllocState) GetCode(addr common.Address) []byte {
+	return a[addr].Code
+}
+
+func (a AllocState) GetBalance(addr common.Address) *uint256.Int {
+	return balanceOf(a[addr])
+}
+
+func (a AllocState) GetNonce(addr common.Address) uint64 {
+	return a[addr].Nonce
+}
+
+func (a AllocState) GetState(addr common.Address, key common.Hash) common.Hash {
+	return a[addr].Storage[key]
+}
+
+func (a AllocState) SetCode(addr common.Address, code []byte) {
+	acc := a[addr]
+	acc.Code = bytes.Clone(code)
+	a[addr] = acc
+}
+
+func (a AllocState) SetBalance(addr common.Address, amount *uint256.Int, _ tracing.BalanceChangeReason) {
+	acc := a[addr]
+	acc.Balance = amount.ToBig()
+	a[addr] = acc
+}
+
+func (a AllocState) SetNonce(addr common.Address, nonce uint64) {
+	acc := a[addr]
+	acc.Nonce = nonce
+	a[addr] = acc
+}
+
+func (a AllocState) SetState(addr common.Address, key, value common.Hash) {
+	acc := a[addr]
+	storage := maps.Clone(acc.Storage)
+	if storage == nil {
+		storage = make(map[common.Hash]common.Hash)
+	}
+	if value == (common.Hash{}) {
+		delete(storage, key)
+	} else {
+		storage[key] = value
+	}
+	acc.Storage = storage
+	a[addr] = acc
+}
+
+func balanceOf(acc types.Account) *uint256.Int {
+	if acc.Balance == nil {
+		return new(uint256.Int)
+	}
+	return uint256.MustFromBig(acc.Balance)
+}
+
+func codeString(code []byte) string {
+	if len(code) == 0 {
+		return "empty"
+	}
+	return fmt.Sprintf("%d bytes %s", len(code), hexutil.Encode(code[:min(len(code), 8)]))
+}
+
+func sortedAddresses[V any](m map[common.Address]V) []common.Address {
+	out := make([]common.Address, 0, len(m))
+	for addr := range m {
+		out = append(out, addr)
+	}
+	slices.SortFunc(out, func(a, b common.Address) int { return a.Cmp(b) })
+	return out
+}
+
+func sortedHashes(m map[common.Hash]common.Hash) []common.Hash {
+	out := make([]common.Hash, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
+	slices.SortFunc(out, func(a, b common.Hash) int { return a.Cmp(b) })
+	return out
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func referenceAllocs() types.GenesisAlloc {
+	ref := make(types.GenesisAlloc)
+	for addr := range Predeploys {
+		impl := common.BytesToAddress(append([]byte{0xc0, 0xde}, addr[2:]...))
+		ref[addr] = types.Account{
+			Code:    []byte{0x60, 0x80},
+			Storage: map[common.Hash]common.Hash{implementationSlot: common.BytesToHash(impl.Bytes())},
+		}
+		ref[impl] = types.Account{Code: []byte{0x60, 0x80, 0x60, 0x40}, Balance: big.NewInt(0)}
+	}
+	return ref
+}
+
+func TestDiffAndApply(t *testing.T) {
+	ref := referenceAllocs()
+	st := make(AllocState)
+
+	changes, err := Diff(st, ref)
+	require.NoError(t, err)
+	// every proxy lacks code and implementation slot, every implementation lacks code
+	require.Len(t, changes, 3*len(Predeploys))
+
+	require.NoError(t, Apply(st, ref))
+	changes, err = Diff(st, ref)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestAccountsMissingPredeploy(t *testing.T) {
+	ref := referenceAllocs()
+	for addr := range Predeploys {
+		delete(ref, addr)
+		break
+	}
+	_, err := Accounts(ref)
+	require.ErrorContains(t, err, "missing from the reference allocs")
+}
+
+func TestConfigDiff(t *testing.T) {
+	cfg := &params.ChainConfig{}
+	require.Empty(t, ConfigDiff(cfg, nil))
+
+	interopTime := uint64(100)
+	changes := ConfigDiff(cfg, &interopTime)
+	require.Len(t, changes, 1)
+	require.Equal(t, "unset", changes[0].From)
+	require.Equal(t, "100", changes[0].To)
+
+	cfg.InteropTime = &interopTime
+	require.Empty(t, ConfigDiff(cfg, &interopTime))
+}
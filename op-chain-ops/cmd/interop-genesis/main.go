@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-chain-ops/devkeys"
+	"github.com/ethereum-optimism/optimism/op-chain-ops/foundry"
+	"github.com/ethereum-optimism/optimism/op-chain-ops/interopgen"
+	op_service "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+)
+
+var (
+	prefix        = "INTEROP_GENESIS"
+	L1ChainIDFlag = &cli.Uint64Flag{
+		Name:    "l1-chain-id",
+		Usage:   "Chain ID of the L1",
+		Value:   900100,
+		EnvVars: op_service.PrefixEnvVar(prefix, "L1_CHAIN_ID"),
+	}
+	L2ChainIDsFlag = &cli.Uint64SliceFlag{
+		Name:     "l2-chain-ids",
+		Usage:    "Chain IDs of the interoperable L2s, comma-separated",
+		Required: true,
+		EnvVars:  op_service.PrefixEnvVar(prefix, "L2_CHAIN_IDS"),
+	}
+	GenesisTimestampFlag = &cli.Uint64Flag{
+		Name:    "genesis-timestamp",
+		Usage:   "Genesis timestamp of the L1 and the L2s. Defaults to the current time.",
+		EnvVars: op_service.PrefixEnvVar(prefix, "GENESIS_TIMESTAMP"),
+	}
+	MnemonicFlag = &cli.StringFlag{
+		Name:    "mnemonic",
+		Usage:   "Mnemonic to derive the operator and user keys of the chains from",
+		Value:   devkeys.TestMnemonic,
+		EnvVars: op_service.PrefixEnvVar(prefix, "MNEMONIC"),
+	}
+	ContractsDirFlag = &cli.PathFlag{
+		Name:     "contracts-dir",
+		Usage:    "Path to packages/contracts-bedrock, with the contracts built by forge",
+		Required: true,
+		EnvVars:  op_service.PrefixEnvVar(prefix, "CONTRACTS_DIR"),
+	}
+	OutDirFlag = &cli.PathFlag{
+		Name:     "outdir",
+		Usage:    "Directory to write the genesis files, rollup configs and supervisor config to",
+		Required: true,
+		EnvVars:  op_service.PrefixEnvVar(prefix, "OUTDIR"),
+	}
+)
+
+func generate(c *cli.Context) error {
+	logger := oplog.NewLogger(c.App.ErrWriter, oplog.ReadCLIConfig(c))
+
+	genesisTimestamp := uint64(time.Now().Unix())
+	if c.IsSet(GenesisTimestampFlag.Name) {
+		genesisTimestamp = c.Uint64(GenesisTimestampFlag.Name)
+	}
+	recipe := &interopgen.InteropDevRecipe{
+		L1ChainID:        c.Uint64(L1ChainIDFlag.Name),
+		L2ChainIDs:       c.Uint64Slice(L2ChainIDsFlag.Name),
+		GenesisTimestamp: genesisTimestamp,
+	}
+	seen := make(map[uint64]struct{})
+	for _, id := range recipe.L2ChainIDs {
+		if id == recipe.L1ChainID {
+			return fmt.Errorf("L2 chain ID %d is the same as the L1 chain ID", id)
+		}
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("duplicate L2 chain ID %d", id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	keys, err := devkeys.NewMnemonicDevKeys(c.String(MnemonicFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to derive keys from mnemonic: %w", err)
+	}
+	worldCfg, err := recipe.Build(keys)
+	if err != nil {
+		return fmt.Errorf("failed to build world config: %w", err)
+	}
+	if err := worldCfg.Check(logger); err != nil {
+		return fmt.Errorf("invalid world config: %w", err)
+	}
+
+	contractsDir := c.Path(ContractsDirFlag.Name)
+	artifacts := foundry.OpenArtifactsDir(filepath.Join(contractsDir, "forge-artifacts"))
+	sourceMap := foundry.NewSourceMapFS(os.DirFS(contractsDir))
+	deployments, out, err := interopgen.Deploy(logger, artifacts, sourceMap, worldCfg)
+	if err != nil {
+		return fmt.Errorf("failed to deploy world: %w", err)
+	}
+	supervisorRegistry, err := out.SupervisorRegistry("interop")
+	if err != nil {
+		return fmt.Errorf("failed to build supervisor registry: %w", err)
+	}
+
+	outDir := c.Path(OutDirFlag.Name)
+	if err := writeJSON(filepath.Join(outDir, "l1", "genesis.json"), out.L1.Genesis); err != nil {
+		return err
+	}
+	for id, l2 := range out.L2s {
+		if err := writeJSON(filepath.Join(outDir, id, "genesis.json"), l2.Genesis); err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(outDir, id, "rollup.json"), l2.RollupCfg); err != nil {
+			return err
+		}
+	}
+	if err := writeJSON(filepath.Join(outDir, "supervisor-registry.json"), supervisorRegistry); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(outDir, "deployments.json"), deployments); err != nil {
+		return err
+	}
+	logger.Info("Generated interop cluster", "l1", recipe.L1ChainID, "l2s", recipe.L2ChainIDs, "outdir", outDir)
+	return nil
+}
+
+func writeJSON(path string, value any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory of %s: %w", path, err)
+	}
+	if err := jsonutil.WriteJSON(value, ioutil.ToAtomicFile(path, 0o644)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "interop-genesis"
+	app.Usage = "Generate the genesis of a cluster of interoperable chains."
+	app.Description = "Deploys a shared L1 and N interoperable L2s, and writes the L1 genesis, the genesis and rollup config " +
+		"of each L2, and the op-supervisor config with the dependency set of the L2s. The chain IDs, L1 and L2 genesis " +
+		"anchors and interop activation times are verified to be consistent across all outputs."
+	app.Flags = append([]cli.Flag{
+		L1ChainIDFlag,
+		L2ChainIDsFlag,
+		GenesisTimestampFlag,
+		MnemonicFlag,
+		ContractsDirFlag,
+		OutDirFlag,
+	}, oplog.CLIFlags(prefix)...)
+	app.Action = generate
+	app.Writer = os.Stdout
+	app.ErrWriter = os.Stderr
+
+	oplog.SetGlobalLogHandler(log.NewTerminalHandler(os.Stderr, true))
+
+	err := app.Run(os.Args)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Application failed: %v\n", err)
+		os.Exit(1)
+	}
+}
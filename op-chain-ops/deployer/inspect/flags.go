@@ -11,7 +11,8 @@ import (
 )
 
 const (
-	OutfileFlagName = "outfile"
+	OutfileFlagName       = "outfile"
+	DependencySetFlagName = "dependency-set"
 )
 
 var (
@@ -27,6 +28,14 @@ var Flags = []cli.Flag{
 	FlagOutfile,
 }
 
+var SupervisorFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:  DependencySetFlagName,
+		Usage: "name of the dependency set in the output registry, to select with the op-supervisor dependency-set flag",
+		Value: "interop",
+	},
+}, Flags...)
+
 var Commands = []*cli.Command{
 	{
 		Name:      "genesis",
@@ -44,6 +53,12 @@ var Commands = []*cli.Command{
 		Action:    RollupCLI,
 		Flags:     Flags,
 	},
+	{
+		Name:   "supervisor",
+		Usage:  "outputs the registry bundle that op-supervisor loads the interop chain set of the deployment from",
+		Action: SupervisorCLI,
+		Flags:  SupervisorFlags,
+	},
 }
 
 type cliConfig struct {
@@ -0,0 +1,82 @@
+package inspect
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-chain-ops/deployer"
+	"github.com/ethereum-optimism/optimism/op-chain-ops/deployer/pipeline"
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/urfave/cli/v2"
+)
+
+func SupervisorCLI(cliCtx *cli.Context) error {
+	workdir := cliCtx.String(deployer.WorkdirFlagName)
+	if workdir == "" {
+		return fmt.Errorf("workdir flag is required")
+	}
+	outfile := cliCtx.String(OutfileFlagName)
+	if outfile == "" {
+		return fmt.Errorf("outfile flag is required")
+	}
+
+	env := &pipeline.Env{Workdir: workdir}
+	globalState, err := env.ReadState()
+	if err != nil {
+		return fmt.Errorf("failed to read intent: %w", err)
+	}
+
+	var rollupConfigs []*rollup.Config
+	for _, chain := range globalState.Chains {
+		_, rollupConfig, err := GenesisAndRollup(globalState, chain.ID)
+		if err != nil {
+			return fmt.Errorf("failed to generate rollup config of chain %s: %w", chain.ID, err)
+		}
+		rollupConfigs = append(rollupConfigs, rollupConfig)
+	}
+
+	bundle, err := SupervisorRegistryFromRollups(cliCtx.String(DependencySetFlagName), rollupConfigs)
+	if err != nil {
+		return fmt.Errorf("failed to generate supervisor registry: %w", err)
+	}
+
+	if err := jsonutil.WriteJSON(bundle, ioutil.ToStdOutOrFileOrNoop(outfile, 0o666)); err != nil {
+		return fmt.Errorf("failed to write supervisor registry: %w", err)
+	}
+
+	return nil
+}
+
+// SupervisorRegistryFromRollups builds the registry bundle that op-supervisor loads the chains from,
+// with a dependency set of the given name that contains all chains. Chains are keyed by decimal chain ID,
+// and the chain index in the dependency set follows the order of the chains. All chains must schedule interop.
+func SupervisorRegistryFromRollups(depSetName string, rollupConfigs []*rollup.Config) (*chaincfg.RegistryBundle, error) {
+	if depSetName == "" {
+		return nil, fmt.Errorf("dependency set name is required")
+	}
+	depSet := &chaincfg.DependencySet{Dependencies: make(map[string]chaincfg.Dependency)}
+	bundle := &chaincfg.RegistryBundle{
+		Chains:         make(map[string]*rollup.Config),
+		DependencySets: map[string]*chaincfg.DependencySet{depSetName: depSet},
+	}
+	for i, rollupConfig := range rollupConfigs {
+		chainID := rollupConfig.L2ChainID.String()
+		if _, ok := bundle.Chains[chainID]; ok {
+			return nil, fmt.Errorf("duplicate chain %s", chainID)
+		}
+		if rollupConfig.InteropTime == nil {
+			return nil, fmt.Errorf("chain %s does not schedule interop", chainID)
+		}
+		bundle.Chains[chainID] = rollupConfig
+		depSet.Dependencies[chainID] = chaincfg.Dependency{
+			ChainIndex:     uint32(i),
+			ActivationTime: *rollupConfig.InteropTime,
+		}
+	}
+	if err := bundle.Check(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
@@ -0,0 +1,55 @@
+package inspect
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupervisorConfigFromRollups(t *testing.T) {
+	interopTime := uint64(1000)
+	rollupConfig := func(chainID int64) *rollup.Config {
+		return &rollup.Config{
+			L2ChainID:   big.NewInt(chainID),
+			InteropTime: &interopTime,
+			Genesis: rollup.Genesis{
+				L1:     eth.BlockID{Number: 10},
+				L2:     eth.BlockID{Number: 0},
+				L2Time: 900,
+			},
+		}
+	}
+
+	bundle, err := SupervisorRegistryFromRollups("interop", []*rollup.Config{rollupConfig(901), rollupConfig(902)})
+	require.NoError(t, err)
+	require.Equal(t, chaincfg.Dependency{ChainIndex: 1, ActivationTime: 1000}, bundle.DependencySets["interop"].Dependencies["902"])
+	require.Equal(t, uint64(10), bundle.Chains["901"].Genesis.L1.Number)
+	require.Equal(t, uint64(900), bundle.Chains["901"].Genesis.L2Time)
+
+	// the bundle is loaded by op-supervisor as a registry
+	path := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, jsonutil.WriteJSON(bundle, ioutil.ToAtomicFile(path, 0o644)))
+	registry, err := chaincfg.LoadRegistry(context.Background(), path, common.Hash{})
+	require.NoError(t, err)
+	depSet, err := registry.DependencySet("interop")
+	require.NoError(t, err)
+	require.True(t, depSet.HasChain("901"))
+	require.True(t, depSet.HasChain("902"))
+
+	_, err = SupervisorRegistryFromRollups("interop", []*rollup.Config{rollupConfig(901), rollupConfig(901)})
+	require.ErrorContains(t, err, "duplicate chain")
+
+	noInterop := rollupConfig(903)
+	noInterop.InteropTime = nil
+	_, err = SupervisorRegistryFromRollups("interop", []*rollup.Config{noInterop})
+	require.ErrorContains(t, err, "does not schedule interop")
+}
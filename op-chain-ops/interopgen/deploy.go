@@ -97,6 +97,9 @@ func Deploy(logger log.Logger, fa *foundry.ArtifactsFS, srcFS *foundry.SourceMap
 		}
 		out.L2s[l2ChainID] = l2Out
 	}
+	if err := out.Check(); err != nil {
+		return nil, nil, fmt.Errorf("inconsistent world output: %w", err)
+	}
 	return deployments, out, nil
 }
 
@@ -1,9 +1,17 @@
 package interopgen
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
 	"github.com/ethereum/go-ethereum/core"
 
+	"github.com/ethereum-optimism/optimism/op-chain-ops/deployer/inspect"
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
 type L1Output struct {
@@ -19,3 +27,86 @@ type WorldOutput struct {
 	L1  *L1Output
 	L2s map[string]*L2Output
 }
+
+// Check verifies that the L2 outputs are consistent with each other, and with the L1 output:
+// all L2s anchor to the L1 genesis block, declare the chain ID they are keyed by in both
+// the genesis and the rollup config, commit to their genesis block, and activate interop at the same time.
+func (o *WorldOutput) Check() error {
+	if o.L1 == nil || o.L1.Genesis == nil || o.L1.Genesis.Config == nil {
+		return errors.New("missing L1 genesis")
+	}
+	l1ChainID := o.L1.Genesis.Config.ChainID
+	if l1ChainID == nil {
+		return errors.New("missing L1 chain ID")
+	}
+	l1Genesis := eth.ToBlockID(o.L1.Genesis.ToBlock())
+	if len(o.L2s) == 0 {
+		return errors.New("no L2 outputs")
+	}
+	var interopTime *uint64
+	var interopChain string
+	for id, l2 := range o.L2s {
+		if l2.Genesis == nil || l2.Genesis.Config == nil || l2.RollupCfg == nil {
+			return fmt.Errorf("L2 %s: missing genesis or rollup config", id)
+		}
+		if err := checkChainID(l2.Genesis.Config.ChainID, id); err != nil {
+			return fmt.Errorf("L2 %s: genesis: %w", id, err)
+		}
+		if err := checkChainID(l2.RollupCfg.L2ChainID, id); err != nil {
+			return fmt.Errorf("L2 %s: rollup config: %w", id, err)
+		}
+		if l2.RollupCfg.L1ChainID.Cmp(l1ChainID) != 0 {
+			return fmt.Errorf("L2 %s: rollup config L1 chain ID %s does not match L1 chain ID %s", id, l2.RollupCfg.L1ChainID, l1ChainID)
+		}
+		if l2.RollupCfg.Genesis.L1 != l1Genesis {
+			return fmt.Errorf("L2 %s: rollup config L1 genesis %s does not match L1 genesis %s", id, l2.RollupCfg.Genesis.L1, l1Genesis)
+		}
+		l2Genesis := l2.Genesis.ToBlock()
+		if genesisID := eth.ToBlockID(l2Genesis); l2.RollupCfg.Genesis.L2 != genesisID {
+			return fmt.Errorf("L2 %s: rollup config L2 genesis %s does not match L2 genesis %s", id, l2.RollupCfg.Genesis.L2, genesisID)
+		}
+		if l2.RollupCfg.Genesis.L2Time != l2Genesis.Time() {
+			return fmt.Errorf("L2 %s: rollup config L2 genesis time %d does not match L2 genesis time %d", id, l2.RollupCfg.Genesis.L2Time, l2Genesis.Time())
+		}
+		if l2.RollupCfg.InteropTime == nil {
+			return fmt.Errorf("L2 %s: interop is not scheduled", id)
+		}
+		if t := l2.Genesis.Config.InteropTime; t == nil || *t != *l2.RollupCfg.InteropTime {
+			return fmt.Errorf("L2 %s: genesis interop time %v does not match rollup config interop time %d", id, t, *l2.RollupCfg.InteropTime)
+		}
+		if interopTime == nil {
+			interopTime, interopChain = l2.RollupCfg.InteropTime, id
+		} else if *interopTime != *l2.RollupCfg.InteropTime {
+			return fmt.Errorf("L2 %s: interop time %d does not match interop time %d of L2 %s", id, *l2.RollupCfg.InteropTime, *interopTime, interopChain)
+		}
+	}
+	return nil
+}
+
+func checkChainID(chainID *big.Int, id string) error {
+	if chainID == nil {
+		return errors.New("missing chain ID")
+	}
+	if chainID.String() != id {
+		return fmt.Errorf("chain ID %s does not match %s", chainID, id)
+	}
+	return nil
+}
+
+// SupervisorRegistry returns the registry bundle that op-supervisor loads the L2s from,
+// with a dependency set of the given name that contains all L2s.
+// The chain index of each L2 in the dependency set follows the order of the chain IDs.
+func (o *WorldOutput) SupervisorRegistry(depSetName string) (*chaincfg.RegistryBundle, error) {
+	ids := make([]string, 0, len(o.L2s))
+	for id := range o.L2s {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return o.L2s[ids[i]].RollupCfg.L2ChainID.Cmp(o.L2s[ids[j]].RollupCfg.L2ChainID) < 0
+	})
+	rollupCfgs := make([]*rollup.Config, len(ids))
+	for i, id := range ids {
+		rollupCfgs[i] = o.L2s[id].RollupCfg
+	}
+	return inspect.SupervisorRegistryFromRollups(depSetName, rollupCfgs)
+}
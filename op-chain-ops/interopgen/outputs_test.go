@@ -0,0 +1,114 @@
+package interopgen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func testWorldOutput(l2ChainIDs ...uint64) *WorldOutput {
+	l1Genesis := &core.Genesis{
+		Config:     &params.ChainConfig{ChainID: big.NewInt(900100)},
+		Timestamp:  1000,
+		GasLimit:   30_000_000,
+		Difficulty: big.NewInt(0),
+	}
+	l1Block := l1Genesis.ToBlock()
+	out := &WorldOutput{
+		L1:  &L1Output{Genesis: l1Genesis},
+		L2s: make(map[string]*L2Output),
+	}
+	for _, chainID := range l2ChainIDs {
+		interopTime := uint64(2000)
+		l2Genesis := &core.Genesis{
+			Config:     &params.ChainConfig{ChainID: new(big.Int).SetUint64(chainID), InteropTime: &interopTime},
+			Timestamp:  1000,
+			GasLimit:   30_000_000,
+			Difficulty: big.NewInt(0),
+		}
+		l2Block := l2Genesis.ToBlock()
+		rollupInteropTime := interopTime
+		out.L2s[l2Genesis.Config.ChainID.String()] = &L2Output{
+			Genesis: l2Genesis,
+			RollupCfg: &rollup.Config{
+				Genesis: rollup.Genesis{
+					L1:     eth.ToBlockID(l1Block),
+					L2:     eth.ToBlockID(l2Block),
+					L2Time: l2Block.Time(),
+				},
+				L1ChainID:   big.NewInt(900100),
+				L2ChainID:   new(big.Int).SetUint64(chainID),
+				InteropTime: &rollupInteropTime,
+			},
+		}
+	}
+	return out
+}
+
+func TestWorldOutputCheck(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, testWorldOutput(900200, 900201).Check())
+	})
+	t.Run("no L2s", func(t *testing.T) {
+		require.ErrorContains(t, testWorldOutput().Check(), "no L2 outputs")
+	})
+	t.Run("mismatched chain ID", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		out.L2s["900201"].RollupCfg.L2ChainID = big.NewInt(900202)
+		require.ErrorContains(t, out.Check(), "rollup config: chain ID 900202 does not match 900201")
+	})
+	t.Run("mismatched L1 chain ID", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		out.L2s["900200"].RollupCfg.L1ChainID = big.NewInt(1)
+		require.ErrorContains(t, out.Check(), "does not match L1 chain ID")
+	})
+	t.Run("mismatched L1 genesis", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		out.L2s["900200"].RollupCfg.Genesis.L1.Hash = types.EmptyRootHash
+		require.ErrorContains(t, out.Check(), "does not match L1 genesis")
+	})
+	t.Run("mismatched L2 genesis", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		out.L2s["900200"].Genesis.GasLimit = 20_000_000
+		require.ErrorContains(t, out.Check(), "does not match L2 genesis")
+	})
+	t.Run("interop not scheduled", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		out.L2s["900200"].RollupCfg.InteropTime = nil
+		require.ErrorContains(t, out.Check(), "interop is not scheduled")
+	})
+	t.Run("mismatched genesis interop time", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		*out.L2s["900200"].RollupCfg.InteropTime = 3000
+		require.ErrorContains(t, out.Check(), "does not match rollup config interop time")
+	})
+	t.Run("mismatched interop time across chains", func(t *testing.T) {
+		out := testWorldOutput(900200, 900201)
+		for _, l2 := range out.L2s {
+			*l2.RollupCfg.InteropTime += l2.RollupCfg.L2ChainID.Uint64()
+			*l2.Genesis.Config.InteropTime = *l2.RollupCfg.InteropTime
+		}
+		require.ErrorContains(t, out.Check(), "does not match interop time")
+	})
+}
+
+func TestWorldOutputSupervisorRegistry(t *testing.T) {
+	out := testWorldOutput(900202, 900200, 900201)
+	bundle, err := out.SupervisorRegistry("interop")
+	require.NoError(t, err)
+	require.Len(t, bundle.Chains, 3)
+	for i, id := range []string{"900200", "900201", "900202"} {
+		dep := bundle.DependencySets["interop"].Dependencies[id]
+		require.Equal(t, uint32(i), dep.ChainIndex)
+		require.Equal(t, uint64(2000), dep.ActivationTime)
+		require.Equal(t, out.L2s[id].RollupCfg, bundle.Chains[id])
+	}
+}
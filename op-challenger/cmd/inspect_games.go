@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum/go-ethereum/common"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	ChallengerRpcFlag = &cli.StringFlag{
+		Name:    "challenger-rpc",
+		Usage:   "HTTP provider URL for the RPC server of a running op-challenger.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "CHALLENGER_RPC"),
+	}
+	InspectGameAddressFlag = &cli.StringFlag{
+		Name:    "game-address",
+		Usage:   "Address of the game to inspect. All tracked games are inspected if not set.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "GAME_ADDRESS"),
+	}
+)
+
+func InspectGames(ctx *cli.Context) error {
+	rpcUrl := ctx.String(ChallengerRpcFlag.Name)
+	if rpcUrl == "" {
+		return fmt.Errorf("missing %v", ChallengerRpcFlag.Name)
+	}
+	var gameAddr common.Address
+	if ctx.IsSet(InspectGameAddressFlag.Name) {
+		addr, err := opservice.ParseAddress(ctx.String(InspectGameAddressFlag.Name))
+		if err != nil {
+			return err
+		}
+		gameAddr = addr
+	}
+
+	client, err := gethrpc.DialContext(ctx.Context, rpcUrl)
+	if err != nil {
+		return fmt.Errorf("failed to dial challenger: %w", err)
+	}
+	defer client.Close()
+
+	inspections, err := fetchInspections(ctx.Context, client, gameAddr)
+	if err != nil {
+		return err
+	}
+	printInspections(inspections, ctx.Bool(VerboseFlag.Name) || gameAddr != (common.Address{}))
+	return nil
+}
+
+func fetchInspections(ctx context.Context, client *gethrpc.Client, gameAddr common.Address) ([]types.GameInspection, error) {
+	if gameAddr != (common.Address{}) {
+		var inspection types.GameInspection
+		if err := client.CallContext(ctx, &inspection, "challenger_inspectGame", gameAddr); err != nil {
+			return nil, fmt.Errorf("failed to inspect game %v: %w", gameAddr, err)
+		}
+		return []types.GameInspection{inspection}, nil
+	}
+	var inspections []types.GameInspection
+	if err := client.CallContext(ctx, &inspections, "challenger_inspectGames"); err != nil {
+		return nil, fmt.Errorf("failed to inspect games: %w", err)
+	}
+	return inspections, nil
+}
+
+func printInspections(inspections []types.GameInspection, verbose bool) {
+	lineFormat := "%-42v %-14v %-9v %5v %14v %14v %-19v %6v %5v %v\n"
+	fmt.Printf(lineFormat, "Game", "Status", "Schedule", "Queue", "Scheduled", "Processed", "Updated", "Claims", "Moves", "Error")
+	for _, inspection := range inspections {
+		schedule := "idle"
+		if inspection.Schedule.Running {
+			schedule = "running"
+		} else if inspection.Schedule.Inflight {
+			schedule = "queued"
+		}
+		updated := "-"
+		claims := 0
+		moves := 0
+		errMsg := ""
+		if player := inspection.Player; player != nil {
+			updated = player.Updated.Local().Format(time.DateTime)
+			claims = len(player.Claims)
+			moves = len(player.Moves)
+			errMsg = player.Error
+		}
+		fmt.Printf(lineFormat, inspection.Game, inspection.Status, schedule, inspection.Schedule.QueuePosition,
+			inspection.Schedule.ScheduledBlock, inspection.Schedule.LastProcessedBlock, updated, claims, moves, errMsg)
+	}
+	if !verbose {
+		return
+	}
+	for _, inspection := range inspections {
+		if inspection.Player == nil {
+			continue
+		}
+		fmt.Printf("\nGame %v\n", inspection.Game)
+		claimFormat := "%4v %6v %5v %-12v %-66v %-42v %-42v\n"
+		fmt.Printf(claimFormat, "Idx", "Parent", "Depth", "Assessment", "Value", "Claimant", "Countered By")
+		for _, claim := range inspection.Player.Claims {
+			parent := ""
+			if claim.ParentIndex >= 0 && claim.Depth > 0 {
+				parent = fmt.Sprint(claim.ParentIndex)
+			}
+			counteredBy := ""
+			if claim.CounteredBy != (common.Address{}) {
+				counteredBy = claim.CounteredBy.Hex()
+			}
+			fmt.Printf(claimFormat, claim.Index, parent, claim.Depth, claim.Assessment, claim.Value, claim.Claimant, counteredBy)
+		}
+		if len(inspection.Player.Moves) == 0 {
+			continue
+		}
+		moveFormat := "%-26v %6v %-6v %-66v %v\n"
+		fmt.Printf("\n"+moveFormat, "Planned Move", "Parent", "Attack", "Value", "Error")
+		for _, move := range inspection.Player.Moves {
+			fmt.Printf(moveFormat, move.Type, move.ParentIndex, move.IsAttack, move.Value, move.Error)
+		}
+	}
+}
+
+func inspectGamesFlags() []cli.Flag {
+	cliFlags := []cli.Flag{
+		ChallengerRpcFlag,
+		InspectGameAddressFlag,
+		VerboseFlag,
+	}
+	cliFlags = append(cliFlags, oplog.CLIFlags(flags.EnvVarPrefix)...)
+	return cliFlags
+}
+
+var InspectGamesCommand = &cli.Command{
+	Name:        "inspect-games",
+	Usage:       "Inspect the games tracked by a running op-challenger",
+	Description: "Reports the claims, claim assessment, planned moves and scheduling state of each game tracked by a running op-challenger. Requires the challenger's RPC server to be enabled.",
+	Action:      Interruptible(InspectGames),
+	Flags:       inspectGamesFlags(),
+}
@@ -52,11 +52,13 @@ func run(ctx context.Context, args []string, action ConfiguredLifecycle) error {
 		ListGamesCommand,
 		ListClaimsCommand,
 		ListCreditsCommand,
+		InspectGamesCommand,
 		CreateGameCommand,
 		MoveCommand,
 		ResolveCommand,
 		ResolveClaimCommand,
 		RunTraceCommand,
+		RunVmWorkerCommand,
 	}
 	app.Action = cliapp.LifecycleCmd(func(ctx *cli.Context, close context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 		logger, err := setupLogging(ctx)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"runtime"
 	"testing"
 	"time"
 
@@ -14,7 +16,9 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/resources"
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
@@ -31,6 +35,7 @@ var (
 	cannonPreState          = "./pre.json"
 	datadir                 = "./test_data"
 	rollupRpc               = "http://example.com:8555"
+	supervisorRpc           = "http://example.com:8545"
 	asteriscNetwork         = "op-mainnet"
 	asteriscBin             = "./bin/asterisc"
 	asteriscServer          = "./bin/op-program"
@@ -224,6 +229,195 @@ func TestMaxConcurrency(t *testing.T) {
 	})
 }
 
+func TestVmRemoteWorkers(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeCannon))
+		require.Empty(t, cfg.Cannon.RemoteWorkers)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeCannon, "--vm-remote-workers", "http://worker1:7400/,https://worker2"))
+		expected := []string{"http://worker1:7400", "https://worker2"}
+		require.Equal(t, expected, cfg.Cannon.RemoteWorkers)
+		require.Equal(t, expected, cfg.Asterisc.RemoteWorkers)
+		require.Equal(t, expected, cfg.AsteriscKona.RemoteWorkers)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid remote vm worker url", addRequiredArgs(types.TraceTypeCannon, "--vm-remote-workers", "worker1:7400"))
+	})
+
+	t.Run("LocalVmNotRequired", func(t *testing.T) {
+		args := addRequiredArgsExcept(types.TraceTypeCannon, "--cannon-bin", "--vm-remote-workers", "http://worker1")
+		cfg := configForArgs(t, args)
+		require.Empty(t, cfg.Cannon.VmBin)
+		require.NoError(t, cfg.Check())
+	})
+}
+
+func TestVmSnapshotCacheDir(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeCannon))
+		require.Empty(t, cfg.Cannon.SnapshotCacheDir)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeCannon, "--vm-snapshot-cache-dir", "/snapshots"))
+		require.Equal(t, "/snapshots", cfg.Cannon.SnapshotCacheDir)
+		require.Equal(t, "/snapshots", cfg.CannonKona.SnapshotCacheDir)
+		require.Equal(t, "/snapshots", cfg.Asterisc.SnapshotCacheDir)
+		require.Equal(t, "/snapshots", cfg.AsteriscKona.SnapshotCacheDir)
+	})
+}
+
+func TestVmResources(t *testing.T) {
+	t.Run("UsesDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.Equal(t, resources.Budget{Slots: runtime.NumCPU(), PreemptWindow: config.DefaultVmPreemptWindow}, cfg.VmResources)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet,
+			"--vm-max-concurrency", "3",
+			"--vm-memory-budget-mib", "4096", "--vm-execution-memory-mib", "1024",
+			"--vm-disk-budget-mib", "8192", "--vm-execution-disk-mib", "2048",
+			"--vm-preempt-window", "3h"))
+		require.Equal(t, resources.Budget{
+			Slots:                3,
+			MemoryBytes:          4096 * 1024 * 1024,
+			ExecutionMemoryBytes: 1024 * 1024 * 1024,
+			DiskBytes:            8192 * 1024 * 1024,
+			ExecutionDiskBytes:   2048 * 1024 * 1024,
+			PreemptWindow:        3 * time.Hour,
+		}, cfg.VmResources)
+	})
+
+	t.Run("ExecutionExceedsBudget", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--vm-memory-budget-mib", "1024", "--vm-execution-memory-mib", "2048"))
+		require.ErrorIs(t, cfg.Check(), config.ErrVmExecutionExceedsBudget)
+	})
+}
+
+func TestDiskQuotas(t *testing.T) {
+	t.Run("UsesDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.Zero(t, cfg.GameDiskQuota)
+		require.Zero(t, cfg.DiskQuota)
+		require.Zero(t, cfg.ResolvedGameRetention)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet,
+			"--game-disk-quota-mib", "1024", "--disk-quota-mib", "4096", "--resolved-game-retention", "24h"))
+		require.EqualValues(t, 1024*1024*1024, cfg.GameDiskQuota)
+		require.EqualValues(t, 4096*1024*1024, cfg.DiskQuota)
+		require.Equal(t, 24*time.Hour, cfg.ResolvedGameRetention)
+		require.NoError(t, cfg.Check())
+	})
+
+	t.Run("GameQuotaExceedsTotal", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--game-disk-quota-mib", "2048", "--disk-quota-mib", "1024"))
+		require.ErrorIs(t, cfg.Check(), config.ErrGameDiskQuotaExceedsTotal)
+	})
+}
+
+func TestDivergenceWebhook(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.Empty(t, cfg.DivergenceWebhook)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		url := "https://example.com/alerts"
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--divergence-webhook", url))
+		require.Equal(t, url, cfg.DivergenceWebhook)
+	})
+}
+
+func TestParticipationPolicy(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.Empty(t, cfg.Participation.Rules)
+		require.Nil(t, cfg.Participation.MaxBond)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet,
+			"--participation-policy", "own-proposals",
+			"--participation-policy", "divergent-roots",
+			"--participation-policy", "own-proposals"))
+		require.Equal(t, []policy.Rule{policy.RuleOwnProposals, policy.RuleDivergentRoots}, cfg.Participation.Rules)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "unknown participation policy: \"bogus\"", addRequiredArgs(types.TraceTypeAlphabet, "--participation-policy", "bogus"))
+	})
+
+	t.Run("MaxBond", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--participation-max-bond-gwei", "1500"))
+		require.Equal(t, big.NewInt(1_500_000_000_000), cfg.Participation.MaxBond)
+	})
+}
+
+func TestNonUrgentActionDeferral(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.Nil(t, cfg.NonUrgentMaxGasPrice)
+		require.Equal(t, config.DefaultMaxActionDeferral, cfg.MaxActionDeferral)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--non-urgent-max-gas-price-gwei", "30", "--max-action-deferral", "6h"))
+		require.Equal(t, big.NewInt(30_000_000_000), cfg.NonUrgentMaxGasPrice)
+		require.Equal(t, 6*time.Hour, cfg.MaxActionDeferral)
+	})
+
+	t.Run("ZeroDisables", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--non-urgent-max-gas-price-gwei", "0"))
+		require.Nil(t, cfg.NonUrgentMaxGasPrice)
+	})
+}
+
+func TestMulticall(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.Equal(t, common.Address{}, cfg.MulticallAddress)
+		require.Equal(t, config.DefaultMulticallBatchSize, cfg.MulticallBatchSize)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		addr := common.Address{0xca, 0x11}
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--multicall-address", addr.Hex(), "--multicall-batch-size", "7"))
+		require.Equal(t, addr, cfg.MulticallAddress)
+		require.Equal(t, uint(7), cfg.MulticallBatchSize)
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		verifyArgsInvalid(
+			t,
+			"invalid multicall address",
+			addRequiredArgs(types.TraceTypeAlphabet, "--multicall-address", "foo"))
+	})
+
+	t.Run("ZeroBatchSize", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--multicall-address", common.Address{0xca}.Hex(), "--multicall-batch-size", "0"))
+		require.ErrorIs(t, cfg.Check(), config.ErrMulticallBatchSizeZero)
+	})
+}
+
+func TestRPC(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet))
+		require.False(t, cfg.RPCEnabled)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(types.TraceTypeAlphabet, "--rpc.enabled", "--rpc.port", "9123"))
+		require.True(t, cfg.RPCEnabled)
+		require.Equal(t, 9123, cfg.RPCConfig.ListenPort)
+	})
+}
+
 func TestMaxPendingTx(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		expected := uint64(345)
@@ -360,6 +554,74 @@ func TestAsteriscKonaRequiredArgs(t *testing.T) {
 	})
 }
 
+func TestSuperCannonRequiredArgs(t *testing.T) {
+	traceType := types.TraceTypeSuperCannon
+	t.Run("NotRequiredForCannonTrace", func(t *testing.T) {
+		configForArgs(t, addRequiredArgsExcept(types.TraceTypeCannon, "--supervisor-rpc"))
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		verifyArgsInvalid(t, "flag supervisor-rpc is required", addRequiredArgsExcept(traceType, "--supervisor-rpc"))
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(traceType))
+		require.Equal(t, supervisorRpc, cfg.SupervisorRpc)
+		require.Equal(t, cannonServer, cfg.Cannon.Server)
+		require.NoError(t, cfg.Check())
+	})
+
+	t.Run("CannonArgsRequired", func(t *testing.T) {
+		verifyArgsInvalid(t, "flag cannon-server is required", addRequiredArgsExcept(traceType, "--cannon-server"))
+	})
+}
+
+func TestCannonKonaRequiredArgs(t *testing.T) {
+	traceType := types.TraceTypeCannonKona
+	t.Run(fmt.Sprintf("TestCannonKonaServer-%v", traceType), func(t *testing.T) {
+		t.Run("NotRequiredForAlphabetTrace", func(t *testing.T) {
+			configForArgs(t, addRequiredArgsExcept(types.TraceTypeAlphabet, "--cannon-kona-server"))
+		})
+
+		t.Run("Required", func(t *testing.T) {
+			verifyArgsInvalid(t, "flag cannon-kona-server is required", addRequiredArgsExcept(traceType, "--cannon-kona-server"))
+		})
+
+		t.Run("Valid", func(t *testing.T) {
+			cfg := configForArgs(t, addRequiredArgsExcept(traceType, "--cannon-kona-server", "--cannon-kona-server=./kona-host"))
+			require.Equal(t, "./kona-host", cfg.CannonKona.Server)
+			require.Equal(t, cannonBin, cfg.CannonKona.VmBin)
+			require.True(t, cfg.CannonKona.BinarySnapshots)
+		})
+	})
+
+	t.Run(fmt.Sprintf("TestCannonKonaAbsolutePrestate-%v", traceType), func(t *testing.T) {
+		t.Run("NotRequiredForAlphabetTrace", func(t *testing.T) {
+			configForArgs(t, addRequiredArgsExcept(types.TraceTypeAlphabet, "--cannon-kona-prestate"))
+		})
+
+		t.Run("Required", func(t *testing.T) {
+			verifyArgsInvalid(t, "flag cannon-kona-prestates-url or cannon-kona-prestate is required", addRequiredArgsExcept(traceType, "--cannon-kona-prestate"))
+		})
+
+		t.Run("Valid", func(t *testing.T) {
+			cfg := configForArgs(t, addRequiredArgsExcept(traceType, "--cannon-kona-prestate", "--cannon-kona-prestate=./pre.bin.gz"))
+			require.Equal(t, "./pre.bin.gz", cfg.CannonKonaAbsolutePreState)
+		})
+	})
+
+	t.Run(fmt.Sprintf("TestCannonKonaAbsolutePrestateBaseURL-%v", traceType), func(t *testing.T) {
+		t.Run("Valid", func(t *testing.T) {
+			cfg := configForArgs(t, addRequiredArgsExcept(traceType, "--cannon-kona-prestate", "--cannon-kona-prestates-url=http://localhost/bar"))
+			require.Equal(t, "http://localhost/bar", cfg.CannonKonaAbsolutePreStateBaseURL.String())
+		})
+	})
+
+	t.Run(fmt.Sprintf("TestCannonBin-%v", traceType), func(t *testing.T) {
+		verifyArgsInvalid(t, "flag cannon-bin is required", addRequiredArgsExcept(traceType, "--cannon-bin"))
+	})
+}
+
 func TestAsteriscBaseRequiredArgs(t *testing.T) {
 	for _, traceType := range []types.TraceType{types.TraceTypeAsterisc, types.TraceTypeAsteriscKona} {
 		traceType := traceType
@@ -636,6 +898,20 @@ func TestCannonRequiredArgs(t *testing.T) {
 			})
 		})
 
+		t.Run(fmt.Sprintf("TestCannonCompactSnapshots-%v", traceType), func(t *testing.T) {
+			t.Run("DefaultsToFalse", func(t *testing.T) {
+				cfg := configForArgs(t, addRequiredArgs(traceType))
+				require.False(t, cfg.Cannon.CompactSnapshots)
+				require.False(t, cfg.CannonKona.CompactSnapshots)
+			})
+
+			t.Run("Enabled", func(t *testing.T) {
+				cfg := configForArgs(t, addRequiredArgs(traceType, "--cannon-compact-snapshots"))
+				require.True(t, cfg.Cannon.CompactSnapshots)
+				require.True(t, cfg.CannonKona.CompactSnapshots)
+			})
+		})
+
 		t.Run(fmt.Sprintf("TestCannonInfoFreq-%v", traceType), func(t *testing.T) {
 			t.Run("UsesDefault", func(t *testing.T) {
 				cfg := configForArgs(t, addRequiredArgs(traceType))
@@ -906,6 +1182,11 @@ func requiredArgs(traceType types.TraceType) map[string]string {
 		addRequiredAsteriscArgs(args)
 	case types.TraceTypeAsteriscKona:
 		addRequiredAsteriscKonaArgs(args)
+	case types.TraceTypeCannonKona:
+		addRequiredCannonKonaArgs(args)
+	case types.TraceTypeSuperCannon:
+		addRequiredCannonArgs(args)
+		args["--supervisor-rpc"] = supervisorRpc
 	}
 	return args
 }
@@ -918,6 +1199,14 @@ func addRequiredCannonArgs(args map[string]string) {
 	args["--l2-eth-rpc"] = l2EthRpc
 }
 
+func addRequiredCannonKonaArgs(args map[string]string) {
+	args["--cannon-network"] = cannonNetwork
+	args["--cannon-bin"] = cannonBin
+	args["--cannon-kona-server"] = cannonServer
+	args["--cannon-kona-prestate"] = cannonPreState
+	args["--l2-eth-rpc"] = l2EthRpc
+}
+
 func addRequiredAsteriscArgs(args map[string]string) {
 	args["--asterisc-network"] = asteriscNetwork
 	args["--asterisc-bin"] = asteriscBin
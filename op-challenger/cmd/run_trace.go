@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"reflect"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/flags"
@@ -52,7 +53,7 @@ func checkMTCannonFlags(ctx *cli.Context, cfg *config.Config) error {
 		if ctx.IsSet(addMTCannonPrestateFlag.Name) != ctx.IsSet(addMTCannonPrestateURLFlag.Name) {
 			return fmt.Errorf("both flag %v and %v must be set when running MT-Cannon traces", addMTCannonPrestateURLFlag.Name, addMTCannonPrestateFlag.Name)
 		}
-		if cfg.Cannon == (vm.Config{}) {
+		if reflect.DeepEqual(cfg.Cannon, vm.Config{}) {
 			return errors.New("required Cannon vm configuration for mt-cannon traces is missing")
 		}
 	}
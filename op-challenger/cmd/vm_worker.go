@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/runner"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/urfave/cli/v2"
+)
+
+func RunVmWorker(ctx *cli.Context, _ context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	logger, err := setupLogging(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Starting VM worker", "version", VersionWithMeta)
+
+	cfg, err := flags.NewConfigFromCLI(ctx, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
+	return runner.NewVmWorker(logger, cfg, ctx.String(vmWorkerAddrFlag.Name), ctx.Int(vmWorkerPortFlag.Name)), nil
+}
+
+func vmWorkerFlags() []cli.Flag {
+	return append(flags.Flags, vmWorkerAddrFlag, vmWorkerPortFlag)
+}
+
+var RunVmWorkerCommand = &cli.Command{
+	Name:        "run-vm-worker",
+	Usage:       "Executes cannon and asterisc traces for challengers configured with remote VM workers",
+	Description: "Serves an HTTP API that challengers use to submit VM executions and fetch the resulting proofs",
+	Action:      cliapp.LifecycleCmd(RunVmWorker),
+	Flags:       vmWorkerFlags(),
+}
+
+var (
+	vmWorkerAddrFlag = &cli.StringFlag{
+		Name:    "vm-worker.addr",
+		Usage:   "Address the VM worker listens on",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "VM_WORKER_ADDR"),
+		Value:   "0.0.0.0",
+	}
+	vmWorkerPortFlag = &cli.IntFlag{
+		Name:    "vm-worker.port",
+		Usage:   "Port the VM worker listens on",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "VM_WORKER_PORT"),
+		Value:   7400,
+	}
+)
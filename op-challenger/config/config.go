@@ -3,16 +3,20 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"net/url"
 	"runtime"
 	"slices"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/resources"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -37,6 +41,10 @@ var (
 	ErrCannonNetworkAndL2Genesis        = errors.New("only specify one of network or l2 genesis path")
 	ErrCannonNetworkUnknown             = errors.New("unknown cannon network")
 	ErrMissingRollupRpc                 = errors.New("missing rollup rpc url")
+	ErrMissingSupervisorRpc             = errors.New("missing supervisor rpc url")
+	ErrVmExecutionExceedsBudget         = errors.New("vm execution resources exceed the total vm resource budget")
+	ErrGameDiskQuotaExceedsTotal        = errors.New("game disk quota exceeds the total disk quota")
+	ErrMulticallBatchSizeZero           = errors.New("multicall batch size must not be 0")
 
 	ErrMissingAsteriscBin                 = errors.New("missing asterisc bin")
 	ErrMissingAsteriscServer              = errors.New("missing asterisc server")
@@ -53,6 +61,7 @@ var (
 
 const (
 	DefaultPollInterval         = time.Second * 12
+	DefaultMulticallBatchSize   = uint(50)
 	DefaultCannonSnapshotFreq   = uint(1_000_000_000)
 	DefaultCannonInfoFreq       = uint(10_000_000)
 	DefaultAsteriscSnapshotFreq = uint(1_000_000_000)
@@ -64,6 +73,11 @@ const (
 	// buffer to monitor games to ensure bonds are claimed.
 	DefaultGameWindow   = time.Duration(28 * 24 * time.Hour)
 	DefaultMaxPendingTx = 10
+	// DefaultVmPreemptWindow is the default time before a response is due within which a VM execution may preempt
+	// lower priority executions.
+	DefaultVmPreemptWindow = 12 * time.Hour
+	// DefaultMaxActionDeferral is the default maximum time non-urgent actions are deferred while gas prices are high.
+	DefaultMaxActionDeferral = 24 * time.Hour
 )
 
 // Config is a well typed config that is parsed from the CLI params.
@@ -80,21 +94,41 @@ type Config struct {
 	PollInterval         time.Duration    // Polling interval for latest-block subscription when using an HTTP RPC provider
 	AllowInvalidPrestate bool             // Whether to allow responding to games where the prestate does not match
 
+	VmResources resources.Budget // Resources available to concurrent VM executions
+
+	GameDiskQuota         uint64        // Maximum disk space in bytes for a single game's data before its oldest VM snapshots are removed (0 for no limit)
+	DiskQuota             uint64        // Maximum disk space in bytes for all game data before resolved games are removed early (0 for no limit)
+	ResolvedGameRetention time.Duration // Time to keep data for games that are no longer in progress
+
 	AdditionalBondClaimants []common.Address // List of addresses to claim bonds for in addition to the tx manager sender
 
+	DivergenceWebhook string // URL to post alerts to when the local trace diverges from honest claims or the output root (disabled if empty)
+
+	Participation policy.Config // Policies selecting which games to participate in
+
 	SelectiveClaimResolution bool // Whether to only resolve claims for the claimants in AdditionalBondClaimants union [TxSender.From()]
 
+	MulticallAddress   common.Address // Address of the Multicall3 contract used to batch claim resolution and credit claims (disabled if zero)
+	MulticallBatchSize uint           // Maximum number of calls to combine into a single multicall transaction
+
 	TraceTypes []types.TraceType // Type of traces supported
 
 	RollupRpc string // L2 Rollup RPC Url
 
 	L2Rpc string // L2 RPC Url
 
+	SupervisorRpc string // L2 supervisor RPC Url, required for super root games
+
 	// Specific to the cannon trace provider
 	Cannon                        vm.Config
 	CannonAbsolutePreState        string   // File to load the absolute pre-state for Cannon traces from
 	CannonAbsolutePreStateBaseURL *url.URL // Base URL to retrieve absolute pre-states for Cannon traces from
 
+	// Specific to the cannon-kona trace provider
+	CannonKona                        vm.Config
+	CannonKonaAbsolutePreState        string   // File to load the absolute pre-state for CannonKona traces from
+	CannonKonaAbsolutePreStateBaseURL *url.URL // Base URL to retrieve absolute pre-states for CannonKona traces from
+
 	// Specific to the asterisc trace provider
 	Asterisc                            vm.Config
 	AsteriscAbsolutePreState            string   // File to load the absolute pre-state for Asterisc traces from
@@ -105,9 +139,14 @@ type Config struct {
 
 	MaxPendingTx uint64 // Maximum number of pending transactions (0 == no limit)
 
+	NonUrgentMaxGasPrice *big.Int      // Maximum gas price in wei to pay for resolutions and credit claims before deferring them (nil for no limit)
+	MaxActionDeferral    time.Duration // Maximum time to defer non-urgent actions while the gas price exceeds NonUrgentMaxGasPrice
+
 	TxMgrConfig   txmgr.CLIConfig
 	MetricsConfig opmetrics.CLIConfig
 	PprofConfig   oppprof.CLIConfig
+	RPCEnabled    bool // Whether to start the RPC server
+	RPCConfig     oprpc.CLIConfig
 }
 
 func NewConfig(
@@ -127,14 +166,18 @@ func NewConfig(
 		GameFactoryAddress: gameFactoryAddress,
 		MaxConcurrency:     uint(runtime.NumCPU()),
 		PollInterval:       DefaultPollInterval,
+		VmResources:        resources.Budget{Slots: runtime.NumCPU(), PreemptWindow: DefaultVmPreemptWindow},
+		MulticallBatchSize: DefaultMulticallBatchSize,
 
 		TraceTypes: supportedTraceTypes,
 
-		MaxPendingTx: DefaultMaxPendingTx,
+		MaxPendingTx:      DefaultMaxPendingTx,
+		MaxActionDeferral: DefaultMaxActionDeferral,
 
 		TxMgrConfig:   txmgr.NewCLIConfig(l1EthRpc, txmgr.DefaultChallengerFlagValues),
 		MetricsConfig: opmetrics.DefaultCLIConfig(),
 		PprofConfig:   oppprof.DefaultCLIConfig(),
+		RPCConfig:     oprpc.DefaultCLIConfig(),
 
 		Datadir: datadir,
 
@@ -148,6 +191,16 @@ func NewConfig(
 			DebugInfo:       true,
 			BinarySnapshots: true,
 		},
+		CannonKona: vm.Config{
+			VmType:          types.TraceTypeCannonKona,
+			L1:              l1EthRpc,
+			L1Beacon:        l1BeaconApi,
+			L2:              l2EthRpc,
+			SnapshotFreq:    DefaultCannonSnapshotFreq,
+			InfoFreq:        DefaultCannonInfoFreq,
+			DebugInfo:       true,
+			BinarySnapshots: true,
+		},
 		Asterisc: vm.Config{
 			VmType:       types.TraceTypeAsterisc,
 			L1:           l1EthRpc,
@@ -197,12 +250,30 @@ func (c Config) Check() error {
 	if c.MaxConcurrency == 0 {
 		return ErrMaxConcurrencyZero
 	}
-	if c.TraceTypeEnabled(types.TraceTypeCannon) || c.TraceTypeEnabled(types.TraceTypePermissioned) {
-		if c.Cannon.VmBin == "" {
-			return ErrMissingCannonBin
-		}
-		if c.Cannon.Server == "" {
-			return ErrMissingCannonServer
+	if c.MulticallAddress != (common.Address{}) && c.MulticallBatchSize == 0 {
+		return ErrMulticallBatchSizeZero
+	}
+	if c.DiskQuota != 0 && c.GameDiskQuota > c.DiskQuota {
+		return fmt.Errorf("%w: game quota %v > total quota %v", ErrGameDiskQuotaExceedsTotal, c.GameDiskQuota, c.DiskQuota)
+	}
+	if c.VmResources.MemoryBytes != 0 && c.VmResources.ExecutionMemoryBytes > c.VmResources.MemoryBytes {
+		return fmt.Errorf("%w: execution memory %v > memory budget %v", ErrVmExecutionExceedsBudget, c.VmResources.ExecutionMemoryBytes, c.VmResources.MemoryBytes)
+	}
+	if c.VmResources.DiskBytes != 0 && c.VmResources.ExecutionDiskBytes > c.VmResources.DiskBytes {
+		return fmt.Errorf("%w: execution disk %v > disk budget %v", ErrVmExecutionExceedsBudget, c.VmResources.ExecutionDiskBytes, c.VmResources.DiskBytes)
+	}
+	if c.TraceTypeEnabled(types.TraceTypeSuperCannon) && c.SupervisorRpc == "" {
+		return ErrMissingSupervisorRpc
+	}
+	if c.TraceTypeEnabled(types.TraceTypeCannon) || c.TraceTypeEnabled(types.TraceTypePermissioned) || c.TraceTypeEnabled(types.TraceTypeSuperCannon) {
+		// Local binaries are not used when executions run on remote workers.
+		if len(c.Cannon.RemoteWorkers) == 0 {
+			if c.Cannon.VmBin == "" {
+				return ErrMissingCannonBin
+			}
+			if c.Cannon.Server == "" {
+				return ErrMissingCannonServer
+			}
 		}
 		if c.Cannon.Network == "" {
 			if c.Cannon.RollupConfigPath == "" {
@@ -236,11 +307,13 @@ func (c Config) Check() error {
 		}
 	}
 	if c.TraceTypeEnabled(types.TraceTypeAsterisc) {
-		if c.Asterisc.VmBin == "" {
-			return ErrMissingAsteriscBin
-		}
-		if c.Asterisc.Server == "" {
-			return ErrMissingAsteriscServer
+		if len(c.Asterisc.RemoteWorkers) == 0 {
+			if c.Asterisc.VmBin == "" {
+				return ErrMissingAsteriscBin
+			}
+			if c.Asterisc.Server == "" {
+				return ErrMissingAsteriscServer
+			}
 		}
 		if c.Asterisc.Network == "" {
 			if c.Asterisc.RollupConfigPath == "" {
@@ -282,5 +355,10 @@ func (c Config) Check() error {
 	if err := c.PprofConfig.Check(); err != nil {
 		return err
 	}
+	if c.RPCEnabled {
+		if err := c.RPCConfig.Check(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -25,6 +25,7 @@ var (
 	validDatadir                          = "/tmp/data"
 	validL2Rpc                            = "http://localhost:9545"
 	validRollupRpc                        = "http://localhost:8555"
+	validSupervisorRpc                    = "http://localhost:8545"
 
 	validAsteriscBin                        = "./bin/asterisc"
 	validAsteriscOpProgramBin               = "./bin/op-program"
@@ -58,6 +59,10 @@ func validConfig(traceType types.TraceType) Config {
 	if traceType == types.TraceTypeAsterisc {
 		applyValidConfigForAsterisc(&cfg)
 	}
+	if traceType == types.TraceTypeSuperCannon {
+		applyValidConfigForCannon(&cfg)
+		cfg.SupervisorRpc = validSupervisorRpc
+	}
 	return cfg
 }
 
@@ -72,6 +77,12 @@ func TestValidConfigIsValid(t *testing.T) {
 	}
 }
 
+func TestSupervisorRpcRequiredForSuperCannon(t *testing.T) {
+	config := validConfig(types.TraceTypeSuperCannon)
+	config.SupervisorRpc = ""
+	require.ErrorIs(t, config.Check(), ErrMissingSupervisorRpc)
+}
+
 func TestTxMgrConfig(t *testing.T) {
 	t.Run("Invalid", func(t *testing.T) {
 		config := validConfig(types.TraceTypeCannon)
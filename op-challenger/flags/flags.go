@@ -2,17 +2,21 @@ package flags
 
 import (
 	"fmt"
+	"math/big"
 	"net/url"
 	"runtime"
 	"slices"
 	"strings"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/resources"
 	"github.com/ethereum-optimism/optimism/op-service/flags"
 	"github.com/ethereum-optimism/superchain-registry/superchain"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
@@ -22,11 +26,14 @@ import (
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
 const EnvVarPrefix = "OP_CHALLENGER"
 
+const mib = 1024 * 1024
+
 func prefixEnvVars(name string) []string {
 	return opservice.PrefixEnvVar(EnvVarPrefix, name)
 }
@@ -48,12 +55,33 @@ var (
 		Usage:   "HTTP provider URL for the rollup node",
 		EnvVars: prefixEnvVars("ROLLUP_RPC"),
 	}
+	SupervisorRpcFlag = &cli.StringFlag{
+		Name:    "supervisor-rpc",
+		Usage:   "HTTP provider URL for the op-supervisor. Required for super root games.",
+		EnvVars: prefixEnvVars("SUPERVISOR_RPC"),
+	}
 	NetworkFlag        = flags.CLINetworkFlag(EnvVarPrefix, "")
 	FactoryAddressFlag = &cli.StringFlag{
 		Name:    "game-factory-address",
 		Usage:   "Address of the fault game factory contract.",
 		EnvVars: prefixEnvVars("GAME_FACTORY_ADDRESS"),
 	}
+	RPCEnabledFlag = &cli.BoolFlag{
+		Name:    "rpc.enabled",
+		Usage:   "Enable the RPC server, which reports on bonds and other challenger state",
+		EnvVars: prefixEnvVars("RPC_ENABLED"),
+	}
+	MulticallAddressFlag = &cli.StringFlag{
+		Name:    "multicall-address",
+		Usage:   "Address of the Multicall3 contract to use to batch claim resolution and bond claim transactions. Batching is disabled if not set.",
+		EnvVars: prefixEnvVars("MULTICALL_ADDRESS"),
+	}
+	MulticallBatchSizeFlag = &cli.UintFlag{
+		Name:    "multicall-batch-size",
+		Usage:   "Maximum number of calls to combine into a single multicall transaction.",
+		EnvVars: prefixEnvVars("MULTICALL_BATCH_SIZE"),
+		Value:   config.DefaultMulticallBatchSize,
+	}
 	GameAllowlistFlag = &cli.StringSliceFlag{
 		Name: "game-allowlist",
 		Usage: "List of Fault Game contract addresses the challenger is allowed to play. " +
@@ -78,6 +106,84 @@ var (
 		EnvVars: prefixEnvVars("MAX_CONCURRENCY"),
 		Value:   uint(runtime.NumCPU()),
 	}
+	VmMaxConcurrencyFlag = &cli.UintFlag{
+		Name:    "vm-max-concurrency",
+		Usage:   "Maximum number of VM executions to run concurrently when generating proofs. 0 for no limit.",
+		EnvVars: prefixEnvVars("VM_MAX_CONCURRENCY"),
+		Value:   uint(runtime.NumCPU()),
+	}
+	VmMemoryBudgetFlag = &cli.Uint64Flag{
+		Name:    "vm-memory-budget-mib",
+		Usage:   "Total memory in MiB available to concurrent VM executions. 0 for no limit.",
+		EnvVars: prefixEnvVars("VM_MEMORY_BUDGET_MIB"),
+	}
+	VmExecutionMemoryFlag = &cli.Uint64Flag{
+		Name:    "vm-execution-memory-mib",
+		Usage:   "Memory in MiB reserved from the VM memory budget by each VM execution.",
+		EnvVars: prefixEnvVars("VM_EXECUTION_MEMORY_MIB"),
+	}
+	VmDiskBudgetFlag = &cli.Uint64Flag{
+		Name:    "vm-disk-budget-mib",
+		Usage:   "Total disk space in MiB available to concurrent VM executions. 0 for no limit.",
+		EnvVars: prefixEnvVars("VM_DISK_BUDGET_MIB"),
+	}
+	VmExecutionDiskFlag = &cli.Uint64Flag{
+		Name:    "vm-execution-disk-mib",
+		Usage:   "Disk space in MiB reserved from the VM disk budget by each VM execution.",
+		EnvVars: prefixEnvVars("VM_EXECUTION_DISK_MIB"),
+	}
+	VmPreemptWindowFlag = &cli.DurationFlag{
+		Name: "vm-preempt-window",
+		Usage: "Time before a game's response is due within which its VM executions may preempt lower priority " +
+			"VM executions when no resources are available. 0 to disable preemption.",
+		EnvVars: prefixEnvVars("VM_PREEMPT_WINDOW"),
+		Value:   config.DefaultVmPreemptWindow,
+	}
+	GameDiskQuotaFlag = &cli.Uint64Flag{
+		Name:    "game-disk-quota-mib",
+		Usage:   "Disk space in MiB a single game's data may use before its oldest VM snapshots are removed. 0 for no limit.",
+		EnvVars: prefixEnvVars("GAME_DISK_QUOTA_MIB"),
+	}
+	DiskQuotaFlag = &cli.Uint64Flag{
+		Name: "disk-quota-mib",
+		Usage: "Disk space in MiB all game data may use. When exceeded, data for resolved games is removed before " +
+			"the retention period ends, then the oldest VM snapshots of games in progress. 0 for no limit.",
+		EnvVars: prefixEnvVars("DISK_QUOTA_MIB"),
+	}
+	ResolvedGameRetentionFlag = &cli.DurationFlag{
+		Name:    "resolved-game-retention",
+		Usage:   "Time to keep data for games that are no longer in progress before it is removed.",
+		EnvVars: prefixEnvVars("RESOLVED_GAME_RETENTION"),
+	}
+	DivergenceWebhookFlag = &cli.StringFlag{
+		Name: "divergence-webhook",
+		Usage: "URL to POST a JSON alert to when the local trace disagrees with a claim posted by the honest claimants " +
+			"or the VM execution disagrees with the rollup node's output root.",
+		EnvVars: prefixEnvVars("DIVERGENCE_WEBHOOK"),
+	}
+	ParticipationPolicyFlag = &cli.StringSliceFlag{
+		Name: "participation-policy",
+		Usage: "Only participate in games selected by at least one of these policies. Participates in all games if not set. Valid options: " +
+			openum.EnumString(policy.Rules),
+		EnvVars: prefixEnvVars("PARTICIPATION_POLICY"),
+	}
+	ParticipationMaxBondFlag = &cli.Uint64Flag{
+		Name: "participation-max-bond-gwei",
+		Usage: "Ignore games where the bond required to counter the root claim exceeds this amount in gwei. " +
+			"Games proposed by the claimants are always played. 0 for no limit.",
+		EnvVars: prefixEnvVars("PARTICIPATION_MAX_BOND_GWEI"),
+	}
+	VmRemoteWorkersFlag = &cli.StringSliceFlag{
+		Name:    "vm-remote-workers",
+		Usage:   "URLs of remote VM workers to execute cannon and asterisc traces on instead of running the VM locally. Workers are tried in order.",
+		EnvVars: prefixEnvVars("VM_REMOTE_WORKERS"),
+	}
+	VmSnapshotCacheDirFlag = &cli.StringFlag{
+		Name: "vm-snapshot-cache-dir",
+		Usage: "Directory to share VM snapshots between games disputing the same output range with the same prestate. " +
+			"Cached snapshots are verified before reuse. Snapshots are not shared if not set.",
+		EnvVars: prefixEnvVars("VM_SNAPSHOT_CACHE_DIR"),
+	}
 	L2EthRpcFlag = &cli.StringFlag{
 		Name:    "l2-eth-rpc",
 		Usage:   "L2 Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)  (cannon/asterisc trace type only)",
@@ -89,6 +195,18 @@ var (
 		Value:   config.DefaultMaxPendingTx,
 		EnvVars: prefixEnvVars("MAX_PENDING_TX"),
 	}
+	NonUrgentMaxGasPriceFlag = &cli.Uint64Flag{
+		Name: "non-urgent-max-gas-price-gwei",
+		Usage: "Defer resolving games and claims and claiming credit while the gas price exceeds this amount in gwei. " +
+			"0 for no limit.",
+		EnvVars: prefixEnvVars("NON_URGENT_MAX_GAS_PRICE_GWEI"),
+	}
+	MaxActionDeferralFlag = &cli.DurationFlag{
+		Name:    "max-action-deferral",
+		Usage:   "Maximum time to defer non-urgent actions while the gas price exceeds the non-urgent max gas price.",
+		EnvVars: prefixEnvVars("MAX_ACTION_DEFERRAL"),
+		Value:   config.DefaultMaxActionDeferral,
+	}
 	HTTPPollInterval = &cli.DurationFlag{
 		Name:    "http-poll-interval",
 		Usage:   "Polling interval for latest-block subscription when using an HTTP RPC provider.",
@@ -136,6 +254,22 @@ var (
 			"Prestates in this directory should be name as <commitment>.json (cannon trace type only)",
 		EnvVars: prefixEnvVars("CANNON_PRESTATES_URL"),
 	}
+	CannonKonaServerFlag = &cli.StringFlag{
+		Name:    "cannon-kona-server",
+		Usage:   "Path to kona executable to use as pre-image oracle server when generating trace data (cannon-kona trace type only)",
+		EnvVars: prefixEnvVars("CANNON_KONA_SERVER"),
+	}
+	CannonKonaPreStateFlag = &cli.StringFlag{
+		Name:    "cannon-kona-prestate",
+		Usage:   "Path to absolute prestate to use when generating trace data (cannon-kona trace type only)",
+		EnvVars: prefixEnvVars("CANNON_KONA_PRESTATE"),
+	}
+	CannonKonaPreStatesURLFlag = &cli.StringFlag{
+		Name: "cannon-kona-prestates-url",
+		Usage: "Base URL to absolute prestates to use when generating trace data. " +
+			"Prestates in this directory should be name as <commitment>.bin.gz (cannon-kona trace type only)",
+		EnvVars: prefixEnvVars("CANNON_KONA_PRESTATES_URL"),
+	}
 	CannonL2Flag = &cli.StringFlag{
 		Name:    "cannon-l2",
 		Usage:   fmt.Sprintf("Deprecated: Use %v instead", L2EthRpcFlag.Name),
@@ -147,6 +281,12 @@ var (
 		EnvVars: prefixEnvVars("CANNON_SNAPSHOT_FREQ"),
 		Value:   config.DefaultCannonSnapshotFreq,
 	}
+	CannonCompactSnapshotsFlag = &cli.BoolFlag{
+		Name: "cannon-compact-snapshots",
+		Usage: "Write compact cannon snapshots which store memory pages that are unchanged between snapshots only once " +
+			"(cannon trace types only)",
+		EnvVars: prefixEnvVars("CANNON_COMPACT_SNAPSHOTS"),
+	}
 	CannonInfoFreqFlag = &cli.UintFlag{
 		Name:    "cannon-info-freq",
 		Usage:   "Frequency of cannon info log messages to generate in VM steps (cannon trace type only)",
@@ -247,14 +387,34 @@ var requiredFlags = []cli.Flag{
 
 // optionalFlags is a list of unchecked cli flags
 var optionalFlags = []cli.Flag{
+	SupervisorRpcFlag,
 	NetworkFlag,
 	FactoryAddressFlag,
 	TraceTypeFlag,
 	MaxConcurrencyFlag,
+	VmMaxConcurrencyFlag,
+	VmMemoryBudgetFlag,
+	VmExecutionMemoryFlag,
+	VmDiskBudgetFlag,
+	VmExecutionDiskFlag,
+	VmPreemptWindowFlag,
+	GameDiskQuotaFlag,
+	DiskQuotaFlag,
+	ResolvedGameRetentionFlag,
+	DivergenceWebhookFlag,
+	ParticipationPolicyFlag,
+	ParticipationMaxBondFlag,
+	VmRemoteWorkersFlag,
+	VmSnapshotCacheDirFlag,
 	L2EthRpcFlag,
 	MaxPendingTransactionsFlag,
+	NonUrgentMaxGasPriceFlag,
+	MaxActionDeferralFlag,
 	HTTPPollInterval,
 	AdditionalBondClaimants,
+	MulticallAddressFlag,
+	MulticallBatchSizeFlag,
+	RPCEnabledFlag,
 	GameAllowlistFlag,
 	CannonNetworkFlag,
 	CannonRollupConfigFlag,
@@ -263,8 +423,12 @@ var optionalFlags = []cli.Flag{
 	CannonServerFlag,
 	CannonPreStateFlag,
 	CannonPreStatesURLFlag,
+	CannonKonaServerFlag,
+	CannonKonaPreStateFlag,
+	CannonKonaPreStatesURLFlag,
 	CannonL2Flag,
 	CannonSnapshotFreqFlag,
+	CannonCompactSnapshotsFlag,
 	CannonInfoFreqFlag,
 	AsteriscNetworkFlag,
 	AsteriscRollupConfigFlag,
@@ -288,6 +452,7 @@ func init() {
 	optionalFlags = append(optionalFlags, txmgr.CLIFlagsWithDefaults(EnvVarPrefix, txmgr.DefaultChallengerFlagValues)...)
 	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, oprpc.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -295,7 +460,9 @@ func init() {
 // Flags contains the list of configuration options available to the binary.
 var Flags []cli.Flag
 
-func CheckCannonFlags(ctx *cli.Context) error {
+func CheckCannonBaseFlags(ctx *cli.Context) error {
+	// The VM and server are only run locally when no remote workers are configured.
+	remoteVm := ctx.IsSet(VmRemoteWorkersFlag.Name)
 	if ctx.IsSet(CannonNetworkFlag.Name) && ctx.IsSet(flags.NetworkFlagName) {
 		return fmt.Errorf("flag %v can not be used with %v", CannonNetworkFlag.Name, flags.NetworkFlagName)
 	}
@@ -315,10 +482,18 @@ func CheckCannonFlags(ctx *cli.Context) error {
 		return fmt.Errorf("flag %v can not be used with %v and %v",
 			CannonNetworkFlag.Name, CannonRollupConfigFlag.Name, CannonL2GenesisFlag.Name)
 	}
-	if !ctx.IsSet(CannonBinFlag.Name) {
+	if !ctx.IsSet(CannonBinFlag.Name) && !remoteVm {
 		return fmt.Errorf("flag %s is required", CannonBinFlag.Name)
 	}
-	if !ctx.IsSet(CannonServerFlag.Name) {
+	return nil
+}
+
+func CheckCannonFlags(ctx *cli.Context) error {
+	if err := CheckCannonBaseFlags(ctx); err != nil {
+		return err
+	}
+	remoteVm := ctx.IsSet(VmRemoteWorkersFlag.Name)
+	if !ctx.IsSet(CannonServerFlag.Name) && !remoteVm {
 		return fmt.Errorf("flag %s is required", CannonServerFlag.Name)
 	}
 	if !ctx.IsSet(CannonPreStateFlag.Name) && !ctx.IsSet(CannonPreStatesURLFlag.Name) {
@@ -327,7 +502,22 @@ func CheckCannonFlags(ctx *cli.Context) error {
 	return nil
 }
 
+func CheckCannonKonaFlags(ctx *cli.Context) error {
+	if err := CheckCannonBaseFlags(ctx); err != nil {
+		return err
+	}
+	remoteVm := ctx.IsSet(VmRemoteWorkersFlag.Name)
+	if !ctx.IsSet(CannonKonaServerFlag.Name) && !remoteVm {
+		return fmt.Errorf("flag %s is required", CannonKonaServerFlag.Name)
+	}
+	if !ctx.IsSet(CannonKonaPreStateFlag.Name) && !ctx.IsSet(CannonKonaPreStatesURLFlag.Name) {
+		return fmt.Errorf("flag %s or %s is required", CannonKonaPreStatesURLFlag.Name, CannonKonaPreStateFlag.Name)
+	}
+	return nil
+}
+
 func CheckAsteriscBaseFlags(ctx *cli.Context) error {
+	remoteVm := ctx.IsSet(VmRemoteWorkersFlag.Name)
 	if ctx.IsSet(AsteriscNetworkFlag.Name) && ctx.IsSet(flags.NetworkFlagName) {
 		return fmt.Errorf("flag %v can not be used with %v", AsteriscNetworkFlag.Name, flags.NetworkFlagName)
 	}
@@ -347,17 +537,18 @@ func CheckAsteriscBaseFlags(ctx *cli.Context) error {
 		return fmt.Errorf("flag %v can not be used with %v and %v",
 			AsteriscNetworkFlag.Name, AsteriscRollupConfigFlag.Name, AsteriscL2GenesisFlag.Name)
 	}
-	if !ctx.IsSet(AsteriscBinFlag.Name) {
+	if !ctx.IsSet(AsteriscBinFlag.Name) && !remoteVm {
 		return fmt.Errorf("flag %s is required", AsteriscBinFlag.Name)
 	}
 	return nil
 }
 
 func CheckAsteriscFlags(ctx *cli.Context) error {
+	remoteVm := ctx.IsSet(VmRemoteWorkersFlag.Name)
 	if err := CheckAsteriscBaseFlags(ctx); err != nil {
 		return err
 	}
-	if !ctx.IsSet(AsteriscServerFlag.Name) {
+	if !ctx.IsSet(AsteriscServerFlag.Name) && !remoteVm {
 		return fmt.Errorf("flag %s is required", AsteriscServerFlag.Name)
 	}
 	if !ctx.IsSet(AsteriscPreStateFlag.Name) && !ctx.IsSet(AsteriscPreStatesURLFlag.Name) {
@@ -367,10 +558,11 @@ func CheckAsteriscFlags(ctx *cli.Context) error {
 }
 
 func CheckAsteriscKonaFlags(ctx *cli.Context) error {
+	remoteVm := ctx.IsSet(VmRemoteWorkersFlag.Name)
 	if err := CheckAsteriscBaseFlags(ctx); err != nil {
 		return err
 	}
-	if !ctx.IsSet(AsteriscKonaServerFlag.Name) {
+	if !ctx.IsSet(AsteriscKonaServerFlag.Name) && !remoteVm {
 		return fmt.Errorf("flag %s is required", AsteriscKonaServerFlag.Name)
 	}
 	if !ctx.IsSet(AsteriscKonaPreStateFlag.Name) && !ctx.IsSet(AsteriscKonaPreStatesURLFlag.Name) {
@@ -403,6 +595,17 @@ func CheckRequired(ctx *cli.Context, traceTypes []types.TraceType) error {
 			if err := CheckAsteriscKonaFlags(ctx); err != nil {
 				return err
 			}
+		case types.TraceTypeCannonKona:
+			if err := CheckCannonKonaFlags(ctx); err != nil {
+				return err
+			}
+		case types.TraceTypeSuperCannon:
+			if !ctx.IsSet(SupervisorRpcFlag.Name) {
+				return fmt.Errorf("flag %s is required", SupervisorRpcFlag.Name)
+			}
+			if err := CheckCannonFlags(ctx); err != nil {
+				return err
+			}
 		case types.TraceTypeAlphabet, types.TraceTypeFast:
 		default:
 			return fmt.Errorf("invalid trace type %v. must be one of %v", traceType, types.TraceTypes)
@@ -425,6 +628,23 @@ func parseTraceTypes(ctx *cli.Context) ([]types.TraceType, error) {
 	return traceTypes, nil
 }
 
+func parseParticipationPolicy(ctx *cli.Context) (policy.Config, error) {
+	var cfg policy.Config
+	for _, name := range ctx.StringSlice(ParticipationPolicyFlag.Name) {
+		rule := new(policy.Rule)
+		if err := rule.Set(name); err != nil {
+			return policy.Config{}, err
+		}
+		if !slices.Contains(cfg.Rules, *rule) {
+			cfg.Rules = append(cfg.Rules, *rule)
+		}
+	}
+	if maxBond := ctx.Uint64(ParticipationMaxBondFlag.Name); maxBond != 0 {
+		cfg.MaxBond = new(big.Int).Mul(new(big.Int).SetUint64(maxBond), big.NewInt(params.GWei))
+	}
+	return cfg, nil
+}
+
 func getL2Rpc(ctx *cli.Context, logger log.Logger) (string, error) {
 	if ctx.IsSet(CannonL2Flag.Name) && ctx.IsSet(L2EthRpcFlag.Name) {
 		return "", fmt.Errorf("flag %v and %v must not be both set", CannonL2Flag.Name, L2EthRpcFlag.Name)
@@ -513,6 +733,30 @@ func NewConfigFromCLI(ctx *cli.Context, logger log.Logger) (*config.Config, erro
 			claimants = append(claimants, claimant)
 		}
 	}
+	var multicallAddress common.Address
+	if ctx.IsSet(MulticallAddressFlag.Name) {
+		multicallAddress, err = opservice.ParseAddress(ctx.String(MulticallAddressFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid multicall address: %w", err)
+		}
+	}
+	participation, err := parseParticipationPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var remoteWorkers []string
+	for _, worker := range ctx.StringSlice(VmRemoteWorkersFlag.Name) {
+		parsed, err := url.Parse(worker)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil, fmt.Errorf("invalid remote vm worker url: %v", worker)
+		}
+		remoteWorkers = append(remoteWorkers, strings.TrimSuffix(worker, "/"))
+	}
+	snapshotCacheDir := ctx.String(VmSnapshotCacheDirFlag.Name)
+	var nonUrgentMaxGasPrice *big.Int
+	if maxGasPrice := ctx.Uint64(NonUrgentMaxGasPriceFlag.Name); maxGasPrice != 0 {
+		nonUrgentMaxGasPrice = new(big.Int).Mul(new(big.Int).SetUint64(maxGasPrice), big.NewInt(params.GWei))
+	}
 	var cannonPrestatesURL *url.URL
 	if ctx.IsSet(CannonPreStatesURLFlag.Name) {
 		parsed, err := url.Parse(ctx.String(CannonPreStatesURLFlag.Name))
@@ -521,6 +765,14 @@ func NewConfigFromCLI(ctx *cli.Context, logger log.Logger) (*config.Config, erro
 		}
 		cannonPrestatesURL = parsed
 	}
+	var cannonKonaPreStatesURL *url.URL
+	if ctx.IsSet(CannonKonaPreStatesURLFlag.Name) {
+		parsed, err := url.Parse(ctx.String(CannonKonaPreStatesURLFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cannon-kona pre states url (%v): %w", ctx.String(CannonKonaPreStatesURLFlag.Name), err)
+		}
+		cannonKonaPreStatesURL = parsed
+	}
 	var asteriscPreStatesURL *url.URL
 	if ctx.IsSet(AsteriscPreStatesURLFlag.Name) {
 		parsed, err := url.Parse(ctx.String(AsteriscPreStatesURLFlag.Name))
@@ -553,18 +805,36 @@ func NewConfigFromCLI(ctx *cli.Context, logger log.Logger) (*config.Config, erro
 	l1Beacon := ctx.String(L1BeaconFlag.Name)
 	return &config.Config{
 		// Required Flags
-		L1EthRpc:                l1EthRpc,
-		L1Beacon:                l1Beacon,
-		TraceTypes:              traceTypes,
-		GameFactoryAddress:      gameFactoryAddress,
-		GameAllowlist:           allowedGames,
-		GameWindow:              ctx.Duration(GameWindowFlag.Name),
-		MaxConcurrency:          maxConcurrency,
+		L1EthRpc:           l1EthRpc,
+		L1Beacon:           l1Beacon,
+		TraceTypes:         traceTypes,
+		GameFactoryAddress: gameFactoryAddress,
+		GameAllowlist:      allowedGames,
+		GameWindow:         ctx.Duration(GameWindowFlag.Name),
+		MaxConcurrency:     maxConcurrency,
+		VmResources: resources.Budget{
+			Slots:                int(ctx.Uint(VmMaxConcurrencyFlag.Name)),
+			MemoryBytes:          ctx.Uint64(VmMemoryBudgetFlag.Name) * mib,
+			ExecutionMemoryBytes: ctx.Uint64(VmExecutionMemoryFlag.Name) * mib,
+			DiskBytes:            ctx.Uint64(VmDiskBudgetFlag.Name) * mib,
+			ExecutionDiskBytes:   ctx.Uint64(VmExecutionDiskFlag.Name) * mib,
+			PreemptWindow:        ctx.Duration(VmPreemptWindowFlag.Name),
+		},
+		GameDiskQuota:           ctx.Uint64(GameDiskQuotaFlag.Name) * mib,
+		DiskQuota:               ctx.Uint64(DiskQuotaFlag.Name) * mib,
+		ResolvedGameRetention:   ctx.Duration(ResolvedGameRetentionFlag.Name),
 		L2Rpc:                   l2Rpc,
 		MaxPendingTx:            ctx.Uint64(MaxPendingTransactionsFlag.Name),
+		NonUrgentMaxGasPrice:    nonUrgentMaxGasPrice,
+		MaxActionDeferral:       ctx.Duration(MaxActionDeferralFlag.Name),
 		PollInterval:            ctx.Duration(HTTPPollInterval.Name),
 		AdditionalBondClaimants: claimants,
+		DivergenceWebhook:       ctx.String(DivergenceWebhookFlag.Name),
+		Participation:           participation,
+		MulticallAddress:        multicallAddress,
+		MulticallBatchSize:      ctx.Uint(MulticallBatchSizeFlag.Name),
 		RollupRpc:               ctx.String(RollupRpcFlag.Name),
+		SupervisorRpc:           ctx.String(SupervisorRpcFlag.Name),
 		Cannon: vm.Config{
 			VmType:           types.TraceTypeCannon,
 			L1:               l1EthRpc,
@@ -579,10 +849,33 @@ func NewConfigFromCLI(ctx *cli.Context, logger log.Logger) (*config.Config, erro
 			InfoFreq:         ctx.Uint(CannonInfoFreqFlag.Name),
 			DebugInfo:        true,
 			BinarySnapshots:  true,
+			CompactSnapshots: ctx.Bool(CannonCompactSnapshotsFlag.Name),
+			RemoteWorkers:    remoteWorkers,
+			SnapshotCacheDir: snapshotCacheDir,
 		},
 		CannonAbsolutePreState:        ctx.String(CannonPreStateFlag.Name),
 		CannonAbsolutePreStateBaseURL: cannonPrestatesURL,
-		Datadir:                       ctx.String(DatadirFlag.Name),
+		CannonKona: vm.Config{
+			VmType:           types.TraceTypeCannonKona,
+			L1:               l1EthRpc,
+			L1Beacon:         l1Beacon,
+			L2:               l2Rpc,
+			VmBin:            ctx.String(CannonBinFlag.Name),
+			Server:           ctx.String(CannonKonaServerFlag.Name),
+			Network:          cannonNetwork,
+			RollupConfigPath: ctx.String(CannonRollupConfigFlag.Name),
+			L2GenesisPath:    ctx.String(CannonL2GenesisFlag.Name),
+			SnapshotFreq:     ctx.Uint(CannonSnapshotFreqFlag.Name),
+			InfoFreq:         ctx.Uint(CannonInfoFreqFlag.Name),
+			DebugInfo:        true,
+			BinarySnapshots:  true,
+			CompactSnapshots: ctx.Bool(CannonCompactSnapshotsFlag.Name),
+			RemoteWorkers:    remoteWorkers,
+			SnapshotCacheDir: snapshotCacheDir,
+		},
+		CannonKonaAbsolutePreState:        ctx.String(CannonKonaPreStateFlag.Name),
+		CannonKonaAbsolutePreStateBaseURL: cannonKonaPreStatesURL,
+		Datadir:                           ctx.String(DatadirFlag.Name),
 		Asterisc: vm.Config{
 			VmType:           types.TraceTypeAsterisc,
 			L1:               l1EthRpc,
@@ -595,6 +888,8 @@ func NewConfigFromCLI(ctx *cli.Context, logger log.Logger) (*config.Config, erro
 			L2GenesisPath:    ctx.String(AsteriscL2GenesisFlag.Name),
 			SnapshotFreq:     ctx.Uint(AsteriscSnapshotFreqFlag.Name),
 			InfoFreq:         ctx.Uint(AsteriscInfoFreqFlag.Name),
+			RemoteWorkers:    remoteWorkers,
+			SnapshotCacheDir: snapshotCacheDir,
 		},
 		AsteriscAbsolutePreState:        ctx.String(AsteriscPreStateFlag.Name),
 		AsteriscAbsolutePreStateBaseURL: asteriscPreStatesURL,
@@ -610,12 +905,16 @@ func NewConfigFromCLI(ctx *cli.Context, logger log.Logger) (*config.Config, erro
 			L2GenesisPath:    ctx.String(AsteriscL2GenesisFlag.Name),
 			SnapshotFreq:     ctx.Uint(AsteriscSnapshotFreqFlag.Name),
 			InfoFreq:         ctx.Uint(AsteriscInfoFreqFlag.Name),
+			RemoteWorkers:    remoteWorkers,
+			SnapshotCacheDir: snapshotCacheDir,
 		},
 		AsteriscKonaAbsolutePreState:        ctx.String(AsteriscKonaPreStateFlag.Name),
 		AsteriscKonaAbsolutePreStateBaseURL: asteriscKonaPreStatesURL,
 		TxMgrConfig:                         txMgrConfig,
 		MetricsConfig:                       metricsConfig,
 		PprofConfig:                         pprofConfig,
+		RPCEnabled:                          ctx.Bool(RPCEnabledFlag.Name),
+		RPCConfig:                           oprpc.ReadCLIConfig(ctx),
 		SelectiveClaimResolution:            ctx.Bool(SelectiveClaimResolutionFlag.Name),
 		AllowInvalidPrestate:                ctx.Bool(UnsafeAllowInvalidPrestate.Name),
 	}, nil
@@ -3,35 +3,163 @@ package game
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/versions"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 const gameDirPrefix = "game-"
 
+type DiskMetricer interface {
+	RecordGameDataDisk(games int, bytes uint64)
+	RecordGameDataPruned(bytes uint64)
+}
+
+// DiskLimits controls how much game data is kept on disk.
+// Zero values are unlimited, except for ResolvedGameRetention where zero removes data as soon as a game is resolved.
+type DiskLimits struct {
+	// GameQuota is the maximum disk space used by a single game before its oldest VM snapshots are removed.
+	GameQuota uint64
+	// TotalQuota is the maximum disk space used by all games. When exceeded, data for resolved games is removed
+	// before its retention period ends, followed by the oldest VM snapshots of games in progress.
+	TotalQuota uint64
+	// ResolvedGameRetention is the time to keep data for games that are no longer in progress.
+	ResolvedGameRetention time.Duration
+}
+
 // diskManager coordinates the storage of game data on disk.
 type diskManager struct {
+	logger  log.Logger
+	m       DiskMetricer
+	clock   clock.Clock
 	datadir string
+	limits  DiskLimits
+
+	// resolvedAt records when each game directory was first found to no longer be required.
+	resolvedAt map[common.Address]time.Time
+	// sizes records the last measured size of each game directory.
+	sizes map[common.Address]gameSize
 }
 
-func newDiskManager(dir string) *diskManager {
-	return &diskManager{datadir: dir}
+// gameSize is the measured size of a game directory. The size is stale if the game has been inflight since it was
+// measured, as its VM executions may have written more data.
+type gameSize struct {
+	bytes uint64
+	stale bool
+}
+
+func newDiskManager(logger log.Logger, m DiskMetricer, cl clock.Clock, dir string, limits DiskLimits) *diskManager {
+	return &diskManager{
+		logger:     logger,
+		m:          m,
+		clock:      cl,
+		datadir:    dir,
+		limits:     limits,
+		resolvedAt: make(map[common.Address]time.Time),
+		sizes:      make(map[common.Address]gameSize),
+	}
 }
 
 func (d *diskManager) DirForGame(addr common.Address) string {
 	return filepath.Join(d.datadir, gameDirPrefix+addr.Hex())
 }
 
-func (d *diskManager) RemoveAllExcept(keep []common.Address) error {
-	entries, err := os.ReadDir(d.datadir)
+type gameDir struct {
+	addr       common.Address
+	path       string
+	size       uint64
+	keep       bool
+	inflight   bool
+	resolvedAt time.Time
+}
+
+// RemoveAllExcept removes data for games not in keep once their retention period has passed and enforces the disk
+// quotas.
+// VM snapshots are only removed for games that aren't inflight, as a VM may be executing for inflight games.
+// The size of a game is only measured again after it has been inflight, so the size of inflight games may lag behind
+// their actual size until they complete.
+func (d *diskManager) RemoveAllExcept(keep []common.Address, inflight []common.Address) error {
+	dirs, err := d.gameDirs(keep, inflight)
 	if err != nil {
-		return fmt.Errorf("failed to list directory: %w", err)
+		return err
 	}
+	now := d.clock.Now()
 	var errs []error
+	var retained []*gameDir
+	var prunable []*gameDir
+	var total uint64
+	var games int
+	for _, dir := range dirs {
+		if dir.keep {
+			if d.limits.GameQuota != 0 && dir.size > d.limits.GameQuota && !dir.inflight {
+				size := dir.size
+				freed, err := d.pruneSnapshots([]*gameDir{dir}, dir.size-d.limits.GameQuota)
+				errs = append(errs, err)
+				d.logger.Warn("Game data exceeds quota, removed VM snapshots", "game", dir.addr, "size", size, "quota", d.limits.GameQuota, "freed", freed)
+			}
+			total += dir.size
+			games++
+			if !dir.inflight {
+				prunable = append(prunable, dir)
+			}
+			continue
+		}
+		if now.Sub(dir.resolvedAt) >= d.limits.ResolvedGameRetention {
+			errs = append(errs, d.removeGame(dir))
+			continue
+		}
+		total += dir.size
+		games++
+		retained = append(retained, dir)
+	}
+
+	if d.limits.TotalQuota != 0 && total > d.limits.TotalQuota {
+		// Remove resolved games with the least remaining retention first.
+		sort.Slice(retained, func(i, j int) bool {
+			return retained[i].resolvedAt.Before(retained[j].resolvedAt)
+		})
+		for _, dir := range retained {
+			if total <= d.limits.TotalQuota {
+				break
+			}
+			d.logger.Warn("Game data exceeds total quota, removing resolved game before retention period ends", "game", dir.addr, "size", dir.size)
+			if err := d.removeGame(dir); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			total -= dir.size
+			games--
+		}
+	}
+	if d.limits.TotalQuota != 0 && total > d.limits.TotalQuota {
+		freed, err := d.pruneSnapshots(prunable, total-d.limits.TotalQuota)
+		errs = append(errs, err)
+		total -= freed
+		d.logger.Warn("Game data exceeds total quota, removed VM snapshots", "size", total+freed, "quota", d.limits.TotalQuota, "freed", freed)
+	}
+	d.m.RecordGameDataDisk(games, total)
+	return errors.Join(errs...)
+}
+
+// gameDirs lists the game directories in the datadir along with their size.
+func (d *diskManager) gameDirs(keep []common.Address, inflight []common.Address) ([]*gameDir, error) {
+	entries, err := os.ReadDir(d.datadir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+	now := d.clock.Now()
+	var dirs []*gameDir
+	found := make(map[common.Address]bool)
 	for _, entry := range entries {
 		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), gameDirPrefix) {
 			// Skip files and directories that don't have the game directory prefix.
@@ -45,11 +173,139 @@ func (d *diskManager) RemoveAllExcept(keep []common.Address) error {
 			// Ignore directories with non-address names.
 			continue
 		}
-		if slices.Contains(keep, addr) {
-			// Preserve data for games we should keep.
-			continue
+		found[addr] = true
+		dir := &gameDir{
+			addr:     addr,
+			path:     filepath.Join(d.datadir, entry.Name()),
+			keep:     slices.Contains(keep, addr),
+			inflight: slices.Contains(inflight, addr),
+		}
+		if dir.keep {
+			delete(d.resolvedAt, addr)
+		} else {
+			resolvedAt, ok := d.resolvedAt[addr]
+			if !ok {
+				resolvedAt = now
+				d.resolvedAt[addr] = now
+			}
+			dir.resolvedAt = resolvedAt
 		}
-		errs = append(errs, os.RemoveAll(filepath.Join(d.datadir, entry.Name())))
+		size, ok := d.sizes[addr]
+		if !ok || (size.stale && !dir.inflight) {
+			size.bytes, err = dirSize(dir.path)
+			if err != nil {
+				d.logger.Warn("Failed to calculate game data size", "game", addr, "err", err)
+			}
+		}
+		size.stale = dir.inflight
+		d.sizes[addr] = size
+		dir.size = size.bytes
+		dirs = append(dirs, dir)
 	}
-	return errors.Join(errs...)
+	for addr := range d.sizes {
+		if !found[addr] {
+			delete(d.sizes, addr)
+		}
+	}
+	return dirs, nil
+}
+
+func (d *diskManager) removeGame(dir *gameDir) error {
+	if err := os.RemoveAll(dir.path); err != nil {
+		return fmt.Errorf("failed to remove data for game %v: %w", dir.addr, err)
+	}
+	delete(d.resolvedAt, dir.addr)
+	delete(d.sizes, dir.addr)
+	d.m.RecordGameDataPruned(dir.size)
+	return nil
+}
+
+type snapshotFile struct {
+	dir     *gameDir
+	path    string
+	size    uint64
+	modTime time.Time
+}
+
+// pruneSnapshots removes the oldest VM snapshots from the game directories until at least target bytes are freed and
+// updates the size of the game directories.
+// Snapshots only speed up VM executions so can be safely removed, unlike the proofs and preimages.
+// Memory pages that are no longer referenced by any compact snapshot are removed along with the snapshots.
+// The games must not be inflight as snapshots may be in use by VM executions.
+func (d *diskManager) pruneSnapshots(dirs []*gameDir, target uint64) (uint64, error) {
+	var snapshots []snapshotFile
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir.path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || filepath.Base(filepath.Dir(path)) != vm.SnapsDir {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, snapshotFile{dir: dir, path: path, size: uint64(info.Size()), modTime: info.ModTime()})
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("failed to list snapshots for game %v: %w", dir.addr, err)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].modTime.Before(snapshots[j].modTime)
+	})
+	var freed uint64
+	defer func() {
+		d.m.RecordGameDataPruned(freed)
+	}()
+	for _, snapshot := range snapshots {
+		if freed >= target {
+			break
+		}
+		if err := os.Remove(snapshot.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return freed, fmt.Errorf("failed to remove snapshot %v: %w", snapshot.path, err)
+		}
+		size := snapshot.size
+		var err error
+		if versions.IsCompactSnapshot(snapshot.path) {
+			// Compact snapshots are small, most of their space is used by the memory pages.
+			// No VM is executing for the game, so there are no pages for snapshots still being written.
+			var pages uint64
+			pages, err = versions.RemoveUnreferencedPages(filepath.Dir(snapshot.path), time.Now())
+			size += pages
+		}
+		freed += size
+		snapshot.dir.size -= min(size, snapshot.dir.size)
+		d.sizes[snapshot.dir.addr] = gameSize{bytes: snapshot.dir.size}
+		if err != nil {
+			return freed, fmt.Errorf("failed to remove unreferenced snapshot pages for game %v: %w", snapshot.dir.addr, err)
+		}
+	}
+	return freed, nil
+}
+
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			// Files may be removed concurrently by the game player.
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
 }
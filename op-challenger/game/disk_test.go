@@ -1,18 +1,27 @@
 package game
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/versions"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/stretchr/testify/require"
 )
 
 func TestDiskManager_DirForGame(t *testing.T) {
 	baseDir := t.TempDir()
 	addr := common.Address{0x53}
-	disk := newDiskManager(baseDir)
+	disk := newTestDiskManager(t, baseDir, DiskLimits{})
 	result := disk.DirForGame(addr)
 	require.Equal(t, filepath.Join(baseDir, gameDirPrefix+addr.Hex()), result)
 }
@@ -21,7 +30,7 @@ func TestDiskManager_RemoveAllExcept(t *testing.T) {
 	baseDir := t.TempDir()
 	keep := common.Address{0x53}
 	delete := common.Address{0xaa}
-	disk := newDiskManager(baseDir)
+	disk := newTestDiskManager(t, baseDir, DiskLimits{})
 	keepDir := disk.DirForGame(keep)
 	deleteDir := disk.DirForGame(delete)
 
@@ -46,7 +55,7 @@ func TestDiskManager_RemoveAllExcept(t *testing.T) {
 	keepFiles := populateDir(keepDir)
 	populateDir(deleteDir)
 
-	require.NoError(t, disk.RemoveAllExcept([]common.Address{keep}))
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{keep}, nil))
 	require.NoDirExists(t, deleteDir, "should have deleted directory")
 	for _, file := range keepFiles {
 		require.FileExists(t, file, "should have kept file for active game")
@@ -55,3 +64,196 @@ func TestDiskManager_RemoveAllExcept(t *testing.T) {
 	require.DirExists(t, unexpectedDir, "should not delete unexpected dir")
 	require.DirExists(t, invalidHexDir, "should not delete dir with invalid address")
 }
+
+func TestDiskManager_ResolvedGameRetention(t *testing.T) {
+	baseDir := t.TempDir()
+	game := common.Address{0xaa}
+	disk, cl, m := newTestDiskManagerWithClock(t, baseDir, DiskLimits{ResolvedGameRetention: time.Hour})
+	dir := disk.DirForGame(game)
+	writeTestFile(t, filepath.Join(dir, "test.txt"), 100)
+
+	require.NoError(t, disk.RemoveAllExcept(nil, nil))
+	require.DirExists(t, dir, "should retain resolved game")
+	require.Equal(t, 1, m.games)
+	require.EqualValues(t, 100, m.bytes)
+
+	cl.AdvanceTime(59 * time.Minute)
+	require.NoError(t, disk.RemoveAllExcept(nil, nil))
+	require.DirExists(t, dir, "should retain resolved game until retention period ends")
+
+	cl.AdvanceTime(time.Minute)
+	require.NoError(t, disk.RemoveAllExcept(nil, nil))
+	require.NoDirExists(t, dir, "should remove resolved game after retention period")
+	require.Zero(t, m.games)
+	require.Zero(t, m.bytes)
+	require.EqualValues(t, 100, m.pruned)
+}
+
+func TestDiskManager_RetentionRestartsWhenGameActiveAgain(t *testing.T) {
+	baseDir := t.TempDir()
+	game := common.Address{0xaa}
+	disk, cl, _ := newTestDiskManagerWithClock(t, baseDir, DiskLimits{ResolvedGameRetention: time.Hour})
+	dir := disk.DirForGame(game)
+	writeTestFile(t, filepath.Join(dir, "test.txt"), 100)
+
+	require.NoError(t, disk.RemoveAllExcept(nil, nil))
+	cl.AdvanceTime(59 * time.Minute)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, nil))
+	cl.AdvanceTime(59 * time.Minute)
+	require.NoError(t, disk.RemoveAllExcept(nil, nil))
+	require.DirExists(t, dir)
+}
+
+func TestDiskManager_GameQuota(t *testing.T) {
+	baseDir := t.TempDir()
+	game := common.Address{0xaa}
+	disk, _, m := newTestDiskManagerWithClock(t, baseDir, DiskLimits{GameQuota: 250})
+	dir := disk.DirForGame(game)
+	proof := filepath.Join(dir, "proofs", "1.json.gz")
+	writeTestFile(t, proof, 100)
+	snapshots := filepath.Join(dir, "context", vm.SnapsDir)
+	oldest := filepath.Join(snapshots, "100.bin.gz")
+	newest := filepath.Join(snapshots, "200.bin.gz")
+	writeTestFile(t, oldest, 100)
+	writeTestFile(t, newest, 100)
+	require.NoError(t, os.Chtimes(oldest, time.Unix(1000, 0), time.Unix(1000, 0)))
+
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, nil))
+	require.NoFileExists(t, oldest, "should remove oldest snapshot")
+	require.FileExists(t, newest, "should keep newest snapshot once under quota")
+	require.FileExists(t, proof, "should not remove proofs")
+	require.EqualValues(t, 200, m.bytes)
+	require.EqualValues(t, 100, m.pruned)
+}
+
+func TestDiskManager_GameQuotaCompactSnapshots(t *testing.T) {
+	baseDir := t.TempDir()
+	game := common.Address{0xaa}
+	snapshots := filepath.Join(baseDir, gameDirPrefix+game.Hex(), "context", vm.SnapsDir)
+	writer := versions.NewCompactSnapshotWriter()
+	var paths []string
+	for _, step := range []uint64{100, 200} {
+		mtState := multithreaded.CreateEmptyState()
+		mtState.Step = step
+		mtState.Memory.SetMemory(0x1000, memory.Word(step))
+		state, err := versions.NewFromState(mtState)
+		require.NoError(t, err)
+		path := filepath.Join(snapshots, fmt.Sprintf("%d%v", step, versions.CompactSnapshotSuffix))
+		require.NoError(t, writer.Write(path, state))
+		paths = append(paths, path)
+	}
+	require.NoError(t, os.Chtimes(paths[0], time.Unix(1000, 0), time.Unix(1000, 0)))
+	packs := func() []string {
+		packs, err := filepath.Glob(filepath.Join(snapshots, versions.CompactSnapshotPagesDir, "*.pack"))
+		require.NoError(t, err)
+		return packs
+	}
+	require.Len(t, packs(), 2)
+	size, err := dirSize(baseDir)
+	require.NoError(t, err)
+
+	disk, _, m := newTestDiskManagerWithClock(t, baseDir, DiskLimits{GameQuota: size - 1})
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, nil))
+	require.NoFileExists(t, paths[0], "should remove oldest snapshot")
+	require.Len(t, packs(), 1, "should remove pages only referenced by the removed snapshot")
+	_, err = versions.LoadStateFromFile(paths[1])
+	require.NoError(t, err, "should keep pages of remaining snapshots")
+	remaining, err := dirSize(baseDir)
+	require.NoError(t, err)
+	require.Equal(t, size-remaining, m.pruned)
+}
+
+func TestDiskManager_TotalQuota(t *testing.T) {
+	baseDir := t.TempDir()
+	active := common.Address{0x01}
+	resolved1 := common.Address{0x02}
+	resolved2 := common.Address{0x03}
+	disk, cl, m := newTestDiskManagerWithClock(t, baseDir, DiskLimits{TotalQuota: 250, ResolvedGameRetention: time.Hour})
+	writeTestFile(t, filepath.Join(disk.DirForGame(active), vm.SnapsDir, "100.bin.gz"), 100)
+	writeTestFile(t, filepath.Join(disk.DirForGame(resolved1), "test.txt"), 100)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{active}, nil))
+	require.DirExists(t, disk.DirForGame(resolved1), "should retain resolved game while under quota")
+
+	cl.AdvanceTime(time.Minute)
+	writeTestFile(t, filepath.Join(disk.DirForGame(resolved2), "test.txt"), 100)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{active}, []common.Address{active}))
+	require.NoDirExists(t, disk.DirForGame(resolved1), "should remove earliest resolved game when over quota")
+	require.DirExists(t, disk.DirForGame(resolved2))
+	require.DirExists(t, disk.DirForGame(active))
+	require.Equal(t, 2, m.games)
+	require.EqualValues(t, 200, m.bytes)
+
+	// Once only active games remain, their snapshots are removed after the game is no longer inflight
+	writeTestFile(t, filepath.Join(disk.DirForGame(active), "proofs", "1.json.gz"), 200)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{active, resolved2}, nil))
+	require.NoFileExists(t, filepath.Join(disk.DirForGame(active), vm.SnapsDir, "100.bin.gz"))
+	require.EqualValues(t, 300, m.bytes)
+}
+
+func TestDiskManager_InflightGames(t *testing.T) {
+	baseDir := t.TempDir()
+	game := common.Address{0xaa}
+	disk, _, m := newTestDiskManagerWithClock(t, baseDir, DiskLimits{GameQuota: 150})
+	snapshot := filepath.Join(disk.DirForGame(game), vm.SnapsDir, "100.bin.gz")
+	writeTestFile(t, snapshot, 100)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, []common.Address{game}))
+	require.EqualValues(t, 100, m.bytes)
+
+	// Size of inflight games isn't measured again until they are no longer inflight
+	writeTestFile(t, filepath.Join(disk.DirForGame(game), "proofs", "1.json.gz"), 100)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, []common.Address{game}))
+	require.EqualValues(t, 100, m.bytes)
+	require.FileExists(t, snapshot)
+
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, nil))
+	require.NoFileExists(t, snapshot, "should remove snapshots once game is not inflight")
+	require.EqualValues(t, 100, m.bytes)
+	require.EqualValues(t, 100, m.pruned)
+
+	// Size of games that haven't been inflight is not measured again
+	writeTestFile(t, filepath.Join(disk.DirForGame(game), "unexpected.txt"), 100)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, nil))
+	require.EqualValues(t, 100, m.bytes)
+}
+
+func TestDiskManager_InflightGameExceedsQuota(t *testing.T) {
+	baseDir := t.TempDir()
+	game := common.Address{0xaa}
+	disk, _, m := newTestDiskManagerWithClock(t, baseDir, DiskLimits{GameQuota: 50, TotalQuota: 50})
+	snapshot := filepath.Join(disk.DirForGame(game), vm.SnapsDir, "100.bin.gz")
+	writeTestFile(t, snapshot, 100)
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{game}, []common.Address{game}))
+	require.FileExists(t, snapshot, "should not remove snapshots of inflight game")
+	require.Zero(t, m.pruned)
+}
+
+func writeTestFile(t *testing.T, path string, size int) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+}
+
+func newTestDiskManager(t *testing.T, dir string, limits DiskLimits) *diskManager {
+	disk, _, _ := newTestDiskManagerWithClock(t, dir, limits)
+	return disk
+}
+
+func newTestDiskManagerWithClock(t *testing.T, dir string, limits DiskLimits) (*diskManager, *clock.DeterministicClock, *stubDiskMetrics) {
+	cl := clock.NewDeterministicClock(time.Unix(10000, 0))
+	m := &stubDiskMetrics{}
+	return newDiskManager(testlog.Logger(t, log.LevelInfo), m, cl, dir, limits), cl, m
+}
+
+type stubDiskMetrics struct {
+	games  int
+	bytes  uint64
+	pruned uint64
+}
+
+func (s *stubDiskMetrics) RecordGameDataDisk(games int, bytes uint64) {
+	s.games = games
+	s.bytes = bytes
+}
+
+func (s *stubDiskMetrics) RecordGameDataPruned(bytes uint64) {
+	s.pruned += bytes
+}
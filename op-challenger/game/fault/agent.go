@@ -5,13 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/solver"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/resources"
 	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
 	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
 	"github.com/ethereum/go-ethereum/common"
@@ -33,6 +37,16 @@ type ClaimLoader interface {
 	IsL2BlockNumberChallenged(ctx context.Context, block rpcblock.Block) (bool, error)
 }
 
+// ActionDeferrer decides whether non-urgent actions, such as resolutions, should be delayed.
+type ActionDeferrer interface {
+	ShouldDefer(ctx context.Context, action sender.DeferrableAction) bool
+}
+
+// DivergenceDetector compares the claims in the game with the local trace and alerts when they disagree.
+type DivergenceDetector interface {
+	Check(ctx context.Context, game types.Game)
+}
+
 type Agent struct {
 	metrics          metrics.Metricer
 	systemClock      clock.Clock
@@ -42,9 +56,18 @@ type Agent struct {
 	responder        Responder
 	selective        bool
 	claimants        []common.Address
+	detector         DivergenceDetector
+	gameAddr         common.Address
+	deferrer         ActionDeferrer
 	maxDepth         types.Depth
 	maxClockDuration time.Duration
 	log              log.Logger
+
+	// detecting is set while a divergence check is running in the background
+	detecting atomic.Bool
+
+	inspectionLock sync.Mutex
+	inspection     *gameTypes.PlayerInspection
 }
 
 func NewAgent(
@@ -59,6 +82,9 @@ func NewAgent(
 	log log.Logger,
 	selective bool,
 	claimants []common.Address,
+	detector DivergenceDetector,
+	gameAddr common.Address,
+	deferrer ActionDeferrer,
 ) *Agent {
 	return &Agent{
 		metrics:          m,
@@ -69,6 +95,9 @@ func NewAgent(
 		responder:        responder,
 		selective:        selective,
 		claimants:        claimants,
+		detector:         detector,
+		gameAddr:         gameAddr,
+		deferrer:         deferrer,
 		maxDepth:         maxDepth,
 		maxClockDuration: maxClockDuration,
 		log:              log,
@@ -98,22 +127,104 @@ func (a *Agent) Act(ctx context.Context) error {
 		return fmt.Errorf("create game from contracts: %w", err)
 	}
 
-	actions, err := a.solver.CalculateNextActions(ctx, game)
-	if err != nil {
-		a.log.Error("Failed to calculate all required moves", "err", err)
+	// Prioritise VM executions for games with the least time left to respond
+	deadline, hasDeadline := a.responseDeadline(game)
+	if hasDeadline {
+		ctx = resources.WithDeadline(ctx, deadline)
+	}
+	if a.detector != nil {
+		a.checkDivergence(ctx, game)
+	}
+	actions, assessment, solverErr := a.solver.CalculateNextActionsAndAssessment(ctx, game)
+	if solverErr != nil {
+		a.log.Error("Failed to calculate all required moves", "err", solverErr)
 	}
 
 	var wg sync.WaitGroup
+	actionErrs := make([]error, len(actions))
 	wg.Add(len(actions))
-	for _, action := range actions {
-		go a.performAction(ctx, &wg, action)
+	for i, action := range actions {
+		i, action := i, action
+		go func() {
+			defer wg.Done()
+			actionErrs[i] = a.performAction(ctx, action)
+		}()
 	}
 	wg.Wait()
+	a.recordInspection(game, deadline, assessment, actions, actionErrs, solverErr)
 	return nil
 }
 
-func (a *Agent) performAction(ctx context.Context, wg *sync.WaitGroup, action types.Action) {
-	defer wg.Done()
+// checkDivergence checks the game for divergence in the background, so that fetching the local trace or sending
+// alerts doesn't delay responses to the game. No check is started while the previous check is still running, any
+// claims it skips are checked when the agent next acts.
+func (a *Agent) checkDivergence(ctx context.Context, game types.Game) {
+	if !a.detecting.CompareAndSwap(false, true) {
+		a.log.Debug("Skipping divergence check, previous check still running")
+		return
+	}
+	go func() {
+		defer a.detecting.Store(false)
+		a.detector.Check(ctx, game)
+	}()
+}
+
+// Inspect returns the claims, assessment and actions from the last time the agent calculated responses to the game.
+// Returns nil if responses haven't been calculated yet.
+func (a *Agent) Inspect() *gameTypes.PlayerInspection {
+	a.inspectionLock.Lock()
+	defer a.inspectionLock.Unlock()
+	return a.inspection
+}
+
+func (a *Agent) recordInspection(game types.Game, deadline time.Time, assessment *solver.ClaimAssessment, actions []types.Action, actionErrs []error, solverErr error) {
+	inspection := &gameTypes.PlayerInspection{
+		Updated:          a.systemClock.Now(),
+		Claims:           make([]gameTypes.ClaimInspection, 0, len(game.Claims())),
+		Moves:            make([]gameTypes.MoveInspection, 0, len(actions)),
+		ResponseDeadline: deadline,
+	}
+	if solverErr != nil {
+		inspection.Error = solverErr.Error()
+	}
+	for _, claim := range game.Claims() {
+		result := gameTypes.ClaimIgnored
+		if assessment.Honest(claim) {
+			result = gameTypes.ClaimHonest
+		} else if assessment.Countered(claim) {
+			result = gameTypes.ClaimDishonest
+		} else if solverErr != nil {
+			result = gameTypes.ClaimUnknown
+		}
+		inspection.Claims = append(inspection.Claims, gameTypes.ClaimInspection{
+			Index:        claim.ContractIndex,
+			ParentIndex:  claim.ParentContractIndex,
+			Depth:        uint64(claim.Depth()),
+			IndexAtDepth: claim.IndexAtDepth(),
+			Value:        claim.Value,
+			Claimant:     claim.Claimant,
+			CounteredBy:  claim.CounteredBy,
+			Assessment:   result,
+		})
+	}
+	for i, action := range actions {
+		move := gameTypes.MoveInspection{
+			Type:        action.Type.String(),
+			ParentIndex: action.ParentClaim.ContractIndex,
+			IsAttack:    action.IsAttack,
+			Value:       action.Value,
+		}
+		if actionErrs[i] != nil {
+			move.Error = actionErrs[i].Error()
+		}
+		inspection.Moves = append(inspection.Moves, move)
+	}
+	a.inspectionLock.Lock()
+	defer a.inspectionLock.Unlock()
+	a.inspection = inspection
+}
+
+func (a *Agent) performAction(ctx context.Context, action types.Action) error {
 	actionLog := a.log.New("action", action.Type)
 	if action.Type == types.ActionTypeStep {
 		containsOracleData := action.OracleData != nil
@@ -146,6 +257,7 @@ func (a *Agent) performAction(ctx context.Context, wg *sync.WaitGroup, action ty
 	if err != nil {
 		actionLog.Error("Action failed", "err", err)
 	}
+	return err
 }
 
 // tryResolve resolves the game if it is in a winning state
@@ -164,6 +276,10 @@ func (a *Agent) tryResolve(ctx context.Context) bool {
 	if err != nil || status == gameTypes.GameStatusInProgress {
 		return false
 	}
+	if a.shouldDefer(ctx, sender.ActionResolveGame, "") {
+		a.log.Debug("Deferring game resolution")
+		return true
+	}
 	a.log.Info("Resolving game")
 	if err := a.responder.Resolve(); err != nil {
 		a.log.Error("Failed to resolve the game", "err", err)
@@ -202,6 +318,10 @@ func (a *Agent) tryResolveClaims(ctx context.Context) error {
 		}
 		a.log.Trace("Checking if claim is resolvable", "claimIdx", claim.ContractIndex)
 		if err := a.responder.CallResolveClaim(ctx, uint64(claim.ContractIndex)); err == nil {
+			if a.shouldDefer(ctx, sender.ActionResolveClaim, strconv.Itoa(claim.ContractIndex)) {
+				a.log.Debug("Deferring claim resolution", "claimIdx", claim.ContractIndex)
+				continue
+			}
 			a.log.Info("Resolving claim", "claimIdx", claim.ContractIndex)
 			resolvableClaims = append(resolvableClaims, uint64(claim.ContractIndex))
 		}
@@ -235,6 +355,34 @@ func (a *Agent) resolveClaims(ctx context.Context) error {
 	}
 }
 
+func (a *Agent) shouldDefer(ctx context.Context, actionType sender.ActionType, detail string) bool {
+	if a.deferrer == nil {
+		return false
+	}
+	return a.deferrer.ShouldDefer(ctx, sender.DeferrableAction{Type: actionType, Game: a.gameAddr, Detail: detail})
+}
+
+// responseDeadline returns the earliest time a chess clock in the game expires.
+// Claims with expired clocks can no longer be countered so are ignored.
+func (a *Agent) responseDeadline(game types.Game) (time.Time, bool) {
+	now := a.l1Clock.Now()
+	var deadline time.Time
+	for _, claim := range game.Claims() {
+		var parent types.Claim
+		if !claim.IsRootPosition() {
+			parent = game.Claims()[claim.ParentContractIndex]
+		}
+		remaining := a.maxClockDuration - types.ChessClock(now, claim, parent)
+		if remaining <= 0 {
+			continue
+		}
+		if expiry := now.Add(remaining); deadline.IsZero() || expiry.Before(deadline) {
+			deadline = expiry
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
+
 // newGameFromContracts initializes a new game state from the state in the contract
 func (a *Agent) newGameFromContracts(ctx context.Context) (types.Game, error) {
 	claims, err := a.loader.GetAllClaims(ctx, rpcblock.Latest)
@@ -22,6 +22,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 )
 
@@ -153,6 +154,33 @@ func TestAgent_SelectiveClaimResolution(t *testing.T) {
 	}
 }
 
+func TestDeferNonUrgentActions(t *testing.T) {
+	gameAddr := common.Address{0xaa}
+	agent, claimLoader, responder := setupTestAgent(t)
+	deferrer := &stubDeferrer{deferred: map[sender.DeferrableAction]bool{
+		{Type: sender.ActionResolveClaim, Game: gameAddr, Detail: "1"}: true,
+		{Type: sender.ActionResolveGame, Game: gameAddr}:               true,
+	}}
+	agent.gameAddr = gameAddr
+	agent.deferrer = deferrer
+	depth := types.Depth(4)
+	claimBuilder := test.NewClaimBuilder(t, depth, alphabet.NewTraceProvider(big.NewInt(0), depth))
+	rootTime := l1Time.Add(-2 * agent.maxClockDuration)
+	gameBuilder := claimBuilder.GameBuilder(test.WithClock(rootTime, 0))
+	gameBuilder.Seq().
+		Attack(test.WithClock(rootTime, 0)).
+		Attack(test.WithClock(rootTime, 0))
+	claimLoader.claims = gameBuilder.Game.Claims()
+	claimLoader.maxLoads = 1
+	responder.callResolveStatus = gameTypes.GameStatusDefenderWon
+
+	require.NoError(t, agent.Act(context.Background()))
+
+	require.Equal(t, []uint64{0, 2}, responder.resolvedClaims, "should only resolve claims that are not deferred")
+	require.Zero(t, responder.resolveCount, "should defer resolving the game")
+	require.Contains(t, deferrer.checked, sender.DeferrableAction{Type: sender.ActionResolveGame, Game: gameAddr})
+}
+
 func TestSkipAttemptingToResolveClaimsWhenClockNotExpired(t *testing.T) {
 	agent, claimLoader, responder := setupTestAgent(t)
 	responder.callResolveErr = errors.New("game is not resolvable")
@@ -194,6 +222,101 @@ func TestLoadClaimsWhenGameNotResolvable(t *testing.T) {
 	require.Zero(t, responder.resolveClaimCount, "should not send resolveClaim")
 }
 
+func TestInspection(t *testing.T) {
+	agent, claimLoader, responder := setupTestAgent(t)
+	responder.callResolveErr = errors.New("game is not resolvable")
+	responder.callResolveClaimErr = errors.New("claim is not resolvable")
+	responder.performActionErr = errors.New("boom")
+	depth := types.Depth(4)
+	claimBuilder := test.NewClaimBuilder(t, depth, alphabet.NewTraceProvider(big.NewInt(0), depth))
+	require.Nil(t, agent.Inspect(), "should not have inspection before acting")
+
+	root := claimBuilder.CreateRootClaim(test.WithInvalidValue(true), test.WithClock(l1Time.Add(-time.Minute), 0))
+	claimLoader.claims = []types.Claim{root}
+	require.NoError(t, agent.Act(context.Background()))
+
+	inspection := agent.Inspect()
+	require.NotNil(t, inspection)
+	require.Empty(t, inspection.Error)
+	require.Equal(t, l1Time.Add(2*time.Minute), inspection.ResponseDeadline)
+	require.Equal(t, []gameTypes.ClaimInspection{{
+		Index:        root.ContractIndex,
+		ParentIndex:  root.ParentContractIndex,
+		Depth:        0,
+		IndexAtDepth: root.IndexAtDepth(),
+		Value:        root.Value,
+		Claimant:     root.Claimant,
+		Assessment:   gameTypes.ClaimDishonest,
+	}}, inspection.Claims)
+	require.Len(t, inspection.Moves, 1)
+	require.Equal(t, gameTypes.MoveInspection{
+		Type:        types.ActionTypeMove.String(),
+		ParentIndex: root.ContractIndex,
+		IsAttack:    true,
+		Value:       claimBuilder.CorrectClaimAtPosition(root.Position.Attack()),
+		Error:       "boom",
+	}, inspection.Moves[0])
+}
+
+func TestDivergenceCheckDoesNotBlockResponses(t *testing.T) {
+	agent, claimLoader, responder := setupTestAgent(t)
+	responder.callResolveErr = errors.New("game is not resolvable")
+	responder.callResolveClaimErr = errors.New("claim is not resolvable")
+	detector := &blockingDetector{started: make(chan struct{}, 2), release: make(chan struct{})}
+	agent.detector = detector
+	depth := types.Depth(4)
+	claimBuilder := test.NewClaimBuilder(t, depth, alphabet.NewTraceProvider(big.NewInt(0), depth))
+	claimLoader.claims = []types.Claim{claimBuilder.CreateRootClaim(test.WithInvalidValue(true), test.WithClock(l1Time.Add(-time.Minute), 0))}
+
+	require.NoError(t, agent.Act(context.Background()))
+	<-detector.started
+	require.Len(t, agent.Inspect().Moves, 1, "should respond while the divergence check is running")
+
+	require.NoError(t, agent.Act(context.Background()))
+	require.Len(t, detector.started, 0, "should not start a check while the previous check is running")
+
+	close(detector.release)
+	require.Eventually(t, func() bool {
+		return !agent.detecting.Load()
+	}, 10*time.Second, time.Millisecond)
+	require.NoError(t, agent.Act(context.Background()))
+	<-detector.started
+}
+
+type blockingDetector struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (d *blockingDetector) Check(_ context.Context, _ types.Game) {
+	d.started <- struct{}{}
+	<-d.release
+}
+
+func TestResponseDeadline(t *testing.T) {
+	agent, _, _ := setupTestAgent(t)
+	depth := types.Depth(4)
+	claimBuilder := test.NewClaimBuilder(t, depth, alphabet.NewTraceProvider(big.NewInt(0), depth))
+
+	t.Run("EarliestExpiry", func(t *testing.T) {
+		rootTime := l1Time.Add(-2 * time.Minute)
+		gameBuilder := claimBuilder.GameBuilder(test.WithClock(rootTime, 0))
+		gameBuilder.Seq().
+			Attack(test.WithClock(rootTime.Add(time.Minute), time.Minute))
+		deadline, ok := agent.responseDeadline(gameBuilder.Game)
+		require.True(t, ok)
+		// The root claim was posted 2 minutes ago so has 1 minute of its 3 minute clock left
+		require.Equal(t, l1Time.Add(time.Minute), deadline)
+	})
+
+	t.Run("AllExpired", func(t *testing.T) {
+		rootTime := l1Time.Add(-agent.maxClockDuration - time.Minute)
+		gameBuilder := claimBuilder.GameBuilder(test.WithClock(rootTime, 0))
+		_, ok := agent.responseDeadline(gameBuilder.Game)
+		require.False(t, ok)
+	})
+}
+
 func setupTestAgent(t *testing.T) (*Agent, *stubClaimLoader, *stubResponder) {
 	logger := testlog.Logger(t, log.LevelInfo)
 	claimLoader := &stubClaimLoader{}
@@ -203,10 +326,20 @@ func setupTestAgent(t *testing.T) (*Agent, *stubClaimLoader, *stubResponder) {
 	responder := &stubResponder{}
 	systemClock := clock.NewDeterministicClock(time.UnixMilli(120200))
 	l1Clock := clock.NewDeterministicClock(l1Time)
-	agent := NewAgent(metrics.NoopMetrics, systemClock, l1Clock, claimLoader, depth, gameDuration, trace.NewSimpleTraceAccessor(provider), responder, logger, false, []common.Address{})
+	agent := NewAgent(metrics.NoopMetrics, systemClock, l1Clock, claimLoader, depth, gameDuration, trace.NewSimpleTraceAccessor(provider), responder, logger, false, []common.Address{}, nil, common.Address{}, nil)
 	return agent, claimLoader, responder
 }
 
+type stubDeferrer struct {
+	deferred map[sender.DeferrableAction]bool
+	checked  []sender.DeferrableAction
+}
+
+func (s *stubDeferrer) ShouldDefer(_ context.Context, action sender.DeferrableAction) bool {
+	s.checked = append(s.checked, action)
+	return s.deferred[action]
+}
+
 type stubClaimLoader struct {
 	callCount          int
 	maxLoads           int
@@ -239,6 +372,8 @@ type stubResponder struct {
 	callResolveClaimErr   error
 	resolveClaimCount     int
 	resolvedClaims        []uint64
+
+	performActionErr error
 }
 
 func (s *stubResponder) CallResolve(_ context.Context) (gameTypes.GameStatus, error) {
@@ -274,5 +409,5 @@ func (s *stubResponder) ResolveClaims(claims ...uint64) error {
 }
 
 func (s *stubResponder) PerformAction(_ context.Context, _ types.Action) error {
-	return nil
+	return s.performActionErr
 }
@@ -7,14 +7,27 @@ import (
 	"math/big"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 )
 
 type TxSender interface {
-	SendAndWaitSimple(txPurpose string, txs ...txmgr.TxCandidate) error
+	SendAndWaitBatched(txPurpose string, txs ...txmgr.TxCandidate) []error
+}
+
+// CostEstimator estimates the cost in wei of sending a transaction.
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, tx txmgr.TxCandidate) (*big.Int, error)
+}
+
+// ActionDeferrer decides whether non-urgent actions, such as claiming credit, should be delayed.
+type ActionDeferrer interface {
+	ShouldDefer(ctx context.Context, action sender.DeferrableAction) bool
 }
 
 type BondClaimMetrics interface {
@@ -22,6 +35,7 @@ type BondClaimMetrics interface {
 }
 
 type BondContract interface {
+	GetAllClaims(ctx context.Context, block rpcblock.Block) ([]faultTypes.Claim, error)
 	GetCredit(ctx context.Context, recipient common.Address) (*big.Int, types.GameStatus, error)
 	ClaimCreditTx(ctx context.Context, recipient common.Address) (txmgr.TxCandidate, error)
 }
@@ -33,64 +47,145 @@ type Claimer struct {
 	metrics         BondClaimMetrics
 	contractCreator BondContractCreator
 	txSender        TxSender
+	costEstimator   CostEstimator
+	deferrer        ActionDeferrer
+	ledger          *BondLedger
 	claimants       []common.Address
 }
 
+// pendingClaim is a credit claim that is ready to be sent.
+type pendingClaim struct {
+	game   types.GameMetadata
+	addr   common.Address
+	credit *big.Int
+	tx     txmgr.TxCandidate
+}
+
 var _ BondClaimer = (*Claimer)(nil)
 
-func NewBondClaimer(l log.Logger, m BondClaimMetrics, contractCreator BondContractCreator, txSender TxSender, claimants ...common.Address) *Claimer {
+// NewBondClaimer creates a new Claimer. If costEstimator is not nil, credit is only claimed when it exceeds the
+// estimated cost of claiming it. If deferrer is not nil, it may delay claims while gas prices are high.
+// If ledger is not nil, it is updated with the bonds in each game.
+func NewBondClaimer(l log.Logger, m BondClaimMetrics, contractCreator BondContractCreator, txSender TxSender, costEstimator CostEstimator, deferrer ActionDeferrer, ledger *BondLedger, claimants ...common.Address) *Claimer {
 	return &Claimer{
 		logger:          l,
 		metrics:         m,
 		contractCreator: contractCreator,
 		txSender:        txSender,
+		costEstimator:   costEstimator,
+		deferrer:        deferrer,
+		ledger:          ledger,
 		claimants:       claimants,
 	}
 }
 
+// ClaimBonds claims the credit available to each claimant from the games.
+// Claims from all games are sent together so they can be batched into fewer transactions.
 func (c *Claimer) ClaimBonds(ctx context.Context, games []types.GameMetadata) (err error) {
+	if c.ledger != nil {
+		defer func() {
+			if saveErr := c.ledger.Save(); saveErr != nil {
+				err = errors.Join(err, saveErr)
+			}
+		}()
+	}
+	var pending []pendingClaim
 	for _, game := range games {
-		for _, claimant := range c.claimants {
-			err = errors.Join(err, c.claimBond(ctx, game, claimant))
+		claims, gameErr := c.prepareGameClaims(ctx, game)
+		err = errors.Join(err, gameErr)
+		pending = append(pending, claims...)
+	}
+	if len(pending) == 0 {
+		return err
+	}
+
+	txs := make([]txmgr.TxCandidate, len(pending))
+	for i, claim := range pending {
+		txs[i] = claim.tx
+	}
+	for i, sendErr := range c.txSender.SendAndWaitBatched("claim credit", txs...) {
+		claim := pending[i]
+		if sendErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to claim credit from game %v for %v: %w", claim.game.Proxy, claim.addr, sendErr))
+			continue
+		}
+		c.metrics.RecordBondClaimed(claim.credit.Uint64())
+		if c.ledger != nil {
+			c.ledger.RecordClaimed(claim.game.Proxy, claim.credit)
 		}
 	}
 	return err
 }
 
-func (c *Claimer) claimBond(ctx context.Context, game types.GameMetadata, addr common.Address) error {
-	c.logger.Debug("Attempting to claim bonds for", "game", game.Proxy, "addr", addr)
-
+// prepareGameClaims creates the credit claims for each claimant in the game and updates the ledger.
+func (c *Claimer) prepareGameClaims(ctx context.Context, game types.GameMetadata) (pending []pendingClaim, err error) {
 	contract, err := c.contractCreator(game)
 	if err != nil {
-		return fmt.Errorf("failed to create bond contract: %w", err)
+		return nil, fmt.Errorf("failed to create bond contract: %w", err)
 	}
-
-	credit, status, err := contract.GetCredit(ctx, addr)
-	if err != nil {
-		return fmt.Errorf("failed to get credit: %w", err)
+	claimable := new(big.Int)
+	status := types.GameStatusInProgress
+	for _, claimant := range c.claimants {
+		c.logger.Debug("Attempting to claim bonds for", "game", game.Proxy, "addr", claimant)
+		credit, creditStatus, creditErr := contract.GetCredit(ctx, claimant)
+		if creditErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to get credit: %w", creditErr))
+			continue
+		}
+		status = creditStatus
+		claimable.Add(claimable, credit)
+		claim, claimErr := c.prepareClaim(ctx, contract, game, claimant, credit, status)
+		if claimErr != nil {
+			err = errors.Join(err, claimErr)
+			continue
+		}
+		if claim != nil {
+			pending = append(pending, *claim)
+		}
+	}
+	if c.ledger != nil && err == nil {
+		claims, claimsErr := contract.GetAllClaims(ctx, rpcblock.Latest)
+		if claimsErr != nil {
+			return pending, fmt.Errorf("failed to load claims for bond ledger: %w", claimsErr)
+		}
+		c.ledger.UpdateGame(game.Proxy, status, claims, claimable)
 	}
+	return pending, err
+}
 
+// prepareClaim creates the transaction to claim credit for addr from the game.
+// Returns nil if there is no credit to claim or claiming it is not yet possible or profitable.
+func (c *Claimer) prepareClaim(ctx context.Context, contract BondContract, game types.GameMetadata, addr common.Address, credit *big.Int, status types.GameStatus) (*pendingClaim, error) {
 	if status == types.GameStatusInProgress {
 		c.logger.Debug("Not claiming credit from in progress game", "game", game.Proxy, "addr", addr, "status", status)
-		return nil
+		return nil, nil
 	}
 	if credit.Cmp(big.NewInt(0)) == 0 {
 		c.logger.Debug("No credit to claim", "game", game.Proxy, "addr", addr)
-		return nil
+		return nil, nil
 	}
 
 	candidate, err := contract.ClaimCreditTx(ctx, addr)
 	if errors.Is(err, contracts.ErrSimulationFailed) {
 		c.logger.Debug("Credit still locked", "game", game.Proxy, "addr", addr)
-		return nil
+		return nil, nil
 	} else if err != nil {
-		return fmt.Errorf("failed to create credit claim tx: %w", err)
+		return nil, fmt.Errorf("failed to create credit claim tx: %w", err)
 	}
 
-	if err = c.txSender.SendAndWaitSimple("claim credit", candidate); err != nil {
-		return fmt.Errorf("failed to claim credit: %w", err)
+	if c.costEstimator != nil {
+		cost, err := c.costEstimator.EstimateCost(ctx, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate cost of claiming credit: %w", err)
+		}
+		if cost.Cmp(credit) >= 0 {
+			c.logger.Info("Deferring unprofitable credit claim", "game", game.Proxy, "addr", addr, "credit", credit, "cost", cost)
+			return nil, nil
+		}
 	}
-
-	c.metrics.RecordBondClaimed(credit.Uint64())
-	return nil
+	if c.deferrer != nil && c.deferrer.ShouldDefer(ctx, sender.DeferrableAction{Type: sender.ActionClaimCredit, Game: game.Proxy, Detail: addr.Hex()}) {
+		c.logger.Debug("Deferring credit claim until gas price drops", "game", game.Proxy, "addr", addr)
+		return nil, nil
+	}
+	return &pendingClaim{game: game, addr: addr, credit: credit, tx: candidate}, nil
 }
@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
@@ -28,6 +32,7 @@ func TestClaimer_ClaimBonds(t *testing.T) {
 		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}, {Proxy: gameAddr}, {Proxy: gameAddr}})
 		require.NoError(t, err)
 		require.Equal(t, 3, txSender.sends)
+		require.Equal(t, 1, txSender.batches, "should send claims from all games together")
 		require.Equal(t, 3, m.RecordBondClaimedCalls)
 	})
 
@@ -99,6 +104,54 @@ func TestClaimer_ClaimBonds(t *testing.T) {
 		require.Equal(t, 0, m.RecordBondClaimedCalls)
 	})
 
+	t.Run("UnprofitableClaimDeferred", func(t *testing.T) {
+		claimant1 := common.Address{0xaa}
+		claimant2 := common.Address{0xbb}
+		gameAddr := common.HexToAddress("0x1234")
+		c, m, contract, txSender := newTestClaimer(t, claimant1, claimant2)
+		c.costEstimator = &stubCostEstimator{cost: big.NewInt(5)}
+		contract.credit[claimant1] = 5
+		contract.credit[claimant2] = 6
+		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.NoError(t, err)
+		require.Equal(t, 1, txSender.sends)
+		require.Equal(t, 1, m.RecordBondClaimedCalls)
+	})
+
+	t.Run("ClaimDeferredWhileGasPriceHigh", func(t *testing.T) {
+		claimant1 := common.Address{0xaa}
+		claimant2 := common.Address{0xbb}
+		gameAddr := common.HexToAddress("0x1234")
+		c, m, contract, txSender := newTestClaimer(t, claimant1, claimant2)
+		c.deferrer = &stubDeferrer{deferred: sender.DeferrableAction{Type: sender.ActionClaimCredit, Game: gameAddr, Detail: claimant1.Hex()}}
+		contract.credit[claimant1] = 5
+		contract.credit[claimant2] = 6
+		err := c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.NoError(t, err)
+		require.Equal(t, 1, txSender.sends)
+		require.Equal(t, 1, m.RecordBondClaimedCalls)
+	})
+
+	t.Run("UpdatesLedger", func(t *testing.T) {
+		gameAddr := common.HexToAddress("0x1234")
+		c, _, contract, txSender := newTestClaimer(t)
+		ledger, err := OpenBondLedger(&stubLedgerMetrics{}, filepath.Join(t.TempDir(), "ledger.json"), txSender.From())
+		require.NoError(t, err)
+		c.ledger = ledger
+		contract.credit[txSender.From()] = 7
+		contract.claims = []faultTypes.Claim{{ClaimData: faultTypes.ClaimData{Bond: big.NewInt(7)}, Claimant: txSender.From()}}
+		err = c.ClaimBonds(context.Background(), []types.GameMetadata{{Proxy: gameAddr}})
+		require.NoError(t, err)
+		report := ledger.Report()
+		require.Equal(t, big.NewInt(7), report.Posted)
+		require.Equal(t, big.NewInt(7), report.Claimed)
+		require.Equal(t, big.NewInt(0), report.Claimable)
+
+		reloaded, err := OpenBondLedger(&stubLedgerMetrics{}, ledger.path, txSender.From())
+		require.NoError(t, err)
+		require.Equal(t, report, reloaded.Report())
+	})
+
 	t.Run("MultipleBondClaimFails", func(t *testing.T) {
 		gameAddr := common.HexToAddress("0x1234")
 		c, m, contract, txSender := newTestClaimer(t)
@@ -122,7 +175,7 @@ func newTestClaimer(t *testing.T, claimants ...common.Address) (*Claimer, *mockC
 	if len(claimants) == 0 {
 		claimants = []common.Address{txSender.From()}
 	}
-	c := NewBondClaimer(logger, m, contractCreator, txSender, claimants...)
+	c := NewBondClaimer(logger, m, contractCreator, txSender, nil, nil, nil, claimants...)
 	return c, m, bondContract, txSender
 }
 
@@ -135,6 +188,7 @@ func (m *mockClaimMetrics) RecordBondClaimed(amount uint64) {
 }
 
 type mockTxSender struct {
+	batches    int
 	sends      int
 	sendFails  bool
 	statusFail bool
@@ -144,23 +198,31 @@ func (s *mockTxSender) From() common.Address {
 	return common.HexToAddress("0x33333")
 }
 
-func (s *mockTxSender) SendAndWaitSimple(_ string, _ ...txmgr.TxCandidate) error {
-	s.sends++
-	if s.sendFails {
-		return mockTxMgrSendError
+func (s *mockTxSender) SendAndWaitBatched(_ string, txs ...txmgr.TxCandidate) []error {
+	s.batches++
+	errs := make([]error, len(txs))
+	for i := range txs {
+		s.sends++
+		if s.sendFails {
+			errs[i] = mockTxMgrSendError
+		} else if s.statusFail {
+			errs[i] = errors.New("transaction reverted")
+		}
 	}
-	if s.statusFail {
-		return errors.New("transaction reverted")
-	}
-	return nil
+	return errs
 }
 
 type stubBondContract struct {
+	claims               []faultTypes.Claim
 	credit               map[common.Address]int64
 	status               types.GameStatus
 	claimSimulationFails bool
 }
 
+func (s *stubBondContract) GetAllClaims(_ context.Context, _ rpcblock.Block) ([]faultTypes.Claim, error) {
+	return s.claims, nil
+}
+
 func (s *stubBondContract) GetCredit(_ context.Context, addr common.Address) (*big.Int, types.GameStatus, error) {
 	return big.NewInt(s.credit[addr]), s.status, nil
 }
@@ -171,3 +233,19 @@ func (s *stubBondContract) ClaimCreditTx(_ context.Context, _ common.Address) (t
 	}
 	return txmgr.TxCandidate{}, nil
 }
+
+type stubCostEstimator struct {
+	cost *big.Int
+}
+
+func (s *stubCostEstimator) EstimateCost(_ context.Context, _ txmgr.TxCandidate) (*big.Int, error) {
+	return s.cost, nil
+}
+
+type stubDeferrer struct {
+	deferred sender.DeferrableAction
+}
+
+func (s *stubDeferrer) ShouldDefer(_ context.Context, action sender.DeferrableAction) bool {
+	return action == s.deferred
+}
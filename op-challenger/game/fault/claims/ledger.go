@@ -0,0 +1,292 @@
+package claims
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type LedgerMetrics interface {
+	RecordBondLedger(posted, atRisk, claimable, claimed *big.Int)
+}
+
+// GameBonds is the bond accounting for a single game.
+type GameBonds struct {
+	Game   common.Address   `json:"game"`
+	Status types.GameStatus `json:"status"`
+	// Posted is the total bonds posted by the claimants.
+	Posted *big.Int `json:"posted"`
+	// AtRisk is the total bonds posted by the claimants on claims that are currently countered
+	// in a game that is still in progress.
+	AtRisk *big.Int `json:"atRisk"`
+	// Claimable is the credit the claimants can currently claim from the game.
+	Claimable *big.Int `json:"claimable"`
+	// Claimed is the total credit claimed from the game.
+	Claimed *big.Int `json:"claimed"`
+	// Counterparties is the bonds at stake against each other party in the game.
+	Counterparties map[common.Address]*CounterpartyBonds `json:"counterparties,omitempty"`
+}
+
+// CounterpartyBonds is the bonds at stake between the claimants and another party.
+type CounterpartyBonds struct {
+	// AtRisk is the bonds posted by the claimants on claims currently countered by the counterparty.
+	AtRisk *big.Int `json:"atRisk"`
+	// Contested is the bonds posted by the counterparty on claims currently countered by the claimants.
+	Contested *big.Int `json:"contested"`
+}
+
+// BondReport summarises the bond accounting across all games.
+type BondReport struct {
+	Posted         *big.Int                              `json:"posted"`
+	AtRisk         *big.Int                              `json:"atRisk"`
+	Claimable      *big.Int                              `json:"claimable"`
+	Claimed        *big.Int                              `json:"claimed"`
+	Counterparties map[common.Address]*CounterpartyBonds `json:"counterparties"`
+	Games          []GameBonds                           `json:"games"`
+}
+
+type ledgerData struct {
+	Games []*GameBonds `json:"games"`
+}
+
+// BondLedger tracks the bonds posted, at risk, claimable and claimed by the claimants in each game.
+// The ledger is persisted so the total claimed survives restarts.
+type BondLedger struct {
+	metrics   LedgerMetrics
+	path      string
+	claimants []common.Address
+
+	lock  sync.Mutex
+	games map[common.Address]*GameBonds
+}
+
+// OpenBondLedger loads the ledger at path, creating an empty ledger if the file does not exist.
+func OpenBondLedger(m LedgerMetrics, path string, claimants ...common.Address) (*BondLedger, error) {
+	l := &BondLedger{
+		metrics:   m,
+		path:      path,
+		claimants: claimants,
+		games:     make(map[common.Address]*GameBonds),
+	}
+	data, err := jsonutil.LoadJSON[ledgerData](path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load bond ledger: %w", err)
+	}
+	for _, game := range data.Games {
+		l.games[game.Game] = game
+	}
+	l.recordMetrics()
+	return l, nil
+}
+
+// UpdateGame updates the bonds posted and at risk in the game from its current claims and
+// the credit currently claimable by the claimants.
+func (l *BondLedger) UpdateGame(game common.Address, status types.GameStatus, claims []faultTypes.Claim, claimable *big.Int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	entry := l.entry(game)
+	entry.Status = status
+	entry.Posted = new(big.Int)
+	entry.AtRisk = new(big.Int)
+	entry.Claimable = new(big.Int).Set(claimable)
+	entry.Counterparties = make(map[common.Address]*CounterpartyBonds)
+
+	winners := uncounteredChildren(claims)
+	for i, claim := range claims {
+		ours := l.isClaimant(claim.Claimant)
+		if ours {
+			entry.Posted.Add(entry.Posted, bondOf(claim))
+		}
+		if status != types.GameStatusInProgress {
+			continue
+		}
+		winner, countered := winners[i]
+		if !countered || ours == l.isClaimant(winner) {
+			continue
+		}
+		if ours {
+			entry.AtRisk.Add(entry.AtRisk, bondOf(claim))
+			bonds := l.counterparty(entry, winner)
+			bonds.AtRisk.Add(bonds.AtRisk, bondOf(claim))
+		} else {
+			bonds := l.counterparty(entry, claim.Claimant)
+			bonds.Contested.Add(bonds.Contested, bondOf(claim))
+		}
+	}
+	l.recordMetrics()
+}
+
+// RecordClaimed records that credit was claimed from the game.
+func (l *BondLedger) RecordClaimed(game common.Address, amount *big.Int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	entry := l.entry(game)
+	entry.Claimed.Add(entry.Claimed, amount)
+	entry.Claimable.Sub(entry.Claimable, amount)
+	if entry.Claimable.Sign() < 0 {
+		entry.Claimable.SetUint64(0)
+	}
+	l.recordMetrics()
+}
+
+// Save persists the ledger.
+func (l *BondLedger) Save() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create bond ledger dir: %w", err)
+	}
+	data := ledgerData{Games: make([]*GameBonds, 0, len(l.games))}
+	for _, game := range l.sortedGames() {
+		data.Games = append(data.Games, l.games[game])
+	}
+	if err := jsonutil.WriteJSON(data, ioutil.ToAtomicFile(l.path, 0o644)); err != nil {
+		return fmt.Errorf("failed to write bond ledger: %w", err)
+	}
+	return nil
+}
+
+// Report returns the bond accounting for all games, with totals across games.
+func (l *BondLedger) Report() BondReport {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	report := BondReport{
+		Posted:         new(big.Int),
+		AtRisk:         new(big.Int),
+		Claimable:      new(big.Int),
+		Claimed:        new(big.Int),
+		Counterparties: make(map[common.Address]*CounterpartyBonds),
+		Games:          make([]GameBonds, 0, len(l.games)),
+	}
+	for _, addr := range l.sortedGames() {
+		game := l.games[addr]
+		report.Posted.Add(report.Posted, game.Posted)
+		report.AtRisk.Add(report.AtRisk, game.AtRisk)
+		report.Claimable.Add(report.Claimable, game.Claimable)
+		report.Claimed.Add(report.Claimed, game.Claimed)
+		for party, bonds := range game.Counterparties {
+			total, ok := report.Counterparties[party]
+			if !ok {
+				total = &CounterpartyBonds{AtRisk: new(big.Int), Contested: new(big.Int)}
+				report.Counterparties[party] = total
+			}
+			total.AtRisk.Add(total.AtRisk, bonds.AtRisk)
+			total.Contested.Add(total.Contested, bonds.Contested)
+		}
+		report.Games = append(report.Games, copyGameBonds(game))
+	}
+	return report
+}
+
+// recordMetrics reports the totals across all games. Must be called with the lock held.
+func (l *BondLedger) recordMetrics() {
+	posted, atRisk, claimable, claimed := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	for _, game := range l.games {
+		posted.Add(posted, game.Posted)
+		atRisk.Add(atRisk, game.AtRisk)
+		claimable.Add(claimable, game.Claimable)
+		claimed.Add(claimed, game.Claimed)
+	}
+	l.metrics.RecordBondLedger(posted, atRisk, claimable, claimed)
+}
+
+func (l *BondLedger) entry(game common.Address) *GameBonds {
+	entry, ok := l.games[game]
+	if !ok {
+		entry = &GameBonds{
+			Game:      game,
+			Posted:    new(big.Int),
+			AtRisk:    new(big.Int),
+			Claimable: new(big.Int),
+			Claimed:   new(big.Int),
+		}
+		l.games[game] = entry
+	}
+	return entry
+}
+
+func (l *BondLedger) counterparty(entry *GameBonds, party common.Address) *CounterpartyBonds {
+	bonds, ok := entry.Counterparties[party]
+	if !ok {
+		bonds = &CounterpartyBonds{AtRisk: new(big.Int), Contested: new(big.Int)}
+		entry.Counterparties[party] = bonds
+	}
+	return bonds
+}
+
+func (l *BondLedger) isClaimant(addr common.Address) bool {
+	return slices.Contains(l.claimants, addr)
+}
+
+func (l *BondLedger) sortedGames() []common.Address {
+	addrs := make([]common.Address, 0, len(l.games))
+	for addr := range l.games {
+		addrs = append(addrs, addr)
+	}
+	slices.SortFunc(addrs, func(a, b common.Address) int {
+		return a.Cmp(b)
+	})
+	return addrs
+}
+
+// uncounteredChildren returns, for each countered claim, the claimant of the child that currently counters it.
+// A claim is countered if it has a child that is not itself countered, or was countered by a step.
+// Children always have a higher index than their parent, so claims are processed in reverse order.
+func uncounteredChildren(claims []faultTypes.Claim) map[int]common.Address {
+	winners := make(map[int]common.Address)
+	for i := len(claims) - 1; i >= 0; i-- {
+		claim := claims[i]
+		if _, countered := winners[i]; !countered && claim.CounteredBy != (common.Address{}) {
+			winners[i] = claim.CounteredBy
+		}
+		if claim.IsRoot() {
+			continue
+		}
+		if _, countered := winners[i]; !countered {
+			// The leftmost uncountered child receives the parent's bond, so keep the lowest index child.
+			winners[claim.ParentContractIndex] = claim.Claimant
+		}
+	}
+	return winners
+}
+
+func bondOf(claim faultTypes.Claim) *big.Int {
+	if claim.Bond == nil {
+		return new(big.Int)
+	}
+	return claim.Bond
+}
+
+func copyGameBonds(game *GameBonds) GameBonds {
+	cp := GameBonds{
+		Game:      game.Game,
+		Status:    game.Status,
+		Posted:    new(big.Int).Set(game.Posted),
+		AtRisk:    new(big.Int).Set(game.AtRisk),
+		Claimable: new(big.Int).Set(game.Claimable),
+		Claimed:   new(big.Int).Set(game.Claimed),
+	}
+	if len(game.Counterparties) > 0 {
+		cp.Counterparties = make(map[common.Address]*CounterpartyBonds, len(game.Counterparties))
+		for party, bonds := range game.Counterparties {
+			cp.Counterparties[party] = &CounterpartyBonds{
+				AtRisk:    new(big.Int).Set(bonds.AtRisk),
+				Contested: new(big.Int).Set(bonds.Contested),
+			}
+		}
+	}
+	return cp
+}
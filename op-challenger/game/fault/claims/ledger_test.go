@@ -0,0 +1,96 @@
+package claims
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBondLedger_UpdateGame(t *testing.T) {
+	us := common.Address{0xaa}
+	them := common.Address{0xbb}
+	game := common.Address{0x01}
+	claim := func(parent int, claimant common.Address, bond int64) faultTypes.Claim {
+		return faultTypes.Claim{
+			ClaimData:           faultTypes.ClaimData{Bond: big.NewInt(bond), Position: faultTypes.NewPositionFromGIndex(big.NewInt(2))},
+			Claimant:            claimant,
+			ParentContractIndex: parent,
+		}
+	}
+	root := faultTypes.Claim{
+		ClaimData: faultTypes.ClaimData{Bond: big.NewInt(100), Position: faultTypes.RootPosition},
+		Claimant:  them,
+	}
+
+	t.Run("InProgress", func(t *testing.T) {
+		metrics := &stubLedgerMetrics{}
+		ledger, err := OpenBondLedger(metrics, filepath.Join(t.TempDir(), "ledger.json"), us)
+		require.NoError(t, err)
+		// We counter the root, they counter us, then we counter them again.
+		// The root and our second claim win, while their claim and our first claim are countered.
+		claims := []faultTypes.Claim{root, claim(0, us, 10), claim(1, them, 20), claim(2, us, 30)}
+		ledger.UpdateGame(game, types.GameStatusInProgress, claims, big.NewInt(0))
+
+		report := ledger.Report()
+		require.Equal(t, big.NewInt(40), report.Posted)
+		require.Equal(t, big.NewInt(0), report.AtRisk)
+		require.Equal(t, big.NewInt(0), report.Claimable)
+		require.Equal(t, big.NewInt(120), report.Counterparties[them].Contested)
+		require.Equal(t, big.NewInt(0), report.Counterparties[them].AtRisk)
+		require.Equal(t, big.NewInt(40), metrics.posted)
+
+		// They counter our last claim so our first two claims are now countered
+		claims = append(claims, claim(3, them, 40))
+		ledger.UpdateGame(game, types.GameStatusInProgress, claims, big.NewInt(0))
+		report = ledger.Report()
+		require.Equal(t, big.NewInt(40), report.AtRisk)
+		require.Equal(t, big.NewInt(40), report.Counterparties[them].AtRisk)
+		require.Equal(t, big.NewInt(0), report.Counterparties[them].Contested)
+		require.Equal(t, big.NewInt(40), metrics.atRisk)
+	})
+
+	t.Run("Resolved", func(t *testing.T) {
+		ledger, err := OpenBondLedger(&stubLedgerMetrics{}, filepath.Join(t.TempDir(), "ledger.json"), us)
+		require.NoError(t, err)
+		claims := []faultTypes.Claim{root, claim(0, us, 10), claim(1, them, 20)}
+		ledger.UpdateGame(game, types.GameStatusDefenderWon, claims, big.NewInt(5))
+		report := ledger.Report()
+		require.Equal(t, big.NewInt(10), report.Posted)
+		require.Equal(t, big.NewInt(0), report.AtRisk, "no bonds at risk once resolved")
+		require.Equal(t, big.NewInt(5), report.Claimable)
+		require.Empty(t, report.Counterparties)
+	})
+}
+
+func TestBondLedger_Persistence(t *testing.T) {
+	us := common.Address{0xaa}
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	ledger, err := OpenBondLedger(&stubLedgerMetrics{}, path, us)
+	require.NoError(t, err)
+	ledger.UpdateGame(common.Address{0x01}, types.GameStatusChallengerWon, nil, big.NewInt(15))
+	ledger.RecordClaimed(common.Address{0x01}, big.NewInt(10))
+	ledger.RecordClaimed(common.Address{0x02}, big.NewInt(3))
+	require.NoError(t, ledger.Save())
+
+	metrics := &stubLedgerMetrics{}
+	reloaded, err := OpenBondLedger(metrics, path, us)
+	require.NoError(t, err)
+	report := reloaded.Report()
+	require.Equal(t, big.NewInt(13), report.Claimed)
+	require.Equal(t, big.NewInt(5), report.Claimable)
+	require.Len(t, report.Games, 2)
+	require.Equal(t, big.NewInt(13), metrics.claimed, "should report metrics on load")
+}
+
+type stubLedgerMetrics struct {
+	posted, atRisk, claimable, claimed *big.Int
+}
+
+func (s *stubLedgerMetrics) RecordBondLedger(posted, atRisk, claimable, claimed *big.Int) {
+	s.posted, s.atRisk, s.claimable, s.claimed = posted, atRisk, claimable, claimed
+}
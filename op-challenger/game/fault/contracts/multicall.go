@@ -0,0 +1,62 @@
+package contracts
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed abis/Multicall3.json
+var multicall3Abi []byte
+
+var methodAggregate3 = "aggregate3"
+
+var ErrEmptyBatch = errors.New("no calls to batch")
+
+// Multicall3 combines multiple calls into a single transaction via the Multicall3 contract.
+// Calls are made with the Multicall3 contract as msg.sender so only calls that don't depend on the sender,
+// such as resolving claims and claiming credit for a recipient, can be batched.
+type Multicall3 struct {
+	contract *batching.BoundContract
+}
+
+// call3 matches the Multicall3.Call3 struct.
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+func NewMulticall3(addr common.Address) *Multicall3 {
+	return &Multicall3{
+		contract: batching.NewBoundContract(mustParseAbi(multicall3Abi), addr),
+	}
+}
+
+func (m *Multicall3) Addr() common.Address {
+	return m.contract.Addr()
+}
+
+// AggregateTx creates a transaction that executes all the supplied calls in order.
+// If any call fails, the entire transaction reverts.
+func (m *Multicall3) AggregateTx(txs ...txmgr.TxCandidate) (txmgr.TxCandidate, error) {
+	if len(txs) == 0 {
+		return txmgr.TxCandidate{}, ErrEmptyBatch
+	}
+	calls := make([]call3, 0, len(txs))
+	for i, tx := range txs {
+		if tx.To == nil {
+			return txmgr.TxCandidate{}, fmt.Errorf("call %v has no target", i)
+		}
+		if tx.Value != nil && tx.Value.Cmp(big.NewInt(0)) != 0 {
+			return txmgr.TxCandidate{}, fmt.Errorf("call %v to %v sends value", i, tx.To)
+		}
+		calls = append(calls, call3{Target: *tx.To, CallData: tx.TxData})
+	}
+	return m.contract.Call(methodAggregate3, calls).ToTxCandidate()
+}
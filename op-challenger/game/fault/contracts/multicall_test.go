@@ -0,0 +1,58 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var multicallAddr = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+func TestMulticall3_AggregateTx(t *testing.T) {
+	multicall := NewMulticall3(multicallAddr)
+	target1 := common.Address{0xaa}
+	target2 := common.Address{0xbb}
+
+	t.Run("CombinesCalls", func(t *testing.T) {
+		tx, err := multicall.AggregateTx(
+			txmgr.TxCandidate{To: &target1, TxData: []byte{0x01, 0x02}},
+			txmgr.TxCandidate{To: &target2, TxData: []byte{0x03}, Value: big.NewInt(0)},
+		)
+		require.NoError(t, err)
+		require.Equal(t, multicallAddr, *tx.To)
+
+		method := mustParseAbi(multicall3Abi).Methods[methodAggregate3]
+		require.Equal(t, method.ID, tx.TxData[:4])
+		args, err := method.Inputs.Unpack(tx.TxData[4:])
+		require.NoError(t, err)
+		calls := args[0].([]struct {
+			Target       common.Address `json:"target"`
+			AllowFailure bool           `json:"allowFailure"`
+			CallData     []byte         `json:"callData"`
+		})
+		require.Len(t, calls, 2)
+		require.Equal(t, target1, calls[0].Target)
+		require.Equal(t, []byte{0x01, 0x02}, calls[0].CallData)
+		require.False(t, calls[0].AllowFailure)
+		require.Equal(t, target2, calls[1].Target)
+		require.Equal(t, []byte{0x03}, calls[1].CallData)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := multicall.AggregateTx()
+		require.ErrorIs(t, err, ErrEmptyBatch)
+	})
+
+	t.Run("RejectValue", func(t *testing.T) {
+		_, err := multicall.AggregateTx(txmgr.TxCandidate{To: &target1, Value: big.NewInt(1)})
+		require.ErrorContains(t, err, "sends value")
+	})
+
+	t.Run("RejectContractCreation", func(t *testing.T) {
+		_, err := multicall.AggregateTx(txmgr.TxCandidate{TxData: []byte{0x01}})
+		require.ErrorContains(t, err, "no target")
+	})
+}
@@ -0,0 +1,129 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
+	"github.com/ethereum-optimism/optimism/packages/contracts-bedrock/snapshots"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	methodProvenWithdrawals    = "provenWithdrawals"
+	methodDisputeGameBlacklist = "disputeGameBlacklist"
+	eventWithdrawalProvenExt1  = "WithdrawalProvenExtension1"
+)
+
+type OptimismPortalContract struct {
+	metrics     metrics.ContractMetricer
+	abi         *abi.ABI
+	multiCaller *batching.MultiCaller
+	contract    *batching.BoundContract
+}
+
+// WithdrawalProof identifies a single proof of a withdrawal. A withdrawal may be proven by multiple submitters.
+type WithdrawalProof struct {
+	WithdrawalHash common.Hash
+	ProofSubmitter common.Address
+}
+
+// ProvenWithdrawal records the dispute game a withdrawal proof was made against.
+type ProvenWithdrawal struct {
+	WithdrawalProof
+	DisputeGame common.Address
+	Timestamp   uint64
+}
+
+func NewOptimismPortalContract(metrics metrics.ContractMetricer, addr common.Address, caller *batching.MultiCaller) *OptimismPortalContract {
+	contractAbi := snapshots.LoadOptimismPortal2ABI()
+	return &OptimismPortalContract{
+		metrics:     metrics,
+		abi:         contractAbi,
+		multiCaller: caller,
+		contract:    batching.NewBoundContract(contractAbi, addr),
+	}
+}
+
+func (p *OptimismPortalContract) Addr() common.Address {
+	return p.contract.Addr()
+}
+
+// WithdrawalProofsQuery returns the log filter that finds withdrawal proofs made between fromBlock and toBlock inclusive.
+func (p *OptimismPortalContract) WithdrawalProofsQuery(fromBlock uint64, toBlock uint64) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{p.contract.Addr()},
+		Topics:    [][]common.Hash{{p.abi.Events[eventWithdrawalProvenExt1].ID}},
+	}
+}
+
+// DecodeWithdrawalProofs returns the withdrawal proofs recorded in the supplied logs.
+// Logs from other contracts or for other events are ignored.
+func (p *OptimismPortalContract) DecodeWithdrawalProofs(logs []ethTypes.Log) []WithdrawalProof {
+	var proofs []WithdrawalProof
+	for _, log := range logs {
+		log := log
+		if log.Address != p.contract.Addr() {
+			// Not from this contract
+			continue
+		}
+		name, result, err := p.contract.DecodeEvent(&log)
+		if err != nil || name != eventWithdrawalProvenExt1 {
+			// Not the event we're looking for
+			continue
+		}
+		proofs = append(proofs, WithdrawalProof{
+			WithdrawalHash: result.GetHash(0),
+			ProofSubmitter: result.GetAddress(1),
+		})
+	}
+	return proofs
+}
+
+// GetProvenWithdrawals returns the dispute game and timestamp each of the supplied withdrawal proofs was made against.
+func (p *OptimismPortalContract) GetProvenWithdrawals(ctx context.Context, block rpcblock.Block, proofs ...WithdrawalProof) ([]*ProvenWithdrawal, error) {
+	defer p.metrics.StartContractRequest("GetProvenWithdrawals")()
+	calls := make([]batching.Call, 0, len(proofs))
+	for _, proof := range proofs {
+		calls = append(calls, p.contract.Call(methodProvenWithdrawals, proof.WithdrawalHash, proof.ProofSubmitter))
+	}
+	results, err := p.multiCaller.Call(ctx, block, calls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch proven withdrawals: %w", err)
+	}
+	proven := make([]*ProvenWithdrawal, len(proofs))
+	for i, result := range results {
+		proven[i] = &ProvenWithdrawal{
+			WithdrawalProof: proofs[i],
+			DisputeGame:     result.GetAddress(0),
+			Timestamp:       result.GetUint64(1),
+		}
+	}
+	return proven, nil
+}
+
+// GetBlacklisted returns whether each of the supplied games has been blacklisted.
+func (p *OptimismPortalContract) GetBlacklisted(ctx context.Context, block rpcblock.Block, games ...common.Address) ([]bool, error) {
+	defer p.metrics.StartContractRequest("GetBlacklisted")()
+	calls := make([]batching.Call, 0, len(games))
+	for _, game := range games {
+		calls = append(calls, p.contract.Call(methodDisputeGameBlacklist, game))
+	}
+	results, err := p.multiCaller.Call(ctx, block, calls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dispute game blacklist: %w", err)
+	}
+	blacklisted := make([]bool, len(games))
+	for i, result := range results {
+		blacklisted[i] = result.GetBool(0)
+	}
+	return blacklisted, nil
+}
@@ -0,0 +1,109 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	contractMetrics "github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
+	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
+	"github.com/ethereum-optimism/optimism/packages/contracts-bedrock/snapshots"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	portalAddr = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+)
+
+func TestOptimismPortal_GetProvenWithdrawals(t *testing.T) {
+	stubRpc, portal := setupOptimismPortalTest(t)
+	block := rpcblock.ByNumber(482)
+
+	proofs := []WithdrawalProof{
+		{WithdrawalHash: common.Hash{0x01}, ProofSubmitter: common.Address{0xaa}},
+		{WithdrawalHash: common.Hash{0x02}, ProofSubmitter: common.Address{0xbb}},
+	}
+	games := []common.Address{{0x11}, {0x22}}
+	for i, proof := range proofs {
+		stubRpc.SetResponse(portalAddr, methodProvenWithdrawals, block,
+			[]interface{}{proof.WithdrawalHash, proof.ProofSubmitter},
+			[]interface{}{games[i], uint64(1000 + i)})
+	}
+
+	actual, err := portal.GetProvenWithdrawals(context.Background(), block, proofs...)
+	require.NoError(t, err)
+	require.Len(t, actual, len(proofs))
+	for i, proven := range actual {
+		require.Equal(t, proofs[i], proven.WithdrawalProof)
+		require.Equal(t, games[i], proven.DisputeGame)
+		require.Equal(t, uint64(1000+i), proven.Timestamp)
+	}
+}
+
+func TestOptimismPortal_GetBlacklisted(t *testing.T) {
+	stubRpc, portal := setupOptimismPortalTest(t)
+	block := rpcblock.ByNumber(482)
+	games := []common.Address{{0x11}, {0x22}}
+	stubRpc.SetResponse(portalAddr, methodDisputeGameBlacklist, block, []interface{}{games[0]}, []interface{}{false})
+	stubRpc.SetResponse(portalAddr, methodDisputeGameBlacklist, block, []interface{}{games[1]}, []interface{}{true})
+
+	actual, err := portal.GetBlacklisted(context.Background(), block, games...)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true}, actual)
+}
+
+func TestOptimismPortal_DecodeWithdrawalProofs(t *testing.T) {
+	_, portal := setupOptimismPortalTest(t)
+	portalAbi := snapshots.LoadOptimismPortal2ABI()
+	proof := WithdrawalProof{WithdrawalHash: common.Hash{0xab}, ProofSubmitter: common.Address{0xcd}}
+	validLog := ethTypes.Log{
+		Address: portalAddr,
+		Topics: []common.Hash{
+			portalAbi.Events[eventWithdrawalProvenExt1].ID,
+			proof.WithdrawalHash,
+			common.BytesToHash(proof.ProofSubmitter.Bytes()),
+		},
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		require.Equal(t, []WithdrawalProof{proof}, portal.DecodeWithdrawalProofs([]ethTypes.Log{validLog}))
+	})
+
+	t.Run("IgnoreIncorrectContract", func(t *testing.T) {
+		log := validLog
+		log.Address = common.Address{0xff}
+		require.Empty(t, portal.DecodeWithdrawalProofs([]ethTypes.Log{log}))
+	})
+
+	t.Run("IgnoreWrongEvent", func(t *testing.T) {
+		log := validLog
+		log.Topics = []common.Hash{
+			portalAbi.Events["WithdrawalProven"].ID,
+			proof.WithdrawalHash,
+			common.BytesToHash(common.Address{0x01}.Bytes()),
+			common.BytesToHash(common.Address{0x02}.Bytes()),
+		}
+		require.Empty(t, portal.DecodeWithdrawalProofs([]ethTypes.Log{log}))
+	})
+}
+
+func TestOptimismPortal_WithdrawalProofsQuery(t *testing.T) {
+	_, portal := setupOptimismPortalTest(t)
+	query := portal.WithdrawalProofsQuery(10, 20)
+	require.Equal(t, big.NewInt(10), query.FromBlock)
+	require.Equal(t, big.NewInt(20), query.ToBlock)
+	require.Equal(t, []common.Address{portalAddr}, query.Addresses)
+	require.Equal(t, snapshots.LoadOptimismPortal2ABI().Events[eventWithdrawalProvenExt1].ID, query.Topics[0][0])
+}
+
+func setupOptimismPortalTest(t *testing.T) (*batchingTest.AbiBasedRpc, *OptimismPortalContract) {
+	portalAbi := snapshots.LoadOptimismPortal2ABI()
+	stubRpc := batchingTest.NewAbiBasedRpc(t, portalAddr, portalAbi)
+	caller := batching.NewMultiCaller(stubRpc, batching.DefaultBatchSize)
+	portal := NewOptimismPortalContract(contractMetrics.NoopContractMetrics, portalAddr, caller)
+	return stubRpc, portal
+}
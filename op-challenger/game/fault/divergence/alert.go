@@ -0,0 +1,110 @@
+package divergence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const webhookTimeout = 10 * time.Second
+
+type Kind string
+
+const (
+	// KindHonestClaim is raised when a claim posted by one of the honest claimants doesn't match the local trace.
+	KindHonestClaim Kind = "honest_claim"
+	// KindOutputRoot is raised when the VM execution disagrees with the output root reported by the rollup node.
+	KindOutputRoot Kind = "output_root"
+)
+
+// Alert describes the point where the local trace diverged from the game.
+// Either the challenger has a local fault or the game is under a real attack so an operator should investigate.
+type Alert struct {
+	Kind         Kind           `json:"kind"`
+	Game         common.Address `json:"game"`
+	ClaimIndex   int            `json:"claimIndex"`
+	Depth        uint64         `json:"depth"`
+	IndexAtDepth *big.Int       `json:"indexAtDepth"`
+	Claimant     common.Address `json:"claimant"`
+	ClaimValue   common.Hash    `json:"claimValue"`
+	// LocalValue is the value calculated from the local trace.
+	// For output root divergences it is the final state of the VM execution.
+	LocalValue common.Hash `json:"localValue"`
+}
+
+type Metrics interface {
+	RecordDivergence(kind string)
+}
+
+// Alerter logs divergences, records them in metrics and optionally posts them to a webhook.
+type Alerter struct {
+	logger  log.Logger
+	m       Metrics
+	webhook string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewAlerter creates a new Alerter. Alerts are only posted to a webhook if the webhook URL is not empty.
+func NewAlerter(logger log.Logger, m Metrics, webhook string) *Alerter {
+	return &Alerter{
+		logger:  logger,
+		m:       m,
+		webhook: webhook,
+		timeout: webhookTimeout,
+		client:  &http.Client{},
+	}
+}
+
+func (a *Alerter) Alert(ctx context.Context, alert Alert) {
+	a.logger.Error("Local trace diverged from game",
+		"kind", alert.Kind,
+		"game", alert.Game,
+		"claimIdx", alert.ClaimIndex,
+		"depth", alert.Depth,
+		"indexAtDepth", alert.IndexAtDepth,
+		"claimant", alert.Claimant,
+		"claim", alert.ClaimValue,
+		"local", alert.LocalValue)
+	a.m.RecordDivergence(string(alert.Kind))
+	if a.webhook == "" {
+		return
+	}
+	if err := a.post(ctx, alert); err != nil {
+		a.logger.Error("Failed to send divergence alert to webhook", "game", alert.Game, "claimIdx", alert.ClaimIndex, "err", err)
+	}
+}
+
+// post sends the alert to the webhook, giving up after the webhook timeout so a slow webhook can't hold up checks.
+func (a *Alerter) post(ctx context.Context, alert Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %v: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
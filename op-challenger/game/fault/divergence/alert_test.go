@@ -0,0 +1,86 @@
+package divergence
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlerter(t *testing.T) {
+	alert := Alert{
+		Kind:         KindOutputRoot,
+		Game:         common.Address{0x11},
+		ClaimIndex:   3,
+		Depth:        2,
+		IndexAtDepth: big.NewInt(1),
+		Claimant:     common.Address{0x22},
+		ClaimValue:   common.Hash{0x33},
+		LocalValue:   common.Hash{0x44},
+	}
+
+	t.Run("WithoutWebhook", func(t *testing.T) {
+		m := &stubMetrics{}
+		alerter := NewAlerter(testlog.Logger(t, log.LevelCrit), m, "")
+		alerter.Alert(context.Background(), alert)
+		require.Equal(t, []string{string(KindOutputRoot)}, m.divergences)
+	})
+
+	t.Run("PostToWebhook", func(t *testing.T) {
+		var received []Alert
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var body Alert
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			received = append(received, body)
+		}))
+		defer server.Close()
+
+		m := &stubMetrics{}
+		alerter := NewAlerter(testlog.Logger(t, log.LevelCrit), m, server.URL)
+		alerter.Alert(context.Background(), alert)
+		require.Equal(t, []Alert{alert}, received)
+		require.Equal(t, []string{string(KindOutputRoot)}, m.divergences)
+	})
+
+	t.Run("WebhookError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		m := &stubMetrics{}
+		alerter := NewAlerter(testlog.Logger(t, log.LevelCrit), m, server.URL)
+		require.ErrorContains(t, alerter.post(context.Background(), alert), "unexpected status")
+	})
+
+	t.Run("WebhookTimeout", func(t *testing.T) {
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		defer server.Close()
+		defer close(done)
+
+		alerter := NewAlerter(testlog.Logger(t, log.LevelCrit), &stubMetrics{}, server.URL)
+		alerter.timeout = 10 * time.Millisecond
+		require.ErrorIs(t, alerter.post(context.Background(), alert), context.DeadlineExceeded)
+	})
+}
+
+type stubMetrics struct {
+	divergences []string
+}
+
+func (s *stubMetrics) RecordDivergence(kind string) {
+	s.divergences = append(s.divergences, kind)
+}
@@ -0,0 +1,135 @@
+package divergence
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type AlertSink interface {
+	Alert(ctx context.Context, alert Alert)
+}
+
+// Detector compares the claims in a game with the local trace and raises an alert when they diverge.
+// Each claim is only checked once. Claims that fail to be checked are retried on the next call to Check.
+type Detector struct {
+	logger     log.Logger
+	alerts     AlertSink
+	game       common.Address
+	trace      types.TraceAccessor
+	claimants  []common.Address
+	splitDepth types.Depth
+	// checkVMStatus enables checking that the VM status of the bottom game's final state agrees with the output
+	// root it is disputing. Not all trace types report a meaningful VM status.
+	checkVMStatus bool
+
+	checked map[int]bool
+}
+
+func NewDetector(
+	logger log.Logger,
+	alerts AlertSink,
+	game common.Address,
+	trace types.TraceAccessor,
+	claimants []common.Address,
+	splitDepth types.Depth,
+	checkVMStatus bool,
+) *Detector {
+	return &Detector{
+		logger:        logger,
+		alerts:        alerts,
+		game:          game,
+		trace:         trace,
+		claimants:     claimants,
+		splitDepth:    splitDepth,
+		checkVMStatus: checkVMStatus,
+		checked:       make(map[int]bool),
+	}
+}
+
+// Check compares any claims that haven't yet been checked with the local trace.
+func (d *Detector) Check(ctx context.Context, game types.Game) {
+	for _, claim := range game.Claims() {
+		if d.checked[claim.ContractIndex] {
+			continue
+		}
+		if err := d.checkClaim(ctx, game, claim); err != nil {
+			d.logger.Warn("Failed to check claim for divergence", "claimIdx", claim.ContractIndex, "err", err)
+			continue
+		}
+		d.checked[claim.ContractIndex] = true
+	}
+}
+
+func (d *Detector) checkClaim(ctx context.Context, game types.Game, claim types.Claim) error {
+	honest := slices.Contains(d.claimants, claim.Claimant)
+	outputRoot := d.checkVMStatus && claim.Depth() == d.splitDepth+1
+	if !honest && !outputRoot {
+		return nil
+	}
+	local, err := d.trace.Get(ctx, game, claim, claim.Position)
+	if err != nil {
+		return fmt.Errorf("failed to get local claim value: %w", err)
+	}
+	if honest && local != claim.Value {
+		d.alerts.Alert(ctx, d.newAlert(KindHonestClaim, claim, local))
+	}
+	if !outputRoot {
+		return nil
+	}
+	// The claim is the root of a bottom game so its value is the final state of the VM execution which reports
+	// whether the disputed output root is valid.
+	post, err := d.disputedOutputRoot(game, claim)
+	if err != nil {
+		return err
+	}
+	localPost, err := d.trace.Get(ctx, game, post, post.Position)
+	if err != nil {
+		return fmt.Errorf("failed to get local output root: %w", err)
+	}
+	outputValid := localPost == post.Value
+	vmValid := local[0] == mipsevm.VMStatusValid
+	if outputValid != vmValid {
+		d.alerts.Alert(ctx, d.newAlert(KindOutputRoot, post, local))
+	}
+	return nil
+}
+
+// disputedOutputRoot finds the claim from the top game that is the post-state of the bottom game rooted at claim.
+func (d *Detector) disputedOutputRoot(game types.Game, claim types.Claim) (types.Claim, error) {
+	topLeaf, err := game.GetParent(claim)
+	if err != nil {
+		return types.Claim{}, fmt.Errorf("failed to get parent of claim %v: %w", claim.ContractIndex, err)
+	}
+	if !game.DefendsParent(claim) {
+		return topLeaf, nil
+	}
+	postTraceIdx := new(big.Int).Add(topLeaf.TraceIndex(d.splitDepth), big.NewInt(1))
+	post := topLeaf
+	for post.TraceIndex(d.splitDepth).Cmp(postTraceIdx) != 0 {
+		post, err = game.GetParent(post)
+		if err != nil {
+			return types.Claim{}, fmt.Errorf("failed to find post claim for claim %v: %w", claim.ContractIndex, err)
+		}
+	}
+	return post, nil
+}
+
+func (d *Detector) newAlert(kind Kind, claim types.Claim, local common.Hash) Alert {
+	return Alert{
+		Kind:         kind,
+		Game:         d.game,
+		ClaimIndex:   claim.ContractIndex,
+		Depth:        uint64(claim.Depth()),
+		IndexAtDepth: claim.IndexAtDepth(),
+		Claimant:     claim.Claimant,
+		ClaimValue:   claim.Value,
+		LocalValue:   local,
+	}
+}
@@ -0,0 +1,203 @@
+package divergence
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	maxDepth   = types.Depth(4)
+	splitDepth = types.Depth(1)
+)
+
+var (
+	honest    = common.Address{0xaa}
+	dishonest = common.Address{0xbb}
+
+	rootPos   = types.NewPositionFromGIndex(big.NewInt(1))
+	leafPos   = rootPos.Attack()
+	attackPos = leafPos.Attack()
+	defendPos = leafPos.Defend()
+
+	rootValue = common.Hash{0x01}
+	leafValue = common.Hash{0x02}
+	validVM   = common.Hash{mipsevm.VMStatusValid, 0x03}
+	invalidVM = common.Hash{mipsevm.VMStatusInvalid, 0x04}
+)
+
+func TestHonestClaims(t *testing.T) {
+	t.Run("Match", func(t *testing.T) {
+		detector, trace, alerts := setupDetector(t, true)
+		trace.set(rootPos, rootValue)
+		detector.Check(context.Background(), newGame(claimAt(0, -1, rootPos, rootValue, honest)))
+		require.Empty(t, alerts.alerts)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		detector, trace, alerts := setupDetector(t, true)
+		trace.set(rootPos, common.Hash{0xff})
+		detector.Check(context.Background(), newGame(claimAt(0, -1, rootPos, rootValue, honest)))
+		require.Len(t, alerts.alerts, 1)
+		alert := alerts.alerts[0]
+		require.Equal(t, KindHonestClaim, alert.Kind)
+		require.Equal(t, 0, alert.ClaimIndex)
+		require.Equal(t, honest, alert.Claimant)
+		require.Equal(t, rootValue, alert.ClaimValue)
+		require.Equal(t, common.Hash{0xff}, alert.LocalValue)
+	})
+
+	t.Run("IgnoreOtherClaimants", func(t *testing.T) {
+		detector, trace, alerts := setupDetector(t, true)
+		trace.set(rootPos, common.Hash{0xff})
+		detector.Check(context.Background(), newGame(claimAt(0, -1, rootPos, rootValue, dishonest)))
+		require.Empty(t, alerts.alerts)
+		require.Zero(t, trace.calls)
+	})
+}
+
+func TestOutputRoot(t *testing.T) {
+	tests := []struct {
+		name        string
+		bottomPos   types.Position
+		agreePost   bool
+		vmState     common.Hash
+		postIdx     int
+		expectAlert bool
+	}{
+		{name: "AttackValidOutputRoot", bottomPos: attackPos, agreePost: true, vmState: validVM, postIdx: 1},
+		{name: "AttackInvalidOutputRoot", bottomPos: attackPos, agreePost: false, vmState: invalidVM, postIdx: 1},
+		{name: "AttackValidOutputRootVMInvalid", bottomPos: attackPos, agreePost: true, vmState: invalidVM, postIdx: 1, expectAlert: true},
+		{name: "AttackInvalidOutputRootVMValid", bottomPos: attackPos, agreePost: false, vmState: validVM, postIdx: 1, expectAlert: true},
+		{name: "DefendValidOutputRoot", bottomPos: defendPos, agreePost: true, vmState: validVM, postIdx: 0},
+		{name: "DefendValidOutputRootVMInvalid", bottomPos: defendPos, agreePost: true, vmState: invalidVM, postIdx: 0, expectAlert: true},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			detector, trace, alerts := setupDetector(t, true)
+			claims := []types.Claim{
+				claimAt(0, -1, rootPos, rootValue, dishonest),
+				claimAt(1, 0, leafPos, leafValue, dishonest),
+				claimAt(2, 1, test.bottomPos, common.Hash{0xee}, dishonest),
+			}
+			post := claims[test.postIdx]
+			if test.agreePost {
+				trace.set(post.Position, post.Value)
+			} else {
+				trace.set(post.Position, common.Hash{0xdd})
+			}
+			trace.set(test.bottomPos, test.vmState)
+
+			detector.Check(context.Background(), newGame(claims...))
+			if !test.expectAlert {
+				require.Empty(t, alerts.alerts)
+				return
+			}
+			require.Len(t, alerts.alerts, 1)
+			alert := alerts.alerts[0]
+			require.Equal(t, KindOutputRoot, alert.Kind)
+			require.Equal(t, test.postIdx, alert.ClaimIndex)
+			require.Equal(t, post.Value, alert.ClaimValue)
+			require.Equal(t, test.vmState, alert.LocalValue)
+		})
+	}
+
+	t.Run("SkipWithoutVMStatus", func(t *testing.T) {
+		detector, trace, alerts := setupDetector(t, false)
+		trace.set(leafPos, leafValue)
+		trace.set(attackPos, invalidVM)
+		detector.Check(context.Background(), newGame(
+			claimAt(0, -1, rootPos, rootValue, dishonest),
+			claimAt(1, 0, leafPos, leafValue, dishonest),
+			claimAt(2, 1, attackPos, common.Hash{0xee}, dishonest),
+		))
+		require.Empty(t, alerts.alerts)
+		require.Zero(t, trace.calls)
+	})
+}
+
+func TestCheckClaimsOnce(t *testing.T) {
+	detector, trace, alerts := setupDetector(t, true)
+	game := newGame(claimAt(0, -1, rootPos, rootValue, honest))
+	trace.err = errors.New("boom")
+	detector.Check(context.Background(), game)
+	require.Empty(t, alerts.alerts)
+	require.Equal(t, 1, trace.calls)
+
+	// Retries claims that failed to be checked
+	trace.err = nil
+	trace.set(rootPos, common.Hash{0xff})
+	detector.Check(context.Background(), game)
+	require.Len(t, alerts.alerts, 1)
+	require.Equal(t, 2, trace.calls)
+
+	// Doesn't check or alert for the same claim again
+	detector.Check(context.Background(), game)
+	require.Len(t, alerts.alerts, 1)
+	require.Equal(t, 2, trace.calls)
+}
+
+func setupDetector(t *testing.T, checkVMStatus bool) (*Detector, *stubTrace, *stubAlerts) {
+	logger := testlog.Logger(t, log.LevelInfo)
+	trace := &stubTrace{values: make(map[string]common.Hash)}
+	alerts := &stubAlerts{}
+	detector := NewDetector(logger, alerts, common.Address{0x11}, trace, []common.Address{honest}, splitDepth, checkVMStatus)
+	return detector, trace, alerts
+}
+
+func newGame(claims ...types.Claim) types.Game {
+	return types.NewGameState(claims, maxDepth)
+}
+
+func claimAt(idx int, parentIdx int, pos types.Position, value common.Hash, claimant common.Address) types.Claim {
+	return types.Claim{
+		ClaimData: types.ClaimData{
+			Value:    value,
+			Bond:     big.NewInt(0),
+			Position: pos,
+		},
+		Claimant:            claimant,
+		ContractIndex:       idx,
+		ParentContractIndex: parentIdx,
+	}
+}
+
+type stubTrace struct {
+	types.TraceAccessor
+	values map[string]common.Hash
+	err    error
+	calls  int
+}
+
+func (s *stubTrace) set(pos types.Position, value common.Hash) {
+	s.values[pos.ToGIndex().String()] = value
+}
+
+func (s *stubTrace) Get(_ context.Context, _ types.Game, _ types.Claim, pos types.Position) (common.Hash, error) {
+	s.calls++
+	if s.err != nil {
+		return common.Hash{}, s.err
+	}
+	value, ok := s.values[pos.ToGIndex().String()]
+	if !ok {
+		return common.Hash{}, errors.New("unexpected position")
+	}
+	return value, nil
+}
+
+type stubAlerts struct {
+	alerts []Alert
+}
+
+func (s *stubAlerts) Alert(_ context.Context, alert Alert) {
+	s.alerts = append(s.alerts, alert)
+}
@@ -39,10 +39,12 @@ type L1HeaderSource interface {
 type TxSender interface {
 	From() common.Address
 	SendAndWaitSimple(txPurpose string, txs ...txmgr.TxCandidate) error
+	SendAndWaitBatched(txPurpose string, txs ...txmgr.TxCandidate) []error
 }
 
 type GamePlayer struct {
 	act                actor
+	inspect            func() *gameTypes.PlayerInspection
 	loader             GameInfo
 	logger             log.Logger
 	syncValidator      SyncValidator
@@ -70,6 +72,8 @@ var actNoop = func(ctx context.Context) error {
 
 type resourceCreator func(ctx context.Context, logger log.Logger, gameDepth types.Depth, dir string) (types.TraceAccessor, error)
 
+type detectorCreator func(logger log.Logger, accessor types.TraceAccessor) DivergenceDetector
+
 func NewGamePlayer(
 	ctx context.Context,
 	systemClock clock.Clock,
@@ -86,6 +90,8 @@ func NewGamePlayer(
 	l1HeaderSource L1HeaderSource,
 	selective bool,
 	claimants []common.Address,
+	newDetector detectorCreator,
+	deferrer ActionDeferrer,
 ) (*GamePlayer, error) {
 	logger = logger.New("game", addr)
 
@@ -148,9 +154,14 @@ func NewGamePlayer(
 		return nil, fmt.Errorf("failed to create the responder: %w", err)
 	}
 
-	agent := NewAgent(m, systemClock, l1Clock, loader, gameDepth, maxClockDuration, accessor, responder, logger, selective, claimants)
+	var detector DivergenceDetector
+	if newDetector != nil {
+		detector = newDetector(logger, accessor)
+	}
+	agent := NewAgent(m, systemClock, l1Clock, loader, gameDepth, maxClockDuration, accessor, responder, logger, selective, claimants, detector, addr, deferrer)
 	return &GamePlayer{
 		act:                agent.Act,
+		inspect:            agent.Inspect,
 		loader:             loader,
 		logger:             logger,
 		status:             status,
@@ -160,6 +171,22 @@ func NewGamePlayer(
 	}, nil
 }
 
+// NewNonParticipatingGamePlayer creates a player for a game the participation policy excluded.
+// The player tracks the game's status until it resolves but never acts on the game.
+func NewNonParticipatingGamePlayer(ctx context.Context, logger log.Logger, addr common.Address, loader GameInfo) (*GamePlayer, error) {
+	logger = logger.New("game", addr)
+	status, err := loader.GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch game status: %w", err)
+	}
+	return &GamePlayer{
+		logger: logger,
+		loader: loader,
+		status: status,
+		act:    actNoop,
+	}, nil
+}
+
 func (g *GamePlayer) ValidatePrestate(ctx context.Context) error {
 	for _, validator := range g.prestateValidators {
 		if err := validator.Validate(ctx); err != nil {
@@ -173,18 +200,30 @@ func (g *GamePlayer) Status() gameTypes.GameStatus {
 	return g.status
 }
 
+// Inspect returns the result of the last time responses to the game were calculated.
+// Returns nil if the game was already resolved when the player was created or it hasn't been progressed yet.
+func (g *GamePlayer) Inspect() *gameTypes.PlayerInspection {
+	if g.inspect == nil {
+		return nil
+	}
+	return g.inspect()
+}
+
 func (g *GamePlayer) ProgressGame(ctx context.Context) gameTypes.GameStatus {
 	if g.status != gameTypes.GameStatusInProgress {
 		// Game is already complete so don't try to perform further actions.
 		g.logger.Trace("Skipping completed game")
 		return g.status
 	}
-	if err := g.syncValidator.ValidateNodeSynced(ctx, g.gameL1Head); errors.Is(err, ErrNotInSync) {
-		g.logger.Warn("Local node not sufficiently up to date", "err", err)
-		return g.status
-	} else if err != nil {
-		g.logger.Error("Could not check local node was in sync", "err", err)
-		return g.status
+	// Players that don't act on the game have no sync validator as they don't depend on the local node.
+	if g.syncValidator != nil {
+		if err := g.syncValidator.ValidateNodeSynced(ctx, g.gameL1Head); errors.Is(err, ErrNotInSync) {
+			g.logger.Warn("Local node not sufficiently up to date", "err", err)
+			return g.status
+		} else if err != nil {
+			g.logger.Error("Could not check local node was in sync", "err", err)
+			return g.status
+		}
 	}
 	g.logger.Trace("Checking if actions are required")
 	if err := g.act(ctx); err != nil {
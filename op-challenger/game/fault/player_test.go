@@ -110,6 +110,22 @@ func TestValidateLocalNodeSync(t *testing.T) {
 	require.Equal(t, 1, gameState.callCount, "does not act when not in sync")
 }
 
+func TestNonParticipatingGamePlayer(t *testing.T) {
+	logger := testlog.Logger(t, log.LevelDebug)
+	gameState := &stubGameState{claimCount: 1}
+	game, err := NewNonParticipatingGamePlayer(context.Background(), logger, common.Address{0xaa}, gameState)
+	require.NoError(t, err)
+	require.NoError(t, game.ValidatePrestate(context.Background()))
+	require.Equal(t, types.GameStatusInProgress, game.Status())
+
+	require.Equal(t, types.GameStatusInProgress, game.ProgressGame(context.Background()))
+	require.Nil(t, game.Inspect())
+
+	gameState.status = types.GameStatusDefenderWon
+	require.Equal(t, types.GameStatusDefenderWon, game.ProgressGame(context.Background()))
+	require.Zero(t, gameState.callCount, "should never act")
+}
+
 func TestValidatePrestate(t *testing.T) {
 	tests := []struct {
 		name       string
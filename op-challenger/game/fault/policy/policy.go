@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Rule selects games to participate in.
+type Rule string
+
+const (
+	// RuleOwnProposals participates in games where the root claim was proposed by one of the claimants.
+	RuleOwnProposals Rule = "own-proposals"
+	// RuleDivergentRoots participates in games where the root claim doesn't match the local node.
+	RuleDivergentRoots Rule = "divergent-roots"
+)
+
+var Rules = []Rule{RuleOwnProposals, RuleDivergentRoots}
+
+func (r Rule) String() string {
+	return string(r)
+}
+
+// Set implements the Set method required by the [cli.Generic] interface.
+func (r *Rule) Set(value string) error {
+	if !slices.Contains(Rules, Rule(value)) {
+		return fmt.Errorf("unknown participation policy: %q", value)
+	}
+	*r = Rule(value)
+	return nil
+}
+
+func (r *Rule) Clone() any {
+	cpy := *r
+	return &cpy
+}
+
+const (
+	reasonAll             = "all"
+	reasonOwnProposal     = "own-proposal"
+	reasonDivergentRoot   = "divergent-root"
+	reasonBondTooHigh     = "bond-too-high"
+	reasonNoMatchingRules = "no-matching-policy"
+)
+
+// Config controls which games the challenger participates in.
+type Config struct {
+	// Rules are the policies that select games to participate in. A game is played if any rule selects it.
+	// All games are played if no rules are set.
+	Rules []Rule
+	// MaxBond is the maximum bond required to counter the root claim of games to participate in.
+	// The claimants' own proposals are exempt. Nil for no limit.
+	MaxBond *big.Int
+}
+
+type Metrics interface {
+	RecordParticipationDecision(participate bool, reason string)
+}
+
+type GameContract interface {
+	GetClaim(ctx context.Context, idx uint64) (types.Claim, error)
+	GetRequiredBond(ctx context.Context, position types.Position) (*big.Int, error)
+}
+
+// Evaluator decides whether to participate in each game.
+type Evaluator struct {
+	logger    log.Logger
+	m         Metrics
+	cfg       Config
+	claimants []common.Address
+}
+
+func NewEvaluator(logger log.Logger, m Metrics, cfg Config, claimants []common.Address) *Evaluator {
+	return &Evaluator{
+		logger:    logger,
+		m:         m,
+		cfg:       cfg,
+		claimants: claimants,
+	}
+}
+
+// Participate returns true if the challenger should act on the game.
+// localRoot provides the local node's view of the root claim.
+func (e *Evaluator) Participate(ctx context.Context, game common.Address, contract GameContract, localRoot types.PrestateProvider) (bool, error) {
+	participate, reason, err := e.evaluate(ctx, contract, localRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate participation policy for game %v: %w", game, err)
+	}
+	e.logger.Info("Evaluated participation policy", "game", game, "participate", participate, "reason", reason)
+	e.m.RecordParticipationDecision(participate, reason)
+	return participate, nil
+}
+
+func (e *Evaluator) evaluate(ctx context.Context, contract GameContract, localRoot types.PrestateProvider) (bool, string, error) {
+	if len(e.cfg.Rules) == 0 && e.cfg.MaxBond == nil {
+		return true, reasonAll, nil
+	}
+	root, err := contract.GetClaim(ctx, 0)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to load root claim: %w", err)
+	}
+	ownProposal := slices.Contains(e.claimants, root.Claimant)
+	if ownProposal && slices.Contains(e.cfg.Rules, RuleOwnProposals) {
+		return true, reasonOwnProposal, nil
+	}
+	// Own proposals aren't subject to the bond limit because the proposal bond is lost if they aren't defended.
+	if e.cfg.MaxBond != nil && !ownProposal {
+		bond, err := contract.GetRequiredBond(ctx, root.Position.Attack())
+		if err != nil {
+			return false, "", fmt.Errorf("failed to load required bond: %w", err)
+		}
+		if bond.Cmp(e.cfg.MaxBond) > 0 {
+			return false, reasonBondTooHigh, nil
+		}
+	}
+	if len(e.cfg.Rules) == 0 {
+		return true, reasonAll, nil
+	}
+	if slices.Contains(e.cfg.Rules, RuleDivergentRoots) {
+		expected, err := localRoot.AbsolutePreStateCommitment(ctx)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to load local root: %w", err)
+		}
+		if expected != root.Value {
+			return true, reasonDivergentRoot, nil
+		}
+	}
+	return false, reasonNoMatchingRules, nil
+}
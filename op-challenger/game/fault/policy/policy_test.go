@@ -0,0 +1,148 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	gameAddr  = common.Address{0x67}
+	honest    = common.Address{0xaa}
+	proposer  = common.Address{0xbb}
+	rootValue = common.Hash{0x01}
+)
+
+func TestParticipate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		claimant    common.Address
+		localRoot   common.Hash
+		bond        int64
+		participate bool
+		reason      string
+	}{
+		{name: "NoPolicy", cfg: Config{}, claimant: proposer, localRoot: rootValue, participate: true, reason: reasonAll},
+		{name: "OwnProposal", cfg: Config{Rules: []Rule{RuleOwnProposals}}, claimant: honest, localRoot: rootValue, participate: true, reason: reasonOwnProposal},
+		{name: "NotOwnProposal", cfg: Config{Rules: []Rule{RuleOwnProposals}}, claimant: proposer, localRoot: common.Hash{0xff}, participate: false, reason: reasonNoMatchingRules},
+		{name: "DivergentRoot", cfg: Config{Rules: []Rule{RuleDivergentRoots}}, claimant: proposer, localRoot: common.Hash{0xff}, participate: true, reason: reasonDivergentRoot},
+		{name: "MatchingRoot", cfg: Config{Rules: []Rule{RuleDivergentRoots}}, claimant: proposer, localRoot: rootValue, participate: false, reason: reasonNoMatchingRules},
+		{name: "OwnProposalWithMatchingRoot", cfg: Config{Rules: []Rule{RuleOwnProposals, RuleDivergentRoots}}, claimant: honest, localRoot: rootValue, participate: true, reason: reasonOwnProposal},
+		{name: "BondBelowLimit", cfg: Config{MaxBond: big.NewInt(100)}, claimant: proposer, localRoot: rootValue, bond: 100, participate: true, reason: reasonAll},
+		{name: "BondAboveLimit", cfg: Config{MaxBond: big.NewInt(100)}, claimant: proposer, localRoot: rootValue, bond: 101, participate: false, reason: reasonBondTooHigh},
+		{name: "BondAboveLimitWithDivergentRoot", cfg: Config{Rules: []Rule{RuleDivergentRoots}, MaxBond: big.NewInt(100)}, claimant: proposer, localRoot: common.Hash{0xff}, bond: 101, participate: false, reason: reasonBondTooHigh},
+		{name: "BondLimitIgnoredForOwnProposal", cfg: Config{MaxBond: big.NewInt(100)}, claimant: honest, localRoot: rootValue, bond: 101, participate: true, reason: reasonAll},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			logger, logs := testlog.CaptureLogger(t, log.LevelInfo)
+			m := &stubMetrics{}
+			evaluator := NewEvaluator(logger, m, test.cfg, []common.Address{honest})
+			contract := &stubContract{claimant: test.claimant, bond: big.NewInt(test.bond)}
+			participate, err := evaluator.Participate(context.Background(), gameAddr, contract, &stubPrestateProvider{root: test.localRoot})
+			require.NoError(t, err)
+			require.Equal(t, test.participate, participate)
+			require.Equal(t, []decision{{test.participate, test.reason}}, m.decisions)
+
+			entry := logs.FindLog(testlog.NewMessageFilter("Evaluated participation policy"))
+			require.NotNil(t, entry)
+			require.Equal(t, gameAddr, entry.AttrValue("game"))
+			require.Equal(t, test.reason, entry.AttrValue("reason"))
+		})
+	}
+}
+
+func TestParticipateErrors(t *testing.T) {
+	cfg := Config{Rules: []Rule{RuleDivergentRoots}, MaxBond: big.NewInt(100)}
+	err := errors.New("boom")
+	tests := []struct {
+		name      string
+		contract  *stubContract
+		localRoot *stubPrestateProvider
+	}{
+		{name: "ClaimError", contract: &stubContract{claimErr: err}, localRoot: &stubPrestateProvider{}},
+		{name: "BondError", contract: &stubContract{bondErr: err}, localRoot: &stubPrestateProvider{}},
+		{name: "LocalRootError", contract: &stubContract{bond: big.NewInt(1)}, localRoot: &stubPrestateProvider{err: err}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			m := &stubMetrics{}
+			evaluator := NewEvaluator(testlog.Logger(t, log.LevelInfo), m, cfg, []common.Address{honest})
+			_, actualErr := evaluator.Participate(context.Background(), gameAddr, test.contract, test.localRoot)
+			require.ErrorIs(t, actualErr, err)
+			require.Empty(t, m.decisions)
+		})
+	}
+}
+
+func TestRuleSet(t *testing.T) {
+	for _, rule := range Rules {
+		var actual Rule
+		require.NoError(t, actual.Set(rule.String()))
+		require.Equal(t, rule, actual)
+	}
+	var invalid Rule
+	require.ErrorContains(t, invalid.Set("bogus"), "unknown participation policy")
+}
+
+type decision struct {
+	participate bool
+	reason      string
+}
+
+type stubMetrics struct {
+	decisions []decision
+}
+
+func (s *stubMetrics) RecordParticipationDecision(participate bool, reason string) {
+	s.decisions = append(s.decisions, decision{participate, reason})
+}
+
+type stubContract struct {
+	claimant common.Address
+	bond     *big.Int
+	claimErr error
+	bondErr  error
+}
+
+func (s *stubContract) GetClaim(_ context.Context, idx uint64) (types.Claim, error) {
+	if s.claimErr != nil {
+		return types.Claim{}, s.claimErr
+	}
+	if idx != 0 {
+		return types.Claim{}, errors.New("unexpected claim index")
+	}
+	return types.Claim{
+		ClaimData: types.ClaimData{
+			Value:    rootValue,
+			Position: types.NewPositionFromGIndex(big.NewInt(1)),
+		},
+		Claimant: s.claimant,
+	}, nil
+}
+
+func (s *stubContract) GetRequiredBond(_ context.Context, _ types.Position) (*big.Int, error) {
+	if s.bondErr != nil {
+		return nil, s.bondErr
+	}
+	return s.bond, nil
+}
+
+type stubPrestateProvider struct {
+	root common.Hash
+	err  error
+}
+
+func (s *stubPrestateProvider) AbsolutePreStateCommitment(_ context.Context) (common.Hash, error) {
+	return s.root, s.err
+}
@@ -25,6 +25,13 @@ var _ PreimageUploader = (*LargePreimageUploader)(nil)
 // ErrChallengePeriodNotOver is returned when the challenge period is not over.
 var ErrChallengePeriodNotOver = errors.New("challenge period not over")
 
+// ErrTooManyCounteredProposals is returned when every attempt to propose a large preimage has been countered.
+var ErrTooManyCounteredProposals = errors.New("too many countered large preimage proposals")
+
+// maxProposalAttempts is the maximum number of proposals created for the same preimage when earlier
+// proposals are countered.
+const maxProposalAttempts = 5
+
 // MaxBlocksPerChunk is the maximum number of keccak blocks per chunk.
 const MaxBlocksPerChunk = 300
 
@@ -55,13 +62,9 @@ func (p *LargePreimageUploader) UploadPreimage(ctx context.Context, parent uint6
 		return fmt.Errorf("failed to split preimage into chunks for data with oracle offset %d: %w", data.OracleOffset, err)
 	}
 
-	uuid := NewUUID(p.txSender.From(), data)
-
-	// Fetch the current metadata for this preimage data, if it exists.
-	ident := keccakTypes.LargePreimageIdent{Claimant: p.txSender.From(), UUID: uuid}
-	metadata, err := p.contract.GetProposalMetadata(ctx, rpcblock.Latest, ident)
+	uuid, metadata, err := p.findProposal(ctx, data)
 	if err != nil {
-		return fmt.Errorf("failed to get pre-image oracle metadata: %w", err)
+		return err
 	}
 
 	// The proposal is not initialized if the queried metadata has a claimed size of 0.
@@ -90,6 +93,25 @@ func (p *LargePreimageUploader) UploadPreimage(ctx context.Context, parent uint6
 	return p.Squeeze(ctx, uuid, stateMatrix)
 }
 
+// findProposal returns the identifier and metadata of the proposal to use for the preimage.
+// A countered proposal can never be squeezed, so a new proposal with a different UUID is used instead.
+func (p *LargePreimageUploader) findProposal(ctx context.Context, data *types.PreimageOracleData) (*big.Int, []keccakTypes.LargePreimageMetaData, error) {
+	for attempt := uint32(0); attempt < maxProposalAttempts; attempt++ {
+		uuid := newAttemptUUID(p.txSender.From(), data, attempt)
+		ident := keccakTypes.LargePreimageIdent{Claimant: p.txSender.From(), UUID: uuid}
+		metadata, err := p.contract.GetProposalMetadata(ctx, rpcblock.Latest, ident)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get pre-image oracle metadata: %w", err)
+		}
+		if len(metadata) > 0 && metadata[0].Countered {
+			p.log.Warn("Large preimage proposal was countered, creating new proposal", "uuid", uuid, "attempt", attempt)
+			continue
+		}
+		return uuid, metadata, nil
+	}
+	return nil, nil, fmt.Errorf("%w: key %v", ErrTooManyCounteredProposals, hexutil.Bytes(data.OracleKey))
+}
+
 // NewUUID generates a new unique identifier for the preimage by hashing the
 // concatenated preimage data, preimage offset, and sender address.
 func NewUUID(sender common.Address, data *types.PreimageOracleData) *big.Int {
@@ -101,6 +123,18 @@ func NewUUID(sender common.Address, data *types.PreimageOracleData) *big.Int {
 	return hash.Big()
 }
 
+// newAttemptUUID generates the unique identifier for a repeated proposal of the same preimage.
+// The first attempt uses the same identifier as NewUUID.
+func newAttemptUUID(sender common.Address, data *types.PreimageOracleData, attempt uint32) *big.Int {
+	uuid := NewUUID(sender, data)
+	if attempt == 0 {
+		return uuid
+	}
+	nonce := make([]byte, 4)
+	binary.BigEndian.PutUint32(nonce, attempt)
+	return crypto.Keccak256Hash(uuid.Bytes(), nonce).Big()
+}
+
 // splitChunks splits the preimage data into chunks of size [MaxChunkSize] (except the last chunk).
 // It also returns the state matrix and the data for the squeeze call if possible.
 func (p *LargePreimageUploader) splitCalls(data *types.PreimageOracleData) (*matrix.StateMatrix, []keccakTypes.InputData, error) {
@@ -110,6 +110,29 @@ func TestLargePreimageUploader_UploadPreimage_EdgeCases(t *testing.T) {
 		require.Equal(t, 6, contract.addCalls)
 	})
 
+	t.Run("PreviousProposalCountered", func(t *testing.T) {
+		oracle, _, txSender, contract := newTestLargePreimageUploader(t)
+		data := mockPreimageOracleData()
+		contract.countered = map[string]bool{NewUUID(txSender.From(), data).String(): true}
+		contract.claimedSize = uint32(len(data.GetPreimageWithoutSize()))
+		err := oracle.UploadPreimage(context.Background(), 0, data)
+		require.NoError(t, err)
+		require.Equal(t, 1, contract.initCalls)
+		require.Equal(t, newAttemptUUID(txSender.From(), data, 1), contract.initUUID)
+	})
+
+	t.Run("AllProposalsCountered", func(t *testing.T) {
+		oracle, _, txSender, contract := newTestLargePreimageUploader(t)
+		data := mockPreimageOracleData()
+		contract.countered = make(map[string]bool)
+		for i := uint32(0); i < maxProposalAttempts; i++ {
+			contract.countered[newAttemptUUID(txSender.From(), data, i).String()] = true
+		}
+		err := oracle.UploadPreimage(context.Background(), 0, data)
+		require.ErrorIs(t, err, ErrTooManyCounteredProposals)
+		require.Zero(t, contract.initCalls)
+	})
+
 	t.Run("ChallengePeriodNotElapsed", func(t *testing.T) {
 		oracle, cl, _, contract := newTestLargePreimageUploader(t)
 		data := mockPreimageOracleData()
@@ -277,10 +300,13 @@ type mockPreimageOracleContract struct {
 	squeezeCallClaimSize uint32
 	squeezePrestate      keccakTypes.Leaf
 	squeezePoststate     keccakTypes.Leaf
+	initUUID             *big.Int
+	countered            map[string]bool
 }
 
-func (s *mockPreimageOracleContract) InitLargePreimage(_ *big.Int, _ uint32, _ uint32) (txmgr.TxCandidate, error) {
+func (s *mockPreimageOracleContract) InitLargePreimage(uuid *big.Int, _ uint32, _ uint32) (txmgr.TxCandidate, error) {
 	s.initCalls++
+	s.initUUID = uuid
 	if s.initFails {
 		return txmgr.TxCandidate{}, mockInitLPPError
 	}
@@ -311,6 +337,9 @@ func (s *mockPreimageOracleContract) ChallengePeriod(_ context.Context) (uint64,
 }
 
 func (s *mockPreimageOracleContract) GetProposalMetadata(_ context.Context, _ rpcblock.Block, idents ...keccakTypes.LargePreimageIdent) ([]keccakTypes.LargePreimageMetaData, error) {
+	if s.countered[idents[0].UUID.String()] {
+		return []keccakTypes.LargePreimageMetaData{{LargePreimageIdent: idents[0], ClaimedSize: 1, Countered: true}}, nil
+	}
 	if s.squeezeCallClaimSize > 0 {
 		metadata := make([]keccakTypes.LargePreimageMetaData, 0)
 		for _, ident := range idents {
@@ -7,7 +7,10 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/divergence"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/outputs"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/super"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
 	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	keccakTypes "github.com/ethereum-optimism/optimism/op-challenger/game/keccak/types"
@@ -43,6 +46,45 @@ type RollupClient interface {
 	SyncStatusProvider
 }
 
+type SupervisorClient interface {
+	super.RootProvider
+	SupervisorSyncStatusProvider
+}
+
+// RegisterTaskCreator creates the RegisterTask for a trace type, which defines how the game's prestates are
+// loaded and how its trace is generated.
+// The supervisor client is only available if an op-supervisor RPC is configured.
+type RegisterTaskCreator func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, supervisor SupervisorClient) *RegisterTask
+
+// registerTaskCreators maps each trace type to the creator of its RegisterTask.
+// Supporting a new game type only requires adding its trace type and an entry here.
+var registerTaskCreators = map[faultTypes.TraceType]RegisterTaskCreator{
+	faultTypes.TraceTypeCannon: func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewCannonRegisterTask(faultTypes.CannonGameType, cfg, m, vm.NewOpProgramServerExecutor(), resources)
+	},
+	faultTypes.TraceTypePermissioned: func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewCannonRegisterTask(faultTypes.PermissionedGameType, cfg, m, vm.NewOpProgramServerExecutor(), resources)
+	},
+	faultTypes.TraceTypeAsterisc: func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewAsteriscRegisterTask(faultTypes.AsteriscGameType, cfg, m, vm.NewOpProgramServerExecutor(), resources)
+	},
+	faultTypes.TraceTypeAsteriscKona: func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewAsteriscKonaRegisterTask(faultTypes.AsteriscKonaGameType, cfg, m, vm.NewKonaExecutor(), resources)
+	},
+	faultTypes.TraceTypeCannonKona: func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewCannonKonaRegisterTask(faultTypes.CannonKonaGameType, cfg, m, vm.NewKonaExecutor(), resources)
+	},
+	faultTypes.TraceTypeSuperCannon: func(cfg *config.Config, m metrics.Metricer, resources vm.ResourceLimiter, supervisor SupervisorClient) *RegisterTask {
+		return NewSuperCannonRegisterTask(faultTypes.SuperCannonGameType, cfg, m, vm.NewOpProgramServerExecutor(), resources, supervisor)
+	},
+	faultTypes.TraceTypeFast: func(_ *config.Config, _ metrics.Metricer, _ vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewAlphabetRegisterTask(faultTypes.FastGameType)
+	},
+	faultTypes.TraceTypeAlphabet: func(_ *config.Config, _ metrics.Metricer, _ vm.ResourceLimiter, _ SupervisorClient) *RegisterTask {
+		return NewAlphabetRegisterTask(faultTypes.AlphabetGameType)
+	},
+}
+
 func RegisterGameTypes(
 	ctx context.Context,
 	systemClock clock.Clock,
@@ -50,43 +92,40 @@ func RegisterGameTypes(
 	logger log.Logger,
 	m metrics.Metricer,
 	cfg *config.Config,
+	resources vm.ResourceLimiter,
 	registry Registry,
 	oracles OracleRegistry,
 	rollupClient RollupClient,
+	supervisorClient SupervisorClient,
 	txSender TxSender,
 	gameFactory *contracts.DisputeGameFactoryContract,
 	caller *batching.MultiCaller,
 	l1HeaderSource L1HeaderSource,
 	selective bool,
 	claimants []common.Address,
+	deferrer ActionDeferrer,
 ) (CloseFunc, error) {
 	l2Client, err := ethclient.DialContext(ctx, cfg.L2Rpc)
 	if err != nil {
 		return nil, fmt.Errorf("dial l2 client %v: %w", cfg.L2Rpc, err)
 	}
 	syncValidator := newSyncStatusValidator(rollupClient)
+	alerter := divergence.NewAlerter(logger, m, cfg.DivergenceWebhook)
+	participation := policy.NewEvaluator(logger, m, cfg.Participation, claimants)
 
 	var registerTasks []*RegisterTask
-	if cfg.TraceTypeEnabled(faultTypes.TraceTypeCannon) {
-		registerTasks = append(registerTasks, NewCannonRegisterTask(faultTypes.CannonGameType, cfg, m, vm.NewOpProgramServerExecutor()))
-	}
-	if cfg.TraceTypeEnabled(faultTypes.TraceTypePermissioned) {
-		registerTasks = append(registerTasks, NewCannonRegisterTask(faultTypes.PermissionedGameType, cfg, m, vm.NewOpProgramServerExecutor()))
-	}
-	if cfg.TraceTypeEnabled(faultTypes.TraceTypeAsterisc) {
-		registerTasks = append(registerTasks, NewAsteriscRegisterTask(faultTypes.AsteriscGameType, cfg, m, vm.NewOpProgramServerExecutor()))
-	}
-	if cfg.TraceTypeEnabled(faultTypes.TraceTypeAsteriscKona) {
-		registerTasks = append(registerTasks, NewAsteriscKonaRegisterTask(faultTypes.AsteriscKonaGameType, cfg, m, vm.NewKonaExecutor()))
-	}
-	if cfg.TraceTypeEnabled(faultTypes.TraceTypeFast) {
-		registerTasks = append(registerTasks, NewAlphabetRegisterTask(faultTypes.FastGameType))
-	}
-	if cfg.TraceTypeEnabled(faultTypes.TraceTypeAlphabet) {
-		registerTasks = append(registerTasks, NewAlphabetRegisterTask(faultTypes.AlphabetGameType))
+	for _, traceType := range faultTypes.TraceTypes {
+		if !cfg.TraceTypeEnabled(traceType) {
+			continue
+		}
+		createTask, ok := registerTaskCreators[traceType]
+		if !ok {
+			return nil, fmt.Errorf("no game type available for trace type %v", traceType)
+		}
+		registerTasks = append(registerTasks, createTask(cfg, m, resources, supervisorClient))
 	}
 	for _, task := range registerTasks {
-		if err := task.Register(ctx, registry, oracles, systemClock, l1Clock, logger, m, syncValidator, rollupClient, txSender, gameFactory, caller, l2Client, l1HeaderSource, selective, claimants); err != nil {
+		if err := task.Register(ctx, registry, oracles, systemClock, l1Clock, logger, m, syncValidator, rollupClient, txSender, gameFactory, caller, l2Client, l1HeaderSource, selective, claimants, alerter, participation, deferrer); err != nil {
 			return nil, fmt.Errorf("failed to register %v game type: %w", task.gameType, err)
 		}
 	}
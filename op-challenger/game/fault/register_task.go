@@ -9,12 +9,15 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/divergence"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/alphabet"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/asterisc"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/cannon"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/outputs"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/prestates"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/super"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
 	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
@@ -32,6 +35,17 @@ import (
 type RegisterTask struct {
 	gameType faultTypes.GameType
 
+	// syncValidator checks the source of the trace is in sync. Defaults to the rollup node's sync status.
+	syncValidator SyncValidator
+	// startingRootName names the game's starting root in validation errors. Defaults to "output root".
+	startingRootName string
+	// newPrestateProvider creates the provider of the game's starting root. Defaults to the output root at the
+	// prestate block.
+	newPrestateProvider func(rollupClient outputs.OutputRollupClient, prestateBlock uint64) faultTypes.PrestateProvider
+	// noVMStatus is set when the final state of the bottom game doesn't report whether the disputed output root is
+	// valid, so divergences between the trace and the output root can't be detected.
+	noVMStatus bool
+
 	getPrestateProvider func(prestateHash common.Hash) (faultTypes.PrestateProvider, error)
 	newTraceAccessor    func(
 		logger log.Logger,
@@ -47,7 +61,7 @@ type RegisterTask struct {
 		poststateBlock uint64) (*trace.Accessor, error)
 }
 
-func NewCannonRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor) *RegisterTask {
+func NewCannonRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor, resources vm.ResourceLimiter) *RegisterTask {
 	stateConverter := cannon.NewStateConverter()
 	return &RegisterTask{
 		gameType: gameType,
@@ -74,12 +88,44 @@ func NewCannonRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m c
 			prestateBlock uint64,
 			poststateBlock uint64) (*trace.Accessor, error) {
 			provider := vmPrestateProvider.(*vm.PrestateProvider)
-			return outputs.NewOutputCannonTraceAccessor(logger, m, cfg.Cannon, serverExecutor, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
+			return outputs.NewOutputCannonTraceAccessor(logger, m, cfg.Cannon, serverExecutor, resources, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
 		},
 	}
 }
 
-func NewAsteriscRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor) *RegisterTask {
+func NewCannonKonaRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor, resources vm.ResourceLimiter) *RegisterTask {
+	stateConverter := cannon.NewStateConverter()
+	return &RegisterTask{
+		gameType: gameType,
+		getPrestateProvider: cachePrestates(
+			gameType,
+			stateConverter,
+			m,
+			cfg.CannonKonaAbsolutePreStateBaseURL,
+			cfg.CannonKonaAbsolutePreState,
+			filepath.Join(cfg.Datadir, "cannon-kona-prestates"),
+			func(path string) faultTypes.PrestateProvider {
+				return vm.NewPrestateProvider(path, stateConverter)
+			}),
+		newTraceAccessor: func(
+			logger log.Logger,
+			m metrics.Metricer,
+			l2Client utils.L2HeaderSource,
+			prestateProvider faultTypes.PrestateProvider,
+			vmPrestateProvider faultTypes.PrestateProvider,
+			rollupClient outputs.OutputRollupClient,
+			dir string,
+			l1Head eth.BlockID,
+			splitDepth faultTypes.Depth,
+			prestateBlock uint64,
+			poststateBlock uint64) (*trace.Accessor, error) {
+			provider := vmPrestateProvider.(*vm.PrestateProvider)
+			return outputs.NewOutputCannonTraceAccessor(logger, m, cfg.CannonKona, serverExecutor, resources, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
+		},
+	}
+}
+
+func NewAsteriscRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor, resources vm.ResourceLimiter) *RegisterTask {
 	stateConverter := asterisc.NewStateConverter()
 	return &RegisterTask{
 		gameType: gameType,
@@ -106,12 +152,12 @@ func NewAsteriscRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m
 			prestateBlock uint64,
 			poststateBlock uint64) (*trace.Accessor, error) {
 			provider := vmPrestateProvider.(*vm.PrestateProvider)
-			return outputs.NewOutputAsteriscTraceAccessor(logger, m, cfg.Asterisc, serverExecutor, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
+			return outputs.NewOutputAsteriscTraceAccessor(logger, m, cfg.Asterisc, serverExecutor, resources, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
 		},
 	}
 }
 
-func NewAsteriscKonaRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor) *RegisterTask {
+func NewAsteriscKonaRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor, resources vm.ResourceLimiter) *RegisterTask {
 	stateConverter := asterisc.NewStateConverter()
 	return &RegisterTask{
 		gameType: gameType,
@@ -138,14 +184,15 @@ func NewAsteriscKonaRegisterTask(gameType faultTypes.GameType, cfg *config.Confi
 			prestateBlock uint64,
 			poststateBlock uint64) (*trace.Accessor, error) {
 			provider := vmPrestateProvider.(*vm.PrestateProvider)
-			return outputs.NewOutputAsteriscTraceAccessor(logger, m, cfg.AsteriscKona, serverExecutor, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
+			return outputs.NewOutputAsteriscTraceAccessor(logger, m, cfg.AsteriscKona, serverExecutor, resources, l2Client, prestateProvider, provider.PrestatePath(), rollupClient, dir, l1Head, splitDepth, prestateBlock, poststateBlock)
 		},
 	}
 }
 
 func NewAlphabetRegisterTask(gameType faultTypes.GameType) *RegisterTask {
 	return &RegisterTask{
-		gameType: gameType,
+		gameType:   gameType,
+		noVMStatus: true,
 		getPrestateProvider: func(_ common.Hash) (faultTypes.PrestateProvider, error) {
 			return alphabet.PrestateProvider, nil
 		},
@@ -166,6 +213,44 @@ func NewAlphabetRegisterTask(gameType faultTypes.GameType) *RegisterTask {
 	}
 }
 
+func NewSuperCannonRegisterTask(gameType faultTypes.GameType, cfg *config.Config, m caching.Metrics, serverExecutor vm.OracleServerExecutor, resources vm.ResourceLimiter, supervisor SupervisorClient) *RegisterTask {
+	stateConverter := cannon.NewStateConverter()
+	return &RegisterTask{
+		gameType:         gameType,
+		syncValidator:    newSupervisorSyncValidator(supervisor),
+		startingRootName: "super root",
+		newPrestateProvider: func(_ outputs.OutputRollupClient, prestateTimestamp uint64) faultTypes.PrestateProvider {
+			return super.NewSuperRootPrestateProvider(supervisor, prestateTimestamp)
+		},
+		getPrestateProvider: cachePrestates(
+			gameType,
+			stateConverter,
+			m,
+			cfg.CannonAbsolutePreStateBaseURL,
+			cfg.CannonAbsolutePreState,
+			filepath.Join(cfg.Datadir, "cannon-prestates"),
+			func(path string) faultTypes.PrestateProvider {
+				return vm.NewPrestateProvider(path, stateConverter)
+			}),
+		newTraceAccessor: func(
+			logger log.Logger,
+			m metrics.Metricer,
+			_ utils.L2HeaderSource,
+			prestateProvider faultTypes.PrestateProvider,
+			vmPrestateProvider faultTypes.PrestateProvider,
+			_ outputs.OutputRollupClient,
+			dir string,
+			l1Head eth.BlockID,
+			splitDepth faultTypes.Depth,
+			prestateTimestamp uint64,
+			poststateTimestamp uint64) (*trace.Accessor, error) {
+			provider := vmPrestateProvider.(*vm.PrestateProvider)
+			superPrestateProvider := prestateProvider.(*super.SuperRootPrestateProvider)
+			return super.NewSuperCannonTraceAccessor(logger, m, cfg.Cannon, serverExecutor, resources, superPrestateProvider, supervisor, provider.PrestatePath(), dir, l1Head, splitDepth, prestateTimestamp, poststateTimestamp)
+		},
+	}
+}
+
 func cachePrestates(
 	gameType faultTypes.GameType,
 	stateConverter vm.StateConverter,
@@ -202,13 +287,40 @@ func (e *RegisterTask) Register(
 	l2Client utils.L2HeaderSource,
 	l1HeaderSource L1HeaderSource,
 	selective bool,
-	claimants []common.Address) error {
+	claimants []common.Address,
+	alerts divergence.AlertSink,
+	participation *policy.Evaluator,
+	deferrer ActionDeferrer) error {
 
+	if e.syncValidator != nil {
+		syncValidator = e.syncValidator
+	}
+	startingRootName := "output root"
+	if e.startingRootName != "" {
+		startingRootName = e.startingRootName
+	}
+	newPrestateProvider := func(rollupClient outputs.OutputRollupClient, prestateBlock uint64) faultTypes.PrestateProvider {
+		return outputs.NewPrestateProvider(rollupClient, prestateBlock)
+	}
+	if e.newPrestateProvider != nil {
+		newPrestateProvider = e.newPrestateProvider
+	}
 	playerCreator := func(game types.GameMetadata, dir string) (scheduler.GamePlayer, error) {
 		contract, err := contracts.NewFaultDisputeGameContract(ctx, m, game.Proxy, caller)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create fault dispute game contracts: %w", err)
 		}
+		// For super root games the block range is the range of timestamps covered by the game.
+		prestateBlock, poststateBlock, err := contract.GetBlockRange(ctx)
+		if err != nil {
+			return nil, err
+		}
+		prestateProvider := newPrestateProvider(rollupClient, prestateBlock)
+		if participate, err := participation.Participate(ctx, game.Proxy, contract, newPrestateProvider(rollupClient, poststateBlock)); err != nil {
+			return nil, err
+		} else if !participate {
+			return NewNonParticipatingGamePlayer(ctx, logger, game.Proxy, contract)
+		}
 		requiredPrestatehash, err := contract.GetAbsolutePrestateHash(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load prestate hash for game %v: %w", game.Proxy, err)
@@ -224,10 +336,6 @@ func (e *RegisterTask) Register(
 			return nil, fmt.Errorf("failed to load oracle for game %v: %w", game.Proxy, err)
 		}
 		oracles.RegisterOracle(oracle)
-		prestateBlock, poststateBlock, err := contract.GetBlockRange(ctx)
-		if err != nil {
-			return nil, err
-		}
 		splitDepth, err := contract.GetSplitDepth(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load split depth: %w", err)
@@ -236,7 +344,6 @@ func (e *RegisterTask) Register(
 		if err != nil {
 			return nil, err
 		}
-		prestateProvider := outputs.NewPrestateProvider(rollupClient, prestateBlock)
 		creator := func(ctx context.Context, logger log.Logger, gameDepth faultTypes.Depth, dir string) (faultTypes.TraceAccessor, error) {
 			accessor, err := e.newTraceAccessor(logger, m, l2Client, prestateProvider, vmPrestateProvider, rollupClient, dir, l1HeadID, splitDepth, prestateBlock, poststateBlock)
 			if err != nil {
@@ -244,9 +351,12 @@ func (e *RegisterTask) Register(
 			}
 			return accessor, nil
 		}
+		detectorCreator := func(logger log.Logger, accessor faultTypes.TraceAccessor) DivergenceDetector {
+			return divergence.NewDetector(logger, alerts, game.Proxy, accessor, claimants, splitDepth, !e.noVMStatus)
+		}
 		prestateValidator := NewPrestateValidator(e.gameType.String(), contract.GetAbsolutePrestateHash, vmPrestateProvider)
-		startingValidator := NewPrestateValidator("output root", contract.GetStartingRootHash, prestateProvider)
-		return NewGamePlayer(ctx, systemClock, l1Clock, logger, m, dir, game.Proxy, txSender, contract, syncValidator, []Validator{prestateValidator, startingValidator}, creator, l1HeaderSource, selective, claimants)
+		startingValidator := NewPrestateValidator(startingRootName, contract.GetStartingRootHash, prestateProvider)
+		return NewGamePlayer(ctx, systemClock, l1Clock, logger, m, dir, game.Proxy, txSender, contract, syncValidator, []Validator{prestateValidator, startingValidator}, creator, l1HeaderSource, selective, claimants, detectorCreator, deferrer)
 	}
 	err := registerOracle(ctx, m, oracles, gameFactory, caller, e.gameType)
 	if err != nil {
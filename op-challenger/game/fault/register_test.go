@@ -0,0 +1,23 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterTaskCreators(t *testing.T) {
+	cfg := &config.Config{Datadir: t.TempDir()}
+	for _, traceType := range faultTypes.TraceTypes {
+		traceType := traceType
+		t.Run(traceType.String(), func(t *testing.T) {
+			createTask, ok := registerTaskCreators[traceType]
+			require.True(t, ok, "no register task for trace type")
+			task := createTask(cfg, metrics.NoopMetrics, nil, nil)
+			require.Equal(t, traceType.GameType(), task.gameType)
+		})
+	}
+}
@@ -30,6 +30,7 @@ type Oracle interface {
 
 type TxSender interface {
 	SendAndWaitSimple(txPurpose string, txs ...txmgr.TxCandidate) error
+	SendAndWaitBatched(txPurpose string, txs ...txmgr.TxCandidate) []error
 }
 
 // FaultResponder implements the [Responder] interface to send onchain transactions.
@@ -75,6 +76,7 @@ func (r *FaultResponder) CallResolveClaim(ctx context.Context, claimIdx uint64)
 }
 
 // ResolveClaims executes resolveClaim transactions to resolve claims in a dispute game.
+// The transactions may be combined into batches, preserving the order claims are resolved in.
 func (r *FaultResponder) ResolveClaims(claimIdxs ...uint64) error {
 	txs := make([]txmgr.TxCandidate, 0, len(claimIdxs))
 	for _, claimIdx := range claimIdxs {
@@ -84,7 +86,7 @@ func (r *FaultResponder) ResolveClaims(claimIdxs ...uint64) error {
 		}
 		txs = append(txs, candidate)
 	}
-	return r.sender.SendAndWaitSimple("resolve claim", txs...)
+	return errors.Join(r.sender.SendAndWaitBatched("resolve claim", txs...)...)
 }
 
 func (r *FaultResponder) PerformAction(ctx context.Context, action types.Action) error {
@@ -100,6 +100,7 @@ func TestResolveClaim(t *testing.T) {
 		err := responder.ResolveClaims(0, 1, 2, 3)
 		require.NoError(t, err)
 		require.Equal(t, 4, mockTxMgr.sends)
+		require.Equal(t, 1, mockTxMgr.batchedSends, "should send claim resolutions as a batch")
 	})
 }
 
@@ -342,6 +343,8 @@ type mockTxManager struct {
 	sends     int
 	sent      []txmgr.TxCandidate
 	sendFails bool
+
+	batchedSends int
 }
 
 func (m *mockTxManager) SendAndWaitSimple(_ string, txs ...txmgr.TxCandidate) error {
@@ -355,6 +358,11 @@ func (m *mockTxManager) SendAndWaitSimple(_ string, txs ...txmgr.TxCandidate) er
 	return nil
 }
 
+func (m *mockTxManager) SendAndWaitBatched(purpose string, txs ...txmgr.TxCandidate) []error {
+	m.batchedSends++
+	return []error{m.SendAndWaitSimple(purpose, txs...)}
+}
+
 func (m *mockTxManager) BlockNumber(_ context.Context) (uint64, error) {
 	panic("not implemented")
 }
@@ -24,9 +24,19 @@ func (s *GameSolver) AgreeWithRootClaim(ctx context.Context, game types.Game) (b
 }
 
 func (s *GameSolver) CalculateNextActions(ctx context.Context, game types.Game) ([]types.Action, error) {
+	actions, _, err := s.CalculateNextActionsAndAssessment(ctx, game)
+	return actions, err
+}
+
+// CalculateNextActionsAndAssessment calculates the next actions the same as CalculateNextActions and also returns the
+// honest actor's assessment of the existing claims in the game.
+// If an error is returned, the assessment only covers the claims that were processed before the error occurred.
+func (s *GameSolver) CalculateNextActionsAndAssessment(ctx context.Context, game types.Game) ([]types.Action, *ClaimAssessment, error) {
+	agreedClaims := newHonestClaimTracker()
+	assessment := &ClaimAssessment{honest: agreedClaims}
 	agreeWithRootClaim, err := s.AgreeWithRootClaim(ctx, game)
 	if err != nil {
-		return nil, fmt.Errorf("failed to determine if root claim is correct: %w", err)
+		return nil, assessment, fmt.Errorf("failed to determine if root claim is correct: %w", err)
 	}
 
 	// Challenging the L2 block number will only work if we have the same output root as the claim
@@ -36,19 +46,18 @@ func (s *GameSolver) CalculateNextActions(ctx context.Context, game types.Game)
 			// We agree with the L2 block number, proceed to processing claims
 		} else if err != nil {
 			// Failed to check L2 block validity
-			return nil, fmt.Errorf("failed to determine L2 block validity: %w", err)
+			return nil, assessment, fmt.Errorf("failed to determine L2 block validity: %w", err)
 		} else {
 			return []types.Action{
 				{
 					Type:                          types.ActionTypeChallengeL2BlockNumber,
 					InvalidL2BlockNumberChallenge: challenge,
 				},
-			}, nil
+			}, assessment, nil
 		}
 	}
 
 	var actions []types.Action
-	agreedClaims := newHonestClaimTracker()
 	if agreeWithRootClaim {
 		agreedClaims.AddHonestClaim(types.Claim{}, game.Claims()[0])
 	}
@@ -63,14 +72,17 @@ func (s *GameSolver) CalculateNextActions(ctx context.Context, game types.Game)
 			// Unable to continue iterating claims safely because we may not have tracked the required honest moves
 			// for this claim which affects the response to later claims.
 			// Any actions we've already identified are still safe to apply.
-			return actions, fmt.Errorf("failed to determine response to claim %v: %w", claim.ContractIndex, err)
+			return actions, assessment, fmt.Errorf("failed to determine response to claim %v: %w", claim.ContractIndex, err)
 		}
 		if action == nil {
 			continue
 		}
+		if action.Type == types.ActionTypeStep {
+			assessment.stepped = append(assessment.stepped, action.ParentClaim.ContractIndex)
+		}
 		actions = append(actions, *action)
 	}
-	return actions, nil
+	return actions, assessment, nil
 }
 
 func (s *GameSolver) calculateStep(ctx context.Context, game types.Game, claim types.Claim, agreedClaims *honestClaimTracker) (*types.Action, error) {
@@ -1,6 +1,10 @@
 package solver
 
-import "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+import (
+	"slices"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+)
 
 type honestClaimTracker struct {
 	// agreed tracks the existing claims in the game that the honest actor would make
@@ -34,3 +38,22 @@ func (a *honestClaimTracker) HonestCounter(parent types.Claim) (types.Claim, boo
 	counter, ok := a.counters[parent.ID()]
 	return counter, ok
 }
+
+// ClaimAssessment reports which existing claims the honest actor agrees with and which it counters.
+type ClaimAssessment struct {
+	honest  *honestClaimTracker
+	stepped []int
+}
+
+// Honest returns true if the claim is one the honest actor would make.
+func (a *ClaimAssessment) Honest(claim types.Claim) bool {
+	return a.honest.IsHonest(claim)
+}
+
+// Countered returns true if the honest actor counters the claim, either with a move or a step.
+func (a *ClaimAssessment) Countered(claim types.Claim) bool {
+	if _, ok := a.honest.HonestCounter(claim); ok {
+		return true
+	}
+	return slices.Contains(a.stepped, claim.ContractIndex)
+}
@@ -34,3 +34,28 @@ func (s *syncStatusValidator) ValidateNodeSynced(ctx context.Context, gameL1Head
 	}
 	return nil
 }
+
+type SupervisorSyncStatusProvider interface {
+	SyncStatus(context.Context) (eth.SupervisorSyncStatus, error)
+}
+
+type supervisorSyncValidator struct {
+	statusProvider SupervisorSyncStatusProvider
+}
+
+func newSupervisorSyncValidator(statusProvider SupervisorSyncStatusProvider) *supervisorSyncValidator {
+	return &supervisorSyncValidator{
+		statusProvider: statusProvider,
+	}
+}
+
+func (s *supervisorSyncValidator) ValidateNodeSynced(ctx context.Context, gameL1Head eth.BlockID) error {
+	syncStatus, err := s.statusProvider.SyncStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve supervisor sync status: %w", err)
+	}
+	if syncStatus.MinSyncedL1.Number <= gameL1Head.Number {
+		return fmt.Errorf("%w require L1 block above %v but at %v", ErrNotInSync, gameL1Head.Number, syncStatus.MinSyncedL1.Number)
+	}
+	return nil
+}
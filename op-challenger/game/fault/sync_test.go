@@ -81,3 +81,39 @@ type stubSyncStatusProvider struct {
 func (s *stubSyncStatusProvider) SyncStatus(_ context.Context) (*eth.SyncStatus, error) {
 	return s.status, s.err
 }
+
+func TestSupervisorSyncStatusProvider(t *testing.T) {
+	requestErr := errors.New("boom")
+	tests := []struct {
+		name         string
+		minSyncedL1  uint64
+		statusReqErr error
+		expected     error
+	}{
+		{name: "ErrorFetchingStatus", statusReqErr: requestErr, expected: requestErr},
+		{name: "MinSyncedL1BelowGameL1Head", minSyncedL1: 99, expected: ErrNotInSync},
+		{name: "MinSyncedL1EqualToGameL1Head", minSyncedL1: 100, expected: ErrNotInSync},
+		{name: "MinSyncedL1AboveGameL1Head", minSyncedL1: 101, expected: nil},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			provider := &stubSupervisorSyncStatusProvider{
+				status: eth.SupervisorSyncStatus{MinSyncedL1: eth.L1BlockRef{Number: test.minSyncedL1}},
+				err:    test.statusReqErr,
+			}
+			validator := newSupervisorSyncValidator(provider)
+			err := validator.ValidateNodeSynced(context.Background(), eth.BlockID{Number: 100})
+			require.ErrorIs(t, err, test.expected)
+		})
+	}
+}
+
+type stubSupervisorSyncStatusProvider struct {
+	status eth.SupervisorSyncStatus
+	err    error
+}
+
+func (s *stubSupervisorSyncStatusProvider) SyncStatus(_ context.Context) (eth.SupervisorSyncStatus, error) {
+	return s.status, s.err
+}
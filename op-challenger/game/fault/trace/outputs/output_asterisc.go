@@ -23,6 +23,7 @@ func NewOutputAsteriscTraceAccessor(
 	m metrics.Metricer,
 	cfg vm.Config,
 	vmCfg vm.OracleServerExecutor,
+	resources vm.ResourceLimiter,
 	l2Client utils.L2HeaderSource,
 	prestateProvider types.PrestateProvider,
 	asteriscPrestate string,
@@ -41,7 +42,7 @@ func NewOutputAsteriscTraceAccessor(
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch asterisc local inputs: %w", err)
 		}
-		provider := asterisc.NewTraceProvider(logger, m.VmMetrics(cfg.VmType.String()), cfg, vmCfg, prestateProvider, asteriscPrestate, localInputs, subdir, depth)
+		provider := asterisc.NewTraceProvider(logger, m.VmMetrics(cfg.VmType.String()), cfg, vmCfg, resources, prestateProvider, asteriscPrestate, localInputs, subdir, depth)
 		return provider, nil
 	}
 
@@ -23,6 +23,7 @@ func NewOutputCannonTraceAccessor(
 	m metrics.Metricer,
 	cfg vm.Config,
 	serverExecutor vm.OracleServerExecutor,
+	resources vm.ResourceLimiter,
 	l2Client utils.L2HeaderSource,
 	prestateProvider types.PrestateProvider,
 	cannonPrestate string,
@@ -41,7 +42,7 @@ func NewOutputCannonTraceAccessor(
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch cannon local inputs: %w", err)
 		}
-		provider := cannon.NewTraceProvider(logger, m.VmMetrics(cfg.VmType.String()), cfg, serverExecutor, prestateProvider, cannonPrestate, localInputs, subdir, depth)
+		provider := cannon.NewTraceProvider(logger, m.VmMetrics(cfg.VmType.String()), cfg, serverExecutor, resources, prestateProvider, cannonPrestate, localInputs, subdir, depth)
 		return provider, nil
 	}
 
@@ -0,0 +1,40 @@
+package super
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var _ PreimagePrestateProvider = (*SuperRootPrestateProvider)(nil)
+
+type SuperRootPrestateProvider struct {
+	provider          RootProvider
+	prestateTimestamp uint64
+}
+
+func NewSuperRootPrestateProvider(provider RootProvider, prestateTimestamp uint64) *SuperRootPrestateProvider {
+	return &SuperRootPrestateProvider{
+		provider:          provider,
+		prestateTimestamp: prestateTimestamp,
+	}
+}
+
+func (s *SuperRootPrestateProvider) AbsolutePreStateCommitment(ctx context.Context) (common.Hash, error) {
+	prestate, err := s.AbsolutePreState(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(prestate), nil
+}
+
+// AbsolutePreState returns the marshaled super root at the prestate timestamp.
+func (s *SuperRootPrestateProvider) AbsolutePreState(ctx context.Context) ([]byte, error) {
+	root, err := s.provider.SuperRootAtTimestamp(ctx, s.prestateTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve super root at timestamp %v: %w", s.prestateTimestamp, err)
+	}
+	return root.Super().Marshal(), nil
+}
@@ -0,0 +1,142 @@
+package super
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	interopTypes "github.com/ethereum-optimism/optimism/op-program/client/interop/types"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	ErrGetStepData = errors.New("GetStepData not supported")
+	ErrIndexTooBig = errors.New("trace index is greater than max uint64")
+)
+
+var _ types.TraceProvider = (*SuperTraceProvider)(nil)
+
+type RootProvider interface {
+	SuperRootAtTimestamp(ctx context.Context, timestamp uint64) (eth.SuperRootResponse, error)
+}
+
+// PreimagePrestateProvider is a [types.PrestateProvider] that also supplies the preimage of the absolute prestate.
+type PreimagePrestateProvider interface {
+	types.PrestateProvider
+	AbsolutePreState(ctx context.Context) ([]byte, error)
+}
+
+// SuperTraceProvider is a [types.TraceProvider] implementation that uses super roots and the transition states
+// between them as a trace. Each timestamp between the prestate and poststate is split into StepsPerTimestamp steps,
+// with the super root for the timestamp reached on the last step.
+type SuperTraceProvider struct {
+	PreimagePrestateProvider
+	logger             log.Logger
+	rootProvider       RootProvider
+	prestateTimestamp  uint64
+	poststateTimestamp uint64
+	l1Head             eth.BlockID
+	gameDepth          types.Depth
+}
+
+func NewTraceProvider(logger log.Logger, prestateProvider PreimagePrestateProvider, rootProvider RootProvider, l1Head eth.BlockID, gameDepth types.Depth, prestateTimestamp, poststateTimestamp uint64) *SuperTraceProvider {
+	return &SuperTraceProvider{
+		PreimagePrestateProvider: prestateProvider,
+		logger:                   logger,
+		rootProvider:             rootProvider,
+		prestateTimestamp:        prestateTimestamp,
+		poststateTimestamp:       poststateTimestamp,
+		l1Head:                   l1Head,
+		gameDepth:                gameDepth,
+	}
+}
+
+// ComputeStep returns the timestamp of the last super root reached at the position and the number of steps taken
+// towards the next super root. Positions after the claimed poststate timestamp all return the poststate super root.
+func (s *SuperTraceProvider) ComputeStep(pos types.Position) (timestamp uint64, step uint64, err error) {
+	traceIndex := pos.TraceIndex(s.gameDepth)
+	if !traceIndex.IsUint64() {
+		return 0, 0, fmt.Errorf("%w: %v", ErrIndexTooBig, traceIndex)
+	}
+	// The value at trace index i is the state after i+1 steps from the prestate.
+	stepCount := traceIndex.Uint64() + 1
+	timestamp = s.prestateTimestamp + stepCount/interopTypes.StepsPerTimestamp
+	step = stepCount % interopTypes.StepsPerTimestamp
+	if stepCount == 0 || timestamp >= s.poststateTimestamp {
+		// stepCount only wraps to 0 for the maximum trace index which is always beyond the poststate
+		return s.poststateTimestamp, 0, nil
+	}
+	return timestamp, step, nil
+}
+
+func (s *SuperTraceProvider) Get(ctx context.Context, pos types.Position) (common.Hash, error) {
+	preimage, err := s.GetPreimageBytes(ctx, pos)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(preimage), nil
+}
+
+// GetPreimageBytes returns the marshaled super root or transition state at the position.
+// States that can't be derived from L1 data up to the game's L1 head are InvalidTransition.
+func (s *SuperTraceProvider) GetPreimageBytes(ctx context.Context, pos types.Position) ([]byte, error) {
+	timestamp, step, err := s.ComputeStep(pos)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.rootProvider.SuperRootAtTimestamp(ctx, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve super root at timestamp %v: %w", timestamp, err)
+	}
+	if root.CrossSafeDerivedFrom.Number > s.l1Head.Number {
+		return interopTypes.InvalidTransition, nil
+	}
+	superRoot := root.Super().Marshal()
+	if step == 0 {
+		return superRoot, nil
+	}
+
+	next, err := s.rootProvider.SuperRootAtTimestamp(ctx, timestamp+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve super root at timestamp %v: %w", timestamp+1, err)
+	}
+	if next.CrossSafeDerivedFrom.Number > s.l1Head.Number {
+		return interopTypes.InvalidTransition, nil
+	}
+	progress := make([]interopTypes.OptimisticBlock, 0, min(step, uint64(len(next.Chains))))
+	for i := uint64(0); i < step && i < uint64(len(next.Chains)); i++ {
+		chain := next.Chains[i]
+		output, err := eth.UnmarshalOutput(chain.Pending)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pending output for chain %v at timestamp %v: %w", chain.ChainID, timestamp+1, err)
+		}
+		outputV0, ok := output.(*eth.OutputV0)
+		if !ok {
+			return nil, fmt.Errorf("unsupported pending output version %v for chain %v", output.Version(), chain.ChainID)
+		}
+		progress = append(progress, interopTypes.OptimisticBlock{
+			BlockHash:  outputV0.BlockHash,
+			OutputRoot: common.Hash(eth.OutputRoot(outputV0)),
+		})
+	}
+	state := &interopTypes.TransitionState{
+		SuperRoot:       superRoot,
+		PendingProgress: progress,
+		Step:            step,
+	}
+	return state.Marshal(), nil
+}
+
+// GetStepData is not supported in the [SuperTraceProvider].
+func (s *SuperTraceProvider) GetStepData(_ context.Context, _ types.Position) (prestate []byte, proofData []byte, preimageData *types.PreimageOracleData, err error) {
+	return nil, nil, nil, ErrGetStepData
+}
+
+// GetL2BlockNumberChallenge is not supported for super roots, where the claimed timestamp is always treated as valid.
+func (s *SuperTraceProvider) GetL2BlockNumberChallenge(_ context.Context) (*types.InvalidL2BlockNumberChallenge, error) {
+	return nil, types.ErrL2BlockNumberValid
+}
@@ -0,0 +1,197 @@
+package super
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	interopTypes "github.com/ethereum-optimism/optimism/op-program/client/interop/types"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	prestateTimestamp  = uint64(1000)
+	poststateTimestamp = uint64(1002)
+	gameDepth          = types.Depth(12) // 4096 leaf nodes, enough for 4 timestamps
+	l1Head             = eth.BlockID{Hash: common.Hash{0xaa}, Number: 500}
+)
+
+func TestComputeStep(t *testing.T) {
+	provider, _ := setup(t)
+	tests := []struct {
+		name      string
+		pos       types.Position
+		timestamp uint64
+		step      uint64
+	}{
+		{"FirstStep", types.NewPosition(gameDepth, big.NewInt(0)), prestateTimestamp, 1},
+		{"LastTransitionStep", types.NewPosition(gameDepth, big.NewInt(interopTypes.StepsPerTimestamp-2)), prestateTimestamp, interopTypes.StepsPerTimestamp - 1},
+		{"FirstSuperRoot", types.NewPosition(gameDepth, big.NewInt(interopTypes.StepsPerTimestamp-1)), prestateTimestamp + 1, 0},
+		{"SecondTimestampStep", types.NewPosition(gameDepth, big.NewInt(interopTypes.StepsPerTimestamp)), prestateTimestamp + 1, 1},
+		{"PostState", types.NewPosition(gameDepth, big.NewInt(2*interopTypes.StepsPerTimestamp-1)), poststateTimestamp, 0},
+		{"AfterPostState", types.NewPosition(gameDepth, big.NewInt(3*interopTypes.StepsPerTimestamp)), poststateTimestamp, 0},
+		{"Root", types.RootPosition, poststateTimestamp, 0},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			timestamp, step, err := provider.ComputeStep(test.pos)
+			require.NoError(t, err)
+			require.Equal(t, test.timestamp, timestamp)
+			require.Equal(t, test.step, step)
+		})
+	}
+
+	t.Run("ErrorsTraceIndexOutOfBounds", func(t *testing.T) {
+		deepProvider := NewTraceProvider(testlog.Logger(t, log.LevelInfo), nil, nil, l1Head, types.Depth(65), prestateTimestamp, poststateTimestamp)
+		_, _, err := deepProvider.ComputeStep(types.NewPosition(types.Depth(65), new(big.Int).Lsh(big.NewInt(1), 64)))
+		require.ErrorIs(t, err, ErrIndexTooBig)
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("SuperRoot", func(t *testing.T) {
+		provider, roots := setup(t)
+		value, err := provider.Get(context.Background(), types.NewPosition(gameDepth, big.NewInt(interopTypes.StepsPerTimestamp-1)))
+		require.NoError(t, err)
+		expected := roots.responses[prestateTimestamp+1]
+		require.Equal(t, common.Hash(expected.SuperRoot), value)
+	})
+
+	t.Run("FirstStep", func(t *testing.T) {
+		provider, roots := setup(t)
+		preimage, err := provider.GetPreimageBytes(context.Background(), types.NewPosition(gameDepth, big.NewInt(0)))
+		require.NoError(t, err)
+		state, err := interopTypes.UnmarshalTransitionState(preimage)
+		require.NoError(t, err)
+		prev := roots.responses[prestateTimestamp]
+		require.Equal(t, prev.Super().Marshal(), state.SuperRoot)
+		require.Equal(t, uint64(1), state.Step)
+		require.Equal(t, []interopTypes.OptimisticBlock{pendingBlock(prestateTimestamp+1, 0)}, state.PendingProgress)
+
+		value, err := provider.Get(context.Background(), types.NewPosition(gameDepth, big.NewInt(0)))
+		require.NoError(t, err)
+		require.Equal(t, state.Hash(), value)
+	})
+
+	t.Run("AllChainsProgressed", func(t *testing.T) {
+		provider, _ := setup(t)
+		preimage, err := provider.GetPreimageBytes(context.Background(), types.NewPosition(gameDepth, big.NewInt(10)))
+		require.NoError(t, err)
+		state, err := interopTypes.UnmarshalTransitionState(preimage)
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), state.Step)
+		require.Equal(t, []interopTypes.OptimisticBlock{pendingBlock(prestateTimestamp+1, 0), pendingBlock(prestateTimestamp+1, 1)}, state.PendingProgress)
+	})
+
+	t.Run("SuperRootNotDerivedFromL1Head", func(t *testing.T) {
+		provider, roots := setup(t)
+		roots.setDerivedFrom(prestateTimestamp+1, l1Head.Number+1)
+		value, err := provider.Get(context.Background(), types.NewPosition(gameDepth, big.NewInt(interopTypes.StepsPerTimestamp-1)))
+		require.NoError(t, err)
+		require.Equal(t, interopTypes.InvalidTransitionHash, value)
+	})
+
+	t.Run("NextSuperRootNotDerivedFromL1Head", func(t *testing.T) {
+		provider, roots := setup(t)
+		roots.setDerivedFrom(prestateTimestamp+1, l1Head.Number+1)
+		value, err := provider.Get(context.Background(), types.NewPosition(gameDepth, big.NewInt(0)))
+		require.NoError(t, err)
+		require.Equal(t, interopTypes.InvalidTransitionHash, value)
+	})
+
+	t.Run("MissingSuperRoot", func(t *testing.T) {
+		provider, roots := setup(t)
+		delete(roots.responses, prestateTimestamp+1)
+		_, err := provider.Get(context.Background(), types.NewPosition(gameDepth, big.NewInt(0)))
+		require.ErrorContains(t, err, "no super root")
+	})
+}
+
+func TestGetStepDataUnsupported(t *testing.T) {
+	provider, _ := setup(t)
+	_, _, _, err := provider.GetStepData(context.Background(), types.RootPosition)
+	require.ErrorIs(t, err, ErrGetStepData)
+}
+
+func TestGetL2BlockNumberChallenge(t *testing.T) {
+	provider, _ := setup(t)
+	_, err := provider.GetL2BlockNumberChallenge(context.Background())
+	require.ErrorIs(t, err, types.ErrL2BlockNumberValid)
+}
+
+func TestPrestateProvider(t *testing.T) {
+	_, roots := setup(t)
+	prestateProvider := NewSuperRootPrestateProvider(roots, prestateTimestamp)
+	expected := roots.responses[prestateTimestamp]
+	prestate, err := prestateProvider.AbsolutePreState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, expected.Super().Marshal(), prestate)
+	commitment, err := prestateProvider.AbsolutePreStateCommitment(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, common.Hash(expected.SuperRoot), commitment)
+}
+
+func setup(t *testing.T) (*SuperTraceProvider, *stubRootProvider) {
+	roots := &stubRootProvider{responses: make(map[uint64]eth.SuperRootResponse)}
+	for timestamp := prestateTimestamp; timestamp <= poststateTimestamp+1; timestamp++ {
+		roots.add(timestamp)
+	}
+	prestateProvider := NewSuperRootPrestateProvider(roots, prestateTimestamp)
+	provider := NewTraceProvider(testlog.Logger(t, log.LevelInfo), prestateProvider, roots, l1Head, gameDepth, prestateTimestamp, poststateTimestamp)
+	return provider, roots
+}
+
+func pendingOutput(timestamp uint64, chainIdx int) *eth.OutputV0 {
+	return &eth.OutputV0{
+		StateRoot: eth.Bytes32{byte(chainIdx), 0x01},
+		BlockHash: common.BigToHash(new(big.Int).SetUint64(timestamp*10 + uint64(chainIdx))),
+	}
+}
+
+func pendingBlock(timestamp uint64, chainIdx int) interopTypes.OptimisticBlock {
+	output := pendingOutput(timestamp, chainIdx)
+	return interopTypes.OptimisticBlock{BlockHash: output.BlockHash, OutputRoot: common.Hash(eth.OutputRoot(output))}
+}
+
+type stubRootProvider struct {
+	responses map[uint64]eth.SuperRootResponse
+}
+
+func (s *stubRootProvider) add(timestamp uint64) {
+	resp := eth.SuperRootResponse{
+		CrossSafeDerivedFrom: eth.BlockID{Number: l1Head.Number - 1},
+		Timestamp:            hexutil.Uint64(timestamp),
+	}
+	for i := 0; i < 2; i++ {
+		resp.Chains = append(resp.Chains, eth.ChainRootInfo{
+			ChainID:   hexutil.Uint64(i + 1),
+			Canonical: eth.OutputRoot(pendingOutput(timestamp, i)),
+			Pending:   pendingOutput(timestamp, i).Marshal(),
+		})
+	}
+	resp.SuperRoot = eth.Bytes32(crypto.Keccak256Hash(resp.Super().Marshal()))
+	s.responses[timestamp] = resp
+}
+
+func (s *stubRootProvider) setDerivedFrom(timestamp uint64, l1Block uint64) {
+	resp := s.responses[timestamp]
+	resp.CrossSafeDerivedFrom = eth.BlockID{Number: l1Block}
+	s.responses[timestamp] = resp
+}
+
+func (s *stubRootProvider) SuperRootAtTimestamp(_ context.Context, timestamp uint64) (eth.SuperRootResponse, error) {
+	resp, ok := s.responses[timestamp]
+	if !ok {
+		return eth.SuperRootResponse{}, fmt.Errorf("no super root at timestamp %v", timestamp)
+	}
+	return resp, nil
+}
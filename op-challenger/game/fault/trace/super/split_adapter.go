@@ -0,0 +1,46 @@
+package super
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/outputs"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/split"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ProposalTraceProviderCreator creates the bottom half trace provider to execute from the agreed prestate, which is
+// a marshaled super root or transition state, to the claimed post state.
+type ProposalTraceProviderCreator func(ctx context.Context, localContext common.Hash, depth types.Depth, agreedPrestate []byte, claimed common.Hash) (types.TraceProvider, error)
+
+func SuperRootSplitAdapter(topProvider *SuperTraceProvider, creator ProposalTraceProviderCreator) split.ProviderCreator {
+	return func(ctx context.Context, depth types.Depth, pre types.Claim, post types.Claim) (types.TraceProvider, error) {
+		localContext := outputs.CreateLocalContext(pre, post)
+		agreedPrestate, err := FetchAgreedPrestate(ctx, topProvider, pre)
+		if err != nil {
+			return nil, err
+		}
+		return creator(ctx, localContext, depth, agreedPrestate, post.Value)
+	}
+}
+
+// FetchAgreedPrestate returns the preimage of the agreed pre claim, or the absolute prestate if there is no pre claim.
+func FetchAgreedPrestate(ctx context.Context, topProvider *SuperTraceProvider, pre types.Claim) ([]byte, error) {
+	if pre == (types.Claim{}) {
+		prestate, err := topProvider.AbsolutePreState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve absolute prestate super root: %w", err)
+		}
+		return prestate, nil
+	}
+	prestate, err := topProvider.GetPreimageBytes(ctx, pre.Position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve agreed prestate: %w", err)
+	}
+	if hash := crypto.Keccak256Hash(prestate); hash != pre.Value {
+		return nil, fmt.Errorf("agreed prestate %v does not match claim %v", hash, pre.Value)
+	}
+	return prestate, nil
+}
@@ -0,0 +1,62 @@
+package super
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/outputs"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuperRootSplitAdapter(t *testing.T) {
+	t.Run("UsesAbsolutePrestateWhenNoPreClaim", func(t *testing.T) {
+		provider, roots := setup(t)
+		post := types.Claim{ClaimData: types.ClaimData{Value: common.Hash{0xbb}, Position: types.NewPosition(gameDepth, big.NewInt(0))}}
+		creator := &capturingCreator{}
+		_, err := SuperRootSplitAdapter(provider, creator.Create)(context.Background(), gameDepth, types.Claim{}, post)
+		require.NoError(t, err)
+		prestate := roots.responses[prestateTimestamp]
+		require.Equal(t, prestate.Super().Marshal(), creator.agreedPrestate)
+		require.Equal(t, post.Value, creator.claimed)
+		require.Equal(t, outputs.CreateLocalContext(types.Claim{}, post), creator.localContext)
+	})
+
+	t.Run("UsesPreClaimPreimage", func(t *testing.T) {
+		provider, _ := setup(t)
+		prePos := types.NewPosition(gameDepth, big.NewInt(4))
+		expected, err := provider.GetPreimageBytes(context.Background(), prePos)
+		require.NoError(t, err)
+		preValue, err := provider.Get(context.Background(), prePos)
+		require.NoError(t, err)
+		pre := types.Claim{ClaimData: types.ClaimData{Value: preValue, Position: prePos}}
+		post := types.Claim{ClaimData: types.ClaimData{Value: common.Hash{0xbb}, Position: types.NewPosition(gameDepth, big.NewInt(5))}}
+		creator := &capturingCreator{}
+		_, err = SuperRootSplitAdapter(provider, creator.Create)(context.Background(), gameDepth, pre, post)
+		require.NoError(t, err)
+		require.Equal(t, expected, creator.agreedPrestate)
+	})
+
+	t.Run("RejectsMismatchedPreClaim", func(t *testing.T) {
+		provider, _ := setup(t)
+		pre := types.Claim{ClaimData: types.ClaimData{Value: common.Hash{0xaa}, Position: types.NewPosition(gameDepth, big.NewInt(4))}}
+		post := types.Claim{ClaimData: types.ClaimData{Value: common.Hash{0xbb}, Position: types.NewPosition(gameDepth, big.NewInt(5))}}
+		_, err := SuperRootSplitAdapter(provider, (&capturingCreator{}).Create)(context.Background(), gameDepth, pre, post)
+		require.ErrorContains(t, err, "does not match claim")
+	})
+}
+
+type capturingCreator struct {
+	localContext   common.Hash
+	agreedPrestate []byte
+	claimed        common.Hash
+}
+
+func (c *capturingCreator) Create(_ context.Context, localContext common.Hash, _ types.Depth, agreedPrestate []byte, claimed common.Hash) (types.TraceProvider, error) {
+	c.localContext = localContext
+	c.agreedPrestate = agreedPrestate
+	c.claimed = claimed
+	return nil, nil
+}
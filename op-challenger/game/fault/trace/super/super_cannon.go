@@ -0,0 +1,53 @@
+package super
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/cannon"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/split"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+func NewSuperCannonTraceAccessor(
+	logger log.Logger,
+	m metrics.Metricer,
+	cfg vm.Config,
+	serverExecutor vm.OracleServerExecutor,
+	resources vm.ResourceLimiter,
+	prestateProvider PreimagePrestateProvider,
+	rootProvider RootProvider,
+	cannonPrestate string,
+	dir string,
+	l1Head eth.BlockID,
+	splitDepth types.Depth,
+	prestateTimestamp uint64,
+	poststateTimestamp uint64,
+) (*trace.Accessor, error) {
+	superProvider := NewTraceProvider(logger, prestateProvider, rootProvider, l1Head, splitDepth, prestateTimestamp, poststateTimestamp)
+	cannonCreator := func(ctx context.Context, localContext common.Hash, depth types.Depth, agreedPrestate []byte, claimed common.Hash) (types.TraceProvider, error) {
+		logger := logger.New("agreedPrestate", crypto.Keccak256Hash(agreedPrestate), "claim", claimed, "localContext", localContext)
+		subdir := filepath.Join(dir, localContext.Hex())
+		localInputs := utils.LocalGameInputs{
+			L1Head:         l1Head.Hash,
+			L2OutputRoot:   crypto.Keccak256Hash(agreedPrestate),
+			L2Claim:        claimed,
+			L2Timestamp:    poststateTimestamp,
+			AgreedPreState: agreedPrestate,
+		}
+		provider := cannon.NewTraceProvider(logger, m.VmMetrics(cfg.VmType.String()), cfg, serverExecutor, resources, prestateProvider, cannonPrestate, localInputs, subdir, depth)
+		return provider, nil
+	}
+
+	cache := NewProviderCache(m, "super_cannon_provider", cannonCreator)
+	selector := split.NewSplitProviderSelector(superProvider, splitDepth, SuperRootSplitAdapter(superProvider, cache.GetOrCreate))
+	return trace.NewAccessor(selector), nil
+}
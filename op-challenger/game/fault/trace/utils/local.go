@@ -16,6 +16,12 @@ type LocalGameInputs struct {
 	L2OutputRoot  common.Hash
 	L2Claim       common.Hash
 	L2BlockNumber *big.Int
+	// L2Timestamp is the timestamp of the claimed super root.
+	// Only set for super root games, which are not bound to a block number of a single chain.
+	L2Timestamp uint64
+	// AgreedPreState is the marshaled super root or transition state agreed on by the game.
+	// Only set for super root games.
+	AgreedPreState []byte
 }
 
 type L2HeaderSource interface {
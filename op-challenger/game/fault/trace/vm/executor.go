@@ -8,11 +8,13 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -34,6 +36,13 @@ type Config struct {
 	InfoFreq        uint   // Frequency of progress log messages (in VM instructions)
 	DebugInfo       bool   // Whether to record debug info from the execution
 	BinarySnapshots bool   // Whether to use binary snapshots instead of JSON
+	// CompactSnapshots enables snapshots that store memory pages shared between snapshots only once.
+	// Only used with binary snapshots. The shared pages are not removed when pruning old snapshots.
+	CompactSnapshots bool
+
+	// SnapshotCacheDir is the directory used to share snapshots between executions with the same prestate and
+	// local inputs. Snapshots are not shared if empty.
+	SnapshotCacheDir string
 
 	// Host Configuration
 	L1               string
@@ -43,33 +52,69 @@ type Config struct {
 	Network          string
 	RollupConfigPath string
 	L2GenesisPath    string
+
+	// RemoteWorkers is the list of worker URLs to execute the VM on instead of running it locally.
+	RemoteWorkers []string
+}
+
+// SnapshotFormat returns the format of the snapshots created by executions using this config.
+func (c Config) SnapshotFormat() SnapshotFormat {
+	switch {
+	case !c.BinarySnapshots:
+		return SnapshotFormatJSON
+	case c.CompactSnapshots:
+		return SnapshotFormatCompact
+	default:
+		return SnapshotFormatBinary
+	}
 }
 
 type OracleServerExecutor interface {
 	OracleCommand(cfg Config, dataDir string, inputs utils.LocalGameInputs) ([]string, error)
 }
 
+// ResourceLimiter bounds the resources used by concurrent VM executions.
+type ResourceLimiter interface {
+	// Acquire blocks until resources for an execution are available, returning a function to release them.
+	// The execution must use the returned context, which is cancelled if the execution is preempted by a higher
+	// priority execution.
+	Acquire(ctx context.Context) (context.Context, func(), error)
+}
+
 type Executor struct {
 	cfg              Config
 	oracleServer     OracleServerExecutor
+	resources        ResourceLimiter
 	logger           log.Logger
 	metrics          Metricer
 	absolutePreState string
 	inputs           utils.LocalGameInputs
 	selectSnapshot   SnapshotSelect
 	cmdExecutor      CmdExecutor
+
+	snapshotCache *SnapshotCache
+	cacheKeyOnce  sync.Once
+	cacheKey      common.Hash
+	cacheKeyErr   error
 }
 
-func NewExecutor(logger log.Logger, m Metricer, cfg Config, oracleServer OracleServerExecutor, prestate string, inputs utils.LocalGameInputs) *Executor {
+// NewExecutor creates an Executor. If resources is nil, executions are not limited.
+func NewExecutor(logger log.Logger, m Metricer, cfg Config, oracleServer OracleServerExecutor, resources ResourceLimiter, prestate string, inputs utils.LocalGameInputs) *Executor {
+	var snapshotCache *SnapshotCache
+	if cfg.SnapshotCacheDir != "" {
+		snapshotCache = NewSnapshotCache(logger, cfg.SnapshotCacheDir)
+	}
 	return &Executor{
 		cfg:              cfg,
 		oracleServer:     oracleServer,
+		resources:        resources,
 		logger:           logger,
 		metrics:          m,
 		inputs:           inputs,
 		absolutePreState: prestate,
 		selectSnapshot:   FindStartingSnapshot,
 		cmdExecutor:      RunCmd,
+		snapshotCache:    snapshotCache,
 	}
 }
 
@@ -83,10 +128,11 @@ func (e *Executor) GenerateProof(ctx context.Context, dir string, i uint64) erro
 // The proof is stored at the specified directory.
 func (e *Executor) DoGenerateProof(ctx context.Context, dir string, begin uint64, end uint64, extraVmArgs ...string) error {
 	snapshotDir := filepath.Join(dir, SnapsDir)
-	start, err := e.selectSnapshot(e.logger, snapshotDir, e.absolutePreState, begin, e.cfg.BinarySnapshots)
+	start, err := e.selectSnapshot(e.logger, snapshotDir, e.absolutePreState, begin, e.cfg.SnapshotFormat())
 	if err != nil {
 		return fmt.Errorf("find starting snapshot: %w", err)
 	}
+	start = e.cachedStartingSnapshot(start, begin)
 	proofDir := filepath.Join(dir, utils.ProofsDir)
 	dataDir := PreimageDir(dir)
 	lastGeneratedState := FinalStatePath(dir, e.cfg.BinarySnapshots)
@@ -99,11 +145,7 @@ func (e *Executor) DoGenerateProof(ctx context.Context, dir string, begin uint64
 		"--proof-at", "=" + strconv.FormatUint(end, 10),
 		"--proof-fmt", filepath.Join(proofDir, "%d.json.gz"),
 		"--snapshot-at", "%" + strconv.FormatUint(uint64(e.cfg.SnapshotFreq), 10),
-	}
-	if e.cfg.BinarySnapshots {
-		args = append(args, "--snapshot-fmt", filepath.Join(snapshotDir, "%d.bin.gz"))
-	} else {
-		args = append(args, "--snapshot-fmt", filepath.Join(snapshotDir, "%d.json.gz"))
+		"--snapshot-fmt", filepath.Join(snapshotDir, "%d"+string(e.cfg.SnapshotFormat())),
 	}
 	if end < math.MaxUint64 {
 		args = append(args, "--stop-at", "="+strconv.FormatUint(end+1, 10))
@@ -128,9 +170,25 @@ func (e *Executor) DoGenerateProof(ctx context.Context, dir string, begin uint64
 	if err := os.MkdirAll(proofDir, 0755); err != nil {
 		return fmt.Errorf("could not create proofs directory %v: %w", proofDir, err)
 	}
+	execCtx := ctx
+	if e.resources != nil {
+		waitStart := time.Now()
+		var release func()
+		execCtx, release, err = e.resources.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to acquire resources for vm execution: %w", err)
+		}
+		defer release()
+		e.logger.Debug("Acquired resources for vm execution", "wait", time.Since(waitStart))
+	}
 	e.logger.Info("Generating trace", "proof", end, "cmd", e.cfg.VmBin, "args", strings.Join(args, ", "))
 	execStart := time.Now()
-	err = e.cmdExecutor(ctx, e.logger.New("proof", end), e.cfg.VmBin, args...)
+	err = e.cmdExecutor(execCtx, e.logger.New("proof", end), e.cfg.VmBin, args...)
+	if err != nil && ctx.Err() == nil && execCtx.Err() != nil {
+		// Snapshots written before the interruption are kept so the execution resumes from them when retried.
+		e.logger.Warn("VM execution interrupted", "proof", end, "cause", context.Cause(execCtx))
+		err = fmt.Errorf("vm execution interrupted: %w", context.Cause(execCtx))
+	}
 	execTime := time.Since(execStart)
 	memoryUsed := "unknown"
 	e.metrics.RecordExecutionTime(execTime)
@@ -143,9 +201,57 @@ func (e *Executor) DoGenerateProof(ctx context.Context, dir string, begin uint64
 		}
 	}
 	e.logger.Info("VM execution complete", "time", execTime, "memory", memoryUsed)
+	if err == nil {
+		e.cacheSnapshots(snapshotDir)
+	}
 	return err
 }
 
+// cachedStartingSnapshot returns the cached snapshot to start execution from if it is closer to traceIndex than the
+// local starting snapshot, otherwise returns localStart.
+func (e *Executor) cachedStartingSnapshot(localStart string, traceIndex uint64) string {
+	key, ok := e.snapshotCacheKey()
+	if !ok {
+		return localStart
+	}
+	cached, cachedIndex := e.snapshotCache.FindSnapshot(key, traceIndex, e.cfg.SnapshotFormat())
+	if cached == "" {
+		return localStart
+	}
+	if localStart != e.absolutePreState {
+		localIndex, err := strconv.ParseUint(strings.SplitN(filepath.Base(localStart), ".", 2)[0], 10, 64)
+		if err == nil && localIndex >= cachedIndex {
+			return localStart
+		}
+	}
+	e.logger.Info("Starting from cached snapshot", "snapshot", cached, "index", cachedIndex)
+	return cached
+}
+
+// cacheSnapshots stores the snapshots created by executions so they can be reused by other games.
+func (e *Executor) cacheSnapshots(snapshotDir string) {
+	key, ok := e.snapshotCacheKey()
+	if !ok {
+		return
+	}
+	if err := e.snapshotCache.Store(key, snapshotDir, e.cfg.SnapshotFormat()); err != nil {
+		e.logger.Warn("Failed to cache snapshots", "err", err)
+	}
+}
+
+func (e *Executor) snapshotCacheKey() (common.Hash, bool) {
+	if e.snapshotCache == nil {
+		return common.Hash{}, false
+	}
+	e.cacheKeyOnce.Do(func() {
+		e.cacheKey, e.cacheKeyErr = SnapshotCacheKey(e.cfg.VmType, e.absolutePreState, e.inputs)
+		if e.cacheKeyErr != nil {
+			e.logger.Warn("Snapshot cache disabled, failed to determine cache key", "err", e.cacheKeyErr)
+		}
+	})
+	return e.cacheKey, e.cacheKeyErr == nil
+}
+
 type debugInfo struct {
 	MemoryUsed hexutil.Uint64 `json:"memory_used"`
 }
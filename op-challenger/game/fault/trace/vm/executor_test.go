@@ -2,6 +2,7 @@ package vm
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/big"
 	"path/filepath"
@@ -41,8 +42,8 @@ func TestGenerateProof(t *testing.T) {
 	}
 	captureExec := func(t *testing.T, cfg Config, proofAt uint64) (string, string, map[string]string) {
 		m := &stubVmMetrics{}
-		executor := NewExecutor(testlog.Logger(t, log.LevelInfo), m, cfg, NewOpProgramServerExecutor(), prestate, inputs)
-		executor.selectSnapshot = func(logger log.Logger, dir string, absolutePreState string, i uint64, binary bool) (string, error) {
+		executor := NewExecutor(testlog.Logger(t, log.LevelInfo), m, cfg, NewOpProgramServerExecutor(), nil, prestate, inputs)
+		executor.selectSnapshot = func(logger log.Logger, dir string, absolutePreState string, i uint64, format SnapshotFormat) (string, error) {
 			return input, nil
 		}
 		var binary string
@@ -141,6 +142,15 @@ func TestGenerateProof(t *testing.T) {
 		_, _, args := captureExec(t, cfg, 100)
 		require.Equal(t, filepath.Join(dir, SnapsDir, "%d.json.gz"), args["--snapshot-fmt"])
 	})
+
+	t.Run("CompactSnapshots", func(t *testing.T) {
+		cfg.Network = "mainnet"
+		cfg.BinarySnapshots = true
+		cfg.CompactSnapshots = true
+		_, _, args := captureExec(t, cfg, 100)
+		require.Equal(t, filepath.Join(dir, SnapsDir, "%d.snap.gz"), args["--snapshot-fmt"])
+		require.Equal(t, FinalStatePath(dir, cfg.BinarySnapshots), args["--output"])
+	})
 }
 
 type stubVmMetrics struct {
@@ -153,3 +163,39 @@ func (c *stubVmMetrics) RecordExecutionTime(_ time.Duration) {
 
 func (c *stubVmMetrics) RecordMemoryUsed(_ uint64) {
 }
+
+func TestGenerateProofPreempted(t *testing.T) {
+	cfg := Config{
+		VmType:       "test",
+		VmBin:        "./bin/testvm",
+		Server:       "./bin/testserver",
+		Network:      "op-test",
+		SnapshotFreq: 500,
+		InfoFreq:     900,
+	}
+	inputs := utils.LocalGameInputs{L2BlockNumber: big.NewInt(3333)}
+	preempted := errors.New("preempted")
+	limiter := &stubResourceLimiter{cause: preempted}
+	executor := NewExecutor(testlog.Logger(t, log.LevelInfo), &stubVmMetrics{}, cfg, NewOpProgramServerExecutor(), limiter, "pre.json", inputs)
+	executor.selectSnapshot = func(logger log.Logger, dir string, absolutePreState string, i uint64, format SnapshotFormat) (string, error) {
+		return "starting.json", nil
+	}
+	executor.cmdExecutor = func(ctx context.Context, l log.Logger, b string, a ...string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	err := executor.GenerateProof(context.Background(), t.TempDir(), 1000)
+	require.ErrorIs(t, err, preempted)
+	require.True(t, limiter.released)
+}
+
+type stubResourceLimiter struct {
+	cause    error
+	released bool
+}
+
+func (s *stubResourceLimiter) Acquire(ctx context.Context) (context.Context, func(), error) {
+	execCtx, cancel := context.WithCancelCause(ctx)
+	cancel(s.cause)
+	return execCtx, func() { s.released = true }, nil
+}
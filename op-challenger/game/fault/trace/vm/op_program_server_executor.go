@@ -1,7 +1,10 @@
 package vm
 
 import (
+	"strconv"
+
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 type OpProgramServerExecutor struct {
@@ -21,10 +24,20 @@ func (s *OpProgramServerExecutor) OracleCommand(cfg Config, dataDir string, inpu
 		"--l2", cfg.L2,
 		"--datadir", dataDir,
 		"--l1.head", inputs.L1Head.Hex(),
-		"--l2.head", inputs.L2Head.Hex(),
-		"--l2.outputroot", inputs.L2OutputRoot.Hex(),
 		"--l2.claim", inputs.L2Claim.Hex(),
-		"--l2.blocknumber", inputs.L2BlockNumber.Text(10),
+	}
+	if len(inputs.AgreedPreState) > 0 {
+		args = append(args,
+			"--interop",
+			"--l2.agreed-prestate", hexutil.Encode(inputs.AgreedPreState),
+			"--l2.timestamp", strconv.FormatUint(inputs.L2Timestamp, 10),
+		)
+	} else {
+		args = append(args,
+			"--l2.head", inputs.L2Head.Hex(),
+			"--l2.outputroot", inputs.L2OutputRoot.Hex(),
+			"--l2.blocknumber", inputs.L2BlockNumber.Text(10),
+		)
 	}
 	if cfg.Network != "" {
 		args = append(args, "--network", cfg.Network)
@@ -95,4 +95,23 @@ func TestOpProgramFillHostCommand(t *testing.T) {
 		require.True(t, slices.Contains(args, "--rollup.config"))
 		require.True(t, slices.Contains(args, "--l2.genesis"))
 	})
+
+	t.Run("Interop", func(t *testing.T) {
+		interopInputs := inputs
+		interopInputs.AgreedPreState = []byte{0x01, 0x02}
+		interopInputs.L2Timestamp = 4444
+		vmConfig := NewOpProgramServerExecutor()
+
+		args, err := vmConfig.OracleCommand(cfg, dir, interopInputs)
+		require.NoError(t, err)
+
+		require.True(t, slices.Contains(args, "--interop"))
+		require.Equal(t, "0x0102", args[slices.Index(args, "--l2.agreed-prestate")+1])
+		require.Equal(t, "4444", args[slices.Index(args, "--l2.timestamp")+1])
+		require.True(t, slices.Contains(args, "--l2.claim"))
+		require.False(t, slices.Contains(args, "--l2.head"))
+		require.False(t, slices.Contains(args, "--l2.outputroot"))
+		require.False(t, slices.Contains(args, "--l2.blocknumber"))
+		require.False(t, slices.Contains(args, "--l2.blocknumber"))
+	})
 }
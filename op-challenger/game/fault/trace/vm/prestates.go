@@ -14,7 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
-type SnapshotSelect func(logger log.Logger, dir string, absolutePreState string, i uint64, binary bool) (string, error)
+type SnapshotSelect func(logger log.Logger, dir string, absolutePreState string, i uint64, format SnapshotFormat) (string, error)
 type CmdExecutor func(ctx context.Context, l log.Logger, binary string, args ...string) error
 
 const (
@@ -22,8 +22,33 @@ const (
 	PreimagesDir     = "preimages"
 	finalStateJson   = "final.json.gz"
 	finalStateBinary = "final.bin.gz"
+	// CompactSnapshotPagesDir is the directory within the snapshots directory where the VM stores the memory pages
+	// shared by compact snapshots.
+	CompactSnapshotPagesDir = "pages"
 )
 
+// SnapshotFormat is the file name suffix of VM snapshots, which identifies their format.
+type SnapshotFormat string
+
+const (
+	SnapshotFormatJSON   SnapshotFormat = ".json.gz"
+	SnapshotFormatBinary SnapshotFormat = ".bin.gz"
+	// SnapshotFormatCompact snapshots only store references to memory pages, which are stored once in
+	// CompactSnapshotPagesDir and shared by all snapshots in the directory.
+	SnapshotFormatCompact SnapshotFormat = ".snap.gz"
+)
+
+func (f SnapshotFormat) nameRegexp() *regexp.Regexp {
+	switch f {
+	case SnapshotFormatBinary:
+		return snapshotBinaryNameRegexp
+	case SnapshotFormatCompact:
+		return snapshotCompactNameRegexp
+	default:
+		return snapshotJsonNameRegexp
+	}
+}
+
 func FinalStatePath(dir string, binarySnapshots bool) string {
 	filename := finalStateJson
 	if binarySnapshots {
@@ -34,6 +59,7 @@ func FinalStatePath(dir string, binarySnapshots bool) string {
 
 var snapshotJsonNameRegexp = regexp.MustCompile(`^[0-9]+\.json\.gz$`)
 var snapshotBinaryNameRegexp = regexp.MustCompile(`^[0-9]+\.bin\.gz$`)
+var snapshotCompactNameRegexp = regexp.MustCompile(`^[0-9]+\.snap\.gz$`)
 
 func PreimageDir(dir string) string {
 	return filepath.Join(dir, PreimagesDir)
@@ -53,13 +79,9 @@ func RunCmd(ctx context.Context, l log.Logger, binary string, args ...string) er
 
 // FindStartingSnapshot finds the closest snapshot before the specified traceIndex in snapDir.
 // If no suitable snapshot can be found it returns absolutePreState.
-func FindStartingSnapshot(logger log.Logger, snapDir string, absolutePreState string, traceIndex uint64, binarySnapshots bool) (string, error) {
-	suffix := ".json.gz"
-	nameRegexp := snapshotJsonNameRegexp
-	if binarySnapshots {
-		suffix = ".bin.gz"
-		nameRegexp = snapshotBinaryNameRegexp
-	}
+func FindStartingSnapshot(logger log.Logger, snapDir string, absolutePreState string, traceIndex uint64, format SnapshotFormat) (string, error) {
+	suffix := string(format)
+	nameRegexp := format.nameRegexp()
 	// Find the closest snapshot to start from
 	entries, err := os.ReadDir(snapDir)
 	if err != nil {
@@ -71,6 +93,9 @@ func FindStartingSnapshot(logger log.Logger, snapDir string, absolutePreState st
 	bestSnap := uint64(0)
 	for _, entry := range entries {
 		if entry.IsDir() {
+			if entry.Name() == CompactSnapshotPagesDir {
+				continue
+			}
 			logger.Warn("Unexpected directory in snapshots dir", "parent", snapDir, "child", entry.Name())
 			continue
 		}
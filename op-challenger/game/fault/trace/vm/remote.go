@@ -0,0 +1,320 @@
+package vm
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	kvtypes "github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	defaultRemotePollInterval = 5 * time.Second
+	preimageArtifactPrefix    = PreimagesDir + "/"
+)
+
+var ErrRemoteJobFailed = errors.New("remote vm execution failed")
+
+// JobState is the state of a job executing on a remote worker.
+type JobState string
+
+const (
+	JobStateRunning  JobState = "running"
+	JobStateComplete JobState = "complete"
+	JobStateFailed   JobState = "failed"
+)
+
+// RemoteJob describes a VM execution to run on a remote worker.
+// Identical jobs are deduplicated by the worker.
+type RemoteJob struct {
+	VmType types.TraceType `json:"vmType"`
+	// Prestate is the name of the absolute prestate file: its keccak256 hash followed by the file extension.
+	Prestate        string                `json:"prestate"`
+	BinarySnapshots bool                  `json:"binarySnapshots"`
+	Inputs          utils.LocalGameInputs `json:"inputs"`
+	TraceIndex      uint64                `json:"traceIndex"`
+}
+
+// JobStatus reports the progress of a remote job.
+type JobStatus struct {
+	ID    string   `json:"id"`
+	State JobState `json:"state"`
+	Error string   `json:"error,omitempty"`
+}
+
+// RemoteExecutor generates proofs by submitting executions to a pool of remote workers and downloading the
+// resulting proof artifacts, so the local host doesn't need to run the VM.
+type RemoteExecutor struct {
+	logger       log.Logger
+	metrics      Metricer
+	cfg          Config
+	client       *http.Client
+	workers      []string
+	prestate     string
+	inputs       utils.LocalGameInputs
+	pollInterval time.Duration
+
+	prestateOnce sync.Once
+	prestateName string
+	prestateErr  error
+}
+
+// NewProofGenerator creates a RemoteExecutor if remote workers are configured, otherwise an Executor that runs
+// the VM locally.
+func NewProofGenerator(logger log.Logger, m Metricer, cfg Config, oracleServer OracleServerExecutor, resources ResourceLimiter, prestate string, inputs utils.LocalGameInputs) utils.ProofGenerator {
+	if len(cfg.RemoteWorkers) > 0 {
+		return NewRemoteExecutor(logger, m, cfg, prestate, inputs)
+	}
+	return NewExecutor(logger, m, cfg, oracleServer, resources, prestate, inputs)
+}
+
+func NewRemoteExecutor(logger log.Logger, m Metricer, cfg Config, prestate string, inputs utils.LocalGameInputs) *RemoteExecutor {
+	return &RemoteExecutor{
+		logger:       logger,
+		metrics:      m,
+		cfg:          cfg,
+		client:       http.DefaultClient,
+		workers:      cfg.RemoteWorkers,
+		prestate:     prestate,
+		inputs:       inputs,
+		pollInterval: defaultRemotePollInterval,
+	}
+}
+
+// GenerateProof executes the VM on a remote worker to generate a proof at the specified trace index.
+// The proof, final state and any preimages required for the proof are stored in the specified directory.
+// Each worker is tried in turn until one succeeds.
+func (e *RemoteExecutor) GenerateProof(ctx context.Context, dir string, i uint64) error {
+	prestate, err := e.prestateFile()
+	if err != nil {
+		return err
+	}
+	job := RemoteJob{
+		VmType:          e.cfg.VmType,
+		Prestate:        prestate,
+		BinarySnapshots: e.cfg.BinarySnapshots,
+		Inputs:          e.inputs,
+		TraceIndex:      i,
+	}
+	var errs []error
+	for _, worker := range e.workers {
+		start := time.Now()
+		err := e.execute(ctx, worker, dir, job)
+		if err == nil {
+			e.metrics.RecordExecutionTime(time.Since(start))
+			e.logger.Info("Remote VM execution complete", "worker", worker, "proof", i, "time", time.Since(start))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		e.logger.Warn("Remote VM execution failed", "worker", worker, "proof", i, "err", err)
+		errs = append(errs, fmt.Errorf("worker %v: %w", worker, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *RemoteExecutor) execute(ctx context.Context, worker string, dir string, job RemoteJob) error {
+	if err := e.ensurePrestate(ctx, worker, job.Prestate); err != nil {
+		return err
+	}
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	var status JobStatus
+	if err := e.request(ctx, http.MethodPost, worker+"/jobs", bytes.NewReader(body), &status); err != nil {
+		return fmt.Errorf("failed to submit job: %w", err)
+	}
+	e.logger.Info("Submitted remote VM execution", "worker", worker, "job", status.ID, "proof", job.TraceIndex)
+	for status.State == JobStateRunning {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(e.pollInterval):
+		}
+		if err := e.request(ctx, http.MethodGet, worker+"/jobs/"+status.ID, nil, &status); err != nil {
+			return fmt.Errorf("failed to poll job %v: %w", status.ID, err)
+		}
+	}
+	if status.State != JobStateComplete {
+		return fmt.Errorf("%w: job %v: %v", ErrRemoteJobFailed, status.ID, status.Error)
+	}
+	return e.fetchArtifacts(ctx, worker, dir, status.ID, job)
+}
+
+// ensurePrestate uploads the prestate to the worker if it does not already have it.
+func (e *RemoteExecutor) ensurePrestate(ctx context.Context, worker string, name string) error {
+	url := worker + "/prestates/" + name
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to check prestate: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	} else if resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to check prestate: unexpected status %v", resp.Status)
+	}
+	e.logger.Info("Uploading prestate to remote worker", "worker", worker, "prestate", name)
+	file, err := os.Open(e.prestate)
+	if err != nil {
+		return fmt.Errorf("failed to open prestate: %w", err)
+	}
+	defer file.Close()
+	if err := e.request(ctx, http.MethodPut, url, file, nil); err != nil {
+		return fmt.Errorf("failed to upload prestate: %w", err)
+	}
+	return nil
+}
+
+// fetchArtifacts downloads the proof artifacts of a completed job into dir.
+func (e *RemoteExecutor) fetchArtifacts(ctx context.Context, worker string, dir string, id string, job RemoteJob) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, worker+"/jobs/"+id+"/artifacts", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch artifacts: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch artifacts: %w", responseError(resp))
+	}
+
+	proofFile := filepath.Join(utils.ProofsDir, fmt.Sprintf("%d.json.gz", job.TraceIndex))
+	finalFile := filepath.Base(FinalStatePath(dir, job.BinarySnapshots))
+	var preimages kvstore.KV
+	defer func() {
+		if preimages != nil {
+			_ = preimages.Close()
+		}
+	}()
+	reader := tar.NewReader(resp.Body)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read artifacts: %w", err)
+		}
+		switch name := header.Name; {
+		case name == proofFile || name == finalFile:
+			if err := writeArtifact(filepath.Join(dir, name), reader); err != nil {
+				return err
+			}
+		case strings.HasPrefix(name, preimageArtifactPrefix):
+			key := common.HexToHash(strings.TrimPrefix(name, preimageArtifactPrefix))
+			value, err := io.ReadAll(reader)
+			if err != nil {
+				return fmt.Errorf("failed to read preimage %v: %w", key, err)
+			}
+			if preimages == nil {
+				if err := os.MkdirAll(PreimageDir(dir), 0755); err != nil {
+					return fmt.Errorf("could not create preimage cache directory: %w", err)
+				}
+				preimages, err = kvstore.NewDiskKV(e.logger, PreimageDir(dir), kvtypes.DataFormatFile)
+				if err != nil {
+					return fmt.Errorf("failed to open preimage store: %w", err)
+				}
+			}
+			if err := preimages.Put(key, value); err != nil {
+				return fmt.Errorf("failed to store preimage %v: %w", key, err)
+			}
+		default:
+			return fmt.Errorf("unexpected artifact: %v", name)
+		}
+	}
+}
+
+// prestateFile returns the name used to identify the prestate on workers.
+func (e *RemoteExecutor) prestateFile() (string, error) {
+	e.prestateOnce.Do(func() {
+		e.prestateName, e.prestateErr = prestateName(e.prestate)
+	})
+	return e.prestateName, e.prestateErr
+}
+
+func (e *RemoteExecutor) request(ctx context.Context, method string, url string, body io.Reader, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func responseError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected status %v: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// prestateName returns the keccak256 hash of the prestate file followed by its extension.
+// The extension is retained because the VM uses it to determine the state format.
+func prestateName(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open prestate: %w", err)
+	}
+	defer file.Close()
+	hasher := crypto.NewKeccakState()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to hash prestate: %w", err)
+	}
+	return common.BytesToHash(hasher.Sum(nil)).Hex() + prestateExt(path), nil
+}
+
+func prestateExt(path string) string {
+	base := filepath.Base(path)
+	if idx := strings.Index(base, "."); idx >= 0 {
+		return base[idx:]
+	}
+	return ""
+}
+
+func writeArtifact(path string, data io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create artifact dir: %w", err)
+	}
+	out, err := ioutil.NewAtomicWriter(path, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact %v: %w", path, err)
+	}
+	if _, err := io.Copy(out, data); err != nil {
+		_ = out.Abort()
+		return fmt.Errorf("failed to write artifact %v: %w", path, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,155 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	kvtypes "github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+const testVmType = types.TraceType("test")
+
+func TestRemoteExecutor(t *testing.T) {
+	inputs := utils.LocalGameInputs{
+		L1Head:        common.Hash{0x11},
+		L2Head:        common.Hash{0x22},
+		L2OutputRoot:  common.Hash{0x33},
+		L2Claim:       common.Hash{0x44},
+		L2BlockNumber: big.NewInt(3333),
+	}
+	oracleKey := preimage.PrecompileKey{0xaa}.PreimageKey()
+	precompileInput := []byte("precompile input")
+
+	setup := func(t *testing.T, vmErr error) (*RemoteExecutor, *stubVmMetrics, *atomic.Int32, string) {
+		logger := testlog.Logger(t, log.LevelInfo)
+		worker := NewWorker(context.Background(), logger, t.TempDir(), map[types.TraceType]WorkerVm{
+			testVmType: {
+				Config:       Config{VmType: testVmType, VmBin: "./bin/testvm", BinarySnapshots: true},
+				OracleServer: NewOpProgramServerExecutor(),
+				Metrics:      &stubVmMetrics{},
+			},
+		}, nil)
+		worker.cmdExecutor = func(ctx context.Context, l log.Logger, binary string, args ...string) error {
+			if vmErr != nil {
+				return vmErr
+			}
+			flags := make(map[string]string)
+			for i := 1; i < len(args) && args[i] != "--"; i += 2 {
+				flags[args[i]] = args[i+1]
+			}
+			var proofAt uint64
+			if _, err := fmt.Sscanf(flags["--proof-at"], "=%d", &proofAt); err != nil {
+				return err
+			}
+			proof := utils.ProofData{ClaimValue: common.Hash{0xbb}, OracleKey: oracleKey[:]}
+			if err := jsonutil.WriteJSON(proof, ioutil.ToAtomicFile(fmt.Sprintf(flags["--proof-fmt"], proofAt), 0o644)); err != nil {
+				return err
+			}
+			if err := os.WriteFile(flags["--output"], []byte("final state"), 0o644); err != nil {
+				return err
+			}
+			dir := filepath.Dir(filepath.Dir(flags["--proof-fmt"]))
+			kv, err := kvstore.NewDiskKV(l, PreimageDir(dir), kvtypes.DataFormatFile)
+			if err != nil {
+				return err
+			}
+			defer kv.Close()
+			return kv.Put(preimage.Keccak256Key(oracleKey).PreimageKey(), precompileInput)
+		}
+
+		uploads := new(atomic.Int32)
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut {
+				uploads.Add(1)
+			}
+			worker.ServeHTTP(rw, r)
+		}))
+		t.Cleanup(server.Close)
+
+		prestate := filepath.Join(t.TempDir(), "prestate.bin.gz")
+		require.NoError(t, os.WriteFile(prestate, []byte("prestate"), 0o644))
+		m := &stubVmMetrics{}
+		cfg := Config{VmType: testVmType, BinarySnapshots: true, RemoteWorkers: []string{server.URL}}
+		executor := NewRemoteExecutor(logger, m, cfg, prestate, inputs)
+		executor.pollInterval = 10 * time.Millisecond
+		return executor, m, uploads, server.URL
+	}
+
+	t.Run("GenerateProof", func(t *testing.T) {
+		executor, m, uploads, _ := setup(t, nil)
+		dir := t.TempDir()
+		require.NoError(t, executor.GenerateProof(context.Background(), dir, 42))
+		require.Equal(t, 1, m.executionTimeRecordCount)
+		require.EqualValues(t, 1, uploads.Load())
+
+		proof, err := loadProof(filepath.Join(dir, utils.ProofsDir, "42.json.gz"))
+		require.NoError(t, err)
+		require.Equal(t, common.Hash{0xbb}, proof.ClaimValue)
+		final, err := os.ReadFile(FinalStatePath(dir, true))
+		require.NoError(t, err)
+		require.Equal(t, []byte("final state"), final)
+
+		kv, err := kvstore.NewDiskKV(testlog.Logger(t, log.LevelInfo), PreimageDir(dir), kvtypes.DataFormatFile)
+		require.NoError(t, err)
+		defer kv.Close()
+		value, err := kv.Get(preimage.Keccak256Key(oracleKey).PreimageKey())
+		require.NoError(t, err)
+		require.Equal(t, precompileInput, value)
+
+		// Prestate is only uploaded once
+		require.NoError(t, executor.GenerateProof(context.Background(), dir, 43))
+		require.EqualValues(t, 1, uploads.Load())
+		require.FileExists(t, filepath.Join(dir, utils.ProofsDir, "43.json.gz"))
+	})
+
+	t.Run("JobFailed", func(t *testing.T) {
+		executor, m, _, _ := setup(t, errors.New("boom"))
+		err := executor.GenerateProof(context.Background(), t.TempDir(), 42)
+		require.ErrorIs(t, err, ErrRemoteJobFailed)
+		require.ErrorContains(t, err, "boom")
+		require.Zero(t, m.executionTimeRecordCount)
+	})
+
+	t.Run("FailOverToNextWorker", func(t *testing.T) {
+		executor, _, _, url := setup(t, nil)
+		unavailable := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(unavailable.Close)
+		executor.workers = []string{unavailable.URL, url}
+		dir := t.TempDir()
+		require.NoError(t, executor.GenerateProof(context.Background(), dir, 42))
+		require.FileExists(t, filepath.Join(dir, utils.ProofsDir, "42.json.gz"))
+	})
+
+	t.Run("RejectsPrestateWithWrongHash", func(t *testing.T) {
+		_, _, _, url := setup(t, nil)
+		name := common.Hash{0x01}.Hex() + ".bin.gz"
+		req, err := http.NewRequest(http.MethodPut, url+"/prestates/"+name, strings.NewReader("prestate"))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
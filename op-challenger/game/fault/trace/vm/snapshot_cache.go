@@ -0,0 +1,300 @@
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/versions"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// snapshotChecksumSuffix is appended to the name of a cached snapshot to give the file storing its keccak256 hash.
+	snapshotChecksumSuffix = ".keccak"
+	// pagesFileSuffix is the suffix of the files storing the memory pages of compact snapshots.
+	pagesFileSuffix = ".pack"
+	// snapshotCacheRetention is how long a cache entry is kept after it was last used.
+	snapshotCacheRetention = 30 * 24 * time.Hour
+)
+
+// snapshotCacheLock serializes changes to snapshot caches. Executors for different games store snapshots to the same
+// cache entries concurrently and unreferenced pages must not be removed while another executor is adding snapshots.
+var snapshotCacheLock sync.Mutex
+
+// SnapshotCache shares VM snapshots between executions with the same VM, prestate and local inputs so that games
+// disputing the same output range don't need to repeat identical executions.
+// Each cached snapshot is stored with its hash which is verified before the snapshot is reused.
+type SnapshotCache struct {
+	logger log.Logger
+	dir    string
+}
+
+func NewSnapshotCache(logger log.Logger, dir string) *SnapshotCache {
+	return &SnapshotCache{
+		logger: logger,
+		dir:    dir,
+	}
+}
+
+// SnapshotCacheKey identifies the executions that produce identical snapshots.
+func SnapshotCacheKey(vmType types.TraceType, prestate string, inputs utils.LocalGameInputs) (common.Hash, error) {
+	name, err := prestateName(prestate)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := json.Marshal(struct {
+		VmType   types.TraceType       `json:"vmType"`
+		Prestate string                `json:"prestate"`
+		Inputs   utils.LocalGameInputs `json:"inputs"`
+	}{vmType, name, inputs})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// FindSnapshot returns the path to the verified cached snapshot with the highest trace index below traceIndex and
+// its trace index. Returns an empty path if no suitable snapshot is cached.
+// Snapshots that fail verification are removed from the cache.
+func (c *SnapshotCache) FindSnapshot(key common.Hash, traceIndex uint64, format SnapshotFormat) (string, uint64) {
+	entryDir := c.entryDir(key)
+	candidates := snapshotIndices(c.logger, entryDir, format)
+	slices.Sort(candidates)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		index := candidates[i]
+		if index == 0 || index >= traceIndex {
+			continue
+		}
+		path := filepath.Join(entryDir, snapshotName(index, format))
+		if err := verifySnapshot(path); err != nil {
+			c.logger.Warn("Removing cached snapshot that failed verification", "path", path, "err", err)
+			_ = os.Remove(path + snapshotChecksumSuffix)
+			_ = os.Remove(path)
+			continue
+		}
+		// Record the use so the entry isn't expired while still in use.
+		now := time.Now()
+		if err := os.Chtimes(entryDir, now, now); err != nil {
+			c.logger.Warn("Failed to update snapshot cache entry time", "dir", entryDir, "err", err)
+		}
+		return path, index
+	}
+	return "", 0
+}
+
+// Store adds the snapshots in snapDir that are not yet cached to the cache.
+// Snapshots are hard linked into the cache where possible to avoid duplicating them on disk.
+// For compact snapshots, the shared memory pages are added to the cache before the snapshots that reference them and
+// pages that aren't referenced by any cached snapshot are removed afterwards.
+func (c *SnapshotCache) Store(key common.Hash, snapDir string, format SnapshotFormat) error {
+	snapshotCacheLock.Lock()
+	defer snapshotCacheLock.Unlock()
+	entryDir := c.entryDir(key)
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return fmt.Errorf("could not create snapshot cache directory %v: %w", entryDir, err)
+	}
+	if format == SnapshotFormatCompact {
+		if err := storePages(filepath.Join(snapDir, CompactSnapshotPagesDir), filepath.Join(entryDir, CompactSnapshotPagesDir)); err != nil {
+			return fmt.Errorf("failed to cache snapshot pages: %w", err)
+		}
+	}
+	cached := make(map[uint64]bool)
+	for _, index := range snapshotIndices(c.logger, entryDir, format) {
+		cached[index] = true
+	}
+	for _, index := range snapshotIndices(c.logger, snapDir, format) {
+		if cached[index] {
+			continue
+		}
+		name := snapshotName(index, format)
+		if err := storeSnapshot(filepath.Join(snapDir, name), filepath.Join(entryDir, name)); err != nil {
+			return fmt.Errorf("failed to cache snapshot %v: %w", name, err)
+		}
+	}
+	if format == SnapshotFormatCompact {
+		// No snapshots are being written to the cache entry while the lock is held, so all unreferenced pages can be removed.
+		if _, err := versions.RemoveUnreferencedPages(entryDir, time.Now()); err != nil {
+			c.logger.Warn("Failed to remove unreferenced snapshot pages", "dir", entryDir, "err", err)
+		}
+	}
+	c.expireEntries()
+	return nil
+}
+
+// expireEntries removes cache entries that haven't been used within the retention period.
+func (c *SnapshotCache) expireEntries() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		c.logger.Warn("Failed to list snapshot cache", "dir", c.dir, "err", err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < snapshotCacheRetention {
+			continue
+		}
+		path := filepath.Join(c.dir, entry.Name())
+		c.logger.Info("Removing expired snapshot cache entry", "dir", path)
+		if err := os.RemoveAll(path); err != nil {
+			c.logger.Warn("Failed to remove expired snapshot cache entry", "dir", path, "err", err)
+		}
+	}
+}
+
+func (c *SnapshotCache) entryDir(key common.Hash) string {
+	return filepath.Join(c.dir, key.Hex())
+}
+
+// storePages links or copies the page files of compact snapshots in srcDir that are not already in destDir.
+// Page files are named by their content so existing files never need to be replaced.
+func storePages(srcDir string, destDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, pagesFileSuffix) {
+			continue
+		}
+		dest := filepath.Join(destDir, name)
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		if err := linkOrCopyFile(filepath.Join(srcDir, name), dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeSnapshot links or copies the snapshot at src to dest and records its hash.
+// The hash is written last so partially stored snapshots are never reused.
+func storeSnapshot(src string, dest string) error {
+	if err := linkOrCopyFile(src, dest); err != nil {
+		return err
+	}
+	hash, err := hashFile(dest)
+	if err != nil {
+		return err
+	}
+	out, err := ioutil.NewAtomicWriter(dest+snapshotChecksumSuffix, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write([]byte(hash.Hex())); err != nil {
+		_ = out.Abort()
+		return err
+	}
+	return out.Close()
+}
+
+func verifySnapshot(path string) error {
+	expected, err := os.ReadFile(path + snapshotChecksumSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot hash: %w", err)
+	}
+	actual, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+	if actual.Hex() != strings.TrimSpace(string(expected)) {
+		return fmt.Errorf("snapshot hash mismatch, expected %s but was %v", expected, actual)
+	}
+	return nil
+}
+
+func hashFile(path string) (common.Hash, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer file.Close()
+	hasher := crypto.NewKeccakState()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash %v: %w", path, err)
+	}
+	return common.BytesToHash(hasher.Sum(nil)), nil
+}
+
+// linkOrCopyFile hard links src to dest, falling back to copying it if it can't be linked.
+// dest is replaced atomically.
+func linkOrCopyFile(src string, dest string) error {
+	tmp := dest + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Link(src, tmp); err != nil {
+		if err := copyFile(src, tmp); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp, dest)
+}
+
+func copyFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func snapshotName(index uint64, format SnapshotFormat) string {
+	return fmt.Sprintf("%d%v", index, format)
+}
+
+// snapshotIndices returns the trace indices of the snapshots in snapDir.
+func snapshotIndices(logger log.Logger, snapDir string, format SnapshotFormat) []uint64 {
+	suffix := string(format)
+	nameRegexp := format.nameRegexp()
+	entries, err := os.ReadDir(snapDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Failed to list snapshots", "dir", snapDir, "err", err)
+		}
+		return nil
+	}
+	var indices []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !nameRegexp.MatchString(name) {
+			continue
+		}
+		index, err := strconv.ParseUint(name[0:len(name)-len(suffix)], 10, 64)
+		if err != nil {
+			continue
+		}
+		indices = append(indices, index)
+	}
+	return indices
+}
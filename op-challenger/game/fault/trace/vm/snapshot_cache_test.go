@@ -0,0 +1,279 @@
+package vm
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/memory"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/multithreaded"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm/versions"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	prestate := filepath.Join(dir, "prestate.bin.gz")
+	require.NoError(t, os.WriteFile(prestate, []byte("prestate"), 0o644))
+	inputs := utils.LocalGameInputs{
+		L1Head:        common.Hash{0x11},
+		L2Head:        common.Hash{0x22},
+		L2OutputRoot:  common.Hash{0x33},
+		L2Claim:       common.Hash{0x44},
+		L2BlockNumber: big.NewInt(3333),
+	}
+	key, err := SnapshotCacheKey("cannon", prestate, inputs)
+	require.NoError(t, err)
+
+	t.Run("SameForIdenticalExecutions", func(t *testing.T) {
+		other := filepath.Join(dir, "other.bin.gz")
+		require.NoError(t, os.WriteFile(other, []byte("prestate"), 0o644))
+		otherKey, err := SnapshotCacheKey("cannon", other, inputs)
+		require.NoError(t, err)
+		require.Equal(t, key, otherKey)
+	})
+
+	t.Run("DiffersByPrestate", func(t *testing.T) {
+		other := filepath.Join(dir, "different.bin.gz")
+		require.NoError(t, os.WriteFile(other, []byte("different"), 0o644))
+		otherKey, err := SnapshotCacheKey("cannon", other, inputs)
+		require.NoError(t, err)
+		require.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("DiffersByInputs", func(t *testing.T) {
+		otherInputs := inputs
+		otherInputs.L1Head = common.Hash{0xaa}
+		otherKey, err := SnapshotCacheKey("cannon", prestate, otherInputs)
+		require.NoError(t, err)
+		require.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("DiffersByVm", func(t *testing.T) {
+		otherKey, err := SnapshotCacheKey("asterisc", prestate, inputs)
+		require.NoError(t, err)
+		require.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("MissingPrestate", func(t *testing.T) {
+		_, err := SnapshotCacheKey("cannon", filepath.Join(dir, "missing.bin.gz"), inputs)
+		require.Error(t, err)
+	})
+}
+
+func TestSnapshotCache(t *testing.T) {
+	key := common.Hash{0xaa}
+	setup := func(t *testing.T) (*SnapshotCache, string) {
+		cache := NewSnapshotCache(testlog.Logger(t, log.LevelInfo), filepath.Join(t.TempDir(), "cache"))
+		snapDir := filepath.Join(t.TempDir(), SnapsDir)
+		require.NoError(t, os.MkdirAll(snapDir, 0755))
+		for _, index := range []uint64{100, 200, 300} {
+			require.NoError(t, os.WriteFile(filepath.Join(snapDir, snapshotName(index, SnapshotFormatBinary)), []byte{byte(index)}, 0o644))
+		}
+		return cache, snapDir
+	}
+
+	t.Run("EmptyCache", func(t *testing.T) {
+		cache, _ := setup(t)
+		path, index := cache.FindSnapshot(key, 1000, SnapshotFormatBinary)
+		require.Empty(t, path)
+		require.Zero(t, index)
+	})
+
+	t.Run("FindClosestSnapshot", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+
+		path, index := cache.FindSnapshot(key, 250, SnapshotFormatBinary)
+		require.Equal(t, filepath.Join(cache.dir, key.Hex(), "200.bin.gz"), path)
+		require.EqualValues(t, 200, index)
+
+		// Must be strictly before the trace index
+		_, index = cache.FindSnapshot(key, 300, SnapshotFormatBinary)
+		require.EqualValues(t, 200, index)
+
+		path, _ = cache.FindSnapshot(key, 100, SnapshotFormatBinary)
+		require.Empty(t, path)
+	})
+
+	t.Run("IgnoreOtherFormat", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		path, _ := cache.FindSnapshot(key, 1000, SnapshotFormatJSON)
+		require.Empty(t, path)
+	})
+
+	t.Run("IgnoreOtherKey", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		path, _ := cache.FindSnapshot(common.Hash{0xbb}, 1000, SnapshotFormatBinary)
+		require.Empty(t, path)
+	})
+
+	t.Run("StoreOnlyNewSnapshots", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		require.NoError(t, os.WriteFile(filepath.Join(snapDir, snapshotName(400, SnapshotFormatBinary)), []byte{4}, 0o644))
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		_, index := cache.FindSnapshot(key, 1000, SnapshotFormatBinary)
+		require.EqualValues(t, 400, index)
+	})
+
+	t.Run("RemoveCorruptSnapshot", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		corrupt := filepath.Join(cache.dir, key.Hex(), snapshotName(300, SnapshotFormatBinary))
+		// Replace rather than modify the file as it may be a hard link to the original snapshot
+		require.NoError(t, os.Remove(corrupt))
+		require.NoError(t, os.WriteFile(corrupt, []byte("corrupt"), 0o644))
+
+		path, index := cache.FindSnapshot(key, 1000, SnapshotFormatBinary)
+		require.Equal(t, filepath.Join(cache.dir, key.Hex(), "200.bin.gz"), path)
+		require.EqualValues(t, 200, index)
+		require.NoFileExists(t, corrupt)
+		require.NoFileExists(t, corrupt+snapshotChecksumSuffix)
+	})
+
+	t.Run("IgnoreSnapshotWithoutChecksum", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		require.NoError(t, os.Remove(filepath.Join(cache.dir, key.Hex(), snapshotName(300, SnapshotFormatBinary)+snapshotChecksumSuffix)))
+		_, index := cache.FindSnapshot(key, 1000, SnapshotFormatBinary)
+		require.EqualValues(t, 200, index)
+	})
+
+	t.Run("CompactSnapshotsIncludePages", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		writeCompactSnapshot(t, snapDir, 500, 5)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatCompact))
+
+		path, index := cache.FindSnapshot(key, 1000, SnapshotFormatCompact)
+		require.Equal(t, filepath.Join(cache.dir, key.Hex(), "500.snap.gz"), path)
+		require.EqualValues(t, 500, index)
+		_, err := versions.LoadStateFromFile(path)
+		require.NoError(t, err)
+
+		// Existing page files are kept when storing later snapshots
+		writeCompactSnapshot(t, snapDir, 600, 6)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatCompact))
+		for _, index := range []uint64{500, 600} {
+			_, err := versions.LoadStateFromFile(filepath.Join(cache.dir, key.Hex(), snapshotName(index, SnapshotFormatCompact)))
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("RemoveUnreferencedPages", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		writeCompactSnapshot(t, snapDir, 500, 5)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatCompact))
+		cachedPages := filepath.Join(cache.dir, key.Hex(), CompactSnapshotPagesDir)
+		expected := listPackFiles(t, cachedPages)
+
+		// The pages of a snapshot that is already cached aren't retained
+		otherDir := filepath.Join(t.TempDir(), SnapsDir)
+		writeCompactSnapshot(t, otherDir, 500, 7)
+		require.NotEqual(t, expected, listPackFiles(t, filepath.Join(otherDir, CompactSnapshotPagesDir)))
+		require.NoError(t, cache.Store(key, otherDir, SnapshotFormatCompact))
+		require.Equal(t, expected, listPackFiles(t, cachedPages))
+		_, err := versions.LoadStateFromFile(filepath.Join(cache.dir, key.Hex(), snapshotName(500, SnapshotFormatCompact)))
+		require.NoError(t, err)
+	})
+
+	t.Run("ExpireUnusedEntries", func(t *testing.T) {
+		cache, snapDir := setup(t)
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		expired := common.Hash{0xee}
+		require.NoError(t, cache.Store(expired, snapDir, SnapshotFormatBinary))
+		old := time.Now().Add(-snapshotCacheRetention - time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(cache.dir, expired.Hex()), old, old))
+
+		require.NoError(t, cache.Store(key, snapDir, SnapshotFormatBinary))
+		require.NoDirExists(t, filepath.Join(cache.dir, expired.Hex()))
+		require.DirExists(t, filepath.Join(cache.dir, key.Hex()))
+	})
+}
+
+func TestExecutorReusesCachedSnapshots(t *testing.T) {
+	tempDir := t.TempDir()
+	prestate := filepath.Join(tempDir, "prestate.bin.gz")
+	require.NoError(t, os.WriteFile(prestate, []byte("prestate"), 0o644))
+	cfg := Config{
+		VmType:           "test",
+		VmBin:            "./bin/testvm",
+		Server:           "./bin/testserver",
+		Network:          "op-test",
+		SnapshotFreq:     500,
+		InfoFreq:         900,
+		BinarySnapshots:  true,
+		SnapshotCacheDir: filepath.Join(tempDir, "cache"),
+	}
+	inputs := utils.LocalGameInputs{
+		L1Head:        common.Hash{0x11},
+		L2Head:        common.Hash{0x22},
+		L2OutputRoot:  common.Hash{0x33},
+		L2Claim:       common.Hash{0x44},
+		L2BlockNumber: big.NewInt(3333),
+	}
+	execute := func(t *testing.T, dir string, proofAt uint64) string {
+		executor := NewExecutor(testlog.Logger(t, log.LevelInfo), &stubVmMetrics{}, cfg, NewOpProgramServerExecutor(), nil, prestate, inputs)
+		var input string
+		executor.cmdExecutor = func(ctx context.Context, l log.Logger, b string, a ...string) error {
+			for i := 1; i < len(a)-1; i++ {
+				if a[i] == "--input" {
+					input = a[i+1]
+				}
+			}
+			// Simulate the VM writing snapshots
+			snapDir := filepath.Join(dir, SnapsDir)
+			for _, index := range []uint64{500, 1000} {
+				if index < proofAt {
+					if err := os.WriteFile(filepath.Join(snapDir, snapshotName(index, SnapshotFormatBinary)), []byte{byte(index)}, 0o644); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		require.NoError(t, executor.GenerateProof(context.Background(), dir, proofAt))
+		return input
+	}
+
+	game1 := filepath.Join(tempDir, "game1")
+	require.Equal(t, prestate, execute(t, game1, 1200))
+
+	// A different game with the same inputs starts from the snapshot created by the first game
+	game2 := filepath.Join(tempDir, "game2")
+	input := execute(t, game2, 1100)
+	require.Equal(t, cfg.SnapshotCacheDir, filepath.Dir(filepath.Dir(input)))
+	require.Equal(t, "1000.bin.gz", filepath.Base(input))
+
+	// Local snapshots are preferred when they are at least as close
+	input = execute(t, game1, 1100)
+	require.Equal(t, filepath.Join(game1, SnapsDir, "1000.bin.gz"), input)
+}
+
+// writeCompactSnapshot writes a compact snapshot with the specified memory value to snapDir.
+func writeCompactSnapshot(t *testing.T, snapDir string, index uint64, value memory.Word) {
+	mtState := multithreaded.CreateEmptyState()
+	mtState.Step = index
+	mtState.Memory.SetMemory(0x1000, value)
+	state, err := versions.NewFromState(mtState)
+	require.NoError(t, err)
+	require.NoError(t, versions.NewCompactSnapshotWriter().Write(filepath.Join(snapDir, snapshotName(index, SnapshotFormatCompact)), state))
+}
+
+func listPackFiles(t *testing.T, dir string) []string {
+	packs, err := filepath.Glob(filepath.Join(dir, "*"+pagesFileSuffix))
+	require.NoError(t, err)
+	for i, pack := range packs {
+		packs[i] = filepath.Base(pack)
+	}
+	return packs
+}
@@ -0,0 +1,423 @@
+package vm
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	kvtypes "github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	workerPrestatesDir = "prestates"
+	workerJobsDir      = "jobs"
+	// jobRetention is how long completed jobs are kept so their artifacts can be fetched.
+	jobRetention = time.Hour
+)
+
+var prestateNameRegexp = regexp.MustCompile(`^(0x[0-9a-f]{64})((?:\.[a-z]+)*)$`)
+
+// WorkerVm is the configuration used by a worker to execute a type of VM.
+type WorkerVm struct {
+	Config       Config
+	OracleServer OracleServerExecutor
+	Metrics      Metricer
+}
+
+type workerJob struct {
+	job      RemoteJob
+	dir      string
+	state    JobState
+	err      error
+	complete time.Time
+}
+
+// Worker executes VM jobs submitted by remote challengers over HTTP.
+//
+//	HEAD /prestates/{name}      check if a prestate is available
+//	PUT  /prestates/{name}      upload a prestate
+//	POST /jobs                  submit a RemoteJob, returning its JobStatus
+//	GET  /jobs/{id}             get the JobStatus of a job
+//	GET  /jobs/{id}/artifacts   download the artifacts of a completed job as a tar stream
+//
+// Executions for the same game and prestate share a directory so later jobs can start from earlier snapshots.
+type Worker struct {
+	logger      log.Logger
+	dir         string
+	vms         map[types.TraceType]WorkerVm
+	resources   ResourceLimiter
+	cmdExecutor CmdExecutor
+	ctx         context.Context
+
+	lock     sync.Mutex
+	jobs     map[string]*workerJob
+	dirLocks map[string]*sync.Mutex
+}
+
+// NewWorker creates a Worker that stores prestates and execution data in dir.
+// Jobs are cancelled when ctx is done. If resources is nil, executions are not limited.
+func NewWorker(ctx context.Context, logger log.Logger, dir string, vms map[types.TraceType]WorkerVm, resources ResourceLimiter) *Worker {
+	return &Worker{
+		logger:      logger,
+		dir:         dir,
+		vms:         vms,
+		resources:   resources,
+		cmdExecutor: RunCmd,
+		ctx:         ctx,
+		jobs:        make(map[string]*workerJob),
+		dirLocks:    make(map[string]*sync.Mutex),
+	}
+}
+
+func (w *Worker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	switch parts := strings.Split(path, "/"); {
+	case len(parts) == 2 && parts[0] == workerPrestatesDir && (r.Method == http.MethodHead || r.Method == http.MethodGet):
+		w.checkPrestate(rw, parts[1])
+	case len(parts) == 2 && parts[0] == workerPrestatesDir && r.Method == http.MethodPut:
+		w.uploadPrestate(rw, r, parts[1])
+	case len(parts) == 1 && parts[0] == workerJobsDir && r.Method == http.MethodPost:
+		w.submitJob(rw, r)
+	case len(parts) == 2 && parts[0] == workerJobsDir && r.Method == http.MethodGet:
+		w.jobStatus(rw, parts[1])
+	case len(parts) == 3 && parts[0] == workerJobsDir && parts[2] == "artifacts" && r.Method == http.MethodGet:
+		w.jobArtifacts(rw, parts[1])
+	default:
+		http.Error(rw, "not found", http.StatusNotFound)
+	}
+}
+
+func (w *Worker) checkPrestate(rw http.ResponseWriter, name string) {
+	if !prestateNameRegexp.MatchString(name) {
+		http.Error(rw, "invalid prestate name", http.StatusBadRequest)
+		return
+	}
+	if _, err := os.Stat(w.prestatePath(name)); errors.Is(err, os.ErrNotExist) {
+		http.Error(rw, "unknown prestate", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (w *Worker) uploadPrestate(rw http.ResponseWriter, r *http.Request, name string) {
+	match := prestateNameRegexp.FindStringSubmatch(name)
+	if match == nil {
+		http.Error(rw, "invalid prestate name", http.StatusBadRequest)
+		return
+	}
+	path := w.prestatePath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out, err := ioutil.NewAtomicWriter(path, 0o644)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hasher := crypto.NewKeccakState()
+	if _, err := io.Copy(io.MultiWriter(out, hasher), r.Body); err != nil {
+		_ = out.Abort()
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if hash := common.BytesToHash(hasher.Sum(nil)); hash.Hex() != match[1] {
+		_ = out.Abort()
+		http.Error(rw, fmt.Sprintf("prestate hash mismatch: got %v", hash), http.StatusBadRequest)
+		return
+	}
+	if err := out.Close(); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.logger.Info("Stored prestate", "prestate", name)
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (w *Worker) submitJob(rw http.ResponseWriter, r *http.Request) {
+	var job RemoteJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid job: %v", err), http.StatusBadRequest)
+		return
+	}
+	vm, ok := w.vms[job.VmType]
+	if !ok {
+		http.Error(rw, fmt.Sprintf("unsupported vm type: %v", job.VmType), http.StatusBadRequest)
+		return
+	}
+	if !prestateNameRegexp.MatchString(job.Prestate) {
+		http.Error(rw, "invalid prestate name", http.StatusBadRequest)
+		return
+	}
+	if _, err := os.Stat(w.prestatePath(job.Prestate)); err != nil {
+		http.Error(rw, fmt.Sprintf("unknown prestate: %v", job.Prestate), http.StatusBadRequest)
+		return
+	}
+	id, dir, err := w.jobIdentity(job)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.lock.Lock()
+	w.pruneJobs()
+	entry, ok := w.jobs[id]
+	if !ok || entry.state == JobStateFailed {
+		entry = &workerJob{job: job, dir: dir, state: JobStateRunning}
+		w.jobs[id] = entry
+		go w.run(id, entry, vm)
+	}
+	status := entry.status(id)
+	w.lock.Unlock()
+	writeJSON(rw, status)
+}
+
+func (w *Worker) jobStatus(rw http.ResponseWriter, id string) {
+	w.lock.Lock()
+	entry, ok := w.jobs[id]
+	var status JobStatus
+	if ok {
+		status = entry.status(id)
+	}
+	w.lock.Unlock()
+	if !ok {
+		http.Error(rw, "unknown job", http.StatusNotFound)
+		return
+	}
+	writeJSON(rw, status)
+}
+
+func (w *Worker) jobArtifacts(rw http.ResponseWriter, id string) {
+	w.lock.Lock()
+	entry, ok := w.jobs[id]
+	complete := ok && entry.state == JobStateComplete
+	w.lock.Unlock()
+	if !ok {
+		http.Error(rw, "unknown job", http.StatusNotFound)
+		return
+	} else if !complete {
+		http.Error(rw, "job not complete", http.StatusConflict)
+		return
+	}
+	dirLock := w.dirLock(entry.dir)
+	dirLock.Lock()
+	defer dirLock.Unlock()
+
+	rw.Header().Set("Content-Type", "application/x-tar")
+	out := tar.NewWriter(rw)
+	if err := w.writeArtifacts(out, entry); err != nil {
+		// The status has already been sent so the best we can do is abort the stream.
+		w.logger.Error("Failed to write job artifacts", "job", id, "err", err)
+		return
+	}
+	if err := out.Close(); err != nil {
+		w.logger.Error("Failed to complete job artifacts", "job", id, "err", err)
+	}
+}
+
+func (w *Worker) run(id string, entry *workerJob, vm WorkerVm) {
+	dirLock := w.dirLock(entry.dir)
+	dirLock.Lock()
+	defer dirLock.Unlock()
+
+	logger := w.logger.New("job", id, "vm", entry.job.VmType, "proof", entry.job.TraceIndex)
+	logger.Info("Starting job")
+	cfg := vm.Config
+	cfg.BinarySnapshots = entry.job.BinarySnapshots
+	executor := NewExecutor(logger, vm.Metrics, cfg, vm.OracleServer, w.resources, w.prestatePath(entry.job.Prestate), entry.job.Inputs)
+	executor.cmdExecutor = w.cmdExecutor
+	err := executor.GenerateProof(w.ctx, entry.dir, entry.job.TraceIndex)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	entry.complete = time.Now()
+	if err != nil {
+		logger.Error("Job failed", "err", err)
+		entry.state = JobStateFailed
+		entry.err = err
+		return
+	}
+	// Later executions in the same directory overwrite the final state so keep a copy for this job.
+	if err := os.Rename(FinalStatePath(entry.dir, cfg.BinarySnapshots), entry.finalStatePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Error("Failed to store final state", "err", err)
+		entry.state = JobStateFailed
+		entry.err = err
+		return
+	}
+	logger.Info("Job complete")
+	entry.state = JobStateComplete
+}
+
+// writeArtifacts writes the proof, final state and preimages required by the proof to out.
+func (w *Worker) writeArtifacts(out *tar.Writer, entry *workerJob) error {
+	proofFile := filepath.Join(utils.ProofsDir, fmt.Sprintf("%d.json.gz", entry.job.TraceIndex))
+	finalFile := filepath.Base(FinalStatePath(entry.dir, entry.job.BinarySnapshots))
+	files := map[string]string{
+		proofFile: filepath.Join(entry.dir, proofFile),
+		finalFile: entry.finalStatePath(),
+	}
+	for _, name := range []string{proofFile, finalFile} {
+		data, err := os.ReadFile(files[name])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := writeTarEntry(out, name, data); err != nil {
+			return err
+		}
+	}
+
+	// Record the preimages loaded for the proof so the challenger can submit them to the preimage oracle.
+	proof, err := loadProof(filepath.Join(entry.dir, proofFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	source := &recordingPreimageSource{}
+	loader := utils.NewPreimageLoader(func() (utils.PreimageSource, error) {
+		kv, err := kvstore.NewDiskKV(w.logger, PreimageDir(entry.dir), kvtypes.DataFormatFile)
+		if err != nil {
+			return nil, err
+		}
+		source.kv = kv
+		return source, nil
+	})
+	if _, err := loader.LoadPreimage(proof); err != nil {
+		return fmt.Errorf("failed to load preimages for proof: %w", err)
+	}
+	for _, key := range source.keys {
+		if err := writeTarEntry(out, preimageArtifactPrefix+key.Hex(), source.values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) jobIdentity(job RemoteJob) (string, string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", "", err
+	}
+	execution := job
+	execution.TraceIndex = 0
+	execData, err := json.Marshal(execution)
+	if err != nil {
+		return "", "", err
+	}
+	id := crypto.Keccak256Hash(data).Hex()
+	dir := filepath.Join(w.dir, workerJobsDir, crypto.Keccak256Hash(execData).Hex())
+	return id, dir, nil
+}
+
+func (w *Worker) prestatePath(name string) string {
+	return filepath.Join(w.dir, workerPrestatesDir, name)
+}
+
+func (w *Worker) dirLock(dir string) *sync.Mutex {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	l, ok := w.dirLocks[dir]
+	if !ok {
+		l = new(sync.Mutex)
+		w.dirLocks[dir] = l
+	}
+	return l
+}
+
+// pruneJobs removes completed jobs older than jobRetention. Must be called with the lock held.
+func (w *Worker) pruneJobs() {
+	for id, entry := range w.jobs {
+		if entry.state != JobStateRunning && time.Since(entry.complete) > jobRetention {
+			delete(w.jobs, id)
+		}
+	}
+}
+
+// finalStatePath is the path the final state of this job's execution is kept at.
+func (j *workerJob) finalStatePath() string {
+	final := filepath.Base(FinalStatePath(j.dir, j.job.BinarySnapshots))
+	return filepath.Join(j.dir, fmt.Sprintf("%d-%s", j.job.TraceIndex, final))
+}
+
+func (j *workerJob) status(id string) JobStatus {
+	status := JobStatus{ID: id, State: j.state}
+	if j.err != nil {
+		status.Error = j.err.Error()
+	}
+	return status
+}
+
+type recordingPreimageSource struct {
+	kv     kvstore.KV
+	keys   []common.Hash
+	values map[common.Hash][]byte
+}
+
+func (s *recordingPreimageSource) Get(key common.Hash) ([]byte, error) {
+	value, err := s.kv.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if s.values == nil {
+		s.values = make(map[common.Hash][]byte)
+	}
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+	return value, nil
+}
+
+func (s *recordingPreimageSource) Close() error {
+	return s.kv.Close()
+}
+
+func loadProof(path string) (*utils.ProofData, error) {
+	file, err := ioutil.OpenDecompressed(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var proof utils.ProofData
+	if err := json.NewDecoder(file).Decode(&proof); err != nil {
+		return nil, fmt.Errorf("failed to read proof: %w", err)
+	}
+	return &proof, nil
+}
+
+func writeTarEntry(out *tar.Writer, name string, data []byte) error {
+	if err := out.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+		return err
+	}
+	_, err := out.Write(data)
+	return err
+}
+
+func writeJSON(rw http.ResponseWriter, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
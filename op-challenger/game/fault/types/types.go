@@ -28,6 +28,8 @@ const (
 	PermissionedGameType GameType = 1
 	AsteriscGameType     GameType = 2
 	AsteriscKonaGameType GameType = 3
+	SuperCannonGameType  GameType = 4
+	CannonKonaGameType   GameType = 8
 	FastGameType         GameType = 254
 	AlphabetGameType     GameType = 255
 	UnknownGameType      GameType = math.MaxUint32
@@ -47,6 +49,10 @@ func (t GameType) String() string {
 		return "asterisc"
 	case AsteriscKonaGameType:
 		return "asterisc-kona"
+	case SuperCannonGameType:
+		return "super-cannon"
+	case CannonKonaGameType:
+		return "cannon-kona"
 	case FastGameType:
 		return "fast"
 	case AlphabetGameType:
@@ -64,10 +70,12 @@ const (
 	TraceTypeCannon       TraceType = "cannon"
 	TraceTypeAsterisc     TraceType = "asterisc"
 	TraceTypeAsteriscKona TraceType = "asterisc-kona"
+	TraceTypeCannonKona   TraceType = "cannon-kona"
+	TraceTypeSuperCannon  TraceType = "super-cannon"
 	TraceTypePermissioned TraceType = "permissioned"
 )
 
-var TraceTypes = []TraceType{TraceTypeAlphabet, TraceTypeCannon, TraceTypePermissioned, TraceTypeAsterisc, TraceTypeAsteriscKona, TraceTypeFast}
+var TraceTypes = []TraceType{TraceTypeAlphabet, TraceTypeCannon, TraceTypePermissioned, TraceTypeAsterisc, TraceTypeAsteriscKona, TraceTypeCannonKona, TraceTypeSuperCannon, TraceTypeFast}
 
 func (t TraceType) String() string {
 	return string(t)
@@ -106,6 +114,10 @@ func (t TraceType) GameType() GameType {
 		return AsteriscGameType
 	case TraceTypeAsteriscKona:
 		return AsteriscKonaGameType
+	case TraceTypeCannonKona:
+		return CannonKonaGameType
+	case TraceTypeSuperCannon:
+		return SuperCannonGameType
 	case TraceTypeFast:
 		return FastGameType
 	case TraceTypeAlphabet:
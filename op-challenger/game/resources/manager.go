@@ -0,0 +1,285 @@
+package resources
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+)
+
+var (
+	ErrExceedsBudget = errors.New("execution exceeds resource budget")
+	ErrPreempted     = errors.New("execution preempted by higher priority execution")
+)
+
+// Budget is the total resources available to concurrent VM executions and the amount reserved by each one.
+// Zero values are unlimited.
+type Budget struct {
+	// Slots is the maximum number of concurrent executions, typically the number of available CPUs.
+	Slots int
+	// MemoryBytes is the total memory available to executions.
+	MemoryBytes uint64
+	// DiskBytes is the total disk space available to executions.
+	DiskBytes uint64
+
+	// ExecutionMemoryBytes is the memory reserved by each execution.
+	ExecutionMemoryBytes uint64
+	// ExecutionDiskBytes is the disk space reserved by each execution while it runs.
+	ExecutionDiskBytes uint64
+
+	// PreemptWindow allows a waiting execution whose deadline is within this duration to preempt the running
+	// execution with the lowest priority when no resources are available. Zero disables preemption.
+	PreemptWindow time.Duration
+}
+
+type Metricer interface {
+	RecordVmResources(running int, waiting int, memoryBytes uint64, diskBytes uint64)
+}
+
+// Manager grants VM executions access to a shared resource budget.
+// When the budget is exhausted, waiting executions are granted resources in priority order,
+// with the execution whose deadline is soonest first. If the budget allows, an execution whose deadline is close
+// preempts the running execution with the lowest priority rather than waiting for it to complete.
+type Manager struct {
+	m      Metricer
+	clock  clock.Clock
+	budget Budget
+
+	lock    sync.Mutex
+	running int
+	memory  uint64
+	disk    uint64
+	waiting waiters
+	active  map[*waiter]struct{}
+	nextSeq uint64
+	// preemptTimer checks for preemption again once the deadline of the highest priority waiter is within the
+	// preemption window
+	preemptTimer clock.Timer
+}
+
+func NewManager(m Metricer, cl clock.Clock, budget Budget) *Manager {
+	return &Manager{
+		m:      m,
+		clock:  cl,
+		budget: budget,
+		active: make(map[*waiter]struct{}),
+	}
+}
+
+// Acquire blocks until the resources for one execution are available, or the context is done.
+// The priority of the execution is taken from the context, see WithDeadline.
+// The execution must use the returned context, which is cancelled with ErrPreempted as the cause if the execution
+// is preempted by a higher priority execution.
+// The returned function must be called to release the resources when the execution completes.
+func (r *Manager) Acquire(ctx context.Context) (context.Context, func(), error) {
+	if err := r.checkFits(); err != nil {
+		return nil, nil, err
+	}
+	deadline, _ := DeadlineFromContext(ctx)
+	execCtx, cancel := context.WithCancelCause(ctx)
+
+	r.lock.Lock()
+	w := &waiter{deadline: deadline, seq: r.nextSeq, ready: make(chan struct{}), cancel: cancel}
+	r.nextSeq++
+	heap.Push(&r.waiting, w)
+	r.grant()
+	r.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return execCtx, r.releaseFunc(w), nil
+	case <-ctx.Done():
+		cancel(ctx.Err())
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if w.index < 0 {
+			// Granted concurrently with the context being cancelled, so give the resources back
+			r.release(w)
+		} else {
+			heap.Remove(&r.waiting, w.index)
+			// Waiters behind this one may now be able to run
+			r.grant()
+		}
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (r *Manager) checkFits() error {
+	if r.budget.MemoryBytes != 0 && r.budget.ExecutionMemoryBytes > r.budget.MemoryBytes {
+		return fmt.Errorf("%w: requires %v bytes of memory but only %v available",
+			ErrExceedsBudget, r.budget.ExecutionMemoryBytes, r.budget.MemoryBytes)
+	}
+	if r.budget.DiskBytes != 0 && r.budget.ExecutionDiskBytes > r.budget.DiskBytes {
+		return fmt.Errorf("%w: requires %v bytes of disk but only %v available",
+			ErrExceedsBudget, r.budget.ExecutionDiskBytes, r.budget.DiskBytes)
+	}
+	return nil
+}
+
+func (r *Manager) releaseFunc(w *waiter) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.lock.Lock()
+			defer r.lock.Unlock()
+			r.release(w)
+		})
+	}
+}
+
+// release returns the resources of one execution. Must be called with the lock held.
+func (r *Manager) release(w *waiter) {
+	delete(r.active, w)
+	w.cancel(context.Canceled)
+	r.running--
+	r.memory -= r.budget.ExecutionMemoryBytes
+	r.disk -= r.budget.ExecutionDiskBytes
+	r.grant()
+}
+
+// grant starts waiting executions in priority order while resources are available.
+// The highest priority waiter blocks all others until it can run, so it isn't starved by lower priority executions.
+// Must be called with the lock held.
+func (r *Manager) grant() {
+	for r.waiting.Len() > 0 && r.available() {
+		w := heap.Pop(&r.waiting).(*waiter)
+		r.running++
+		r.memory += r.budget.ExecutionMemoryBytes
+		r.disk += r.budget.ExecutionDiskBytes
+		r.active[w] = struct{}{}
+		close(w.ready)
+	}
+	r.preempt()
+	r.m.RecordVmResources(r.running, r.waiting.Len(), r.memory, r.disk)
+}
+
+// preempt cancels the lowest priority running execution if the highest priority waiter can't run and its deadline is
+// within the preemption window. If its deadline isn't yet within the window, preemption is checked again once it is.
+// Only one execution is preempted at a time, with the next preemption considered once the preempted execution has
+// released its resources.
+// Must be called with the lock held.
+func (r *Manager) preempt() {
+	if r.preemptTimer != nil {
+		r.preemptTimer.Stop()
+		r.preemptTimer = nil
+	}
+	if r.budget.PreemptWindow == 0 || r.waiting.Len() == 0 {
+		return
+	}
+	next := r.waiting[0]
+	if next.deadline.IsZero() {
+		return
+	}
+	if untilWindow := next.deadline.Sub(r.clock.Now()) - r.budget.PreemptWindow; untilWindow > 0 {
+		r.preemptTimer = r.clock.AfterFunc(untilWindow, func() {
+			// Check in a new goroutine as the clock may call this function while holding its own locks
+			go r.checkPreemption()
+		})
+		return
+	}
+	var victim *waiter
+	for w := range r.active {
+		if w.preempted {
+			// Wait for the previous preemption to complete
+			return
+		}
+		if victim == nil || higherPriority(victim, w) {
+			victim = w
+		}
+	}
+	if victim == nil || !higherPriority(next, victim) {
+		return
+	}
+	victim.preempted = true
+	victim.cancel(ErrPreempted)
+}
+
+// checkPreemption preempts the lowest priority running execution if required.
+func (r *Manager) checkPreemption() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.preempt()
+}
+
+func (r *Manager) available() bool {
+	if r.budget.Slots != 0 && r.running >= r.budget.Slots {
+		return false
+	}
+	if r.budget.MemoryBytes != 0 && r.memory+r.budget.ExecutionMemoryBytes > r.budget.MemoryBytes {
+		return false
+	}
+	if r.budget.DiskBytes != 0 && r.disk+r.budget.ExecutionDiskBytes > r.budget.DiskBytes {
+		return false
+	}
+	return true
+}
+
+type deadlineKey struct{}
+
+// WithDeadline returns a context that prioritises executions by the time they must complete by.
+// Executions with an earlier deadline are granted resources first, and executions without a deadline last.
+func WithDeadline(ctx context.Context, deadline time.Time) context.Context {
+	return context.WithValue(ctx, deadlineKey{}, deadline)
+}
+
+// DeadlineFromContext returns the execution deadline set by WithDeadline, if any.
+func DeadlineFromContext(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(deadlineKey{}).(time.Time)
+	return deadline, ok
+}
+
+type waiter struct {
+	deadline  time.Time
+	seq       uint64
+	ready     chan struct{}
+	index     int
+	cancel    context.CancelCauseFunc
+	preempted bool
+}
+
+// higherPriority returns true if a should be granted resources before b.
+// Executions with a deadline always take priority over those without, then the earliest deadline, then arrival order.
+func higherPriority(a, b *waiter) bool {
+	if a.deadline.IsZero() != b.deadline.IsZero() {
+		return !a.deadline.IsZero()
+	}
+	if !a.deadline.Equal(b.deadline) {
+		return a.deadline.Before(b.deadline)
+	}
+	return a.seq < b.seq
+}
+
+// waiters is a priority queue ordered by deadline then arrival order. Implements heap.Interface.
+type waiters []*waiter
+
+func (w waiters) Len() int { return len(w) }
+
+func (w waiters) Less(i, j int) bool {
+	return higherPriority(w[i], w[j])
+}
+
+func (w waiters) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+	w[i].index = i
+	w[j].index = j
+}
+
+func (w *waiters) Push(x any) {
+	item := x.(*waiter)
+	item.index = len(*w)
+	*w = append(*w, item)
+}
+
+func (w *waiters) Pop() any {
+	old := *w
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*w = old[:n-1]
+	return item
+}
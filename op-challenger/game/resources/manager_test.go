@@ -0,0 +1,275 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_LimitsSlots(t *testing.T) {
+	m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 2})
+	release1 := acquire(t, m, context.Background())
+	release2 := acquire(t, m, context.Background())
+
+	result := acquireAsync(m, context.Background())
+	requireBlocked(t, result)
+
+	release1()
+	requireGranted(t, result)()
+	release2()
+}
+
+func TestManager_LimitsMemoryAndDisk(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{MemoryBytes: 10, ExecutionMemoryBytes: 4})
+		acquire(t, m, context.Background())
+		release := acquire(t, m, context.Background())
+		result := acquireAsync(m, context.Background())
+		requireBlocked(t, result)
+		release()
+		requireGranted(t, result)
+	})
+
+	t.Run("Disk", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{DiskBytes: 10, ExecutionDiskBytes: 6})
+		release := acquire(t, m, context.Background())
+		result := acquireAsync(m, context.Background())
+		requireBlocked(t, result)
+		release()
+		requireGranted(t, result)
+	})
+
+	t.Run("ExceedsBudget", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{MemoryBytes: 10, ExecutionMemoryBytes: 11})
+		_, _, err := m.Acquire(context.Background())
+		require.ErrorIs(t, err, ErrExceedsBudget)
+	})
+}
+
+func TestManager_PrioritisesEarliestDeadline(t *testing.T) {
+	m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 1})
+	release := acquire(t, m, context.Background())
+
+	now := time.Unix(10000, 0)
+	noDeadline := acquireAsync(m, context.Background())
+	requireBlocked(t, noDeadline)
+	late := acquireAsync(m, WithDeadline(context.Background(), now.Add(time.Hour)))
+	requireBlocked(t, late)
+	early := acquireAsync(m, WithDeadline(context.Background(), now.Add(time.Minute)))
+	requireBlocked(t, early)
+
+	release()
+	release = requireGranted(t, early)
+	requireBlocked(t, late)
+	release()
+	release = requireGranted(t, late)
+	requireBlocked(t, noDeadline)
+	release()
+	requireGranted(t, noDeadline)
+}
+
+func TestManager_CancelWhileWaiting(t *testing.T) {
+	metrics := &stubMetrics{}
+	m := NewManager(metrics, clock.SystemClock, Budget{Slots: 1})
+	release := acquire(t, m, context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := acquireAsync(m, WithDeadline(ctx, time.Unix(1, 0)))
+	requireBlocked(t, cancelled)
+	next := acquireAsync(m, context.Background())
+	requireBlocked(t, next)
+
+	cancel()
+	res := <-cancelled
+	require.ErrorIs(t, res.err, context.Canceled)
+	require.Equal(t, 1, metrics.Waiting())
+
+	release()
+	requireGranted(t, next)()
+	require.Equal(t, 0, metrics.Running())
+}
+
+func TestManager_ReleaseIsIdempotent(t *testing.T) {
+	metrics := &stubMetrics{}
+	m := NewManager(metrics, clock.SystemClock, Budget{Slots: 1})
+	release := acquire(t, m, context.Background())
+	release()
+	release()
+	require.Equal(t, 0, metrics.Running())
+}
+
+func TestManager_Preemption(t *testing.T) {
+	t.Run("PreemptLowestPriority", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 2, PreemptWindow: time.Hour})
+		lateCtx, releaseLate := acquireWithContext(t, m, WithDeadline(context.Background(), time.Now().Add(2*time.Hour)))
+		noDeadlineCtx, releaseNoDeadline := acquireWithContext(t, m, context.Background())
+
+		urgent := acquireAsync(m, WithDeadline(context.Background(), time.Now().Add(time.Minute)))
+		requireBlocked(t, urgent)
+		require.ErrorIs(t, context.Cause(noDeadlineCtx), ErrPreempted)
+		require.NoError(t, lateCtx.Err())
+
+		releaseNoDeadline()
+		requireGranted(t, urgent)()
+		releaseLate()
+	})
+
+	t.Run("NotWithinWindow", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 1, PreemptWindow: time.Hour})
+		runningCtx, release := acquireWithContext(t, m, context.Background())
+		result := acquireAsync(m, WithDeadline(context.Background(), time.Now().Add(2*time.Hour)))
+		requireBlocked(t, result)
+		require.NoError(t, runningCtx.Err())
+		release()
+		requireGranted(t, result)()
+	})
+
+	t.Run("WaiterWithoutDeadline", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 1, PreemptWindow: time.Hour})
+		runningCtx, release := acquireWithContext(t, m, context.Background())
+		result := acquireAsync(m, context.Background())
+		requireBlocked(t, result)
+		require.NoError(t, runningCtx.Err())
+		release()
+		requireGranted(t, result)()
+	})
+
+	t.Run("DoNotPreemptHigherPriority", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 1, PreemptWindow: time.Hour})
+		runningCtx, release := acquireWithContext(t, m, WithDeadline(context.Background(), time.Now().Add(time.Minute)))
+		result := acquireAsync(m, WithDeadline(context.Background(), time.Now().Add(10*time.Minute)))
+		requireBlocked(t, result)
+		require.NoError(t, runningCtx.Err())
+		release()
+		requireGranted(t, result)()
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 1})
+		runningCtx, release := acquireWithContext(t, m, context.Background())
+		result := acquireAsync(m, WithDeadline(context.Background(), time.Now().Add(time.Minute)))
+		requireBlocked(t, result)
+		require.NoError(t, runningCtx.Err())
+		release()
+		requireGranted(t, result)()
+	})
+
+	t.Run("PreemptWhenDeadlineEntersWindow", func(t *testing.T) {
+		cl := clock.NewDeterministicClock(time.Unix(10000, 0))
+		m := NewManager(&stubMetrics{}, cl, Budget{Slots: 1, PreemptWindow: time.Hour})
+		runningCtx, release := acquireWithContext(t, m, context.Background())
+		result := acquireAsync(m, WithDeadline(context.Background(), cl.Now().Add(2*time.Hour)))
+		requireBlocked(t, result)
+		require.NoError(t, runningCtx.Err())
+
+		cl.AdvanceTime(59 * time.Minute)
+		requireBlocked(t, result)
+		require.NoError(t, runningCtx.Err())
+
+		cl.AdvanceTime(time.Minute)
+		require.Eventually(t, func() bool {
+			return errors.Is(context.Cause(runningCtx), ErrPreempted)
+		}, 5*time.Second, time.Millisecond)
+		release()
+		requireGranted(t, result)()
+	})
+
+	t.Run("OnePreemptionAtATime", func(t *testing.T) {
+		m := NewManager(&stubMetrics{}, clock.SystemClock, Budget{Slots: 2, PreemptWindow: time.Hour})
+		ctx1, release1 := acquireWithContext(t, m, context.Background())
+		ctx2, release2 := acquireWithContext(t, m, context.Background())
+
+		urgent1 := acquireAsync(m, WithDeadline(context.Background(), time.Now().Add(time.Minute)))
+		urgent2 := acquireAsync(m, WithDeadline(context.Background(), time.Now().Add(2*time.Minute)))
+		requireBlocked(t, urgent1)
+		requireBlocked(t, urgent2)
+		require.ErrorIs(t, context.Cause(ctx2), ErrPreempted, "should preempt the most recent execution")
+		require.NoError(t, ctx1.Err())
+
+		release2()
+		release := requireGranted(t, urgent1)
+		require.ErrorIs(t, context.Cause(ctx1), ErrPreempted, "should preempt next execution once first is released")
+		release1()
+		requireGranted(t, urgent2)()
+		release()
+	})
+}
+
+type acquireResult struct {
+	release func()
+	err     error
+}
+
+func acquire(t *testing.T, m *Manager, ctx context.Context) func() {
+	_, release := acquireWithContext(t, m, ctx)
+	return release
+}
+
+func acquireWithContext(t *testing.T, m *Manager, ctx context.Context) (context.Context, func()) {
+	execCtx, release, err := m.Acquire(ctx)
+	require.NoError(t, err)
+	return execCtx, release
+}
+
+func acquireAsync(m *Manager, ctx context.Context) chan acquireResult {
+	result := make(chan acquireResult, 1)
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		_, release, err := m.Acquire(ctx)
+		result <- acquireResult{release: release, err: err}
+	}()
+	<-started
+	// Allow the acquire call to join the queue so the order of waiters is deterministic
+	time.Sleep(10 * time.Millisecond)
+	return result
+}
+
+func requireBlocked(t *testing.T, result chan acquireResult) {
+	select {
+	case <-result:
+		t.Fatal("acquire should be blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func requireGranted(t *testing.T, result chan acquireResult) func() {
+	select {
+	case res := <-result:
+		require.NoError(t, res.err)
+		return res.release
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire should have been granted")
+		return nil
+	}
+}
+
+type stubMetrics struct {
+	lock    sync.Mutex
+	running int
+	waiting int
+}
+
+func (s *stubMetrics) RecordVmResources(running int, waiting int, _ uint64, _ uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.running = running
+	s.waiting = waiting
+}
+
+func (s *stubMetrics) Running() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.running
+}
+
+func (s *stubMetrics) Waiting() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.waiting
+}
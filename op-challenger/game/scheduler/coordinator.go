@@ -1,10 +1,13 @@
 package scheduler
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"slices"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 
@@ -26,6 +29,9 @@ type CoordinatorMetricer interface {
 type gameState struct {
 	player                GamePlayer
 	inflight              bool
+	running               atomic.Bool
+	queueSeq              uint64
+	scheduledBlockNum     uint64
 	lastProcessedBlockNum uint64
 	status                types.GameStatus
 }
@@ -40,6 +46,9 @@ type coordinator struct {
 	// resultQueue is the incoming queue of jobs that have been completed by workers
 	resultQueue <-chan job
 
+	// inspectQueue is the incoming queue of requests to inspect the tracked games
+	inspectQueue <-chan chan<- []types.GameInspection
+
 	logger       log.Logger
 	m            CoordinatorMetricer
 	createPlayer PlayerCreator
@@ -50,6 +59,9 @@ type coordinator struct {
 
 	// lastScheduledBlockNum is the highest block number that the coordinator has seen and scheduled jobs.
 	lastScheduledBlockNum uint64
+
+	// queueSeq is incremented for each job created so the order of queued jobs is known.
+	queueSeq uint64
 }
 
 // schedule takes the current list of games to attempt to progress, filters out games that have previous
@@ -105,7 +117,8 @@ func (c *coordinator) schedule(ctx context.Context, games []types.GameMetadata,
 	c.lastScheduledBlockNum = blockNumber
 	c.m.RecordActedL1Block(lowestProcessedBlockNum)
 
-	// Finally, enqueue the jobs
+	// Finally, enqueue the jobs with the games closest to a clock expiring first
+	c.prioritise(jobs)
 	for _, j := range jobs {
 		if err := c.enqueueJob(ctx, j); err != nil {
 			errs = append(errs, fmt.Errorf("failed to enqueue job for game %v: %w", j.addr, err))
@@ -149,7 +162,37 @@ func (c *coordinator) createJob(ctx context.Context, game types.GameMetadata, bl
 		return nil, nil
 	}
 	state.inflight = true
-	return newJob(blockNumber, game.Proxy, state.player, state.status), nil
+	state.scheduledBlockNum = blockNumber
+	return newJob(blockNumber, game.Proxy, state.player, state.status, &state.running), nil
+}
+
+// prioritise sorts jobs by the response deadline from the last time each game was progressed, earliest first.
+// Games without a known deadline are queued after those with one, keeping their existing order.
+// The queue sequence of each game is updated to match the order jobs will be queued in.
+func (c *coordinator) prioritise(jobs []job) {
+	deadlines := make(map[common.Address]time.Time, len(jobs))
+	for _, j := range jobs {
+		if inspection := j.player.Inspect(); inspection != nil {
+			deadlines[j.addr] = inspection.ResponseDeadline
+		}
+	}
+	slices.SortStableFunc(jobs, func(a, b job) int {
+		aDeadline, bDeadline := deadlines[a.addr], deadlines[b.addr]
+		switch {
+		case aDeadline.IsZero() && bDeadline.IsZero():
+			return 0
+		case aDeadline.IsZero():
+			return 1
+		case bDeadline.IsZero():
+			return -1
+		default:
+			return aDeadline.Compare(bDeadline)
+		}
+	})
+	for _, j := range jobs {
+		c.queueSeq++
+		c.states[j.addr].queueSeq = c.queueSeq
+	}
 }
 
 func (c *coordinator) enqueueJob(ctx context.Context, j job) error {
@@ -161,6 +204,8 @@ func (c *coordinator) enqueueJob(ctx context.Context, j job) error {
 			if err := c.processResult(result); err != nil {
 				c.logger.Error("Failed to process result", "err", err)
 			}
+		case req := <-c.inspectQueue:
+			req <- c.inspect()
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -173,6 +218,7 @@ func (c *coordinator) processResult(j job) error {
 		return fmt.Errorf("game %v received unexpected result: %w", j.addr, errUnknownGame)
 	}
 	state.inflight = false
+	state.running.Store(false)
 	state.status = j.status
 	state.lastProcessedBlockNum = j.block
 	c.deleteResolvedGameFiles()
@@ -180,24 +226,63 @@ func (c *coordinator) processResult(j job) error {
 	return nil
 }
 
+// inspect reports the scheduling state of each tracked game and the player's view of the game, sorted by address.
+func (c *coordinator) inspect() []types.GameInspection {
+	inspections := make([]types.GameInspection, 0, len(c.states))
+	for addr, state := range c.states {
+		inspection := types.GameInspection{
+			Game:   addr,
+			Status: state.status.String(),
+			Schedule: types.ScheduleInspection{
+				Inflight:           state.inflight,
+				Running:            state.inflight && state.running.Load(),
+				LastProcessedBlock: state.lastProcessedBlockNum,
+			},
+		}
+		if state.inflight {
+			inspection.Schedule.ScheduledBlock = state.scheduledBlockNum
+		}
+		if inspection.Schedule.Inflight && !inspection.Schedule.Running {
+			for _, other := range c.states {
+				if other.inflight && !other.running.Load() && other.queueSeq < state.queueSeq {
+					inspection.Schedule.QueuePosition++
+				}
+			}
+		}
+		if state.player != nil {
+			inspection.Player = state.player.Inspect()
+		}
+		inspections = append(inspections, inspection)
+	}
+	slices.SortFunc(inspections, func(a, b types.GameInspection) int {
+		return bytes.Compare(a.Game[:], b.Game[:])
+	})
+	return inspections
+}
+
 func (c *coordinator) deleteResolvedGameFiles() {
 	var keepGames []common.Address
+	var inflightGames []common.Address
 	for addr, state := range c.states {
 		if state.status == types.GameStatusInProgress || state.inflight {
 			keepGames = append(keepGames, addr)
 		}
+		if state.inflight {
+			inflightGames = append(inflightGames, addr)
+		}
 	}
-	if err := c.disk.RemoveAllExcept(keepGames); err != nil {
+	if err := c.disk.RemoveAllExcept(keepGames, inflightGames); err != nil {
 		c.logger.Error("Unable to cleanup game data", "err", err)
 	}
 }
 
-func newCoordinator(logger log.Logger, m CoordinatorMetricer, jobQueue chan<- job, resultQueue <-chan job, createPlayer PlayerCreator, disk DiskManager, allowInvalidPrestate bool) *coordinator {
+func newCoordinator(logger log.Logger, m CoordinatorMetricer, jobQueue chan<- job, resultQueue <-chan job, inspectQueue <-chan chan<- []types.GameInspection, createPlayer PlayerCreator, disk DiskManager, allowInvalidPrestate bool) *coordinator {
 	return &coordinator{
 		logger:               logger,
 		m:                    m,
 		jobQueue:             jobQueue,
 		resultQueue:          resultQueue,
+		inspectQueue:         inspectQueue,
 		createPlayer:         createPlayer,
 		disk:                 disk,
 		states:               make(map[common.Address]*gameState),
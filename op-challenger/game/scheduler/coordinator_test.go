@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler/test"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
@@ -119,6 +120,42 @@ func TestScheduleGameAgainAfterCompletion(t *testing.T) {
 	require.Len(t, workQueue, 1, "should reschedule completed game")
 }
 
+func TestSchedulePrioritisesEarliestResponseDeadline(t *testing.T) {
+	c, workQueue, _, games, _, _ := setupCoordinatorTest(t, 10)
+	late := common.Address{0xaa}
+	noDeadline := common.Address{0xbb}
+	early := common.Address{0xcc}
+	mid := common.Address{0xdd}
+	ctx := context.Background()
+	allGames := asGames(late, noDeadline, early, mid)
+
+	// Games that haven't been progressed yet are queued in order
+	require.NoError(t, c.schedule(ctx, allGames, 0))
+	for _, addr := range []common.Address{late, noDeadline, early, mid} {
+		j := <-workQueue
+		require.Equal(t, addr, j.addr)
+		require.NoError(t, c.processResult(j))
+	}
+
+	now := time.Unix(10000, 0)
+	games.created[late].Inspection = &types.PlayerInspection{ResponseDeadline: now.Add(time.Hour)}
+	games.created[noDeadline].Inspection = &types.PlayerInspection{}
+	games.created[early].Inspection = &types.PlayerInspection{ResponseDeadline: now.Add(time.Minute)}
+	games.created[mid].Inspection = &types.PlayerInspection{ResponseDeadline: now.Add(10 * time.Minute)}
+	require.NoError(t, c.schedule(ctx, allGames, 1))
+
+	inspections := c.inspect()
+	queuePositions := make(map[common.Address]int)
+	for _, inspection := range inspections {
+		queuePositions[inspection.Game] = inspection.Schedule.QueuePosition
+	}
+	for i, addr := range []common.Address{early, mid, late, noDeadline} {
+		require.Equal(t, i, queuePositions[addr], "incorrect queue position for game %v", addr)
+		j := <-workQueue
+		require.Equal(t, addr, j.addr)
+	}
+}
+
 func TestResultForUnknownGame(t *testing.T) {
 	c, _, _, _, _, _ := setupCoordinatorTest(t, 10)
 	err := c.processResult(job{addr: common.Address{0xaa}})
@@ -392,6 +429,52 @@ func TestDropOldGameStates(t *testing.T) {
 	require.Contains(t, c.states, gameAddr4, "should create state for game 4")
 }
 
+func TestInspect(t *testing.T) {
+	c, workQueue, _, games, _, _ := setupCoordinatorTest(t, 10)
+	gameAddr1 := common.Address{0xaa}
+	gameAddr2 := common.Address{0xbb}
+	gameAddr3 := common.Address{0xcc}
+	ctx := context.Background()
+
+	require.NoError(t, c.schedule(ctx, asGames(gameAddr3, gameAddr1, gameAddr2), 5))
+	playerInspection := &types.PlayerInspection{Error: "boom"}
+	games.created[gameAddr2].Inspection = playerInspection
+
+	// First job has been picked up by a worker
+	j := <-workQueue
+	require.Equal(t, gameAddr3, j.addr)
+	j.running.Store(true)
+
+	inspections := c.inspect()
+	require.Equal(t, []types.GameInspection{
+		{
+			Game:     gameAddr1,
+			Status:   types.GameStatusInProgress.String(),
+			Schedule: types.ScheduleInspection{Inflight: true, QueuePosition: 0, ScheduledBlock: 5},
+		},
+		{
+			Game:     gameAddr2,
+			Status:   types.GameStatusInProgress.String(),
+			Schedule: types.ScheduleInspection{Inflight: true, QueuePosition: 1, ScheduledBlock: 5},
+			Player:   playerInspection,
+		},
+		{
+			Game:     gameAddr3,
+			Status:   types.GameStatusInProgress.String(),
+			Schedule: types.ScheduleInspection{Inflight: true, Running: true, ScheduledBlock: 5},
+		},
+	}, inspections)
+
+	j.status = types.GameStatusDefenderWon
+	require.NoError(t, c.processResult(j))
+	inspections = c.inspect()
+	require.Equal(t, types.GameInspection{
+		Game:     gameAddr3,
+		Status:   types.GameStatusDefenderWon.String(),
+		Schedule: types.ScheduleInspection{LastProcessedBlock: 5},
+	}, inspections[2])
+}
+
 func setupCoordinatorTest(t *testing.T, bufferSize int) (*coordinator, <-chan job, chan job, *createdGames, *stubDiskManager, *testlog.CapturingHandler) {
 	logger, logs := testlog.CaptureLogger(t, log.LevelInfo)
 	workQueue := make(chan job, bufferSize)
@@ -401,7 +484,7 @@ func setupCoordinatorTest(t *testing.T, bufferSize int) (*coordinator, <-chan jo
 		created: make(map[common.Address]*test.StubGamePlayer),
 	}
 	disk := &stubDiskManager{gameDirExists: make(map[common.Address]bool)}
-	c := newCoordinator(logger, &stubSchedulerMetrics{}, workQueue, resultQueue, games.CreateGame, disk, false)
+	c := newCoordinator(logger, &stubSchedulerMetrics{}, workQueue, resultQueue, make(chan chan<- []types.GameInspection), games.CreateGame, disk, false)
 	return c, workQueue, resultQueue, games, disk, logs
 }
 
@@ -459,7 +542,7 @@ func (s *stubDiskManager) DirForGame(addr common.Address) string {
 	return addr.Hex()
 }
 
-func (s *stubDiskManager) RemoveAllExcept(addrs []common.Address) error {
+func (s *stubDiskManager) RemoveAllExcept(addrs []common.Address, _ []common.Address) error {
 	for address := range s.gameDirExists {
 		keep := slices.Contains(addrs, address)
 		s.gameDirExists[address] = keep
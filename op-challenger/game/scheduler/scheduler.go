@@ -35,6 +35,7 @@ type Scheduler struct {
 	scheduleQueue  chan blockGames
 	jobQueue       chan job
 	resultQueue    chan job
+	inspectQueue   chan chan<- []types.GameInspection
 	wg             sync.WaitGroup
 	cancel         func()
 }
@@ -49,14 +50,17 @@ func NewScheduler(logger log.Logger, m SchedulerMetricer, disk DiskManager, maxC
 	// allowing them to potentially skip update cycles.
 	scheduleQueue := make(chan blockGames, 1)
 
+	inspectQueue := make(chan chan<- []types.GameInspection)
+
 	return &Scheduler{
 		logger:         logger,
 		m:              m,
-		coordinator:    newCoordinator(logger, m, jobQueue, resultQueue, createPlayer, disk, allowInvalidPrestate),
+		coordinator:    newCoordinator(logger, m, jobQueue, resultQueue, inspectQueue, createPlayer, disk, allowInvalidPrestate),
 		maxConcurrency: maxConcurrency,
 		scheduleQueue:  scheduleQueue,
 		jobQueue:       jobQueue,
 		resultQueue:    resultQueue,
+		inspectQueue:   inspectQueue,
 	}
 }
 
@@ -99,6 +103,22 @@ func (s *Scheduler) Schedule(games []types.GameMetadata, blockNumber uint64) err
 	}
 }
 
+// Inspect returns the scheduling state of each tracked game along with the game player's view of the game.
+func (s *Scheduler) Inspect(ctx context.Context) ([]types.GameInspection, error) {
+	result := make(chan []types.GameInspection, 1)
+	select {
+	case s.inspectQueue <- result:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case inspections := <-result:
+		return inspections, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 func (s *Scheduler) loop(ctx context.Context) {
 	defer s.wg.Done()
 	for {
@@ -113,6 +133,8 @@ func (s *Scheduler) loop(ctx context.Context) {
 			if err := s.coordinator.processResult(j); err != nil {
 				s.logger.Error("Error while processing game result", "game", j.addr, "err", err)
 			}
+		case req := <-s.inspectQueue:
+			req <- s.coordinator.inspect()
 		}
 	}
 }
@@ -67,7 +67,7 @@ func (t *trackingDiskManager) DirForGame(addr common.Address) string {
 	return addr.Hex()
 }
 
-func (t *trackingDiskManager) RemoveAllExcept(addrs []common.Address) error {
+func (t *trackingDiskManager) RemoveAllExcept(addrs []common.Address, _ []common.Address) error {
 	t.removeExceptCalls <- addrs
 	return nil
 }
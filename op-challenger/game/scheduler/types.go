@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -12,11 +13,13 @@ type GamePlayer interface {
 	ValidatePrestate(ctx context.Context) error
 	ProgressGame(ctx context.Context) types.GameStatus
 	Status() types.GameStatus
+	Inspect() *types.PlayerInspection
 }
 
 type DiskManager interface {
 	DirForGame(addr common.Address) string
-	RemoveAllExcept(addrs []common.Address) error
+	// RemoveAllExcept removes data for games not in keep. Data that is in use by the inflight games is never removed.
+	RemoveAllExcept(keep []common.Address, inflight []common.Address) error
 }
 
 type job struct {
@@ -24,13 +27,16 @@ type job struct {
 	addr   common.Address
 	player GamePlayer
 	status types.GameStatus
+	// running is set by the worker while it is progressing the game.
+	running *atomic.Bool
 }
 
-func newJob(block uint64, addr common.Address, player GamePlayer, status types.GameStatus) *job {
+func newJob(block uint64, addr common.Address, player GamePlayer, status types.GameStatus, running *atomic.Bool) *job {
 	return &job{
-		block:  block,
-		addr:   addr,
-		player: player,
-		status: status,
+		block:   block,
+		addr:    addr,
+		player:  player,
+		status:  status,
+		running: running,
 	}
 }
@@ -16,6 +16,7 @@ func progressGames(ctx context.Context, in <-chan job, out chan<- job, wg *sync.
 			return
 		case j := <-in:
 			threadActive()
+			j.running.Store(true)
 			j.status = j.player.ProgressGame(ctx)
 			out <- j
 			threadIdle()
@@ -26,7 +26,8 @@ func TestWorkerShouldProcessJobsUntilContextDone(t *testing.T) {
 	go progressGames(ctx, in, out, &wg, ms.ThreadActive, ms.ThreadIdle)
 
 	in <- job{
-		player: &test.StubGamePlayer{StatusValue: types.GameStatusInProgress},
+		player:  &test.StubGamePlayer{StatusValue: types.GameStatusInProgress},
+		running: &atomic.Bool{},
 	}
 	waitErr := wait.For(context.Background(), 100*time.Millisecond, func() (bool, error) {
 		return ms.activeCalls.Load() >= 1, nil
@@ -36,7 +37,8 @@ func TestWorkerShouldProcessJobsUntilContextDone(t *testing.T) {
 	require.EqualValues(t, ms.idleCalls.Load(), 1)
 
 	in <- job{
-		player: &test.StubGamePlayer{StatusValue: types.GameStatusDefenderWon},
+		player:  &test.StubGamePlayer{StatusValue: types.GameStatusDefenderWon},
+		running: &atomic.Bool{},
 	}
 	waitErr = wait.For(context.Background(), 100*time.Millisecond, func() (bool, error) {
 		return ms.activeCalls.Load() >= 2, nil
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
 	"sync/atomic"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/game/keccak"
@@ -20,8 +21,10 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/registry"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/resources"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-challenger/rpc"
 	"github.com/ethereum-optimism/optimism/op-challenger/version"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/clock"
@@ -29,10 +32,14 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/httputil"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
+// bondLedgerFile is the file in the data directory the bond ledger is persisted to.
+const bondLedgerFile = "bond-ledger.json"
+
 type Service struct {
 	logger  log.Logger
 	metrics metrics.Metricer
@@ -46,22 +53,27 @@ type Service struct {
 	txMgr    *txmgr.SimpleTxManager
 	txSender *sender.TxSender
 
+	deferrals *sender.DeferralQueue
+
 	systemClock clock.Clock
 	l1Clock     *clock.SimpleClock
 
 	claimants []common.Address
 	claimer   *claims.BondClaimScheduler
+	bonds     *claims.BondLedger
 
 	factoryContract *contracts.DisputeGameFactoryContract
 	registry        *registry.GameTypeRegistry
 	oracles         *registry.OracleRegistry
 	rollupClient    *sources.RollupClient
+	supervisor      *sources.SupervisorClient
 
 	l1Client   *ethclient.Client
 	pollClient client.RPC
 
 	pprofService *oppprof.Service
 	metricsSrv   *httputil.HTTPServer
+	rpcServer    *oprpc.Server
 
 	balanceMetricer io.Closer
 
@@ -96,6 +108,9 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 	if err := s.initRollupClient(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to init rollup client: %w", err)
 	}
+	if err := s.initSupervisorClient(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to init supervisor client: %w", err)
+	}
 	if err := s.initPollClient(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to init poll client: %w", err)
 	}
@@ -111,7 +126,7 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 	if err := s.registerGameTypes(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to register game types: %w", err)
 	}
-	if err := s.initBondClaims(); err != nil {
+	if err := s.initBondClaims(cfg); err != nil {
 		return fmt.Errorf("failed to init bond claiming: %w", err)
 	}
 	if err := s.initScheduler(cfg); err != nil {
@@ -123,6 +138,10 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 
 	s.initMonitor(cfg)
 
+	if err := s.initRPCServer(cfg); err != nil {
+		return fmt.Errorf("failed to init rpc server: %w", err)
+	}
+
 	s.metrics.RecordInfo(version.SimpleWithMeta)
 	s.metrics.RecordUp()
 	return nil
@@ -140,6 +159,10 @@ func (s *Service) initTxManager(ctx context.Context, cfg *config.Config) error {
 	}
 	s.txMgr = txMgr
 	s.txSender = sender.NewTxSender(ctx, s.logger, txMgr, cfg.MaxPendingTx)
+	s.deferrals = sender.NewDeferralQueue(s.logger, s.systemClock, txMgr, cfg.NonUrgentMaxGasPrice, cfg.MaxActionDeferral)
+	if cfg.MulticallAddress != (common.Address{}) {
+		s.txSender.EnableBatching(contracts.NewMulticall3(cfg.MulticallAddress), int(cfg.MulticallBatchSize))
+	}
 	return nil
 }
 
@@ -204,12 +227,41 @@ func (s *Service) initFactoryContract(cfg *config.Config) error {
 	return nil
 }
 
-func (s *Service) initBondClaims() error {
-	claimer := claims.NewBondClaimer(s.logger, s.metrics, s.registry.CreateBondContract, s.txSender, s.claimants...)
+func (s *Service) initBondClaims(cfg *config.Config) error {
+	ledger, err := claims.OpenBondLedger(s.metrics, filepath.Join(cfg.Datadir, bondLedgerFile), s.claimants...)
+	if err != nil {
+		return err
+	}
+	s.bonds = ledger
+	costEstimator := sender.NewGasCostEstimator(s.l1Client, s.txMgr, s.txSender.From())
+	claimer := claims.NewBondClaimer(s.logger, s.metrics, s.registry.CreateBondContract, s.txSender, costEstimator, s.deferrals, ledger, s.claimants...)
 	s.claimer = claims.NewBondClaimScheduler(s.logger, s.metrics, claimer)
 	return nil
 }
 
+func (s *Service) initRPCServer(cfg *config.Config) error {
+	if !cfg.RPCEnabled {
+		return nil
+	}
+	server := oprpc.NewServer(
+		cfg.RPCConfig.ListenAddr,
+		cfg.RPCConfig.ListenPort,
+		version.SimpleWithMeta,
+		oprpc.WithLogger(s.logger),
+	)
+	server.AddAPI(rpc.GetChallengerAPI(rpc.NewChallengerAPI(s.bonds, s.sched, s.deferrals)))
+	if cfg.RPCConfig.EnableAdmin {
+		server.AddAPI(s.txMgr.API())
+		s.logger.Info("Admin RPC enabled")
+	}
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("unable to start RPC server: %w", err)
+	}
+	s.logger.Info("Started RPC server", "endpoint", server.Endpoint())
+	s.rpcServer = server
+	return nil
+}
+
 func (s *Service) initRollupClient(ctx context.Context, cfg *config.Config) error {
 	if cfg.RollupRpc == "" {
 		return nil
@@ -222,11 +274,28 @@ func (s *Service) initRollupClient(ctx context.Context, cfg *config.Config) erro
 	return nil
 }
 
+func (s *Service) initSupervisorClient(ctx context.Context, cfg *config.Config) error {
+	if cfg.SupervisorRpc == "" {
+		return nil
+	}
+	supervisor, err := dial.DialSupervisorClientWithTimeout(ctx, dial.DefaultDialTimeout, s.logger, cfg.SupervisorRpc)
+	if err != nil {
+		return err
+	}
+	s.supervisor = supervisor
+	return nil
+}
+
 func (s *Service) registerGameTypes(ctx context.Context, cfg *config.Config) error {
 	gameTypeRegistry := registry.NewGameTypeRegistry()
 	oracles := registry.NewOracleRegistry()
 	caller := batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize)
-	closer, err := fault.RegisterGameTypes(ctx, s.systemClock, s.l1Clock, s.logger, s.metrics, cfg, gameTypeRegistry, oracles, s.rollupClient, s.txSender, s.factoryContract, caller, s.l1Client, cfg.SelectiveClaimResolution, s.claimants)
+	vmResources := resources.NewManager(s.metrics, s.systemClock, cfg.VmResources)
+	var supervisor fault.SupervisorClient
+	if s.supervisor != nil {
+		supervisor = s.supervisor
+	}
+	closer, err := fault.RegisterGameTypes(ctx, s.systemClock, s.l1Clock, s.logger, s.metrics, cfg, vmResources, gameTypeRegistry, oracles, s.rollupClient, supervisor, s.txSender, s.factoryContract, caller, s.l1Client, cfg.SelectiveClaimResolution, s.claimants, s.deferrals)
 	if err != nil {
 		return err
 	}
@@ -237,7 +306,11 @@ func (s *Service) registerGameTypes(ctx context.Context, cfg *config.Config) err
 }
 
 func (s *Service) initScheduler(cfg *config.Config) error {
-	disk := newDiskManager(cfg.Datadir)
+	disk := newDiskManager(s.logger, s.metrics, s.systemClock, cfg.Datadir, DiskLimits{
+		GameQuota:             cfg.GameDiskQuota,
+		TotalQuota:            cfg.DiskQuota,
+		ResolvedGameRetention: cfg.ResolvedGameRetention,
+	})
 	s.sched = scheduler.NewScheduler(s.logger, s.metrics, disk, cfg.MaxConcurrency, s.registry.CreatePlayer, cfg.AllowInvalidPrestate)
 	return nil
 }
@@ -307,6 +380,9 @@ func (s *Service) Stop(ctx context.Context) error {
 	if s.rollupClient != nil {
 		s.rollupClient.Close()
 	}
+	if s.supervisor != nil {
+		s.supervisor.Close()
+	}
 	if s.pollClient != nil {
 		s.pollClient.Close()
 	}
@@ -318,6 +394,11 @@ func (s *Service) Stop(ctx context.Context) error {
 			result = errors.Join(result, fmt.Errorf("failed to close metrics server: %w", err))
 		}
 	}
+	if s.rpcServer != nil {
+		if err := s.rpcServer.Stop(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close rpc server: %w", err))
+		}
+	}
 	s.stopped.Store(true)
 	s.logger.Info("stopped challenger game service", "err", result)
 	return result
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ClaimAssessment is the honest actor's view of a claim.
+type ClaimAssessment string
+
+const (
+	// ClaimHonest is a claim the honest actor would make.
+	ClaimHonest ClaimAssessment = "honest"
+	// ClaimDishonest is a claim the honest actor counters.
+	ClaimDishonest ClaimAssessment = "dishonest"
+	// ClaimIgnored is a claim the honest actor has no need to counter, such as claims that only counter other
+	// dishonest claims.
+	ClaimIgnored ClaimAssessment = "ignored"
+	// ClaimUnknown is a claim that couldn't be assessed because of an error calculating the responses to earlier claims.
+	ClaimUnknown ClaimAssessment = "unknown"
+)
+
+// GameInspection is the challenger's internal view of a tracked game.
+type GameInspection struct {
+	Game     common.Address     `json:"game"`
+	Status   string             `json:"status"`
+	Schedule ScheduleInspection `json:"schedule"`
+	// Player is nil if the game player hasn't been created yet or the game was already resolved when first loaded.
+	Player *PlayerInspection `json:"player,omitempty"`
+}
+
+// ScheduleInspection describes where a game is up to in the scheduler.
+type ScheduleInspection struct {
+	// Inflight is true when the game has been scheduled to be progressed and the result hasn't been processed yet.
+	Inflight bool `json:"inflight"`
+	// Running is true when a worker is currently progressing the game.
+	Running bool `json:"running"`
+	// QueuePosition is the number of games queued ahead of this one, when the game is inflight but not yet running.
+	QueuePosition int `json:"queuePosition"`
+	// ScheduledBlock is the L1 block the inflight progression was scheduled for.
+	ScheduledBlock uint64 `json:"scheduledBlock"`
+	// LastProcessedBlock is the L1 block the game was last progressed for.
+	LastProcessedBlock uint64 `json:"lastProcessedBlock"`
+}
+
+// PlayerInspection records the result of the last time the game was progressed.
+type PlayerInspection struct {
+	Updated time.Time         `json:"updated"`
+	Claims  []ClaimInspection `json:"claims"`
+	Moves   []MoveInspection  `json:"moves"`
+	// ResponseDeadline is the earliest time a chess clock in the game expires. Zero if no clocks are running.
+	ResponseDeadline time.Time `json:"responseDeadline"`
+	// Error is the error that prevented responses being calculated for all claims, if any.
+	// Errors running the VM or fetching the local trace are reported here.
+	Error string `json:"error,omitempty"`
+}
+
+type ClaimInspection struct {
+	Index        int             `json:"index"`
+	ParentIndex  int             `json:"parentIndex"`
+	Depth        uint64          `json:"depth"`
+	IndexAtDepth *big.Int        `json:"indexAtDepth"`
+	Value        common.Hash     `json:"value"`
+	Claimant     common.Address  `json:"claimant"`
+	CounteredBy  common.Address  `json:"counteredBy"`
+	Assessment   ClaimAssessment `json:"assessment"`
+}
+
+// MoveInspection is an action the challenger planned to perform and its outcome.
+type MoveInspection struct {
+	Type        string      `json:"type"`
+	ParentIndex int         `json:"parentIndex"`
+	IsAttack    bool        `json:"isAttack"`
+	Value       common.Hash `json:"value,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
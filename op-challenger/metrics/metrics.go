@@ -2,8 +2,10 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/httputil"
 	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 	"github.com/ethereum/go-ethereum/common"
@@ -46,6 +48,7 @@ type Metricer interface {
 
 	RecordBondClaimFailed()
 	RecordBondClaimed(amount uint64)
+	RecordBondLedger(posted, atRisk, claimable, claimed *big.Int)
 
 	RecordGamesStatus(inProgress, defenderWon, challengerWon int)
 
@@ -59,6 +62,15 @@ type Metricer interface {
 	IncIdleExecutors()
 	DecIdleExecutors()
 
+	RecordVmResources(running int, waiting int, memoryBytes uint64, diskBytes uint64)
+
+	RecordGameDataDisk(games int, bytes uint64)
+	RecordGameDataPruned(bytes uint64)
+
+	RecordDivergence(kind string)
+
+	RecordParticipationDecision(participate bool, reason string)
+
 	// Record vm execution metrics
 	VmMetricer
 	VmMetrics(vmType string) *VmMetrics
@@ -98,6 +110,16 @@ type Metrics struct {
 	gameActTime         prometheus.Histogram
 	vmExecutionTime     *prometheus.HistogramVec
 	vmMemoryUsed        *prometheus.HistogramVec
+	vmResources         prometheus.GaugeVec
+	bondLedger          prometheus.GaugeVec
+
+	gameDataDirs        prometheus.Gauge
+	gameDataBytes       prometheus.Gauge
+	gameDataPrunedBytes prometheus.Counter
+
+	divergences prometheus.CounterVec
+
+	participationDecisions prometheus.CounterVec
 
 	trackedGames  prometheus.GaugeVec
 	inflightGames prometheus.Gauge
@@ -187,6 +209,50 @@ func NewMetrics() *Metrics {
 			// 100MiB increments from 0 to 1.5GiB
 			Buckets: prometheus.LinearBuckets(0, 1024*1024*100, 15),
 		}, []string{"vm"}),
+		vmResources: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "vm_resources",
+			Help:      "Resources reserved by running VM executions and the number of executions waiting for resources",
+		}, []string{
+			"resource",
+		}),
+		gameDataDirs: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "game_data_dirs",
+			Help:      "Number of games with data stored on disk, including resolved games being retained",
+		}),
+		gameDataBytes: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "game_data_bytes",
+			Help:      "Disk space used by game data, including VM snapshots and proofs",
+		}),
+		gameDataPrunedBytes: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "game_data_pruned_bytes",
+			Help:      "Total disk space freed by removing game data and VM snapshots",
+		}),
+		divergences: *factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "divergences",
+			Help:      "Number of claims where the local trace diverged from an honest claim or the output root",
+		}, []string{
+			"kind",
+		}),
+		participationDecisions: *factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "participation_decisions",
+			Help:      "Number of games the participation policy decided to play or skip, by reason",
+		}, []string{
+			"decision",
+			"reason",
+		}),
+		bondLedger: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "bond_ledger",
+			Help:      "Total bonds in ETH posted, at risk, claimable and claimed by the claimants across all games",
+		}, []string{
+			"state",
+		}),
 		bondClaimFailures: factory.NewCounter(prometheus.CounterOpts{
 			Namespace: Namespace,
 			Name:      "claim_failures",
@@ -284,6 +350,13 @@ func (m *Metrics) RecordLargePreimageCount(count int) {
 	m.preimageCount.Set(float64(count))
 }
 
+func (m *Metrics) RecordBondLedger(posted, atRisk, claimable, claimed *big.Int) {
+	m.bondLedger.WithLabelValues("posted").Set(eth.WeiToEther(posted))
+	m.bondLedger.WithLabelValues("at_risk").Set(eth.WeiToEther(atRisk))
+	m.bondLedger.WithLabelValues("claimable").Set(eth.WeiToEther(claimable))
+	m.bondLedger.WithLabelValues("claimed").Set(eth.WeiToEther(claimed))
+}
+
 func (m *Metrics) RecordBondClaimFailed() {
 	m.bondClaimFailures.Add(1)
 }
@@ -324,6 +397,34 @@ func (m *Metrics) DecIdleExecutors() {
 	m.executors.WithLabelValues("idle").Dec()
 }
 
+func (m *Metrics) RecordVmResources(running int, waiting int, memoryBytes uint64, diskBytes uint64) {
+	m.vmResources.WithLabelValues("running").Set(float64(running))
+	m.vmResources.WithLabelValues("waiting").Set(float64(waiting))
+	m.vmResources.WithLabelValues("memory_bytes").Set(float64(memoryBytes))
+	m.vmResources.WithLabelValues("disk_bytes").Set(float64(diskBytes))
+}
+
+func (m *Metrics) RecordGameDataDisk(games int, bytes uint64) {
+	m.gameDataDirs.Set(float64(games))
+	m.gameDataBytes.Set(float64(bytes))
+}
+
+func (m *Metrics) RecordGameDataPruned(bytes uint64) {
+	m.gameDataPrunedBytes.Add(float64(bytes))
+}
+
+func (m *Metrics) RecordDivergence(kind string) {
+	m.divergences.WithLabelValues(kind).Inc()
+}
+
+func (m *Metrics) RecordParticipationDecision(participate bool, reason string) {
+	decision := "skip"
+	if participate {
+		decision = "participate"
+	}
+	m.participationDecisions.WithLabelValues(decision, reason).Inc()
+}
+
 func (m *Metrics) RecordGamesStatus(inProgress, defenderWon, challengerWon int) {
 	m.trackedGames.WithLabelValues("in_progress").Set(float64(inProgress))
 	m.trackedGames.WithLabelValues("defender_won").Set(float64(defenderWon))
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 	"time"
 
 	contractMetrics "github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts/metrics"
@@ -36,8 +37,9 @@ func (*NoopMetricsImpl) RecordPreimageChallenged()      {}
 func (*NoopMetricsImpl) RecordPreimageChallengeFailed() {}
 func (*NoopMetricsImpl) RecordLargePreimageCount(_ int) {}
 
-func (*NoopMetricsImpl) RecordBondClaimFailed()   {}
-func (*NoopMetricsImpl) RecordBondClaimed(uint64) {}
+func (*NoopMetricsImpl) RecordBondClaimFailed()               {}
+func (*NoopMetricsImpl) RecordBondClaimed(uint64)             {}
+func (*NoopMetricsImpl) RecordBondLedger(_, _, _, _ *big.Int) {}
 
 func (*NoopMetricsImpl) RecordVmExecutionTime(_ string, _ time.Duration) {}
 func (*NoopMetricsImpl) RecordVmMemoryUsed(_ string, _ uint64)           {}
@@ -54,6 +56,15 @@ func (*NoopMetricsImpl) DecActiveExecutors() {}
 func (*NoopMetricsImpl) IncIdleExecutors()   {}
 func (*NoopMetricsImpl) DecIdleExecutors()   {}
 
+func (*NoopMetricsImpl) RecordVmResources(_ int, _ int, _ uint64, _ uint64) {}
+
+func (*NoopMetricsImpl) RecordGameDataDisk(_ int, _ uint64) {}
+func (*NoopMetricsImpl) RecordGameDataPruned(_ uint64)      {}
+
+func (*NoopMetricsImpl) RecordDivergence(_ string) {}
+
+func (*NoopMetricsImpl) RecordParticipationDecision(_ bool, _ string) {}
+
 func (*NoopMetricsImpl) CacheAdd(_ string, _ int, _ bool) {}
 func (*NoopMetricsImpl) CacheGet(_ string, _ bool)        {}
 
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
+	"github.com/ethereum/go-ethereum/common"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+var ErrGameNotTracked = errors.New("game not tracked")
+
+type BondReporter interface {
+	Report() claims.BondReport
+}
+
+type GameInspector interface {
+	Inspect(ctx context.Context) ([]types.GameInspection, error)
+}
+
+type DeferralReporter interface {
+	Deferred() []sender.DeferredAction
+}
+
+// ChallengerAPI reports on the state of the challenger.
+type ChallengerAPI struct {
+	bonds     BondReporter
+	games     GameInspector
+	deferrals DeferralReporter
+}
+
+func NewChallengerAPI(bonds BondReporter, games GameInspector, deferrals DeferralReporter) *ChallengerAPI {
+	return &ChallengerAPI{
+		bonds:     bonds,
+		games:     games,
+		deferrals: deferrals,
+	}
+}
+
+func GetChallengerAPI(api *ChallengerAPI) gethrpc.API {
+	return gethrpc.API{
+		Namespace: "challenger",
+		Service:   api,
+	}
+}
+
+// BondReport returns the bonds posted, at risk, claimable and claimed in each game and the totals across all games.
+func (a *ChallengerAPI) BondReport(_ context.Context) (claims.BondReport, error) {
+	return a.bonds.Report(), nil
+}
+
+// InspectGames returns the challenger's view of each tracked game, including the assessment of its claims, the moves
+// planned the last time the game was progressed and where the game is up to in the scheduler.
+func (a *ChallengerAPI) InspectGames(ctx context.Context) ([]types.GameInspection, error) {
+	return a.games.Inspect(ctx)
+}
+
+// InspectGame returns the challenger's view of a single tracked game.
+func (a *ChallengerAPI) InspectGame(ctx context.Context, game common.Address) (types.GameInspection, error) {
+	inspections, err := a.games.Inspect(ctx)
+	if err != nil {
+		return types.GameInspection{}, err
+	}
+	for _, inspection := range inspections {
+		if inspection.Game == game {
+			return inspection, nil
+		}
+	}
+	return types.GameInspection{}, fmt.Errorf("%w: %v", ErrGameNotTracked, game)
+}
+
+// DeferredActions returns the non-urgent actions currently being deferred because the gas price is too high.
+func (a *ChallengerAPI) DeferredActions(_ context.Context) ([]sender.DeferredAction, error) {
+	return a.deferrals.Deferred(), nil
+}
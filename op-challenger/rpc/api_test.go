@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/sender"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBondReport(t *testing.T) {
+	expected := claims.BondReport{
+		Posted:         big.NewInt(1),
+		AtRisk:         big.NewInt(2),
+		Claimable:      big.NewInt(3),
+		Claimed:        big.NewInt(4),
+		Counterparties: map[common.Address]*claims.CounterpartyBonds{{0xaa}: {AtRisk: big.NewInt(5), Contested: big.NewInt(6)}},
+		Games:          []claims.GameBonds{},
+	}
+	client := setupClient(t, NewChallengerAPI(&stubReporter{report: expected}, &stubInspector{}, &stubDeferrals{}))
+	var actual claims.BondReport
+	require.NoError(t, client.CallContext(context.Background(), &actual, "challenger_bondReport"))
+	require.Equal(t, expected, actual)
+}
+
+func TestInspectGames(t *testing.T) {
+	expected := []types.GameInspection{
+		{
+			Game:     common.Address{0xaa},
+			Status:   types.GameStatusInProgress.String(),
+			Schedule: types.ScheduleInspection{Inflight: true, QueuePosition: 2, ScheduledBlock: 5, LastProcessedBlock: 4},
+			Player: &types.PlayerInspection{
+				Updated: time.Unix(1000, 0).UTC(),
+				Claims: []types.ClaimInspection{
+					{Index: 0, ParentIndex: -1, IndexAtDepth: big.NewInt(0), Value: common.Hash{0x01}, Assessment: types.ClaimDishonest},
+					{Index: 1, ParentIndex: 0, Depth: 1, IndexAtDepth: big.NewInt(0), Value: common.Hash{0x02}, Assessment: types.ClaimHonest},
+				},
+				Moves: []types.MoveInspection{{Type: "move", ParentIndex: 1, IsAttack: true, Value: common.Hash{0x03}, Error: "boom"}},
+				Error: "failed",
+			},
+		},
+		{
+			Game:     common.Address{0xbb},
+			Status:   types.GameStatusDefenderWon.String(),
+			Schedule: types.ScheduleInspection{LastProcessedBlock: 5},
+		},
+	}
+	client := setupClient(t, NewChallengerAPI(&stubReporter{}, &stubInspector{games: expected}, &stubDeferrals{}))
+
+	t.Run("AllGames", func(t *testing.T) {
+		var actual []types.GameInspection
+		require.NoError(t, client.CallContext(context.Background(), &actual, "challenger_inspectGames"))
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("SingleGame", func(t *testing.T) {
+		var actual types.GameInspection
+		require.NoError(t, client.CallContext(context.Background(), &actual, "challenger_inspectGame", common.Address{0xbb}))
+		require.Equal(t, expected[1], actual)
+	})
+
+	t.Run("UnknownGame", func(t *testing.T) {
+		var actual types.GameInspection
+		err := client.CallContext(context.Background(), &actual, "challenger_inspectGame", common.Address{0xcc})
+		require.ErrorContains(t, err, ErrGameNotTracked.Error())
+	})
+}
+
+func TestDeferredActions(t *testing.T) {
+	expected := []sender.DeferredAction{
+		{
+			DeferrableAction: sender.DeferrableAction{Type: sender.ActionResolveClaim, Game: common.Address{0xaa}, Detail: "3"},
+			DeferredSince:    time.Unix(1000, 0).UTC(),
+			LastChecked:      time.Unix(2000, 0).UTC(),
+			GasPrice:         big.NewInt(50_000_000_000),
+		},
+		{
+			DeferrableAction: sender.DeferrableAction{Type: sender.ActionResolveGame, Game: common.Address{0xbb}},
+			DeferredSince:    time.Unix(1500, 0).UTC(),
+			LastChecked:      time.Unix(2000, 0).UTC(),
+			GasPrice:         big.NewInt(50_000_000_000),
+		},
+	}
+	client := setupClient(t, NewChallengerAPI(&stubReporter{}, &stubInspector{}, &stubDeferrals{actions: expected}))
+	var actual []sender.DeferredAction
+	require.NoError(t, client.CallContext(context.Background(), &actual, "challenger_deferredActions"))
+	require.Equal(t, expected, actual)
+}
+
+func setupClient(t *testing.T, api *ChallengerAPI) *gethrpc.Client {
+	server := oprpc.NewServer("127.0.0.1", 0, "test", oprpc.WithLogger(testlog.Logger(t, log.LevelInfo)))
+	server.AddAPI(GetChallengerAPI(api))
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+	client, err := gethrpc.Dial("http://" + server.Endpoint())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+type stubReporter struct {
+	report claims.BondReport
+}
+
+func (s *stubReporter) Report() claims.BondReport {
+	return s.report
+}
+
+type stubInspector struct {
+	games []types.GameInspection
+}
+
+func (s *stubInspector) Inspect(_ context.Context) ([]types.GameInspection, error) {
+	return s.games, nil
+}
+
+type stubDeferrals struct {
+	actions []sender.DeferredAction
+}
+
+func (s *stubDeferrals) Deferred() []sender.DeferredAction {
+	return s.actions
+}
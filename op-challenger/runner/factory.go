@@ -35,7 +35,7 @@ func createTraceProvider(
 			return nil, err
 		}
 		prestateProvider := vm.NewPrestateProvider(prestate, stateConverter)
-		return cannon.NewTraceProvider(logger, m, cfg.Cannon, vmConfig, prestateProvider, prestate, localInputs, dir, 42), nil
+		return cannon.NewTraceProvider(logger, m, cfg.Cannon, vmConfig, nil, prestateProvider, prestate, localInputs, dir, 42), nil
 	case types.TraceTypeAsterisc:
 		vmConfig := vm.NewOpProgramServerExecutor()
 		stateConverter := asterisc.NewStateConverter()
@@ -44,7 +44,16 @@ func createTraceProvider(
 			return nil, err
 		}
 		prestateProvider := vm.NewPrestateProvider(prestate, stateConverter)
-		return asterisc.NewTraceProvider(logger, m, cfg.Asterisc, vmConfig, prestateProvider, prestate, localInputs, dir, 42), nil
+		return asterisc.NewTraceProvider(logger, m, cfg.Asterisc, vmConfig, nil, prestateProvider, prestate, localInputs, dir, 42), nil
+	case types.TraceTypeCannonKona:
+		vmConfig := vm.NewKonaExecutor()
+		stateConverter := cannon.NewStateConverter()
+		prestate, err := getPrestate(prestateHash, cfg.CannonKonaAbsolutePreStateBaseURL, cfg.CannonKonaAbsolutePreState, dir, stateConverter)
+		if err != nil {
+			return nil, err
+		}
+		prestateProvider := vm.NewPrestateProvider(prestate, stateConverter)
+		return cannon.NewTraceProvider(logger, m, cfg.CannonKona, vmConfig, nil, prestateProvider, prestate, localInputs, dir, 42), nil
 	case types.TraceTypeAsteriscKona:
 		vmConfig := vm.NewKonaExecutor()
 		stateConverter := asterisc.NewStateConverter()
@@ -53,7 +62,7 @@ func createTraceProvider(
 			return nil, err
 		}
 		prestateProvider := vm.NewPrestateProvider(prestate, stateConverter)
-		return asterisc.NewTraceProvider(logger, m, cfg.AsteriscKona, vmConfig, prestateProvider, prestate, localInputs, dir, 42), nil
+		return asterisc.NewTraceProvider(logger, m, cfg.AsteriscKona, vmConfig, nil, prestateProvider, prestate, localInputs, dir, 42), nil
 	}
 	return nil, errors.New("invalid trace type")
 }
@@ -77,7 +86,7 @@ func createMTTraceProvider(
 		return nil, fmt.Errorf("failed to get prestate %v: %w", prestateHash, err)
 	}
 	prestateProvider := vm.NewPrestateProvider(prestatePath, stateConverter)
-	return cannon.NewTraceProvider(logger, m, vmConfig, executor, prestateProvider, prestatePath, localInputs, dir, 42), nil
+	return cannon.NewTraceProvider(logger, m, vmConfig, executor, nil, prestateProvider, prestatePath, localInputs, dir, 42), nil
 }
 
 func getPrestate(prestateHash common.Hash, prestateBaseUrl *url.URL, prestatePath string, dataDir string, stateConverter vm.StateConverter) (string, error) {
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/resources"
+	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// VmWorker serves VM executions to challengers configured with remote workers.
+type VmWorker struct {
+	log        log.Logger
+	cfg        *config.Config
+	listenAddr string
+	listenPort int
+	m          *metrics.Metrics
+
+	running    atomic.Bool
+	cancel     context.CancelFunc
+	server     *httputil.HTTPServer
+	metricsSrv *httputil.HTTPServer
+}
+
+func NewVmWorker(logger log.Logger, cfg *config.Config, listenAddr string, listenPort int) *VmWorker {
+	return &VmWorker{
+		log:        logger,
+		cfg:        cfg,
+		listenAddr: listenAddr,
+		listenPort: listenPort,
+		m:          metrics.NewMetrics(),
+	}
+}
+
+func (w *VmWorker) Start(_ context.Context) error {
+	if !w.running.CompareAndSwap(false, true) {
+		return errors.New("already started")
+	}
+	// Jobs outlive the start context and are only cancelled when the worker stops.
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	if w.cfg.MetricsConfig.Enabled {
+		metricsSrv, err := opmetrics.StartServer(w.m.Registry(), w.cfg.MetricsConfig.ListenAddr, w.cfg.MetricsConfig.ListenPort)
+		if err != nil {
+			return fmt.Errorf("failed to start metrics server: %w", err)
+		}
+		w.log.Info("started metrics server", "addr", metricsSrv.Addr())
+		w.metricsSrv = metricsSrv
+	}
+
+	vms := make(map[types.TraceType]vm.WorkerVm)
+	for _, traceType := range w.cfg.TraceTypes {
+		switch traceType {
+		case types.TraceTypeCannon, types.TraceTypePermissioned, types.TraceTypeSuperCannon:
+			vms[w.cfg.Cannon.VmType] = w.workerVm(w.cfg.Cannon, vm.NewOpProgramServerExecutor())
+		case types.TraceTypeCannonKona:
+			vms[w.cfg.CannonKona.VmType] = w.workerVm(w.cfg.CannonKona, vm.NewKonaExecutor())
+		case types.TraceTypeAsterisc:
+			vms[w.cfg.Asterisc.VmType] = w.workerVm(w.cfg.Asterisc, vm.NewOpProgramServerExecutor())
+		case types.TraceTypeAsteriscKona:
+			vms[w.cfg.AsteriscKona.VmType] = w.workerVm(w.cfg.AsteriscKona, vm.NewKonaExecutor())
+		default:
+			w.log.Warn("Trace type does not use a VM", "type", traceType)
+		}
+	}
+	if len(vms) == 0 {
+		return errors.New("no vm trace types enabled")
+	}
+	worker := vm.NewWorker(ctx, w.log, w.cfg.Datadir, vms, resources.NewManager(w.m, clock.SystemClock, w.cfg.VmResources))
+	server, err := httputil.StartHTTPServer(net.JoinHostPort(w.listenAddr, strconv.Itoa(w.listenPort)), worker)
+	if err != nil {
+		return fmt.Errorf("failed to start vm worker server: %w", err)
+	}
+	w.server = server
+	w.log.Info("VM worker started", "addr", server.Addr())
+	return nil
+}
+
+func (w *VmWorker) workerVm(cfg vm.Config, oracleServer vm.OracleServerExecutor) vm.WorkerVm {
+	return vm.WorkerVm{
+		Config:       cfg,
+		OracleServer: oracleServer,
+		Metrics:      w.m.VmMetrics(cfg.VmType.String()),
+	}
+}
+
+func (w *VmWorker) Stop(ctx context.Context) error {
+	w.log.Info("Stopping")
+	if !w.running.CompareAndSwap(true, false) {
+		return errors.New("not started")
+	}
+	w.cancel()
+	var result error
+	if w.server != nil {
+		result = errors.Join(result, w.server.Stop(ctx))
+	}
+	if w.metricsSrv != nil {
+		result = errors.Join(result, w.metricsSrv.Stop(ctx))
+	}
+	return result
+}
+
+func (w *VmWorker) Stopped() bool {
+	return !w.running.Load()
+}
+
+var _ cliapp.Lifecycle = (*VmWorker)(nil)
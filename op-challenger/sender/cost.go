@@ -0,0 +1,54 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+type GasPricer interface {
+	SuggestGasPriceCaps(ctx context.Context) (tipCap *big.Int, baseFee *big.Int, blobBaseFee *big.Int, err error)
+}
+
+// GasCostEstimator estimates the cost of sending a transaction from the current gas price.
+// Transactions sent as part of a batch share the intrinsic transaction cost, so the estimate for an
+// individual transaction is an upper bound on its share of the batch cost.
+type GasCostEstimator struct {
+	estimator GasEstimator
+	pricer    GasPricer
+	from      common.Address
+}
+
+func NewGasCostEstimator(estimator GasEstimator, pricer GasPricer, from common.Address) *GasCostEstimator {
+	return &GasCostEstimator{
+		estimator: estimator,
+		pricer:    pricer,
+		from:      from,
+	}
+}
+
+func (e *GasCostEstimator) EstimateCost(ctx context.Context, tx txmgr.TxCandidate) (*big.Int, error) {
+	gas, err := e.estimator.EstimateGas(ctx, ethereum.CallMsg{
+		From:  e.from,
+		To:    tx.To,
+		Data:  tx.TxData,
+		Value: tx.Value,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	tipCap, baseFee, _, err := e.pricer.SuggestGasPriceCaps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	gasPrice := new(big.Int).Add(tipCap, baseFee)
+	return gasPrice.Mul(gasPrice, new(big.Int).SetUint64(gas)), nil
+}
@@ -0,0 +1,63 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasCostEstimator(t *testing.T) {
+	from := common.Address{0xaa}
+	to := common.Address{0xbb}
+	tx := txmgr.TxCandidate{To: &to, TxData: []byte{0x01}}
+
+	t.Run("Success", func(t *testing.T) {
+		gasEstimator := &stubGasEstimator{gas: 50_000}
+		estimator := NewGasCostEstimator(gasEstimator, &stubGasPricer{tipCap: big.NewInt(2), baseFee: big.NewInt(8)}, from)
+		cost, err := estimator.EstimateCost(context.Background(), tx)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(500_000), cost)
+		require.Equal(t, from, gasEstimator.msg.From)
+		require.Equal(t, &to, gasEstimator.msg.To)
+		require.Equal(t, tx.TxData, gasEstimator.msg.Data)
+	})
+
+	t.Run("EstimateGasFails", func(t *testing.T) {
+		err := errors.New("boom")
+		estimator := NewGasCostEstimator(&stubGasEstimator{err: err}, &stubGasPricer{tipCap: big.NewInt(2), baseFee: big.NewInt(8)}, from)
+		_, actual := estimator.EstimateCost(context.Background(), tx)
+		require.ErrorIs(t, actual, err)
+	})
+}
+
+type stubGasEstimator struct {
+	gas uint64
+	err error
+	msg ethereum.CallMsg
+}
+
+func (s *stubGasEstimator) EstimateGas(_ context.Context, msg ethereum.CallMsg) (uint64, error) {
+	s.msg = msg
+	return s.gas, s.err
+}
+
+type stubGasPricer struct {
+	tipCap  *big.Int
+	baseFee *big.Int
+	err     error
+	calls   int
+}
+
+func (s *stubGasPricer) SuggestGasPriceCaps(_ context.Context) (*big.Int, *big.Int, *big.Int, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, nil, nil, s.err
+	}
+	return s.tipCap, s.baseFee, big.NewInt(0), nil
+}
@@ -0,0 +1,159 @@
+package sender
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// gasPriceCacheDuration is how long a fetched gas price is reused for, roughly one L1 block.
+	gasPriceCacheDuration = 12 * time.Second
+	// staleDeferralAge is how long a deferred action is kept without being checked again before it is assumed to be
+	// no longer required, for example because another party already sent it.
+	staleDeferralAge = time.Hour
+)
+
+type ActionType string
+
+const (
+	ActionResolveClaim ActionType = "resolve_claim"
+	ActionResolveGame  ActionType = "resolve_game"
+	ActionClaimCredit  ActionType = "claim_credit"
+)
+
+// DeferrableAction identifies a non-urgent action that may be delayed while gas prices are high.
+type DeferrableAction struct {
+	Type ActionType     `json:"type"`
+	Game common.Address `json:"game"`
+	// Detail distinguishes actions of the same type in a game, such as the claim index or credit recipient.
+	Detail string `json:"detail,omitempty"`
+}
+
+// DeferredAction is an action currently being delayed because the gas price exceeds the configured maximum.
+type DeferredAction struct {
+	DeferrableAction
+	DeferredSince time.Time `json:"deferredSince"`
+	LastChecked   time.Time `json:"lastChecked"`
+	GasPrice      *big.Int  `json:"gasPrice"`
+}
+
+// DeferralQueue delays non-urgent actions, such as resolutions and credit claims, while the gas price exceeds a
+// maximum. Actions are sent once the gas price drops or they have been deferred for longer than the max deferral,
+// so they are never delayed indefinitely.
+type DeferralQueue struct {
+	logger      log.Logger
+	clock       clock.Clock
+	pricer      GasPricer
+	maxGasPrice *big.Int
+	maxDeferral time.Duration
+
+	lock          sync.Mutex
+	deferred      map[DeferrableAction]*DeferredAction
+	gasPrice      *big.Int
+	gasPriceFetch time.Time
+}
+
+// NewDeferralQueue creates a new DeferralQueue. Actions are never deferred if maxGasPrice is nil.
+func NewDeferralQueue(logger log.Logger, cl clock.Clock, pricer GasPricer, maxGasPrice *big.Int, maxDeferral time.Duration) *DeferralQueue {
+	return &DeferralQueue{
+		logger:      logger,
+		clock:       cl,
+		pricer:      pricer,
+		maxGasPrice: maxGasPrice,
+		maxDeferral: maxDeferral,
+		deferred:    make(map[DeferrableAction]*DeferredAction),
+	}
+}
+
+// ShouldDefer returns true if the action should not be sent yet because the gas price is too high.
+// The action is added to the queue until it is sent, so it must be checked again each time it would be sent.
+func (q *DeferralQueue) ShouldDefer(ctx context.Context, action DeferrableAction) bool {
+	if q.maxGasPrice == nil {
+		return false
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	now := q.clock.Now()
+	gasPrice, err := q.currentGasPrice(ctx, now)
+	if err != nil {
+		// Prefer sending unnecessarily expensive transactions to never sending them.
+		q.logger.Warn("Failed to fetch gas price, not deferring action", "action", action.Type, "game", action.Game, "err", err)
+		delete(q.deferred, action)
+		return false
+	}
+	entry, ok := q.deferred[action]
+	if gasPrice.Cmp(q.maxGasPrice) <= 0 {
+		if ok {
+			q.logger.Info("Sending deferred action as gas price dropped", "action", action.Type, "game", action.Game,
+				"detail", action.Detail, "gasPrice", gasPrice, "deferred", now.Sub(entry.DeferredSince))
+			delete(q.deferred, action)
+		}
+		return false
+	}
+	if !ok {
+		entry = &DeferredAction{DeferrableAction: action, DeferredSince: now}
+		q.deferred[action] = entry
+		q.logger.Info("Deferring action until gas price drops", "action", action.Type, "game", action.Game,
+			"detail", action.Detail, "gasPrice", gasPrice, "maxGasPrice", q.maxGasPrice)
+	}
+	if deferredFor := now.Sub(entry.DeferredSince); deferredFor >= q.maxDeferral {
+		q.logger.Warn("Sending deferred action despite high gas price", "action", action.Type, "game", action.Game,
+			"detail", action.Detail, "gasPrice", gasPrice, "maxGasPrice", q.maxGasPrice, "deferred", deferredFor)
+		delete(q.deferred, action)
+		return false
+	}
+	entry.LastChecked = now
+	entry.GasPrice = gasPrice
+	return true
+}
+
+// Deferred returns the actions currently being deferred, oldest first.
+func (q *DeferralQueue) Deferred() []DeferredAction {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	now := q.clock.Now()
+	actions := make([]DeferredAction, 0, len(q.deferred))
+	for key, entry := range q.deferred {
+		if now.Sub(entry.LastChecked) > staleDeferralAge {
+			delete(q.deferred, key)
+			continue
+		}
+		actions = append(actions, *entry)
+	}
+	slices.SortFunc(actions, func(a, b DeferredAction) int {
+		if c := a.DeferredSince.Compare(b.DeferredSince); c != 0 {
+			return c
+		}
+		if c := bytes.Compare(a.Game[:], b.Game[:]); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Detail, b.Detail)
+	})
+	return actions
+}
+
+// currentGasPrice returns the gas price a transaction would currently pay. Must be called with the lock held.
+func (q *DeferralQueue) currentGasPrice(ctx context.Context, now time.Time) (*big.Int, error) {
+	if q.gasPrice != nil && now.Sub(q.gasPriceFetch) < gasPriceCacheDuration {
+		return q.gasPrice, nil
+	}
+	tipCap, baseFee, _, err := q.pricer.SuggestGasPriceCaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.gasPrice = new(big.Int).Add(tipCap, baseFee)
+	q.gasPriceFetch = now
+	return q.gasPrice, nil
+}
@@ -0,0 +1,112 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferralQueue_ShouldDefer(t *testing.T) {
+	action := DeferrableAction{Type: ActionResolveGame, Game: common.Address{0xaa}}
+
+	setup := func(t *testing.T, maxGasPrice *big.Int) (*DeferralQueue, *stubGasPricer, *clock.DeterministicClock) {
+		cl := clock.NewDeterministicClock(time.Unix(10_000, 0))
+		pricer := &stubGasPricer{tipCap: big.NewInt(2), baseFee: big.NewInt(8)}
+		queue := NewDeferralQueue(testlog.Logger(t, log.LevelInfo), cl, pricer, maxGasPrice, 24*time.Hour)
+		return queue, pricer, cl
+	}
+
+	t.Run("DisabledWithoutMaxGasPrice", func(t *testing.T) {
+		queue, pricer, _ := setup(t, nil)
+		require.False(t, queue.ShouldDefer(context.Background(), action))
+		require.Zero(t, pricer.calls)
+		require.Empty(t, queue.Deferred())
+	})
+
+	t.Run("ProceedWhenGasPriceAtMax", func(t *testing.T) {
+		queue, _, _ := setup(t, big.NewInt(10))
+		require.False(t, queue.ShouldDefer(context.Background(), action))
+		require.Empty(t, queue.Deferred())
+	})
+
+	t.Run("DeferWhenGasPriceAboveMax", func(t *testing.T) {
+		queue, _, cl := setup(t, big.NewInt(9))
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		require.Equal(t, []DeferredAction{{
+			DeferrableAction: action,
+			DeferredSince:    cl.Now(),
+			LastChecked:      cl.Now(),
+			GasPrice:         big.NewInt(10),
+		}}, queue.Deferred())
+	})
+
+	t.Run("ProceedOnceGasPriceDrops", func(t *testing.T) {
+		queue, pricer, cl := setup(t, big.NewInt(9))
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		pricer.baseFee = big.NewInt(5)
+		cl.AdvanceTime(gasPriceCacheDuration)
+		require.False(t, queue.ShouldDefer(context.Background(), action))
+		require.Empty(t, queue.Deferred())
+	})
+
+	t.Run("ProceedAfterMaxDeferral", func(t *testing.T) {
+		queue, _, cl := setup(t, big.NewInt(9))
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		cl.AdvanceTime(24*time.Hour - time.Second)
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		cl.AdvanceTime(time.Second)
+		require.False(t, queue.ShouldDefer(context.Background(), action))
+		require.Empty(t, queue.Deferred())
+	})
+
+	t.Run("CacheGasPrice", func(t *testing.T) {
+		queue, pricer, cl := setup(t, big.NewInt(9))
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		require.True(t, queue.ShouldDefer(context.Background(), DeferrableAction{Type: ActionClaimCredit, Game: common.Address{0xbb}}))
+		require.Equal(t, 1, pricer.calls)
+		cl.AdvanceTime(gasPriceCacheDuration)
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		require.Equal(t, 2, pricer.calls)
+	})
+
+	t.Run("ProceedWhenGasPriceUnavailable", func(t *testing.T) {
+		queue, pricer, cl := setup(t, big.NewInt(9))
+		require.True(t, queue.ShouldDefer(context.Background(), action))
+		pricer.err = errors.New("boom")
+		cl.AdvanceTime(gasPriceCacheDuration)
+		require.False(t, queue.ShouldDefer(context.Background(), action))
+		require.Empty(t, queue.Deferred())
+	})
+}
+
+func TestDeferralQueue_Deferred(t *testing.T) {
+	cl := clock.NewDeterministicClock(time.Unix(10_000, 0))
+	pricer := &stubGasPricer{tipCap: big.NewInt(2), baseFee: big.NewInt(8)}
+	queue := NewDeferralQueue(testlog.Logger(t, log.LevelInfo), cl, pricer, big.NewInt(1), 24*time.Hour)
+
+	stale := DeferrableAction{Type: ActionResolveGame, Game: common.Address{0xaa}}
+	claim1 := DeferrableAction{Type: ActionResolveClaim, Game: common.Address{0xbb}, Detail: "1"}
+	claim0 := DeferrableAction{Type: ActionResolveClaim, Game: common.Address{0xbb}, Detail: "0"}
+	credit := DeferrableAction{Type: ActionClaimCredit, Game: common.Address{0xcc}}
+	require.True(t, queue.ShouldDefer(context.Background(), stale))
+	cl.AdvanceTime(time.Minute)
+	require.True(t, queue.ShouldDefer(context.Background(), credit))
+	require.True(t, queue.ShouldDefer(context.Background(), claim1))
+	require.True(t, queue.ShouldDefer(context.Background(), claim0))
+	cl.AdvanceTime(staleDeferralAge)
+
+	deferred := queue.Deferred()
+	actions := make([]DeferrableAction, 0, len(deferred))
+	for _, action := range deferred {
+		actions = append(actions, action.DeferrableAction)
+	}
+	require.Equal(t, []DeferrableAction{claim0, claim1, credit}, actions)
+}
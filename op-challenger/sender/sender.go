@@ -13,11 +13,19 @@ import (
 
 var ErrTransactionReverted = errors.New("transaction published but reverted")
 
+// Batcher combines multiple transactions into a single transaction.
+type Batcher interface {
+	AggregateTx(txs ...txmgr.TxCandidate) (txmgr.TxCandidate, error)
+}
+
 type TxSender struct {
 	log log.Logger
 
 	txMgr txmgr.TxManager
 	queue *txmgr.Queue[int]
+
+	batcher      Batcher
+	maxBatchSize int
 }
 
 func NewTxSender(ctx context.Context, logger log.Logger, txMgr txmgr.TxManager, maxPending uint64) *TxSender {
@@ -29,6 +37,13 @@ func NewTxSender(ctx context.Context, logger log.Logger, txMgr txmgr.TxManager,
 	}
 }
 
+// EnableBatching configures SendAndWaitBatched to combine transactions using the batcher,
+// with at most maxBatchSize transactions in each combined transaction.
+func (s *TxSender) EnableBatching(batcher Batcher, maxBatchSize int) {
+	s.batcher = batcher
+	s.maxBatchSize = maxBatchSize
+}
+
 func (s *TxSender) From() common.Address {
 	return s.txMgr.From()
 }
@@ -60,3 +75,39 @@ func (s *TxSender) SendAndWaitSimple(txPurpose string, txs ...txmgr.TxCandidate)
 	errs := s.SendAndWaitDetailed(txPurpose, txs...)
 	return errors.Join(errs...)
 }
+
+// SendAndWaitBatched sends transactions that don't depend on msg.sender, combining them into batches if batching
+// is enabled. Transactions are executed in order. If any transaction in a batch fails, the entire batch fails.
+// Returns the error for each supplied transaction, which is the error of the batch it was included in.
+func (s *TxSender) SendAndWaitBatched(txPurpose string, txs ...txmgr.TxCandidate) []error {
+	if s.batcher == nil || len(txs) <= 1 {
+		return s.SendAndWaitDetailed(txPurpose, txs...)
+	}
+	batchSize := s.maxBatchSize
+	if batchSize <= 0 {
+		batchSize = len(txs)
+	}
+	errs := make([]error, len(txs))
+	var batches []txmgr.TxCandidate
+	var batchStarts []int
+	for start := 0; start < len(txs); start += batchSize {
+		end := min(start+batchSize, len(txs))
+		batch, err := s.batcher.AggregateTx(txs[start:end]...)
+		if err != nil {
+			for i := start; i < end; i++ {
+				errs[i] = fmt.Errorf("failed to create batch: %w", err)
+			}
+			continue
+		}
+		batches = append(batches, batch)
+		batchStarts = append(batchStarts, start)
+	}
+	s.log.Debug("Sending batched transactions", "purpose", txPurpose, "txs", len(txs), "batches", len(batches))
+	for i, err := range s.SendAndWaitDetailed(txPurpose, batches...) {
+		end := min(batchStarts[i]+batchSize, len(txs))
+		for j := batchStarts[i]; j < end; j++ {
+			errs[j] = err
+		}
+	}
+	return errs
+}
@@ -114,6 +114,60 @@ func TestSendAndWaitReturnIndividualErrors(t *testing.T) {
 	require.NoError(t, errs[2])
 }
 
+func TestSendAndWaitBatched(t *testing.T) {
+	tx := func(i byte) txmgr.TxCandidate {
+		return txmgr.TxCandidate{TxData: []byte{i}}
+	}
+	setup := func(t *testing.T) (*TxSender, *stubTxMgr) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		t.Cleanup(cancel)
+		txMgr := &stubTxMgr{
+			sending:    make(map[byte]chan *types.Receipt),
+			syncStatus: map[byte]uint64{0: types.ReceiptStatusSuccessful, 2: types.ReceiptStatusSuccessful, 4: types.ReceiptStatusSuccessful},
+		}
+		return NewTxSender(ctx, testlog.Logger(t, log.LevelInfo), txMgr, 500), txMgr
+	}
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		sender, txMgr := setup(t)
+		txMgr.syncStatus[1] = types.ReceiptStatusSuccessful
+		require.Equal(t, []error{nil, nil}, sender.SendAndWaitBatched("testing", tx(0), tx(1)))
+	})
+
+	t.Run("SplitIntoBatches", func(t *testing.T) {
+		sender, _ := setup(t)
+		batcher := &stubBatcher{}
+		sender.EnableBatching(batcher, 2)
+		require.Equal(t, make([]error, 5), sender.SendAndWaitBatched("testing", tx(0), tx(1), tx(2), tx(3), tx(4)))
+		require.Equal(t, [][]byte{{0, 1}, {2, 3}, {4}}, batcher.batches)
+	})
+
+	t.Run("BatchFails", func(t *testing.T) {
+		sender, txMgr := setup(t)
+		txMgr.syncStatus[0] = types.ReceiptStatusFailed
+		sender.EnableBatching(&stubBatcher{}, 2)
+		errs := sender.SendAndWaitBatched("testing", tx(0), tx(1), tx(2))
+		require.Len(t, errs, 3)
+		require.ErrorIs(t, errs[0], ErrTransactionReverted)
+		require.ErrorIs(t, errs[1], ErrTransactionReverted)
+		require.NoError(t, errs[2])
+	})
+}
+
+// stubBatcher combines transactions by concatenating their data.
+type stubBatcher struct {
+	batches [][]byte
+}
+
+func (s *stubBatcher) AggregateTx(txs ...txmgr.TxCandidate) (txmgr.TxCandidate, error) {
+	var data []byte
+	for _, tx := range txs {
+		data = append(data, tx.TxData...)
+	}
+	s.batches = append(s.batches, data)
+	return txmgr.TxCandidate{TxData: data}, nil
+}
+
 type stubTxMgr struct {
 	m          sync.Mutex
 	sending    map[byte]chan *types.Receipt
@@ -291,6 +291,64 @@ func (_c *SequencerControl_StopSequencer_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// SyncStatus provides a mock function with given fields: ctx
+func (_m *SequencerControl) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncStatus")
+	}
+
+	var r0 *eth.SyncStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*eth.SyncStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *eth.SyncStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*eth.SyncStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SequencerControl_SyncStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SyncStatus'
+type SequencerControl_SyncStatus_Call struct {
+	*mock.Call
+}
+
+// SyncStatus is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *SequencerControl_Expecter) SyncStatus(ctx interface{}) *SequencerControl_SyncStatus_Call {
+	return &SequencerControl_SyncStatus_Call{Call: _e.mock.On("SyncStatus", ctx)}
+}
+
+func (_c *SequencerControl_SyncStatus_Call) Run(run func(ctx context.Context)) *SequencerControl_SyncStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *SequencerControl_SyncStatus_Call) Return(_a0 *eth.SyncStatus, _a1 error) *SequencerControl_SyncStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SequencerControl_SyncStatus_Call) RunAndReturn(run func(context.Context) (*eth.SyncStatus, error)) *SequencerControl_SyncStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewSequencerControl creates a new instance of SequencerControl. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSequencerControl(t interface {
@@ -18,6 +18,7 @@ type SequencerControl interface {
 	SequencerActive(ctx context.Context) (bool, error)
 	LatestUnsafeBlock(ctx context.Context) (eth.BlockInfo, error)
 	PostUnsafePayload(ctx context.Context, payload *eth.ExecutionPayloadEnvelope) error
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
 }
 
 // NewSequencerControl creates a new SequencerControl instance.
@@ -59,3 +60,8 @@ func (s *sequencerController) SequencerActive(ctx context.Context) (bool, error)
 func (s *sequencerController) PostUnsafePayload(ctx context.Context, payload *eth.ExecutionPayloadEnvelope) error {
 	return s.node.PostUnsafePayload(ctx, payload)
 }
+
+// SyncStatus implements SequencerControl.
+func (s *sequencerController) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return s.node.SyncStatus(ctx)
+}
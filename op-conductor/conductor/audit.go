@@ -0,0 +1,104 @@
+package conductor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	conductorrpc "github.com/ethereum-optimism/optimism/op-conductor/rpc"
+)
+
+// Actions recorded in the audit trail.
+const (
+	AuditActionPause  = "pause"
+	AuditActionResume = "resume"
+)
+
+var ErrAuditInfoRequired = errors.New("operator and reason are required")
+
+// auditTrail records who paused or resumed the conductor and why.
+// If a path is set, the entries are appended to the file as JSON lines, and loaded from it on startup.
+type auditTrail struct {
+	mtx     sync.Mutex
+	path    string
+	entries []conductorrpc.AuditEntry
+}
+
+func newAuditTrail(path string) (*auditTrail, error) {
+	a := &auditTrail{path: path}
+	if path == "" {
+		return a, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry conductorrpc.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode audit log entry %d: %w", len(a.entries), err)
+		}
+		a.entries = append(a.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return a, nil
+}
+
+// add adds the entry to the in-memory trail and persists it.
+// The entry is kept in memory even if it cannot be persisted, as it records a change that has already been made.
+func (a *auditTrail) add(entry conductorrpc.AuditEntry) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.entries = append(a.entries, entry)
+	if a.path == "" {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	return nil
+}
+
+// last returns the latest entry, or nil if the trail is empty.
+func (a *auditTrail) last() *conductorrpc.AuditEntry {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if len(a.entries) == 0 {
+		return nil
+	}
+	entry := a.entries[len(a.entries)-1]
+	return &entry
+}
+
+// list returns all entries, oldest first.
+func (a *auditTrail) list() []conductorrpc.AuditEntry {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return slices.Clone(a.entries)
+}
@@ -0,0 +1,43 @@
+package conductor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	conductorrpc "github.com/ethereum-optimism/optimism/op-conductor/rpc"
+)
+
+func TestAuditTrailPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := newAuditTrail(path)
+	require.NoError(t, err)
+	require.Nil(t, audit.last())
+	require.Empty(t, audit.list())
+
+	pause := conductorrpc.AuditEntry{Time: time.Unix(100, 0).UTC(), Action: AuditActionPause, Operator: "alice", Reason: "maintenance"}
+	resume := conductorrpc.AuditEntry{Time: time.Unix(200, 0).UTC(), Action: AuditActionResume, Operator: "bob", Reason: "maintenance done"}
+	require.NoError(t, audit.add(pause))
+	require.NoError(t, audit.add(resume))
+	require.Equal(t, &resume, audit.last())
+
+	// the trail is loaded again on restart
+	reloaded, err := newAuditTrail(path)
+	require.NoError(t, err)
+	require.Equal(t, []conductorrpc.AuditEntry{pause, resume}, reloaded.list())
+
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o644))
+	_, err = newAuditTrail(path)
+	require.ErrorContains(t, err, "failed to decode audit log entry 0")
+}
+
+func TestAuditTrailInMemory(t *testing.T) {
+	audit, err := newAuditTrail("")
+	require.NoError(t, err)
+	entry := conductorrpc.AuditEntry{Action: AuditActionPause, Operator: "alice", Reason: "maintenance"}
+	require.NoError(t, audit.add(entry))
+	require.Equal(t, []conductorrpc.AuditEntry{entry}, audit.list())
+}
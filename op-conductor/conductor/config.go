@@ -3,6 +3,7 @@ package conductor
 import (
 	"fmt"
 	"math"
+	"path/filepath"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -18,6 +19,12 @@ import (
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 )
 
+const (
+	ConsensusBackendRaft   = "raft"
+	ConsensusBackendEtcd   = "etcd"
+	ConsensusBackendConsul = "consul"
+)
+
 type Config struct {
 	// ConsensusAddr is the address to listen for consensus connections.
 	ConsensusAddr string
@@ -25,6 +32,19 @@ type Config struct {
 	// ConsensusPort is the port to listen for consensus connections.
 	ConsensusPort int
 
+	// ConsensusBackend is the backend used for leader election and state storage.
+	ConsensusBackend string
+
+	// ConsensusEndpoint is the HTTP endpoint of the external coordination service of the etcd and consul backends.
+	ConsensusEndpoint string
+
+	// ConsensusKeyPrefix is the prefix of the keys the cluster state is stored at by the etcd and consul backends.
+	ConsensusKeyPrefix string
+
+	// ConsensusSessionTTL is the time after which the leadership of an unresponsive leader expires with the etcd
+	// and consul backends.
+	ConsensusSessionTTL time.Duration
+
 	// RaftServerID is the unique ID for this server used by raft consensus.
 	RaftServerID string
 
@@ -49,9 +69,30 @@ type Config struct {
 	// ExecutionRPC is the HTTP provider URL for execution layer.
 	ExecutionRPC string
 
+	// SupervisorRPC is the optional HTTP provider URL for op-supervisor.
+	SupervisorRPC string
+
 	// Paused is true if the conductor should start in a paused state.
 	Paused bool
 
+	// AuditLogPath is the file to persist the audit trail of pauses and resumes to, if not empty.
+	AuditLogPath string
+
+	// HandoverTimeout is the time after which a healthy leader failing to start sequencing transfers leadership
+	// back to another server. Zero disables the recovery.
+	HandoverTimeout time.Duration
+
+	// WarmupInterval is the interval at which a standby sequencer is fed the latest unsafe head in consensus,
+	// and checked to be synced. Zero disables the warm-up.
+	WarmupInterval time.Duration
+
+	// WarmupMaxLag is the maximum number of blocks a standby sequencer may be behind consensus to be considered warm.
+	WarmupMaxLag uint64
+
+	// Observer is true if the conductor only observes the cluster, without participating in leader election
+	// or controlling the sequencer.
+	Observer bool
+
 	// HealthCheck is the health check configuration.
 	HealthCheck HealthCheckConfig
 
@@ -78,8 +119,23 @@ func (c *Config) Check() error {
 	if c.RaftServerID == "" {
 		return fmt.Errorf("missing raft server ID")
 	}
-	if c.RaftStorageDir == "" {
-		return fmt.Errorf("missing raft storage directory")
+	switch c.ConsensusBackend {
+	case "", ConsensusBackendRaft:
+		if c.RaftStorageDir == "" {
+			return fmt.Errorf("missing raft storage directory")
+		}
+	case ConsensusBackendEtcd, ConsensusBackendConsul:
+		if c.ConsensusEndpoint == "" {
+			return fmt.Errorf("missing consensus endpoint for %v backend", c.ConsensusBackend)
+		}
+		if c.ConsensusSessionTTL < time.Second {
+			return fmt.Errorf("consensus session ttl must be at least 1s")
+		}
+	default:
+		return fmt.Errorf("unknown consensus backend: %q", c.ConsensusBackend)
+	}
+	if c.Observer && c.RaftBootstrap {
+		return fmt.Errorf("observer cannot bootstrap the cluster")
 	}
 	if c.NodeRPC == "" {
 		return fmt.Errorf("missing node RPC")
@@ -116,24 +172,42 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*Config, error) {
 		return nil, errors.Wrap(err, "failed to load rollup config")
 	}
 
+	consensusBackend := ctx.String(flags.ConsensusBackend.Name)
+	raftStorageDir := ctx.String(flags.RaftStorageDir.Name)
+	auditLogPath := ctx.String(flags.AuditLogPath.Name)
+	if auditLogPath == "" && raftStorageDir != "" && (consensusBackend == "" || consensusBackend == ConsensusBackendRaft) {
+		auditLogPath = filepath.Join(raftStorageDir, "audit.jsonl")
+	}
+
 	return &Config{
 		ConsensusAddr:         ctx.String(flags.ConsensusAddr.Name),
 		ConsensusPort:         ctx.Int(flags.ConsensusPort.Name),
+		ConsensusBackend:      consensusBackend,
+		ConsensusEndpoint:     ctx.String(flags.ConsensusEndpoint.Name),
+		ConsensusKeyPrefix:    ctx.String(flags.ConsensusKeyPrefix.Name),
+		ConsensusSessionTTL:   ctx.Duration(flags.ConsensusSessionTTL.Name),
 		RaftBootstrap:         ctx.Bool(flags.RaftBootstrap.Name),
 		RaftServerID:          ctx.String(flags.RaftServerID.Name),
-		RaftStorageDir:        ctx.String(flags.RaftStorageDir.Name),
+		RaftStorageDir:        raftStorageDir,
 		RaftSnapshotInterval:  ctx.Duration(flags.RaftSnapshotInterval.Name),
 		RaftSnapshotThreshold: ctx.Uint64(flags.RaftSnapshotThreshold.Name),
 		RaftTrailingLogs:      ctx.Uint64(flags.RaftTrailingLogs.Name),
 		NodeRPC:               ctx.String(flags.NodeRPC.Name),
 		ExecutionRPC:          ctx.String(flags.ExecutionRPC.Name),
+		SupervisorRPC:         ctx.String(flags.SupervisorRPC.Name),
 		Paused:                ctx.Bool(flags.Paused.Name),
+		Observer:              ctx.Bool(flags.Observer.Name),
+		AuditLogPath:          auditLogPath,
+		HandoverTimeout:       ctx.Duration(flags.HandoverTimeout.Name),
+		WarmupInterval:        ctx.Duration(flags.WarmupInterval.Name),
+		WarmupMaxLag:          ctx.Uint64(flags.WarmupMaxLag.Name),
 		HealthCheck: HealthCheckConfig{
-			Interval:       ctx.Uint64(flags.HealthCheckInterval.Name),
-			UnsafeInterval: ctx.Uint64(flags.HealthCheckUnsafeInterval.Name),
-			SafeEnabled:    ctx.Bool(flags.HealthCheckSafeEnabled.Name),
-			SafeInterval:   ctx.Uint64(flags.HealthCheckSafeInterval.Name),
-			MinPeerCount:   ctx.Uint64(flags.HealthCheckMinPeerCount.Name),
+			Interval:        ctx.Uint64(flags.HealthCheckInterval.Name),
+			UnsafeInterval:  ctx.Uint64(flags.HealthCheckUnsafeInterval.Name),
+			SafeEnabled:     ctx.Bool(flags.HealthCheckSafeEnabled.Name),
+			SafeInterval:    ctx.Uint64(flags.HealthCheckSafeInterval.Name),
+			MinPeerCount:    ctx.Uint64(flags.HealthCheckMinPeerCount.Name),
+			MaxCrossSafeLag: ctx.Uint64(flags.HealthCheckMaxCrossSafeLag.Name),
 		},
 		RollupCfg:      *rollupCfg,
 		RPCEnableProxy: ctx.Bool(flags.RPCEnableProxy.Name),
@@ -160,6 +234,10 @@ type HealthCheckConfig struct {
 
 	// MinPeerCount is the minimum number of peers required for the sequencer to be healthy.
 	MinPeerCount uint64
+
+	// MaxCrossSafeLag is the maximum lag of the cross-safe head behind the local-safe head in seconds,
+	// once interop is active. Zero disables the check.
+	MaxCrossSafeLag uint64
 }
 
 func (c *HealthCheckConfig) Check() error {
@@ -0,0 +1,50 @@
+package conductor
+
+import (
+	"sync"
+
+	conductorrpc "github.com/ethereum-optimism/optimism/op-conductor/rpc"
+)
+
+// maxEvents is the number of most recent control events kept in the event log.
+const maxEvents = 256
+
+// Kinds of control events.
+const (
+	EventLeadershipChange = "leadership_change"
+	EventHealthChange     = "health_change"
+	EventAction           = "action"
+)
+
+// eventLog is a bounded in-memory log of control events, for post-incident analysis.
+type eventLog struct {
+	mtx    sync.Mutex
+	events []conductorrpc.ControlEvent
+	next   int
+	full   bool
+}
+
+func newEventLog(size int) *eventLog {
+	return &eventLog{events: make([]conductorrpc.ControlEvent, size)}
+}
+
+// add appends the event, overwriting the oldest event once the log is full.
+func (l *eventLog) add(event conductorrpc.ControlEvent) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.events[l.next] = event
+	l.next = (l.next + 1) % len(l.events)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// list returns the events, oldest first.
+func (l *eventLog) list() []conductorrpc.ControlEvent {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if !l.full {
+		return append([]conductorrpc.ControlEvent{}, l.events[:l.next]...)
+	}
+	return append(append([]conductorrpc.ControlEvent{}, l.events[l.next:]...), l.events[:l.next]...)
+}
@@ -0,0 +1,137 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ethereum-optimism/optimism/op-conductor/consensus"
+	conductorrpc "github.com/ethereum-optimism/optimism/op-conductor/rpc"
+)
+
+// memberHealthTimeout bounds the health check of a single cluster member.
+const memberHealthTimeout = 5 * time.Second
+
+// ClusterHealth returns the health of the cluster members, checked through their op-conductor RPC endpoints.
+func (oc *OpConductor) ClusterHealth(ctx context.Context, rpcs map[string]string) ([]conductorrpc.MemberHealth, error) {
+	membership, err := oc.cons.ClusterMembership()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster membership")
+	}
+	return oc.membersHealth(ctx, membership.Servers, rpcs), nil
+}
+
+// ChangeMembership adds, removes or replaces a cluster member. The change is checked against the membership it
+// results in: a new voter must be healthy, and a majority of the voters must be healthy. When replacing a member,
+// the new member is added before the old one is removed, so that the cluster never runs short of voters.
+func (oc *OpConductor) ChangeMembership(ctx context.Context, change conductorrpc.MembershipChange) (*consensus.ClusterMembership, error) {
+	if change.Add == nil && change.Remove == "" {
+		return nil, errors.New("no membership change requested")
+	}
+	membership, err := oc.cons.ClusterMembership()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster membership")
+	}
+	if change.Version != 0 && change.Version != membership.Version {
+		return nil, fmt.Errorf("cluster membership version %d does not match expected version %d", membership.Version, change.Version)
+	}
+
+	servers := slices.Clone(membership.Servers)
+	if change.Remove != "" {
+		idx := slices.IndexFunc(servers, func(s consensus.ServerInfo) bool { return s.ID == change.Remove })
+		if idx < 0 {
+			return nil, fmt.Errorf("server %v is not a member of the cluster", change.Remove)
+		}
+		servers = slices.Delete(servers, idx, idx+1)
+	}
+	if change.Add != nil {
+		if change.Add.ID == "" || change.Add.Addr == "" {
+			return nil, errors.New("server to add requires an id and an address")
+		}
+		if slices.ContainsFunc(servers, func(s consensus.ServerInfo) bool { return s.ID == change.Add.ID }) {
+			return nil, fmt.Errorf("server %v is already a member of the cluster", change.Add.ID)
+		}
+		servers = append(servers, *change.Add)
+	}
+
+	health := oc.membersHealth(ctx, servers, change.RPCs)
+	var voters, healthy int
+	for _, h := range health {
+		if h.Suffrage != consensus.Voter {
+			continue
+		}
+		voters++
+		if h.Healthy {
+			healthy++
+		}
+	}
+	if change.Add != nil && change.Add.Suffrage == consensus.Voter {
+		if h := health[len(health)-1]; !h.Healthy {
+			return nil, fmt.Errorf("new voter %v is not healthy: %v", h.ID, h.Error)
+		}
+	}
+	if healthy < voters/2+1 {
+		return nil, fmt.Errorf("%w: %d of %d voters healthy", ErrQuorumUnsafe, healthy, voters)
+	}
+
+	version := membership.Version
+	if change.Add != nil {
+		add := oc.cons.AddVoter
+		if change.Add.Suffrage == consensus.Nonvoter {
+			add = oc.cons.AddNonVoter
+		}
+		if err := add(change.Add.ID, change.Add.Addr, version); err != nil {
+			return nil, errors.Wrapf(err, "failed to add server %v", change.Add.ID)
+		}
+		oc.log.Info("added server to the cluster", "server", change.Add.ID, "addr", change.Add.Addr, "suffrage", change.Add.Suffrage)
+		if change.Remove != "" {
+			updated, err := oc.cons.ClusterMembership()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get cluster membership")
+			}
+			version = updated.Version
+		}
+	}
+	if change.Remove != "" {
+		if err := oc.cons.RemoveServer(change.Remove, version); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove server %v", change.Remove)
+		}
+		oc.log.Info("removed server from the cluster", "server", change.Remove)
+	}
+	return oc.cons.ClusterMembership()
+}
+
+// membersHealth checks the health of the given servers. This server reports its own health,
+// the other ones are checked through their op-conductor RPC endpoint.
+func (oc *OpConductor) membersHealth(ctx context.Context, servers []consensus.ServerInfo, rpcs map[string]string) []conductorrpc.MemberHealth {
+	leader := oc.cons.LeaderWithID()
+	health := make([]conductorrpc.MemberHealth, 0, len(servers))
+	for _, s := range servers {
+		h := conductorrpc.MemberHealth{ServerInfo: s, Leader: leader != nil && leader.ID == s.ID}
+		if s.ID == oc.cfg.RaftServerID {
+			h.Healthy = oc.healthy.Load()
+		} else if endpoint, ok := rpcs[s.ID]; !ok {
+			h.Error = "no rpc endpoint"
+		} else if healthy, err := oc.memberHealthy(ctx, endpoint); err != nil {
+			h.Error = err.Error()
+		} else {
+			h.Healthy = healthy
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+func (oc *OpConductor) memberHealthy(ctx context.Context, endpoint string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, memberHealthTimeout)
+	defer cancel()
+	target, err := oc.dialTarget(ctx, endpoint)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to dial conductor")
+	}
+	defer target.Close()
+	return target.SequencerHealthy(ctx)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/go-multierror"
@@ -37,8 +39,30 @@ var (
 	ErrPauseTimeout       = errors.New("timeout to pause conductor")
 	ErrUnsafeHeadMismatch = errors.New("unsafe head mismatch")
 	ErrNoUnsafeHead       = errors.New("no unsafe head")
+	ErrTransferNotReady   = errors.New("target server is not ready to take over leadership")
+	ErrObserver           = errors.New("not permitted in observer mode")
+	ErrQuorumUnsafe       = errors.New("membership change would leave the cluster without a healthy quorum of voters")
 )
 
+// maxHandoverIncidents is the number of most recent handover incidents kept in memory.
+const maxHandoverIncidents = 16
+
+// targetConductor is the subset of the API of another conductor used to check it before transferring leadership to it,
+// or before changing the cluster membership.
+type targetConductor interface {
+	SequencerHealthy(ctx context.Context) (bool, error)
+	SequencerUnsafeHead(ctx context.Context) (eth.BlockID, error)
+	Close()
+}
+
+func dialTargetConductor(ctx context.Context, endpoint string) (targetConductor, error) {
+	c, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return conductorrpc.NewAPIClient(c), nil
+}
+
 // New creates a new OpConductor instance.
 func New(ctx context.Context, cfg *Config, log log.Logger, version string) (*OpConductor, error) {
 	return NewOpConductor(ctx, cfg, log, metrics.NewMetrics(), version, nil, nil, nil)
@@ -73,6 +97,8 @@ func NewOpConductor(
 		cons:         cons,
 		hmon:         hmon,
 		retryBackoff: func() time.Duration { return time.Duration(rand.Intn(2000)) * time.Millisecond },
+		dialTarget:   dialTargetConductor,
+		events:       newEventLog(maxEvents),
 	}
 	oc.loopActionFn = oc.loopAction
 
@@ -111,12 +137,24 @@ func (c *OpConductor) init(ctx context.Context) error {
 	if err := c.initHealthMonitor(ctx); err != nil {
 		return errors.Wrap(err, "failed to initialize health monitor")
 	}
+	if err := c.initAuditTrail(); err != nil {
+		return errors.Wrap(err, "failed to initialize audit trail")
+	}
 	if err := c.initRPCServer(ctx); err != nil {
 		return errors.Wrap(err, "failed to initialize rpc server")
 	}
 	return nil
 }
 
+func (c *OpConductor) initAuditTrail() error {
+	audit, err := newAuditTrail(c.cfg.AuditLogPath)
+	if err != nil {
+		return err
+	}
+	c.audit = audit
+	return nil
+}
+
 func (c *OpConductor) initSequencerControl(ctx context.Context) error {
 	if c.ctrl != nil {
 		return nil
@@ -149,19 +187,43 @@ func (c *OpConductor) initConsensus(ctx context.Context) error {
 	}
 
 	serverAddr := fmt.Sprintf("%s:%d", c.cfg.ConsensusAddr, c.cfg.ConsensusPort)
-	raftConsensusConfig := &consensus.RaftConsensusConfig{
-		ServerID:          c.cfg.RaftServerID,
-		ServerAddr:        serverAddr,
-		StorageDir:        c.cfg.RaftStorageDir,
-		Bootstrap:         c.cfg.RaftBootstrap,
-		RollupCfg:         &c.cfg.RollupCfg,
-		SnapshotInterval:  c.cfg.RaftSnapshotInterval,
-		SnapshotThreshold: c.cfg.RaftSnapshotThreshold,
-		TrailingLogs:      c.cfg.RaftTrailingLogs,
-	}
-	cons, err := consensus.NewRaftConsensus(c.log, raftConsensusConfig)
-	if err != nil {
-		return errors.Wrap(err, "failed to create raft consensus")
+	var cons consensus.Consensus
+	switch c.cfg.ConsensusBackend {
+	case ConsensusBackendEtcd, ConsensusBackendConsul:
+		var coord consensus.Coordinator
+		if c.cfg.ConsensusBackend == ConsensusBackendEtcd {
+			coord = consensus.NewEtcdCoordinator(c.cfg.ConsensusEndpoint)
+		} else {
+			coord = consensus.NewConsulCoordinator(c.cfg.ConsensusEndpoint)
+		}
+		externalConsensusConfig := &consensus.ExternalConsensusConfig{
+			ServerID:   c.cfg.RaftServerID,
+			ServerAddr: serverAddr,
+			Bootstrap:  c.cfg.RaftBootstrap,
+			KeyPrefix:  c.cfg.ConsensusKeyPrefix,
+			SessionTTL: c.cfg.ConsensusSessionTTL,
+		}
+		ec, err := consensus.NewExternalConsensus(c.log, externalConsensusConfig, coord)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %v consensus", c.cfg.ConsensusBackend)
+		}
+		cons = ec
+	default:
+		raftConsensusConfig := &consensus.RaftConsensusConfig{
+			ServerID:          c.cfg.RaftServerID,
+			ServerAddr:        serverAddr,
+			StorageDir:        c.cfg.RaftStorageDir,
+			Bootstrap:         c.cfg.RaftBootstrap,
+			RollupCfg:         &c.cfg.RollupCfg,
+			SnapshotInterval:  c.cfg.RaftSnapshotInterval,
+			SnapshotThreshold: c.cfg.RaftSnapshotThreshold,
+			TrailingLogs:      c.cfg.RaftTrailingLogs,
+		}
+		rc, err := consensus.NewRaftConsensus(c.log, raftConsensusConfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to create raft consensus")
+		}
+		cons = rc
 	}
 	c.cons = cons
 	c.leaderUpdateCh = c.cons.LeaderCh()
@@ -185,6 +247,15 @@ func (c *OpConductor) initHealthMonitor(ctx context.Context) error {
 	}
 	p2p := opp2p.NewClient(pc)
 
+	var supervisor health.SupervisorAPI
+	if c.cfg.SupervisorRPC != "" {
+		sc, err := opclient.NewRPC(ctx, c.log, c.cfg.SupervisorRPC)
+		if err != nil {
+			return errors.Wrap(err, "failed to create supervisor rpc client")
+		}
+		supervisor = sources.NewSupervisorClient(sc)
+	}
+
 	c.hmon = health.NewSequencerHealthMonitor(
 		c.log,
 		c.metrics,
@@ -192,10 +263,12 @@ func (c *OpConductor) initHealthMonitor(ctx context.Context) error {
 		c.cfg.HealthCheck.UnsafeInterval,
 		c.cfg.HealthCheck.SafeInterval,
 		c.cfg.HealthCheck.MinPeerCount,
+		c.cfg.HealthCheck.MaxCrossSafeLag,
 		c.cfg.HealthCheck.SafeEnabled,
 		&c.cfg.RollupCfg,
 		node,
 		p2p,
+		supervisor,
 	)
 	c.healthUpdateCh = c.hmon.Subscribe()
 
@@ -291,7 +364,24 @@ type OpConductor struct {
 	rpcServer     *oprpc.Server
 	metricsServer *httputil.HTTPServer
 
+	// handover tracks the failed attempts to start sequencing after acquiring leadership, it is only accessed by the control loop.
+	handover     *conductorrpc.HandoverIncident
+	incidentsMtx sync.Mutex
+	incidents    []conductorrpc.HandoverIncident
+
+	audit *auditTrail
+
+	// events is the log of control decisions, leaderlessSince is when the cluster was first seen without leader,
+	// it is only accessed by the control loop.
+	events          *eventLog
+	leaderlessSince time.Time
+
+	// warmup is the latest warm-up status of the standby sequencer, lastFed is only accessed by the warm-up loop.
+	warmup  atomic.Pointer[conductorrpc.WarmupStatus]
+	lastFed common.Hash
+
 	retryBackoff func() time.Duration
+	dialTarget   func(ctx context.Context, endpoint string) (targetConductor, error)
 }
 
 type state struct {
@@ -321,6 +411,12 @@ var _ cliapp.Lifecycle = (*OpConductor)(nil)
 func (oc *OpConductor) Start(ctx context.Context) error {
 	oc.log.Info("starting OpConductor")
 
+	if oc.cfg.Observer {
+		if err := oc.checkObserverSuffrage(); err != nil {
+			return err
+		}
+	}
+
 	if err := oc.hmon.Start(ctx); err != nil {
 		return errors.Wrap(err, "failed to start health monitor")
 	}
@@ -346,6 +442,17 @@ func (oc *OpConductor) Start(ctx context.Context) error {
 	oc.wg.Add(1)
 	go oc.loop()
 
+	if oc.cfg.WarmupInterval > 0 {
+		oc.wg.Add(1)
+		go oc.warmupLoop()
+	}
+
+	if oc.cfg.Observer {
+		oc.log.Info("running in observer mode")
+		oc.wg.Add(1)
+		go oc.observeLoop()
+	}
+
 	oc.metrics.RecordInfo(oc.version)
 	oc.metrics.RecordUp()
 
@@ -440,6 +547,41 @@ func (oc *OpConductor) Resume(ctx context.Context) error {
 	}
 }
 
+// PauseWithReason pauses the control loop like Pause, recording the operator and the reason in the audit trail.
+func (oc *OpConductor) PauseWithReason(ctx context.Context, operator, reason string) error {
+	return oc.auditedPauseOrResume(ctx, AuditActionPause, operator, reason, oc.Pause)
+}
+
+// ResumeWithReason resumes the control loop like Resume, recording the operator and the reason in the audit trail.
+func (oc *OpConductor) ResumeWithReason(ctx context.Context, operator, reason string) error {
+	return oc.auditedPauseOrResume(ctx, AuditActionResume, operator, reason, oc.Resume)
+}
+
+func (oc *OpConductor) auditedPauseOrResume(ctx context.Context, action, operator, reason string, fn func(context.Context) error) error {
+	if strings.TrimSpace(operator) == "" || strings.TrimSpace(reason) == "" {
+		return ErrAuditInfoRequired
+	}
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	oc.log.Info("conductor "+action+"d", "operator", operator, "reason", reason)
+	// The state has changed at this point, so failing to persist the entry is not reported as a failure of the call.
+	if err := oc.audit.add(conductorrpc.AuditEntry{Time: time.Now(), Action: action, Operator: operator, Reason: reason}); err != nil {
+		oc.log.Error("Failed to persist audit log entry", "action", action, "operator", operator, "reason", reason, "err", err)
+	}
+	return nil
+}
+
+// PauseState returns whether OpConductor is paused, and the latest pause or resume in the audit trail.
+func (oc *OpConductor) PauseState(_ context.Context) *conductorrpc.PauseState {
+	return &conductorrpc.PauseState{Paused: oc.Paused(), LastChange: oc.audit.last()}
+}
+
+// PauseHistory returns the audit trail of pauses and resumes, oldest first.
+func (oc *OpConductor) PauseHistory(_ context.Context) []conductorrpc.AuditEntry {
+	return oc.audit.list()
+}
+
 // Paused returns true if OpConductor is paused.
 func (oc *OpConductor) Paused() bool {
 	return oc.paused.Load()
@@ -487,9 +629,107 @@ func (oc *OpConductor) TransferLeaderToServer(_ context.Context, id string, addr
 	return oc.cons.TransferLeaderTo(id, addr)
 }
 
+// TransferLeaderToServerChecked transfers leadership to a specific server, if the target server is ready to take over:
+//  1. its sequencer is healthy
+//  2. its unsafe head is caught up with the unsafe head of the cluster
+//  3. the batcher is drained, i.e. the safe head of the leader is caught up with its unsafe head
+//
+// The returned report lists the checks that failed, in which case ErrTransferNotReady is returned (unless dry-run).
+func (oc *OpConductor) TransferLeaderToServerChecked(ctx context.Context, req conductorrpc.TransferLeaderRequest) (*conductorrpc.TransferLeaderReport, error) {
+	if !oc.cons.Leader() {
+		return nil, errors.New("only the leader can transfer leadership")
+	}
+	if req.ID == oc.cfg.RaftServerID {
+		return nil, errors.New("cannot transfer leadership to the leader itself")
+	}
+	membership, err := oc.cons.ClusterMembership()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster membership")
+	}
+	idx := slices.IndexFunc(membership.Servers, func(s consensus.ServerInfo) bool { return s.ID == req.ID })
+	if idx < 0 || membership.Servers[idx].Addr != req.Addr {
+		return nil, fmt.Errorf("server %v at %v is not a member of the cluster", req.ID, req.Addr)
+	}
+	if membership.Servers[idx].Suffrage != consensus.Voter {
+		return nil, fmt.Errorf("server %v is not a voter", req.ID)
+	}
+
+	report := &conductorrpc.TransferLeaderReport{Failures: []string{}}
+
+	unsafeHead, err := oc.cons.LatestUnsafePayload()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve unsafe head from consensus")
+	}
+	if unsafeHead == nil {
+		return nil, ErrNoUnsafeHead
+	}
+	report.LeaderUnsafeHead = unsafeHead.ExecutionPayload.ID()
+
+	target, err := oc.dialTarget(ctx, req.RPC)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial target conductor")
+	}
+	defer target.Close()
+
+	report.TargetHealthy, err = target.SequencerHealthy(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get target sequencer health")
+	}
+	if !report.TargetHealthy {
+		report.Failures = append(report.Failures, "target sequencer is not healthy")
+	}
+
+	report.TargetUnsafeHead, err = target.SequencerUnsafeHead(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get target unsafe head")
+	}
+	if report.TargetUnsafeHead.Number+req.MaxUnsafeLag < report.LeaderUnsafeHead.Number {
+		report.Failures = append(report.Failures, fmt.Sprintf("target unsafe head %v is more than %d blocks behind leader unsafe head %v",
+			report.TargetUnsafeHead, req.MaxUnsafeLag, report.LeaderUnsafeHead))
+	}
+
+	status, err := oc.ctrl.SyncStatus(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sync status")
+	}
+	report.LeaderSafeHead = status.SafeL2.ID()
+	if report.LeaderSafeHead.Number+req.MaxSafeLag < report.LeaderUnsafeHead.Number {
+		report.Failures = append(report.Failures, fmt.Sprintf("batcher is not drained, safe head %v is more than %d blocks behind unsafe head %v",
+			report.LeaderSafeHead, req.MaxSafeLag, report.LeaderUnsafeHead))
+	}
+
+	oc.log.Info("checked leadership transfer", "target", req.ID, "dry_run", req.DryRun, "failures", report.Failures)
+	if req.DryRun {
+		return report, nil
+	}
+	if len(report.Failures) > 0 {
+		return report, fmt.Errorf("%w: %v", ErrTransferNotReady, strings.Join(report.Failures, "; "))
+	}
+	if err := oc.cons.TransferLeaderTo(req.ID, req.Addr); err != nil {
+		return report, err
+	}
+	report.Transferred = true
+	return report, nil
+}
+
+// SequencerUnsafeHead returns the unsafe head of the sequencer.
+func (oc *OpConductor) SequencerUnsafeHead(ctx context.Context) (eth.BlockID, error) {
+	head, err := oc.ctrl.LatestUnsafeBlock(ctx)
+	if err != nil {
+		return eth.BlockID{}, errors.Wrap(err, "failed to get latest unsafe block")
+	}
+	return eth.ToBlockID(head), nil
+}
+
 // CommitUnsafePayload commits an unsafe payload (latest head) to the cluster FSM ensuring strong consistency by leveraging Raft consensus mechanisms.
 func (oc *OpConductor) CommitUnsafePayload(_ context.Context, payload *eth.ExecutionPayloadEnvelope) error {
-	return oc.cons.CommitUnsafePayload(payload)
+	if oc.cfg.Observer {
+		return ErrObserver
+	}
+	start := time.Now()
+	err := oc.cons.CommitUnsafePayload(payload)
+	oc.metrics.RecordCommitLatency(time.Since(start).Seconds())
+	return err
 }
 
 // SequencerHealthy returns true if sequencer is healthy.
@@ -557,6 +797,9 @@ func (oc *OpConductor) handleLeaderUpdate(leader bool) {
 	oc.log.Info("Leadership status changed", "server", oc.cons.ServerID(), "leader", leader)
 
 	oc.leader.Store(leader)
+	oc.metrics.RecordLeadershipChange(leader)
+	oc.recordEvent(EventLeadershipChange, NewState(leader, oc.healthy.Load(), oc.seqActive.Load()), fmt.Sprintf("leader=%v", leader), nil)
+	oc.sampleLeader()
 	oc.queueAction()
 }
 
@@ -572,21 +815,33 @@ func (oc *OpConductor) handleHealthUpdate(hcerr error) {
 
 	if oc.healthy.Swap(healthy) != healthy {
 		// queue an action if health status changed.
+		oc.metrics.RecordHealthFlap(healthy)
+		oc.recordEvent(EventHealthChange, NewState(oc.leader.Load(), healthy, oc.seqActive.Load()), fmt.Sprintf("healthy=%v", healthy), hcerr)
 		oc.queueAction()
 	}
 
 	oc.hcerr = hcerr
+	oc.sampleLeader()
 }
 
 // action tries to bring the sequencer to the desired state, a retry will be queued if any action failed.
 func (oc *OpConductor) action() {
+	if oc.cfg.Observer {
+		oc.observerAction()
+		return
+	}
 	if oc.Paused() {
 		return
 	}
 
 	var err error
+	var decision string // reason of the control decision, recorded in the event log unless empty
 	status := NewState(oc.leader.Load(), oc.healthy.Load(), oc.seqActive.Load())
 	oc.log.Debug("entering action with status", "status", status)
+	if !status.leader || status.active {
+		// not waiting for sequencing to start after acquiring leadership anymore
+		oc.handover = nil
+	}
 
 	// exhaust all cases below for completeness, 3 state, 8 cases.
 	switch {
@@ -595,11 +850,13 @@ func (oc *OpConductor) action() {
 		oc.log.Error("server (follower) is not healthy", "server", oc.cons.ServerID())
 	case !status.leader && !status.healthy && status.active:
 		// sequencer is not leader, not healthy, but it is sequencing, stop it
+		decision = "stop sequencing as an unhealthy follower"
 		err = oc.stopSequencer()
 	case !status.leader && status.healthy && !status.active:
 		// normal follower, do nothing
 	case !status.leader && status.healthy && status.active:
 		// stop sequencer, this happens when current server steps down as leader.
+		decision = "stop sequencing after stepping down as leader"
 		err = oc.stopSequencer()
 	case status.leader && !status.healthy && !status.active:
 		// There are 2 scenarios we need to handle:
@@ -607,6 +864,7 @@ func (oc *OpConductor) action() {
 		//    however if leadership transfer took longer than the time for health monitor to treat the node as unhealthy,
 		//    then basically the entire network is stalled and we need to start sequencing in this case.
 		if !oc.prevState.leader && !oc.prevState.active && !errors.Is(oc.hcerr, health.ErrSequencerConnectionDown) {
+			decision = "start sequencing as an unhealthy leader, as the network is stalled"
 			err = oc.startSequencer()
 			if err != nil {
 				oc.log.Error("failed to start sequencer, transferring leadership instead", "server", oc.cons.ServerID(), "err", err)
@@ -617,6 +875,7 @@ func (oc *OpConductor) action() {
 
 		// 2. for other cases, we should try to transfer leader to another node.
 		//    for example, if follower became a leader and unhealthy at the same time (just unhealthy itself), then we should transfer leadership.
+		decision = "transfer leadership as an unhealthy leader"
 		err = oc.transferLeader()
 	case status.leader && !status.healthy && status.active:
 		// There are two scenarios we need to handle here:
@@ -625,12 +884,14 @@ func (oc *OpConductor) action() {
 		//    note: we need to also make sure that the health error is not due to ErrSequencerConnectionDown
 		//    		because in this case, we should stop sequencing and transfer leadership to other nodes.
 		if oc.prevState.leader && !oc.prevState.healthy && !oc.prevState.active && !errors.Is(oc.hcerr, health.ErrSequencerConnectionDown) {
+			decision = "keep sequencing until healthy"
 			err = errors.New("waiting for sequencing to become healthy by itself")
 			break
 		}
 
 		// 2. we're here because an healthy leader became unhealthy itself
 		//    then we should try to stop sequencing locally and transfer leadership.
+		decision = "stop sequencing and transfer leadership as an unhealthy leader"
 		var result *multierror.Error
 		// Try to stop sequencer first, but since sequencer is not healthy, we may not be able to stop it.
 		// In this case, it's fine to continue to try to transfer leadership to another server. This is safe because
@@ -649,13 +910,19 @@ func (oc *OpConductor) action() {
 		}
 		err = result.ErrorOrNil()
 	case status.leader && status.healthy && !status.active:
-		// start sequencer
-		err = oc.startSequencer()
+		// start sequencer, and recover if the handover to this server failed
+		decision = "start sequencing as a healthy leader"
+		if err = oc.startSequencer(); err != nil {
+			err = oc.recoverFailedHandover(err)
+		}
 	case status.leader && status.healthy && status.active:
 		// normal leader, do nothing
 	}
 
 	oc.log.Debug("exiting action with status and error", "status", status, "err", err)
+	if decision != "" {
+		oc.recordEvent(EventAction, status, decision, err)
+	}
 	if err != nil {
 		select {
 		case <-oc.shutdownCtx.Done():
@@ -673,6 +940,25 @@ func (oc *OpConductor) action() {
 	}
 }
 
+// observerAction transfers leadership away as soon as the observer is elected as leader.
+// An observer never controls its sequencer, so the cluster would otherwise stall while it is leader.
+func (oc *OpConductor) observerAction() {
+	if !oc.leader.Load() {
+		return
+	}
+	status := NewState(true, oc.healthy.Load(), oc.seqActive.Load())
+	oc.log.Error("observer was elected as leader, transferring leadership", "server", oc.cons.ServerID())
+	err := oc.transferLeader()
+	oc.recordEvent(EventAction, status, "transfer leadership as an observer", err)
+	if err != nil {
+		select {
+		case <-oc.shutdownCtx.Done():
+		case <-time.After(oc.retryBackoff()):
+			oc.queueAction()
+		}
+	}
+}
+
 // transferLeader tries to transfer leadership to another server.
 func (oc *OpConductor) transferLeader() error {
 	// TransferLeader here will do round robin to try to transfer leadership to the next healthy node.
@@ -695,6 +981,78 @@ func (oc *OpConductor) transferLeader() error {
 	}
 }
 
+// recoverFailedHandover tracks the failures to start sequencing after acquiring leadership. Once they last longer than
+// the handover timeout, the handover is considered failed: there is no active sequencer while the previous leader is
+// already stopped. Leadership is then transferred to another server, and the incident is recorded.
+func (oc *OpConductor) recoverFailedHandover(startErr error) error {
+	if oc.cfg.HandoverTimeout == 0 {
+		return startErr
+	}
+
+	now := time.Now()
+	if oc.handover == nil {
+		oc.handover = &conductorrpc.HandoverIncident{Server: oc.cons.ServerID(), StartedAt: now}
+	}
+	oc.handover.Attempts++
+	oc.handover.LastError = startErr.Error()
+	if now.Sub(oc.handover.StartedAt) < oc.cfg.HandoverTimeout {
+		return startErr
+	}
+
+	oc.handover.DetectedAt = now
+	if unsafeHead, err := oc.cons.LatestUnsafePayload(); err == nil && unsafeHead != nil {
+		oc.handover.UnsafeHead = unsafeHead.ExecutionPayload.ID()
+	}
+	oc.log.Error("failed to start sequencing after acquiring leadership, transferring leadership",
+		"server", oc.handover.Server, "started_at", oc.handover.StartedAt, "attempts", oc.handover.Attempts, "err", startErr)
+
+	err := oc.transferLeader()
+	oc.metrics.RecordHandoverRecovery(err == nil)
+	oc.handover.Recovered = err == nil
+	oc.handover.RecoveryError = ""
+	if err != nil {
+		oc.handover.RecoveryError = err.Error()
+	}
+	oc.recordIncident(*oc.handover)
+	if err == nil {
+		oc.handover = nil
+	}
+	// otherwise keep tracking the incident, so that the next failure retries the recovery immediately
+	return err
+}
+
+// recordIncident logs the incident and keeps it in memory, replacing the previous report of the same incident.
+func (oc *OpConductor) recordIncident(incident conductorrpc.HandoverIncident) {
+	oc.log.Warn("handover incident",
+		"server", incident.Server,
+		"unsafe_head", incident.UnsafeHead,
+		"started_at", incident.StartedAt,
+		"detected_at", incident.DetectedAt,
+		"attempts", incident.Attempts,
+		"last_error", incident.LastError,
+		"recovered", incident.Recovered,
+		"recovery_error", incident.RecoveryError,
+	)
+
+	oc.incidentsMtx.Lock()
+	defer oc.incidentsMtx.Unlock()
+	if n := len(oc.incidents); n > 0 && oc.incidents[n-1].StartedAt.Equal(incident.StartedAt) {
+		oc.incidents[n-1] = incident
+		return
+	}
+	oc.incidents = append(oc.incidents, incident)
+	if len(oc.incidents) > maxHandoverIncidents {
+		oc.incidents = oc.incidents[len(oc.incidents)-maxHandoverIncidents:]
+	}
+}
+
+// HandoverIncidents returns the reports of the most recent failed leadership handovers, oldest first.
+func (oc *OpConductor) HandoverIncidents(_ context.Context) []conductorrpc.HandoverIncident {
+	oc.incidentsMtx.Lock()
+	defer oc.incidentsMtx.Unlock()
+	return slices.Clone(oc.incidents)
+}
+
 func (oc *OpConductor) stopSequencer() error {
 	oc.log.Info(
 		"stopping sequencer",
@@ -797,6 +1155,200 @@ func (oc *OpConductor) compareUnsafeHead(ctx context.Context) (*eth.ExecutionPay
 	return unsafeInCons, unsafeInNode, nil
 }
 
+// checkObserverSuffrage refuses to run an observer that is a voter of the cluster, as it could be elected as leader.
+func (oc *OpConductor) checkObserverSuffrage() error {
+	membership, err := oc.cons.ClusterMembership()
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster membership")
+	}
+	for _, server := range membership.Servers {
+		if server.ID == oc.cons.ServerID() && server.Suffrage == consensus.Voter {
+			return fmt.Errorf("observer %s is a voter of the cluster, it must be added as a non-voter", server.ID)
+		}
+	}
+	return nil
+}
+
+// observeLoop periodically records the cluster state in observer mode.
+func (oc *OpConductor) observeLoop() {
+	defer oc.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(oc.cfg.HealthCheck.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-oc.shutdownCtx.Done():
+			return
+		case <-ticker.C:
+			oc.observe(oc.shutdownCtx)
+		}
+	}
+}
+
+// observe records metrics about the cluster health, and the divergence of the local node from the cluster.
+func (oc *OpConductor) observe(ctx context.Context) {
+	membership, err := oc.cons.ClusterMembership()
+	if err != nil {
+		oc.log.Error("failed to get cluster membership", "err", err)
+	} else {
+		var voters, nonvoters int
+		for _, server := range membership.Servers {
+			if server.Suffrage == consensus.Voter {
+				voters++
+			} else {
+				nonvoters++
+			}
+			if server.ID == oc.cons.ServerID() && server.Suffrage == consensus.Voter {
+				oc.log.Error("observer is a voter of the cluster and may be elected as leader, it must be added as a non-voter", "server", server.ID)
+			}
+		}
+		oc.metrics.RecordClusterMembership(voters, nonvoters)
+	}
+
+	leader := oc.cons.LeaderWithID()
+	hasLeader := leader != nil && leader.ID != ""
+	if !hasLeader {
+		oc.log.Warn("cluster has no leader")
+	}
+	oc.metrics.RecordClusterLeader(hasLeader)
+
+	unsafeInCons, err := oc.cons.LatestUnsafePayload()
+	if err != nil || unsafeInCons == nil {
+		oc.log.Warn("no unsafe head in consensus", "err", err)
+		return
+	}
+	unsafeInNode, err := oc.ctrl.LatestUnsafeBlock(ctx)
+	if err != nil {
+		oc.log.Error("failed to get latest unsafe block from node", "err", err)
+		return
+	}
+	consID, nodeID := unsafeInCons.ExecutionPayload.ID(), eth.ToBlockID(unsafeInNode)
+	diverged := consID.Number == nodeID.Number && consID.Hash != nodeID.Hash
+	if diverged {
+		oc.log.Error("unsafe head of the node diverged from consensus", "consensus", consID, "node", nodeID)
+	}
+	oc.metrics.RecordUnsafeHeads(consID.Number, nodeID.Number, diverged)
+}
+
+// warmupLoop periodically warms up the sequencer while it is on standby.
+func (oc *OpConductor) warmupLoop() {
+	defer oc.wg.Done()
+
+	ticker := time.NewTicker(oc.cfg.WarmupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-oc.shutdownCtx.Done():
+			return
+		case <-ticker.C:
+			oc.warmUp(oc.shutdownCtx)
+		}
+	}
+}
+
+// warmUp feeds the latest unsafe head in consensus to the standby sequencer, and checks that its execution layer is
+// synced with it, so that failover does not have to wait for the sequencer to catch up.
+func (oc *OpConductor) warmUp(ctx context.Context) {
+	status := &conductorrpc.WarmupStatus{LastChecked: time.Now()}
+	defer func() {
+		oc.warmup.Store(status)
+		if status.Enabled {
+			oc.metrics.RecordWarmup(status.Warm, status.Lag)
+		}
+	}()
+	if oc.seqActive.Load() {
+		// the active sequencer is the source of the unsafe head in consensus
+		return
+	}
+	if oc.cfg.Observer || oc.Paused() {
+		// observers never sequence, and the sequencer of a paused conductor is managed by the operator
+		return
+	}
+	status.Enabled = true
+
+	unsafeInCons, err := oc.cons.LatestUnsafePayload()
+	if err != nil {
+		status.Error = errors.Wrap(err, "unable to retrieve unsafe head from consensus").Error()
+		return
+	}
+	if unsafeInCons == nil {
+		status.Error = ErrNoUnsafeHead.Error()
+		return
+	}
+	unsafeInNode, err := oc.ctrl.LatestUnsafeBlock(ctx)
+	if err != nil {
+		status.Error = errors.Wrap(err, "failed to get latest unsafe block from EL").Error()
+		return
+	}
+	status.ConsensusUnsafeHead = unsafeInCons.ExecutionPayload.ID()
+	status.NodeUnsafeHead = eth.ToBlockID(unsafeInNode)
+
+	switch cons, node := status.ConsensusUnsafeHead, status.NodeUnsafeHead; {
+	case cons.Number > node.Number:
+		status.Lag = cons.Number - node.Number
+		// feed each unsafe head once, the node fills any gap before it through p2p or EL sync
+		if oc.lastFed != cons.Hash {
+			if err := oc.ctrl.PostUnsafePayload(ctx, unsafeInCons); err != nil {
+				status.Error = errors.Wrap(err, "failed to post unsafe head payload envelope to op-node").Error()
+				return
+			}
+			oc.lastFed = cons.Hash
+		}
+	case cons.Number == node.Number && cons.Hash != node.Hash:
+		status.Error = ErrUnsafeHeadMismatch.Error()
+		oc.log.Warn("unsafe head of the standby sequencer diverged from consensus", "consensus", cons, "node", node)
+		return
+	}
+	status.Warm = status.Lag <= oc.cfg.WarmupMaxLag
+}
+
+// WarmupStatus returns the latest warm-up status of the sequencer.
+func (oc *OpConductor) WarmupStatus(_ context.Context) *conductorrpc.WarmupStatus {
+	if status := oc.warmup.Load(); status != nil {
+		return status
+	}
+	return &conductorrpc.WarmupStatus{}
+}
+
+// sampleLeader records the duration of leader elections, as the time the cluster is seen without leader.
+// The cluster is sampled on every leader and health update, so durations are measured at the health check interval.
+func (oc *OpConductor) sampleLeader() {
+	leader := oc.cons.LeaderWithID()
+	hasLeader := leader != nil && leader.ID != ""
+	switch {
+	case !hasLeader && oc.leaderlessSince.IsZero():
+		oc.leaderlessSince = time.Now()
+	case hasLeader && !oc.leaderlessSince.IsZero():
+		duration := time.Since(oc.leaderlessSince)
+		oc.leaderlessSince = time.Time{}
+		oc.log.Info("leader elected", "leader", leader.ID, "duration", duration)
+		oc.metrics.RecordElectionDuration(duration.Seconds())
+	}
+}
+
+// recordEvent records a control event in the event log.
+func (oc *OpConductor) recordEvent(kind string, status *state, reason string, err error) {
+	event := conductorrpc.ControlEvent{
+		Time:    time.Now(),
+		Kind:    kind,
+		Leader:  status.leader,
+		Healthy: status.healthy,
+		Active:  status.active,
+		Reason:  reason,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	oc.events.add(event)
+}
+
+// Events returns the event log of control decisions, oldest first.
+func (oc *OpConductor) Events(_ context.Context) []conductorrpc.ControlEvent {
+	return oc.events.list()
+}
+
 func (oc *OpConductor) updateSequencerActiveStatus() error {
 	active, err := oc.ctrl.SequencerActive(oc.shutdownCtx)
 	if err != nil {
@@ -3,6 +3,7 @@ package conductor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"testing"
@@ -13,13 +14,16 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	clientmocks "github.com/ethereum-optimism/optimism/op-conductor/client/mocks"
+	"github.com/ethereum-optimism/optimism/op-conductor/consensus"
 	consensusmocks "github.com/ethereum-optimism/optimism/op-conductor/consensus/mocks"
 	"github.com/ethereum-optimism/optimism/op-conductor/health"
 	healthmocks "github.com/ethereum-optimism/optimism/op-conductor/health/mocks"
 	"github.com/ethereum-optimism/optimism/op-conductor/metrics"
+	conductorrpc "github.com/ethereum-optimism/optimism/op-conductor/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
@@ -115,6 +119,7 @@ func (s *OpConductorTestSuite) SetupTest() {
 	s.cons = &consensusmocks.Consensus{}
 	s.hmon = &healthmocks.HealthMonitor{}
 	s.cons.EXPECT().ServerID().Return("SequencerA")
+	s.cons.EXPECT().LeaderWithID().Return(&consensus.ServerInfo{ID: "SequencerA"}).Maybe()
 
 	conductor, err := NewOpConductor(s.ctx, &s.cfg, s.log, s.metrics, s.version, s.ctrl, s.cons, s.hmon)
 	s.NoError(err)
@@ -867,6 +872,455 @@ func (s *OpConductorTestSuite) TestConductorRestart() {
 	s.ctrl.AssertCalled(s.T(), "StopSequencer", mock.Anything)
 }
 
+type fakeTargetConductor struct {
+	healthy    bool
+	unsafeHead eth.BlockID
+}
+
+func (f *fakeTargetConductor) SequencerHealthy(_ context.Context) (bool, error) {
+	return f.healthy, nil
+}
+
+func (f *fakeTargetConductor) SequencerUnsafeHead(_ context.Context) (eth.BlockID, error) {
+	return f.unsafeHead, nil
+}
+
+func (f *fakeTargetConductor) Close() {}
+
+func (s *OpConductorTestSuite) TestTransferLeaderToServerChecked() {
+	target := &fakeTargetConductor{healthy: true, unsafeHead: eth.BlockID{Number: 99}}
+	s.conductor.dialTarget = func(_ context.Context, endpoint string) (targetConductor, error) {
+		s.Equal("http://target:8547", endpoint)
+		return target, nil
+	}
+	s.cons.EXPECT().Leader().Return(true)
+	s.cons.EXPECT().ClusterMembership().Return(&consensus.ClusterMembership{
+		Servers: []consensus.ServerInfo{
+			{ID: "SequencerA", Addr: "127.0.0.1:50050", Suffrage: consensus.Voter},
+			{ID: "SequencerB", Addr: "127.0.0.1:50051", Suffrage: consensus.Voter},
+			{ID: "SequencerC", Addr: "127.0.0.1:50052", Suffrage: consensus.Nonvoter},
+		},
+	}, nil)
+	s.cons.EXPECT().LatestUnsafePayload().Return(&eth.ExecutionPayloadEnvelope{
+		ExecutionPayload: &eth.ExecutionPayload{BlockNumber: 100},
+	}, nil)
+	s.ctrl.EXPECT().SyncStatus(mock.Anything).Return(&eth.SyncStatus{SafeL2: eth.L2BlockRef{Number: 90}}, nil)
+
+	req := conductorrpc.TransferLeaderRequest{
+		ID:           "SequencerB",
+		Addr:         "127.0.0.1:50051",
+		RPC:          "http://target:8547",
+		MaxUnsafeLag: 2,
+		MaxSafeLag:   10,
+		DryRun:       true,
+	}
+
+	s.Run("UnknownTarget", func() {
+		invalid := req
+		invalid.Addr = "127.0.0.1:50052"
+		_, err := s.conductor.TransferLeaderToServerChecked(s.ctx, invalid)
+		s.ErrorContains(err, "not a member")
+		invalid.ID = "SequencerC"
+		_, err = s.conductor.TransferLeaderToServerChecked(s.ctx, invalid)
+		s.ErrorContains(err, "not a voter")
+	})
+
+	s.Run("DryRun", func() {
+		report, err := s.conductor.TransferLeaderToServerChecked(s.ctx, req)
+		s.NoError(err)
+		s.Empty(report.Failures)
+		s.False(report.Transferred)
+		s.Equal(uint64(100), report.LeaderUnsafeHead.Number)
+		s.Equal(uint64(99), report.TargetUnsafeHead.Number)
+		s.Equal(uint64(90), report.LeaderSafeHead.Number)
+		s.cons.AssertNotCalled(s.T(), "TransferLeaderTo", mock.Anything, mock.Anything)
+	})
+
+	s.Run("NotReady", func() {
+		target.healthy = false
+		notReady := req
+		notReady.MaxUnsafeLag = 0
+		notReady.MaxSafeLag = 5
+		report, err := s.conductor.TransferLeaderToServerChecked(s.ctx, notReady)
+		s.NoError(err)
+		s.Len(report.Failures, 3)
+
+		notReady.DryRun = false
+		report, err = s.conductor.TransferLeaderToServerChecked(s.ctx, notReady)
+		s.ErrorIs(err, ErrTransferNotReady)
+		s.False(report.Transferred)
+		s.cons.AssertNotCalled(s.T(), "TransferLeaderTo", mock.Anything, mock.Anything)
+		target.healthy = true
+	})
+
+	s.Run("Transfer", func() {
+		s.cons.EXPECT().TransferLeaderTo("SequencerB", "127.0.0.1:50051").Return(nil).Once()
+		transfer := req
+		transfer.DryRun = false
+		report, err := s.conductor.TransferLeaderToServerChecked(s.ctx, transfer)
+		s.NoError(err)
+		s.True(report.Transferred)
+	})
+}
+
+// In this test, leadership is handed over to a healthy follower, which fails to start sequencing until the handover
+// timeout expires, and then transfers leadership back to another server.
+// 1. [follower, healthy, not sequencing] -- become leader -->
+// 2. [leader, healthy, not sequencing] -- start sequencing failed, retry -->
+// 3. [leader, healthy, not sequencing] -- start sequencing failed, handover timed out, transfer leadership succeeded -->
+// 4. [follower, healthy, not sequencing]
+func (s *OpConductorTestSuite) TestFailedHandoverRecovery() {
+	cfg := s.cfg
+	cfg.HandoverTimeout = time.Nanosecond
+	s.conductor.cfg = &cfg
+	s.enableSynchronization()
+
+	s.cons.EXPECT().LatestUnsafePayload().Return(nil, s.err)
+
+	// step 1 & 2: become leader, start sequencing failed
+	s.updateLeaderStatusAndExecuteAction(true)
+	s.True(s.conductor.leader.Load())
+	s.False(s.conductor.seqActive.Load())
+	s.Empty(s.conductor.HandoverIncidents(s.ctx))
+	s.cons.AssertNotCalled(s.T(), "TransferLeader")
+
+	// step 3: start sequencing failed again after the handover timeout, transfer leadership
+	s.cons.EXPECT().TransferLeader().Return(nil).Times(1)
+	s.executeAction()
+
+	s.False(s.conductor.leader.Load())
+	s.False(s.conductor.seqActive.Load())
+	s.cons.AssertNumberOfCalls(s.T(), "TransferLeader", 1)
+	s.ctrl.AssertNotCalled(s.T(), "StartSequencer", mock.Anything, mock.Anything)
+
+	incidents := s.conductor.HandoverIncidents(s.ctx)
+	s.Len(incidents, 1)
+	s.Equal("SequencerA", incidents[0].Server)
+	s.Equal(2, incidents[0].Attempts)
+	s.True(incidents[0].Recovered)
+	s.Contains(incidents[0].LastError, "unable to retrieve unsafe head from consensus")
+	s.Nil(s.conductor.handover)
+}
+
+func (s *OpConductorTestSuite) TestWarmUp() {
+	cfg := s.cfg
+	cfg.WarmupMaxLag = 1
+	s.conductor.cfg = &cfg
+	s.False(s.conductor.WarmupStatus(s.ctx).Enabled)
+
+	payload := &eth.ExecutionPayloadEnvelope{
+		ExecutionPayload: &eth.ExecutionPayload{BlockNumber: 10, BlockHash: common.Hash{0x0a}},
+	}
+	s.cons.EXPECT().LatestUnsafePayload().Return(payload, nil)
+
+	// behind consensus, feed the unsafe head once
+	s.ctrl.EXPECT().LatestUnsafeBlock(mock.Anything).Return(&testutils.MockBlockInfo{InfoNum: 8, InfoHash: common.Hash{0x08}}, nil).Times(2)
+	s.ctrl.EXPECT().PostUnsafePayload(mock.Anything, payload).Return(nil).Once()
+	s.conductor.warmUp(s.ctx)
+	status := s.conductor.WarmupStatus(s.ctx)
+	s.True(status.Enabled)
+	s.False(status.Warm)
+	s.Equal(uint64(2), status.Lag)
+	s.Equal(payload.ExecutionPayload.ID(), status.ConsensusUnsafeHead)
+	s.conductor.warmUp(s.ctx)
+	s.ctrl.AssertNumberOfCalls(s.T(), "PostUnsafePayload", 1)
+
+	// within the max lag
+	s.ctrl.EXPECT().LatestUnsafeBlock(mock.Anything).Return(&testutils.MockBlockInfo{InfoNum: 9, InfoHash: common.Hash{0x09}}, nil).Once()
+	s.conductor.warmUp(s.ctx)
+	status = s.conductor.WarmupStatus(s.ctx)
+	s.True(status.Warm)
+	s.Equal(uint64(1), status.Lag)
+
+	// diverged from consensus
+	s.ctrl.EXPECT().LatestUnsafeBlock(mock.Anything).Return(&testutils.MockBlockInfo{InfoNum: 10, InfoHash: common.Hash{0xff}}, nil).Once()
+	s.conductor.warmUp(s.ctx)
+	status = s.conductor.WarmupStatus(s.ctx)
+	s.False(status.Warm)
+	s.Equal(ErrUnsafeHeadMismatch.Error(), status.Error)
+
+	// the active sequencer is not warmed up
+	s.conductor.seqActive.Store(true)
+	s.conductor.warmUp(s.ctx)
+	s.False(s.conductor.WarmupStatus(s.ctx).Enabled)
+
+	// nor is the sequencer of a paused conductor
+	s.conductor.seqActive.Store(false)
+	s.conductor.paused.Store(true)
+	s.conductor.warmUp(s.ctx)
+	s.False(s.conductor.WarmupStatus(s.ctx).Enabled)
+
+	// nor the sequencer of an observer
+	s.conductor.paused.Store(false)
+	cfg.Observer = true
+	s.conductor.warmUp(s.ctx)
+	s.False(s.conductor.WarmupStatus(s.ctx).Enabled)
+}
+
+func (s *OpConductorTestSuite) TestEventLog() {
+	s.enableSynchronization()
+
+	// [follower, healthy, not sequencing] -- become leader, start sequencing
+	s.cons.EXPECT().LatestUnsafePayload().Return(&eth.ExecutionPayloadEnvelope{
+		ExecutionPayload: &eth.ExecutionPayload{BlockNumber: 1, BlockHash: common.Hash{0x01}},
+	}, nil).Times(1)
+	s.ctrl.EXPECT().LatestUnsafeBlock(mock.Anything).Return(&testutils.MockBlockInfo{InfoNum: 1, InfoHash: common.Hash{0x01}}, nil).Times(1)
+	s.ctrl.EXPECT().StartSequencer(mock.Anything, common.Hash{0x01}).Return(nil).Times(1)
+	s.updateLeaderStatusAndExecuteAction(true)
+
+	// [leader, healthy, sequencing] -- become unhealthy, stop sequencing and transfer leadership
+	s.ctrl.EXPECT().StopSequencer(mock.Anything).Return(common.Hash{}, nil).Times(1)
+	s.cons.EXPECT().TransferLeader().Return(nil).Times(1)
+	s.updateHealthStatusAndExecuteAction(health.ErrSequencerNotHealthy)
+
+	events := s.conductor.Events(s.ctx)
+	s.Len(events, 4)
+	s.Equal(EventLeadershipChange, events[0].Kind)
+	s.Equal("leader=true", events[0].Reason)
+	s.Equal(EventAction, events[1].Kind)
+	s.Equal("start sequencing as a healthy leader", events[1].Reason)
+	s.Empty(events[1].Error)
+	s.Equal(EventHealthChange, events[2].Kind)
+	s.Equal(health.ErrSequencerNotHealthy.Error(), events[2].Error)
+	s.Equal(EventAction, events[3].Kind)
+	s.Equal("stop sequencing and transfer leadership as an unhealthy leader", events[3].Reason)
+	s.True(events[3].Leader)
+	s.False(events[3].Healthy)
+	s.True(events[3].Active)
+	s.Empty(events[3].Error)
+}
+
+type electionMetrics struct {
+	metrics.NoopMetricsImpl
+	durations []float64
+}
+
+func (m *electionMetrics) RecordElectionDuration(duration float64) {
+	m.durations = append(m.durations, duration)
+}
+
+func (s *OpConductorTestSuite) TestElectionDuration() {
+	m := &electionMetrics{}
+	s.conductor.metrics = m
+	cons := &consensusmocks.Consensus{}
+	s.conductor.cons = cons
+	defer func() { s.conductor.cons = s.cons }()
+
+	cons.EXPECT().LeaderWithID().Return(&consensus.ServerInfo{}).Times(2)
+	s.conductor.sampleLeader()
+	start := s.conductor.leaderlessSince
+	s.False(start.IsZero())
+	s.conductor.sampleLeader()
+	s.Equal(start, s.conductor.leaderlessSince)
+	s.Empty(m.durations)
+
+	cons.EXPECT().LeaderWithID().Return(&consensus.ServerInfo{ID: "SequencerB"}).Times(2)
+	s.conductor.sampleLeader()
+	s.True(s.conductor.leaderlessSince.IsZero())
+	s.Len(m.durations, 1)
+	s.conductor.sampleLeader()
+	s.Len(m.durations, 1)
+}
+
+func (s *OpConductorTestSuite) TestChangeMembership() {
+	targets := map[string]*fakeTargetConductor{
+		"http://b:8547": {healthy: true},
+		"http://c:8547": {healthy: true},
+		"http://d:8547": {healthy: true},
+	}
+	s.conductor.dialTarget = func(_ context.Context, endpoint string) (targetConductor, error) {
+		return targets[endpoint], nil
+	}
+	s.conductor.healthy.Store(true)
+	membership := &consensus.ClusterMembership{
+		Servers: []consensus.ServerInfo{
+			{ID: "SequencerA", Addr: "127.0.0.1:50050", Suffrage: consensus.Voter},
+			{ID: "SequencerB", Addr: "127.0.0.1:50051", Suffrage: consensus.Voter},
+			{ID: "SequencerC", Addr: "127.0.0.1:50052", Suffrage: consensus.Voter},
+		},
+		Version: 5,
+	}
+	s.cons.EXPECT().ClusterMembership().Return(membership, nil)
+	rpcs := map[string]string{"SequencerB": "http://b:8547", "SequencerC": "http://c:8547", "SequencerD": "http://d:8547"}
+
+	s.Run("Health", func() {
+		targets["http://c:8547"].healthy = false
+		defer func() { targets["http://c:8547"].healthy = true }()
+		health, err := s.conductor.ClusterHealth(s.ctx, map[string]string{"SequencerC": "http://c:8547"})
+		s.NoError(err)
+		s.Len(health, 3)
+		s.True(health[0].Healthy)
+		s.True(health[0].Leader)
+		s.False(health[1].Healthy)
+		s.Equal("no rpc endpoint", health[1].Error)
+		s.False(health[2].Healthy)
+		s.Empty(health[2].Error)
+	})
+
+	s.Run("VersionMismatch", func() {
+		_, err := s.conductor.ChangeMembership(s.ctx, conductorrpc.MembershipChange{Remove: "SequencerC", RPCs: rpcs, Version: 4})
+		s.ErrorContains(err, "does not match")
+	})
+
+	s.Run("QuorumUnsafe", func() {
+		targets["http://b:8547"].healthy = false
+		defer func() { targets["http://b:8547"].healthy = true }()
+		s.conductor.healthy.Store(false)
+		defer s.conductor.healthy.Store(true)
+		_, err := s.conductor.ChangeMembership(s.ctx, conductorrpc.MembershipChange{Remove: "SequencerC", RPCs: rpcs})
+		s.ErrorIs(err, ErrQuorumUnsafe)
+		s.cons.AssertNotCalled(s.T(), "RemoveServer", mock.Anything, mock.Anything)
+	})
+
+	s.Run("UnhealthyVoter", func() {
+		targets["http://d:8547"].healthy = false
+		defer func() { targets["http://d:8547"].healthy = true }()
+		_, err := s.conductor.ChangeMembership(s.ctx, conductorrpc.MembershipChange{
+			Add:  &consensus.ServerInfo{ID: "SequencerD", Addr: "127.0.0.1:50053", Suffrage: consensus.Voter},
+			RPCs: rpcs,
+		})
+		s.ErrorContains(err, "not healthy")
+		s.cons.AssertNotCalled(s.T(), "AddVoter", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	s.Run("Replace", func() {
+		s.cons.EXPECT().AddVoter("SequencerD", "127.0.0.1:50053", uint64(5)).Return(nil).Once()
+		s.cons.EXPECT().RemoveServer("SequencerC", uint64(5)).Return(nil).Once()
+		_, err := s.conductor.ChangeMembership(s.ctx, conductorrpc.MembershipChange{
+			Add:     &consensus.ServerInfo{ID: "SequencerD", Addr: "127.0.0.1:50053", Suffrage: consensus.Voter},
+			Remove:  "SequencerC",
+			RPCs:    rpcs,
+			Version: 5,
+		})
+		s.NoError(err)
+	})
+}
+
+func (s *OpConductorTestSuite) TestPauseWithReason() {
+	s.disableSynchronization()
+
+	s.ErrorIs(s.conductor.PauseWithReason(s.ctx, "", "maintenance"), ErrAuditInfoRequired)
+	s.ErrorIs(s.conductor.PauseWithReason(s.ctx, "alice", " "), ErrAuditInfoRequired)
+	s.False(s.conductor.Paused())
+	s.Nil(s.conductor.PauseState(s.ctx).LastChange)
+
+	s.NoError(s.conductor.PauseWithReason(s.ctx, "alice", "maintenance"))
+	state := s.conductor.PauseState(s.ctx)
+	s.True(state.Paused)
+	s.Equal(AuditActionPause, state.LastChange.Action)
+	s.Equal("alice", state.LastChange.Operator)
+
+	s.ctrl.EXPECT().SequencerActive(mock.Anything).Return(false, nil).Times(1)
+	s.NoError(s.conductor.ResumeWithReason(s.ctx, "bob", "maintenance done"))
+	state = s.conductor.PauseState(s.ctx)
+	s.False(state.Paused)
+	s.Equal(AuditActionResume, state.LastChange.Action)
+
+	history := s.conductor.PauseHistory(s.ctx)
+	s.Len(history, 2)
+	s.Equal("maintenance", history[0].Reason)
+	s.Equal("maintenance done", history[1].Reason)
+}
+
+func (s *OpConductorTestSuite) TestPauseWithReasonAuditFailure() {
+	s.disableSynchronization()
+	// The audit log cannot be opened for writing, as its path is a directory
+	s.conductor.audit = &auditTrail{path: s.T().TempDir()}
+
+	s.NoError(s.conductor.PauseWithReason(s.ctx, "alice", "maintenance"), "pause took effect, so should not fail")
+	state := s.conductor.PauseState(s.ctx)
+	s.True(state.Paused)
+	s.Equal("alice", state.LastChange.Operator, "should still record the change in memory")
+}
+
+func TestEventLogBounded(t *testing.T) {
+	l := newEventLog(3)
+	require.Empty(t, l.list())
+	for i := 0; i < 5; i++ {
+		l.add(conductorrpc.ControlEvent{Reason: fmt.Sprint(i)})
+	}
+	events := l.list()
+	require.Len(t, events, 3)
+	for i, event := range events {
+		require.Equal(t, fmt.Sprint(i+2), event.Reason)
+	}
+}
+
+type observerMetrics struct {
+	metrics.NoopMetricsImpl
+	voters, nonvoters int
+	hasLeader         bool
+	consensus, node   uint64
+	diverged          bool
+}
+
+func (m *observerMetrics) RecordClusterMembership(voters, nonvoters int) {
+	m.voters, m.nonvoters = voters, nonvoters
+}
+
+func (m *observerMetrics) RecordClusterLeader(hasLeader bool) {
+	m.hasLeader = hasLeader
+}
+
+func (m *observerMetrics) RecordUnsafeHeads(consensus, node uint64, diverged bool) {
+	m.consensus, m.node, m.diverged = consensus, node, diverged
+}
+
+func (s *OpConductorTestSuite) TestObserver() {
+	cfg := s.cfg
+	cfg.Observer = true
+	cfg.HealthCheck.Interval = 3600 // observe explicitly below, instead of in the observe loop
+	s.conductor.cfg = &cfg
+	m := &observerMetrics{}
+	s.conductor.metrics = m
+
+	s.cons.EXPECT().ClusterMembership().Return(&consensus.ClusterMembership{
+		Servers: []consensus.ServerInfo{
+			{ID: "SequencerB", Suffrage: consensus.Voter},
+			{ID: "SequencerC", Suffrage: consensus.Voter},
+			{ID: "SequencerA", Suffrage: consensus.Nonvoter},
+		},
+	}, nil)
+
+	// an observer never controls its sequencer, nor commits unsafe payloads, and gives up leadership immediately
+	s.cons.EXPECT().TransferLeader().Return(nil).Once()
+	s.conductor.leader.Store(true)
+	s.conductor.healthy.Store(true)
+	s.conductor.seqActive.Store(false)
+	s.enableSynchronization()
+	s.False(s.conductor.leader.Load())
+	s.ctrl.AssertNotCalled(s.T(), "StartSequencer", mock.Anything, mock.Anything)
+	s.ErrorIs(s.conductor.CommitUnsafePayload(s.ctx, &eth.ExecutionPayloadEnvelope{}), ErrObserver)
+	s.cons.EXPECT().LatestUnsafePayload().Return(&eth.ExecutionPayloadEnvelope{
+		ExecutionPayload: &eth.ExecutionPayload{BlockNumber: 2, BlockHash: common.Hash{0x02}},
+	}, nil)
+	mockBlockInfo := &testutils.MockBlockInfo{InfoNum: 2, InfoHash: common.Hash{0x03}}
+	s.ctrl.EXPECT().LatestUnsafeBlock(mock.Anything).Return(mockBlockInfo, nil)
+
+	s.conductor.observe(s.ctx)
+	s.Equal(2, m.voters)
+	s.Equal(1, m.nonvoters)
+	s.True(m.hasLeader)
+	s.Equal(uint64(2), m.consensus)
+	s.Equal(uint64(2), m.node)
+	s.True(m.diverged)
+}
+
+func (s *OpConductorTestSuite) TestObserverVoter() {
+	cfg := s.cfg
+	cfg.Observer = true
+	s.conductor.cfg = &cfg
+
+	s.cons.EXPECT().ClusterMembership().Return(&consensus.ClusterMembership{
+		Servers: []consensus.ServerInfo{
+			{ID: "SequencerB", Suffrage: consensus.Voter},
+			{ID: "SequencerA", Suffrage: consensus.Voter},
+		},
+	}, nil)
+	s.ErrorContains(s.conductor.Start(s.ctx), "must be added as a non-voter")
+	s.hmon.AssertNotCalled(s.T(), "Start", mock.Anything)
+}
+
 func (s *OpConductorTestSuite) TestHandleInitError() {
 	// This will cause an error in the init function, which should cause the conductor to stop successfully without issues.
 	_, err := New(s.ctx, &s.cfg, s.log, s.version)
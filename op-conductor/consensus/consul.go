@@ -0,0 +1,173 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var _ Coordinator = (*ConsulCoordinator)(nil)
+
+// ConsulCoordinator implements Coordinator using the HTTP API of consul.
+// Leadership is held with a consul lock, and the keys locked by a session are deleted when the session is invalidated.
+type ConsulCoordinator struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewConsulCoordinator creates a new ConsulCoordinator for the consul agent at the endpoint, e.g. http://127.0.0.1:8500.
+func NewConsulCoordinator(endpoint string) *ConsulCoordinator {
+	return &ConsulCoordinator{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type consulKVPair struct {
+	Value       []byte
+	ModifyIndex uint64
+	Session     string
+}
+
+type consulTxnOp struct {
+	KV consulTxnKV
+}
+
+type consulTxnKV struct {
+	Verb    string
+	Key     string
+	Value   []byte `json:",omitempty"`
+	Session string `json:",omitempty"`
+}
+
+// do sends the request and decodes the JSON response into out, if not nil.
+// The status code is returned for the caller to handle non-2xx responses that are not errors.
+func (c *ConsulCoordinator) do(ctx context.Context, method, path string, query url.Values, body io.Reader, out any) (int, error) {
+	u := c.endpoint + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("consul %v %v: %v: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("consul %v %v: failed to decode response: %w", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func (c *ConsulCoordinator) CreateSession(ctx context.Context, ttl uint64) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"Name":     "op-conductor",
+		"TTL":      fmt.Sprintf("%ds", ttl),
+		"Behavior": "delete",
+		// Allow another server to acquire leadership as soon as the session expires,
+		// the session ttl already covers the time for the previous leader to notice.
+		"LockDelay": "0s",
+	})
+	if err != nil {
+		return "", err
+	}
+	var resp struct{ ID string }
+	if _, err := c.do(ctx, http.MethodPut, "/v1/session/create", nil, bytes.NewReader(body), &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (c *ConsulCoordinator) RenewSession(ctx context.Context, session string) error {
+	status, err := c.do(ctx, http.MethodPut, "/v1/session/renew/"+url.PathEscape(session), nil, nil, nil)
+	if status == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrSessionExpired, session)
+	}
+	return err
+}
+
+func (c *ConsulCoordinator) DestroySession(ctx context.Context, session string) error {
+	_, err := c.do(ctx, http.MethodPut, "/v1/session/destroy/"+url.PathEscape(session), nil, nil, nil)
+	return err
+}
+
+// put stores the value at the key with the given query parameters, returning whether it was stored.
+func (c *ConsulCoordinator) put(ctx context.Context, key string, value []byte, query url.Values) (bool, error) {
+	var ok bool
+	if _, err := c.do(ctx, http.MethodPut, "/v1/kv/"+key, query, bytes.NewReader(value), &ok); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+func (c *ConsulCoordinator) Acquire(ctx context.Context, key string, session string, value []byte) (bool, error) {
+	return c.put(ctx, key, value, url.Values{"acquire": {session}})
+}
+
+func (c *ConsulCoordinator) Release(ctx context.Context, key string, session string) error {
+	// Releasing a consul lock keeps the key, so delete it only if still locked by the session.
+	pair, err := c.Get(ctx, key)
+	if err != nil || pair == nil || pair.Session != session {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodDelete, "/v1/kv/"+key, url.Values{"cas": {strconv.FormatUint(pair.Index, 10)}}, nil, nil)
+	return err
+}
+
+func (c *ConsulCoordinator) Get(ctx context.Context, key string) (*KVPair, error) {
+	var pairs []consulKVPair
+	status, err := c.do(ctx, http.MethodGet, "/v1/kv/"+key, url.Values{"consistent": {""}}, nil, &pairs)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	return &KVPair{Value: pairs[0].Value, Index: pairs[0].ModifyIndex, Session: pairs[0].Session}, nil
+}
+
+func (c *ConsulCoordinator) CompareAndSwap(ctx context.Context, key string, value []byte, index uint64) (bool, error) {
+	return c.put(ctx, key, value, url.Values{"cas": {strconv.FormatUint(index, 10)}})
+}
+
+func (c *ConsulCoordinator) PutIfHolder(ctx context.Context, key string, value []byte, lockKey string, session string) (bool, error) {
+	body, err := json.Marshal([]consulTxnOp{
+		{KV: consulTxnKV{Verb: "check-session", Key: lockKey, Session: session}},
+		{KV: consulTxnKV{Verb: "set", Key: key, Value: value}},
+	})
+	if err != nil {
+		return false, err
+	}
+	status, err := c.do(ctx, http.MethodPut, "/v1/txn", nil, bytes.NewReader(body), nil)
+	if status == http.StatusConflict {
+		// The transaction was rolled back, as the lock is not held by the session
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *ConsulCoordinator) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/v1/kv/"+key, nil, nil, nil)
+	return err
+}
@@ -0,0 +1,61 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulCoordinator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/kv/prefix/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/kv/prefix/leader":
+			require.True(t, r.URL.Query().Has("consistent"))
+			_, _ = w.Write([]byte(`[{"Key":"prefix/leader","Value":"dmFsdWU=","ModifyIndex":12,"Session":"abc"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/kv/prefix/leader":
+			require.Equal(t, "abc", r.URL.Query().Get("acquire"))
+			body, _ := io.ReadAll(r.Body)
+			require.Equal(t, "value", string(body))
+			_, _ = w.Write([]byte("true"))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/session/renew/expired":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/txn":
+			var ops []consulTxnOp
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ops))
+			require.Equal(t, consulTxnKV{Verb: "check-session", Key: "prefix/leader", Session: "other"}, ops[0].KV)
+			require.Equal(t, consulTxnKV{Verb: "set", Key: "prefix/unsafe", Value: []byte("payload")}, ops[1].KV)
+			w.WriteHeader(http.StatusConflict)
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	coord := NewConsulCoordinator(srv.URL + "/")
+	ctx := context.Background()
+
+	pair, err := coord.Get(ctx, "prefix/missing")
+	require.NoError(t, err)
+	require.Nil(t, pair)
+
+	pair, err = coord.Get(ctx, "prefix/leader")
+	require.NoError(t, err)
+	require.Equal(t, &KVPair{Value: []byte("value"), Index: 12, Session: "abc"}, pair)
+
+	acquired, err := coord.Acquire(ctx, "prefix/leader", "abc", []byte("value"))
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	require.ErrorIs(t, coord.RenewSession(ctx, "expired"), ErrSessionExpired)
+
+	stored, err := coord.PutIfHolder(ctx, "prefix/unsafe", []byte("payload"), "prefix/leader", "other")
+	require.NoError(t, err)
+	require.False(t, stored)
+}
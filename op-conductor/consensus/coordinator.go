@@ -0,0 +1,45 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSessionExpired is returned by a Coordinator when the session does not exist anymore.
+var ErrSessionExpired = errors.New("session expired")
+
+// KVPair is a key-value pair stored in a Coordinator.
+type KVPair struct {
+	Value []byte
+	// Index is the modification index of the pair, used for compare-and-swap.
+	Index uint64
+	// Session is the session holding the lock of the pair, if any.
+	Session string
+}
+
+// Coordinator is the client of an external coordination service, such as etcd or consul,
+// that provides the primitives to implement leader election and strongly consistent storage.
+type Coordinator interface {
+	// CreateSession creates a session that expires if it is not renewed within the ttl in seconds.
+	// Locks held by the session are released and their keys deleted when the session expires.
+	CreateSession(ctx context.Context, ttl uint64) (string, error)
+	// RenewSession renews the session, returning ErrSessionExpired if the session does not exist anymore.
+	RenewSession(ctx context.Context, session string) error
+	// DestroySession destroys the session, releasing all its locks.
+	DestroySession(ctx context.Context, session string) error
+
+	// Acquire stores the value at the key locked by the session, if the key does not exist.
+	Acquire(ctx context.Context, key string, session string, value []byte) (bool, error)
+	// Release deletes the key if it is locked by the session.
+	Release(ctx context.Context, key string, session string) error
+
+	// Get returns the pair stored at the key, or nil if the key does not exist.
+	Get(ctx context.Context, key string) (*KVPair, error)
+	// CompareAndSwap stores the value at the key if the pair was not modified since the index.
+	// An index of zero only stores the value if the key does not exist.
+	CompareAndSwap(ctx context.Context, key string, value []byte, index uint64) (bool, error)
+	// PutIfHolder stores the value at the key if lockKey is locked by the session.
+	PutIfHolder(ctx context.Context, key string, value []byte, lockKey string, session string) (bool, error)
+	// Delete deletes the key.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,226 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var _ Coordinator = (*EtcdCoordinator)(nil)
+
+// EtcdCoordinator implements Coordinator using the JSON gateway of the etcd v3 API.
+// Sessions are etcd leases, and a key is locked by a session if it is attached to its lease.
+type EtcdCoordinator struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewEtcdCoordinator creates a new EtcdCoordinator for the etcd server at the endpoint, e.g. http://127.0.0.1:2379.
+func NewEtcdCoordinator(endpoint string) *EtcdCoordinator {
+	return &EtcdCoordinator{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// etcdInt is an int64 of the etcd JSON gateway, which is encoded as a string.
+type etcdInt int64
+
+func (i etcdInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+func (i *etcdInt) UnmarshalJSON(data []byte) error {
+	v, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = etcdInt(v)
+	return nil
+}
+
+type etcdKeyValue struct {
+	Key         []byte  `json:"key"`
+	Value       []byte  `json:"value"`
+	ModRevision etcdInt `json:"mod_revision"`
+	Lease       etcdInt `json:"lease"`
+}
+
+type etcdCompare struct {
+	Key            []byte   `json:"key"`
+	Target         string   `json:"target"`
+	Result         string   `json:"result"`
+	CreateRevision *etcdInt `json:"create_revision,omitempty"`
+	ModRevision    *etcdInt `json:"mod_revision,omitempty"`
+	Lease          *etcdInt `json:"lease,omitempty"`
+}
+
+type etcdPutRequest struct {
+	Key   []byte  `json:"key"`
+	Value []byte  `json:"value"`
+	Lease etcdInt `json:"lease,omitempty"`
+}
+
+type etcdDeleteRangeRequest struct {
+	Key []byte `json:"key"`
+}
+
+type etcdRequestOp struct {
+	RequestPut         *etcdPutRequest         `json:"request_put,omitempty"`
+	RequestDeleteRange *etcdDeleteRangeRequest `json:"request_delete_range,omitempty"`
+}
+
+func (c *EtcdCoordinator) do(ctx context.Context, path string, in any, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("etcd %v: %v: %s", path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("etcd %v: failed to decode response: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func parseLease(session string) (etcdInt, error) {
+	id, err := strconv.ParseInt(session, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etcd lease %q: %w", session, err)
+	}
+	return etcdInt(id), nil
+}
+
+// txn applies the operation if the comparison succeeds, returning whether it succeeded.
+func (c *EtcdCoordinator) txn(ctx context.Context, cmp etcdCompare, op etcdRequestOp) (bool, error) {
+	var resp struct {
+		Succeeded bool `json:"succeeded"`
+	}
+	req := map[string]any{
+		"compare": []etcdCompare{cmp},
+		"success": []etcdRequestOp{op},
+	}
+	if err := c.do(ctx, "/v3/kv/txn", req, &resp); err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (c *EtcdCoordinator) CreateSession(ctx context.Context, ttl uint64) (string, error) {
+	var resp struct {
+		ID etcdInt `json:"ID"`
+	}
+	if err := c.do(ctx, "/v3/lease/grant", map[string]etcdInt{"TTL": etcdInt(ttl)}, &resp); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(int64(resp.ID), 10), nil
+}
+
+func (c *EtcdCoordinator) RenewSession(ctx context.Context, session string) error {
+	lease, err := parseLease(session)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Result struct {
+			TTL etcdInt `json:"TTL"`
+		} `json:"result"`
+	}
+	if err := c.do(ctx, "/v3/lease/keepalive", map[string]etcdInt{"ID": lease}, &resp); err != nil {
+		return err
+	}
+	if resp.Result.TTL <= 0 {
+		return fmt.Errorf("%w: %v", ErrSessionExpired, session)
+	}
+	return nil
+}
+
+func (c *EtcdCoordinator) DestroySession(ctx context.Context, session string) error {
+	lease, err := parseLease(session)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, "/v3/lease/revoke", map[string]etcdInt{"ID": lease}, nil)
+}
+
+func (c *EtcdCoordinator) Acquire(ctx context.Context, key string, session string, value []byte) (bool, error) {
+	lease, err := parseLease(session)
+	if err != nil {
+		return false, err
+	}
+	var absent etcdInt
+	return c.txn(ctx,
+		etcdCompare{Key: []byte(key), Target: "CREATE", Result: "EQUAL", CreateRevision: &absent},
+		etcdRequestOp{RequestPut: &etcdPutRequest{Key: []byte(key), Value: value, Lease: lease}})
+}
+
+func (c *EtcdCoordinator) Release(ctx context.Context, key string, session string) error {
+	lease, err := parseLease(session)
+	if err != nil {
+		return err
+	}
+	_, err = c.txn(ctx,
+		etcdCompare{Key: []byte(key), Target: "LEASE", Result: "EQUAL", Lease: &lease},
+		etcdRequestOp{RequestDeleteRange: &etcdDeleteRangeRequest{Key: []byte(key)}})
+	return err
+}
+
+func (c *EtcdCoordinator) Get(ctx context.Context, key string) (*KVPair, error) {
+	var resp struct {
+		Kvs []etcdKeyValue `json:"kvs"`
+	}
+	if err := c.do(ctx, "/v3/kv/range", map[string][]byte{"key": []byte(key)}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	kv := resp.Kvs[0]
+	pair := &KVPair{Value: kv.Value, Index: uint64(kv.ModRevision)}
+	if kv.Lease != 0 {
+		pair.Session = strconv.FormatInt(int64(kv.Lease), 10)
+	}
+	return pair, nil
+}
+
+func (c *EtcdCoordinator) CompareAndSwap(ctx context.Context, key string, value []byte, index uint64) (bool, error) {
+	rev := etcdInt(index)
+	cmp := etcdCompare{Key: []byte(key), Target: "MOD", Result: "EQUAL", ModRevision: &rev}
+	if index == 0 {
+		cmp = etcdCompare{Key: []byte(key), Target: "CREATE", Result: "EQUAL", CreateRevision: &rev}
+	}
+	return c.txn(ctx, cmp, etcdRequestOp{RequestPut: &etcdPutRequest{Key: []byte(key), Value: value}})
+}
+
+func (c *EtcdCoordinator) PutIfHolder(ctx context.Context, key string, value []byte, lockKey string, session string) (bool, error) {
+	lease, err := parseLease(session)
+	if err != nil {
+		return false, err
+	}
+	return c.txn(ctx,
+		etcdCompare{Key: []byte(lockKey), Target: "LEASE", Result: "EQUAL", Lease: &lease},
+		etcdRequestOp{RequestPut: &etcdPutRequest{Key: []byte(key), Value: value}})
+}
+
+func (c *EtcdCoordinator) Delete(ctx context.Context, key string) error {
+	return c.do(ctx, "/v3/kv/deleterange", map[string][]byte{"key": []byte(key)}, nil)
+}
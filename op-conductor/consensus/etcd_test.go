@@ -0,0 +1,56 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtcdCoordinator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch r.URL.Path {
+		case "/v3/lease/grant":
+			require.Equal(t, map[string]any{"TTL": "10"}, req)
+			_, _ = w.Write([]byte(`{"ID":"7587","TTL":"10"}`))
+		case "/v3/lease/keepalive":
+			require.Equal(t, map[string]any{"ID": "7587"}, req)
+			_, _ = w.Write([]byte(`{"result":{"ID":"7587"}}`))
+		case "/v3/kv/range":
+			require.Equal(t, map[string]any{"key": "cHJlZml4L2xlYWRlcg=="}, req)
+			_, _ = w.Write([]byte(`{"kvs":[{"key":"cHJlZml4L2xlYWRlcg==","value":"dmFsdWU=","mod_revision":"12","lease":"7587"}]}`))
+		case "/v3/kv/txn":
+			require.Equal(t, map[string]any{
+				"compare": []any{map[string]any{"key": "cHJlZml4L2xlYWRlcg==", "target": "CREATE", "result": "EQUAL", "create_revision": "0"}},
+				"success": []any{map[string]any{"request_put": map[string]any{"key": "cHJlZml4L2xlYWRlcg==", "value": "dmFsdWU=", "lease": "7587"}}},
+			}, req)
+			_, _ = w.Write([]byte(`{"succeeded":true}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	coord := NewEtcdCoordinator(srv.URL)
+	ctx := context.Background()
+
+	session, err := coord.CreateSession(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, "7587", session)
+
+	// A lease without TTL has expired
+	require.ErrorIs(t, coord.RenewSession(ctx, session), ErrSessionExpired)
+
+	acquired, err := coord.Acquire(ctx, "prefix/leader", session, []byte("value"))
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	pair, err := coord.Get(ctx, "prefix/leader")
+	require.NoError(t, err)
+	require.Equal(t, &KVPair{Value: []byte("value"), Index: 12, Session: "7587"}, pair)
+}
@@ -0,0 +1,588 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/pkg/errors"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrNotLeader          = errors.New("not the leader")
+	ErrMembershipChanged  = errors.New("cluster membership changed")
+	ErrUnknownServer      = errors.New("unknown server")
+	ErrNoTransferTarget   = errors.New("no other voter to transfer leadership to")
+	ErrCommitNotPermitted = errors.New("leadership lost before the payload was committed")
+)
+
+const (
+	leaderKey   = "leader"
+	membersKey  = "members"
+	transferKey = "transfer"
+	unsafeKey   = "unsafe"
+)
+
+var _ Consensus = (*ExternalConsensus)(nil)
+
+type ExternalConsensusConfig struct {
+	ServerID   string
+	ServerAddr string
+	Bootstrap  bool
+	// KeyPrefix is the prefix of the keys the cluster state is stored at in the coordination service.
+	KeyPrefix string
+	// SessionTTL is the time after which the leadership of a server that stopped renewing its session expires.
+	SessionTTL time.Duration
+}
+
+// leadershipTransfer is the target of a leadership transfer in progress.
+type leadershipTransfer struct {
+	ID     string `json:"id"`
+	Expiry int64  `json:"expiry"`
+}
+
+// ExternalConsensus implements Consensus using an external coordination service, such as etcd or consul.
+// The leader is the server holding the lock of the leader key, with a session that it renews periodically.
+// The cluster membership and the latest unsafe payload are stored in the coordination service, and the unsafe payload
+// is only stored if the server still holds the leader lock.
+type ExternalConsensus struct {
+	log   log.Logger
+	cfg   ExternalConsensusConfig
+	coord Coordinator
+
+	leaderCh chan bool
+
+	// campaignMtx serializes leader elections and leadership transfers.
+	campaignMtx sync.Mutex
+
+	mtx     sync.Mutex
+	session string
+	leader  bool
+	// campaignAfter delays the next leader election of this server, to let another server take over leadership.
+	campaignAfter time.Time
+	// renewedAt is when the last successful renewal of the session was requested. Once it is older than the session TTL,
+	// the coordination service may have expired the session, and another server may have acquired leadership.
+	renewedAt time.Time
+	// leaderInfo is the leader as of the last campaign, such that LeaderWithID doesn't query the coordination service.
+	leaderInfo ServerInfo
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewExternalConsensus creates a new ExternalConsensus instance and starts campaigning for leadership.
+func NewExternalConsensus(log log.Logger, cfg *ExternalConsensusConfig, coord Coordinator) (*ExternalConsensus, error) {
+	if cfg.SessionTTL < time.Second {
+		return nil, fmt.Errorf("session ttl must be at least 1s, got %v", cfg.SessionTTL)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ec := &ExternalConsensus{
+		log:      log,
+		cfg:      *cfg,
+		coord:    coord,
+		leaderCh: make(chan bool, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	if cfg.Bootstrap {
+		if err := ec.bootstrap(); err != nil {
+			cancel()
+			return nil, errors.Wrap(err, "failed to bootstrap cluster")
+		}
+	}
+
+	ec.wg.Add(1)
+	go ec.campaignLoop()
+	return ec, nil
+}
+
+func (ec *ExternalConsensus) key(name string) string {
+	return ec.cfg.KeyPrefix + "/" + name
+}
+
+// bootstrap adds this server as the only voter of the cluster, if the cluster has no members yet.
+func (ec *ExternalConsensus) bootstrap() error {
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	membership, index, err := ec.membership(ctx)
+	if err != nil {
+		return err
+	}
+	if len(membership.Servers) > 0 {
+		ec.log.Info("cluster already bootstrapped", "servers", len(membership.Servers))
+		return nil
+	}
+	membership.Servers = []ServerInfo{{ID: ec.cfg.ServerID, Addr: ec.cfg.ServerAddr, Suffrage: Voter}}
+	return ec.storeMembership(ctx, membership, index)
+}
+
+func (ec *ExternalConsensus) campaignLoop() {
+	defer ec.wg.Done()
+	ticker := time.NewTicker(ec.cfg.SessionTTL / 3)
+	defer ticker.Stop()
+	for {
+		if err := ec.campaign(); err != nil {
+			ec.log.Warn("failed to update leadership", "err", err)
+		}
+		select {
+		case <-ec.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign renews the session of this server, and acquires or releases leadership according to the cluster state.
+func (ec *ExternalConsensus) campaign() error {
+	ec.campaignMtx.Lock()
+	defer ec.campaignMtx.Unlock()
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+
+	// A renewal that takes longer than the session TTL can't keep the session anyway.
+	renewCtx, renewCancel := context.WithTimeout(ctx, ec.cfg.SessionTTL)
+	session, err := ec.renewSession(renewCtx)
+	renewCancel()
+	if err != nil {
+		ec.expireLeadership()
+		return err
+	}
+
+	membership, _, err := ec.membership(ctx)
+	if err != nil {
+		return err
+	}
+	voter := slices.ContainsFunc(membership.Servers, func(s ServerInfo) bool {
+		return s.ID == ec.cfg.ServerID && s.Suffrage == Voter
+	})
+
+	holder, err := ec.coord.Get(ctx, ec.key(leaderKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to get leader")
+	}
+	ec.setLeaderInfo(holder)
+	if holder != nil && holder.Session == session {
+		if !voter {
+			ec.log.Info("stepping down as leader, no longer a voter")
+			return ec.stepDown(ctx, session)
+		}
+		ec.setLeader(true)
+		return nil
+	}
+	ec.setLeader(false)
+	if holder != nil || !voter || !ec.mayCampaign(ctx) {
+		return nil
+	}
+
+	info, err := json.Marshal(ServerInfo{ID: ec.cfg.ServerID, Addr: ec.cfg.ServerAddr, Suffrage: Voter})
+	if err != nil {
+		return err
+	}
+	acquired, err := ec.coord.Acquire(ctx, ec.key(leaderKey), session, info)
+	if err != nil {
+		return errors.Wrap(err, "failed to acquire leadership")
+	}
+	if acquired {
+		ec.log.Info("acquired leadership")
+		ec.mtx.Lock()
+		ec.leaderInfo = ServerInfo{ID: ec.cfg.ServerID, Addr: ec.cfg.ServerAddr, Suffrage: Voter}
+		ec.mtx.Unlock()
+		if err := ec.coord.Delete(ctx, ec.key(transferKey)); err != nil {
+			ec.log.Warn("failed to clear leadership transfer", "err", err)
+		}
+		ec.setLeader(true)
+	}
+	return nil
+}
+
+// renewSession renews the session of this server, or creates a new session if it expired.
+func (ec *ExternalConsensus) renewSession(ctx context.Context) (string, error) {
+	ec.mtx.Lock()
+	session := ec.session
+	ec.mtx.Unlock()
+
+	// The session is renewed from when the request is sent at the latest.
+	requested := time.Now()
+	if session != "" {
+		err := ec.coord.RenewSession(ctx, session)
+		if err == nil {
+			ec.mtx.Lock()
+			ec.renewedAt = requested
+			ec.mtx.Unlock()
+			return session, nil
+		}
+		if !errors.Is(err, ErrSessionExpired) {
+			return "", errors.Wrap(err, "failed to renew session")
+		}
+		ec.log.Warn("session expired", "session", session)
+		ec.setLeader(false)
+	}
+
+	session, err := ec.coord.CreateSession(ctx, uint64(ec.cfg.SessionTTL/time.Second))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create session")
+	}
+	ec.mtx.Lock()
+	ec.session = session
+	ec.renewedAt = requested
+	ec.mtx.Unlock()
+	return session, nil
+}
+
+// expireLeadership steps down if the session was not renewed within the session TTL,
+// as the coordination service may have expired it without this server being able to find out.
+func (ec *ExternalConsensus) expireLeadership() {
+	ec.mtx.Lock()
+	expired := ec.leader && time.Since(ec.renewedAt) >= ec.cfg.SessionTTL
+	renewedAt := ec.renewedAt
+	ec.mtx.Unlock()
+	if expired {
+		ec.log.Warn("stepping down as leader, session was not renewed within its ttl", "renewedAt", renewedAt)
+		ec.setLeader(false)
+	}
+}
+
+// setLeaderInfo caches the leader stored in the leader key, or no leader if the key is not held.
+func (ec *ExternalConsensus) setLeaderInfo(holder *KVPair) {
+	var info ServerInfo
+	if holder != nil {
+		if err := json.Unmarshal(holder.Value, &info); err != nil {
+			ec.log.Error("failed to decode leader", "err", err)
+			info = ServerInfo{}
+		}
+	}
+	ec.mtx.Lock()
+	ec.leaderInfo = info
+	ec.mtx.Unlock()
+}
+
+// mayCampaign returns whether this server may acquire leadership, which is not the case for a short time after
+// it transferred leadership, or while leadership is transferred to another server.
+func (ec *ExternalConsensus) mayCampaign(ctx context.Context) bool {
+	ec.mtx.Lock()
+	campaignAfter := ec.campaignAfter
+	ec.mtx.Unlock()
+	if time.Now().Before(campaignAfter) {
+		return false
+	}
+	pair, err := ec.coord.Get(ctx, ec.key(transferKey))
+	if err != nil || pair == nil {
+		return err == nil
+	}
+	var transfer leadershipTransfer
+	if err := json.Unmarshal(pair.Value, &transfer); err != nil {
+		ec.log.Warn("ignoring invalid leadership transfer", "err", err)
+		return true
+	}
+	return transfer.ID == ec.cfg.ServerID || time.Now().Unix() > transfer.Expiry
+}
+
+func (ec *ExternalConsensus) stepDown(ctx context.Context, session string) error {
+	ec.mtx.Lock()
+	ec.campaignAfter = time.Now().Add(ec.cfg.SessionTTL)
+	ec.leaderInfo = ServerInfo{}
+	ec.mtx.Unlock()
+	ec.setLeader(false)
+	if err := ec.coord.Release(ctx, ec.key(leaderKey), session); err != nil {
+		return errors.Wrap(err, "failed to release leadership")
+	}
+	return nil
+}
+
+// setLeader updates the leadership status, notifying LeaderCh if it changed.
+// Only the latest status is kept if the channel is not read in time.
+func (ec *ExternalConsensus) setLeader(leader bool) {
+	ec.mtx.Lock()
+	defer ec.mtx.Unlock()
+	if ec.leader == leader {
+		return
+	}
+	ec.leader = leader
+	select {
+	case <-ec.leaderCh:
+	default:
+	}
+	ec.leaderCh <- leader
+}
+
+func (ec *ExternalConsensus) membership(ctx context.Context) (*ClusterMembership, uint64, error) {
+	pair, err := ec.coord.Get(ctx, ec.key(membersKey))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get cluster membership")
+	}
+	if pair == nil {
+		return &ClusterMembership{}, 0, nil
+	}
+	var membership ClusterMembership
+	if err := json.Unmarshal(pair.Value, &membership); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to decode cluster membership")
+	}
+	return &membership, pair.Index, nil
+}
+
+func (ec *ExternalConsensus) storeMembership(ctx context.Context, membership *ClusterMembership, index uint64) error {
+	membership.Version++
+	data, err := json.Marshal(membership)
+	if err != nil {
+		return err
+	}
+	stored, err := ec.coord.CompareAndSwap(ctx, ec.key(membersKey), data, index)
+	if err != nil {
+		return errors.Wrap(err, "failed to store cluster membership")
+	}
+	if !stored {
+		return ErrMembershipChanged
+	}
+	return nil
+}
+
+// updateMembership applies the update to the cluster membership, if the membership is at the expected version.
+func (ec *ExternalConsensus) updateMembership(version uint64, update func(m *ClusterMembership) error) error {
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	membership, index, err := ec.membership(ctx)
+	if err != nil {
+		return err
+	}
+	if version != 0 && version != membership.Version {
+		return fmt.Errorf("%w: expected version %d, current version %d", ErrMembershipChanged, version, membership.Version)
+	}
+	if err := update(membership); err != nil {
+		return err
+	}
+	return ec.storeMembership(ctx, membership, index)
+}
+
+func (ec *ExternalConsensus) addServer(id, addr string, suffrage ServerSuffrage, version uint64) error {
+	err := ec.updateMembership(version, func(m *ClusterMembership) error {
+		for i, s := range m.Servers {
+			if s.ID == id {
+				m.Servers[i] = ServerInfo{ID: id, Addr: addr, Suffrage: suffrage}
+				return nil
+			}
+		}
+		m.Servers = append(m.Servers, ServerInfo{ID: id, Addr: addr, Suffrage: suffrage})
+		return nil
+	})
+	if err != nil {
+		ec.log.Error("failed to add server", "id", id, "addr", addr, "suffrage", suffrage, "version", version, "err", err)
+	}
+	return err
+}
+
+// AddVoter implements Consensus, it tries to add a voting member into the cluster.
+func (ec *ExternalConsensus) AddVoter(id, addr string, version uint64) error {
+	return ec.addServer(id, addr, Voter, version)
+}
+
+// AddNonVoter implements Consensus, it tries to add a non-voting member into the cluster.
+func (ec *ExternalConsensus) AddNonVoter(id, addr string, version uint64) error {
+	return ec.addServer(id, addr, Nonvoter, version)
+}
+
+// DemoteVoter implements Consensus, it tries to demote a voting member into a non-voting member in the cluster.
+// A demoted leader steps down when it next renews its session.
+func (ec *ExternalConsensus) DemoteVoter(id string, version uint64) error {
+	err := ec.updateMembership(version, func(m *ClusterMembership) error {
+		for i, s := range m.Servers {
+			if s.ID == id {
+				m.Servers[i].Suffrage = Nonvoter
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %v", ErrUnknownServer, id)
+	})
+	if err != nil {
+		ec.log.Error("failed to demote voter", "id", id, "version", version, "err", err)
+	}
+	return err
+}
+
+// RemoveServer implements Consensus, it tries to remove a member (both voter or non-voter) from the cluster.
+// A removed leader steps down when it next renews its session.
+func (ec *ExternalConsensus) RemoveServer(id string, version uint64) error {
+	err := ec.updateMembership(version, func(m *ClusterMembership) error {
+		m.Servers = slices.DeleteFunc(m.Servers, func(s ServerInfo) bool { return s.ID == id })
+		return nil
+	})
+	if err != nil {
+		ec.log.Error("failed to remove server", "id", id, "version", version, "err", err)
+	}
+	return err
+}
+
+// LeaderCh implements Consensus, it returns a channel that will be notified when leadership status changes (true = leader, false = follower).
+func (ec *ExternalConsensus) LeaderCh() <-chan bool {
+	return ec.leaderCh
+}
+
+// Leader implements Consensus, it returns true if it is the leader of the cluster.
+// A leader whose session was not renewed within the session TTL is not the leader anymore,
+// even before the campaign loop notices and notifies LeaderCh.
+func (ec *ExternalConsensus) Leader() bool {
+	ec.mtx.Lock()
+	defer ec.mtx.Unlock()
+	return ec.leader && time.Since(ec.renewedAt) < ec.cfg.SessionTTL
+}
+
+// LeaderWithID implements Consensus, it returns the leader's server ID and address.
+// Empty server info is returned if there is no leader.
+// The leader is cached by the campaign loop, so it may be up to a third of the session TTL old,
+// and doesn't block on the coordination service.
+func (ec *ExternalConsensus) LeaderWithID() *ServerInfo {
+	ec.mtx.Lock()
+	defer ec.mtx.Unlock()
+	info := ec.leaderInfo
+	return &info
+}
+
+// ServerID implements Consensus, it returns the server ID of the current server.
+func (ec *ExternalConsensus) ServerID() string {
+	return ec.cfg.ServerID
+}
+
+// TransferLeader implements Consensus, it triggers leadership transfer to another member in the cluster.
+func (ec *ExternalConsensus) TransferLeader() error {
+	if !ec.Leader() {
+		return nil
+	}
+	ec.campaignMtx.Lock()
+	defer ec.campaignMtx.Unlock()
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	membership, _, err := ec.membership(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(membership.Servers, func(s ServerInfo) bool {
+		return s.ID != ec.cfg.ServerID && s.Suffrage == Voter
+	}) {
+		return ErrNoTransferTarget
+	}
+	if err := ec.stepDown(ctx, ec.currentSession()); err != nil {
+		ec.log.Error("failed to transfer leadership", "err", err)
+		return err
+	}
+	return nil
+}
+
+// TransferLeaderTo implements Consensus, it triggers leadership transfer to a specific member in the cluster.
+// Other servers don't campaign for leadership until the target acquires it, or the transfer times out.
+func (ec *ExternalConsensus) TransferLeaderTo(id string, addr string) error {
+	if !ec.Leader() {
+		return ErrNotLeader
+	}
+	ec.campaignMtx.Lock()
+	defer ec.campaignMtx.Unlock()
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	membership, _, err := ec.membership(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(membership.Servers, func(s ServerInfo) bool {
+		return s.ID == id && s.Addr == addr && s.Suffrage == Voter
+	}) {
+		return fmt.Errorf("%w: %v is not a voter at %v", ErrUnknownServer, id, addr)
+	}
+	transfer, err := json.Marshal(leadershipTransfer{ID: id, Expiry: time.Now().Add(2 * ec.cfg.SessionTTL).Unix()})
+	if err != nil {
+		return err
+	}
+	session := ec.currentSession()
+	if ok, err := ec.coord.PutIfHolder(ctx, ec.key(transferKey), transfer, ec.key(leaderKey), session); err != nil {
+		return errors.Wrap(err, "failed to store leadership transfer")
+	} else if !ok {
+		return ErrNotLeader
+	}
+	if err := ec.stepDown(ctx, session); err != nil {
+		ec.log.Error("failed to transfer leadership to server", "id", id, "addr", addr, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (ec *ExternalConsensus) currentSession() string {
+	ec.mtx.Lock()
+	defer ec.mtx.Unlock()
+	return ec.session
+}
+
+// Shutdown implements Consensus, it stops campaigning and releases leadership.
+func (ec *ExternalConsensus) Shutdown() error {
+	ec.cancel()
+	ec.wg.Wait()
+	ec.setLeader(false)
+
+	session := ec.currentSession()
+	if session == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	if err := ec.coord.DestroySession(ctx, session); err != nil {
+		ec.log.Error("failed to destroy session", "err", err)
+		return err
+	}
+	return nil
+}
+
+// CommitUnsafePayload implements Consensus, it stores the latest unsafe payload if this server is still the leader.
+func (ec *ExternalConsensus) CommitUnsafePayload(payload *eth.ExecutionPayloadEnvelope) error {
+	ec.log.Debug("committing unsafe payload", "number", uint64(payload.ExecutionPayload.BlockNumber), "hash", payload.ExecutionPayload.BlockHash.Hex())
+	if !ec.Leader() {
+		return ErrNotLeader
+	}
+
+	var buf bytes.Buffer
+	if _, err := payload.MarshalSSZ(&buf); err != nil {
+		return errors.Wrap(err, "failed to marshal payload envelope")
+	}
+
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	ok, err := ec.coord.PutIfHolder(ctx, ec.key(unsafeKey), buf.Bytes(), ec.key(leaderKey), ec.currentSession())
+	if err != nil {
+		return errors.Wrap(err, "failed to store payload envelope")
+	}
+	if !ok {
+		return ErrCommitNotPermitted
+	}
+	ec.log.Debug("unsafe payload committed", "number", uint64(payload.ExecutionPayload.BlockNumber), "hash", payload.ExecutionPayload.BlockHash.Hex())
+	return nil
+}
+
+// LatestUnsafePayload implements Consensus, it returns the latest unsafe payload stored in the coordination service.
+func (ec *ExternalConsensus) LatestUnsafePayload() (*eth.ExecutionPayloadEnvelope, error) {
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	pair, err := ec.coord.Get(ctx, ec.key(unsafeKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get payload envelope")
+	}
+	if pair == nil {
+		return nil, nil
+	}
+	payload := &eth.ExecutionPayloadEnvelope{}
+	if err := payload.UnmarshalSSZ(uint32(len(pair.Value)), bytes.NewReader(pair.Value)); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal payload envelope")
+	}
+	return payload, nil
+}
+
+// ClusterMembership implements Consensus, it returns the current cluster membership configuration.
+func (ec *ExternalConsensus) ClusterMembership() (*ClusterMembership, error) {
+	ctx, cancel := context.WithTimeout(ec.ctx, defaultTimeout)
+	defer cancel()
+	membership, _, err := ec.membership(ctx)
+	return membership, err
+}
@@ -0,0 +1,279 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+// memCoordinator is an in-memory Coordinator. Sessions only expire when expired explicitly.
+type memCoordinator struct {
+	mtx         sync.Mutex
+	index       uint64
+	nextSession int
+	sessions    map[string]bool
+	kv          map[string]KVPair
+	// unavailable fails session renewals and reads, as if the coordination service could not be reached.
+	unavailable bool
+}
+
+var errUnavailable = errors.New("coordination service unavailable")
+
+func (m *memCoordinator) setUnavailable(unavailable bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.unavailable = unavailable
+}
+
+var _ Coordinator = (*memCoordinator)(nil)
+
+func newMemCoordinator() *memCoordinator {
+	return &memCoordinator{sessions: make(map[string]bool), kv: make(map[string]KVPair)}
+}
+
+func (m *memCoordinator) put(key string, value []byte, session string) {
+	m.index++
+	m.kv[key] = KVPair{Value: value, Index: m.index, Session: session}
+}
+
+func (m *memCoordinator) CreateSession(_ context.Context, _ uint64) (string, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.nextSession++
+	session := fmt.Sprintf("session-%d", m.nextSession)
+	m.sessions[session] = true
+	return session, nil
+}
+
+func (m *memCoordinator) RenewSession(_ context.Context, session string) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.unavailable {
+		return errUnavailable
+	}
+	if !m.sessions[session] {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+func (m *memCoordinator) DestroySession(_ context.Context, session string) error {
+	m.expire(session)
+	return nil
+}
+
+// expire invalidates the session, deleting the keys it locked.
+func (m *memCoordinator) expire(session string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.sessions, session)
+	for key, pair := range m.kv {
+		if pair.Session == session {
+			delete(m.kv, key)
+		}
+	}
+}
+
+func (m *memCoordinator) Acquire(_ context.Context, key string, session string, value []byte) (bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.kv[key]; ok || !m.sessions[session] {
+		return false, nil
+	}
+	m.put(key, value, session)
+	return true, nil
+}
+
+func (m *memCoordinator) Release(_ context.Context, key string, session string) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.kv[key].Session == session {
+		delete(m.kv, key)
+	}
+	return nil
+}
+
+func (m *memCoordinator) Get(_ context.Context, key string) (*KVPair, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.unavailable {
+		return nil, errUnavailable
+	}
+	pair, ok := m.kv[key]
+	if !ok {
+		return nil, nil
+	}
+	return &pair, nil
+}
+
+func (m *memCoordinator) CompareAndSwap(_ context.Context, key string, value []byte, index uint64) (bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.kv[key].Index != index {
+		return false, nil
+	}
+	m.put(key, value, "")
+	return true, nil
+}
+
+func (m *memCoordinator) PutIfHolder(_ context.Context, key string, value []byte, lockKey string, session string) (bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.kv[lockKey].Session != session {
+		return false, nil
+	}
+	m.put(key, value, "")
+	return true, nil
+}
+
+func (m *memCoordinator) Delete(_ context.Context, key string) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.kv, key)
+	return nil
+}
+
+func newTestExternalConsensus(t *testing.T, coord Coordinator, id string, bootstrap bool) *ExternalConsensus {
+	cfg := &ExternalConsensusConfig{
+		ServerID:   id,
+		ServerAddr: id + ":50050",
+		Bootstrap:  bootstrap,
+		KeyPrefix:  "test",
+		SessionTTL: time.Second,
+	}
+	cons, err := NewExternalConsensus(testlog.Logger(t, log.LevelInfo), cfg, coord)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cons.Shutdown() })
+	return cons
+}
+
+func requireLeader(t *testing.T, cons *ExternalConsensus) {
+	require.Eventually(t, cons.Leader, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestExternalConsensusElection(t *testing.T) {
+	coord := newMemCoordinator()
+	a := newTestExternalConsensus(t, coord, "A", true)
+	requireLeader(t, a)
+	require.True(t, <-a.LeaderCh())
+	require.Equal(t, &ServerInfo{ID: "A", Addr: "A:50050", Suffrage: Voter}, a.LeaderWithID())
+
+	membership, err := a.ClusterMembership()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), membership.Version)
+	require.ErrorIs(t, a.AddVoter("B", "B:50050", membership.Version+1), ErrMembershipChanged)
+	require.NoError(t, a.AddVoter("B", "B:50050", membership.Version))
+
+	b := newTestExternalConsensus(t, coord, "B", false)
+	require.Never(t, b.Leader, time.Second, 50*time.Millisecond)
+	require.Equal(t, "A", b.LeaderWithID().ID)
+
+	t.Run("TransferLeaderTo", func(t *testing.T) {
+		require.ErrorIs(t, b.TransferLeaderTo("A", "A:50050"), ErrNotLeader)
+		require.ErrorIs(t, a.TransferLeaderTo("C", "C:50050"), ErrUnknownServer)
+		require.NoError(t, a.TransferLeaderTo("B", "B:50050"))
+		require.False(t, a.Leader())
+		requireLeader(t, b)
+		require.Eventually(t, func() bool { return a.LeaderWithID().ID == "B" }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("SessionExpiry", func(t *testing.T) {
+		expired := b.currentSession()
+		coord.expire(expired)
+		// Either server may acquire leadership with a new session
+		require.Eventually(t, func() bool {
+			pair, err := coord.Get(context.Background(), "test/leader")
+			return err == nil && pair != nil && pair.Session != expired && a.Leader() != b.Leader()
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Demote", func(t *testing.T) {
+		leader, follower := a, b
+		if b.Leader() {
+			leader, follower = b, a
+		}
+		membership, err := a.ClusterMembership()
+		require.NoError(t, err)
+		require.NoError(t, follower.DemoteVoter(leader.ServerID(), membership.Version))
+		require.Eventually(t, func() bool { return !leader.Leader() }, 5*time.Second, 10*time.Millisecond)
+		requireLeader(t, follower)
+		require.ErrorIs(t, follower.TransferLeader(), ErrNoTransferTarget)
+	})
+}
+
+func TestExternalConsensusUnavailable(t *testing.T) {
+	coord := newMemCoordinator()
+	a := newTestExternalConsensus(t, coord, "A", true)
+	requireLeader(t, a)
+	require.True(t, <-a.LeaderCh())
+	leader := &ServerInfo{ID: "A", Addr: "A:50050", Suffrage: Voter}
+
+	// The leader steps down once its session wasn't renewed within the session TTL,
+	// as the coordination service may have expired the session in the meantime.
+	coord.setUnavailable(true)
+	start := time.Now()
+	select {
+	case isLeader := <-a.LeaderCh():
+		require.False(t, isLeader)
+	case <-time.After(5 * time.Second):
+		t.Fatal("leader did not step down")
+	}
+	require.GreaterOrEqual(t, time.Since(start), a.cfg.SessionTTL/2)
+	require.False(t, a.Leader())
+	// The leader is served from the cache, without reaching the coordination service.
+	require.Equal(t, leader, a.LeaderWithID())
+
+	// The leader still holds the session, so it resumes leadership once the coordination service is reachable again.
+	coord.setUnavailable(false)
+	requireLeader(t, a)
+	require.Equal(t, leader, a.LeaderWithID())
+}
+
+func TestExternalConsensusCommit(t *testing.T) {
+	coord := newMemCoordinator()
+	a := newTestExternalConsensus(t, coord, "A", true)
+	require.NoError(t, a.AddNonVoter("B", "B:50050", 0))
+	b := newTestExternalConsensus(t, coord, "B", false)
+	requireLeader(t, a)
+
+	unsafeHead, err := b.LatestUnsafePayload()
+	require.NoError(t, err)
+	require.Nil(t, unsafeHead)
+
+	one := hexutil.Uint64(1)
+	hash := common.HexToHash("0x12345")
+	payload := &eth.ExecutionPayloadEnvelope{
+		ParentBeaconBlockRoot: &hash,
+		ExecutionPayload: &eth.ExecutionPayload{
+			BlockNumber:   2,
+			Timestamp:     hexutil.Uint64(time.Now().Unix()),
+			Transactions:  []eth.Data{},
+			ExtraData:     []byte{},
+			Withdrawals:   &types.Withdrawals{},
+			ExcessBlobGas: &one,
+			BlobGasUsed:   &one,
+		},
+	}
+	require.ErrorIs(t, b.CommitUnsafePayload(payload), ErrNotLeader)
+	require.NoError(t, a.CommitUnsafePayload(payload))
+
+	unsafeHead, err = b.LatestUnsafePayload()
+	require.NoError(t, err)
+	require.Equal(t, payload, unsafeHead)
+
+	// A leader that lost its session can't commit, even before noticing it is not the leader anymore
+	coord.expire(a.currentSession())
+	require.ErrorIs(t, a.CommitUnsafePayload(payload), ErrCommitNotPermitted)
+}
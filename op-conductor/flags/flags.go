@@ -29,6 +29,29 @@ var (
 		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "CONSENSUS_PORT"),
 		Value:   50050,
 	}
+	ConsensusBackend = &cli.StringFlag{
+		Name:    "consensus.backend",
+		Usage:   "Backend for leader election and state storage: raft (embedded raft cluster), etcd or consul",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "CONSENSUS_BACKEND"),
+		Value:   "raft",
+	}
+	ConsensusEndpoint = &cli.StringFlag{
+		Name:    "consensus.endpoint",
+		Usage:   "HTTP endpoint of the etcd or consul coordination service. Required for the etcd and consul backends",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "CONSENSUS_ENDPOINT"),
+	}
+	ConsensusKeyPrefix = &cli.StringFlag{
+		Name:    "consensus.key-prefix",
+		Usage:   "Prefix of the keys the cluster state is stored at in the etcd or consul coordination service",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "CONSENSUS_KEY_PREFIX"),
+		Value:   "op-conductor",
+	}
+	ConsensusSessionTTL = &cli.DurationFlag{
+		Name:    "consensus.session-ttl",
+		Usage:   "Time after which the leadership of an unresponsive leader expires with the etcd or consul backends",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "CONSENSUS_SESSION_TTL"),
+		Value:   10 * time.Second,
+	}
 	RaftBootstrap = &cli.BoolFlag{
 		Name:    "raft.bootstrap",
 		Usage:   "If this node should bootstrap a new raft cluster",
@@ -42,7 +65,7 @@ var (
 	}
 	RaftStorageDir = &cli.StringFlag{
 		Name:    "raft.storage.dir",
-		Usage:   "Directory to store raft data",
+		Usage:   "Directory to store raft data. Required for the raft backend",
 		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "RAFT_STORAGE_DIR"),
 	}
 	RaftSnapshotInterval = &cli.DurationFlag{
@@ -100,12 +123,55 @@ var (
 		Usage:   "Minimum number of peers required to be considered healthy",
 		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "HEALTHCHECK_MIN_PEER_COUNT"),
 	}
+	HealthCheckMaxCrossSafeLag = &cli.Uint64Flag{
+		Name:    "healthcheck.max-cross-safe-lag",
+		Usage:   "Maximum lag of the cross-safe head behind the local-safe head measured in seconds once interop is active, 0 to disable",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "HEALTHCHECK_MAX_CROSS_SAFE_LAG"),
+		Value:   0,
+	}
+	SupervisorRPC = &cli.StringFlag{
+		Name:    "supervisor.rpc",
+		Usage:   "HTTP provider URL for op-supervisor, used to check the interop health of the sequencer",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "SUPERVISOR_RPC"),
+	}
 	Paused = &cli.BoolFlag{
 		Name:    "paused",
 		Usage:   "Whether the conductor is paused",
 		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "PAUSED"),
 		Value:   false,
 	}
+	HandoverTimeout = &cli.DurationFlag{
+		Name: "handover.timeout",
+		Usage: "Time after which a leader that is healthy but fails to start sequencing considers the handover failed, " +
+			"and transfers leadership back to another server. Disabled by default, e.g. 30s to enable",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "HANDOVER_TIMEOUT"),
+	}
+	WarmupInterval = &cli.DurationFlag{
+		Name: "warmup.interval",
+		Usage: "Interval at which a standby sequencer is fed the latest unsafe head in consensus and its execution layer " +
+			"is checked to be synced, to keep it ready for failover. Disabled by default, e.g. 1s to enable",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "WARMUP_INTERVAL"),
+	}
+	WarmupMaxLag = &cli.Uint64Flag{
+		Name:    "warmup.max-lag",
+		Usage:   "Maximum number of blocks the unsafe head of a standby sequencer may be behind consensus to be considered warm",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "WARMUP_MAX_LAG"),
+		Value:   1,
+	}
+	AuditLogPath = &cli.StringFlag{
+		Name: "audit-log.path",
+		Usage: "File to persist the audit trail of pauses and resumes to. " +
+			"Defaults to audit.jsonl in the raft storage directory with the raft backend, otherwise the trail is kept in memory",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "AUDIT_LOG_PATH"),
+	}
+	Observer = &cli.BoolFlag{
+		Name: "observer",
+		Usage: "Run as a read-only observer that mirrors the cluster state and exports metrics about its health, " +
+			"without participating in leader election or controlling the sequencer. It must be added to the cluster as a non-voter, " +
+			"it refuses to start as a voter and transfers leadership away if it is ever elected",
+		EnvVars: opservice.PrefixEnvVar(EnvVarPrefix, "OBSERVER"),
+		Value:   false,
+	}
 	RPCEnableProxy = &cli.BoolFlag{
 		Name:    "rpc.enable-proxy",
 		Usage:   "Enable the RPC proxy to underlying sequencer services",
@@ -118,7 +184,6 @@ var requiredFlags = []cli.Flag{
 	ConsensusAddr,
 	ConsensusPort,
 	RaftServerID,
-	RaftStorageDir,
 	NodeRPC,
 	ExecutionRPC,
 	HealthCheckInterval,
@@ -128,10 +193,22 @@ var requiredFlags = []cli.Flag{
 
 var optionalFlags = []cli.Flag{
 	Paused,
+	AuditLogPath,
+	Observer,
+	HandoverTimeout,
+	WarmupInterval,
+	WarmupMaxLag,
 	RPCEnableProxy,
 	RaftBootstrap,
+	RaftStorageDir,
+	ConsensusBackend,
+	ConsensusEndpoint,
+	ConsensusKeyPrefix,
+	ConsensusSessionTTL,
 	HealthCheckSafeEnabled,
 	HealthCheckSafeInterval,
+	HealthCheckMaxCrossSafeLag,
+	SupervisorRPC,
 	RaftSnapshotInterval,
 	RaftSnapshotThreshold,
 	RaftTrailingLogs,
@@ -12,13 +12,20 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
 var (
-	ErrSequencerNotHealthy     = errors.New("sequencer is not healthy")
-	ErrSequencerConnectionDown = errors.New("cannot connect to sequencer rpc endpoints")
+	ErrSequencerNotHealthy      = errors.New("sequencer is not healthy")
+	ErrSequencerConnectionDown  = errors.New("cannot connect to sequencer rpc endpoints")
+	ErrSupervisorConnectionDown = errors.New("cannot connect to supervisor rpc endpoint")
 )
 
+// SupervisorAPI is the subset of the op-supervisor API used to check the interop health of the sequencer.
+type SupervisorAPI interface {
+	SyncStatus(ctx context.Context) (eth.SupervisorSyncStatus, error)
+}
+
 // HealthMonitor defines the interface for monitoring the health of the sequencer.
 //
 //go:generate mockery --name HealthMonitor --output mocks/ --with-expecter=true
@@ -35,20 +42,24 @@ type HealthMonitor interface {
 // interval is the interval between health checks measured in seconds.
 // safeInterval is the interval between safe head progress measured in seconds.
 // minPeerCount is the minimum number of peers required for the sequencer to be healthy.
-func NewSequencerHealthMonitor(log log.Logger, metrics metrics.Metricer, interval, unsafeInterval, safeInterval, minPeerCount uint64, safeEnabled bool, rollupCfg *rollup.Config, node dial.RollupClientInterface, p2p p2p.API) HealthMonitor {
+// maxCrossSafeLag is the maximum lag of the cross-safe head behind the local-safe head measured in seconds, 0 disables the check.
+// supervisor is optional, and if set the sequencer is unhealthy when the supervisor cannot be reached once interop is active.
+func NewSequencerHealthMonitor(log log.Logger, metrics metrics.Metricer, interval, unsafeInterval, safeInterval, minPeerCount, maxCrossSafeLag uint64, safeEnabled bool, rollupCfg *rollup.Config, node dial.RollupClientInterface, p2p p2p.API, supervisor SupervisorAPI) HealthMonitor {
 	return &SequencerHealthMonitor{
-		log:            log,
-		metrics:        metrics,
-		interval:       interval,
-		healthUpdateCh: make(chan error),
-		rollupCfg:      rollupCfg,
-		unsafeInterval: unsafeInterval,
-		safeEnabled:    safeEnabled,
-		safeInterval:   safeInterval,
-		minPeerCount:   minPeerCount,
-		timeProviderFn: currentTimeProvicer,
-		node:           node,
-		p2p:            p2p,
+		log:             log,
+		metrics:         metrics,
+		interval:        interval,
+		healthUpdateCh:  make(chan error),
+		rollupCfg:       rollupCfg,
+		unsafeInterval:  unsafeInterval,
+		safeEnabled:     safeEnabled,
+		safeInterval:    safeInterval,
+		minPeerCount:    minPeerCount,
+		maxCrossSafeLag: maxCrossSafeLag,
+		timeProviderFn:  currentTimeProvicer,
+		node:            node,
+		p2p:             p2p,
+		supervisor:      supervisor,
 	}
 }
 
@@ -64,6 +75,7 @@ type SequencerHealthMonitor struct {
 	safeEnabled        bool
 	safeInterval       uint64
 	minPeerCount       uint64
+	maxCrossSafeLag    uint64
 	interval           uint64
 	healthUpdateCh     chan error
 	lastSeenUnsafeNum  uint64
@@ -71,8 +83,9 @@ type SequencerHealthMonitor struct {
 
 	timeProviderFn func() uint64
 
-	node dial.RollupClientInterface
-	p2p  p2p.API
+	node       dial.RollupClientInterface
+	p2p        p2p.API
+	supervisor SupervisorAPI
 }
 
 var _ HealthMonitor = (*SequencerHealthMonitor)(nil)
@@ -130,11 +143,12 @@ func (hm *SequencerHealthMonitor) loop(ctx context.Context) {
 	}
 }
 
-// healthCheck checks the health of the sequencer by 3 criteria:
+// healthCheck checks the health of the sequencer by 5 criteria:
 // 1. unsafe head is progressing per block time
 // 2. unsafe head is not too far behind now (measured by unsafeInterval)
 // 3. safe head is progressing every configured batch submission interval
 // 4. peer count is above the configured minimum
+// 5. once interop is active, the supervisor is reachable and the cross-safe head is not too far behind the local-safe head
 func (hm *SequencerHealthMonitor) healthCheck(ctx context.Context) error {
 	status, err := hm.node.SyncStatus(ctx)
 	if err != nil {
@@ -209,10 +223,45 @@ func (hm *SequencerHealthMonitor) healthCheck(ctx context.Context) error {
 		return ErrSequencerNotHealthy
 	}
 
+	if err := hm.interopHealthCheck(ctx, status); err != nil {
+		return err
+	}
+
 	hm.log.Info("sequencer is healthy")
 	return nil
 }
 
+// interopHealthCheck checks that blocks built by the sequencer are not at risk of being invalidated
+// because it lost track of the cross-chain dependencies.
+func (hm *SequencerHealthMonitor) interopHealthCheck(ctx context.Context, status *eth.SyncStatus) error {
+	if !hm.rollupCfg.IsInterop(status.UnsafeL2.Time) {
+		return nil
+	}
+
+	if hm.supervisor != nil {
+		if _, err := hm.supervisor.SyncStatus(ctx); err != nil {
+			hm.log.Error("health monitor failed to get supervisor sync status", "err", err)
+			return ErrSupervisorConnectionDown
+		}
+	}
+
+	// Post-interop, the safe head of the sync status is the cross-safe head.
+	crossSafeLag := calculateTimeDiff(status.LocalSafeL2.Time, status.SafeL2.Time)
+	if hm.maxCrossSafeLag > 0 && crossSafeLag > hm.maxCrossSafeLag {
+		hm.log.Error(
+			"cross-safe head is falling behind the local-safe head",
+			"local_safe_head_num", status.LocalSafeL2.Number,
+			"local_safe_head_time", status.LocalSafeL2.Time,
+			"cross_safe_head_num", status.SafeL2.Number,
+			"cross_safe_head_time", status.SafeL2.Time,
+			"max_cross_safe_lag", hm.maxCrossSafeLag,
+			"cross_safe_lag", crossSafeLag,
+		)
+		return ErrSequencerNotHealthy
+	}
+	return nil
+}
+
 func calculateTimeDiff(now, then uint64) uint64 {
 	if now < then {
 		return 0
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
@@ -13,9 +14,13 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	p2pMocks "github.com/ethereum-optimism/optimism/op-node/p2p/mocks"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/frontend"
 )
 
 const (
@@ -205,6 +210,59 @@ func (s *HealthMonitorTestSuite) TestHealthyWithUnsafeLag() {
 	s.NoError(monitor.Stop())
 }
 
+func (s *HealthMonitorTestSuite) TestInteropHealth() {
+	s.T().Parallel()
+	interopTime := uint64(100)
+	rollupCfg := &rollup.Config{
+		BlockTime:   blockTime,
+		InteropTime: &interopTime,
+	}
+	status := func(unsafeTime, localSafeTime, crossSafeTime uint64) *eth.SyncStatus {
+		return &eth.SyncStatus{
+			UnsafeL2:    eth.L2BlockRef{Time: unsafeTime},
+			LocalSafeL2: eth.L2BlockRef{Time: localSafeTime},
+			SafeL2:      eth.L2BlockRef{Time: crossSafeTime},
+		}
+	}
+	tests := []struct {
+		name           string
+		status         *eth.SyncStatus
+		supervisorDown bool
+		expected       error
+	}{
+		{name: "PreInterop", status: status(98, 98, 0), supervisorDown: true},
+		{name: "Healthy", status: status(200, 190, 180)},
+		{name: "CrossSafeLagging", status: status(200, 190, 150), expected: ErrSequencerNotHealthy},
+		{name: "SupervisorDown", status: status(200, 190, 180), supervisorDown: true, expected: ErrSupervisorConnectionDown},
+	}
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			// serve the supervisor API with the frontend of op-supervisor, as the supervisor RPC server does
+			srv := rpc.NewServer()
+			s.Require().NoError(srv.RegisterName("supervisor", &frontend.QueryFrontend{Supervisor: backend.NewMockBackend()}))
+			supervisor := sources.NewSupervisorClient(client.NewBaseRPCClient(rpc.DialInProc(srv)))
+			defer supervisor.Close()
+			if test.supervisorDown {
+				srv.Stop()
+			} else {
+				defer srv.Stop()
+			}
+			monitor := &SequencerHealthMonitor{
+				log:             s.log,
+				rollupCfg:       rollupCfg,
+				maxCrossSafeLag: 20,
+				supervisor:      supervisor,
+			}
+			s.ErrorIs(monitor.interopHealthCheck(context.Background(), test.status), test.expected)
+		})
+	}
+
+	s.Run("Disabled", func() {
+		monitor := &SequencerHealthMonitor{log: s.log, rollupCfg: rollupCfg}
+		s.NoError(monitor.interopHealthCheck(context.Background(), status(200, 190, 0)))
+	})
+}
+
 func mockSyncStatus(unsafeTime, unsafeNum, safeTime, safeNum uint64) *eth.SyncStatus {
 	return &eth.SyncStatus{
 		UnsafeL2: eth.L2BlockRef{
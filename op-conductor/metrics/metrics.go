@@ -19,6 +19,15 @@ type Metricer interface {
 	RecordStopSequencer(success bool)
 	RecordHealthCheck(success bool, err error)
 	RecordLoopExecutionTime(duration float64)
+	RecordClusterMembership(voters, nonvoters int)
+	RecordClusterLeader(hasLeader bool)
+	RecordUnsafeHeads(consensus, node uint64, diverged bool)
+	RecordHandoverRecovery(success bool)
+	RecordWarmup(warm bool, lag uint64)
+	RecordLeadershipChange(leader bool)
+	RecordElectionDuration(duration float64)
+	RecordCommitLatency(duration float64)
+	RecordHealthFlap(healthy bool)
 }
 
 // Metrics implementation must implement RegistryMetricer to allow the metrics server to work.
@@ -37,8 +46,23 @@ type Metrics struct {
 	sequencerStarts *prometheus.CounterVec
 	sequencerStops  *prometheus.CounterVec
 	stateChanges    *prometheus.CounterVec
+	leaderChanges   *prometheus.CounterVec
+	healthFlaps     *prometheus.CounterVec
+
+	electionDuration prometheus.Histogram
+	commitLatency    prometheus.Histogram
+
+	handoverRecoveries *prometheus.CounterVec
+
+	warm      prometheus.Gauge
+	warmupLag prometheus.Gauge
 
 	loopExecutionTime prometheus.Histogram
+
+	clusterMembers     *prometheus.GaugeVec
+	clusterLeader      prometheus.Gauge
+	unsafeHeads        *prometheus.GaugeVec
+	unsafeHeadDiverged prometheus.Gauge
 }
 
 func (m *Metrics) Registry() *prometheus.Registry {
@@ -97,12 +121,69 @@ func NewMetrics() *Metrics {
 			"healthy",
 			"active",
 		}),
+		leaderChanges: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "leadership_changes_count",
+			Help:      "Number of leadership changes of this server",
+		}, []string{"leader"}),
+		healthFlaps: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "health_flaps_count",
+			Help:      "Number of changes of the sequencer health",
+		}, []string{"healthy"}),
+		electionDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "election_duration",
+			Help:      "Time (in seconds) the cluster was seen without leader before a leader was elected",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}),
+		commitLatency: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "commit_latency",
+			Help:      "Time (in seconds) to commit an unsafe payload to consensus",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}),
+		handoverRecoveries: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "handover_recoveries_count",
+			Help:      "Number of recoveries from failed leadership handovers",
+		}, []string{"success"}),
+		warm: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "standby_warm",
+			Help:      "1 if the standby sequencer is synced with the unsafe head in consensus and ready for failover",
+		}),
+		warmupLag: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "standby_unsafe_lag",
+			Help:      "Number of blocks the unsafe head of the standby sequencer is behind the unsafe head in consensus",
+		}),
 		loopExecutionTime: factory.NewHistogram(prometheus.HistogramOpts{
 			Namespace: Namespace,
 			Name:      "loop_execution_time",
 			Help:      "Time (in seconds) to execute conductor loop iteration",
 			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 		}),
+		clusterMembers: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "cluster_members",
+			Help:      "Number of servers in the cluster, as seen by an observer",
+		}, []string{"suffrage"}),
+		clusterLeader: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "cluster_leader",
+			Help:      "1 if the cluster has a leader, as seen by an observer",
+		}),
+		unsafeHeads: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "unsafe_head",
+			Help:      "Unsafe head block number, as seen by an observer",
+		}, []string{"source"}),
+		unsafeHeadDiverged: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "unsafe_head_diverged",
+			Help:      "1 if the unsafe head of the node diverged from the unsafe head in consensus, as seen by an observer",
+		}),
 	}
 }
 
@@ -151,7 +232,63 @@ func (m *Metrics) RecordStopSequencer(success bool) {
 	m.sequencerStops.WithLabelValues(strconv.FormatBool(success)).Inc()
 }
 
+// RecordHandoverRecovery increments the handoverRecoveries counter.
+func (m *Metrics) RecordHandoverRecovery(success bool) {
+	m.handoverRecoveries.WithLabelValues(strconv.FormatBool(success)).Inc()
+}
+
+// RecordWarmup records the warm-up status of the standby sequencer.
+func (m *Metrics) RecordWarmup(warm bool, lag uint64) {
+	m.warm.Set(boolToFloat64(warm))
+	m.warmupLag.Set(float64(lag))
+}
+
+// RecordLeadershipChange increments the leaderChanges counter.
+func (m *Metrics) RecordLeadershipChange(leader bool) {
+	m.leaderChanges.WithLabelValues(strconv.FormatBool(leader)).Inc()
+}
+
+// RecordElectionDuration records the time the cluster was without leader.
+func (m *Metrics) RecordElectionDuration(duration float64) {
+	m.electionDuration.Observe(duration)
+}
+
+// RecordCommitLatency records the time it took to commit an unsafe payload to consensus.
+func (m *Metrics) RecordCommitLatency(duration float64) {
+	m.commitLatency.Observe(duration)
+}
+
+// RecordHealthFlap increments the healthFlaps counter.
+func (m *Metrics) RecordHealthFlap(healthy bool) {
+	m.healthFlaps.WithLabelValues(strconv.FormatBool(healthy)).Inc()
+}
+
 // RecordLoopExecutionTime records the time it took to execute the conductor loop.
 func (m *Metrics) RecordLoopExecutionTime(duration float64) {
 	m.loopExecutionTime.Observe(duration)
 }
+
+// RecordClusterMembership records the number of voters and non-voters in the cluster.
+func (m *Metrics) RecordClusterMembership(voters, nonvoters int) {
+	m.clusterMembers.WithLabelValues("voter").Set(float64(voters))
+	m.clusterMembers.WithLabelValues("nonvoter").Set(float64(nonvoters))
+}
+
+// RecordClusterLeader records whether the cluster has a leader.
+func (m *Metrics) RecordClusterLeader(hasLeader bool) {
+	m.clusterLeader.Set(boolToFloat64(hasLeader))
+}
+
+// RecordUnsafeHeads records the unsafe head in consensus and in the node, and whether they diverged.
+func (m *Metrics) RecordUnsafeHeads(consensus, node uint64, diverged bool) {
+	m.unsafeHeads.WithLabelValues("consensus").Set(float64(consensus))
+	m.unsafeHeads.WithLabelValues("node").Set(float64(node))
+	m.unsafeHeadDiverged.Set(boolToFloat64(diverged))
+}
+
+func boolToFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
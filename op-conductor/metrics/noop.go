@@ -12,3 +12,12 @@ func (*NoopMetricsImpl) RecordStartSequencer(success bool)
 func (*NoopMetricsImpl) RecordStopSequencer(success bool)                         {}
 func (*NoopMetricsImpl) RecordHealthCheck(success bool, err error)                {}
 func (*NoopMetricsImpl) RecordLoopExecutionTime(duration float64)                 {}
+func (*NoopMetricsImpl) RecordClusterMembership(voters, nonvoters int)            {}
+func (*NoopMetricsImpl) RecordClusterLeader(hasLeader bool)                       {}
+func (*NoopMetricsImpl) RecordUnsafeHeads(consensus, node uint64, diverged bool)  {}
+func (*NoopMetricsImpl) RecordHandoverRecovery(success bool)                      {}
+func (*NoopMetricsImpl) RecordWarmup(warm bool, lag uint64)                       {}
+func (*NoopMetricsImpl) RecordLeadershipChange(leader bool)                       {}
+func (*NoopMetricsImpl) RecordElectionDuration(duration float64)                  {}
+func (*NoopMetricsImpl) RecordCommitLatency(duration float64)                     {}
+func (*NoopMetricsImpl) RecordHealthFlap(healthy bool)                            {}
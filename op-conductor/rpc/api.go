@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
 
@@ -19,10 +20,14 @@ type API interface {
 	// It does not impact the actual raft consensus leadership status. It is supposed to be used when the cluster is unhealthy
 	// and the node is the only one up, to allow batcher to be able to connect to the node, so that it could download blocks from the manually started sequencer.
 	OverrideLeader(ctx context.Context) error
-	// Pause pauses op-conductor.
-	Pause(ctx context.Context) error
-	// Resume resumes op-conductor.
-	Resume(ctx context.Context) error
+	// Pause pauses op-conductor. The operator and the reason are required, and recorded in the audit trail.
+	Pause(ctx context.Context, operator string, reason string) error
+	// Resume resumes op-conductor. The operator and the reason are required, and recorded in the audit trail.
+	Resume(ctx context.Context, operator string, reason string) error
+	// PauseState returns whether op-conductor is paused, and the latest pause or resume in the audit trail.
+	PauseState(ctx context.Context) (*PauseState, error)
+	// PauseHistory returns the audit trail of pauses and resumes.
+	PauseHistory(ctx context.Context) ([]AuditEntry, error)
 	// Stop stops op-conductor.
 	Stop(ctx context.Context) error
 	// Paused returns true if op-conductor is paused.
@@ -47,8 +52,24 @@ type API interface {
 	TransferLeader(ctx context.Context) error
 	// TransferLeaderToServer transfers leadership to a specific server.
 	TransferLeaderToServer(ctx context.Context, id string, addr string) error
+	// TransferLeaderToServerChecked transfers leadership to a specific server after running pre-flight checks on the target.
+	// The transfer is refused if any check fails. With DryRun set, only the checks are run.
+	TransferLeaderToServerChecked(ctx context.Context, req TransferLeaderRequest) (*TransferLeaderReport, error)
 	// ClusterMembership returns the current cluster membership configuration.
 	ClusterMembership(ctx context.Context) (*consensus.ClusterMembership, error)
+	// ClusterHealth returns the health of the cluster members, checked through the given op-conductor RPC endpoints.
+	ClusterHealth(ctx context.Context, rpcs map[string]string) ([]MemberHealth, error)
+	// ChangeMembership adds, removes or replaces a cluster member. The change is refused if it would leave
+	// the cluster without a healthy quorum of voters.
+	ChangeMembership(ctx context.Context, change MembershipChange) (*consensus.ClusterMembership, error)
+	// HandoverIncidents returns the reports of the most recent failed leadership handovers of this server.
+	HandoverIncidents(ctx context.Context) ([]HandoverIncident, error)
+	// WarmupStatus returns whether the standby sequencer is warm, i.e. ready to take over sequencing without catching up.
+	WarmupStatus(ctx context.Context) (*WarmupStatus, error)
+	// Events returns the log of the most recent control events, with the reasons of the control decisions.
+	Events(ctx context.Context) ([]ControlEvent, error)
+	// SequencerUnsafeHead returns the unsafe head of the sequencer managed by this conductor.
+	SequencerUnsafeHead(ctx context.Context) (eth.BlockID, error)
 
 	// APIs called by op-node
 	// Active returns true if op-conductor is active (not paused or stopped).
@@ -57,6 +78,115 @@ type API interface {
 	CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayloadEnvelope) error
 }
 
+// TransferLeaderRequest defines a guarded leadership transfer to a specific server.
+type TransferLeaderRequest struct {
+	// ID and Addr identify the target server in the cluster.
+	ID   string `json:"id"`
+	Addr string `json:"addr"`
+	// RPC is the op-conductor RPC endpoint of the target server, used to check its sequencer.
+	RPC string `json:"rpc"`
+	// MaxUnsafeLag is the maximum number of blocks the unsafe head of the target may be behind the leader.
+	MaxUnsafeLag uint64 `json:"maxUnsafeLag"`
+	// MaxSafeLag is the maximum number of blocks the safe head of the leader may be behind its unsafe head.
+	// The conductor has no visibility into the batcher, so the batcher is considered drained once its
+	// submitted blocks became safe up to this lag.
+	MaxSafeLag uint64 `json:"maxSafeLag"`
+	// DryRun only runs the pre-flight checks, without transferring leadership.
+	DryRun bool `json:"dryRun"`
+}
+
+// TransferLeaderReport is the outcome of a guarded leadership transfer.
+type TransferLeaderReport struct {
+	TargetHealthy    bool        `json:"targetHealthy"`
+	LeaderUnsafeHead eth.BlockID `json:"leaderUnsafeHead"`
+	TargetUnsafeHead eth.BlockID `json:"targetUnsafeHead"`
+	LeaderSafeHead   eth.BlockID `json:"leaderSafeHead"`
+	// Failures describes the pre-flight checks that did not pass, the transfer is only attempted if empty.
+	Failures    []string `json:"failures"`
+	Transferred bool     `json:"transferred"`
+}
+
+// MemberHealth is the health of a cluster member.
+type MemberHealth struct {
+	consensus.ServerInfo
+	Leader  bool `json:"leader"`
+	Healthy bool `json:"healthy"`
+	// Error describes why the health of the member could not be checked, in which case it is considered unhealthy.
+	Error string `json:"error,omitempty"`
+}
+
+// MembershipChange is a change of the cluster membership. With both Add and Remove set, Remove is replaced by Add.
+type MembershipChange struct {
+	// Add is the server to add to the cluster, if any. A voter must be healthy to be added.
+	Add *consensus.ServerInfo `json:"add,omitempty"`
+	// Remove is the ID of the server to remove from the cluster, if any.
+	Remove string `json:"remove,omitempty"`
+	// RPCs are the op-conductor RPC endpoints of the cluster members by server ID, used to check their health.
+	// Members without endpoint are considered unhealthy, except for the server handling the request.
+	RPCs map[string]string `json:"rpcs"`
+	// Version is the expected version of the cluster membership, the change is refused if it does not match.
+	// If zero, the version is not checked.
+	Version uint64 `json:"version"`
+}
+
+// HandoverIncident reports a failed leadership handover, where this server acquired leadership but failed to start
+// sequencing, and the recovery from it.
+type HandoverIncident struct {
+	Server string `json:"server"`
+	// UnsafeHead is the unsafe head in consensus when the failed handover was detected.
+	UnsafeHead eth.BlockID `json:"unsafeHead"`
+	StartedAt  time.Time   `json:"startedAt"`
+	DetectedAt time.Time   `json:"detectedAt"`
+	// Attempts is the number of failed attempts to start sequencing.
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"lastError"`
+	// Recovered is true if leadership was transferred to another server, otherwise RecoveryError describes the failure.
+	Recovered     bool   `json:"recovered"`
+	RecoveryError string `json:"recoveryError,omitempty"`
+}
+
+// WarmupStatus is the warm-up status of a standby sequencer.
+type WarmupStatus struct {
+	// Enabled is false if warm-up is disabled, or if the sequencer is active.
+	Enabled bool `json:"enabled"`
+	Warm    bool `json:"warm"`
+	// ConsensusUnsafeHead and NodeUnsafeHead are the unsafe heads in consensus and in the execution layer of the sequencer.
+	ConsensusUnsafeHead eth.BlockID `json:"consensusUnsafeHead"`
+	NodeUnsafeHead      eth.BlockID `json:"nodeUnsafeHead"`
+	// Lag is the number of blocks the sequencer is behind consensus.
+	Lag         uint64    `json:"lag"`
+	LastChecked time.Time `json:"lastChecked"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ControlEvent is an entry of the event log of a conductor: a leadership or health change, or a control decision.
+type ControlEvent struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	// Leader, Healthy and Active are the state of the conductor when the event happened.
+	Leader  bool   `json:"leader"`
+	Healthy bool   `json:"healthy"`
+	Active  bool   `json:"active"`
+	Reason  string `json:"reason"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AuditEntry records who paused or resumed op-conductor, and why.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Operator string    `json:"operator"`
+	Reason   string    `json:"reason"`
+}
+
+// PauseState is the pause state of op-conductor.
+type PauseState struct {
+	Paused bool `json:"paused"`
+	// LastChange is the latest pause or resume in the audit trail, if any.
+	// It may not match Paused if op-conductor was restarted since, or paused with the paused flag on startup.
+	LastChange *AuditEntry `json:"lastChange"`
+}
+
 // ExecutionProxyAPI defines the methods proxied to the execution rpc backend
 // This should include all methods that are called by op-batcher or op-proposer
 type ExecutionProxyAPI interface {
@@ -11,8 +11,10 @@ import (
 )
 
 type conductor interface {
-	Pause(ctx context.Context) error
-	Resume(ctx context.Context) error
+	PauseWithReason(ctx context.Context, operator, reason string) error
+	ResumeWithReason(ctx context.Context, operator, reason string) error
+	PauseState(ctx context.Context) *PauseState
+	PauseHistory(ctx context.Context) []AuditEntry
 	Stop(ctx context.Context) error
 	Paused() bool
 	Stopped() bool
@@ -26,7 +28,14 @@ type conductor interface {
 	TransferLeader(ctx context.Context) error
 	TransferLeaderToServer(ctx context.Context, id string, addr string) error
 	CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayloadEnvelope) error
+	TransferLeaderToServerChecked(ctx context.Context, req TransferLeaderRequest) (*TransferLeaderReport, error)
 	ClusterMembership(ctx context.Context) (*consensus.ClusterMembership, error)
+	ClusterHealth(ctx context.Context, rpcs map[string]string) ([]MemberHealth, error)
+	ChangeMembership(ctx context.Context, change MembershipChange) (*consensus.ClusterMembership, error)
+	SequencerUnsafeHead(ctx context.Context) (eth.BlockID, error)
+	HandoverIncidents(ctx context.Context) []HandoverIncident
+	WarmupStatus(ctx context.Context) *WarmupStatus
+	Events(ctx context.Context) []ControlEvent
 }
 
 // APIBackend is the backend implementation of the API.
@@ -107,13 +116,23 @@ func (api *APIBackend) LeaderWithID(ctx context.Context) (*consensus.ServerInfo,
 }
 
 // Pause implements API.
-func (api *APIBackend) Pause(ctx context.Context) error {
-	return api.con.Pause(ctx)
+func (api *APIBackend) Pause(ctx context.Context, operator string, reason string) error {
+	return api.con.PauseWithReason(ctx, operator, reason)
 }
 
 // Resume implements API.
-func (api *APIBackend) Resume(ctx context.Context) error {
-	return api.con.Resume(ctx)
+func (api *APIBackend) Resume(ctx context.Context, operator string, reason string) error {
+	return api.con.ResumeWithReason(ctx, operator, reason)
+}
+
+// PauseState implements API.
+func (api *APIBackend) PauseState(ctx context.Context) (*PauseState, error) {
+	return api.con.PauseState(ctx), nil
+}
+
+// PauseHistory implements API.
+func (api *APIBackend) PauseHistory(ctx context.Context) ([]AuditEntry, error) {
+	return api.con.PauseHistory(ctx), nil
 }
 
 // Stop implements API.
@@ -133,6 +152,12 @@ func (api *APIBackend) TransferLeaderToServer(ctx context.Context, id string, ad
 	return api.con.TransferLeaderToServer(ctx, id, addr)
 }
 
+// TransferLeaderToServerChecked implements API. As with TransferLeaderToServer, a successful transfer only means that
+// leadership transfer is in progress.
+func (api *APIBackend) TransferLeaderToServerChecked(ctx context.Context, req TransferLeaderRequest) (*TransferLeaderReport, error) {
+	return api.con.TransferLeaderToServerChecked(ctx, req)
+}
+
 // SequencerHealthy implements API.
 func (api *APIBackend) SequencerHealthy(ctx context.Context) (bool, error) {
 	return api.con.SequencerHealthy(ctx), nil
@@ -142,3 +167,33 @@ func (api *APIBackend) SequencerHealthy(ctx context.Context) (bool, error) {
 func (api *APIBackend) ClusterMembership(ctx context.Context) (*consensus.ClusterMembership, error) {
 	return api.con.ClusterMembership(ctx)
 }
+
+// ClusterHealth implements API.
+func (api *APIBackend) ClusterHealth(ctx context.Context, rpcs map[string]string) ([]MemberHealth, error) {
+	return api.con.ClusterHealth(ctx, rpcs)
+}
+
+// ChangeMembership implements API.
+func (api *APIBackend) ChangeMembership(ctx context.Context, change MembershipChange) (*consensus.ClusterMembership, error) {
+	return api.con.ChangeMembership(ctx, change)
+}
+
+// SequencerUnsafeHead implements API.
+func (api *APIBackend) SequencerUnsafeHead(ctx context.Context) (eth.BlockID, error) {
+	return api.con.SequencerUnsafeHead(ctx)
+}
+
+// HandoverIncidents implements API.
+func (api *APIBackend) HandoverIncidents(ctx context.Context) ([]HandoverIncident, error) {
+	return api.con.HandoverIncidents(ctx), nil
+}
+
+// WarmupStatus implements API.
+func (api *APIBackend) WarmupStatus(ctx context.Context) (*WarmupStatus, error) {
+	return api.con.WarmupStatus(ctx), nil
+}
+
+// Events implements API.
+func (api *APIBackend) Events(ctx context.Context) ([]ControlEvent, error) {
+	return api.con.Events(ctx), nil
+}
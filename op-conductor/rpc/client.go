@@ -93,13 +93,27 @@ func (c *APIClient) LeaderWithID(ctx context.Context) (*consensus.ServerInfo, er
 }
 
 // Pause implements API.
-func (c *APIClient) Pause(ctx context.Context) error {
-	return c.c.CallContext(ctx, nil, prefixRPC("pause"))
+func (c *APIClient) Pause(ctx context.Context, operator string, reason string) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("pause"), operator, reason)
 }
 
 // Resume implements API.
-func (c *APIClient) Resume(ctx context.Context) error {
-	return c.c.CallContext(ctx, nil, prefixRPC("resume"))
+func (c *APIClient) Resume(ctx context.Context, operator string, reason string) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("resume"), operator, reason)
+}
+
+// PauseState implements API.
+func (c *APIClient) PauseState(ctx context.Context) (*PauseState, error) {
+	var state *PauseState
+	err := c.c.CallContext(ctx, &state, prefixRPC("pauseState"))
+	return state, err
+}
+
+// PauseHistory implements API.
+func (c *APIClient) PauseHistory(ctx context.Context) ([]AuditEntry, error) {
+	var history []AuditEntry
+	err := c.c.CallContext(ctx, &history, prefixRPC("pauseHistory"))
+	return history, err
 }
 
 // Stop implements API.
@@ -117,6 +131,13 @@ func (c *APIClient) TransferLeaderToServer(ctx context.Context, id string, addr
 	return c.c.CallContext(ctx, nil, prefixRPC("transferLeaderToServer"), id, addr)
 }
 
+// TransferLeaderToServerChecked implements API.
+func (c *APIClient) TransferLeaderToServerChecked(ctx context.Context, req TransferLeaderRequest) (*TransferLeaderReport, error) {
+	var report *TransferLeaderReport
+	err := c.c.CallContext(ctx, &report, prefixRPC("transferLeaderToServerChecked"), req)
+	return report, err
+}
+
 // SequencerHealthy implements API.
 func (c *APIClient) SequencerHealthy(ctx context.Context) (bool, error) {
 	var healthy bool
@@ -130,3 +151,45 @@ func (c *APIClient) ClusterMembership(ctx context.Context) (*consensus.ClusterMe
 	err := c.c.CallContext(ctx, &clusterMembership, prefixRPC("clusterMembership"))
 	return &clusterMembership, err
 }
+
+// SequencerUnsafeHead implements API.
+func (c *APIClient) SequencerUnsafeHead(ctx context.Context) (eth.BlockID, error) {
+	var head eth.BlockID
+	err := c.c.CallContext(ctx, &head, prefixRPC("sequencerUnsafeHead"))
+	return head, err
+}
+
+// HandoverIncidents implements API.
+func (c *APIClient) HandoverIncidents(ctx context.Context) ([]HandoverIncident, error) {
+	var incidents []HandoverIncident
+	err := c.c.CallContext(ctx, &incidents, prefixRPC("handoverIncidents"))
+	return incidents, err
+}
+
+// WarmupStatus implements API.
+func (c *APIClient) WarmupStatus(ctx context.Context) (*WarmupStatus, error) {
+	var status *WarmupStatus
+	err := c.c.CallContext(ctx, &status, prefixRPC("warmupStatus"))
+	return status, err
+}
+
+// Events implements API.
+func (c *APIClient) Events(ctx context.Context) ([]ControlEvent, error) {
+	var events []ControlEvent
+	err := c.c.CallContext(ctx, &events, prefixRPC("events"))
+	return events, err
+}
+
+// ClusterHealth implements API.
+func (c *APIClient) ClusterHealth(ctx context.Context, rpcs map[string]string) ([]MemberHealth, error) {
+	var health []MemberHealth
+	err := c.c.CallContext(ctx, &health, prefixRPC("clusterHealth"), rpcs)
+	return health, err
+}
+
+// ChangeMembership implements API.
+func (c *APIClient) ChangeMembership(ctx context.Context, change MembershipChange) (*consensus.ClusterMembership, error) {
+	var membership consensus.ClusterMembership
+	err := c.c.CallContext(ctx, &membership, prefixRPC("changeMembership"), change)
+	return &membership, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/ethereum-optimism/superchain-registry/superchain"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 )
 
@@ -93,6 +95,35 @@ func TestGameFactoryAddress(t *testing.T) {
 	})
 }
 
+func TestOptimismPortalAddress(t *testing.T) {
+	t.Run("NotRequired", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, common.Address{}, cfg.OptimismPortalAddress)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		addr := common.Address{0x11, 0x22}
+		cfg := configForArgs(t, addRequiredArgs("--optimism-portal-address", addr.Hex()))
+		require.Equal(t, addr, cfg.OptimismPortalAddress)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid optimism portal address", addRequiredArgs("--optimism-portal-address", "foo"))
+	})
+
+	t.Run("DefaultsToNetwork", func(t *testing.T) {
+		opSepoliaChainId := uint64(11155420)
+		cfg := configForArgs(t, addRequiredArgsExcept("--game-factory-address", "--network=op-sepolia"))
+		require.EqualValues(t, superchain.Addresses[opSepoliaChainId].OptimismPortalProxy, cfg.OptimismPortalAddress)
+	})
+
+	t.Run("OverridesNetwork", func(t *testing.T) {
+		addr := common.Address{0xbb, 0xcc, 0xdd}
+		cfg := configForArgs(t, addRequiredArgsExcept("--game-factory-address", "--optimism-portal-address", addr.Hex(), "--network", "op-sepolia"))
+		require.Equal(t, addr, cfg.OptimismPortalAddress)
+	})
+}
+
 func TestNetwork(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		opSepoliaChainId := uint64(11155420)
@@ -105,6 +136,60 @@ func TestNetwork(t *testing.T) {
 	})
 }
 
+func TestNetworkName(t *testing.T) {
+	t.Run("UsesDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, config.DefaultNetworkName, cfg.NetworkName)
+	})
+
+	t.Run("DefaultsToNetwork", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgsExcept("--game-factory-address", "--network=op-sepolia"))
+		require.Equal(t, "op-sepolia", cfg.NetworkName)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgsExcept("--game-factory-address", "--network=op-sepolia", "--network-name=sepolia"))
+		require.Equal(t, "sepolia", cfg.NetworkName)
+	})
+}
+
+func TestAdditionalNetworks(t *testing.T) {
+	t.Run("NotRequired", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Empty(t, cfg.AdditionalNetworks)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		addr := common.Address{0xcc}
+		cfg := configForArgs(t, addRequiredArgs(
+			"--additional-networks", "chain-a:"+addr.Hex()+":http://example.com:9545",
+			"--additional-networks", "op-sepolia::http://example.com:9546"))
+		opSepoliaChainId := uint64(11155420)
+		require.Equal(t, []config.NetworkConfig{
+			{Name: "chain-a", GameFactoryAddress: addr, RollupRpc: "http://example.com:9545"},
+			{
+				Name:               "op-sepolia",
+				GameFactoryAddress: common.Address(superchain.Addresses[opSepoliaChainId].DisputeGameFactoryProxy),
+				RollupRpc:          "http://example.com:9546",
+
+				OptimismPortalAddress: common.Address(superchain.Addresses[opSepoliaChainId].OptimismPortalProxy),
+			},
+		}, cfg.AdditionalNetworks)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid network \"chain-a\"", addRequiredArgs("--additional-networks", "chain-a"))
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid game factory address for network chain-a", addRequiredArgs("--additional-networks", "chain-a:0xnope:http://example.com"))
+	})
+
+	t.Run("UnknownNetwork", func(t *testing.T) {
+		verifyArgsInvalid(t, "unknown chain: not-a-network", addRequiredArgs("--additional-networks", "not-a-network::http://example.com"))
+	})
+}
+
 func TestHonestActors(t *testing.T) {
 	t.Run("NotRequired", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs())
@@ -234,6 +319,54 @@ func TestMaxConcurrency(t *testing.T) {
 	})
 }
 
+func TestBondsAtRiskThresholds(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Nil(t, cfg.GameBondsAtRiskThreshold)
+		require.Nil(t, cfg.TotalBondsAtRiskThreshold)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(
+			"--game-bonds-at-risk-threshold-gwei", "2000000000",
+			"--total-bonds-at-risk-threshold-gwei", "5"))
+		require.Equal(t, new(big.Int).Mul(big.NewInt(2), big.NewInt(params.Ether)), cfg.GameBondsAtRiskThreshold)
+		require.Equal(t, big.NewInt(5*params.GWei), cfg.TotalBondsAtRiskThreshold)
+	})
+
+	t.Run("Zero", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(
+			"--game-bonds-at-risk-threshold-gwei", "0",
+			"--total-bonds-at-risk-threshold-gwei", "0"))
+		require.Nil(t, cfg.GameBondsAtRiskThreshold)
+		require.Nil(t, cfg.TotalBondsAtRiskThreshold)
+	})
+}
+
+func TestStatusConfig(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, config.StatusConfig{
+			ListenAddr: config.DefaultStatusListenAddr,
+			ListenPort: config.DefaultStatusListenPort,
+		}, cfg.StatusConfig)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--status.enabled", "--status.addr", "127.0.0.1", "--status.port", "8080"))
+		require.Equal(t, config.StatusConfig{
+			Enabled:    true,
+			ListenAddr: "127.0.0.1",
+			ListenPort: 8080,
+		}, cfg.StatusConfig)
+	})
+
+	t.Run("InvalidPort", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--status.enabled", "--status.port", "70000"))
+		require.ErrorIs(t, cfg.Check(), config.ErrInvalidStatusPort)
+	})
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := dryRunWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
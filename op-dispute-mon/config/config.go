@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -16,6 +17,9 @@ var (
 	ErrMissingGameFactoryAddress = errors.New("missing game factory address")
 	ErrMissingRollupRpc          = errors.New("missing rollup rpc url")
 	ErrMissingMaxConcurrency     = errors.New("missing max concurrency")
+	ErrMissingNetworkName        = errors.New("missing network name")
+	ErrDuplicateNetworkName      = errors.New("duplicate network name")
+	ErrInvalidStatusPort         = errors.New("invalid status api port")
 )
 
 const (
@@ -30,8 +34,24 @@ const (
 
 	//DefaultMaxConcurrency is the default number of threads to use when fetching game data
 	DefaultMaxConcurrency = uint(5)
+
+	// DefaultNetworkName is the name used to label metrics for the network configured by GameFactoryAddress and
+	// RollupRpc when monitoring additional networks and no network name is set.
+	DefaultNetworkName = "default"
+
+	DefaultStatusListenAddr = "0.0.0.0"
+	DefaultStatusListenPort = 7310
 )
 
+// NetworkConfig identifies the dispute games of a single network to monitor.
+type NetworkConfig struct {
+	Name               string         // Name used to label metrics for the network
+	GameFactoryAddress common.Address // Address of the dispute game factory
+	RollupRpc          string         // The rollup node RPC URL.
+
+	OptimismPortalAddress common.Address // Address of the OptimismPortal to check withdrawal proofs in (disabled if zero)
+}
+
 // Config is a well typed config that is parsed from the CLI params.
 // It also contains config options for auxiliary services.
 type Config struct {
@@ -45,20 +65,51 @@ type Config struct {
 	IgnoredGames    []common.Address // Games to exclude from monitoring
 	MaxConcurrency  uint             // Maximum number of threads to use when fetching game data
 
+	OptimismPortalAddress common.Address // Address of the OptimismPortal to check withdrawal proofs in (disabled if zero)
+
+	NetworkName        string          // Name of the network configured by GameFactoryAddress and RollupRpc
+	AdditionalNetworks []NetworkConfig // Further networks to monitor. Metrics are labelled with the network name if set.
+
+	GameBondsAtRiskThreshold  *big.Int // Honest bonds forecast to be lost in a single game that trigger an alert (disabled if nil)
+	TotalBondsAtRiskThreshold *big.Int // Honest bonds forecast to be lost across all games that trigger an alert (disabled if nil)
+
+	StatusConfig  StatusConfig
 	MetricsConfig opmetrics.CLIConfig
 	PprofConfig   oppprof.CLIConfig
 }
 
+// StatusConfig configures the HTTP server exposing the monitor's current assessment of games as JSON.
+type StatusConfig struct {
+	Enabled    bool
+	ListenAddr string
+	ListenPort int
+}
+
+func (c StatusConfig) Check() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ListenPort < 0 || c.ListenPort > 65535 {
+		return ErrInvalidStatusPort
+	}
+	return nil
+}
+
 func NewConfig(gameFactoryAddress common.Address, l1EthRpc string, rollupRpc string) Config {
 	return Config{
 		L1EthRpc:           l1EthRpc,
 		RollupRpc:          rollupRpc,
 		GameFactoryAddress: gameFactoryAddress,
 
+		NetworkName:     DefaultNetworkName,
 		MonitorInterval: DefaultMonitorInterval,
 		GameWindow:      DefaultGameWindow,
 		MaxConcurrency:  DefaultMaxConcurrency,
 
+		StatusConfig: StatusConfig{
+			ListenAddr: DefaultStatusListenAddr,
+			ListenPort: DefaultStatusListenPort,
+		},
 		MetricsConfig: opmetrics.DefaultCLIConfig(),
 		PprofConfig:   oppprof.DefaultCLIConfig(),
 	}
@@ -77,6 +128,25 @@ func (c Config) Check() error {
 	if c.MaxConcurrency == 0 {
 		return ErrMissingMaxConcurrency
 	}
+	names := make(map[string]bool)
+	for _, network := range c.Networks() {
+		if network.Name == "" {
+			return ErrMissingNetworkName
+		}
+		if names[network.Name] {
+			return fmt.Errorf("%w: %v", ErrDuplicateNetworkName, network.Name)
+		}
+		names[network.Name] = true
+		if network.GameFactoryAddress == (common.Address{}) {
+			return fmt.Errorf("network %v: %w", network.Name, ErrMissingGameFactoryAddress)
+		}
+		if network.RollupRpc == "" {
+			return fmt.Errorf("network %v: %w", network.Name, ErrMissingRollupRpc)
+		}
+	}
+	if err := c.StatusConfig.Check(); err != nil {
+		return fmt.Errorf("status config: %w", err)
+	}
 	if err := c.MetricsConfig.Check(); err != nil {
 		return fmt.Errorf("metrics config: %w", err)
 	}
@@ -85,3 +155,15 @@ func (c Config) Check() error {
 	}
 	return nil
 }
+
+// Networks returns all networks to monitor, starting with the network configured by GameFactoryAddress and RollupRpc.
+func (c Config) Networks() []NetworkConfig {
+	primary := NetworkConfig{
+		Name:               c.NetworkName,
+		GameFactoryAddress: c.GameFactoryAddress,
+		RollupRpc:          c.RollupRpc,
+
+		OptimismPortalAddress: c.OptimismPortalAddress,
+	}
+	return append([]NetworkConfig{primary}, c.AdditionalNetworks...)
+}
@@ -45,3 +45,55 @@ func TestMaxConcurrencyRequired(t *testing.T) {
 	config.MaxConcurrency = 0
 	require.ErrorIs(t, config.Check(), ErrMissingMaxConcurrency)
 }
+
+func TestNetworks(t *testing.T) {
+	config := validConfig()
+	config.NetworkName = "primary"
+	config.OptimismPortalAddress = common.Address{0x56}
+	additional := NetworkConfig{Name: "other", GameFactoryAddress: common.Address{0x45}, RollupRpc: "http://localhost:9555"}
+	config.AdditionalNetworks = []NetworkConfig{additional}
+	require.NoError(t, config.Check())
+	require.Equal(t, []NetworkConfig{
+		{Name: "primary", GameFactoryAddress: validGameFactoryAddress, RollupRpc: validRollupRpc, OptimismPortalAddress: common.Address{0x56}},
+		additional,
+	}, config.Networks())
+}
+
+func TestNetworkNameRequired(t *testing.T) {
+	config := validConfig()
+	config.NetworkName = ""
+	require.ErrorIs(t, config.Check(), ErrMissingNetworkName)
+}
+
+func TestNetworkNamesUnique(t *testing.T) {
+	config := validConfig()
+	config.AdditionalNetworks = []NetworkConfig{{Name: DefaultNetworkName, GameFactoryAddress: common.Address{0x45}, RollupRpc: "http://localhost:9555"}}
+	require.ErrorIs(t, config.Check(), ErrDuplicateNetworkName)
+}
+
+func TestAdditionalNetworkGameFactoryAddressRequired(t *testing.T) {
+	config := validConfig()
+	config.AdditionalNetworks = []NetworkConfig{{Name: "other", RollupRpc: "http://localhost:9555"}}
+	require.ErrorIs(t, config.Check(), ErrMissingGameFactoryAddress)
+}
+
+func TestAdditionalNetworkRollupRpcRequired(t *testing.T) {
+	config := validConfig()
+	config.AdditionalNetworks = []NetworkConfig{{Name: "other", GameFactoryAddress: common.Address{0x45}}}
+	require.ErrorIs(t, config.Check(), ErrMissingRollupRpc)
+}
+
+func TestStatusConfig(t *testing.T) {
+	t.Run("InvalidPortIgnoredWhenDisabled", func(t *testing.T) {
+		config := validConfig()
+		config.StatusConfig.ListenPort = -1
+		require.NoError(t, config.Check())
+	})
+
+	t.Run("InvalidPort", func(t *testing.T) {
+		config := validConfig()
+		config.StatusConfig.Enabled = true
+		config.StatusConfig.ListenPort = 65536
+		require.ErrorIs(t, config.Check(), ErrInvalidStatusPort)
+	})
+}
@@ -2,17 +2,22 @@ package flags
 
 import (
 	"fmt"
+	"math/big"
+	"strings"
 
 	challengerFlags "github.com/ethereum-optimism/optimism/op-challenger/flags"
 	"github.com/ethereum-optimism/optimism/op-service/flags"
+	"github.com/ethereum-optimism/superchain-registry/superchain"
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/config"
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 const (
@@ -36,6 +41,12 @@ var (
 		EnvVars: prefixEnvVars("ROLLUP_RPC"),
 	}
 	// Optional Flags
+	OptimismPortalAddressFlag = &cli.StringFlag{
+		Name: "optimism-portal-address",
+		Usage: "Address of the OptimismPortal contract to check withdrawal proofs in. " +
+			"Defaults to the portal for --network if set. Withdrawal proofs are not checked if unset.",
+		EnvVars: prefixEnvVars("OPTIMISM_PORTAL_ADDRESS"),
+	}
 	GameFactoryAddressFlag = &cli.StringFlag{
 		Name:    "game-factory-address",
 		Usage:   "Address of the fault game factory contract.",
@@ -71,6 +82,47 @@ var (
 		EnvVars: prefixEnvVars("MAX_CONCURRENCY"),
 		Value:   config.DefaultMaxConcurrency,
 	}
+	NetworkNameFlag = &cli.StringFlag{
+		Name: "network-name",
+		Usage: "Name used to label metrics for the network set by --game-factory-address or --network and --rollup-rpc " +
+			"when additional networks are monitored. Defaults to the --network value if set.",
+		EnvVars: prefixEnvVars("NETWORK_NAME"),
+		Value:   config.DefaultNetworkName,
+	}
+	AdditionalNetworksFlag = &cli.StringSliceFlag{
+		Name: "additional-networks",
+		Usage: "Additional networks to monitor, in the form <name>:<game-factory-address>:<rollup-rpc>. " +
+			"The game factory address may be omitted if the name is a known network, in which case withdrawal proofs are also checked. " +
+			"Metrics are labelled with the network name when set.",
+		EnvVars: prefixEnvVars("ADDITIONAL_NETWORKS"),
+	}
+	GameBondsAtRiskThresholdFlag = &cli.Uint64Flag{
+		Name:    "game-bonds-at-risk-threshold-gwei",
+		Usage:   "Alert when honest actors are forecast to lose more than this amount of bonds in a single game, in gwei. 0 to disable.",
+		EnvVars: prefixEnvVars("GAME_BONDS_AT_RISK_THRESHOLD_GWEI"),
+	}
+	TotalBondsAtRiskThresholdFlag = &cli.Uint64Flag{
+		Name:    "total-bonds-at-risk-threshold-gwei",
+		Usage:   "Alert when honest actors are forecast to lose more than this amount of bonds across all games, in gwei. 0 to disable.",
+		EnvVars: prefixEnvVars("TOTAL_BONDS_AT_RISK_THRESHOLD_GWEI"),
+	}
+	StatusEnabledFlag = &cli.BoolFlag{
+		Name:    "status.enabled",
+		Usage:   "Enable the HTTP server exposing the current assessment of monitored games as JSON at /status",
+		EnvVars: prefixEnvVars("STATUS_ENABLED"),
+	}
+	StatusAddrFlag = &cli.StringFlag{
+		Name:    "status.addr",
+		Usage:   "Status API listening address",
+		EnvVars: prefixEnvVars("STATUS_ADDR"),
+		Value:   config.DefaultStatusListenAddr,
+	}
+	StatusPortFlag = &cli.IntFlag{
+		Name:    "status.port",
+		Usage:   "Status API listening port",
+		EnvVars: prefixEnvVars("STATUS_PORT"),
+		Value:   config.DefaultStatusListenPort,
+	}
 )
 
 // requiredFlags are checked by [CheckRequired]
@@ -82,12 +134,20 @@ var requiredFlags = []cli.Flag{
 // optionalFlags is a list of unchecked cli flags
 var optionalFlags = []cli.Flag{
 	GameFactoryAddressFlag,
+	OptimismPortalAddressFlag,
 	NetworkFlag,
 	HonestActorsFlag,
 	MonitorIntervalFlag,
 	GameWindowFlag,
 	IgnoredGamesFlag,
 	MaxConcurrencyFlag,
+	NetworkNameFlag,
+	AdditionalNetworksFlag,
+	GameBondsAtRiskThresholdFlag,
+	TotalBondsAtRiskThresholdFlag,
+	StatusEnabledFlag,
+	StatusAddrFlag,
+	StatusPortFlag,
 }
 
 func init() {
@@ -120,6 +180,11 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		return nil, err
 	}
 
+	portalAddress, err := optimismPortalAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var actors []common.Address
 	if ctx.IsSet(HonestActorsFlag.Name) {
 		for _, addrStr := range ctx.StringSlice(HonestActorsFlag.Name) {
@@ -147,6 +212,19 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		return nil, fmt.Errorf("%v must not be 0", MaxConcurrencyFlag.Name)
 	}
 
+	networkName := ctx.String(NetworkNameFlag.Name)
+	if !ctx.IsSet(NetworkNameFlag.Name) && ctx.IsSet(flags.NetworkFlagName) {
+		networkName = ctx.String(flags.NetworkFlagName)
+	}
+	var additionalNetworks []config.NetworkConfig
+	for _, value := range ctx.StringSlice(AdditionalNetworksFlag.Name) {
+		network, err := parseNetwork(value)
+		if err != nil {
+			return nil, err
+		}
+		additionalNetworks = append(additionalNetworks, network)
+	}
+
 	metricsConfig := opmetrics.ReadCLIConfig(ctx)
 	pprofConfig := oppprof.ReadCLIConfig(ctx)
 
@@ -155,13 +233,87 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		GameFactoryAddress: gameFactoryAddress,
 		RollupRpc:          ctx.String(RollupRpcFlag.Name),
 
+		OptimismPortalAddress: portalAddress,
+
 		HonestActors:    actors,
 		MonitorInterval: ctx.Duration(MonitorIntervalFlag.Name),
 		GameWindow:      ctx.Duration(GameWindowFlag.Name),
 		IgnoredGames:    ignoredGames,
 		MaxConcurrency:  maxConcurrency,
 
+		NetworkName:        networkName,
+		AdditionalNetworks: additionalNetworks,
+
+		GameBondsAtRiskThreshold:  gweiThreshold(ctx, GameBondsAtRiskThresholdFlag.Name),
+		TotalBondsAtRiskThreshold: gweiThreshold(ctx, TotalBondsAtRiskThresholdFlag.Name),
+
+		StatusConfig: config.StatusConfig{
+			Enabled:    ctx.Bool(StatusEnabledFlag.Name),
+			ListenAddr: ctx.String(StatusAddrFlag.Name),
+			ListenPort: ctx.Int(StatusPortFlag.Name),
+		},
 		MetricsConfig: metricsConfig,
 		PprofConfig:   pprofConfig,
 	}, nil
 }
+
+// parseNetwork parses a network to monitor in the form <name>:<game-factory-address>:<rollup-rpc>.
+// The game factory address is loaded from the superchain registry if it is omitted.
+func parseNetwork(value string) (config.NetworkConfig, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return config.NetworkConfig{}, fmt.Errorf("invalid network %q: expected <name>:<game-factory-address>:<rollup-rpc>", value)
+	}
+	network := config.NetworkConfig{
+		Name:      parts[0],
+		RollupRpc: parts[2],
+	}
+	if parts[1] != "" {
+		addr, err := opservice.ParseAddress(parts[1])
+		if err != nil {
+			return config.NetworkConfig{}, fmt.Errorf("invalid game factory address for network %v: %w", network.Name, err)
+		}
+		network.GameFactoryAddress = addr
+		return network, nil
+	}
+	chainCfg := chaincfg.ChainByName(network.Name)
+	if chainCfg == nil {
+		return config.NetworkConfig{}, fmt.Errorf("unknown chain: %v", network.Name)
+	}
+	addrs, ok := superchain.Addresses[chainCfg.ChainID]
+	if !ok || addrs.DisputeGameFactoryProxy == (superchain.Address{}) {
+		return config.NetworkConfig{}, fmt.Errorf("dispute factory proxy not available for chain %v", network.Name)
+	}
+	network.GameFactoryAddress = common.Address(addrs.DisputeGameFactoryProxy)
+	network.OptimismPortalAddress = common.Address(addrs.OptimismPortalProxy)
+	return network, nil
+}
+
+// optimismPortalAddress returns the portal to check withdrawal proofs in, preferring the explicitly configured address
+// over the portal of the selected network. Returns the zero address if withdrawal proofs should not be checked.
+func optimismPortalAddress(ctx *cli.Context) (common.Address, error) {
+	if ctx.IsSet(OptimismPortalAddressFlag.Name) {
+		addr, err := opservice.ParseAddress(ctx.String(OptimismPortalAddressFlag.Name))
+		if err != nil {
+			return common.Address{}, fmt.Errorf("invalid optimism portal address: %w", err)
+		}
+		return addr, nil
+	}
+	if !ctx.IsSet(flags.NetworkFlagName) {
+		return common.Address{}, nil
+	}
+	chainCfg := chaincfg.ChainByName(ctx.String(flags.NetworkFlagName))
+	if chainCfg == nil {
+		return common.Address{}, nil
+	}
+	return common.Address(superchain.Addresses[chainCfg.ChainID].OptimismPortalProxy), nil
+}
+
+// gweiThreshold reads a threshold in gwei from the named flag and returns it in wei, or nil if it is disabled.
+func gweiThreshold(ctx *cli.Context, name string) *big.Int {
+	gwei := ctx.Uint64(name)
+	if gwei == 0 {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gwei), big.NewInt(params.GWei))
+}
@@ -67,6 +67,15 @@ const (
 	DisagreeChallengerWins
 )
 
+type WithdrawalProofStatus uint8
+
+const (
+	ProofAgainstValidGame WithdrawalProofStatus = iota
+	ProofAgainstInProgressGame
+	ProofAgainstBlacklistedGame
+	ProofAgainstInvalidGame
+)
+
 type ClaimStatus struct {
 	resolved     bool
 	clockExpired bool
@@ -149,6 +158,16 @@ type HonestActorData struct {
 	WonBonds          *big.Int
 }
 
+// BondsAtRiskData records the unresolved bonds honest actors are forecast to lose or gain in games in progress.
+type BondsAtRiskData struct {
+	Losing             *big.Int
+	Gaining            *big.Int
+	GamesLosing        int      // Number of games where honest actors are forecast to lose bonds
+	MaxGameLosing      *big.Int // Largest amount honest actors are forecast to lose in a single game
+	GamesOverThreshold int      // Number of games where the bonds forecast to be lost exceed the per-game threshold
+	TotalOverThreshold bool     // Whether the total bonds forecast to be lost exceed the total threshold
+}
+
 type Metricer interface {
 	RecordInfo(version string)
 	RecordUp()
@@ -159,8 +178,12 @@ type Metricer interface {
 
 	RecordHonestActorClaims(address common.Address, stats *HonestActorData)
 
+	RecordBondsAtRisk(data *BondsAtRiskData)
+
 	RecordGameResolutionStatus(status ResolutionStatus, count int)
 
+	RecordOverdueResolutions(count int)
+
 	RecordCredit(expectation CreditExpectation, count int)
 
 	RecordHonestWithdrawableAmounts(map[common.Address]*big.Int)
@@ -179,10 +202,14 @@ type Metricer interface {
 
 	RecordIgnoredGames(count int)
 
+	RecordIncorrectForecasts(clockExpired bool, count int)
+
 	RecordBondCollateral(addr common.Address, required, available *big.Int)
 
 	RecordL2Challenges(agreement bool, count int)
 
+	RecordWithdrawalProofs(status WithdrawalProofStatus, count int)
+
 	caching.Metrics
 	contractMetrics.ContractMetricer
 }
@@ -200,13 +227,19 @@ type Metrics struct {
 
 	monitorDuration prometheus.Histogram
 
-	resolutionStatus prometheus.GaugeVec
+	resolutionStatus   prometheus.GaugeVec
+	overdueResolutions prometheus.Gauge
 
 	claims prometheus.GaugeVec
 
 	honestActorClaims prometheus.GaugeVec
 	honestActorBonds  prometheus.GaugeVec
 
+	bondsAtRisk           prometheus.GaugeVec
+	gamesLosingBonds      prometheus.Gauge
+	maxGameBondsAtRisk    prometheus.Gauge
+	bondsAtRiskThresholds prometheus.GaugeVec
+
 	withdrawalRequests prometheus.GaugeVec
 
 	info prometheus.GaugeVec
@@ -220,9 +253,11 @@ type Metrics struct {
 	gamesAgreement             prometheus.GaugeVec
 	latestValidProposalL2Block prometheus.Gauge
 	latestProposals            prometheus.GaugeVec
+	incorrectForecasts         prometheus.GaugeVec
 	ignoredGames               prometheus.Gauge
 	failedGames                prometheus.Gauge
 	l2Challenges               prometheus.GaugeVec
+	withdrawalProofs           prometheus.GaugeVec
 
 	requiredCollateral  prometheus.GaugeVec
 	availableCollateral prometheus.GaugeVec
@@ -236,8 +271,16 @@ var _ Metricer = (*Metrics)(nil)
 
 func NewMetrics() *Metrics {
 	registry := opmetrics.NewRegistry()
-	factory := opmetrics.With(registry)
+	return newMetrics(registry, opmetrics.With(registry))
+}
 
+// NewNetworkMetrics creates metrics for monitoring a single network of many, registered with the shared registry.
+// All metrics are labelled with the network name.
+func NewNetworkMetrics(registry *prometheus.Registry, network string) *Metrics {
+	return newMetrics(registry, opmetrics.WithLabels(registry, prometheus.Labels{"network": network}))
+}
+
+func newMetrics(registry *prometheus.Registry, factory opmetrics.Factory) *Metrics {
 	return &Metrics{
 		ns:       Namespace,
 		registry: registry,
@@ -285,6 +328,30 @@ func NewMetrics() *Metrics {
 			"honest_actor_address",
 			"state",
 		}),
+		bondsAtRisk: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "bonds_at_risk",
+			Help:      "Sum of unresolved bonds honest actors are forecast to lose or gain in games in progress",
+		}, []string{
+			"state",
+		}),
+		gamesLosingBonds: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "games_losing_bonds",
+			Help:      "Number of games in progress where honest actors are forecast to lose bonds",
+		}),
+		maxGameBondsAtRisk: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "max_game_bonds_at_risk",
+			Help:      "Largest sum of bonds honest actors are forecast to lose in a single game",
+		}),
+		bondsAtRiskThresholds: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "bonds_at_risk_threshold_exceeded",
+			Help:      "Number of games, or 1 for the total, where the bonds honest actors are forecast to lose exceed the configured threshold",
+		}, []string{
+			"scope",
+		}),
 		resolutionStatus: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
 			Name:      "resolution_status",
@@ -293,6 +360,11 @@ func NewMetrics() *Metrics {
 			"completion",
 			"max_duration",
 		}),
+		overdueResolutions: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "overdue_resolutions",
+			Help:      "Number of in progress games that have not been resolved long after their claim clocks expired",
+		}),
 		credits: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
 			Name:      "credits",
@@ -377,6 +449,14 @@ func NewMetrics() *Metrics {
 			"delayedWETH",
 			"balance",
 		}),
+		incorrectForecasts: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "incorrect_forecasts",
+			Help:      "Number of in progress games forecast to resolve incorrectly",
+		}, []string{
+			// Whether the claim clocks have expired so no further moves can change the outcome.
+			"clock_expired",
+		}),
 		l2Challenges: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
 			Name:      "l2_block_challenges",
@@ -386,6 +466,13 @@ func NewMetrics() *Metrics {
 			// An l2 block number challenge with an agreement means the challenge was invalid.
 			"root_agreement",
 		}),
+		withdrawalProofs: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "withdrawal_proofs",
+			Help:      "Number of withdrawal proofs by the status of the dispute game they were proven against",
+		}, []string{
+			"game_status",
+		}),
 	}
 }
 
@@ -427,6 +514,19 @@ func (m *Metrics) RecordHonestActorClaims(address common.Address, stats *HonestA
 	m.honestActorBonds.WithLabelValues(address.Hex(), "won").Set(weiToEther(stats.WonBonds))
 }
 
+func (m *Metrics) RecordBondsAtRisk(data *BondsAtRiskData) {
+	m.bondsAtRisk.WithLabelValues("losing").Set(weiToEther(data.Losing))
+	m.bondsAtRisk.WithLabelValues("gaining").Set(weiToEther(data.Gaining))
+	m.gamesLosingBonds.Set(float64(data.GamesLosing))
+	m.maxGameBondsAtRisk.Set(weiToEther(data.MaxGameLosing))
+	m.bondsAtRiskThresholds.WithLabelValues("game").Set(float64(data.GamesOverThreshold))
+	total := 0
+	if data.TotalOverThreshold {
+		total = 1
+	}
+	m.bondsAtRiskThresholds.WithLabelValues("total").Set(float64(total))
+}
+
 func (m *Metrics) RecordGameResolutionStatus(status ResolutionStatus, count int) {
 	asLabels := func(status ResolutionStatus) []string {
 		switch status {
@@ -449,6 +549,10 @@ func (m *Metrics) RecordGameResolutionStatus(status ResolutionStatus, count int)
 	m.resolutionStatus.WithLabelValues(asLabels(status)...).Set(float64(count))
 }
 
+func (m *Metrics) RecordOverdueResolutions(count int) {
+	m.overdueResolutions.Set(float64(count))
+}
+
 func (m *Metrics) RecordCredit(expectation CreditExpectation, count int) {
 	asLabels := func(expectation CreditExpectation) []string {
 		switch expectation {
@@ -516,6 +620,14 @@ func (m *Metrics) RecordIgnoredGames(count int) {
 	m.ignoredGames.Set(float64(count))
 }
 
+func (m *Metrics) RecordIncorrectForecasts(clockExpired bool, count int) {
+	expired := "false"
+	if clockExpired {
+		expired = "true"
+	}
+	m.incorrectForecasts.WithLabelValues(expired).Set(float64(count))
+}
+
 func (m *Metrics) RecordFailedGames(count int) {
 	m.failedGames.Set(float64(count))
 }
@@ -543,6 +655,24 @@ func (m *Metrics) RecordL2Challenges(agreement bool, count int) {
 	m.l2Challenges.WithLabelValues(agree).Set(float64(count))
 }
 
+func (m *Metrics) RecordWithdrawalProofs(status WithdrawalProofStatus, count int) {
+	asLabel := func(status WithdrawalProofStatus) string {
+		switch status {
+		case ProofAgainstValidGame:
+			return "valid"
+		case ProofAgainstInProgressGame:
+			return "in_progress"
+		case ProofAgainstBlacklistedGame:
+			return "blacklisted"
+		case ProofAgainstInvalidGame:
+			return "invalid"
+		default:
+			panic(fmt.Errorf("unknown withdrawal proof status: %v", status))
+		}
+	}
+	m.withdrawalProofs.WithLabelValues(asLabel(status)).Set(float64(count))
+}
+
 const (
 	inProgress = true
 	correct    = true
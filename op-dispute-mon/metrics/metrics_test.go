@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"testing"
+
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkMetricsShareRegistry(t *testing.T) {
+	registry := opmetrics.NewRegistry()
+	NewNetworkMetrics(registry, "a").RecordIgnoredGames(1)
+	NewNetworkMetrics(registry, "b").RecordIgnoredGames(2)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	ignored := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != Namespace+"_ignored_games" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			require.Len(t, metric.GetLabel(), 1)
+			require.Equal(t, "network", metric.GetLabel()[0].GetName())
+			ignored[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+	}
+	require.Equal(t, map[string]float64{"a": 1, "b": 2}, ignored)
+}
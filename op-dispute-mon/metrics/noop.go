@@ -24,8 +24,12 @@ func (*NoopMetricsImpl) CacheGet(_ string, _ bool)        {}
 
 func (*NoopMetricsImpl) RecordHonestActorClaims(_ common.Address, _ *HonestActorData) {}
 
+func (*NoopMetricsImpl) RecordBondsAtRisk(_ *BondsAtRiskData) {}
+
 func (*NoopMetricsImpl) RecordGameResolutionStatus(_ ResolutionStatus, _ int) {}
 
+func (*NoopMetricsImpl) RecordOverdueResolutions(_ int) {}
+
 func (*NoopMetricsImpl) RecordCredit(_ CreditExpectation, _ int) {}
 
 func (*NoopMetricsImpl) RecordHonestWithdrawableAmounts(map[common.Address]*big.Int) {}
@@ -44,8 +48,12 @@ func (*NoopMetricsImpl) RecordLatestProposals(_, _ uint64) {}
 
 func (*NoopMetricsImpl) RecordIgnoredGames(_ int) {}
 
+func (*NoopMetricsImpl) RecordIncorrectForecasts(_ bool, _ int) {}
+
 func (*NoopMetricsImpl) RecordFailedGames(_ int) {}
 
 func (*NoopMetricsImpl) RecordBondCollateral(_ common.Address, _, _ *big.Int) {}
 
 func (*NoopMetricsImpl) RecordL2Challenges(_ bool, _ int) {}
+
+func (*NoopMetricsImpl) RecordWithdrawalProofs(_ WithdrawalProofStatus, _ int) {}
@@ -0,0 +1,98 @@
+package mon
+
+import (
+	"math/big"
+
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/transform"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type BondsAtRiskMetrics interface {
+	RecordBondsAtRisk(data *metrics.BondsAtRiskData)
+}
+
+// BondsAtRiskMonitor reports the bonds honest actors stand to lose or gain in games that are in progress,
+// based on how the game would resolve if no further moves were made.
+type BondsAtRiskMonitor struct {
+	logger       log.Logger
+	metrics      BondsAtRiskMetrics
+	honestActors types.HonestActors
+
+	// gameThreshold is the amount of honest bonds forecast to be lost in a single game that triggers an alert.
+	// Nil to disable the alert.
+	gameThreshold *big.Int
+	// totalThreshold is the amount of honest bonds forecast to be lost across all games that triggers an alert.
+	// Nil to disable the alert.
+	totalThreshold *big.Int
+}
+
+func NewBondsAtRiskMonitor(logger log.Logger, metrics BondsAtRiskMetrics, honestActors types.HonestActors, gameThreshold, totalThreshold *big.Int) *BondsAtRiskMonitor {
+	return &BondsAtRiskMonitor{
+		logger:         logger,
+		metrics:        metrics,
+		honestActors:   honestActors,
+		gameThreshold:  gameThreshold,
+		totalThreshold: totalThreshold,
+	}
+}
+
+func (b *BondsAtRiskMonitor) CheckBondsAtRisk(games []*types.EnrichedGameData) {
+	data := &metrics.BondsAtRiskData{
+		Losing:        big.NewInt(0),
+		Gaining:       big.NewInt(0),
+		MaxGameLosing: big.NewInt(0),
+	}
+	for _, game := range games {
+		if game.Status != gameTypes.GameStatusInProgress {
+			continue
+		}
+		losing, gaining := b.gameBondsAtRisk(game)
+		data.Losing.Add(data.Losing, losing)
+		data.Gaining.Add(data.Gaining, gaining)
+		if losing.Sign() > 0 {
+			data.GamesLosing++
+			b.logger.Warn("Honest actors forecast to lose bonds", "game", game.Proxy, "losing", losing, "gaining", gaining)
+		}
+		if losing.Cmp(data.MaxGameLosing) > 0 {
+			data.MaxGameLosing = losing
+		}
+		if b.gameThreshold != nil && losing.Cmp(b.gameThreshold) > 0 {
+			data.GamesOverThreshold++
+			b.logger.Error("Honest bonds at risk in game exceed threshold", "game", game.Proxy, "losing", losing, "threshold", b.gameThreshold)
+		}
+	}
+	if b.totalThreshold != nil && data.Losing.Cmp(b.totalThreshold) > 0 {
+		data.TotalOverThreshold = true
+		b.logger.Error("Total honest bonds at risk exceed threshold", "losing", data.Losing, "threshold", b.totalThreshold)
+	}
+	b.metrics.RecordBondsAtRisk(data)
+}
+
+// gameBondsAtRisk returns the unresolved honest bonds that would be lost and the unresolved bonds honest actors would
+// win if the game resolved with its current claims.
+func (b *BondsAtRiskMonitor) gameBondsAtRisk(game *types.EnrichedGameData) (*big.Int, *big.Int) {
+	losing := big.NewInt(0)
+	gaining := big.NewInt(0)
+	if len(b.honestActors) == 0 {
+		return losing, gaining
+	}
+	// The tree copies the claims so resolving it doesn't modify the game data.
+	tree := transform.CreateBidirectionalTree(game.Claims)
+	Resolve(tree)
+	for i, claim := range game.Claims {
+		if claim.Resolved || claim.Bond == nil {
+			continue
+		}
+		counteredBy := tree.Claims[i].Claim.CounteredBy
+		if b.honestActors.Contains(claim.Claimant) && counteredBy != (common.Address{}) && !b.honestActors.Contains(counteredBy) {
+			losing.Add(losing, claim.Bond)
+		} else if !b.honestActors.Contains(claim.Claimant) && b.honestActors.Contains(counteredBy) {
+			gaining.Add(gaining, claim.Bond)
+		}
+	}
+	return losing, gaining
+}
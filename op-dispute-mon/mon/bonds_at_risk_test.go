@@ -0,0 +1,168 @@
+package mon
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	riskHonest    = common.Address{0xaa}
+	riskHonest2   = common.Address{0xab}
+	riskDishonest = common.Address{0xbb}
+)
+
+func TestCheckBondsAtRisk(t *testing.T) {
+	t.Run("NoGames", func(t *testing.T) {
+		monitor, m, _ := setupBondsAtRiskTest(t, nil, nil)
+		monitor.CheckBondsAtRisk(nil)
+		require.Equal(t, &metrics.BondsAtRiskData{
+			Losing:        big.NewInt(0),
+			Gaining:       big.NewInt(0),
+			MaxGameLosing: big.NewInt(0),
+		}, m.data)
+	})
+
+	t.Run("HonestClaimCountered", func(t *testing.T) {
+		monitor, m, logs := setupBondsAtRiskTest(t, nil, nil)
+		// Dishonest root is countered by honest claim which is countered by dishonest claim, so root is uncontested.
+		game := newBondsAtRiskGame(common.Address{0x01},
+			newRiskClaim(0, math.MaxInt64, riskDishonest, 1, false),
+			newRiskClaim(1, 0, riskHonest, 10, false),
+			newRiskClaim(2, 1, riskDishonest, 100, false),
+		)
+		monitor.CheckBondsAtRisk([]*types.EnrichedGameData{game})
+		require.Equal(t, big.NewInt(10), m.data.Losing)
+		require.Equal(t, big.NewInt(0), m.data.Gaining)
+		require.Equal(t, 1, m.data.GamesLosing)
+		require.Equal(t, big.NewInt(10), m.data.MaxGameLosing)
+
+		l := logs.FindLog(testlog.NewLevelFilter(log.LevelWarn), testlog.NewMessageFilter("Honest actors forecast to lose bonds"))
+		require.NotNil(t, l)
+		require.Equal(t, game.Proxy, l.AttrValue("game"))
+	})
+
+	t.Run("HonestCounterWinning", func(t *testing.T) {
+		monitor, m, _ := setupBondsAtRiskTest(t, nil, nil)
+		game := newBondsAtRiskGame(common.Address{0x01},
+			newRiskClaim(0, math.MaxInt64, riskDishonest, 1, false),
+			newRiskClaim(1, 0, riskHonest, 10, false),
+		)
+		monitor.CheckBondsAtRisk([]*types.EnrichedGameData{game})
+		require.Equal(t, big.NewInt(0), m.data.Losing)
+		require.Equal(t, big.NewInt(1), m.data.Gaining)
+		require.Zero(t, m.data.GamesLosing)
+	})
+
+	t.Run("IgnoreBondsBetweenHonestActors", func(t *testing.T) {
+		monitor, m, _ := setupBondsAtRiskTest(t, nil, nil)
+		game := newBondsAtRiskGame(common.Address{0x01},
+			newRiskClaim(0, math.MaxInt64, riskHonest, 1, false),
+			newRiskClaim(1, 0, riskHonest2, 10, false),
+		)
+		monitor.CheckBondsAtRisk([]*types.EnrichedGameData{game})
+		require.Equal(t, big.NewInt(0), m.data.Losing)
+		require.Equal(t, big.NewInt(0), m.data.Gaining)
+	})
+
+	t.Run("IgnoreResolvedClaimsAndGames", func(t *testing.T) {
+		monitor, m, _ := setupBondsAtRiskTest(t, nil, nil)
+		resolvedClaims := newBondsAtRiskGame(common.Address{0x01},
+			newRiskClaim(0, math.MaxInt64, riskDishonest, 1, true),
+			newRiskClaim(1, 0, riskHonest, 10, true),
+			newRiskClaim(2, 1, riskDishonest, 100, true),
+		)
+		resolvedGame := newBondsAtRiskGame(common.Address{0x02},
+			newRiskClaim(0, math.MaxInt64, riskDishonest, 1, false),
+			newRiskClaim(1, 0, riskHonest, 10, false),
+			newRiskClaim(2, 1, riskDishonest, 100, false),
+		)
+		resolvedGame.Status = gameTypes.GameStatusDefenderWon
+		monitor.CheckBondsAtRisk([]*types.EnrichedGameData{resolvedClaims, resolvedGame})
+		require.Equal(t, big.NewInt(0), m.data.Losing)
+		require.Equal(t, big.NewInt(0), m.data.Gaining)
+	})
+
+	t.Run("DoesNotModifyGameClaims", func(t *testing.T) {
+		monitor, _, _ := setupBondsAtRiskTest(t, nil, nil)
+		game := newBondsAtRiskGame(common.Address{0x01},
+			newRiskClaim(0, math.MaxInt64, riskDishonest, 1, false),
+			newRiskClaim(1, 0, riskHonest, 10, false),
+		)
+		monitor.CheckBondsAtRisk([]*types.EnrichedGameData{game})
+		require.Equal(t, common.Address{}, game.Claims[0].CounteredBy)
+	})
+
+	t.Run("Thresholds", func(t *testing.T) {
+		monitor, m, logs := setupBondsAtRiskTest(t, big.NewInt(15), big.NewInt(25))
+		newLosingGame := func(addr common.Address, bond int64) *types.EnrichedGameData {
+			return newBondsAtRiskGame(addr,
+				newRiskClaim(0, math.MaxInt64, riskDishonest, 1, false),
+				newRiskClaim(1, 0, riskHonest, bond, false),
+				newRiskClaim(2, 1, riskDishonest, 100, false),
+			)
+		}
+		monitor.CheckBondsAtRisk([]*types.EnrichedGameData{
+			newLosingGame(common.Address{0x01}, 10),
+			newLosingGame(common.Address{0x02}, 15),
+			newLosingGame(common.Address{0x03}, 16),
+		})
+		require.Equal(t, big.NewInt(41), m.data.Losing)
+		require.Equal(t, 3, m.data.GamesLosing)
+		require.Equal(t, big.NewInt(16), m.data.MaxGameLosing)
+		require.Equal(t, 1, m.data.GamesOverThreshold)
+		require.True(t, m.data.TotalOverThreshold)
+
+		l := logs.FindLog(testlog.NewLevelFilter(log.LevelError), testlog.NewMessageFilter("Honest bonds at risk in game exceed threshold"))
+		require.NotNil(t, l)
+		require.Equal(t, common.Address{0x03}, l.AttrValue("game"))
+		require.NotNil(t, logs.FindLog(testlog.NewLevelFilter(log.LevelError), testlog.NewMessageFilter("Total honest bonds at risk exceed threshold")))
+	})
+}
+
+func setupBondsAtRiskTest(t *testing.T, gameThreshold, totalThreshold *big.Int) (*BondsAtRiskMonitor, *stubBondsAtRiskMetrics, *testlog.CapturingHandler) {
+	logger, logs := testlog.CaptureLogger(t, log.LvlDebug)
+	m := &stubBondsAtRiskMetrics{}
+	honestActors := types.NewHonestActors([]common.Address{riskHonest, riskHonest2})
+	return NewBondsAtRiskMonitor(logger, m, honestActors, gameThreshold, totalThreshold), m, logs
+}
+
+func newBondsAtRiskGame(addr common.Address, claims ...types.EnrichedClaim) *types.EnrichedGameData {
+	return &types.EnrichedGameData{
+		GameMetadata: gameTypes.GameMetadata{Proxy: addr},
+		Status:       gameTypes.GameStatusInProgress,
+		Claims:       claims,
+	}
+}
+
+func newRiskClaim(idx int, parentIdx int, claimant common.Address, bond int64, resolved bool) types.EnrichedClaim {
+	return types.EnrichedClaim{
+		Claim: faultTypes.Claim{
+			ClaimData: faultTypes.ClaimData{
+				Bond:     big.NewInt(bond),
+				Position: faultTypes.NewPosition(faultTypes.Depth(idx), big.NewInt(0)),
+			},
+			Claimant:            claimant,
+			ContractIndex:       idx,
+			ParentContractIndex: parentIdx,
+		},
+		Resolved: resolved,
+	}
+}
+
+type stubBondsAtRiskMetrics struct {
+	data *metrics.BondsAtRiskData
+}
+
+func (s *stubBondsAtRiskMetrics) RecordBondsAtRisk(data *metrics.BondsAtRiskData) {
+	s.data = data
+}
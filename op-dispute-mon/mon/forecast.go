@@ -20,6 +20,7 @@ type ForecastMetrics interface {
 	RecordLatestProposals(validTimestamp, invalidTimestamp uint64)
 	RecordIgnoredGames(count int)
 	RecordFailedGames(count int)
+	RecordIncorrectForecasts(clockExpired bool, count int)
 }
 
 type forecastBatch struct {
@@ -28,6 +29,10 @@ type forecastBatch struct {
 	AgreeChallengerAhead    int
 	DisagreeChallengerAhead int
 
+	// Incorrect forecasts for in progress games, split by whether further moves can still change the outcome.
+	IncorrectClockExpired int
+	IncorrectInProgress   int
+
 	AgreeDefenderWins      int
 	DisagreeDefenderWins   int
 	AgreeChallengerWins    int
@@ -40,12 +45,14 @@ type forecastBatch struct {
 
 type Forecast struct {
 	logger  log.Logger
+	clock   RClock
 	metrics ForecastMetrics
 }
 
-func NewForecast(logger log.Logger, metrics ForecastMetrics) *Forecast {
+func NewForecast(logger log.Logger, metrics ForecastMetrics, clock RClock) *Forecast {
 	return &Forecast{
 		logger:  logger,
+		clock:   clock,
 		metrics: metrics,
 	}
 }
@@ -71,6 +78,9 @@ func (f *Forecast) recordBatch(batch forecastBatch, ignoredCount, failedCount in
 	f.metrics.RecordGameAgreement(metrics.AgreeDefenderAhead, batch.AgreeDefenderAhead)
 	f.metrics.RecordGameAgreement(metrics.DisagreeDefenderAhead, batch.DisagreeDefenderAhead)
 
+	f.metrics.RecordIncorrectForecasts(true, batch.IncorrectClockExpired)
+	f.metrics.RecordIncorrectForecasts(false, batch.IncorrectInProgress)
+
 	f.metrics.RecordLatestValidProposalL2Block(batch.LatestValidProposalL2Block)
 	f.metrics.RecordLatestProposals(batch.LatestValidProposal, batch.LatestInvalidProposal)
 
@@ -164,5 +174,22 @@ func (f *Forecast) forecastGame(game *monTypes.EnrichedGameData, metrics *foreca
 		}
 	}
 
+	if forecastStatus != expectedResult {
+		f.recordIncorrectForecast(game, forecastStatus, expectedResult, metrics)
+	}
 	return nil
 }
+
+// recordIncorrectForecast categorises an in progress game forecast to resolve incorrectly by whether the claim
+// clocks have expired. Once they have, no further moves can be made and the game will resolve incorrectly.
+func (f *Forecast) recordIncorrectForecast(game *monTypes.EnrichedGameData, forecastStatus, expectedResult types.GameStatus, metrics *forecastBatch) {
+	expiry, ok := clocksExpireAt(game)
+	if ok && !f.clock.Now().Before(expiry) {
+		metrics.IncorrectClockExpired++
+		f.logger.Error("Game will resolve incorrectly", "game", game.Proxy, "blockNum", game.L2BlockNumber,
+			"forecast", forecastStatus, "expectedResult", expectedResult, "clocksExpired", expiry,
+			"rootClaim", game.RootClaim, "correctClaim", game.ExpectedRootClaim)
+		return
+	}
+	metrics.IncorrectInProgress++
+}
@@ -4,11 +4,13 @@ import (
 	"math"
 	"math/big"
 	"testing"
+	"time"
 
 	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
 	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -296,14 +298,67 @@ func TestForecast_Forecast_MultipleGames(t *testing.T) {
 	require.EqualValues(t, 8, m.latestValidProposalL2Block)
 	require.EqualValues(t, 7, m.latestInvalidProposal)
 	require.EqualValues(t, 8, m.latestValidProposal)
+	// Claim clocks have all expired since the claims have no timestamp and the max clock duration is 0
+	require.Equal(t, map[bool]int{true: 2, false: 0}, m.incorrectForecasts)
+}
+
+func TestForecast_Forecast_IncorrectForecast(t *testing.T) {
+	createGame := func(cl *clock.DeterministicClock, agree bool, remaining time.Duration) *monTypes.EnrichedGameData {
+		maxClockDuration := time.Hour
+		claims := createDeepClaimList()[:1]
+		// Root claim is uncountered so the forecast is defender wins.
+		claims[0].Clock = faultTypes.Clock{Timestamp: cl.Now().Add(remaining - maxClockDuration)}
+		return &monTypes.EnrichedGameData{
+			Status:            types.GameStatusInProgress,
+			Claims:            claims,
+			RootClaim:         mockRootClaim,
+			AgreeWithClaim:    agree,
+			ExpectedRootClaim: common.Hash{0xaa},
+			MaxClockDuration:  uint64(maxClockDuration.Seconds()),
+		}
+	}
+
+	t.Run("CorrectForecast", func(t *testing.T) {
+		forecast, m, logs, cl := setupForecastTestWithClock(t)
+		forecast.Forecast([]*monTypes.EnrichedGameData{createGame(cl, true, 0)}, 0, 0)
+		require.Equal(t, map[bool]int{true: 0, false: 0}, m.incorrectForecasts)
+		require.Nil(t, logs.FindLog(testlog.NewMessageFilter("Game will resolve incorrectly")))
+	})
+
+	t.Run("ClockNotExpired", func(t *testing.T) {
+		forecast, m, logs, cl := setupForecastTestWithClock(t)
+		forecast.Forecast([]*monTypes.EnrichedGameData{createGame(cl, false, time.Minute)}, 0, 0)
+		require.Equal(t, map[bool]int{true: 0, false: 1}, m.incorrectForecasts)
+		require.NotNil(t, logs.FindLog(testlog.NewLevelFilter(log.LevelWarn), testlog.NewMessageFilter(unexpectedResultLog)))
+		require.Nil(t, logs.FindLog(testlog.NewMessageFilter("Game will resolve incorrectly")))
+	})
+
+	t.Run("ClockExpired", func(t *testing.T) {
+		forecast, m, logs, cl := setupForecastTestWithClock(t)
+		game := createGame(cl, false, 0)
+		forecast.Forecast([]*monTypes.EnrichedGameData{game}, 0, 0)
+		require.Equal(t, map[bool]int{true: 1, false: 0}, m.incorrectForecasts)
+		l := logs.FindLog(testlog.NewLevelFilter(log.LevelError), testlog.NewMessageFilter("Game will resolve incorrectly"))
+		require.NotNil(t, l)
+		require.Equal(t, types.GameStatusDefenderWon, l.AttrValue("forecast"))
+		require.Equal(t, types.GameStatusChallengerWon, l.AttrValue("expectedResult"))
+		require.Equal(t, cl.Now(), l.AttrValue("clocksExpired"))
+	})
 }
 
 func setupForecastTest(t *testing.T) (*Forecast, *mockForecastMetrics, *testlog.CapturingHandler) {
+	forecast, m, logs, _ := setupForecastTestWithClock(t)
+	return forecast, m, logs
+}
+
+func setupForecastTestWithClock(t *testing.T) (*Forecast, *mockForecastMetrics, *testlog.CapturingHandler, *clock.DeterministicClock) {
 	logger, capturedLogs := testlog.CaptureLogger(t, log.LvlDebug)
 	m := &mockForecastMetrics{
-		gameAgreement: zeroGameAgreement(),
+		gameAgreement:      zeroGameAgreement(),
+		incorrectForecasts: make(map[bool]int),
 	}
-	return NewForecast(logger, m), m, capturedLogs
+	cl := clock.NewDeterministicClock(time.Unix(int64(time.Hour.Seconds()), 0))
+	return NewForecast(logger, m, cl), m, capturedLogs, cl
 }
 
 func zeroGameAgreement() map[metrics.GameAgreementStatus]int {
@@ -326,6 +381,7 @@ type mockForecastMetrics struct {
 	latestInvalidProposal      uint64
 	latestValidProposal        uint64
 	contractCreationFails      int
+	incorrectForecasts         map[bool]int
 }
 
 func (m *mockForecastMetrics) RecordFailedGames(count int) {
@@ -349,6 +405,10 @@ func (m *mockForecastMetrics) RecordIgnoredGames(count int) {
 	m.ignoredGames = count
 }
 
+func (m *mockForecastMetrics) RecordIncorrectForecasts(clockExpired bool, count int) {
+	m.incorrectForecasts[clockExpired] = count
+}
+
 func createDeepClaimList() []monTypes.EnrichedClaim {
 	return []monTypes.EnrichedClaim{
 		{
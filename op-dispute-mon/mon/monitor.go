@@ -43,6 +43,9 @@ type gameMonitor struct {
 	claims           Monitor
 	withdrawals      Monitor
 	l2Challenges     Monitor
+	bondsAtRisk      Monitor
+	withdrawalProofs Monitor
+	status           Monitor
 	extract          Extract
 	fetchBlockHash   BlockHashFetcher
 	fetchBlockNumber BlockNumberFetcher
@@ -61,6 +64,9 @@ func newGameMonitor(
 	claims Monitor,
 	withdrawals Monitor,
 	l2Challenges Monitor,
+	bondsAtRisk Monitor,
+	withdrawalProofs Monitor,
+	status Monitor,
 	extract Extract,
 	fetchBlockNumber BlockNumberFetcher,
 	fetchBlockHash BlockHashFetcher,
@@ -79,6 +85,9 @@ func newGameMonitor(
 		claims:           claims,
 		withdrawals:      withdrawals,
 		l2Challenges:     l2Challenges,
+		bondsAtRisk:      bondsAtRisk,
+		withdrawalProofs: withdrawalProofs,
+		status:           status,
 		extract:          extract,
 		fetchBlockNumber: fetchBlockNumber,
 		fetchBlockHash:   fetchBlockHash,
@@ -107,6 +116,9 @@ func (m *gameMonitor) monitorGames() error {
 	m.claims(enrichedGames)
 	m.withdrawals(enrichedGames)
 	m.l2Challenges(enrichedGames)
+	m.bondsAtRisk(enrichedGames)
+	m.withdrawalProofs(enrichedGames)
+	m.status(enrichedGames)
 	timeTaken := m.clock.Since(start)
 	m.metrics.RecordMonitorDuration(timeTaken)
 	m.logger.Info("Completed monitoring update", "blockNumber", blockNumber, "blockHash", blockHash, "duration", timeTaken, "games", len(enrichedGames), "ignored", ignored, "failed", failed)
@@ -25,7 +25,7 @@ func TestMonitor_MonitorGames(t *testing.T) {
 	t.Parallel()
 
 	t.Run("FailedFetchBlocknumber", func(t *testing.T) {
-		monitor, _, _, _, _, _, _, _ := setupMonitorTest(t)
+		monitor, _, _, _, _, _, _, _, _, _, _ := setupMonitorTest(t)
 		boom := errors.New("boom")
 		monitor.fetchBlockNumber = func(ctx context.Context) (uint64, error) {
 			return 0, boom
@@ -35,7 +35,7 @@ func TestMonitor_MonitorGames(t *testing.T) {
 	})
 
 	t.Run("FailedFetchBlockHash", func(t *testing.T) {
-		monitor, _, _, _, _, _, _, _ := setupMonitorTest(t)
+		monitor, _, _, _, _, _, _, _, _, _, _ := setupMonitorTest(t)
 		boom := errors.New("boom")
 		monitor.fetchBlockHash = func(ctx context.Context, number *big.Int) (common.Hash, error) {
 			return common.Hash{}, boom
@@ -45,7 +45,7 @@ func TestMonitor_MonitorGames(t *testing.T) {
 	})
 
 	t.Run("MonitorsWithNoGames", func(t *testing.T) {
-		monitor, factory, forecast, bonds, withdrawals, resolutions, claims, l2Challenges := setupMonitorTest(t)
+		monitor, factory, forecast, bonds, withdrawals, resolutions, claims, l2Challenges, bondsAtRisk, withdrawalProofs, status := setupMonitorTest(t)
 		factory.games = []*monTypes.EnrichedGameData{}
 		err := monitor.monitorGames()
 		require.NoError(t, err)
@@ -55,10 +55,13 @@ func TestMonitor_MonitorGames(t *testing.T) {
 		require.Equal(t, 1, claims.calls)
 		require.Equal(t, 1, withdrawals.calls)
 		require.Equal(t, 1, l2Challenges.calls)
+		require.Equal(t, 1, bondsAtRisk.calls)
+		require.Equal(t, 1, withdrawalProofs.calls)
+		require.Equal(t, 1, status.calls)
 	})
 
 	t.Run("MonitorsMultipleGames", func(t *testing.T) {
-		monitor, factory, forecast, bonds, withdrawals, resolutions, claims, l2Challenges := setupMonitorTest(t)
+		monitor, factory, forecast, bonds, withdrawals, resolutions, claims, l2Challenges, bondsAtRisk, withdrawalProofs, status := setupMonitorTest(t)
 		factory.games = []*monTypes.EnrichedGameData{{}, {}, {}}
 		err := monitor.monitorGames()
 		require.NoError(t, err)
@@ -68,6 +71,9 @@ func TestMonitor_MonitorGames(t *testing.T) {
 		require.Equal(t, 1, claims.calls)
 		require.Equal(t, 1, withdrawals.calls)
 		require.Equal(t, 1, l2Challenges.calls)
+		require.Equal(t, 1, bondsAtRisk.calls)
+		require.Equal(t, 1, withdrawalProofs.calls)
+		require.Equal(t, 1, status.calls)
 	})
 }
 
@@ -75,7 +81,7 @@ func TestMonitor_StartMonitoring(t *testing.T) {
 	t.Run("MonitorsGames", func(t *testing.T) {
 		addr1 := common.Address{0xaa}
 		addr2 := common.Address{0xbb}
-		monitor, factory, forecaster, _, _, _, _, _ := setupMonitorTest(t)
+		monitor, factory, forecaster, _, _, _, _, _, _, _, _ := setupMonitorTest(t)
 		factory.games = []*monTypes.EnrichedGameData{newEnrichedGameData(addr1, 9999), newEnrichedGameData(addr2, 9999)}
 		factory.maxSuccess = len(factory.games) // Only allow two successful fetches
 
@@ -88,7 +94,7 @@ func TestMonitor_StartMonitoring(t *testing.T) {
 	})
 
 	t.Run("FailsToFetchGames", func(t *testing.T) {
-		monitor, factory, forecaster, _, _, _, _, _ := setupMonitorTest(t)
+		monitor, factory, forecaster, _, _, _, _, _, _, _, _ := setupMonitorTest(t)
 		factory.fetchErr = errors.New("boom")
 
 		monitor.StartMonitoring()
@@ -110,7 +116,7 @@ func newEnrichedGameData(proxy common.Address, timestamp uint64) *monTypes.Enric
 	}
 }
 
-func setupMonitorTest(t *testing.T) (*gameMonitor, *mockExtractor, *mockForecast, *mockBonds, *mockMonitor, *mockResolutionMonitor, *mockMonitor, *mockMonitor) {
+func setupMonitorTest(t *testing.T) (*gameMonitor, *mockExtractor, *mockForecast, *mockBonds, *mockMonitor, *mockResolutionMonitor, *mockMonitor, *mockMonitor, *mockMonitor, *mockMonitor, *mockMonitor) {
 	logger := testlog.Logger(t, log.LvlDebug)
 	fetchBlockNum := func(ctx context.Context) (uint64, error) {
 		return 1, nil
@@ -128,6 +134,9 @@ func setupMonitorTest(t *testing.T) (*gameMonitor, *mockExtractor, *mockForecast
 	claims := &mockMonitor{}
 	withdrawals := &mockMonitor{}
 	l2Challenges := &mockMonitor{}
+	bondsAtRisk := &mockMonitor{}
+	withdrawalProofs := &mockMonitor{}
+	status := &mockMonitor{}
 	monitor := newGameMonitor(
 		context.Background(),
 		logger,
@@ -141,11 +150,14 @@ func setupMonitorTest(t *testing.T) (*gameMonitor, *mockExtractor, *mockForecast
 		claims.Check,
 		withdrawals.Check,
 		l2Challenges.Check,
+		bondsAtRisk.Check,
+		withdrawalProofs.Check,
+		status.Check,
 		extractor.Extract,
 		fetchBlockNum,
 		fetchBlockHash,
 	)
-	return monitor, extractor, forecast, bonds, withdrawals, resolutions, claims, l2Challenges
+	return monitor, extractor, forecast, bonds, withdrawals, resolutions, claims, l2Challenges, bondsAtRisk, withdrawalProofs, status
 }
 
 type mockResolutionMonitor struct {
@@ -13,6 +13,7 @@ const MaxResolveDelay = time.Minute
 
 type ResolutionMetrics interface {
 	RecordGameResolutionStatus(status metrics.ResolutionStatus, count int)
+	RecordOverdueResolutions(count int)
 }
 
 type ResolutionMonitor struct {
@@ -31,7 +32,11 @@ func NewResolutionMonitor(logger log.Logger, metrics ResolutionMetrics, clock RC
 
 func (r *ResolutionMonitor) CheckResolutions(games []*types.EnrichedGameData) {
 	statusMetrics := make(map[metrics.ResolutionStatus]int)
+	overdue := 0
 	for _, game := range games {
+		if r.checkOverdue(game) {
+			overdue++
+		}
 		complete := game.Status != gameTypes.GameStatusInProgress
 		duration := uint64(r.clock.Now().Unix()) - game.Timestamp
 		maxDurationReached := duration >= (2 * game.MaxClockDuration)
@@ -73,4 +78,46 @@ func (r *ResolutionMonitor) CheckResolutions(games []*types.EnrichedGameData) {
 	r.metrics.RecordGameResolutionStatus(metrics.ResolvableBeforeMaxDuration, statusMetrics[metrics.ResolvableBeforeMaxDuration])
 	r.metrics.RecordGameResolutionStatus(metrics.InProgressMaxDuration, statusMetrics[metrics.InProgressMaxDuration])
 	r.metrics.RecordGameResolutionStatus(metrics.InProgressBeforeMaxDuration, statusMetrics[metrics.InProgressBeforeMaxDuration])
+	r.metrics.RecordOverdueResolutions(overdue)
+}
+
+// checkOverdue returns true if the game is still in progress more than MaxResolveDelay after the clocks of all its
+// claims expired. Unlike the max duration, this reflects when the game actually became resolvable.
+func (r *ResolutionMonitor) checkOverdue(game *types.EnrichedGameData) bool {
+	if game.Status != gameTypes.GameStatusInProgress {
+		return false
+	}
+	expiry, ok := clocksExpireAt(game)
+	if !ok {
+		return false
+	}
+	delay := r.clock.Now().Sub(expiry)
+	if delay <= MaxResolveDelay {
+		return false
+	}
+	r.logger.Warn("Game resolution overdue", "game", game.Proxy, "clocksExpired", expiry, "delay", delay)
+	return true
+}
+
+// clocksExpireAt returns the time when the chess clocks of all claims in the game have expired, after which no
+// further moves can be made and every claim, and so the game, can be resolved.
+// Returns false if the game has no claims.
+func clocksExpireAt(game *types.EnrichedGameData) (time.Time, bool) {
+	if len(game.Claims) == 0 {
+		return time.Time{}, false
+	}
+	maxChessTime := time.Duration(game.MaxClockDuration) * time.Second
+	var latest time.Time
+	for _, claim := range game.Claims {
+		// The clock of the team countering the claim continues from the time it accumulated in earlier turns.
+		var accumulated time.Duration
+		if !claim.IsRoot() {
+			accumulated = game.Claims[claim.ParentContractIndex].Clock.Duration
+		}
+		expiry := claim.Clock.Timestamp.Add(maxChessTime - accumulated)
+		if expiry.After(latest) {
+			latest = expiry
+		}
+	}
+	return latest, true
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
@@ -26,6 +27,64 @@ func TestResolutionMonitor_CheckResolutions(t *testing.T) {
 	require.Equal(t, 1, m.calls[metrics.InProgressBeforeMaxDuration])
 }
 
+func TestResolutionMonitor_OverdueResolutions(t *testing.T) {
+	maxClockDuration := time.Hour
+	createGame := func(cl *clock.DeterministicClock, status gameTypes.GameStatus, sinceExpiry time.Duration) *types.EnrichedGameData {
+		// Claims are posted at 0, 10 and 15 minutes so the clocks of the root, child and grandchild expire
+		// at 60, 70 and 65 minutes since each claim's clock continues from the time its team used previously.
+		start := cl.Now().Add(-70*time.Minute - sinceExpiry)
+		root := faultTypes.Claim{
+			ClaimData: faultTypes.ClaimData{Position: faultTypes.RootPosition},
+			Clock:     faultTypes.Clock{Timestamp: start},
+		}
+		child := faultTypes.Claim{
+			ClaimData:     faultTypes.ClaimData{Position: faultTypes.RootPosition.Attack()},
+			Clock:         faultTypes.Clock{Duration: 10 * time.Minute, Timestamp: start.Add(10 * time.Minute)},
+			ContractIndex: 1,
+		}
+		grandchild := faultTypes.Claim{
+			ClaimData:           faultTypes.ClaimData{Position: faultTypes.RootPosition.Attack().Attack()},
+			Clock:               faultTypes.Clock{Duration: 5 * time.Minute, Timestamp: start.Add(15 * time.Minute)},
+			ContractIndex:       2,
+			ParentContractIndex: 1,
+		}
+		return &types.EnrichedGameData{
+			Status:           status,
+			MaxClockDuration: uint64(maxClockDuration.Seconds()),
+			Claims:           []types.EnrichedClaim{{Claim: root}, {Claim: child}, {Claim: grandchild}},
+		}
+	}
+
+	t.Run("NotOverdue", func(t *testing.T) {
+		r, cl, m, logs := newTestResolutionMonitorWithLogs(t)
+		r.CheckResolutions([]*types.EnrichedGameData{
+			createGame(cl, gameTypes.GameStatusInProgress, -time.Minute),
+			createGame(cl, gameTypes.GameStatusInProgress, MaxResolveDelay),
+			createGame(cl, gameTypes.GameStatusDefenderWon, time.Hour),
+		})
+		require.Zero(t, m.overdue)
+		require.Nil(t, logs.FindLog(testlog.NewMessageFilter("Game resolution overdue")))
+	})
+
+	t.Run("Overdue", func(t *testing.T) {
+		r, cl, m, logs := newTestResolutionMonitorWithLogs(t)
+		game := createGame(cl, gameTypes.GameStatusInProgress, MaxResolveDelay+time.Second)
+		r.CheckResolutions([]*types.EnrichedGameData{game})
+		require.Equal(t, 1, m.overdue)
+		l := logs.FindLog(testlog.NewLevelFilter(log.LevelWarn), testlog.NewMessageFilter("Game resolution overdue"))
+		require.NotNil(t, l)
+		require.Equal(t, MaxResolveDelay+time.Second, l.AttrValue("delay"))
+		require.Equal(t, cl.Now().Add(-MaxResolveDelay-time.Second), l.AttrValue("clocksExpired"))
+	})
+}
+
+func newTestResolutionMonitorWithLogs(t *testing.T) (*ResolutionMonitor, *clock.DeterministicClock, *stubResolutionMetrics, *testlog.CapturingHandler) {
+	logger, logs := testlog.CaptureLogger(t, log.LvlInfo)
+	cl := clock.NewDeterministicClock(time.Unix(int64(time.Hour.Seconds()), 0))
+	metrics := &stubResolutionMetrics{}
+	return NewResolutionMonitor(logger, metrics, cl), cl, metrics, logs
+}
+
 func newTestResolutionMonitor(t *testing.T) (*ResolutionMonitor, *clock.DeterministicClock, *stubResolutionMetrics) {
 	logger := testlog.Logger(t, log.LvlInfo)
 	cl := clock.NewDeterministicClock(time.Unix(int64(time.Hour.Seconds()), 0))
@@ -34,7 +93,12 @@ func newTestResolutionMonitor(t *testing.T) (*ResolutionMonitor, *clock.Determin
 }
 
 type stubResolutionMetrics struct {
-	calls map[metrics.ResolutionStatus]int
+	calls   map[metrics.ResolutionStatus]int
+	overdue int
+}
+
+func (s *stubResolutionMetrics) RecordOverdueResolutions(count int) {
+	s.overdue = count
 }
 
 func (s *stubResolutionMetrics) RecordGameResolutionStatus(status metrics.ResolutionStatus, count int) {
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net"
+	"strconv"
 	"sync/atomic"
 
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/bonds"
@@ -12,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/config"
 	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
@@ -29,15 +32,31 @@ import (
 )
 
 type Service struct {
+	logger   log.Logger
+	registry *prometheus.Registry
+	networks []*networkMonitor
+
+	cl clock.Clock
+
+	l1Client *ethclient.Client
+
+	pprofService *oppprof.Service
+	metricsSrv   *httputil.HTTPServer
+	statusSrv    *httputil.HTTPServer
+
+	stopped atomic.Bool
+}
+
+// networkMonitor monitors the games created by a single dispute game factory.
+type networkMonitor struct {
 	logger       log.Logger
+	network      config.NetworkConfig
 	metrics      metrics.Metricer
 	monitor      *gameMonitor
 	honestActors types.HonestActors
 
 	factoryContract *contracts.DisputeGameFactoryContract
 
-	cl clock.Clock
-
 	extractor    *extract.Extractor
 	forecast     *Forecast
 	bonds        *bonds.Bonds
@@ -45,23 +64,16 @@ type Service struct {
 	resolutions  *ResolutionMonitor
 	claims       *ClaimMonitor
 	withdrawals  *WithdrawalMonitor
+	proofs       *WithdrawalProofMonitor
+	status       *StatusMonitor
 	rollupClient *sources.RollupClient
-
-	l1Client *ethclient.Client
-
-	pprofService *oppprof.Service
-	metricsSrv   *httputil.HTTPServer
-
-	stopped atomic.Bool
 }
 
 // NewService creates a new Service.
 func NewService(ctx context.Context, logger log.Logger, cfg *config.Config) (*Service, error) {
 	s := &Service{
-		cl:           clock.SystemClock,
-		logger:       logger,
-		metrics:      metrics.NewMetrics(),
-		honestActors: types.NewHonestActors(cfg.HonestActors),
+		cl:     clock.SystemClock,
+		logger: logger,
 	}
 
 	if err := s.initFromConfig(ctx, cfg); err != nil {
@@ -78,56 +90,109 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 	if err := s.initPProf(&cfg.PprofConfig); err != nil {
 		return fmt.Errorf("failed to init profiling: %w", err)
 	}
+	s.initNetworks(cfg)
 	if err := s.initMetricsServer(&cfg.MetricsConfig); err != nil {
 		return fmt.Errorf("failed to init metrics server: %w", err)
 	}
-	if err := s.initFactoryContract(cfg); err != nil {
-		return fmt.Errorf("failed to create factory contract bindings: %w", err)
+	for _, n := range s.networks {
+		if err := n.initFromConfig(ctx, cfg, s.cl, s.l1Client); err != nil {
+			return err
+		}
+	}
+	if err := s.initStatusServer(&cfg.StatusConfig); err != nil {
+		return fmt.Errorf("failed to init status server: %w", err)
+	}
+	return nil
+}
+
+// initNetworks creates the monitor for each network. When only a single network is monitored, metrics are not
+// labelled with the network name to remain compatible with existing dashboards and alerts.
+func (s *Service) initNetworks(cfg *config.Config) {
+	networks := cfg.Networks()
+	if len(networks) == 1 {
+		m := metrics.NewMetrics()
+		s.registry = m.Registry()
+		s.networks = []*networkMonitor{newNetworkMonitor(s.logger, m, networks[0], cfg.HonestActors)}
+		return
+	}
+	s.registry = opmetrics.NewRegistry()
+	for _, network := range networks {
+		m := metrics.NewNetworkMetrics(s.registry, network.Name)
+		s.networks = append(s.networks, newNetworkMonitor(s.logger.New("network", network.Name), m, network, cfg.HonestActors))
 	}
-	if err := s.initOutputRollupClient(ctx, cfg); err != nil {
+}
+
+func newNetworkMonitor(logger log.Logger, m metrics.Metricer, network config.NetworkConfig, honestActors []common.Address) *networkMonitor {
+	return &networkMonitor{
+		logger:       logger,
+		network:      network,
+		metrics:      m,
+		honestActors: types.NewHonestActors(honestActors),
+	}
+}
+
+func (n *networkMonitor) initFromConfig(ctx context.Context, cfg *config.Config, cl clock.Clock, l1Client *ethclient.Client) error {
+	n.initFactoryContract(l1Client)
+	if err := n.initOutputRollupClient(ctx); err != nil {
 		return fmt.Errorf("failed to init rollup client: %w", err)
 	}
 
-	s.initClaimMonitor(cfg)
-	s.initResolutionMonitor()
-	s.initWithdrawalMonitor()
+	n.initClaimMonitor(cl)
+	n.initResolutionMonitor(cl)
+	n.initWithdrawalMonitor(cl)
+	n.initWithdrawalProofMonitor(ctx, l1Client)
+	n.initStatusMonitor(cl)
 
-	s.initGameCallerCreator() // Must be called before initForecast
+	n.initGameCallerCreator(l1Client) // Must be called before initForecast
 
-	s.initExtractor(cfg)
+	n.initExtractor(cfg, l1Client)
 
-	s.initForecast(cfg)
-	s.initBonds()
+	n.initForecast(cl)
+	n.initBonds(cl)
 
-	s.initMonitor(ctx, cfg) // Monitor must be initialized last
+	n.initMonitor(ctx, cfg, cl, l1Client) // Monitor must be initialized last
 
-	s.metrics.RecordInfo(version.SimpleWithMeta)
-	s.metrics.RecordUp()
+	n.metrics.RecordInfo(version.SimpleWithMeta)
+	n.metrics.RecordUp()
 
 	return nil
 }
 
-func (s *Service) initClaimMonitor(cfg *config.Config) {
-	s.claims = NewClaimMonitor(s.logger, s.cl, s.honestActors, s.metrics)
+func (n *networkMonitor) initClaimMonitor(cl clock.Clock) {
+	n.claims = NewClaimMonitor(n.logger, cl, n.honestActors, n.metrics)
+}
+
+func (n *networkMonitor) initResolutionMonitor(cl clock.Clock) {
+	n.resolutions = NewResolutionMonitor(n.logger, n.metrics, cl)
 }
 
-func (s *Service) initResolutionMonitor() {
-	s.resolutions = NewResolutionMonitor(s.logger, s.metrics, s.cl)
+func (n *networkMonitor) initWithdrawalMonitor(cl clock.Clock) {
+	n.withdrawals = NewWithdrawalMonitor(n.logger, cl, n.metrics, n.honestActors)
+}
+
+func (n *networkMonitor) initWithdrawalProofMonitor(ctx context.Context, l1Client *ethclient.Client) {
+	if n.network.OptimismPortalAddress == (common.Address{}) {
+		n.logger.Info("Not checking withdrawal proofs as no OptimismPortal address is configured")
+		return
+	}
+	portal := contracts.NewOptimismPortalContract(n.metrics, n.network.OptimismPortalAddress,
+		batching.NewMultiCaller(l1Client.Client(), batching.DefaultBatchSize))
+	n.proofs = NewWithdrawalProofMonitor(ctx, n.logger, n.metrics, portal, l1Client)
 }
 
-func (s *Service) initWithdrawalMonitor() {
-	s.withdrawals = NewWithdrawalMonitor(s.logger, s.cl, s.metrics, s.honestActors)
+func (n *networkMonitor) initStatusMonitor(cl clock.Clock) {
+	n.status = NewStatusMonitor(cl, n.network.Name)
 }
 
-func (s *Service) initGameCallerCreator() {
-	s.game = extract.NewGameCallerCreator(s.metrics, batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize))
+func (n *networkMonitor) initGameCallerCreator(l1Client *ethclient.Client) {
+	n.game = extract.NewGameCallerCreator(n.metrics, batching.NewMultiCaller(l1Client.Client(), batching.DefaultBatchSize))
 }
 
-func (s *Service) initExtractor(cfg *config.Config) {
-	s.extractor = extract.NewExtractor(
-		s.logger,
-		s.game.CreateContract,
-		s.factoryContract.GetGamesAtOrAfter,
+func (n *networkMonitor) initExtractor(cfg *config.Config, l1Client *ethclient.Client) {
+	n.extractor = extract.NewExtractor(
+		n.logger,
+		n.game.CreateContract,
+		n.factoryContract.GetGamesAtOrAfter,
 		cfg.IgnoredGames,
 		cfg.MaxConcurrency,
 		extract.NewClaimEnricher(),
@@ -135,25 +200,25 @@ func (s *Service) initExtractor(cfg *config.Config) {
 		extract.NewWithdrawalsEnricher(),
 		extract.NewBondEnricher(),
 		extract.NewBalanceEnricher(),
-		extract.NewL1HeadBlockNumEnricher(s.l1Client),
-		extract.NewAgreementEnricher(s.logger, s.metrics, s.rollupClient),
+		extract.NewL1HeadBlockNumEnricher(l1Client),
+		extract.NewAgreementEnricher(n.logger, n.metrics, n.rollupClient),
 	)
 }
 
-func (s *Service) initForecast(cfg *config.Config) {
-	s.forecast = NewForecast(s.logger, s.metrics)
+func (n *networkMonitor) initForecast(cl clock.Clock) {
+	n.forecast = NewForecast(n.logger, n.metrics, cl)
 }
 
-func (s *Service) initBonds() {
-	s.bonds = bonds.NewBonds(s.logger, s.metrics, s.cl)
+func (n *networkMonitor) initBonds(cl clock.Clock) {
+	n.bonds = bonds.NewBonds(n.logger, n.metrics, cl)
 }
 
-func (s *Service) initOutputRollupClient(ctx context.Context, cfg *config.Config) error {
-	outputRollupClient, err := dial.DialRollupClientWithTimeout(ctx, dial.DefaultDialTimeout, s.logger, cfg.RollupRpc)
+func (n *networkMonitor) initOutputRollupClient(ctx context.Context) error {
+	outputRollupClient, err := dial.DialRollupClientWithTimeout(ctx, dial.DefaultDialTimeout, n.logger, n.network.RollupRpc)
 	if err != nil {
 		return fmt.Errorf("failed to dial rollup client: %w", err)
 	}
-	s.rollupClient = outputRollupClient
+	n.rollupClient = outputRollupClient
 	return nil
 }
 
@@ -188,11 +253,7 @@ func (s *Service) initMetricsServer(cfg *opmetrics.CLIConfig) error {
 		return nil
 	}
 	s.logger.Debug("starting metrics server", "addr", cfg.ListenAddr, "port", cfg.ListenPort)
-	m, ok := s.metrics.(opmetrics.RegistryMetricer)
-	if !ok {
-		return fmt.Errorf("metrics were enabled, but metricer %T does not expose registry for metrics-server", s.metrics)
-	}
-	metricsSrv, err := opmetrics.StartServer(m.Registry(), cfg.ListenAddr, cfg.ListenPort)
+	metricsSrv, err := opmetrics.StartServer(s.registry, cfg.ListenAddr, cfg.ListenPort)
 	if err != nil {
 		return fmt.Errorf("failed to start metrics server: %w", err)
 	}
@@ -201,37 +262,61 @@ func (s *Service) initMetricsServer(cfg *opmetrics.CLIConfig) error {
 	return nil
 }
 
-func (s *Service) initFactoryContract(cfg *config.Config) error {
-	factoryContract := contracts.NewDisputeGameFactoryContract(s.metrics, cfg.GameFactoryAddress,
-		batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize))
-	s.factoryContract = factoryContract
+func (s *Service) initStatusServer(cfg *config.StatusConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	monitors := make([]*StatusMonitor, 0, len(s.networks))
+	for _, n := range s.networks {
+		monitors = append(monitors, n.status)
+	}
+	s.logger.Debug("starting status server", "addr", cfg.ListenAddr, "port", cfg.ListenPort)
+	statusSrv, err := httputil.StartHTTPServer(net.JoinHostPort(cfg.ListenAddr, strconv.Itoa(cfg.ListenPort)), NewStatusHandler(s.logger, monitors...))
+	if err != nil {
+		return fmt.Errorf("failed to start status server: %w", err)
+	}
+	s.logger.Info("started status server", "addr", statusSrv.Addr())
+	s.statusSrv = statusSrv
 	return nil
 }
 
-func (s *Service) initMonitor(ctx context.Context, cfg *config.Config) {
+func (n *networkMonitor) initFactoryContract(l1Client *ethclient.Client) {
+	n.factoryContract = contracts.NewDisputeGameFactoryContract(n.metrics, n.network.GameFactoryAddress,
+		batching.NewMultiCaller(l1Client.Client(), batching.DefaultBatchSize))
+}
+
+func (n *networkMonitor) initMonitor(ctx context.Context, cfg *config.Config, cl clock.Clock, l1Client *ethclient.Client) {
 	blockHashFetcher := func(ctx context.Context, blockNumber *big.Int) (common.Hash, error) {
-		block, err := s.l1Client.BlockByNumber(ctx, blockNumber)
+		block, err := l1Client.BlockByNumber(ctx, blockNumber)
 		if err != nil {
 			return common.Hash{}, fmt.Errorf("failed to fetch block by number: %w", err)
 		}
 		return block.Hash(), nil
 	}
-	l2ChallengesMonitor := NewL2ChallengesMonitor(s.logger, s.metrics)
-	s.monitor = newGameMonitor(
+	l2ChallengesMonitor := NewL2ChallengesMonitor(n.logger, n.metrics)
+	bondsAtRiskMonitor := NewBondsAtRiskMonitor(n.logger, n.metrics, n.honestActors, cfg.GameBondsAtRiskThreshold, cfg.TotalBondsAtRiskThreshold)
+	withdrawalProofs := func(games []*types.EnrichedGameData) {}
+	if n.proofs != nil {
+		withdrawalProofs = n.proofs.CheckWithdrawalProofs
+	}
+	n.monitor = newGameMonitor(
 		ctx,
-		s.logger,
-		s.cl,
-		s.metrics,
+		n.logger,
+		cl,
+		n.metrics,
 		cfg.MonitorInterval,
 		cfg.GameWindow,
-		s.forecast.Forecast,
-		s.bonds.CheckBonds,
-		s.resolutions.CheckResolutions,
-		s.claims.CheckClaims,
-		s.withdrawals.CheckWithdrawals,
+		n.forecast.Forecast,
+		n.bonds.CheckBonds,
+		n.resolutions.CheckResolutions,
+		n.claims.CheckClaims,
+		n.withdrawals.CheckWithdrawals,
 		l2ChallengesMonitor.CheckL2Challenges,
-		s.extractor.Extract,
-		s.l1Client.BlockNumber,
+		bondsAtRiskMonitor.CheckBondsAtRisk,
+		withdrawalProofs,
+		n.status.CheckStatus,
+		n.extractor.Extract,
+		l1Client.BlockNumber,
 		blockHashFetcher,
 	)
 }
@@ -239,7 +324,9 @@ func (s *Service) initMonitor(ctx context.Context, cfg *config.Config) {
 func (s *Service) Start(ctx context.Context) error {
 	s.logger.Info("Starting scheduler")
 	s.logger.Info("Starting monitoring")
-	s.monitor.StartMonitoring()
+	for _, n := range s.networks {
+		n.monitor.StartMonitoring()
+	}
 	s.logger.Info("Dispute monitor game service start completed")
 	return nil
 }
@@ -262,6 +349,11 @@ func (s *Service) Stop(ctx context.Context) error {
 			result = errors.Join(result, fmt.Errorf("failed to close metrics server: %w", err))
 		}
 	}
+	if s.statusSrv != nil {
+		if err := s.statusSrv.Stop(ctx); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close status server: %w", err))
+		}
+	}
 	s.stopped.Store(true)
 	s.logger.Info("stopped dispute mon service", "err", result)
 	return result
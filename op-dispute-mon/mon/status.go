@@ -0,0 +1,169 @@
+package mon
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/transform"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	WarningIncorrectResult        = "resolved incorrectly"
+	WarningIncorrectForecast      = "forecast to resolve incorrectly"
+	WarningClocksExpiredIncorrect = "clocks expired with incorrect forecast"
+	WarningResolutionOverdue      = "resolution overdue"
+	WarningValidL2BlockChallenged = "valid L2 block number challenged"
+)
+
+// NetworkStatus is the assessment of all games monitored for a network, as served by the status API.
+type NetworkStatus struct {
+	Network string `json:"network"`
+	// Updated is the time the games were last assessed. Zero if the games have not yet been assessed.
+	Updated time.Time    `json:"updated"`
+	Games   []GameReport `json:"games"`
+}
+
+// GameReport is the assessment of a single game.
+type GameReport struct {
+	Game              common.Address `json:"game"`
+	GameType          uint32         `json:"gameType"`
+	Timestamp         uint64         `json:"timestamp"`
+	L1Head            common.Hash    `json:"l1Head"`
+	L2BlockNumber     uint64         `json:"l2BlockNumber"`
+	RootClaim         common.Hash    `json:"rootClaim"`
+	ExpectedRootClaim common.Hash    `json:"expectedRootClaim"`
+	AgreeWithClaim    bool           `json:"agreeWithClaim"`
+	Status            string         `json:"status"`
+	// Forecast is the status the game would resolve with based on its current claims. Only set for in progress games.
+	Forecast string `json:"forecast,omitempty"`
+	// ClocksExpireAt is the time after which no further moves can be made. Nil if the game has no claims.
+	ClocksExpireAt *time.Time                  `json:"clocksExpireAt,omitempty"`
+	Claims         int                         `json:"claims"`
+	Credits        map[common.Address]*big.Int `json:"credits,omitempty"`
+	Warnings       []string                    `json:"warnings,omitempty"`
+}
+
+// StatusMonitor records the assessment of the games from each monitoring cycle so it can be served by the status API.
+type StatusMonitor struct {
+	clock   RClock
+	network string
+
+	lock   sync.Mutex
+	status NetworkStatus
+}
+
+func NewStatusMonitor(clock RClock, network string) *StatusMonitor {
+	return &StatusMonitor{
+		clock:   clock,
+		network: network,
+		status:  NetworkStatus{Network: network, Games: []GameReport{}},
+	}
+}
+
+func (s *StatusMonitor) CheckStatus(games []*types.EnrichedGameData) {
+	now := s.clock.Now()
+	status := NetworkStatus{
+		Network: s.network,
+		Updated: now,
+		Games:   make([]GameReport, 0, len(games)),
+	}
+	for _, game := range games {
+		status.Games = append(status.Games, assessGame(game, now))
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status = status
+}
+
+// Status returns the assessment from the latest monitoring cycle.
+func (s *StatusMonitor) Status() NetworkStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.status
+}
+
+func assessGame(game *types.EnrichedGameData, now time.Time) GameReport {
+	result := GameReport{
+		Game:              game.Proxy,
+		GameType:          game.GameType,
+		Timestamp:         game.Timestamp,
+		L1Head:            game.L1Head,
+		L2BlockNumber:     game.L2BlockNumber,
+		RootClaim:         game.RootClaim,
+		ExpectedRootClaim: game.ExpectedRootClaim,
+		AgreeWithClaim:    game.AgreeWithClaim,
+		Status:            game.Status.String(),
+		Claims:            len(game.Claims),
+		Credits:           game.Credits,
+	}
+	expiry, hasExpiry := clocksExpireAt(game)
+	if hasExpiry {
+		result.ClocksExpireAt = &expiry
+	}
+	expectedResult := gameTypes.GameStatusDefenderWon
+	if !game.AgreeWithClaim {
+		expectedResult = gameTypes.GameStatusChallengerWon
+	}
+	if game.BlockNumberChallenged && game.AgreeWithClaim {
+		result.Warnings = append(result.Warnings, WarningValidL2BlockChallenged)
+	}
+	if game.Status != gameTypes.GameStatusInProgress {
+		if game.Status != expectedResult {
+			result.Warnings = append(result.Warnings, WarningIncorrectResult)
+		}
+		return result
+	}
+
+	forecast := gameTypes.GameStatusChallengerWon
+	if !game.BlockNumberChallenged {
+		forecast = Resolve(transform.CreateBidirectionalTree(game.Claims))
+	}
+	result.Forecast = forecast.String()
+	clocksExpired := hasExpiry && !now.Before(expiry)
+	if forecast != expectedResult {
+		if clocksExpired {
+			result.Warnings = append(result.Warnings, WarningClocksExpiredIncorrect)
+		} else {
+			result.Warnings = append(result.Warnings, WarningIncorrectForecast)
+		}
+	}
+	if hasExpiry && now.Sub(expiry) > MaxResolveDelay {
+		result.Warnings = append(result.Warnings, WarningResolutionOverdue)
+	}
+	return result
+}
+
+// NewStatusHandler creates a handler serving the latest assessment of each network as JSON from GET /status.
+// A single network can be selected with the network query parameter.
+func NewStatusHandler(logger log.Logger, monitors ...*StatusMonitor) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		network := r.URL.Query().Get("network")
+		statuses := make([]NetworkStatus, 0, len(monitors))
+		for _, monitor := range monitors {
+			if network == "" || monitor.network == network {
+				statuses = append(statuses, monitor.Status())
+			}
+		}
+		if network != "" && len(statuses) == 0 {
+			http.Error(w, "unknown network", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(statuses); err != nil {
+			logger.Warn("Failed to write status response", "err", err)
+		}
+	})
+	return mux
+}
@@ -0,0 +1,173 @@
+package mon
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusMonitor_CheckStatus(t *testing.T) {
+	now := time.Unix(100_000, 0)
+	maxClockDuration := time.Hour
+	createGame := func(addr common.Address, status gameTypes.GameStatus, agree bool, rootPosted time.Time) *types.EnrichedGameData {
+		return &types.EnrichedGameData{
+			GameMetadata:     gameTypes.GameMetadata{Proxy: addr, GameType: 1, Timestamp: uint64(rootPosted.Unix())},
+			L2BlockNumber:    42,
+			RootClaim:        common.Hash{0x01},
+			Status:           status,
+			AgreeWithClaim:   agree,
+			MaxClockDuration: uint64(maxClockDuration.Seconds()),
+			Claims: []types.EnrichedClaim{{Claim: faultTypes.Claim{
+				ClaimData: faultTypes.ClaimData{Position: faultTypes.RootPosition},
+				Clock:     faultTypes.Clock{Timestamp: rootPosted},
+			}}},
+		}
+	}
+
+	t.Run("NotYetAssessed", func(t *testing.T) {
+		monitor := NewStatusMonitor(clock.NewDeterministicClock(now), "test")
+		status := monitor.Status()
+		require.Equal(t, "test", status.Network)
+		require.True(t, status.Updated.IsZero())
+		require.Empty(t, status.Games)
+	})
+
+	t.Run("ResolvedCorrectly", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusDefenderWon, true, now.Add(-2*maxClockDuration))
+		game.Credits = map[common.Address]*big.Int{{0x01}: big.NewInt(5)}
+		report := checkSingleGame(t, now, game)
+		expiry := now.Add(-maxClockDuration)
+		require.Equal(t, GameReport{
+			Game:           game.Proxy,
+			GameType:       1,
+			Timestamp:      game.Timestamp,
+			L2BlockNumber:  42,
+			RootClaim:      common.Hash{0x01},
+			AgreeWithClaim: true,
+			Status:         gameTypes.GameStatusDefenderWon.String(),
+			ClocksExpireAt: &expiry,
+			Claims:         1,
+			Credits:        game.Credits,
+		}, report)
+	})
+
+	t.Run("ResolvedIncorrectly", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusChallengerWon, true, now.Add(-2*maxClockDuration))
+		report := checkSingleGame(t, now, game)
+		require.Empty(t, report.Forecast)
+		require.Equal(t, []string{WarningIncorrectResult}, report.Warnings)
+	})
+
+	t.Run("ForecastCorrect", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusInProgress, true, now.Add(-time.Minute))
+		report := checkSingleGame(t, now, game)
+		require.Equal(t, gameTypes.GameStatusDefenderWon.String(), report.Forecast)
+		require.Empty(t, report.Warnings)
+	})
+
+	t.Run("ForecastIncorrect", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusInProgress, false, now.Add(-time.Minute))
+		report := checkSingleGame(t, now, game)
+		require.Equal(t, gameTypes.GameStatusDefenderWon.String(), report.Forecast)
+		require.Equal(t, []string{WarningIncorrectForecast}, report.Warnings)
+	})
+
+	t.Run("ClocksExpiredWithIncorrectForecast", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusInProgress, false, now.Add(-maxClockDuration))
+		report := checkSingleGame(t, now, game)
+		require.Equal(t, []string{WarningClocksExpiredIncorrect}, report.Warnings)
+	})
+
+	t.Run("ResolutionOverdue", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusInProgress, true, now.Add(-maxClockDuration-MaxResolveDelay-time.Second))
+		report := checkSingleGame(t, now, game)
+		require.Equal(t, []string{WarningResolutionOverdue}, report.Warnings)
+	})
+
+	t.Run("ValidL2BlockChallenged", func(t *testing.T) {
+		game := createGame(common.Address{0xaa}, gameTypes.GameStatusInProgress, true, now.Add(-time.Minute))
+		game.BlockNumberChallenged = true
+		report := checkSingleGame(t, now, game)
+		require.Equal(t, gameTypes.GameStatusChallengerWon.String(), report.Forecast)
+		require.Equal(t, []string{WarningValidL2BlockChallenged, WarningIncorrectForecast}, report.Warnings)
+	})
+}
+
+func TestStatusHandler(t *testing.T) {
+	now := time.Unix(100_000, 0)
+	cl := clock.NewDeterministicClock(now)
+	network1 := NewStatusMonitor(cl, "network1")
+	network2 := NewStatusMonitor(cl, "network2")
+	game := &types.EnrichedGameData{
+		GameMetadata: gameTypes.GameMetadata{Proxy: common.Address{0xaa}},
+		Status:       gameTypes.GameStatusDefenderWon,
+	}
+	network1.CheckStatus([]*types.EnrichedGameData{game})
+	server := httptest.NewServer(NewStatusHandler(testlog.Logger(t, log.LevelInfo), network1, network2))
+	defer server.Close()
+
+	get := func(t *testing.T, path string) (int, []NetworkStatus) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		var statuses []NetworkStatus
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&statuses))
+		return resp.StatusCode, statuses
+	}
+
+	t.Run("AllNetworks", func(t *testing.T) {
+		code, statuses := get(t, "/status")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, statuses, 2)
+		require.Equal(t, "network1", statuses[0].Network)
+		require.True(t, now.Equal(statuses[0].Updated))
+		require.Len(t, statuses[0].Games, 1)
+		require.Equal(t, game.Proxy, statuses[0].Games[0].Game)
+		require.Equal(t, "network2", statuses[1].Network)
+		require.Empty(t, statuses[1].Games)
+	})
+
+	t.Run("SingleNetwork", func(t *testing.T) {
+		code, statuses := get(t, "/status?network=network2")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, statuses, 1)
+		require.Equal(t, "network2", statuses[0].Network)
+	})
+
+	t.Run("UnknownNetwork", func(t *testing.T) {
+		code, _ := get(t, "/status?network=unknown")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/status", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func checkSingleGame(t *testing.T, now time.Time, game *types.EnrichedGameData) GameReport {
+	monitor := NewStatusMonitor(clock.NewDeterministicClock(now), "test")
+	monitor.CheckStatus([]*types.EnrichedGameData{game})
+	status := monitor.Status()
+	require.Equal(t, now, status.Updated)
+	require.Len(t, status.Games, 1)
+	return status.Games[0]
+}
@@ -0,0 +1,175 @@
+package mon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxLogBlockRange is the maximum number of blocks to request withdrawal proof logs for in a single request.
+const maxLogBlockRange = 5000
+
+type WithdrawalProofMetrics interface {
+	RecordWithdrawalProofs(status metrics.WithdrawalProofStatus, count int)
+}
+
+type PortalContract interface {
+	WithdrawalProofsQuery(fromBlock uint64, toBlock uint64) ethereum.FilterQuery
+	DecodeWithdrawalProofs(logs []ethTypes.Log) []contracts.WithdrawalProof
+	GetProvenWithdrawals(ctx context.Context, block rpcblock.Block, proofs ...contracts.WithdrawalProof) ([]*contracts.ProvenWithdrawal, error)
+	GetBlacklisted(ctx context.Context, block rpcblock.Block, games ...common.Address) ([]bool, error)
+}
+
+type LogFetcher interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]ethTypes.Log, error)
+}
+
+// WithdrawalProofMonitor correlates withdrawals proven in the OptimismPortal with the dispute games they were proven
+// against, reporting proofs made against games that are in progress, blacklisted or resolved as invalid.
+type WithdrawalProofMonitor struct {
+	ctx     context.Context
+	logger  log.Logger
+	metrics WithdrawalProofMetrics
+	portal  PortalContract
+	logs    LogFetcher
+
+	// proofs is the set of withdrawal proofs being tracked.
+	proofs map[contracts.WithdrawalProof]bool
+	// nextBlock is the next L1 block to search for withdrawal proofs. Zero if the search has not yet started.
+	nextBlock uint64
+}
+
+func NewWithdrawalProofMonitor(ctx context.Context, logger log.Logger, metrics WithdrawalProofMetrics, portal PortalContract, logs LogFetcher) *WithdrawalProofMonitor {
+	return &WithdrawalProofMonitor{
+		ctx:     ctx,
+		logger:  logger,
+		metrics: metrics,
+		portal:  portal,
+		logs:    logs,
+		proofs:  make(map[contracts.WithdrawalProof]bool),
+	}
+}
+
+func (w *WithdrawalProofMonitor) CheckWithdrawalProofs(games []*types.EnrichedGameData) {
+	if err := w.loadProofs(games); err != nil {
+		// Continue to check the proofs that have already been found
+		w.logger.Error("Failed to load withdrawal proofs", "err", err)
+	}
+	counts := make(map[metrics.WithdrawalProofStatus]int)
+	if err := w.checkProofs(games, counts); err != nil {
+		w.logger.Error("Failed to check withdrawal proofs", "err", err)
+		return
+	}
+	w.metrics.RecordWithdrawalProofs(metrics.ProofAgainstValidGame, counts[metrics.ProofAgainstValidGame])
+	w.metrics.RecordWithdrawalProofs(metrics.ProofAgainstInProgressGame, counts[metrics.ProofAgainstInProgressGame])
+	w.metrics.RecordWithdrawalProofs(metrics.ProofAgainstBlacklistedGame, counts[metrics.ProofAgainstBlacklistedGame])
+	w.metrics.RecordWithdrawalProofs(metrics.ProofAgainstInvalidGame, counts[metrics.ProofAgainstInvalidGame])
+}
+
+// loadProofs searches for withdrawal proofs made since the last search.
+// The first search starts from the oldest L1 head of the monitored games as proofs can't be made before a game exists.
+func (w *WithdrawalProofMonitor) loadProofs(games []*types.EnrichedGameData) error {
+	head, err := w.logs.BlockNumber(w.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch head block number: %w", err)
+	}
+	if w.nextBlock == 0 {
+		w.nextBlock = head
+		for _, game := range games {
+			if game.L1HeadNum < w.nextBlock {
+				w.nextBlock = game.L1HeadNum
+			}
+		}
+	}
+	for w.nextBlock <= head {
+		toBlock := min(w.nextBlock+maxLogBlockRange-1, head)
+		logs, err := w.logs.FilterLogs(w.ctx, w.portal.WithdrawalProofsQuery(w.nextBlock, toBlock))
+		if err != nil {
+			return fmt.Errorf("failed to fetch withdrawal proofs from block %v to %v: %w", w.nextBlock, toBlock, err)
+		}
+		for _, proof := range w.portal.DecodeWithdrawalProofs(logs) {
+			w.proofs[proof] = true
+		}
+		w.nextBlock = toBlock + 1
+	}
+	return nil
+}
+
+func (w *WithdrawalProofMonitor) checkProofs(games []*types.EnrichedGameData, counts map[metrics.WithdrawalProofStatus]int) error {
+	if len(w.proofs) == 0 {
+		return nil
+	}
+	proofs := make([]contracts.WithdrawalProof, 0, len(w.proofs))
+	for proof := range w.proofs {
+		proofs = append(proofs, proof)
+	}
+	proven, err := w.portal.GetProvenWithdrawals(w.ctx, rpcblock.Latest, proofs...)
+	if err != nil {
+		return err
+	}
+	var provenGames []common.Address
+	seen := make(map[common.Address]bool)
+	for _, withdrawal := range proven {
+		if !seen[withdrawal.DisputeGame] {
+			seen[withdrawal.DisputeGame] = true
+			provenGames = append(provenGames, withdrawal.DisputeGame)
+		}
+	}
+	blacklistResults, err := w.portal.GetBlacklisted(w.ctx, rpcblock.Latest, provenGames...)
+	if err != nil {
+		return err
+	}
+	blacklisted := make(map[common.Address]bool)
+	for i, game := range provenGames {
+		blacklisted[game] = blacklistResults[i]
+	}
+	gamesByAddr := make(map[common.Address]*types.EnrichedGameData)
+	for _, game := range games {
+		gamesByAddr[game.Proxy] = game
+	}
+
+	for _, withdrawal := range proven {
+		game := gamesByAddr[withdrawal.DisputeGame]
+		if blacklisted[withdrawal.DisputeGame] {
+			counts[metrics.ProofAgainstBlacklistedGame]++
+			w.logger.Error("Withdrawal proven against blacklisted game", "withdrawal", withdrawal.WithdrawalHash,
+				"submitter", withdrawal.ProofSubmitter, "game", withdrawal.DisputeGame)
+			continue
+		}
+		if game == nil {
+			// The game is no longer monitored so stop tracking the proof.
+			w.logger.Debug("Withdrawal proven against unmonitored game", "withdrawal", withdrawal.WithdrawalHash,
+				"submitter", withdrawal.ProofSubmitter, "game", withdrawal.DisputeGame)
+			delete(w.proofs, withdrawal.WithdrawalProof)
+			continue
+		}
+		switch {
+		case game.Status == gameTypes.GameStatusChallengerWon || (game.Status == gameTypes.GameStatusDefenderWon && !game.AgreeWithClaim):
+			counts[metrics.ProofAgainstInvalidGame]++
+			w.logger.Error("Withdrawal proven against invalid game", "withdrawal", withdrawal.WithdrawalHash,
+				"submitter", withdrawal.ProofSubmitter, "game", game.Proxy, "status", game.Status, "agreement", game.AgreeWithClaim)
+		case game.Status == gameTypes.GameStatusInProgress:
+			counts[metrics.ProofAgainstInProgressGame]++
+			if game.AgreeWithClaim {
+				w.logger.Warn("Withdrawal proven against in progress game", "withdrawal", withdrawal.WithdrawalHash,
+					"submitter", withdrawal.ProofSubmitter, "game", game.Proxy)
+			} else {
+				w.logger.Error("Withdrawal proven against in progress game with invalid root claim", "withdrawal", withdrawal.WithdrawalHash,
+					"submitter", withdrawal.ProofSubmitter, "game", game.Proxy)
+			}
+		default:
+			counts[metrics.ProofAgainstValidGame]++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package mon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/metrics"
+	monTypes "github.com/ethereum-optimism/optimism/op-dispute-mon/mon/types"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	validGame       = common.Address{0xaa}
+	inProgressGame  = common.Address{0xbb}
+	invalidGame     = common.Address{0xcc}
+	blacklistedGame = common.Address{0xdd}
+	unmonitoredGame = common.Address{0xee}
+)
+
+func TestCheckWithdrawalProofs(t *testing.T) {
+	games := []*monTypes.EnrichedGameData{
+		{GameMetadata: types.GameMetadata{Proxy: validGame}, Status: types.GameStatusDefenderWon, AgreeWithClaim: true, L1HeadNum: 200},
+		{GameMetadata: types.GameMetadata{Proxy: inProgressGame}, Status: types.GameStatusInProgress, AgreeWithClaim: true, L1HeadNum: 100},
+		{GameMetadata: types.GameMetadata{Proxy: invalidGame}, Status: types.GameStatusDefenderWon, AgreeWithClaim: false, L1HeadNum: 300},
+		{GameMetadata: types.GameMetadata{Proxy: blacklistedGame}, Status: types.GameStatusDefenderWon, AgreeWithClaim: true, L1HeadNum: 400},
+	}
+
+	t.Run("NoProofs", func(t *testing.T) {
+		monitor, _, _, m, _ := setupWithdrawalProofMonitorTest(t, 100)
+		monitor.CheckWithdrawalProofs(games)
+		require.Equal(t, 0, m.counts[metrics.ProofAgainstValidGame])
+		require.Equal(t, 0, m.counts[metrics.ProofAgainstInProgressGame])
+		require.Equal(t, 0, m.counts[metrics.ProofAgainstBlacklistedGame])
+		require.Equal(t, 0, m.counts[metrics.ProofAgainstInvalidGame])
+	})
+
+	t.Run("SearchesFromOldestGame", func(t *testing.T) {
+		monitor, portal, logFetcher, _, _ := setupWithdrawalProofMonitorTest(t, 100+maxLogBlockRange+10)
+		monitor.CheckWithdrawalProofs(games)
+		require.Equal(t, [][2]uint64{{100, 100 + maxLogBlockRange - 1}, {100 + maxLogBlockRange, 100 + maxLogBlockRange + 10}}, portal.queries)
+
+		// Subsequent checks only search new blocks
+		logFetcher.head += 5
+		monitor.CheckWithdrawalProofs(games)
+		require.Len(t, portal.queries, 3)
+		require.Equal(t, [2]uint64{100 + maxLogBlockRange + 11, 100 + maxLogBlockRange + 15}, portal.queries[2])
+	})
+
+	t.Run("CategorizeProofs", func(t *testing.T) {
+		monitor, portal, _, m, logs := setupWithdrawalProofMonitorTest(t, 1000)
+		portal.addProof(common.Hash{0x01}, validGame)
+		portal.addProof(common.Hash{0x02}, validGame)
+		portal.addProof(common.Hash{0x03}, inProgressGame)
+		portal.addProof(common.Hash{0x04}, invalidGame)
+		portal.addProof(common.Hash{0x05}, blacklistedGame)
+		portal.addProof(common.Hash{0x06}, unmonitoredGame)
+		portal.blacklisted[blacklistedGame] = true
+		monitor.CheckWithdrawalProofs(games)
+
+		require.Equal(t, 2, m.counts[metrics.ProofAgainstValidGame])
+		require.Equal(t, 1, m.counts[metrics.ProofAgainstInProgressGame])
+		require.Equal(t, 1, m.counts[metrics.ProofAgainstBlacklistedGame])
+		require.Equal(t, 1, m.counts[metrics.ProofAgainstInvalidGame])
+
+		require.NotNil(t, logs.FindLog(
+			testlog.NewLevelFilter(log.LevelWarn),
+			testlog.NewMessageFilter("Withdrawal proven against in progress game"),
+			testlog.NewAttributesFilter("game", inProgressGame.Hex())))
+		require.NotNil(t, logs.FindLog(
+			testlog.NewLevelFilter(log.LevelError),
+			testlog.NewMessageFilter("Withdrawal proven against invalid game"),
+			testlog.NewAttributesFilter("game", invalidGame.Hex())))
+		require.NotNil(t, logs.FindLog(
+			testlog.NewLevelFilter(log.LevelError),
+			testlog.NewMessageFilter("Withdrawal proven against blacklisted game"),
+			testlog.NewAttributesFilter("game", blacklistedGame.Hex())))
+
+		// Proofs against games that are no longer monitored are not tracked
+		require.Len(t, monitor.proofs, 5)
+	})
+
+	t.Run("ProofAgainstInProgressGameWithInvalidRoot", func(t *testing.T) {
+		monitor, portal, _, m, logs := setupWithdrawalProofMonitorTest(t, 1000)
+		game := &monTypes.EnrichedGameData{GameMetadata: types.GameMetadata{Proxy: inProgressGame}, Status: types.GameStatusInProgress, AgreeWithClaim: false}
+		portal.addProof(common.Hash{0x01}, inProgressGame)
+		monitor.CheckWithdrawalProofs([]*monTypes.EnrichedGameData{game})
+		require.Equal(t, 1, m.counts[metrics.ProofAgainstInProgressGame])
+		require.NotNil(t, logs.FindLog(
+			testlog.NewLevelFilter(log.LevelError),
+			testlog.NewMessageFilter("Withdrawal proven against in progress game with invalid root claim"),
+			testlog.NewAttributesFilter("game", inProgressGame.Hex())))
+	})
+
+	t.Run("ChecksKnownProofsWhenSearchFails", func(t *testing.T) {
+		monitor, portal, logFetcher, m, logs := setupWithdrawalProofMonitorTest(t, 1000)
+		portal.addProof(common.Hash{0x01}, invalidGame)
+		monitor.CheckWithdrawalProofs(games)
+		require.Equal(t, 1, m.counts[metrics.ProofAgainstInvalidGame])
+
+		logFetcher.head = 2000
+		logFetcher.err = errors.New("boom")
+		m.counts = make(map[metrics.WithdrawalProofStatus]int)
+		monitor.CheckWithdrawalProofs(games)
+		require.Equal(t, 1, m.counts[metrics.ProofAgainstInvalidGame])
+		require.NotNil(t, logs.FindLog(
+			testlog.NewLevelFilter(log.LevelError),
+			testlog.NewMessageFilter("Failed to load withdrawal proofs")))
+	})
+}
+
+func setupWithdrawalProofMonitorTest(t *testing.T, head uint64) (*WithdrawalProofMonitor, *stubPortal, *stubLogFetcher, *stubWithdrawalProofMetrics, *testlog.CapturingHandler) {
+	logger, logs := testlog.CaptureLogger(t, log.LvlDebug)
+	portal := &stubPortal{
+		proofs:      make(map[contracts.WithdrawalProof]common.Address),
+		blacklisted: make(map[common.Address]bool),
+	}
+	logFetcher := &stubLogFetcher{head: head}
+	m := &stubWithdrawalProofMetrics{counts: make(map[metrics.WithdrawalProofStatus]int)}
+	monitor := NewWithdrawalProofMonitor(context.Background(), logger, m, portal, logFetcher)
+	return monitor, portal, logFetcher, m, logs
+}
+
+type stubWithdrawalProofMetrics struct {
+	counts map[metrics.WithdrawalProofStatus]int
+}
+
+func (s *stubWithdrawalProofMetrics) RecordWithdrawalProofs(status metrics.WithdrawalProofStatus, count int) {
+	s.counts[status] = count
+}
+
+type stubLogFetcher struct {
+	head uint64
+	err  error
+}
+
+func (s *stubLogFetcher) BlockNumber(_ context.Context) (uint64, error) {
+	return s.head, nil
+}
+
+func (s *stubLogFetcher) FilterLogs(_ context.Context, _ ethereum.FilterQuery) ([]ethTypes.Log, error) {
+	// The stub portal records the queries and supplies the decoded proofs
+	return nil, s.err
+}
+
+type stubPortal struct {
+	queries     [][2]uint64
+	proofs      map[contracts.WithdrawalProof]common.Address
+	blacklisted map[common.Address]bool
+}
+
+func (s *stubPortal) addProof(hash common.Hash, game common.Address) {
+	s.proofs[contracts.WithdrawalProof{WithdrawalHash: hash, ProofSubmitter: common.Address{0x99}}] = game
+}
+
+func (s *stubPortal) WithdrawalProofsQuery(fromBlock uint64, toBlock uint64) ethereum.FilterQuery {
+	s.queries = append(s.queries, [2]uint64{fromBlock, toBlock})
+	return ethereum.FilterQuery{}
+}
+
+func (s *stubPortal) DecodeWithdrawalProofs(_ []ethTypes.Log) []contracts.WithdrawalProof {
+	proofs := make([]contracts.WithdrawalProof, 0, len(s.proofs))
+	for proof := range s.proofs {
+		proofs = append(proofs, proof)
+	}
+	return proofs
+}
+
+func (s *stubPortal) GetProvenWithdrawals(_ context.Context, _ rpcblock.Block, proofs ...contracts.WithdrawalProof) ([]*contracts.ProvenWithdrawal, error) {
+	proven := make([]*contracts.ProvenWithdrawal, len(proofs))
+	for i, proof := range proofs {
+		proven[i] = &contracts.ProvenWithdrawal{WithdrawalProof: proof, DisputeGame: s.proofs[proof]}
+	}
+	return proven, nil
+}
+
+func (s *stubPortal) GetBlacklisted(_ context.Context, _ rpcblock.Block, games ...common.Address) ([]bool, error) {
+	blacklisted := make([]bool, len(games))
+	for i, game := range games {
+		blacklisted[i] = s.blacklisted[game]
+	}
+	return blacklisted, nil
+}
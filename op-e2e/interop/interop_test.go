@@ -7,10 +7,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum-optimism/optimism/op-chain-ops/interopgen"
 	"github.com/ethereum-optimism/optimism/op-e2e/system/helpers"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	supervisortypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
 // TestInteropTrivial tests a simple interop scenario
@@ -25,10 +28,7 @@ func TestInteropTrivial(t *testing.T) {
 		L2ChainIDs:       []uint64{900200, 900201},
 		GenesisTimestamp: uint64(time.Now().Unix() + 3), // start chain 3 seconds from now
 	}
-	worldResources := worldResourcePaths{
-		foundryArtifacts: "../../packages/contracts-bedrock/forge-artifacts",
-		sourceMap:        "../../packages/contracts-bedrock",
-	}
+	worldResources := WorldResourcePathsFromRoot("../..")
 
 	// create a super system from the recipe
 	// and get the L2 IDs for use in the test
@@ -98,3 +98,33 @@ func TestInteropTrivial(t *testing.T) {
 	time.Sleep(10 * time.Second)
 
 }
+
+// TestInteropExecuteMessage tests that a message initiated on one chain can be executed on another chain.
+// An event-log is emitted on Chain A, and executed by a transaction on Chain B through the CrossL2Inbox.
+// Both the initiating and the executing blocks are checked to become cross-unsafe.
+func TestInteropExecuteMessage(t *testing.T) {
+	recipe := interopgen.InteropDevRecipe{
+		L1ChainID:        900100,
+		L2ChainIDs:       []uint64{900200, 900201},
+		GenesisTimestamp: uint64(time.Now().Unix() + 3), // start chain 3 seconds from now
+	}
+	worldResources := WorldResourcePathsFromRoot("../..")
+	s2 := NewSuperSystem(t, &recipe, worldResources)
+	ids := s2.L2IDs()
+	chainA := ids[0]
+	chainB := ids[1]
+	s2.AddUser("Alice")
+
+	// initiate a message on Chain A
+	s2.DeployEmitterContract(chainA, "Alice")
+	initRec := s2.EmitData(chainA, "Alice", "0x1234567890abcdef")
+	identifier, payload := s2.InitiatingMessage(chainA, initRec, 0)
+	s2.WaitForSafety(chainA, eth.ReceiptBlockID(initRec), supervisortypes.CrossUnsafe)
+
+	// the message can only be executed in a block after the initiating block
+	s2.AdvanceL2(chainB)
+	target := s2.Address(chainB, "Alice")
+	execRec := s2.ExecuteMessage(chainB, "Alice", identifier, target, payload)
+	require.Equal(t, types.ReceiptStatusSuccessful, execRec.Status, "executing message should succeed")
+	s2.WaitForSafety(chainB, eth.ReceiptBlockID(execRec), supervisortypes.CrossUnsafe)
+}
@@ -1,3 +1,6 @@
+// Package interop provides an interop devnet harness: multiple L2 chains on a shared L1, with an op-supervisor,
+// and an op-node, batcher and proposer per L2. The harness is importable, to write interop integration tests
+// outside of op-e2e: create it with NewSuperSystem, and drive it through the SuperSystem interface.
 package interop
 
 import (
@@ -14,6 +17,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-e2e/system/helpers"
 
 	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -45,14 +49,18 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/endpoint"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
+	"github.com/ethereum-optimism/optimism/op-service/predeploys"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	supervisorConfig "github.com/ethereum-optimism/optimism/op-supervisor/config"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor"
+	supervisortypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+	"github.com/ethereum-optimism/optimism/packages/contracts-bedrock/snapshots"
 )
 
 // SuperSystem is an interface for the system (collection of connected resources)
@@ -94,10 +102,18 @@ type SuperSystem interface {
 	EmitData(network string, username string, data string) *types.Receipt
 	// Access a contract on a network by name
 	Contract(network string, contractName string) interface{}
+	// Wait for the sequencer of a network to build a new block, and return it
+	AdvanceL2(network string) eth.BlockID
+	// Get the identifier and the payload of the message initiated by a log of a receipt on a network
+	InitiatingMessage(network string, receipt *types.Receipt, logIndex int) (supervisortypes.Identifier, []byte)
+	// Execute a message on a network, from the given user, through the CrossL2Inbox
+	ExecuteMessage(network string, username string, id supervisortypes.Identifier, target common.Address, message []byte) *types.Receipt
+	// Wait for the supervisor to consider a block of a network at least as safe as the given safety level
+	WaitForSafety(network string, block eth.BlockID, level supervisortypes.SafetyLevel)
 }
 
 // NewSuperSystem creates a new SuperSystem from a recipe. It creates an interopE2ESystem.
-func NewSuperSystem(t *testing.T, recipe *interopgen.InteropDevRecipe, w worldResourcePaths) SuperSystem {
+func NewSuperSystem(t *testing.T, recipe *interopgen.InteropDevRecipe, w WorldResourcePaths) SuperSystem {
 	s2 := &interopE2ESystem{recipe: recipe}
 	s2.prepare(t, w)
 	return s2
@@ -141,13 +157,23 @@ func (s *interopE2ESystem) prepareHDWallet() *devkeys.MnemonicDevKeys {
 	return hdWallet
 }
 
-type worldResourcePaths struct {
-	foundryArtifacts string
-	sourceMap        string
+// WorldResourcePaths are the paths to the contracts used to deploy the world.
+type WorldResourcePaths struct {
+	FoundryArtifacts string
+	SourceMap        string
+}
+
+// WorldResourcePathsFromRoot returns the paths to the contracts in the monorepo at the given root.
+// The contracts must have been built with forge.
+func WorldResourcePathsFromRoot(root string) WorldResourcePaths {
+	return WorldResourcePaths{
+		FoundryArtifacts: filepath.Join(root, "packages", "contracts-bedrock", "forge-artifacts"),
+		SourceMap:        filepath.Join(root, "packages", "contracts-bedrock"),
+	}
 }
 
 // prepareWorld creates the world configuration from the recipe and deploys it
-func (s *interopE2ESystem) prepareWorld(w worldResourcePaths) (*interopgen.WorldDeployment, *interopgen.WorldOutput) {
+func (s *interopE2ESystem) prepareWorld(w WorldResourcePaths) (*interopgen.WorldDeployment, *interopgen.WorldOutput) {
 	// Build the world configuration from the recipe and the HD wallet
 	worldCfg, err := s.recipe.Build(s.hdWallet)
 	require.NoError(s.t, err)
@@ -157,8 +183,8 @@ func (s *interopE2ESystem) prepareWorld(w worldResourcePaths) (*interopgen.World
 	require.NoError(s.t, worldCfg.Check(logger))
 
 	// create the foundry artifacts and source map
-	foundryArtifacts := foundry.OpenArtifactsDir(w.foundryArtifacts)
-	sourceMap := foundry.NewSourceMapFS(os.DirFS(w.sourceMap))
+	foundryArtifacts := foundry.OpenArtifactsDir(w.FoundryArtifacts)
+	sourceMap := foundry.NewSourceMapFS(os.DirFS(w.SourceMap))
 
 	// deploy the world, using the logger, foundry artifacts, source map, and world configuration
 	worldDeployment, worldOutput, err := interopgen.Deploy(logger, foundryArtifacts, sourceMap, worldCfg)
@@ -469,7 +495,7 @@ func (s *interopE2ESystem) SupervisorClient() *sources.SupervisorClient {
 // prepare sets up the system for testing
 // components are built iteratively, so that they can be reused or modified
 // their creation can't be safely skipped or reordered at this time
-func (s *interopE2ESystem) prepare(t *testing.T, w worldResourcePaths) {
+func (s *interopE2ESystem) prepare(t *testing.T, w WorldResourcePaths) {
 	s.t = t
 	s.logger = testlog.Logger(s.t, log.LevelInfo)
 	s.hdWallet = s.prepareHDWallet()
@@ -658,6 +684,98 @@ func (s *interopE2ESystem) Contract(id string, name string) interface{} {
 	return s.l2s[id].contracts[name]
 }
 
+// AdvanceL2 waits for the sequencer of the L2 to build a new block, and returns it.
+func (s *interopE2ESystem) AdvanceL2(id string) eth.BlockID {
+	client := s.L2GethClient(id)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	start, err := client.HeaderByNumber(ctx, nil)
+	require.NoError(s.t, err, "failed to get L2 head")
+	for {
+		head, err := client.HeaderByNumber(ctx, nil)
+		require.NoError(s.t, err, "failed to get L2 head")
+		if head.Number.Cmp(start.Number) > 0 {
+			return eth.HeaderBlockID(head)
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			s.t.Fatalf("no new block on L2 %s after %v", id, start.Number)
+		}
+	}
+}
+
+// InitiatingMessage returns the identifier and the payload of the message initiated by
+// the log at the given index of the receipt, to execute the message on another L2.
+func (s *interopE2ESystem) InitiatingMessage(id string, receipt *types.Receipt, logIndex int) (supervisortypes.Identifier, []byte) {
+	require.Less(s.t, logIndex, len(receipt.Logs), "no log %d in receipt", logIndex)
+	l := receipt.Logs[logIndex]
+	header, err := s.L2GethClient(id).HeaderByHash(context.Background(), receipt.BlockHash)
+	require.NoError(s.t, err, "failed to get block of initiating message")
+	var payload []byte
+	for _, topic := range l.Topics {
+		payload = append(payload, topic.Bytes()...)
+	}
+	payload = append(payload, l.Data...)
+	return supervisortypes.Identifier{
+		Origin:      l.Address,
+		BlockNumber: l.BlockNumber,
+		LogIndex:    uint64(l.Index),
+		Timestamp:   header.Time,
+		ChainID:     supervisortypes.ChainIDFromBig(s.l2s[id].chainID),
+	}, payload
+}
+
+// ExecuteMessage executes a message on the L2, from the given sender, by calling executeMessage on the CrossL2Inbox.
+func (s *interopE2ESystem) ExecuteMessage(
+	id string,
+	sender string,
+	msgIdentifier supervisortypes.Identifier,
+	target common.Address,
+	message []byte,
+) *types.Receipt {
+	chainID := msgIdentifier.ChainID
+	data, err := snapshots.LoadCrossL2InboxABI().Pack("executeMessage", struct {
+		Origin      common.Address
+		BlockNumber *big.Int
+		LogIndex    *big.Int
+		Timestamp   *big.Int
+		ChainId     *big.Int
+	}{
+		Origin:      msgIdentifier.Origin,
+		BlockNumber: new(big.Int).SetUint64(msgIdentifier.BlockNumber),
+		LogIndex:    new(big.Int).SetUint64(msgIdentifier.LogIndex),
+		Timestamp:   new(big.Int).SetUint64(msgIdentifier.Timestamp),
+		ChainId:     (*uint256.Int)(&chainID).ToBig(),
+	}, target, message)
+	require.NoError(s.t, err, "failed to pack executeMessage call")
+	inbox := predeploys.CrossL2InboxAddr
+	return s.SendL2Tx(id, sender, func(opts *helpers.TxOpts) {
+		opts.ToAddr = &inbox
+		opts.Data = data
+		opts.Gas = 1_000_000
+		opts.GasFeeCap = big.NewInt(1_000_000_000)
+		opts.GasTipCap = big.NewInt(1_000_000_000)
+	})
+}
+
+// WaitForSafety waits for the supervisor to consider the block of the L2 at least as safe as the given level.
+func (s *interopE2ESystem) WaitForSafety(id string, block eth.BlockID, level supervisortypes.SafetyLevel) {
+	chainID := supervisortypes.ChainIDFromBig(s.l2s[id].chainID)
+	var current supervisortypes.SafetyLevel
+	require.Eventually(s.t, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		safety, err := s.SupervisorClient().CheckBlock(ctx, chainID, block.Hash, block.Number)
+		if err != nil {
+			s.logger.Warn("Failed to check block safety", "chain", id, "block", block, "err", err)
+			return false
+		}
+		current = safety
+		return current.AtLeastAsSafe(level)
+	}, 2*time.Minute, time.Second, "block %s of L2 %s did not reach safety level %s", block, id, level)
+}
+
 func mustDial(t *testing.T, logger log.Logger) func(v string) *rpc.Client {
 	return func(v string) *rpc.Client {
 		cl, err := dial.DialRPCClientWithTimeout(context.Background(), 30*time.Second, logger, v)
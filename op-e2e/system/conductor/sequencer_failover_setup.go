@@ -112,9 +112,9 @@ func setupSequencerFailoverTest(t *testing.T) (*e2esys.System, map[string]*condu
 	}, 50*time.Second, 500*time.Millisecond, "Expected sequencers to become healthy")
 
 	// unpause all conductors
-	require.NoError(t, c1.client.Resume(ctx))
-	require.NoError(t, c2.client.Resume(ctx))
-	require.NoError(t, c3.client.Resume(ctx))
+	require.NoError(t, c1.client.Resume(ctx, "op-e2e", "sequencers are healthy"))
+	require.NoError(t, c2.client.Resume(ctx, "op-e2e", "sequencers are healthy"))
+	require.NoError(t, c3.client.Resume(ctx, "op-e2e", "sequencers are healthy"))
 
 	// final check, make sure everything is in the right place
 	require.True(t, conductorResumed(t, ctx, c1))
@@ -55,17 +55,25 @@ func TestSequencerFailover_ConductorRPC(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, active, "Expected conductor to be active")
 
-	err = c1.client.Pause(ctx)
+	require.Error(t, c1.client.Pause(ctx, "op-e2e", ""), "Expected pause without reason to be refused")
+	err = c1.client.Pause(ctx, "op-e2e", "test pause")
 	require.NoError(t, err)
 	active, err = c1.client.Active(ctx)
 	require.NoError(t, err)
 	require.False(t, active, "Expected conductor to be paused")
+	pauseState, err := c1.client.PauseState(ctx)
+	require.NoError(t, err)
+	require.True(t, pauseState.Paused)
+	require.Equal(t, "test pause", pauseState.LastChange.Reason)
 
-	err = c1.client.Resume(ctx)
+	err = c1.client.Resume(ctx, "op-e2e", "test resume")
 	require.NoError(t, err)
 	active, err = c1.client.Active(ctx)
 	require.NoError(t, err)
 	require.True(t, active, "Expected conductor to be active")
+	history, err := c1.client.PauseHistory(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 3, "Expected the resume on setup, and the pause and resume above")
 
 	t.Log("Testing LeaderWithID")
 	leader1, err := c1.client.LeaderWithID(ctx)
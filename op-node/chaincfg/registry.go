@@ -0,0 +1,195 @@
+package chaincfg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+)
+
+// maxRegistrySize limits how much data is read when loading a registry bundle.
+const maxRegistrySize = 64 << 20
+
+var (
+	ErrUnknownChain         = errors.New("unknown chain")
+	ErrUnknownDependencySet = errors.New("unknown dependency set")
+	ErrRegistryHashMismatch = errors.New("registry hash mismatch")
+)
+
+// DependencySet is a set of chains that accept each other's messages, keyed by decimal chain ID.
+// The JSON encoding matches the dependency set emitted by op-deployer for op-supervisor.
+type DependencySet struct {
+	Dependencies map[string]Dependency `json:"dependencies"`
+}
+
+type Dependency struct {
+	ChainIndex uint32 `json:"chainIndex"`
+	// ActivationTime is the interop activation time of the chain.
+	ActivationTime uint64 `json:"activationTime"`
+}
+
+// HasChain returns true if the chain with the given decimal chain ID is part of the dependency set.
+func (d *DependencySet) HasChain(chainID string) bool {
+	_, ok := d.Dependencies[chainID]
+	return ok
+}
+
+// RegistryBundle is a self-contained set of chain configs and interop dependency sets,
+// to distribute registry data that is not embedded in the binary.
+type RegistryBundle struct {
+	// Chains are keyed by chain name.
+	Chains map[string]*rollup.Config `json:"chains"`
+	// DependencySets are keyed by name.
+	DependencySets map[string]*DependencySet `json:"dependencySets"`
+}
+
+// Check verifies that the chain configs and dependency sets agree with each other:
+// every chain of a dependency set is in the bundle, and activates interop at the time of its dependency.
+func (b *RegistryBundle) Check() error {
+	byID := make(map[string]*rollup.Config, len(b.Chains))
+	for name, cfg := range b.Chains {
+		if cfg == nil || cfg.L2ChainID == nil {
+			return fmt.Errorf("chain %q: missing chain ID", name)
+		}
+		id := cfg.L2ChainID.String()
+		if _, ok := byID[id]; ok {
+			return fmt.Errorf("chain %q: duplicate chain ID %s", name, id)
+		}
+		byID[id] = cfg
+	}
+	for name, depSet := range b.DependencySets {
+		indices := make(map[uint32]string, len(depSet.Dependencies))
+		for id, dep := range depSet.Dependencies {
+			cfg, ok := byID[id]
+			if !ok {
+				return fmt.Errorf("dependency set %q: %w %s", name, ErrUnknownChain, id)
+			}
+			if other, ok := indices[dep.ChainIndex]; ok {
+				return fmt.Errorf("dependency set %q: chains %s and %s have the same chain index %d", name, id, other, dep.ChainIndex)
+			}
+			indices[dep.ChainIndex] = id
+			if cfg.InteropTime == nil {
+				return fmt.Errorf("dependency set %q: chain %s does not schedule interop", name, id)
+			}
+			if *cfg.InteropTime != dep.ActivationTime {
+				return fmt.Errorf("dependency set %q: chain %s activates at %d, but its interop time is %d",
+					name, id, dep.ActivationTime, *cfg.InteropTime)
+			}
+		}
+	}
+	return nil
+}
+
+// Registry provides chain configs and interop dependency sets, by chain name or chain ID,
+// so that services configured from the same registry agree on the parameters of the chains.
+type Registry struct {
+	// bundle is nil when using the superchain-registry embedded in the binary.
+	bundle *RegistryBundle
+}
+
+// EmbeddedRegistry is the superchain-registry embedded in the binary.
+// It does not define any dependency sets yet.
+var EmbeddedRegistry = &Registry{}
+
+// LoadRegistry loads a registry bundle from a http(s) URL or a file path.
+// The bundle must match the SHA-256 hash it is pinned to. The hash may only be omitted for files.
+func LoadRegistry(ctx context.Context, source string, pinned common.Hash) (*Registry, error) {
+	remote := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+	if remote && pinned == (common.Hash{}) {
+		return nil, fmt.Errorf("remote registry %q must be pinned to a hash", source)
+	}
+	var data []byte
+	var err error
+	if remote {
+		data, err = fetchRegistry(ctx, source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry %q: %w", source, err)
+	}
+	if pinned != (common.Hash{}) {
+		if got := common.Hash(sha256.Sum256(data)); got != pinned {
+			return nil, fmt.Errorf("%w: registry %q has hash %s, expected %s", ErrRegistryHashMismatch, source, got, pinned)
+		}
+	}
+	var bundle RegistryBundle
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode registry %q: %w", source, err)
+	}
+	if err := bundle.Check(); err != nil {
+		return nil, fmt.Errorf("invalid registry %q: %w", source, err)
+	}
+	return &Registry{bundle: &bundle}, nil
+}
+
+func fetchRegistry(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRegistrySize {
+		return nil, fmt.Errorf("registry is larger than %d bytes", maxRegistrySize)
+	}
+	return data, nil
+}
+
+// RollupConfig returns the rollup config of the chain with the given name, or decimal chain ID.
+func (r *Registry) RollupConfig(chain string) (*rollup.Config, error) {
+	if r.bundle == nil {
+		if id, err := strconv.ParseUint(chain, 10, 64); err == nil && ChainByName(chain) == nil {
+			return rollup.LoadOPStackRollupConfig(id)
+		}
+		return GetRollupConfig(chain)
+	}
+	// copy the config, so overrides applied by the caller do not change the registry
+	if cfg, ok := r.bundle.Chains[chain]; ok {
+		out := *cfg
+		return &out, nil
+	}
+	if id, ok := new(big.Int).SetString(chain, 10); ok {
+		for _, cfg := range r.bundle.Chains {
+			if cfg.L2ChainID.Cmp(id) == 0 {
+				out := *cfg
+				return &out, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownChain, chain)
+}
+
+// DependencySet returns the dependency set with the given name.
+func (r *Registry) DependencySet(name string) (*DependencySet, error) {
+	if r.bundle != nil {
+		if depSet, ok := r.bundle.DependencySets[name]; ok {
+			return depSet, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownDependencySet, name)
+}
@@ -0,0 +1,144 @@
+package chaincfg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+)
+
+func testBundle() *RegistryBundle {
+	interopTime := uint64(1000)
+	return &RegistryBundle{
+		Chains: map[string]*rollup.Config{
+			"chain-a": {L2ChainID: big.NewInt(900200), InteropTime: &interopTime},
+			"chain-b": {L2ChainID: big.NewInt(900201), InteropTime: &interopTime},
+		},
+		DependencySets: map[string]*DependencySet{
+			"devnet": {Dependencies: map[string]Dependency{
+				"900200": {ChainIndex: 0, ActivationTime: 1000},
+				"900201": {ChainIndex: 1, ActivationTime: 1000},
+			}},
+		},
+	}
+}
+
+func writeBundle(t *testing.T, bundle *RegistryBundle) (string, common.Hash) {
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path, sha256.Sum256(data)
+}
+
+func TestLoadRegistry(t *testing.T) {
+	path, hash := writeBundle(t, testBundle())
+
+	t.Run("file", func(t *testing.T) {
+		registry, err := LoadRegistry(context.Background(), path, common.Hash{})
+		require.NoError(t, err)
+
+		byName, err := registry.RollupConfig("chain-a")
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(900200), byName.L2ChainID)
+		byID, err := registry.RollupConfig("900201")
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(900201), byID.L2ChainID)
+		_, err = registry.RollupConfig("chain-c")
+		require.ErrorIs(t, err, ErrUnknownChain)
+
+		depSet, err := registry.DependencySet("devnet")
+		require.NoError(t, err)
+		require.True(t, depSet.HasChain("900200"))
+		require.False(t, depSet.HasChain("900202"))
+		_, err = registry.DependencySet("mainnet")
+		require.ErrorIs(t, err, ErrUnknownDependencySet)
+	})
+
+	t.Run("pinned file", func(t *testing.T) {
+		_, err := LoadRegistry(context.Background(), path, hash)
+		require.NoError(t, err)
+		_, err = LoadRegistry(context.Background(), path, common.Hash{0x01})
+		require.ErrorIs(t, err, ErrRegistryHashMismatch)
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeFile(w, r, path)
+		}))
+		defer srv.Close()
+		_, err := LoadRegistry(context.Background(), srv.URL, common.Hash{})
+		require.ErrorContains(t, err, "must be pinned")
+		_, err = LoadRegistry(context.Background(), srv.URL, common.Hash{0x01})
+		require.ErrorIs(t, err, ErrRegistryHashMismatch)
+		registry, err := LoadRegistry(context.Background(), srv.URL, hash)
+		require.NoError(t, err)
+		_, err = registry.DependencySet("devnet")
+		require.NoError(t, err)
+	})
+}
+
+func TestRegistryRollupConfigIsCopy(t *testing.T) {
+	path, _ := writeBundle(t, testBundle())
+	registry, err := LoadRegistry(context.Background(), path, common.Hash{})
+	require.NoError(t, err)
+	cfg, err := registry.RollupConfig("chain-a")
+	require.NoError(t, err)
+	cfg.BlockTime = 5
+	again, err := registry.RollupConfig("chain-a")
+	require.NoError(t, err)
+	require.Zero(t, again.BlockTime)
+}
+
+func TestRegistryBundleCheck(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, testBundle().Check())
+	})
+	t.Run("unknown chain", func(t *testing.T) {
+		bundle := testBundle()
+		bundle.DependencySets["devnet"].Dependencies["900202"] = Dependency{ChainIndex: 2, ActivationTime: 1000}
+		require.ErrorIs(t, bundle.Check(), ErrUnknownChain)
+	})
+	t.Run("duplicate chain ID", func(t *testing.T) {
+		bundle := testBundle()
+		bundle.Chains["chain-c"] = &rollup.Config{L2ChainID: big.NewInt(900200)}
+		require.ErrorContains(t, bundle.Check(), "duplicate chain ID")
+	})
+	t.Run("duplicate chain index", func(t *testing.T) {
+		bundle := testBundle()
+		bundle.DependencySets["devnet"].Dependencies["900201"] = Dependency{ChainIndex: 0, ActivationTime: 1000}
+		require.ErrorContains(t, bundle.Check(), "same chain index")
+	})
+	t.Run("interop not scheduled", func(t *testing.T) {
+		bundle := testBundle()
+		bundle.Chains["chain-a"].InteropTime = nil
+		require.ErrorContains(t, bundle.Check(), "does not schedule interop")
+	})
+	t.Run("mismatched activation time", func(t *testing.T) {
+		bundle := testBundle()
+		bundle.DependencySets["devnet"].Dependencies["900201"] = Dependency{ChainIndex: 1, ActivationTime: 2000}
+		require.ErrorContains(t, bundle.Check(), "activates at 2000, but its interop time is 1000")
+	})
+}
+
+func TestEmbeddedRegistry(t *testing.T) {
+	byName, err := EmbeddedRegistry.RollupConfig("op-mainnet")
+	require.NoError(t, err)
+	byID, err := EmbeddedRegistry.RollupConfig("10")
+	require.NoError(t, err)
+	require.Equal(t, byName, byID)
+	_, err = EmbeddedRegistry.RollupConfig("bar")
+	require.ErrorContains(t, err, "invalid network")
+	_, err = EmbeddedRegistry.DependencySet("op-mainnet")
+	require.ErrorIs(t, err, ErrUnknownDependencySet)
+}
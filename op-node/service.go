@@ -212,7 +212,11 @@ func NewRollupConfigFromCLI(log log.Logger, ctx *cli.Context) (*rollup.Config, e
 	if ctx.Bool(flags.BetaExtraNetworks.Name) {
 		log.Warn("The beta.extra-networks flag is deprecated and can be omitted safely.")
 	}
-	rollupConfig, err := NewRollupConfig(log, network, rollupConfigPath)
+	registry, err := opflags.LoadRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	rollupConfig, err := newRollupConfig(log, registry, network, rollupConfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +225,12 @@ func NewRollupConfigFromCLI(log log.Logger, ctx *cli.Context) (*rollup.Config, e
 }
 
 func NewRollupConfig(log log.Logger, network string, rollupConfigPath string) (*rollup.Config, error) {
+	return newRollupConfig(log, chaincfg.EmbeddedRegistry, network, rollupConfigPath)
+}
+
+// newRollupConfig loads the rollup config of the network, by name or chain ID, from the registry,
+// or the rollup config file if no network is selected.
+func newRollupConfig(log log.Logger, registry *chaincfg.Registry, network string, rollupConfigPath string) (*rollup.Config, error) {
 	if network != "" {
 		if rollupConfigPath != "" {
 			log.Error(`Cannot configure network and rollup-config at the same time.
@@ -228,7 +238,7 @@ Startup will proceed to use the network-parameter and ignore the rollup config.
 Conflicting configuration is deprecated, and will stop the op-node from starting in the future.
 `, "network", network, "rollup_config", rollupConfigPath)
 		}
-		rollupConfig, err := chaincfg.GetRollupConfig(network)
+		rollupConfig, err := registry.RollupConfig(network)
 		if err != nil {
 			return nil, err
 		}
@@ -121,6 +121,24 @@ func (k PrecompileKey) TerminalString() string {
 	return "0x" + hex.EncodeToString(k[:])
 }
 
+// GlobalGenericKey is the hash of the data a generic global pre-image describes.
+// The pre-image can't be verified from the key alone.
+type GlobalGenericKey [32]byte
+
+func (k GlobalGenericKey) PreimageKey() (out [32]byte) {
+	out = k
+	out[0] = byte(GlobalGenericKeyType)
+	return
+}
+
+func (k GlobalGenericKey) String() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+func (k GlobalGenericKey) TerminalString() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
 // Hint is an interface to enable any program type to function as a hint,
 // when passed to the Hinter interface, returning a string representation
 // of what data the host should prepare pre-images for.
@@ -41,6 +41,9 @@ func WithVerification(source PreimageGetter) PreimageGetter {
 		case PrecompileKeyType:
 			// Can't verify precompile result without knowing the input preimage
 			return data, nil
+		case GlobalGenericKeyType:
+			// Can't verify generic global data without knowing what it describes
+			return data, nil
 		default:
 			return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeyType, key[0])
 		}
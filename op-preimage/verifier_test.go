@@ -45,6 +45,12 @@ func TestWithVerification(t *testing.T) {
 			data:         []byte{4, 3, 5, 7, 3},
 			expectedData: []byte{4, 3, 5, 7, 3},
 		},
+		{
+			name:         "GlobalGenericKey NoVerification",
+			key:          GlobalGenericKey([32]byte{1, 2, 3, 4}),
+			data:         []byte{4, 3, 5, 7, 3},
+			expectedData: []byte{4, 3, 5, 7, 3},
+		},
 		{
 			name:        "UnknownKey",
 			key:         invalidKey([32]byte{0xaa}),
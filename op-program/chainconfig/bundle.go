@@ -0,0 +1,109 @@
+package chainconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	ErrInvalidBundle = errors.New("invalid chain config bundle")
+	ErrChainNotFound = errors.New("chain not found in config bundle")
+)
+
+// Bundle is the configuration of chains that are not included in the embedded superchain registry, such as custom
+// or Stage-0 chains. A bundle is identified by the keccak256 hash of its JSON encoding, which allows the program to
+// verify that it was supplied with the expected configuration.
+type Bundle struct {
+	Chains []*BundleChain `json:"chains"`
+	// DependencySet is the chain IDs in the interop dependency set.
+	// If empty, all chains in the bundle are in the dependency set.
+	DependencySet []uint64 `json:"dependencySet,omitempty"`
+}
+
+// BundleChain is the configuration of a single chain in a Bundle.
+type BundleChain struct {
+	Rollup *rollup.Config `json:"rollup"`
+	// ChainConfig is the config section of the L2 genesis.
+	ChainConfig *params.ChainConfig `json:"chainConfig"`
+}
+
+// DecodeBundle decodes and checks a JSON encoded bundle.
+func DecodeBundle(data []byte) (*Bundle, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	if err := bundle.Check(); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// Check verifies that each chain has a valid rollup config and a chain config for the same chain ID,
+// that no chain is included twice and that all chains in the dependency set are included.
+func (b *Bundle) Check() error {
+	if len(b.Chains) == 0 {
+		return fmt.Errorf("%w: no chains", ErrInvalidBundle)
+	}
+	var chainIDs []uint64
+	for i, chain := range b.Chains {
+		if chain == nil || chain.Rollup == nil || chain.ChainConfig == nil {
+			return fmt.Errorf("%w: chain %d must have a rollup config and chain config", ErrInvalidBundle, i)
+		}
+		if err := chain.Rollup.Check(); err != nil {
+			return fmt.Errorf("%w: chain %d: %w", ErrInvalidBundle, i, err)
+		}
+		if chain.ChainConfig.ChainID == nil || chain.ChainConfig.ChainID.Cmp(chain.Rollup.L2ChainID) != 0 {
+			return fmt.Errorf("%w: chain %d: chain config chain ID %v does not match rollup config chain ID %v",
+				ErrInvalidBundle, i, chain.ChainConfig.ChainID, chain.Rollup.L2ChainID)
+		}
+		chainID := chain.Rollup.L2ChainID.Uint64()
+		if slices.Contains(chainIDs, chainID) {
+			return fmt.Errorf("%w: duplicate chain %v", ErrInvalidBundle, chainID)
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	for _, chainID := range b.DependencySet {
+		if !slices.Contains(chainIDs, chainID) {
+			return fmt.Errorf("%w: dependency set chain %v is not in the bundle", ErrInvalidBundle, chainID)
+		}
+	}
+	return nil
+}
+
+// RollupConfig returns the rollup config of the chain with the given chain ID.
+// Chains that are not in the dependency set are not found.
+func (b *Bundle) RollupConfig(chainID uint64) (*rollup.Config, error) {
+	chain, err := b.chain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return chain.Rollup, nil
+}
+
+// ChainConfig returns the chain config of the chain with the given chain ID.
+// Chains that are not in the dependency set are not found.
+func (b *Bundle) ChainConfig(chainID uint64) (*params.ChainConfig, error) {
+	chain, err := b.chain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return chain.ChainConfig, nil
+}
+
+func (b *Bundle) chain(chainID uint64) (*BundleChain, error) {
+	if len(b.DependencySet) > 0 && !slices.Contains(b.DependencySet, chainID) {
+		return nil, fmt.Errorf("%w: %v is not in the dependency set", ErrChainNotFound, chainID)
+	}
+	for _, chain := range b.Chains {
+		if chain.Rollup.L2ChainID.Uint64() == chainID {
+			return chain, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrChainNotFound, chainID)
+}
@@ -0,0 +1,61 @@
+package chainconfig
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	sepolia := &BundleChain{Rollup: chaincfg.Sepolia, ChainConfig: OPSepoliaChainConfig}
+	mainnet := &BundleChain{Rollup: chaincfg.Mainnet, ChainConfig: OPMainnetChainConfig}
+	sepoliaID := chaincfg.Sepolia.L2ChainID.Uint64()
+	mainnetID := chaincfg.Mainnet.L2ChainID.Uint64()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		data, err := json.Marshal(&Bundle{Chains: []*BundleChain{sepolia, mainnet}})
+		require.NoError(t, err)
+		bundle, err := DecodeBundle(data)
+		require.NoError(t, err)
+
+		rollupCfg, err := bundle.RollupConfig(mainnetID)
+		require.NoError(t, err)
+		require.Equal(t, chaincfg.Mainnet.L2ChainID, rollupCfg.L2ChainID)
+		chainCfg, err := bundle.ChainConfig(sepoliaID)
+		require.NoError(t, err)
+		require.Equal(t, OPSepoliaChainConfig.ChainID, chainCfg.ChainID)
+		_, err = bundle.ChainConfig(1234)
+		require.ErrorIs(t, err, ErrChainNotFound)
+	})
+
+	t.Run("DependencySet", func(t *testing.T) {
+		bundle := &Bundle{Chains: []*BundleChain{sepolia, mainnet}, DependencySet: []uint64{sepoliaID}}
+		require.NoError(t, bundle.Check())
+		_, err := bundle.RollupConfig(sepoliaID)
+		require.NoError(t, err)
+		_, err = bundle.RollupConfig(mainnetID)
+		require.ErrorIs(t, err, ErrChainNotFound)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		mismatched := &BundleChain{Rollup: chaincfg.Sepolia, ChainConfig: &params.ChainConfig{ChainID: big.NewInt(10)}}
+		tests := map[string]*Bundle{
+			"NoChains":            {},
+			"MissingChainConfig":  {Chains: []*BundleChain{{Rollup: chaincfg.Sepolia}}},
+			"MismatchedChainID":   {Chains: []*BundleChain{mismatched}},
+			"DuplicateChain":      {Chains: []*BundleChain{sepolia, sepolia}},
+			"UnknownDependencyID": {Chains: []*BundleChain{sepolia}, DependencySet: []uint64{mainnetID}},
+		}
+		for name, bundle := range tests {
+			t.Run(name, func(t *testing.T) {
+				require.ErrorIs(t, bundle.Check(), ErrInvalidBundle)
+			})
+		}
+		_, err := DecodeBundle([]byte("not json"))
+		require.ErrorIs(t, err, ErrInvalidBundle)
+	})
+}
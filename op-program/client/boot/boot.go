@@ -1,14 +1,16 @@
-package client
+package boot
 
 import (
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"math"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -22,11 +24,18 @@ const (
 	// These local keys are only used for custom chains
 	L2ChainConfigLocalIndex
 	RollupConfigLocalIndex
+
+	// ConfigBundleHashLocalIndex is only used for chains configured by a config bundle
+	ConfigBundleHashLocalIndex
 )
 
 // CustomChainIDIndicator is used to detect when the program should load custom chain configuration
 const CustomChainIDIndicator = uint64(math.MaxUint64)
 
+// BundleChainIDIndicator is used to detect when the program should load chain configuration from the config bundle
+// identified by the hash at ConfigBundleHashLocalIndex.
+const BundleChainIDIndicator = uint64(math.MaxUint64 - 1)
+
 type BootInfo struct {
 	L1Head             common.Hash
 	L2OutputRoot       common.Hash
@@ -59,7 +68,15 @@ func (br *BootstrapClient) BootInfo() *BootInfo {
 
 	var l2ChainConfig *params.ChainConfig
 	var rollupConfig *rollup.Config
-	if l2ChainID == CustomChainIDIndicator {
+	if l2ChainID == BundleChainIDIndicator {
+		bundle := br.ConfigBundle()
+		if len(bundle.Chains) != 1 {
+			panic(fmt.Errorf("config bundle must contain a single chain, got %d", len(bundle.Chains)))
+		}
+		rollupConfig = bundle.Chains[0].Rollup
+		l2ChainConfig = bundle.Chains[0].ChainConfig
+		l2ChainID = rollupConfig.L2ChainID.Uint64()
+	} else if l2ChainID == CustomChainIDIndicator {
 		l2ChainConfig = new(params.ChainConfig)
 		err := json.Unmarshal(br.r.Get(L2ChainConfigLocalIndex), &l2ChainConfig)
 		if err != nil {
@@ -92,3 +109,18 @@ func (br *BootstrapClient) BootInfo() *BootInfo {
 		RollupConfig:       rollupConfig,
 	}
 }
+
+// ConfigBundle reads the config bundle with the hash at ConfigBundleHashLocalIndex.
+// The bundle is a keccak256 pre-image so is verified against the hash, but the hash itself is provided by the host.
+func (br *BootstrapClient) ConfigBundle() *chainconfig.Bundle {
+	hash := common.BytesToHash(br.r.Get(ConfigBundleHashLocalIndex))
+	data := br.r.Get(preimage.Keccak256Key(hash))
+	if crypto.Keccak256Hash(data) != hash {
+		panic(fmt.Errorf("config bundle does not match hash %v", hash))
+	}
+	bundle, err := chainconfig.DecodeBundle(data)
+	if err != nil {
+		panic(fmt.Errorf("failed to bootstrap config bundle: %w", err))
+	}
+	return bundle
+}
@@ -0,0 +1,101 @@
+package boot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var ErrUnknownChainID = errors.New("unknown chain id")
+
+// BootInfoInterop is the boot info for interop mode, where the program transitions from an agreed super root or
+// transition state towards the super root at the game timestamp.
+// The local keys are shared with the single chain BootInfo:
+// the agreed prestate replaces the L2 output root and the game timestamp replaces the L2 claim block number.
+type BootInfoInterop struct {
+	Configs ConfigSource
+
+	L1Head         common.Hash
+	AgreedPrestate common.Hash
+	Claim          common.Hash
+	GameTimestamp  uint64
+}
+
+// ConfigSource provides the configuration of each chain in the dependency set.
+type ConfigSource interface {
+	RollupConfig(chainID uint64) (*rollup.Config, error)
+	ChainConfig(chainID uint64) (*params.ChainConfig, error)
+}
+
+// BootInfoInterop reads the boot info for interop mode.
+// In interop mode the L2 chain ID local key only indicates whether custom chain configs are used, as the chains
+// are listed in the agreed super root. Custom configs are read as JSON arrays with one entry per chain.
+// Chains configured by a config bundle are restricted to the bundle's dependency set, if it has one.
+func (br *BootstrapClient) BootInfoInterop() *BootInfoInterop {
+	l1Head := common.BytesToHash(br.r.Get(L1HeadLocalIndex))
+	agreedPrestate := common.BytesToHash(br.r.Get(L2OutputRootLocalIndex))
+	claim := common.BytesToHash(br.r.Get(L2ClaimLocalIndex))
+	gameTimestamp := binary.BigEndian.Uint64(br.r.Get(L2ClaimBlockNumberLocalIndex))
+	l2ChainID := binary.BigEndian.Uint64(br.r.Get(L2ChainIDLocalIndex))
+
+	var configs ConfigSource = namedConfigSource{}
+	if l2ChainID == BundleChainIDIndicator {
+		configs = br.ConfigBundle()
+	} else if l2ChainID == CustomChainIDIndicator {
+		var custom customConfigSource
+		if err := json.Unmarshal(br.r.Get(L2ChainConfigLocalIndex), &custom.chainConfigs); err != nil {
+			panic("failed to bootstrap l2ChainConfigs")
+		}
+		if err := json.Unmarshal(br.r.Get(RollupConfigLocalIndex), &custom.rollupConfigs); err != nil {
+			panic("failed to bootstrap rollup configs")
+		}
+		configs = &custom
+	}
+	return &BootInfoInterop{
+		Configs:        configs,
+		L1Head:         l1Head,
+		AgreedPrestate: agreedPrestate,
+		Claim:          claim,
+		GameTimestamp:  gameTimestamp,
+	}
+}
+
+// namedConfigSource looks up the configs of chains that are built into the program.
+type namedConfigSource struct{}
+
+func (namedConfigSource) RollupConfig(chainID uint64) (*rollup.Config, error) {
+	return chainconfig.RollupConfigByChainID(chainID)
+}
+
+func (namedConfigSource) ChainConfig(chainID uint64) (*params.ChainConfig, error) {
+	return chainconfig.ChainConfigByChainID(chainID)
+}
+
+type customConfigSource struct {
+	rollupConfigs []*rollup.Config
+	chainConfigs  []*params.ChainConfig
+}
+
+func (c *customConfigSource) RollupConfig(chainID uint64) (*rollup.Config, error) {
+	for _, cfg := range c.rollupConfigs {
+		if cfg.L2ChainID.Uint64() == chainID {
+			return cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rollup config for chain %v", ErrUnknownChainID, chainID)
+}
+
+func (c *customConfigSource) ChainConfig(chainID uint64) (*params.ChainConfig, error) {
+	for _, cfg := range c.chainConfigs {
+		if cfg.ChainID.Uint64() == chainID {
+			return cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no chain config for chain %v", ErrUnknownChainID, chainID)
+}
@@ -0,0 +1,112 @@
+package boot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapClientInterop(t *testing.T) {
+	t.Run("NamedChains", func(t *testing.T) {
+		oracle := newMockInteropBootstrapOracle(chaincfg.Sepolia.L2ChainID.Uint64(), nil, nil)
+		bootInfo := NewBootstrapClient(oracle).BootInfoInterop()
+		oracle.assertBootInfo(t, bootInfo)
+
+		rollupCfg, err := bootInfo.Configs.RollupConfig(chaincfg.Sepolia.L2ChainID.Uint64())
+		require.NoError(t, err)
+		require.Equal(t, chaincfg.Sepolia, rollupCfg)
+		chainCfg, err := bootInfo.Configs.ChainConfig(chaincfg.Sepolia.L2ChainID.Uint64())
+		require.NoError(t, err)
+		require.Equal(t, chainconfig.OPSepoliaChainConfig, chainCfg)
+	})
+
+	t.Run("CustomChains", func(t *testing.T) {
+		rollupCfgs := []*rollup.Config{chaincfg.Sepolia, chaincfg.Mainnet}
+		chainCfgs := []*params.ChainConfig{chainconfig.OPSepoliaChainConfig, chainconfig.OPMainnetChainConfig}
+		oracle := newMockInteropBootstrapOracle(CustomChainIDIndicator, rollupCfgs, chainCfgs)
+		bootInfo := NewBootstrapClient(oracle).BootInfoInterop()
+		oracle.assertBootInfo(t, bootInfo)
+
+		for i, expected := range rollupCfgs {
+			chainID := expected.L2ChainID.Uint64()
+			rollupCfg, err := bootInfo.Configs.RollupConfig(chainID)
+			require.NoError(t, err)
+			require.Equal(t, expected, rollupCfg)
+			chainCfg, err := bootInfo.Configs.ChainConfig(chainID)
+			require.NoError(t, err)
+			require.Equal(t, chainCfgs[i].ChainID, chainCfg.ChainID)
+		}
+
+		_, err := bootInfo.Configs.RollupConfig(1234)
+		require.ErrorIs(t, err, ErrUnknownChainID)
+		_, err = bootInfo.Configs.ChainConfig(1234)
+		require.ErrorIs(t, err, ErrUnknownChainID)
+	})
+}
+
+type mockInteropBootstrapOracle struct {
+	l1Head         common.Hash
+	agreedPrestate common.Hash
+	claim          common.Hash
+	gameTimestamp  uint64
+	chainID        uint64
+	rollupCfgs     []*rollup.Config
+	chainCfgs      []*params.ChainConfig
+}
+
+func newMockInteropBootstrapOracle(chainID uint64, rollupCfgs []*rollup.Config, chainCfgs []*params.ChainConfig) *mockInteropBootstrapOracle {
+	return &mockInteropBootstrapOracle{
+		l1Head:         common.HexToHash("0x1111"),
+		agreedPrestate: common.HexToHash("0x2222"),
+		claim:          common.HexToHash("0x3333"),
+		gameTimestamp:  1234,
+		chainID:        chainID,
+		rollupCfgs:     rollupCfgs,
+		chainCfgs:      chainCfgs,
+	}
+}
+
+func (o *mockInteropBootstrapOracle) assertBootInfo(t *testing.T, bootInfo *BootInfoInterop) {
+	require.Equal(t, o.l1Head, bootInfo.L1Head)
+	require.Equal(t, o.agreedPrestate, bootInfo.AgreedPrestate)
+	require.Equal(t, o.claim, bootInfo.Claim)
+	require.Equal(t, o.gameTimestamp, bootInfo.GameTimestamp)
+}
+
+func (o *mockInteropBootstrapOracle) Get(key preimage.Key) []byte {
+	switch key.PreimageKey() {
+	case L1HeadLocalIndex.PreimageKey():
+		return o.l1Head[:]
+	case L2OutputRootLocalIndex.PreimageKey():
+		return o.agreedPrestate[:]
+	case L2ClaimLocalIndex.PreimageKey():
+		return o.claim[:]
+	case L2ClaimBlockNumberLocalIndex.PreimageKey():
+		return binary.BigEndian.AppendUint64(nil, o.gameTimestamp)
+	case L2ChainIDLocalIndex.PreimageKey():
+		return binary.BigEndian.AppendUint64(nil, o.chainID)
+	case L2ChainConfigLocalIndex.PreimageKey():
+		if o.chainID != CustomChainIDIndicator {
+			panic(fmt.Sprintf("unexpected oracle request for preimage key %x", key.PreimageKey()))
+		}
+		b, _ := json.Marshal(o.chainCfgs)
+		return b
+	case RollupConfigLocalIndex.PreimageKey():
+		if o.chainID != CustomChainIDIndicator {
+			panic(fmt.Sprintf("unexpected oracle request for preimage key %x", key.PreimageKey()))
+		}
+		b, _ := json.Marshal(o.rollupCfgs)
+		return b
+	default:
+		panic("unknown key")
+	}
+}
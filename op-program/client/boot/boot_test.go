@@ -1,15 +1,17 @@
-package client
+package boot
 
 import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,6 +58,56 @@ func TestBootstrapClient_UnknownChainPanics(t *testing.T) {
 	require.Panics(t, func() { client.BootInfo() })
 }
 
+func TestBootstrapClient_ConfigBundle(t *testing.T) {
+	bundle := &chainconfig.Bundle{Chains: []*chainconfig.BundleChain{
+		{Rollup: chaincfg.Sepolia, ChainConfig: chainconfig.OPSepoliaChainConfig},
+	}}
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	hash := crypto.Keccak256Hash(data)
+	oracle := bundleOracle{
+		L1HeadLocalIndex.PreimageKey():             common.HexToHash("0x1111").Bytes(),
+		L2OutputRootLocalIndex.PreimageKey():       common.HexToHash("0x2222").Bytes(),
+		L2ClaimLocalIndex.PreimageKey():            common.HexToHash("0x3333").Bytes(),
+		L2ClaimBlockNumberLocalIndex.PreimageKey(): binary.BigEndian.AppendUint64(nil, 1),
+		L2ChainIDLocalIndex.PreimageKey():          binary.BigEndian.AppendUint64(nil, BundleChainIDIndicator),
+		ConfigBundleHashLocalIndex.PreimageKey():   hash.Bytes(),
+		preimage.Keccak256Key(hash).PreimageKey():  data,
+	}
+
+	t.Run("SingleChain", func(t *testing.T) {
+		bootInfo := NewBootstrapClient(oracle).BootInfo()
+		require.Equal(t, chaincfg.Sepolia.L2ChainID.Uint64(), bootInfo.L2ChainID)
+		require.Equal(t, chaincfg.Sepolia.L2ChainID, bootInfo.RollupConfig.L2ChainID)
+		require.Equal(t, chainconfig.OPSepoliaChainConfig.ChainID, bootInfo.L2ChainConfig.ChainID)
+	})
+
+	t.Run("Interop", func(t *testing.T) {
+		bootInfo := NewBootstrapClient(oracle).BootInfoInterop()
+		rollupCfg, err := bootInfo.Configs.RollupConfig(chaincfg.Sepolia.L2ChainID.Uint64())
+		require.NoError(t, err)
+		require.Equal(t, chaincfg.Sepolia.L2ChainID, rollupCfg.L2ChainID)
+		_, err = bootInfo.Configs.RollupConfig(1234)
+		require.ErrorIs(t, err, chainconfig.ErrChainNotFound)
+	})
+
+	t.Run("HashMismatchPanics", func(t *testing.T) {
+		corrupt := maps.Clone(oracle)
+		corrupt[preimage.Keccak256Key(hash).PreimageKey()] = append(data, ' ')
+		require.Panics(t, func() { NewBootstrapClient(corrupt).BootInfo() })
+	})
+}
+
+type bundleOracle map[[32]byte][]byte
+
+func (o bundleOracle) Get(key preimage.Key) []byte {
+	v, ok := o[key.PreimageKey()]
+	if !ok {
+		panic(fmt.Sprintf("unexpected oracle request for preimage key %x", key.PreimageKey()))
+	}
+	return v
+}
+
 type mockBoostrapOracle struct {
 	b      *BootInfo
 	custom bool
@@ -6,9 +6,13 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/client/decisionlog"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 )
 
+// interop can be set to "true" at build time with -ldflags to build a client that runs in interop mode.
+var interop string
+
 func main() {
 	// Default to a machine parsable but relatively human friendly log format.
 	// Don't do anything fancy to detect if color output is supported.
@@ -17,6 +21,14 @@ func main() {
 		Format: oplog.FormatLogFmt,
 		Color:  false,
 	})
+	if path := os.Getenv(decisionlog.EnvVar); path != "" {
+		// The file is closed when the process exits.
+		f, err := os.Create(path)
+		if err != nil {
+			logger.Crit("Failed to create derivation log", "err", err)
+		}
+		logger = log.NewLogger(decisionlog.NewHandler(logger.Handler(), f))
+	}
 	oplog.SetGlobalLogHandler(logger.Handler())
-	client.Main(logger)
+	client.Main(logger, interop == "true" || os.Getenv(client.InteropEnvVar) == "true")
 }
@@ -0,0 +1,145 @@
+// Package decisionlog records the decisions made by the derivation pipeline as structured events.
+// Events are identified from the log records of the derivation pipeline, so that the program execution is unchanged.
+package decisionlog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// EnvVar is the environment variable that specifies the file to write the decision log to,
+// when the client runs as a native process.
+const EnvVar = "OP_PROGRAM_CLIENT_DERIVATION_LOG"
+
+// Event kinds
+const (
+	KindBatchAccepted    = "batch_accepted"
+	KindBatchDropped     = "batch_dropped"
+	KindBatchGenerated   = "batch_generated"
+	KindChannelCreated   = "channel_created"
+	KindChannelAssembled = "channel_assembled"
+	KindChannelTimedOut  = "channel_timed_out"
+	KindChannelPruned    = "channel_pruned"
+	KindChannelDropped   = "channel_dropped"
+	KindFrameDropped     = "frame_dropped"
+	KindOriginAdvanced   = "origin_advanced"
+	KindPipelineReset    = "pipeline_reset"
+)
+
+// kinds maps the derivation pipeline log messages to the kind of decision they record.
+var kinds = map[string]string{
+	"Found next batch":      KindBatchAccepted,
+	"Dropping batch":        KindBatchDropped,
+	"Generating next batch": KindBatchGenerated,
+	"parent block does not match the next batch. dropped cached batches": KindBatchDropped,
+
+	"created new channel":                 KindChannelCreated,
+	"Reading channel":                     KindChannelAssembled,
+	"channel timed out":                   KindChannelTimedOut,
+	"pruning channel":                     KindChannelPruned,
+	"channel is timed out, ignore frame":  KindFrameDropped,
+	"failed to ingest frame into channel": KindFrameDropped,
+	"Failed to parse frames":              KindFrameDropped,
+	"failed to read batch from channel reader, skipping to next channel now": KindChannelDropped,
+
+	"Advancing bq origin":                    KindOriginAdvanced,
+	"completed reset of derivation pipeline": KindPipelineReset,
+}
+
+// batchCheckAttr is the attribute that identifies the records logged while checking the validity of a batch.
+const batchCheckAttr = "batch_index"
+
+// Event is a single derivation decision.
+type Event struct {
+	Kind string `json:"kind"`
+	Msg  string `json:"msg"`
+	// Reason is the reason a batch was dropped, as logged by the batch validity check.
+	Reason string         `json:"reason,omitempty"`
+	Attrs  map[string]any `json:"attrs,omitempty"`
+}
+
+// output is shared by all handlers derived from the same root handler.
+type output struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	// reason is the most recent batch check message, pending the decision on the batch.
+	reason string
+}
+
+type handler struct {
+	inner slog.Handler
+	out   *output
+	attrs []slog.Attr
+}
+
+// NewHandler returns a log handler that passes all records to inner and additionally writes each derivation decision
+// to w as a JSON encoded Event per line.
+func NewHandler(inner slog.Handler, w io.Writer) slog.Handler {
+	return &handler{inner: inner, out: &output{enc: json.NewEncoder(w)}}
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo || h.inner.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelInfo {
+		if err := h.record(r); err != nil {
+			return err
+		}
+	}
+	if !h.inner.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *handler) record(r slog.Record) error {
+	attrs := make(map[string]any, len(h.attrs)+r.NumAttrs())
+	add := func(a slog.Attr) bool {
+		v := a.Value.Resolve().Any()
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		attrs[a.Key] = v
+		return true
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(add)
+
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	kind, ok := kinds[r.Message]
+	if !ok {
+		if _, ok := attrs[batchCheckAttr]; ok {
+			h.out.reason = r.Message
+		}
+		return nil
+	}
+	ev := Event{Kind: kind, Msg: r.Message, Attrs: attrs}
+	switch kind {
+	case KindBatchDropped:
+		ev.Reason = h.out.reason
+		h.out.reason = ""
+	case KindBatchAccepted:
+		h.out.reason = ""
+	}
+	return h.out.enc.Encode(ev)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{
+		inner: h.inner.WithAttrs(attrs),
+		out:   h.out,
+		attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{inner: h.inner.WithGroup(name), out: h.out, attrs: h.attrs}
+}
@@ -0,0 +1,103 @@
+package decisionlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("PassesRecordsThrough", func(t *testing.T) {
+		var out bytes.Buffer
+		inner, capt := testlog.CaptureLogger(t, log.LevelDebug)
+		logger := log.NewLogger(NewHandler(inner.Handler(), &out))
+
+		logger.Info("unrelated message", "a", 1)
+		logger.Debug("Found next batch")
+		require.NotNil(t, capt.FindLog(testlog.NewMessageFilter("unrelated message")))
+		require.NotNil(t, capt.FindLog(testlog.NewMessageFilter("Found next batch")))
+		// Neither is a decision at the level it was logged at
+		require.Empty(t, readEvents(t, &out))
+	})
+
+	t.Run("RecordsDecisionsBelowInnerLevel", func(t *testing.T) {
+		var out bytes.Buffer
+		inner := log.NewTerminalHandlerWithLevel(new(bytes.Buffer), log.LevelError, false)
+		logger := log.NewLogger(NewHandler(inner, &out))
+
+		origin := eth.BlockID{Hash: [32]byte{1}, Number: 5}
+		logger.New("stage", "bq").Info("Advancing bq origin", "origin", origin)
+
+		events := readEvents(t, &out)
+		require.Len(t, events, 1)
+		require.Equal(t, KindOriginAdvanced, events[0].Kind)
+		require.Equal(t, "bq", events[0].Attrs["stage"])
+		require.Equal(t, map[string]any{"hash": origin.Hash.String(), "number": float64(5)}, events[0].Attrs["origin"])
+	})
+
+	t.Run("DroppedBatchReason", func(t *testing.T) {
+		var out bytes.Buffer
+		logger := log.NewLogger(NewHandler(log.DiscardHandler(), &out))
+
+		check := logger.New("batch_index", 0, "batch_timestamp", 10)
+		check.Warn("dropping batch with old timestamp", "min_timestamp", 12)
+		logger.New("batch_timestamp", 10).Warn("Dropping batch")
+		check.Info("Found next batch")
+		logger.Warn("Dropping batch")
+
+		events := readEvents(t, &out)
+		require.Len(t, events, 3)
+		require.Equal(t, KindBatchDropped, events[0].Kind)
+		require.Equal(t, "dropping batch with old timestamp", events[0].Reason)
+		require.Equal(t, float64(10), events[0].Attrs["batch_timestamp"])
+		require.Equal(t, KindBatchAccepted, events[1].Kind)
+		require.Empty(t, events[1].Reason)
+		// The reason is not carried over to later batches
+		require.Equal(t, KindBatchDropped, events[2].Kind)
+		require.Empty(t, events[2].Reason)
+	})
+
+	t.Run("Channels", func(t *testing.T) {
+		var out bytes.Buffer
+		logger := log.NewLogger(NewHandler(log.DiscardHandler(), &out))
+
+		logger.Info("created new channel")
+		logger.Warn("failed to ingest frame into channel", "err", errors.New("boom"))
+		logger.Info("Reading channel", "frames", 2)
+		logger.Info("channel timed out")
+
+		events := readEvents(t, &out)
+		require.Len(t, events, 4)
+		require.Equal(t, KindChannelCreated, events[0].Kind)
+		require.Equal(t, KindFrameDropped, events[1].Kind)
+		require.Equal(t, "boom", events[1].Attrs["err"])
+		require.Equal(t, KindChannelAssembled, events[2].Kind)
+		require.Equal(t, KindChannelTimedOut, events[3].Kind)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		h := NewHandler(log.DiscardHandler(), new(bytes.Buffer))
+		require.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+		require.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	})
+}
+
+func readEvents(t *testing.T, r *bytes.Buffer) []Event {
+	var events []Event
+	dec := json.NewDecoder(r)
+	for dec.More() {
+		var ev Event
+		require.NoError(t, dec.Decode(&ev))
+		events = append(events, ev)
+	}
+	return events
+}
@@ -0,0 +1,191 @@
+package interop
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-program/client/boot"
+	interopTypes "github.com/ethereum-optimism/optimism/op-program/client/interop/types"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/source/contracts"
+	backendTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+// EventDecoder decodes executing messages from logs emitted by the CrossL2Inbox.
+type EventDecoder interface {
+	DecodeExecutingMessageLog(log *types.Log) (backendTypes.ExecutingMessage, error)
+}
+
+// depositsOnlyBlock is a block that replaces an optimistic block with invalid executing messages.
+// It keeps the attributes and deposit transactions of the optimistic block and drops all other transactions.
+type depositsOnlyBlock struct {
+	block      *types.Block
+	receipts   types.Receipts
+	outputRoot common.Hash
+}
+
+// consolidate checks the executing messages in the pending blocks of each chain and returns the marshaled super root
+// for the timestamp after super, which commits to the output roots of the pending blocks.
+// Pending blocks with invalid executing messages are replaced by deposits-only blocks, as required by the interop spec.
+func consolidate(logger log.Logger, bootInfo *boot.BootInfoInterop, l1Oracle l1.Oracle, super *eth.SuperV1, pending []interopTypes.OptimisticBlock,
+	oracleFor func(chainID uint64) ChainOracle, executor taskExecutor) ([]byte, error) {
+	if len(pending) != len(super.Chains) {
+		return nil, fmt.Errorf("%w: %v pending blocks for %v chains", ErrInvalidAgreedPrestate, len(pending), len(super.Chains))
+	}
+	checker := &messageChecker{
+		decoder:   contracts.NewCrossL2Inbox(),
+		oracleFor: oracleFor,
+		timestamp: super.Timestamp + 1,
+		pending:   make(map[uint64]common.Hash, len(pending)),
+		replaced:  make(map[common.Hash]*depositsOnlyBlock),
+	}
+	outputs := make([]common.Hash, len(pending))
+	for i, chain := range super.Chains {
+		checker.pending[chain.ChainID] = pending[i].BlockHash
+		outputs[i] = pending[i].OutputRoot
+	}
+	// Replacing a block removes its initiating messages, which may invalidate executing messages in the blocks of
+	// other chains, so the blocks are checked again until no further block is replaced.
+	// Deposits can not execute messages, so deposits-only blocks are not checked again.
+	replacedChains := make(map[uint64]bool)
+	for {
+		replacedAny := false
+		for i, chain := range super.Chains {
+			if replacedChains[chain.ChainID] {
+				continue
+			}
+			blockHash := checker.pending[chain.ChainID]
+			err := checker.checkBlock(chain.ChainID, blockHash)
+			if errors.Is(err, ErrInvalidExecutingMessage) {
+				logger.Warn("Replacing block with deposits-only block", "chainID", chain.ChainID, "block", blockHash, "err", err)
+				replacement, err := buildDepositsOnlyBlock(logger, bootInfo, l1Oracle, oracleFor(chain.ChainID), executor, chain, blockHash)
+				if err != nil {
+					return nil, fmt.Errorf("failed to replace block %v of chain %v: %w", blockHash, chain.ChainID, err)
+				}
+				checker.replaced[replacement.block.Hash()] = replacement
+				checker.pending[chain.ChainID] = replacement.block.Hash()
+				outputs[i] = replacement.outputRoot
+				replacedChains[chain.ChainID] = true
+				replacedAny = true
+			} else if err != nil {
+				return nil, fmt.Errorf("chain %v block %v can not be consolidated: %w", chain.ChainID, blockHash, err)
+			}
+		}
+		if !replacedAny {
+			break
+		}
+	}
+	next := &eth.SuperV1{Timestamp: super.Timestamp + 1}
+	for i, chain := range super.Chains {
+		next.Chains = append(next.Chains, eth.ChainIDAndOutput{ChainID: chain.ChainID, Output: eth.Bytes32(outputs[i])})
+	}
+	logger.Info("Consolidated super root", "timestamp", next.Timestamp, "superRoot", eth.SuperRoot(next), "replacedBlocks", len(replacedChains))
+	return next.Marshal(), nil
+}
+
+// buildDepositsOnlyBlock builds the deposits-only block that replaces the optimistic block of chain,
+// on top of the block committed to by the agreed output of chain.
+func buildDepositsOnlyBlock(logger log.Logger, bootInfo *boot.BootInfoInterop, l1Oracle l1.Oracle, l2Oracle ChainOracle, executor taskExecutor,
+	chain eth.ChainIDAndOutput, optimisticBlockHash common.Hash) (*depositsOnlyBlock, error) {
+	rollupCfg, err := bootInfo.Configs.RollupConfig(chain.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("no rollup config available for chain ID %v: %w", chain.ChainID, err)
+	}
+	l2ChainConfig, err := bootInfo.Configs.ChainConfig(chain.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("no chain config available for chain ID %v: %w", chain.ChainID, err)
+	}
+	optimisticBlock := l2Oracle.BlockByHash(optimisticBlockHash)
+	return executor.BuildDepositsOnlyBlock(logger, rollupCfg, l2ChainConfig, optimisticBlock, common.Hash(chain.Output), l1Oracle, l2Oracle)
+}
+
+type messageChecker struct {
+	decoder   EventDecoder
+	oracleFor func(chainID uint64) ChainOracle
+	// timestamp is the timestamp being consolidated
+	timestamp uint64
+	// pending is the pending block hash of each chain in the dependency set
+	pending map[uint64]common.Hash
+	// replaced holds the deposits-only blocks that replaced invalid pending blocks, by block hash.
+	// They are built during consolidation so are not available from the oracle.
+	replaced map[common.Hash]*depositsOnlyBlock
+}
+
+func (c *messageChecker) blockByHash(chainID uint64, blockHash common.Hash) *types.Block {
+	if replacement, ok := c.replaced[blockHash]; ok {
+		return replacement.block
+	}
+	return c.oracleFor(chainID).BlockByHash(blockHash)
+}
+
+func (c *messageChecker) receiptsByBlockHash(chainID uint64, blockHash common.Hash) (*types.Block, types.Receipts) {
+	if replacement, ok := c.replaced[blockHash]; ok {
+		return replacement.block, replacement.receipts
+	}
+	return c.oracleFor(chainID).ReceiptsByBlockHash(blockHash)
+}
+
+// checkBlock checks that every executing message in the block refers to an existing initiating message.
+// Blocks from before the consolidated timestamp were already checked when their timestamp was consolidated.
+func (c *messageChecker) checkBlock(chainID uint64, blockHash common.Hash) error {
+	block, receipts := c.receiptsByBlockHash(chainID, blockHash)
+	if block.Time() != c.timestamp {
+		return nil
+	}
+	for _, rcpt := range receipts {
+		for _, l := range rcpt.Logs {
+			msg, err := c.decoder.DecodeExecutingMessageLog(l)
+			if errors.Is(err, contracts.ErrEventNotFound) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("%w: log %v: %w", ErrInvalidExecutingMessage, l.Index, err)
+			}
+			if err := c.checkMessage(chainID, block, l.Index, msg); err != nil {
+				return fmt.Errorf("%w: log %v: %w", ErrInvalidExecutingMessage, l.Index, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *messageChecker) checkMessage(execChainID uint64, execBlock *types.Block, execLogIdx uint, msg backendTypes.ExecutingMessage) error {
+	if msg.Timestamp > execBlock.Time() {
+		return fmt.Errorf("initiating message timestamp %v is after executing block timestamp %v", msg.Timestamp, execBlock.Time())
+	}
+	chainID := uint64(msg.Chain)
+	head, ok := c.pending[chainID]
+	if !ok {
+		return fmt.Errorf("initiating chain %v is not in the dependency set", chainID)
+	}
+	if chainID == execChainID && msg.BlockNum == execBlock.NumberU64() && uint64(msg.LogIdx) >= uint64(execLogIdx) {
+		return fmt.Errorf("initiating log %v is not before the executing log %v", msg.LogIdx, execLogIdx)
+	}
+	block := c.blockByHash(chainID, head)
+	for block.NumberU64() > msg.BlockNum {
+		block = c.blockByHash(chainID, block.ParentHash())
+	}
+	if block.NumberU64() != msg.BlockNum {
+		return fmt.Errorf("initiating block %v is after the pending block %v of chain %v", msg.BlockNum, block.NumberU64(), chainID)
+	}
+	if block.Time() != msg.Timestamp {
+		return fmt.Errorf("initiating block %v has timestamp %v, not %v", msg.BlockNum, block.Time(), msg.Timestamp)
+	}
+	_, receipts := c.receiptsByBlockHash(chainID, block.Hash())
+	for _, rcpt := range receipts {
+		for _, l := range rcpt.Logs {
+			if l.Index != uint(msg.LogIdx) {
+				continue
+			}
+			if backendTypes.LogToLogHash(l) != msg.Hash {
+				return fmt.Errorf("initiating log %v in block %v does not match message hash %v", msg.LogIdx, msg.BlockNum, msg.Hash)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("initiating log %v not found in block %v", msg.LogIdx, msg.BlockNum)
+}
@@ -0,0 +1,19 @@
+package interop
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+const (
+	HintAgreedPrestate = "agreed-pre-state"
+)
+
+type AgreedPrestateHint common.Hash
+
+var _ preimage.Hint = AgreedPrestateHint{}
+
+func (l AgreedPrestateHint) Hint() string {
+	return HintAgreedPrestate + " " + (common.Hash)(l).String()
+}
@@ -0,0 +1,234 @@
+package interop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/boot"
+	"github.com/ethereum-optimism/optimism/op-program/client/claim"
+	interopTypes "github.com/ethereum-optimism/optimism/op-program/client/interop/types"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2/engineapi"
+	"github.com/ethereum-optimism/optimism/op-program/client/tasks"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrInvalidAgreedPrestate   = errors.New("invalid agreed prestate")
+	ErrTooManyChains           = errors.New("too many chains to derive before consolidation")
+	ErrDerivationIncomplete    = errors.New("derivation did not reach the target block")
+	ErrInvalidExecutingMessage = errors.New("invalid executing message")
+)
+
+// ChainOracle provides the data of a single chain in the dependency set.
+type ChainOracle interface {
+	l2.Oracle
+	ReceiptsByBlockHash(blockHash common.Hash) (*types.Block, types.Receipts)
+}
+
+// taskExecutor derives the next block of a single chain, and replaces it if it turns out to be invalid.
+type taskExecutor interface {
+	DeriveBlock(logger log.Logger, rollupCfg *rollup.Config, l2ChainConfig *params.ChainConfig, l1Head common.Hash,
+		agreedOutputRoot common.Hash, blockNumber uint64, l1Oracle l1.Oracle, l2Oracle l2.Oracle) (interopTypes.OptimisticBlock, error)
+	BuildDepositsOnlyBlock(logger log.Logger, rollupCfg *rollup.Config, l2ChainConfig *params.ChainConfig, optimisticBlock *types.Block,
+		agreedOutputRoot common.Hash, l1Oracle l1.Oracle, l2Oracle l2.Oracle) (*depositsOnlyBlock, error)
+}
+
+// RunInteropProgram executes a single step of the transition from the agreed super root or transition state
+// and validates that the resulting state matches the claim.
+func RunInteropProgram(logger log.Logger, bootInfo *boot.BootInfoInterop, pClient preimage.Oracle, hClient preimage.Hinter, l1Oracle l1.Oracle) error {
+	logger.Info("Interop program bootstrapped", "bootInfo", bootInfo)
+	hClient.Hint(AgreedPrestateHint(bootInfo.AgreedPrestate))
+	agreedPrestate := pClient.Get(preimage.Keccak256Key(bootInfo.AgreedPrestate))
+
+	oracles := make(map[uint64]ChainOracle)
+	oracleFor := func(chainID uint64) ChainOracle {
+		oracle, ok := oracles[chainID]
+		if !ok {
+			oracle = l2.NewPreimageOracleForChain(pClient, hClient, chainID)
+			oracles[chainID] = oracle
+		}
+		return oracle
+	}
+	expected, err := stateTransition(logger, bootInfo, l1Oracle, oracleFor, &interopTaskExecutor{}, agreedPrestate)
+	if err != nil {
+		return err
+	}
+	actual := crypto.Keccak256Hash(expected)
+	logger.Info("Validating claim", "expected", actual, "claim", bootInfo.Claim)
+	if actual != bootInfo.Claim {
+		return fmt.Errorf("%w: claim: %v actual: %v", claim.ErrClaimNotValid, bootInfo.Claim, actual)
+	}
+	return nil
+}
+
+// stateTransition returns the marshaled state reached by a single step from the agreed prestate.
+// Each step derives the block at the next timestamp for one chain, in the order the chains appear in the super root.
+// The remaining steps until interopTypes.ConsolidateStep are padding, after which the derived blocks are
+// consolidated into the super root for the next timestamp.
+func stateTransition(logger log.Logger, bootInfo *boot.BootInfoInterop, l1Oracle l1.Oracle, oracleFor func(chainID uint64) ChainOracle, executor taskExecutor, agreedPrestate []byte) ([]byte, error) {
+	if bytes.Equal(agreedPrestate, interopTypes.InvalidTransition) {
+		// Once a transition is invalid, all later states are too.
+		return interopTypes.InvalidTransition, nil
+	}
+	state, super, err := parseAgreedState(agreedPrestate)
+	if err != nil {
+		return nil, err
+	}
+	if super.Timestamp >= bootInfo.GameTimestamp {
+		// The claimed timestamp has been reached so the super root remains unchanged.
+		return agreedPrestate, nil
+	}
+	if len(super.Chains) > interopTypes.ConsolidateStep {
+		return nil, fmt.Errorf("%w: %v", ErrTooManyChains, len(super.Chains))
+	}
+
+	if state.Step == interopTypes.ConsolidateStep {
+		logger.Info("Consolidating pending blocks", "timestamp", super.Timestamp+1)
+		return consolidate(logger, bootInfo, l1Oracle, super, state.PendingProgress, oracleFor, executor)
+	}
+	next := &interopTypes.TransitionState{
+		SuperRoot:       state.SuperRoot,
+		PendingProgress: state.PendingProgress,
+		Step:            state.Step + 1,
+	}
+	if state.Step < uint64(len(super.Chains)) {
+		chain := super.Chains[state.Step]
+		logger.Info("Deriving optimistic block", "chainID", chain.ChainID, "timestamp", super.Timestamp+1)
+		block, err := deriveOptimisticBlock(logger, bootInfo, l1Oracle, oracleFor(chain.ChainID), executor, chain, super.Timestamp+1)
+		if errors.Is(err, ErrDerivationIncomplete) {
+			logger.Warn("Transition is invalid, L1 data was exhausted before reaching the target block", "err", err)
+			return interopTypes.InvalidTransition, nil
+		} else if err != nil {
+			return nil, err
+		}
+		next.PendingProgress = append(next.PendingProgress, block)
+	}
+	return next.Marshal(), nil
+}
+
+func parseAgreedState(data []byte) (*interopTypes.TransitionState, *eth.SuperV1, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("%w: empty", ErrInvalidAgreedPrestate)
+	}
+	state := &interopTypes.TransitionState{SuperRoot: data}
+	if data[0] == interopTypes.TransitionStateVersion {
+		var err error
+		state, err = interopTypes.UnmarshalTransitionState(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidAgreedPrestate, err)
+		}
+	}
+	super, err := eth.UnmarshalSuperRoot(state.SuperRoot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidAgreedPrestate, err)
+	}
+	superV1, ok := super.(*eth.SuperV1)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unsupported super root version %v", ErrInvalidAgreedPrestate, super.Version())
+	}
+	if uint64(len(state.PendingProgress)) != min(state.Step, uint64(len(superV1.Chains))) {
+		return nil, nil, fmt.Errorf("%w: %v pending blocks at step %v", ErrInvalidAgreedPrestate, len(state.PendingProgress), state.Step)
+	}
+	return state, superV1, nil
+}
+
+// deriveOptimisticBlock derives the block of chain at timestamp from its output in the agreed super root.
+// Chains with a block time greater than one second may not have a block at timestamp, in which case the agreed
+// block is used.
+func deriveOptimisticBlock(logger log.Logger, bootInfo *boot.BootInfoInterop, l1Oracle l1.Oracle, l2Oracle l2.Oracle, executor taskExecutor, chain eth.ChainIDAndOutput, timestamp uint64) (interopTypes.OptimisticBlock, error) {
+	rollupCfg, err := bootInfo.Configs.RollupConfig(chain.ChainID)
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("no rollup config available for chain ID %v: %w", chain.ChainID, err)
+	}
+	l2ChainConfig, err := bootInfo.Configs.ChainConfig(chain.ChainID)
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("no chain config available for chain ID %v: %w", chain.ChainID, err)
+	}
+	blockNumber, err := rollupCfg.TargetBlockNumber(timestamp)
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("no block at timestamp %v for chain ID %v: %w", timestamp, chain.ChainID, err)
+	}
+	agreedOutput, ok := l2Oracle.OutputByRoot(common.Hash(chain.Output)).(*eth.OutputV0)
+	if !ok {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("unsupported output version for chain ID %v", chain.ChainID)
+	}
+	if agreedBlock := l2Oracle.BlockByHash(agreedOutput.BlockHash); agreedBlock.NumberU64() >= blockNumber {
+		return interopTypes.OptimisticBlock{BlockHash: agreedOutput.BlockHash, OutputRoot: common.Hash(chain.Output)}, nil
+	}
+	return executor.DeriveBlock(logger, rollupCfg, l2ChainConfig, bootInfo.L1Head, common.Hash(chain.Output), blockNumber, l1Oracle, l2Oracle)
+}
+
+type interopTaskExecutor struct{}
+
+func (t *interopTaskExecutor) DeriveBlock(logger log.Logger, rollupCfg *rollup.Config, l2ChainConfig *params.ChainConfig, l1Head common.Hash,
+	agreedOutputRoot common.Hash, blockNumber uint64, l1Oracle l1.Oracle, l2Oracle l2.Oracle) (interopTypes.OptimisticBlock, error) {
+	l2Source, err := tasks.RunDerivation(logger, rollupCfg, l2ChainConfig, l1Head, agreedOutputRoot, blockNumber, l1Oracle, l2.NewCachingOracle(l2Oracle))
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, err
+	}
+	head, err := l2Source.L2BlockRefByLabel(context.Background(), eth.Safe)
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("cannot retrieve safe head: %w", err)
+	}
+	if head.Number < blockNumber {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("%w: safe head %v, target %v", ErrDerivationIncomplete, head, blockNumber)
+	}
+	block, err := l2Source.L2BlockRefByNumber(context.Background(), blockNumber)
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("cannot retrieve derived block %v: %w", blockNumber, err)
+	}
+	outputRoot, err := l2Source.L2OutputRoot(blockNumber)
+	if err != nil {
+		return interopTypes.OptimisticBlock{}, fmt.Errorf("calculate L2 output root: %w", err)
+	}
+	return interopTypes.OptimisticBlock{BlockHash: block.Hash, OutputRoot: common.Hash(outputRoot)}, nil
+}
+
+// BuildDepositsOnlyBlock builds a block with the attributes and deposit transactions of optimisticBlock
+// on top of the block committed to by agreedOutputRoot.
+func (t *interopTaskExecutor) BuildDepositsOnlyBlock(logger log.Logger, rollupCfg *rollup.Config, l2ChainConfig *params.ChainConfig, optimisticBlock *types.Block,
+	agreedOutputRoot common.Hash, l1Oracle l1.Oracle, l2Oracle l2.Oracle) (*depositsOnlyBlock, error) {
+	engineBackend, err := l2.NewOracleBackedL2Chain(logger, l2Oracle, l1Oracle /* kzg oracle */, l2ChainConfig, agreedOutputRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oracle-backed L2 chain: %w", err)
+	}
+	processor, err := engineapi.NewBlockProcessorFromHeader(engineBackend, optimisticBlock.Header())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block processor: %w", err)
+	}
+	for i, tx := range optimisticBlock.Transactions() {
+		if !tx.IsDepositTx() {
+			continue
+		}
+		if err := processor.AddTx(tx); err != nil {
+			return nil, fmt.Errorf("invalid deposit transaction (%d): %w", i, err)
+		}
+	}
+	block, err := processor.Assemble()
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble deposits-only block: %w", err)
+	}
+	if err := engineBackend.InsertBlockWithoutSetHead(block); err != nil {
+		return nil, fmt.Errorf("failed to insert deposits-only block: %w", err)
+	}
+	if _, err := engineBackend.SetCanonical(block); err != nil {
+		return nil, fmt.Errorf("failed to set deposits-only block as head: %w", err)
+	}
+	outputRoot, err := l2.NewOracleEngine(rollupCfg, logger, engineBackend).L2OutputRoot(block.NumberU64())
+	if err != nil {
+		return nil, fmt.Errorf("calculate L2 output root: %w", err)
+	}
+	return &depositsOnlyBlock{block: block, receipts: processor.Receipts(), outputRoot: common.Hash(outputRoot)}, nil
+}
@@ -0,0 +1,469 @@
+package interop
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum-optimism/optimism/op-program/client/boot"
+	interopTypes "github.com/ethereum-optimism/optimism/op-program/client/interop/types"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2/test"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/packages/contracts-bedrock/snapshots"
+)
+
+const agreedTimestamp = 100
+
+func TestStateTransition(t *testing.T) {
+	t.Run("DeriveFirstChain", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		expected := s.pendingBlock(0)
+		s.executor.expect(s.chains[0], expected)
+
+		next, err := s.run(s.agreedSuper().Marshal())
+		require.NoError(t, err)
+		requireTransitionState(t, &interopTypes.TransitionState{
+			SuperRoot:       s.agreedSuper().Marshal(),
+			PendingProgress: []interopTypes.OptimisticBlock{expected},
+			Step:            1,
+		}, next)
+	})
+
+	t.Run("DeriveSecondChain", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		first := s.pendingBlock(0)
+		second := s.pendingBlock(1)
+		s.executor.expect(s.chains[1], second)
+
+		agreed := &interopTypes.TransitionState{
+			SuperRoot:       s.agreedSuper().Marshal(),
+			PendingProgress: []interopTypes.OptimisticBlock{first},
+			Step:            1,
+		}
+		next, err := s.run(agreed.Marshal())
+		require.NoError(t, err)
+		requireTransitionState(t, &interopTypes.TransitionState{
+			SuperRoot:       s.agreedSuper().Marshal(),
+			PendingProgress: []interopTypes.OptimisticBlock{first, second},
+			Step:            2,
+		}, next)
+	})
+
+	t.Run("Padding", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		pending := []interopTypes.OptimisticBlock{s.pendingBlock(0), s.pendingBlock(1)}
+		agreed := &interopTypes.TransitionState{
+			SuperRoot:       s.agreedSuper().Marshal(),
+			PendingProgress: pending,
+			Step:            500,
+		}
+		next, err := s.run(agreed.Marshal())
+		require.NoError(t, err)
+		requireTransitionState(t, &interopTypes.TransitionState{
+			SuperRoot:       s.agreedSuper().Marshal(),
+			PendingProgress: pending,
+			Step:            501,
+		}, next)
+	})
+
+	t.Run("ReuseAgreedBlockWhenNoBlockAtTimestamp", func(t *testing.T) {
+		s := newTestSetup(t, 2, 1)
+		next, err := s.run(s.agreedSuper().Marshal())
+		require.NoError(t, err)
+		requireTransitionState(t, &interopTypes.TransitionState{
+			SuperRoot: s.agreedSuper().Marshal(),
+			PendingProgress: []interopTypes.OptimisticBlock{{
+				BlockHash:  s.chains[0].agreedBlock.Hash(),
+				OutputRoot: common.Hash(eth.OutputRoot(s.chains[0].agreedOutput)),
+			}},
+			Step: 1,
+		}, next)
+	})
+
+	t.Run("DerivationIncomplete", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		s.executor.err = fmt.Errorf("%w: ran out of data", ErrDerivationIncomplete)
+		next, err := s.run(s.agreedSuper().Marshal())
+		require.NoError(t, err)
+		require.Equal(t, interopTypes.InvalidTransition, next)
+	})
+
+	t.Run("DerivationFailed", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		s.executor.err = errors.New("boom")
+		_, err := s.run(s.agreedSuper().Marshal())
+		require.ErrorIs(t, err, s.executor.err)
+	})
+
+	t.Run("InvalidTransitionRemainsInvalid", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		next, err := s.run(interopTypes.InvalidTransition)
+		require.NoError(t, err)
+		require.Equal(t, interopTypes.InvalidTransition, next)
+	})
+
+	t.Run("GameTimestampReached", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		s.bootInfo.GameTimestamp = agreedTimestamp
+		agreed := s.agreedSuper().Marshal()
+		next, err := s.run(agreed)
+		require.NoError(t, err)
+		require.Equal(t, agreed, next)
+	})
+
+	t.Run("InvalidAgreedPrestate", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		_, err := s.run([]byte{0xaa})
+		require.ErrorIs(t, err, ErrInvalidAgreedPrestate)
+	})
+
+	t.Run("PendingProgressDoesNotMatchStep", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		agreed := &interopTypes.TransitionState{
+			SuperRoot: s.agreedSuper().Marshal(),
+			Step:      1,
+		}
+		_, err := s.run(agreed.Marshal())
+		require.ErrorIs(t, err, ErrInvalidAgreedPrestate)
+	})
+}
+
+func TestConsolidate(t *testing.T) {
+	consolidateState := func(s *testSetup) []byte {
+		return (&interopTypes.TransitionState{
+			SuperRoot:       s.agreedSuper().Marshal(),
+			PendingProgress: []interopTypes.OptimisticBlock{s.pendingBlock(0), s.pendingBlock(1)},
+			Step:            interopTypes.ConsolidateStep,
+		}).Marshal()
+	}
+	expectedSuper := func(s *testSetup, outputs ...common.Hash) []byte {
+		if len(outputs) == 0 {
+			outputs = []common.Hash{s.pendingBlock(0).OutputRoot, s.pendingBlock(1).OutputRoot}
+		}
+		return (&eth.SuperV1{
+			Timestamp: agreedTimestamp + 1,
+			Chains: []eth.ChainIDAndOutput{
+				{ChainID: s.chains[0].chainID, Output: eth.Bytes32(outputs[0])},
+				{ChainID: s.chains[1].chainID, Output: eth.Bytes32(outputs[1])},
+			},
+		}).Marshal()
+	}
+
+	t.Run("NoMessages", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		next, err := s.run(consolidateState(s))
+		require.NoError(t, err)
+		require.Equal(t, expectedSuper(s), next)
+		require.Empty(t, s.executor.replaced)
+	})
+
+	t.Run("ValidExecutingMessage", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		initLog := s.chains[0].addLog(&types.Log{Address: common.Address{0xaa}, Topics: []common.Hash{{0x01}}, Data: []byte{1, 2, 3}})
+		s.chains[1].addLog(executingMessageLog(t, initLog, s.chains[0].chainID, agreedTimestamp+1))
+
+		next, err := s.run(consolidateState(s))
+		require.NoError(t, err)
+		require.Equal(t, expectedSuper(s), next)
+	})
+
+	t.Run("ValidExecutingMessageFromAgreedBlock", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		initLog := &types.Log{Address: common.Address{0xaa}, Topics: []common.Hash{{0x01}}, Data: []byte{1, 2, 3}}
+		s.chains[0].oracle.Receipts[s.chains[0].agreedBlock.Hash()] = types.Receipts{{Logs: []*types.Log{initLog}}}
+		s.chains[1].addLog(executingMessageLog(t, initLog, s.chains[0].chainID, agreedTimestamp))
+
+		next, err := s.run(consolidateState(s))
+		require.NoError(t, err)
+		require.Equal(t, expectedSuper(s), next)
+	})
+
+	invalidMessages := []struct {
+		name  string
+		setup func(s *testSetup)
+	}{
+		{"InitiatingMessageDoesNotMatch", func(s *testSetup) {
+			initLog := s.chains[0].addLog(&types.Log{Address: common.Address{0xaa}, Topics: []common.Hash{{0x01}}, Data: []byte{1, 2, 3}})
+			execLog := executingMessageLog(t, &types.Log{Address: initLog.Address, Topics: initLog.Topics, Data: []byte{4}, Index: initLog.Index},
+				s.chains[0].chainID, agreedTimestamp+1)
+			s.chains[1].addLog(execLog)
+		}},
+		{"InitiatingMessageAfterExecutingMessage", func(s *testSetup) {
+			execLog := executingMessageLog(t, &types.Log{Address: common.Address{0xaa}, Index: 1}, s.chains[1].chainID, agreedTimestamp+1)
+			s.chains[1].addLog(execLog)
+			s.chains[1].addLog(&types.Log{Address: common.Address{0xaa}})
+		}},
+		{"InitiatingMessageFromFuture", func(s *testSetup) {
+			initLog := s.chains[0].addLog(&types.Log{Address: common.Address{0xaa}})
+			s.chains[1].addLog(executingMessageLog(t, initLog, s.chains[0].chainID, agreedTimestamp+2))
+		}},
+		{"UnknownChain", func(s *testSetup) {
+			initLog := s.chains[0].addLog(&types.Log{Address: common.Address{0xaa}})
+			s.chains[1].addLog(executingMessageLog(t, initLog, 999, agreedTimestamp+1))
+		}},
+	}
+	for _, test := range invalidMessages {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestSetup(t, 1, 1)
+			test.setup(s)
+
+			next, err := s.run(consolidateState(s))
+			require.NoError(t, err)
+			require.Equal(t, expectedSuper(s, s.pendingBlock(0).OutputRoot, s.executor.replacementOutput(s.chains[1])), next)
+			require.Len(t, s.executor.replaced, 1)
+		})
+	}
+
+	t.Run("ReplacedBlockInvalidatesDependentBlock", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		// The block of chain 0 executes a message from the block of chain 1, which is valid until the block of
+		// chain 1 is replaced because of its own invalid executing message.
+		initLog := s.chains[1].addLog(&types.Log{Address: common.Address{0xaa}})
+		s.chains[0].addLog(executingMessageLog(t, initLog, s.chains[1].chainID, agreedTimestamp+1))
+		s.chains[1].addLog(executingMessageLog(t, initLog, 999, agreedTimestamp+1))
+
+		next, err := s.run(consolidateState(s))
+		require.NoError(t, err)
+		require.Equal(t, expectedSuper(s, s.executor.replacementOutput(s.chains[0]), s.executor.replacementOutput(s.chains[1])), next)
+		require.Len(t, s.executor.replaced, 2)
+	})
+
+	t.Run("ReplacementFailed", func(t *testing.T) {
+		s := newTestSetup(t, 1, 1)
+		initLog := s.chains[0].addLog(&types.Log{Address: common.Address{0xaa}})
+		s.chains[1].addLog(executingMessageLog(t, initLog, 999, agreedTimestamp+1))
+		s.executor.err = errors.New("boom")
+
+		_, err := s.run(consolidateState(s))
+		require.ErrorIs(t, err, s.executor.err)
+	})
+}
+
+func requireTransitionState(t *testing.T, expected *interopTypes.TransitionState, actual []byte) {
+	state, err := interopTypes.UnmarshalTransitionState(actual)
+	require.NoError(t, err)
+	require.Equal(t, expected.Marshal(), state.Marshal())
+}
+
+// executingMessageLog creates a CrossL2Inbox ExecutingMessage log referencing initLog.
+func executingMessageLog(t *testing.T, initLog *types.Log, chainID uint64, timestamp uint64) *types.Log {
+	payload := make([]byte, 0)
+	for _, topic := range initLog.Topics {
+		payload = append(payload, topic.Bytes()...)
+	}
+	payload = append(payload, initLog.Data...)
+	payloadHash := crypto.Keccak256Hash(payload)
+	// Test chains have a block time of 1 and genesis at timestamp 0 so the block number matches the timestamp.
+	blockNumber := timestamp
+	identifier := struct {
+		Origin      common.Address
+		BlockNumber *big.Int
+		LogIndex    *big.Int
+		Timestamp   *big.Int
+		ChainId     *big.Int
+	}{
+		Origin:      initLog.Address,
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+		LogIndex:    new(big.Int).SetUint64(uint64(initLog.Index)),
+		Timestamp:   new(big.Int).SetUint64(timestamp),
+		ChainId:     new(big.Int).SetUint64(chainID),
+	}
+	abi := snapshots.LoadCrossL2InboxABI()
+	event := abi.Events["ExecutingMessage"]
+	data, err := event.Inputs.Pack(payloadHash, identifier)
+	require.NoError(t, err)
+	return &types.Log{
+		Address: predeploys.CrossL2InboxAddr,
+		Topics:  []common.Hash{event.ID, payloadHash},
+		Data:    data,
+	}
+}
+
+type testChain struct {
+	chainID      uint64
+	oracle       *test.StubBlockOracle
+	agreedBlock  *types.Block
+	agreedOutput eth.Output
+	pendingBlock *types.Block
+}
+
+// addLog adds l to the receipts of the pending block.
+func (c *testChain) addLog(l *types.Log) *types.Log {
+	hash := c.pendingBlock.Hash()
+	l.Index = uint(len(c.oracle.Receipts[hash]))
+	c.oracle.Receipts[hash] = append(c.oracle.Receipts[hash], &types.Receipt{Logs: []*types.Log{l}})
+	return l
+}
+
+type testSetup struct {
+	t        *testing.T
+	chains   []*testChain
+	bootInfo *boot.BootInfoInterop
+	executor *stubTaskExecutor
+}
+
+// newTestSetup creates two chains with the given block times, with genesis at timestamp 0.
+func newTestSetup(t *testing.T, blockTimeA uint64, blockTimeB uint64) *testSetup {
+	configs := &stubConfigSource{rollupCfgs: make(map[uint64]*rollup.Config)}
+	s := &testSetup{
+		t: t,
+		bootInfo: &boot.BootInfoInterop{
+			Configs:       configs,
+			L1Head:        common.Hash{0x11},
+			GameTimestamp: agreedTimestamp + 10,
+		},
+		executor: &stubTaskExecutor{t: t},
+	}
+	for i, blockTime := range []uint64{blockTimeA, blockTimeB} {
+		chainID := uint64(900 + i)
+		rollupCfg := &rollup.Config{
+			BlockTime: blockTime,
+			L2ChainID: new(big.Int).SetUint64(chainID),
+		}
+		configs.rollupCfgs[chainID] = rollupCfg
+		oracle, _ := test.NewStubOracle(t)
+		agreedNum, err := rollupCfg.TargetBlockNumber(agreedTimestamp)
+		require.NoError(t, err)
+		agreedBlock := types.NewBlockWithHeader(&types.Header{
+			Number: new(big.Int).SetUint64(agreedNum),
+			Time:   agreedTimestamp,
+			Extra:  []byte{byte(i)},
+		})
+		agreedOutput := &eth.OutputV0{BlockHash: agreedBlock.Hash(), StateRoot: eth.Bytes32{byte(i)}}
+		pendingBlock := types.NewBlockWithHeader(&types.Header{
+			ParentHash:  agreedBlock.Hash(),
+			Number:      new(big.Int).SetUint64(agreedNum + 1),
+			Time:        agreedTimestamp + 1,
+			Extra:       []byte{byte(i)},
+			ReceiptHash: types.EmptyReceiptsHash,
+		})
+		oracle.Blocks[agreedBlock.Hash()] = agreedBlock
+		oracle.Blocks[pendingBlock.Hash()] = pendingBlock
+		oracle.Receipts[pendingBlock.Hash()] = types.Receipts{}
+		oracle.Outputs[common.Hash(eth.OutputRoot(agreedOutput))] = agreedOutput
+		s.chains = append(s.chains, &testChain{
+			chainID:      chainID,
+			oracle:       oracle,
+			agreedBlock:  agreedBlock,
+			agreedOutput: agreedOutput,
+			pendingBlock: pendingBlock,
+		})
+	}
+	return s
+}
+
+func (s *testSetup) agreedSuper() *eth.SuperV1 {
+	super := &eth.SuperV1{Timestamp: agreedTimestamp}
+	for _, chain := range s.chains {
+		super.Chains = append(super.Chains, eth.ChainIDAndOutput{ChainID: chain.chainID, Output: eth.OutputRoot(chain.agreedOutput)})
+	}
+	return super
+}
+
+func (s *testSetup) pendingBlock(i int) interopTypes.OptimisticBlock {
+	return interopTypes.OptimisticBlock{
+		BlockHash:  s.chains[i].pendingBlock.Hash(),
+		OutputRoot: common.Hash{0xbb, byte(i)},
+	}
+}
+
+func (s *testSetup) run(agreedPrestate []byte) ([]byte, error) {
+	logger := testlog.Logger(s.t, log.LevelInfo)
+	oracleFor := func(chainID uint64) ChainOracle {
+		for _, chain := range s.chains {
+			if chain.chainID == chainID {
+				return chain.oracle
+			}
+		}
+		s.t.Fatalf("unexpected request for oracle for chain %v", chainID)
+		return nil
+	}
+	return stateTransition(logger, s.bootInfo, nil, oracleFor, s.executor, agreedPrestate)
+}
+
+type stubConfigSource struct {
+	rollupCfgs map[uint64]*rollup.Config
+}
+
+func (s *stubConfigSource) RollupConfig(chainID uint64) (*rollup.Config, error) {
+	cfg, ok := s.rollupCfgs[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", boot.ErrUnknownChainID, chainID)
+	}
+	return cfg, nil
+}
+
+func (s *stubConfigSource) ChainConfig(chainID uint64) (*params.ChainConfig, error) {
+	return chainconfig.OPSepoliaChainConfig, nil
+}
+
+type stubTaskExecutor struct {
+	t        *testing.T
+	expected map[common.Hash]interopTypes.OptimisticBlock
+	replaced map[common.Hash]*depositsOnlyBlock
+	err      error
+}
+
+// expect configures the block returned when deriving from the agreed output of chain.
+func (e *stubTaskExecutor) expect(chain *testChain, block interopTypes.OptimisticBlock) {
+	if e.expected == nil {
+		e.expected = make(map[common.Hash]interopTypes.OptimisticBlock)
+	}
+	e.expected[common.Hash(eth.OutputRoot(chain.agreedOutput))] = block
+}
+
+func (e *stubTaskExecutor) DeriveBlock(_ log.Logger, _ *rollup.Config, _ *params.ChainConfig, _ common.Hash,
+	agreedOutputRoot common.Hash, _ uint64, _ l1.Oracle, _ l2.Oracle) (interopTypes.OptimisticBlock, error) {
+	if e.err != nil {
+		return interopTypes.OptimisticBlock{}, e.err
+	}
+	block, ok := e.expected[agreedOutputRoot]
+	require.Truef(e.t, ok, "unexpected derivation from output root %v", agreedOutputRoot)
+	return block, nil
+}
+
+// BuildDepositsOnlyBlock replaces the optimistic block with a block that only differs in its extra data,
+// as the optimistic blocks of the tests have no transactions.
+func (e *stubTaskExecutor) BuildDepositsOnlyBlock(_ log.Logger, _ *rollup.Config, _ *params.ChainConfig, optimisticBlock *types.Block,
+	agreedOutputRoot common.Hash, _ l1.Oracle, _ l2.Oracle) (*depositsOnlyBlock, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.replaced == nil {
+		e.replaced = make(map[common.Hash]*depositsOnlyBlock)
+	}
+	require.NotContainsf(e.t, e.replaced, agreedOutputRoot, "block built on output root %v replaced twice", agreedOutputRoot)
+	header := types.CopyHeader(optimisticBlock.Header())
+	header.Extra = append(header.Extra, 0xdd)
+	replacement := &depositsOnlyBlock{
+		block:      types.NewBlockWithHeader(header),
+		receipts:   types.Receipts{},
+		outputRoot: e.replacementOutputRoot(agreedOutputRoot),
+	}
+	e.replaced[agreedOutputRoot] = replacement
+	return replacement, nil
+}
+
+// replacementOutput returns the output root of the deposits-only block that replaces the pending block of chain.
+func (e *stubTaskExecutor) replacementOutput(chain *testChain) common.Hash {
+	return e.replacementOutputRoot(common.Hash(eth.OutputRoot(chain.agreedOutput)))
+}
+
+func (e *stubTaskExecutor) replacementOutputRoot(agreedOutputRoot common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("deposits-only"), agreedOutputRoot.Bytes())
+}
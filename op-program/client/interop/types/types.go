@@ -0,0 +1,78 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// TransitionStateVersion is the version byte prefixed to marshaled transition states.
+	// It is distinct from any super root version so the two can't be confused.
+	TransitionStateVersion = byte(255)
+
+	// StepsPerTimestamp is the number of steps used to transition from one super root to the next.
+	StepsPerTimestamp = 1024
+	// ConsolidateStep is the step that consolidates the pending blocks of all chains into the next super root.
+	ConsolidateStep = StepsPerTimestamp - 1
+)
+
+var (
+	ErrInvalidTransitionState        = errors.New("invalid transition state")
+	ErrInvalidTransitionStateVersion = errors.New("invalid transition state version")
+
+	// InvalidTransition is the preimage of the claim used for states that can't be reached from the game's L1 head.
+	InvalidTransition     = []byte("invalid")
+	InvalidTransitionHash = crypto.Keccak256Hash(InvalidTransition)
+)
+
+// OptimisticBlock is a block that has been derived for a chain but not yet cross-safe.
+type OptimisticBlock struct {
+	BlockHash  common.Hash
+	OutputRoot common.Hash
+}
+
+// TransitionState is an intermediate state between two super roots.
+// Each step derives the optimistic block for the next chain in the super root, after which the remaining steps
+// until the next timestamp are padding before the blocks are consolidated into the next super root.
+type TransitionState struct {
+	SuperRoot       []byte
+	PendingProgress []OptimisticBlock
+	Step            uint64
+}
+
+func (t *TransitionState) Version() byte {
+	return TransitionStateVersion
+}
+
+func (t *TransitionState) Marshal() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(t.Version())
+	// Encoding only fails for unsupported types which can't occur here.
+	if err := rlp.Encode(&buf, t); err != nil {
+		panic(fmt.Errorf("failed to encode transition state: %w", err))
+	}
+	return buf.Bytes()
+}
+
+func (t *TransitionState) Hash() common.Hash {
+	return crypto.Keccak256Hash(t.Marshal())
+}
+
+func UnmarshalTransitionState(data []byte) (*TransitionState, error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidTransitionState
+	}
+	if data[0] != TransitionStateVersion {
+		return nil, ErrInvalidTransitionStateVersion
+	}
+	var state TransitionState
+	if err := rlp.DecodeBytes(data[1:], &state); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransitionState, err)
+	}
+	return &state, nil
+}
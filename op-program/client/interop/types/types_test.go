@@ -0,0 +1,26 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitionStateRoundTrip(t *testing.T) {
+	state := &TransitionState{
+		SuperRoot:       []byte{0x01, 0x02, 0x03},
+		PendingProgress: []OptimisticBlock{{BlockHash: common.Hash{0x11}, OutputRoot: common.Hash{0x22}}},
+		Step:            3,
+	}
+	data := state.Marshal()
+	require.Equal(t, TransitionStateVersion, data[0])
+	actual, err := UnmarshalTransitionState(data)
+	require.NoError(t, err)
+	require.Equal(t, state, actual)
+
+	_, err = UnmarshalTransitionState([]byte{0x01})
+	require.ErrorIs(t, err, ErrInvalidTransitionStateVersion)
+	_, err = UnmarshalTransitionState(nil)
+	require.ErrorIs(t, err, ErrInvalidTransitionState)
+}
@@ -0,0 +1,39 @@
+package l1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
+)
+
+var ErrUnsupportedAltDACommitment = errors.New("unsupported alt-DA commitment type")
+
+// AltDAStorage implements altda.DAStorage by retrieving inputs from the pre-image oracle.
+// Only keccak256 commitments are supported, as the input for a generic commitment can't be verified.
+type AltDAStorage struct {
+	oracle Oracle
+}
+
+var _ altda.DAStorage = (*AltDAStorage)(nil)
+
+func NewAltDAStorage(oracle Oracle) *AltDAStorage {
+	return &AltDAStorage{oracle: oracle}
+}
+
+func (s *AltDAStorage) GetInput(_ context.Context, comm altda.CommitmentData) ([]byte, error) {
+	keccakComm, ok := comm.(altda.Keccak256Commitment)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedAltDACommitment, comm.CommitmentType())
+	}
+	input, ok := s.oracle.GetAltDAInput(keccakComm)
+	if !ok {
+		return nil, altda.ErrNotFound
+	}
+	return input, nil
+}
+
+func (s *AltDAStorage) SetInput(_ context.Context, _ []byte) (altda.CommitmentData, error) {
+	return nil, errors.New("alt-DA storage is read-only")
+}
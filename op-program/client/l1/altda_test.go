@@ -0,0 +1,61 @@
+package l1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+func TestAltDAStorage(t *testing.T) {
+	input := []byte("alt-DA input data")
+	comm := altda.NewKeccak256Commitment(input)
+
+	availabilityKey := preimage.GlobalGenericKey(crypto.Keccak256Hash(comm.Encode())).PreimageKey()
+	available := true
+
+	var hints mock.Mock
+	oracle := NewPreimageOracle(
+		preimage.OracleFn(func(key preimage.Key) []byte {
+			if key.PreimageKey() == availabilityKey {
+				if available {
+					return []byte{1}
+				}
+				return []byte{0}
+			}
+			require.True(t, available, "requested input of unavailable commitment")
+			require.Equal(t, preimage.Keccak256Key(comm).PreimageKey(), key.PreimageKey())
+			return input
+		}),
+		preimage.HinterFn(func(v preimage.Hint) {
+			hints.MethodCalled("hint", v.Hint())
+		}))
+	storage := NewAltDAStorage(oracle)
+
+	t.Run("Keccak256", func(t *testing.T) {
+		hints.On("hint", AltDAInputHint(comm.Encode()).Hint()).Once().Return()
+		result, err := storage.GetInput(context.Background(), comm)
+		require.NoError(t, err)
+		require.Equal(t, input, result)
+		hints.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		available = false
+		t.Cleanup(func() { available = true })
+		hints.On("hint", AltDAInputHint(comm.Encode()).Hint()).Once().Return()
+		_, err := storage.GetInput(context.Background(), comm)
+		require.ErrorIs(t, err, altda.ErrNotFound)
+		hints.AssertExpectations(t)
+	})
+
+	t.Run("GenericUnsupported", func(t *testing.T) {
+		_, err := storage.GetInput(context.Background(), altda.NewGenericCommitment([]byte{1, 2, 3}))
+		require.ErrorIs(t, err, ErrUnsupportedAltDACommitment)
+	})
+}
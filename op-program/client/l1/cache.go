@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
@@ -104,3 +105,8 @@ func (o *CachingOracle) Precompile(address common.Address, input []byte, require
 	o.pcmps.Add(cacheKey, precompileResult{res, ok})
 	return res, ok
 }
+
+// GetAltDAInput is not cached as the alt-DA manager only requests each input once.
+func (o *CachingOracle) GetAltDAInput(commitment altda.Keccak256Commitment) ([]byte, bool) {
+	return o.oracle.GetAltDAInput(commitment)
+}
@@ -14,6 +14,7 @@ const (
 	HintL1Blob         = "l1-blob"
 	HintL1Precompile   = "l1-precompile"
 	HintL1PrecompileV2 = "l1-precompile-v2"
+	HintAltDAInput     = "altda-input"
 )
 
 type BlockHeaderHint common.Hash
@@ -63,3 +64,12 @@ var _ preimage.Hint = PrecompileHintV2{}
 func (l PrecompileHintV2) Hint() string {
 	return HintL1PrecompileV2 + " " + hexutil.Encode(l)
 }
+
+// AltDAInputHint requests the input data for an encoded alt-DA commitment.
+type AltDAInputHint []byte
+
+var _ preimage.Hint = AltDAInputHint{}
+
+func (l AltDAInputHint) Hint() string {
+	return HintAltDAInput + " " + hexutil.Encode(l)
+}
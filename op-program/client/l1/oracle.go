@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -30,6 +31,10 @@ type Oracle interface {
 
 	// Precompile retrieves the result and success indicator of a precompile call for the given input.
 	Precompile(precompileAddress common.Address, input []byte, requiredGas uint64) ([]byte, bool)
+
+	// GetAltDAInput retrieves the input data committed to by the given keccak256 alt-DA commitment.
+	// Returns false if the alt-DA server did not have the input.
+	GetAltDAInput(commitment altda.Keccak256Commitment) ([]byte, bool)
 }
 
 // PreimageOracle implements Oracle using by interfacing with the pure preimage.Oracle
@@ -130,3 +135,17 @@ func (p *PreimageOracle) Precompile(address common.Address, input []byte, requir
 	}
 	return result[1:], result[0] == 1
 }
+
+func (p *PreimageOracle) GetAltDAInput(commitment altda.Keccak256Commitment) ([]byte, bool) {
+	p.hint.Hint(AltDAInputHint(commitment.Encode()))
+	// The availability of the input is served by the host, as unavailable data has no pre-image.
+	status := p.oracle.Get(preimage.GlobalGenericKey(crypto.Keccak256Hash(commitment.Encode())))
+	if len(status) != 1 {
+		panic(fmt.Errorf("unexpected alt-DA availability oracle behavior, got result: %x", status))
+	}
+	if status[0] != 1 {
+		return nil, false
+	}
+	// The commitment is the keccak256 hash of the input so the input is verified by the pre-image key.
+	return p.oracle.Get(preimage.Keccak256Key(common.BytesToHash(commitment))), true
+}
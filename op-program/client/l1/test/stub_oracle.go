@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -27,6 +28,9 @@ type StubOracle struct {
 
 	// PcmpResults maps hashed input to the results of precompile calls
 	PcmpResults map[common.Hash][]byte
+
+	// AltDAInputs maps keccak256 alt-DA commitments to their input data. Missing commitments are unavailable.
+	AltDAInputs map[common.Hash][]byte
 }
 
 func NewStubOracle(t *testing.T) *StubOracle {
@@ -37,6 +41,7 @@ func NewStubOracle(t *testing.T) *StubOracle {
 		Rcpts:       make(map[common.Hash]types.Receipts),
 		Blobs:       make(map[eth.L1BlockRef]map[eth.IndexedBlobHash]*eth.Blob),
 		PcmpResults: make(map[common.Hash][]byte),
+		AltDAInputs: make(map[common.Hash][]byte),
 	}
 }
 
@@ -85,3 +90,8 @@ func (o StubOracle) Precompile(addr common.Address, input []byte, requiredGas ui
 	}
 	return result, true
 }
+
+func (o StubOracle) GetAltDAInput(commitment altda.Keccak256Commitment) ([]byte, bool) {
+	input, ok := o.AltDAInputs[common.BytesToHash(commitment)]
+	return input, ok
+}
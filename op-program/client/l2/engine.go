@@ -33,19 +33,32 @@ func NewOracleEngine(rollupCfg *rollup.Config, logger log.Logger, backend engine
 }
 
 func (o *OracleEngine) L2OutputRoot(l2ClaimBlockNum uint64) (eth.Bytes32, error) {
-	outBlock := o.backend.GetHeaderByNumber(l2ClaimBlockNum)
+	output, err := o.L2OutputV0(l2ClaimBlockNum)
+	if err != nil {
+		return eth.Bytes32{}, err
+	}
+	return eth.OutputRoot(output), nil
+}
+
+// L2OutputV0 returns the components of the output root at the given block number.
+func (o *OracleEngine) L2OutputV0(blockNum uint64) (*eth.OutputV0, error) {
+	outBlock := o.backend.GetHeaderByNumber(blockNum)
 	if outBlock == nil {
-		return eth.Bytes32{}, fmt.Errorf("failed to get L2 block at %d", l2ClaimBlockNum)
+		return nil, fmt.Errorf("failed to get L2 block at %d", blockNum)
 	}
 	stateDB, err := o.backend.StateAt(outBlock.Root)
 	if err != nil {
-		return eth.Bytes32{}, fmt.Errorf("failed to open L2 state db at block %s: %w", outBlock.Hash(), err)
+		return nil, fmt.Errorf("failed to open L2 state db at block %s: %w", outBlock.Hash(), err)
 	}
 	withdrawalsTrie, err := stateDB.OpenStorageTrie(predeploys.L2ToL1MessagePasserAddr)
 	if err != nil {
-		return eth.Bytes32{}, fmt.Errorf("withdrawals trie unavailable at block %v: %w", outBlock.Hash(), err)
+		return nil, fmt.Errorf("withdrawals trie unavailable at block %v: %w", outBlock.Hash(), err)
 	}
-	return rollup.ComputeL2OutputRootV0(eth.HeaderBlockInfo(outBlock), withdrawalsTrie.Hash())
+	return &eth.OutputV0{
+		StateRoot:                eth.Bytes32(outBlock.Root),
+		MessagePasserStorageRoot: eth.Bytes32(withdrawalsTrie.Hash()),
+		BlockHash:                outBlock.Hash(),
+	}, nil
 }
 
 func (o *OracleEngine) GetPayload(ctx context.Context, payloadInfo eth.PayloadInfo) (*eth.ExecutionPayloadEnvelope, error) {
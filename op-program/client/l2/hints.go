@@ -1,7 +1,10 @@
 package l2
 
 import (
+	"encoding/binary"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
@@ -12,6 +15,7 @@ const (
 	HintL2Code         = "l2-code"
 	HintL2StateNode    = "l2-state-node"
 	HintL2Output       = "l2-output"
+	HintL2Receipts     = "l2-receipts"
 )
 
 type BlockHeaderHint common.Hash
@@ -53,3 +57,24 @@ var _ preimage.Hint = L2OutputHint{}
 func (l L2OutputHint) Hint() string {
 	return HintL2Output + " " + (common.Hash)(l).String()
 }
+
+type ReceiptsHint common.Hash
+
+var _ preimage.Hint = ReceiptsHint{}
+
+func (l ReceiptsHint) Hint() string {
+	return HintL2Receipts + " " + (common.Hash)(l).String()
+}
+
+// ChainHint appends the chain ID to the data of an L2 hint,
+// so the host can serve data from the right chain when multiple chains are derived in interop mode.
+type ChainHint struct {
+	Inner   preimage.Hint
+	ChainID uint64
+}
+
+var _ preimage.Hint = ChainHint{}
+
+func (l ChainHint) Hint() string {
+	return l.Inner.Hint() + hexutil.Encode(binary.BigEndian.AppendUint64(nil, l.ChainID))[2:]
+}
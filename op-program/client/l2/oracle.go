@@ -52,6 +52,13 @@ func NewPreimageOracle(raw preimage.Oracle, hint preimage.Hinter) *PreimageOracl
 	}
 }
 
+// NewPreimageOracleForChain creates a PreimageOracle that adds chainID to all hints, for use in interop mode.
+func NewPreimageOracleForChain(raw preimage.Oracle, hint preimage.Hinter, chainID uint64) *PreimageOracle {
+	return NewPreimageOracle(raw, preimage.HinterFn(func(v preimage.Hint) {
+		hint.Hint(ChainHint{Inner: v, ChainID: chainID})
+	}))
+}
+
 func (p *PreimageOracle) headerByBlockHash(blockHash common.Hash) *types.Header {
 	p.hint.Hint(BlockHeaderHint(blockHash))
 	headerRlp := p.oracle.Get(preimage.Keccak256Key(blockHash))
@@ -83,6 +90,23 @@ func (p *PreimageOracle) LoadTransactions(blockHash common.Hash, txHash common.H
 	return txs
 }
 
+// ReceiptsByBlockHash retrieves the block with the given hash and its receipts.
+func (p *PreimageOracle) ReceiptsByBlockHash(blockHash common.Hash) (*types.Block, types.Receipts) {
+	block := p.BlockByHash(blockHash)
+	p.hint.Hint(ReceiptsHint(blockHash))
+
+	opaqueReceipts := mpt.ReadTrie(block.ReceiptHash(), func(key common.Hash) []byte {
+		return p.oracle.Get(preimage.Keccak256Key(key))
+	})
+
+	txHashes := eth.TransactionsToHashes(block.Transactions())
+	receipts, err := eth.DecodeRawReceipts(eth.ToBlockID(block), opaqueReceipts, txHashes)
+	if err != nil {
+		panic(fmt.Errorf("bad receipts data for block %s: %w", blockHash, err))
+	}
+	return block, receipts
+}
+
 func (p *PreimageOracle) NodeByHash(nodeHash common.Hash) []byte {
 	p.hint.Hint(StateNodeHint(nodeHash))
 	return p.oracle.Get(preimage.Keccak256Key(nodeHash))
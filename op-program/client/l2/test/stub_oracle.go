@@ -19,9 +19,10 @@ type stateOracle interface {
 }
 
 type StubBlockOracle struct {
-	t       *testing.T
-	Blocks  map[common.Hash]*types.Block
-	Outputs map[common.Hash]eth.Output
+	t        *testing.T
+	Blocks   map[common.Hash]*types.Block
+	Receipts map[common.Hash]types.Receipts
+	Outputs  map[common.Hash]eth.Output
 	stateOracle
 }
 
@@ -30,6 +31,7 @@ func NewStubOracle(t *testing.T) (*StubBlockOracle, *StubStateOracle) {
 	blockOracle := StubBlockOracle{
 		t:           t,
 		Blocks:      make(map[common.Hash]*types.Block),
+		Receipts:    make(map[common.Hash]types.Receipts),
 		Outputs:     make(map[common.Hash]eth.Output),
 		stateOracle: stateOracle,
 	}
@@ -61,6 +63,15 @@ func (o StubBlockOracle) BlockByHash(blockHash common.Hash) *types.Block {
 	return block
 }
 
+func (o StubBlockOracle) ReceiptsByBlockHash(blockHash common.Hash) (*types.Block, types.Receipts) {
+	block := o.BlockByHash(blockHash)
+	receipts, ok := o.Receipts[blockHash]
+	if !ok {
+		o.t.Fatalf("requested unknown receipts for block %s", blockHash)
+	}
+	return block, receipts
+}
+
 func (o StubBlockOracle) OutputByRoot(root common.Hash) eth.Output {
 	output, ok := o.Outputs[root]
 	if !ok {
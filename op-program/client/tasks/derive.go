@@ -0,0 +1,54 @@
+package tasks
+
+import (
+	"fmt"
+
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	cldr "github.com/ethereum-optimism/optimism/op-program/client/driver"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// RunDerivation executes the L2 state transition from the agreed l2OutputRoot towards l2ClaimBlockNum, given a
+// minimal interface to retrieve data. It returns the engine, which can be queried for the resulting L2 chain.
+// Derivation stops early, without error, if the L1 data up to l1Head is exhausted before reaching l2ClaimBlockNum.
+func RunDerivation(logger log.Logger, cfg *rollup.Config, l2Cfg *params.ChainConfig, l1Head common.Hash, l2OutputRoot common.Hash, l2ClaimBlockNum uint64, l1Oracle l1.Oracle, l2Oracle l2.Oracle) (*l2.OracleEngine, error) {
+	l1Source := l1.NewOracleL1Client(logger, l1Oracle, l1Head)
+	l1BlobsSource := l1.NewBlobFetcher(logger, l1Oracle)
+	engineBackend, err := l2.NewOracleBackedL2Chain(logger, l2Oracle, l1Oracle /* kzg oracle */, l2Cfg, l2OutputRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oracle-backed L2 chain: %w", err)
+	}
+	l2Source := l2.NewOracleEngine(cfg, logger, engineBackend)
+	altDA, err := newAltDA(logger, cfg, l1Oracle)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Starting derivation")
+	d := cldr.NewDriver(logger, cfg, l1Source, l1BlobsSource, altDA, l2Source, l2ClaimBlockNum)
+	if err := d.RunComplete(); err != nil {
+		return nil, fmt.Errorf("failed to run program to completion: %w", err)
+	}
+	return l2Source, nil
+}
+
+// newAltDA creates the alt-DA input fetcher for the chain, reading inputs from the pre-image oracle.
+func newAltDA(logger log.Logger, cfg *rollup.Config, l1Oracle l1.Oracle) (derive.AltDAInputFetcher, error) {
+	if !cfg.AltDAEnabled() {
+		return altda.Disabled, nil
+	}
+	daCfg, err := cfg.GetOPAltDAConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid alt-DA config: %w", err)
+	}
+	if daCfg.CommitmentType != altda.Keccak256CommitmentType {
+		return nil, fmt.Errorf("%w: %v", l1.ErrUnsupportedAltDACommitment, daCfg.CommitmentType)
+	}
+	return altda.NewAltDAWithStorage(logger, daCfg, l1.NewAltDAStorage(l1Oracle), &altda.NoopMetrics{}), nil
+}
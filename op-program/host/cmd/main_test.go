@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/types"
@@ -16,7 +18,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 )
 
@@ -88,7 +92,7 @@ func TestNetwork(t *testing.T) {
 	})
 
 	t.Run("Required", func(t *testing.T) {
-		verifyArgsInvalid(t, "flag rollup.config or network is required", addRequiredArgsExcept("--network"))
+		verifyArgsInvalid(t, "flag rollup.config, network or config.bundle is required", addRequiredArgsExcept("--network"))
 	})
 
 	t.Run("DisallowNetworkAndRollupConfig", func(t *testing.T) {
@@ -100,7 +104,7 @@ func TestNetwork(t *testing.T) {
 		genesisFile := writeValidGenesis(t)
 
 		cfg := configForArgs(t, addRequiredArgsExcept("--network", "--rollup.config", configFile, "--l2.genesis", genesisFile))
-		require.Equal(t, *chaincfg.Sepolia, *cfg.Rollup)
+		require.Equal(t, *chaincfg.Sepolia, *cfg.Rollups[0])
 	})
 
 	for _, name := range chaincfg.AvailableNetworks() {
@@ -110,7 +114,7 @@ func TestNetwork(t *testing.T) {
 		t.Run("Network_"+name, func(t *testing.T) {
 			args := replaceRequiredArg("--network", name)
 			cfg := configForArgs(t, args)
-			require.Equal(t, *expected, *cfg.Rollup)
+			require.Equal(t, *expected, *cfg.Rollups[0])
 		})
 	}
 }
@@ -135,10 +139,41 @@ func TestDataFormat(t *testing.T) {
 	})
 }
 
+func TestCache(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.CacheDir)
+	})
+
+	t.Run("Dir", func(t *testing.T) {
+		expected := "/tmp/mainTestCacheDir"
+		cfg := configForArgs(t, addRequiredArgs("--cache.dir", expected))
+		require.Equal(t, expected, cfg.CacheDir)
+		require.Equal(t, uint64(10*1024*1024*1024), cfg.CacheSize)
+	})
+
+	t.Run("Size", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--cache.dir", "/tmp/mainTestCacheDir", "--cache.size", "5"))
+		require.Equal(t, uint64(5*1024*1024), cfg.CacheSize)
+	})
+}
+
+func TestPrefetchConcurrency(t *testing.T) {
+	t.Run("DefaultDisabled", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Zero(t, cfg.PrefetchConcurrency)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--prefetch.concurrency", "8"))
+		require.Equal(t, uint64(8), cfg.PrefetchConcurrency)
+	})
+}
+
 func TestL2(t *testing.T) {
 	expected := "https://example.com:8545"
 	cfg := configForArgs(t, addRequiredArgs("--l2", expected))
-	require.Equal(t, expected, cfg.L2URL)
+	require.Equal(t, []string{expected}, cfg.L2URLs)
 }
 
 func TestL2Genesis(t *testing.T) {
@@ -151,12 +186,39 @@ func TestL2Genesis(t *testing.T) {
 		rollupCfgFile := writeValidRollupConfig(t)
 		genesisFile := writeValidGenesis(t)
 		cfg := configForArgs(t, addRequiredArgsExcept("--network", "--rollup.config", rollupCfgFile, "--l2.genesis", genesisFile))
-		require.Equal(t, l2GenesisConfig, cfg.L2ChainConfig)
+		require.Equal(t, l2GenesisConfig, cfg.L2ChainConfigs[0])
 	})
 
 	t.Run("NotRequiredForGoerli", func(t *testing.T) {
 		cfg := configForArgs(t, replaceRequiredArg("--network", "sepolia"))
-		require.Equal(t, chainconfig.OPSepoliaChainConfig, cfg.L2ChainConfig)
+		require.Equal(t, chainconfig.OPSepoliaChainConfig, cfg.L2ChainConfigs[0])
+	})
+}
+
+func TestConfigBundle(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		bundleFile, hash := writeValidConfigBundle(t)
+		cfg := configForArgs(t, addRequiredArgsExcept("--network", "--config.bundle", bundleFile, "--config.bundle.hash", hash.Hex()))
+		require.Len(t, cfg.Rollups, 1)
+		require.Equal(t, chaincfg.Sepolia.L2ChainID, cfg.Rollups[0].L2ChainID)
+		require.Equal(t, chainconfig.OPSepoliaChainConfig.ChainID, cfg.L2ChainConfigs[0].ChainID)
+		require.Equal(t, hash, crypto.Keccak256Hash(cfg.ConfigBundle))
+	})
+
+	t.Run("HashRequired", func(t *testing.T) {
+		bundleFile, _ := writeValidConfigBundle(t)
+		verifyArgsInvalid(t, "flag config.bundle.hash is required", addRequiredArgsExcept("--network", "--config.bundle", bundleFile))
+	})
+
+	t.Run("HashMismatch", func(t *testing.T) {
+		bundleFile, _ := writeValidConfigBundle(t)
+		_, _, err := runWithArgs(addRequiredArgsExcept("--network", "--config.bundle", bundleFile, "--config.bundle.hash", common.Hash{0xaa}.Hex()))
+		require.ErrorIs(t, err, config.ErrConfigBundleMismatch)
+	})
+
+	t.Run("NotAllowedWithNetwork", func(t *testing.T) {
+		bundleFile, hash := writeValidConfigBundle(t)
+		verifyArgsInvalid(t, "cannot specify config.bundle with", addRequiredArgs("--config.bundle", bundleFile, "--config.bundle.hash", hash.Hex()))
 	})
 }
 
@@ -211,6 +273,39 @@ func TestL1(t *testing.T) {
 	require.Equal(t, expected, cfg.L1URL)
 }
 
+func TestFallbackSources(t *testing.T) {
+	t.Run("DefaultNone", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Empty(t, cfg.L1FallbackURLs)
+		require.Empty(t, cfg.L1BeaconFallbackURLs)
+		require.Empty(t, cfg.L2FallbackURLs)
+	})
+
+	t.Run("Multiple", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(
+			"--l1", "http://l1", "--l1.beacon", "http://beacon", "--l2", "http://l2",
+			"--l1.fallback", "http://l1-a", "--l1.fallback", "http://l1-b",
+			"--l1.beacon.fallback", "http://beacon-a",
+			"--l2.fallback", "http://l2-a", "--l2.fallback", "http://l2-b"))
+		require.Equal(t, []string{"http://l1-a", "http://l1-b"}, cfg.L1FallbackURLs)
+		require.Equal(t, []string{"http://beacon-a"}, cfg.L1BeaconFallbackURLs)
+		require.Equal(t, []string{"http://l2-a", "http://l2-b"}, cfg.L2FallbackURLs)
+	})
+}
+
+func TestAltDAServer(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.AltDAServerURL)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		expected := "http://localhost:3100"
+		cfg := configForArgs(t, addRequiredArgs("--altda.server", expected))
+		require.Equal(t, expected, cfg.AltDAServerURL)
+	})
+}
+
 func TestL1TrustRPC(t *testing.T) {
 	t.Run("DefaultFalse", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs())
@@ -301,6 +396,17 @@ func TestExec(t *testing.T) {
 	})
 }
 
+func TestDerivationLog(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.DerivationLog)
+	})
+	t.Run("Set", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--derivation.log", "/tmp/derivation.jsonl"))
+		require.Equal(t, "/tmp/derivation.jsonl", cfg.DerivationLog)
+	})
+}
+
 func TestServerMode(t *testing.T) {
 	t.Run("DefaultFalse", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs())
@@ -323,6 +429,53 @@ func TestServerMode(t *testing.T) {
 	})
 }
 
+func TestInterop(t *testing.T) {
+	agreedPrestate := "0x0100000000000000010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000aa"
+	interopArgs := func(args ...string) []string {
+		req := requiredArgs()
+		delete(req, "--l2.head")
+		delete(req, "--l2.outputroot")
+		delete(req, "--l2.blocknumber")
+		req["--l2.agreed-prestate"] = agreedPrestate
+		req["--l2.timestamp"] = "1234"
+		return append(append(toArgList(req), "--interop"), args...)
+	}
+
+	t.Run("DefaultFalse", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.False(t, cfg.InteropEnabled)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		cfg := configForArgs(t, interopArgs())
+		require.True(t, cfg.InteropEnabled)
+		require.Equal(t, common.FromHex(agreedPrestate), cfg.AgreedPrestate)
+		require.Equal(t, crypto.Keccak256Hash(common.FromHex(agreedPrestate)), cfg.L2OutputRoot)
+		require.Equal(t, uint64(1234), cfg.L2ClaimBlockNumber)
+	})
+
+	t.Run("AgreedPrestateRequired", func(t *testing.T) {
+		args := interopArgs()
+		verifyArgsInvalid(t, "flag l2.agreed-prestate is required", slices.DeleteFunc(args, func(arg string) bool {
+			return arg == "--l2.agreed-prestate" || arg == agreedPrestate
+		}))
+	})
+
+	t.Run("TimestampRequired", func(t *testing.T) {
+		args := interopArgs()
+		verifyArgsInvalid(t, "flag l2.timestamp is required", slices.DeleteFunc(args, func(arg string) bool {
+			return arg == "--l2.timestamp" || arg == "1234"
+		}))
+	})
+
+	t.Run("MultipleNetworks", func(t *testing.T) {
+		cfg := configForArgs(t, interopArgs("--network", "op-mainnet", "--l2", "http://a", "--l2", "http://b"))
+		require.Equal(t, []*rollup.Config{chaincfg.Sepolia, chaincfg.Mainnet}, cfg.Rollups)
+		require.Equal(t, []*params.ChainConfig{chainconfig.OPSepoliaChainConfig, chainconfig.OPMainnetChainConfig}, cfg.L2ChainConfigs)
+		require.Equal(t, []string{"http://a", "http://b"}, cfg.L2URLs)
+	})
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := runWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
@@ -395,6 +548,17 @@ func writeValidRollupConfig(t *testing.T) string {
 	return cfgFile
 }
 
+func writeValidConfigBundle(t *testing.T) (string, common.Hash) {
+	dir := t.TempDir()
+	j, err := json.Marshal(&chainconfig.Bundle{
+		Chains: []*chainconfig.BundleChain{{Rollup: chaincfg.Sepolia, ChainConfig: chainconfig.OPSepoliaChainConfig}},
+	})
+	require.NoError(t, err)
+	bundleFile := dir + "/bundle.json"
+	require.NoError(t, os.WriteFile(bundleFile, j, 0666))
+	return bundleFile, crypto.Keccak256Hash(j)
+}
+
 func toArgList(req map[string]string) []string {
 	var combined []string
 	for name, value := range req {
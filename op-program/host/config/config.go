@@ -8,6 +8,7 @@ import (
 	"slices"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/host/types"
 
 	opnode "github.com/ethereum-optimism/optimism/op-node"
@@ -16,27 +17,38 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/urfave/cli/v2"
 )
 
 var (
-	ErrMissingRollupConfig = errors.New("missing rollup config")
-	ErrMissingL2Genesis    = errors.New("missing l2 genesis")
-	ErrInvalidL1Head       = errors.New("invalid l1 head")
-	ErrInvalidL2Head       = errors.New("invalid l2 head")
-	ErrInvalidL2OutputRoot = errors.New("invalid l2 output root")
-	ErrL1AndL2Inconsistent = errors.New("l1 and l2 options must be specified together or both omitted")
-	ErrInvalidL2Claim      = errors.New("invalid l2 claim")
-	ErrInvalidL2ClaimBlock = errors.New("invalid l2 claim block number")
-	ErrDataDirRequired     = errors.New("datadir must be specified when in non-fetching mode")
-	ErrNoExecInServerMode  = errors.New("exec command must not be set when in server mode")
-	ErrInvalidDataFormat   = errors.New("invalid data format")
+	ErrMissingRollupConfig         = errors.New("missing rollup config")
+	ErrMissingL2Genesis            = errors.New("missing l2 genesis")
+	ErrInvalidL1Head               = errors.New("invalid l1 head")
+	ErrInvalidL2Head               = errors.New("invalid l2 head")
+	ErrInvalidL2OutputRoot         = errors.New("invalid l2 output root")
+	ErrL1AndL2Inconsistent         = errors.New("l1 and l2 options must be specified together or both omitted")
+	ErrInvalidL2Claim              = errors.New("invalid l2 claim")
+	ErrInvalidL2ClaimBlock         = errors.New("invalid l2 claim block number")
+	ErrDataDirRequired             = errors.New("datadir must be specified when in non-fetching mode")
+	ErrNoExecInServerMode          = errors.New("exec command must not be set when in server mode")
+	ErrNoDerivationLogInServerMode = errors.New("derivation log must not be set when in server mode")
+	ErrInvalidDataFormat           = errors.New("invalid data format")
+	ErrInvalidCacheSize            = errors.New("cache size must be greater than zero")
+	ErrFallbackWithoutPrimary      = errors.New("fallback sources require a primary source")
+	ErrMultipleChains              = errors.New("multiple l2 chains are only supported in interop mode")
+	ErrNoL2ChainConfig             = errors.New("no l2 chain config for rollup")
+	ErrMissingAgreedPrestate       = errors.New("missing agreed prestate")
+	ErrInvalidAgreedPrestate       = errors.New("agreed prestate does not match l2 output root")
+	ErrConfigBundleMismatch        = errors.New("config bundle does not match expected hash")
 )
 
 type Config struct {
-	Rollup *rollup.Config
+	// Rollups are the rollup configs of the L2 chains.
+	// Multiple chains are only supported in interop mode.
+	Rollups []*rollup.Config
 	// DataDir is the directory to read/write pre-image data from/to.
 	// If not set, an in-memory key-value store is used and fetching data must be enabled
 	DataDir string
@@ -44,28 +56,51 @@ type Config struct {
 	// DataFormat specifies the format to use for on-disk storage. Only applies when DataDir is set.
 	DataFormat types.DataFormat
 
+	// CacheDir is the directory of the pre-image cache shared between runs. The cache is disabled if not set.
+	CacheDir string
+	// CacheSize is the maximum size of the pre-image cache in bytes. Only applies when CacheDir is set.
+	CacheSize uint64
+
+	// PrefetchConcurrency is the maximum number of pre-images prefetched concurrently ahead of the client's requests.
+	// Speculative prefetching is disabled if 0.
+	PrefetchConcurrency uint64
+
 	// L1Head is the block hash of the L1 chain head block
 	L1Head      common.Hash
 	L1URL       string
 	L1BeaconURL string
 	L1TrustRPC  bool
 	L1RPCKind   sources.RPCProviderKind
+	// L1FallbackURLs and L1BeaconFallbackURLs are used, in order, when the primary L1 source fails or returns
+	// data that does not match the requested hash.
+	L1FallbackURLs       []string
+	L1BeaconFallbackURLs []string
 
 	// L2Head is the l2 block hash contained in the L2 Output referenced by the L2OutputRoot
 	L2Head common.Hash
-	// L2OutputRoot is the agreed L2 output root to start derivation from
+	// L2OutputRoot is the agreed L2 output root to start derivation from.
+	// In interop mode it is the hash of the AgreedPrestate.
 	L2OutputRoot common.Hash
-	L2URL        string
+	L2URLs       []string
+	// L2FallbackURLs are used, in order, when the primary L2 source for the same chain fails or returns
+	// data that does not match the requested hash.
+	L2FallbackURLs []string
+	// AltDAServerURL is the alt-DA server to fetch alt-DA input data from.
+	AltDAServerURL string
 	// L2Claim is the claimed L2 output root to verify
 	L2Claim common.Hash
 	// L2ClaimBlockNumber is the block number the claimed L2 output root is from
 	// Must be above 0 and to be a valid claim needs to be above the L2Head block.
+	// In interop mode it is the timestamp of the claimed super root.
 	L2ClaimBlockNumber uint64
-	// L2ChainConfig is the op-geth chain config for the L2 execution engine
-	L2ChainConfig *params.ChainConfig
+	// L2ChainConfigs are the op-geth chain configs for the L2 execution engines
+	L2ChainConfigs []*params.ChainConfig
 	// ExecCmd specifies the client program to execute in a separate process.
 	// If unset, the fault proof client is run in the same process.
 	ExecCmd string
+	// DerivationLog is the file to write a JSON lines log of the derivation decisions of the client program to.
+	// Only applies when the client program is run in the same process or with ExecCmd.
+	DerivationLog string
 
 	// ServerMode indicates that the program should run in pre-image server mode and wait for requests.
 	// No client program is run.
@@ -73,19 +108,29 @@ type Config struct {
 
 	// IsCustomChainConfig indicates that the program uses a custom chain configuration
 	IsCustomChainConfig bool
+	// ConfigBundle is the JSON encoded chain config bundle the Rollups and L2ChainConfigs were loaded from, if any.
+	// The program reads the bundle as a keccak256 pre-image of the hash provided as a local input.
+	ConfigBundle []byte
+
+	// InteropEnabled indicates that the program validates a super root transition of an interop dependency set.
+	InteropEnabled bool
+	// AgreedPrestate is the pre-image of the agreed super root or transition state to start from in interop mode.
+	AgreedPrestate []byte
 }
 
 func (c *Config) Check() error {
-	if c.Rollup == nil {
+	if len(c.Rollups) == 0 {
 		return ErrMissingRollupConfig
 	}
-	if err := c.Rollup.Check(); err != nil {
-		return err
+	for _, rollupCfg := range c.Rollups {
+		if err := rollupCfg.Check(); err != nil {
+			return err
+		}
 	}
 	if c.L1Head == (common.Hash{}) {
 		return ErrInvalidL1Head
 	}
-	if c.L2Head == (common.Hash{}) {
+	if !c.InteropEnabled && c.L2Head == (common.Hash{}) {
 		return ErrInvalidL2Head
 	}
 	if c.L2OutputRoot == (common.Hash{}) {
@@ -94,10 +139,25 @@ func (c *Config) Check() error {
 	if c.L2ClaimBlockNumber == 0 {
 		return ErrInvalidL2ClaimBlock
 	}
-	if c.L2ChainConfig == nil {
+	if len(c.L2ChainConfigs) == 0 {
 		return ErrMissingL2Genesis
 	}
-	if (c.L1URL != "") != (c.L2URL != "") {
+	for _, rollupCfg := range c.Rollups {
+		if c.L2ChainConfig(rollupCfg.L2ChainID.Uint64()) == nil {
+			return fmt.Errorf("%w: chain ID %v", ErrNoL2ChainConfig, rollupCfg.L2ChainID)
+		}
+	}
+	if c.InteropEnabled {
+		if len(c.AgreedPrestate) == 0 {
+			return ErrMissingAgreedPrestate
+		}
+		if crypto.Keccak256Hash(c.AgreedPrestate) != c.L2OutputRoot {
+			return ErrInvalidAgreedPrestate
+		}
+	} else if len(c.Rollups) > 1 || len(c.L2ChainConfigs) > 1 || len(c.L2URLs) > 1 {
+		return ErrMultipleChains
+	}
+	if (c.L1URL != "") != (len(c.L2URLs) > 0) {
 		return ErrL1AndL2Inconsistent
 	}
 	if !c.FetchingEnabled() && c.DataDir == "" {
@@ -106,14 +166,35 @@ func (c *Config) Check() error {
 	if c.ServerMode && c.ExecCmd != "" {
 		return ErrNoExecInServerMode
 	}
+	if c.ServerMode && c.DerivationLog != "" {
+		return ErrNoDerivationLogInServerMode
+	}
 	if c.DataDir != "" && !slices.Contains(types.SupportedDataFormats, c.DataFormat) {
 		return ErrInvalidDataFormat
 	}
+	if (len(c.L1FallbackURLs) > 0 && c.L1URL == "") ||
+		(len(c.L1BeaconFallbackURLs) > 0 && c.L1BeaconURL == "") ||
+		(len(c.L2FallbackURLs) > 0 && len(c.L2URLs) == 0) {
+		return ErrFallbackWithoutPrimary
+	}
+	if c.CacheDir != "" && c.CacheSize == 0 {
+		return ErrInvalidCacheSize
+	}
 	return nil
 }
 
 func (c *Config) FetchingEnabled() bool {
-	return c.L1URL != "" && c.L2URL != "" && c.L1BeaconURL != ""
+	return c.L1URL != "" && len(c.L2URLs) > 0 && c.L1BeaconURL != ""
+}
+
+// L2ChainConfig returns the chain config for the L2 chain with the given chain ID, or nil if it is not configured.
+func (c *Config) L2ChainConfig(chainID uint64) *params.ChainConfig {
+	for _, chainCfg := range c.L2ChainConfigs {
+		if chainCfg.ChainID.Uint64() == chainID {
+			return chainCfg
+		}
+	}
+	return nil
 }
 
 // NewConfig creates a Config with all optional values set to the CLI default value
@@ -129,8 +210,8 @@ func NewConfig(
 	_, err := params.LoadOPStackChainConfig(l2Genesis.ChainID.Uint64())
 	isCustomConfig := err != nil
 	return &Config{
-		Rollup:              rollupCfg,
-		L2ChainConfig:       l2Genesis,
+		Rollups:             []*rollup.Config{rollupCfg},
+		L2ChainConfigs:      []*params.ChainConfig{l2Genesis},
 		L1Head:              l1Head,
 		L2Head:              l2Head,
 		L2OutputRoot:        l2OutputRoot,
@@ -139,6 +220,7 @@ func NewConfig(
 		L1RPCKind:           sources.RPCKindStandard,
 		IsCustomChainConfig: isCustomConfig,
 		DataFormat:          types.DataFormatDirectory,
+		CacheSize:           flags.CacheSize.Value * 1024 * 1024,
 	}
 }
 
@@ -146,17 +228,56 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 	if err := flags.CheckRequired(ctx); err != nil {
 		return nil, err
 	}
-	rollupCfg, err := opnode.NewRollupConfigFromCLI(log, ctx)
-	if err != nil {
-		return nil, err
+	var rollupCfgs []*rollup.Config
+	for _, network := range ctx.StringSlice(flags.Network.Name) {
+		rollupCfg, err := opnode.NewRollupConfig(log, network, "")
+		if err != nil {
+			return nil, err
+		}
+		rollupCfgs = append(rollupCfgs, rollupCfg)
+	}
+	for _, path := range ctx.StringSlice(flags.RollupConfig.Name) {
+		rollupCfg, err := opnode.NewRollupConfig(log, "", path)
+		if err != nil {
+			return nil, err
+		}
+		rollupCfgs = append(rollupCfgs, rollupCfg)
 	}
-	l2Head := common.HexToHash(ctx.String(flags.L2Head.Name))
-	if l2Head == (common.Hash{}) {
-		return nil, ErrInvalidL2Head
+	var l2ChainConfigs []*params.ChainConfig
+	var configBundle []byte
+	if path := ctx.String(flags.ConfigBundle.Name); path != "" {
+		data, bundle, err := loadConfigBundle(path, common.HexToHash(ctx.String(flags.ConfigBundleHash.Name)))
+		if err != nil {
+			return nil, err
+		}
+		for _, chain := range bundle.Chains {
+			rollupCfgs = append(rollupCfgs, chain.Rollup)
+			l2ChainConfigs = append(l2ChainConfigs, chain.ChainConfig)
+		}
+		configBundle = data
 	}
-	l2OutputRoot := common.HexToHash(ctx.String(flags.L2OutputRoot.Name))
-	if l2OutputRoot == (common.Hash{}) {
-		return nil, ErrInvalidL2OutputRoot
+	interopEnabled := ctx.Bool(flags.Interop.Name)
+	var l2Head common.Hash
+	var l2OutputRoot common.Hash
+	var agreedPrestate []byte
+	var l2ClaimBlockNum uint64
+	if interopEnabled {
+		agreedPrestate = common.FromHex(ctx.String(flags.L2AgreedPrestate.Name))
+		if len(agreedPrestate) == 0 {
+			return nil, ErrMissingAgreedPrestate
+		}
+		l2OutputRoot = crypto.Keccak256Hash(agreedPrestate)
+		l2ClaimBlockNum = ctx.Uint64(flags.L2Timestamp.Name)
+	} else {
+		l2Head = common.HexToHash(ctx.String(flags.L2Head.Name))
+		if l2Head == (common.Hash{}) {
+			return nil, ErrInvalidL2Head
+		}
+		l2OutputRoot = common.HexToHash(ctx.String(flags.L2OutputRoot.Name))
+		if l2OutputRoot == (common.Hash{}) {
+			return nil, ErrInvalidL2OutputRoot
+		}
+		l2ClaimBlockNum = ctx.Uint64(flags.L2BlockNumber.Name)
 	}
 	strClaim := ctx.String(flags.L2Claim.Name)
 	l2Claim := common.HexToHash(strClaim)
@@ -166,57 +287,86 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 		strClaim != "0000000000000000000000000000000000000000000000000000000000000000" {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidL2Claim, strClaim)
 	}
-	l2ClaimBlockNum := ctx.Uint64(flags.L2BlockNumber.Name)
 	l1Head := common.HexToHash(ctx.String(flags.L1Head.Name))
 	if l1Head == (common.Hash{}) {
 		return nil, ErrInvalidL1Head
 	}
-	l2GenesisPath := ctx.String(flags.L2GenesisPath.Name)
-	var l2ChainConfig *params.ChainConfig
+	l2GenesisPaths := ctx.StringSlice(flags.L2GenesisPath.Name)
 	var isCustomConfig bool
-	if l2GenesisPath == "" {
-		networkName := ctx.String(flags.Network.Name)
-		ch := chaincfg.ChainByName(networkName)
-		if ch == nil {
-			return nil, fmt.Errorf("flag %s is required for network %s", flags.L2GenesisPath.Name, networkName)
+	if len(l2GenesisPaths) == 0 {
+		for _, networkName := range ctx.StringSlice(flags.Network.Name) {
+			ch := chaincfg.ChainByName(networkName)
+			if ch == nil {
+				return nil, fmt.Errorf("flag %s is required for network %s", flags.L2GenesisPath.Name, networkName)
+			}
+			cfg, err := params.LoadOPStackChainConfig(ch.ChainID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load chain config for chain %d: %w", ch.ChainID, err)
+			}
+			l2ChainConfigs = append(l2ChainConfigs, cfg)
 		}
-		cfg, err := params.LoadOPStackChainConfig(ch.ChainID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load chain config for chain %d: %w", ch.ChainID, err)
-		}
-		l2ChainConfig = cfg
 	} else {
-		l2ChainConfig, err = loadChainConfigFromGenesis(l2GenesisPath)
+		for _, l2GenesisPath := range l2GenesisPaths {
+			l2ChainConfig, err := loadChainConfigFromGenesis(l2GenesisPath)
+			if err != nil {
+				return nil, fmt.Errorf("invalid genesis: %w", err)
+			}
+			l2ChainConfigs = append(l2ChainConfigs, l2ChainConfig)
+		}
 		isCustomConfig = true
 	}
-	if err != nil {
-		return nil, fmt.Errorf("invalid genesis: %w", err)
-	}
 	dbFormat := types.DataFormat(ctx.String(flags.DataFormat.Name))
 	if !slices.Contains(types.SupportedDataFormats, dbFormat) {
 		return nil, fmt.Errorf("invalid %w: %v", ErrInvalidDataFormat, dbFormat)
 	}
 	return &Config{
-		Rollup:              rollupCfg,
-		DataDir:             ctx.String(flags.DataDir.Name),
-		DataFormat:          dbFormat,
-		L2URL:               ctx.String(flags.L2NodeAddr.Name),
-		L2ChainConfig:       l2ChainConfig,
-		L2Head:              l2Head,
-		L2OutputRoot:        l2OutputRoot,
-		L2Claim:             l2Claim,
-		L2ClaimBlockNumber:  l2ClaimBlockNum,
-		L1Head:              l1Head,
-		L1URL:               ctx.String(flags.L1NodeAddr.Name),
-		L1BeaconURL:         ctx.String(flags.L1BeaconAddr.Name),
-		L1TrustRPC:          ctx.Bool(flags.L1TrustRPC.Name),
-		L1RPCKind:           sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
-		ExecCmd:             ctx.String(flags.Exec.Name),
-		ServerMode:          ctx.Bool(flags.Server.Name),
-		IsCustomChainConfig: isCustomConfig,
+		Rollups:              rollupCfgs,
+		DataDir:              ctx.String(flags.DataDir.Name),
+		DataFormat:           dbFormat,
+		CacheDir:             ctx.String(flags.CacheDir.Name),
+		CacheSize:            ctx.Uint64(flags.CacheSize.Name) * 1024 * 1024,
+		PrefetchConcurrency:  ctx.Uint64(flags.PrefetchConcurrency.Name),
+		L2URLs:               ctx.StringSlice(flags.L2NodeAddr.Name),
+		L2ChainConfigs:       l2ChainConfigs,
+		L2Head:               l2Head,
+		L2OutputRoot:         l2OutputRoot,
+		L2Claim:              l2Claim,
+		L2ClaimBlockNumber:   l2ClaimBlockNum,
+		L1Head:               l1Head,
+		L1URL:                ctx.String(flags.L1NodeAddr.Name),
+		L1BeaconURL:          ctx.String(flags.L1BeaconAddr.Name),
+		L1FallbackURLs:       ctx.StringSlice(flags.L1FallbackAddrs.Name),
+		L1BeaconFallbackURLs: ctx.StringSlice(flags.L1BeaconFallbackAddrs.Name),
+		L2FallbackURLs:       ctx.StringSlice(flags.L2FallbackAddrs.Name),
+		AltDAServerURL:       ctx.String(flags.AltDAServerAddr.Name),
+		L1TrustRPC:           ctx.Bool(flags.L1TrustRPC.Name),
+		L1RPCKind:            sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
+		ExecCmd:              ctx.String(flags.Exec.Name),
+		DerivationLog:        ctx.String(flags.DerivationLog.Name),
+		ServerMode:           ctx.Bool(flags.Server.Name),
+		IsCustomChainConfig:  isCustomConfig,
+		ConfigBundle:         configBundle,
+		InteropEnabled:       interopEnabled,
+		AgreedPrestate:       agreedPrestate,
 	}, nil
 }
 
+// loadConfigBundle reads the chain config bundle at path, checking it matches the expected keccak256 hash.
+func loadConfigBundle(path string, expectedHash common.Hash) ([]byte, *chainconfig.Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read config bundle: %w", err)
+	}
+	if hash := crypto.Keccak256Hash(data); hash != expectedHash {
+		return nil, nil, fmt.Errorf("%w: expected %v but was %v", ErrConfigBundleMismatch, expectedHash, hash)
+	}
+	bundle, err := chainconfig.DecodeBundle(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, bundle, nil
+}
+
 func loadChainConfigFromGenesis(path string) (*params.ChainConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +23,7 @@ var (
 	validL2Claim         = common.Hash{0xcc}
 	validL2OutputRoot    = common.Hash{0xdd}
 	validL2ClaimBlockNum = uint64(15)
+	validAgreedPrestate  = []byte{1}
 )
 
 // TestValidConfigIsValid checks that the config provided by validConfig is actually valid
@@ -33,14 +35,14 @@ func TestValidConfigIsValid(t *testing.T) {
 func TestRollupConfig(t *testing.T) {
 	t.Run("Required", func(t *testing.T) {
 		config := validConfig()
-		config.Rollup = nil
+		config.Rollups = nil
 		err := config.Check()
 		require.ErrorIs(t, err, ErrMissingRollupConfig)
 	})
 
 	t.Run("Invalid", func(t *testing.T) {
 		config := validConfig()
-		config.Rollup = &rollup.Config{}
+		config.Rollups = []*rollup.Config{{}}
 		err := config.Check()
 		require.ErrorIs(t, err, rollup.ErrBlockTimeZero)
 	})
@@ -83,7 +85,7 @@ func TestL2ClaimBlockNumberRequired(t *testing.T) {
 
 func TestL2GenesisRequired(t *testing.T) {
 	config := validConfig()
-	config.L2ChainConfig = nil
+	config.L2ChainConfigs = nil
 	err := config.Check()
 	require.ErrorIs(t, err, ErrMissingL2Genesis)
 }
@@ -96,19 +98,19 @@ func TestFetchingArgConsistency(t *testing.T) {
 	})
 	t.Run("RequireL1WhenL2Set", func(t *testing.T) {
 		cfg := validConfig()
-		cfg.L2URL = "https://example.com:1234"
+		cfg.L2URLs = []string{"https://example.com:1234"}
 		require.ErrorIs(t, cfg.Check(), ErrL1AndL2Inconsistent)
 	})
 	t.Run("AllowNeitherSet", func(t *testing.T) {
 		cfg := validConfig()
 		cfg.L1URL = ""
-		cfg.L2URL = ""
+		cfg.L2URLs = nil
 		require.NoError(t, cfg.Check())
 	})
 	t.Run("AllowBothSet", func(t *testing.T) {
 		cfg := validConfig()
 		cfg.L1URL = "https://example.com:1234"
-		cfg.L2URL = "https://example.com:4678"
+		cfg.L2URLs = []string{"https://example.com:4678"}
 		require.NoError(t, cfg.Check())
 	})
 }
@@ -121,13 +123,13 @@ func TestFetchingEnabled(t *testing.T) {
 
 	t.Run("FetchingEnabledWhenFetcherUrlsSpecified", func(t *testing.T) {
 		cfg := validConfig()
-		cfg.L2URL = "https://example.com:1234"
+		cfg.L2URLs = []string{"https://example.com:1234"}
 		require.False(t, cfg.FetchingEnabled(), "Should not enable fetching when node URL not supplied")
 	})
 
 	t.Run("FetchingNotEnabledWhenNoL1UrlSpecified", func(t *testing.T) {
 		cfg := validConfig()
-		cfg.L2URL = "https://example.com:1234"
+		cfg.L2URLs = []string{"https://example.com:1234"}
 		require.False(t, cfg.FetchingEnabled(), "Should not enable L1 fetching when L1 node URL not supplied")
 	})
 
@@ -141,7 +143,7 @@ func TestFetchingEnabled(t *testing.T) {
 		cfg := validConfig()
 		cfg.L1URL = "https://example.com:1234"
 		cfg.L1BeaconURL = "https://example.com:5678"
-		cfg.L2URL = "https://example.com:91011"
+		cfg.L2URLs = []string{"https://example.com:91011"}
 		require.True(t, cfg.FetchingEnabled(), "Should enable fetching when node URL supplied")
 	})
 }
@@ -150,7 +152,7 @@ func TestRequireDataDirInNonFetchingMode(t *testing.T) {
 	cfg := validConfig()
 	cfg.DataDir = ""
 	cfg.L1URL = ""
-	cfg.L2URL = ""
+	cfg.L2URLs = nil
 	err := cfg.Check()
 	require.ErrorIs(t, err, ErrDataDirRequired)
 }
@@ -163,6 +165,14 @@ func TestRejectExecAndServerMode(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoExecInServerMode)
 }
 
+func TestRejectDerivationLogAndServerMode(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServerMode = true
+	cfg.DerivationLog = "derivation.jsonl"
+	err := cfg.Check()
+	require.ErrorIs(t, err, ErrNoDerivationLogInServerMode)
+}
+
 func TestIsCustomChainConfig(t *testing.T) {
 	t.Run("nonCustom", func(t *testing.T) {
 		cfg := validConfig()
@@ -192,8 +202,95 @@ func TestDBFormat(t *testing.T) {
 	}
 }
 
+func TestFallbackRequiresPrimary(t *testing.T) {
+	t.Run("L1", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L1FallbackURLs = []string{"https://example.com:1234"}
+		require.ErrorIs(t, cfg.Check(), ErrFallbackWithoutPrimary)
+	})
+	t.Run("L1Beacon", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L1BeaconFallbackURLs = []string{"https://example.com:1234"}
+		require.ErrorIs(t, cfg.Check(), ErrFallbackWithoutPrimary)
+	})
+	t.Run("L2", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2FallbackURLs = []string{"https://example.com:1234"}
+		require.ErrorIs(t, cfg.Check(), ErrFallbackWithoutPrimary)
+	})
+	t.Run("Valid", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L1BeaconURL = "https://example.com:5678"
+		cfg.L2URLs = []string{"https://example.com:91011"}
+		cfg.L1FallbackURLs = []string{"https://example.com:1"}
+		cfg.L1BeaconFallbackURLs = []string{"https://example.com:2"}
+		cfg.L2FallbackURLs = []string{"https://example.com:3"}
+		require.NoError(t, cfg.Check())
+	})
+}
+
+func TestCacheSize(t *testing.T) {
+	cfg := validConfig()
+	cfg.CacheDir = "/tmp/configTestCache"
+	require.NoError(t, cfg.Check())
+
+	cfg.CacheSize = 0
+	require.ErrorIs(t, cfg.Check(), ErrInvalidCacheSize)
+}
+
+func TestMultipleChainsRequireInterop(t *testing.T) {
+	cfg := validConfig()
+	cfg.Rollups = append(cfg.Rollups, chaincfg.Mainnet)
+	cfg.L2ChainConfigs = append(cfg.L2ChainConfigs, chainconfig.OPMainnetChainConfig)
+	require.ErrorIs(t, cfg.Check(), ErrMultipleChains)
+
+	cfg.InteropEnabled = true
+	cfg.AgreedPrestate = validAgreedPrestate
+	cfg.L2OutputRoot = crypto.Keccak256Hash(validAgreedPrestate)
+	require.NoError(t, cfg.Check())
+}
+
+func TestRollupRequiresChainConfig(t *testing.T) {
+	cfg := validInteropConfig()
+	cfg.Rollups = append(cfg.Rollups, chaincfg.Mainnet)
+	require.ErrorIs(t, cfg.Check(), ErrNoL2ChainConfig)
+}
+
+func TestInteropConfig(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		require.NoError(t, validInteropConfig().Check())
+	})
+
+	t.Run("L2HeadNotRequired", func(t *testing.T) {
+		cfg := validInteropConfig()
+		cfg.L2Head = common.Hash{}
+		require.NoError(t, cfg.Check())
+	})
+
+	t.Run("AgreedPrestateRequired", func(t *testing.T) {
+		cfg := validInteropConfig()
+		cfg.AgreedPrestate = nil
+		require.ErrorIs(t, cfg.Check(), ErrMissingAgreedPrestate)
+	})
+
+	t.Run("AgreedPrestateMustMatchOutputRoot", func(t *testing.T) {
+		cfg := validInteropConfig()
+		cfg.L2OutputRoot = common.Hash{0xee}
+		require.ErrorIs(t, cfg.Check(), ErrInvalidAgreedPrestate)
+	})
+}
+
 func validConfig() *Config {
 	cfg := NewConfig(validRollupConfig, validL2Genesis, validL1Head, validL2Head, validL2OutputRoot, validL2Claim, validL2ClaimBlockNum)
 	cfg.DataDir = "/tmp/configTest"
 	return cfg
 }
+
+func validInteropConfig() *Config {
+	cfg := validConfig()
+	cfg.InteropEnabled = true
+	cfg.AgreedPrestate = validAgreedPrestate
+	cfg.L2OutputRoot = crypto.Keccak256Hash(validAgreedPrestate)
+	return cfg
+}
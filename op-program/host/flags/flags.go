@@ -21,16 +21,26 @@ func prefixEnvVars(name string) []string {
 }
 
 var (
-	RollupConfig = &cli.StringFlag{
+	RollupConfig = &cli.StringSliceFlag{
 		Name:    "rollup.config",
-		Usage:   "Rollup chain parameters",
+		Usage:   "Rollup chain parameters. May be specified multiple times in interop mode",
 		EnvVars: prefixEnvVars("ROLLUP_CONFIG"),
 	}
-	Network = &cli.StringFlag{
+	Network = &cli.StringSliceFlag{
 		Name:    "network",
-		Usage:   fmt.Sprintf("Predefined network selection. Available networks: %s", strings.Join(chaincfg.AvailableNetworks(), ", ")),
+		Usage:   fmt.Sprintf("Predefined network selection. May be specified multiple times in interop mode. Available networks: %s", strings.Join(chaincfg.AvailableNetworks(), ", ")),
 		EnvVars: prefixEnvVars("NETWORK"),
 	}
+	ConfigBundle = &cli.StringFlag{
+		Name:    "config.bundle",
+		Usage:   "Path to a JSON bundle of the rollup and chain configs of chains that are not predefined networks, as an alternative to --rollup.config and --l2.genesis. Requires --config.bundle.hash",
+		EnvVars: prefixEnvVars("CONFIG_BUNDLE"),
+	}
+	ConfigBundleHash = &cli.StringFlag{
+		Name:    "config.bundle.hash",
+		Usage:   "Expected keccak256 hash of the --config.bundle file",
+		EnvVars: prefixEnvVars("CONFIG_BUNDLE_HASH"),
+	}
 	DataDir = &cli.StringFlag{
 		Name:    "datadir",
 		Usage:   "Directory to use for preimage data storage. Default uses in-memory storage",
@@ -38,13 +48,29 @@ var (
 	}
 	DataFormat = &cli.StringFlag{
 		Name:    "data.format",
-		Usage:   fmt.Sprintf("Format to use for preimage data storage. Available formats: %s", openum.EnumString(types.SupportedDataFormats)),
+		Usage:   fmt.Sprintf("Format to use for preimage data storage. Existing data directories keep their recorded format. Available formats: %s", openum.EnumString(types.SupportedDataFormats)),
 		EnvVars: prefixEnvVars("DATA_FORMAT"),
 		Value:   string(types.DataFormatDirectory),
 	}
-	L2NodeAddr = &cli.StringFlag{
+	CacheDir = &cli.StringFlag{
+		Name:    "cache.dir",
+		Usage:   "Directory to use as a pre-image cache shared between runs. Cached pre-images are verified when read. Default disables the cache",
+		EnvVars: prefixEnvVars("CACHE_DIR"),
+	}
+	CacheSize = &cli.Uint64Flag{
+		Name:    "cache.size",
+		Usage:   "Maximum size of the pre-image cache in MiB. Least recently used pre-images are evicted when exceeded",
+		EnvVars: prefixEnvVars("CACHE_SIZE"),
+		Value:   10 * 1024,
+	}
+	PrefetchConcurrency = &cli.Uint64Flag{
+		Name:    "prefetch.concurrency",
+		Usage:   "Maximum number of pre-images to prefetch concurrently ahead of the client's requests, such as the parents of requested blocks and children of requested state nodes. Increases the load on the L1 and L2 RPCs. Default disables speculative prefetching",
+		EnvVars: prefixEnvVars("PREFETCH_CONCURRENCY"),
+	}
+	L2NodeAddr = &cli.StringSliceFlag{
 		Name:    "l2",
-		Usage:   "Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)",
+		Usage:   "Address of L2 JSON-RPC endpoint to use (eth and debug namespace required). May be specified multiple times in interop mode",
 		EnvVars: prefixEnvVars("L2_RPC"),
 	}
 	L1Head = &cli.StringFlag{
@@ -72,9 +98,19 @@ var (
 		Usage:   "Number of the L2 block that the claim is from",
 		EnvVars: prefixEnvVars("L2_BLOCK_NUM"),
 	}
-	L2GenesisPath = &cli.StringFlag{
+	L2AgreedPrestate = &cli.StringFlag{
+		Name:    "l2.agreed-prestate",
+		Usage:   "Agreed super root or transition state to start from, as the hex encoded pre-image. Only used in interop mode",
+		EnvVars: prefixEnvVars("L2_AGREED_PRESTATE"),
+	}
+	L2Timestamp = &cli.Uint64Flag{
+		Name:    "l2.timestamp",
+		Usage:   "Timestamp of the super root that the claim is for. Only used in interop mode",
+		EnvVars: prefixEnvVars("L2_TIMESTAMP"),
+	}
+	L2GenesisPath = &cli.StringSliceFlag{
 		Name:    "l2.genesis",
-		Usage:   "Path to the op-geth genesis file",
+		Usage:   "Path to the op-geth genesis file. May be specified multiple times in interop mode",
 		EnvVars: prefixEnvVars("L2_GENESIS"),
 	}
 	L1NodeAddr = &cli.StringFlag{
@@ -87,6 +123,26 @@ var (
 		Usage:   "Address of L1 Beacon API endpoint to use",
 		EnvVars: prefixEnvVars("L1_BEACON_API"),
 	}
+	L1FallbackAddrs = &cli.StringSliceFlag{
+		Name:    "l1.fallback",
+		Usage:   "Address of an L1 JSON-RPC endpoint to use when the primary L1 endpoint fails or returns invalid data. May be specified multiple times",
+		EnvVars: prefixEnvVars("L1_FALLBACK_RPC"),
+	}
+	L1BeaconFallbackAddrs = &cli.StringSliceFlag{
+		Name:    "l1.beacon.fallback",
+		Usage:   "Address of an L1 Beacon API endpoint to use when the primary L1 Beacon endpoint fails or returns invalid data. May be specified multiple times",
+		EnvVars: prefixEnvVars("L1_BEACON_FALLBACK_API"),
+	}
+	L2FallbackAddrs = &cli.StringSliceFlag{
+		Name:    "l2.fallback",
+		Usage:   "Address of an L2 JSON-RPC endpoint to use when the primary L2 endpoint for the same chain fails or returns invalid data. May be specified multiple times",
+		EnvVars: prefixEnvVars("L2_FALLBACK_RPC"),
+	}
+	AltDAServerAddr = &cli.StringFlag{
+		Name:    "altda.server",
+		Usage:   "Address of the alt-DA server to fetch alt-DA input data from. Required for chains using alt-DA",
+		EnvVars: prefixEnvVars("ALTDA_SERVER"),
+	}
 	L1TrustRPC = &cli.BoolFlag{
 		Name:    "l1.trustrpc",
 		Usage:   "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
@@ -107,11 +163,22 @@ var (
 		Usage:   "Run the specified client program as a separate process detached from the host. Default is to run the client program in the host process.",
 		EnvVars: prefixEnvVars("EXEC"),
 	}
+	DerivationLog = &cli.StringFlag{
+		Name:      "derivation.log",
+		Usage:     "Path of a file to write the derivation decisions of the client program to, as JSON lines. Includes the batches accepted and dropped with the reason, channel assembly and L1 origin advancement.",
+		EnvVars:   prefixEnvVars("DERIVATION_LOG"),
+		TakesFile: true,
+	}
 	Server = &cli.BoolFlag{
 		Name:    "server",
 		Usage:   "Run in pre-image server mode without executing any client program.",
 		EnvVars: prefixEnvVars("SERVER"),
 	}
+	Interop = &cli.BoolFlag{
+		Name:    "interop",
+		Usage:   "Validate a super root transition of an interop dependency set instead of a single chain output root (experimental)",
+		EnvVars: prefixEnvVars("INTEROP"),
+	}
 )
 
 // Flags contains the list of configuration options available to the binary.
@@ -119,46 +186,84 @@ var Flags []cli.Flag
 
 var requiredFlags = []cli.Flag{
 	L1Head,
+	L2Claim,
+}
+
+// singleChainRequiredFlags are only required when interop mode is disabled.
+var singleChainRequiredFlags = []cli.Flag{
 	L2Head,
 	L2OutputRoot,
-	L2Claim,
 	L2BlockNumber,
 }
 
+// interopRequiredFlags are only required when interop mode is enabled.
+var interopRequiredFlags = []cli.Flag{
+	L2AgreedPrestate,
+	L2Timestamp,
+}
+
 var programFlags = []cli.Flag{
 	RollupConfig,
 	Network,
+	ConfigBundle,
+	ConfigBundleHash,
 	DataDir,
 	DataFormat,
+	CacheDir,
+	CacheSize,
+	PrefetchConcurrency,
 	L2NodeAddr,
 	L2GenesisPath,
 	L1NodeAddr,
 	L1BeaconAddr,
+	L1FallbackAddrs,
+	L1BeaconFallbackAddrs,
+	L2FallbackAddrs,
+	AltDAServerAddr,
 	L1TrustRPC,
 	L1RPCProviderKind,
 	Exec,
+	DerivationLog,
 	Server,
+	Interop,
 }
 
 func init() {
 	Flags = append(Flags, oplog.CLIFlags(EnvVarPrefix)...)
 	Flags = append(Flags, requiredFlags...)
+	Flags = append(Flags, singleChainRequiredFlags...)
+	Flags = append(Flags, interopRequiredFlags...)
 	Flags = append(Flags, programFlags...)
 }
 
 func CheckRequired(ctx *cli.Context) error {
-	rollupConfig := ctx.String(RollupConfig.Name)
-	network := ctx.String(Network.Name)
-	if rollupConfig == "" && network == "" {
-		return fmt.Errorf("flag %s or %s is required", RollupConfig.Name, Network.Name)
-	}
-	if rollupConfig != "" && network != "" {
-		return fmt.Errorf("cannot specify both %s and %s", RollupConfig.Name, Network.Name)
+	rollupConfigs := ctx.StringSlice(RollupConfig.Name)
+	networks := ctx.StringSlice(Network.Name)
+	if ctx.String(ConfigBundle.Name) != "" {
+		if len(rollupConfigs) != 0 || len(networks) != 0 || len(ctx.StringSlice(L2GenesisPath.Name)) != 0 {
+			return fmt.Errorf("cannot specify %s with %s, %s or %s", ConfigBundle.Name, RollupConfig.Name, Network.Name, L2GenesisPath.Name)
+		}
+		if ctx.String(ConfigBundleHash.Name) == "" {
+			return fmt.Errorf("flag %s is required with %s", ConfigBundleHash.Name, ConfigBundle.Name)
+		}
+	} else {
+		if len(rollupConfigs) == 0 && len(networks) == 0 {
+			return fmt.Errorf("flag %s, %s or %s is required", RollupConfig.Name, Network.Name, ConfigBundle.Name)
+		}
+		if len(rollupConfigs) != 0 && len(networks) != 0 {
+			return fmt.Errorf("cannot specify both %s and %s", RollupConfig.Name, Network.Name)
+		}
+		if len(networks) == 0 && len(ctx.StringSlice(L2GenesisPath.Name)) == 0 {
+			return fmt.Errorf("flag %s is required for custom networks", L2GenesisPath.Name)
+		}
 	}
-	if network == "" && ctx.String(L2GenesisPath.Name) == "" {
-		return fmt.Errorf("flag %s is required for custom networks", L2GenesisPath.Name)
+	required := append([]cli.Flag{}, requiredFlags...)
+	if ctx.Bool(Interop.Name) {
+		required = append(required, interopRequiredFlags...)
+	} else {
+		required = append(required, singleChainRequiredFlags...)
 	}
-	for _, flag := range requiredFlags {
+	for _, flag := range required {
 		if !ctx.IsSet(flag.Names()[0]) {
 			return fmt.Errorf("flag %s is required", flag.Names()[0])
 		}
@@ -8,10 +8,15 @@ import (
 	"io/fs"
 	"os"
 	"os/exec"
+	"slices"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	cl "github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/client/decisionlog"
+	interopTypes "github.com/ethereum-optimism/optimism/op-program/client/interop/types"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
@@ -19,8 +24,11 @@ import (
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/ctxinterrupt"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -52,7 +60,9 @@ func Main(logger log.Logger, cfg *config.Config) error {
 		return fmt.Errorf("invalid config: %w", err)
 	}
 	opservice.ValidateEnvVars(flags.EnvVarPrefix, flags.Flags, logger)
-	cfg.Rollup.LogDescription(logger, chaincfg.L2ChainIDToNetworkDisplayName)
+	for _, rollupCfg := range cfg.Rollups {
+		rollupCfg.LogDescription(logger, chaincfg.L2ChainIDToNetworkDisplayName)
+	}
 
 	hostCtx, stop := ctxinterrupt.WithSignalWaiter(context.Background())
 	defer stop()
@@ -120,6 +130,12 @@ func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Confi
 	var cmd *exec.Cmd
 	if cfg.ExecCmd != "" {
 		cmd = exec.CommandContext(ctx, cfg.ExecCmd)
+		if cfg.InteropEnabled {
+			cmd.Env = append(cmd.Environ(), cl.InteropEnvVar+"=true")
+		}
+		if cfg.DerivationLog != "" {
+			cmd.Env = append(cmd.Environ(), decisionlog.EnvVar+"="+cfg.DerivationLog)
+		}
 		cmd.ExtraFiles = make([]*os.File, cl.MaxFd-3) // not including stdin, stdout and stderr
 		cmd.ExtraFiles[cl.HClientRFd-3] = hClientRW.Reader()
 		cmd.ExtraFiles[cl.HClientWFd-3] = hClientRW.Writer()
@@ -138,7 +154,16 @@ func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Confi
 		logger.Debug("Client program completed successfully")
 		return nil
 	} else {
-		return cl.RunProgram(logger, pClientRW, hClientRW)
+		clientLogger := logger
+		if cfg.DerivationLog != "" {
+			f, err := os.Create(cfg.DerivationLog)
+			if err != nil {
+				return fmt.Errorf("failed to create derivation log: %w", err)
+			}
+			defer f.Close()
+			clientLogger = log.NewLogger(decisionlog.NewHandler(logger.Handler(), f))
+		}
+		return cl.RunProgram(clientLogger, pClientRW, hClientRW, cfg.InteropEnabled)
 	}
 }
 
@@ -151,8 +176,9 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 	var hinterDone chan error
 	logger.Info("Starting preimage server")
 	var kv kvstore.KV
+	var prefetch Prefetcher
 
-	// Close the preimage/hint channels, and then kv store once the server and hinter have exited.
+	// Close the preimage/hint channels, then the prefetcher and kv store once the server and hinter have exited.
 	defer func() {
 		preimageChannel.Close()
 		hintChannel.Close()
@@ -165,6 +191,10 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 			<-hinterDone
 		}
 
+		// Stop any prefetches still writing to the kv store
+		if closer, ok := prefetch.(io.Closer); ok {
+			_ = closer.Close()
+		}
 		if kv != nil {
 			kv.Close()
 		}
@@ -183,12 +213,27 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 		}
 		kv = store
 	}
+	if cfg.CacheDir != "" {
+		cache, err := kvstore.NewDiskCache(logger, cfg.CacheDir, cfg.CacheSize)
+		if err != nil {
+			return fmt.Errorf("creating pre-image cache: %w", err)
+		}
+		kv = kvstore.NewCachedKV(kv, cache)
+	}
+	if len(cfg.ConfigBundle) > 0 {
+		// The client reads the config bundle as a keccak256 pre-image. Storing it ensures it is also available offline.
+		key := preimage.Keccak256Key(crypto.Keccak256Hash(cfg.ConfigBundle)).PreimageKey()
+		if err := kv.Put(key, cfg.ConfigBundle); err != nil {
+			return fmt.Errorf("storing config bundle: %w", err)
+		}
+	}
 
 	var (
 		getPreimage kvstore.PreimageSource
 		hinter      preimage.HintHandler
+		err         error
 	)
-	prefetch, err := prefetcherCreator(ctx, logger, kv, cfg)
+	prefetch, err = prefetcherCreator(ctx, logger, kv, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create prefetcher: %w", err)
 	}
@@ -229,32 +274,137 @@ func makeDefaultPrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV
 	if !cfg.FetchingEnabled() {
 		return nil, nil
 	}
-	logger.Info("Connecting to L1 node", "l1", cfg.L1URL)
-	l1RPC, err := client.NewRPC(ctx, logger, cfg.L1URL, client.WithDialBackoff(10))
-	if err != nil {
-		return nil, fmt.Errorf("failed to setup L1 RPC: %w", err)
+	var l1Sources []prefetcher.NamedL1Source
+	for _, l1URL := range append([]string{cfg.L1URL}, cfg.L1FallbackURLs...) {
+		logger.Info("Connecting to L1 node", "l1", l1URL)
+		l1RPC, err := client.NewRPC(ctx, logger, l1URL, client.WithDialBackoff(10))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup L1 RPC: %w", err)
+		}
+		l1ClCfg := sources.L1ClientDefaultConfig(cfg.Rollups[0], cfg.L1TrustRPC, cfg.L1RPCKind)
+		l1Cl, err := sources.NewL1Client(l1RPC, logger, nil, l1ClCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create L1 client: %w", err)
+		}
+		l1Sources = append(l1Sources, prefetcher.NamedL1Source{Name: l1URL, Source: l1Cl})
+	}
+	var l1BlobSources []prefetcher.NamedL1BlobSource
+	for _, beaconURL := range append([]string{cfg.L1BeaconURL}, cfg.L1BeaconFallbackURLs...) {
+		l1Beacon := sources.NewBeaconHTTPClient(client.NewBasicHTTPClient(beaconURL, logger))
+		l1BlobFetcher := sources.NewL1BeaconClient(l1Beacon, sources.L1BeaconClientConfig{FetchAllSidecars: false})
+		l1BlobSources = append(l1BlobSources, prefetcher.NamedL1BlobSource{Name: beaconURL, Source: l1BlobFetcher})
 	}
+	l1Source := prefetcher.NewFallbackL1Source(logger, l1Sources)
+	l1BlobSource := prefetcher.NewFallbackL1BlobSource(logger, l1BlobSources)
 
-	logger.Info("Connecting to L2 node", "l2", cfg.L2URL)
-	l2RPC, err := client.NewRPC(ctx, logger, cfg.L2URL, client.WithDialBackoff(10))
-	if err != nil {
-		return nil, fmt.Errorf("failed to setup L2 RPC: %w", err)
+	if !cfg.InteropEnabled {
+		var l2Sources []prefetcher.NamedL2Source
+		for _, l2URL := range append([]string{cfg.L2URLs[0]}, cfg.L2FallbackURLs...) {
+			logger.Info("Connecting to L2 node", "l2", l2URL)
+			l2RPC, err := client.NewRPC(ctx, logger, l2URL, client.WithDialBackoff(10))
+			if err != nil {
+				return nil, fmt.Errorf("failed to setup L2 RPC: %w", err)
+			}
+			l2ClCfg := sources.L2ClientDefaultConfig(cfg.Rollups[0], true)
+			l2Cl, err := NewL2Client(l2RPC, logger, nil, &L2ClientConfig{L2ClientConfig: l2ClCfg, L2Head: cfg.L2Head})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create L2 client: %w", err)
+			}
+			l2DebugCl := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
+			l2Sources = append(l2Sources, prefetcher.NamedL2Source{Name: l2URL, Source: l2DebugCl})
+		}
+		p := prefetcher.NewPrefetcher(logger, l1Source, l1BlobSource, prefetcher.NewFallbackL2Source(logger, l2Sources), kv)
+		setAltDASource(logger, p, cfg)
+		p.SetSpeculativePrefetch(int(cfg.PrefetchConcurrency))
+		return p, nil
 	}
 
-	l1ClCfg := sources.L1ClientDefaultConfig(cfg.Rollup, cfg.L1TrustRPC, cfg.L1RPCKind)
-	l2ClCfg := sources.L2ClientDefaultConfig(cfg.Rollup, true)
-	l1Cl, err := sources.NewL1Client(l1RPC, logger, nil, l1ClCfg)
+	super, err := agreedSuperRoot(cfg.AgreedPrestate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create L1 client: %w", err)
+		return nil, err
+	}
+	// Primary URLs come first so they are preferred over fallbacks for the same chain.
+	l2SourcesByChain := make(map[uint64][]prefetcher.NamedL2Source)
+	for _, l2URL := range append(slices.Clone(cfg.L2URLs), cfg.L2FallbackURLs...) {
+		logger.Info("Connecting to L2 node", "l2", l2URL)
+		l2RPC, err := client.NewRPC(ctx, logger, l2URL, client.WithDialBackoff(10))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup L2 RPC: %w", err)
+		}
+		var chainID hexutil.Big
+		if err := l2RPC.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+			return nil, fmt.Errorf("failed to load chain ID from L2 RPC %v: %w", l2URL, err)
+		}
+		rollupCfg, err := rollupConfigForChain(cfg, chainID.ToInt().Uint64())
+		if err != nil {
+			return nil, err
+		}
+		l2ClCfg := sources.L2ClientDefaultConfig(rollupCfg, true)
+		l2Cl, err := NewL2Client(l2RPC, logger, nil, &L2ClientConfig{L2ClientConfig: l2ClCfg})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create L2 client: %w", err)
+		}
+		// Outputs are only requested for the agreed super root, so use the block at its timestamp as the L2 head.
+		blockNum, err := rollupCfg.TargetBlockNumber(super.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("no block at agreed timestamp %v for chain %v: %w", super.Timestamp, rollupCfg.L2ChainID, err)
+		}
+		head, err := l2Cl.L2BlockRefByNumber(ctx, blockNum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load agreed block %v of chain %v: %w", blockNum, rollupCfg.L2ChainID, err)
+		}
+		l2Cl.l2Head = head.Hash
+		chain := rollupCfg.L2ChainID.Uint64()
+		l2Source := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
+		l2SourcesByChain[chain] = append(l2SourcesByChain[chain], prefetcher.NamedL2Source{Name: l2URL, Source: l2Source})
+	}
+	l2Sources := make(map[uint64]prefetcher.L2Source, len(l2SourcesByChain))
+	for chainID, chainSources := range l2SourcesByChain {
+		l2Sources[chainID] = prefetcher.NewFallbackL2Source(logger, chainSources)
 	}
-	l1Beacon := sources.NewBeaconHTTPClient(client.NewBasicHTTPClient(cfg.L1BeaconURL, logger))
-	l1BlobFetcher := sources.NewL1BeaconClient(l1Beacon, sources.L1BeaconClientConfig{FetchAllSidecars: false})
-	l2Cl, err := NewL2Client(l2RPC, logger, nil, &L2ClientConfig{L2ClientConfig: l2ClCfg, L2Head: cfg.L2Head})
+	p := prefetcher.NewInteropPrefetcher(logger, l1Source, l1BlobSource, cfg.Rollups[0].L2ChainID.Uint64(), l2Sources, kv, cfg.AgreedPrestate)
+	setAltDASource(logger, p, cfg)
+	p.SetSpeculativePrefetch(int(cfg.PrefetchConcurrency))
+	return p, nil
+}
+
+func setAltDASource(logger log.Logger, p *prefetcher.Prefetcher, cfg *config.Config) {
+	if cfg.AltDAServerURL == "" {
+		return
+	}
+	logger.Info("Using alt-DA server", "url", cfg.AltDAServerURL)
+	// Inputs are verified by the prefetcher so the client doesn't need to verify them.
+	p.SetAltDASource(altda.NewDAClient(cfg.AltDAServerURL, false, false))
+}
+
+// agreedSuperRoot returns the super root that the agreed super root or transition state is based on.
+func agreedSuperRoot(agreedPrestate []byte) (*eth.SuperV1, error) {
+	superRoot := agreedPrestate
+	if len(agreedPrestate) > 0 && agreedPrestate[0] == interopTypes.TransitionStateVersion {
+		state, err := interopTypes.UnmarshalTransitionState(agreedPrestate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid agreed transition state: %w", err)
+		}
+		superRoot = state.SuperRoot
+	}
+	super, err := eth.UnmarshalSuperRoot(superRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create L2 client: %w", err)
+		return nil, fmt.Errorf("invalid agreed super root: %w", err)
+	}
+	superV1, ok := super.(*eth.SuperV1)
+	if !ok {
+		return nil, fmt.Errorf("unsupported super root version %v", super.Version())
+	}
+	return superV1, nil
+}
+
+func rollupConfigForChain(cfg *config.Config, chainID uint64) (*rollup.Config, error) {
+	for _, rollupCfg := range cfg.Rollups {
+		if rollupCfg.L2ChainID.Uint64() == chainID {
+			return rollupCfg, nil
+		}
 	}
-	l2DebugCl := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
-	return prefetcher.NewPrefetcher(logger, l1Cl, l1BlobFetcher, l2DebugCl, kv), nil
+	return nil, fmt.Errorf("no rollup config for L2 chain %v", chainID)
 }
 
 func routeHints(logger log.Logger, hHostRW io.ReadWriter, hinter preimage.HintHandler) chan error {
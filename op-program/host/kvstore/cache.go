@@ -0,0 +1,303 @@
+package kvstore
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const cacheFileExt = ".bin"
+
+type cacheEntry struct {
+	key  common.Hash
+	size int64
+}
+
+// DiskCache is a size-bounded, disk-backed cache of pre-images intended to be shared between program runs.
+// Only pre-images whose key commits to the content hash (keccak256 and sha256) are cached, and values are verified
+// against their key when read so a corrupted or tampered cache can never serve an invalid pre-image.
+// When the total size of cached values exceeds the configured maximum, the least recently used entries are evicted.
+// Recency is persisted via the file modification time so it is preserved across runs.
+// DiskCache is safe for concurrent use with a single instance. Multiple instances may share the same directory
+// as long as the file system supports atomic renames, though each instance only tracks the size of entries it knows
+// about so the directory may temporarily exceed the maximum size.
+type DiskCache struct {
+	sync.Mutex
+	log     log.Logger
+	path    string
+	maxSize int64
+
+	size    int64
+	lru     *list.List // front is most recently used
+	entries map[common.Hash]*list.Element
+}
+
+// NewDiskCache creates a DiskCache storing up to maxSize bytes of pre-images in the given directory.
+// Any entries already present in the directory are loaded, ordered by their last use.
+func NewDiskCache(logger log.Logger, dir string, maxSize uint64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	c := &DiskCache{
+		log:     logger,
+		path:    dir,
+		maxSize: int64(maxSize),
+		lru:     list.New(),
+		entries: make(map[common.Hash]*list.Element),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	logger.Info("Using pre-image cache", "dir", dir, "entries", len(c.entries), "size", c.size, "max", maxSize)
+	c.evict()
+	return c, nil
+}
+
+// load scans the cache directory for existing entries.
+func (c *DiskCache) load() error {
+	type found struct {
+		cacheEntry
+		modTime time.Time
+	}
+	var existing []found
+	err := filepath.WalkDir(c.path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), cacheFileExt) {
+			return nil
+		}
+		key, err := c.keyFromPath(p)
+		if err != nil {
+			c.log.Warn("Ignoring unrecognised file in pre-image cache", "file", p, "err", err)
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		existing = append(existing, found{cacheEntry{key: key, size: info.Size()}, info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load pre-image cache: %w", err)
+	}
+	// Oldest first, so each entry is pushed in front of the less recently used ones.
+	sort.Slice(existing, func(i, j int) bool { return existing[i].modTime.Before(existing[j].modTime) })
+	for _, e := range existing {
+		c.entries[e.key] = c.lru.PushFront(e.cacheEntry)
+		c.size += e.size
+	}
+	return nil
+}
+
+// pathKey returns the file path for the given key, using the same directory layout as directoryKV.
+func (c *DiskCache) pathKey(k common.Hash) string {
+	key := k.String()
+	dir, name := key[2:6], key[6:]
+	return path.Join(c.path, dir, name+cacheFileExt)
+}
+
+func (c *DiskCache) keyFromPath(p string) (common.Hash, error) {
+	dir, name := filepath.Base(filepath.Dir(p)), strings.TrimSuffix(filepath.Base(p), cacheFileExt)
+	hex := "0x" + dir + name
+	if len(dir) != 4 || len(hex) != 2+2*common.HashLength {
+		return common.Hash{}, errors.New("invalid cache file path")
+	}
+	var k common.Hash
+	if err := k.UnmarshalText([]byte(hex)); err != nil {
+		return common.Hash{}, err
+	}
+	return k, nil
+}
+
+// cacheable reports whether the value for the key can be verified from the key alone.
+func cacheable(k common.Hash) bool {
+	switch preimage.KeyType(k[0]) {
+	case preimage.Keccak256KeyType, preimage.Sha256KeyType:
+		return true
+	default:
+		return false
+	}
+}
+
+// verify checks that the value matches the content hash committed to by the key.
+func verify(k common.Hash, v []byte) bool {
+	switch preimage.KeyType(k[0]) {
+	case preimage.Keccak256KeyType:
+		return preimage.Keccak256Key(crypto.Keccak256Hash(v)).PreimageKey() == k
+	case preimage.Sha256KeyType:
+		return preimage.Sha256Key(sha256.Sum256(v)).PreimageKey() == k
+	default:
+		return false
+	}
+}
+
+func (c *DiskCache) Put(k common.Hash, v []byte) error {
+	if !cacheable(k) || int64(len(v)) > c.maxSize {
+		return nil
+	}
+	c.Lock()
+	defer c.Unlock()
+	if elem, ok := c.entries[k]; ok {
+		c.lru.MoveToFront(elem)
+		return nil
+	}
+	f, err := openTempFile(c.path, k.String()+cacheFileExt+".*")
+	if err != nil {
+		return fmt.Errorf("failed to open temp file for cached pre-image %s: %w", k, err)
+	}
+	defer os.Remove(f.Name()) // Clean up the temp file if it doesn't actually get moved into place
+	if _, err := f.Write(v); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write cached pre-image %s to disk: %w", k, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close temp cached pre-image %s file: %w", k, err)
+	}
+	targetFile := c.pathKey(k)
+	if err := os.MkdirAll(path.Dir(targetFile), 0777); err != nil {
+		return fmt.Errorf("failed to create parent directory for cached pre-image %s: %w", k, err)
+	}
+	if err := os.Rename(f.Name(), targetFile); err != nil {
+		return fmt.Errorf("failed to move temp file %v to final destination %v: %w", f.Name(), targetFile, err)
+	}
+	c.entries[k] = c.lru.PushFront(cacheEntry{key: k, size: int64(len(v))})
+	c.size += int64(len(v))
+	c.evict()
+	return nil
+}
+
+func (c *DiskCache) Get(k common.Hash) ([]byte, error) {
+	if !cacheable(k) {
+		return nil, ErrNotFound
+	}
+	c.Lock()
+	defer c.Unlock()
+	targetFile := c.pathKey(k)
+	dat, err := os.ReadFile(targetFile)
+	if errors.Is(err, os.ErrNotExist) {
+		// May have been removed by another instance sharing the directory.
+		c.forget(k)
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cached pre-image %s: %w", k, err)
+	}
+	if !verify(k, dat) {
+		c.log.Warn("Removing invalid pre-image from cache", "key", k)
+		c.remove(k)
+		return nil, ErrNotFound
+	}
+	if elem, ok := c.entries[k]; ok {
+		c.lru.MoveToFront(elem)
+	} else {
+		// Added by another instance sharing the directory.
+		c.entries[k] = c.lru.PushFront(cacheEntry{key: k, size: int64(len(dat))})
+		c.size += int64(len(dat))
+	}
+	now := time.Now()
+	if err := os.Chtimes(targetFile, now, now); err != nil {
+		c.log.Debug("Failed to update cached pre-image access time", "key", k, "err", err)
+	}
+	return dat, nil
+}
+
+// evict removes the least recently used entries until the cache is within its maximum size.
+// The caller must hold the lock.
+func (c *DiskCache) evict() {
+	for c.size > c.maxSize {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			return
+		}
+		c.remove(oldest.Value.(cacheEntry).key)
+	}
+}
+
+// remove deletes the entry from disk and stops tracking it. The caller must hold the lock.
+func (c *DiskCache) remove(k common.Hash) {
+	if err := os.Remove(c.pathKey(k)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.log.Warn("Failed to remove cached pre-image", "key", k, "err", err)
+	}
+	c.forget(k)
+}
+
+// forget stops tracking the entry without touching the disk. The caller must hold the lock.
+func (c *DiskCache) forget(k common.Hash) {
+	elem, ok := c.entries[k]
+	if !ok {
+		return
+	}
+	c.size -= elem.Value.(cacheEntry).size
+	c.lru.Remove(elem)
+	delete(c.entries, k)
+}
+
+func (c *DiskCache) Close() error {
+	return nil
+}
+
+var _ KV = (*DiskCache)(nil)
+
+// cachedKV reads through to a DiskCache when a pre-image is not available in the primary store,
+// and writes newly stored pre-images to both.
+type cachedKV struct {
+	store KV
+	cache *DiskCache
+}
+
+// NewCachedKV wraps store so that pre-images missing from it are served from cache when available
+// and pre-images added to it are also added to cache.
+func NewCachedKV(store KV, cache *DiskCache) KV {
+	return &cachedKV{store: store, cache: cache}
+}
+
+func (c *cachedKV) Put(k common.Hash, v []byte) error {
+	if err := c.store.Put(k, v); err != nil {
+		return err
+	}
+	if err := c.cache.Put(k, v); err != nil {
+		c.cache.log.Warn("Failed to cache pre-image", "key", k, "err", err)
+	}
+	return nil
+}
+
+func (c *cachedKV) Get(k common.Hash) ([]byte, error) {
+	v, err := c.store.Get(k)
+	if !errors.Is(err, ErrNotFound) {
+		return v, err
+	}
+	v, err = c.cache.Get(k)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		// The cache is only an optimisation, so fall back to fetching the pre-image.
+		c.cache.log.Warn("Failed to read pre-image from cache", "key", k, "err", err)
+		return nil, ErrNotFound
+	}
+	// Populate the primary store so the pre-image remains available even if later evicted from the cache.
+	if err := c.store.Put(k, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *cachedKV) Close() error {
+	return errors.Join(c.store.Close(), c.cache.Close())
+}
+
+var _ KV = (*cachedKV)(nil)
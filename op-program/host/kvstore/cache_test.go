@@ -0,0 +1,164 @@
+package kvstore
+
+import (
+	"crypto/sha256"
+	"os"
+	"testing"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func keccakKey(v []byte) common.Hash {
+	return preimage.Keccak256Key(crypto.Keccak256Hash(v)).PreimageKey()
+}
+
+func newTestDiskCache(t *testing.T, dir string, maxSize uint64) *DiskCache {
+	cache, err := NewDiskCache(testlog.Logger(t, log.LevelDebug), dir, maxSize)
+	require.NoError(t, err)
+	return cache
+}
+
+func TestDiskCache(t *testing.T) {
+	t.Run("Roundtrip", func(t *testing.T) {
+		cache := newTestDiskCache(t, t.TempDir(), 1000)
+		val := []byte("hello world")
+		key := keccakKey(val)
+		_, err := cache.Get(key)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, cache.Put(key, val))
+		dat, err := cache.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+	})
+
+	t.Run("Sha256", func(t *testing.T) {
+		cache := newTestDiskCache(t, t.TempDir(), 1000)
+		val := []byte("hello world")
+		key := preimage.Sha256Key(sha256.Sum256(val)).PreimageKey()
+		require.NoError(t, cache.Put(key, val))
+		dat, err := cache.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+	})
+
+	t.Run("IgnoreUnverifiableKeys", func(t *testing.T) {
+		cache := newTestDiskCache(t, t.TempDir(), 1000)
+		val := []byte("blob data")
+		key := preimage.BlobKey(crypto.Keccak256Hash(val)).PreimageKey()
+		require.NoError(t, cache.Put(key, val))
+		_, err := cache.Get(key)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("PersistAcrossInstances", func(t *testing.T) {
+		dir := t.TempDir()
+		val := []byte("hello world")
+		key := keccakKey(val)
+		require.NoError(t, newTestDiskCache(t, dir, 1000).Put(key, val))
+
+		dat, err := newTestDiskCache(t, dir, 1000).Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+	})
+
+	t.Run("RemoveCorruptEntries", func(t *testing.T) {
+		cache := newTestDiskCache(t, t.TempDir(), 1000)
+		val := []byte("hello world")
+		key := keccakKey(val)
+		require.NoError(t, cache.Put(key, val))
+		require.NoError(t, os.WriteFile(cache.pathKey(key), []byte("corrupt"), filePermission))
+
+		_, err := cache.Get(key)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.NoFileExists(t, cache.pathKey(key))
+		require.Zero(t, cache.size)
+	})
+
+	t.Run("EvictLeastRecentlyUsed", func(t *testing.T) {
+		cache := newTestDiskCache(t, t.TempDir(), 30)
+		val1, val2, val3 := []byte("value one 1"), []byte("value two 2"), []byte("value three")
+		key1, key2, key3 := keccakKey(val1), keccakKey(val2), keccakKey(val3)
+		require.NoError(t, cache.Put(key1, val1))
+		require.NoError(t, cache.Put(key2, val2))
+		// Use key1 so key2 becomes the least recently used.
+		_, err := cache.Get(key1)
+		require.NoError(t, err)
+
+		require.NoError(t, cache.Put(key3, val3))
+		_, err = cache.Get(key2)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.NoFileExists(t, cache.pathKey(key2))
+		_, err = cache.Get(key1)
+		require.NoError(t, err)
+		_, err = cache.Get(key3)
+		require.NoError(t, err)
+	})
+
+	t.Run("EvictOnLoad", func(t *testing.T) {
+		dir := t.TempDir()
+		val1, val2 := []byte("value one 1"), []byte("value two 2")
+		key1, key2 := keccakKey(val1), keccakKey(val2)
+		cache := newTestDiskCache(t, dir, 1000)
+		require.NoError(t, cache.Put(key1, val1))
+		require.NoError(t, cache.Put(key2, val2))
+
+		cache = newTestDiskCache(t, dir, 15)
+		require.LessOrEqual(t, cache.size, int64(15))
+		require.Len(t, cache.entries, 1)
+	})
+
+	t.Run("IgnoreValuesLargerThanCache", func(t *testing.T) {
+		cache := newTestDiskCache(t, t.TempDir(), 5)
+		val := []byte("hello world")
+		key := keccakKey(val)
+		require.NoError(t, cache.Put(key, val))
+		_, err := cache.Get(key)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCachedKV(t *testing.T) {
+	val := []byte("hello world")
+	key := keccakKey(val)
+
+	t.Run("WriteThrough", func(t *testing.T) {
+		store := NewMemKV()
+		cache := newTestDiskCache(t, t.TempDir(), 1000)
+		kv := NewCachedKV(store, cache)
+		require.NoError(t, kv.Put(key, val))
+
+		dat, err := store.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+		dat, err = cache.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+	})
+
+	t.Run("ReadFromCache", func(t *testing.T) {
+		store := NewMemKV()
+		cache := newTestDiskCache(t, t.TempDir(), 1000)
+		require.NoError(t, cache.Put(key, val))
+		kv := NewCachedKV(store, cache)
+
+		dat, err := kv.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+		// Should populate the primary store
+		dat, err = store.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, val, dat)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		kv := NewCachedKV(NewMemKV(), newTestDiskCache(t, t.TempDir(), 1000))
+		_, err := kv.Get(key)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -52,6 +52,9 @@ func NewDiskKV(logger log.Logger, dir string, defaultFormat types.DataFormat) (K
 		}
 	} else if err != nil {
 		return nil, err
+	} else if format != defaultFormat {
+		logger.Warn("Using existing disk storage with a different format to the requested format",
+			"datadir", dir, "format", format, "requested", defaultFormat)
 	} else {
 		logger.Info("Using existing disk storage", "datadir", dir, "format", format)
 	}
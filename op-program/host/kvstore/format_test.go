@@ -53,9 +53,16 @@ func TestNewDiskKV(t *testing.T) {
 				require.NoError(t, kv1.Put(hash, value))
 				require.NoError(t, kv1.Close())
 
-				// Should use existing format
+				// Should use existing format, warning if it differs from the specified format
+				logger, logs := testlog.CaptureLogger(t, log.LevelWarn)
 				kv2, err := NewDiskKV(logger, dir, specifiedFormat)
 				require.NoError(t, err)
+				warning := logs.FindLog(testlog.NewLevelFilter(log.LevelWarn), testlog.NewMessageContainsFilter("different format"))
+				if existingFormat == specifiedFormat {
+					require.Nil(t, warning)
+				} else {
+					require.NotNil(t, warning)
+				}
 				actual, err := kv2.Get(hash)
 				require.NoError(t, err)
 				require.Equal(t, value, actual)
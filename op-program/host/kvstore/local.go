@@ -4,9 +4,10 @@ import (
 	"encoding/binary"
 	"encoding/json"
 
-	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/client/boot"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 type LocalPreimageSource struct {
@@ -18,13 +19,14 @@ func NewLocalPreimageSource(config *config.Config) *LocalPreimageSource {
 }
 
 var (
-	l1HeadKey             = client.L1HeadLocalIndex.PreimageKey()
-	l2OutputRootKey       = client.L2OutputRootLocalIndex.PreimageKey()
-	l2ClaimKey            = client.L2ClaimLocalIndex.PreimageKey()
-	l2ClaimBlockNumberKey = client.L2ClaimBlockNumberLocalIndex.PreimageKey()
-	l2ChainIDKey          = client.L2ChainIDLocalIndex.PreimageKey()
-	l2ChainConfigKey      = client.L2ChainConfigLocalIndex.PreimageKey()
-	rollupKey             = client.RollupConfigLocalIndex.PreimageKey()
+	l1HeadKey             = boot.L1HeadLocalIndex.PreimageKey()
+	l2OutputRootKey       = boot.L2OutputRootLocalIndex.PreimageKey()
+	l2ClaimKey            = boot.L2ClaimLocalIndex.PreimageKey()
+	l2ClaimBlockNumberKey = boot.L2ClaimBlockNumberLocalIndex.PreimageKey()
+	l2ChainIDKey          = boot.L2ChainIDLocalIndex.PreimageKey()
+	l2ChainConfigKey      = boot.L2ChainConfigLocalIndex.PreimageKey()
+	rollupKey             = boot.RollupConfigLocalIndex.PreimageKey()
+	configBundleHashKey   = boot.ConfigBundleHashLocalIndex.PreimageKey()
 )
 
 func (s *LocalPreimageSource) Get(key common.Hash) ([]byte, error) {
@@ -41,16 +43,30 @@ func (s *LocalPreimageSource) Get(key common.Hash) ([]byte, error) {
 		// The CustomChainIDIndicator informs the client to rely on the L2ChainConfigKey to
 		// read the chain config. Otherwise, it'll attempt to read a non-existent hardcoded chain config
 		var chainID uint64
-		if s.config.IsCustomChainConfig {
-			chainID = client.CustomChainIDIndicator
+		if len(s.config.ConfigBundle) > 0 {
+			chainID = boot.BundleChainIDIndicator
+		} else if s.config.IsCustomChainConfig {
+			chainID = boot.CustomChainIDIndicator
 		} else {
-			chainID = s.config.L2ChainConfig.ChainID.Uint64()
+			chainID = s.config.L2ChainConfigs[0].ChainID.Uint64()
 		}
 		return binary.BigEndian.AppendUint64(nil, chainID), nil
 	case l2ChainConfigKey:
-		return json.Marshal(s.config.L2ChainConfig)
+		// In interop mode the client reads the configs of all chains in the dependency set.
+		if s.config.InteropEnabled {
+			return json.Marshal(s.config.L2ChainConfigs)
+		}
+		return json.Marshal(s.config.L2ChainConfigs[0])
 	case rollupKey:
-		return json.Marshal(s.config.Rollup)
+		if s.config.InteropEnabled {
+			return json.Marshal(s.config.Rollups)
+		}
+		return json.Marshal(s.config.Rollups[0])
+	case configBundleHashKey:
+		if len(s.config.ConfigBundle) == 0 {
+			return nil, ErrNotFound
+		}
+		return crypto.Keccak256(s.config.ConfigBundle), nil
 	default:
 		return nil, ErrNotFound
 	}
@@ -6,21 +6,24 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/boot"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 )
 
 func TestLocalPreimageSource(t *testing.T) {
 	cfg := &config.Config{
-		Rollup:             chaincfg.Sepolia,
+		Rollups:            []*rollup.Config{chaincfg.Sepolia},
 		L1Head:             common.HexToHash("0x1111"),
 		L2OutputRoot:       common.HexToHash("0x2222"),
 		L2Claim:            common.HexToHash("0x3333"),
 		L2ClaimBlockNumber: 1234,
-		L2ChainConfig:      params.GoerliChainConfig,
+		L2ChainConfigs:     []*params.ChainConfig{params.GoerliChainConfig},
 	}
 	source := NewLocalPreimageSource(cfg)
 	tests := []struct {
@@ -32,9 +35,9 @@ func TestLocalPreimageSource(t *testing.T) {
 		{"L2OutputRoot", l2OutputRootKey, cfg.L2OutputRoot.Bytes()},
 		{"L2Claim", l2ClaimKey, cfg.L2Claim.Bytes()},
 		{"L2ClaimBlockNumber", l2ClaimBlockNumberKey, binary.BigEndian.AppendUint64(nil, cfg.L2ClaimBlockNumber)},
-		{"L2ChainID", l2ChainIDKey, binary.BigEndian.AppendUint64(nil, cfg.L2ChainConfig.ChainID.Uint64())},
-		{"Rollup", rollupKey, asJson(t, cfg.Rollup)},
-		{"ChainConfig", l2ChainConfigKey, asJson(t, cfg.L2ChainConfig)},
+		{"L2ChainID", l2ChainIDKey, binary.BigEndian.AppendUint64(nil, cfg.L2ChainConfigs[0].ChainID.Uint64())},
+		{"Rollup", rollupKey, asJson(t, cfg.Rollups[0])},
+		{"ChainConfig", l2ChainConfigKey, asJson(t, cfg.L2ChainConfigs[0])},
 		{"Unknown", preimage.LocalIndexKey(1000).PreimageKey(), nil},
 	}
 	for _, test := range tests {
@@ -50,6 +53,53 @@ func TestLocalPreimageSource(t *testing.T) {
 	}
 }
 
+func TestLocalPreimageSourceInterop(t *testing.T) {
+	cfg := &config.Config{
+		Rollups:             []*rollup.Config{chaincfg.Mainnet, chaincfg.Sepolia},
+		L2ChainConfigs:      []*params.ChainConfig{params.GoerliChainConfig, params.SepoliaChainConfig},
+		IsCustomChainConfig: true,
+		InteropEnabled:      true,
+	}
+	source := NewLocalPreimageSource(cfg)
+
+	chainID, err := source.Get(l2ChainIDKey)
+	require.NoError(t, err)
+	require.Equal(t, binary.BigEndian.AppendUint64(nil, boot.CustomChainIDIndicator), chainID)
+
+	rollups, err := source.Get(rollupKey)
+	require.NoError(t, err)
+	require.Equal(t, asJson(t, cfg.Rollups), rollups)
+
+	chainConfigs, err := source.Get(l2ChainConfigKey)
+	require.NoError(t, err)
+	require.Equal(t, asJson(t, cfg.L2ChainConfigs), chainConfigs)
+}
+
+func TestLocalPreimageSourceConfigBundle(t *testing.T) {
+	t.Run("NoBundle", func(t *testing.T) {
+		source := NewLocalPreimageSource(&config.Config{})
+		_, err := source.Get(configBundleHashKey)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Bundle", func(t *testing.T) {
+		cfg := &config.Config{
+			Rollups:        []*rollup.Config{chaincfg.Sepolia},
+			L2ChainConfigs: []*params.ChainConfig{params.GoerliChainConfig},
+			ConfigBundle:   []byte(`{"chains":[]}`),
+		}
+		source := NewLocalPreimageSource(cfg)
+
+		chainID, err := source.Get(l2ChainIDKey)
+		require.NoError(t, err)
+		require.Equal(t, binary.BigEndian.AppendUint64(nil, boot.BundleChainIDIndicator), chainID)
+
+		hash, err := source.Get(configBundleHashKey)
+		require.NoError(t, err)
+		require.Equal(t, crypto.Keccak256(cfg.ConfigBundle), hash)
+	})
+}
+
 func asJson(t *testing.T, v any) []byte {
 	d, err := json.Marshal(v)
 	require.NoError(t, err)
@@ -0,0 +1,320 @@
+package prefetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// NamedL1Source is an L1Source identified by a name used when recording which source served a request.
+type NamedL1Source struct {
+	Name   string
+	Source L1Source
+}
+
+// NamedL1BlobSource is an L1BlobSource identified by a name used when recording which source served a request.
+type NamedL1BlobSource struct {
+	Name   string
+	Source L1BlobSource
+}
+
+// NamedL2Source is an L2Source identified by a name used when recording which source served a request.
+type NamedL2Source struct {
+	Name   string
+	Source L2Source
+}
+
+// fetchWithFallback tries each source in order, returning the first result that passes verification.
+// The source that served the request is logged so the origin of fetched pre-images can be traced.
+func fetchWithFallback[T any](logger log.Logger, names []string, desc string, fetch func(i int) (T, error)) (T, error) {
+	var errs []error
+	for i, name := range names {
+		res, err := fetch(i)
+		if err != nil {
+			logger.Warn("Source failed to serve request", "source", name, "request", desc, "err", err)
+			errs = append(errs, fmt.Errorf("%v: %w", name, err))
+			continue
+		}
+		if i == 0 {
+			logger.Debug("Request served by source", "source", name, "request", desc)
+		} else {
+			logger.Info("Request served by fallback source", "source", name, "request", desc)
+		}
+		return res, nil
+	}
+	var empty T
+	return empty, fmt.Errorf("all sources failed to serve %v: %w", desc, errors.Join(errs...))
+}
+
+type infoAndTxs struct {
+	info eth.BlockInfo
+	txs  types.Transactions
+}
+
+type infoAndReceipts struct {
+	info     eth.BlockInfo
+	receipts types.Receipts
+}
+
+// FallbackL1Source serves each request from the first of its sources to return a valid response.
+type FallbackL1Source struct {
+	logger  log.Logger
+	names   []string
+	sources []L1Source
+}
+
+func NewFallbackL1Source(logger log.Logger, sources []NamedL1Source) *FallbackL1Source {
+	s := &FallbackL1Source{logger: logger}
+	for _, source := range sources {
+		s.names = append(s.names, source.Name)
+		s.sources = append(s.sources, source.Source)
+	}
+	return s
+}
+
+func (s *FallbackL1Source) InfoByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, error) {
+	return fetchWithFallback(s.logger, s.names, "l1 info "+blockHash.String(), func(i int) (eth.BlockInfo, error) {
+		info, err := s.sources[i].InfoByHash(ctx, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := verifyHeader(info, blockHash); err != nil {
+			return nil, err
+		}
+		return info, nil
+	})
+}
+
+func (s *FallbackL1Source) InfoAndTxsByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	res, err := fetchWithFallback(s.logger, s.names, "l1 txs "+blockHash.String(), func(i int) (infoAndTxs, error) {
+		info, txs, err := s.sources[i].InfoAndTxsByHash(ctx, blockHash)
+		if err != nil {
+			return infoAndTxs{}, err
+		}
+		if err := verifyTransactions(info, blockHash, txs); err != nil {
+			return infoAndTxs{}, err
+		}
+		return infoAndTxs{info, txs}, nil
+	})
+	return res.info, res.txs, err
+}
+
+func (s *FallbackL1Source) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	res, err := fetchWithFallback(s.logger, s.names, "l1 receipts "+blockHash.String(), func(i int) (infoAndReceipts, error) {
+		info, receipts, err := s.sources[i].FetchReceipts(ctx, blockHash)
+		if err != nil {
+			return infoAndReceipts{}, err
+		}
+		if err := verifyReceipts(info, blockHash, receipts); err != nil {
+			return infoAndReceipts{}, err
+		}
+		return infoAndReceipts{info, receipts}, nil
+	})
+	return res.info, res.receipts, err
+}
+
+var _ L1Source = (*FallbackL1Source)(nil)
+
+// FallbackL1BlobSource serves each request from the first of its sources to return a valid response.
+type FallbackL1BlobSource struct {
+	logger  log.Logger
+	names   []string
+	sources []L1BlobSource
+}
+
+func NewFallbackL1BlobSource(logger log.Logger, sources []NamedL1BlobSource) *FallbackL1BlobSource {
+	s := &FallbackL1BlobSource{logger: logger}
+	for _, source := range sources {
+		s.names = append(s.names, source.Name)
+		s.sources = append(s.sources, source.Source)
+	}
+	return s
+}
+
+func (s *FallbackL1BlobSource) GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error) {
+	desc := fmt.Sprintf("blob sidecars at %v", ref.Time)
+	return fetchWithFallback(s.logger, s.names, desc, func(i int) ([]*eth.BlobSidecar, error) {
+		sidecars, err := s.sources[i].GetBlobSidecars(ctx, ref, hashes)
+		if err != nil {
+			return nil, err
+		}
+		if len(sidecars) != len(hashes) {
+			return nil, fmt.Errorf("expected %v sidecars but got %v", len(hashes), len(sidecars))
+		}
+		for j, sidecar := range sidecars {
+			commitment := kzg4844.Commitment(sidecar.KZGCommitment)
+			if versionedHash := eth.KZGToVersionedHash(commitment); versionedHash != hashes[j].Hash {
+				return nil, fmt.Errorf("sidecar %v has versioned hash %v but expected %v", j, versionedHash, hashes[j].Hash)
+			}
+			if err := eth.VerifyBlobProof(&sidecar.Blob, commitment, kzg4844.Proof(sidecar.KZGProof)); err != nil {
+				return nil, fmt.Errorf("invalid blob proof for sidecar %v: %w", j, err)
+			}
+		}
+		return sidecars, nil
+	})
+}
+
+func (s *FallbackL1BlobSource) GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error) {
+	desc := fmt.Sprintf("blobs at %v", ref.Time)
+	return fetchWithFallback(s.logger, s.names, desc, func(i int) ([]*eth.Blob, error) {
+		blobs, err := s.sources[i].GetBlobs(ctx, ref, hashes)
+		if err != nil {
+			return nil, err
+		}
+		if len(blobs) != len(hashes) {
+			return nil, fmt.Errorf("expected %v blobs but got %v", len(hashes), len(blobs))
+		}
+		for j, blob := range blobs {
+			commitment, err := blob.ComputeKZGCommitment()
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute commitment for blob %v: %w", j, err)
+			}
+			if versionedHash := eth.KZGToVersionedHash(commitment); versionedHash != hashes[j].Hash {
+				return nil, fmt.Errorf("blob %v has versioned hash %v but expected %v", j, versionedHash, hashes[j].Hash)
+			}
+		}
+		return blobs, nil
+	})
+}
+
+var _ L1BlobSource = (*FallbackL1BlobSource)(nil)
+
+// FallbackL2Source serves each request from the first of its sources to return a valid response.
+type FallbackL2Source struct {
+	logger  log.Logger
+	names   []string
+	sources []L2Source
+}
+
+func NewFallbackL2Source(logger log.Logger, sources []NamedL2Source) *FallbackL2Source {
+	s := &FallbackL2Source{logger: logger}
+	for _, source := range sources {
+		s.names = append(s.names, source.Name)
+		s.sources = append(s.sources, source.Source)
+	}
+	return s
+}
+
+func (s *FallbackL2Source) InfoAndTxsByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	res, err := fetchWithFallback(s.logger, s.names, "l2 txs "+blockHash.String(), func(i int) (infoAndTxs, error) {
+		info, txs, err := s.sources[i].InfoAndTxsByHash(ctx, blockHash)
+		if err != nil {
+			return infoAndTxs{}, err
+		}
+		if err := verifyTransactions(info, blockHash, txs); err != nil {
+			return infoAndTxs{}, err
+		}
+		return infoAndTxs{info, txs}, nil
+	})
+	return res.info, res.txs, err
+}
+
+func (s *FallbackL2Source) NodeByHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	return fetchWithFallback(s.logger, s.names, "l2 node "+hash.String(), func(i int) ([]byte, error) {
+		node, err := s.sources[i].NodeByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		return node, verifyKeccak(node, hash)
+	})
+}
+
+func (s *FallbackL2Source) CodeByHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	return fetchWithFallback(s.logger, s.names, "l2 code "+hash.String(), func(i int) ([]byte, error) {
+		code, err := s.sources[i].CodeByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		return code, verifyKeccak(code, hash)
+	})
+}
+
+func (s *FallbackL2Source) OutputByRoot(ctx context.Context, root common.Hash) (eth.Output, error) {
+	return fetchWithFallback(s.logger, s.names, "l2 output "+root.String(), func(i int) (eth.Output, error) {
+		output, err := s.sources[i].OutputByRoot(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if actual := common.Hash(eth.OutputRoot(output)); actual != root {
+			return nil, fmt.Errorf("output has root %v but expected %v", actual, root)
+		}
+		return output, nil
+	})
+}
+
+func (s *FallbackL2Source) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	res, err := fetchWithFallback(s.logger, s.names, "l2 receipts "+blockHash.String(), func(i int) (infoAndReceipts, error) {
+		info, receipts, err := s.sources[i].FetchReceipts(ctx, blockHash)
+		if err != nil {
+			return infoAndReceipts{}, err
+		}
+		if err := verifyReceipts(info, blockHash, receipts); err != nil {
+			return infoAndReceipts{}, err
+		}
+		return infoAndReceipts{info, receipts}, nil
+	})
+	return res.info, res.receipts, err
+}
+
+var _ L2Source = (*FallbackL2Source)(nil)
+
+func verifyKeccak(data []byte, expected common.Hash) error {
+	if actual := crypto.Keccak256Hash(data); actual != expected {
+		return fmt.Errorf("data has hash %v but expected %v", actual, expected)
+	}
+	return nil
+}
+
+// verifyHeader checks the header RLP of info hashes to the expected block hash and returns the decoded header.
+func verifyHeader(info eth.BlockInfo, blockHash common.Hash) (*types.Header, error) {
+	headerRLP, err := info.HeaderRLP()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode header: %w", err)
+	}
+	if err := verifyKeccak(headerRLP, blockHash); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(headerRLP, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode header: %w", err)
+	}
+	return &header, nil
+}
+
+func verifyTransactions(info eth.BlockInfo, blockHash common.Hash, txs types.Transactions) error {
+	header, err := verifyHeader(info, blockHash)
+	if err != nil {
+		return err
+	}
+	opaqueTxs, err := eth.EncodeTransactions(txs)
+	if err != nil {
+		return fmt.Errorf("failed to encode transactions: %w", err)
+	}
+	if root, _ := mpt.WriteTrie(opaqueTxs); root != header.TxHash {
+		return fmt.Errorf("transactions have root %v but header specifies %v", root, header.TxHash)
+	}
+	return nil
+}
+
+func verifyReceipts(info eth.BlockInfo, blockHash common.Hash, receipts types.Receipts) error {
+	if _, err := verifyHeader(info, blockHash); err != nil {
+		return err
+	}
+	opaqueReceipts, err := eth.EncodeReceipts(receipts)
+	if err != nil {
+		return fmt.Errorf("failed to encode receipts: %w", err)
+	}
+	if root, _ := mpt.WriteTrie(opaqueReceipts); root != info.ReceiptHash() {
+		return fmt.Errorf("receipts have root %v but header specifies %v", root, info.ReceiptHash())
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package prefetcher
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+func TestFallbackL1Source(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(123))
+	block, receipts := testutils.RandomBlock(rng, 3)
+	hash := block.Hash()
+	info := eth.BlockToInfo(block)
+	wrongInfo := eth.HeaderBlockInfo(testutils.RandomHeader(rng))
+
+	t.Run("UsePrimary", func(t *testing.T) {
+		source, primary, fallback := createFallbackL1Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectInfoByHash(hash, info, nil)
+
+		result, err := source.InfoByHash(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, info, result)
+	})
+
+	t.Run("FallbackOnError", func(t *testing.T) {
+		source, primary, fallback := createFallbackL1Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectInfoAndTxsByHash(hash, wrongInfo, nil, errors.New("boom"))
+		fallback.ExpectInfoAndTxsByHash(hash, info, block.Transactions(), nil)
+
+		resultInfo, resultTxs, err := source.InfoAndTxsByHash(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, info, resultInfo)
+		require.Equal(t, block.Transactions(), resultTxs)
+	})
+
+	t.Run("FallbackOnWrongHeader", func(t *testing.T) {
+		source, primary, fallback := createFallbackL1Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectInfoByHash(hash, wrongInfo, nil)
+		fallback.ExpectInfoByHash(hash, info, nil)
+
+		result, err := source.InfoByHash(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, info, result)
+	})
+
+	t.Run("FallbackOnWrongTransactions", func(t *testing.T) {
+		source, primary, fallback := createFallbackL1Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectInfoAndTxsByHash(hash, info, block.Transactions()[1:], nil)
+		fallback.ExpectInfoAndTxsByHash(hash, info, block.Transactions(), nil)
+
+		_, resultTxs, err := source.InfoAndTxsByHash(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, block.Transactions(), resultTxs)
+	})
+
+	t.Run("FallbackOnWrongReceipts", func(t *testing.T) {
+		source, primary, fallback := createFallbackL1Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectFetchReceipts(hash, info, types.Receipts(receipts[1:]), nil)
+		fallback.ExpectFetchReceipts(hash, info, types.Receipts(receipts), nil)
+
+		_, resultReceipts, err := source.FetchReceipts(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, types.Receipts(receipts), resultReceipts)
+	})
+
+	t.Run("AllSourcesFail", func(t *testing.T) {
+		source, primary, fallback := createFallbackL1Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectInfoByHash(hash, wrongInfo, errors.New("boom"))
+		fallback.ExpectInfoByHash(hash, wrongInfo, nil)
+
+		_, err := source.InfoByHash(ctx, hash)
+		require.ErrorContains(t, err, "primary: boom")
+		require.ErrorContains(t, err, "fallback: invalid header")
+	})
+}
+
+func createFallbackL1Source(t *testing.T) (*FallbackL1Source, *testutils.MockL1Source, *testutils.MockL1Source) {
+	logger := testlog.Logger(t, log.LevelDebug)
+	primary := &testutils.MockL1Source{}
+	fallback := &testutils.MockL1Source{}
+	source := NewFallbackL1Source(logger, []NamedL1Source{
+		{Name: "primary", Source: primary},
+		{Name: "fallback", Source: fallback},
+	})
+	return source, primary, fallback
+}
+
+func TestFallbackL2Source(t *testing.T) {
+	ctx := context.Background()
+	node := []byte{1, 2, 3}
+	hash := crypto.Keccak256Hash(node)
+	output := &eth.OutputV0{StateRoot: eth.Bytes32{0xaa}, BlockHash: common.Hash{0xbb}}
+	outputRoot := common.Hash(eth.OutputRoot(output))
+
+	t.Run("FallbackOnWrongNode", func(t *testing.T) {
+		source, primary, fallback := createFallbackL2Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectNodeByHash(hash, []byte{4, 5, 6}, nil)
+		fallback.ExpectNodeByHash(hash, node, nil)
+
+		result, err := source.NodeByHash(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, node, result)
+	})
+
+	t.Run("FallbackOnCodeError", func(t *testing.T) {
+		source, primary, fallback := createFallbackL2Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectCodeByHash(hash, nil, errors.New("boom"))
+		fallback.ExpectCodeByHash(hash, node, nil)
+
+		result, err := source.CodeByHash(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, node, result)
+	})
+
+	t.Run("FallbackOnWrongOutput", func(t *testing.T) {
+		source, primary, fallback := createFallbackL2Source(t)
+		defer primary.AssertExpectations(t)
+		defer fallback.AssertExpectations(t)
+		primary.ExpectOutputByRoot(outputRoot, &eth.OutputV0{StateRoot: eth.Bytes32{0xcc}}, nil)
+		fallback.ExpectOutputByRoot(outputRoot, output, nil)
+
+		result, err := source.OutputByRoot(ctx, outputRoot)
+		require.NoError(t, err)
+		require.Equal(t, output, result)
+	})
+}
+
+func createFallbackL2Source(t *testing.T) (*FallbackL2Source, *MockL2Source, *MockL2Source) {
+	logger := testlog.Logger(t, log.LevelDebug)
+	primary := &MockL2Source{}
+	fallback := &MockL2Source{}
+	source := NewFallbackL2Source(logger, []NamedL2Source{
+		{Name: "primary", Source: primary},
+		{Name: "fallback", Source: fallback},
+	})
+	return source, primary, fallback
+}
@@ -8,7 +8,9 @@ import (
 	"slices"
 	"strings"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/interop"
 	"github.com/ethereum-optimism/optimism/op-program/client/l1"
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
@@ -26,6 +28,8 @@ import (
 var (
 	precompileSuccess = [1]byte{1}
 	precompileFailure = [1]byte{0}
+	altDAAvailable    = [1]byte{1}
+	altDAUnavailable  = [1]byte{0}
 )
 
 var acceleratedPrecompiles = []common.Address{
@@ -50,25 +54,75 @@ type L2Source interface {
 	NodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
 	CodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
 	OutputByRoot(ctx context.Context, root common.Hash) (eth.Output, error)
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
+}
+
+// AltDASource retrieves alt-DA input data by commitment.
+type AltDASource interface {
+	GetInput(ctx context.Context, comm altda.CommitmentData) ([]byte, error)
 }
 
 type Prefetcher struct {
 	logger        log.Logger
 	l1Fetcher     L1Source
 	l1BlobFetcher L1BlobSource
-	l2Fetcher     L2Source
-	lastHint      string
-	kvStore       kvstore.KV
+	altDAFetcher  AltDASource
+	// l2Fetchers are the L2 sources by chain ID.
+	// L2 hints without a chain ID are served by the source for defaultChainID.
+	l2Fetchers     map[uint64]L2Source
+	defaultChainID uint64
+	agreedPrestate []byte
+	lastHint       string
+	kvStore        kvstore.KV
+	// speculative prefetches pre-images ahead of the client's requests. Disabled if nil.
+	speculative *speculativePrefetcher
 }
 
+// NewPrefetcher creates a Prefetcher for a single L2 chain.
 func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l1BlobFetcher L1BlobSource, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
+	return NewInteropPrefetcher(logger, l1Fetcher, l1BlobFetcher, 0, map[uint64]L2Source{0: l2Fetcher}, kvStore, nil)
+}
+
+// NewInteropPrefetcher creates a Prefetcher for multiple L2 chains, serving L2 hints that specify a chain ID from
+// the source for that chain. The agreedPrestate is served in response to interop.HintAgreedPrestate hints.
+func NewInteropPrefetcher(logger log.Logger, l1Fetcher L1Source, l1BlobFetcher L1BlobSource, defaultChainID uint64, l2Fetchers map[uint64]L2Source, kvStore kvstore.KV, agreedPrestate []byte) *Prefetcher {
+	retryingL2Fetchers := make(map[uint64]L2Source, len(l2Fetchers))
+	for chainID, l2Fetcher := range l2Fetchers {
+		retryingL2Fetchers[chainID] = NewRetryingL2Source(logger, l2Fetcher)
+	}
 	return &Prefetcher{
-		logger:        logger,
-		l1Fetcher:     NewRetryingL1Source(logger, l1Fetcher),
-		l1BlobFetcher: NewRetryingL1BlobSource(logger, l1BlobFetcher),
-		l2Fetcher:     NewRetryingL2Source(logger, l2Fetcher),
-		kvStore:       kvStore,
+		logger:         logger,
+		l1Fetcher:      NewRetryingL1Source(logger, l1Fetcher),
+		l1BlobFetcher:  NewRetryingL1BlobSource(logger, l1BlobFetcher),
+		l2Fetchers:     retryingL2Fetchers,
+		defaultChainID: defaultChainID,
+		agreedPrestate: agreedPrestate,
+		kvStore:        kvStore,
+	}
+}
+
+// SetAltDASource sets the source used to serve alt-DA input hints.
+func (p *Prefetcher) SetAltDASource(source AltDASource) {
+	p.altDAFetcher = NewRetryingAltDASource(p.logger, source)
+}
+
+// SetSpeculativePrefetch enables prefetching the pre-images the client is likely to request next, such as the
+// parent of a requested block, concurrently with the client's requests. At most limit speculative prefetches are
+// in progress at once. A limit of 0 leaves speculative prefetching disabled.
+// Close must be called to stop speculative prefetches before the kv store is closed.
+func (p *Prefetcher) SetSpeculativePrefetch(limit int) {
+	if limit <= 0 {
+		return
+	}
+	p.speculative = newSpeculativePrefetcher(p.logger, limit, p.prefetch)
+}
+
+// Close stops any speculative prefetches in progress.
+func (p *Prefetcher) Close() error {
+	if p.speculative != nil {
+		p.speculative.close()
 	}
+	return nil
 }
 
 func (p *Prefetcher) Hint(hint string) error {
@@ -85,14 +139,20 @@ func (p *Prefetcher) GetPreimage(ctx context.Context, key common.Hash) ([]byte,
 	// before we get to read it.
 	for errors.Is(err, kvstore.ErrNotFound) && p.lastHint != "" {
 		hint := p.lastHint
-		if err := p.prefetch(ctx, hint); err != nil {
-			return nil, fmt.Errorf("prefetch failed: %w", err)
+		// Wait for a speculative prefetch of the hint instead of fetching the same data again.
+		if p.speculative == nil || !p.speculative.await(ctx, hint) {
+			if err := p.prefetch(ctx, hint); err != nil {
+				return nil, fmt.Errorf("prefetch failed: %w", err)
+			}
 		}
 		pre, err = p.kvStore.Get(key)
 		if err != nil {
 			p.logger.Error("Fetched pre-images for last hint but did not find required key", "hint", hint, "key", key)
 		}
 	}
+	if err == nil && p.speculative != nil && p.lastHint != "" {
+		p.speculative.expand(p.lastHint, p.relatedHints)
+	}
 	return pre, err
 }
 
@@ -235,12 +295,40 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 			return err
 		}
 		return p.kvStore.Put(preimage.PrecompileKey(inputHash).PreimageKey(), result)
+	case l1.HintAltDAInput:
+		if p.altDAFetcher == nil {
+			return errors.New("alt-DA input requested but no alt-DA server configured")
+		}
+		comm, err := altda.DecodeCommitmentData(hintBytes)
+		if err != nil {
+			return fmt.Errorf("invalid alt-DA input hint %x: %w", hint, err)
+		}
+		// Only keccak256 commitments can be verified by the client via the pre-image key.
+		keccakComm, ok := comm.(altda.Keccak256Commitment)
+		if !ok {
+			return fmt.Errorf("unsupported alt-DA commitment type: %v", comm.CommitmentType())
+		}
+		availabilityKey := preimage.GlobalGenericKey(crypto.Keccak256Hash(hintBytes)).PreimageKey()
+		input, err := p.altDAFetcher.GetInput(ctx, comm)
+		if errors.Is(err, altda.ErrNotFound) {
+			p.logger.Warn("Alt-DA input not available", "commitment", comm)
+			return p.kvStore.Put(availabilityKey, altDAUnavailable[:])
+		} else if err != nil {
+			return fmt.Errorf("failed to fetch alt-DA input %v: %w", comm, err)
+		}
+		if err := comm.Verify(input); err != nil {
+			return fmt.Errorf("invalid alt-DA input for %v: %w", comm, err)
+		}
+		if err := p.kvStore.Put(preimage.Keccak256Key(common.BytesToHash(keccakComm)).PreimageKey(), input); err != nil {
+			return err
+		}
+		return p.kvStore.Put(availabilityKey, altDAAvailable[:])
 	case l2.HintL2BlockHeader, l2.HintL2Transactions:
-		if len(hintBytes) != 32 {
-			return fmt.Errorf("invalid L2 header/tx hint: %x", hint)
+		hash, l2Fetcher, err := p.l2HashAndSource(hintBytes)
+		if err != nil {
+			return fmt.Errorf("invalid L2 header/tx hint %x: %w", hint, err)
 		}
-		hash := common.Hash(hintBytes)
-		header, txs, err := p.l2Fetcher.InfoAndTxsByHash(ctx, hash)
+		header, txs, err := l2Fetcher.InfoAndTxsByHash(ctx, hash)
 		if err != nil {
 			return fmt.Errorf("failed to fetch L2 block %s: %w", hash, err)
 		}
@@ -253,40 +341,74 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 			return err
 		}
 		return p.storeTransactions(txs)
+	case l2.HintL2Receipts:
+		hash, l2Fetcher, err := p.l2HashAndSource(hintBytes)
+		if err != nil {
+			return fmt.Errorf("invalid L2 receipts hint %x: %w", hint, err)
+		}
+		_, receipts, err := l2Fetcher.FetchReceipts(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("failed to fetch L2 block %s receipts: %w", hash, err)
+		}
+		return p.storeReceipts(receipts)
 	case l2.HintL2StateNode:
-		if len(hintBytes) != 32 {
-			return fmt.Errorf("invalid L2 state node hint: %x", hint)
+		hash, l2Fetcher, err := p.l2HashAndSource(hintBytes)
+		if err != nil {
+			return fmt.Errorf("invalid L2 state node hint %x: %w", hint, err)
 		}
-		hash := common.Hash(hintBytes)
-		node, err := p.l2Fetcher.NodeByHash(ctx, hash)
+		node, err := l2Fetcher.NodeByHash(ctx, hash)
 		if err != nil {
 			return fmt.Errorf("failed to fetch L2 state node %s: %w", hash, err)
 		}
 		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), node)
 	case l2.HintL2Code:
-		if len(hintBytes) != 32 {
-			return fmt.Errorf("invalid L2 code hint: %x", hint)
+		hash, l2Fetcher, err := p.l2HashAndSource(hintBytes)
+		if err != nil {
+			return fmt.Errorf("invalid L2 code hint %x: %w", hint, err)
 		}
-		hash := common.Hash(hintBytes)
-		code, err := p.l2Fetcher.CodeByHash(ctx, hash)
+		code, err := l2Fetcher.CodeByHash(ctx, hash)
 		if err != nil {
 			return fmt.Errorf("failed to fetch L2 contract code %s: %w", hash, err)
 		}
 		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), code)
 	case l2.HintL2Output:
-		if len(hintBytes) != 32 {
-			return fmt.Errorf("invalid L2 output hint: %x", hint)
+		hash, l2Fetcher, err := p.l2HashAndSource(hintBytes)
+		if err != nil {
+			return fmt.Errorf("invalid L2 output hint %x: %w", hint, err)
 		}
-		hash := common.Hash(hintBytes)
-		output, err := p.l2Fetcher.OutputByRoot(ctx, hash)
+		output, err := l2Fetcher.OutputByRoot(ctx, hash)
 		if err != nil {
 			return fmt.Errorf("failed to fetch L2 output root %s: %w", hash, err)
 		}
 		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), output.Marshal())
+	case interop.HintAgreedPrestate:
+		if len(p.agreedPrestate) == 0 {
+			return errors.New("no agreed prestate available")
+		}
+		hash := crypto.Keccak256Hash(p.agreedPrestate)
+		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), p.agreedPrestate)
 	}
 	return fmt.Errorf("unknown hint type: %v", hintType)
 }
 
+// l2HashAndSource parses the data of an L2 hint, which is a hash optionally followed by a big endian uint64 chain ID,
+// and returns the hash and the source for the chain.
+func (p *Prefetcher) l2HashAndSource(hintBytes []byte) (common.Hash, L2Source, error) {
+	chainID := p.defaultChainID
+	switch len(hintBytes) {
+	case 32:
+	case 40:
+		chainID = binary.BigEndian.Uint64(hintBytes[32:])
+	default:
+		return common.Hash{}, nil, fmt.Errorf("invalid length %v", len(hintBytes))
+	}
+	source, ok := p.l2Fetchers[chainID]
+	if !ok {
+		return common.Hash{}, nil, fmt.Errorf("unknown chain ID %v", chainID)
+	}
+	return common.Hash(hintBytes[:32]), source, nil
+}
+
 func (p *Prefetcher) storeReceipts(receipts types.Receipts) error {
 	opaqueReceipts, err := eth.EncodeReceipts(receipts)
 	if err != nil {
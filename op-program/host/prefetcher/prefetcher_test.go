@@ -16,7 +16,9 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/interop"
 	"github.com/ethereum-optimism/optimism/op-program/client/l1"
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
@@ -512,6 +514,144 @@ func TestFetchL2Code(t *testing.T) {
 	})
 }
 
+func TestFetchL2Receipts(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	block, receipts := testutils.RandomBlock(rng, 10)
+	hash := block.Hash()
+
+	t.Run("AlreadyKnown", func(t *testing.T) {
+		prefetcher, _, _, _, kv := createPrefetcher(t)
+		storeBlock(t, kv, block, receipts)
+
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, actualReceipts := oracle.ReceiptsByBlockHash(hash)
+		require.EqualValues(t, block.Header(), result.Header())
+		assertReceiptsEqual(t, receipts, actualReceipts)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		prefetcher, _, _, l2Cl, _ := createPrefetcher(t)
+		l2Cl.ExpectInfoAndTxsByHash(hash, eth.BlockToInfo(block), block.Transactions(), nil)
+		l2Cl.ExpectFetchReceipts(hash, eth.BlockToInfo(block), receipts, nil)
+		defer l2Cl.MockL2Client.AssertExpectations(t)
+
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, actualReceipts := oracle.ReceiptsByBlockHash(hash)
+		require.EqualValues(t, block.Header(), result.Header())
+		assertReceiptsEqual(t, receipts, actualReceipts)
+	})
+}
+
+func TestFetchL2BlockForChain(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	block, _ := testutils.RandomBlock(rng, 10)
+	hash := block.Hash()
+	logger := testlog.Logger(t, log.LevelDebug)
+	chainA := newL2Client()
+	chainB := newL2Client()
+	prefetcher := NewInteropPrefetcher(logger, new(testutils.MockL1Source), new(testutils.MockBlobsFetcher), 10,
+		map[uint64]L2Source{10: chainA, 20: chainB}, kvstore.NewMemKV(), nil)
+
+	chainB.ExpectInfoAndTxsByHash(hash, eth.BlockToInfo(block), block.Transactions(), nil)
+	defer chainA.MockL2Client.AssertExpectations(t)
+	defer chainB.MockL2Client.AssertExpectations(t)
+
+	oracle := l2.NewPreimageOracleForChain(asOracleFn(t, prefetcher), asHinter(t, prefetcher), 20)
+	result := oracle.BlockByHash(hash)
+	require.EqualValues(t, block.Header(), result.Header())
+
+	require.NoError(t, prefetcher.Hint(l2.ChainHint{Inner: l2.BlockHeaderHint(common.Hash{0xaa}), ChainID: 30}.Hint()))
+	_, err := prefetcher.GetPreimage(context.Background(), preimage.Keccak256Key(common.Hash{0xaa}).PreimageKey())
+	require.ErrorContains(t, err, "unknown chain ID 30")
+}
+
+func TestFetchAgreedPrestate(t *testing.T) {
+	prestate := []byte{1, 2, 3, 4, 5}
+	hash := crypto.Keccak256Hash(prestate)
+	key := preimage.Keccak256Key(hash).PreimageKey()
+	logger := testlog.Logger(t, log.LevelDebug)
+
+	t.Run("Available", func(t *testing.T) {
+		prefetcher := NewInteropPrefetcher(logger, new(testutils.MockL1Source), new(testutils.MockBlobsFetcher), 10,
+			map[uint64]L2Source{10: newL2Client()}, kvstore.NewMemKV(), prestate)
+		require.NoError(t, prefetcher.Hint(interop.AgreedPrestateHint(hash).Hint()))
+		result, err := prefetcher.GetPreimage(context.Background(), key)
+		require.NoError(t, err)
+		require.Equal(t, prestate, result)
+	})
+
+	t.Run("NotAvailable", func(t *testing.T) {
+		prefetcher, _, _, _, _ := createPrefetcher(t)
+		require.NoError(t, prefetcher.Hint(interop.AgreedPrestateHint(hash).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorContains(t, err, "no agreed prestate available")
+	})
+}
+
+type stubAltDASource map[string][]byte
+
+func (s stubAltDASource) GetInput(_ context.Context, comm altda.CommitmentData) ([]byte, error) {
+	input, ok := s[string(comm.Encode())]
+	if !ok {
+		return nil, altda.ErrNotFound
+	}
+	return input, nil
+}
+
+func TestFetchAltDAInput(t *testing.T) {
+	input := []byte("alt-DA input")
+	comm := altda.NewKeccak256Commitment(input)
+	key := preimage.Keccak256Key(common.BytesToHash(comm)).PreimageKey()
+
+	t.Run("Unknown", func(t *testing.T) {
+		prefetcher, _, _, _, _ := createPrefetcher(t)
+		prefetcher.SetAltDASource(stubAltDASource{string(comm.Encode()): input})
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, ok := oracle.GetAltDAInput(comm)
+		require.True(t, ok)
+		require.Equal(t, input, result)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		prefetcher, _, _, _, kv := createPrefetcher(t)
+		prefetcher.SetAltDASource(stubAltDASource{})
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, ok := oracle.GetAltDAInput(comm)
+		require.False(t, ok)
+		require.Nil(t, result)
+		_, err := kv.Get(key)
+		require.ErrorIs(t, err, kvstore.ErrNotFound)
+	})
+
+	t.Run("RejectInvalidInput", func(t *testing.T) {
+		prefetcher, _, _, _, _ := createPrefetcher(t)
+		prefetcher.SetAltDASource(stubAltDASource{string(comm.Encode()): []byte("wrong")})
+
+		require.NoError(t, prefetcher.Hint(l1.AltDAInputHint(comm.Encode()).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorIs(t, err, altda.ErrCommitmentMismatch)
+	})
+
+	t.Run("RejectGenericCommitment", func(t *testing.T) {
+		prefetcher, _, _, _, _ := createPrefetcher(t)
+		prefetcher.SetAltDASource(stubAltDASource{})
+
+		require.NoError(t, prefetcher.Hint(l1.AltDAInputHint(altda.NewGenericCommitment([]byte{1}).Encode()).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorContains(t, err, "unsupported alt-DA commitment type")
+	})
+
+	t.Run("NoSourceConfigured", func(t *testing.T) {
+		prefetcher, _, _, _, _ := createPrefetcher(t)
+
+		require.NoError(t, prefetcher.Hint(l1.AltDAInputHint(comm.Encode()).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorContains(t, err, "no alt-DA server configured")
+	})
+}
+
 func TestBadHints(t *testing.T) {
 	prefetcher, _, _, _, kv := createPrefetcher(t)
 	hash := common.Hash{0xad}
@@ -610,16 +750,20 @@ func (m *l2Client) ExpectOutputByRoot(root common.Hash, output eth.Output, err e
 	m.Mock.On("OutputByRoot", root).Once().Return(output, &err)
 }
 
+func newL2Client() *l2Client {
+	return &l2Client{
+		MockL2Client:    new(testutils.MockL2Client),
+		MockDebugClient: new(testutils.MockDebugClient),
+	}
+}
+
 func createPrefetcher(t *testing.T) (*Prefetcher, *testutils.MockL1Source, *testutils.MockBlobsFetcher, *l2Client, kvstore.KV) {
 	logger := testlog.Logger(t, log.LevelDebug)
 	kv := kvstore.NewMemKV()
 
 	l1Source := new(testutils.MockL1Source)
 	l1BlobSource := new(testutils.MockBlobsFetcher)
-	l2Source := &l2Client{
-		MockL2Client:    new(testutils.MockL2Client),
-		MockDebugClient: new(testutils.MockDebugClient),
-	}
+	l2Source := newL2Client()
 
 	prefetcher := NewPrefetcher(logger, l1Source, l1BlobSource, l2Source, kv)
 	return prefetcher, l1Source, l1BlobSource, l2Source, kv
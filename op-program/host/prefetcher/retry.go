@@ -2,8 +2,10 @@ package prefetcher
 
 import (
 	"context"
+	"errors"
 	"math"
 
+	altda "github.com/ethereum-optimism/optimism/op-alt-da"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum/go-ethereum/common"
@@ -95,6 +97,43 @@ func (s *RetryingL1BlobSource) GetBlobs(ctx context.Context, ref eth.L1BlockRef,
 
 var _ L1BlobSource = (*RetryingL1BlobSource)(nil)
 
+type RetryingAltDASource struct {
+	logger   log.Logger
+	source   AltDASource
+	strategy retry.Strategy
+}
+
+func NewRetryingAltDASource(logger log.Logger, source AltDASource) *RetryingAltDASource {
+	return &RetryingAltDASource{
+		logger:   logger,
+		source:   source,
+		strategy: retry.Exponential(),
+	}
+}
+
+// GetInput retries failed requests, except when the input is not found as that is a valid response.
+func (s *RetryingAltDASource) GetInput(ctx context.Context, comm altda.CommitmentData) ([]byte, error) {
+	input, found, err := retry.Do2(ctx, maxAttempts, s.strategy, func() ([]byte, bool, error) {
+		input, err := s.source.GetInput(ctx, comm)
+		if errors.Is(err, altda.ErrNotFound) {
+			return nil, false, nil
+		} else if err != nil {
+			s.logger.Warn("Failed to retrieve alt-DA input", "commitment", comm, "err", err)
+			return nil, false, err
+		}
+		return input, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, altda.ErrNotFound
+	}
+	return input, nil
+}
+
+var _ AltDASource = (*RetryingAltDASource)(nil)
+
 type RetryingL2Source struct {
 	logger   log.Logger
 	source   L2Source
@@ -142,6 +181,16 @@ func (s *RetryingL2Source) OutputByRoot(ctx context.Context, root common.Hash) (
 	})
 }
 
+func (s *RetryingL2Source) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	return retry.Do2(ctx, maxAttempts, s.strategy, func() (eth.BlockInfo, types.Receipts, error) {
+		i, r, err := s.source.FetchReceipts(ctx, blockHash)
+		if err != nil {
+			s.logger.Warn("Failed to fetch l2 receipts", "hash", blockHash, "err", err)
+		}
+		return i, r, err
+	})
+}
+
 func NewRetryingL2Source(logger log.Logger, source L2Source) *RetryingL2Source {
 	return &RetryingL2Source{
 		logger:   logger,
@@ -0,0 +1,222 @@
+package prefetcher
+
+import (
+	"context"
+	"sync"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// speculativePrefetcher prefetches the pre-images of hints that the client is likely to send next, before it
+// requests them. At most limit prefetches are in progress at once.
+// Prefetches started speculatively do not start further speculative prefetches themselves, so the lookahead
+// moves forward as the client sends its hints.
+type speculativePrefetcher struct {
+	logger log.Logger
+	fetch  func(ctx context.Context, hint string) error
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	lock sync.Mutex
+	// expanded is the hints that related hints have been started for.
+	expanded map[string]struct{}
+	// started is the hints that have been prefetched speculatively, including those still in progress.
+	started  map[string]struct{}
+	inflight map[string]*speculativeFetch
+}
+
+type speculativeFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func newSpeculativePrefetcher(logger log.Logger, limit int, fetch func(ctx context.Context, hint string) error) *speculativePrefetcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &speculativePrefetcher{
+		logger:   logger,
+		fetch:    fetch,
+		ctx:      ctx,
+		cancel:   cancel,
+		sem:      make(chan struct{}, limit),
+		expanded: make(map[string]struct{}),
+		started:  make(map[string]struct{}),
+		inflight: make(map[string]*speculativeFetch),
+	}
+}
+
+// expand starts prefetching the hints returned by related, unless hint has already been expanded.
+func (s *speculativePrefetcher) expand(hint string, related func(hint string) []string) {
+	s.lock.Lock()
+	_, ok := s.expanded[hint]
+	s.expanded[hint] = struct{}{}
+	s.lock.Unlock()
+	if ok {
+		return
+	}
+	for _, next := range related(hint) {
+		s.start(next)
+	}
+}
+
+func (s *speculativePrefetcher) start(hint string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.started[hint]; ok || s.ctx.Err() != nil {
+		return
+	}
+	fetch := &speculativeFetch{done: make(chan struct{})}
+	s.started[hint] = struct{}{}
+	s.inflight[hint] = fetch
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.finish(hint, fetch)
+		select {
+		case s.sem <- struct{}{}:
+		case <-s.ctx.Done():
+			fetch.err = s.ctx.Err()
+			return
+		}
+		fetch.err = s.fetch(s.ctx, hint)
+		<-s.sem
+		if fetch.err != nil {
+			// The client will request the pre-image again if it actually needs it.
+			s.logger.Debug("Speculative prefetch failed", "hint", hint, "err", fetch.err)
+		}
+	}()
+}
+
+func (s *speculativePrefetcher) finish(hint string, fetch *speculativeFetch) {
+	s.lock.Lock()
+	delete(s.inflight, hint)
+	s.lock.Unlock()
+	close(fetch.done)
+}
+
+// await waits for a speculative prefetch of hint that is in progress.
+// Returns true if there was such a prefetch and it succeeded.
+func (s *speculativePrefetcher) await(ctx context.Context, hint string) bool {
+	s.lock.Lock()
+	fetch, ok := s.inflight[hint]
+	s.lock.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case <-fetch.done:
+		return fetch.err == nil
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// close stops any speculative prefetches and waits for them to exit.
+func (s *speculativePrefetcher) close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// relatedHints returns the hints the client is likely to send after hint, based on the pre-images already stored
+// for hint:
+//   - the transactions, receipts and parent header of an L1 block
+//   - the parent header and transactions of an L2 block
+//   - the child nodes of an L2 state trie node
+func (p *Prefetcher) relatedHints(hint string) []string {
+	hintType, hintBytes, err := parseHint(hint)
+	if err != nil || len(hintBytes) < 32 {
+		return nil
+	}
+	hash := common.Hash(hintBytes[:32])
+	// L2 hints may be followed by a chain ID, which applies to the related hints too.
+	suffix := hintBytes[32:]
+	l2Hint := func(hintType string, hash common.Hash) string {
+		return hintType + " " + hexutil.Bytes(append(hash.Bytes(), suffix...)).String()
+	}
+	switch hintType {
+	case l1.HintL1BlockHeader:
+		header := p.storedHeader(hash)
+		if header == nil {
+			return nil
+		}
+		return []string{
+			l1.TransactionsHint(hash).Hint(),
+			l1.ReceiptsHint(hash).Hint(),
+			l1.BlockHeaderHint(header.ParentHash).Hint(),
+		}
+	case l2.HintL2BlockHeader, l2.HintL2Transactions:
+		header := p.storedHeader(hash)
+		if header == nil {
+			return nil
+		}
+		return []string{l2Hint(l2.HintL2BlockHeader, header.ParentHash)}
+	case l2.HintL2StateNode:
+		node, err := p.kvStore.Get(preimage.Keccak256Key(hash).PreimageKey())
+		if err != nil {
+			return nil
+		}
+		var hints []string
+		for _, child := range trieNodeChildren(node) {
+			hints = append(hints, l2Hint(l2.HintL2StateNode, child))
+		}
+		return hints
+	}
+	return nil
+}
+
+func (p *Prefetcher) storedHeader(hash common.Hash) *types.Header {
+	data, err := p.kvStore.Get(preimage.Keccak256Key(hash).PreimageKey())
+	if err != nil {
+		return nil
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		return nil
+	}
+	return &header
+}
+
+// trieNodeChildren returns the hashes of the nodes referenced by a branch or extension node.
+// Children small enough to be embedded in the node are not referenced by hash.
+func trieNodeChildren(node []byte) []common.Hash {
+	content, _, err := rlp.SplitList(node)
+	if err != nil {
+		return nil
+	}
+	var items [][]byte
+	for len(content) > 0 {
+		kind, val, rest, err := rlp.Split(content)
+		if err != nil {
+			return nil
+		}
+		if kind == rlp.String {
+			items = append(items, val)
+		} else {
+			items = append(items, nil)
+		}
+		content = rest
+	}
+	var children []common.Hash
+	switch len(items) {
+	case 17:
+		for _, item := range items[:16] {
+			if len(item) == 32 {
+				children = append(children, common.Hash(item))
+			}
+		}
+	case 2:
+		// The first nibble of the compact encoded path distinguishes extension (0, 1) from leaf (2, 3) nodes.
+		if len(items[0]) > 0 && items[0][0]>>4 < 2 && len(items[1]) == 32 {
+			children = append(children, common.Hash(items[1]))
+		}
+	}
+	return children
+}
@@ -0,0 +1,138 @@
+package prefetcher
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+func TestSpeculativePrefetchL1Block(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	parent, _ := testutils.RandomBlock(rng, 1)
+	block, receipts := testutils.RandomBlock(rng, 2)
+	header := block.Header()
+	header.ParentHash = parent.Hash()
+	block = types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: block.Transactions()})
+	hash := block.Hash()
+
+	prefetcher, l1Cl, _, _, kv := createPrefetcher(t)
+	prefetcher.SetSpeculativePrefetch(2)
+	l1Cl.ExpectInfoByHash(hash, eth.BlockToInfo(block), nil)
+	l1Cl.ExpectInfoAndTxsByHash(hash, eth.BlockToInfo(block), block.Transactions(), nil)
+	l1Cl.ExpectFetchReceipts(hash, eth.BlockToInfo(block), receipts, nil)
+	l1Cl.ExpectInfoByHash(parent.Hash(), eth.BlockToInfo(parent), nil)
+	defer l1Cl.AssertExpectations(t)
+
+	require.NoError(t, prefetcher.Hint(l1.BlockHeaderHint(hash).Hint()))
+	_, err := prefetcher.GetPreimage(context.Background(), preimage.Keccak256Key(hash).PreimageKey())
+	require.NoError(t, err)
+
+	// The parent header, transactions and receipts are fetched without being requested.
+	requireEventuallyStored(t, kv, preimage.Keccak256Key(parent.Hash()).PreimageKey())
+	requireEventuallyStored(t, kv, preimage.Keccak256Key(block.TxHash()).PreimageKey())
+	requireEventuallyStored(t, kv, preimage.Keccak256Key(block.ReceiptHash()).PreimageKey())
+	require.NoError(t, prefetcher.Close())
+
+	// Requesting the parent is served from the kv store without fetching again.
+	oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+	require.Equal(t, parent.Hash(), oracle.HeaderByBlockHash(parent.Hash()).Hash())
+}
+
+func TestSpeculativePrefetchL2StateNode(t *testing.T) {
+	child1 := []byte("child one that is longer than thirty two bytes")
+	child2 := []byte("child two that is longer than thirty two bytes")
+	var branch [17]any
+	for i := range branch {
+		branch[i] = []byte{}
+	}
+	branch[3] = crypto.Keccak256(child1)
+	branch[9] = crypto.Keccak256(child2)
+	node, err := rlp.EncodeToBytes(branch)
+	require.NoError(t, err)
+	hash := crypto.Keccak256Hash(node)
+
+	prefetcher, _, _, l2Cl, kv := createPrefetcher(t)
+	prefetcher.SetSpeculativePrefetch(1)
+	defer prefetcher.Close()
+	l2Cl.ExpectNodeByHash(hash, node, nil)
+	l2Cl.ExpectNodeByHash(crypto.Keccak256Hash(child1), child1, nil)
+	l2Cl.ExpectNodeByHash(crypto.Keccak256Hash(child2), child2, nil)
+	defer l2Cl.MockDebugClient.AssertExpectations(t)
+
+	require.NoError(t, prefetcher.Hint(l2.StateNodeHint(hash).Hint()))
+	result, err := prefetcher.GetPreimage(context.Background(), preimage.Keccak256Key(hash).PreimageKey())
+	require.NoError(t, err)
+	require.Equal(t, node, result)
+
+	requireEventuallyStored(t, kv, preimage.Keccak256Key(crypto.Keccak256Hash(child1)).PreimageKey())
+	requireEventuallyStored(t, kv, preimage.Keccak256Key(crypto.Keccak256Hash(child2)).PreimageKey())
+}
+
+func TestSpeculativePrefetchDisabled(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	block, _ := testutils.RandomBlock(rng, 1)
+	hash := block.Hash()
+
+	prefetcher, l1Cl, _, _, _ := createPrefetcher(t)
+	prefetcher.SetSpeculativePrefetch(0)
+	// Only the requested header is fetched.
+	l1Cl.ExpectInfoByHash(hash, eth.BlockToInfo(block), nil)
+	defer l1Cl.AssertExpectations(t)
+
+	require.NoError(t, prefetcher.Hint(l1.BlockHeaderHint(hash).Hint()))
+	_, err := prefetcher.GetPreimage(context.Background(), preimage.Keccak256Key(hash).PreimageKey())
+	require.NoError(t, err)
+	require.NoError(t, prefetcher.Close())
+}
+
+func TestTrieNodeChildren(t *testing.T) {
+	childHash := crypto.Keccak256(common.Hash{0xaa}.Bytes())
+	encode := func(v any) []byte {
+		data, err := rlp.EncodeToBytes(v)
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("Branch", func(t *testing.T) {
+		var branch [17]any
+		for i := range branch {
+			branch[i] = []byte{}
+		}
+		branch[0] = childHash
+		branch[5] = []any{[]byte{0x20}, []byte{1}} // embedded node
+		require.Equal(t, []common.Hash{common.Hash(childHash)}, trieNodeChildren(encode(branch)))
+	})
+
+	t.Run("Extension", func(t *testing.T) {
+		require.Equal(t, []common.Hash{common.Hash(childHash)}, trieNodeChildren(encode([]any{[]byte{0x00, 0x12}, childHash})))
+	})
+
+	t.Run("Leaf", func(t *testing.T) {
+		require.Empty(t, trieNodeChildren(encode([]any{[]byte{0x20, 0x12}, childHash})))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		require.Empty(t, trieNodeChildren([]byte{0x01, 0x02}))
+	})
+}
+
+func requireEventuallyStored(t *testing.T, kv kvstore.KV, key common.Hash) {
+	require.Eventually(t, func() bool {
+		_, err := kv.Get(key)
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+}
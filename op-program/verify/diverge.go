@@ -0,0 +1,279 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum-optimism/optimism/op-program/client/tasks"
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Stages of derivation that a divergent field is attributed to.
+const (
+	// StageChannel covers whether the batch for the block was read from a channel at all.
+	StageChannel = "channel"
+	// StageBatch covers the block inputs decoded from batch data: the L1 origin, sequence number and transactions.
+	StageBatch = "batch"
+	// StageBlock covers the remaining block attributes and the resulting block hash.
+	StageBlock = "block"
+	// StageState covers the results of executing the block.
+	StageState = "state"
+	// StageSafeHead indicates derivation ran out of L1 data before reaching the claimed block.
+	StageSafeHead = "safe-head"
+)
+
+// FieldDiff is a single field that differs between the derived and expected chains.
+type FieldDiff struct {
+	Stage    string `json:"stage"`
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Derived  string `json:"derived"`
+}
+
+// Divergence describes the first L2 block where the chain derived by the program differs from the chain
+// served by the L2 RPC.
+type Divergence struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	Expected    eth.L2BlockRef `json:"expected"`
+	Derived     eth.L2BlockRef `json:"derived"`
+	Diffs       []FieldDiff    `json:"diffs"`
+}
+
+// chainSource is the view of an L2 chain used to locate and describe a divergence.
+type chainSource interface {
+	PayloadByNumber(ctx context.Context, number uint64) (*eth.ExecutionPayloadEnvelope, error)
+	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+}
+
+type outputSource func(ctx context.Context, ref eth.L2BlockRef) (*eth.OutputV0, error)
+
+// offlineChain is the chain derived offline. Blocks and state are loaded lazily from the pre-image store, so the
+// store is kept open until the chain is closed. Missing pre-images panic, matching the behaviour of the client
+// program, so panics are returned as errors.
+type offlineChain struct {
+	engine *l2.OracleEngine
+	kv     kvstore.KV
+}
+
+// deriveOffline re-runs derivation in-process using the pre-images already stored in cfg.DataDir,
+// returning the derived chain so it can be inspected. The chain must be closed once it is no longer used.
+func deriveOffline(logger log.Logger, cfg *config.Config, rollupCfg *rollup.Config, chainCfg *params.ChainConfig) (*offlineChain, error) {
+	kv, err := kvstore.NewDiskKV(logger, cfg.DataDir, cfg.DataFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pre-image store: %w", err)
+	}
+	splitter := kvstore.NewPreimageSourceSplitter(kvstore.NewLocalPreimageSource(cfg).Get, kv.Get)
+	getter := preimage.WithVerification(splitter.Get)
+	oracle := preimage.OracleFn(func(key preimage.Key) []byte {
+		data, err := getter(key.PreimageKey())
+		if err != nil {
+			panic(fmt.Errorf("failed to load pre-image %v: %w", key, err))
+		}
+		return data
+	})
+	hinter := preimage.HinterFn(func(v preimage.Hint) {})
+
+	l1Oracle := l1.NewCachingOracle(l1.NewPreimageOracle(oracle, hinter))
+	l2Oracle := l2.NewCachingOracle(l2.NewPreimageOracle(oracle, hinter))
+	engine, err := runDerivation(logger, cfg, rollupCfg, chainCfg, l1Oracle, l2Oracle)
+	if err != nil {
+		_ = kv.Close()
+		return nil, err
+	}
+	return &offlineChain{engine: engine, kv: kv}, nil
+}
+
+func runDerivation(logger log.Logger, cfg *config.Config, rollupCfg *rollup.Config, chainCfg *params.ChainConfig,
+	l1Oracle l1.Oracle, l2Oracle l2.Oracle) (engine *l2.OracleEngine, err error) {
+	defer recoverPanic(&err)
+	return tasks.RunDerivation(logger, rollupCfg, chainCfg, cfg.L1Head, cfg.L2OutputRoot, cfg.L2ClaimBlockNumber, l1Oracle, l2Oracle)
+}
+
+func (c *offlineChain) PayloadByNumber(ctx context.Context, number uint64) (envelope *eth.ExecutionPayloadEnvelope, err error) {
+	defer recoverPanic(&err)
+	return c.engine.PayloadByNumber(ctx, number)
+}
+
+func (c *offlineChain) L2BlockRefByNumber(ctx context.Context, num uint64) (ref eth.L2BlockRef, err error) {
+	defer recoverPanic(&err)
+	return c.engine.L2BlockRefByNumber(ctx, num)
+}
+
+func (c *offlineChain) L2BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (ref eth.L2BlockRef, err error) {
+	defer recoverPanic(&err)
+	return c.engine.L2BlockRefByLabel(ctx, label)
+}
+
+func (c *offlineChain) Output(_ context.Context, ref eth.L2BlockRef) (output *eth.OutputV0, err error) {
+	defer recoverPanic(&err)
+	return c.engine.L2OutputV0(ref.Number)
+}
+
+func (c *offlineChain) Close() error {
+	return c.kv.Close()
+}
+
+// recoverPanic converts a panic, such as from a missing pre-image, to an error. Must be deferred.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("derivation failed: %v", r)
+	}
+}
+
+// findDivergence locates the first block after agreedBlockNum, up to claimedBlockNum, where the derived chain
+// differs from the expected chain. Block hashes commit to the full history so the search can bisect.
+// Returns nil if the chains match over the entire range.
+func findDivergence(ctx context.Context, derived chainSource, derivedOutput outputSource, derivedSafeHead uint64,
+	expected chainSource, expectedOutput outputSource, agreedBlockNum uint64, claimedBlockNum uint64) (*Divergence, error) {
+	end := min(claimedBlockNum, derivedSafeHead)
+	var searchErr error
+	count := int(end - agreedBlockNum)
+	idx := sort.Search(count, func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		num := agreedBlockNum + 1 + uint64(i)
+		derivedRef, err := derived.L2BlockRefByNumber(ctx, num)
+		if err != nil {
+			searchErr = fmt.Errorf("failed to load derived block %v: %w", num, err)
+			return true
+		}
+		expectedRef, err := expected.L2BlockRefByNumber(ctx, num)
+		if err != nil {
+			searchErr = fmt.Errorf("failed to load expected block %v: %w", num, err)
+			return true
+		}
+		return derivedRef.Hash != expectedRef.Hash
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	if idx == count {
+		if derivedSafeHead >= claimedBlockNum {
+			return nil, nil
+		}
+		return &Divergence{
+			BlockNumber: derivedSafeHead + 1,
+			Diffs: []FieldDiff{{
+				Stage:    StageSafeHead,
+				Field:    "safeHead",
+				Expected: fmt.Sprint(claimedBlockNum),
+				Derived:  fmt.Sprint(derivedSafeHead),
+			}},
+		}, nil
+	}
+	return describeDivergence(ctx, agreedBlockNum+1+uint64(idx), derived, derivedOutput, expected, expectedOutput)
+}
+
+func describeDivergence(ctx context.Context, num uint64, derived chainSource, derivedOutput outputSource, expected chainSource, expectedOutput outputSource) (*Divergence, error) {
+	load := func(src chainSource, outputs outputSource, desc string) (eth.L2BlockRef, *eth.ExecutionPayload, *eth.OutputV0, error) {
+		ref, err := src.L2BlockRefByNumber(ctx, num)
+		if err != nil {
+			return eth.L2BlockRef{}, nil, nil, fmt.Errorf("failed to load %v block ref %v: %w", desc, num, err)
+		}
+		envelope, err := src.PayloadByNumber(ctx, num)
+		if err != nil {
+			return eth.L2BlockRef{}, nil, nil, fmt.Errorf("failed to load %v payload %v: %w", desc, num, err)
+		}
+		output, err := outputs(ctx, ref)
+		if err != nil {
+			return eth.L2BlockRef{}, nil, nil, fmt.Errorf("failed to load %v output %v: %w", desc, num, err)
+		}
+		return ref, envelope.ExecutionPayload, output, nil
+	}
+	derivedRef, derivedPayload, derivedOut, err := load(derived, derivedOutput, "derived")
+	if err != nil {
+		return nil, err
+	}
+	expectedRef, expectedPayload, expectedOut, err := load(expected, expectedOutput, "expected")
+	if err != nil {
+		return nil, err
+	}
+	return &Divergence{
+		BlockNumber: num,
+		Expected:    expectedRef,
+		Derived:     derivedRef,
+		Diffs:       diffBlocks(expectedRef, expectedPayload, expectedOut, derivedRef, derivedPayload, derivedOut),
+	}, nil
+}
+
+// diffBlocks lists the fields that differ between the expected and derived blocks, ordered by derivation stage.
+func diffBlocks(expectedRef eth.L2BlockRef, expected *eth.ExecutionPayload, expectedOut *eth.OutputV0,
+	derivedRef eth.L2BlockRef, derived *eth.ExecutionPayload, derivedOut *eth.OutputV0) []FieldDiff {
+	var diffs []FieldDiff
+	add := func(stage string, field string, expected any, derived any) {
+		e, d := fmt.Sprint(expected), fmt.Sprint(derived)
+		if e != d {
+			diffs = append(diffs, FieldDiff{Stage: stage, Field: field, Expected: e, Derived: d})
+		}
+	}
+
+	// Blocks without a batch only contain deposits, for example because the channel with the batch was dropped
+	// after timing out or including invalid frames. Empty batches are indistinguishable so are reported the same way.
+	add(StageChannel, "hasBatch", hasBatch(expected.Transactions), hasBatch(derived.Transactions))
+
+	add(StageBatch, "l1Origin", expectedRef.L1Origin, derivedRef.L1Origin)
+	add(StageBatch, "sequenceNumber", expectedRef.SequenceNumber, derivedRef.SequenceNumber)
+	add(StageBatch, "transactionCount", len(expected.Transactions), len(derived.Transactions))
+	// Only the first differing transaction is reported as later ones are likely offset by the same difference.
+	for i := 0; i < min(len(expected.Transactions), len(derived.Transactions)); i++ {
+		if !bytes.Equal(expected.Transactions[i], derived.Transactions[i]) {
+			add(StageBatch, fmt.Sprintf("transactions[%d]", i),
+				crypto.Keccak256Hash(expected.Transactions[i]), crypto.Keccak256Hash(derived.Transactions[i]))
+			break
+		}
+	}
+
+	add(StageBlock, "parentHash", expected.ParentHash, derived.ParentHash)
+	add(StageBlock, "timestamp", uint64(expected.Timestamp), uint64(derived.Timestamp))
+	add(StageBlock, "feeRecipient", expected.FeeRecipient, derived.FeeRecipient)
+	add(StageBlock, "prevRandao", expected.PrevRandao, derived.PrevRandao)
+	add(StageBlock, "gasLimit", uint64(expected.GasLimit), uint64(derived.GasLimit))
+	add(StageBlock, "extraData", expected.ExtraData, derived.ExtraData)
+	add(StageBlock, "baseFeePerGas", expected.BaseFeePerGas, derived.BaseFeePerGas)
+	add(StageBlock, "blockHash", expected.BlockHash, derived.BlockHash)
+
+	add(StageState, "gasUsed", uint64(expected.GasUsed), uint64(derived.GasUsed))
+	add(StageState, "receiptsRoot", expected.ReceiptsRoot, derived.ReceiptsRoot)
+	add(StageState, "stateRoot", expectedOut.StateRoot, derivedOut.StateRoot)
+	add(StageState, "messagePasserStorageRoot", expectedOut.MessagePasserStorageRoot, derivedOut.MessagePasserStorageRoot)
+	add(StageState, "outputRoot", eth.OutputRoot(expectedOut), eth.OutputRoot(derivedOut))
+	return diffs
+}
+
+// hasBatch returns true if the block includes transactions from a batch, as opposed to only deposits.
+func hasBatch(txs []eth.Data) bool {
+	for _, tx := range txs {
+		if len(tx) == 0 || tx[0] != types.DepositTxType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDivergence writes the divergence as JSON to the given path, or stdout if the path is "-".
+func writeDivergence(path string, divergence *Divergence) error {
+	data, err := json.MarshalIndent(divergence, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode divergence: %w", err)
+	}
+	if path == "-" {
+		_, err = fmt.Println(string(data))
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,142 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type stubChain struct {
+	refs     map[uint64]eth.L2BlockRef
+	payloads map[uint64]*eth.ExecutionPayload
+	outputs  map[uint64]*eth.OutputV0
+}
+
+// newStubChain creates a chain of blocks [0, length) where the block at forkBlock and all later blocks
+// differ from a chain created with a different forkBlock.
+func newStubChain(length uint64, forkBlock uint64, forkedTx eth.Data) *stubChain {
+	c := &stubChain{
+		refs:     make(map[uint64]eth.L2BlockRef),
+		payloads: make(map[uint64]*eth.ExecutionPayload),
+		outputs:  make(map[uint64]*eth.OutputV0),
+	}
+	for i := uint64(0); i < length; i++ {
+		hash := common.Hash{byte(i), 0x01}
+		txs := []eth.Data{{0x01}, {0x02}}
+		stateRoot := eth.Bytes32{byte(i), 0x02}
+		if i >= forkBlock {
+			hash[2] = 0xff
+			stateRoot[2] = 0xff
+			if i == forkBlock {
+				txs[1] = forkedTx
+			}
+		}
+		c.refs[i] = eth.L2BlockRef{Hash: hash, Number: i}
+		c.payloads[i] = &eth.ExecutionPayload{BlockHash: hash, BlockNumber: eth.Uint64Quantity(i), Transactions: txs}
+		c.outputs[i] = &eth.OutputV0{StateRoot: stateRoot, BlockHash: hash}
+	}
+	return c
+}
+
+func (c *stubChain) PayloadByNumber(_ context.Context, number uint64) (*eth.ExecutionPayloadEnvelope, error) {
+	payload, ok := c.payloads[number]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %v", number)
+	}
+	return &eth.ExecutionPayloadEnvelope{ExecutionPayload: payload}, nil
+}
+
+func (c *stubChain) L2BlockRefByNumber(_ context.Context, num uint64) (eth.L2BlockRef, error) {
+	ref, ok := c.refs[num]
+	if !ok {
+		return eth.L2BlockRef{}, fmt.Errorf("unknown block %v", num)
+	}
+	return ref, nil
+}
+
+func (c *stubChain) Output(_ context.Context, ref eth.L2BlockRef) (*eth.OutputV0, error) {
+	return c.outputs[ref.Number], nil
+}
+
+func TestFindDivergence(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NoDivergence", func(t *testing.T) {
+		expected := newStubChain(20, 100, nil)
+		derived := newStubChain(20, 100, nil)
+		divergence, err := findDivergence(ctx, derived, derived.Output, 19, expected, expected.Output, 5, 19)
+		require.NoError(t, err)
+		require.Nil(t, divergence)
+	})
+
+	t.Run("DivergentTransaction", func(t *testing.T) {
+		expected := newStubChain(20, 100, nil)
+		derived := newStubChain(20, 12, eth.Data{0x03})
+		divergence, err := findDivergence(ctx, derived, derived.Output, 19, expected, expected.Output, 5, 19)
+		require.NoError(t, err)
+		require.NotNil(t, divergence)
+		require.EqualValues(t, 12, divergence.BlockNumber)
+		require.Equal(t, expected.refs[12], divergence.Expected)
+		require.Equal(t, derived.refs[12], divergence.Derived)
+
+		fields := make(map[string]string)
+		for _, diff := range divergence.Diffs {
+			fields[diff.Field] = diff.Stage
+		}
+		require.Equal(t, map[string]string{
+			"transactions[1]": StageBatch,
+			"blockHash":       StageBlock,
+			"stateRoot":       StageState,
+			"outputRoot":      StageState,
+		}, fields)
+	})
+
+	t.Run("MissingBatch", func(t *testing.T) {
+		expected := newStubChain(20, 100, nil)
+		derived := newStubChain(20, 12, eth.Data{0x03})
+		derived.payloads[12].Transactions = []eth.Data{{types.DepositTxType, 0x01}}
+		divergence, err := findDivergence(ctx, derived, derived.Output, 19, expected, expected.Output, 5, 19)
+		require.NoError(t, err)
+		require.EqualValues(t, 12, divergence.BlockNumber)
+		require.Equal(t, FieldDiff{Stage: StageChannel, Field: "hasBatch", Expected: "true", Derived: "false"}, divergence.Diffs[0])
+	})
+
+	t.Run("DivergentFirstBlock", func(t *testing.T) {
+		expected := newStubChain(20, 100, nil)
+		derived := newStubChain(20, 6, eth.Data{0x03})
+		divergence, err := findDivergence(ctx, derived, derived.Output, 19, expected, expected.Output, 5, 19)
+		require.NoError(t, err)
+		require.EqualValues(t, 6, divergence.BlockNumber)
+	})
+
+	t.Run("SafeHeadBeforeClaim", func(t *testing.T) {
+		expected := newStubChain(20, 100, nil)
+		derived := newStubChain(15, 100, nil)
+		divergence, err := findDivergence(ctx, derived, derived.Output, 14, expected, expected.Output, 5, 19)
+		require.NoError(t, err)
+		require.EqualValues(t, 15, divergence.BlockNumber)
+		require.Equal(t, []FieldDiff{{Stage: StageSafeHead, Field: "safeHead", Expected: "19", Derived: "14"}}, divergence.Diffs)
+	})
+
+	t.Run("DivergenceBeforeSafeHead", func(t *testing.T) {
+		expected := newStubChain(20, 100, nil)
+		derived := newStubChain(15, 10, eth.Data{0x03})
+		divergence, err := findDivergence(ctx, derived, derived.Output, 14, expected, expected.Output, 5, 19)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, divergence.BlockNumber)
+	})
+}
+
+func TestRecoverPanic(t *testing.T) {
+	fn := func() (err error) {
+		defer recoverPanic(&err)
+		panic(errors.New("missing pre-image"))
+	}
+	require.ErrorContains(t, fn(), "missing pre-image")
+}
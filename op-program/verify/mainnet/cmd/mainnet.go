@@ -20,6 +20,7 @@ func main() {
 	var l1HashStr string
 	var l2Start uint64
 	var l2End uint64
+	var diffOutput string
 	flag.StringVar(&l1RpcUrl, "l1", "", "L1 RPC URL to use")
 	flag.StringVar(&l1BeaconUrl, "l1.beacon", "", "L1 Beacon URL to use")
 	flag.StringVar(&l1RpcKind, "l1-rpckind", "", "L1 RPC kind")
@@ -29,6 +30,8 @@ func main() {
 	flag.StringVar(&l1HashStr, "l1.head", "", "Hash of L1 block to use")
 	flag.Uint64Var(&l2Start, "l2.start", 0, "Block number of agreed L2 block")
 	flag.Uint64Var(&l2End, "l2.end", 0, "Block number of claimed L2 block")
+	flag.StringVar(&diffOutput, "diff", "",
+		"File to write a JSON description of where derivation diverged from the L2 RPC if the claim is rejected. Use - for stdout.")
 	flag.Parse()
 
 	if l1RpcUrl == "" {
@@ -49,6 +52,9 @@ func main() {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to create runner: %v\n", err.Error())
 		os.Exit(1)
 	}
+	if diffOutput != "" {
+		runner.EnableDivergenceDiff(diffOutput)
+	}
 
 	if l1HashStr == "" && l2Start == 0 && l2End == 0 {
 		err = runner.RunToFinalized(context.Background())
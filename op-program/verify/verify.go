@@ -2,6 +2,7 @@ package verify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-program/client/claim"
 	"github.com/ethereum-optimism/optimism/op-program/host"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-service/client"
@@ -38,6 +40,7 @@ type Runner struct {
 	logCfg      oplog.CLIConfig
 	setupLog    log.Logger
 	rollupCfg   *rollup.Config
+	diffOutput  string
 }
 
 func NewRunner(l1RpcUrl string, l1RpcKind string, l1BeaconUrl string, l2RpcUrl string, dataDir string, network string, chainCfg *params.ChainConfig) (*Runner, error) {
@@ -79,6 +82,12 @@ func NewRunner(l1RpcUrl string, l1RpcKind string, l1BeaconUrl string, l2RpcUrl s
 	}, nil
 }
 
+// EnableDivergenceDiff causes a JSON description of the first divergent L2 block to be written to path when the
+// program rejects a claim taken from the L2 RPC. A path of "-" writes to stdout.
+func (r *Runner) EnableDivergenceDiff(path string) {
+	r.diffOutput = path
+}
+
 func (r *Runner) RunBetweenBlocks(ctx context.Context, l1Head common.Hash, startBlockNum uint64, endBlockNumber uint64) error {
 	if startBlockNum >= endBlockNumber {
 		return fmt.Errorf("start block number %v must be less than end block number %v", startBlockNum, endBlockNumber)
@@ -205,7 +214,7 @@ func (r *Runner) run(l1Head common.Hash, agreedBlockInfo eth.BlockInfo, agreedOu
 	onlineCfg := *offlineCfg
 	onlineCfg.L1URL = r.l1RpcUrl
 	onlineCfg.L1BeaconURL = r.l1BeaconUrl
-	onlineCfg.L2URL = r.l2RpcUrl
+	onlineCfg.L2URLs = []string{r.l2RpcUrl}
 	if r.l1RpcKind != "" {
 		onlineCfg.L1RPCKind = sources.RPCProviderKind(r.l1RpcKind)
 	}
@@ -213,6 +222,11 @@ func (r *Runner) run(l1Head common.Hash, agreedBlockInfo eth.BlockInfo, agreedOu
 	fmt.Println("Running in online mode")
 	err = host.Main(oplog.NewLogger(os.Stderr, r.logCfg), &onlineCfg)
 	if err != nil {
+		if r.diffOutput != "" && errors.Is(err, claim.ErrClaimNotValid) {
+			if diffErr := r.reportDivergence(context.Background(), offlineCfg, agreedBlockInfo.NumberU64()); diffErr != nil {
+				fmt.Printf("Failed to determine divergence: %v\n", diffErr)
+			}
+		}
 		return fmt.Errorf("online mode failed: %w", err)
 	}
 
@@ -224,6 +238,35 @@ func (r *Runner) run(l1Head common.Hash, agreedBlockInfo eth.BlockInfo, agreedOu
 	return nil
 }
 
+// reportDivergence re-derives the chain from the pre-images fetched by the online run and reports the first block
+// that differs from the chain served by the L2 RPC.
+func (r *Runner) reportDivergence(ctx context.Context, cfg *config.Config, agreedBlockNum uint64) error {
+	fmt.Println("Claim rejected, searching for divergence")
+	logger := oplog.NewLogger(os.Stderr, r.logCfg)
+	derived, err := deriveOffline(logger, cfg, r.rollupCfg, r.chainCfg)
+	if err != nil {
+		return err
+	}
+	defer derived.Close()
+	safeHead, err := derived.L2BlockRefByLabel(ctx, eth.Safe)
+	if err != nil {
+		return fmt.Errorf("failed to load derived safe head: %w", err)
+	}
+	expectedOutput := func(ctx context.Context, ref eth.L2BlockRef) (*eth.OutputV0, error) {
+		return r.l2Client.OutputV0AtBlock(ctx, ref.Hash)
+	}
+	divergence, err := findDivergence(ctx, derived, derived.Output, safeHead.Number, r.l2Client, expectedOutput, agreedBlockNum, cfg.L2ClaimBlockNumber)
+	if err != nil {
+		return err
+	}
+	if divergence == nil {
+		fmt.Println("Derived chain matches L2 RPC, no divergence found")
+		return nil
+	}
+	fmt.Printf("Derivation diverged at block %v\n", divergence.BlockNumber)
+	return writeDivergence(r.diffOutput, divergence)
+}
+
 func outputAtBlockNum(ctx context.Context, l2Client *sources.L2Client, blockNum uint64) (eth.BlockInfo, common.Hash, error) {
 	startBlockInfo, err := l2Client.InfoByNumber(ctx, blockNum)
 	if err != nil {
@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -12,18 +13,25 @@ import (
 	"github.com/ethereum-optimism/optimism/packages/contracts-bedrock/snapshots"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 const (
 	methodGameCount   = "gameCount"
 	methodGameAtIndex = "gameAtIndex"
+	methodGameImpls   = "gameImpls"
+	methodGames       = "games"
 	methodInitBonds   = "initBonds"
 	methodCreateGame  = "create"
 	methodVersion     = "version"
 
 	methodClaim = "claimData"
+
+	eventDisputeGameCreated = "DisputeGameCreated"
 )
 
+var ErrEventNotFound = errors.New("event not found")
+
 type gameMetadata struct {
 	GameType  uint32
 	Timestamp time.Time
@@ -90,14 +98,46 @@ func (f *DisputeGameFactory) HasProposedSince(ctx context.Context, proposer comm
 	}
 }
 
-func (f *DisputeGameFactory) ProposalTx(ctx context.Context, gameType uint32, outputRoot common.Hash, l2BlockNum uint64) (txmgr.TxCandidate, error) {
+// GameImpl returns the implementation address registered for the specified game type.
+// The zero address is returned if no implementation is registered.
+func (f *DisputeGameFactory) GameImpl(ctx context.Context, gameType uint32) (common.Address, error) {
+	cCtx, cancel := context.WithTimeout(ctx, f.networkTimeout)
+	defer cancel()
+	result, err := f.caller.SingleCall(cCtx, rpcblock.Latest, f.contract.Call(methodGameImpls, gameType))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to load game impl for type %v: %w", gameType, err)
+	}
+	return result.GetAddress(0), nil
+}
+
+// InitBond returns the bond required to create a game of the specified type.
+func (f *DisputeGameFactory) InitBond(ctx context.Context, gameType uint32) (*big.Int, error) {
 	cCtx, cancel := context.WithTimeout(ctx, f.networkTimeout)
 	defer cancel()
 	result, err := f.caller.SingleCall(cCtx, rpcblock.Latest, f.contract.Call(methodInitBonds, gameType))
 	if err != nil {
-		return txmgr.TxCandidate{}, fmt.Errorf("failed to fetch init bond: %w", err)
+		return nil, fmt.Errorf("failed to fetch init bond: %w", err)
+	}
+	return result.GetBigInt(0), nil
+}
+
+// GameExists returns true if a game with the specified type, root claim and L2 sequence number has been created.
+func (f *DisputeGameFactory) GameExists(ctx context.Context, gameType uint32, rootClaim common.Hash, l2SequenceNum uint64) (bool, error) {
+	cCtx, cancel := context.WithTimeout(ctx, f.networkTimeout)
+	defer cancel()
+	extraData := common.BigToHash(new(big.Int).SetUint64(l2SequenceNum)).Bytes()
+	result, err := f.caller.SingleCall(cCtx, rpcblock.Latest, f.contract.Call(methodGames, gameType, rootClaim, extraData))
+	if err != nil {
+		return false, fmt.Errorf("failed to load game: %w", err)
+	}
+	return result.GetAddress(0) != (common.Address{}), nil
+}
+
+func (f *DisputeGameFactory) ProposalTx(ctx context.Context, gameType uint32, outputRoot common.Hash, l2BlockNum uint64) (txmgr.TxCandidate, error) {
+	initBond, err := f.InitBond(ctx, gameType)
+	if err != nil {
+		return txmgr.TxCandidate{}, err
 	}
-	initBond := result.GetBigInt(0)
 	call := f.contract.Call(methodCreateGame, gameType, outputRoot, common.BigToHash(big.NewInt(int64(l2BlockNum))).Bytes())
 	candidate, err := call.ToTxCandidate()
 	if err != nil {
@@ -107,6 +147,29 @@ func (f *DisputeGameFactory) ProposalTx(ctx context.Context, gameType uint32, ou
 	return candidate, err
 }
 
+// DecodeDisputeGameCreatedLog finds the DisputeGameCreated event emitted by this factory in the receipt and
+// returns the address, game type and root claim of the created game.
+func (f *DisputeGameFactory) DecodeDisputeGameCreatedLog(rcpt *types.Receipt) (common.Address, uint32, common.Hash, error) {
+	for _, log := range rcpt.Logs {
+		if log.Address != f.contract.Addr() {
+			// Not from this contract
+			continue
+		}
+		name, result, err := f.contract.DecodeEvent(log)
+		if err != nil {
+			// Not a valid event
+			continue
+		}
+		if name != eventDisputeGameCreated {
+			// Not the event we're looking for
+			continue
+		}
+
+		return result.GetAddress(0), result.GetUint32(1), result.GetHash(2), nil
+	}
+	return common.Address{}, 0, common.Hash{}, fmt.Errorf("%w: %v", ErrEventNotFound, eventDisputeGameCreated)
+}
+
 func (f *DisputeGameFactory) gameCount(ctx context.Context) (uint64, error) {
 	cCtx, cancel := context.WithTimeout(ctx, f.networkTimeout)
 	defer cancel()
@@ -12,6 +12,7 @@ import (
 	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
 	"github.com/ethereum-optimism/optimism/packages/contracts-bedrock/snapshots"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -194,6 +195,70 @@ func TestProposalTx(t *testing.T) {
 	require.Truef(t, bond.Cmp(tx.Value) == 0, "Expected bond %v but was %v", bond, tx.Value)
 }
 
+func TestGameImpl(t *testing.T) {
+	stubRpc, factory := setupDisputeGameFactoryTest(t)
+	gameType := uint32(1)
+	impl := common.Address{0xbb}
+	stubRpc.SetResponse(factoryAddr, methodGameImpls, rpcblock.Latest, []interface{}{gameType}, []interface{}{impl})
+	actual, err := factory.GameImpl(context.Background(), gameType)
+	require.NoError(t, err)
+	require.Equal(t, impl, actual)
+}
+
+func TestGameExists(t *testing.T) {
+	stubRpc, factory := setupDisputeGameFactoryTest(t)
+	gameType := uint32(1)
+	rootClaim := common.Hash{0xaa}
+	extraData := common.BigToHash(big.NewInt(456)).Bytes()
+	stubRpc.SetResponse(factoryAddr, methodGames, rpcblock.Latest, []interface{}{gameType, rootClaim, extraData}, []interface{}{common.Address{0xcc}, uint64(1000)})
+	exists, err := factory.GameExists(context.Background(), gameType, rootClaim, 456)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	otherExtraData := common.BigToHash(big.NewInt(457)).Bytes()
+	stubRpc.SetResponse(factoryAddr, methodGames, rpcblock.Latest, []interface{}{gameType, rootClaim, otherExtraData}, []interface{}{common.Address{}, uint64(0)})
+	exists, err = factory.GameExists(context.Background(), gameType, rootClaim, 457)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestDecodeDisputeGameCreatedLog(t *testing.T) {
+	_, factory := setupDisputeGameFactoryTest(t)
+	eventAbi := snapshots.LoadDisputeGameFactoryABI().Events[eventDisputeGameCreated]
+	gameAddr := common.Address{0x11}
+	gameType := uint32(4)
+	rootClaim := common.Hash{0xaa, 0xbb, 0xcc}
+	createReceipt := func(addr common.Address) *types.Receipt {
+		return &types.Receipt{
+			Status: types.ReceiptStatusSuccessful,
+			Logs: []*types.Log{
+				{
+					Address: addr,
+					Topics: []common.Hash{
+						eventAbi.ID,
+						common.BytesToHash(gameAddr.Bytes()),
+						common.BytesToHash(big.NewInt(int64(gameType)).Bytes()),
+						rootClaim,
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("IgnoreIncorrectContract", func(t *testing.T) {
+		_, _, _, err := factory.DecodeDisputeGameCreatedLog(createReceipt(common.Address{0xaa}))
+		require.ErrorIs(t, err, ErrEventNotFound)
+	})
+
+	t.Run("ValidEvent", func(t *testing.T) {
+		actualGameAddr, actualGameType, actualRootClaim, err := factory.DecodeDisputeGameCreatedLog(createReceipt(factoryAddr))
+		require.NoError(t, err)
+		require.Equal(t, gameAddr, actualGameAddr)
+		require.Equal(t, gameType, actualGameType)
+		require.Equal(t, rootClaim, actualRootClaim)
+	})
+}
+
 func withClaims(stubRpc *batchingTest.AbiBasedRpc, games ...gameMetadata) {
 	gameAbi := snapshots.LoadFaultDisputeGameABI()
 	stubRpc.SetResponse(factoryAddr, methodGameCount, rpcblock.Latest, nil, []interface{}{big.NewInt(int64(len(games)))})
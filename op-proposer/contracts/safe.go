@@ -0,0 +1,110 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	methodExecTransaction = "execTransaction"
+	methodGetThreshold    = "getThreshold"
+	methodIsOwner         = "isOwner"
+
+	// safeOperationCall is the Safe operation type for a regular call, as opposed to a delegatecall.
+	safeOperationCall = uint8(0)
+)
+
+// safeABI is the subset of the Safe smart account ABI used to submit proposals through a Safe.
+const safeABI = `[
+	{"type":"function","name":"execTransaction","stateMutability":"payable","inputs":[
+		{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},
+		{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},
+		{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},
+		{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]},
+	{"type":"function","name":"getThreshold","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"isOwner","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// Safe submits transactions through a Safe smart account that has the proposer role.
+type Safe struct {
+	caller         *batching.MultiCaller
+	contract       *batching.BoundContract
+	networkTimeout time.Duration
+}
+
+func NewSafe(addr common.Address, caller *batching.MultiCaller, networkTimeout time.Duration) (*Safe, error) {
+	safeABI, err := abi.JSON(strings.NewReader(safeABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse safe ABI: %w", err)
+	}
+	return &Safe{
+		caller:         caller,
+		contract:       batching.NewBoundContract(&safeABI, addr),
+		networkTimeout: networkTimeout,
+	}, nil
+}
+
+func (s *Safe) Addr() common.Address {
+	return s.contract.Addr()
+}
+
+// CheckExecutor verifies that the executor can execute transactions through the Safe without other signatures,
+// which requires it to be an owner of a Safe with a threshold of one.
+func (s *Safe) CheckExecutor(ctx context.Context, executor common.Address) error {
+	cCtx, cancel := context.WithTimeout(ctx, s.networkTimeout)
+	defer cancel()
+	results, err := s.caller.Call(cCtx, rpcblock.Latest,
+		s.contract.Call(methodGetThreshold),
+		s.contract.Call(methodIsOwner, executor))
+	if err != nil {
+		return fmt.Errorf("failed to load safe config: %w", err)
+	}
+	if threshold := results[0].GetBigInt(0); threshold.Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("safe %v requires %v signatures, only a threshold of 1 is supported", s.Addr(), threshold)
+	}
+	if !results[1].GetBool(0) {
+		return fmt.Errorf("%v is not an owner of safe %v", executor, s.Addr())
+	}
+	return nil
+}
+
+// ExecTx wraps the transaction in a call to execTransaction on the Safe, to be sent by the executor.
+// The executor must be an owner and is authorised with a pre-validated signature, so no other signatures
+// are required when the Safe threshold is one. Any value is sent along with the call and forwarded by the Safe.
+func (s *Safe) ExecTx(executor common.Address, tx txmgr.TxCandidate) (txmgr.TxCandidate, error) {
+	if tx.To == nil {
+		return txmgr.TxCandidate{}, fmt.Errorf("cannot create contracts through safe %v", s.Addr())
+	}
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	call := s.contract.Call(methodExecTransaction,
+		*tx.To, value, tx.TxData, safeOperationCall,
+		new(big.Int), new(big.Int), new(big.Int), common.Address{}, common.Address{},
+		preValidatedSignature(executor))
+	candidate, err := call.ToTxCandidate()
+	if err != nil {
+		return txmgr.TxCandidate{}, err
+	}
+	candidate.Value = tx.Value
+	return candidate, nil
+}
+
+// preValidatedSignature returns a Safe signature that is valid when the transaction is sent by the owner itself.
+// It encodes the owner as r, zero as s and a v of 1.
+func preValidatedSignature(owner common.Address) []byte {
+	sig := make([]byte, 65)
+	copy(sig[12:32], owner[:])
+	sig[64] = 1
+	return sig
+}
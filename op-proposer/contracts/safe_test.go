@@ -0,0 +1,76 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching/rpcblock"
+	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var safeAddr = common.Address{0x5a, 0xfe}
+
+func TestSafeCheckExecutor(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		stubRpc, safe := setupSafeTest(t)
+		stubRpc.SetResponse(safeAddr, methodGetThreshold, rpcblock.Latest, nil, []interface{}{big.NewInt(1)})
+		stubRpc.SetResponse(safeAddr, methodIsOwner, rpcblock.Latest, []interface{}{proposerAddr}, []interface{}{true})
+		require.NoError(t, safe.CheckExecutor(context.Background(), proposerAddr))
+	})
+
+	t.Run("ThresholdTooHigh", func(t *testing.T) {
+		stubRpc, safe := setupSafeTest(t)
+		stubRpc.SetResponse(safeAddr, methodGetThreshold, rpcblock.Latest, nil, []interface{}{big.NewInt(2)})
+		stubRpc.SetResponse(safeAddr, methodIsOwner, rpcblock.Latest, []interface{}{proposerAddr}, []interface{}{true})
+		require.ErrorContains(t, safe.CheckExecutor(context.Background(), proposerAddr), "threshold")
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		stubRpc, safe := setupSafeTest(t)
+		stubRpc.SetResponse(safeAddr, methodGetThreshold, rpcblock.Latest, nil, []interface{}{big.NewInt(1)})
+		stubRpc.SetResponse(safeAddr, methodIsOwner, rpcblock.Latest, []interface{}{proposerAddr}, []interface{}{false})
+		require.ErrorContains(t, safe.CheckExecutor(context.Background(), proposerAddr), "not an owner")
+	})
+}
+
+func TestSafeExecTx(t *testing.T) {
+	stubRpc, safe := setupSafeTest(t)
+	inner := txmgr.TxCandidate{
+		To:     &factoryAddr,
+		TxData: []byte{0x01, 0x02},
+		Value:  big.NewInt(500),
+	}
+	sig := make([]byte, 65)
+	copy(sig[12:32], proposerAddr[:])
+	sig[64] = 1
+	stubRpc.SetResponse(safeAddr, methodExecTransaction, rpcblock.Latest, []interface{}{
+		factoryAddr, big.NewInt(500), []byte{0x01, 0x02}, safeOperationCall,
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, sig,
+	}, []interface{}{true})
+	tx, err := safe.ExecTx(proposerAddr, inner)
+	require.NoError(t, err)
+	stubRpc.VerifyTxCandidate(tx)
+	require.Equal(t, safeAddr, *tx.To)
+	require.Equal(t, inner.Value, tx.Value)
+
+	_, err = safe.ExecTx(proposerAddr, txmgr.TxCandidate{TxData: []byte{0x01}})
+	require.Error(t, err)
+}
+
+func setupSafeTest(t *testing.T) (*batchingTest.AbiBasedRpc, *Safe) {
+	parsed, err := abi.JSON(strings.NewReader(safeABI))
+	require.NoError(t, err)
+	stubRpc := batchingTest.NewAbiBasedRpc(t, safeAddr, &parsed)
+	caller := batching.NewMultiCaller(stubRpc, batching.DefaultBatchSize)
+	safe, err := NewSafe(safeAddr, caller, time.Minute)
+	require.NoError(t, err)
+	return stubRpc, safe
+}
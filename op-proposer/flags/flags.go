@@ -27,13 +27,20 @@ var (
 		Usage:   "HTTP provider URL for L1",
 		EnvVars: prefixEnvVars("L1_ETH_RPC"),
 	}
+
+	// Optional flags
 	RollupRpcFlag = &cli.StringFlag{
-		Name:    "rollup-rpc",
-		Usage:   "HTTP provider URL for the rollup node. A comma-separated list enables the active rollup provider.",
+		Name: "rollup-rpc",
+		Usage: "HTTP provider URL for the rollup node. A comma-separated list enables the active rollup provider. " +
+			"Required unless proposing super roots via the supervisor.",
 		EnvVars: prefixEnvVars("ROLLUP_RPC"),
 	}
-
-	// Optional flags
+	SupervisorRpcFlag = &cli.StringFlag{
+		Name: "supervisor-rpc",
+		Usage: "HTTP provider URL for the op-supervisor. When set, interop super roots are proposed " +
+			"to the dispute game factory instead of the output roots of the rollup node.",
+		EnvVars: prefixEnvVars("SUPERVISOR_RPC"),
+	}
 	L2OOAddressFlag = &cli.StringFlag{
 		Name:    "l2oo-address",
 		Usage:   "Address of the L2OutputOracle contract",
@@ -60,6 +67,28 @@ var (
 		Usage:   "Interval between submitting L2 output proposals when the dispute game factory address is set",
 		EnvVars: prefixEnvVars("PROPOSAL_INTERVAL"),
 	}
+	ProposalMinIntervalFlag = &cli.DurationFlag{
+		Name: "proposal-min-interval",
+		Usage: "Minimum interval between L2 output proposals when the dispute game factory address is set. " +
+			"Proposals are made early, after this interval, when L1 fees are at or below the cheap fee threshold.",
+		EnvVars: prefixEnvVars("PROPOSAL_MIN_INTERVAL"),
+	}
+	ProposalMaxIntervalFlag = &cli.DurationFlag{
+		Name: "proposal-max-interval",
+		Usage: "Maximum interval between L2 output proposals when the dispute game factory address is set. " +
+			"Proposals are delayed, up to this interval, when L1 fees are above the expensive fee threshold.",
+		EnvVars: prefixEnvVars("PROPOSAL_MAX_INTERVAL"),
+	}
+	ProposalCheapFeeFlag = &cli.Float64Flag{
+		Name:    "proposal-cheap-fee-gwei",
+		Usage:   "L1 fee (base fee plus tip) in GWei at or below which proposals may be made after the minimum proposal interval. 0 disables early proposals.",
+		EnvVars: prefixEnvVars("PROPOSAL_CHEAP_FEE_GWEI"),
+	}
+	ProposalExpensiveFeeFlag = &cli.Float64Flag{
+		Name:    "proposal-expensive-fee-gwei",
+		Usage:   "L1 fee (base fee plus tip) in GWei above which proposals are delayed up to the maximum proposal interval. 0 disables delaying proposals.",
+		EnvVars: prefixEnvVars("PROPOSAL_EXPENSIVE_FEE_GWEI"),
+	}
 	DisputeGameTypeFlag = &cli.UintFlag{
 		Name:    "game-type",
 		Usage:   "Dispute game type to create via the configured DisputeGameFactory",
@@ -79,25 +108,86 @@ var (
 		Value:   false,
 		EnvVars: prefixEnvVars("WAIT_NODE_SYNC"),
 	}
+	LeaderLeaseFileFlag = &cli.StringFlag{
+		Name: "leader-lease-file",
+		Usage: "Path to a lease file on storage shared by redundant proposer instances. " +
+			"When set, only the instance holding the lease submits proposals.",
+		EnvVars: prefixEnvVars("LEADER_LEASE_FILE"),
+	}
+	LeaderLeaseDurationFlag = &cli.DurationFlag{
+		Name:    "leader-lease-duration",
+		Usage:   "Duration of the leader lease. Another instance takes over if the leader fails to renew it within this time.",
+		Value:   time.Minute,
+		EnvVars: prefixEnvVars("LEADER_LEASE_DURATION"),
+	}
+	LeaderIDFlag = &cli.StringFlag{
+		Name:    "leader-id",
+		Usage:   "Unique identifier of this proposer instance for leader election. Defaults to the hostname.",
+		EnvVars: prefixEnvVars("LEADER_ID"),
+	}
+	VerifierRollupRpcsFlag = &cli.StringSliceFlag{
+		Name: "verifier-rollup-rpcs",
+		Usage: "HTTP provider URLs of additional rollup nodes used to cross-verify output roots before proposing. " +
+			"Proposals are refused if any verifier disagrees with the primary rollup node.",
+		EnvVars: prefixEnvVars("VERIFIER_ROLLUP_RPCS"),
+	}
+	ProposalJournalFlag = &cli.StringFlag{
+		Name: "proposal-journal",
+		Usage: "Path to a file recording sent and included proposals, used to avoid duplicate or missed " +
+			"proposals across restarts. Disabled if not set.",
+		EnvVars: prefixEnvVars("PROPOSAL_JOURNAL"),
+	}
+	DryRunFlag = &cli.BoolFlag{
+		Name: "dry-run",
+		Usage: "Compute and log the proposals that would be made, including their calldata and estimated cost, " +
+			"without sending any transactions.",
+		EnvVars: prefixEnvVars("DRY_RUN"),
+	}
+	ProposerWalletFlag = &cli.StringFlag{
+		Name: "proposer-wallet",
+		Usage: "Address of a Safe smart account holding the proposer role. Proposals are sent through the Safe, " +
+			"which must have a threshold of 1 with the tx sender as an owner, unless proposal-handoff-dir is set. " +
+			"Not supported for the permissioned game type, which only accepts proposals originating from the proposer.",
+		EnvVars: prefixEnvVars("PROPOSER_WALLET"),
+	}
+	ProposalHandoffDirFlag = &cli.StringFlag{
+		Name: "proposal-handoff-dir",
+		Usage: "Directory to write proposal transaction bundles to for an external signer or multisig, instead of " +
+			"sending them. Bundles use the Safe transaction builder format. Requires proposer-wallet.",
+		EnvVars: prefixEnvVars("PROPOSAL_HANDOFF_DIR"),
+	}
 	// Legacy Flags
 	L2OutputHDPathFlag = txmgr.L2OutputHDPathFlag
 )
 
 var requiredFlags = []cli.Flag{
 	L1EthRpcFlag,
-	RollupRpcFlag,
 }
 
 var optionalFlags = []cli.Flag{
+	RollupRpcFlag,
+	SupervisorRpcFlag,
 	L2OOAddressFlag,
 	PollIntervalFlag,
 	AllowNonFinalizedFlag,
 	L2OutputHDPathFlag,
 	DisputeGameFactoryAddressFlag,
 	ProposalIntervalFlag,
+	ProposalMinIntervalFlag,
+	ProposalMaxIntervalFlag,
+	ProposalCheapFeeFlag,
+	ProposalExpensiveFeeFlag,
 	DisputeGameTypeFlag,
 	ActiveSequencerCheckDurationFlag,
 	WaitNodeSyncFlag,
+	LeaderLeaseFileFlag,
+	LeaderLeaseDurationFlag,
+	LeaderIDFlag,
+	VerifierRollupRpcsFlag,
+	ProposalJournalFlag,
+	DryRunFlag,
+	ProposerWalletFlag,
+	ProposalHandoffDirFlag,
 }
 
 func init() {
@@ -2,12 +2,16 @@ package metrics
 
 import (
 	"io"
+	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -34,6 +38,14 @@ type Metricer interface {
 	StartBalanceMetrics(l log.Logger, client *ethclient.Client, account common.Address) io.Closer
 
 	RecordL2BlocksProposed(l2ref eth.L2BlockRef)
+
+	RecordGameCreated(gameType uint32)
+
+	RecordOutputDivergence()
+
+	RecordProposalSubmitted(delay time.Duration)
+	RecordProposalIncluded(delay time.Duration, receipt *types.Receipt)
+	RecordProposalFailure(reason string)
 }
 
 type Metrics struct {
@@ -47,6 +59,15 @@ type Metrics struct {
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
+
+	gamesCreated      *prometheus.CounterVec
+	outputDivergences prometheus.Counter
+
+	proposalSubmitDelay    prometheus.Histogram
+	proposalInclusionDelay prometheus.Histogram
+	proposalGasUsed        prometheus.Histogram
+	proposalFeesTotal      prometheus.Counter
+	proposalFailures       *prometheus.CounterVec
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -81,6 +102,48 @@ func NewMetrics(procName string) *Metrics {
 			Name:      "up",
 			Help:      "1 if the op-proposer has finished starting up",
 		}),
+		gamesCreated: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "games_created_total",
+			Help:      "Number of dispute games created by the proposer, by game type",
+		}, []string{
+			"game_type",
+		}),
+		outputDivergences: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "output_divergences_total",
+			Help:      "Number of times a verifier rollup node disagreed with the output root to be proposed",
+		}),
+		proposalSubmitDelay: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "proposal_submit_delay_seconds",
+			Help:      "Time from the L2 block of a proposal becoming final, or safe if non-finalized proposals are allowed, to its submission",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+		}),
+		proposalInclusionDelay: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "proposal_inclusion_delay_seconds",
+			Help:      "Time from submitting a proposal transaction to its inclusion on L1",
+			Buckets:   prometheus.ExponentialBuckets(2, 2, 10),
+		}),
+		proposalGasUsed: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "proposal_gas_used",
+			Help:      "Gas used by included proposal transactions",
+			Buckets:   prometheus.ExponentialBuckets(50_000, 1.5, 12),
+		}),
+		proposalFeesTotal: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "proposal_fees_gwei_total",
+			Help:      "Sum of L1 fees paid by included proposal transactions, in GWEI",
+		}),
+		proposalFailures: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "proposal_failures_total",
+			Help:      "Number of failed proposal submissions, by reason",
+		}, []string{
+			"reason",
+		}),
 	}
 }
 
@@ -113,6 +176,45 @@ func (m *Metrics) RecordL2BlocksProposed(l2ref eth.L2BlockRef) {
 	m.RecordL2Ref(BlockProposed, l2ref)
 }
 
+// RecordGameCreated should be called when a proposal creates a new dispute game
+func (m *Metrics) RecordGameCreated(gameType uint32) {
+	m.gamesCreated.WithLabelValues(strconv.FormatUint(uint64(gameType), 10)).Inc()
+}
+
+// RecordOutputDivergence should be called when a verifier disagrees with an output root
+func (m *Metrics) RecordOutputDivergence() {
+	m.outputDivergences.Inc()
+}
+
+const (
+	FailureReasonL1Sync   = "l1_sync"
+	FailureReasonTxData   = "tx_data"
+	FailureReasonSend     = "send"
+	FailureReasonTimeout  = "timeout"
+	FailureReasonReverted = "reverted"
+)
+
+// RecordProposalSubmitted should be called when a proposal transaction is submitted,
+// with the time since the L2 block of the proposal became final.
+func (m *Metrics) RecordProposalSubmitted(delay time.Duration) {
+	m.proposalSubmitDelay.Observe(delay.Seconds())
+}
+
+// RecordProposalIncluded should be called when a proposal transaction is included on L1,
+// with the time since it was submitted.
+func (m *Metrics) RecordProposalIncluded(delay time.Duration, receipt *types.Receipt) {
+	m.proposalInclusionDelay.Observe(delay.Seconds())
+	m.proposalGasUsed.Observe(float64(receipt.GasUsed))
+	if receipt.EffectiveGasPrice != nil {
+		m.proposalFeesTotal.Add(float64(receipt.EffectiveGasPrice.Uint64()*receipt.GasUsed) / params.GWei)
+	}
+}
+
+// RecordProposalFailure should be called when submitting a proposal fails, with one of the FailureReason constants
+func (m *Metrics) RecordProposalFailure(reason string) {
+	m.proposalFailures.WithLabelValues(reason).Inc()
+}
+
 func (m *Metrics) Document() []opmetrics.DocumentedMetric {
 	return m.factory.Document()
 }
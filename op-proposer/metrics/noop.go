@@ -2,8 +2,10 @@ package metrics
 
 import (
 	"io"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 
@@ -24,6 +26,12 @@ func (*noopMetrics) RecordInfo(version string) {}
 func (*noopMetrics) RecordUp()                 {}
 
 func (*noopMetrics) RecordL2BlocksProposed(l2ref eth.L2BlockRef) {}
+func (*noopMetrics) RecordGameCreated(gameType uint32)           {}
+func (*noopMetrics) RecordOutputDivergence()                     {}
+
+func (*noopMetrics) RecordProposalSubmitted(delay time.Duration)                        {}
+func (*noopMetrics) RecordProposalIncluded(delay time.Duration, receipt *types.Receipt) {}
+func (*noopMetrics) RecordProposalFailure(reason string)                                {}
 
 func (*noopMetrics) StartBalanceMetrics(log.Logger, *ethclient.Client, common.Address) io.Closer {
 	return nil
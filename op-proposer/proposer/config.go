@@ -2,11 +2,14 @@ package proposer
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-proposer/flags"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
@@ -24,8 +27,12 @@ type CLIConfig struct {
 	L1EthRpc string
 
 	// RollupRpc is the HTTP provider URL for the rollup node. A comma-separated list enables the active rollup provider.
+	// Required unless SupervisorRpc is set.
 	RollupRpc string
 
+	// SupervisorRpc is the HTTP provider URL for the op-supervisor. When set, super roots are proposed.
+	SupervisorRpc string
+
 	// L2OOAddress is the L2OutputOracle contract address.
 	L2OOAddress string
 
@@ -53,6 +60,18 @@ type CLIConfig struct {
 	// ProposalInterval is the delay between submitting L2 output proposals when the DGFAddress is set.
 	ProposalInterval time.Duration
 
+	// ProposalMinInterval is the minimum delay between proposals, used when L1 fees are cheap.
+	ProposalMinInterval time.Duration
+
+	// ProposalMaxInterval is the maximum delay between proposals, used when L1 fees are expensive.
+	ProposalMaxInterval time.Duration
+
+	// ProposalCheapFeeGwei is the L1 fee at or below which proposals are made after ProposalMinInterval.
+	ProposalCheapFeeGwei float64
+
+	// ProposalExpensiveFeeGwei is the L1 fee above which proposals are delayed up to ProposalMaxInterval.
+	ProposalExpensiveFeeGwei float64
+
 	// DisputeGameType is the type of dispute game to create when submitting an output proposal.
 	DisputeGameType uint32
 
@@ -61,6 +80,30 @@ type CLIConfig struct {
 
 	// Whether to wait for the sequencer to sync to a recent block at startup.
 	WaitNodeSync bool
+
+	// LeaderLeaseFile is the path to the lease file shared by redundant proposers. Leader election is disabled if empty.
+	LeaderLeaseFile string
+
+	// LeaderLeaseDuration is how long a leader lease remains valid without renewal.
+	LeaderLeaseDuration time.Duration
+
+	// LeaderID uniquely identifies this instance for leader election.
+	LeaderID string
+
+	// VerifierRollupRpcs are the HTTP provider URLs of rollup nodes that must agree on an output root before it is proposed.
+	VerifierRollupRpcs []string
+
+	// ProposalJournal is the path to the file recording sent and included proposals. Disabled if empty.
+	ProposalJournal string
+
+	// DryRun logs proposals instead of sending them.
+	DryRun bool
+
+	// ProposerWallet is the address of the Safe holding the proposer role, if proposals are not sent directly.
+	ProposerWallet string
+
+	// ProposalHandoffDir is the directory proposal bundles are written to for an external signer, instead of sending.
+	ProposalHandoffDir string
 }
 
 func (c *CLIConfig) Check() error {
@@ -77,6 +120,35 @@ func (c *CLIConfig) Check() error {
 		return err
 	}
 
+	if c.ProposalMinInterval != 0 || c.ProposalMaxInterval != 0 || c.ProposalCheapFeeGwei != 0 || c.ProposalExpensiveFeeGwei != 0 {
+		if c.DGFAddress == "" {
+			return errors.New("fee-aware proposal scheduling requires the `DisputeGameFactory` address")
+		}
+		if c.ProposalMinInterval > c.ProposalInterval {
+			return errors.New("the minimum proposal interval must not exceed the proposal interval")
+		}
+		if c.ProposalMaxInterval != 0 && c.ProposalMaxInterval < c.ProposalInterval {
+			return errors.New("the maximum proposal interval must not be less than the proposal interval")
+		}
+		if c.ProposalCheapFeeGwei < 0 || c.ProposalExpensiveFeeGwei < 0 {
+			return errors.New("proposal fee thresholds must not be negative")
+		}
+		if c.ProposalExpensiveFeeGwei != 0 && c.ProposalCheapFeeGwei > c.ProposalExpensiveFeeGwei {
+			return errors.New("the cheap proposal fee must not exceed the expensive proposal fee")
+		}
+	}
+	if c.RollupRpc == "" && c.SupervisorRpc == "" {
+		return errors.New("neither the rollup node nor the supervisor RPC was provided")
+	}
+	if c.RollupRpc != "" && c.SupervisorRpc != "" {
+		return errors.New("both the rollup node and the supervisor RPC were provided")
+	}
+	if c.SupervisorRpc != "" && c.DGFAddress == "" {
+		return errors.New("the supervisor RPC was provided but the `DisputeGameFactory` address was not set")
+	}
+	if c.SupervisorRpc != "" && len(c.VerifierRollupRpcs) > 0 {
+		return errors.New("verifier rollup RPCs are not supported when proposing super roots")
+	}
 	if c.DGFAddress == "" && c.L2OOAddress == "" {
 		return errors.New("neither the `DisputeGameFactory` nor `L2OutputOracle` address was provided")
 	}
@@ -90,6 +162,29 @@ func (c *CLIConfig) Check() error {
 		return errors.New("the `ProposalInterval` was provided but the `DisputeGameFactory` address was not set")
 	}
 
+	if c.ProposerWallet != "" {
+		if _, err := opservice.ParseAddress(c.ProposerWallet); err != nil {
+			return fmt.Errorf("invalid proposer wallet address: %w", err)
+		}
+	}
+	if c.ProposalHandoffDir != "" {
+		if c.ProposerWallet == "" {
+			return errors.New("the proposal handoff directory requires the proposer wallet address")
+		}
+		if c.DryRun {
+			return errors.New("proposal handoff and dry run mode cannot both be enabled")
+		}
+	}
+
+	if c.LeaderLeaseFile != "" {
+		if c.LeaderLeaseDuration <= c.PollInterval {
+			return errors.New("the leader lease duration must be greater than the poll interval")
+		}
+		if c.LeaderID == "" {
+			return errors.New("a leader ID is required when leader election is enabled")
+		}
+	}
+
 	return nil
 }
 
@@ -98,11 +193,12 @@ func NewConfig(ctx *cli.Context) *CLIConfig {
 	return &CLIConfig{
 		// Required Flags
 		L1EthRpc:     ctx.String(flags.L1EthRpcFlag.Name),
-		RollupRpc:    ctx.String(flags.RollupRpcFlag.Name),
 		L2OOAddress:  ctx.String(flags.L2OOAddressFlag.Name),
 		PollInterval: ctx.Duration(flags.PollIntervalFlag.Name),
 		TxMgrConfig:  txmgr.ReadCLIConfig(ctx),
 		// Optional Flags
+		RollupRpc:                    ctx.String(flags.RollupRpcFlag.Name),
+		SupervisorRpc:                ctx.String(flags.SupervisorRpcFlag.Name),
 		AllowNonFinalized:            ctx.Bool(flags.AllowNonFinalizedFlag.Name),
 		RPCConfig:                    oprpc.ReadCLIConfig(ctx),
 		LogConfig:                    oplog.ReadCLIConfig(ctx),
@@ -110,8 +206,32 @@ func NewConfig(ctx *cli.Context) *CLIConfig {
 		PprofConfig:                  oppprof.ReadCLIConfig(ctx),
 		DGFAddress:                   ctx.String(flags.DisputeGameFactoryAddressFlag.Name),
 		ProposalInterval:             ctx.Duration(flags.ProposalIntervalFlag.Name),
+		ProposalMinInterval:          ctx.Duration(flags.ProposalMinIntervalFlag.Name),
+		ProposalMaxInterval:          ctx.Duration(flags.ProposalMaxIntervalFlag.Name),
+		ProposalCheapFeeGwei:         ctx.Float64(flags.ProposalCheapFeeFlag.Name),
+		ProposalExpensiveFeeGwei:     ctx.Float64(flags.ProposalExpensiveFeeFlag.Name),
 		DisputeGameType:              uint32(ctx.Uint(flags.DisputeGameTypeFlag.Name)),
 		ActiveSequencerCheckDuration: ctx.Duration(flags.ActiveSequencerCheckDurationFlag.Name),
 		WaitNodeSync:                 ctx.Bool(flags.WaitNodeSyncFlag.Name),
+		LeaderLeaseFile:              ctx.String(flags.LeaderLeaseFileFlag.Name),
+		LeaderLeaseDuration:          ctx.Duration(flags.LeaderLeaseDurationFlag.Name),
+		LeaderID:                     leaderID(ctx),
+		VerifierRollupRpcs:           ctx.StringSlice(flags.VerifierRollupRpcsFlag.Name),
+		ProposalJournal:              ctx.String(flags.ProposalJournalFlag.Name),
+		DryRun:                       ctx.Bool(flags.DryRunFlag.Name),
+		ProposerWallet:               ctx.String(flags.ProposerWalletFlag.Name),
+		ProposalHandoffDir:           ctx.String(flags.ProposalHandoffDirFlag.Name),
+	}
+}
+
+// leaderID returns the configured leader ID, defaulting to the hostname.
+func leaderID(ctx *cli.Context) string {
+	if id := ctx.String(flags.LeaderIDFlag.Name); id != "" {
+		return id
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
 	}
+	return hostname
 }
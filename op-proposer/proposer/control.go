@@ -0,0 +1,135 @@
+package proposer
+
+import (
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-proposer/proposer/rpc"
+)
+
+// ProposeNow requests an immediate proposal, ignoring the proposal interval.
+// The proposal is attempted on the next iteration of the driver loop, subject to leadership and pausing.
+// Only supported with the DisputeGameFactory, since the L2OutputOracle only accepts outputs at the next interval.
+func (l *L2OutputSubmitter) ProposeNow() error {
+	if l.dgfContract == nil {
+		return ErrProposeNowNotSupported
+	}
+	l.mutex.Lock()
+	running := l.running
+	l.mutex.Unlock()
+	if !running {
+		return ErrProposerNotRunning
+	}
+
+	l.controlLock.Lock()
+	l.proposeNow = true
+	l.controlLock.Unlock()
+	l.Log.Info("Immediate proposal requested")
+	select {
+	case l.proposeNowCh <- struct{}{}:
+	default:
+		// Wake up already pending
+	}
+	return nil
+}
+
+// SkipNextProposal skips the next scheduled proposal and restarts the proposal interval from that point.
+// Only supported with the DisputeGameFactory, since the L2OutputOracle requires every interval to be proposed.
+func (l *L2OutputSubmitter) SkipNextProposal() error {
+	if l.dgfContract == nil {
+		return ErrSkipNotSupported
+	}
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	l.skipNext = true
+	l.Log.Info("Next scheduled proposal will be skipped")
+	return nil
+}
+
+// PauseProposing stops proposals from being made without stopping the driver loop.
+func (l *L2OutputSubmitter) PauseProposing() {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	l.paused = true
+	l.Log.Info("Proposing paused")
+}
+
+// ResumeProposing resumes proposing after PauseProposing.
+func (l *L2OutputSubmitter) ResumeProposing() {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	l.paused = false
+	l.Log.Info("Proposing resumed")
+}
+
+// Status returns a snapshot of the proposal pipeline.
+func (l *L2OutputSubmitter) Status() rpc.ProposerStatus {
+	l.mutex.Lock()
+	running := l.running
+	l.mutex.Unlock()
+
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	return rpc.ProposerStatus{
+		Running:    running,
+		Paused:     l.paused,
+		Leader:     l.leader,
+		ProposeNow: l.proposeNow,
+		SkipNext:   l.skipNext,
+		Pending:    copyProposalInfo(l.pending),
+		Last:       copyProposalInfo(l.last),
+	}
+}
+
+func (l *L2OutputSubmitter) isPaused() bool {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	return l.paused
+}
+
+// takeProposeNow returns whether an immediate proposal was requested, clearing the request.
+func (l *L2OutputSubmitter) takeProposeNow() bool {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	proposeNow := l.proposeNow
+	l.proposeNow = false
+	return proposeNow
+}
+
+// takeSkipNext returns whether the next proposal should be skipped, clearing the request and recording the skip.
+func (l *L2OutputSubmitter) takeSkipNext() bool {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	if !l.skipNext {
+		return false
+	}
+	l.skipNext = false
+	l.lastSkip = time.Now()
+	return true
+}
+
+func (l *L2OutputSubmitter) lastSkipTime() time.Time {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	return l.lastSkip
+}
+
+func (l *L2OutputSubmitter) setPending(info *rpc.ProposalInfo) {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	l.pending = info
+}
+
+func (l *L2OutputSubmitter) completePending(info *rpc.ProposalInfo) {
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	l.pending = nil
+	l.last = info
+}
+
+func copyProposalInfo(info *rpc.ProposalInfo) *rpc.ProposalInfo {
+	if info == nil {
+		return nil
+	}
+	cpy := *info
+	return &cpy
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/ethereum-optimism/optimism/op-proposer/bindings"
 	"github.com/ethereum-optimism/optimism/op-proposer/contracts"
 	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-proposer/proposer/leader"
+	"github.com/ethereum-optimism/optimism/op-proposer/proposer/rpc"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
@@ -24,8 +26,11 @@ import (
 )
 
 var (
-	supportedL2OutputVersion = eth.Bytes32{}
-	ErrProposerNotRunning    = errors.New("proposer is not running")
+	supportedL2OutputVersion  = eth.Bytes32{}
+	ErrProposerNotRunning     = errors.New("proposer is not running")
+	ErrOutputRootDivergence   = errors.New("output root divergence")
+	ErrSkipNotSupported       = errors.New("skipping proposals is only supported with the DisputeGameFactory")
+	ErrProposeNowNotSupported = errors.New("immediate proposals are only supported with the DisputeGameFactory")
 )
 
 type L1Client interface {
@@ -37,6 +42,23 @@ type L1Client interface {
 	// CallContract executes an Ethereum contract call with the specified data as the
 	// input.
 	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+
+	// EstimateGas estimates the gas required by a transaction, used to cost proposals in dry run mode.
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+
+	// ChainID returns the L1 chain ID, included in proposal handoff bundles.
+	ChainID(ctx context.Context) (*big.Int, error)
+
+	// NonceAt and PendingNonceAt are used to track the transactions of pending proposals in the journal.
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// ContractWallet sends proposals through a smart contract wallet that holds the proposer role.
+type ContractWallet interface {
+	Addr() common.Address
+	CheckExecutor(ctx context.Context, executor common.Address) error
+	ExecTx(executor common.Address, tx txmgr.TxCandidate) (txmgr.TxCandidate, error)
 }
 
 type L2OOContract interface {
@@ -46,8 +68,30 @@ type L2OOContract interface {
 
 type DGFContract interface {
 	Version(ctx context.Context) (string, error)
+	GameImpl(ctx context.Context, gameType uint32) (common.Address, error)
+	GameExists(ctx context.Context, gameType uint32, rootClaim common.Hash, l2SequenceNum uint64) (bool, error)
 	HasProposedSince(ctx context.Context, proposer common.Address, cutoff time.Time, gameType uint32) (bool, time.Time, error)
 	ProposalTx(ctx context.Context, gameType uint32, outputRoot common.Hash, l2BlockNum uint64) (txmgr.TxCandidate, error)
+	DecodeDisputeGameCreatedLog(rcpt *types.Receipt) (common.Address, uint32, common.Hash, error)
+}
+
+// proposalTimeout is the maximum time spent sending a single proposal.
+// Pending proposals in the journal that are older than this are abandoned if their transaction is no longer pending.
+const proposalTimeout = 10 * time.Minute
+
+// maxTrackedGames is the number of most recently created games retained by the driver.
+const maxTrackedGames = 100
+
+// permissionedGameType is the game type of the PermissionedDisputeGame.
+const permissionedGameType = 1
+
+// CreatedGame describes a dispute game created by this proposer.
+type CreatedGame struct {
+	Address     common.Address `json:"address"`
+	GameType    uint32         `json:"gameType"`
+	RootClaim   common.Hash    `json:"rootClaim"`
+	SequenceNum uint64         `json:"sequenceNum"`
+	TxHash      common.Hash    `json:"txHash"`
 }
 
 type RollupClient interface {
@@ -55,6 +99,11 @@ type RollupClient interface {
 	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
 }
 
+type SupervisorClient interface {
+	SyncStatus(ctx context.Context) (eth.SupervisorSyncStatus, error)
+	SuperRootAtTimestamp(ctx context.Context, timestamp uint64) (eth.SuperRootResponse, error)
+}
+
 type DriverSetup struct {
 	Log         log.Logger
 	Metr        metrics.Metricer
@@ -65,8 +114,36 @@ type DriverSetup struct {
 
 	// RollupProvider's RollupClient() is used to retrieve output roots from
 	RollupProvider dial.RollupProvider
+
+	// Elector decides whether this instance may propose. Defaults to always being the leader if nil.
+	Elector leader.Elector
+
+	// Verifiers are additional rollup nodes which must agree with the RollupProvider on every output root
+	Verifiers []RollupClient
+
+	// SupervisorClient is used to retrieve super roots from when proposing in interop super-root mode.
+	// Output roots are proposed from the RollupProvider if nil.
+	SupervisorClient SupervisorClient
+
+	// Journal durably records proposals so they survive restarts. Optional.
+	Journal *ProposalJournal
+}
+
+type trackedProposal struct {
+	root   common.Hash
+	seqNum uint64
+	since  time.Time
 }
 
+// finalizedObservation is the first time the final head of the proposal source was seen at or past seqNum.
+type finalizedObservation struct {
+	seqNum uint64
+	seen   time.Time
+}
+
+// maxFinalizedObservations bounds the history of final heads used to determine when proposals became final.
+const maxFinalizedObservations = 256
+
 // L2OutputSubmitter is responsible for proposing outputs
 type L2OutputSubmitter struct {
 	DriverSetup
@@ -84,6 +161,33 @@ type L2OutputSubmitter struct {
 	l2ooABI      *abi.ABI
 
 	dgfContract DGFContract
+
+	// wallet is the contract wallet proposals are sent through, nil if sent directly
+	wallet ContractWallet
+
+	gamesLock    sync.Mutex
+	createdGames []CreatedGame
+
+	// finalized records when the final head of the proposal source was first seen at each sequence number, oldest
+	// first. The final head is the safe head if non-finalized proposals are allowed. Only accessed from the driver loop.
+	finalized []finalizedObservation
+
+	// lastUnsent is the most recent proposal logged in dry run mode or handed off to an external signer,
+	// only accessed from the driver loop
+	lastUnsent trackedProposal
+
+	// proposeNowCh wakes up the driver loop when an immediate proposal is requested
+	proposeNowCh chan struct{}
+
+	// controlLock guards the admin controls and pipeline status below
+	controlLock sync.Mutex
+	leader      bool
+	paused      bool
+	proposeNow  bool
+	skipNext    bool
+	lastSkip    time.Time
+	pending     *rpc.ProposalInfo
+	last        *rpc.ProposalInfo
 }
 
 // NewL2OutputSubmitter creates a new L2 Output Submitter
@@ -98,13 +202,53 @@ func NewL2OutputSubmitter(setup DriverSetup) (_ *L2OutputSubmitter, err error) {
 		}
 	}()
 
+	var submitter *L2OutputSubmitter
 	if setup.Cfg.L2OutputOracleAddr != nil {
-		return newL2OOSubmitter(ctx, cancel, setup)
+		submitter, err = newL2OOSubmitter(ctx, cancel, setup)
 	} else if setup.Cfg.DisputeGameFactoryAddr != nil {
-		return newDGFSubmitter(ctx, cancel, setup)
+		submitter, err = newDGFSubmitter(ctx, cancel, setup)
 	} else {
 		return nil, errors.New("neither the `L2OutputOracle` nor `DisputeGameFactory` addresses were provided")
 	}
+	if err != nil {
+		return nil, err
+	}
+	if err = submitter.initWallet(ctx); err != nil {
+		return nil, err
+	}
+	return submitter, nil
+}
+
+// initWallet sets up the contract wallet proposals are sent through, if configured.
+func (l *L2OutputSubmitter) initWallet(ctx context.Context) error {
+	if l.Cfg.ProposerWallet == nil {
+		return nil
+	}
+	// The permissioned dispute game only accepts proposals from transactions that originate from the proposer,
+	// which a contract wallet can't send.
+	if l.Cfg.DisputeGameFactoryAddr != nil && l.Cfg.DisputeGameType == permissionedGameType {
+		return fmt.Errorf("game type %v is permissioned, and can't be proposed through a proposer wallet", l.Cfg.DisputeGameType)
+	}
+	safe, err := contracts.NewSafe(*l.Cfg.ProposerWallet, l.Multicaller, l.Cfg.NetworkTimeout)
+	if err != nil {
+		return err
+	}
+	if l.Cfg.SendsProposals() {
+		if err := safe.CheckExecutor(ctx, l.Txmgr.From()); err != nil {
+			return fmt.Errorf("cannot send proposals through wallet: %w", err)
+		}
+	}
+	l.Log.Info("Using proposer wallet", "address", safe.Addr(), "handoff", l.Cfg.ProposalHandoffDir != "")
+	l.wallet = safe
+	return nil
+}
+
+// proposerAddress returns the address proposals are made from.
+func (l *L2OutputSubmitter) proposerAddress() common.Address {
+	if l.wallet != nil {
+		return l.wallet.Addr()
+	}
+	return l.Txmgr.From()
 }
 
 func newL2OOSubmitter(ctx context.Context, cancel context.CancelFunc, setup DriverSetup) (*L2OutputSubmitter, error) {
@@ -130,10 +274,11 @@ func newL2OOSubmitter(ctx context.Context, cancel context.CancelFunc, setup Driv
 	}
 
 	return &L2OutputSubmitter{
-		DriverSetup: setup,
-		done:        make(chan struct{}),
-		ctx:         ctx,
-		cancel:      cancel,
+		DriverSetup:  setup,
+		done:         make(chan struct{}),
+		proposeNowCh: make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
 
 		l2ooContract: l2ooContract,
 		l2ooABI:      parsed,
@@ -150,11 +295,23 @@ func newDGFSubmitter(ctx context.Context, cancel context.CancelFunc, setup Drive
 	}
 	log.Info("Connected to DisputeGameFactory", "address", setup.Cfg.DisputeGameFactoryAddr, "version", version)
 
+	impl, err := dgfCaller.GameImpl(ctx, setup.Cfg.DisputeGameType)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if impl == (common.Address{}) {
+		cancel()
+		return nil, fmt.Errorf("no implementation registered for game type %v", setup.Cfg.DisputeGameType)
+	}
+	log.Info("Using dispute game type", "gameType", setup.Cfg.DisputeGameType, "impl", impl)
+
 	return &L2OutputSubmitter{
-		DriverSetup: setup,
-		done:        make(chan struct{}),
-		ctx:         ctx,
-		cancel:      cancel,
+		DriverSetup:  setup,
+		done:         make(chan struct{}),
+		proposeNowCh: make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
 
 		dgfContract: dgfCaller,
 	}, nil
@@ -178,6 +335,10 @@ func (l *L2OutputSubmitter) StartL2OutputSubmitting() error {
 		}
 	}
 
+	if l.Journal != nil {
+		l.reconcileJournal(l.ctx)
+	}
+
 	l.wg.Add(1)
 	go l.loop()
 
@@ -208,6 +369,14 @@ func (l *L2OutputSubmitter) StopL2OutputSubmitting() error {
 	close(l.done)
 	l.wg.Wait()
 
+	if l.Elector != nil {
+		cCtx, cancel := context.WithTimeout(context.Background(), l.Cfg.NetworkTimeout)
+		defer cancel()
+		if err := l.Elector.Resign(cCtx); err != nil {
+			l.Log.Warn("Failed to resign leadership", "err", err)
+		}
+	}
+
 	l.Log.Info("Proposer stopped")
 	return nil
 }
@@ -225,7 +394,7 @@ func (l *L2OutputSubmitter) FetchL2OOOutput(ctx context.Context) (*eth.OutputRes
 	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
 	defer cancel()
 	callOpts := &bind.CallOpts{
-		From:    l.Txmgr.From(),
+		From:    l.proposerAddress(),
 		Context: cCtx,
 	}
 	nextCheckpointBlockBig, err := l.l2ooContract.NextBlockNumber(callOpts)
@@ -268,17 +437,13 @@ func (l *L2OutputSubmitter) FetchL2OOOutput(ctx context.Context) (*eth.OutputRes
 // The passed context is expected to be a lifecycle context. A network timeout
 // context will be derived from it.
 func (l *L2OutputSubmitter) FetchDGFOutput(ctx context.Context) (*eth.OutputResponse, bool, error) {
-	cutoff := time.Now().Add(-l.Cfg.ProposalInterval)
-	proposedRecently, proposalTime, err := l.dgfContract.HasProposedSince(ctx, l.Txmgr.From(), cutoff, l.Cfg.DisputeGameType)
-	if err != nil {
-		return nil, false, fmt.Errorf("could not check for recent proposal: %w", err)
-	}
+	return l.fetchDGFOutput(ctx, false)
+}
 
-	if proposedRecently {
-		l.Log.Debug("Duration since last game not past proposal interval", "duration", time.Since(proposalTime))
-		return nil, false, nil
+func (l *L2OutputSubmitter) fetchDGFOutput(ctx context.Context, force bool) (*eth.OutputResponse, bool, error) {
+	if due, err := l.dgfProposalDue(ctx, force); err != nil || !due {
+		return nil, false, err
 	}
-	l.Log.Info("No proposals found for at least proposal interval, submitting proposal now", "proposalInterval", l.Cfg.ProposalInterval)
 
 	// Fetch the current L2 heads
 	currentBlockNumber, err := l.FetchCurrentBlockNumber(ctx)
@@ -299,6 +464,139 @@ func (l *L2OutputSubmitter) FetchDGFOutput(ctx context.Context) (*eth.OutputResp
 	return output, true, nil
 }
 
+// FetchSuperRootProposal queries the DGF for the latest game and infers whether it is time to make another proposal.
+// If necessary, it fetches the super root at the latest finalized (or safe, if AllowNonFinalized is set) timestamp
+// from the supervisor and returns it along with a boolean for whether the proposal should be submitted at all.
+// The passed context is expected to be a lifecycle context. A network timeout
+// context will be derived from it.
+func (l *L2OutputSubmitter) FetchSuperRootProposal(ctx context.Context) (Proposal, bool, error) {
+	return l.fetchSuperRootProposal(ctx, false)
+}
+
+func (l *L2OutputSubmitter) fetchSuperRootProposal(ctx context.Context, force bool) (Proposal, bool, error) {
+	if due, err := l.dgfProposalDue(ctx, force); err != nil || !due {
+		return Proposal{}, false, err
+	}
+
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	status, err := l.SupervisorClient.SyncStatus(cCtx)
+	if err != nil {
+		return Proposal{}, false, fmt.Errorf("could not fetch supervisor sync status: %w", err)
+	}
+	timestamp := uint64(status.FinalizedTimestamp)
+	if l.Cfg.AllowNonFinalized {
+		timestamp = uint64(status.SafeTimestamp)
+	}
+	if timestamp == 0 {
+		l.Log.Info("Skipping proposal, no super root timestamp available yet")
+		return Proposal{}, false, nil
+	}
+	l.observeFinalized(timestamp)
+
+	super, err := l.SupervisorClient.SuperRootAtTimestamp(cCtx, timestamp)
+	if err != nil {
+		return Proposal{}, false, fmt.Errorf("could not fetch super root at timestamp %d: %w", timestamp, err)
+	}
+	if uint64(super.Timestamp) != timestamp { // sanity check, e.g. in case of bad RPC caching
+		return Proposal{}, false, fmt.Errorf("super root timestamp %d mismatches requested %d", super.Timestamp, timestamp)
+	}
+	// Recompute the super root from its components so the proposal is guaranteed to commit to the reported outputs
+	if expected := eth.SuperRoot(super.Super()); expected != super.SuperRoot {
+		return Proposal{}, false, fmt.Errorf("super root %v at timestamp %d does not match its components, expected %v", super.SuperRoot, timestamp, expected)
+	}
+	return Proposal{
+		Root:        common.Hash(super.SuperRoot),
+		SequenceNum: timestamp,
+		CurrentL1:   status.MinSyncedL1.ID(),
+		Super:       &super,
+	}, true, nil
+}
+
+// dgfProposalDue checks whether a proposal is due according to the proposal schedule,
+// based on the time since this proposer last created a game and, if needed, the current L1 fee.
+// A forced proposal is always due. A skipped proposal restarts the proposal interval.
+func (l *L2OutputSubmitter) dgfProposalDue(ctx context.Context, force bool) (bool, error) {
+	if force {
+		l.Log.Info("Immediate proposal requested, submitting proposal now")
+		return true, nil
+	}
+	schedule := l.Cfg.ProposalSchedule()
+	lookback := schedule.LookbackWindow()
+	cutoff := time.Now().Add(-lookback)
+	proposedRecently, proposalTime, err := l.dgfContract.HasProposedSince(ctx, l.proposerAddress(), cutoff, l.Cfg.DisputeGameType)
+	if err != nil {
+		return false, fmt.Errorf("could not check for recent proposal: %w", err)
+	}
+
+	elapsed := lookback
+	if proposedRecently {
+		elapsed = time.Since(proposalTime)
+	}
+	if lastSkip := l.lastSkipTime(); !lastSkip.IsZero() && time.Since(lastSkip) < elapsed {
+		elapsed = time.Since(lastSkip)
+	}
+	if !l.Cfg.SendsProposals() && !l.lastUnsent.since.IsZero() && time.Since(l.lastUnsent.since) < elapsed {
+		// Unsent proposals don't create games until executed externally, if ever, so schedule from the last one
+		elapsed = time.Since(l.lastUnsent.since)
+	}
+	var fee *big.Int
+	if schedule.NeedsFee(elapsed) {
+		fee, err = l.currentL1Fee(ctx)
+		if err != nil {
+			// Without a fee, the schedule falls back to the nominal proposal interval
+			l.Log.Warn("Failed to fetch L1 fee for proposal scheduling", "err", err)
+		}
+	}
+	if !schedule.Due(elapsed, fee) {
+		l.Log.Debug("Duration since last game not past proposal interval", "duration", elapsed, "fee", fee)
+		return false, nil
+	}
+	if l.takeSkipNext() {
+		l.Log.Info("Skipping scheduled proposal as requested", "duration", elapsed)
+		return false, nil
+	}
+	l.Log.Info("Proposal interval elapsed, submitting proposal now",
+		"proposalInterval", l.Cfg.ProposalInterval, "duration", elapsed, "fee", fee)
+	return true, nil
+}
+
+// currentL1Fee returns the suggested L1 fee per gas, including the base fee and the priority fee.
+func (l *L2OutputSubmitter) currentL1Fee(ctx context.Context) (*big.Int, error) {
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	tipCap, baseFee, _, err := l.Txmgr.SuggestGasPriceCaps(cCtx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Add(tipCap, baseFee), nil
+}
+
+// FetchProposal returns the next proposal to make using whichever proposal source is configured,
+// along with a boolean for whether the proposal should be submitted at all.
+func (l *L2OutputSubmitter) FetchProposal(ctx context.Context) (Proposal, bool, error) {
+	return l.fetchProposal(ctx, false)
+}
+
+// fetchProposal returns the next proposal. If force is set, the proposal interval is ignored.
+func (l *L2OutputSubmitter) fetchProposal(ctx context.Context, force bool) (Proposal, bool, error) {
+	var output *eth.OutputResponse
+	var shouldPropose bool
+	var err error
+	switch {
+	case l.dgfContract == nil:
+		output, shouldPropose, err = l.FetchL2OOOutput(ctx)
+	case l.SupervisorClient != nil:
+		return l.fetchSuperRootProposal(ctx, force)
+	default:
+		output, shouldPropose, err = l.fetchDGFOutput(ctx, force)
+	}
+	if err != nil || !shouldPropose {
+		return Proposal{}, false, err
+	}
+	return OutputProposal(output), true, nil
+}
+
 // FetchCurrentBlockNumber gets the current block number from the [L2OutputSubmitter]'s [RollupClient]. If the `AllowNonFinalized` configuration
 // option is set, it will return the safe head block number, and if not, it will return the finalized head block number.
 func (l *L2OutputSubmitter) FetchCurrentBlockNumber(ctx context.Context) (uint64, error) {
@@ -313,10 +611,12 @@ func (l *L2OutputSubmitter) FetchCurrentBlockNumber(ctx context.Context) (uint64
 	}
 
 	// Use either the finalized or safe head depending on the config. Finalized head is default & safer.
+	final := status.FinalizedL2.Number
 	if l.Cfg.AllowNonFinalized {
-		return status.SafeL2.Number, nil
+		final = status.SafeL2.Number
 	}
-	return status.FinalizedL2.Number, nil
+	l.observeFinalized(final)
+	return final, nil
 }
 
 func (l *L2OutputSubmitter) FetchOutput(ctx context.Context, block uint64) (*eth.OutputResponse, error) {
@@ -335,9 +635,39 @@ func (l *L2OutputSubmitter) FetchOutput(ctx context.Context, block uint64) (*eth
 	if onum := output.BlockRef.Number; onum != block { // sanity check, e.g. in case of bad RPC caching
 		return nil, fmt.Errorf("output block number %d mismatches requested %d", output.BlockRef.Number, block)
 	}
+	if err := l.verifyOutput(ctx, output); err != nil {
+		return nil, err
+	}
 	return output, nil
 }
 
+// verifyOutput checks that every configured verifier agrees with the output root of the primary rollup node.
+// Any disagreement is reported as an ErrOutputRootDivergence and prevents the output from being proposed.
+// Verifiers that fail to respond also prevent the proposal, as agreement could not be established.
+func (l *L2OutputSubmitter) verifyOutput(ctx context.Context, output *eth.OutputResponse) error {
+	for i, verifier := range l.Verifiers {
+		cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+		verified, err := verifier.OutputAtBlock(cCtx, output.BlockRef.Number)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("verifier %d failed to fetch output at block %d: %w", i, output.BlockRef.Number, err)
+		}
+		if verified.OutputRoot != output.OutputRoot || verified.BlockRef.Hash != output.BlockRef.Hash {
+			l.Log.Error("Output root divergence detected, refusing to propose",
+				"verifier", i,
+				"l2blocknum", output.BlockRef.Number,
+				"expected_output", output.OutputRoot,
+				"expected_block_hash", output.BlockRef.Hash,
+				"verifier_output", verified.OutputRoot,
+				"verifier_block_hash", verified.BlockRef.Hash)
+			l.Metr.RecordOutputDivergence()
+			return fmt.Errorf("%w: verifier %d reported %v at block %d but expected %v",
+				ErrOutputRootDivergence, i, verified.OutputRoot, output.BlockRef.Number, output.OutputRoot)
+		}
+	}
+	return nil
+}
+
 // ProposeL2OutputTxData creates the transaction data for the ProposeL2Output function
 func (l *L2OutputSubmitter) ProposeL2OutputTxData(output *eth.OutputResponse) ([]byte, error) {
 	return proposeL2OutputTxData(l.l2ooABI, output)
@@ -354,9 +684,47 @@ func proposeL2OutputTxData(abi *abi.ABI, output *eth.OutputResponse) ([]byte, er
 }
 
 func (l *L2OutputSubmitter) ProposeL2OutputDGFTxCandidate(ctx context.Context, output *eth.OutputResponse) (txmgr.TxCandidate, error) {
+	return l.proposalDGFTxCandidate(ctx, OutputProposal(output))
+}
+
+// proposalTxCandidate builds the transaction to submit the proposal to whichever contract is configured.
+func (l *L2OutputSubmitter) proposalTxCandidate(ctx context.Context, proposal Proposal) (txmgr.TxCandidate, error) {
+	if l.Cfg.DisputeGameFactoryAddr != nil {
+		return l.proposalDGFTxCandidate(ctx, proposal)
+	}
+	data, err := l.ProposeL2OutputTxData(proposal.Legacy)
+	if err != nil {
+		return txmgr.TxCandidate{}, err
+	}
+	return txmgr.TxCandidate{
+		TxData:   data,
+		To:       l.Cfg.L2OutputOracleAddr,
+		GasLimit: 0,
+	}, nil
+}
+
+// submissionTxCandidate builds the transaction to send for the proposal, routing it through the wallet if configured.
+func (l *L2OutputSubmitter) submissionTxCandidate(ctx context.Context, proposal Proposal) (txmgr.TxCandidate, error) {
+	candidate, err := l.proposalTxCandidate(ctx, proposal)
+	if err != nil {
+		return txmgr.TxCandidate{}, err
+	}
+	if l.wallet == nil {
+		return candidate, nil
+	}
+	// The wallet config may have changed since startup, in which case the transaction would revert.
+	if err := l.wallet.CheckExecutor(ctx, l.Txmgr.From()); err != nil {
+		return txmgr.TxCandidate{}, fmt.Errorf("cannot send proposal through wallet: %w", err)
+	}
+	return l.wallet.ExecTx(l.Txmgr.From(), candidate)
+}
+
+// proposalDGFTxCandidate creates the transaction to create a dispute game for the proposal.
+// The sequence number of the proposal is used as the game's extra data.
+func (l *L2OutputSubmitter) proposalDGFTxCandidate(ctx context.Context, proposal Proposal) (txmgr.TxCandidate, error) {
 	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
 	defer cancel()
-	return l.dgfContract.ProposalTx(cCtx, l.Cfg.DisputeGameType, common.Hash(output.OutputRoot), output.BlockRef.Number)
+	return l.dgfContract.ProposalTx(cCtx, l.Cfg.DisputeGameType, proposal.Root, proposal.SequenceNum)
 }
 
 // We wait until l1head advances beyond blocknum. This is used to make sure proposal tx won't
@@ -388,47 +756,116 @@ func (l *L2OutputSubmitter) waitForL1Head(ctx context.Context, blockNum uint64)
 }
 
 // sendTransaction creates & sends transactions through the underlying transaction manager.
-func (l *L2OutputSubmitter) sendTransaction(ctx context.Context, output *eth.OutputResponse) error {
-	err := l.waitForL1Head(ctx, output.Status.HeadL1.Number+1)
-	if err != nil {
-		return err
-	}
-
-	l.Log.Info("Proposing output root", "output", output.OutputRoot, "block", output.BlockRef)
-	var receipt *types.Receipt
-	if l.Cfg.DisputeGameFactoryAddr != nil {
-		candidate, err := l.ProposeL2OutputDGFTxCandidate(ctx, output)
+func (l *L2OutputSubmitter) sendTransaction(ctx context.Context, proposal Proposal) (*types.Receipt, error) {
+	if output := proposal.Legacy; output != nil {
+		err := l.waitForL1Head(ctx, output.Status.HeadL1.Number+1)
 		if err != nil {
-			return err
-		}
-		receipt, err = l.Txmgr.Send(ctx, candidate)
-		if err != nil {
-			return err
+			l.Metr.RecordProposalFailure(metrics.FailureReasonL1Sync)
+			return nil, err
 		}
+		l.Log.Info("Proposing output root", "output", output.OutputRoot, "block", output.BlockRef)
 	} else {
-		data, err := l.ProposeL2OutputTxData(output)
-		if err != nil {
-			return err
-		}
-		receipt, err = l.Txmgr.Send(ctx, txmgr.TxCandidate{
-			TxData:   data,
-			To:       l.Cfg.L2OutputOracleAddr,
-			GasLimit: 0,
-		})
-		if err != nil {
-			return err
+		l.Log.Info("Proposing super root", "superRoot", proposal.Root, "timestamp", proposal.SequenceNum)
+	}
+
+	candidate, err := l.submissionTxCandidate(ctx, proposal)
+	if err != nil {
+		l.Metr.RecordProposalFailure(metrics.FailureReasonTxData)
+		return nil, err
+	}
+
+	if l.Journal != nil {
+		if err := l.journalPending(ctx, proposal); err != nil {
+			// Without a durable record a restart could propose twice, so don't propose at all
+			return nil, err
 		}
 	}
 
+	submitted := time.Now()
+	l.Metr.RecordProposalSubmitted(submitted.Sub(l.readySince(proposal)))
+	receipt, err := l.Txmgr.Send(ctx, candidate)
+	if l.Journal != nil {
+		l.journalResult(proposal, receipt, err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		l.Metr.RecordProposalFailure(metrics.FailureReasonTimeout)
+		return nil, err
+	} else if err != nil {
+		l.Metr.RecordProposalFailure(metrics.FailureReasonSend)
+		return nil, err
+	}
+	l.Metr.RecordProposalIncluded(time.Since(submitted), receipt)
+
 	if receipt.Status == types.ReceiptStatusFailed {
+		l.Metr.RecordProposalFailure(metrics.FailureReasonReverted)
 		l.Log.Error("Proposer tx successfully published but reverted", "tx_hash", receipt.TxHash)
 	} else {
 		l.Log.Info("Proposer tx successfully published",
 			"tx_hash", receipt.TxHash,
-			"l1blocknum", output.Status.CurrentL1.Number,
-			"l1blockhash", output.Status.CurrentL1.Hash)
+			"l1blocknum", proposal.CurrentL1.Number,
+			"l1blockhash", proposal.CurrentL1.Hash)
+		if l.dgfContract != nil {
+			l.trackCreatedGame(receipt, proposal)
+		}
+	}
+	return receipt, nil
+}
+
+// readySince returns the time the proposal became final: the first time the finalized head, or the safe head if
+// non-finalized proposals are allowed, was seen at or past the sequence number of the proposal.
+// Final heads are seen whenever the sync status is fetched, so proposals that were already final when the proposer
+// started, or whose observation is no longer retained, are measured from the earliest retained observation.
+func (l *L2OutputSubmitter) readySince(proposal Proposal) time.Time {
+	for _, observation := range l.finalized {
+		if observation.seqNum >= proposal.SequenceNum {
+			return observation.seen
+		}
+	}
+	// Not yet seen as final, e.g. for proposals made without fetching the sync status
+	l.observeFinalized(proposal.SequenceNum)
+	return l.finalized[len(l.finalized)-1].seen
+}
+
+// observeFinalized records the first time the final head of the proposal source was seen at seqNum.
+func (l *L2OutputSubmitter) observeFinalized(seqNum uint64) {
+	if n := len(l.finalized); n > 0 && l.finalized[n-1].seqNum >= seqNum {
+		return
+	}
+	l.finalized = append(l.finalized, finalizedObservation{seqNum: seqNum, seen: time.Now()})
+	if len(l.finalized) > maxFinalizedObservations {
+		l.finalized = l.finalized[len(l.finalized)-maxFinalizedObservations:]
+	}
+}
+
+// trackCreatedGame records the dispute game created by a successful proposal transaction.
+func (l *L2OutputSubmitter) trackCreatedGame(receipt *types.Receipt, proposal Proposal) {
+	addr, gameType, rootClaim, err := l.dgfContract.DecodeDisputeGameCreatedLog(receipt)
+	if err != nil {
+		l.Log.Warn("Failed to find created dispute game in proposal receipt", "tx_hash", receipt.TxHash, "err", err)
+		return
+	}
+	l.Log.Info("Created dispute game", "game", addr, "gameType", gameType, "rootClaim", rootClaim, "sequenceNum", proposal.SequenceNum)
+	l.Metr.RecordGameCreated(gameType)
+
+	l.gamesLock.Lock()
+	defer l.gamesLock.Unlock()
+	l.createdGames = append(l.createdGames, CreatedGame{
+		Address:     addr,
+		GameType:    gameType,
+		RootClaim:   rootClaim,
+		SequenceNum: proposal.SequenceNum,
+		TxHash:      receipt.TxHash,
+	})
+	if len(l.createdGames) > maxTrackedGames {
+		l.createdGames = l.createdGames[len(l.createdGames)-maxTrackedGames:]
 	}
-	return nil
+}
+
+// CreatedGames returns the most recent dispute games created by this proposer, oldest first.
+func (l *L2OutputSubmitter) CreatedGames() []CreatedGame {
+	l.gamesLock.Lock()
+	defer l.gamesLock.Unlock()
+	return append([]CreatedGame(nil), l.createdGames...)
 }
 
 // loop is responsible for creating & submitting the next outputs
@@ -442,38 +879,220 @@ func (l *L2OutputSubmitter) loop() {
 	for {
 		select {
 		case <-ticker.C:
-			// prioritize quit signal
+		case <-l.proposeNowCh:
+		case <-l.done:
+			return
+		}
+		// prioritize quit signal
+		select {
+		case <-l.done:
+			return
+		default:
+		}
+
+		l.tryPropose(ctx)
+	}
+}
+
+// tryPropose makes a proposal if this instance is the leader, proposing is not paused and a proposal is due.
+func (l *L2OutputSubmitter) tryPropose(ctx context.Context) {
+	if !l.isLeader(ctx) {
+		return
+	}
+	if l.isPaused() {
+		l.Log.Debug("Proposing is paused")
+		return
+	}
+
+	if l.Journal != nil {
+		if pending := l.reconcileJournal(ctx); len(pending) > 0 {
+			l.Log.Info("Waiting for pending proposal to be included", "root", pending[0].Root, "sequenceNum", pending[0].SequenceNum)
+			return
+		}
+	}
+
+	// A note on retrying: the outer ticker already runs on a short
+	// poll interval, which has a default value of 6 seconds. So no
+	// retry logic is needed around output fetching here.
+	proposal, shouldPropose, err := l.fetchProposal(ctx, l.takeProposeNow())
+	if err != nil {
+		l.Log.Warn("Error getting output", "err", err)
+		return
+	} else if !shouldPropose {
+		// debug logging already in Fetch(DGF|L2OO)Output and FetchSuperRootProposal
+		return
+	}
+	if l.Journal != nil && l.Journal.IsProposed(proposal.Root, proposal.SequenceNum) {
+		l.Log.Info("Skipping proposal already recorded as included", "root", proposal.Root, "sequenceNum", proposal.SequenceNum)
+		return
+	}
+
+	if !l.Cfg.SendsProposals() && l.lastUnsent.root == proposal.Root && l.lastUnsent.seqNum == proposal.SequenceNum {
+		l.Log.Debug("Proposal already logged or handed off", "root", proposal.Root, "sequenceNum", proposal.SequenceNum)
+		return
+	}
+
+	l.proposeOutput(ctx, proposal)
+}
+
+// isLeader campaigns for leadership and reports whether this instance should propose.
+// Any error is treated as not being the leader so that two instances never propose concurrently.
+func (l *L2OutputSubmitter) isLeader(ctx context.Context) bool {
+	if l.Elector == nil {
+		l.controlLock.Lock()
+		defer l.controlLock.Unlock()
+		l.leader = true
+		return true
+	}
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	leader, err := l.Elector.Campaign(cCtx)
+	if err != nil {
+		l.Log.Warn("Failed to campaign for leadership", "err", err)
+		leader = false
+	}
+	l.controlLock.Lock()
+	defer l.controlLock.Unlock()
+	if leader != l.leader {
+		l.Log.Info("Proposer leadership changed", "leader", leader)
+		l.leader = leader
+	}
+	return leader
+}
+
+// renewLeadership keeps campaigning every poll interval until the returned function is called, so that the lease is
+// not lost while a proposal transaction is being sent. The lease duration is longer than the poll interval, so the
+// lease is renewed before it expires. The returned function waits for any campaign in progress to finish.
+func (l *L2OutputSubmitter) renewLeadership(ctx context.Context) func() {
+	if l.Elector == nil {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(l.Cfg.PollInterval)
+		defer ticker.Stop()
+		for {
 			select {
-			case <-l.done:
+			case <-ticker.C:
+				if !l.isLeader(ctx) {
+					l.Log.Warn("Lost leadership while sending proposal transaction")
+				}
+			case <-stop:
+				return
+			case <-ctx.Done():
 				return
-			default:
 			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
 
-			// A note on retrying: the outer ticker already runs on a short
-			// poll interval, which has a default value of 6 seconds. So no
-			// retry logic is needed around output fetching here.
-			var output *eth.OutputResponse
-			var shouldPropose bool
-			var err error
-			if l.dgfContract == nil {
-				output, shouldPropose, err = l.FetchL2OOOutput(ctx)
-			} else {
-				output, shouldPropose, err = l.FetchDGFOutput(ctx)
-			}
-			if err != nil {
-				l.Log.Warn("Error getting output", "err", err)
-				continue
-			} else if !shouldPropose {
-				// debug logging already in Fetch(DGF|L2OO)Output
-				continue
-			}
+// journalPending records the proposal as pending in the journal, along with the nonce its transaction will use.
+// Only one proposal is sent at a time, so the transaction uses the next pending nonce of the sender.
+func (l *L2OutputSubmitter) journalPending(ctx context.Context, proposal Proposal) error {
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	nonce, err := l.L1Client.PendingNonceAt(cCtx, l.Txmgr.From())
+	if err != nil {
+		return fmt.Errorf("failed to get proposer nonce: %w", err)
+	}
+	if err := l.Journal.RecordPending(proposal.Root, proposal.SequenceNum, nonce, time.Now()); err != nil {
+		return fmt.Errorf("failed to record pending proposal: %w", err)
+	}
+	return nil
+}
 
-			l.proposeOutput(ctx, output)
-		case <-l.done:
-			return
+// journalResult records the outcome of sending a proposal in the journal.
+// If sending failed, the transaction may still be included so the proposal is left pending to be reconciled.
+func (l *L2OutputSubmitter) journalResult(proposal Proposal, receipt *types.Receipt, sendErr error) {
+	var err error
+	if sendErr != nil {
+		l.Log.Warn("Keeping proposal pending until its transaction is included or replaced", "root", proposal.Root, "sequenceNum", proposal.SequenceNum)
+		return
+	} else if receipt.Status == types.ReceiptStatusFailed {
+		err = l.Journal.RecordAbandoned(proposal.Root, proposal.SequenceNum)
+	} else {
+		err = l.Journal.RecordProposed(proposal.Root, proposal.SequenceNum, receipt.TxHash, time.Now())
+	}
+	if err != nil {
+		l.Log.Error("Failed to record proposal result", "root", proposal.Root, "sequenceNum", proposal.SequenceNum, "err", err)
+	}
+}
+
+// reconcileJournal checks pending proposals from the journal against L1, recording those that were included
+// and abandoning those whose nonce was used by another transaction. Proposals with no transaction pending
+// in the mempool are abandoned once older than the proposal timeout. Returns the proposals still pending.
+func (l *L2OutputSubmitter) reconcileJournal(ctx context.Context) []JournalEntry {
+	for _, entry := range l.Journal.Pending() {
+		// Check the nonce before inclusion, so a proposal included in a block that used the nonce is seen as included
+		replaced, inMempool, err := l.pendingTxStatus(ctx, entry)
+		if err != nil {
+			l.Log.Warn("Failed to check status of pending proposal transaction", "root", entry.Root, "sequenceNum", entry.SequenceNum, "err", err)
+			continue
+		}
+		included, err := l.proposalIncluded(ctx, entry)
+		if err != nil {
+			l.Log.Warn("Failed to check if pending proposal was included", "root", entry.Root, "sequenceNum", entry.SequenceNum, "err", err)
+			continue
+		}
+		if included {
+			l.Log.Info("Pending proposal was included", "root", entry.Root, "sequenceNum", entry.SequenceNum)
+			err = l.Journal.RecordProposed(entry.Root, entry.SequenceNum, entry.TxHash, time.Now())
+		} else if replaced {
+			l.Log.Warn("Abandoning pending proposal replaced by another transaction", "root", entry.Root, "sequenceNum", entry.SequenceNum, "nonce", *entry.Nonce)
+			err = l.Journal.RecordAbandoned(entry.Root, entry.SequenceNum)
+		} else if !inMempool && time.Since(entry.Time) > proposalTimeout {
+			l.Log.Warn("Abandoning pending proposal that was not included", "root", entry.Root, "sequenceNum", entry.SequenceNum, "since", entry.Time)
+			err = l.Journal.RecordAbandoned(entry.Root, entry.SequenceNum)
+		}
+		if err != nil {
+			l.Log.Error("Failed to update proposal journal", "err", err)
 		}
 	}
+	return l.Journal.Pending()
+}
 
+// pendingTxStatus reports whether the nonce of a pending proposal transaction was used on L1
+// and whether a transaction with the nonce is still in the mempool.
+func (l *L2OutputSubmitter) pendingTxStatus(ctx context.Context, entry JournalEntry) (replaced bool, inMempool bool, err error) {
+	if entry.Nonce == nil {
+		return false, false, nil
+	}
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	from := l.Txmgr.From()
+	confirmed, err := l.L1Client.NonceAt(cCtx, from, nil)
+	if err != nil {
+		return false, false, fmt.Errorf("querying confirmed nonce: %w", err)
+	}
+	if confirmed > *entry.Nonce {
+		return true, false, nil
+	}
+	pending, err := l.L1Client.PendingNonceAt(cCtx, from)
+	if err != nil {
+		return false, false, fmt.Errorf("querying pending nonce: %w", err)
+	}
+	return false, pending > *entry.Nonce, nil
+}
+
+// proposalIncluded checks L1 to determine if the journaled proposal was included.
+func (l *L2OutputSubmitter) proposalIncluded(ctx context.Context, entry JournalEntry) (bool, error) {
+	if l.dgfContract != nil {
+		return l.dgfContract.GameExists(ctx, l.Cfg.DisputeGameType, entry.Root, entry.SequenceNum)
+	}
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	next, err := l.l2ooContract.NextBlockNumber(&bind.CallOpts{Context: cCtx})
+	if err != nil {
+		return false, fmt.Errorf("querying next block number: %w", err)
+	}
+	// The L2OutputOracle only accepts outputs in order, so the output was proposed if the oracle has moved past it
+	return next.Uint64() > entry.SequenceNum, nil
 }
 
 func (l *L2OutputSubmitter) waitNodeSync() error {
@@ -485,6 +1104,10 @@ func (l *L2OutputSubmitter) waitNodeSync() error {
 		return fmt.Errorf("failed to retrieve current L1 block number: %w", err)
 	}
 
+	if l.RollupProvider == nil {
+		// Super root proposals are sourced from the supervisor, which tracks sync itself
+		return nil
+	}
 	rollupClient, err := l.RollupProvider.RollupClient(l.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get rollup client: %w", err)
@@ -493,17 +1116,49 @@ func (l *L2OutputSubmitter) waitNodeSync() error {
 	return dial.WaitRollupSync(l.ctx, l.Log, rollupClient, l1head, time.Second*12)
 }
 
-func (l *L2OutputSubmitter) proposeOutput(ctx context.Context, output *eth.OutputResponse) {
-	cCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+func (l *L2OutputSubmitter) proposeOutput(ctx context.Context, proposal Proposal) {
+	cCtx, cancel := context.WithTimeout(ctx, proposalTimeout)
 	defer cancel()
 
-	if err := l.sendTransaction(cCtx, output); err != nil {
+	if l.Cfg.DryRun {
+		if err := l.simulateProposal(cCtx, proposal); err != nil {
+			l.Log.Error("Failed to simulate proposal", "err", err, "root", proposal.Root, "sequenceNum", proposal.SequenceNum)
+		}
+		return
+	}
+	if l.Cfg.ProposalHandoffDir != "" {
+		if err := l.handoffProposal(cCtx, proposal); err != nil {
+			l.Log.Error("Failed to hand off proposal", "err", err, "root", proposal.Root, "sequenceNum", proposal.SequenceNum)
+		}
+		return
+	}
+
+	info := &rpc.ProposalInfo{
+		Root:        proposal.Root,
+		SequenceNum: proposal.SequenceNum,
+		Time:        time.Now(),
+	}
+	l.setPending(info)
+	stopRenewing := l.renewLeadership(cCtx)
+	receipt, err := l.sendTransaction(cCtx, proposal)
+	stopRenewing()
+	result := *info
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.TxHash = receipt.TxHash
+	}
+	l.completePending(&result)
+	if err != nil {
 		l.Log.Error("Failed to send proposal transaction",
 			"err", err,
-			"l1blocknum", output.Status.CurrentL1.Number,
-			"l1blockhash", output.Status.CurrentL1.Hash,
-			"l1head", output.Status.HeadL1.Number)
+			"root", proposal.Root,
+			"sequenceNum", proposal.SequenceNum,
+			"l1blocknum", proposal.CurrentL1.Number,
+			"l1blockhash", proposal.CurrentL1.Hash)
 		return
 	}
-	l.Metr.RecordL2BlocksProposed(output.BlockRef)
+	if proposal.Legacy != nil {
+		l.Metr.RecordL2BlocksProposed(proposal.Legacy.BlockRef)
+	}
 }
@@ -2,8 +2,11 @@ package proposer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,13 +14,16 @@ import (
 	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	txmgrmocks "github.com/ethereum-optimism/optimism/op-service/txmgr/mocks"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/stretchr/testify/mock"
@@ -40,6 +46,7 @@ func (m *MockL2OOContract) NextBlockNumber(opts *bind.CallOpts) (*big.Int, error
 
 type StubDGFContract struct {
 	hasProposedCount int
+	existingGames    map[common.Hash]bool
 }
 
 func (m *StubDGFContract) HasProposedSince(_ context.Context, _ common.Address, _ time.Time, _ uint32) (bool, time.Time, error) {
@@ -47,14 +54,26 @@ func (m *StubDGFContract) HasProposedSince(_ context.Context, _ common.Address,
 	return false, time.Unix(1000, 0), nil
 }
 
-func (m *StubDGFContract) ProposalTx(_ context.Context, _ uint32, _ common.Hash, _ uint64) (txmgr.TxCandidate, error) {
-	panic("not implemented")
+func (m *StubDGFContract) ProposalTx(_ context.Context, _ uint32, rootClaim common.Hash, _ uint64) (txmgr.TxCandidate, error) {
+	return txmgr.TxCandidate{To: &common.Address{0xdf}, TxData: rootClaim.Bytes(), Value: big.NewInt(10)}, nil
 }
 
 func (m *StubDGFContract) Version(_ context.Context) (string, error) {
 	panic("not implemented")
 }
 
+func (m *StubDGFContract) GameImpl(_ context.Context, _ uint32) (common.Address, error) {
+	panic("not implemented")
+}
+
+func (m *StubDGFContract) GameExists(_ context.Context, _ uint32, rootClaim common.Hash, _ uint64) (bool, error) {
+	return m.existingGames[rootClaim], nil
+}
+
+func (m *StubDGFContract) DecodeDisputeGameCreatedLog(_ *types.Receipt) (common.Address, uint32, common.Hash, error) {
+	return common.Address{0xdd}, 0, common.Hash{0xee}, nil
+}
+
 type mockRollupEndpointProvider struct {
 	rollupClient    *testutils.MockRollupClient
 	rollupClientErr error
@@ -179,3 +198,535 @@ func TestL2OutputSubmitter_OutputRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestL2OutputSubmitter_TrackCreatedGames(t *testing.T) {
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{Log: testlog.Logger(t, log.LevelDebug), Metr: metrics.NoopMetrics},
+		dgfContract: new(StubDGFContract),
+	}
+	for i := 0; i < maxTrackedGames+5; i++ {
+		ps.trackCreatedGame(&types.Receipt{TxHash: common.Hash{byte(i)}}, Proposal{SequenceNum: uint64(i)})
+	}
+	games := ps.CreatedGames()
+	require.Len(t, games, maxTrackedGames)
+	require.Equal(t, CreatedGame{
+		Address:     common.Address{0xdd},
+		RootClaim:   common.Hash{0xee},
+		SequenceNum: maxTrackedGames + 4,
+		TxHash:      common.Hash{byte(maxTrackedGames + 4)},
+	}, games[len(games)-1])
+	require.Equal(t, uint64(5), games[0].SequenceNum)
+}
+
+func TestL2OutputSubmitter_ReconcileJournal(t *testing.T) {
+	now := time.Now()
+	stale := now.Add(-time.Hour)
+	tests := []struct {
+		name         string
+		nonce        *uint64
+		time         time.Time
+		included     bool
+		confirmed    uint64
+		pending      uint64
+		expectStatus JournalStatus
+	}{
+		{name: "Included", nonce: ptr(uint64(5)), time: now, included: true, confirmed: 6, pending: 6, expectStatus: JournalProposed},
+		{name: "Replaced", nonce: ptr(uint64(5)), time: now, confirmed: 6, pending: 6},
+		{name: "InMempool", nonce: ptr(uint64(5)), time: now, confirmed: 5, pending: 6, expectStatus: JournalPending},
+		{name: "StaleInMempool", nonce: ptr(uint64(5)), time: stale, confirmed: 5, pending: 6, expectStatus: JournalPending},
+		{name: "StaleNotInMempool", nonce: ptr(uint64(5)), time: stale, confirmed: 5, pending: 5},
+		{name: "RecentNotInMempool", nonce: ptr(uint64(5)), time: now, confirmed: 5, pending: 5, expectStatus: JournalPending},
+		{name: "StaleWithoutNonce", time: stale},
+		{name: "RecentWithoutNonce", time: now, expectStatus: JournalPending},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			root := common.Hash{0x01}
+			journal, err := OpenProposalJournal(filepath.Join(t.TempDir(), "journal.json"))
+			require.NoError(t, err)
+			journal.entries = []JournalEntry{{Root: root, SequenceNum: 1, Status: JournalPending, Time: test.time, Nonce: test.nonce}}
+
+			txMgr := &txmgrmocks.TxManager{}
+			txMgr.On("From").Return(common.Address{0xab}).Maybe()
+			ps := &L2OutputSubmitter{
+				DriverSetup: DriverSetup{
+					Log:      testlog.Logger(t, log.LevelDebug),
+					Cfg:      ProposerConfig{NetworkTimeout: time.Second},
+					Txmgr:    txMgr,
+					L1Client: &stubL1Client{nonce: test.confirmed, pendingNonce: test.pending},
+					Journal:  journal,
+				},
+				dgfContract: &StubDGFContract{existingGames: map[common.Hash]bool{root: test.included}},
+			}
+			ps.reconcileJournal(context.Background())
+
+			require.Equal(t, test.expectStatus == JournalProposed, journal.IsProposed(root, 1))
+			if test.expectStatus == JournalPending {
+				require.Len(t, journal.Pending(), 1)
+			} else {
+				require.Empty(t, journal.Pending())
+			}
+		})
+	}
+}
+
+func TestL2OutputSubmitter_JournalSendFailure(t *testing.T) {
+	journal, err := OpenProposalJournal(filepath.Join(t.TempDir(), "journal.json"))
+	require.NoError(t, err)
+	txMgr := &txmgrmocks.TxManager{}
+	txMgr.On("From").Return(common.Address{0xab})
+	txMgr.On("Send", mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{
+			Log:      testlog.Logger(t, log.LevelDebug),
+			Metr:     metrics.NoopMetrics,
+			Cfg:      ProposerConfig{DisputeGameFactoryAddr: &common.Address{0xdf}, NetworkTimeout: time.Second},
+			Txmgr:    txMgr,
+			L1Client: &stubL1Client{pendingNonce: 7},
+			Journal:  journal,
+		},
+		dgfContract: new(StubDGFContract),
+	}
+	ps.proposeOutput(context.Background(), Proposal{Root: common.Hash{0x01}, SequenceNum: 5})
+
+	// The transaction may still be included, so the proposal is kept pending with its nonce
+	pending := journal.Pending()
+	require.Len(t, pending, 1)
+	require.Equal(t, common.Hash{0x01}, pending[0].Root)
+	require.Equal(t, uint64(7), *pending[0].Nonce)
+	txMgr.AssertExpectations(t)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestL2OutputSubmitter_ReadySince(t *testing.T) {
+	ps := &L2OutputSubmitter{}
+	ps.observeFinalized(10)
+	finalAt10 := ps.finalized[0].seen
+	ps.observeFinalized(10)
+	ps.observeFinalized(5)
+	require.Len(t, ps.finalized, 1, "only advances of the final head should be recorded")
+
+	time.Sleep(time.Millisecond)
+	ps.observeFinalized(20)
+	finalAt20 := ps.finalized[1].seen
+	require.True(t, finalAt20.After(finalAt10))
+
+	require.Equal(t, finalAt10, ps.readySince(Proposal{SequenceNum: 8}), "proposal should be measured from the first final head past it")
+	require.Equal(t, finalAt10, ps.readySince(Proposal{SequenceNum: 10}))
+	require.Equal(t, finalAt20, ps.readySince(Proposal{SequenceNum: 15}))
+
+	// A proposal that wasn't seen as final is measured from now, and remembered
+	time.Sleep(time.Millisecond)
+	since := ps.readySince(Proposal{SequenceNum: 30})
+	require.True(t, since.After(finalAt20))
+	require.Equal(t, since, ps.readySince(Proposal{SequenceNum: 30}))
+
+	for i := uint64(0); i < 2*maxFinalizedObservations; i++ {
+		ps.observeFinalized(100 + i)
+	}
+	require.Len(t, ps.finalized, maxFinalizedObservations)
+	require.Equal(t, uint64(100+maxFinalizedObservations), ps.finalized[0].seqNum)
+}
+
+type stubL1Client struct {
+	L1Client
+	estimatedGas uint64
+	nonce        uint64
+	pendingNonce uint64
+}
+
+func (s *stubL1Client) NonceAt(_ context.Context, _ common.Address, _ *big.Int) (uint64, error) {
+	return s.nonce, nil
+}
+
+func (s *stubL1Client) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return s.pendingNonce, nil
+}
+
+func (s *stubL1Client) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	return s.estimatedGas, nil
+}
+
+func (s *stubL1Client) ChainID(_ context.Context) (*big.Int, error) {
+	return big.NewInt(900), nil
+}
+
+type stubWallet struct {
+	addr        common.Address
+	executorErr error
+}
+
+func (w *stubWallet) Addr() common.Address {
+	return w.addr
+}
+
+func (w *stubWallet) CheckExecutor(_ context.Context, _ common.Address) error {
+	return w.executorErr
+}
+
+func (w *stubWallet) ExecTx(_ common.Address, tx txmgr.TxCandidate) (txmgr.TxCandidate, error) {
+	return txmgr.TxCandidate{To: &w.addr, TxData: append([]byte{0xee}, tx.TxData...), Value: tx.Value}, nil
+}
+
+func TestL2OutputSubmitter_Handoff(t *testing.T) {
+	dir := t.TempDir()
+	txMgr := &txmgrmocks.TxManager{}
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{
+			Log:      testlog.Logger(t, log.LevelInfo),
+			Metr:     metrics.NoopMetrics,
+			Cfg:      ProposerConfig{ProposalHandoffDir: dir, DisputeGameFactoryAddr: &common.Address{0xdf}, NetworkTimeout: time.Second},
+			Txmgr:    txMgr,
+			L1Client: &stubL1Client{},
+		},
+		dgfContract: new(StubDGFContract),
+		wallet:      &stubWallet{addr: common.Address{0x5a}},
+	}
+	proposal := Proposal{Root: common.Hash{0x01, 0x02}, SequenceNum: 5}
+	ps.proposeOutput(context.Background(), proposal)
+	txMgr.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+
+	bundle, err := jsonutil.LoadJSON[HandoffBundle](filepath.Join(dir, "proposal-5-01020000.json"))
+	require.NoError(t, err)
+	require.Equal(t, "900", bundle.ChainID)
+	require.Equal(t, common.Address{0x5a}, bundle.Meta.CreatedFromSafeAddress)
+	// The bundle contains the proposal itself, for the wallet to execute, rather than a wallet transaction
+	require.Equal(t, []HandoffTx{{To: common.Address{0xdf}, Value: "10", Data: proposal.Root.Bytes()}}, bundle.Transactions)
+	require.Equal(t, proposal.Root, ps.lastUnsent.root)
+}
+
+func TestL2OutputSubmitter_WalletTx(t *testing.T) {
+	txMgr := &txmgrmocks.TxManager{}
+	txMgr.On("From").Return(common.Address{0xaa})
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{
+			Cfg:   ProposerConfig{DisputeGameFactoryAddr: &common.Address{0xdf}, NetworkTimeout: time.Second},
+			Txmgr: txMgr,
+		},
+		dgfContract: new(StubDGFContract),
+		wallet:      &stubWallet{addr: common.Address{0x5a}},
+	}
+	candidate, err := ps.submissionTxCandidate(context.Background(), Proposal{Root: common.Hash{0x01}})
+	require.NoError(t, err)
+	require.Equal(t, common.Address{0x5a}, *candidate.To)
+	require.Equal(t, byte(0xee), candidate.TxData[0])
+	require.Equal(t, common.Address{0x5a}, ps.proposerAddress())
+
+	// The executor is checked again before each proposal, in case the wallet config changed.
+	ps.wallet = &stubWallet{addr: common.Address{0x5a}, executorErr: errors.New("threshold changed")}
+	_, err = ps.submissionTxCandidate(context.Background(), Proposal{Root: common.Hash{0x01}})
+	require.ErrorContains(t, err, "threshold changed")
+}
+
+func TestL2OutputSubmitter_WalletPermissionedGame(t *testing.T) {
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{
+			Log: testlog.Logger(t, log.LevelInfo),
+			Cfg: ProposerConfig{
+				DisputeGameFactoryAddr: &common.Address{0xdf},
+				DisputeGameType:        permissionedGameType,
+				ProposerWallet:         &common.Address{0x5a},
+			},
+		},
+	}
+	require.ErrorContains(t, ps.initWallet(context.Background()), "permissioned")
+}
+
+func TestL2OutputSubmitter_DryRun(t *testing.T) {
+	logger, logs := testlog.CaptureLogger(t, log.LevelInfo)
+	txMgr := &txmgrmocks.TxManager{}
+	txMgr.On("From").Return(common.Address{0xaa})
+	txMgr.On("SuggestGasPriceCaps", mock.Anything).Return(big.NewInt(1), big.NewInt(9), big.NewInt(0), nil)
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{
+			Log:      logger,
+			Metr:     metrics.NoopMetrics,
+			Cfg:      ProposerConfig{DryRun: true, DisputeGameFactoryAddr: &common.Address{0xdf}, NetworkTimeout: time.Second},
+			Txmgr:    txMgr,
+			L1Client: &stubL1Client{estimatedGas: 100},
+		},
+		dgfContract: new(StubDGFContract),
+	}
+	proposal := Proposal{Root: common.Hash{0x01}, SequenceNum: 5}
+	ps.proposeOutput(context.Background(), proposal)
+
+	// Send is not mocked, so any attempt to send would fail the test
+	txMgr.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	entry := logs.FindLog(testlog.NewMessageFilter("Dry run: not sending proposal"))
+	require.NotNil(t, entry)
+	require.Equal(t, uint64(100), entry.AttrValue("gas"))
+	require.Equal(t, common.Hash{0x01}, ps.lastUnsent.root)
+	require.Equal(t, uint64(5), ps.lastUnsent.seqNum)
+	require.Nil(t, ps.Status().Last, "dry run proposals should not be recorded as submitted")
+}
+
+type stubElector struct {
+	leader bool
+	count  atomic.Int32
+}
+
+func (s *stubElector) Campaign(_ context.Context) (bool, error) {
+	s.count.Add(1)
+	return s.leader, nil
+}
+
+func (s *stubElector) Resign(_ context.Context) error {
+	return nil
+}
+
+func TestL2OutputSubmitter_NotLeader(t *testing.T) {
+	elector := &stubElector{}
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{Log: testlog.Logger(t, log.LevelDebug), Elector: elector},
+	}
+	require.False(t, ps.isLeader(context.Background()))
+	elector.leader = true
+	require.True(t, ps.isLeader(context.Background()))
+	require.Equal(t, int32(2), elector.count.Load())
+}
+
+func TestL2OutputSubmitter_RenewLeadershipWhileSending(t *testing.T) {
+	elector := &stubElector{leader: true}
+	txMgr := &txmgrmocks.TxManager{}
+	txMgr.On("Send", mock.Anything, mock.Anything).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful}, nil).
+		Once().
+		Run(func(_ mock.Arguments) {
+			// Simulate a send that takes several poll intervals, such as while the fee is bumped
+			require.Eventually(t, func() bool {
+				return elector.count.Load() >= 3
+			}, 10*time.Second, time.Millisecond, "should renew the lease while sending")
+		})
+	ps := &L2OutputSubmitter{
+		DriverSetup: DriverSetup{
+			Log:     testlog.Logger(t, log.LevelDebug),
+			Metr:    metrics.NoopMetrics,
+			Cfg:     ProposerConfig{DisputeGameFactoryAddr: &common.Address{0xdf}, NetworkTimeout: time.Second, PollInterval: time.Millisecond},
+			Txmgr:   txMgr,
+			Elector: elector,
+		},
+		dgfContract: new(StubDGFContract),
+	}
+	ps.proposeOutput(context.Background(), Proposal{Root: common.Hash{0x01}, SequenceNum: 5})
+	txMgr.AssertExpectations(t)
+
+	// Renewal stops once the transaction is sent
+	count := elector.count.Load()
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, count, elector.count.Load())
+}
+
+func TestL2OutputSubmitter_VerifyOutput(t *testing.T) {
+	output := &eth.OutputResponse{
+		OutputRoot: eth.Bytes32{0xaa},
+		BlockRef:   eth.L2BlockRef{Number: 42, Hash: common.Hash{0xbb}},
+	}
+	newSubmitter := func(verifiers ...RollupClient) *L2OutputSubmitter {
+		return &L2OutputSubmitter{
+			DriverSetup: DriverSetup{
+				Log:       testlog.Logger(t, log.LevelDebug),
+				Metr:      metrics.NoopMetrics,
+				Verifiers: verifiers,
+			},
+		}
+	}
+
+	t.Run("NoVerifiers", func(t *testing.T) {
+		require.NoError(t, newSubmitter().verifyOutput(context.Background(), output))
+	})
+
+	t.Run("Agreement", func(t *testing.T) {
+		verifier := new(testutils.MockRollupClient)
+		verifier.ExpectOutputAtBlock(42, output, nil)
+		require.NoError(t, newSubmitter(verifier).verifyOutput(context.Background(), output))
+		verifier.AssertExpectations(t)
+	})
+
+	t.Run("Divergence", func(t *testing.T) {
+		agrees := new(testutils.MockRollupClient)
+		agrees.ExpectOutputAtBlock(42, output, nil)
+		diverges := new(testutils.MockRollupClient)
+		diverges.ExpectOutputAtBlock(42, &eth.OutputResponse{OutputRoot: eth.Bytes32{0xcc}, BlockRef: output.BlockRef}, nil)
+		err := newSubmitter(agrees, diverges).verifyOutput(context.Background(), output)
+		require.ErrorIs(t, err, ErrOutputRootDivergence)
+	})
+
+	t.Run("VerifierError", func(t *testing.T) {
+		verifier := new(testutils.MockRollupClient)
+		verifier.ExpectOutputAtBlock(42, nil, fmt.Errorf("boom"))
+		err := newSubmitter(verifier).verifyOutput(context.Background(), output)
+		require.ErrorContains(t, err, "boom")
+		require.NotErrorIs(t, err, ErrOutputRootDivergence)
+	})
+}
+
+type stubSupervisorClient struct {
+	status eth.SupervisorSyncStatus
+	supers map[uint64]eth.SuperRootResponse
+}
+
+func (s *stubSupervisorClient) SyncStatus(_ context.Context) (eth.SupervisorSyncStatus, error) {
+	return s.status, nil
+}
+
+func (s *stubSupervisorClient) SuperRootAtTimestamp(_ context.Context, timestamp uint64) (eth.SuperRootResponse, error) {
+	super, ok := s.supers[timestamp]
+	if !ok {
+		return eth.SuperRootResponse{}, fmt.Errorf("no super root at %d", timestamp)
+	}
+	return super, nil
+}
+
+func TestL2OutputSubmitter_FetchSuperRootProposal(t *testing.T) {
+	newSuper := func(timestamp uint64) eth.SuperRootResponse {
+		resp := eth.SuperRootResponse{
+			Timestamp: hexutil.Uint64(timestamp),
+			Chains: []eth.ChainRootInfo{
+				{ChainID: 1, Canonical: eth.Bytes32{0x01}},
+				{ChainID: 2, Canonical: eth.Bytes32{0x02}},
+			},
+		}
+		resp.SuperRoot = eth.SuperRoot(resp.Super())
+		return resp
+	}
+	newSubmitter := func(t *testing.T, supervisor *stubSupervisorClient, allowNonFinalized bool) *L2OutputSubmitter {
+		txmgr := txmgrmocks.NewTxManager(t)
+		txmgr.On("From").Return(common.Address{0xab})
+		return &L2OutputSubmitter{
+			DriverSetup: DriverSetup{
+				Log:              testlog.Logger(t, log.LevelDebug),
+				Cfg:              ProposerConfig{NetworkTimeout: time.Minute, AllowNonFinalized: allowNonFinalized},
+				Txmgr:            txmgr,
+				SupervisorClient: supervisor,
+			},
+			dgfContract: new(StubDGFContract),
+		}
+	}
+	supervisor := &stubSupervisorClient{
+		status: eth.SupervisorSyncStatus{
+			MinSyncedL1:        eth.L1BlockRef{Number: 50, Hash: common.Hash{0x50}},
+			SafeTimestamp:      2000,
+			FinalizedTimestamp: 1000,
+		},
+		supers: map[uint64]eth.SuperRootResponse{
+			1000: newSuper(1000),
+			2000: newSuper(2000),
+		},
+	}
+
+	t.Run("Finalized", func(t *testing.T) {
+		proposal, shouldPropose, err := newSubmitter(t, supervisor, false).FetchProposal(context.Background())
+		require.NoError(t, err)
+		require.True(t, shouldPropose)
+		expected := newSuper(1000)
+		require.Equal(t, Proposal{
+			Root:        common.Hash(expected.SuperRoot),
+			SequenceNum: 1000,
+			CurrentL1:   eth.BlockID{Number: 50, Hash: common.Hash{0x50}},
+			Super:       &expected,
+		}, proposal)
+	})
+
+	t.Run("Safe", func(t *testing.T) {
+		proposal, shouldPropose, err := newSubmitter(t, supervisor, true).FetchProposal(context.Background())
+		require.NoError(t, err)
+		require.True(t, shouldPropose)
+		require.Equal(t, uint64(2000), proposal.SequenceNum)
+	})
+
+	t.Run("NoTimestampYet", func(t *testing.T) {
+		_, shouldPropose, err := newSubmitter(t, &stubSupervisorClient{}, false).FetchProposal(context.Background())
+		require.NoError(t, err)
+		require.False(t, shouldPropose)
+	})
+
+	t.Run("InconsistentSuperRoot", func(t *testing.T) {
+		invalid := newSuper(1000)
+		invalid.SuperRoot = eth.Bytes32{0xba, 0xd0}
+		badSupervisor := &stubSupervisorClient{
+			status: supervisor.status,
+			supers: map[uint64]eth.SuperRootResponse{1000: invalid},
+		}
+		_, shouldPropose, err := newSubmitter(t, badSupervisor, false).FetchProposal(context.Background())
+		require.ErrorContains(t, err, "does not match its components")
+		require.False(t, shouldPropose)
+	})
+}
+
+func TestL2OutputSubmitter_AdminControls(t *testing.T) {
+	newSubmitter := func(t *testing.T) (*L2OutputSubmitter, *StubDGFContract) {
+		txmgr := txmgrmocks.NewTxManager(t)
+		txmgr.On("From").Return(common.Address{0xab}).Maybe()
+		dgf := new(StubDGFContract)
+		return &L2OutputSubmitter{
+			DriverSetup: DriverSetup{
+				Log:   testlog.Logger(t, log.LevelDebug),
+				Cfg:   ProposerConfig{ProposalInterval: time.Hour},
+				Txmgr: txmgr,
+			},
+			proposeNowCh: make(chan struct{}, 1),
+			dgfContract:  dgf,
+		}, dgf
+	}
+
+	t.Run("ProposeNowRequiresRunning", func(t *testing.T) {
+		l, _ := newSubmitter(t)
+		require.ErrorIs(t, l.ProposeNow(), ErrProposerNotRunning)
+	})
+
+	t.Run("ProposeNowForcesProposal", func(t *testing.T) {
+		l, dgf := newSubmitter(t)
+		l.running = true
+		require.NoError(t, l.ProposeNow())
+		require.True(t, l.Status().ProposeNow)
+		require.Len(t, l.proposeNowCh, 1)
+
+		due, err := l.dgfProposalDue(context.Background(), l.takeProposeNow())
+		require.NoError(t, err)
+		require.True(t, due)
+		require.Zero(t, dgf.hasProposedCount, "should not check for previous proposals")
+		require.False(t, l.Status().ProposeNow)
+	})
+
+	t.Run("SkipNext", func(t *testing.T) {
+		l, _ := newSubmitter(t)
+		require.NoError(t, l.SkipNextProposal())
+		require.True(t, l.Status().SkipNext)
+
+		due, err := l.dgfProposalDue(context.Background(), false)
+		require.NoError(t, err)
+		require.False(t, due, "should skip proposal")
+		require.False(t, l.Status().SkipNext)
+
+		due, err = l.dgfProposalDue(context.Background(), false)
+		require.NoError(t, err)
+		require.False(t, due, "should restart proposal interval after skip")
+	})
+
+	t.Run("ProposeNowNotSupportedForL2OO", func(t *testing.T) {
+		l := &L2OutputSubmitter{l2ooContract: new(MockL2OOContract), running: true, proposeNowCh: make(chan struct{}, 1)}
+		require.ErrorIs(t, l.ProposeNow(), ErrProposeNowNotSupported)
+		require.False(t, l.Status().ProposeNow)
+		require.Empty(t, l.proposeNowCh)
+	})
+
+	t.Run("SkipNotSupportedForL2OO", func(t *testing.T) {
+		l := &L2OutputSubmitter{l2ooContract: new(MockL2OOContract)}
+		require.ErrorIs(t, l.SkipNextProposal(), ErrSkipNotSupported)
+	})
+
+	t.Run("Pause", func(t *testing.T) {
+		l, dgf := newSubmitter(t)
+		l.PauseProposing()
+		require.True(t, l.Status().Paused)
+		l.tryPropose(context.Background())
+		require.Zero(t, dgf.hasProposedCount, "should not attempt proposal while paused")
+
+		l.ResumeProposing()
+		require.False(t, l.Status().Paused)
+	})
+}
@@ -0,0 +1,59 @@
+package proposer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// simulateProposal builds the proposal transaction and logs it along with its estimated cost, without sending it.
+func (l *L2OutputSubmitter) simulateProposal(ctx context.Context, proposal Proposal) error {
+	if output := proposal.Legacy; output != nil {
+		// Gas estimation fails until the L1 head is past the output's L1 block, as when sending
+		if err := l.waitForL1Head(ctx, output.Status.HeadL1.Number+1); err != nil {
+			return err
+		}
+	}
+	candidate, err := l.submissionTxCandidate(ctx, proposal)
+	if err != nil {
+		return fmt.Errorf("failed to create proposal tx: %w", err)
+	}
+
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	gas, err := l.L1Client.EstimateGas(cCtx, ethereum.CallMsg{
+		From:  l.Txmgr.From(),
+		To:    candidate.To,
+		Value: candidate.Value,
+		Data:  candidate.TxData,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	fee, err := l.currentL1Fee(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch L1 fee: %w", err)
+	}
+	cost := new(big.Int).Mul(fee, new(big.Int).SetUint64(gas))
+	if candidate.Value != nil {
+		cost.Add(cost, candidate.Value)
+	}
+
+	l.lastUnsent = trackedProposal{root: proposal.Root, seqNum: proposal.SequenceNum, since: time.Now()}
+	l.Log.Info("Dry run: not sending proposal",
+		"root", proposal.Root,
+		"sequenceNum", proposal.SequenceNum,
+		"to", candidate.To,
+		"value", candidate.Value,
+		"calldata", hexutil.Bytes(candidate.TxData),
+		"gas", gas,
+		"feePerGas", fee,
+		"estimatedCostEth", eth.WeiToEther(cost))
+	return nil
+}
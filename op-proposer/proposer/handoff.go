@@ -0,0 +1,78 @@
+package proposer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+)
+
+// HandoffBundle is a proposal transaction for an external signer or multisig to execute.
+// It uses the Safe transaction builder batch format so it can be imported directly into a Safe.
+type HandoffBundle struct {
+	Version      string      `json:"version"`
+	ChainID      string      `json:"chainId"`
+	CreatedAt    int64       `json:"createdAt"`
+	Meta         HandoffMeta `json:"meta"`
+	Transactions []HandoffTx `json:"transactions"`
+}
+
+type HandoffMeta struct {
+	Name                   string         `json:"name"`
+	Description            string         `json:"description"`
+	CreatedFromSafeAddress common.Address `json:"createdFromSafeAddress"`
+}
+
+type HandoffTx struct {
+	To    common.Address `json:"to"`
+	Value string         `json:"value"`
+	Data  hexutil.Bytes  `json:"data"`
+}
+
+// handoffProposal writes the proposal transaction to the handoff directory for an external signer to execute.
+func (l *L2OutputSubmitter) handoffProposal(ctx context.Context, proposal Proposal) error {
+	candidate, err := l.proposalTxCandidate(ctx, proposal)
+	if err != nil {
+		return fmt.Errorf("failed to create proposal tx: %w", err)
+	}
+	cCtx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	chainID, err := l.L1Client.ChainID(cCtx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch L1 chain ID: %w", err)
+	}
+
+	value := "0"
+	if candidate.Value != nil {
+		value = candidate.Value.String()
+	}
+	now := time.Now()
+	bundle := HandoffBundle{
+		Version:   "1.0",
+		ChainID:   chainID.String(),
+		CreatedAt: now.UnixMilli(),
+		Meta: HandoffMeta{
+			Name:                   fmt.Sprintf("Proposal %v", proposal.SequenceNum),
+			Description:            fmt.Sprintf("Propose root %v for L2 sequence number %v", proposal.Root, proposal.SequenceNum),
+			CreatedFromSafeAddress: l.proposerAddress(),
+		},
+		Transactions: []HandoffTx{{
+			To:    *candidate.To,
+			Value: value,
+			Data:  candidate.TxData,
+		}},
+	}
+	path := filepath.Join(l.Cfg.ProposalHandoffDir, fmt.Sprintf("proposal-%d-%s.json", proposal.SequenceNum, proposal.Root.Hex()[2:10]))
+	if err := jsonutil.WriteJSON(bundle, ioutil.ToAtomicFile(path, 0o644)); err != nil {
+		return fmt.Errorf("failed to write handoff bundle: %w", err)
+	}
+	l.lastUnsent = trackedProposal{root: proposal.Root, seqNum: proposal.SequenceNum, since: now}
+	l.Log.Info("Proposal handed off for external signing", "root", proposal.Root, "sequenceNum", proposal.SequenceNum, "path", path)
+	return nil
+}
@@ -0,0 +1,149 @@
+package proposer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+)
+
+// maxJournalEntries is the number of proposals retained in the journal.
+// Older proposed entries are dropped, pending entries are always retained.
+const maxJournalEntries = 1000
+
+type JournalStatus string
+
+const (
+	// JournalPending indicates the proposal transaction was being sent but is not yet known to be included.
+	JournalPending JournalStatus = "pending"
+	// JournalProposed indicates the proposal was included on L1.
+	JournalProposed JournalStatus = "proposed"
+)
+
+type JournalEntry struct {
+	Root        common.Hash   `json:"root"`
+	SequenceNum uint64        `json:"sequenceNum"`
+	Status      JournalStatus `json:"status"`
+	Time        time.Time     `json:"time"`
+	TxHash      common.Hash   `json:"txHash,omitempty"`
+	// Nonce is the nonce of the pending proposal transaction. Nil for entries written before nonces were recorded.
+	Nonce *uint64 `json:"nonce,omitempty"`
+}
+
+type journalData struct {
+	Entries []JournalEntry `json:"entries"`
+}
+
+// ProposalJournal durably records proposals before they are sent and once they are included,
+// so that a restarted proposer neither proposes twice nor skips a proposal that never made it on chain.
+type ProposalJournal struct {
+	path    string
+	lock    sync.Mutex
+	entries []JournalEntry
+}
+
+// OpenProposalJournal loads the journal at path, creating an empty journal if the file does not exist.
+func OpenProposalJournal(path string) (*ProposalJournal, error) {
+	j := &ProposalJournal{path: path}
+	data, err := jsonutil.LoadJSON[journalData](path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return j, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load proposal journal: %w", err)
+	}
+	j.entries = data.Entries
+	return j, nil
+}
+
+// RecordPending records that a proposal is about to be sent in a transaction with the given nonce.
+func (j *ProposalJournal) RecordPending(root common.Hash, seqNum uint64, nonce uint64, now time.Time) error {
+	return j.update(func() {
+		j.remove(root, seqNum)
+		j.entries = append(j.entries, JournalEntry{Root: root, SequenceNum: seqNum, Status: JournalPending, Time: now, Nonce: &nonce})
+	})
+}
+
+// RecordProposed records that a proposal was included on L1.
+func (j *ProposalJournal) RecordProposed(root common.Hash, seqNum uint64, txHash common.Hash, now time.Time) error {
+	return j.update(func() {
+		j.remove(root, seqNum)
+		j.entries = append(j.entries, JournalEntry{Root: root, SequenceNum: seqNum, Status: JournalProposed, Time: now, TxHash: txHash})
+	})
+}
+
+// RecordAbandoned removes a pending proposal that is known not to have been included and can no longer be,
+// allowing it to be proposed again.
+func (j *ProposalJournal) RecordAbandoned(root common.Hash, seqNum uint64) error {
+	return j.update(func() {
+		j.remove(root, seqNum)
+	})
+}
+
+// Pending returns all proposals that were sent but not yet confirmed to be included.
+func (j *ProposalJournal) Pending() []JournalEntry {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	var pending []JournalEntry
+	for _, entry := range j.entries {
+		if entry.Status == JournalPending {
+			pending = append(pending, entry)
+		}
+	}
+	return pending
+}
+
+// IsProposed returns true if the proposal is recorded as included on L1.
+func (j *ProposalJournal) IsProposed(root common.Hash, seqNum uint64) bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	for _, entry := range j.entries {
+		if entry.Root == root && entry.SequenceNum == seqNum && entry.Status == JournalProposed {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *ProposalJournal) remove(root common.Hash, seqNum uint64) {
+	entries := j.entries[:0]
+	for _, entry := range j.entries {
+		if entry.Root != root || entry.SequenceNum != seqNum {
+			entries = append(entries, entry)
+		}
+	}
+	j.entries = entries
+}
+
+func (j *ProposalJournal) update(fn func()) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	fn()
+	j.prune()
+	if err := jsonutil.WriteJSON(journalData{Entries: j.entries}, ioutil.ToAtomicFile(j.path, 0o644)); err != nil {
+		return fmt.Errorf("failed to write proposal journal: %w", err)
+	}
+	return nil
+}
+
+// prune drops the oldest proposed entries once the journal exceeds maxJournalEntries.
+func (j *ProposalJournal) prune() {
+	excess := len(j.entries) - maxJournalEntries
+	if excess <= 0 {
+		return
+	}
+	entries := make([]JournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		if excess > 0 && entry.Status == JournalProposed {
+			excess--
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	j.entries = entries
+}
@@ -0,0 +1,62 @@
+package proposer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	now := time.Unix(1000, 0).UTC()
+
+	t.Run("MissingFile", func(t *testing.T) {
+		journal, err := OpenProposalJournal(path)
+		require.NoError(t, err)
+		require.Empty(t, journal.Pending())
+	})
+
+	t.Run("PersistAcrossRestart", func(t *testing.T) {
+		journal, err := OpenProposalJournal(path)
+		require.NoError(t, err)
+		require.NoError(t, journal.RecordPending(common.Hash{0x01}, 1, 4, now))
+		require.NoError(t, journal.RecordPending(common.Hash{0x02}, 2, 5, now))
+		require.NoError(t, journal.RecordProposed(common.Hash{0x01}, 1, common.Hash{0xaa}, now))
+
+		reopened, err := OpenProposalJournal(path)
+		require.NoError(t, err)
+		require.True(t, reopened.IsProposed(common.Hash{0x01}, 1))
+		require.False(t, reopened.IsProposed(common.Hash{0x02}, 2))
+		nonce := uint64(5)
+		require.Equal(t, []JournalEntry{{Root: common.Hash{0x02}, SequenceNum: 2, Status: JournalPending, Time: now, Nonce: &nonce}}, reopened.Pending())
+	})
+
+	t.Run("Abandon", func(t *testing.T) {
+		journal, err := OpenProposalJournal(path)
+		require.NoError(t, err)
+		require.NoError(t, journal.RecordAbandoned(common.Hash{0x02}, 2))
+		require.Empty(t, journal.Pending())
+
+		reopened, err := OpenProposalJournal(path)
+		require.NoError(t, err)
+		require.Empty(t, reopened.Pending())
+		require.True(t, reopened.IsProposed(common.Hash{0x01}, 1))
+	})
+}
+
+func TestProposalJournal_Prune(t *testing.T) {
+	journal, err := OpenProposalJournal(filepath.Join(t.TempDir(), "journal.json"))
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	require.NoError(t, journal.RecordPending(common.Hash{0xff}, 0, 0, now))
+	for i := uint64(1); i <= maxJournalEntries+5; i++ {
+		require.NoError(t, journal.RecordProposed(common.Hash{0x01}, i, common.Hash{}, now))
+	}
+	require.Len(t, journal.entries, maxJournalEntries)
+	require.Len(t, journal.Pending(), 1, "pending entries should never be pruned")
+	require.False(t, journal.IsProposed(common.Hash{0x01}, 6))
+	require.True(t, journal.IsProposed(common.Hash{0x01}, 7))
+}
@@ -0,0 +1,166 @@
+// Package leader provides leader election between redundant proposer instances,
+// so that exactly one instance submits proposals at a time.
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+)
+
+// Elector decides whether this proposer instance is currently the leader.
+type Elector interface {
+	// Campaign attempts to acquire or renew leadership and reports whether this instance is the leader.
+	// It is called before every proposal attempt and every poll interval while a proposal is sent, so implementations
+	// must renew any lease they hold.
+	Campaign(ctx context.Context) (bool, error)
+
+	// Resign gives up leadership if it is held, allowing another instance to take over immediately.
+	Resign(ctx context.Context) error
+}
+
+// AlwaysLeader is used when leader election is disabled and the proposer runs as a single instance.
+type AlwaysLeader struct{}
+
+func (AlwaysLeader) Campaign(_ context.Context) (bool, error) {
+	return true, nil
+}
+
+func (AlwaysLeader) Resign(_ context.Context) error {
+	return nil
+}
+
+var _ Elector = AlwaysLeader{}
+
+// lease is the on-disk record of the current leader.
+type lease struct {
+	Holder string    `json:"holder"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// FileLease elects a leader via a lease file on storage shared by all proposer instances.
+// The leader must renew the lease before it expires, otherwise another instance takes over.
+// Access to the lease file is serialized with an advisory lock on a sibling ".lock" file.
+type FileLease struct {
+	path     string
+	id       string
+	duration time.Duration
+	clock    clock.Clock
+	lock     *flock.Flock
+}
+
+func NewFileLease(path string, id string, duration time.Duration, cl clock.Clock) *FileLease {
+	return &FileLease{
+		path:     path,
+		id:       id,
+		duration: duration,
+		clock:    cl,
+		lock:     flock.New(path + ".lock"),
+	}
+}
+
+func (f *FileLease) Campaign(ctx context.Context) (bool, error) {
+	var leader bool
+	err := f.withLock(ctx, func() error {
+		current, err := f.read()
+		if err != nil {
+			return err
+		}
+		now := f.clock.Now()
+		if current.Holder != "" && current.Holder != f.id && now.Before(current.Expiry) {
+			// Another instance holds a valid lease
+			return nil
+		}
+		leader = true
+		return f.write(lease{Holder: f.id, Expiry: now.Add(f.duration)})
+	})
+	if err != nil {
+		return false, err
+	}
+	return leader, nil
+}
+
+func (f *FileLease) Resign(ctx context.Context) error {
+	return f.withLock(ctx, func() error {
+		current, err := f.read()
+		if err != nil {
+			return err
+		}
+		if current.Holder != f.id {
+			return nil
+		}
+		return f.write(lease{})
+	})
+}
+
+// Leader returns the current lease holder, or the empty string if the lease is vacant or expired.
+func (f *FileLease) Leader(ctx context.Context) (string, error) {
+	var holder string
+	err := f.withLock(ctx, func() error {
+		current, err := f.read()
+		if err != nil {
+			return err
+		}
+		if f.clock.Now().Before(current.Expiry) {
+			holder = current.Holder
+		}
+		return nil
+	})
+	return holder, err
+}
+
+func (f *FileLease) withLock(ctx context.Context, fn func() error) error {
+	locked, err := f.lock.TryLockContext(ctx, 10*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("failed to lock lease file: %w", err)
+	}
+	if !locked {
+		return errors.New("failed to lock lease file")
+	}
+	defer func() {
+		_ = f.lock.Unlock()
+	}()
+	return fn()
+}
+
+func (f *FileLease) read() (lease, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lease{}, nil
+	} else if err != nil {
+		return lease{}, fmt.Errorf("failed to read lease file: %w", err)
+	}
+	var l lease
+	if len(data) == 0 {
+		return l, nil
+	}
+	if err := json.Unmarshal(data, &l); err != nil {
+		return lease{}, fmt.Errorf("failed to parse lease file: %w", err)
+	}
+	return l, nil
+}
+
+func (f *FileLease) write(l lease) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to encode lease: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write lease file: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to replace lease file: %w", err)
+	}
+	return nil
+}
+
+var _ Elector = (*FileLease)(nil)
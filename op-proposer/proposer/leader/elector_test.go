@@ -0,0 +1,73 @@
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+)
+
+func TestFileLease(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "lease.json")
+	cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+	a := NewFileLease(path, "a", time.Minute, cl)
+	b := NewFileLease(path, "b", time.Minute, cl)
+
+	t.Run("FirstCandidateWins", func(t *testing.T) {
+		leader, err := a.Campaign(ctx)
+		require.NoError(t, err)
+		require.True(t, leader)
+
+		leader, err = b.Campaign(ctx)
+		require.NoError(t, err)
+		require.False(t, leader)
+
+		holder, err := b.Leader(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "a", holder)
+	})
+
+	t.Run("LeaderRenews", func(t *testing.T) {
+		cl.AdvanceTime(50 * time.Second)
+		leader, err := a.Campaign(ctx)
+		require.NoError(t, err)
+		require.True(t, leader)
+
+		cl.AdvanceTime(50 * time.Second)
+		leader, err = b.Campaign(ctx)
+		require.NoError(t, err)
+		require.False(t, leader)
+	})
+
+	t.Run("TakeoverAfterExpiry", func(t *testing.T) {
+		cl.AdvanceTime(time.Minute)
+		leader, err := b.Campaign(ctx)
+		require.NoError(t, err)
+		require.True(t, leader)
+
+		leader, err = a.Campaign(ctx)
+		require.NoError(t, err)
+		require.False(t, leader)
+	})
+
+	t.Run("TakeoverAfterResign", func(t *testing.T) {
+		require.NoError(t, a.Resign(ctx)) // Not the leader so has no effect
+		leader, err := a.Campaign(ctx)
+		require.NoError(t, err)
+		require.False(t, leader)
+
+		require.NoError(t, b.Resign(ctx))
+		holder, err := a.Leader(ctx)
+		require.NoError(t, err)
+		require.Empty(t, holder)
+
+		leader, err = a.Campaign(ctx)
+		require.NoError(t, err)
+		require.True(t, leader)
+	})
+}
@@ -0,0 +1,33 @@
+package proposer
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Proposal is a root to propose, either an output root of a single chain or an interop super root.
+type Proposal struct {
+	// Root is the output root or super root to propose
+	Root common.Hash
+	// SequenceNum identifies the proposal: the L2 block number for output roots, or the timestamp for super roots
+	SequenceNum uint64
+	// CurrentL1 is the L1 block the proposed root was derived from
+	CurrentL1 eth.BlockID
+
+	// Legacy is the output the proposal is based on. Only set for output root proposals.
+	Legacy *eth.OutputResponse
+	// Super is the super root the proposal is based on, including its per-chain output components.
+	// Only set for super root proposals.
+	Super *eth.SuperRootResponse
+}
+
+// OutputProposal creates a proposal for the output root of a single chain.
+func OutputProposal(output *eth.OutputResponse) Proposal {
+	return Proposal{
+		Root:        common.Hash(output.OutputRoot),
+		SequenceNum: output.BlockRef.Number,
+		CurrentL1:   output.Status.CurrentL1.ID(),
+		Legacy:      output,
+	}
+}
@@ -13,6 +13,11 @@ import (
 type ProposerDriver interface {
 	StartL2OutputSubmitting() error
 	StopL2OutputSubmitting() error
+	ProposeNow() error
+	SkipNextProposal() error
+	PauseProposing()
+	ResumeProposing()
+	Status() ProposerStatus
 }
 
 type adminAPI struct {
@@ -41,3 +46,30 @@ func (a *adminAPI) StartProposer(_ context.Context) error {
 func (a *adminAPI) StopProposer(ctx context.Context) error {
 	return a.b.StopL2OutputSubmitting()
 }
+
+// ProposeNow triggers a proposal immediately, ignoring the proposal interval.
+func (a *adminAPI) ProposeNow(_ context.Context) error {
+	return a.b.ProposeNow()
+}
+
+// SkipNextProposal skips the next scheduled proposal, restarting the proposal interval.
+func (a *adminAPI) SkipNextProposal(_ context.Context) error {
+	return a.b.SkipNextProposal()
+}
+
+// PauseProposer pauses proposing while keeping the proposer running, so it can be resumed immediately.
+func (a *adminAPI) PauseProposer(_ context.Context) error {
+	a.b.PauseProposing()
+	return nil
+}
+
+// ResumeProposer resumes proposing after a pause.
+func (a *adminAPI) ResumeProposer(_ context.Context) error {
+	a.b.ResumeProposing()
+	return nil
+}
+
+// ProposerStatus returns the current state of the proposal pipeline.
+func (a *adminAPI) ProposerStatus(_ context.Context) (ProposerStatus, error) {
+	return a.b.Status(), nil
+}
@@ -0,0 +1,34 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProposalInfo describes a proposal made, or being made, by the proposer.
+type ProposalInfo struct {
+	Root        common.Hash `json:"root"`
+	SequenceNum uint64      `json:"sequenceNum"`
+	Time        time.Time   `json:"time"`
+	TxHash      common.Hash `json:"txHash,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// ProposerStatus is a snapshot of the proposal pipeline.
+type ProposerStatus struct {
+	// Running is true if the proposer loop is running
+	Running bool `json:"running"`
+	// Paused is true if proposing has been paused via the admin API
+	Paused bool `json:"paused"`
+	// Leader is true if this instance was the leader at the last proposal attempt
+	Leader bool `json:"leader"`
+	// ProposeNow is true if an immediate proposal has been requested but not yet attempted
+	ProposeNow bool `json:"proposeNow"`
+	// SkipNext is true if the next scheduled proposal will be skipped
+	SkipNext bool `json:"skipNext"`
+	// Pending is the proposal currently being submitted, if any
+	Pending *ProposalInfo `json:"pending,omitempty"`
+	// Last is the most recently completed proposal attempt, if any
+	Last *ProposalInfo `json:"last,omitempty"`
+}
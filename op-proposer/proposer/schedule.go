@@ -0,0 +1,61 @@
+package proposer
+
+import (
+	"math/big"
+	"time"
+)
+
+// ProposalSchedule determines when a dispute game proposal is due, allowing the proposal interval to
+// flex with L1 fees. Proposals are made every Interval by default. When fees are at or below CheapFee,
+// proposals may be made as soon as MinInterval has elapsed. When fees are above ExpensiveFee, proposals
+// are delayed, but never beyond MaxInterval.
+type ProposalSchedule struct {
+	Interval    time.Duration
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	// CheapFee is the fee at or below which proposals are made early. Nil disables early proposals.
+	CheapFee *big.Int
+	// ExpensiveFee is the fee above which proposals are delayed. Nil disables delaying proposals.
+	ExpensiveFee *big.Int
+}
+
+// FeeAware returns true if the schedule depends on L1 fees.
+func (s ProposalSchedule) FeeAware() bool {
+	return (s.CheapFee != nil && s.MinInterval < s.Interval) || (s.ExpensiveFee != nil && s.MaxInterval > s.Interval)
+}
+
+// LookbackWindow returns how far back the last proposal must be searched for to apply the schedule.
+func (s ProposalSchedule) LookbackWindow() time.Duration {
+	return max(s.Interval, s.MaxInterval)
+}
+
+// Due returns whether a proposal should be made given the time elapsed since the last proposal and the current fee.
+// The fee is only consulted if the elapsed time falls within the flexible part of the schedule, so may be nil otherwise.
+func (s ProposalSchedule) Due(elapsed time.Duration, fee *big.Int) bool {
+	switch {
+	case elapsed >= s.LookbackWindow():
+		// Hard maximum reached, always propose to preserve liveness
+		return true
+	case elapsed >= s.Interval:
+		return s.ExpensiveFee == nil || fee == nil || fee.Cmp(s.ExpensiveFee) <= 0
+	case elapsed >= s.MinInterval:
+		return s.CheapFee != nil && fee != nil && fee.Cmp(s.CheapFee) <= 0
+	default:
+		return false
+	}
+}
+
+// NeedsFee returns whether the fee is required to decide if a proposal is due after the elapsed time.
+func (s ProposalSchedule) NeedsFee(elapsed time.Duration) bool {
+	switch {
+	case elapsed >= s.LookbackWindow():
+		return false
+	case elapsed >= s.Interval:
+		return s.ExpensiveFee != nil
+	case elapsed >= s.MinInterval:
+		return s.CheapFee != nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,55 @@
+package proposer
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalSchedule(t *testing.T) {
+	cheap := big.NewInt(10)
+	normal := big.NewInt(50)
+	expensive := big.NewInt(100)
+	schedule := ProposalSchedule{
+		Interval:     time.Hour,
+		MinInterval:  30 * time.Minute,
+		MaxInterval:  2 * time.Hour,
+		CheapFee:     cheap,
+		ExpensiveFee: big.NewInt(75),
+	}
+	require.True(t, schedule.FeeAware())
+	require.Equal(t, 2*time.Hour, schedule.LookbackWindow())
+
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		fee      *big.Int
+		due      bool
+		needsFee bool
+	}{
+		{name: "TooEarlyEvenWhenCheap", elapsed: 29 * time.Minute, fee: cheap, due: false, needsFee: false},
+		{name: "EarlyWhenCheap", elapsed: 30 * time.Minute, fee: cheap, due: true, needsFee: true},
+		{name: "NotEarlyWhenNormal", elapsed: 45 * time.Minute, fee: normal, due: false, needsFee: true},
+		{name: "OnScheduleWhenNormal", elapsed: time.Hour, fee: normal, due: true, needsFee: true},
+		{name: "DelayedWhenExpensive", elapsed: 90 * time.Minute, fee: expensive, due: false, needsFee: true},
+		{name: "HardMaximumWhenExpensive", elapsed: 2 * time.Hour, fee: expensive, due: true, needsFee: false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.due, schedule.Due(test.elapsed, test.fee))
+			require.Equal(t, test.needsFee, schedule.NeedsFee(test.elapsed))
+		})
+	}
+}
+
+func TestProposalScheduleFixedInterval(t *testing.T) {
+	schedule := ProposalSchedule{Interval: time.Hour, MinInterval: time.Hour}
+	require.False(t, schedule.FeeAware())
+	require.Equal(t, time.Hour, schedule.LookbackWindow())
+	require.False(t, schedule.Due(59*time.Minute, nil))
+	require.True(t, schedule.Due(time.Hour, nil))
+	require.False(t, schedule.NeedsFee(time.Hour))
+}
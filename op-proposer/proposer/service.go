@@ -5,19 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-proposer/proposer/leader"
 	"github.com/ethereum-optimism/optimism/op-proposer/proposer/rpc"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/httputil"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 
@@ -35,6 +40,12 @@ type ProposerConfig struct {
 
 	// How frequently to post L2 outputs when the DisputeGameFactory is configured
 	ProposalInterval time.Duration
+	// Bounds on the proposal interval when it flexes with L1 fees. Zero values default to ProposalInterval.
+	ProposalMinInterval time.Duration
+	ProposalMaxInterval time.Duration
+	// L1 fee thresholds used to propose early or delay proposals. Nil disables the respective behaviour.
+	ProposalCheapFee     *big.Int
+	ProposalExpensiveFee *big.Int
 
 	L2OutputOracleAddr     *common.Address
 	DisputeGameFactoryAddr *common.Address
@@ -47,6 +58,37 @@ type ProposerConfig struct {
 	AllowNonFinalized bool
 
 	WaitNodeSync bool
+
+	// DryRun logs the proposals that would be made, without sending transactions.
+	DryRun bool
+
+	// ProposerWallet is the Safe holding the proposer role. Proposals are sent directly from the tx sender if nil.
+	ProposerWallet *common.Address
+	// ProposalHandoffDir is the directory proposal bundles are written to for an external signer, instead of sending.
+	ProposalHandoffDir string
+}
+
+// SendsProposals returns true if proposal transactions are sent, rather than only logged or handed off.
+func (c ProposerConfig) SendsProposals() bool {
+	return !c.DryRun && c.ProposalHandoffDir == ""
+}
+
+// ProposalSchedule returns the schedule for dispute game proposals.
+func (c ProposerConfig) ProposalSchedule() ProposalSchedule {
+	schedule := ProposalSchedule{
+		Interval:     c.ProposalInterval,
+		MinInterval:  c.ProposalMinInterval,
+		MaxInterval:  c.ProposalMaxInterval,
+		CheapFee:     c.ProposalCheapFee,
+		ExpensiveFee: c.ProposalExpensiveFee,
+	}
+	if schedule.MinInterval == 0 {
+		schedule.MinInterval = schedule.Interval
+	}
+	if schedule.MaxInterval == 0 {
+		schedule.MaxInterval = schedule.Interval
+	}
+	return schedule
 }
 
 type ProposerService struct {
@@ -58,6 +100,16 @@ type ProposerService struct {
 	TxManager      txmgr.TxManager
 	L1Client       *ethclient.Client
 	RollupProvider dial.RollupProvider
+	Elector        leader.Elector
+
+	// Verifiers are the additional rollup clients used to cross-verify output roots
+	Verifiers []*sources.RollupClient
+
+	// SupervisorClient is set when proposing super roots
+	SupervisorClient *sources.SupervisorClient
+
+	// Journal records proposals across restarts, nil if disabled
+	Journal *ProposalJournal
 
 	driver *L2OutputSubmitter
 
@@ -93,9 +145,23 @@ func (ps *ProposerService) initFromCLIConfig(ctx context.Context, version string
 	ps.NetworkTimeout = cfg.TxMgrConfig.NetworkTimeout
 	ps.AllowNonFinalized = cfg.AllowNonFinalized
 	ps.WaitNodeSync = cfg.WaitNodeSync
+	ps.DryRun = cfg.DryRun
+	if ps.DryRun {
+		ps.Log.Warn("Dry run mode enabled, proposals will be logged but not sent")
+	}
+	if wallet, err := opservice.ParseAddress(cfg.ProposerWallet); err == nil {
+		ps.ProposerWallet = &wallet
+	}
+	ps.ProposalHandoffDir = cfg.ProposalHandoffDir
 
 	ps.initL2ooAddress(cfg)
-	ps.initDGF(cfg)
+	if err := ps.initDGF(cfg); err != nil {
+		return err
+	}
+	ps.initElector(cfg)
+	if err := ps.initJournal(cfg); err != nil {
+		return err
+	}
 
 	if err := ps.initRPCClients(ctx, cfg); err != nil {
 		return err
@@ -129,6 +195,15 @@ func (ps *ProposerService) initRPCClients(ctx context.Context, cfg *CLIConfig) e
 	}
 	ps.L1Client = l1Client
 
+	if cfg.SupervisorRpc != "" {
+		supervisorClient, err := dial.DialSupervisorClientWithTimeout(ctx, dial.DefaultDialTimeout, ps.Log, cfg.SupervisorRpc)
+		if err != nil {
+			return fmt.Errorf("failed to dial supervisor RPC: %w", err)
+		}
+		ps.SupervisorClient = supervisorClient
+		return nil
+	}
+
 	var rollupProvider dial.RollupProvider
 	if strings.Contains(cfg.RollupRpc, ",") {
 		rollupUrls := strings.Split(cfg.RollupRpc, ",")
@@ -140,6 +215,14 @@ func (ps *ProposerService) initRPCClients(ctx context.Context, cfg *CLIConfig) e
 		return fmt.Errorf("failed to build L2 endpoint provider: %w", err)
 	}
 	ps.RollupProvider = rollupProvider
+
+	for _, url := range cfg.VerifierRollupRpcs {
+		verifier, err := dial.DialRollupClientWithTimeout(ctx, dial.DefaultDialTimeout, ps.Log, url)
+		if err != nil {
+			return fmt.Errorf("failed to dial verifier rollup RPC %v: %w", url, err)
+		}
+		ps.Verifiers = append(ps.Verifiers, verifier)
+	}
 	return nil
 }
 
@@ -213,19 +296,69 @@ func (ps *ProposerService) initL2ooAddress(cfg *CLIConfig) {
 	ps.L2OutputOracleAddr = &l2ooAddress
 }
 
-func (ps *ProposerService) initDGF(cfg *CLIConfig) {
+func (ps *ProposerService) initDGF(cfg *CLIConfig) error {
 	dgfAddress, err := opservice.ParseAddress(cfg.DGFAddress)
 	if err != nil {
 		// Return no error & set no DGF related configuration fields.
-		return
+		return nil
 	}
 	ps.DisputeGameFactoryAddr = &dgfAddress
 	ps.ProposalInterval = cfg.ProposalInterval
+	ps.ProposalMinInterval = cfg.ProposalMinInterval
+	ps.ProposalMaxInterval = cfg.ProposalMaxInterval
 	ps.DisputeGameType = cfg.DisputeGameType
+	if cfg.ProposalCheapFeeGwei != 0 {
+		ps.ProposalCheapFee, err = eth.GweiToWei(cfg.ProposalCheapFeeGwei)
+		if err != nil {
+			return fmt.Errorf("invalid cheap proposal fee: %w", err)
+		}
+	}
+	if cfg.ProposalExpensiveFeeGwei != 0 {
+		ps.ProposalExpensiveFee, err = eth.GweiToWei(cfg.ProposalExpensiveFeeGwei)
+		if err != nil {
+			return fmt.Errorf("invalid expensive proposal fee: %w", err)
+		}
+	}
+	schedule := ps.ProposalSchedule()
+	if schedule.FeeAware() {
+		ps.Log.Info("Proposal interval flexes with L1 fees", "interval", schedule.Interval,
+			"minInterval", schedule.MinInterval, "maxInterval", schedule.MaxInterval,
+			"cheapFee", schedule.CheapFee, "expensiveFee", schedule.ExpensiveFee)
+	} else if schedule.CheapFee != nil || schedule.ExpensiveFee != nil {
+		ps.Log.Warn("Proposal fee thresholds have no effect without a min interval below or max interval above the proposal interval",
+			"interval", schedule.Interval, "minInterval", schedule.MinInterval, "maxInterval", schedule.MaxInterval)
+	}
+	return nil
+}
+
+func (ps *ProposerService) initElector(cfg *CLIConfig) {
+	if cfg.LeaderLeaseFile == "" {
+		ps.Elector = leader.AlwaysLeader{}
+		return
+	}
+	ps.Log.Info("Leader election enabled", "leaseFile", cfg.LeaderLeaseFile, "leaseDuration", cfg.LeaderLeaseDuration, "id", cfg.LeaderID)
+	ps.Elector = leader.NewFileLease(cfg.LeaderLeaseFile, cfg.LeaderID, cfg.LeaderLeaseDuration, clock.SystemClock)
+}
+
+func (ps *ProposerService) initJournal(cfg *CLIConfig) error {
+	if cfg.ProposalJournal == "" {
+		return nil
+	}
+	journal, err := OpenProposalJournal(cfg.ProposalJournal)
+	if err != nil {
+		return err
+	}
+	ps.Log.Info("Proposal journal enabled", "path", cfg.ProposalJournal, "pending", len(journal.Pending()))
+	ps.Journal = journal
+	return nil
 }
 
 func (ps *ProposerService) initDriver() error {
-	driver, err := NewL2OutputSubmitter(DriverSetup{
+	verifiers := make([]RollupClient, len(ps.Verifiers))
+	for i, v := range ps.Verifiers {
+		verifiers[i] = v
+	}
+	setup := DriverSetup{
 		Log:            ps.Log,
 		Metr:           ps.Metrics,
 		Cfg:            ps.ProposerConfig,
@@ -233,7 +366,14 @@ func (ps *ProposerService) initDriver() error {
 		L1Client:       ps.L1Client,
 		Multicaller:    batching.NewMultiCaller(ps.L1Client.Client(), batching.DefaultBatchSize),
 		RollupProvider: ps.RollupProvider,
-	})
+		Elector:        ps.Elector,
+		Verifiers:      verifiers,
+		Journal:        ps.Journal,
+	}
+	if ps.SupervisorClient != nil {
+		setup.SupervisorClient = ps.SupervisorClient
+	}
+	driver, err := NewL2OutputSubmitter(setup)
 	if err != nil {
 		return err
 	}
@@ -330,6 +470,14 @@ func (ps *ProposerService) Stop(ctx context.Context) error {
 		ps.RollupProvider.Close()
 	}
 
+	for _, verifier := range ps.Verifiers {
+		verifier.Close()
+	}
+
+	if ps.SupervisorClient != nil {
+		ps.SupervisorClient.Close()
+	}
+
 	if result == nil {
 		ps.stopped.Store(true)
 		ps.Log.Info("L2Output Submitter stopped")
@@ -47,6 +47,20 @@ func DialRollupClientWithTimeout(ctx context.Context, timeout time.Duration, log
 	return sources.NewRollupClient(client.NewBaseRPCClient(rpcCl)), nil
 }
 
+// DialSupervisorClientWithTimeout attempts to dial the RPC provider using the provided URL.
+// If the dial doesn't complete within timeout seconds, this method will return an error.
+func DialSupervisorClientWithTimeout(ctx context.Context, timeout time.Duration, log log.Logger, url string) (*sources.SupervisorClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rpcCl, err := dialRPCClientWithBackoff(ctx, log, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return sources.NewSupervisorClient(client.NewBaseRPCClient(rpcCl)), nil
+}
+
 // DialRPCClientWithTimeout attempts to dial the RPC provider using the provided URL.
 // If the dial doesn't complete within timeout seconds, this method will return an error.
 func DialRPCClientWithTimeout(ctx context.Context, timeout time.Duration, log log.Logger, url string) (*rpc.Client, error) {
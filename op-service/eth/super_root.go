@@ -0,0 +1,138 @@
+package eth
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrInvalidSuperRoot        = errors.New("invalid super root")
+	ErrInvalidSuperRootVersion = errors.New("invalid super root version")
+)
+
+const (
+	SuperRootVersionV1 = byte(1)
+
+	// superRootV1MinLen is the length of a marshaled SuperV1 with no chains: version byte and timestamp
+	superRootV1MinLen = 1 + 8
+	// chainIDAndOutputLen is the marshaled length of a single chain ID and output root pair
+	chainIDAndOutputLen = 32 + 32
+)
+
+type Super interface {
+	// Version returns the version of the super root
+	Version() byte
+
+	// Marshal a super root into a byte slice for hashing
+	Marshal() []byte
+}
+
+// ChainIDAndOutput is the output root of a single chain included in a super root.
+type ChainIDAndOutput struct {
+	ChainID uint64
+	Output  Bytes32
+}
+
+// SuperV1 commits to the output roots of all chains in the dependency set at a given timestamp.
+// Chains must be sorted by ascending chain ID.
+type SuperV1 struct {
+	Timestamp uint64
+	Chains    []ChainIDAndOutput
+}
+
+func (o *SuperV1) Version() byte {
+	return SuperRootVersionV1
+}
+
+func (o *SuperV1) Marshal() []byte {
+	buf := make([]byte, superRootV1MinLen+len(o.Chains)*chainIDAndOutputLen)
+	buf[0] = o.Version()
+	binary.BigEndian.PutUint64(buf[1:9], o.Timestamp)
+	offset := superRootV1MinLen
+	for _, chain := range o.Chains {
+		// Chain IDs are encoded as uint256 values
+		binary.BigEndian.PutUint64(buf[offset+24:offset+32], chain.ChainID)
+		copy(buf[offset+32:offset+64], chain.Output[:])
+		offset += chainIDAndOutputLen
+	}
+	return buf
+}
+
+// SuperRoot returns the keccak256 hash of the marshaled super root
+func SuperRoot(super Super) Bytes32 {
+	marshaled := super.Marshal()
+	return Bytes32(crypto.Keccak256Hash(marshaled))
+}
+
+func UnmarshalSuperRoot(data []byte) (Super, error) {
+	if len(data) < 1 {
+		return nil, ErrInvalidSuperRoot
+	}
+	switch data[0] {
+	case SuperRootVersionV1:
+		return unmarshalSuperRootV1(data)
+	default:
+		return nil, ErrInvalidSuperRootVersion
+	}
+}
+
+func unmarshalSuperRootV1(data []byte) (*SuperV1, error) {
+	if len(data) < superRootV1MinLen || (len(data)-superRootV1MinLen)%chainIDAndOutputLen != 0 {
+		return nil, ErrInvalidSuperRoot
+	}
+	var output SuperV1
+	// data[:1] is the version
+	output.Timestamp = binary.BigEndian.Uint64(data[1:9])
+	for i := superRootV1MinLen; i < len(data); i += chainIDAndOutputLen {
+		for _, b := range data[i : i+24] {
+			if b != 0 {
+				// Chain ID exceeds uint64
+				return nil, ErrInvalidSuperRoot
+			}
+		}
+		chain := ChainIDAndOutput{ChainID: binary.BigEndian.Uint64(data[i+24 : i+32])}
+		copy(chain.Output[:], data[i+32:i+64])
+		output.Chains = append(output.Chains, chain)
+	}
+	return &output, nil
+}
+
+// ChainRootInfo is the output root of a single chain as reported by the supervisor.
+type ChainRootInfo struct {
+	ChainID hexutil.Uint64 `json:"chainID"`
+	// Canonical is the output root of the latest canonical block at the super root timestamp.
+	Canonical Bytes32 `json:"canonical"`
+	// Pending is the marshaled output of the optimistic next block, used for interop transition proofs.
+	Pending hexutil.Bytes `json:"pending"`
+}
+
+// SuperRootResponse is the super root at a given timestamp, with the per-chain components it commits to.
+type SuperRootResponse struct {
+	// CrossSafeDerivedFrom is the L1 block from which all chains became cross-safe at the super root timestamp.
+	CrossSafeDerivedFrom BlockID         `json:"crossSafeDerivedFrom"`
+	Timestamp            hexutil.Uint64  `json:"timestamp"`
+	SuperRoot            Bytes32         `json:"superRoot"`
+	Chains               []ChainRootInfo `json:"chains"`
+}
+
+// Super reconstructs the super root preimage from the per-chain components of the response.
+func (r *SuperRootResponse) Super() *SuperV1 {
+	super := &SuperV1{Timestamp: uint64(r.Timestamp)}
+	for _, chain := range r.Chains {
+		super.Chains = append(super.Chains, ChainIDAndOutput{ChainID: uint64(chain.ChainID), Output: chain.Canonical})
+	}
+	return super
+}
+
+// SupervisorSyncStatus is the sync status of the supervisor across all chains in the dependency set.
+type SupervisorSyncStatus struct {
+	// MinSyncedL1 is the highest L1 block that all chains have been synced to.
+	MinSyncedL1 L1BlockRef `json:"minSyncedL1"`
+	// SafeTimestamp is the highest timestamp at which all chains are cross-safe.
+	SafeTimestamp hexutil.Uint64 `json:"safeTimestamp"`
+	// FinalizedTimestamp is the highest timestamp at which all chains are finalized.
+	FinalizedTimestamp hexutil.Uint64 `json:"finalizedTimestamp"`
+}
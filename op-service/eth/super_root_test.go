@@ -0,0 +1,64 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuperRootV1Codec(t *testing.T) {
+	t.Run("NoChains", func(t *testing.T) {
+		super := SuperV1{Timestamp: 7000}
+		marshaled := super.Marshal()
+		require.Len(t, marshaled, superRootV1MinLen)
+		unmarshaled, err := UnmarshalSuperRoot(marshaled)
+		require.NoError(t, err)
+		require.Equal(t, super, *unmarshaled.(*SuperV1))
+	})
+
+	t.Run("WithChains", func(t *testing.T) {
+		super := SuperV1{
+			Timestamp: 7000,
+			Chains: []ChainIDAndOutput{
+				{ChainID: 10, Output: Bytes32{1, 2, 3}},
+				{ChainID: 11, Output: Bytes32{4, 5, 6}},
+			},
+		}
+		marshaled := super.Marshal()
+		require.Equal(t, SuperRootVersionV1, marshaled[0])
+		unmarshaled, err := UnmarshalSuperRoot(marshaled)
+		require.NoError(t, err)
+		require.Equal(t, super, *unmarshaled.(*SuperV1))
+		require.Equal(t, Bytes32(crypto.Keccak256Hash(marshaled)), SuperRoot(&super))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := UnmarshalSuperRoot([]byte{})
+		require.ErrorIs(t, err, ErrInvalidSuperRoot)
+		_, err = UnmarshalSuperRoot([]byte{0: 0xA, 8: 0})
+		require.ErrorIs(t, err, ErrInvalidSuperRootVersion)
+		_, err = UnmarshalSuperRoot([]byte{0: SuperRootVersionV1, 9: 0})
+		require.ErrorIs(t, err, ErrInvalidSuperRoot)
+		// Chain ID exceeds uint64
+		_, err = UnmarshalSuperRoot([]byte{0: SuperRootVersionV1, 9: 1, 72: 0})
+		require.ErrorIs(t, err, ErrInvalidSuperRoot)
+	})
+}
+
+func TestSuperRootResponseSuper(t *testing.T) {
+	resp := SuperRootResponse{
+		Timestamp: 42,
+		Chains: []ChainRootInfo{
+			{ChainID: 1, Canonical: Bytes32{1}},
+			{ChainID: 2, Canonical: Bytes32{2}},
+		},
+	}
+	require.Equal(t, &SuperV1{
+		Timestamp: 42,
+		Chains: []ChainIDAndOutput{
+			{ChainID: 1, Output: Bytes32{1}},
+			{ChainID: 2, Output: Bytes32{2}},
+		},
+	}, resp.Super())
+}
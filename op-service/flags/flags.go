@@ -6,6 +6,9 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 )
@@ -19,6 +22,8 @@ const (
 	FjordOverrideFlagName    = "override.fjord"
 	GraniteOverrideFlagName  = "override.granite"
 	HoloceneOverrideFlagName = "override.holocene"
+	RegistryFlagName         = "registry"
+	RegistryHashFlagName     = "registry.sha256"
 )
 
 func CLIFlags(envPrefix string, category string) []cli.Flag {
@@ -88,6 +93,45 @@ func CLIRollupConfigFlag(envPrefix string, category string) cli.Flag {
 	}
 }
 
+// CLIRegistryFlags are the flags to load chain configs and dependency sets from a registry bundle,
+// instead of the superchain-registry embedded in the binary.
+func CLIRegistryFlags(envPrefix string, category string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     RegistryFlagName,
+			Usage:    "URL or file path of a registry bundle to load the network from, instead of the embedded superchain-registry",
+			EnvVars:  opservice.PrefixEnvVar(envPrefix, "REGISTRY"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     RegistryHashFlagName,
+			Usage:    "SHA-256 hash the registry bundle must match. Required for URLs.",
+			EnvVars:  opservice.PrefixEnvVar(envPrefix, "REGISTRY_SHA256"),
+			Category: category,
+		},
+	}
+}
+
+// LoadRegistry loads the registry selected by the registry flags, or the embedded registry if none is selected.
+func LoadRegistry(ctx *cli.Context) (*chaincfg.Registry, error) {
+	source := ctx.String(RegistryFlagName)
+	if source == "" {
+		if ctx.IsSet(RegistryHashFlagName) {
+			return nil, fmt.Errorf("flag %s requires flag %s", RegistryHashFlagName, RegistryFlagName)
+		}
+		return chaincfg.EmbeddedRegistry, nil
+	}
+	var pinned common.Hash
+	if ctx.IsSet(RegistryHashFlagName) {
+		data, err := hexutil.Decode(ctx.String(RegistryHashFlagName))
+		if err != nil || len(data) != len(pinned) {
+			return nil, fmt.Errorf("invalid registry hash %q: must be 32 hex-encoded bytes", ctx.String(RegistryHashFlagName))
+		}
+		pinned = common.Hash(data)
+	}
+	return chaincfg.LoadRegistry(ctx.Context, source, pinned)
+}
+
 // This checks flags that are exclusive & required. Specifically for each
 // set of flags, exactly one flag must be set.
 var requiredXorFlags = [][]string{
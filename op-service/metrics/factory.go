@@ -35,6 +35,13 @@ func With(registry *prometheus.Registry) Factory {
 	}
 }
 
+// WithLabels creates a Factory that registers metrics with the registry and adds the constant labels to every metric.
+func WithLabels(registry *prometheus.Registry, labels prometheus.Labels) Factory {
+	return &documentor{
+		factory: promauto.With(prometheus.WrapRegistererWith(labels, registry)),
+	}
+}
+
 func (d *documentor) NewCounter(opts prometheus.CounterOpts) prometheus.Counter {
 	d.metrics = append(d.metrics, DocumentedMetric{
 		Type: "counter",
@@ -66,6 +66,10 @@ func (r *RollupClient) SequencerActive(ctx context.Context) (bool, error) {
 	return result, err
 }
 
+func (r *RollupClient) ResetDerivationPipeline(ctx context.Context) error {
+	return r.rpc.CallContext(ctx, nil, "admin_resetDerivationPipeline")
+}
+
 func (r *RollupClient) PostUnsafePayload(ctx context.Context, payload *eth.ExecutionPayloadEnvelope) error {
 	return r.rpc.CallContext(ctx, nil, "admin_postUnsafePayload", payload)
 }
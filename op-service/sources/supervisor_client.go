@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
@@ -79,6 +80,31 @@ func (cl *SupervisorClient) CheckBlock(ctx context.Context,
 	return result, nil
 }
 
+func (cl *SupervisorClient) SyncStatus(ctx context.Context) (eth.SupervisorSyncStatus, error) {
+	var result eth.SupervisorSyncStatus
+	err := cl.client.CallContext(
+		ctx,
+		&result,
+		"supervisor_syncStatus")
+	if err != nil {
+		return eth.SupervisorSyncStatus{}, fmt.Errorf("failed to get Supervisor sync status: %w", err)
+	}
+	return result, nil
+}
+
+func (cl *SupervisorClient) SuperRootAtTimestamp(ctx context.Context, timestamp uint64) (eth.SuperRootResponse, error) {
+	var result eth.SuperRootResponse
+	err := cl.client.CallContext(
+		ctx,
+		&result,
+		"supervisor_superRootAtTimestamp",
+		hexutil.Uint64(timestamp))
+	if err != nil {
+		return eth.SuperRootResponse{}, fmt.Errorf("failed to get super root at timestamp %d: %w", timestamp, err)
+	}
+	return result, nil
+}
+
 func (cl *SupervisorClient) Close() {
 	cl.client.Close()
 }
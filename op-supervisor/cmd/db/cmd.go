@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/urfave/cli/v2"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-supervisor/metrics"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+)
+
+var (
+	dataDirFlag = &cli.PathFlag{
+		Name:     "datadir",
+		Usage:    "Data directory of the supervisor",
+		Required: true,
+	}
+	searchCheckpointFrequencyFlag = &cli.Uint64Flag{
+		Name:  "search-checkpoint-frequency",
+		Usage: "Search checkpoint frequency of the compacted log databases, or 0 to keep the frequency of each database",
+	}
+)
+
+var Subcommands = cli.Commands{
+	{
+		Name:  "verify",
+		Usage: "Verifies the log databases of a supervisor",
+		Description: "Replays every entry of the log database of each chain, and checks the invariants of the entries. " +
+			"The first invalid entry of each invalid database is reported. " +
+			"The databases are opened read-only, so the data directory of a running supervisor can be verified.",
+		Flags: []cli.Flag{dataDirFlag},
+		Action: func(ctx *cli.Context) error {
+			logger := oplog.NewLogger(ctx.App.Writer, oplog.DefaultCLIConfig())
+			if err := backend.VerifyLogDBs(logger, metrics.NoopMetrics, ctx.Path(dataDirFlag.Name)); err != nil {
+				return fmt.Errorf("verification failed: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Name:  "compact",
+		Usage: "Compacts the log databases of a supervisor",
+		Description: "Rewrites the log database of each chain into a denser layout, without the padding, search checkpoints " +
+			"and invalidated blocks that are no longer needed, and resolves the heads of each chain to the compacted database. " +
+			"The supervisor must not be running. If the compaction is interrupted, the supervisor does not start " +
+			"until the command is run again.",
+		Flags: []cli.Flag{dataDirFlag, searchCheckpointFrequencyFlag},
+		Action: func(ctx *cli.Context) error {
+			logger := oplog.NewLogger(ctx.App.Writer, oplog.DefaultCLIConfig())
+			frequency := ctx.Uint64(searchCheckpointFrequencyFlag.Name)
+			if frequency > math.MaxUint32 {
+				return fmt.Errorf("search checkpoint frequency %d is too large", frequency)
+			}
+			opts := logs.CompactOptions{SearchCheckpointFrequency: uint32(frequency)}
+			if err := backend.CompactLogDBs(logger, metrics.NoopMetrics, ctx.Path(dataDirFlag.Name), opts); err != nil {
+				return fmt.Errorf("compaction failed: %w", err)
+			}
+			return nil
+		},
+	},
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 
+	"github.com/ethereum-optimism/optimism/op-supervisor/cmd/db"
+	"github.com/ethereum-optimism/optimism/op-supervisor/cmd/snapshot"
 	"github.com/ethereum-optimism/optimism/op-supervisor/config"
 	"github.com/urfave/cli/v2"
 
@@ -48,6 +50,16 @@ func run(ctx context.Context, args []string, fn supervisor.MainFn) error {
 			Name:        "doc",
 			Subcommands: doc.NewSubcommands(metrics.NewMetrics("default")),
 		},
+		{
+			Name:        "db",
+			Usage:       "Maintains the supervisor databases",
+			Subcommands: db.Subcommands,
+		},
+		{
+			Name:        "snapshot",
+			Usage:       "Exports or imports a snapshot of the supervisor databases",
+			Subcommands: snapshot.Subcommands,
+		},
 	}
 	return app.RunContext(ctx, args)
 }
@@ -0,0 +1,68 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-supervisor/metrics"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend"
+)
+
+var (
+	dataDirFlag = &cli.PathFlag{
+		Name:     "datadir",
+		Usage:    "Data directory of the supervisor",
+		Required: true,
+	}
+	fileFlag = &cli.PathFlag{
+		Name:     "file",
+		Usage:    "Path of the snapshot archive",
+		Required: true,
+	}
+)
+
+var Subcommands = cli.Commands{
+	{
+		Name:  "export",
+		Usage: "Exports the heads and log databases of a supervisor into a snapshot archive",
+		Description: "The snapshot can be imported into the data directory of a new supervisor, " +
+			"which then only has to sync the blocks after the snapshot from the L2 RPCs. " +
+			"The supervisor must not be running while its data directory is exported.",
+		Flags: []cli.Flag{dataDirFlag, fileFlag},
+		Action: func(ctx *cli.Context) error {
+			logger := oplog.NewLogger(ctx.App.Writer, oplog.DefaultCLIConfig())
+			out, err := os.OpenFile(ctx.Path(fileFlag.Name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err != nil {
+				return fmt.Errorf("failed to create snapshot file: %w", err)
+			}
+			if err := backend.ExportSnapshot(logger, metrics.NoopMetrics, ctx.Path(dataDirFlag.Name), out); err != nil {
+				return errors.Join(fmt.Errorf("failed to export snapshot: %w", err), out.Close(), os.Remove(out.Name()))
+			}
+			if err := out.Sync(); err != nil {
+				return errors.Join(err, out.Close())
+			}
+			return out.Close()
+		},
+	},
+	{
+		Name:  "import",
+		Usage: "Imports a snapshot archive into the data directory of a new supervisor",
+		Flags: []cli.Flag{dataDirFlag, fileFlag},
+		Action: func(ctx *cli.Context) error {
+			logger := oplog.NewLogger(ctx.App.Writer, oplog.DefaultCLIConfig())
+			in, err := os.Open(ctx.Path(fileFlag.Name))
+			if err != nil {
+				return fmt.Errorf("failed to open snapshot file: %w", err)
+			}
+			defer in.Close()
+			if err := backend.ImportSnapshot(logger, metrics.NoopMetrics, ctx.Path(dataDirFlag.Name), in); err != nil {
+				return fmt.Errorf("failed to import snapshot: %w", err)
+			}
+			return nil
+		},
+	},
+}
@@ -3,10 +3,13 @@ package config
 import (
 	"errors"
 
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
 )
 
 var (
@@ -27,6 +30,42 @@ type Config struct {
 
 	L2RPCs  []string
 	Datadir string
+
+	// DependencySet, if set, restricts the L2 RPCs to chains of the dependency set.
+	DependencySet *chaincfg.DependencySet
+
+	// LightVerificationRPCs are op-node RPCs of the L2 chains, to cross-check the indexed data against.
+	// Data of a chain with such an RPC is not promoted past cross-unsafe until it is light-verified.
+	LightVerificationRPCs []string
+
+	// SearchCheckpointFrequency is the number of entries between search checkpoints in new log databases.
+	// If zero, the default is used.
+	SearchCheckpointFrequency uint64
+
+	// FullHashes stores full 32-byte hashes in new log databases, instead of hashes truncated to 20 bytes.
+	// All log databases must use the same hash mode, so that messages can be checked across chains.
+	FullHashes bool
+
+	// WideLogIndices stores the log index of executing messages in 4 bytes in new log databases, instead of 3 bytes.
+	WideLogIndices bool
+
+	// DBMaxLogsPerBlock is the maximum number of logs per block in the log databases. If zero, there is no limit
+	// other than what the format supports.
+	DBMaxLogsPerBlock uint64
+
+	// DBBackend is the store of the entries of the log databases: file, memory or pebble.
+	DBBackend string
+
+	// DBSync is the policy for syncing the write-ahead logs of the log databases to disk: none, group or always.
+	DBSync string
+
+	// DBPrune removes the log data of each chain before its cross-finalized head,
+	// such that the log databases do not grow without bound.
+	DBPrune bool
+
+	// DBCompressDepth is the number of most recent segments of each log database that are kept uncompressed.
+	// Older segments are compressed to save disk space. 0 disables compression.
+	DBCompressDepth uint64
 }
 
 func (c *Config) Check() error {
@@ -54,5 +93,9 @@ func NewConfig(l2RPCs []string, datadir string) *Config {
 		MockRun:       false,
 		L2RPCs:        l2RPCs,
 		Datadir:       datadir,
+
+		SearchCheckpointFrequency: uint64(logs.DefaultOptions().SearchCheckpointFrequency),
+		DBBackend:                 entrydb.BackendFile.String(),
+		DBSync:                    entrydb.DefaultWALConfig().Sync.String(),
 	}
 }
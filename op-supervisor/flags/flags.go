@@ -6,11 +6,15 @@ import (
 	"github.com/ethereum-optimism/optimism/op-supervisor/config"
 	"github.com/urfave/cli/v2"
 
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	opflags "github.com/ethereum-optimism/optimism/op-service/flags"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
 )
 
 const EnvVarPrefix = "OP_SUPERVISOR"
@@ -30,6 +34,69 @@ var (
 		Usage:   "Directory to store data generated as part of responding to games",
 		EnvVars: prefixEnvVars("DATADIR"),
 	}
+	DependencySetFlag = &cli.StringFlag{
+		Name:    "dependency-set",
+		Usage:   "Name of the interop dependency set in the registry. The L2 RPCs must all serve chains of the dependency set.",
+		EnvVars: prefixEnvVars("DEPENDENCY_SET"),
+	}
+	LightVerificationRPCsFlag = &cli.StringSliceFlag{
+		Name: "light-verification.rpcs",
+		Usage: "op-node RPCs, independent of the L2 RPCs, to light-verify the indexed chain data against. " +
+			"Blocks of chains with such a source are not promoted past cross-unsafe until they match its safe output roots.",
+		EnvVars: prefixEnvVars("LIGHT_VERIFICATION_RPCS"),
+	}
+	SearchCheckpointFrequencyFlag = &cli.Uint64Flag{
+		Name: "db.search-checkpoint-frequency",
+		Usage: "Number of entries between search checkpoints in new log databases. " +
+			"More entries reduce write amplification, at the cost of slower searches. " +
+			"Existing databases keep the frequency they were created with.",
+		Value:   uint64(logs.DefaultOptions().SearchCheckpointFrequency),
+		EnvVars: prefixEnvVars("DB_SEARCH_CHECKPOINT_FREQUENCY"),
+	}
+	FullHashesFlag = &cli.BoolFlag{
+		Name: "db.full-hashes",
+		Usage: "Store full 32-byte hashes in new log databases, instead of hashes truncated to 20 bytes. " +
+			"Existing databases must have been created with the same setting.",
+		EnvVars: prefixEnvVars("DB_FULL_HASHES"),
+	}
+	WideLogIndicesFlag = &cli.BoolFlag{
+		Name: "db.wide-log-indices",
+		Usage: "Store the log index of executing messages in 4 bytes in new log databases, instead of 3 bytes, " +
+			"for chains with more than 16M logs per block. Such databases cannot be opened by earlier versions.",
+		EnvVars: prefixEnvVars("DB_WIDE_LOG_INDICES"),
+	}
+	DBMaxLogsPerBlockFlag = &cli.Uint64Flag{
+		Name:    "db.max-logs-per-block",
+		Usage:   "Maximum number of logs per block in the log databases. 0 means no limit other than what the format supports.",
+		EnvVars: prefixEnvVars("DB_MAX_LOGS_PER_BLOCK"),
+	}
+	DBBackendFlag = &cli.StringFlag{
+		Name: "db.backend",
+		Usage: "Store of the entries of the log databases: 'file' stores them in segment files, " +
+			"'pebble' in a Pebble key-value store, and 'memory' keeps them in memory only, for testing. " +
+			"Snapshots can only be exported from and imported to file databases.",
+		Value:   entrydb.BackendFile.String(),
+		EnvVars: prefixEnvVars("DB_BACKEND"),
+	}
+	DBSyncFlag = &cli.StringFlag{
+		Name: "db.sync",
+		Usage: "When to sync the write-ahead logs of the log databases to disk: " +
+			"'none' leaves it to the OS, 'group' syncs once per group of blocks, 'always' syncs every block.",
+		Value:   entrydb.DefaultWALConfig().Sync.String(),
+		EnvVars: prefixEnvVars("DB_SYNC"),
+	}
+	DBPruneFlag = &cli.BoolFlag{
+		Name: "db.prune",
+		Usage: "Remove the log data of each chain before its cross-finalized head, to bound the size of the log databases. " +
+			"Messages that were initiated before the pruned data can no longer be checked.",
+		EnvVars: prefixEnvVars("DB_PRUNE"),
+	}
+	DBCompressDepthFlag = &cli.Uint64Flag{
+		Name: "db.compress-depth",
+		Usage: "Number of most recent segments of each log database to keep uncompressed. " +
+			"Older segments are compressed with zstd, at the cost of slower reads of old logs. 0 disables compression.",
+		EnvVars: prefixEnvVars("DB_COMPRESS_DEPTH"),
+	}
 	MockRunFlag = &cli.BoolFlag{
 		Name:    "mock-run",
 		Usage:   "Mock run, no actual backend used, just presenting the service",
@@ -44,10 +111,21 @@ var requiredFlags = []cli.Flag{
 }
 
 var optionalFlags = []cli.Flag{
+	DependencySetFlag,
+	LightVerificationRPCsFlag,
+	SearchCheckpointFrequencyFlag,
+	FullHashesFlag,
+	WideLogIndicesFlag,
+	DBMaxLogsPerBlockFlag,
+	DBBackendFlag,
+	DBSyncFlag,
+	DBPruneFlag,
+	DBCompressDepthFlag,
 	MockRunFlag,
 }
 
 func init() {
+	optionalFlags = append(optionalFlags, opflags.CLIRegistryFlags(EnvVarPrefix, "")...)
 	optionalFlags = append(optionalFlags, oprpc.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
@@ -79,5 +157,29 @@ func ConfigFromCLI(ctx *cli.Context, version string) *config.Config {
 		MockRun:       ctx.Bool(MockRunFlag.Name),
 		L2RPCs:        ctx.StringSlice(L2RPCsFlag.Name),
 		Datadir:       ctx.Path(DataDirFlag.Name),
+
+		LightVerificationRPCs:     ctx.StringSlice(LightVerificationRPCsFlag.Name),
+		SearchCheckpointFrequency: ctx.Uint64(SearchCheckpointFrequencyFlag.Name),
+		FullHashes:                ctx.Bool(FullHashesFlag.Name),
+		WideLogIndices:            ctx.Bool(WideLogIndicesFlag.Name),
+		DBMaxLogsPerBlock:         ctx.Uint64(DBMaxLogsPerBlockFlag.Name),
+		DBBackend:                 ctx.String(DBBackendFlag.Name),
+		DBSync:                    ctx.String(DBSyncFlag.Name),
+		DBPrune:                   ctx.Bool(DBPruneFlag.Name),
+		DBCompressDepth:           ctx.Uint64(DBCompressDepthFlag.Name),
+	}
+}
+
+// LoadDependencySet loads the dependency set selected by the dependency-set flag from the registry.
+// It returns nil if no dependency set is selected.
+func LoadDependencySet(ctx *cli.Context) (*chaincfg.DependencySet, error) {
+	name := ctx.String(DependencySetFlag.Name)
+	if name == "" {
+		return nil, nil
+	}
+	registry, err := opflags.LoadRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
+	return registry.DependencySet(name)
 }
@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -20,6 +23,14 @@ type Metricer interface {
 
 	RecordDBEntryCount(chainID types.ChainID, count int64)
 	RecordDBSearchEntriesRead(chainID types.ChainID, count int64)
+	RecordDBEntriesWritten(chainID types.ChainID, entryType string, count int64)
+	RecordDBPaddingRatio(chainID types.ChainID, ratio float64)
+	RecordDBSearchCheckpoint(chainID types.ChainID, repeat bool)
+	RecordDBBlockSealed(chainID types.ChainID)
+	RecordDBFlush(chainID types.ChainID, duration time.Duration)
+	RecordDBFileSize(chainID types.ChainID, size int64)
+	RecordDBReplayProgress(chainID types.ChainID, done int64, total int64)
+	RecordDBOpen(chainID types.ChainID, duration time.Duration, restored bool)
 
 	Document() []opmetrics.DocumentedMetric
 }
@@ -37,6 +48,14 @@ type Metrics struct {
 
 	DBEntryCountVec        *prometheus.GaugeVec
 	DBSearchEntriesReadVec *prometheus.HistogramVec
+	DBEntriesWrittenVec    *prometheus.CounterVec
+	DBPaddingRatioVec      *prometheus.GaugeVec
+	DBSearchCheckpointsVec *prometheus.CounterVec
+	DBBlocksSealedVec      *prometheus.CounterVec
+	DBFlushDurationVec     *prometheus.HistogramVec
+	DBFileSizeVec          *prometheus.GaugeVec
+	DBReplayProgressVec    *prometheus.GaugeVec
+	DBOpenDurationVec      *prometheus.HistogramVec
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
@@ -118,6 +137,67 @@ func NewMetrics(procName string) *Metrics {
 		}, []string{
 			"chain",
 		}),
+		DBEntriesWrittenVec: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "logdb_entries_written",
+			Help:      "Entries written to the log database by chain ID and entry type",
+		}, []string{
+			"chain",
+			"type",
+		}),
+		DBPaddingRatioVec: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "logdb_padding_ratio",
+			Help:      "Fraction of the entries written to the log database since startup that are padding, by chain ID",
+		}, []string{
+			"chain",
+		}),
+		DBSearchCheckpointsVec: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "logdb_search_checkpoints",
+			Help:      "Search checkpoints written to the log database by chain ID, sealing a new block or repeating the last block at a checkpoint interval",
+		}, []string{
+			"chain",
+			"repeat",
+		}),
+		DBBlocksSealedVec: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "logdb_blocks_sealed",
+			Help:      "Blocks sealed in the log database by chain ID",
+		}, []string{
+			"chain",
+		}),
+		DBFlushDurationVec: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "logdb_flush_seconds",
+			Help:      "Time to append the entries of a write to the log database by chain ID",
+			Buckets:   []float64{.00001, .0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{
+			"chain",
+		}),
+		DBFileSizeVec: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "logdb_file_size_bytes",
+			Help:      "Size of the files of the log database by chain ID, excluding the write-ahead log",
+		}, []string{
+			"chain",
+		}),
+		DBReplayProgressVec: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "logdb_replay_progress",
+			Help:      "Fraction of the entries of the log database that were replayed while opening it, by chain ID",
+		}, []string{
+			"chain",
+		}),
+		DBOpenDurationVec: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "logdb_open_seconds",
+			Help:      "Time to open the log database by chain ID, and whether the state was restored rather than replayed",
+			Buckets:   []float64{.001, .01, .1, .5, 1, 5, 10, 30, 60, 300},
+		}, []string{
+			"chain",
+			"restored",
+		}),
 	}
 }
 
@@ -167,6 +247,47 @@ func (m *Metrics) RecordDBSearchEntriesRead(chainID types.ChainID, count int64)
 	m.DBSearchEntriesReadVec.WithLabelValues(chainIDLabel(chainID)).Observe(float64(count))
 }
 
+func (m *Metrics) RecordDBEntriesWritten(chainID types.ChainID, entryType string, count int64) {
+	m.DBEntriesWrittenVec.WithLabelValues(chainIDLabel(chainID), entryType).Add(float64(count))
+}
+
+func (m *Metrics) RecordDBPaddingRatio(chainID types.ChainID, ratio float64) {
+	m.DBPaddingRatioVec.WithLabelValues(chainIDLabel(chainID)).Set(ratio)
+}
+
+func (m *Metrics) RecordDBSearchCheckpoint(chainID types.ChainID, repeat bool) {
+	chain := chainIDLabel(chainID)
+	if repeat {
+		m.DBSearchCheckpointsVec.WithLabelValues(chain, "true").Inc()
+	} else {
+		m.DBSearchCheckpointsVec.WithLabelValues(chain, "false").Inc()
+	}
+}
+
+func (m *Metrics) RecordDBBlockSealed(chainID types.ChainID) {
+	m.DBBlocksSealedVec.WithLabelValues(chainIDLabel(chainID)).Inc()
+}
+
+func (m *Metrics) RecordDBFlush(chainID types.ChainID, duration time.Duration) {
+	m.DBFlushDurationVec.WithLabelValues(chainIDLabel(chainID)).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RecordDBFileSize(chainID types.ChainID, size int64) {
+	m.DBFileSizeVec.WithLabelValues(chainIDLabel(chainID)).Set(float64(size))
+}
+
+func (m *Metrics) RecordDBReplayProgress(chainID types.ChainID, done int64, total int64) {
+	progress := 1.0
+	if total > 0 {
+		progress = float64(done) / float64(total)
+	}
+	m.DBReplayProgressVec.WithLabelValues(chainIDLabel(chainID)).Set(progress)
+}
+
+func (m *Metrics) RecordDBOpen(chainID types.ChainID, duration time.Duration, restored bool) {
+	m.DBOpenDurationVec.WithLabelValues(chainIDLabel(chainID), strconv.FormatBool(restored)).Observe(duration.Seconds())
+}
+
 func chainIDLabel(chainID types.ChainID) string {
 	return chainID.String()
 }
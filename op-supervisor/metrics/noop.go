@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
@@ -21,3 +23,12 @@ func (m *noopMetrics) CacheGet(_ types.ChainID, _ string, _ bool)        {}
 
 func (m *noopMetrics) RecordDBEntryCount(_ types.ChainID, _ int64)        {}
 func (m *noopMetrics) RecordDBSearchEntriesRead(_ types.ChainID, _ int64) {}
+
+func (m *noopMetrics) RecordDBEntriesWritten(_ types.ChainID, _ string, _ int64) {}
+func (m *noopMetrics) RecordDBPaddingRatio(_ types.ChainID, _ float64)           {}
+func (m *noopMetrics) RecordDBSearchCheckpoint(_ types.ChainID, _ bool)          {}
+func (m *noopMetrics) RecordDBBlockSealed(_ types.ChainID)                       {}
+func (m *noopMetrics) RecordDBFlush(_ types.ChainID, _ time.Duration)            {}
+func (m *noopMetrics) RecordDBFileSize(_ types.ChainID, _ int64)                 {}
+func (m *noopMetrics) RecordDBReplayProgress(_ types.ChainID, _ int64, _ int64)  {}
+func (m *noopMetrics) RecordDBOpen(_ types.ChainID, _ time.Duration, _ bool)     {}
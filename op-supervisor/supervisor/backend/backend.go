@@ -5,23 +5,27 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"path/filepath"
+	"slices"
 	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/uint256"
 
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-supervisor/config"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/heads"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/source"
-	backendTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/frontend"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
@@ -31,9 +35,15 @@ type SupervisorBackend struct {
 	logger  log.Logger
 	m       Metrics
 	dataDir string
+	depSet  *chaincfg.DependencySet
+	dbOpts  logs.Options
+	backend entrydb.Backend
+	walCfg  entrydb.WALConfig
+	segCfg  entrydb.SegmentConfig
 
-	chainMonitors map[types.ChainID]*source.ChainMonitor
-	db            *db.ChainsDB
+	chainMonitors  map[types.ChainID]*source.ChainMonitor
+	lightVerifiers []*source.LightVerifier
+	db             *db.ChainsDB
 
 	maintenanceCancel context.CancelFunc
 }
@@ -49,11 +59,48 @@ func NewSupervisorBackend(ctx context.Context, logger log.Logger, m Metrics, cfg
 	}
 
 	// create the head tracker
-	headTracker, err := heads.NewHeadTracker(filepath.Join(cfg.Datadir, "heads.json"))
+	headTracker, err := heads.NewHeadTracker(filepath.Join(cfg.Datadir, headsFileName))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load existing heads: %w", err)
 	}
 
+	// options of new log databases
+	dbOpts := logs.DefaultOptions()
+	if cfg.SearchCheckpointFrequency != 0 {
+		if cfg.SearchCheckpointFrequency > math.MaxUint32 {
+			return nil, fmt.Errorf("search checkpoint frequency %d is too large", cfg.SearchCheckpointFrequency)
+		}
+		dbOpts.SearchCheckpointFrequency = uint32(cfg.SearchCheckpointFrequency)
+	}
+	dbOpts.FullHashes = cfg.FullHashes
+	dbOpts.WideLogIndices = cfg.WideLogIndices
+	if cfg.DBMaxLogsPerBlock > math.MaxUint32 {
+		return nil, fmt.Errorf("max logs per block %d is too large", cfg.DBMaxLogsPerBlock)
+	}
+	dbOpts.MaxLogsPerBlock = uint32(cfg.DBMaxLogsPerBlock)
+	if err := dbOpts.Check(); err != nil {
+		return nil, fmt.Errorf("invalid log db options: %w", err)
+	}
+	backend := entrydb.BackendFile
+	if cfg.DBBackend != "" {
+		backend, err = entrydb.ParseBackend(cfg.DBBackend)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log db backend: %w", err)
+		}
+	}
+	walCfg := entrydb.DefaultWALConfig()
+	if cfg.DBSync != "" {
+		walCfg.Sync, err = entrydb.ParseSyncPolicy(cfg.DBSync)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log db sync policy: %w", err)
+		}
+	}
+	segCfg := entrydb.DefaultSegmentConfig()
+	if cfg.DBCompressDepth > math.MaxInt32 {
+		return nil, fmt.Errorf("log db compress depth %d is too large", cfg.DBCompressDepth)
+	}
+	segCfg.CompressDepth = int(cfg.DBCompressDepth)
+
 	// create the chains db
 	db := db.NewChainsDB(map[types.ChainID]db.LogStorage{}, headTracker, logger)
 
@@ -65,6 +112,11 @@ func NewSupervisorBackend(ctx context.Context, logger log.Logger, m Metrics, cfg
 		logger:        logger,
 		m:             m,
 		dataDir:       cfg.Datadir,
+		depSet:        cfg.DependencySet,
+		dbOpts:        dbOpts,
+		backend:       backend,
+		walCfg:        walCfg,
+		segCfg:        segCfg,
 		chainMonitors: chainMonitors,
 		db:            db,
 	}
@@ -77,9 +129,47 @@ func NewSupervisorBackend(ctx context.Context, logger log.Logger, m Metrics, cfg
 			return nil, fmt.Errorf("failed to add chain monitor for rpc %v: %w", rpc, err)
 		}
 	}
+
+	if cfg.DBPrune {
+		db.EnablePruning()
+	}
+
+	// data of chains with a light verifier is only promoted past cross-unsafe once verified against its source
+	for _, rpc := range cfg.LightVerificationRPCs {
+		if err := super.addLightVerifier(ctx, logger, rpc); err != nil {
+			return nil, fmt.Errorf("failed to add light verifier for rpc %v: %w", rpc, err)
+		}
+	}
+	if len(super.lightVerifiers) > 0 {
+		for chainID := range chainMonitors {
+			if !db.LightVerification(chainID) {
+				logger.Warn("No light verifier for chain, its data is promoted without verification", "chainID", chainID)
+			}
+		}
+	}
 	return super, nil
 }
 
+// addLightVerifier adds a light verifier of the chain served by the given op-node rpc endpoint
+func (su *SupervisorBackend) addLightVerifier(ctx context.Context, logger log.Logger, rpc string) error {
+	rollupClient, err := dial.DialRollupClientWithTimeout(ctx, 10*time.Second, logger, rpc)
+	if err != nil {
+		return fmt.Errorf("failed to connect to rpc %v: %w", rpc, err)
+	}
+	rollupCfg, err := rollupClient.RollupConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load rollup config for rpc %v: %w", rpc, err)
+	}
+	chainID := types.ChainIDFromBig(rollupCfg.L2ChainID)
+	if su.chainMonitors[chainID] == nil {
+		return fmt.Errorf("chain %v of rpc %v is not monitored", chainID, rpc)
+	}
+	su.logger.Info("adding light verifier", "rpc", rpc, "chainID", chainID)
+	su.db.EnableLightVerification(chainID)
+	su.lightVerifiers = append(su.lightVerifiers, source.NewLightVerifier(logger, chainID, rollupClient, su.db))
+	return nil
+}
+
 // addFromRPC adds a chain monitor to the supervisor backend from an rpc endpoint
 // it does not expect to be called after the backend has been started
 // it will start the monitor if shouldStart is true
@@ -89,6 +179,9 @@ func (su *SupervisorBackend) addFromRPC(ctx context.Context, logger log.Logger,
 	if err != nil {
 		return err
 	}
+	if su.depSet != nil && !su.depSet.HasChain(chainID.String()) {
+		return fmt.Errorf("chain %v of rpc %v is not in the dependency set", chainID, rpc)
+	}
 	su.logger.Info("adding from rpc connection", "rpc", rpc, "chainID", chainID)
 	// create metrics and a logdb for the chain
 	cm := newChainMetrics(chainID, su.m)
@@ -96,13 +189,24 @@ func (su *SupervisorBackend) addFromRPC(ctx context.Context, logger log.Logger,
 	if err != nil {
 		return fmt.Errorf("failed to create datadir for chain %v: %w", chainID, err)
 	}
-	logDB, err := logs.NewFromFile(logger, cm, path, true)
+	if err := checkNoCompaction(su.dataDir, chainID); err != nil {
+		return err
+	}
+	logDB, err := logs.NewFromBackend(logger, cm, su.backend, path, true, su.dbOpts, su.walCfg, su.segCfg, logs.DefaultReplayConfig())
 	if err != nil {
 		return fmt.Errorf("failed to create logdb for chain %v at %v: %w", chainID, path, err)
 	}
+	// A truncated hash cannot be checked against a full hash, so all chains must use the same hash mode.
+	if logDB.FullHashes() != su.dbOpts.FullHashes {
+		return errors.Join(fmt.Errorf("logdb for chain %v at %v has full hashes %v, but full hashes %v is configured",
+			chainID, path, logDB.FullHashes(), su.dbOpts.FullHashes), logDB.Close())
+	}
 	if su.chainMonitors[chainID] != nil {
 		return fmt.Errorf("chain monitor for chain %v already exists", chainID)
 	}
+	if err := su.db.AddLogDB(chainID, logDB); err != nil {
+		return errors.Join(err, logDB.Close())
+	}
 	monitor, err := source.NewChainMonitor(ctx, logger, cm, chainID, rpc, rpcClient, su.db)
 	if err != nil {
 		return fmt.Errorf("failed to create monitor for rpc %v: %w", rpc, err)
@@ -114,7 +218,9 @@ func (su *SupervisorBackend) addFromRPC(ctx context.Context, logger log.Logger,
 		}
 	}
 	su.chainMonitors[chainID] = monitor
-	su.db.AddLogDB(chainID, logDB)
+	if shouldStart && len(su.lightVerifiers) > 0 {
+		su.logger.Warn("Light verification is not available for chains added at runtime, its data is promoted without verification", "chainID", chainID)
+	}
 	return nil
 }
 
@@ -146,6 +252,12 @@ func (su *SupervisorBackend) Start(ctx context.Context) error {
 			return fmt.Errorf("failed to start chain monitor: %w", err)
 		}
 	}
+	// start light verifiers
+	for _, verifier := range su.lightVerifiers {
+		if err := verifier.Start(); err != nil {
+			return fmt.Errorf("failed to start light verifier: %w", err)
+		}
+	}
 	// start db maintenance loop
 	maintenanceCtx, cancel := context.WithCancel(context.Background())
 	su.db.StartCrossHeadMaintenance(maintenanceCtx)
@@ -168,6 +280,11 @@ func (su *SupervisorBackend) Stop(ctx context.Context) error {
 			errs = errors.Join(errs, fmt.Errorf("failed to stop chain monitor: %w", err))
 		}
 	}
+	for _, verifier := range su.lightVerifiers {
+		if err := verifier.Stop(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to stop light verifier: %w", err))
+		}
+	}
 	// close the database
 	if err := su.db.Close(); err != nil {
 		errs = errors.Join(errs, fmt.Errorf("failed to close database: %w", err))
@@ -191,7 +308,7 @@ func (su *SupervisorBackend) CheckMessage(identifier types.Identifier, payloadHa
 	chainID := identifier.ChainID
 	blockNum := identifier.BlockNumber
 	logIdx := identifier.LogIndex
-	i, err := su.db.Check(chainID, blockNum, uint32(logIdx), backendTypes.TruncateHash(payloadHash))
+	i, err := su.db.Check(chainID, blockNum, uint32(logIdx), payloadHash)
 	if errors.Is(err, logs.ErrFuture) {
 		return types.Unsafe, nil
 	}
@@ -263,3 +380,111 @@ func (su *SupervisorBackend) CheckBlock(chainID *hexutil.U256, blockHash common.
 	}
 	return safest, nil
 }
+
+// SyncStatus returns the sync status of the supervisor across all monitored chains:
+// the lowest L1 block that the local-safe head of a chain was derived from,
+// and the timestamps up to which all chains are cross-safe and cross-finalized.
+// Only the number and the truncated hash of the L1 block are set.
+func (su *SupervisorBackend) SyncStatus() (eth.SupervisorSyncStatus, error) {
+	if len(su.chainMonitors) == 0 {
+		return eth.SupervisorSyncStatus{}, errors.New("no chains are monitored")
+	}
+	safe := db.NewSafetyChecker(types.Safe, su.db)
+	finalized := db.NewSafetyChecker(types.Finalized, su.db)
+	var status eth.SupervisorSyncStatus
+	first := true
+	for chainID := range su.chainMonitors {
+		var l1 eth.BlockID
+		localSafe, _, err := su.db.SealedBlockAt(chainID, safe.LocalHeadForChain(chainID))
+		if err == nil {
+			l1, err = su.db.DerivedFrom(chainID, localSafe)
+		}
+		if err != nil && !errors.Is(err, logs.ErrFuture) {
+			return eth.SupervisorSyncStatus{}, fmt.Errorf("failed to find L1 block of local-safe head of chain %v: %w", chainID, err)
+		}
+		safeTime, err := su.headTimestamp(chainID, safe.CrossHeadForChain(chainID))
+		if err != nil {
+			return eth.SupervisorSyncStatus{}, fmt.Errorf("failed to find cross-safe head of chain %v: %w", chainID, err)
+		}
+		finalizedTime, err := su.headTimestamp(chainID, finalized.CrossHeadForChain(chainID))
+		if err != nil {
+			return eth.SupervisorSyncStatus{}, fmt.Errorf("failed to find cross-finalized head of chain %v: %w", chainID, err)
+		}
+		if first || l1.Number < status.MinSyncedL1.Number {
+			status.MinSyncedL1 = eth.L1BlockRef{Hash: l1.Hash, Number: l1.Number}
+		}
+		if first || safeTime < uint64(status.SafeTimestamp) {
+			status.SafeTimestamp = hexutil.Uint64(safeTime)
+		}
+		if first || finalizedTime < uint64(status.FinalizedTimestamp) {
+			status.FinalizedTimestamp = hexutil.Uint64(finalizedTime)
+		}
+		first = false
+	}
+	return status, nil
+}
+
+// headTimestamp returns the timestamp of the block that the given head of the chain points at,
+// or 0 if the head does not point at a block yet.
+func (su *SupervisorBackend) headTimestamp(chainID types.ChainID, head entrydb.EntryIdx) (uint64, error) {
+	_, timestamp, err := su.db.SealedBlockAt(chainID, head)
+	if errors.Is(err, logs.ErrFuture) {
+		return 0, nil
+	}
+	return timestamp, err
+}
+
+// SuperRootAtTimestamp returns the super root of the monitored chains at the given timestamp.
+// The block of each chain is the last block at or before the timestamp, which must be cross-safe.
+// The outputs of the blocks are retrieved from the source of each chain, and checked against the blocks in the DB.
+// returns ErrFuture if the block of any chain is not known or not cross-safe yet
+func (su *SupervisorBackend) SuperRootAtTimestamp(ctx context.Context, timestamp hexutil.Uint64) (eth.SuperRootResponse, error) {
+	chainIDs := make([]types.ChainID, 0, len(su.chainMonitors))
+	for chainID := range su.chainMonitors {
+		chainIDs = append(chainIDs, chainID)
+	}
+	// the chains of a super root are sorted by ascending chain ID
+	slices.SortFunc(chainIDs, func(a, b types.ChainID) int {
+		return (*uint256.Int)(&a).Cmp((*uint256.Int)(&b))
+	})
+	safe := db.NewSafetyChecker(types.Safe, su.db)
+	resp := eth.SuperRootResponse{Timestamp: timestamp}
+	for _, chainID := range chainIDs {
+		id, err := chainID.ToUInt64()
+		if err != nil {
+			return eth.SuperRootResponse{}, err
+		}
+		block, _, err := su.db.FindSealedBlockByTimestamp(chainID, uint64(timestamp))
+		if err != nil {
+			return eth.SuperRootResponse{}, fmt.Errorf("failed to find block of chain %v at timestamp %d: %w", chainID, timestamp, err)
+		}
+		output, err := su.chainMonitors[chainID].OutputV0AtBlock(ctx, block.Number)
+		if err != nil {
+			return eth.SuperRootResponse{}, fmt.Errorf("failed to get output of block %d of chain %v: %w", block.Number, chainID, err)
+		}
+		// the DB may only store truncated hashes, so look the block up again by the full hash of the output
+		block = eth.BlockID{Hash: output.BlockHash, Number: block.Number}
+		next, err := su.db.FindSealedBlock(chainID, block)
+		if err != nil {
+			return eth.SuperRootResponse{}, fmt.Errorf("output of chain %v does not match block %d: %w", chainID, block.Number, err)
+		}
+		if next > safe.CrossHeadForChain(chainID) {
+			return eth.SuperRootResponse{}, fmt.Errorf("block %s of chain %v is not cross-safe yet: %w", block, chainID, logs.ErrFuture)
+		}
+		derivedFrom, err := su.db.DerivedFrom(chainID, block)
+		if err != nil {
+			return eth.SuperRootResponse{}, fmt.Errorf("failed to find L1 block of block %s of chain %v: %w", block, chainID, err)
+		}
+		if derivedFrom.Number > resp.CrossSafeDerivedFrom.Number {
+			resp.CrossSafeDerivedFrom = derivedFrom
+		}
+		resp.Chains = append(resp.Chains, eth.ChainRootInfo{
+			ChainID:   hexutil.Uint64(id),
+			Canonical: eth.OutputRoot(output),
+			// the supervisor only serves cross-safe blocks, so the optimistic block is the canonical block
+			Pending: output.Marshal(),
+		})
+	}
+	resp.SuperRoot = eth.SuperRoot(resp.Super())
+	return resp, nil
+}
@@ -1,6 +1,8 @@
 package backend
 
 import (
+	"time"
+
 	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
@@ -12,6 +14,14 @@ type Metrics interface {
 
 	RecordDBEntryCount(chainID types.ChainID, count int64)
 	RecordDBSearchEntriesRead(chainID types.ChainID, count int64)
+	RecordDBEntriesWritten(chainID types.ChainID, entryType string, count int64)
+	RecordDBPaddingRatio(chainID types.ChainID, ratio float64)
+	RecordDBSearchCheckpoint(chainID types.ChainID, repeat bool)
+	RecordDBBlockSealed(chainID types.ChainID)
+	RecordDBFlush(chainID types.ChainID, duration time.Duration)
+	RecordDBFileSize(chainID types.ChainID, size int64)
+	RecordDBReplayProgress(chainID types.ChainID, done int64, total int64)
+	RecordDBOpen(chainID types.ChainID, duration time.Duration, restored bool)
 }
 
 // chainMetrics is an adapter between the metrics API expected by clients that assume there's only a single chain
@@ -44,5 +54,37 @@ func (c *chainMetrics) RecordDBSearchEntriesRead(count int64) {
 	c.delegate.RecordDBSearchEntriesRead(c.chainID, count)
 }
 
+func (c *chainMetrics) RecordDBEntriesWritten(entryType string, count int64) {
+	c.delegate.RecordDBEntriesWritten(c.chainID, entryType, count)
+}
+
+func (c *chainMetrics) RecordDBPaddingRatio(ratio float64) {
+	c.delegate.RecordDBPaddingRatio(c.chainID, ratio)
+}
+
+func (c *chainMetrics) RecordDBSearchCheckpoint(repeat bool) {
+	c.delegate.RecordDBSearchCheckpoint(c.chainID, repeat)
+}
+
+func (c *chainMetrics) RecordDBBlockSealed() {
+	c.delegate.RecordDBBlockSealed(c.chainID)
+}
+
+func (c *chainMetrics) RecordDBFlush(duration time.Duration) {
+	c.delegate.RecordDBFlush(c.chainID, duration)
+}
+
+func (c *chainMetrics) RecordDBFileSize(size int64) {
+	c.delegate.RecordDBFileSize(c.chainID, size)
+}
+
+func (c *chainMetrics) RecordDBReplayProgress(done int64, total int64) {
+	c.delegate.RecordDBReplayProgress(c.chainID, done, total)
+}
+
+func (c *chainMetrics) RecordDBOpen(duration time.Duration, restored bool) {
+	c.delegate.RecordDBOpen(c.chainID, duration, restored)
+}
+
 var _ caching.Metrics = (*chainMetrics)(nil)
 var _ logs.Metrics = (*chainMetrics)(nil)
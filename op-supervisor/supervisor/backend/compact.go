@@ -0,0 +1,148 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/heads"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+// CompactLogDBs compacts the log database of each chain in the data directory, see logs.Compact,
+// and resolves the heads of each chain to the entry indices of its compacted database, see logs.DB.ResolveIndex.
+// The data directory must not be in use by a running supervisor.
+//
+// The heads of a chain are recorded before its database is compacted, and replaced once they are resolved.
+// If the compaction is interrupted, the supervisor does not start until the databases are compacted again,
+// which resolves the recorded heads if the compacted database replaced the database, and otherwise compacts it again.
+func CompactLogDBs(logger log.Logger, m Metrics, datadir string, opts logs.CompactOptions) error {
+	headTracker, err := heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+	if err != nil {
+		return fmt.Errorf("failed to load heads: %w", err)
+	}
+	chainIDs, err := listLogDBChains(datadir)
+	if err != nil {
+		return err
+	}
+	for _, chainID := range chainIDs {
+		if err := compactChain(logger, newChainMetrics(chainID, m), datadir, chainID, headTracker, opts); err != nil {
+			return fmt.Errorf("failed to compact log database of chain %v: %w", chainID, err)
+		}
+	}
+	return nil
+}
+
+func compactChain(logger log.Logger, m logs.Metrics, datadir string, chainID types.ChainID, headTracker *heads.HeadTracker, opts logs.CompactOptions) error {
+	path := filepath.Join(datadir, chainID.String(), logDBFileName)
+	compactHeadsPath := filepath.Join(datadir, chainID.String(), compactHeadsFileName)
+	recorded, err := readCompactHeads(compactHeadsPath)
+	if err != nil {
+		return err
+	}
+	if recorded == nil {
+		// The heads were resolved with the index map of the last compaction, so it is removed,
+		// such that an index map after the compaction means that the compacted database replaced the database.
+		if err := logs.RemoveIndexMap(path); err != nil {
+			return err
+		}
+		current := headTracker.Current().Get(chainID)
+		if err := jsonutil.WriteJSON(current, ioutil.ToAtomicFile(compactHeadsPath, 0o644)); err != nil {
+			return fmt.Errorf("failed to record heads: %w", err)
+		}
+		if err := logs.Compact(logger, m, path, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig()); err != nil {
+			return err
+		}
+		recorded = &current
+	} else {
+		logger.Info("Resuming interrupted compaction", "chainID", chainID)
+	}
+
+	// Opening the database completes the replacement with the compacted database, if it was interrupted.
+	logDB, err := logs.NewFromFile(logger, m, path, false,
+		logs.DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), logs.DefaultReplayConfig())
+	if err != nil {
+		return fmt.Errorf("failed to open log database: %w", err)
+	}
+	compacted := logDB.Compacted()
+	resolved := resolveHeads(logger, chainID, logDB, *recorded)
+	if err := logDB.Close(); err != nil {
+		return fmt.Errorf("failed to close log database: %w", err)
+	}
+	if !compacted {
+		// The compaction was interrupted before it replaced the database, so the heads are unchanged.
+		if err := os.Remove(compactHeadsPath); err != nil {
+			return fmt.Errorf("failed to remove recorded heads: %w", err)
+		}
+		return compactChain(logger, m, datadir, chainID, headTracker, opts)
+	}
+	err = headTracker.Apply(heads.OperationFn(func(h *heads.Heads) error {
+		h.Put(chainID, resolved)
+		return nil
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to store resolved heads: %w", err)
+	}
+	if err := os.Remove(compactHeadsPath); err != nil {
+		return fmt.Errorf("failed to remove recorded heads: %w", err)
+	}
+	logger.Info("Resolved heads of compacted log database", "chainID", chainID, "heads", resolved)
+	return nil
+}
+
+// resolveHeads resolves the heads from before the compaction of logDB to the entry indices of logDB.
+// Heads that can not be resolved, as their entries were pruned, are reset.
+func resolveHeads(logger log.Logger, chainID types.ChainID, logDB *logs.DB, h heads.ChainHeads) heads.ChainHeads {
+	resolve := func(name string, idx entrydb.EntryIdx) entrydb.EntryIdx {
+		if idx == 0 {
+			return 0
+		}
+		resolved, ok := logDB.ResolveIndex(idx)
+		if !ok {
+			logger.Warn("Resetting head that is not in the compacted log database", "chainID", chainID, "head", name, "index", idx)
+			return 0
+		}
+		return resolved
+	}
+	return heads.ChainHeads{
+		Unsafe:         resolve("localUnsafe", h.Unsafe),
+		CrossUnsafe:    resolve("crossUnsafe", h.CrossUnsafe),
+		LocalSafe:      resolve("localSafe", h.LocalSafe),
+		CrossSafe:      resolve("crossSafe", h.CrossSafe),
+		LocalFinalized: resolve("localFinalized", h.LocalFinalized),
+		CrossFinalized: resolve("crossFinalized", h.CrossFinalized),
+		LightVerified:  resolve("lightVerified", h.LightVerified),
+	}
+}
+
+// readCompactHeads reads the heads recorded by an interrupted compaction, or returns nil if there are none.
+func readCompactHeads(path string) (*heads.ChainHeads, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	h, err := jsonutil.LoadJSON[heads.ChainHeads](path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded heads: %w", err)
+	}
+	return h, nil
+}
+
+// checkNoCompaction returns an error if the compaction of the log database of the chain was interrupted,
+// as the heads of the chain may not match the entries of the database until the compaction is completed.
+func checkNoCompaction(datadir string, chainID types.ChainID) error {
+	if _, err := os.Stat(filepath.Join(datadir, chainID.String(), compactHeadsFileName)); errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return fmt.Errorf("compaction of the log database of chain %v was interrupted, run the db compact command to complete it", chainID)
+}
@@ -0,0 +1,131 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/metrics"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/heads"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+func TestCompactLogDBs(t *testing.T) {
+	chainA := types.ChainIDFromUInt64(900)
+	chainB := types.ChainIDFromUInt64(901)
+	compactOpts := logs.CompactOptions{SearchCheckpointFrequency: 64}
+	openDB := func(t *testing.T, datadir string, chainID types.ChainID) *logs.DB {
+		logDB, err := logs.NewFromFile(testlog.Logger(t, log.LvlInfo), newChainMetrics(chainID, metrics.NoopMetrics),
+			filepath.Join(datadir, chainID.String(), logDBFileName), false,
+			logs.Options{SearchCheckpointFrequency: 8}, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), logs.DefaultReplayConfig())
+		require.NoError(t, err)
+		return logDB
+	}
+	// headBlocks are the blocks that the heads of each chain point to, in the order of the fields of heads.ChainHeads.
+	headBlocks := []uint64{100, 90, 50, 40, 20, 10, 30}
+	// headsAt returns the heads of the chain that point to the sealed headBlocks of the log database of the chain.
+	headsAt := func(t *testing.T, datadir string, chainID types.ChainID) heads.ChainHeads {
+		logDB := openDB(t, datadir, chainID)
+		defer logDB.Close()
+		idx := make([]entrydb.EntryIdx, len(headBlocks))
+		for i, n := range headBlocks {
+			var err error
+			idx[i], err = logDB.FindSealedBlock(eth.BlockID{Hash: testBlockHash(chainID, n), Number: n})
+			require.NoError(t, err)
+		}
+		return heads.ChainHeads{
+			Unsafe: idx[0], CrossUnsafe: idx[1], LocalSafe: idx[2], CrossSafe: idx[3],
+			LocalFinalized: idx[4], CrossFinalized: idx[5], LightVerified: idx[6],
+		}
+	}
+	// createDataDir creates a data directory with 100 blocks for each chain, with logs that are padded to the
+	// frequent search checkpoints, and with heads that point to sealed blocks.
+	createDataDir := func(t *testing.T) (string, *heads.HeadTracker) {
+		datadir := t.TempDir()
+		tracker, err := heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+		require.NoError(t, err)
+		for _, chainID := range []types.ChainID{chainA, chainB} {
+			_, err := prepLogDBPath(chainID, datadir)
+			require.NoError(t, err)
+			logDB := openDB(t, datadir, chainID)
+			for i := uint64(0); i <= 100; i++ {
+				if i > 0 {
+					parent := eth.BlockID{Hash: testBlockHash(chainID, i-1), Number: i - 1}
+					for j := uint32(0); j < uint32(i%4); j++ {
+						require.NoError(t, logDB.AddLog(common.Hash{0xaa, byte(i), byte(j)}, parent, j, nil))
+					}
+				}
+				require.NoError(t, logDB.SealBlock(testBlockHash(chainID, i-1), eth.BlockID{Hash: testBlockHash(chainID, i), Number: i}, 1000+i))
+			}
+			require.NoError(t, logDB.Close())
+			h := headsAt(t, datadir, chainID)
+			require.NoError(t, tracker.Apply(heads.OperationFn(func(current *heads.Heads) error {
+				current.Put(chainID, h)
+				return nil
+			})))
+		}
+		return datadir, tracker
+	}
+	requireResolvedHeads := func(t *testing.T, datadir string, before *heads.Heads) {
+		tracker, err := heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+		require.NoError(t, err)
+		for _, chainID := range []types.ChainID{chainA, chainB} {
+			after := tracker.Current().Get(chainID)
+			require.Equal(t, headsAt(t, datadir, chainID), after)
+			require.Less(t, after.Unsafe, before.Get(chainID).Unsafe, "compaction should remove padding and checkpoints")
+			require.NoError(t, checkNoCompaction(datadir, chainID))
+		}
+	}
+
+	t.Run("ResolveHeads", func(t *testing.T) {
+		datadir, tracker := createDataDir(t)
+		before := tracker.Current()
+		require.NoError(t, CompactLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir, compactOpts))
+		requireResolvedHeads(t, datadir, before)
+
+		// Compacting again resolves the heads with the index map of the new compaction.
+		compacted, err := heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+		require.NoError(t, err)
+		require.NoError(t, CompactLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir, logs.CompactOptions{}))
+		tracker, err = heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+		require.NoError(t, err)
+		require.Equal(t, compacted.Current(), tracker.Current())
+	})
+
+	t.Run("InterruptedBeforeReplace", func(t *testing.T) {
+		datadir, tracker := createDataDir(t)
+		before := tracker.Current()
+		// The heads were recorded, but the compaction did not replace the database.
+		compactHeadsPath := filepath.Join(datadir, chainA.String(), compactHeadsFileName)
+		require.NoError(t, jsonutil.WriteJSON(before.Get(chainA), ioutil.ToAtomicFile(compactHeadsPath, 0o644)))
+		require.ErrorContains(t, checkNoCompaction(datadir, chainA), "interrupted")
+
+		require.NoError(t, CompactLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir, compactOpts))
+		requireResolvedHeads(t, datadir, before)
+	})
+
+	t.Run("InterruptedAfterReplace", func(t *testing.T) {
+		datadir, tracker := createDataDir(t)
+		before := tracker.Current()
+		// The compacted database replaced the database, but the heads were not resolved.
+		compactHeadsPath := filepath.Join(datadir, chainA.String(), compactHeadsFileName)
+		require.NoError(t, jsonutil.WriteJSON(before.Get(chainA), ioutil.ToAtomicFile(compactHeadsPath, 0o644)))
+		require.NoError(t, logs.Compact(testlog.Logger(t, log.LvlInfo), newChainMetrics(chainA, metrics.NoopMetrics), filepath.Join(datadir, chainA.String(), logDBFileName),
+			compactOpts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig()))
+
+		require.NoError(t, CompactLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir, compactOpts))
+		requireResolvedHeads(t, datadir, before)
+		_, err := os.Stat(compactHeadsPath)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -25,13 +26,34 @@ var (
 type LogStorage interface {
 	io.Closer
 
-	AddLog(logHash backendTypes.TruncatedHash, parentBlock eth.BlockID,
+	AddLog(logHash common.Hash, parentBlock eth.BlockID,
 		logIdx uint32, execMsg *backendTypes.ExecutingMessage) error
 
+	// WriteBatch writes the updates that fn adds to the batch with a single append.
+	WriteBatch(fn func(b logs.Batch) error) error
+
+	// AddLogWithExecMsgs adds a log that carries any number of executing messages.
+	AddLogWithExecMsgs(logHash common.Hash, parentBlock eth.BlockID,
+		logIdx uint32, execMsgs []backendTypes.ExecutingMessage) error
+
+	// AddDepositEvent adds a log that was emitted by a deposit transaction.
+	AddDepositEvent(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32) error
+
 	SealBlock(parentHash common.Hash, block eth.BlockID, timestamp uint64) error
 
+	// Rewind removes all data after the seal of the given block.
 	Rewind(newHeadBlockNum uint64) error
 
+	// InvalidateBlock invalidates the given sealed block and the blocks after it,
+	// such that the parent block becomes the last sealed block, but records the invalidated data rather than removing it.
+	InvalidateBlock(block eth.BlockID) (logs.InvalidatedBlock, error)
+
+	// Prune removes old data before the given entry index, to bound the size of the database.
+	Prune(entryIdx entrydb.EntryIdx) error
+
+	// NextIndex returns the index of the next entry that will be written.
+	NextIndex() entrydb.EntryIdx
+
 	LatestSealedBlockNum() (n uint64, ok bool)
 
 	// FindSealedBlock finds the requested block, to check if it exists,
@@ -40,12 +62,67 @@ type LogStorage interface {
 	// returns ErrDifferent if the known block does not match
 	FindSealedBlock(block eth.BlockID) (nextEntry entrydb.EntryIdx, err error)
 
+	// FindSealedBlockByTimestamp finds the last sealed block with a timestamp at or before the given timestamp.
+	// returns ErrFuture if the timestamp is after the latest sealed block
+	// returns ErrSkipped if the timestamp is before the first block
+	FindSealedBlockByTimestamp(timestamp uint64) (block eth.BlockID, blockTime uint64, err error)
+
+	// SealedBlockAt returns the last block that was sealed before the given entry index, with its timestamp.
+	// returns ErrFuture if the entry index is not known yet, or if no block was sealed before it
+	SealedBlockAt(entryIdx entrydb.EntryIdx) (block eth.BlockID, timestamp uint64, err error)
+
+	// AddDerivedFrom links the last sealed block to the L1 block it was derived from.
+	AddDerivedFrom(block eth.BlockID, l1 eth.BlockID) error
+
+	// DerivedFrom returns the L1 block that the given sealed block was derived from.
+	// returns ErrFuture if the block, or the link to L1 of the block, is not known yet
+	// returns ErrConflict if the known block does not match
+	DerivedFrom(block eth.BlockID) (l1 eth.BlockID, err error)
+
+	// AddSafeHead records that the local-safe or cross-safe head advanced to the given sealed block.
+	AddSafeHead(level types.SafetyLevel, block eth.BlockID) error
+
+	// SafeHeadAt returns the local-safe or cross-safe head, as last recorded before the given entry index.
+	// returns ErrFuture if the entry index is not known yet, or if no head of the level was recorded before it
+	SafeHeadAt(entryIdx entrydb.EntryIdx, level types.SafetyLevel) (eth.BlockID, error)
+
+	// AddL1Block registers the given L1 block, such that messages that reference it can be checked.
+	AddL1Block(l1 eth.BlockID) error
+
+	// L1BlockAt returns the last L1 block that was registered before the given entry index.
+	// returns ErrFuture if the entry index is not known yet, or if no L1 block was registered before it
+	L1BlockAt(entryIdx entrydb.EntryIdx) (eth.BlockID, error)
+
+	// ContainsL1Block returns the index of the entry that registered the given L1 block.
+	// returns ErrFuture if the L1 block is after the last registered L1 block
+	// returns ErrConflict if a different L1 block with the same number is registered
+	// returns ErrSkipped if the L1 block was not registered, but L1 blocks after it were
+	ContainsL1Block(l1 eth.BlockID) (entrydb.EntryIdx, error)
+
 	IteratorStartingAt(i entrydb.EntryIdx) (logs.Iterator, error)
 
+	// Snapshot returns an immutable view of the DB, that can be read while the DB is written to.
+	Snapshot() *logs.Snapshot
+
+	// IterateRange returns an iterator over the sealed blocks from fromBlock up to and including toBlock,
+	// with the logs and executing messages of each block.
+	// returns ErrFuture if the parent of fromBlock is not known yet
+	IterateRange(fromBlock, toBlock uint64) (logs.BlockIterator, error)
+
 	// returns ErrConflict if the log does not match the canonical chain.
 	// returns ErrFuture if the log is out of reach.
 	// returns nil if the log is known and matches the canonical chain.
-	Contains(blockNum uint64, logIdx uint32, logHash backendTypes.TruncatedHash) (nextIndex entrydb.EntryIdx, err error)
+	Contains(blockNum uint64, logIdx uint32, logHash common.Hash) (nextIndex entrydb.EntryIdx, err error)
+
+	// ContainsDeposit is like Contains, but only matches logs that were emitted by a deposit transaction.
+	// returns ErrConflict if the log is known, but was not emitted by a deposit transaction.
+	ContainsDeposit(blockNum uint64, logIdx uint32, logHash common.Hash) (nextIndex entrydb.EntryIdx, err error)
+
+	// Dependents returns the logs with executing messages that reference the given initiating log of the given chain.
+	Dependents(chain uint32, blockNum uint64, logIdx uint32) []logs.Dependent
+
+	// SubscribeChanges subscribes the channel to the sealed blocks, appended logs and rewinds of the DB.
+	SubscribeChanges(ch chan<- logs.Change) event.Subscription
 }
 
 var _ LogStorage = (*logs.DB)(nil)
@@ -62,6 +139,12 @@ type ChainsDB struct {
 	heads            HeadsStorage
 	maintenanceReady chan struct{}
 	logger           log.Logger
+
+	// lightVerified lists the chains whose local safe and finalized heads are restricted to their light-verified head.
+	lightVerified map[types.ChainID]struct{}
+
+	// pruning removes the log data of each chain before its cross-finalized head, during maintenance.
+	pruning bool
 }
 
 func NewChainsDB(logDBs map[types.ChainID]LogStorage, heads HeadsStorage, l log.Logger) *ChainsDB {
@@ -70,14 +153,73 @@ func NewChainsDB(logDBs map[types.ChainID]LogStorage, heads HeadsStorage, l log.
 		heads:            heads,
 		logger:           l,
 		maintenanceReady: make(chan struct{}, 1),
+		lightVerified:    make(map[types.ChainID]struct{}),
 	}
 }
 
-func (db *ChainsDB) AddLogDB(chain types.ChainID, logDB LogStorage) {
+// AddLogDB adds the log DB of the chain. The heads of the chain are clipped to the data in the log DB,
+// as the heads may have been stored for data that did not survive a restart, e.g. with the memory backend.
+func (db *ChainsDB) AddLogDB(chain types.ChainID, logDB LogStorage) error {
 	if db.logDBs[chain] != nil {
 		log.Warn("overwriting existing logDB for chain", "chain", chain)
 	}
+	next := logDB.NextIndex()
+	if current := db.heads.Current().Get(chain); current.Unsafe > next {
+		db.logger.Warn("Heads point past the data in the log db, clipping them", "chain", chain, "unsafe", current.Unsafe, "next", next)
+	}
+	if err := db.clipHeads(chain, next); err != nil {
+		return fmt.Errorf("failed to clip heads of chain %v to its log db: %w", chain, err)
+	}
 	db.logDBs[chain] = logDB
+	return nil
+}
+
+// EnableLightVerification makes the chains db only promote data of the chain past cross-unsafe
+// once it has been verified against an independent source, see SetLightVerified.
+// Other chains are not affected. It must be called before the cross-head maintenance is started.
+func (db *ChainsDB) EnableLightVerification(chain types.ChainID) {
+	db.lightVerified[chain] = struct{}{}
+}
+
+// LightVerification returns whether light verification is enabled for the chain.
+func (db *ChainsDB) LightVerification(chain types.ChainID) bool {
+	_, ok := db.lightVerified[chain]
+	return ok
+}
+
+// EnablePruning makes the chains db remove the log data of each chain before its cross-finalized head,
+// such that the databases do not grow without bound.
+// Messages that were initiated before the pruned data can no longer be checked.
+// It must be called before the cross-head maintenance is started.
+func (db *ChainsDB) EnablePruning() {
+	db.pruning = true
+}
+
+// SetLightVerified updates the head up to which the data of the chain has been verified against an independent source.
+func (db *ChainsDB) SetLightVerified(chain types.ChainID, index entrydb.EntryIdx) error {
+	if _, ok := db.logDBs[chain]; !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	err := db.heads.Apply(heads.OperationFn(func(h *heads.Heads) error {
+		chainHeads := h.Get(chain)
+		chainHeads.LightVerified = index
+		h.Put(chain, chainHeads)
+		return nil
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to update light-verified head of chain %v: %w", chain, err)
+	}
+	db.RequestMaintenance()
+	return nil
+}
+
+// verifiedHead clips the given local head of the chain to its light-verified head,
+// if light verification is enabled for the chain.
+func (db *ChainsDB) verifiedHead(chain types.ChainID, local entrydb.EntryIdx) entrydb.EntryIdx {
+	if !db.LightVerification(chain) {
+		return local
+	}
+	return min(local, db.heads.Current().Get(chain).LightVerified)
 }
 
 // ResumeFromLastSealedBlock prepares the chains db to resume recording events after a restart.
@@ -125,7 +267,7 @@ func (db *ChainsDB) StartCrossHeadMaintenance(ctx context.Context) {
 }
 
 // Check calls the underlying logDB to determine if the given log entry is safe with respect to the checker's criteria.
-func (db *ChainsDB) Check(chain types.ChainID, blockNum uint64, logIdx uint32, logHash backendTypes.TruncatedHash) (entrydb.EntryIdx, error) {
+func (db *ChainsDB) Check(chain types.ChainID, blockNum uint64, logIdx uint32, logHash common.Hash) (entrydb.EntryIdx, error) {
 	logDB, ok := db.logDBs[chain]
 	if !ok {
 		return 0, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
@@ -133,6 +275,35 @@ func (db *ChainsDB) Check(chain types.ChainID, blockNum uint64, logIdx uint32, l
 	return logDB.Contains(blockNum, logIdx, logHash)
 }
 
+// CheckDeposit is like Check, but only accepts a log entry that was emitted by a deposit transaction.
+func (db *ChainsDB) CheckDeposit(chain types.ChainID, blockNum uint64, logIdx uint32, logHash common.Hash) (entrydb.EntryIdx, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return 0, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.ContainsDeposit(blockNum, logIdx, logHash)
+}
+
+// Dependents returns the logs of each chain with executing messages that reference the given initiating log,
+// i.e. the logs that are invalidated if the initiating log is invalidated.
+// Chains without such logs are not included.
+func (db *ChainsDB) Dependents(chain types.ChainID, blockNum uint64, logIdx uint32) (map[types.ChainID][]logs.Dependent, error) {
+	if _, ok := db.logDBs[chain]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	id, err := chain.ToUInt32()
+	if err != nil {
+		return nil, fmt.Errorf("chain %v cannot be referenced by executing messages: %w", chain, err)
+	}
+	result := make(map[types.ChainID][]logs.Dependent)
+	for dependentChain, logDB := range db.logDBs {
+		if deps := logDB.Dependents(id, blockNum, logIdx); len(deps) > 0 {
+			result[dependentChain] = deps
+		}
+	}
+	return result, nil
+}
+
 // RequestMaintenance requests that the maintenance loop update the cross-heads
 // it does not block if maintenance is already scheduled
 func (db *ChainsDB) RequestMaintenance() {
@@ -159,6 +330,22 @@ func (db *ChainsDB) updateAllHeads() error {
 			return fmt.Errorf("failed to update cross-heads for safety level %v: %w", checker.Name(), err)
 		}
 	}
+	if db.pruning {
+		if err := db.pruneFinalized(); err != nil {
+			return fmt.Errorf("failed to prune finalized data: %w", err)
+		}
+	}
+	return nil
+}
+
+// pruneFinalized removes the log data of each chain before its cross-finalized head.
+func (db *ChainsDB) pruneFinalized() error {
+	current := db.heads.Current()
+	for chain, logDB := range db.logDBs {
+		if err := logDB.Prune(current.Get(chain).CrossFinalized); err != nil {
+			return fmt.Errorf("failed to prune log db of chain %v: %w", chain, err)
+		}
+	}
 	return nil
 }
 
@@ -193,16 +380,22 @@ func (db *ChainsDB) UpdateCrossHeadsForChain(chainID types.ChainID, checker Safe
 			updated = localHead != xHead
 			break
 		}
-		exec := iter.ExecMessage()
-		if exec == nil {
+		execMsgs := iter.ExecMessages()
+		if len(execMsgs) == 0 {
 			panic("expected executing message after traversing to one without error")
 		}
-		// use the checker to determine if this message is safe
-		safe := checker.Check(
-			types.ChainIDFromUInt64(uint64(exec.Chain)),
-			exec.BlockNum,
-			exec.LogIdx,
-			exec.Hash)
+		// use the checker to determine if all messages of the log are safe
+		safe := true
+		for _, exec := range execMsgs {
+			if !checker.Check(
+				types.ChainIDFromUInt64(uint64(exec.Chain)),
+				exec.BlockNum,
+				exec.LogIdx,
+				exec.Hash) {
+				safe = false
+				break
+			}
+		}
 		if !safe {
 			break
 		}
@@ -267,7 +460,7 @@ func (db *ChainsDB) SealBlock(chain types.ChainID, parentHash common.Hash, block
 	return logDB.SealBlock(parentHash, block, timestamp)
 }
 
-func (db *ChainsDB) AddLog(chain types.ChainID, logHash backendTypes.TruncatedHash, parentBlock eth.BlockID, logIdx uint32, execMsg *backendTypes.ExecutingMessage) error {
+func (db *ChainsDB) AddLog(chain types.ChainID, logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsg *backendTypes.ExecutingMessage) error {
 	logDB, ok := db.logDBs[chain]
 	if !ok {
 		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
@@ -275,12 +468,182 @@ func (db *ChainsDB) AddLog(chain types.ChainID, logHash backendTypes.TruncatedHa
 	return logDB.AddLog(logHash, parentBlock, logIdx, execMsg)
 }
 
+// WriteBatch writes the updates that fn adds to the batch to the log DB of the chain, atomically.
+func (db *ChainsDB) WriteBatch(chain types.ChainID, fn func(b logs.Batch) error) error {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.WriteBatch(fn)
+}
+
+// AddLogWithExecMsgs adds a log of the chain that carries any number of executing messages.
+func (db *ChainsDB) AddLogWithExecMsgs(chain types.ChainID, logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsgs []backendTypes.ExecutingMessage) error {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.AddLogWithExecMsgs(logHash, parentBlock, logIdx, execMsgs)
+}
+
+// AddDerivedFrom links the last sealed block of the chain to the L1 block it was derived from.
+func (db *ChainsDB) AddDerivedFrom(chain types.ChainID, block eth.BlockID, l1 eth.BlockID) error {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.AddDerivedFrom(block, l1)
+}
+
+// DerivedFrom returns the L1 block that the given block of the chain was derived from.
+func (db *ChainsDB) DerivedFrom(chain types.ChainID, block eth.BlockID) (l1 eth.BlockID, err error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return eth.BlockID{}, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.DerivedFrom(block)
+}
+
+// AddSafeHead records that the local-safe or cross-safe head of the chain advanced to the given block.
+func (db *ChainsDB) AddSafeHead(chain types.ChainID, level types.SafetyLevel, block eth.BlockID) error {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.AddSafeHead(level, block)
+}
+
+// SafeHeadAt returns the local-safe or cross-safe head of the chain, as last recorded before the given entry index.
+func (db *ChainsDB) SafeHeadAt(chain types.ChainID, entryIdx entrydb.EntryIdx, level types.SafetyLevel) (eth.BlockID, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return eth.BlockID{}, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.SafeHeadAt(entryIdx, level)
+}
+
+// AddL1Block registers the given L1 block in the log database of the chain.
+func (db *ChainsDB) AddL1Block(chain types.ChainID, l1 eth.BlockID) error {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.AddL1Block(l1)
+}
+
+// L1BlockAt returns the last L1 block that was registered in the log database of the chain,
+// before the given entry index.
+func (db *ChainsDB) L1BlockAt(chain types.ChainID, entryIdx entrydb.EntryIdx) (eth.BlockID, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return eth.BlockID{}, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.L1BlockAt(entryIdx)
+}
+
+// ContainsL1Block returns the index of the entry that registered the given L1 block in the log database of the chain.
+func (db *ChainsDB) ContainsL1Block(chain types.ChainID, l1 eth.BlockID) (entrydb.EntryIdx, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return 0, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.ContainsL1Block(l1)
+}
+
+// Snapshot returns an immutable view of the log DB of the chain, that can be read while the DB is written to.
+func (db *ChainsDB) Snapshot(chain types.ChainID) (*logs.Snapshot, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.Snapshot(), nil
+}
+
+// SubscribeChanges subscribes the channel to the sealed blocks, appended logs and rewinds of the log DB of the chain.
+func (db *ChainsDB) SubscribeChanges(chain types.ChainID, ch chan<- logs.Change) (event.Subscription, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.SubscribeChanges(ch), nil
+}
+
+// FindSealedBlockByTimestamp finds the last sealed block of the chain with a timestamp at or before the given timestamp.
+func (db *ChainsDB) FindSealedBlockByTimestamp(chain types.ChainID, timestamp uint64) (block eth.BlockID, blockTime uint64, err error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return eth.BlockID{}, 0, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.FindSealedBlockByTimestamp(timestamp)
+}
+
+// SealedBlockAt returns the last block of the chain that was sealed before the given entry index, with its timestamp.
+func (db *ChainsDB) SealedBlockAt(chain types.ChainID, entryIdx entrydb.EntryIdx) (block eth.BlockID, timestamp uint64, err error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return eth.BlockID{}, 0, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.SealedBlockAt(entryIdx)
+}
+
+// IterateRange returns an iterator over the sealed blocks of the chain from fromBlock up to and including toBlock,
+// with the logs and executing messages of each block.
+func (db *ChainsDB) IterateRange(chain types.ChainID, fromBlock, toBlock uint64) (logs.BlockIterator, error) {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	return logDB.IterateRange(fromBlock, toBlock)
+}
+
 func (db *ChainsDB) Rewind(chain types.ChainID, headBlockNum uint64) error {
 	logDB, ok := db.logDBs[chain]
 	if !ok {
 		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
 	}
-	return logDB.Rewind(headBlockNum)
+	if err := logDB.Rewind(headBlockNum); err != nil {
+		return err
+	}
+	// the heads may not point past the data that remains after the rewind
+	err := db.clipHeads(chain, logDB.NextIndex())
+	if err != nil {
+		return fmt.Errorf("failed to rewind heads of chain %v: %w", chain, err)
+	}
+	return nil
+}
+
+// InvalidateBlock invalidates the given block of the given chain, and the blocks after it, see logs.DB.InvalidateBlock.
+func (db *ChainsDB) InvalidateBlock(chain types.ChainID, block eth.BlockID) error {
+	logDB, ok := db.logDBs[chain]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownChain, chain)
+	}
+	invalidated, err := logDB.InvalidateBlock(block)
+	if err != nil {
+		return err
+	}
+	// the heads may not point into the invalidated data
+	err = db.clipHeads(chain, invalidated.Start)
+	if err != nil {
+		return fmt.Errorf("failed to clip heads of chain %v to invalidated block %s: %w", chain, block, err)
+	}
+	return nil
+}
+
+// clipHeads makes sure that none of the heads of the chain point past the given entry.
+func (db *ChainsDB) clipHeads(chain types.ChainID, limit entrydb.EntryIdx) error {
+	return db.heads.Apply(heads.OperationFn(func(h *heads.Heads) error {
+		chainHeads := h.Get(chain)
+		chainHeads.Unsafe = min(chainHeads.Unsafe, limit)
+		chainHeads.CrossUnsafe = min(chainHeads.CrossUnsafe, limit)
+		chainHeads.LocalSafe = min(chainHeads.LocalSafe, limit)
+		chainHeads.CrossSafe = min(chainHeads.CrossSafe, limit)
+		chainHeads.LocalFinalized = min(chainHeads.LocalFinalized, limit)
+		chainHeads.CrossFinalized = min(chainHeads.CrossFinalized, limit)
+		chainHeads.LightVerified = min(chainHeads.LightVerified, limit)
+		h.Put(chain, chainHeads)
+		return nil
+	}))
 }
 
 func (db *ChainsDB) Close() error {
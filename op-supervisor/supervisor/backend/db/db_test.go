@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -23,7 +24,7 @@ import (
 func TestChainsDB_AddLog(t *testing.T) {
 	t.Run("UnknownChain", func(t *testing.T) {
 		db := NewChainsDB(nil, &stubHeadStorage{}, testlog.Logger(t, log.LevelDebug))
-		err := db.AddLog(types.ChainIDFromUInt64(2), backendTypes.TruncatedHash{}, eth.BlockID{}, 33, nil)
+		err := db.AddLog(types.ChainIDFromUInt64(2), common.Hash{}, eth.BlockID{}, 33, nil)
 		require.ErrorIs(t, err, ErrUnknownChain)
 	})
 
@@ -36,13 +37,40 @@ func TestChainsDB_AddLog(t *testing.T) {
 		bl10 := eth.BlockID{Hash: common.Hash{0x10}, Number: 10}
 		err := db.SealBlock(chainID, common.Hash{0x9}, bl10, 1234)
 		require.NoError(t, err, err)
-		err = db.AddLog(chainID, backendTypes.TruncatedHash{}, bl10, 0, nil)
+		err = db.AddLog(chainID, common.Hash{}, bl10, 0, nil)
 		require.NoError(t, err, err)
 		require.Equal(t, 1, logDB.addLogCalls)
 		require.Equal(t, 1, logDB.sealBlockCalls)
 	})
 }
 
+func TestChainsDB_AddLogDB(t *testing.T) {
+	chainID := types.ChainIDFromUInt64(1)
+	h := heads.NewHeads()
+	h.Put(chainID, heads.ChainHeads{
+		Unsafe:         40,
+		CrossUnsafe:    30,
+		LocalSafe:      25,
+		CrossSafe:      15,
+		LocalFinalized: 10,
+		CrossFinalized: 5,
+		LightVerified:  22,
+	})
+	headStorage := &stubHeadStorage{h}
+	db := NewChainsDB(map[types.ChainID]LogStorage{}, headStorage, testlog.Logger(t, log.LevelDebug))
+	// the heads were stored for data that the log db no longer has, e.g. with the memory backend
+	require.NoError(t, db.AddLogDB(chainID, &stubLogDB{nextIndex: 20}))
+	require.Equal(t, heads.ChainHeads{
+		Unsafe:         20,
+		CrossUnsafe:    20,
+		LocalSafe:      20,
+		CrossSafe:      15,
+		LocalFinalized: 10,
+		CrossFinalized: 5,
+		LightVerified:  20,
+	}, headStorage.Current().Get(chainID))
+}
+
 func TestChainsDB_Rewind(t *testing.T) {
 	t.Run("UnknownChain", func(t *testing.T) {
 		db := NewChainsDB(nil, &stubHeadStorage{}, testlog.Logger(t, log.LevelDebug))
@@ -61,6 +89,118 @@ func TestChainsDB_Rewind(t *testing.T) {
 		require.NoError(t, err, err)
 		require.EqualValues(t, 23, logDB.headBlockNum)
 	})
+
+	t.Run("ClipHeads", func(t *testing.T) {
+		chainID := types.ChainIDFromUInt64(1)
+		logDB := &stubLogDB{nextIndex: 20}
+		h := heads.NewHeads()
+		h.Put(chainID, heads.ChainHeads{
+			Unsafe:         40,
+			CrossUnsafe:    30,
+			LocalSafe:      25,
+			CrossSafe:      15,
+			LocalFinalized: 10,
+			CrossFinalized: 5,
+			LightVerified:  22,
+		})
+		headStorage := &stubHeadStorage{h}
+		db := NewChainsDB(map[types.ChainID]LogStorage{
+			chainID: logDB,
+		}, headStorage,
+			testlog.Logger(t, log.LevelDebug))
+		require.NoError(t, db.Rewind(chainID, 23))
+		require.Equal(t, heads.ChainHeads{
+			Unsafe:         20,
+			CrossUnsafe:    20,
+			LocalSafe:      20,
+			CrossSafe:      15,
+			LocalFinalized: 10,
+			CrossFinalized: 5,
+			LightVerified:  20,
+		}, headStorage.Current().Get(chainID))
+	})
+}
+
+func TestChainsDB_InvalidateBlock(t *testing.T) {
+	chainID := types.ChainIDFromUInt64(1)
+	block := eth.BlockID{Hash: common.Hash{0xaa}, Number: 23}
+	t.Run("UnknownChain", func(t *testing.T) {
+		db := NewChainsDB(nil, &stubHeadStorage{}, testlog.Logger(t, log.LevelDebug))
+		err := db.InvalidateBlock(types.ChainIDFromUInt64(2), block)
+		require.ErrorIs(t, err, ErrUnknownChain)
+	})
+
+	t.Run("ClipHeads", func(t *testing.T) {
+		// the invalidated data starts at entry 20
+		logDB := &stubLogDB{nextIndex: 20}
+		h := heads.NewHeads()
+		h.Put(chainID, heads.ChainHeads{
+			Unsafe:         40,
+			CrossUnsafe:    30,
+			LocalSafe:      25,
+			CrossSafe:      15,
+			LocalFinalized: 10,
+			CrossFinalized: 5,
+			LightVerified:  22,
+		})
+		headStorage := &stubHeadStorage{h}
+		db := NewChainsDB(map[types.ChainID]LogStorage{
+			chainID: logDB,
+		}, headStorage,
+			testlog.Logger(t, log.LevelDebug))
+		require.NoError(t, db.InvalidateBlock(chainID, block))
+		require.Equal(t, block, logDB.invalidated)
+		require.Equal(t, heads.ChainHeads{
+			Unsafe:         20,
+			CrossUnsafe:    20,
+			LocalSafe:      20,
+			CrossSafe:      15,
+			LocalFinalized: 10,
+			CrossFinalized: 5,
+			LightVerified:  20,
+		}, headStorage.Current().Get(chainID))
+	})
+}
+
+func TestChainsDB_PruneFinalized(t *testing.T) {
+	chainA := types.ChainIDFromUInt64(1)
+	chainB := types.ChainIDFromUInt64(2)
+	logDBA := &stubLogDB{}
+	logDBB := &stubLogDB{}
+	h := heads.NewHeads()
+	h.Put(chainA, heads.ChainHeads{CrossSafe: 50, LocalFinalized: 40, CrossFinalized: 30})
+	h.Put(chainB, heads.ChainHeads{CrossSafe: 20, LocalFinalized: 15, CrossFinalized: 10})
+	db := NewChainsDB(map[types.ChainID]LogStorage{
+		chainA: logDBA,
+		chainB: logDBB,
+	}, &stubHeadStorage{h}, testlog.Logger(t, log.LevelDebug))
+	require.NoError(t, db.pruneFinalized())
+	require.EqualValues(t, 30, logDBA.prunedBefore)
+	require.EqualValues(t, 10, logDBB.prunedBefore)
+}
+
+func TestChainsDB_Dependents(t *testing.T) {
+	chainA := types.ChainIDFromUInt64(1)
+	chainB := types.ChainIDFromUInt64(2)
+	chainC := types.ChainIDFromUInt64(3)
+	dbA := &stubLogDB{dependents: map[uint32][]logs.Dependent{2: {{BlockNum: 5, LogIdx: 1}}}}
+	dbB := &stubLogDB{}
+	dbC := &stubLogDB{dependents: map[uint32][]logs.Dependent{2: {{BlockNum: 7, LogIdx: 0}, {BlockNum: 8, LogIdx: 3}}}}
+	db := NewChainsDB(map[types.ChainID]LogStorage{chainA: dbA, chainB: dbB, chainC: dbC}, &stubHeadStorage{}, testlog.Logger(t, log.LevelDebug))
+
+	deps, err := db.Dependents(chainB, 10, 2)
+	require.NoError(t, err)
+	require.Equal(t, map[types.ChainID][]logs.Dependent{
+		chainA: {{BlockNum: 5, LogIdx: 1}},
+		chainC: {{BlockNum: 7, LogIdx: 0}, {BlockNum: 8, LogIdx: 3}},
+	}, deps)
+
+	deps, err = db.Dependents(chainA, 10, 2)
+	require.NoError(t, err)
+	require.Empty(t, deps)
+
+	_, err = db.Dependents(types.ChainIDFromUInt64(4), 10, 2)
+	require.ErrorIs(t, err, ErrUnknownChain)
 }
 
 func TestChainsDB_UpdateCrossHeads(t *testing.T) {
@@ -201,7 +341,7 @@ func setupStubbedForUpdateHeads(chainID types.ChainID) (*stubLogDB, *stubChecker
 		logDB.executingMessages = append(logDB.executingMessages, &backendTypes.ExecutingMessage{
 			BlockNum: uint64(100 + int(i/3)),
 			LogIdx:   uint32(i),
-			Hash:     backendTypes.TruncatedHash{},
+			Hash:     common.Hash{},
 		})
 	}
 
@@ -210,7 +350,7 @@ func setupStubbedForUpdateHeads(chainID types.ChainID) (*stubLogDB, *stubChecker
 	logIndex := uint32(0)
 	executedCount := 0
 	for i := entrydb.EntryIdx(0); i <= local; i++ {
-		var logHash backendTypes.TruncatedHash
+		var logHash common.Hash
 		rng.Read(logHash[:])
 
 		execIndex := -1
@@ -266,7 +406,7 @@ func (s *stubChecker) CrossHeadForChain(chainID types.ChainID) entrydb.EntryIdx
 }
 
 // stubbed Check returns true for the first numSafe calls, and false thereafter
-func (s *stubChecker) Check(chain types.ChainID, blockNum uint64, logIdx uint32, logHash backendTypes.TruncatedHash) bool {
+func (s *stubChecker) Check(chain types.ChainID, blockNum uint64, logIdx uint32, logHash common.Hash) bool {
 	if s.checkCalls >= s.numSafe {
 		return false
 	}
@@ -289,8 +429,11 @@ type stubHeadStorage struct {
 	heads *heads.Heads
 }
 
-func (s *stubHeadStorage) Apply(heads.Operation) error {
-	return nil
+func (s *stubHeadStorage) Apply(op heads.Operation) error {
+	if s.heads == nil {
+		s.heads = heads.NewHeads()
+	}
+	return op.Apply(s.heads)
 }
 
 func (s *stubHeadStorage) Current() *heads.Heads {
@@ -305,7 +448,7 @@ type nextLogResponse struct {
 
 	logIdx uint32
 
-	evtHash backendTypes.TruncatedHash
+	evtHash common.Hash
 
 	err error
 
@@ -356,21 +499,29 @@ func (s *stubIterator) NextIndex() entrydb.EntryIdx {
 	return s.index + 1
 }
 
-func (s *stubIterator) SealedBlock() (hash backendTypes.TruncatedHash, num uint64, ok bool) {
+func (s *stubIterator) SealedBlock() (hash common.Hash, num uint64, ok bool) {
+	panic("not yet supported")
+}
+
+func (s *stubIterator) DerivedFrom() (l1 eth.BlockID, ok bool) {
 	panic("not yet supported")
 }
 
-func (s *stubIterator) InitMessage() (hash backendTypes.TruncatedHash, logIndex uint32, ok bool) {
+func (s *stubIterator) InitMessage() (hash common.Hash, logIndex uint32, ok bool) {
 	if s.index < 0 {
-		return backendTypes.TruncatedHash{}, 0, false
+		return common.Hash{}, 0, false
 	}
 	if s.index >= entrydb.EntryIdx(len(s.db.nextLogs)) {
-		return backendTypes.TruncatedHash{}, 0, false
+		return common.Hash{}, 0, false
 	}
 	e := s.db.nextLogs[s.index]
 	return e.evtHash, e.logIdx, true
 }
 
+func (s *stubIterator) IsDeposit() bool {
+	return false
+}
+
 func (s *stubIterator) ExecMessage() *backendTypes.ExecutingMessage {
 	if s.index < 0 {
 		return nil
@@ -385,20 +536,46 @@ func (s *stubIterator) ExecMessage() *backendTypes.ExecutingMessage {
 	return s.db.executingMessages[e.execIdx]
 }
 
+func (s *stubIterator) ExecMessages() []backendTypes.ExecutingMessage {
+	if msg := s.ExecMessage(); msg != nil {
+		return []backendTypes.ExecutingMessage{*msg}
+	}
+	return nil
+}
+
 var _ logs.Iterator = (*stubIterator)(nil)
 
 type stubLogDB struct {
 	addLogCalls    int
 	sealBlockCalls int
 	headBlockNum   uint64
+	nextIndex      entrydb.EntryIdx
+	prunedBefore   entrydb.EntryIdx
+	invalidated    eth.BlockID
 
 	executingMessages []*backendTypes.ExecutingMessage
 	nextLogs          []nextLogResponse
 
 	containsResponse containsResponse
+
+	dependents map[uint32][]logs.Dependent
+}
+
+func (s *stubLogDB) AddLog(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsg *backendTypes.ExecutingMessage) error {
+	s.addLogCalls++
+	return nil
+}
+
+func (s *stubLogDB) WriteBatch(fn func(b logs.Batch) error) error {
+	panic("not implemented")
 }
 
-func (s *stubLogDB) AddLog(logHash backendTypes.TruncatedHash, parentBlock eth.BlockID, logIdx uint32, execMsg *backendTypes.ExecutingMessage) error {
+func (s *stubLogDB) AddLogWithExecMsgs(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsgs []backendTypes.ExecutingMessage) error {
+	s.addLogCalls++
+	return nil
+}
+
+func (s *stubLogDB) AddDepositEvent(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32) error {
 	s.addLogCalls++
 	return nil
 }
@@ -416,6 +593,34 @@ func (s *stubLogDB) FindSealedBlock(block eth.BlockID) (nextEntry entrydb.EntryI
 	panic("not implemented")
 }
 
+func (s *stubLogDB) AddDerivedFrom(block eth.BlockID, l1 eth.BlockID) error {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) DerivedFrom(block eth.BlockID) (l1 eth.BlockID, err error) {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) AddSafeHead(level types.SafetyLevel, block eth.BlockID) error {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) SafeHeadAt(entryIdx entrydb.EntryIdx, level types.SafetyLevel) (eth.BlockID, error) {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) AddL1Block(l1 eth.BlockID) error {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) L1BlockAt(entryIdx entrydb.EntryIdx) (eth.BlockID, error) {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) ContainsL1Block(l1 eth.BlockID) (entrydb.EntryIdx, error) {
+	panic("not implemented")
+}
+
 func (s *stubLogDB) IteratorStartingAt(i entrydb.EntryIdx) (logs.Iterator, error) {
 	return &stubIterator{
 		index: i - 1,
@@ -423,6 +628,22 @@ func (s *stubLogDB) IteratorStartingAt(i entrydb.EntryIdx) (logs.Iterator, error
 	}, nil
 }
 
+func (s *stubLogDB) FindSealedBlockByTimestamp(timestamp uint64) (block eth.BlockID, blockTime uint64, err error) {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) SealedBlockAt(entryIdx entrydb.EntryIdx) (block eth.BlockID, timestamp uint64, err error) {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) Snapshot() *logs.Snapshot {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) IterateRange(fromBlock, toBlock uint64) (logs.BlockIterator, error) {
+	panic("not implemented")
+}
+
 var _ LogStorage = (*stubLogDB)(nil)
 
 type containsResponse struct {
@@ -432,15 +653,41 @@ type containsResponse struct {
 
 // stubbed Contains records the arguments passed to it
 // it returns the response set in the struct, or an empty response
-func (s *stubLogDB) Contains(blockNum uint64, logIdx uint32, logHash backendTypes.TruncatedHash) (nextIndex entrydb.EntryIdx, err error) {
+func (s *stubLogDB) Contains(blockNum uint64, logIdx uint32, logHash common.Hash) (nextIndex entrydb.EntryIdx, err error) {
 	return s.containsResponse.index, s.containsResponse.err
 }
 
+func (s *stubLogDB) ContainsDeposit(blockNum uint64, logIdx uint32, logHash common.Hash) (nextIndex entrydb.EntryIdx, err error) {
+	panic("not implemented")
+}
+
+func (s *stubLogDB) Dependents(chain uint32, blockNum uint64, logIdx uint32) []logs.Dependent {
+	return s.dependents[chain]
+}
+
+func (s *stubLogDB) SubscribeChanges(ch chan<- logs.Change) event.Subscription {
+	panic("not implemented")
+}
+
 func (s *stubLogDB) Rewind(newHeadBlockNum uint64) error {
 	s.headBlockNum = newHeadBlockNum
 	return nil
 }
 
+func (s *stubLogDB) InvalidateBlock(block eth.BlockID) (logs.InvalidatedBlock, error) {
+	s.invalidated = block
+	return logs.InvalidatedBlock{Block: block, Start: s.nextIndex}, nil
+}
+
+func (s *stubLogDB) Prune(entryIdx entrydb.EntryIdx) error {
+	s.prunedBefore = entryIdx
+	return nil
+}
+
+func (s *stubLogDB) NextIndex() entrydb.EntryIdx {
+	return s.nextIndex
+}
+
 func (s *stubLogDB) LatestBlockNum() uint64 {
 	return s.headBlockNum
 }
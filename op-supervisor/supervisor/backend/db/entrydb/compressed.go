@@ -0,0 +1,232 @@
+package entrydb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// compressedSuffix is appended to the path of a segment file, for the compressed segment file.
+	compressedSuffix = ".zst"
+	// compressedBlockEntries is the number of entries that are compressed together.
+	// Reading an entry of a compressed segment decompresses the block of entries that holds it.
+	compressedBlockEntries = 4096
+	// compressedTrailerSize is the size of the trailer at the end of a compressed segment file:
+	// <uint64 entry count: 8 bytes><uint32 block count: 4 bytes><checksum of the block index and trailer: 4 bytes>
+	// The trailer is preceded by the block index: the uint64 end offset of each block.
+	compressedTrailerSize = 8 + 4 + 4
+)
+
+// zstdDecoder decompresses the blocks of all compressed segments. It is safe for concurrent use with DecodeAll.
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+// compressedSegment is a read-only segment, of which the entries are stored compressed with zstd.
+// The file starts with the header of the segment, followed by the compressed blocks of entries,
+// the block index and the trailer.
+// Each block holds compressedBlockEntries entries as stored in an EntryDB, including their checksums,
+// and is compressed separately, such that entries can be read without decompressing the entire segment.
+type compressedSegment struct {
+	file    *os.File
+	header  Header
+	entries int64
+	// fileSize is the size of the compressed file in bytes.
+	fileSize int64
+	// blocks holds the end offset of each block. Each block starts where the previous block ends.
+	blocks []int64
+
+	// cacheLock guards the last decompressed block, as entries may be read concurrently.
+	cacheLock   sync.Mutex
+	cachedBlock int64
+	cached      []byte
+}
+
+// writeCompressedSegment compresses the entries of src into a new file at the given path, and syncs the file.
+func writeCompressedSegment(path string, src *EntryDB) error {
+	header, ok := src.Header()
+	if !ok {
+		return errors.New("segment without header cannot be compressed")
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeCompressedEntries(file, header, src); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	return file.Close()
+}
+
+func writeCompressedEntries(w io.Writer, header Header, src *EntryDB) error {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return err
+	}
+	defer encoder.Close()
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	offset := int64(HeaderSize)
+	var trailer []byte
+	var block []byte
+	entries := make([]Entry, 0, compressedBlockEntries)
+	for idx := EntryIdx(0); idx <= src.LastEntryIdx(); idx++ {
+		entry, err := src.Read(idx)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		if len(entries) < compressedBlockEntries && idx < src.LastEntryIdx() {
+			continue
+		}
+		block = encoder.EncodeAll(src.encode(idx+1-EntryIdx(len(entries)), entries), block[:0])
+		if _, err := w.Write(block); err != nil {
+			return err
+		}
+		offset += int64(len(block))
+		trailer = binary.LittleEndian.AppendUint64(trailer, uint64(offset))
+		entries = entries[:0]
+	}
+	blocks := len(trailer) / 8
+	trailer = binary.LittleEndian.AppendUint64(trailer, uint64(src.Size()))
+	trailer = binary.LittleEndian.AppendUint32(trailer, uint32(blocks))
+	trailer = binary.LittleEndian.AppendUint32(trailer, crc32.Checksum(trailer, checksumTable))
+	_, err = w.Write(trailer)
+	return err
+}
+
+// openCompressedSegment opens the compressed segment file at the given path, and reads its block index.
+func openCompressedSegment(path string) (*compressedSegment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := readCompressedSegment(file)
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	return c, nil
+}
+
+func readCompressedSegment(file *os.File) (*compressedSegment, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var header Header
+	var trailer [compressedTrailerSize]byte
+	if info.Size() < HeaderSize+compressedTrailerSize {
+		return nil, fmt.Errorf("%w: compressed segment of %d bytes is too small", ErrCorrupted, info.Size())
+	}
+	if _, err := file.ReadAt(header[:], 0); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if _, err := file.ReadAt(trailer[:], info.Size()-compressedTrailerSize); err != nil {
+		return nil, fmt.Errorf("failed to read trailer: %w", err)
+	}
+	blocks := int64(binary.LittleEndian.Uint32(trailer[8:12]))
+	indexStart := info.Size() - compressedTrailerSize - blocks*8
+	if indexStart < HeaderSize {
+		return nil, fmt.Errorf("%w: block index of compressed segment does not fit in the file", ErrCorrupted)
+	}
+	index := make([]byte, blocks*8+compressedTrailerSize-4)
+	if _, err := file.ReadAt(index, indexStart); err != nil {
+		return nil, fmt.Errorf("failed to read block index: %w", err)
+	}
+	if binary.LittleEndian.Uint32(trailer[12:]) != crc32.Checksum(index, checksumTable) {
+		return nil, fmt.Errorf("%w: checksum mismatch of block index of compressed segment", ErrCorrupted)
+	}
+	c := &compressedSegment{
+		file:        file,
+		header:      header,
+		entries:     int64(binary.LittleEndian.Uint64(trailer[:8])),
+		fileSize:    info.Size(),
+		blocks:      make([]int64, blocks),
+		cachedBlock: -1,
+	}
+	for i := range c.blocks {
+		c.blocks[i] = int64(binary.LittleEndian.Uint64(index[i*8:]))
+	}
+	if (c.entries+compressedBlockEntries-1)/compressedBlockEntries != blocks {
+		return nil, fmt.Errorf("%w: compressed segment with %d entries has %d blocks", ErrCorrupted, c.entries, blocks)
+	}
+	return c, nil
+}
+
+func (c *compressedSegment) recordSize() int64 {
+	if c.header.Checksums() {
+		return EntrySize + ChecksumSize
+	}
+	return EntrySize
+}
+
+func (c *compressedSegment) Size() int64 {
+	return c.entries
+}
+
+// FileSize returns the size of the compressed file.
+func (c *compressedSegment) FileSize() int64 {
+	return c.fileSize
+}
+
+// Read an entry from the segment by index. Returns io.EOF iff idx is after the last entry.
+// Returns ErrCorrupted if the block of the entry cannot be decompressed, or the entry does not match its checksum.
+func (c *compressedSegment) Read(idx EntryIdx) (Entry, error) {
+	if int64(idx) >= c.entries {
+		return Entry{}, io.EOF
+	}
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+	block := int64(idx) / compressedBlockEntries
+	if block != c.cachedBlock {
+		data, err := c.readBlock(block)
+		if err != nil {
+			return Entry{}, fmt.Errorf("failed to read entry %v: %w", idx, err)
+		}
+		c.cachedBlock, c.cached = block, data
+	}
+	offset := (int64(idx) % compressedBlockEntries) * c.recordSize()
+	if offset+c.recordSize() > int64(len(c.cached)) {
+		return Entry{}, fmt.Errorf("%w: entry %v is missing from its compressed block", ErrCorrupted, idx)
+	}
+	record := c.cached[offset : offset+c.recordSize()]
+	out := Entry(record[:EntrySize])
+	if c.header.Checksums() && binary.LittleEndian.Uint32(record[EntrySize:]) != checksum(idx, out) {
+		return Entry{}, fmt.Errorf("%w: checksum mismatch of entry %v", ErrCorrupted, idx)
+	}
+	return out, nil
+}
+
+// readBlock reads and decompresses the given block of entries.
+func (c *compressedSegment) readBlock(block int64) ([]byte, error) {
+	start := int64(HeaderSize)
+	if block > 0 {
+		start = c.blocks[block-1]
+	}
+	end := c.blocks[block]
+	if end < start {
+		return nil, fmt.Errorf("%w: invalid offsets of compressed block %v", ErrCorrupted, block)
+	}
+	data := make([]byte, end-start)
+	if _, err := c.file.ReadAt(data, start); err != nil {
+		return nil, fmt.Errorf("failed to read compressed block %v: %w", block, err)
+	}
+	decompressed, err := zstdDecoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decompress block %v: %v", ErrCorrupted, block, err)
+	}
+	return decompressed, nil
+}
+
+func (c *compressedSegment) Close() error {
+	return c.file.Close()
+}
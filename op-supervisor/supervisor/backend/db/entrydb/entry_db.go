@@ -1,27 +1,61 @@
 package entrydb
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/log"
 )
 
 const (
 	EntrySize = 24
+	// ChecksumSize is the size of the checksum that follows each entry, in databases with checksums.
+	ChecksumSize = 4
+	// HeaderSize is the size of the header at the start of the database file.
+	HeaderSize = EntrySize
+	// headerMarker is the first byte of the header. It is not a valid entry type,
+	// so that databases created without a header, by earlier versions, can be recognized by their first entry.
+	headerMarker = 0xff
+	// headerFlagChecksums is set in the flags byte of the header if each entry is followed by a checksum.
+	headerFlagChecksums = 0x01
+	// mmapRemapSize is the number of bytes that may be appended after the memory-mapped data,
+	// before the data is mapped again to include them. Until then, these bytes are read with file I/O.
+	mmapRemapSize = 16 << 20
 )
 
+// ErrCorrupted is returned when the stored data of an entry does not match its checksum.
+var ErrCorrupted = errors.New("corrupted entry")
+
+// ErrReadOnly is returned when a database that was opened read-only is written to.
+var ErrReadOnly = errors.New("database is read-only")
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
 type EntryIdx int64
 
+// Header is stored at the start of the database file, and describes the format of the entries that follow it.
+// The first byte is the header marker, and the last byte holds the flags of the EntryDB.
+// The remaining bytes are defined by the user of the EntryDB.
+type Header [HeaderSize]byte
+
+// Checksums returns true if each entry of the database is followed by a checksum.
+func (h Header) Checksums() bool {
+	return h[HeaderSize-1]&headerFlagChecksums != 0
+}
+
 type Entry [EntrySize]byte
 
 func (entry Entry) Type() EntryType {
 	return EntryType(entry[0])
 }
 
-type EntryTypeFlag uint8
+type EntryTypeFlag uint16
 
 const (
 	FlagSearchCheckpoint EntryTypeFlag = 1 << TypeSearchCheckpoint
@@ -29,9 +63,12 @@ const (
 	FlagInitiatingEvent  EntryTypeFlag = 1 << TypeInitiatingEvent
 	FlagExecutingLink    EntryTypeFlag = 1 << TypeExecutingLink
 	FlagExecutingCheck   EntryTypeFlag = 1 << TypeExecutingCheck
-	FlagPadding          EntryTypeFlag = 1 << TypePadding
-	// for additional padding
-	FlagPadding2 EntryTypeFlag = FlagPadding << 1
+	FlagHashExtension    EntryTypeFlag = 1 << TypeHashExtension
+	FlagDerivedFrom      EntryTypeFlag = 1 << TypeDerivedFrom
+	FlagSafeHead         EntryTypeFlag = 1 << TypeSafeHead
+	FlagL1Block          EntryTypeFlag = 1 << TypeL1Block
+	FlagDepositEvent     EntryTypeFlag = 1 << TypeDepositEvent
+	FlagInvalidated      EntryTypeFlag = 1 << TypeInvalidated
 )
 
 func (ex EntryTypeFlag) Any(v EntryTypeFlag) bool {
@@ -55,6 +92,12 @@ const (
 	TypeExecutingLink
 	TypeExecutingCheck
 	TypePadding
+	TypeHashExtension
+	TypeDerivedFrom
+	TypeSafeHead
+	TypeL1Block
+	TypeDepositEvent
+	TypeInvalidated
 )
 
 func (d EntryType) String() string {
@@ -71,6 +114,18 @@ func (d EntryType) String() string {
 		return "executingCheck"
 	case TypePadding:
 		return "padding"
+	case TypeHashExtension:
+		return "hashExtension"
+	case TypeDerivedFrom:
+		return "derivedFrom"
+	case TypeSafeHead:
+		return "safeHead"
+	case TypeL1Block:
+		return "l1Block"
+	case TypeDepositEvent:
+		return "depositEvent"
+	case TypeInvalidated:
+		return "invalidated"
 	default:
 		return fmt.Sprintf("unknown-%d", uint8(d))
 	}
@@ -83,11 +138,34 @@ type dataAccess interface {
 	io.Writer
 	io.Closer
 	Truncate(size int64) error
+	Sync() error
 }
 
 type EntryDB struct {
-	data         dataAccess
-	lastEntryIdx EntryIdx
+	log  log.Logger
+	data dataAccess
+	// lastEntryIdx is the index of the last entry.
+	// It is accessed atomically, as entries may be read concurrently with appends.
+	lastEntryIdx atomic.Int64
+	// header is the header of the database, or nil if the database does not have a header.
+	header *Header
+	// checksums is true if each entry is followed by a checksum of the entry and its index.
+	checksums bool
+	// wal is the write-ahead log of the database, or nil if the database is written without one.
+	wal *wal
+
+	// mmap is true if the data is to be memory-mapped.
+	// It is disabled if the data cannot be memory-mapped.
+	mmap bool
+	// mapped is the memory-mapped data, or nil if the data is not memory-mapped.
+	// Reads within the mapped data avoid file I/O.
+	mapped []byte
+	// mappedLock prevents the data from being unmapped while it is read.
+	// Readers may read concurrently with writes, e.g. with iterators that outlive the read-lock of their DB.
+	mappedLock sync.RWMutex
+
+	// readOnly is true if the database was opened read-only, see NewEntryDBReadOnly.
+	readOnly bool
 
 	cleanupFailedWrite bool
 }
@@ -95,9 +173,13 @@ type EntryDB struct {
 // NewEntryDB creates an EntryDB. A new file will be created if the specified path does not exist,
 // but parent directories will not be created.
 // If the file exists it will be used as the existing data.
+// An empty database is initialized with the given header, while the header of existing data is retained.
+// Existing data without a header remains without a header.
+// New databases store a checksum with each entry, to detect corruption of the data on disk.
+// Existing databases only do so if they were created with checksums.
 // Returns ErrRecoveryRequired if the existing file is not a valid entry db. A EntryDB is still returned but all
 // operations will return ErrRecoveryRequired until the Recover method is called.
-func NewEntryDB(logger log.Logger, path string) (*EntryDB, error) {
+func NewEntryDB(logger log.Logger, path string, header Header) (*EntryDB, error) {
 	logger.Info("Opening entry database", "path", path)
 	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
 	if err != nil {
@@ -107,39 +189,314 @@ func NewEntryDB(logger log.Logger, path string) (*EntryDB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to stat database at %v: %w", path, err)
 	}
-	size := info.Size() / EntrySize
-	db := &EntryDB{
-		data:         file,
-		lastEntryIdx: EntryIdx(size - 1),
+	db := &EntryDB{log: logger, data: file, mmap: true}
+	if err := db.init(info.Size(), header); err != nil {
+		return nil, fmt.Errorf("failed to init database at %v: %w", path, err)
 	}
-	if size*EntrySize != info.Size() {
-		logger.Warn("File size is not a multiple of entry size. Truncating to last complete entry", "fileSize", size, "entrySize", EntrySize)
+	// A new or partial header is written by init, and there are no entries to recover yet.
+	if info.Size() >= HeaderSize && db.dataSize(db.Size()) != info.Size() {
+		logger.Warn("File size is not a multiple of entry size. Truncating to last complete entry", "fileSize", info.Size(), "entrySize", db.recordSize())
 		if err := db.recover(); err != nil {
 			return nil, fmt.Errorf("failed to recover database at %v: %w", path, err)
 		}
 	}
+	db.remap()
+	return db, nil
+}
+
+// NewEntryDBReadOnly opens the existing EntryDB at the given path read-only,
+// e.g. to inspect the database while another process writes to it.
+// The entries are those that were stored when the database was opened:
+// a trailing partial entry is ignored, and entries that are appended later are not read.
+// The data is not memory-mapped, as the file may be truncated by the writer while it is read.
+// Append and Truncate return ErrReadOnly.
+func NewEntryDBReadOnly(logger log.Logger, path string) (*EntryDB, error) {
+	logger.Info("Opening entry database read-only", "path", path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database at %v: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to stat database at %v: %w", path, err), file.Close())
+	}
+	if info.Size() < HeaderSize {
+		return nil, errors.Join(fmt.Errorf("database at %v has no complete header", path), file.Close())
+	}
+	db := &EntryDB{log: logger, data: file, readOnly: true}
+	if err := db.init(info.Size(), Header{}); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to init database at %v: %w", path, err), file.Close())
+	}
+	return db, nil
+}
+
+// remap memory-maps the data, to read entries without file I/O.
+// If the data cannot be memory-mapped, entries are read with file I/O instead.
+func (e *EntryDB) remap() {
+	file, ok := e.data.(*os.File)
+	if !ok || !e.mmap {
+		return
+	}
+	e.unmap()
+	size := e.dataSize(e.Size())
+	if size == 0 {
+		return
+	}
+	mapped, err := mmapFile(file, size)
+	if err != nil {
+		e.log.Warn("Failed to memory-map database, reading with file I/O", "err", err)
+		e.mmap = false
+		return
+	}
+	e.mappedLock.Lock()
+	e.mapped = mapped
+	e.mappedLock.Unlock()
+}
+
+// unmap removes the memory-mapping of the data, if any, such that entries are read with file I/O.
+func (e *EntryDB) unmap() {
+	e.mappedLock.Lock()
+	defer e.mappedLock.Unlock()
+	if e.mapped == nil {
+		return
+	}
+	if err := munmap(e.mapped); err != nil {
+		e.log.Warn("Failed to unmap database", "err", err)
+	}
+	e.mapped = nil
+}
+
+// readRecord reads the stored record at the given offset,
+// from the memory-mapped data if the record is mapped, and with file I/O otherwise.
+func (e *EntryDB) readRecord(record []byte, offset int64) (int, error) {
+	e.mappedLock.RLock()
+	defer e.mappedLock.RUnlock()
+	if end := offset + int64(len(record)); end <= int64(len(e.mapped)) {
+		return copy(record, e.mapped[offset:end]), nil
+	}
+	return e.data.ReadAt(record, offset)
+}
+
+// NewEntryDBWithWAL creates an EntryDB, like NewEntryDB, that writes each batch of appended entries
+// to a write-ahead log, next to the database file, before writing it to the database.
+// When the database is opened, any batch that was only partially written to the database is completed,
+// and entries of a batch that did not make it to the write-ahead log are discarded.
+// This makes each Append atomic, also if the process crashes while writing.
+func NewEntryDBWithWAL(logger log.Logger, path string, header Header, cfg WALConfig) (*EntryDB, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, fmt.Errorf("invalid write-ahead log config: %w", err)
+	}
+	db, err := NewEntryDB(logger, path, header)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.openWAL(logger, path+".wal", cfg); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to open write-ahead log of database at %v: %w", path, err), db.Close())
+	}
 	return db, nil
 }
 
+// openWAL recovers the database to the last complete batch in the write-ahead log at the given path,
+// and starts a new write-ahead log.
+func (e *EntryDB) openWAL(logger log.Logger, path string, cfg WALConfig) error {
+	base, records, ok, err := readWAL(path)
+	if err != nil {
+		return err
+	}
+	if ok {
+		committed := e.Size()
+		if base <= committed {
+			committed = base
+		} else {
+			logger.Warn("Database has fewer entries than at the last checkpoint", "entries", committed, "checkpoint", base)
+		}
+		for _, record := range records {
+			if int64(record.start) > committed {
+				logger.Warn("Write-ahead log does not continue from the database", "entries", committed, "batchStart", record.start)
+				break
+			}
+			if err := e.Truncate(record.start - 1); err != nil {
+				return fmt.Errorf("failed to truncate to batch at entry %v: %w", record.start, err)
+			}
+			if _, err := e.data.Write(e.encode(record.start, record.entries)); err != nil {
+				return fmt.Errorf("failed to replay batch at entry %v: %w", record.start, err)
+			}
+			e.lastEntryIdx.Store(int64(record.start) + int64(len(record.entries)) - 1)
+			committed = e.Size()
+		}
+		if committed < e.Size() {
+			logger.Warn("Discarding entries of incomplete batch", "entries", e.Size(), "committed", committed)
+			if err := e.Truncate(EntryIdx(committed) - 1); err != nil {
+				return fmt.Errorf("failed to discard entries of incomplete batch: %w", err)
+			}
+		}
+		if len(records) > 0 {
+			logger.Info("Recovered database from write-ahead log", "batches", len(records), "entries", committed)
+		}
+	}
+	if cfg.Sync != SyncNone {
+		if err := e.data.Sync(); err != nil {
+			return fmt.Errorf("failed to sync database: %w", err)
+		}
+	}
+	w, err := createWAL(path, cfg, e.Size())
+	if err != nil {
+		return err
+	}
+	e.wal = w
+	return nil
+}
+
+// checkpoint syncs the database, and resets the write-ahead log, if there is any.
+func (e *EntryDB) checkpoint() error {
+	if e.wal == nil {
+		return nil
+	}
+	if e.wal.cfg.Sync != SyncNone {
+		if err := e.data.Sync(); err != nil {
+			return fmt.Errorf("failed to sync database: %w", err)
+		}
+	}
+	if err := e.wal.reset(e.Size()); err != nil {
+		return fmt.Errorf("failed to reset write-ahead log: %w", err)
+	}
+	return nil
+}
+
+// closeWAL checkpoints the database, and closes and removes its write-ahead log, if there is any.
+// Entries that are appended after this are no longer written through a write-ahead log.
+func (e *EntryDB) closeWAL() error {
+	if e.wal == nil {
+		return nil
+	}
+	if err := e.checkpoint(); err != nil {
+		return err
+	}
+	if err := e.wal.Close(); err != nil {
+		return fmt.Errorf("failed to close write-ahead log: %w", err)
+	}
+	if err := removeFile(e.wal.path); err != nil {
+		return fmt.Errorf("failed to remove write-ahead log: %w", err)
+	}
+	e.wal = nil
+	return nil
+}
+
+// init reads the header of existing data, or writes the given header if there is no data yet,
+// and determines the number of entries from the size of the data.
+func (e *EntryDB) init(size int64, header Header) error {
+	if size < HeaderSize {
+		// Remove any partially written header, and start with the given header.
+		if err := e.data.Truncate(0); err != nil {
+			return fmt.Errorf("failed to truncate partial header: %w", err)
+		}
+		header[0] = headerMarker
+		header[HeaderSize-1] = headerFlagChecksums
+		if _, err := e.data.Write(header[:]); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		e.header = &header
+		e.checksums = true
+		e.lastEntryIdx.Store(-1)
+		return nil
+	}
+	var existing Header
+	if _, err := e.data.ReadAt(existing[:], 0); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	if existing[0] == headerMarker {
+		e.header = &existing
+		e.checksums = existing.Checksums()
+		size -= HeaderSize
+	}
+	e.lastEntryIdx.Store(size/e.recordSize() - 1)
+	return nil
+}
+
+// Header returns the header of the database, or false if the database was created without a header.
+func (e *EntryDB) Header() (Header, bool) {
+	if e.header == nil {
+		return Header{}, false
+	}
+	return *e.header, true
+}
+
+// ReadHeader reads the header of the database at the given path, without opening the database.
+// Returns false if the database was created without a header, or has no complete header yet.
+func ReadHeader(path string) (Header, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Header{}, false, fmt.Errorf("failed to open database at %v: %w", path, err)
+	}
+	defer file.Close()
+	var h Header
+	if _, err := io.ReadFull(file, h[:]); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return Header{}, false, nil
+	} else if err != nil {
+		return Header{}, false, fmt.Errorf("failed to read header of database at %v: %w", path, err)
+	}
+	if h[0] != headerMarker {
+		return Header{}, false, nil
+	}
+	return h, true, nil
+}
+
+// recordSize returns the size of an entry as stored, including its checksum, if any.
+func (e *EntryDB) recordSize() int64 {
+	if e.checksums {
+		return EntrySize + ChecksumSize
+	}
+	return EntrySize
+}
+
+// checksum computes the checksum of the entry at the given index.
+// The index is included, so that entries that are stored at the wrong position are detected too.
+func checksum(idx EntryIdx, entry Entry) uint32 {
+	var data [8 + EntrySize]byte
+	binary.LittleEndian.PutUint64(data[:8], uint64(idx))
+	copy(data[8:], entry[:])
+	return crc32.Checksum(data[:], checksumTable)
+}
+
+// dataSize returns the size of the data, including the header, when holding the given number of entries.
+func (e *EntryDB) dataSize(entries int64) int64 {
+	size := entries * e.recordSize()
+	if e.header != nil {
+		size += HeaderSize
+	}
+	return size
+}
+
+// FileSize returns the size of the database file, excluding any trailing partial entry.
+func (e *EntryDB) FileSize() int64 {
+	return e.dataSize(e.Size())
+}
+
 func (e *EntryDB) Size() int64 {
-	return int64(e.lastEntryIdx) + 1
+	return e.lastEntryIdx.Load() + 1
 }
 
 func (e *EntryDB) LastEntryIdx() EntryIdx {
-	return e.lastEntryIdx
+	return EntryIdx(e.lastEntryIdx.Load())
 }
 
 // Read an entry from the database by index. Returns io.EOF iff idx is after the last entry.
+// Returns ErrCorrupted if the entry does not match its checksum.
 func (e *EntryDB) Read(idx EntryIdx) (Entry, error) {
-	if idx > e.lastEntryIdx {
+	if idx > e.LastEntryIdx() {
 		return Entry{}, io.EOF
 	}
-	var out Entry
-	read, err := e.data.ReadAt(out[:], int64(idx)*EntrySize)
+	var buf [EntrySize + ChecksumSize]byte
+	record := buf[:e.recordSize()]
+	read, err := e.readRecord(record, e.dataSize(int64(idx)))
 	// Ignore io.EOF if we read the entire last entry as ReadAt may return io.EOF or nil when it reads the last byte
-	if err != nil && !(errors.Is(err, io.EOF) && read == EntrySize) {
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(record)) {
 		return Entry{}, fmt.Errorf("failed to read entry %v: %w", idx, err)
 	}
+	out := Entry(record[:EntrySize])
+	if e.checksums && binary.LittleEndian.Uint32(record[EntrySize:]) != checksum(idx, out) {
+		return Entry{}, fmt.Errorf("%w: checksum mismatch of entry %v", ErrCorrupted, idx)
+	}
 	return out, nil
 }
 
@@ -148,23 +505,29 @@ func (e *EntryDB) Read(idx EntryIdx) (Entry, error) {
 // If the write fails, it will attempt to truncate any partially written data.
 // Subsequent writes to this instance will fail until partially written data is truncated.
 func (e *EntryDB) Append(entries ...Entry) error {
+	if e.readOnly {
+		return ErrReadOnly
+	}
 	if e.cleanupFailedWrite {
 		// Try to rollback partially written data from a previous Append
-		if truncateErr := e.Truncate(e.lastEntryIdx); truncateErr != nil {
+		if truncateErr := e.Truncate(e.LastEntryIdx()); truncateErr != nil {
 			return fmt.Errorf("failed to recover from previous write error: %w", truncateErr)
 		}
 	}
-	data := make([]byte, 0, len(entries)*EntrySize)
-	for _, entry := range entries {
-		data = append(data, entry[:]...)
+	if e.wal != nil {
+		if err := e.wal.append(e.LastEntryIdx()+1, entries); err != nil {
+			// Start over with a fresh write-ahead log, as records after a partial record are not recovered.
+			return errors.Join(err, e.checkpoint())
+		}
 	}
+	data := e.encode(e.LastEntryIdx()+1, entries)
 	if n, err := e.data.Write(data); err != nil {
 		if n == 0 {
 			// Didn't write any data, so no recovery required
 			return err
 		}
 		// Try to rollback the partially written data
-		if truncateErr := e.Truncate(e.lastEntryIdx); truncateErr != nil {
+		if truncateErr := e.Truncate(e.LastEntryIdx()); truncateErr != nil {
 			// Failed to rollback, set a flag to attempt the clean up on the next write
 			e.cleanupFailedWrite = true
 			return errors.Join(err, fmt.Errorf("failed to remove partially written data: %w", truncateErr))
@@ -172,29 +535,68 @@ func (e *EntryDB) Append(entries ...Entry) error {
 		// Successfully rolled back the changes, still report the failed write
 		return err
 	}
-	e.lastEntryIdx += EntryIdx(len(entries))
+	e.lastEntryIdx.Add(int64(len(entries)))
+	if e.mmap && e.dataSize(e.Size())-int64(len(e.mapped)) >= mmapRemapSize {
+		e.remap()
+	}
+	if e.wal != nil && e.wal.size >= walCheckpointSize {
+		return e.checkpoint()
+	}
 	return nil
 }
 
+// encode the entries, starting at the given index, as stored in the database.
+func (e *EntryDB) encode(start EntryIdx, entries []Entry) []byte {
+	data := make([]byte, 0, int64(len(entries))*e.recordSize())
+	for i, entry := range entries {
+		data = append(data, entry[:]...)
+		if e.checksums {
+			data = binary.LittleEndian.AppendUint32(data, checksum(start+EntryIdx(i), entry))
+		}
+	}
+	return data
+}
+
 // Truncate the database so that the last retained entry is idx. Any entries after idx are deleted.
+// With a write-ahead log, this is a checkpoint, such that the deleted entries are not recovered from the write-ahead log.
 func (e *EntryDB) Truncate(idx EntryIdx) error {
-	if err := e.data.Truncate((int64(idx) + 1) * EntrySize); err != nil {
+	if e.readOnly {
+		return ErrReadOnly
+	}
+	// Memory-mapped data must not be read past the end of the file.
+	e.unmap()
+	if err := e.data.Truncate(e.dataSize(int64(idx) + 1)); err != nil {
 		return fmt.Errorf("failed to truncate to entry %v: %w", idx, err)
 	}
 	// Update the lastEntryIdx cache
-	e.lastEntryIdx = idx
+	e.lastEntryIdx.Store(int64(idx))
 	e.cleanupFailedWrite = false
-	return nil
+	e.remap()
+	return e.checkpoint()
 }
 
 // recover an invalid database by truncating back to the last complete event.
 func (e *EntryDB) recover() error {
-	if err := e.data.Truncate((e.Size()) * EntrySize); err != nil {
+	if err := e.data.Truncate(e.dataSize(e.Size())); err != nil {
 		return fmt.Errorf("failed to truncate trailing partial entries: %w", err)
 	}
 	return nil
 }
 
+// Sync flushes the data of the database to disk.
+func (e *EntryDB) Sync() error {
+	return e.data.Sync()
+}
+
 func (e *EntryDB) Close() error {
+	e.unmap()
+	if e.wal != nil {
+		if err := e.checkpoint(); err != nil {
+			return errors.Join(err, e.wal.Close(), e.data.Close())
+		}
+		if err := e.wal.Close(); err != nil {
+			return errors.Join(err, e.data.Close())
+		}
+	}
 	return e.data.Close()
 }
@@ -114,7 +114,7 @@ func TestTruncateTrailingPartialEntries(t *testing.T) {
 	copy(invalidData[EntrySize:], entry2[:])
 	invalidData[len(invalidData)-1] = 3 // Some invalid trailing data
 	require.NoError(t, os.WriteFile(file, invalidData, 0o644))
-	db, err := NewEntryDB(logger, file)
+	db, err := NewEntryDB(logger, file, Header{})
 	require.NoError(t, err)
 	defer db.Close()
 
@@ -125,6 +125,158 @@ func TestTruncateTrailingPartialEntries(t *testing.T) {
 	require.EqualValues(t, 2*EntrySize, stat.Size())
 }
 
+func TestHeader(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	header := Header{0, 1, 2, 3}
+
+	t.Run("NewDatabase", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDB(logger, file, header)
+		require.NoError(t, err)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2)))
+		require.NoError(t, db.Truncate(0))
+		require.NoError(t, db.Close())
+		stat, err := os.Stat(file)
+		require.NoError(t, err)
+		require.EqualValues(t, HeaderSize+EntrySize+ChecksumSize, stat.Size())
+
+		// The existing header is retained, even if a different header is requested
+		db, err = NewEntryDB(logger, file, Header{0, 4})
+		require.NoError(t, err)
+		defer db.Close()
+		actual, ok := db.Header()
+		require.True(t, ok)
+		expected := Header{headerMarker, 1, 2, 3}
+		expected[HeaderSize-1] = headerFlagChecksums
+		require.Equal(t, expected, actual)
+		require.True(t, actual.Checksums())
+		require.EqualValues(t, 1, db.Size())
+		requireRead(t, db, 0, createEntry(1))
+	})
+
+	t.Run("PartialHeader", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		require.NoError(t, os.WriteFile(file, []byte{headerMarker, 1}, 0o644))
+		db, err := NewEntryDB(logger, file, header)
+		require.NoError(t, err)
+		defer db.Close()
+		actual, ok := db.Header()
+		require.True(t, ok)
+		require.Equal(t, Header{headerMarker, 1, 2, 3}, Header(append(actual[:HeaderSize-1:HeaderSize-1], 0)))
+		require.EqualValues(t, 0, db.Size())
+	})
+
+	t.Run("ExistingWithoutHeader", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		entry1 := createEntry(1)
+		require.NoError(t, os.WriteFile(file, entry1[:], 0o644))
+		db, err := NewEntryDB(logger, file, header)
+		require.NoError(t, err)
+		defer db.Close()
+		_, ok := db.Header()
+		require.False(t, ok)
+		require.EqualValues(t, 1, db.Size())
+		requireRead(t, db, 0, entry1)
+		require.NoError(t, db.Append(createEntry(2)))
+		requireRead(t, db, 1, createEntry(2))
+	})
+
+	t.Run("ReadHeader", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDB(logger, file, header)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		actual, ok, err := ReadHeader(file)
+		require.NoError(t, err)
+		require.True(t, ok)
+		expected, _ := db.Header()
+		require.Equal(t, expected, actual)
+
+		entry1 := createEntry(1)
+		require.NoError(t, os.WriteFile(file, entry1[:], 0o644))
+		_, ok, err = ReadHeader(file)
+		require.NoError(t, err)
+		require.False(t, ok, "should not read an entry as header")
+
+		_, _, err = ReadHeader(filepath.Join(t.TempDir(), "missing.db"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestChecksums(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+
+	t.Run("DetectCorruption", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2), createEntry(3)))
+		require.NoError(t, db.Close())
+
+		// flip a bit of the entry at index 1
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		data[HeaderSize+(EntrySize+ChecksumSize)+5] ^= 0x01
+		require.NoError(t, os.WriteFile(file, data, 0o644))
+
+		db, err = NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		defer db.Close()
+		requireRead(t, db, 0, createEntry(1))
+		_, err = db.Read(1)
+		require.ErrorIs(t, err, ErrCorrupted)
+		require.ErrorContains(t, err, "entry 1")
+		requireRead(t, db, 2, createEntry(3))
+	})
+
+	t.Run("DetectMisplacedEntry", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		require.NoError(t, db.Append(createEntry(1), createEntry(1)))
+		require.NoError(t, db.Close())
+
+		// swap the checksums, the entries are the same, but stored at a different index
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		first := HeaderSize + EntrySize
+		second := first + EntrySize + ChecksumSize
+		var tmp [ChecksumSize]byte
+		copy(tmp[:], data[first:first+ChecksumSize])
+		copy(data[first:first+ChecksumSize], data[second:second+ChecksumSize])
+		copy(data[second:second+ChecksumSize], tmp[:])
+		require.NoError(t, os.WriteFile(file, data, 0o644))
+
+		db, err = NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Read(0)
+		require.ErrorIs(t, err, ErrCorrupted)
+		_, err = db.Read(1)
+		require.ErrorIs(t, err, ErrCorrupted)
+	})
+
+	t.Run("ExistingHeaderWithoutChecksums", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		header := Header{headerMarker, 1, 2, 3}
+		entry1 := createEntry(1)
+		require.NoError(t, os.WriteFile(file, append(header[:], entry1[:]...), 0o644))
+		db, err := NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		defer db.Close()
+		actual, ok := db.Header()
+		require.True(t, ok)
+		require.False(t, actual.Checksums())
+		require.EqualValues(t, 1, db.Size())
+		requireRead(t, db, 0, entry1)
+		require.NoError(t, db.Append(createEntry(2)))
+		requireRead(t, db, 1, createEntry(2))
+		stat, err := os.Stat(file)
+		require.NoError(t, err)
+		require.EqualValues(t, HeaderSize+2*EntrySize, stat.Size())
+	})
+}
+
 func TestWriteErrors(t *testing.T) {
 	expectedErr := errors.New("some error")
 
@@ -189,7 +341,7 @@ func createEntry(i byte) Entry {
 
 func createEntryDB(t *testing.T) *EntryDB {
 	logger := testlog.Logger(t, log.LvlInfo)
-	db, err := NewEntryDB(logger, filepath.Join(t.TempDir(), "entries.db"))
+	db, err := NewEntryDB(logger, filepath.Join(t.TempDir(), "entries.db"), Header{})
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		require.NoError(t, db.Close())
@@ -199,7 +351,8 @@ func createEntryDB(t *testing.T) *EntryDB {
 
 func createEntryDBWithStubData() (*EntryDB, *stubDataAccess) {
 	stubData := &stubDataAccess{}
-	db := &EntryDB{data: stubData, lastEntryIdx: -1}
+	db := &EntryDB{data: stubData}
+	db.lastEntryIdx.Store(-1)
 	return db, stubData
 }
 
@@ -227,6 +380,10 @@ func (s *stubDataAccess) Close() error {
 	return nil
 }
 
+func (s *stubDataAccess) Sync() error {
+	return nil
+}
+
 func (s *stubDataAccess) Truncate(size int64) error {
 	if s.truncateErr != nil {
 		return s.truncateErr
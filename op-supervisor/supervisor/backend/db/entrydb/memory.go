@@ -0,0 +1,94 @@
+package entrydb
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MemoryStore keeps entries in memory only, e.g. for tests, or for data that does not have to survive a restart.
+type MemoryStore struct {
+	header Header
+
+	mu sync.RWMutex
+	// first is the index of the first entry, after the pruned entries
+	first   EntryIdx
+	entries []Entry
+}
+
+// NewMemoryStore creates an empty store with the given header.
+// The header is marked like the header of an EntryDB with checksums, such that it reads the same.
+func NewMemoryStore(header Header) *MemoryStore {
+	header[0] = headerMarker
+	header[HeaderSize-1] = headerFlagChecksums
+	return &MemoryStore{header: header}
+}
+
+func (m *MemoryStore) Header() (Header, bool) {
+	return m.header, true
+}
+
+func (m *MemoryStore) Size() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return int64(m.first) + int64(len(m.entries))
+}
+
+func (m *MemoryStore) LastEntryIdx() EntryIdx {
+	return EntryIdx(m.Size() - 1)
+}
+
+func (m *MemoryStore) FirstEntryIdx() EntryIdx {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.first
+}
+
+func (m *MemoryStore) Read(idx EntryIdx) (Entry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if idx < m.first {
+		return Entry{}, fmt.Errorf("%w: entry %v is before the first entry %v", ErrPruned, idx, m.first)
+	}
+	if idx-m.first >= EntryIdx(len(m.entries)) {
+		return Entry{}, io.EOF
+	}
+	return m.entries[idx-m.first], nil
+}
+
+func (m *MemoryStore) Append(entries ...Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func (m *MemoryStore) Truncate(idx EntryIdx) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if idx < m.first-1 {
+		return fmt.Errorf("%w: cannot truncate to entry %v, before the first entry %v", ErrPruned, idx, m.first)
+	}
+	if n := idx + 1 - m.first; n < EntryIdx(len(m.entries)) {
+		m.entries = m.entries[:n]
+	}
+	return nil
+}
+
+// Prune removes the entries before idx. The last entry is never removed.
+func (m *MemoryStore) Prune(idx EntryIdx) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := min(idx-m.first, EntryIdx(len(m.entries))-1)
+	if n <= 0 {
+		return nil
+	}
+	// Copy the remaining entries, such that the memory of the pruned entries is released.
+	m.entries = append([]Entry(nil), m.entries[n:]...)
+	m.first += n
+	return nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
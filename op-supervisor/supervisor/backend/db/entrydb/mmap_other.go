@@ -0,0 +1,19 @@
+//go:build !unix
+
+package entrydb
+
+import (
+	"errors"
+	"os"
+)
+
+var errMmapUnsupported = errors.New("memory-mapping files is not supported on this platform")
+
+// mmapFile is not supported on this platform, all reads fall back to file I/O.
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	return nil, errMmapUnsupported
+}
+
+func munmap(b []byte) error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package entrydb
+
+import (
+	"encoding/binary"
+	"flag"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// benchDBSize is the size of the entry file used in the read benchmarks.
+// Use e.g. -args -entrydb.bench-size=4294967296 to benchmark a multi-GB entry file.
+var benchDBSize = flag.Int64("entrydb.bench-size", 256<<20, "size in bytes of the entry file to benchmark reads with")
+
+func TestMmap(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+
+	t.Run("ReadMappedAndAppended", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2)))
+		require.NoError(t, db.Close())
+
+		db, err = NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		defer db.Close()
+		require.NotNil(t, db.mapped, "existing data should be memory-mapped")
+		require.NoError(t, db.Append(createEntry(3)))
+		requireRead(t, db, 0, createEntry(1))
+		requireRead(t, db, 1, createEntry(2))
+		// appended after the data was mapped, read with file I/O
+		requireRead(t, db, 2, createEntry(3))
+	})
+
+	t.Run("RemapAfterAppending", func(t *testing.T) {
+		db := createEntryDB(t)
+		entries := make([]Entry, mmapRemapSize/(EntrySize+ChecksumSize)+1)
+		for i := range entries {
+			entries[i] = createEntry(byte(i))
+		}
+		require.NoError(t, db.Append(entries...))
+		require.EqualValues(t, db.dataSize(db.Size()), len(db.mapped), "should have mapped the appended data")
+		requireRead(t, db, EntryIdx(len(entries)-1), entries[len(entries)-1])
+	})
+
+	t.Run("Truncate", func(t *testing.T) {
+		db := createEntryDB(t)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2), createEntry(3)))
+		db.remap()
+		require.NoError(t, db.Truncate(0))
+		require.EqualValues(t, db.dataSize(1), len(db.mapped))
+		requireRead(t, db, 0, createEntry(1))
+		_, err := db.Read(1)
+		require.ErrorIs(t, err, io.EOF)
+		require.NoError(t, db.Append(createEntry(4)))
+		requireRead(t, db, 1, createEntry(4))
+	})
+
+	t.Run("DetectCorruption", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2)))
+		require.NoError(t, db.Close())
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		data[HeaderSize+(EntrySize+ChecksumSize)+5] ^= 0x01
+		require.NoError(t, os.WriteFile(file, data, 0o644))
+
+		db, err = NewEntryDB(logger, file, Header{})
+		require.NoError(t, err)
+		defer db.Close()
+		require.NotNil(t, db.mapped)
+		_, err = db.Read(1)
+		require.ErrorIs(t, err, ErrCorrupted)
+	})
+}
+
+// BenchmarkRead compares reads from memory-mapped data with reads with file I/O,
+// with sequential reads, as by iterators, and random reads, as by binary searches.
+func BenchmarkRead(b *testing.B) {
+	logger := testlog.Logger(b, log.LvlInfo)
+	file := filepath.Join(b.TempDir(), "entries.db")
+	entries := createBenchDB(b, file, *benchDBSize)
+
+	for _, mmap := range []bool{true, false} {
+		db, err := NewEntryDB(logger, file, Header{})
+		require.NoError(b, err)
+		if !mmap {
+			db.mmap = false
+			db.unmap()
+		}
+		name := "File"
+		if mmap {
+			name = "Mmap"
+		}
+		b.Run(name+"/Sequential", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := db.Read(EntryIdx(int64(i) % entries)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(name+"/Random", func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1234))
+			for i := 0; i < b.N; i++ {
+				if _, err := db.Read(EntryIdx(rng.Int63n(entries))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		require.NoError(b, db.Close())
+	}
+}
+
+// createBenchDB writes an entry file of about the given size, and returns the number of entries.
+func createBenchDB(b *testing.B, file string, size int64) int64 {
+	db, err := NewEntryDB(testlog.Logger(b, log.LvlInfo), file, Header{})
+	require.NoError(b, err)
+	entries := size / (EntrySize + ChecksumSize)
+	const batchSize = 1 << 16
+	batch := make([]Entry, 0, batchSize)
+	for i := int64(0); i < entries; i++ {
+		var entry Entry
+		binary.LittleEndian.PutUint64(entry[:8], uint64(i))
+		batch = append(batch, entry)
+		if len(batch) == batchSize || i == entries-1 {
+			require.NoError(b, db.Append(batch...))
+			batch = batch[:0]
+		}
+	}
+	require.NoError(b, db.Close())
+	return entries
+}
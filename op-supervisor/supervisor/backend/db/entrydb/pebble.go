@@ -0,0 +1,274 @@
+package entrydb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// Keys are prefixed with a constant byte to differentiate the entries from the metadata of the store.
+	keyPrefixMeta  byte = 0
+	keyPrefixEntry byte = 1
+
+	// pebbleRecordSize is the size of an entry as stored, followed by its checksum.
+	pebbleRecordSize = EntrySize + ChecksumSize
+)
+
+var (
+	headerKey = []byte{keyPrefixMeta, 'h'}
+	// firstKey and sizeKey hold the index of the first entry, and the index after the last entry.
+	firstKey = []byte{keyPrefixMeta, 'f'}
+	sizeKey  = []byte{keyPrefixMeta, 's'}
+)
+
+func entryKey(idx EntryIdx) []byte {
+	key := make([]byte, 0, 9)
+	key = append(key, keyPrefixEntry)
+	key = binary.BigEndian.AppendUint64(key, uint64(idx))
+	return key
+}
+
+// PebbleStore stores entries in a Pebble key-value store, keyed by their index.
+// Each entry is stored with its checksum, and each write is a single atomic batch,
+// so the store needs no write-ahead log of its own.
+type PebbleStore struct {
+	log       log.Logger
+	db        *pebble.DB
+	header    Header
+	writeOpts *pebble.WriteOptions
+
+	// mu guards first and size, which are updated after each write is committed.
+	mu    sync.RWMutex
+	first EntryIdx
+	size  EntryIdx
+}
+
+// OpenPebbleStore opens the Pebble store at the given path, or creates a new store with the given header.
+// The header of an existing store is retained.
+// Writes are synced to disk unless the sync policy of the write-ahead log config is SyncNone.
+func OpenPebbleStore(logger log.Logger, path string, header Header, walCfg WALConfig) (*PebbleStore, error) {
+	if err := walCfg.Check(); err != nil {
+		return nil, fmt.Errorf("invalid write-ahead log config: %w", err)
+	}
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pebble store at %v: %w", path, err)
+	}
+	s := &PebbleStore{
+		log:       logger,
+		db:        db,
+		writeOpts: &pebble.WriteOptions{Sync: walCfg.Sync != SyncNone},
+	}
+	if err := s.init(header); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return s, nil
+}
+
+// init reads the metadata of the store, or writes the metadata of a new store with the given header.
+func (s *PebbleStore) init(header Header) error {
+	stored, closer, err := s.db.Get(headerKey)
+	if errors.Is(err, pebble.ErrNotFound) {
+		// Entries are always stored with checksums, and the header is marked as in an EntryDB.
+		header[0] = headerMarker
+		header[HeaderSize-1] = headerFlagChecksums
+		batch := s.db.NewBatch()
+		defer batch.Close()
+		if err := errors.Join(
+			batch.Set(headerKey, header[:], nil),
+			batch.Set(firstKey, binary.BigEndian.AppendUint64(nil, 0), nil),
+			batch.Set(sizeKey, binary.BigEndian.AppendUint64(nil, 0), nil),
+		); err != nil {
+			return fmt.Errorf("failed to create metadata: %w", err)
+		}
+		if err := batch.Commit(s.writeOpts); err != nil {
+			return fmt.Errorf("failed to write metadata: %w", err)
+		}
+		s.header = header
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	if len(stored) != HeaderSize {
+		return errors.Join(fmt.Errorf("%w: header of %d bytes", ErrCorrupted, len(stored)), closer.Close())
+	}
+	copy(s.header[:], stored)
+	if err := closer.Close(); err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	first, err := s.readIndex(firstKey)
+	if err != nil {
+		return fmt.Errorf("failed to read first entry: %w", err)
+	}
+	size, err := s.readIndex(sizeKey)
+	if err != nil {
+		return fmt.Errorf("failed to read size: %w", err)
+	}
+	if first > size {
+		return fmt.Errorf("%w: first entry %v is after the size %v", ErrCorrupted, first, size)
+	}
+	s.first, s.size = first, size
+	return nil
+}
+
+func (s *PebbleStore) readIndex(key []byte) (EntryIdx, error) {
+	val, closer, err := s.db.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+	if len(val) != 8 {
+		return 0, fmt.Errorf("%w: index of %d bytes", ErrCorrupted, len(val))
+	}
+	return EntryIdx(binary.BigEndian.Uint64(val)), nil
+}
+
+// Header returns the header of the store.
+func (s *PebbleStore) Header() (Header, bool) {
+	return s.header, true
+}
+
+func (s *PebbleStore) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int64(s.size)
+}
+
+// FileSize returns the size of the files of the Pebble store on disk.
+func (s *PebbleStore) FileSize() int64 {
+	return int64(s.db.Metrics().DiskSpaceUsage())
+}
+
+func (s *PebbleStore) LastEntryIdx() EntryIdx {
+	return EntryIdx(s.Size() - 1)
+}
+
+// FirstEntryIdx returns the index of the first entry that was not pruned.
+func (s *PebbleStore) FirstEntryIdx() EntryIdx {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.first
+}
+
+// Read an entry from the store by index. Returns io.EOF iff idx is after the last entry.
+// Returns ErrPruned if the entry was pruned, and ErrCorrupted if the entry does not match its checksum.
+func (s *PebbleStore) Read(idx EntryIdx) (Entry, error) {
+	s.mu.RLock()
+	first, size := s.first, s.size
+	s.mu.RUnlock()
+	if idx >= size {
+		return Entry{}, io.EOF
+	}
+	if idx < first {
+		return Entry{}, fmt.Errorf("%w: entry %v is before the first entry %v", ErrPruned, idx, first)
+	}
+	val, closer, err := s.db.Get(entryKey(idx))
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to read entry %v: %w", idx, err)
+	}
+	defer closer.Close()
+	if len(val) != pebbleRecordSize {
+		return Entry{}, fmt.Errorf("%w: entry %v of %d bytes", ErrCorrupted, idx, len(val))
+	}
+	out := Entry(val[:EntrySize])
+	if binary.LittleEndian.Uint32(val[EntrySize:]) != checksum(idx, out) {
+		return Entry{}, fmt.Errorf("%w: checksum mismatch of entry %v", ErrCorrupted, idx)
+	}
+	return out, nil
+}
+
+// Append entries to the store, in a single batch.
+func (s *PebbleStore) Append(entries ...Entry) error {
+	s.mu.RLock()
+	size := s.size
+	s.mu.RUnlock()
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	var record [pebbleRecordSize]byte
+	for i, entry := range entries {
+		idx := size + EntryIdx(i)
+		copy(record[:EntrySize], entry[:])
+		binary.LittleEndian.PutUint32(record[EntrySize:], checksum(idx, entry))
+		if err := batch.Set(entryKey(idx), record[:], nil); err != nil {
+			return fmt.Errorf("failed to write entry %v: %w", idx, err)
+		}
+	}
+	newSize := size + EntryIdx(len(entries))
+	if err := s.commit(batch, sizeKey, newSize); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.size = newSize
+	s.mu.Unlock()
+	return nil
+}
+
+// Truncate the store so that the last retained entry is idx, which must not be after the last entry.
+// Returns ErrPruned if the entry before idx was pruned, as the entries could then not be appended to.
+func (s *PebbleStore) Truncate(idx EntryIdx) error {
+	s.mu.RLock()
+	first, size := s.first, s.size
+	s.mu.RUnlock()
+	if idx < first-1 {
+		return fmt.Errorf("%w: cannot truncate to entry %v, before the first entry %v", ErrPruned, idx, first)
+	}
+	if idx+1 >= size {
+		return nil
+	}
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(entryKey(idx+1), entryKey(size), nil); err != nil {
+		return fmt.Errorf("failed to delete entries after %v: %w", idx, err)
+	}
+	if err := s.commit(batch, sizeKey, idx+1); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.size = idx + 1
+	s.mu.Unlock()
+	return nil
+}
+
+// Prune removes the entries before idx. The last entry is never removed.
+func (s *PebbleStore) Prune(idx EntryIdx) error {
+	s.mu.RLock()
+	first, size := s.first, s.size
+	s.mu.RUnlock()
+	idx = min(idx, size-1)
+	if idx <= first {
+		return nil
+	}
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(entryKey(first), entryKey(idx), nil); err != nil {
+		return fmt.Errorf("failed to delete entries before %v: %w", idx, err)
+	}
+	if err := s.commit(batch, firstKey, idx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.first = idx
+	s.mu.Unlock()
+	return nil
+}
+
+// commit sets the given metadata key to the given index, and commits the batch.
+func (s *PebbleStore) commit(batch *pebble.Batch, key []byte, idx EntryIdx) error {
+	if err := batch.Set(key, binary.BigEndian.AppendUint64(nil, uint64(idx)), nil); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	if err := batch.Commit(s.writeOpts); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+	return nil
+}
+
+func (s *PebbleStore) Close() error {
+	return s.db.Close()
+}
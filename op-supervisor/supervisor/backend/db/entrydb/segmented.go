@@ -0,0 +1,680 @@
+package entrydb
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gofrs/flock"
+)
+
+const (
+	// defaultSegmentSize is the default number of entries after which a new segment file is started.
+	defaultSegmentSize = 1 << 20
+	// segmentSuffix precedes the index of the first entry of a segment, in the name of the segment file.
+	segmentSuffix = ".seg-"
+	// segmentIndexDigits is the number of digits of the index of the first entry, in the name of a segment file.
+	segmentIndexDigits = 20
+)
+
+// ErrPruned is returned when an entry is read that was removed by pruning.
+var ErrPruned = errors.New("pruned entry")
+
+// ErrLocked is returned when a database is opened for writing while another process has it open for writing.
+var ErrLocked = errors.New("database is locked by another process")
+
+// SegmentConfig configures the segment files of a SegmentedDB.
+type SegmentConfig struct {
+	// Size is the number of entries after which a new segment is started.
+	Size int64
+	// CompressDepth is the number of most recent segments that are kept uncompressed.
+	// Older segments are compressed with zstd, to reduce disk usage at the cost of slower reads.
+	// Compression is disabled if zero.
+	CompressDepth int
+}
+
+func DefaultSegmentConfig() SegmentConfig {
+	return SegmentConfig{
+		Size: defaultSegmentSize,
+	}
+}
+
+func (c SegmentConfig) Check() error {
+	if c.Size < 1 {
+		return fmt.Errorf("segment size must be at least 1, got %d", c.Size)
+	}
+	if c.CompressDepth < 0 {
+		return fmt.Errorf("compress depth must not be negative, got %d", c.CompressDepth)
+	}
+	return nil
+}
+
+// SegmentPath returns the path of the segment file, of the database at the given path,
+// that starts with the entry at the given index.
+func SegmentPath(path string, start EntryIdx) string {
+	return fmt.Sprintf("%s%s%0*d", path, segmentSuffix, segmentIndexDigits, start)
+}
+
+// segment holds the entries of a range of entry indices, indexed from zero at the start of the range.
+// The entries are either in an EntryDB, or in a compressed segment file.
+type segment struct {
+	start EntryIdx
+	path  string
+	// db holds the entries of the segment, or is nil if the segment is compressed.
+	db *EntryDB
+	// compressed holds the entries of the segment, or is nil if the segment is not compressed.
+	compressed *compressedSegment
+}
+
+func (seg *segment) size() int64 {
+	if seg.compressed != nil {
+		return seg.compressed.Size()
+	}
+	return seg.db.Size()
+}
+
+func (seg *segment) fileSize() int64 {
+	if seg.compressed != nil {
+		return seg.compressed.FileSize()
+	}
+	return seg.db.FileSize()
+}
+
+func (seg *segment) read(idx EntryIdx) (Entry, error) {
+	if seg.compressed != nil {
+		return seg.compressed.Read(idx)
+	}
+	return seg.db.Read(idx)
+}
+
+func (seg *segment) close() error {
+	if seg.compressed != nil {
+		return seg.compressed.Close()
+	}
+	return seg.db.Close()
+}
+
+// SegmentedDB stores entries in segment files, that each hold a range of entries,
+// such that the entries of old segments can be removed entirely, without rewriting the remaining entries.
+// The file at the path of the database holds only the header; the segment files are stored next to it.
+// A new segment is started once the last segment holds the configured number of entries,
+// at the start of the next batch, such that each batch is appended to a single segment.
+// Only the last segment is written to, through a write-ahead log.
+// Segments that are older than the configured depth may be compressed, as they are only read from.
+// Segments are compressed in the background, such that writes are not blocked while a segment is compressed.
+// A database that is opened for writing is locked, such that no other process can open it for writing,
+// but it can be opened read-only by other processes, see OpenSegmentedDBReadOnly.
+type SegmentedDB struct {
+	log    log.Logger
+	path   string
+	header Header
+	cfg    SegmentConfig
+	walCfg WALConfig
+
+	// lock is held while the database is open for writing, or nil if the database is read-only.
+	lock *flock.Flock
+
+	// segmentsLock prevents segments from being closed or replaced while entries are read.
+	// Entries may be read concurrently with writes, and only writes change the segments.
+	segmentsLock sync.RWMutex
+	segments     []*segment
+
+	// compressLock is held while a segment is compressed, and while segments are removed or decompressed,
+	// such that a segment is only compressed while it remains older than the configured depth.
+	compressLock sync.Mutex
+	// compressCh requests the background compression of segments, if compression is enabled.
+	compressCh chan struct{}
+	// compressStop stops the background compression, which closes compressDone once it stopped.
+	compressStop chan struct{}
+	compressDone chan struct{}
+}
+
+// NewSegmentedDB opens the segmented database at the given path, or creates a new database with the given header.
+// The header of an existing database is retained.
+// Returns an error if the file at the path holds entries, as it is then not a segmented database.
+func NewSegmentedDB(logger log.Logger, path string, header Header, cfg SegmentConfig, walCfg WALConfig) (*SegmentedDB, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, fmt.Errorf("invalid segment config: %w", err)
+	}
+	if err := walCfg.Check(); err != nil {
+		return nil, fmt.Errorf("invalid write-ahead log config: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if locked, err := lock.TryLock(); err != nil {
+		return nil, fmt.Errorf("failed to lock database at %v: %w", path, err)
+	} else if !locked {
+		return nil, fmt.Errorf("%w: %v", ErrLocked, path)
+	}
+	manifest, err := NewEntryDB(logger, path, header)
+	if err != nil {
+		return nil, errors.Join(err, lock.Unlock())
+	}
+	header, ok := manifest.Header()
+	entries := manifest.Size()
+	if err := manifest.Close(); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to close database at %v: %w", path, err), lock.Unlock())
+	}
+	if !ok || entries > 0 {
+		return nil, errors.Join(fmt.Errorf("database at %v is not segmented", path), lock.Unlock())
+	}
+	s := &SegmentedDB{
+		log:    logger,
+		path:   path,
+		header: header,
+		cfg:    cfg,
+		walCfg: walCfg,
+		lock:   lock,
+	}
+	if err := s.openSegments(); err != nil {
+		return nil, errors.Join(err, s.Close())
+	}
+	if cfg.CompressDepth > 0 {
+		s.compressCh = make(chan struct{}, 1)
+		s.compressStop = make(chan struct{})
+		s.compressDone = make(chan struct{})
+		go s.compressLoop()
+		s.requestCompression()
+	}
+	return s, nil
+}
+
+// OpenSegmentedDBReadOnly opens the existing segmented database at the given path read-only.
+// The database may be open for writing by another process at the same time:
+// the entries are those that were written to the segment files when the database was opened.
+// Segments that the writer compresses or prunes after the database is opened remain readable,
+// but entries that the writer truncates may no longer be read, after which the database must be opened again.
+// Append, Truncate and Prune return ErrReadOnly.
+func OpenSegmentedDBReadOnly(logger log.Logger, path string) (*SegmentedDB, error) {
+	header, ok, err := ReadHeader(path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("database at %v has no header", path)
+	}
+	if info, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to stat database at %v: %w", path, err)
+	} else if info.Size() > HeaderSize {
+		return nil, fmt.Errorf("database at %v is not segmented", path)
+	}
+	s := &SegmentedDB{
+		log:    logger,
+		path:   path,
+		header: header,
+	}
+	if err := s.openSegmentsReadOnly(); err != nil {
+		return nil, errors.Join(err, s.Close())
+	}
+	return s, nil
+}
+
+// openSegmentsReadOnly opens the segment files of the database read-only,
+// accounting for segments that are compressed or pruned by a writer while they are opened.
+func (s *SegmentedDB) openSegmentsReadOnly() error {
+	files, err := listSegments(s.path)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		seg := &segment{start: f.start, path: SegmentPath(s.path, f.start)}
+		if !f.compressed {
+			seg.db, err = NewEntryDBReadOnly(s.log, seg.path)
+		}
+		if f.compressed || errors.Is(err, fs.ErrNotExist) {
+			// The uncompressed file is removed once the segment is compressed.
+			seg.db = nil
+			seg.compressed, err = openCompressedSegment(seg.path + compressedSuffix)
+		}
+		if errors.Is(err, fs.ErrNotExist) && len(s.segments) == 0 {
+			continue // pruned since the segments were listed
+		}
+		if err != nil {
+			return fmt.Errorf("failed to open segment at %v: %w", f.start, err)
+		}
+		s.segments = append(s.segments, seg)
+		if i := len(s.segments) - 1; i > 0 {
+			prev := s.segments[i-1]
+			if end := prev.start + EntryIdx(prev.size()); end != f.start {
+				return fmt.Errorf("%w: segment at %v does not continue from the previous segment, which ends at %v", ErrCorrupted, f.start, end)
+			}
+		}
+	}
+	if len(s.segments) == 0 {
+		return fmt.Errorf("database at %v has no segments", s.path)
+	}
+	return nil
+}
+
+// openSegments opens the segment files of the database, or creates the first segment if there are none.
+func (s *SegmentedDB) openSegments() error {
+	files, err := listSegments(s.path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		files = []segmentFile{{start: 0}}
+	}
+	for i, f := range files {
+		seg := &segment{start: f.start, path: SegmentPath(s.path, f.start)}
+		if i < len(files)-1 || f.compressed {
+			// Segments before the last one were checkpointed when the next segment was started,
+			// so any write-ahead log that was left behind holds no entries.
+			if err := removeFile(seg.path + ".wal"); err != nil {
+				return fmt.Errorf("failed to remove write-ahead log of segment at %v: %w", f.start, err)
+			}
+		}
+		if f.compressed {
+			// The compressed file is only moved into place once complete,
+			// so an uncompressed file next to it is left behind by an interrupted (de)compression.
+			if err := removeFile(seg.path); err != nil {
+				return fmt.Errorf("failed to remove uncompressed copy of segment at %v: %w", f.start, err)
+			}
+			seg.compressed, err = openCompressedSegment(seg.path + compressedSuffix)
+		} else if i < len(files)-1 {
+			seg.db, err = NewEntryDB(s.log, seg.path, s.header)
+		} else {
+			seg.db, err = NewEntryDBWithWAL(s.log, seg.path, s.header, s.walCfg)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to open segment at %v: %w", f.start, err)
+		}
+		s.segments = append(s.segments, seg)
+		if i > 0 {
+			prev := s.segments[i-1]
+			if end := prev.start + EntryIdx(prev.size()); end != f.start {
+				return fmt.Errorf("%w: segment at %v does not continue from the previous segment, which ends at %v", ErrCorrupted, f.start, end)
+			}
+		}
+	}
+	// The last segment is written to, so it must not be compressed.
+	if last := s.segments[len(s.segments)-1]; last.compressed != nil {
+		if err := s.decompressSegment(last); err != nil {
+			return fmt.Errorf("failed to decompress last segment at %v: %w", last.start, err)
+		}
+		if err := last.db.openWAL(s.log, last.path+".wal", s.walCfg); err != nil {
+			return fmt.Errorf("failed to open write-ahead log of segment at %v: %w", last.start, err)
+		}
+	}
+	return nil
+}
+
+// segmentFile is a segment file, as found next to the database file.
+type segmentFile struct {
+	start      EntryIdx
+	compressed bool
+}
+
+// listSegments returns the segment files of the database at the given path, in order.
+func listSegments(path string) ([]segmentFile, error) {
+	names, err := filepath.Glob(path + segmentSuffix + "*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+	compressed := make(map[EntryIdx]bool)
+	for _, name := range names {
+		suffix := strings.TrimPrefix(name, path+segmentSuffix)
+		isCompressed := strings.HasSuffix(suffix, compressedSuffix)
+		suffix = strings.TrimSuffix(suffix, compressedSuffix)
+		if len(suffix) != segmentIndexDigits {
+			continue // e.g. the write-ahead log of a segment
+		}
+		start, err := strconv.ParseInt(suffix, 10, 64)
+		if err != nil {
+			continue
+		}
+		compressed[EntryIdx(start)] = compressed[EntryIdx(start)] || isCompressed
+	}
+	files := make([]segmentFile, 0, len(compressed))
+	for start, isCompressed := range compressed {
+		files = append(files, segmentFile{start: start, compressed: isCompressed})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].start < files[j].start })
+	return files, nil
+}
+
+// SegmentedDBFiles returns the files that hold the segmented database at the given path, in order:
+// the database file with the header, followed by the file of each segment.
+// The database must not be open, such that all entries are stored in the segment files,
+// and not in a write-ahead log.
+func SegmentedDBFiles(path string) ([]string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	segments, err := listSegments(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	for _, seg := range segments {
+		name := SegmentPath(path, seg.start)
+		if seg.compressed {
+			name += compressedSuffix
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// requestCompression requests the background compression of the segments that are older than the configured depth.
+func (s *SegmentedDB) requestCompression() {
+	if s.compressCh == nil {
+		return
+	}
+	select {
+	case s.compressCh <- struct{}{}:
+	default:
+		// Compression is already requested
+	}
+}
+
+func (s *SegmentedDB) compressLoop() {
+	defer close(s.compressDone)
+	for {
+		select {
+		case <-s.compressStop:
+			return
+		case <-s.compressCh:
+			s.compressSegments()
+		}
+	}
+}
+
+// compressSegments compresses the segments that are older than the configured depth, if not compressed yet.
+// A segment that fails to compress remains uncompressed, as entries can still be read from it,
+// and is compressed again on the next request.
+func (s *SegmentedDB) compressSegments() {
+	s.segmentsLock.RLock()
+	candidates := slices.Clone(s.segments[:max(len(s.segments)-s.cfg.CompressDepth, 0)])
+	s.segmentsLock.RUnlock()
+	for _, seg := range candidates {
+		select {
+		case <-s.compressStop:
+			return
+		default:
+		}
+		if err := s.compressIfOld(seg); err != nil {
+			s.log.Warn("Failed to compress segment", "path", seg.path, "err", err)
+		}
+	}
+}
+
+// compressIfOld compresses the segment if it is still older than the configured depth, and not compressed yet.
+// Segments may have been removed or decompressed by writes since it was found to be old.
+func (s *SegmentedDB) compressIfOld(seg *segment) error {
+	s.compressLock.Lock()
+	defer s.compressLock.Unlock()
+	s.segmentsLock.RLock()
+	i := slices.Index(s.segments, seg)
+	old := i >= 0 && i < len(s.segments)-s.cfg.CompressDepth
+	s.segmentsLock.RUnlock()
+	if !old || seg.compressed != nil {
+		return nil
+	}
+	return s.compressSegment(seg)
+}
+
+// compressSegment replaces the entries of the segment with a compressed copy.
+// The compressed file is written to a temporary file first, and then moved into place,
+// such that the segment remains intact if the process crashes while compressing it.
+func (s *SegmentedDB) compressSegment(seg *segment) error {
+	tmpPath := seg.path + compressedSuffix + ".tmp"
+	if err := writeCompressedSegment(tmpPath, seg.db); err != nil {
+		return errors.Join(fmt.Errorf("failed to write compressed segment: %w", err), removeFile(tmpPath))
+	}
+	if err := os.Rename(tmpPath, seg.path+compressedSuffix); err != nil {
+		return fmt.Errorf("failed to move compressed segment into place: %w", err)
+	}
+	compressed, err := openCompressedSegment(seg.path + compressedSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to open compressed segment: %w", err)
+	}
+	s.segmentsLock.Lock()
+	db := seg.db
+	seg.db, seg.compressed = nil, compressed
+	s.segmentsLock.Unlock()
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("failed to close uncompressed segment: %w", err)
+	}
+	if err := removeFile(seg.path); err != nil {
+		return fmt.Errorf("failed to remove uncompressed segment: %w", err)
+	}
+	s.log.Info("Compressed segment", "path", seg.path, "entries", compressed.Size())
+	return nil
+}
+
+// decompressSegment replaces the compressed entries of the segment with an uncompressed copy, to write to it again.
+// The compressed file is removed only once the uncompressed copy is complete,
+// and remains the copy that is used if the process crashes while decompressing the segment.
+func (s *SegmentedDB) decompressSegment(seg *segment) error {
+	if err := removeFile(seg.path); err != nil {
+		return fmt.Errorf("failed to remove incomplete uncompressed segment: %w", err)
+	}
+	db, err := NewEntryDB(s.log, seg.path, seg.compressed.header)
+	if err != nil {
+		return fmt.Errorf("failed to create uncompressed segment: %w", err)
+	}
+	batch := make([]Entry, 0, compressedBlockEntries)
+	for idx := EntryIdx(0); idx < EntryIdx(seg.compressed.Size()); idx++ {
+		entry, err := seg.compressed.Read(idx)
+		if err != nil {
+			return errors.Join(err, db.Close())
+		}
+		batch = append(batch, entry)
+		if len(batch) == compressedBlockEntries || int64(idx) == seg.compressed.Size()-1 {
+			if err := db.Append(batch...); err != nil {
+				return errors.Join(fmt.Errorf("failed to write uncompressed entries: %w", err), db.Close())
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := db.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync uncompressed segment: %w", err), db.Close())
+	}
+	s.segmentsLock.Lock()
+	compressed := seg.compressed
+	seg.db, seg.compressed = db, nil
+	s.segmentsLock.Unlock()
+	if err := compressed.Close(); err != nil {
+		return fmt.Errorf("failed to close compressed segment: %w", err)
+	}
+	return removeFile(seg.path + compressedSuffix)
+}
+
+// Header returns the header of the database.
+func (s *SegmentedDB) Header() (Header, bool) {
+	return s.header, true
+}
+
+func (s *SegmentedDB) Size() int64 {
+	s.segmentsLock.RLock()
+	defer s.segmentsLock.RUnlock()
+	last := s.segments[len(s.segments)-1]
+	return int64(last.start) + last.size()
+}
+
+// FileSize returns the total size of the database file and the segment files, excluding write-ahead logs.
+func (s *SegmentedDB) FileSize() int64 {
+	s.segmentsLock.RLock()
+	defer s.segmentsLock.RUnlock()
+	size := int64(HeaderSize)
+	for _, seg := range s.segments {
+		size += seg.fileSize()
+	}
+	return size
+}
+
+func (s *SegmentedDB) LastEntryIdx() EntryIdx {
+	return EntryIdx(s.Size() - 1)
+}
+
+// FirstEntryIdx returns the index of the first entry that was not pruned.
+func (s *SegmentedDB) FirstEntryIdx() EntryIdx {
+	s.segmentsLock.RLock()
+	defer s.segmentsLock.RUnlock()
+	return s.segments[0].start
+}
+
+// Read an entry from the database by index. Returns io.EOF iff idx is after the last entry.
+// Returns ErrPruned if the entry was pruned, and ErrCorrupted if the entry does not match its checksum.
+func (s *SegmentedDB) Read(idx EntryIdx) (Entry, error) {
+	s.segmentsLock.RLock()
+	defer s.segmentsLock.RUnlock()
+	if first := s.segments[0].start; idx < first {
+		return Entry{}, fmt.Errorf("%w: entry %v is before the first entry %v", ErrPruned, idx, first)
+	}
+	i := sort.Search(len(s.segments), func(i int) bool { return s.segments[i].start > idx }) - 1
+	seg := s.segments[i]
+	return seg.read(idx - seg.start)
+}
+
+// Append entries to the last segment of the database.
+// A new segment is started first if the last segment is full.
+func (s *SegmentedDB) Append(entries ...Entry) error {
+	if s.lock == nil {
+		return ErrReadOnly
+	}
+	if last := s.segments[len(s.segments)-1]; last.db.Size() >= s.cfg.Size {
+		if err := s.startSegment(); err != nil {
+			return fmt.Errorf("failed to start new segment: %w", err)
+		}
+	}
+	return s.segments[len(s.segments)-1].db.Append(entries...)
+}
+
+// startSegment starts a new segment after the last segment, which is no longer written to after this.
+func (s *SegmentedDB) startSegment() error {
+	last := s.segments[len(s.segments)-1]
+	if err := last.db.closeWAL(); err != nil {
+		return fmt.Errorf("failed to close write-ahead log of segment at %v: %w", last.start, err)
+	}
+	start := last.start + EntryIdx(last.db.Size())
+	seg := &segment{start: start, path: SegmentPath(s.path, start)}
+	db, err := NewEntryDBWithWAL(s.log, seg.path, s.header, s.walCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create segment at %v: %w", start, err)
+	}
+	seg.db = db
+	s.segmentsLock.Lock()
+	s.segments = append(s.segments, seg)
+	s.segmentsLock.Unlock()
+	s.log.Info("Started new segment", "path", seg.path)
+	s.requestCompression()
+	return nil
+}
+
+// Truncate the database so that the last retained entry is idx. Any entries after idx are deleted,
+// including the segments that only hold entries after idx.
+// Returns ErrPruned if entries before idx were pruned.
+func (s *SegmentedDB) Truncate(idx EntryIdx) error {
+	if s.lock == nil {
+		return ErrReadOnly
+	}
+	if first := s.segments[0].start; idx < first-1 {
+		return fmt.Errorf("%w: cannot truncate to entry %v, before the first entry %v", ErrPruned, idx, first)
+	}
+	s.compressLock.Lock()
+	defer s.compressLock.Unlock()
+	for len(s.segments) > 1 && s.segments[len(s.segments)-1].start > idx {
+		s.segmentsLock.Lock()
+		seg := s.segments[len(s.segments)-1]
+		s.segments = s.segments[:len(s.segments)-1]
+		s.segmentsLock.Unlock()
+		if err := seg.remove(); err != nil {
+			return fmt.Errorf("failed to remove segment at %v: %w", seg.start, err)
+		}
+	}
+	last := s.segments[len(s.segments)-1]
+	if last.compressed != nil {
+		if err := s.decompressSegment(last); err != nil {
+			return fmt.Errorf("failed to decompress segment at %v: %w", last.start, err)
+		}
+	}
+	if last.db.wal == nil {
+		// The segment was not written to since the next segment was started.
+		if err := last.db.openWAL(s.log, last.path+".wal", s.walCfg); err != nil {
+			return fmt.Errorf("failed to open write-ahead log of segment at %v: %w", last.start, err)
+		}
+	}
+	return last.db.Truncate(idx - last.start)
+}
+
+// Prune removes the segments that only hold entries before idx.
+// Segments are removed entirely, so entries before idx may remain. The last segment is never removed.
+func (s *SegmentedDB) Prune(idx EntryIdx) error {
+	if s.lock == nil {
+		return ErrReadOnly
+	}
+	s.compressLock.Lock()
+	defer s.compressLock.Unlock()
+	s.segmentsLock.Lock()
+	var pruned []*segment
+	for len(s.segments) > 1 && s.segments[1].start <= idx {
+		pruned = append(pruned, s.segments[0])
+		s.segments = s.segments[1:]
+	}
+	s.segmentsLock.Unlock()
+	// Segments are removed in order, such that the remaining segments are contiguous if the process crashes.
+	for _, seg := range pruned {
+		if err := seg.remove(); err != nil {
+			return fmt.Errorf("failed to remove segment at %v: %w", seg.start, err)
+		}
+	}
+	if len(pruned) > 0 {
+		s.log.Info("Pruned segments", "segments", len(pruned), "firstEntry", s.FirstEntryIdx())
+	}
+	return nil
+}
+
+// remove closes the segment and deletes its files.
+// The segment must no longer be in the list of segments of the database.
+func (seg *segment) remove() error {
+	if err := seg.close(); err != nil {
+		return err
+	}
+	if err := removeFile(seg.path); err != nil {
+		return err
+	}
+	if err := removeFile(seg.path + compressedSuffix); err != nil {
+		return err
+	}
+	return removeFile(seg.path + ".wal")
+}
+
+// removeFile removes the file at the given path, if it exists.
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *SegmentedDB) Close() error {
+	if s.compressStop != nil {
+		close(s.compressStop)
+		<-s.compressDone
+		s.compressStop = nil
+	}
+	s.segmentsLock.Lock()
+	defer s.segmentsLock.Unlock()
+	var result error
+	for _, seg := range s.segments {
+		if err := seg.close(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close segment at %v: %w", seg.start, err))
+		}
+	}
+	s.segments = nil
+	if s.lock != nil {
+		if err := s.lock.Unlock(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to unlock database: %w", err))
+		}
+	}
+	return result
+}
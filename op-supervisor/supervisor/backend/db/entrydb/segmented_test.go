@@ -0,0 +1,289 @@
+package entrydb
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentedDB(t *testing.T) {
+	open := func(t *testing.T, path string) *SegmentedDB {
+		db, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{1: 1}, SegmentConfig{Size: 4}, DefaultWALConfig())
+		require.NoError(t, err)
+		return db
+	}
+	// appendBatches appends batches of 3 entries, such that every second batch starts a new segment
+	appendBatches := func(t *testing.T, db *SegmentedDB, batches int) {
+		for i := 0; i < batches; i++ {
+			require.NoError(t, db.Append(createEntry(byte(3*i)), createEntry(byte(3*i+1)), createEntry(byte(3*i+2))))
+		}
+	}
+	requireEntries := func(t *testing.T, db *SegmentedDB, from EntryIdx, size int64) {
+		require.EqualValues(t, size, db.Size())
+		require.EqualValues(t, from, db.FirstEntryIdx())
+		for i := from; i < EntryIdx(size); i++ {
+			entry, err := db.Read(i)
+			require.NoError(t, err)
+			require.Equal(t, createEntry(byte(i)), entry)
+		}
+		_, err := db.Read(EntryIdx(size))
+		require.ErrorIs(t, err, io.EOF)
+	}
+	// requireCompressed waits for the segments at the given starts to be compressed in the background
+	requireCompressed := func(t *testing.T, db *SegmentedDB, starts ...EntryIdx) {
+		require.Eventually(t, func() bool {
+			db.segmentsLock.RLock()
+			defer db.segmentsLock.RUnlock()
+			for _, seg := range db.segments {
+				if slices.Contains(starts, seg.start) && seg.compressed == nil {
+					return false
+				}
+			}
+			return true
+		}, 10*time.Second, time.Millisecond)
+	}
+	requireSegments := func(t *testing.T, path string, starts ...EntryIdx) {
+		names, err := filepath.Glob(path + segmentSuffix + "*")
+		require.NoError(t, err)
+		var expected []string
+		for _, start := range starts {
+			expected = append(expected, SegmentPath(path, start))
+		}
+		expected = append(expected, SegmentPath(path, starts[len(starts)-1])+".wal")
+		require.ElementsMatch(t, expected, names)
+	}
+
+	t.Run("StartSegments", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		appendBatches(t, db, 5)
+		requireEntries(t, db, 0, 15)
+		requireSegments(t, path, 0, 6, 12)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireEntries(t, db, 0, 15)
+		h, ok := db.Header()
+		require.True(t, ok)
+		require.EqualValues(t, 1, h[1])
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.EqualValues(t, HeaderSize, info.Size(), "should only store the header in the database file")
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		appendBatches(t, db, 5)
+		// entry 5 is in the first segment, so it is retained
+		require.NoError(t, db.Prune(5))
+		requireEntries(t, db, 0, 15)
+		require.NoError(t, db.Prune(11))
+		requireEntries(t, db, 6, 15)
+		requireSegments(t, path, 6, 12)
+		_, err := db.Read(5)
+		require.ErrorIs(t, err, ErrPruned)
+		// the last segment is never pruned
+		require.NoError(t, db.Prune(100))
+		requireEntries(t, db, 12, 15)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireEntries(t, db, 12, 15)
+		appendBatches(t, db, 6)
+		require.EqualValues(t, 33, db.Size())
+		require.ErrorIs(t, db.Truncate(10), ErrPruned)
+	})
+
+	t.Run("TruncateSegments", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		appendBatches(t, db, 5)
+		require.NoError(t, db.Truncate(4))
+		requireEntries(t, db, 0, 5)
+		requireSegments(t, path, 0)
+		// entries are appended to the segment that became the last one
+		require.NoError(t, db.Append(createEntry(5)))
+		requireEntries(t, db, 0, 6)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireEntries(t, db, 0, 6)
+		require.NoError(t, db.Truncate(-1))
+		requireEntries(t, db, 0, 0)
+	})
+
+	t.Run("CompressSegments", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		cfg := SegmentConfig{Size: 4, CompressDepth: 1}
+		db, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{1: 1}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		appendBatches(t, db, 5)
+		requireEntries(t, db, 0, 15)
+		requireCompressed(t, db, 0, 6)
+		names, err := filepath.Glob(path + segmentSuffix + "*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			SegmentPath(path, 0) + compressedSuffix,
+			SegmentPath(path, 6) + compressedSuffix,
+			SegmentPath(path, 12),
+			SegmentPath(path, 12) + ".wal",
+		}, names, "should only compress the segments before the last one")
+		require.NoError(t, db.Close())
+
+		db, err = NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		requireEntries(t, db, 0, 15)
+		// truncating into a compressed segment decompresses it, to write to it again
+		require.NoError(t, db.Truncate(8))
+		requireEntries(t, db, 0, 9)
+		require.NoError(t, db.Append(createEntry(9)))
+		requireEntries(t, db, 0, 10)
+		names, err = filepath.Glob(path + segmentSuffix + "*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			SegmentPath(path, 0) + compressedSuffix,
+			SegmentPath(path, 6),
+			SegmentPath(path, 6) + ".wal",
+		}, names)
+	})
+
+	t.Run("CompressMultipleBlocks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		cfg := SegmentConfig{Size: 2*compressedBlockEntries + 10, CompressDepth: 1}
+		db, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{1: 1}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		entries := make([]Entry, cfg.Size)
+		for batch := 0; batch < 2; batch++ {
+			for i := range entries {
+				entries[i] = createEntry(byte(int64(batch)*cfg.Size + int64(i)))
+			}
+			require.NoError(t, db.Append(entries...))
+		}
+		require.NoError(t, db.Append(createEntry(byte(2*cfg.Size))))
+		requireEntries(t, db, 0, 2*cfg.Size+1)
+		requireCompressed(t, db, 0)
+		require.Len(t, db.segments[0].compressed.blocks, 3)
+	})
+
+	t.Run("CorruptedCompressedBlock", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		cfg := SegmentConfig{Size: 4, CompressDepth: 1}
+		db, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{1: 1}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		appendBatches(t, db, 3)
+		requireCompressed(t, db, 0)
+		require.NoError(t, db.Close())
+
+		compressedPath := SegmentPath(path, 0) + compressedSuffix
+		data, err := os.ReadFile(compressedPath)
+		require.NoError(t, err)
+		data[HeaderSize+5] ^= 0xff
+		require.NoError(t, os.WriteFile(compressedPath, data, 0o644))
+		db, err = NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Read(2)
+		require.ErrorIs(t, err, ErrCorrupted)
+		requireRead := func(idx EntryIdx) {
+			entry, err := db.Read(idx)
+			require.NoError(t, err)
+			require.Equal(t, createEntry(byte(idx)), entry)
+		}
+		requireRead(6)
+	})
+
+	t.Run("AppendWhileCompressing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		cfg := SegmentConfig{Size: 4, CompressDepth: 1}
+		db, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{1: 1}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		// hold the lock of the background compression, as if compressing a segment
+		db.compressLock.Lock()
+		appendBatches(t, db, 5)
+		requireEntries(t, db, 0, 15)
+		require.Nil(t, db.segments[0].compressed)
+		db.compressLock.Unlock()
+		requireCompressed(t, db, 0, 6)
+		requireEntries(t, db, 0, 15)
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		_, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{}, SegmentConfig{Size: 4}, DefaultWALConfig())
+		require.ErrorIs(t, err, ErrLocked)
+		require.NoError(t, db.Close())
+		db = open(t, path)
+		require.NoError(t, db.Close())
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		cfg := SegmentConfig{Size: 4, CompressDepth: 2}
+		db, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{1: 1}, cfg, DefaultWALConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		appendBatches(t, db, 5)
+
+		ro, err := OpenSegmentedDBReadOnly(testlog.Logger(t, log.LvlInfo), path)
+		require.NoError(t, err)
+		defer ro.Close()
+		requireEntries(t, ro, 0, 15)
+		h, _ := db.Header()
+		roHeader, ok := ro.Header()
+		require.True(t, ok)
+		require.Equal(t, h, roHeader)
+		require.ErrorIs(t, ro.Append(createEntry(15)), ErrReadOnly)
+		require.ErrorIs(t, ro.Truncate(10), ErrReadOnly)
+		require.ErrorIs(t, ro.Prune(10), ErrReadOnly)
+
+		// the writer compresses and prunes segments that are open read-only
+		appendBatches(t, db, 2)
+		require.NoError(t, db.Prune(12))
+		requireEntries(t, ro, 0, 15)
+		ro2, err := OpenSegmentedDBReadOnly(testlog.Logger(t, log.LvlInfo), path)
+		require.NoError(t, err)
+		defer ro2.Close()
+		require.EqualValues(t, 12, ro2.FirstEntryIdx())
+		require.EqualValues(t, 21, ro2.Size())
+	})
+
+	t.Run("ReadOnlyMissing", func(t *testing.T) {
+		_, err := OpenSegmentedDBReadOnly(testlog.Logger(t, log.LvlInfo), filepath.Join(t.TempDir(), "test.db"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("NotSegmented", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		entries, err := NewEntryDB(testlog.Logger(t, log.LvlInfo), path, Header{})
+		require.NoError(t, err)
+		require.NoError(t, entries.Append(createEntry(1)))
+		require.NoError(t, entries.Close())
+		_, err = NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{}, SegmentConfig{Size: 4}, DefaultWALConfig())
+		require.ErrorContains(t, err, "not segmented")
+	})
+
+	t.Run("MissingSegment", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		appendBatches(t, db, 5)
+		require.NoError(t, db.Close())
+		require.NoError(t, os.Remove(SegmentPath(path, 6)))
+		_, err := NewSegmentedDB(testlog.Logger(t, log.LvlInfo), path, Header{}, SegmentConfig{Size: 4}, DefaultWALConfig())
+		require.ErrorIs(t, err, ErrCorrupted)
+	})
+}
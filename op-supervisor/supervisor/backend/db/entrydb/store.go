@@ -0,0 +1,85 @@
+package entrydb
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Store is a store of entries, as used by the log DB.
+// Entries can be read concurrently with writes.
+type Store interface {
+	// Header returns the header of the store, and false if the store has no header.
+	Header() (Header, bool)
+	Size() int64
+	LastEntryIdx() EntryIdx
+	// FirstEntryIdx returns the index of the first entry that was not pruned.
+	FirstEntryIdx() EntryIdx
+	// Read an entry by index. Returns io.EOF iff idx is after the last entry, and ErrPruned if the entry was pruned.
+	Read(idx EntryIdx) (Entry, error)
+	// Append entries after the last entry, atomically.
+	Append(entries ...Entry) error
+	// Truncate the store so that the last retained entry is idx, which must not be after the last entry.
+	Truncate(idx EntryIdx) error
+	// Prune removes entries before idx, to the extent that the store supports.
+	Prune(idx EntryIdx) error
+	Close() error
+}
+
+var (
+	_ Store = (*SegmentedDB)(nil)
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*PebbleStore)(nil)
+)
+
+// Backend is the kind of store that the entries of a log DB are kept in.
+type Backend uint8
+
+const (
+	// BackendFile stores the entries in segment files, see SegmentedDB.
+	BackendFile Backend = iota
+	// BackendMemory keeps the entries in memory only, see MemoryStore.
+	BackendMemory
+	// BackendPebble stores the entries in a Pebble key-value store, see PebbleStore.
+	BackendPebble
+)
+
+func (b Backend) String() string {
+	switch b {
+	case BackendFile:
+		return "file"
+	case BackendMemory:
+		return "memory"
+	case BackendPebble:
+		return "pebble"
+	default:
+		return fmt.Sprintf("unknown-%d", uint8(b))
+	}
+}
+
+// ParseBackend parses the name of a backend, as returned by Backend.String.
+func ParseBackend(name string) (Backend, error) {
+	for _, b := range []Backend{BackendFile, BackendMemory, BackendPebble} {
+		if b.String() == name {
+			return b, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown backend %q", name)
+}
+
+// OpenStore opens the store of the given backend at the given path, or creates a new store with the given header.
+// The header of an existing store is retained.
+// The segment config only applies to the file backend; the write-ahead log config determines when writes are synced.
+// The memory backend does not use the path, and always creates a new store.
+func OpenStore(logger log.Logger, backend Backend, path string, header Header, segCfg SegmentConfig, walCfg WALConfig) (Store, error) {
+	switch backend {
+	case BackendFile:
+		return NewSegmentedDB(logger, path, header, segCfg, walCfg)
+	case BackendMemory:
+		return NewMemoryStore(header), nil
+	case BackendPebble:
+		return OpenPebbleStore(logger, path, header, walCfg)
+	default:
+		return nil, fmt.Errorf("unknown backend %v", backend)
+	}
+}
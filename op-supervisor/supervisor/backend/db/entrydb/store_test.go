@@ -0,0 +1,139 @@
+package entrydb
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	header := Header{1: 1}
+	requireEntries := func(t *testing.T, s Store, from EntryIdx, size int64) {
+		require.EqualValues(t, size, s.Size())
+		require.EqualValues(t, size-1, s.LastEntryIdx())
+		require.EqualValues(t, from, s.FirstEntryIdx())
+		for i := from; i < EntryIdx(size); i++ {
+			entry, err := s.Read(i)
+			require.NoError(t, err)
+			require.Equal(t, createEntry(byte(i)), entry)
+		}
+		_, err := s.Read(EntryIdx(size))
+		require.ErrorIs(t, err, io.EOF)
+		if from > 0 {
+			_, err := s.Read(from - 1)
+			require.ErrorIs(t, err, ErrPruned)
+		}
+	}
+	appendEntries := func(t *testing.T, s Store, from, to byte) {
+		var entries []Entry
+		for i := from; i < to; i++ {
+			entries = append(entries, createEntry(i))
+		}
+		require.NoError(t, s.Append(entries...))
+	}
+
+	for _, backend := range []Backend{BackendFile, BackendMemory, BackendPebble} {
+		backend := backend
+		open := func(t *testing.T, path string) Store {
+			s, err := OpenStore(testlog.Logger(t, log.LvlInfo), backend, path, header, SegmentConfig{Size: 4}, DefaultWALConfig())
+			require.NoError(t, err)
+			return s
+		}
+		t.Run(backend.String(), func(t *testing.T) {
+			t.Run("AppendAndRead", func(t *testing.T) {
+				s := open(t, filepath.Join(t.TempDir(), "test.db"))
+				defer s.Close()
+				h, ok := s.Header()
+				require.True(t, ok)
+				require.Equal(t, header[1], h[1])
+				require.True(t, h.Checksums())
+				requireEntries(t, s, 0, 0)
+				appendEntries(t, s, 0, 3)
+				appendEntries(t, s, 3, 10)
+				requireEntries(t, s, 0, 10)
+			})
+
+			t.Run("Truncate", func(t *testing.T) {
+				s := open(t, filepath.Join(t.TempDir(), "test.db"))
+				defer s.Close()
+				appendEntries(t, s, 0, 10)
+				require.NoError(t, s.Truncate(5))
+				requireEntries(t, s, 0, 6)
+				appendEntries(t, s, 6, 8)
+				requireEntries(t, s, 0, 8)
+				require.NoError(t, s.Truncate(-1))
+				requireEntries(t, s, 0, 0)
+			})
+
+			t.Run("Prune", func(t *testing.T) {
+				s := open(t, filepath.Join(t.TempDir(), "test.db"))
+				defer s.Close()
+				appendEntries(t, s, 0, 12)
+				// stores may retain more entries than needed, but not fewer
+				require.NoError(t, s.Prune(9))
+				first := s.FirstEntryIdx()
+				require.LessOrEqual(t, first, EntryIdx(9))
+				requireEntries(t, s, first, 12)
+				// the last entry is never pruned
+				require.NoError(t, s.Prune(100))
+				first = s.FirstEntryIdx()
+				require.LessOrEqual(t, first, EntryIdx(11))
+				requireEntries(t, s, first, 12)
+				if first > 0 {
+					require.ErrorIs(t, s.Truncate(first-2), ErrPruned)
+				}
+				appendEntries(t, s, 12, 14)
+				requireEntries(t, s, first, 14)
+			})
+
+			if backend == BackendMemory {
+				return
+			}
+			t.Run("Reopen", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "test.db")
+				s := open(t, path)
+				appendEntries(t, s, 0, 12)
+				require.NoError(t, s.Prune(9))
+				first := s.FirstEntryIdx()
+				require.NoError(t, s.Truncate(10))
+				require.NoError(t, s.Close())
+
+				s, err := OpenStore(testlog.Logger(t, log.LvlInfo), backend, path, Header{2: 2}, SegmentConfig{Size: 4}, DefaultWALConfig())
+				require.NoError(t, err)
+				defer s.Close()
+				h, _ := s.Header()
+				require.Equal(t, header[1], h[1], "should retain the header of the existing store")
+				requireEntries(t, s, first, 11)
+			})
+		})
+	}
+
+	t.Run("ParseBackend", func(t *testing.T) {
+		for _, backend := range []Backend{BackendFile, BackendMemory, BackendPebble} {
+			parsed, err := ParseBackend(backend.String())
+			require.NoError(t, err)
+			require.Equal(t, backend, parsed)
+		}
+		_, err := ParseBackend("leveldb")
+		require.Error(t, err)
+	})
+}
+
+func TestPebbleStoreCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := OpenPebbleStore(testlog.Logger(t, log.LvlInfo), path, Header{}, DefaultWALConfig())
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Append(createEntry(0), createEntry(1)))
+	// as if entry 0 was stored at the position of entry 1
+	val, closer, err := s.db.Get(entryKey(0))
+	require.NoError(t, err)
+	require.NoError(t, errors.Join(s.db.Set(entryKey(1), val, nil), closer.Close()))
+	_, err = s.Read(1)
+	require.ErrorIs(t, err, ErrCorrupted)
+}
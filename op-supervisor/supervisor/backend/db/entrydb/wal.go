@@ -0,0 +1,225 @@
+package entrydb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+)
+
+const (
+	// walHeaderSize is the size of the header of the write-ahead log:
+	// <uint64 number of entries in the database at the last checkpoint: 8 bytes><checksum: 4 bytes>
+	walHeaderSize = 8 + 4
+	// walRecordOverhead is the size of a write-ahead log record, excluding the entries:
+	// <uint64 index of the first entry: 8 bytes><uint32 entry count: 4 bytes><entries><checksum: 4 bytes>
+	walRecordOverhead = 8 + 4 + 4
+	// walCheckpointSize is the size of the write-ahead log after which the database is synced,
+	// and the write-ahead log is reset.
+	walCheckpointSize = 1 << 20
+	// defaultWALGroupSize is the default number of batches that are synced together with SyncGroup.
+	defaultWALGroupSize = 16
+)
+
+// SyncPolicy configures when the write-ahead log is synced to disk.
+type SyncPolicy uint8
+
+const (
+	// SyncNone leaves syncing to the OS.
+	// Batches remain atomic if the process crashes, but may be lost or torn if the machine crashes.
+	SyncNone SyncPolicy = iota
+	// SyncGroup syncs once for a group of batches.
+	// This bounds the number of batches that may be lost if the machine crashes, at a fraction of the syncs.
+	SyncGroup
+	// SyncAlways syncs every batch before it is written to the database.
+	SyncAlways
+)
+
+func (p SyncPolicy) String() string {
+	switch p {
+	case SyncNone:
+		return "none"
+	case SyncGroup:
+		return "group"
+	case SyncAlways:
+		return "always"
+	default:
+		return fmt.Sprintf("unknown-%d", uint8(p))
+	}
+}
+
+// ParseSyncPolicy parses the name of a sync policy, as returned by SyncPolicy.String.
+func ParseSyncPolicy(name string) (SyncPolicy, error) {
+	for _, p := range []SyncPolicy{SyncNone, SyncGroup, SyncAlways} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown sync policy %q", name)
+}
+
+// WALConfig configures the write-ahead log of an EntryDB.
+type WALConfig struct {
+	// Sync determines when the write-ahead log is synced to disk.
+	Sync SyncPolicy
+	// GroupSize is the number of batches that are synced together with SyncGroup.
+	GroupSize int
+}
+
+func DefaultWALConfig() WALConfig {
+	return WALConfig{
+		Sync:      SyncGroup,
+		GroupSize: defaultWALGroupSize,
+	}
+}
+
+func (c WALConfig) Check() error {
+	switch c.Sync {
+	case SyncNone, SyncAlways:
+	case SyncGroup:
+		if c.GroupSize < 1 {
+			return fmt.Errorf("group size must be at least 1, got %d", c.GroupSize)
+		}
+	default:
+		return fmt.Errorf("unknown sync policy %s", c.Sync)
+	}
+	return nil
+}
+
+// walRecord is a batch of entries, as stored in the write-ahead log.
+type walRecord struct {
+	start   EntryIdx
+	entries []Entry
+}
+
+// wal is a write-ahead log of the batches of entries that are appended to an EntryDB.
+// Each batch is written to the write-ahead log before it is written to the database,
+// such that a batch that was only partially written to the database can be completed when the database is opened.
+// The write-ahead log is reset at each checkpoint, after the database itself is synced.
+type wal struct {
+	path string
+	file *os.File
+	cfg  WALConfig
+	// size of the write-ahead log file
+	size int64
+	// number of batches that were written since the last sync
+	pending int
+}
+
+// readWAL reads the write-ahead log at the given path.
+// It returns the number of entries in the database at the last checkpoint,
+// and the complete records that were written since.
+// Returns false if there is no valid write-ahead log.
+func readWAL(path string) (base int64, records []walRecord, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil, false, nil
+	} else if err != nil {
+		return 0, nil, false, fmt.Errorf("failed to read write-ahead log: %w", err)
+	}
+	if len(data) < walHeaderSize || binary.LittleEndian.Uint32(data[8:12]) != crc32.Checksum(data[:8], checksumTable) {
+		return 0, nil, false, nil
+	}
+	base = int64(binary.LittleEndian.Uint64(data[:8]))
+	data = data[walHeaderSize:]
+	for len(data) >= walRecordOverhead {
+		count := int(binary.LittleEndian.Uint32(data[8:12]))
+		size := walRecordOverhead + count*EntrySize
+		if len(data) < size {
+			break // incomplete record
+		}
+		if binary.LittleEndian.Uint32(data[size-4:size]) != crc32.Checksum(data[:size-4], checksumTable) {
+			break // torn record
+		}
+		record := walRecord{start: EntryIdx(binary.LittleEndian.Uint64(data[:8])), entries: make([]Entry, count)}
+		for i := range record.entries {
+			copy(record.entries[i][:], data[12+i*EntrySize:])
+		}
+		records = append(records, record)
+		data = data[size:]
+	}
+	return base, records, true, nil
+}
+
+// createWAL creates an empty write-ahead log, for a database with the given number of entries.
+// The write-ahead log is written to a temporary file first, and then moved into place,
+// such that a crash never leaves a partially written header behind.
+func createWAL(path string, cfg WALConfig, base int64) (*wal, error) {
+	var header [walHeaderSize]byte
+	binary.LittleEndian.PutUint64(header[:8], uint64(base))
+	binary.LittleEndian.PutUint32(header[8:12], crc32.Checksum(header[:8], checksumTable))
+	tmpPath := path + ".tmp"
+	if err := writeFile(tmpPath, header[:], cfg.Sync != SyncNone); err != nil {
+		return nil, fmt.Errorf("failed to write write-ahead log: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, fmt.Errorf("failed to move write-ahead log into place: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	return &wal{path: path, file: file, cfg: cfg, size: walHeaderSize}, nil
+}
+
+func writeFile(path string, data []byte, sync bool) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if sync {
+		if err := file.Sync(); err != nil {
+			return errors.Join(err, file.Close())
+		}
+	}
+	return file.Close()
+}
+
+// append writes a batch of entries, starting at the given index, to the write-ahead log.
+// The write-ahead log is synced as configured by the sync policy.
+func (w *wal) append(start EntryIdx, entries []Entry) error {
+	data := make([]byte, 0, walRecordOverhead+len(entries)*EntrySize)
+	data = binary.LittleEndian.AppendUint64(data, uint64(start))
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(entries)))
+	for _, entry := range entries {
+		data = append(data, entry[:]...)
+	}
+	data = binary.LittleEndian.AppendUint32(data, crc32.Checksum(data, checksumTable))
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	if err != nil {
+		// A partial record is ignored when the write-ahead log is read.
+		return fmt.Errorf("failed to write batch to write-ahead log: %w", err)
+	}
+	w.pending += 1
+	if w.cfg.Sync == SyncAlways || (w.cfg.Sync == SyncGroup && w.pending >= w.cfg.GroupSize) {
+		if err := w.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync write-ahead log: %w", err)
+		}
+		w.pending = 0
+	}
+	return nil
+}
+
+// reset replaces the write-ahead log with an empty one, for a database with the given number of entries.
+// The database must be synced before the write-ahead log is reset.
+func (w *wal) reset(base int64) error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close write-ahead log: %w", err)
+	}
+	next, err := createWAL(w.path, w.cfg, base)
+	if err != nil {
+		return err
+	}
+	*w = *next
+	return nil
+}
+
+func (w *wal) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,132 @@
+package entrydb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWAL(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	open := func(t *testing.T, file string) *EntryDB {
+		db, err := NewEntryDBWithWAL(logger, file, Header{}, WALConfig{Sync: SyncAlways})
+		require.NoError(t, err)
+		return db
+	}
+	// fileSize returns the size of the database file, holding the given number of entries
+	fileSize := func(entries int64) int64 {
+		return HeaderSize + entries*(EntrySize+ChecksumSize)
+	}
+
+	t.Run("ResetOnClose", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db := open(t, file)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2)))
+		require.NoError(t, db.Close())
+
+		info, err := os.Stat(file + ".wal")
+		require.NoError(t, err)
+		require.EqualValues(t, walHeaderSize, info.Size())
+
+		db = open(t, file)
+		defer db.Close()
+		require.EqualValues(t, 2, db.Size())
+		requireRead(t, db, 0, createEntry(1))
+		requireRead(t, db, 1, createEntry(2))
+	})
+
+	t.Run("ReplayTornBatch", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db := open(t, file)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2)))
+		require.NoError(t, db.Append(createEntry(3), createEntry(4), createEntry(5)))
+		// crash while writing the last batch: only part of the batch made it into the database
+		require.NoError(t, os.Truncate(file, fileSize(3)+5))
+
+		db = open(t, file)
+		defer db.Close()
+		require.EqualValues(t, 5, db.Size())
+		for i := byte(0); i < 5; i++ {
+			requireRead(t, db, EntryIdx(i), createEntry(i+1))
+		}
+	})
+
+	t.Run("DiscardBatchMissingFromWAL", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db := open(t, file)
+		require.NoError(t, db.Append(createEntry(1)))
+		// crash after writing a batch to the database, while the batch did not make it into the write-ahead log
+		walData, err := os.ReadFile(file + ".wal")
+		require.NoError(t, err)
+		require.NoError(t, db.Append(createEntry(2), createEntry(3)))
+		require.NoError(t, os.WriteFile(file+".wal", walData, 0o644))
+
+		db = open(t, file)
+		defer db.Close()
+		require.EqualValues(t, 1, db.Size())
+		requireRead(t, db, 0, createEntry(1))
+	})
+
+	t.Run("DiscardTornWALRecord", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db := open(t, file)
+		require.NoError(t, db.Append(createEntry(1)))
+		require.NoError(t, db.Append(createEntry(2), createEntry(3)))
+		// crash while writing the last batch to the write-ahead log
+		info, err := os.Stat(file + ".wal")
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(file+".wal", info.Size()-1))
+		require.NoError(t, os.Truncate(file, fileSize(2)))
+
+		db = open(t, file)
+		defer db.Close()
+		require.EqualValues(t, 1, db.Size())
+		requireRead(t, db, 0, createEntry(1))
+	})
+
+	t.Run("TruncateIsNotUndone", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db := open(t, file)
+		require.NoError(t, db.Append(createEntry(1), createEntry(2), createEntry(3)))
+		require.NoError(t, db.Truncate(0))
+		require.NoError(t, db.Append(createEntry(4)))
+		// crash without closing the database
+
+		db = open(t, file)
+		defer db.Close()
+		require.EqualValues(t, 2, db.Size())
+		requireRead(t, db, 0, createEntry(1))
+		requireRead(t, db, 1, createEntry(4))
+	})
+
+	t.Run("GroupSync", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		db, err := NewEntryDBWithWAL(logger, file, Header{}, WALConfig{Sync: SyncGroup, GroupSize: 3})
+		require.NoError(t, err)
+		defer db.Close()
+		for i := byte(0); i < 4; i++ {
+			require.NoError(t, db.Append(createEntry(i)))
+		}
+		require.Equal(t, 1, db.wal.pending, "should sync once for the first three batches")
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "entries.db")
+		_, err := NewEntryDBWithWAL(logger, file, Header{}, WALConfig{Sync: SyncGroup})
+		require.ErrorContains(t, err, "group size")
+	})
+}
+
+func TestParseSyncPolicy(t *testing.T) {
+	for _, p := range []SyncPolicy{SyncNone, SyncGroup, SyncAlways} {
+		actual, err := ParseSyncPolicy(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p, actual)
+	}
+	_, err := ParseSyncPolicy("sometimes")
+	require.Error(t, err)
+}
@@ -20,6 +20,9 @@ type ChainHeads struct {
 	CrossSafe      entrydb.EntryIdx `json:"crossSafe"`
 	LocalFinalized entrydb.EntryIdx `json:"localFinalized"`
 	CrossFinalized entrydb.EntryIdx `json:"crossFinalized"`
+	// LightVerified is the head up to which the chain data has been verified against an independent source.
+	// It is only used in light-verification mode, to hold back promotion past cross-unsafe.
+	LightVerified entrydb.EntryIdx `json:"lightVerified"`
 }
 
 type Heads struct {
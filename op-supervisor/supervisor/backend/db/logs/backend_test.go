@@ -0,0 +1,85 @@
+package logs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+func TestBackends(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: 16}
+	execMsg := types.ExecutingMessage{Chain: 7, BlockNum: 1, LogIdx: 0, Timestamp: 500, Hash: storedHash(createHash(9), false)}
+	// addBlocks adds the given blocks, each with two logs, the second of which executes a message
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			block := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+			require.NoError(t, db.WriteBatch(func(b Batch) error {
+				if i > 0 {
+					parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+					if err := b.AddLog(createHash(100*i), parent, 0, nil); err != nil {
+						return err
+					}
+					if err := b.AddLog(createHash(100*i+1), parent, 1, &execMsg); err != nil {
+						return err
+					}
+				}
+				return b.SealBlock(createHash(i-1), block, 500+uint64(i))
+			}))
+		}
+	}
+	requireBlocks := func(t *testing.T, db *DB, to int) {
+		n, ok := db.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.EqualValues(t, to, n)
+		for i := 1; i <= to; i++ {
+			_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)})
+			require.NoError(t, err)
+			requireContains(t, db, uint64(i), 0, createHash(100*i))
+			requireContains(t, db, uint64(i), 1, createHash(100*i+1), execMsg)
+		}
+		_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(to + 1), Number: uint64(to + 1)})
+		require.ErrorIs(t, err, ErrFuture)
+	}
+	open := func(t *testing.T, backend entrydb.Backend, path string) *DB {
+		db, err := NewFromBackend(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, backend, path, false, opts,
+			entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		db := open(t, entrydb.BackendMemory, "")
+		defer db.Close()
+		addBlocks(t, db, 0, 30)
+		requireBlocks(t, db, 30)
+		require.NoError(t, db.Rewind(20))
+		requireBlocks(t, db, 20)
+		addBlocks(t, db, 21, 25)
+		requireBlocks(t, db, 25)
+		require.NoError(t, db.Verify())
+	})
+
+	t.Run("Pebble", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, entrydb.BackendPebble, path)
+		addBlocks(t, db, 0, 30)
+		requireBlocks(t, db, 30)
+		require.NoError(t, db.Rewind(20))
+		require.NoError(t, db.Close())
+
+		db = open(t, entrydb.BackendPebble, path)
+		defer db.Close()
+		requireBlocks(t, db, 20)
+		require.Len(t, db.Dependents(execMsg.Chain, execMsg.BlockNum, execMsg.LogIdx), 20)
+		addBlocks(t, db, 21, 25)
+		requireBlocks(t, db, 25)
+		require.NoError(t, db.Verify())
+	})
+}
@@ -0,0 +1,236 @@
+package logs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+const (
+	// bloomSize is the size in bytes of the bloom filter of the log hashes of a search checkpoint interval.
+	// With the default search checkpoint frequency this is at least 8 bits per log hash.
+	bloomSize = 256
+	// bloomHashFuncs is the number of bits that are set in a bloom filter per log hash.
+	bloomHashFuncs = 4
+	// bloomRecordSize is the size of a bloom filter as stored: <bloom filter: 256 bytes><checksum: 4 bytes>
+	bloomRecordSize = bloomSize + 4
+)
+
+var bloomChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// bloomFilter is a bloom filter of the log hashes of a search checkpoint interval.
+// Only the truncated part of each log hash is added, such that the filter is the same with and without full hashes.
+type bloomFilter [bloomSize]byte
+
+// bloomBits returns the bits that represent the log hash in a bloom filter.
+// Log hashes are uniformly distributed, so the bits are taken from the hash itself.
+func bloomBits(logHash common.Hash) (bits [bloomHashFuncs]uint32) {
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint32(logHash[i*4:]) % (bloomSize * 8)
+	}
+	return bits
+}
+
+func (f *bloomFilter) add(logHash common.Hash) {
+	for _, bit := range bloomBits(logHash) {
+		f[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// mayContain returns false if the log hash was definitely not added to the filter.
+func (f *bloomFilter) mayContain(logHash common.Hash) bool {
+	for _, bit := range bloomBits(logHash) {
+		if f[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// addEntry adds the log hash of the entry to the filter, if the entry is an initiating event or a deposit event.
+func (f *bloomFilter) addEntry(entry entrydb.Entry) error {
+	switch entry.Type() {
+	case entrydb.TypeInitiatingEvent:
+		evt, err := newInitiatingEventFromEntry(entry)
+		if err != nil {
+			return err
+		}
+		f.add(evt.logHash)
+	case entrydb.TypeDepositEvent:
+		evt, err := newDepositEventFromEntry(entry)
+		if err != nil {
+			return err
+		}
+		f.add(evt.logHash)
+	}
+	return nil
+}
+
+// bloomIndex holds a bloom filter of the log hashes of each search checkpoint interval of the DB,
+// such that lookups of log hashes that are not in an interval can skip reading the entries of the interval.
+// The filter of an interval is stored in a sidecar file next to the DB, once the search checkpoint after it is written.
+// The filter of the last interval is kept in memory only, and is rebuilt from the entries when the DB is opened.
+// The sidecar file is derived from the DB, and is made consistent with it whenever the state of the DB is initialized.
+type bloomIndex struct {
+	log  log.Logger
+	file *os.File
+	// count is the number of intervals with a stored filter
+	count int64
+	// current is the filter of the interval after the stored filters
+	current bloomFilter
+}
+
+func openBloomIndex(logger log.Logger, path string) (*bloomIndex, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bloom filters at %v: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to stat bloom filters at %v: %w", path, err), file.Close())
+	}
+	return &bloomIndex{log: logger, file: file, count: info.Size() / bloomRecordSize}, nil
+}
+
+// store writes the current filter as the filter of the given interval, and starts a new filter.
+// The filter is not written if an earlier filter is missing, e.g. after a failed write.
+// Missing filters are rebuilt when the DB is opened; until then, the intervals without a filter are read as usual.
+func (b *bloomIndex) store(interval int64) {
+	filter := b.current
+	b.current = bloomFilter{}
+	if interval != b.count {
+		return
+	}
+	record := binary.LittleEndian.AppendUint32(filter[:], crc32.Checksum(filter[:], bloomChecksumTable))
+	if _, err := b.file.WriteAt(record, interval*bloomRecordSize); err != nil {
+		b.log.Warn("Failed to store bloom filter", "interval", interval, "err", err)
+		return
+	}
+	b.count += 1
+}
+
+// mayContain returns false if the log hash is definitely not in the given interval.
+// Intervals without a stored filter may contain any log hash.
+func (b *bloomIndex) mayContain(interval int64, logHash common.Hash) bool {
+	if interval >= b.count {
+		return true
+	}
+	var record [bloomRecordSize]byte
+	if _, err := b.file.ReadAt(record[:], interval*bloomRecordSize); err != nil && !errors.Is(err, io.EOF) {
+		b.log.Warn("Failed to read bloom filter", "interval", interval, "err", err)
+		return true
+	}
+	filter := bloomFilter(record[:bloomSize])
+	if binary.LittleEndian.Uint32(record[bloomSize:]) != crc32.Checksum(filter[:], bloomChecksumTable) {
+		b.log.Warn("Ignoring corrupted bloom filter", "interval", interval)
+		return true
+	}
+	return filter.mayContain(logHash)
+}
+
+// truncate removes the filters of the given interval and later intervals.
+func (b *bloomIndex) truncate(count int64) error {
+	if err := b.file.Truncate(count * bloomRecordSize); err != nil {
+		return fmt.Errorf("failed to truncate bloom filters: %w", err)
+	}
+	b.count = count
+	return nil
+}
+
+func (b *bloomIndex) Close() error {
+	return b.file.Close()
+}
+
+// syncBlooms makes the bloom filters consistent with the entries of the DB:
+// it removes filters of intervals that are no longer complete, and builds any missing filters.
+func (db *DB) syncBlooms() error {
+	if db.blooms == nil {
+		return nil
+	}
+	// An interval is complete, and its filter stored, once the search checkpoint after it is written.
+	var complete int64
+	if db.lastEntryIdx() >= 0 {
+		complete = int64(db.lastEntryIdx() / db.checkpointFrequency)
+	}
+	if db.blooms.count > complete {
+		if err := db.blooms.truncate(complete); err != nil {
+			return err
+		}
+	}
+	// The filters of intervals with pruned entries cannot be built,
+	// so these intervals get a filter that may contain any log hash.
+	first := int64(db.firstCheckpoint())
+	for interval := db.blooms.count; interval < min(complete, first); interval++ {
+		for i := range db.blooms.current {
+			db.blooms.current[i] = 0xff
+		}
+		db.blooms.store(interval)
+	}
+	// Build the missing filters, and then the filter of the last interval.
+	if err := db.buildBlooms(db.blooms.count, complete); err != nil {
+		return err
+	}
+	for idx := entrydb.EntryIdx(max(complete, first)) * db.checkpointFrequency; idx <= db.lastEntryIdx(); idx++ {
+		entry, err := db.store.Read(idx)
+		if err != nil {
+			return fmt.Errorf("failed to read entry %v to build bloom filter: %w", idx, err)
+		}
+		if err := db.blooms.current.addEntry(entry); err != nil {
+			return fmt.Errorf("failed to add entry %v to bloom filter: %w", idx, err)
+		}
+	}
+	return nil
+}
+
+// updateBlooms adds the given entries, that were appended to the DB, to the bloom filters.
+func (db *DB) updateBlooms(start entrydb.EntryIdx, entries []entrydb.Entry) {
+	if db.blooms == nil {
+		return
+	}
+	for i, entry := range entries {
+		idx := start + entrydb.EntryIdx(i)
+		if idx > 0 && idx%db.checkpointFrequency == 0 {
+			db.blooms.store(int64(idx/db.checkpointFrequency) - 1)
+		}
+		if err := db.blooms.current.addEntry(entry); err != nil {
+			db.log.Warn("Failed to add entry to bloom filter", "index", idx, "err", err)
+		}
+	}
+}
+
+// bloomMayContain returns false if the log with the given hash is definitely not at the given position.
+func (db *DB) bloomMayContain(blockNum uint64, logIdx uint32, logHash common.Hash) bool {
+	if db.blooms == nil || blockNum == 0 {
+		return true
+	}
+	// A search for the log starts at this checkpoint, and the log is after it, if it exists.
+	checkpointIdx, err := db.searchCheckpoint(blockNum-1, logIdx)
+	if err != nil {
+		return true // leave it to the search to report the error
+	}
+	for interval := int64(checkpointIdx / db.checkpointFrequency); ; {
+		if db.blooms.mayContain(interval, logHash) {
+			return true
+		}
+		// The interval has a stored filter, so the next search checkpoint exists.
+		// The log is in a later interval only if it comes after that checkpoint.
+		// The intervals of invalidated entries are skipped, up to the checkpoint after them.
+		nextIdx := db.skipInvalidated(entrydb.EntryIdx(interval+1) * db.checkpointFrequency)
+		next, err := db.readSearchCheckpoint(nextIdx)
+		if err != nil {
+			return true
+		}
+		if next.blockNum > blockNum-1 || (next.blockNum == blockNum-1 && next.logsSince > logIdx) {
+			return false
+		}
+		interval = int64(nextIdx / db.checkpointFrequency)
+	}
+}
@@ -0,0 +1,116 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+func TestBloomFilters(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: 16}
+	// addBlocks adds blocks 0 up to and including the given block, with 5 logs each
+	addBlocks := func(t *testing.T, db *DB, to int) {
+		for i := 0; i <= to; i++ {
+			if i > 0 {
+				parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+				for j := 0; j < 5; j++ {
+					require.NoError(t, db.AddLog(createHash(100*i+j), parent, uint32(j), nil))
+				}
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+	}
+	open := func(t *testing.T, path string) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+	// requireSkipped checks that a lookup of a log hash that is not in the DB does not read any entries
+	requireSkipped := func(t *testing.T, db *DB, blockNum uint64, logIdx uint32) {
+		m := db.m.(*stubMetrics)
+		m.entriesReadForSearch = 0
+		_, err := db.Contains(blockNum, logIdx, createHash(7777))
+		require.ErrorIs(t, err, ErrConflict)
+		require.Zero(t, m.entriesReadForSearch, "should not read any entries")
+	}
+	requireAllLogs := func(t *testing.T, db *DB, to int) {
+		for i := 1; i <= to; i++ {
+			for j := 0; j < 5; j++ {
+				requireContains(t, db, uint64(i), uint32(j), createHash(100*i+j))
+			}
+		}
+	}
+
+	t.Run("SkipIntervals", func(t *testing.T) {
+		runDBTestWithOptions(t, opts,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				addBlocks(t, db, 20)
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NotZero(t, db.blooms.count)
+				requireAllLogs(t, db, 20)
+				requireSkipped(t, db, 3, 2)
+				requireSkipped(t, db, 10, 4)
+				// logs after the last stored filter may still be added
+				_, err := db.Contains(21, 0, createHash(7777))
+				require.ErrorIs(t, err, ErrFuture)
+			})
+	})
+
+	t.Run("RebuildMissingFilters", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 20)
+		count := db.blooms.count
+		require.NoError(t, db.Close())
+		require.NoError(t, os.Truncate(path+".bloom", bloomRecordSize+5))
+
+		db = open(t, path)
+		defer db.Close()
+		require.Equal(t, count, db.blooms.count)
+		requireAllLogs(t, db, 20)
+		requireSkipped(t, db, 3, 2)
+	})
+
+	t.Run("RemoveFiltersOnRewind", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		defer db.Close()
+		addBlocks(t, db, 20)
+		require.NoError(t, db.Rewind(5))
+		require.EqualValues(t, db.lastEntryIdx()/db.checkpointFrequency, db.blooms.count)
+		info, err := os.Stat(path + ".bloom")
+		require.NoError(t, err)
+		require.EqualValues(t, db.blooms.count*bloomRecordSize, info.Size())
+
+		// the rewound blocks are replaced with blocks with other logs
+		for i := 6; i <= 20; i++ {
+			parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+			require.NoError(t, db.AddLog(createHash(200*i), parent, 0, nil))
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+		for i := 6; i <= 20; i++ {
+			requireContains(t, db, uint64(i), 0, createHash(200*i))
+		}
+		requireAllLogs(t, db, 5)
+	})
+
+	t.Run("IgnoreCorruptedFilter", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 20)
+		require.NoError(t, db.Close())
+		require.NoError(t, os.WriteFile(path+".bloom", make([]byte, bloomRecordSize*3), 0o644))
+
+		db = open(t, path)
+		defer db.Close()
+		requireAllLogs(t, db, 20)
+	})
+}
@@ -0,0 +1,131 @@
+package logs
+
+import (
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+// ChangeKind is the kind of a change to the DB.
+type ChangeKind uint8
+
+const (
+	// ChangeSealedBlock is the seal of a block.
+	ChangeSealedBlock ChangeKind = iota
+	// ChangeAppendedLog is a log that was appended to the block after the last sealed block.
+	ChangeAppendedLog
+	// ChangeRewound is a rewind of the DB, that removed all data after the seal of a block.
+	ChangeRewound
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeSealedBlock:
+		return "sealedBlock"
+	case ChangeAppendedLog:
+		return "appendedLog"
+	case ChangeRewound:
+		return "rewound"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a change to the DB, as emitted to the subscribers of the DB, see DB.SubscribeChanges.
+type Change struct {
+	Kind ChangeKind
+	// Block is the sealed block, the parent block of the appended log,
+	// or the last sealed block that remains after the rewind.
+	Block eth.BlockID
+	// Timestamp is the timestamp of Block.
+	Timestamp uint64
+	// LogIdx and Log are the index and the contents of the appended log.
+	LogIdx uint32
+	Log    BlockLog
+	// NextIndex is the index of the entry after the change.
+	NextIndex entrydb.EntryIdx
+}
+
+// SubscribeChanges subscribes the given channel to the changes to the DB, in the order that they are written.
+// Changes are sent once they can be read from new snapshots, while the DB is still locked for writing,
+// so writes block until all subscribers received the changes: the channel should be buffered,
+// and be drained without writes to the DB or reads other than of snapshots, or the subscription be unsubscribed.
+func (db *DB) SubscribeChanges(ch chan<- Change) event.Subscription {
+	return db.changes.Subscribe(ch)
+}
+
+// replayWritten applies the given entries, that were appended to the DB, to a copy of the last published state.
+// fn is called after each block that the entries seal, and after each log that the entries complete,
+// with the state after the block or log.
+func (db *DB) replayWritten(entries []entrydb.Entry, fn func(l *logContext, kind ChangeKind) error) error {
+	state := db.tail.Load().clone()
+	sealedHash, sealedNum, _ := state.SealedBlock()
+	seenLog := false
+	for _, entry := range entries {
+		if err := state.ApplyEntry(entry); err != nil {
+			return err
+		}
+		if typ := entry.Type(); typ == entrydb.TypeInitiatingEvent || typ == entrydb.TypeDepositEvent {
+			seenLog = true
+		}
+		hash, num, ok := state.SealedBlock()
+		if !ok {
+			continue
+		}
+		// Search checkpoints repeat the last sealed block, which is not sealed again.
+		if hash != sealedHash || num != sealedNum {
+			sealedHash, sealedNum = hash, num
+			if err := fn(state, ChangeSealedBlock); err != nil {
+				return err
+			}
+		}
+		if seenLog && !state.hasIncompleteLog() {
+			seenLog = false
+			if err := fn(state, ChangeAppendedLog); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writtenChanges returns the changes that the given entries, appended to the DB, make to the last published state.
+func (db *DB) writtenChanges(entries []entrydb.Entry) []Change {
+	var changes []Change
+	err := db.replayWritten(entries, func(l *logContext, kind ChangeKind) error {
+		changes = append(changes, newChange(l, kind))
+		return nil
+	})
+	if err != nil {
+		db.log.Warn("Failed to read changes of written entries", "err", err)
+	}
+	return changes
+}
+
+// notifyChanges sends the given changes to the subscribers.
+func (db *DB) notifyChanges(changes []Change) {
+	for _, c := range changes {
+		db.changes.Send(c)
+	}
+}
+
+// newChange returns the change of the given kind, as of the given state.
+func newChange(l *logContext, kind ChangeKind) Change {
+	hash, num, _ := l.SealedBlock()
+	c := Change{
+		Kind:      kind,
+		Block:     eth.BlockID{Hash: hash, Number: num},
+		Timestamp: l.timestamp,
+		NextIndex: l.nextEntryIndex,
+	}
+	if kind == ChangeAppendedLog {
+		var logHash common.Hash
+		logHash, c.LogIdx, _ = l.InitMessage()
+		c.Log = BlockLog{Hash: logHash, ExecMsgs: slices.Clone(l.execMsgs), Deposit: l.deposit}
+	}
+	return c
+}
@@ -0,0 +1,96 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+func TestSubscribeChanges(t *testing.T) {
+	// drain returns the changes that were sent so far
+	drain := func(ch chan Change) []Change {
+		var changes []Change
+		for {
+			select {
+			case c := <-ch:
+				changes = append(changes, c)
+			default:
+				return changes
+			}
+		}
+	}
+	execMsg := types.ExecutingMessage{Chain: 3, BlockNum: 2, LogIdx: 1, Timestamp: 400, Hash: createHash(9)}
+
+	for _, opts := range []Options{DefaultOptions(), {SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true}} {
+		opts := opts
+		name := "TruncatedHashes"
+		if opts.FullHashes {
+			name = "FullHashes"
+		}
+		// block returns the block with the given number, with the part of its hash that is stored
+		block := func(i int) eth.BlockID {
+			return eth.BlockID{Hash: storedHash(createHash(i), opts.FullHashes), Number: uint64(i)}
+		}
+		t.Run(name, func(t *testing.T) {
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					ch := make(chan Change, 100)
+					sub := db.SubscribeChanges(ch)
+					defer sub.Unsubscribe()
+
+					require.NoError(t, db.SealBlock(common.Hash{}, block(0), 500))
+					require.NoError(t, db.AddLog(createHash(100), block(0), 0, nil))
+					require.NoError(t, db.AddDepositEvent(createHash(101), block(0), 1))
+					require.NoError(t, db.WriteBatch(func(b Batch) error {
+						if err := b.AddLog(createHash(102), block(0), 2, &execMsg); err != nil {
+							return err
+						}
+						return b.SealBlock(createHash(0), block(1), 501)
+					}))
+					// enough blocks to repeat the last sealed block at search checkpoints
+					for i := 2; i <= 5; i++ {
+						for j := 0; j < 3; j++ {
+							require.NoError(t, db.AddLog(createHash(100*i+j), block(i-1), uint32(j), nil))
+						}
+						require.NoError(t, db.SealBlock(createHash(i-1), block(i), 500+uint64(i)))
+					}
+
+					changes := drain(ch)
+					require.Equal(t, Change{Kind: ChangeSealedBlock, Block: block(0), Timestamp: 500, NextIndex: changes[0].NextIndex}, changes[0])
+					require.Equal(t, ChangeAppendedLog, changes[1].Kind)
+					require.Equal(t, block(0), changes[1].Block)
+					require.Equal(t, BlockLog{Hash: storedHash(createHash(100), opts.FullHashes)}, changes[1].Log)
+					require.Equal(t, ChangeAppendedLog, changes[2].Kind)
+					require.EqualValues(t, 1, changes[2].LogIdx)
+					require.True(t, changes[2].Log.Deposit)
+					require.Equal(t, ChangeAppendedLog, changes[3].Kind)
+					require.EqualValues(t, 2, changes[3].LogIdx)
+					storedMsg := execMsg
+					storedMsg.Hash = storedHash(execMsg.Hash, opts.FullHashes)
+					require.Equal(t, []types.ExecutingMessage{storedMsg}, changes[3].Log.ExecMsgs)
+					require.Equal(t, Change{Kind: ChangeSealedBlock, Block: block(1), Timestamp: 501, NextIndex: changes[4].NextIndex}, changes[4])
+					require.Len(t, changes, 5+4*4, "each block should be sealed once")
+					for i, c := range changes {
+						if i > 0 {
+							require.Greater(t, c.NextIndex, changes[i-1].NextIndex)
+						}
+						require.LessOrEqual(t, c.NextIndex, db.NextIndex())
+					}
+					require.Equal(t, block(5), changes[len(changes)-1].Block)
+
+					require.NoError(t, db.Rewind(3))
+					changes = drain(ch)
+					require.Equal(t, []Change{{Kind: ChangeRewound, Block: block(3), Timestamp: 503, NextIndex: db.NextIndex()}}, changes)
+
+					sub.Unsubscribe()
+					require.NoError(t, db.SealBlock(createHash(3), block(4), 504))
+					require.Empty(t, drain(ch))
+				})
+		})
+	}
+}
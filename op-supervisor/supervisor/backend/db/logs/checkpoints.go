@@ -0,0 +1,206 @@
+package logs
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+// checkpointIndex holds the search checkpoints of the DB in memory,
+// such that the binary searches over the checkpoints do not read entries from the store.
+// The checkpoint with number n is the entry at index n * checkpointFrequency.
+// It is shared with the snapshots of the DB, so it has its own lock.
+// The index also tracks the ranges of invalidated entries, that end at the checkpoints that re-seal
+// the parents of invalidated blocks, such that reads can skip them.
+type checkpointIndex struct {
+	mu sync.RWMutex
+	// first is the number of the first checkpoint in the index.
+	first entrydb.EntryIdx
+	// checkpoints holds the checkpoints from the first, in order.
+	checkpoints []searchCheckpoint
+	// invalidations holds the numbers of the indexed checkpoints that end a range of invalidated entries, in order.
+	invalidations []entrydb.EntryIdx
+}
+
+// invalidatedRange is a range of invalidated entries: from start, up to but not including end.
+type invalidatedRange struct {
+	start, end entrydb.EntryIdx
+}
+
+// get returns the checkpoint with the given number, or false if it is not in the index.
+func (c *checkpointIndex) get(n entrydb.EntryIdx) (searchCheckpoint, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if n < c.first || n >= c.first+entrydb.EntryIdx(len(c.checkpoints)) {
+		return searchCheckpoint{}, false
+	}
+	return c.checkpoints[n-c.first], true
+}
+
+// end returns the number of the checkpoint after the last checkpoint in the index.
+func (c *checkpointIndex) end() entrydb.EntryIdx {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.first + entrydb.EntryIdx(len(c.checkpoints))
+}
+
+// add adds the checkpoint with the given number, which must follow the last checkpoint in the index.
+func (c *checkpointIndex) add(n entrydb.EntryIdx, checkpoint searchCheckpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.checkpoints) == 0 {
+		c.first = n
+	} else if n != c.first+entrydb.EntryIdx(len(c.checkpoints)) {
+		panic(fmt.Errorf("checkpoint %d does not follow the last indexed checkpoint %d", n, c.first+entrydb.EntryIdx(len(c.checkpoints))-1))
+	}
+	c.checkpoints = append(c.checkpoints, checkpoint)
+	if checkpoint.invalidated > 0 {
+		c.invalidations = append(c.invalidations, n)
+	}
+}
+
+// skip returns the first index at or after the given index that is not in a range of invalidated entries.
+func (c *checkpointIndex) skip(idx, frequency entrydb.EntryIdx) entrydb.EntryIdx {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// The ranges are ordered by their end, so the end of a range can only be in a later range.
+	for _, n := range c.invalidations {
+		if r := c.invalidatedRange(n, frequency); r.start <= idx && idx < r.end {
+			idx = r.end
+		}
+	}
+	return idx
+}
+
+// skipBack returns the last index at or before the given index that is not in a range of invalidated entries.
+func (c *checkpointIndex) skipBack(idx, frequency entrydb.EntryIdx) entrydb.EntryIdx {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := len(c.invalidations) - 1; i >= 0; i-- {
+		if r := c.invalidatedRange(c.invalidations[i], frequency); r.start <= idx && idx < r.end {
+			idx = r.start - 1
+		}
+	}
+	return idx
+}
+
+// invalidatedRange returns the range of invalidated entries that ends at the checkpoint with the given number.
+func (c *checkpointIndex) invalidatedRange(n, frequency entrydb.EntryIdx) invalidatedRange {
+	end := n * frequency
+	return invalidatedRange{start: end - entrydb.EntryIdx(c.checkpoints[n-c.first].invalidated), end: end}
+}
+
+// invalidatedRanges returns the ranges of invalidated entries, ordered by their end,
+// given the number of entries between checkpoints.
+// Ranges either nest or do not overlap, as the parent of an invalidated block is never itself invalidated.
+func (c *checkpointIndex) invalidatedRanges(frequency entrydb.EntryIdx) []invalidatedRange {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ranges := make([]invalidatedRange, 0, len(c.invalidations))
+	for _, n := range c.invalidations {
+		ranges = append(ranges, c.invalidatedRange(n, frequency))
+	}
+	return ranges
+}
+
+// truncate removes the checkpoints with the given number and after.
+func (c *checkpointIndex) truncate(n entrydb.EntryIdx) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkpoints = c.checkpoints[:max(min(n-c.first, entrydb.EntryIdx(len(c.checkpoints))), 0)]
+	count := len(c.invalidations)
+	for count > 0 && c.invalidations[count-1] >= n {
+		count--
+	}
+	c.invalidations = c.invalidations[:count]
+}
+
+// prune removes the checkpoints before the given number.
+func (c *checkpointIndex) prune(n entrydb.EntryIdx) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n <= c.first {
+		return
+	}
+	count := 0
+	for count < len(c.invalidations) && c.invalidations[count] < n {
+		count++
+	}
+	c.invalidations = c.invalidations[count:]
+	if n >= c.first+entrydb.EntryIdx(len(c.checkpoints)) {
+		c.first = n
+		c.checkpoints = nil
+		return
+	}
+	// Copy the remaining checkpoints, such that the memory of the pruned ones is released.
+	c.checkpoints = append([]searchCheckpoint(nil), c.checkpoints[n-c.first:]...)
+	c.first = n
+}
+
+// skipInvalidated returns the index of the first entry at or after the given index that was not invalidated.
+func (db *DB) skipInvalidated(idx entrydb.EntryIdx) entrydb.EntryIdx {
+	if db.checkpoints == nil {
+		return idx
+	}
+	return db.checkpoints.skip(idx, db.checkpointFrequency)
+}
+
+// skipInvalidatedBack returns the index of the last entry at or before the given index that was not invalidated,
+// or a negative index if there is none.
+func (db *DB) skipInvalidatedBack(idx entrydb.EntryIdx) entrydb.EntryIdx {
+	if db.checkpoints == nil {
+		return idx
+	}
+	return db.checkpoints.skipBack(idx, db.checkpointFrequency)
+}
+
+// syncCheckpoints makes the checkpoint index consistent with the entries of the DB:
+// it removes checkpoints that are no longer in the DB, and reads any missing checkpoints.
+func (db *DB) syncCheckpoints() error {
+	if db.checkpoints == nil {
+		return nil
+	}
+	var n entrydb.EntryIdx
+	if db.lastEntryIdx() >= 0 {
+		n = db.lastEntryIdx()/db.checkpointFrequency + 1
+	}
+	db.checkpoints.truncate(n)
+	db.checkpoints.prune(db.firstCheckpoint())
+	for i := max(db.checkpoints.end(), db.firstCheckpoint()); i < n; i++ {
+		entry, err := db.store.Read(i * db.checkpointFrequency)
+		if err != nil {
+			return fmt.Errorf("failed to read search checkpoint %v: %w", i, err)
+		}
+		checkpoint, err := newSearchCheckpointFromEntry(entry)
+		if err != nil {
+			return fmt.Errorf("failed to decode search checkpoint %v: %w", i, err)
+		}
+		db.checkpoints.add(i, checkpoint)
+	}
+	return nil
+}
+
+// updateCheckpoints adds the search checkpoints among the given entries, that were appended to the DB, to the index.
+func (db *DB) updateCheckpoints(start entrydb.EntryIdx, entries []entrydb.Entry) {
+	if db.checkpoints == nil {
+		return
+	}
+	for i, entry := range entries {
+		idx := start + entrydb.EntryIdx(i)
+		if idx%db.checkpointFrequency != 0 {
+			continue
+		}
+		n := idx / db.checkpointFrequency
+		if n != db.checkpoints.end() {
+			// A checkpoint failed to be indexed, so the later checkpoints are read from the store.
+			continue
+		}
+		checkpoint, err := newSearchCheckpointFromEntry(entry)
+		if err != nil {
+			db.log.Warn("Failed to index search checkpoint", "index", idx, "err", err)
+			continue
+		}
+		db.checkpoints.add(n, checkpoint)
+	}
+}
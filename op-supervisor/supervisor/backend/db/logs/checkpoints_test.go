@@ -0,0 +1,146 @@
+package logs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+func TestCheckpointIndex(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: 16}
+	// addBlocks adds the given blocks, with 3 logs each
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			if i > 0 {
+				parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+				for j := 0; j < 3; j++ {
+					require.NoError(t, db.AddLog(createHash(100*i+j), parent, uint32(j), nil))
+				}
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+	}
+	open := func(t *testing.T, path string) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.SegmentConfig{Size: 50}, DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+	// requireIndexed checks that the index holds exactly the search checkpoints that were not pruned
+	requireIndexed := func(t *testing.T, db *DB) {
+		first := db.firstCheckpoint()
+		var n entrydb.EntryIdx
+		if db.lastEntryIdx() >= 0 {
+			n = db.lastEntryIdx()/db.checkpointFrequency + 1
+		}
+		require.Equal(t, first, db.checkpoints.first)
+		require.Len(t, db.checkpoints.checkpoints, int(n-first))
+		for i := first; i < n; i++ {
+			entry, err := db.store.Read(i * db.checkpointFrequency)
+			require.NoError(t, err)
+			expected, err := newSearchCheckpointFromEntry(entry)
+			require.NoError(t, err)
+			actual, ok := db.checkpoints.get(i)
+			require.True(t, ok)
+			require.Equalf(t, expected, actual, "checkpoint %d", i)
+		}
+	}
+	requireBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)})
+			require.NoError(t, err)
+			if i > 0 {
+				requireContains(t, db, uint64(i), 2, createHash(100*i+2))
+			}
+		}
+	}
+
+	t.Run("Writes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		requireIndexed(t, db)
+		addBlocks(t, db, 0, 50)
+		requireIndexed(t, db)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireIndexed(t, db)
+		addBlocks(t, db, 51, 60)
+		requireIndexed(t, db)
+		requireBlocks(t, db, 0, 60)
+	})
+
+	t.Run("Rewind", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		defer db.Close()
+		addBlocks(t, db, 0, 50)
+		require.NoError(t, db.Rewind(20))
+		requireIndexed(t, db)
+		addBlocks(t, db, 21, 30)
+		requireIndexed(t, db)
+		requireBlocks(t, db, 0, 30)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 100)
+		next, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(80), Number: 80})
+		require.NoError(t, err)
+		require.NoError(t, db.Prune(next))
+		require.Positive(t, db.firstCheckpoint(), "should have pruned entries")
+		requireIndexed(t, db)
+		requireBlocks(t, db, 80, 100)
+		_, err = db.FindSealedBlock(eth.BlockID{Hash: createHash(10), Number: 10})
+		require.ErrorIs(t, err, ErrSkipped)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireIndexed(t, db)
+		requireBlocks(t, db, 80, 100)
+	})
+
+	t.Run("SearchInMemory", func(t *testing.T) {
+		store := &countingEntryStore{}
+		db, err := NewFromEntryStore(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, store, false, opts)
+		require.NoError(t, err)
+		addBlocks(t, db, 0, 50)
+		snapshot := db.Snapshot()
+		addBlocks(t, db, 51, 60)
+
+		store.reads = 0
+		for i := 0; i <= 60; i++ {
+			checkpointIdx, err := db.searchCheckpoint(uint64(i), 1)
+			require.NoError(t, err)
+			checkpoint, err := newSearchCheckpointFromEntry(store.entries[checkpointIdx])
+			require.NoError(t, err)
+			require.LessOrEqual(t, checkpoint.blockNum, uint64(i))
+			_, err = db.searchCheckpointByTimestamp(500 + uint64(i))
+			require.NoError(t, err)
+		}
+		for i := 0; i <= 50; i++ {
+			_, err := snapshot.db.searchCheckpoint(uint64(i), 1)
+			require.NoError(t, err)
+		}
+		require.Zero(t, store.reads, "should not read any entries")
+	})
+}
+
+// countingEntryStore counts the entries that are read from it.
+type countingEntryStore struct {
+	stubEntryStore
+	reads int
+}
+
+func (s *countingEntryStore) Read(idx entrydb.EntryIdx) (entrydb.Entry, error) {
+	s.reads++
+	return s.stubEntryStore.Read(idx)
+}
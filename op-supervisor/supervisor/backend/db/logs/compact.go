@@ -0,0 +1,487 @@
+package logs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+const (
+	// compactSuffix is appended to the path of a database, for the database that a compaction writes.
+	compactSuffix = ".compact"
+	// compactDoneSuffix is appended to the path of the compacted database, for the marker file
+	// that is written once the compacted database is complete, and may replace the database.
+	compactDoneSuffix = ".done"
+	// compactSwapSuffix is appended to the path of the compacted database, for the marker file
+	// that is written once the files of the database are removed, and the compacted files are to be moved in place.
+	compactSwapSuffix = ".swap"
+	// indexRunSize is the size of a run of the index map as stored:
+	// <uint64 first entry index before compaction: 8 bytes><uint64 first entry index after compaction: 8 bytes>
+	// <uint64 number of entries: 8 bytes><checksum: 4 bytes>
+	indexRunSize = 28
+)
+
+// CompactOptions configure a compaction, see Compact.
+type CompactOptions struct {
+	// SearchCheckpointFrequency is the search checkpoint frequency of the compacted database,
+	// or zero to keep the frequency of the database.
+	// A higher frequency takes fewer search checkpoints, and less padding to align logs to them.
+	SearchCheckpointFrequency uint32
+}
+
+// Compact rewrites the database at the given path into a denser layout. The database must not be open.
+// The sealed blocks, logs, and the data that links them to L1, are written again on top of an empty database,
+// such that search checkpoints and padding are derived again, with the search checkpoint frequency of opts.
+// Padding and search checkpoints that are not needed by the new layout are removed,
+// as are the entries of invalidated blocks, and the records of the invalidated blocks: see InvalidatedBlocks.
+// If the first search checkpoint that was not pruned is within the logs of a block,
+// the compacted database starts at the seal of the next block.
+//
+// Entry indices change: an index map is stored next to the compacted database, that maps the entry indices
+// from before the last compaction to the entry indices after it, e.g. of stored heads, see DB.ResolveIndex.
+//
+// The compacted database is written next to the database, and then replaces it,
+// such that the database remains intact if the compaction is interrupted before it is complete.
+// If the replacement is interrupted, it is completed when the database is compacted or opened again.
+// Only databases of the file backend can be compacted.
+func Compact(logger log.Logger, m Metrics, path string, opts CompactOptions, walCfg entrydb.WALConfig, segCfg entrydb.SegmentConfig) error {
+	if err := buildCompacted(logger, m, path, opts, walCfg, segCfg); err != nil {
+		return err
+	}
+	return finishCompaction(logger, path)
+}
+
+// buildCompacted writes the compacted database next to the database at the given path, and marks it as complete.
+func buildCompacted(logger log.Logger, m Metrics, path string, opts CompactOptions, walCfg entrydb.WALConfig, segCfg entrydb.SegmentConfig) error {
+	// Complete any earlier compaction first, such that it is not lost when its output is discarded.
+	if err := finishCompaction(logger, path); err != nil {
+		return err
+	}
+	// Migrate the database first, to read the options it was created with from its header.
+	if err := migrate(logger, path, walCfg); err != nil {
+		return fmt.Errorf("failed to migrate DB: %w", err)
+	}
+	h, ok, err := entrydb.ReadHeader(path)
+	if err != nil {
+		return fmt.Errorf("failed to read header of DB: %w", err)
+	} else if !ok {
+		return fmt.Errorf("no DB to compact at %v", path)
+	}
+	srcOpts, err := optionsFromHeader(h)
+	if err != nil {
+		return fmt.Errorf("failed to read options of DB: %w", err)
+	}
+	dstOpts := srcOpts
+	if opts.SearchCheckpointFrequency != 0 {
+		dstOpts.SearchCheckpointFrequency = opts.SearchCheckpointFrequency
+	}
+	if err := dstOpts.Check(); err != nil {
+		return fmt.Errorf("invalid compaction options: %w", err)
+	}
+	src, err := NewFromFile(logger, m, path, false, srcOpts, walCfg, segCfg, DefaultReplayConfig())
+	if err != nil {
+		return fmt.Errorf("failed to open DB: %w", err)
+	}
+	dstPath := path + compactSuffix
+	// Discard the output of any earlier, interrupted, compaction.
+	if err := removeDBFiles(dstPath, true); err != nil {
+		return errors.Join(fmt.Errorf("failed to remove output of interrupted compaction: %w", err), src.Close())
+	}
+	dst, err := NewFromFile(logger, m, dstPath, false, dstOpts, walCfg, segCfg, DefaultReplayConfig())
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to create compacted DB: %w", err), src.Close())
+	}
+	runs, err := copyCompacted(src, dst)
+	if err != nil {
+		return errors.Join(err, dst.Close(), src.Close())
+	}
+	logger.Info("Compacted DB", "path", path, "entries", src.lastEntryContext.NextIndex(), "compacted", dst.lastEntryContext.NextIndex(),
+		"frequency", dstOpts.SearchCheckpointFrequency)
+	if err := dst.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close compacted DB: %w", err), src.Close())
+	}
+	if err := src.Close(); err != nil {
+		return fmt.Errorf("failed to close DB: %w", err)
+	}
+	if err := writeIndexMap(dstPath+".idxmap", runs); err != nil {
+		return err
+	}
+	if err := writeMarker(dstPath + compactDoneSuffix); err != nil {
+		return fmt.Errorf("failed to mark compacted DB as complete: %w", err)
+	}
+	return nil
+}
+
+// copyCompacted writes the data of src on top of the empty dst, and returns the runs of entries that were copied.
+// The entries of each block seal, log, derived-from, safe head and L1 block are the same in both databases,
+// as these are never interrupted by padding or search checkpoints.
+func copyCompacted(src, dst *DB) (*indexMap, error) {
+	runs := &indexMap{}
+	ctx := &dst.lastEntryContext
+	i := src.newIterator(src.firstCheckpoint() * src.checkpointFrequency)
+	i.current.need.Add(entrydb.FlagCanonicalHash)
+	// started is set by the first block that is sealed after the first search checkpoint.
+	started := false
+	// start is the index of the first entry of the current block seal, log, or other data.
+	var start entrydb.EntryIdx
+	var startType entrydb.EntryType
+	for {
+		typ, err := i.next()
+		if errors.Is(err, ErrFuture) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read DB: %w", err)
+		}
+		idx := i.NextIndex() - 1
+		switch typ {
+		case entrydb.TypePadding:
+			continue
+		case entrydb.TypeCanonicalHash, entrydb.TypeExecutingLink, entrydb.TypeExecutingCheck, entrydb.TypeHashExtension:
+		default:
+			start, startType = idx, typ
+		}
+		st := &i.current
+		if !st.hasCompleteBlock() || st.hasIncompleteLog() || st.need.Any(entrydb.FlagHashExtension) {
+			continue
+		}
+		next := ctx.NextIndex()
+		switch startType {
+		case entrydb.TypeSearchCheckpoint:
+			if started && st.blockNum == ctx.blockNum {
+				continue // a search checkpoint that repeats the last sealed block
+			}
+			if !started && st.logsSince > 0 {
+				continue // the first search checkpoint is within a block that was sealed before it
+			}
+			err = ctx.SealBlock(ctx.blockHash, eth.BlockID{Hash: st.blockHash, Number: st.blockNum}, st.timestamp)
+			started = true
+		case entrydb.TypeInitiatingEvent, entrydb.TypeDepositEvent:
+			if !started {
+				continue
+			}
+			parent := eth.BlockID{Hash: st.blockHash, Number: st.blockNum}
+			if st.deposit {
+				err = ctx.ApplyDepositEvent(parent, st.logsSince-1, st.logHash)
+			} else {
+				err = ctx.ApplyLog(parent, st.logsSince-1, st.logHash, slices.Clone(st.execMsgs))
+			}
+		case entrydb.TypeDerivedFrom:
+			if !started {
+				continue
+			}
+			err = ctx.ApplyDerivedFrom(eth.BlockID{Hash: st.blockHash, Number: st.blockNum}, st.derivedFrom)
+		case entrydb.TypeSafeHead, entrydb.TypeL1Block:
+			if !started {
+				continue
+			}
+			entry, readErr := src.store.Read(idx)
+			if readErr != nil {
+				return nil, fmt.Errorf("failed to read entry %d: %w", idx, readErr)
+			}
+			if typ == entrydb.TypeSafeHead {
+				var head safeHead
+				if head, err = newSafeHeadFromEntry(entry); err == nil {
+					err = ctx.ApplySafeHead(head.crossSafe, head.blockNum)
+				}
+			} else {
+				var b l1Block
+				if b, err = newL1BlockFromEntry(entry); err == nil {
+					err = ctx.ApplyL1Block(b.l1)
+				}
+			}
+		default:
+			continue // the record of an invalidated block, or an entry that does not start any data
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s entry %d: %w", startType, start, err)
+		}
+		// The copied entries may be preceded by padding and a search checkpoint, and if they end at a checkpoint interval,
+		// they are followed by the search checkpoint that repeats the last sealed block, and its canonical hash.
+		count := idx + 1 - start
+		end := ctx.NextIndex()
+		if repeat := 1 + ctx.hashEntryCount(); end%dst.checkpointFrequency == repeat && end-repeat-count >= next {
+			end -= repeat
+		}
+		if end-count < next {
+			return nil, fmt.Errorf("copy of %s entry %d takes fewer entries than the %d entries before", startType, start, count)
+		}
+		runs.add(start, end-count, count)
+		if len(ctx.out) >= migrationBatchSize {
+			if err := dst.flush(); err != nil {
+				return nil, fmt.Errorf("failed to write compacted entries: %w", err)
+			}
+		}
+	}
+	if err := dst.flush(); err != nil {
+		return nil, fmt.Errorf("failed to write compacted entries: %w", err)
+	}
+	// The end of the database, e.g. after the entries of an invalidated block, maps to the end of the compacted database.
+	runs.add(src.lastEntryContext.NextIndex(), ctx.NextIndex(), 0)
+	if started && (ctx.blockNum != src.lastEntryContext.blockNum || ctx.blockHash != src.lastEntryContext.blockHash ||
+		ctx.logsSince != src.lastEntryContext.logsSince) {
+		return nil, fmt.Errorf("compacted DB ends at block %d (%s) with %d logs, but the DB ends at block %d (%s) with %d logs",
+			ctx.blockNum, ctx.blockHash, ctx.logsSince, src.lastEntryContext.blockNum, src.lastEntryContext.blockHash, src.lastEntryContext.logsSince)
+	}
+	if err := dst.Verify(); err != nil {
+		return nil, fmt.Errorf("failed to verify compacted DB: %w", err)
+	}
+	return runs, nil
+}
+
+// finishCompaction replaces the database at the given path with the compacted database,
+// if the compaction of the database was complete, but the replacement was interrupted.
+// The files of the database are removed before the compacted files are moved in place,
+// which is marked in between, such that the replacement is completed if it is interrupted again.
+func finishCompaction(logger log.Logger, path string) error {
+	dstPath := path + compactSuffix
+	done, err := fileExists(dstPath + compactDoneSuffix)
+	if err != nil {
+		return err
+	}
+	if done {
+		logger.Info("Replacing DB with compacted DB", "path", path)
+		if err := removeDBFiles(path, false); err != nil {
+			return fmt.Errorf("failed to remove files of compacted DB: %w", err)
+		}
+		if err := os.Rename(dstPath+compactDoneSuffix, dstPath+compactSwapSuffix); err != nil {
+			return fmt.Errorf("failed to mark files of compacted DB as removed: %w", err)
+		}
+	}
+	swap, err := fileExists(dstPath + compactSwapSuffix)
+	if err != nil || !swap {
+		return err
+	}
+	files, err := dbFiles(dstPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err := os.Rename(name, path+strings.TrimPrefix(name, dstPath)); err != nil {
+			return fmt.Errorf("failed to move compacted file %v: %w", name, err)
+		}
+	}
+	// The header of the database is replaced last, as it holds the options of the compacted entries.
+	if err := os.Rename(dstPath, path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to replace DB with compacted DB: %w", err)
+	}
+	if err := removeFile(dstPath + ".lock"); err != nil {
+		return err
+	}
+	return removeFile(dstPath + compactSwapSuffix)
+}
+
+// dbFiles returns the files of the database at the given path, other than the header file and the lock file:
+// the segment files, the write-ahead log of the database before segments, and the files of its indices and state.
+func dbFiles(path string) ([]string, error) {
+	segments, err := filepath.Glob(path + ".seg-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segment files: %w", err)
+	}
+	files := segments
+	for _, suffix := range []string{".wal", ".bloom", ".deps", ".state", ".idxmap"} {
+		ok, err := fileExists(path + suffix)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			files = append(files, path+suffix)
+		}
+	}
+	return files, nil
+}
+
+// removeDBFiles removes the files of the database at the given path, and the header file if withHeader is set.
+func removeDBFiles(path string, withHeader bool) error {
+	files, err := dbFiles(path)
+	if err != nil {
+		return err
+	}
+	if withHeader {
+		files = append(files, path)
+	}
+	for _, name := range files {
+		if err := removeFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileExists(path string) (bool, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to stat %v: %w", path, err)
+	}
+	return true, nil
+}
+
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove %v: %w", path, err)
+	}
+	return nil
+}
+
+// writeMarker creates an empty file, and syncs it.
+func writeMarker(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	return f.Close()
+}
+
+// indexRun is a run of consecutive entries that a compaction moved to consecutive entries of the compacted database.
+type indexRun struct {
+	old   entrydb.EntryIdx
+	new   entrydb.EntryIdx
+	count entrydb.EntryIdx
+}
+
+func (r indexRun) encode() []byte {
+	data := make([]byte, 0, indexRunSize)
+	data = binary.LittleEndian.AppendUint64(data, uint64(r.old))
+	data = binary.LittleEndian.AppendUint64(data, uint64(r.new))
+	data = binary.LittleEndian.AppendUint64(data, uint64(r.count))
+	return binary.LittleEndian.AppendUint32(data, crc32.Checksum(data, bloomChecksumTable))
+}
+
+func decodeIndexRun(data []byte) (indexRun, error) {
+	if binary.LittleEndian.Uint32(data[24:28]) != crc32.Checksum(data[:24], bloomChecksumTable) {
+		return indexRun{}, fmt.Errorf("%w: index map checksum mismatch", ErrDataCorruption)
+	}
+	return indexRun{
+		old:   entrydb.EntryIdx(binary.LittleEndian.Uint64(data[0:8])),
+		new:   entrydb.EntryIdx(binary.LittleEndian.Uint64(data[8:16])),
+		count: entrydb.EntryIdx(binary.LittleEndian.Uint64(data[16:24])),
+	}, nil
+}
+
+// indexMap maps the entry indices of a database from before its last compaction to the entry indices after it.
+// It holds the runs of entries that were kept, ordered by entry index.
+// The last run holds no entries, and maps the end of the database before the compaction.
+// The index map is stored in a sidecar file next to the DB, which the compaction writes, and is read when the DB is opened.
+type indexMap struct {
+	runs []indexRun
+}
+
+// add adds a run of entries, that extends the last run if the entries follow it in both databases.
+func (m *indexMap) add(old, new, count entrydb.EntryIdx) {
+	if n := len(m.runs); n > 0 {
+		last := &m.runs[n-1]
+		if last.old+last.count == old && last.new+last.count == new {
+			last.count += count
+			return
+		}
+	}
+	m.runs = append(m.runs, indexRun{old: old, new: new, count: count})
+}
+
+// resolve returns the entry index that the given entry index from before the compaction resolves to.
+// An entry index is resolved as the position after the entry before it, as entry indices of the DB are positions,
+// e.g. the next index after a log: if that entry was kept, the index resolves to the index after the same entry,
+// and otherwise to the index after the last entry before it that was kept.
+// Returns false if the index is at or before the first run, or after the end of the database before the compaction.
+func (m *indexMap) resolve(idx entrydb.EntryIdx) (entrydb.EntryIdx, bool) {
+	i := sort.Search(len(m.runs), func(i int) bool { return m.runs[i].old >= idx }) - 1
+	if i < 0 {
+		return 0, false
+	}
+	r := m.runs[i]
+	if i == len(m.runs)-1 && idx > r.old+r.count {
+		return 0, false
+	}
+	if idx < r.old+r.count {
+		return r.new + idx - r.old, true
+	}
+	return r.new + r.count, true
+}
+
+// writeIndexMap writes the runs of the index map to a new file, that replaces the file at the given path.
+func writeIndexMap(path string, m *indexMap) error {
+	data := make([]byte, 0, len(m.runs)*indexRunSize)
+	for _, r := range m.runs {
+		data = append(data, r.encode()...)
+	}
+	tmp, err := os.OpenFile(path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create index map: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return errors.Join(fmt.Errorf("failed to write index map: %w", err), tmp.Close())
+	}
+	if err := tmp.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync index map: %w", err), tmp.Close())
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close index map: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace index map: %w", err)
+	}
+	return nil
+}
+
+// readIndexMap reads the index map at the given path, or returns nil if the database was not compacted.
+func readIndexMap(path string) (*indexMap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read index map at %v: %w", path, err)
+	}
+	if len(data)%indexRunSize != 0 {
+		return nil, fmt.Errorf("%w: index map at %v has incomplete run", ErrDataCorruption, path)
+	}
+	m := &indexMap{runs: make([]indexRun, 0, len(data)/indexRunSize)}
+	for offset := 0; offset < len(data); offset += indexRunSize {
+		r, err := decodeIndexRun(data[offset : offset+indexRunSize])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode index map at %v: %w", path, err)
+		}
+		m.runs = append(m.runs, r)
+	}
+	return m, nil
+}
+
+// ResolveIndex returns the entry index that the given entry index, from before the last compaction, resolves to,
+// e.g. of a stored head. Entry indices are resolved as positions after the entry before them,
+// such as the entry indices that NextIndex, FindSealedBlock and Contains return:
+// if that entry was kept, the index resolves to the position after the same entry,
+// and otherwise, e.g. for padding, to the position after the last entry before it that was kept.
+// Returns false if the DB was not compacted, or if the index is at or before the first sealed block that was kept,
+// or after the end of the DB before the compaction. See Compact.
+func (db *DB) ResolveIndex(idx entrydb.EntryIdx) (entrydb.EntryIdx, bool) {
+	if db.indexMap == nil {
+		return 0, false
+	}
+	return db.indexMap.resolve(idx)
+}
+
+// Compacted returns whether the DB has an index map, to resolve the entry indices from before its last compaction.
+// See ResolveIndex.
+func (db *DB) Compacted() bool {
+	return db.indexMap != nil
+}
+
+// RemoveIndexMap removes the index map of the database at the given path,
+// once no entry index from before its last compaction is left to resolve. See DB.ResolveIndex.
+func RemoveIndexMap(path string) error {
+	return removeFile(path + ".idxmap")
+}
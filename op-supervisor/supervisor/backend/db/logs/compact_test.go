@@ -0,0 +1,323 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+	supTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+func TestCompact(t *testing.T) {
+	open := func(t *testing.T, path string, opts Options) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts,
+			entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+	compact := func(t *testing.T, path string, opts CompactOptions) {
+		require.NoError(t, Compact(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, opts,
+			entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig()))
+	}
+	// addBlocks adds the given blocks, each with a deposit event and a log with up to 2 executing messages,
+	// such that logs of different sizes are padded to the search checkpoints.
+	// Each block is linked to L1, and some blocks register an L1 block or record a safe head.
+	addBlocks := func(t *testing.T, db *DB, hashes map[uint64]common.Hash, from, to int, seed int) {
+		for i := from; i <= to; i++ {
+			block := eth.BlockID{Hash: createHash(seed + i), Number: uint64(i)}
+			if i > 0 {
+				parent := eth.BlockID{Hash: hashes[uint64(i-1)], Number: uint64(i - 1)}
+				require.NoError(t, db.AddDepositEvent(createHash(seed+100*i), parent, 0))
+				var execMsgs []types.ExecutingMessage
+				for j := 0; j < i%3; j++ {
+					execMsgs = append(execMsgs, types.ExecutingMessage{Chain: 3, BlockNum: uint64(j), LogIdx: uint32(i), Timestamp: 400, Hash: createHash(seed + 1000*i + j)})
+				}
+				require.NoError(t, db.AddLogWithExecMsgs(createHash(seed+100*i+1), parent, 1, execMsgs))
+			}
+			require.NoError(t, db.SealBlock(hashes[uint64(i-1)], block, 500+uint64(i)))
+			hashes[uint64(i)] = block.Hash
+			require.NoError(t, db.AddDerivedFrom(block, eth.BlockID{Hash: createHash(seed + 7000 + i), Number: 100 + uint64(i)}))
+			if i%5 == 0 {
+				require.NoError(t, db.AddSafeHead(supTypes.Safe, block))
+			}
+			if i%7 == 0 {
+				require.NoError(t, db.AddL1Block(eth.BlockID{Hash: createHash(seed + 8000 + i), Number: 200 + uint64(i)}))
+			}
+		}
+	}
+	// indices holds the entry indices of the sealed blocks and logs of a DB, and the data read at these indices.
+	type indices struct {
+		blocks  map[uint64]entrydb.EntryIdx
+		logs    map[[2]uint64]entrydb.EntryIdx
+		safe    map[entrydb.EntryIdx]eth.BlockID
+		l1      map[entrydb.EntryIdx]eth.BlockID
+		derived map[uint64]eth.BlockID
+		next    entrydb.EntryIdx
+	}
+	readIndices := func(t *testing.T, db *DB, hashes map[uint64]common.Hash, to int) indices {
+		idx := indices{
+			blocks:  make(map[uint64]entrydb.EntryIdx),
+			logs:    make(map[[2]uint64]entrydb.EntryIdx),
+			safe:    make(map[entrydb.EntryIdx]eth.BlockID),
+			l1:      make(map[entrydb.EntryIdx]eth.BlockID),
+			derived: make(map[uint64]eth.BlockID),
+			next:    db.NextIndex(),
+		}
+		for i := 0; i <= to; i++ {
+			block := eth.BlockID{Hash: hashes[uint64(i)], Number: uint64(i)}
+			n, err := db.FindSealedBlock(block)
+			require.NoError(t, err)
+			idx.blocks[uint64(i)] = n
+			idx.derived[uint64(i)], err = db.DerivedFrom(block)
+			require.NoError(t, err)
+			if safe, err := db.SafeHeadAt(n, supTypes.Safe); err == nil {
+				idx.safe[n] = safe
+			}
+			if l1, err := db.L1BlockAt(n); err == nil {
+				idx.l1[n] = l1
+			}
+			if i == 0 {
+				continue
+			}
+			for logIdx := uint32(0); logIdx < 2; logIdx++ {
+				hash, err := db.Get(uint64(i), logIdx)
+				require.NoError(t, err)
+				contains := db.Contains
+				if logIdx == 0 {
+					contains = db.ContainsDeposit
+				}
+				n, err := contains(uint64(i), logIdx, hash)
+				require.NoError(t, err)
+				idx.logs[[2]uint64{uint64(i), uint64(logIdx)}] = n
+			}
+		}
+		return idx
+	}
+	// requireResolved checks that the data of the DB is the same as before the compaction,
+	// and that the entry indices from before the compaction resolve to the entry indices of the same data.
+	requireResolved := func(t *testing.T, db *DB, hashes map[uint64]common.Hash, to int, before indices) {
+		require.NoError(t, db.Verify())
+		after := readIndices(t, db, hashes, to)
+		require.Equal(t, before.derived, after.derived)
+		for num, n := range before.blocks {
+			// The index may be after a search checkpoint that repeats the block, which the compaction removes.
+			// It then resolves to a later index after the same block seal, before the logs of the next block.
+			resolved, ok := db.ResolveIndex(n)
+			require.True(t, ok)
+			require.GreaterOrEqual(t, resolved, after.blocks[num], "block %d", num)
+			end, ok := after.logs[[2]uint64{num + 1, 0}]
+			if !ok {
+				end = after.next
+			}
+			require.LessOrEqual(t, resolved, end, "block %d", num)
+		}
+		for key, n := range before.logs {
+			resolved, ok := db.ResolveIndex(n)
+			require.True(t, ok)
+			require.Equal(t, after.logs[key], resolved, "log %d of block %d", key[1], key[0])
+		}
+		for n, safe := range before.safe {
+			resolved, _ := db.ResolveIndex(n)
+			head, err := db.SafeHeadAt(resolved, supTypes.Safe)
+			require.NoError(t, err)
+			require.Equal(t, safe, head)
+		}
+		for n, l1 := range before.l1 {
+			resolved, _ := db.ResolveIndex(n)
+			b, err := db.L1BlockAt(resolved)
+			require.NoError(t, err)
+			require.Equal(t, l1, b)
+		}
+		resolved, ok := db.ResolveIndex(before.next)
+		require.True(t, ok)
+		require.Equal(t, after.next, resolved)
+		_, ok = db.ResolveIndex(before.next + 1)
+		require.False(t, ok)
+	}
+	// requireNoCompactionFiles checks that no files of the compaction remain next to the DB.
+	requireNoCompactionFiles := func(t *testing.T, path string) {
+		files, err := filepath.Glob(path + compactSuffix + "*")
+		require.NoError(t, err)
+		require.Empty(t, files)
+	}
+
+	for _, fullHashes := range []bool{false, true} {
+		opts := Options{SearchCheckpointFrequency: 16, FullHashes: fullHashes}
+		name := "TruncatedHashes"
+		if fullHashes {
+			name = "FullHashes"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Run("Compact", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "test.db")
+				db := open(t, path, opts)
+				hashes := make(map[uint64]common.Hash)
+				addBlocks(t, db, hashes, 0, 30, 0)
+				_, err := db.InvalidateBlock(eth.BlockID{Hash: hashes[20], Number: 20})
+				require.NoError(t, err)
+				// the link to L1 of the parent block is not kept when the block is invalidated
+				require.NoError(t, db.AddDerivedFrom(eth.BlockID{Hash: hashes[19], Number: 19}, eth.BlockID{Hash: createHash(7019), Number: 119}))
+				addBlocks(t, db, hashes, 20, 40, 50000)
+				before := readIndices(t, db, hashes, 40)
+				require.NoError(t, db.Close())
+
+				compact(t, path, CompactOptions{})
+				requireNoCompactionFiles(t, path)
+				db = open(t, path, opts)
+				defer db.Close()
+				require.Less(t, db.NextIndex(), before.next, "the entries of the invalidated blocks are removed")
+				requireResolved(t, db, hashes, 40, before)
+				blocks, err := db.InvalidatedBlocks()
+				require.NoError(t, err)
+				require.Empty(t, blocks)
+				require.Len(t, db.Dependents(3, 0, 23), 1)
+				require.Len(t, db.Dependents(3, 1, 23), 1)
+
+				// blocks are sealed on top of the compacted DB as before
+				addBlocks(t, db, hashes, 41, 45, 50000)
+				require.NoError(t, db.Verify())
+			})
+
+			t.Run("Frequency", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "test.db")
+				db := open(t, path, opts)
+				hashes := make(map[uint64]common.Hash)
+				addBlocks(t, db, hashes, 0, 40, 0)
+				before := readIndices(t, db, hashes, 40)
+				require.NoError(t, db.Close())
+
+				compact(t, path, CompactOptions{})
+				db = open(t, path, opts)
+				require.Equal(t, before.next, db.NextIndex(), "the layout is the same with the same options")
+				requireResolved(t, db, hashes, 40, before)
+				require.NoError(t, db.Close())
+
+				compact(t, path, CompactOptions{SearchCheckpointFrequency: 64})
+				db = open(t, path, opts)
+				defer db.Close()
+				require.EqualValues(t, 64, db.checkpointFrequency)
+				require.Less(t, db.NextIndex(), before.next, "fewer search checkpoints and less padding")
+				// the indices from before the last compaction are resolved, which match those before the first compaction
+				requireResolved(t, db, hashes, 40, before)
+			})
+
+			t.Run("Pruned", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "test.db")
+				segCfg := entrydb.SegmentConfig{Size: 64}
+				db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts,
+					entrydb.DefaultWALConfig(), segCfg, DefaultReplayConfig())
+				require.NoError(t, err)
+				hashes := make(map[uint64]common.Hash)
+				addBlocks(t, db, hashes, 0, 40, 0)
+				pruned, err := db.FindSealedBlock(eth.BlockID{Hash: hashes[20], Number: 20})
+				require.NoError(t, err)
+				require.NoError(t, db.Prune(pruned))
+				old, err := db.FindSealedBlock(eth.BlockID{Hash: hashes[30], Number: 30})
+				require.NoError(t, err)
+				require.NoError(t, db.Close())
+
+				require.NoError(t, Compact(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, CompactOptions{},
+					entrydb.DefaultWALConfig(), segCfg))
+				db = open(t, path, opts)
+				defer db.Close()
+				require.NoError(t, db.Verify())
+				// the compacted DB starts at the first block that was sealed after the first search checkpoint that was kept
+				_, err = db.FindSealedBlock(eth.BlockID{Hash: hashes[10], Number: 10})
+				require.ErrorIs(t, err, ErrSkipped)
+				n, err := db.FindSealedBlock(eth.BlockID{Hash: hashes[30], Number: 30})
+				require.NoError(t, err)
+				resolved, ok := db.ResolveIndex(old)
+				require.True(t, ok)
+				require.GreaterOrEqual(t, resolved, n)
+				requireContains(t, db, 40, 0, storedHash(createHash(4000), fullHashes))
+				_, ok = db.ResolveIndex(0)
+				require.False(t, ok)
+			})
+
+			t.Run("InterruptedReplace", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "test.db")
+				db := open(t, path, opts)
+				hashes := make(map[uint64]common.Hash)
+				addBlocks(t, db, hashes, 0, 20, 0)
+				before := readIndices(t, db, hashes, 20)
+				require.NoError(t, db.Close())
+
+				require.NoError(t, buildCompacted(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, CompactOptions{SearchCheckpointFrequency: 32},
+					entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig()))
+				// the files of the DB are removed, but the compacted files are not moved in place yet
+				require.NoError(t, removeDBFiles(path, false))
+				require.NoError(t, os.Rename(path+compactSuffix+compactDoneSuffix, path+compactSuffix+compactSwapSuffix))
+
+				// the replacement is completed when the DB is opened
+				db = open(t, path, opts)
+				defer db.Close()
+				requireNoCompactionFiles(t, path)
+				require.EqualValues(t, 32, db.checkpointFrequency)
+				requireResolved(t, db, hashes, 20, before)
+			})
+
+			t.Run("NotCompacted", func(t *testing.T) {
+				db := open(t, filepath.Join(t.TempDir(), "test.db"), opts)
+				defer db.Close()
+				addBlocks(t, db, make(map[uint64]common.Hash), 0, 3, 0)
+				_, ok := db.ResolveIndex(0)
+				require.False(t, ok)
+				require.Error(t, Compact(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, filepath.Join(t.TempDir(), "missing.db"),
+					CompactOptions{}, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig()))
+			})
+		})
+	}
+}
+
+func TestIndexMap(t *testing.T) {
+	m := &indexMap{}
+	m.add(10, 0, 3)
+	m.add(13, 3, 2) // extends the first run
+	m.add(20, 5, 4)
+	m.add(30, 9, 0)
+	require.Len(t, m.runs, 3)
+	for _, tc := range []struct {
+		old, new entrydb.EntryIdx
+		ok       bool
+	}{
+		{old: 9, ok: false},
+		{old: 10, ok: false},
+		{old: 11, new: 1, ok: true},
+		{old: 15, new: 5, ok: true},
+		{old: 16, new: 5, ok: true}, // after a removed entry, resolves to the index after the entries before it
+		{old: 20, new: 5, ok: true},
+		{old: 21, new: 6, ok: true},
+		{old: 24, new: 9, ok: true},
+		{old: 25, new: 9, ok: true},
+		{old: 30, new: 9, ok: true},
+		{old: 31, ok: false},
+	} {
+		n, ok := m.resolve(tc.old)
+		require.Equal(t, tc.ok, ok, "index %d", tc.old)
+		require.Equal(t, tc.new, n, "index %d", tc.old)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.idxmap")
+	read, err := readIndexMap(path)
+	require.NoError(t, err)
+	require.Nil(t, read)
+	require.NoError(t, writeIndexMap(path, m))
+	read, err = readIndexMap(path)
+	require.NoError(t, err)
+	require.Equal(t, m, read)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[indexRunSize+1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	_, err = readIndexMap(path)
+	require.ErrorIs(t, err, ErrDataCorruption)
+}
@@ -1,23 +1,51 @@
 package logs
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+	supTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
 const (
-	searchCheckpointFrequency    = 256
-	eventFlagHasExecutingMessage = byte(1)
+	defaultSearchCheckpointFrequency = 256
+	// minSearchCheckpointFrequency fits a search checkpoint, its canonical hash,
+	// and the initiating event, executing link and executing check of a log in a single checkpoint interval.
+	minSearchCheckpointFrequency = 5
+	// minFullHashesSearchCheckpointFrequency additionally fits the hash extensions
+	// of the canonical hash, the initiating event and the executing check.
+	minFullHashesSearchCheckpointFrequency = 8
+	eventFlagHasExecutingMessage           = byte(1)
+	// eventFlagHasMultipleExecutingMessages is set if the initiating event is followed by more than one executing message.
+	// The number of executing messages is then stored in the initiating event.
+	eventFlagHasMultipleExecutingMessages = byte(2)
+	// maxExecMsgsPerLog is the maximum number of executing messages that can follow a single initiating event.
+	maxExecMsgsPerLog = 255
+
+	// headerVersion is the version of the database format, stored in the header of the database file.
+	// Databases of earlier versions are upgraded when opened, by the migrations of each later version.
+	// Since version 2, the entries are stored in segment files next to the database file, which holds only the header.
+	headerVersion = byte(2)
+	// wideLogIdxHeaderVersion is the version of databases with wide log indices, see Options.WideLogIndices.
+	// Earlier versions reject these databases when they are opened, as they would decode executing links incorrectly.
+	// The version does not track the entry types that were added since headerVersion: earlier versions open
+	// databases without wide log indices, but fail with an unknown entry type on the first entry of a type they
+	// don't support.
+	wideLogIdxHeaderVersion = byte(3)
 )
 
 var (
@@ -33,19 +61,120 @@ var (
 	ErrFuture = errors.New("future data")
 	// ErrConflict happens when we know for sure that there is different canonical data
 	ErrConflict = errors.New("conflicting data")
+	// ErrTooManyLogs happens when a log is added to a block that already holds the maximum number of logs.
+	ErrTooManyLogs = errors.New("too many logs in block")
+	// ErrOverflow happens when a value does not fit in the encoding of an entry.
+	ErrOverflow = errors.New("value overflows entry encoding")
 )
 
+// Options configure the format of a new database.
+// An existing database retains the options it was created with.
+type Options struct {
+	// SearchCheckpointFrequency is the number of entries between search checkpoints.
+	// More entries between checkpoints reduce write amplification, at the cost of reading more entries per search.
+	SearchCheckpointFrequency uint32
+	// FullHashes stores full 32-byte hashes for canonical hashes, log hashes and executing checks,
+	// instead of only the first 20 bytes of each hash.
+	// The remaining bytes of each hash take an additional entry.
+	FullHashes bool
+	// WideLogIndices stores the log index of executing messages in 4 bytes, instead of 3 bytes,
+	// at the cost of limiting the block number of executing messages to 7 bytes.
+	// Databases with wide log indices are created with a later format version.
+	WideLogIndices bool
+	// MaxLogsPerBlock is the maximum number of logs that can be added to a block, or zero for the maximum
+	// that the format supports. The limit is not part of the format, and applies to existing databases as well.
+	MaxLogsPerBlock uint32
+}
+
+func DefaultOptions() Options {
+	return Options{
+		SearchCheckpointFrequency: defaultSearchCheckpointFrequency,
+	}
+}
+
+func (o Options) Check() error {
+	minFrequency := uint32(minSearchCheckpointFrequency)
+	if o.FullHashes {
+		minFrequency = minFullHashesSearchCheckpointFrequency
+	}
+	if o.SearchCheckpointFrequency < minFrequency {
+		return fmt.Errorf("search checkpoint frequency %d is less than the minimum of %d",
+			o.SearchCheckpointFrequency, minFrequency)
+	}
+	return nil
+}
+
+// header encodes the options into the header of the database file
+// <marker: 1 byte><version: 1 byte><uint32 search checkpoint frequency: 4 bytes><full hashes: 1 byte>
+// The version determines whether log indices are wide; MaxLogsPerBlock is not encoded.
+func (o Options) header() entrydb.Header {
+	var h entrydb.Header
+	h[1] = headerVersion
+	if o.WideLogIndices {
+		h[1] = wideLogIdxHeaderVersion
+	}
+	binary.LittleEndian.PutUint32(h[2:6], o.SearchCheckpointFrequency)
+	if o.FullHashes {
+		h[6] = 1
+	}
+	return h
+}
+
+func optionsFromHeader(h entrydb.Header) (Options, error) {
+	if h[1] != headerVersion && h[1] != wideLogIdxHeaderVersion {
+		return Options{}, fmt.Errorf("%w: unsupported header version %d", ErrDataCorruption, h[1])
+	}
+	if h[6] > 1 {
+		return Options{}, fmt.Errorf("%w: invalid full hashes flag %d", ErrDataCorruption, h[6])
+	}
+	opts := Options{
+		SearchCheckpointFrequency: binary.LittleEndian.Uint32(h[2:6]),
+		FullHashes:                h[6] == 1,
+		WideLogIndices:            h[1] == wideLogIdxHeaderVersion,
+	}
+	if err := opts.Check(); err != nil {
+		return Options{}, fmt.Errorf("%w: invalid header: %w", ErrDataCorruption, err)
+	}
+	return opts, nil
+}
+
 type Metrics interface {
 	RecordDBEntryCount(count int64)
 	RecordDBSearchEntriesRead(count int64)
+
+	// RecordDBEntriesWritten records the number of entries of the given type that were written.
+	RecordDBEntriesWritten(entryType string, count int64)
+	// RecordDBPaddingRatio records the fraction of the entries written since the DB was opened that are padding.
+	RecordDBPaddingRatio(ratio float64)
+	// RecordDBSearchCheckpoint records a written search checkpoint, that either seals a new block,
+	// or repeats the last sealed block at the start of a checkpoint interval.
+	RecordDBSearchCheckpoint(repeat bool)
+	RecordDBBlockSealed()
+	// RecordDBFlush records the time it took to append the entries of a write to the store.
+	RecordDBFlush(duration time.Duration)
+	RecordDBFileSize(size int64)
+	// RecordDBReplayProgress records the progress of the replay of the entries while the DB is opened.
+	RecordDBReplayProgress(done int64, total int64)
+	// RecordDBOpen records the time it took to open the DB, and whether the state after the last entry
+	// was restored from the file written when the DB was last closed, rather than replayed.
+	RecordDBOpen(duration time.Duration, restored bool)
+}
+
+// fileSizer is implemented by stores that can report the size of the files that hold the entries.
+type fileSizer interface {
+	FileSize() int64
 }
 
 type EntryStore interface {
 	Size() int64
 	LastEntryIdx() entrydb.EntryIdx
+	// FirstEntryIdx returns the index of the first entry that was not pruned.
+	FirstEntryIdx() entrydb.EntryIdx
 	Read(idx entrydb.EntryIdx) (entrydb.Entry, error)
 	Append(entries ...entrydb.Entry) error
 	Truncate(idx entrydb.EntryIdx) error
+	// Prune removes entries before idx, to the extent that the store supports.
+	Prune(idx entrydb.EntryIdx) error
 	Close() error
 }
 
@@ -62,29 +191,164 @@ type DB struct {
 	store  EntryStore
 	rwLock sync.RWMutex
 
+	checkpointFrequency entrydb.EntryIdx
+	fullHashes          bool
+	wideLogIndices      bool
+	// maxLogsPerBlock is the maximum number of logs that can be added to a block, see Options.MaxLogsPerBlock.
+	maxLogsPerBlock uint32
+
 	lastEntryContext logContext
+
+	// blooms holds bloom filters of the log hashes between search checkpoints, or nil if there are none.
+	blooms *bloomIndex
+	// checkpoints holds the search checkpoints in memory, or nil if they are read from the store.
+	checkpoints *checkpointIndex
+	// changes is the feed of the changes to the DB, see SubscribeChanges.
+	changes event.Feed
+
+	// dependents holds the logs with executing messages by the initiating log they reference, or nil if there are none.
+	dependents *dependentsIndex
+
+	// replayCfg configures the replay of the entries when the DB is opened.
+	replayCfg ReplayConfig
+	// tailStatePath is the path of the file that the state after the last entry is stored to when the DB is closed,
+	// or empty if the state is not stored.
+	tailStatePath string
+
+	// indexMap maps the entry indices from before the last compaction of the DB, or is nil if the DB was not compacted.
+	indexMap *indexMap
+
+	// tail is a copy of the state after the last write, for new snapshots to read from.
+	tail atomic.Pointer[logContext]
+	// rewinds is the number of times that entries were removed, which makes earlier snapshots stale.
+	rewinds atomic.Uint64
+
+	// entriesWritten and paddingWritten count the entries, and the padding entries among them,
+	// that were written since the DB was opened, for the padding ratio metric.
+	entriesWritten int64
+	paddingWritten int64
 }
 
-func NewFromFile(logger log.Logger, m Metrics, path string, trimToLastSealed bool) (*DB, error) {
-	store, err := entrydb.NewEntryDB(logger, path)
+// NewFromFile opens the database at the given path, or creates a new database with the given options.
+// An existing database is opened with the options it was created with.
+// A database of an earlier version is migrated to the current version before it is opened,
+// and the replacement of a database by its compacted database is completed, if it was interrupted, see Compact.
+// Entries are written through a write-ahead log, configured by walCfg, such that each write is atomic.
+// Trailing entries that leave a block seal or log incomplete, or that are corrupted,
+// e.g. of a write that the write-ahead log could not recover, are trimmed when the database is opened.
+// Entries are stored in segment files, configured by segCfg, such that old entries can be pruned, see Prune,
+// and older segments can be compressed.
+// Entries are replayed as configured by replayCfg, to build the state and indices of the DB;
+// the state after the last entry is stored when the DB is closed, such that it is not replayed when opened again.
+func NewFromFile(logger log.Logger, m Metrics, path string, trimToLastSealed bool, opts Options, walCfg entrydb.WALConfig, segCfg entrydb.SegmentConfig, replayCfg ReplayConfig) (*DB, error) {
+	return NewFromBackend(logger, m, entrydb.BackendFile, path, trimToLastSealed, opts, walCfg, segCfg, replayCfg)
+}
+
+// NewFromBackend opens the database at the given path, with the entries stored in the given backend,
+// as NewFromFile does for the file backend. Only databases of the file backend are migrated.
+// The bloom filters, dependents and state of a database of the Pebble backend are stored next to the Pebble store.
+// A database of the memory backend is always new, and holds no bloom filters or dependents.
+func NewFromBackend(logger log.Logger, m Metrics, backend entrydb.Backend, path string, trimToLastSealed bool, opts Options, walCfg entrydb.WALConfig, segCfg entrydb.SegmentConfig, replayCfg ReplayConfig) (*DB, error) {
+	start := time.Now()
+	if err := opts.Check(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	if backend == entrydb.BackendFile {
+		if err := finishCompaction(logger, path); err != nil {
+			return nil, fmt.Errorf("failed to complete compaction of DB: %w", err)
+		}
+		if err := migrate(logger, path, walCfg); err != nil {
+			return nil, fmt.Errorf("failed to migrate DB: %w", err)
+		}
+	}
+	store, err := entrydb.OpenStore(logger, backend, path, opts.header(), segCfg, walCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open DB: %w", err)
 	}
-	return NewFromEntryStore(logger, m, store, trimToLastSealed)
+	h, _ := store.Header()
+	stored, err := optionsFromHeader(h)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to read options of DB: %w", err), store.Close())
+	}
+	stored.MaxLogsPerBlock = opts.MaxLogsPerBlock
+	if stored != opts {
+		logger.Warn("Using options of existing DB", "requested", opts, "existing", stored)
+	}
+	opts = stored
+	db := newDB(logger, m, store, opts)
+	if backend == entrydb.BackendMemory {
+		if err := db.init(trimToLastSealed); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to init database: %w", err), store.Close())
+		}
+		m.RecordDBOpen(time.Since(start), false)
+		return db, nil
+	}
+	db.replayCfg = replayCfg
+	indexMap, err := readIndexMap(path + ".idxmap")
+	if err != nil {
+		return nil, errors.Join(err, store.Close())
+	}
+	db.indexMap = indexMap
+	db.tailStatePath = path + ".state"
+	state := db.restoreTailState(trimToLastSealed)
+	if state == nil {
+		if err := db.init(trimToLastSealed); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to init database: %w", err), store.Close())
+		}
+	}
+	blooms, err := openBloomIndex(logger, path+".bloom")
+	if err != nil {
+		return nil, errors.Join(err, store.Close())
+	}
+	db.blooms = blooms
+	if !db.restoreBlooms(state) {
+		if err := db.syncBlooms(); err != nil {
+			db.tailStatePath = "" // the state is not to be stored if the DB failed to open
+			return nil, errors.Join(fmt.Errorf("failed to build bloom filters: %w", err), db.Close())
+		}
+	}
+	dependents, err := openDependentsIndex(logger, path+".deps")
+	if err != nil {
+		db.tailStatePath = ""
+		return nil, errors.Join(err, db.Close())
+	}
+	db.dependents = dependents
+	if err := db.syncDependents(); err != nil {
+		db.tailStatePath = ""
+		return nil, errors.Join(fmt.Errorf("failed to index dependents: %w", err), db.Close())
+	}
+	m.RecordDBOpen(time.Since(start), state != nil)
+	return db, nil
 }
 
-func NewFromEntryStore(logger log.Logger, m Metrics, store EntryStore, trimToLastSealed bool) (*DB, error) {
-	db := &DB{
-		log:   logger,
-		m:     m,
-		store: store,
+func NewFromEntryStore(logger log.Logger, m Metrics, store EntryStore, trimToLastSealed bool, opts Options) (*DB, error) {
+	if err := opts.Check(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
 	}
+	db := newDB(logger, m, store, opts)
 	if err := db.init(trimToLastSealed); err != nil {
 		return nil, fmt.Errorf("failed to init database: %w", err)
 	}
 	return db, nil
 }
 
+func newDB(logger log.Logger, m Metrics, store EntryStore, opts Options) *DB {
+	db := &DB{
+		log:                 logger,
+		m:                   m,
+		store:               store,
+		checkpointFrequency: entrydb.EntryIdx(opts.SearchCheckpointFrequency),
+		fullHashes:          opts.FullHashes,
+		wideLogIndices:      opts.WideLogIndices,
+		maxLogsPerBlock:     opts.MaxLogsPerBlock,
+		checkpoints:         &checkpointIndex{},
+	}
+	if db.maxLogsPerBlock == 0 {
+		db.maxLogsPerBlock = math.MaxUint32
+	}
+	return db
+}
+
 func (db *DB) lastEntryIdx() entrydb.EntryIdx {
 	return db.store.LastEntryIdx()
 }
@@ -102,51 +366,140 @@ func (db *DB) init(trimToLastSealed bool) error {
 		// This will infer into a checkpoint (half of the block seal here)
 		// and is then followed up with canonical-hash entry of genesis.
 		db.lastEntryContext = logContext{
-			nextEntryIndex: 0,
-			blockHash:      types.TruncatedHash{},
-			blockNum:       0,
-			timestamp:      0,
-			logsSince:      0,
-			logHash:        types.TruncatedHash{},
-			execMsg:        nil,
-			out:            nil,
+			checkpointFrequency: db.checkpointFrequency,
+			fullHashes:          db.fullHashes,
+			wideLogIndices:      db.wideLogIndices,
+			nextEntryIndex:      0,
+			blockHash:           common.Hash{},
+			blockNum:            0,
+			timestamp:           0,
+			logsSince:           0,
+			logHash:             common.Hash{},
+			execMsgs:            nil,
+			out:                 nil,
 		}
-		return nil
+		db.publishTail()
+		if err := db.syncCheckpoints(); err != nil {
+			return err
+		}
+		return db.syncBlooms()
 	}
 	// start at the last checkpoint,
 	// and then apply any remaining changes on top, to hydrate the state.
-	lastCheckpoint := (db.lastEntryIdx() / searchCheckpointFrequency) * searchCheckpointFrequency
-	i := db.newIterator(lastCheckpoint)
-	i.current.need.Add(entrydb.FlagCanonicalHash)
-	if err := i.End(); err != nil {
+	lastCheckpoint := (db.lastEntryIdx() / db.checkpointFrequency) * db.checkpointFrequency
+	state, consistent, err := db.replayTail(lastCheckpoint)
+	if err != nil {
 		return fmt.Errorf("failed to init from remaining trailing data: %w", err)
 	}
-	db.lastEntryContext = i.current
+	if consistent <= db.lastEntryIdx() {
+		// The last write did not complete, e.g. as the process stopped while the entries were flushed.
+		// Trim back to the last consistent entry, and init again, as this may remove the last checkpoint.
+		db.log.Warn("Truncating incomplete trailing entries", "prev", db.lastEntryIdx(), "new", consistent-1)
+		if err := db.store.Truncate(consistent - 1); err != nil {
+			return fmt.Errorf("failed to truncate incomplete trailing entries: %w", err)
+		}
+		return db.init(trimToLastSealed)
+	}
+	db.lastEntryContext = state
+	db.publishTail()
+	if err := db.syncCheckpoints(); err != nil {
+		return err
+	}
+	if err := db.syncDependents(); err != nil {
+		return err
+	}
+	return db.syncBlooms()
+}
+
+// replayTail replays the entries from the given search checkpoint up to the end of the DB.
+// It returns the state after the entries, and the index after the last entry at which the state is consistent:
+// with a complete block seal, and without an incomplete log or hash extension.
+// The flags of an initiating event tell how many executing message entries complete the log.
+// The index of the search checkpoint itself is consistent, as writes do not cross search checkpoints.
+// Trailing entries that do not match their checksum end the replay, as they were only partially written.
+// A corrupted entry that is followed by an intact entry was not partially written, and results in ErrDataCorruption.
+func (db *DB) replayTail(checkpoint entrydb.EntryIdx) (state logContext, consistent entrydb.EntryIdx, err error) {
+	i := db.newIterator(checkpoint)
+	i.current.need.Add(entrydb.FlagCanonicalHash)
+	consistent = checkpoint
+	for {
+		if _, err := i.next(); errors.Is(err, ErrFuture) {
+			break
+		} else if errors.Is(err, entrydb.ErrCorrupted) {
+			// the iterator does not advance past the entry it failed to read
+			if err := db.checkCorruptedTail(i.current.nextEntryIndex); err != nil {
+				return logContext{}, 0, err
+			}
+			db.log.Warn("Ignoring corrupted trailing entries", "err", err)
+			break
+		} else if err != nil {
+			return logContext{}, 0, err
+		}
+		if i.current.hasCompleteBlock() && !i.current.hasIncompleteLog() && !i.current.need.Any(entrydb.FlagHashExtension) {
+			consistent = i.NextIndex()
+		}
+	}
+	return i.current, consistent, nil
+}
+
+// checkCorruptedTail checks that the entries after the given corrupted entry are all corrupted too,
+// such that the corruption is limited to the final entries, as left by an incomplete write.
+func (db *DB) checkCorruptedTail(corrupted entrydb.EntryIdx) error {
+	for idx := corrupted + 1; idx <= db.lastEntryIdx(); idx++ {
+		_, err := db.store.Read(idx)
+		if err == nil {
+			return fmt.Errorf("%w: entry %d is corrupted, but entry %d after it is intact", ErrDataCorruption, corrupted, idx)
+		} else if !errors.Is(err, entrydb.ErrCorrupted) {
+			return fmt.Errorf("failed to read entry %d after corrupted entry %d: %w", idx, corrupted, err)
+		}
+	}
 	return nil
 }
 
 func (db *DB) trimToLastSealed() error {
+	i, err := db.lastSealedEntryIdx()
+	if err != nil {
+		return err
+	}
+	if i < db.lastEntryIdx() {
+		db.log.Warn("Truncating unexpected trailing entries", "prev", db.lastEntryIdx(), "new", i)
+		// trim such that the last entry is the end of the block seal we identified
+		return db.store.Truncate(i)
+	}
+	return nil
+}
+
+// lastSealedEntryIdx returns the index of the last entry of the last complete block seal,
+// or -1 if no block seal is complete.
+func (db *DB) lastSealedEntryIdx() (entrydb.EntryIdx, error) {
 	i := db.lastEntryIdx()
 	for ; i >= 0; i-- {
 		entry, err := db.store.Read(i)
 		if err != nil {
-			return fmt.Errorf("failed to read %v to check for trailing entries: %w", i, err)
+			return 0, fmt.Errorf("failed to read %v to check for trailing entries: %w", i, err)
 		}
-		if entry.Type() == entrydb.TypeCanonicalHash {
-			// only an executing hash, indicating a sealed block, is a valid point for restart
-			break
+		typ := entry.Type()
+		if typ == entrydb.TypeCanonicalHash || typ == entrydb.TypeInvalidated {
+			// only an executing hash, indicating a sealed block, is a valid point for restart,
+			// or an invalidated block, which follows the re-seal of its parent block
+			if !db.fullHashes {
+				break
+			}
+			// with full hashes, the block is only sealed once the hash extension is written
+			if i < db.lastEntryIdx() {
+				i++
+				break
+			}
 		}
 	}
-	if i < db.lastEntryIdx() {
-		db.log.Warn("Truncating unexpected trailing entries", "prev", db.lastEntryIdx(), "new", i)
-		// trim such that the last entry is the canonical-hash we identified
-		return db.store.Truncate(i)
-	}
-	return nil
+	return i, nil
 }
 
 func (db *DB) updateEntryCountMetric() {
 	db.m.RecordDBEntryCount(db.store.Size())
+	if s, ok := db.store.(fileSizer); ok {
+		db.m.RecordDBFileSize(s.FileSize())
+	}
 }
 
 func (db *DB) IteratorStartingAt(i entrydb.EntryIdx) (Iterator, error) {
@@ -182,6 +535,34 @@ func (db *DB) IteratorStartingAt(i entrydb.EntryIdx) (Iterator, error) {
 	return iter, nil
 }
 
+// IterateRange returns an iterator over the sealed blocks from fromBlock up to and including toBlock,
+// with the logs and executing messages of each block.
+// The logs of a block are added after the seal of its parent block,
+// so the parent of fromBlock must be known, unless fromBlock is 0.
+// returns ErrFuture if the parent of fromBlock is not known yet
+func (db *DB) IterateRange(fromBlock, toBlock uint64) (BlockIterator, error) {
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("invalid range, from block %d is after to block %d", fromBlock, toBlock)
+	}
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	if fromBlock == 0 {
+		// Block 0 has no logs, and is sealed without a parent block.
+		iter, err := db.newIteratorAt(0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find block 0: %w", err)
+		}
+		hash, _, _ := iter.SealedBlock()
+		first := &BlockLogs{Block: eth.BlockID{Hash: hash, Number: 0}, Timestamp: iter.current.timestamp}
+		return &blockIterator{iter: iter, first: first, next: 1, to: toBlock}, nil
+	}
+	iter, err := db.newIteratorAt(fromBlock-1, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find parent block %d: %w", fromBlock-1, err)
+	}
+	return &blockIterator{iter: iter, next: fromBlock, to: toBlock}, nil
+}
+
 // FindSealedBlock finds the requested block, to check if it exists,
 // returning the next index after it where things continue from.
 // returns ErrFuture if the block is too new to be able to tell
@@ -199,12 +580,105 @@ func (db *DB) FindSealedBlock(block eth.BlockID) (nextEntry entrydb.EntryIdx, er
 	if !ok {
 		panic("expected block")
 	}
-	if types.TruncateHash(block.Hash) != h {
+	if storedHash(block.Hash, db.fullHashes) != h {
 		return 0, fmt.Errorf("queried %s but got %s at number %d: %w", block.Hash, h, block.Number, ErrConflict)
 	}
 	return iter.NextIndex(), nil
 }
 
+// FindSealedBlockByTimestamp finds the last sealed block with a timestamp at or before the given timestamp,
+// and returns the block with its timestamp.
+// Unless the DB stores full hashes, only the first 20 bytes of the block hash are set.
+// returns ErrFuture if the timestamp is after the latest sealed block, as a later block may still be at or before it
+// returns ErrSkipped if the timestamp is before the first block in the DB
+func (db *DB) FindSealedBlockByTimestamp(timestamp uint64) (block eth.BlockID, blockTime uint64, err error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	checkpointIdx, err := db.searchCheckpointByTimestamp(timestamp)
+	if errors.Is(err, io.EOF) {
+		return eth.BlockID{}, 0, fmt.Errorf("no blocks known yet: %w", ErrFuture)
+	} else if err != nil {
+		return eth.BlockID{}, 0, fmt.Errorf("failed to search for timestamp %d: %w", timestamp, err)
+	}
+	iter := db.newIterator(checkpointIdx)
+	iter.current.need.Add(entrydb.FlagCanonicalHash)
+	defer func() {
+		db.m.RecordDBSearchEntriesRead(iter.entriesRead)
+	}()
+	// The search checkpoint after the interval, if any, is after the timestamp,
+	// so walk the blocks of the interval, up to the first block that is after the timestamp.
+	found := false
+	for {
+		if err := iter.NextBlock(); errors.Is(err, ErrFuture) {
+			break
+		} else if err != nil {
+			return eth.BlockID{}, 0, fmt.Errorf("failed to read next block: %w", err)
+		}
+		if iter.current.timestamp > timestamp {
+			break
+		}
+		h, num, _ := iter.SealedBlock()
+		block, blockTime, found = eth.BlockID{Hash: h, Number: num}, iter.current.timestamp, true
+	}
+	if !found {
+		return eth.BlockID{}, 0, fmt.Errorf("no sealed block at or before timestamp %d yet: %w", timestamp, ErrFuture)
+	}
+	if block.Number == db.lastEntryContext.blockNum && blockTime < timestamp {
+		return eth.BlockID{}, 0, fmt.Errorf("timestamp %d is after the latest sealed block %d at %d: %w", timestamp, block.Number, blockTime, ErrFuture)
+	}
+	return block, blockTime, nil
+}
+
+// SealedBlockAt returns the last block that was sealed before the entry at the given index, with its timestamp,
+// i.e. the block that a head at the given index points at.
+// Unless the DB stores full hashes, only the first 20 bytes of the block hash are set.
+// returns ErrFuture if the entry index is not known yet, or if no block was sealed before it
+// returns ErrSkipped if the block was sealed before the pruned entries
+func (db *DB) SealedBlockAt(entryIdx entrydb.EntryIdx) (block eth.BlockID, timestamp uint64, err error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+
+	if entryIdx > db.lastEntryContext.NextIndex() {
+		return eth.BlockID{}, 0, fmt.Errorf("entry %d is not known yet: %w", entryIdx, ErrFuture)
+	}
+	last := db.skipInvalidatedBack(entryIdx - 1)
+	if last < 0 {
+		return eth.BlockID{}, 0, fmt.Errorf("no block sealed before entry %d: %w", entryIdx, ErrFuture)
+	}
+	// Every interval starts with a search checkpoint, so the state can be rebuilt from the start of the interval.
+	checkpointIdx := (last / db.checkpointFrequency) * db.checkpointFrequency
+	if checkpointIdx < db.store.FirstEntryIdx() {
+		return eth.BlockID{}, 0, fmt.Errorf("entry %d is in the pruned entries: %w", entryIdx, ErrSkipped)
+	}
+	iter := db.newIterator(checkpointIdx)
+	iter.current.need.Add(entrydb.FlagCanonicalHash)
+	defer func() {
+		db.m.RecordDBSearchEntriesRead(iter.entriesRead)
+	}()
+	for iter.NextIndex() <= last {
+		if _, err := iter.next(); err != nil {
+			return eth.BlockID{}, 0, fmt.Errorf("failed to read up to entry %d: %w", entryIdx, err)
+		}
+	}
+	h, num, ok := iter.SealedBlock()
+	if !ok {
+		return eth.BlockID{}, 0, fmt.Errorf("no complete block sealed before entry %d: %w", entryIdx, ErrFuture)
+	}
+	return eth.BlockID{Hash: h, Number: num}, iter.current.timestamp, nil
+}
+
+// Dependents returns the logs with executing messages that reference the given initiating log,
+// of the chain with the given ID, in the order that they were added.
+// Logs of a DB that was not opened from a file are not indexed, so no logs are returned.
+func (db *DB) Dependents(chain uint32, blockNum uint64, logIdx uint32) []Dependent {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	if db.dependents == nil {
+		return nil
+	}
+	return db.dependents.get(dependentKey{chain: chain, blockNum: blockNum, logIdx: logIdx})
+}
+
 // LatestSealedBlockNum returns the block number of the block that was last sealed,
 // or ok=false if there is no sealed block (i.e. empty DB)
 func (db *DB) LatestSealedBlockNum() (n uint64, ok bool) {
@@ -220,9 +694,10 @@ func (db *DB) LatestSealedBlockNum() (n uint64, ok bool) {
 	return db.lastEntryContext.blockNum, true
 }
 
-// Get returns the truncated hash of the log at the specified blockNum (of the sealed block)
+// Get returns the stored hash of the log at the specified blockNum (of the sealed block)
 // and logIdx (of the log after the block), or an error if the log is not found.
-func (db *DB) Get(blockNum uint64, logIdx uint32) (types.TruncatedHash, error) {
+// Unless the DB stores full hashes, only the first 20 bytes of the hash are set.
+func (db *DB) Get(blockNum uint64, logIdx uint32) (common.Hash, error) {
 	db.rwLock.RLock()
 	defer db.rwLock.RUnlock()
 	hash, _, err := db.findLogInfo(blockNum, logIdx)
@@ -234,46 +709,81 @@ func (db *DB) Get(blockNum uint64, logIdx uint32) (types.TruncatedHash, error) {
 // If the log is determined to conflict with the canonical chain, then ErrConflict is returned.
 // logIdx is the index of the log in the array of all logs in the block.
 // This can be used to check the validity of cross-chain interop events.
-func (db *DB) Contains(blockNum uint64, logIdx uint32, logHash types.TruncatedHash) (entrydb.EntryIdx, error) {
+// Unless the DB stores full hashes, only the first 20 bytes of the logHash are compared.
+func (db *DB) Contains(blockNum uint64, logIdx uint32, logHash common.Hash) (entrydb.EntryIdx, error) {
 	db.rwLock.RLock()
 	defer db.rwLock.RUnlock()
+	iter, err := db.containsLog(blockNum, logIdx, logHash)
+	if err != nil {
+		return 0, err
+	}
+	return iter.NextIndex(), nil
+}
+
+// ContainsDeposit returns no error iff the specified logHash is recorded in the specified blockNum and logIdx,
+// and the log was emitted by a deposit transaction, see AddDepositEvent.
+// This can be used to check cross-chain interop events of which the initiating side is a deposit,
+// without consulting the transactions of the block.
+// If the log is recorded, but was not emitted by a deposit transaction, then ErrConflict is returned.
+// Other errors are those of Contains.
+func (db *DB) ContainsDeposit(blockNum uint64, logIdx uint32, logHash common.Hash) (entrydb.EntryIdx, error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	iter, err := db.containsLog(blockNum, logIdx, logHash)
+	if err != nil {
+		return 0, err
+	}
+	if !iter.IsDeposit() {
+		return 0, fmt.Errorf("log %d of block %d was not emitted by a deposit transaction: %w", logIdx, blockNum, ErrConflict)
+	}
+	return iter.NextIndex(), nil
+}
+
+// containsLog returns an iterator positioned right after the log, iff the specified logHash is recorded
+// in the specified blockNum and logIdx. See Contains for the errors.
+func (db *DB) containsLog(blockNum uint64, logIdx uint32, logHash common.Hash) (Iterator, error) {
 	db.log.Trace("Checking for log", "blockNum", blockNum, "logIdx", logIdx, "hash", logHash)
 
+	if !db.bloomMayContain(blockNum, logIdx, logHash) {
+		// The interval with the log does not contain the hash, whether the log exists or not.
+		return nil, fmt.Errorf("payload hash mismatch: %s is not in the search checkpoint interval of log %d of block %d: %w",
+			logHash, logIdx, blockNum, ErrConflict)
+	}
 	evtHash, iter, err := db.findLogInfo(blockNum, logIdx)
 	if err != nil {
-		return 0, err // may be ErrConflict if the block does not have as many logs
+		return nil, err // may be ErrConflict if the block does not have as many logs
 	}
 	db.log.Trace("Found initiatingEvent", "blockNum", blockNum, "logIdx", logIdx, "hash", evtHash)
 	// Found the requested block and log index, check if the hash matches
-	if evtHash != logHash {
-		return 0, fmt.Errorf("payload hash mismatch: expected %s, got %s", logHash, evtHash)
+	if evtHash != storedHash(logHash, db.fullHashes) {
+		return nil, fmt.Errorf("payload hash mismatch: expected %s, got %s", logHash, evtHash)
 	}
-	return iter.NextIndex(), nil
+	return iter, nil
 }
 
-func (db *DB) findLogInfo(blockNum uint64, logIdx uint32) (types.TruncatedHash, Iterator, error) {
+func (db *DB) findLogInfo(blockNum uint64, logIdx uint32) (common.Hash, Iterator, error) {
 	if blockNum == 0 {
-		return types.TruncatedHash{}, nil, ErrConflict // no logs in block 0
+		return common.Hash{}, nil, ErrConflict // no logs in block 0
 	}
 	// blockNum-1, such that we find a log that came after the parent num-1 was sealed.
 	// logIdx, such that all entries before logIdx can be skipped, but logIdx itself is still readable.
 	iter, err := db.newIteratorAt(blockNum-1, logIdx)
 	if errors.Is(err, ErrFuture) {
 		db.log.Trace("Could not find log yet", "blockNum", blockNum, "logIdx", logIdx)
-		return types.TruncatedHash{}, nil, err
+		return common.Hash{}, nil, err
 	} else if err != nil {
 		db.log.Error("Failed searching for log", "blockNum", blockNum, "logIdx", logIdx)
-		return types.TruncatedHash{}, nil, err
+		return common.Hash{}, nil, err
 	}
 	if err := iter.NextInitMsg(); err != nil {
-		return types.TruncatedHash{}, nil, fmt.Errorf("failed to read initiating message %d, on top of block %d: %w", logIdx, blockNum, err)
+		return common.Hash{}, nil, fmt.Errorf("failed to read initiating message %d, on top of block %d: %w", logIdx, blockNum, err)
 	}
 	if _, x, ok := iter.SealedBlock(); !ok {
 		panic("expected block")
 	} else if x < blockNum-1 {
 		panic(fmt.Errorf("bug in newIteratorAt, expected to have found parent block %d but got %d", blockNum-1, x))
 	} else if x > blockNum-1 {
-		return types.TruncatedHash{}, nil, fmt.Errorf("log does not exist, found next block already: %w", ErrConflict)
+		return common.Hash{}, nil, fmt.Errorf("log does not exist, found next block already: %w", ErrConflict)
 	}
 	logHash, x, ok := iter.InitMessage()
 	if !ok {
@@ -310,7 +820,7 @@ func (db *DB) newIteratorAt(blockNum uint64, logIndex uint32) (*iterator, error)
 	}()
 	// First walk up to the block that we are sealed up to (incl.)
 	for {
-		if _, n, _ := iter.SealedBlock(); n == blockNum { // we may already have it exactly
+		if _, n, ok := iter.SealedBlock(); ok && n == blockNum { // we may already have it exactly
 			break
 		}
 		if err := iter.NextBlock(); errors.Is(err, ErrFuture) {
@@ -372,7 +882,10 @@ func (db *DB) newIterator(index entrydb.EntryIdx) *iterator {
 	return &iterator{
 		db: db,
 		current: logContext{
-			nextEntryIndex: index,
+			checkpointFrequency: db.checkpointFrequency,
+			fullHashes:          db.fullHashes,
+			wideLogIndices:      db.wideLogIndices,
+			nextEntryIndex:      index,
 		},
 	}
 }
@@ -381,10 +894,13 @@ func (db *DB) newIterator(index entrydb.EntryIdx) *iterator {
 // to find the closest one with an equal or lower block number and equal or lower amount of seen logs.
 // Returns the index of the searchCheckpoint to begin reading from or an error.
 func (db *DB) searchCheckpoint(sealedBlockNum uint64, logsSince uint32) (entrydb.EntryIdx, error) {
-	n := (db.lastEntryIdx() / searchCheckpointFrequency) + 1
+	n := (db.lastEntryIdx() / db.checkpointFrequency) + 1
 	// Define: x is the array of known checkpoints
 	// Invariant: x[i] <= target, x[j] > target.
-	i, j := entrydb.EntryIdx(0), n
+	i, j := db.firstCheckpoint(), n
+	if i >= j {
+		return 0, fmt.Errorf("no search checkpoint after the pruned entries: %w", ErrSkipped)
+	}
 	for i+1 < j { // i is inclusive, j is exclusive.
 		// Get the checkpoint exactly in-between,
 		// bias towards a higher value if an even number of checkpoints.
@@ -394,7 +910,7 @@ func (db *DB) searchCheckpoint(sealedBlockNum uint64, logsSince uint32) (entrydb
 		//
 		// The following holds: i ≤ h < j
 		h := entrydb.EntryIdx((uint64(i) + uint64(j)) >> 1)
-		checkpoint, err := db.readSearchCheckpoint(h * searchCheckpointFrequency)
+		checkpoint, err := db.readSearchCheckpoint(h * db.checkpointFrequency)
 		if err != nil {
 			return 0, fmt.Errorf("failed to read entry %v: %w", h, err)
 		}
@@ -408,7 +924,7 @@ func (db *DB) searchCheckpoint(sealedBlockNum uint64, logsSince uint32) (entrydb
 	if i+1 != j {
 		panic("expected to have 1 checkpoint left")
 	}
-	result := i * searchCheckpointFrequency
+	result := db.skipInvalidated(i * db.checkpointFrequency)
 	checkpoint, err := db.readSearchCheckpoint(result)
 	if err != nil {
 		return 0, fmt.Errorf("failed to read final search checkpoint result: %w", err)
@@ -421,6 +937,48 @@ func (db *DB) searchCheckpoint(sealedBlockNum uint64, logsSince uint32) (entrydb
 	return result, nil
 }
 
+// firstCheckpoint returns the number of the first search checkpoint that was not pruned.
+func (db *DB) firstCheckpoint() entrydb.EntryIdx {
+	return (db.store.FirstEntryIdx() + db.checkpointFrequency - 1) / db.checkpointFrequency
+}
+
+// searchCheckpointByTimestamp performs a binary search of the searchCheckpoint entries
+// to find the last one with a timestamp at or before the given timestamp.
+// Returns the index of the searchCheckpoint to begin reading from or an error.
+func (db *DB) searchCheckpointByTimestamp(timestamp uint64) (entrydb.EntryIdx, error) {
+	n := (db.lastEntryIdx() / db.checkpointFrequency) + 1
+	// Invariant: x[i].timestamp <= target, x[j].timestamp > target.
+	i, j := db.firstCheckpoint(), n
+	if i >= j {
+		return 0, fmt.Errorf("no search checkpoint after the pruned entries: %w", ErrSkipped)
+	}
+	for i+1 < j { // i is inclusive, j is exclusive.
+		h := entrydb.EntryIdx((uint64(i) + uint64(j)) >> 1)
+		checkpoint, err := db.readSearchCheckpoint(h * db.checkpointFrequency)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read entry %v: %w", h, err)
+		}
+		if checkpoint.timestamp <= timestamp {
+			i = h
+		} else {
+			j = h
+		}
+	}
+	if i+1 != j {
+		panic("expected to have 1 checkpoint left")
+	}
+	result := db.skipInvalidated(i * db.checkpointFrequency)
+	checkpoint, err := db.readSearchCheckpoint(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read final search checkpoint result: %w", err)
+	}
+	if checkpoint.timestamp > timestamp {
+		return 0, fmt.Errorf("missing data, earliest search checkpoint is block %d at %d, cannot find something at or before %d: %w",
+			checkpoint.blockNum, checkpoint.timestamp, timestamp, ErrSkipped)
+	}
+	return result, nil
+}
+
 // debug util to log the last 10 entries of the chain
 func (db *DB) debugTip() {
 	for x := 0; x < 10; x++ {
@@ -440,14 +998,56 @@ func (db *DB) flush() error {
 		db.log.Trace("appending entry", "type", e.Type(), "entry", hexutil.Bytes(e[:]),
 			"next", int(db.lastEntryContext.nextEntryIndex)-len(db.lastEntryContext.out)+i)
 	}
+	start := time.Now()
 	if err := db.store.Append(db.lastEntryContext.out...); err != nil {
 		return fmt.Errorf("failed to append entries: %w", err)
 	}
+	db.m.RecordDBFlush(time.Since(start))
+	db.recordWrittenEntries(db.lastEntryContext.out)
+	first := db.lastEntryContext.nextEntryIndex - entrydb.EntryIdx(len(db.lastEntryContext.out))
+	db.updateCheckpoints(first, db.lastEntryContext.out)
+	db.updateBlooms(first, db.lastEntryContext.out)
+	db.updateDependents(first, db.lastEntryContext.out)
+	changes := db.writtenChanges(db.lastEntryContext.out)
 	db.lastEntryContext.out = db.lastEntryContext.out[:0]
+	db.publishTail()
 	db.updateEntryCountMetric()
+	db.notifyChanges(changes)
 	return nil
 }
 
+// recordWrittenEntries updates the metrics of the entries that were written on top of the last published state.
+func (db *DB) recordWrittenEntries(entries []entrydb.Entry) {
+	counts := make(map[entrydb.EntryType]int64)
+	prev := db.tail.Load()
+	// A search checkpoint that does not follow a sealed block, at the start of the DB, seals the first block.
+	blockNum, sealed := prev.blockNum, prev.nextEntryIndex > 0
+	for _, entry := range entries {
+		counts[entry.Type()]++
+		if entry.Type() != entrydb.TypeSearchCheckpoint {
+			continue
+		}
+		cp, err := newSearchCheckpointFromEntry(entry)
+		if err != nil {
+			continue
+		}
+		repeat := sealed && cp.blockNum == blockNum
+		db.m.RecordDBSearchCheckpoint(repeat)
+		if !repeat {
+			db.m.RecordDBBlockSealed()
+		}
+		blockNum, sealed = cp.blockNum, true
+	}
+	for typ, count := range counts {
+		db.m.RecordDBEntriesWritten(typ.String(), count)
+	}
+	db.entriesWritten += int64(len(entries))
+	db.paddingWritten += counts[entrydb.TypePadding]
+	if db.entriesWritten > 0 {
+		db.m.RecordDBPaddingRatio(float64(db.paddingWritten) / float64(db.entriesWritten))
+	}
+}
+
 func (db *DB) SealBlock(parentHash common.Hash, block eth.BlockID, timestamp uint64) error {
 	db.rwLock.Lock()
 	defer db.rwLock.Unlock()
@@ -459,19 +1059,359 @@ func (db *DB) SealBlock(parentHash common.Hash, block eth.BlockID, timestamp uin
 	return db.flush()
 }
 
-func (db *DB) AddLog(logHash types.TruncatedHash, parentBlock eth.BlockID, logIdx uint32, execMsg *types.ExecutingMessage) error {
+func (db *DB) AddLog(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsg *types.ExecutingMessage) error {
+	var execMsgs []types.ExecutingMessage
+	if execMsg != nil {
+		execMsgs = []types.ExecutingMessage{*execMsg}
+	}
+	return db.AddLogWithExecMsgs(logHash, parentBlock, logIdx, execMsgs)
+}
+
+// Batch is a set of updates to the DB, that are written together.
+type Batch interface {
+	SealBlock(parentHash common.Hash, block eth.BlockID, timestamp uint64) error
+	AddLog(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsg *types.ExecutingMessage) error
+	AddDepositEvent(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32) error
+}
+
+type batch struct {
+	db *DB
+}
+
+func (b batch) SealBlock(parentHash common.Hash, block eth.BlockID, timestamp uint64) error {
+	if err := b.db.lastEntryContext.SealBlock(parentHash, block, timestamp); err != nil {
+		return fmt.Errorf("failed to seal block: %w", err)
+	}
+	return nil
+}
+
+func (b batch) AddLog(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsg *types.ExecutingMessage) error {
+	var execMsgs []types.ExecutingMessage
+	if execMsg != nil {
+		execMsgs = []types.ExecutingMessage{*execMsg}
+	}
+	if err := b.db.checkLogLimit(logIdx); err != nil {
+		return err
+	}
+	if err := b.db.lastEntryContext.ApplyLog(parentBlock, logIdx, logHash, execMsgs); err != nil {
+		return fmt.Errorf("failed to apply log: %w", err)
+	}
+	return nil
+}
+
+func (b batch) AddDepositEvent(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32) error {
+	if err := b.db.checkLogLimit(logIdx); err != nil {
+		return err
+	}
+	if err := b.db.lastEntryContext.ApplyDepositEvent(parentBlock, logIdx, logHash); err != nil {
+		return fmt.Errorf("failed to apply deposit event: %w", err)
+	}
+	return nil
+}
+
+// checkLogLimit checks that a log with the given index does not exceed the maximum number of logs per block.
+// Logs are added in order, so this also keeps the count of logs since the last sealed block from overflowing.
+func (db *DB) checkLogLimit(logIdx uint32) error {
+	if logIdx >= db.maxLogsPerBlock {
+		return fmt.Errorf("%w: cannot add log %d, the maximum is %d logs per block", ErrTooManyLogs, logIdx, db.maxLogsPerBlock)
+	}
+	return nil
+}
+
+// WriteBatch applies the updates of fn, and writes the resulting entries with a single append.
+// With a write-ahead log, this is atomic: after a crash, either all or none of the updates are in the DB.
+// E.g. a block and its logs can be written as a batch.
+// If fn returns an error, none of the updates are written.
+func (db *DB) WriteBatch(fn func(b Batch) error) error {
 	db.rwLock.Lock()
 	defer db.rwLock.Unlock()
 
-	if err := db.lastEntryContext.ApplyLog(parentBlock, logIdx, logHash, execMsg); err != nil {
+	if err := fn(batch{db: db}); err != nil {
+		// drop the updates, by restoring the state of the entries that were written before
+		if initErr := db.init(false); initErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to restore state after failed batch: %w", initErr))
+		}
+		return err
+	}
+	db.log.Trace("Applied batch", "entries", len(db.lastEntryContext.out))
+	return db.flush()
+}
+
+// AddLogWithExecMsgs adds a log that carries any number of executing messages.
+// All entries of the log must fit between two search checkpoints,
+// which limits the number of executing messages by the search checkpoint frequency.
+func (db *DB) AddLogWithExecMsgs(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsgs []types.ExecutingMessage) error {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+
+	if err := db.checkLogLimit(logIdx); err != nil {
+		return err
+	}
+	if err := db.lastEntryContext.ApplyLog(parentBlock, logIdx, logHash, execMsgs); err != nil {
 		return fmt.Errorf("failed to apply log: %w", err)
 	}
-	db.log.Trace("Applied log", "parentBlock", parentBlock, "logIndex", logIdx, "logHash", logHash, "executing", execMsg != nil)
+	db.log.Trace("Applied log", "parentBlock", parentBlock, "logIndex", logIdx, "logHash", logHash, "executing", len(execMsgs))
+	return db.flush()
+}
+
+// AddDepositEvent adds a log that was emitted by a deposit transaction.
+// It is a log like any other, see AddLog, but it is recorded as a deposit, see ContainsDeposit.
+// Deposit transactions cannot execute messages, so the log does not carry any executing messages.
+func (db *DB) AddDepositEvent(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32) error {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+
+	if err := db.checkLogLimit(logIdx); err != nil {
+		return err
+	}
+	if err := db.lastEntryContext.ApplyDepositEvent(parentBlock, logIdx, logHash); err != nil {
+		return fmt.Errorf("failed to apply deposit event: %w", err)
+	}
+	db.log.Trace("Applied deposit event", "parentBlock", parentBlock, "logIndex", logIdx, "logHash", logHash)
+	return db.flush()
+}
+
+// AddDerivedFrom links the given block to the L1 block it was derived from.
+// The block must be the last sealed block. Only the first 15 bytes of the L1 block hash are stored.
+func (db *DB) AddDerivedFrom(block eth.BlockID, l1 eth.BlockID) error {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+
+	if err := db.lastEntryContext.ApplyDerivedFrom(block, l1); err != nil {
+		return fmt.Errorf("failed to apply derived from: %w", err)
+	}
+	db.log.Trace("Applied derived from", "block", block, "l1", l1)
+	return db.flush()
+}
+
+// DerivedFrom returns the L1 block that the given sealed block was derived from.
+// Only the first 15 bytes of the returned L1 block hash are set.
+// returns ErrFuture if the block, or the link to L1 of the block, is not known yet
+// returns ErrConflict if the known block does not match
+// returns ErrSkipped if the next block was sealed without linking the block to L1
+func (db *DB) DerivedFrom(block eth.BlockID) (l1 eth.BlockID, err error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	iter, err := db.newIteratorAt(block.Number, 0)
+	if errors.Is(err, ErrFuture) {
+		return eth.BlockID{}, fmt.Errorf("block %d is not known yet: %w", block.Number, ErrFuture)
+	} else if err != nil {
+		return eth.BlockID{}, fmt.Errorf("failed to find sealed block %d: %w", block.Number, err)
+	}
+	h, _, ok := iter.SealedBlock()
+	if !ok {
+		panic("expected block")
+	}
+	if storedHash(block.Hash, db.fullHashes) != h {
+		return eth.BlockID{}, fmt.Errorf("queried %s but got %s at number %d: %w", block.Hash, h, block.Number, ErrConflict)
+	}
+	// The link to L1 follows the seal of the block, before the seal of the next block.
+	for {
+		typ, err := iter.next()
+		if err != nil {
+			return eth.BlockID{}, fmt.Errorf("failed to find L1 block of block %d: %w", block.Number, err)
+		}
+		if typ == entrydb.TypeDerivedFrom {
+			l1, ok := iter.DerivedFrom()
+			if !ok {
+				panic("expected derived from")
+			}
+			return l1, nil
+		}
+		if iter.current.blockNum > block.Number {
+			return eth.BlockID{}, fmt.Errorf("block %d was not linked to L1: %w", block.Number, ErrSkipped)
+		}
+	}
+}
+
+// AddSafeHead records that the local-safe (types.Safe) or cross-safe (types.CrossSafe) head
+// advanced to the given block. The block must be sealed already.
+// returns ErrFuture if the block is not sealed yet
+// returns ErrConflict if the known block does not match
+func (db *DB) AddSafeHead(level supTypes.SafetyLevel, block eth.BlockID) error {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+
+	crossSafe, err := safeHeadLevel(level)
+	if err != nil {
+		return err
+	}
+	iter, err := db.newIteratorAt(block.Number, 0)
+	if errors.Is(err, ErrFuture) {
+		return fmt.Errorf("block %d is not known yet: %w", block.Number, ErrFuture)
+	} else if err != nil {
+		return fmt.Errorf("failed to find sealed block %d: %w", block.Number, err)
+	}
+	h, _, ok := iter.SealedBlock()
+	if !ok {
+		panic("expected block")
+	}
+	if storedHash(block.Hash, db.fullHashes) != h {
+		return fmt.Errorf("queried %s but got %s at number %d: %w", block.Hash, h, block.Number, ErrConflict)
+	}
+	if err := db.lastEntryContext.ApplySafeHead(crossSafe, block.Number); err != nil {
+		return fmt.Errorf("failed to apply safe head: %w", err)
+	}
+	db.log.Trace("Applied safe head", "level", level, "block", block)
+	return db.flush()
+}
+
+// SafeHeadAt returns the local-safe (types.Safe) or cross-safe (types.CrossSafe) head,
+// as last recorded before the entry at the given index.
+// This scans back entry by entry, so the cost grows with the distance to the last recorded head of the level.
+// returns ErrFuture if the entry index is not known yet, or if no head of the level was recorded before it
+func (db *DB) SafeHeadAt(entryIdx entrydb.EntryIdx, level supTypes.SafetyLevel) (eth.BlockID, error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+
+	crossSafe, err := safeHeadLevel(level)
+	if err != nil {
+		return eth.BlockID{}, err
+	}
+	if entryIdx > db.lastEntryContext.NextIndex() {
+		return eth.BlockID{}, fmt.Errorf("entry %d is not known yet: %w", entryIdx, ErrFuture)
+	}
+	for i := db.skipInvalidatedBack(entryIdx - 1); i >= 0; i = db.skipInvalidatedBack(i - 1) {
+		entry, err := db.store.Read(i)
+		if err != nil {
+			return eth.BlockID{}, fmt.Errorf("failed to read entry %d: %w", i, err)
+		}
+		if entry.Type() != entrydb.TypeSafeHead {
+			continue
+		}
+		head, err := newSafeHeadFromEntry(entry)
+		if err != nil {
+			return eth.BlockID{}, fmt.Errorf("failed to decode safe head at entry %d: %w", i, err)
+		}
+		if head.crossSafe != crossSafe {
+			continue
+		}
+		iter, err := db.newIteratorAt(head.blockNum, 0)
+		if err != nil {
+			return eth.BlockID{}, fmt.Errorf("failed to find sealed block %d of safe head at entry %d: %w", head.blockNum, i, err)
+		}
+		h, _, ok := iter.SealedBlock()
+		if !ok {
+			panic("expected block")
+		}
+		return eth.BlockID{Hash: h, Number: head.blockNum}, nil
+	}
+	return eth.BlockID{}, fmt.Errorf("no %s head recorded before entry %d: %w", level, entryIdx, ErrFuture)
+}
+
+// AddL1Block registers the given L1 block, as a kind of initiating event that messages can reference.
+// L1 blocks are registered in order: an L1 block with a number at or below that of the last registered L1 block
+// replaces the L1 blocks from that number onwards, after an L1 reorg.
+// The last block must be sealed. Only the first 15 bytes of the L1 block hash are stored.
+func (db *DB) AddL1Block(l1 eth.BlockID) error {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+
+	if err := db.lastEntryContext.ApplyL1Block(l1); err != nil {
+		return fmt.Errorf("failed to apply L1 block: %w", err)
+	}
+	db.log.Trace("Applied L1 block", "l1", l1)
 	return db.flush()
 }
 
+// L1BlockAt returns the last L1 block that was registered before the entry at the given index,
+// i.e. the L1 block that was known when the entry was written.
+// Only the first 15 bytes of the returned L1 block hash are set.
+// This scans back entry by entry, so the cost grows with the distance to the last registered L1 block.
+// returns ErrFuture if the entry index is not known yet, or if no L1 block was registered before it
+func (db *DB) L1BlockAt(entryIdx entrydb.EntryIdx) (eth.BlockID, error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+
+	if entryIdx > db.lastEntryContext.NextIndex() {
+		return eth.BlockID{}, fmt.Errorf("entry %d is not known yet: %w", entryIdx, ErrFuture)
+	}
+	for i := db.skipInvalidatedBack(entryIdx - 1); i >= 0; i = db.skipInvalidatedBack(i - 1) {
+		entry, err := db.store.Read(i)
+		if err != nil {
+			return eth.BlockID{}, fmt.Errorf("failed to read entry %d: %w", i, err)
+		}
+		if entry.Type() != entrydb.TypeL1Block {
+			continue
+		}
+		b, err := newL1BlockFromEntry(entry)
+		if err != nil {
+			return eth.BlockID{}, fmt.Errorf("failed to decode L1 block at entry %d: %w", i, err)
+		}
+		return b.l1, nil
+	}
+	return eth.BlockID{}, fmt.Errorf("no L1 block registered before entry %d: %w", entryIdx, ErrFuture)
+}
+
+// ContainsL1Block returns the index of the entry that registered the given L1 block,
+// such that a message that references the L1 block can be checked.
+// The L1 block is checked against the registrations since the last L1 reorg, see AddL1Block.
+// Only the first 15 bytes of the L1 block hash are checked.
+// This scans back entry by entry, so the cost grows with the age of the L1 block.
+// returns ErrFuture if the L1 block is after the last registered L1 block
+// returns ErrConflict if a different L1 block with the same number is registered
+// returns ErrSkipped if the L1 block is before the last registered L1 block, but was not registered itself
+func (db *DB) ContainsL1Block(l1 eth.BlockID) (entrydb.EntryIdx, error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+
+	expected := newL1Block(l1).l1
+	// bound is the number of the earliest L1 block that was registered after the entries that are yet to be scanned.
+	// An L1 block at or after the bound was replaced by an L1 reorg.
+	var bound *uint64
+	for i := db.lastEntryIdx(); i >= 0; i = db.skipInvalidatedBack(i - 1) {
+		entry, err := db.store.Read(i)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read entry %d: %w", i, err)
+		}
+		if entry.Type() != entrydb.TypeL1Block {
+			continue
+		}
+		b, err := newL1BlockFromEntry(entry)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode L1 block at entry %d: %w", i, err)
+		}
+		if bound != nil && b.l1.Number >= *bound {
+			continue // replaced by an L1 reorg
+		}
+		switch {
+		case b.l1.Number == expected.Number:
+			if b.l1 != expected {
+				return 0, fmt.Errorf("queried L1 block %s but got %s: %w", l1, b.l1, ErrConflict)
+			}
+			return i, nil
+		case b.l1.Number < expected.Number:
+			if bound == nil {
+				return 0, fmt.Errorf("L1 block %d is after the last registered L1 block %d: %w", l1.Number, b.l1.Number, ErrFuture)
+			}
+			return 0, fmt.Errorf("L1 block %d was not registered: %w", l1.Number, ErrSkipped)
+		}
+		bound = &b.l1.Number
+	}
+	if bound == nil {
+		return 0, fmt.Errorf("no L1 block registered: %w", ErrFuture)
+	}
+	return 0, fmt.Errorf("L1 block %d is before the first registered L1 block: %w", l1.Number, ErrSkipped)
+}
+
+// safeHeadLevel returns true if the given safety level is recorded as cross-safe head,
+// and false if it is recorded as local-safe head.
+func safeHeadLevel(level supTypes.SafetyLevel) (crossSafe bool, err error) {
+	switch level {
+	case supTypes.Safe:
+		return false, nil
+	case supTypes.CrossSafe:
+		return true, nil
+	default:
+		return false, fmt.Errorf("safe heads can only be recorded for local-safe and cross-safe, not %s", level)
+	}
+}
+
 // Rewind the database to remove any blocks after headBlockNum
 // The block at headBlockNum itself is not removed.
+// The entries are truncated back to the seal of the block,
+// and the in-memory state is restored, such that the next block can be appended on top of it,
+// e.g. to replace the blocks that were removed because of a reorg.
 func (db *DB) Rewind(newHeadBlockNum uint64) error {
 	db.rwLock.Lock()
 	defer db.rwLock.Unlock()
@@ -481,19 +1421,71 @@ func (db *DB) Rewind(newHeadBlockNum uint64) error {
 	if err != nil {
 		return err
 	}
-	// Truncate to contain idx+1 entries, since indices are 0 based,
-	// this deletes everything after idx
-	if err := db.store.Truncate(iter.NextIndex()); err != nil {
+	// The iterator is positioned right after the last entry that seals the block.
+	// Truncate such that this entry is the last entry, to delete everything after it.
+	// Snapshots that may read the deleted entries are marked as stale first.
+	if iter.NextIndex() < db.lastEntryContext.NextIndex() {
+		db.rewinds.Add(1)
+	}
+	if err := db.store.Truncate(iter.NextIndex() - 1); err != nil {
 		return fmt.Errorf("failed to truncate to block %v: %w", newHeadBlockNum, err)
 	}
 	// Use db.init() to find the log context for the new latest log entry
-	if err := db.init(true); err != nil {
+	if err := db.init(false); err != nil {
 		return fmt.Errorf("failed to find new last entry context: %w", err)
 	}
+	db.notifyChanges([]Change{newChange(&db.lastEntryContext, ChangeRewound)})
+	return nil
+}
+
+// Prune removes old entries that are no longer needed, to bound the size of the database on disk.
+// Entries are only removed up to the search checkpoint at or before the given entry index,
+// e.g. the entry index of the finalized head, such that the data at and after the index can still be searched.
+// The store removes entries in whole segments, so more entries than needed may be retained.
+// Lookups of data that was pruned return ErrSkipped.
+func (db *DB) Prune(entryIdx entrydb.EntryIdx) error {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+	checkpoint := (min(entryIdx, db.lastEntryIdx()) / db.checkpointFrequency) * db.checkpointFrequency
+	if checkpoint <= db.store.FirstEntryIdx() {
+		return nil
+	}
+	if err := db.store.Prune(checkpoint); err != nil {
+		return fmt.Errorf("failed to prune entries before %v: %w", checkpoint, err)
+	}
+	if db.checkpoints != nil {
+		db.checkpoints.prune(db.firstCheckpoint())
+	}
+	if db.dependents != nil {
+		if err := db.dependents.prune(db.firstCheckpoint() * db.checkpointFrequency); err != nil {
+			return fmt.Errorf("failed to prune dependents: %w", err)
+		}
+	}
 	return nil
 }
 
+// FullHashes returns true if the database stores full hashes, rather than truncated hashes.
+func (db *DB) FullHashes() bool {
+	return db.fullHashes
+}
+
+// NextIndex returns the index of the next entry that will be written to the database.
+// All entries before this index are readable.
+func (db *DB) NextIndex() entrydb.EntryIdx {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	return db.lastEntryContext.NextIndex()
+}
+
+// readSearchCheckpoint reads the search checkpoint at the given entry index.
+// A checkpoint of invalidated entries is substituted by the checkpoint that ends them, which re-seals the same state.
 func (db *DB) readSearchCheckpoint(entryIdx entrydb.EntryIdx) (searchCheckpoint, error) {
+	entryIdx = db.skipInvalidated(entryIdx)
+	if db.checkpoints != nil && entryIdx%db.checkpointFrequency == 0 && entryIdx <= db.lastEntryIdx() {
+		if checkpoint, ok := db.checkpoints.get(entryIdx / db.checkpointFrequency); ok {
+			return checkpoint, nil
+		}
+	}
 	data, err := db.store.Read(entryIdx)
 	if err != nil {
 		return searchCheckpoint{}, fmt.Errorf("failed to read entry %v: %w", entryIdx, err)
@@ -502,5 +1494,16 @@ func (db *DB) readSearchCheckpoint(entryIdx entrydb.EntryIdx) (searchCheckpoint,
 }
 
 func (db *DB) Close() error {
+	db.storeTailState()
+	if db.dependents != nil {
+		if err := db.dependents.Close(); err != nil {
+			db.log.Warn("Failed to close dependents", "err", err)
+		}
+	}
+	if db.blooms != nil {
+		if err := db.blooms.Close(); err != nil {
+			return errors.Join(fmt.Errorf("failed to close bloom filters: %w", err), db.store.Close())
+		}
+	}
 	return db.store.Close()
 }
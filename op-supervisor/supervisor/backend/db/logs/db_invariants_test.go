@@ -11,30 +11,45 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type statInvariant func(stat os.FileInfo, m *stubMetrics) error
-type entryInvariant func(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error
+type statInvariant func(stat os.FileInfo, recordSize int64, m *stubMetrics) error
+type entryInvariant func(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error
 
 // checkDBInvariants reads the database log directly and asserts a set of invariants on the data.
+// The tests write fewer entries than fit in a segment, so all entries are in the first segment.
 func checkDBInvariants(t *testing.T, dbPath string, m *stubMetrics) {
-	stat, err := os.Stat(dbPath)
+	segmentPath := entrydb.SegmentPath(dbPath, 0)
+	stat, err := os.Stat(segmentPath)
 	require.NoError(t, err)
 
+	// Read the header, to know the format of the entries
+	file, err := os.OpenFile(segmentPath, os.O_RDONLY, 0o644)
+	require.NoError(t, err)
+	var header entrydb.Header
+	_, err = io.ReadFull(file, header[:])
+	require.NoError(t, err, "failed to read header")
+	opts, err := optionsFromHeader(header)
+	require.NoError(t, err)
+	recordSize := int64(entrydb.EntrySize)
+	if header.Checksums() {
+		recordSize += entrydb.ChecksumSize
+	}
+
 	statInvariants := []statInvariant{
 		invariantFileSizeMultipleOfEntrySize,
 		invariantFileSizeMatchesEntryCountMetric,
 	}
 	for _, invariant := range statInvariants {
-		require.NoError(t, invariant(stat, m))
+		require.NoError(t, invariant(stat, recordSize, m))
 	}
 
-	// Read all entries as binary blobs
-	file, err := os.OpenFile(dbPath, os.O_RDONLY, 0o644)
-	require.NoError(t, err)
-	entries := make([]entrydb.Entry, stat.Size()/entrydb.EntrySize)
+	// Read all entries as binary blobs, the checksums are verified by reading through the entry DB
+	entries := make([]entrydb.Entry, (stat.Size()-entrydb.HeaderSize)/recordSize)
+	record := make([]byte, recordSize)
 	for i := range entries {
-		n, err := io.ReadFull(file, entries[i][:])
+		n, err := io.ReadFull(file, record)
 		require.NoErrorf(t, err, "failed to read entry %v", i)
-		require.EqualValuesf(t, entrydb.EntrySize, n, "read wrong length for entry %v", i)
+		require.EqualValuesf(t, recordSize, n, "read wrong length for entry %v", i)
+		entries[i] = entrydb.Entry(record[:entrydb.EntrySize])
 	}
 
 	entryInvariants := []entryInvariant{
@@ -45,10 +60,13 @@ func checkDBInvariants(t *testing.T, dbPath string, m *stubMetrics) {
 		invariantExecLinkOnlyAfterInitiatingEventWithFlagSet,
 		invariantExecCheckAfterExecLink,
 		invariantExecCheckOnlyAfterExecLink,
+		invariantHashExtensionAfterEveryHash,
+		invariantHashExtensionOnlyAfterHash,
+		invariantAnnotationAfterCompleteSealOrLog,
 	}
 	for i, entry := range entries {
 		for _, invariant := range entryInvariants {
-			err := invariant(i, entry, entries, m)
+			err := invariant(i, entry, entries, opts, m)
 			if err != nil {
 				require.NoErrorf(t, err, "Invariant breached: \n%v", fmtEntries(entries))
 			}
@@ -64,30 +82,31 @@ func fmtEntries(entries []entrydb.Entry) string {
 	return out
 }
 
-func invariantFileSizeMultipleOfEntrySize(stat os.FileInfo, _ *stubMetrics) error {
-	size := stat.Size()
-	if size%entrydb.EntrySize != 0 {
-		return fmt.Errorf("expected file size to be a multiple of entry size (%v) but was %v", entrydb.EntrySize, size)
+func invariantFileSizeMultipleOfEntrySize(stat os.FileInfo, recordSize int64, _ *stubMetrics) error {
+	size := stat.Size() - entrydb.HeaderSize
+	if size%recordSize != 0 {
+		return fmt.Errorf("expected file size to be a multiple of entry size (%v) but was %v", recordSize, size)
 	}
 	return nil
 }
 
-func invariantFileSizeMatchesEntryCountMetric(stat os.FileInfo, m *stubMetrics) error {
-	size := stat.Size()
-	if m.entryCount*entrydb.EntrySize != size {
-		return fmt.Errorf("expected file size to be entryCount (%v) * entrySize (%v) = %v but was %v", m.entryCount, entrydb.EntrySize, m.entryCount*entrydb.EntrySize, size)
+func invariantFileSizeMatchesEntryCountMetric(stat os.FileInfo, recordSize int64, m *stubMetrics) error {
+	size := stat.Size() - entrydb.HeaderSize
+	if m.entryCount*recordSize != size {
+		return fmt.Errorf("expected file size to be entryCount (%v) * entrySize (%v) = %v but was %v", m.entryCount, recordSize, m.entryCount*recordSize, size)
 	}
 	return nil
 }
 
-func invariantSearchCheckpointAtEverySearchCheckpointFrequency(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
-	if entryIdx%searchCheckpointFrequency == 0 && entry.Type() != entrydb.TypeSearchCheckpoint {
-		return fmt.Errorf("should have search checkpoints every %v entries but entry %v was %x", searchCheckpointFrequency, entryIdx, entry)
+func invariantSearchCheckpointAtEverySearchCheckpointFrequency(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	freq := int(opts.SearchCheckpointFrequency)
+	if entryIdx%freq == 0 && entry.Type() != entrydb.TypeSearchCheckpoint {
+		return fmt.Errorf("should have search checkpoints every %v entries but entry %v was %x", freq, entryIdx, entry)
 	}
 	return nil
 }
 
-func invariantCanonicalHashOrCheckpointAfterEverySearchCheckpoint(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
+func invariantCanonicalHashOrCheckpointAfterEverySearchCheckpoint(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
 	if entry.Type() != entrydb.TypeSearchCheckpoint {
 		return nil
 	}
@@ -102,7 +121,7 @@ func invariantCanonicalHashOrCheckpointAfterEverySearchCheckpoint(entryIdx int,
 }
 
 // invariantSearchCheckpointBeforeEveryCanonicalHash ensures we don't have extra canonical-hash entries
-func invariantSearchCheckpointBeforeEveryCanonicalHash(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
+func invariantSearchCheckpointBeforeEveryCanonicalHash(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
 	if entry.Type() != entrydb.TypeCanonicalHash {
 		return nil
 	}
@@ -116,7 +135,8 @@ func invariantSearchCheckpointBeforeEveryCanonicalHash(entryIdx int, entry entry
 	return nil
 }
 
-func invariantExecLinkAfterInitEventWithFlagSet(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
+func invariantExecLinkAfterInitEventWithFlagSet(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	freq := int(opts.SearchCheckpointFrequency)
 	if entry.Type() != entrydb.TypeInitiatingEvent {
 		return nil
 	}
@@ -124,8 +144,8 @@ func invariantExecLinkAfterInitEventWithFlagSet(entryIdx int, entry entrydb.Entr
 	if !hasExecMessage {
 		return nil
 	}
-	linkIdx := entryIdx + 1
-	if linkIdx%searchCheckpointFrequency == 0 {
+	linkIdx := entryIdx + 1 + hashExtensionEntries(opts)
+	if linkIdx%freq == 0 {
 		linkIdx += 2 // Skip over the search checkpoint and canonical hash events
 	}
 	if len(entries) <= linkIdx {
@@ -137,21 +157,25 @@ func invariantExecLinkAfterInitEventWithFlagSet(entryIdx int, entry entrydb.Entr
 	return nil
 }
 
-func invariantExecLinkOnlyAfterInitiatingEventWithFlagSet(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
+func invariantExecLinkOnlyAfterInitiatingEventWithFlagSet(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	freq := int(opts.SearchCheckpointFrequency)
 	if entry.Type() != entrydb.TypeExecutingLink {
 		return nil
 	}
 	if entryIdx == 0 {
 		return errors.New("found executing link as first entry")
 	}
-	initIdx := entryIdx - 1
-	if initIdx%searchCheckpointFrequency == 1 {
+	initIdx := entryIdx - 1 - hashExtensionEntries(opts)
+	if initIdx%freq == 1 {
 		initIdx -= 2 // Skip the canonical hash and search checkpoint entries
 	}
 	if initIdx < 0 {
 		return fmt.Errorf("found executing link without a preceding initiating event at entry %v", entryIdx)
 	}
 	initEntry := entries[initIdx]
+	if initEntry.Type() == entrydb.TypeExecutingCheck {
+		return nil // a further executing message of the same initiating event
+	}
 	if initEntry.Type() != entrydb.TypeInitiatingEvent {
 		return fmt.Errorf("expected initiating event at entry %v prior to executing link at %v but got %x", initIdx, entryIdx, initEntry[0])
 	}
@@ -162,12 +186,13 @@ func invariantExecLinkOnlyAfterInitiatingEventWithFlagSet(entryIdx int, entry en
 	return nil
 }
 
-func invariantExecCheckAfterExecLink(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
+func invariantExecCheckAfterExecLink(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	freq := int(opts.SearchCheckpointFrequency)
 	if entry.Type() != entrydb.TypeExecutingLink {
 		return nil
 	}
 	checkIdx := entryIdx + 1
-	if checkIdx%searchCheckpointFrequency == 0 {
+	if checkIdx%freq == 0 {
 		checkIdx += 2 // Skip the search checkpoint and canonical hash entries
 	}
 	if checkIdx >= len(entries) {
@@ -180,7 +205,8 @@ func invariantExecCheckAfterExecLink(entryIdx int, entry entrydb.Entry, entries
 	return nil
 }
 
-func invariantExecCheckOnlyAfterExecLink(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, m *stubMetrics) error {
+func invariantExecCheckOnlyAfterExecLink(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	freq := int(opts.SearchCheckpointFrequency)
 	if entry.Type() != entrydb.TypeExecutingCheck {
 		return nil
 	}
@@ -188,7 +214,7 @@ func invariantExecCheckOnlyAfterExecLink(entryIdx int, entry entrydb.Entry, entr
 		return errors.New("found executing check as first entry")
 	}
 	linkIdx := entryIdx - 1
-	if linkIdx%searchCheckpointFrequency == 1 {
+	if linkIdx%freq == 1 {
 		linkIdx -= 2 // Skip the canonical hash and search checkpoint entries
 	}
 	if linkIdx < 0 {
@@ -200,3 +226,73 @@ func invariantExecCheckOnlyAfterExecLink(entryIdx int, entry entrydb.Entry, entr
 	}
 	return nil
 }
+
+// hashExtensionEntries returns the number of hash-extension entries that follow an entry with a hash
+func hashExtensionEntries(opts Options) int {
+	if opts.FullHashes {
+		return 1
+	}
+	return 0
+}
+
+func invariantHashExtensionAfterEveryHash(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	switch entry.Type() {
+	case entrydb.TypeCanonicalHash, entrydb.TypeInitiatingEvent, entrydb.TypeExecutingCheck, entrydb.TypeDepositEvent:
+	default:
+		return nil
+	}
+	if !opts.FullHashes {
+		return nil
+	}
+	if entryIdx+1 >= len(entries) {
+		return fmt.Errorf("expected hash extension after %s at entry %v but no further entries found", entry.Type(), entryIdx)
+	}
+	if nextEntry := entries[entryIdx+1]; nextEntry.Type() != entrydb.TypeHashExtension {
+		return fmt.Errorf("expected hash extension after %s at entry %v but got %x", entry.Type(), entryIdx, nextEntry)
+	}
+	return nil
+}
+
+func invariantHashExtensionOnlyAfterHash(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	if entry.Type() != entrydb.TypeHashExtension {
+		return nil
+	}
+	if !opts.FullHashes {
+		return fmt.Errorf("found hash extension at entry %v in database without full hashes", entryIdx)
+	}
+	if entryIdx == 0 {
+		return errors.New("found hash extension as first entry")
+	}
+	switch prevEntry := entries[entryIdx-1]; prevEntry.Type() {
+	case entrydb.TypeCanonicalHash, entrydb.TypeInitiatingEvent, entrydb.TypeExecutingCheck, entrydb.TypeDepositEvent:
+		return nil
+	default:
+		return fmt.Errorf("expected entry with hash before hash extension at entry %v but got %x", entryIdx, prevEntry)
+	}
+}
+
+func invariantAnnotationAfterCompleteSealOrLog(entryIdx int, entry entrydb.Entry, entries []entrydb.Entry, opts Options, m *stubMetrics) error {
+	switch entry.Type() {
+	case entrydb.TypeDerivedFrom, entrydb.TypeSafeHead, entrydb.TypeL1Block:
+	default:
+		return nil
+	}
+	if entryIdx == 0 {
+		return fmt.Errorf("found %s as first entry", entry.Type())
+	}
+	prevEntry := entries[entryIdx-1]
+	switch prevEntry.Type() {
+	case entrydb.TypeSearchCheckpoint, entrydb.TypeExecutingLink:
+		return fmt.Errorf("expected complete block seal or log before %s at entry %v but got %x", entry.Type(), entryIdx, prevEntry)
+	case entrydb.TypeInitiatingEvent:
+		if prevEntry[1]&eventFlagHasExecutingMessage != 0 {
+			return fmt.Errorf("expected executing message after initiating event at entry %v but got %s", entryIdx-1, entry.Type())
+		}
+		fallthrough
+	case entrydb.TypeCanonicalHash, entrydb.TypeExecutingCheck, entrydb.TypeDepositEvent:
+		if opts.FullHashes {
+			return fmt.Errorf("expected hash extension before %s at entry %v but got %x", entry.Type(), entryIdx, prevEntry)
+		}
+	}
+	return nil
+}
@@ -2,11 +2,13 @@ package logs
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -18,10 +20,12 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+	supTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
-func createTruncatedHash(i int) types.TruncatedHash {
-	return types.TruncateHash(createHash(i))
+// createTruncatedHash creates a hash as stored by a DB without full hashes
+func createTruncatedHash(i int) common.Hash {
+	return storedHash(createHash(i), false)
 }
 
 func createHash(i int) common.Hash {
@@ -36,16 +40,20 @@ func createHash(i int) common.Hash {
 
 func TestErrorOpeningDatabase(t *testing.T) {
 	dir := t.TempDir()
-	_, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, filepath.Join(dir, "missing-dir", "file.db"), false)
+	_, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, filepath.Join(dir, "missing-dir", "file.db"), false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
 	require.ErrorIs(t, err, os.ErrNotExist)
 }
 
 func runDBTest(t *testing.T, setup func(t *testing.T, db *DB, m *stubMetrics), assert func(t *testing.T, db *DB, m *stubMetrics)) {
+	runDBTestWithOptions(t, DefaultOptions(), setup, assert)
+}
+
+func runDBTestWithOptions(t *testing.T, opts Options, setup func(t *testing.T, db *DB, m *stubMetrics), assert func(t *testing.T, db *DB, m *stubMetrics)) {
 	createDb := func(t *testing.T, dir string) (*DB, *stubMetrics, string) {
 		logger := testlog.Logger(t, log.LvlTrace)
 		path := filepath.Join(dir, "test.db")
 		m := &stubMetrics{}
-		db, err := NewFromFile(logger, m, path, false)
+		db, err := NewFromFile(logger, m, path, false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
 		require.NoError(t, err, "Failed to create database")
 		t.Cleanup(func() {
 			err := db.Close()
@@ -60,6 +68,7 @@ func runDBTest(t *testing.T, setup func(t *testing.T, db *DB, m *stubMetrics), a
 		db, m, _ := createDb(t, t.TempDir())
 		setup(t, db, m)
 		assert(t, db, m)
+		require.NoError(t, db.Verify())
 	})
 
 	t.Run("Existing", func(t *testing.T) {
@@ -73,6 +82,272 @@ func runDBTest(t *testing.T, setup func(t *testing.T, db *DB, m *stubMetrics), a
 		db2, m, path := createDb(t, dir)
 		assert(t, db2, m)
 		checkDBInvariants(t, path, m)
+		require.NoError(t, db2.Verify())
+	})
+}
+
+func TestOptions(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		opts := Options{SearchCheckpointFrequency: minSearchCheckpointFrequency - 1}
+		_, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, filepath.Join(t.TempDir(), "file.db"), false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.ErrorContains(t, err, "less than the minimum")
+	})
+
+	t.Run("InvalidWithFullHashes", func(t *testing.T) {
+		opts := Options{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency - 1, FullHashes: true}
+		_, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, filepath.Join(t.TempDir(), "file.db"), false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.ErrorContains(t, err, "less than the minimum")
+	})
+
+	t.Run("PersistedInHeader", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, Options{SearchCheckpointFrequency: 7}, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		db, err = NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		require.EqualValues(t, 7, db.checkpointFrequency, "should use the options the database was created with")
+		require.NoError(t, db.Close())
+
+		fullPath := filepath.Join(t.TempDir(), "full.db")
+		db, err = NewFromFile(logger, &stubMetrics{}, fullPath, false, Options{SearchCheckpointFrequency: 9, FullHashes: true}, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		db, err = NewFromFile(logger, &stubMetrics{}, fullPath, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		require.True(t, db.FullHashes(), "should store full hashes as the database was created with")
+	})
+
+	t.Run("ExistingWithoutHeader", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		var data []byte
+		for _, entry := range []entrydb.Entry{
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createTruncatedHash(300)).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
+		} {
+			data = append(data, entry[:]...)
+		}
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, Options{SearchCheckpointFrequency: 7}, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		require.EqualValues(t, defaultSearchCheckpointFrequency, db.checkpointFrequency, "should use the default options")
+		_, err = db.Contains(1, 0, createTruncatedHash(1))
+		require.NoError(t, err)
+	})
+}
+
+// TestSmallSearchCheckpointFrequency uses a tiny interval between search checkpoints,
+// to exercise the padding of logs with executing messages around the checkpoints.
+func TestWriteMetrics(t *testing.T) {
+	m := &stubMetrics{}
+	path := filepath.Join(t.TempDir(), "test.db")
+	opts := Options{SearchCheckpointFrequency: 8, FullHashes: true}
+	db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), m, path, false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+	require.NoError(t, err)
+	defer db.Close()
+	for i := 0; i <= 20; i++ {
+		parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+		require.NoError(t, db.WriteBatch(func(b Batch) error {
+			for j := 0; i > 0 && j < i%3; j++ {
+				if err := b.AddLog(createHash(100*i+j), parent, uint32(j), nil); err != nil {
+					return err
+				}
+			}
+			return b.SealBlock(parent.Hash, eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i))
+		}))
+	}
+
+	var total int64
+	for _, count := range m.entriesWritten {
+		total += count
+	}
+	require.EqualValues(t, db.NextIndex(), total, "should count every written entry")
+	require.Positive(t, m.entriesWritten["padding"])
+	require.Equal(t, float64(m.entriesWritten["padding"])/float64(total), m.paddingRatio)
+	require.EqualValues(t, 21, m.blocksSealed)
+	require.Equal(t, m.entriesWritten["searchCheckpoint"], m.searchCheckpoints)
+	require.Positive(t, m.repeatedCheckpoints, "should repeat blocks at checkpoint intervals")
+	require.Equal(t, m.searchCheckpoints-m.blocksSealed, m.repeatedCheckpoints)
+	require.EqualValues(t, 21, m.flushes)
+	require.EqualValues(t, 2*entrydb.HeaderSize+total*(entrydb.EntrySize+entrydb.ChecksumSize), m.fileSize)
+}
+
+func TestSmallSearchCheckpointFrequency(t *testing.T) {
+	for _, freq := range []uint32{minSearchCheckpointFrequency, 6, 7} {
+		freq := freq
+		t.Run(fmt.Sprintf("Frequency%d", freq), func(t *testing.T) {
+			execMsg := types.ExecutingMessage{
+				Chain:     33,
+				BlockNum:  22,
+				LogIdx:    99,
+				Timestamp: 948294,
+				Hash:      createTruncatedHash(332299),
+			}
+			runDBTestWithOptions(t, Options{SearchCheckpointFrequency: freq},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+						for j := 0; j < i%4; j++ {
+							var msg *types.ExecutingMessage
+							if j%2 == 0 {
+								msg = &execMsg
+							}
+							require.NoError(t, db.AddLog(createTruncatedHash(j), bl, uint32(j), msg))
+						}
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 1; i < 20; i++ {
+						for j := 0; j < (i-1)%4; j++ {
+							if j%2 == 0 {
+								requireContains(t, db, uint64(i), uint32(j), createHash(j), execMsg)
+							} else {
+								requireContains(t, db, uint64(i), uint32(j), createHash(j))
+							}
+						}
+						_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)})
+						require.NoError(t, err)
+					}
+					requireFuture(t, db, 21, 0, createHash(0))
+				})
+		})
+	}
+}
+
+func TestMultipleExecMsgs(t *testing.T) {
+	execMsg := func(i int, fullHashes bool) types.ExecutingMessage {
+		return types.ExecutingMessage{
+			Chain:     uint32(30 + i),
+			BlockNum:  uint64(20 + i),
+			LogIdx:    uint32(90 + i),
+			Timestamp: uint64(948294 + i),
+			Hash:      storedHash(createHash(332299+i), fullHashes),
+		}
+	}
+	for _, opts := range []Options{
+		{SearchCheckpointFrequency: 16},
+		{SearchCheckpointFrequency: 16, FullHashes: true},
+		DefaultOptions(),
+	} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d-FullHashes%v", opts.SearchCheckpointFrequency, opts.FullHashes), func(t *testing.T) {
+			execMsgs := func(i, j int) []types.ExecutingMessage {
+				var msgs []types.ExecutingMessage
+				for k := 0; k < (i+j)%4; k++ {
+					msgs = append(msgs, execMsg(k, opts.FullHashes))
+				}
+				return msgs
+			}
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+						for j := 0; j < i%3; j++ {
+							require.NoError(t, db.AddLogWithExecMsgs(createHash(j), bl, uint32(j), execMsgs(i, j)))
+						}
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 1; i < 20; i++ {
+						for j := 0; j < (i-1)%3; j++ {
+							requireContains(t, db, uint64(i), uint32(j), createHash(j), execMsgs(i-1, j)...)
+						}
+					}
+				})
+		})
+	}
+
+	t.Run("TooManyExecMsgs", func(t *testing.T) {
+		runDBTestWithOptions(t, Options{SearchCheckpointFrequency: minSearchCheckpointFrequency},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.SealBlock(createHash(0), eth.BlockID{Hash: createHash(1), Number: 1}, 501))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				bl := eth.BlockID{Hash: createHash(1), Number: 1}
+				msgs := []types.ExecutingMessage{execMsg(0, false), execMsg(1, false)}
+				require.ErrorContains(t, db.AddLogWithExecMsgs(createHash(0), bl, 0, msgs), "does not fit")
+				require.NoError(t, db.AddLogWithExecMsgs(createHash(0), bl, 0, msgs[:1]))
+				requireContains(t, db, 2, 0, createHash(0), msgs[0])
+			})
+	})
+}
+
+// TestFullHashes stores full hashes, with small intervals between search checkpoints,
+// to exercise the padding of the hash extensions around the checkpoints.
+func TestFullHashes(t *testing.T) {
+	// differs from the given hash only in the bytes that are not stored in truncated mode
+	tailChanged := func(h common.Hash) common.Hash {
+		h[common.HashLength-1] ^= 0xff
+		return h
+	}
+	for _, freq := range []uint32{minFullHashesSearchCheckpointFrequency, 9, 10, 11, defaultSearchCheckpointFrequency} {
+		freq := freq
+		t.Run(fmt.Sprintf("Frequency%d", freq), func(t *testing.T) {
+			execMsg := types.ExecutingMessage{
+				Chain:     33,
+				BlockNum:  22,
+				LogIdx:    99,
+				Timestamp: 948294,
+				Hash:      createHash(332299),
+			}
+			runDBTestWithOptions(t, Options{SearchCheckpointFrequency: freq, FullHashes: true},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+						for j := 0; j < i%4; j++ {
+							var msg *types.ExecutingMessage
+							if j%2 == 0 {
+								msg = &execMsg
+							}
+							require.NoError(t, db.AddLog(createHash(j), bl, uint32(j), msg))
+						}
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					require.True(t, db.FullHashes())
+					for i := 1; i < 20; i++ {
+						for j := 0; j < (i-1)%4; j++ {
+							if j%2 == 0 {
+								requireContains(t, db, uint64(i), uint32(j), createHash(j), execMsg)
+							} else {
+								requireContains(t, db, uint64(i), uint32(j), createHash(j))
+							}
+							_, err := db.Contains(uint64(i), uint32(j), tailChanged(createHash(j)))
+							require.ErrorContains(t, err, "payload hash mismatch")
+						}
+						_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)})
+						require.NoError(t, err)
+						_, err = db.FindSealedBlock(eth.BlockID{Hash: tailChanged(createHash(i)), Number: uint64(i)})
+						require.ErrorIs(t, err, ErrConflict)
+					}
+					requireFuture(t, db, 21, 0, createHash(0))
+				})
+		})
+	}
+
+	t.Run("TruncatedComparesPrefix", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				bl := eth.BlockID{Hash: createHash(1), Number: 1}
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.SealBlock(createHash(0), bl, 501))
+				require.NoError(t, db.AddLog(createHash(1), bl, 0, nil))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.False(t, db.FullHashes())
+				requireContains(t, db, 2, 0, tailChanged(createHash(1)))
+				_, err := db.FindSealedBlock(eth.BlockID{Hash: tailChanged(createHash(1)), Number: 1})
+				require.NoError(t, err)
+			})
 	})
 }
 
@@ -325,11 +600,11 @@ func TestAddLog(t *testing.T) {
 		// Ignoring seal-checkpoints in checkpoint counting comments here;
 		// First search-checkpoint is at entry idx 0
 		// Block 1 logs don't reach the second search-checkpoint
-		block1LogCount := searchCheckpointFrequency - 10
+		block1LogCount := defaultSearchCheckpointFrequency - 10
 		// Block 2 logs extend to just after the third search-checkpoint
-		block2LogCount := searchCheckpointFrequency + 16
+		block2LogCount := defaultSearchCheckpointFrequency + 16
 		// Block 3 logs extend to immediately before the fourth search-checkpoint
-		block3LogCount := searchCheckpointFrequency - 19
+		block3LogCount := defaultSearchCheckpointFrequency - 19
 		block4LogCount := 2
 		runDBTest(t,
 			func(t *testing.T, db *DB, m *stubMetrics) {
@@ -375,9 +650,9 @@ func TestAddLog(t *testing.T) {
 
 				// Verify that we're right before the fourth search-checkpoint will be written.
 				// entryCount is the number of entries, so given 0 based indexing is the index of the next entry
-				// the first checkpoint is at entry 0, the second at entry searchCheckpointFrequency etc
-				// so the fourth is at entry 3*searchCheckpointFrequency.
-				require.EqualValues(t, 3*searchCheckpointFrequency-1, m.entryCount)
+				// the first checkpoint is at entry 0, the second at entry defaultSearchCheckpointFrequency etc
+				// so the fourth is at entry 3*defaultSearchCheckpointFrequency.
+				require.EqualValues(t, 3*defaultSearchCheckpointFrequency-1, m.entryCount)
 				{ // create block 4
 					for i := 0; i < block4LogCount; i++ {
 						// includes a fourth search checkpoint
@@ -422,7 +697,7 @@ func TestAddDependentLog(t *testing.T) {
 		BlockNum:  42894,
 		LogIdx:    42,
 		Timestamp: 8742482,
-		Hash:      types.TruncateHash(createHash(8844)),
+		Hash:      storedHash(createHash(8844), false),
 	}
 	t.Run("FirstEntry", func(t *testing.T) {
 		runDBTest(t,
@@ -442,13 +717,13 @@ func TestAddDependentLog(t *testing.T) {
 			func(t *testing.T, db *DB, m *stubMetrics) {
 				bl15 := eth.BlockID{Hash: createHash(15), Number: 15}
 				require.NoError(t, db.lastEntryContext.forceBlock(bl15, 5000))
-				for i := uint32(0); m.entryCount < searchCheckpointFrequency-1; i++ {
+				for i := uint32(0); m.entryCount < defaultSearchCheckpointFrequency-1; i++ {
 					require.NoError(t, db.AddLog(createTruncatedHash(9), bl15, i, nil))
 				}
 				bl16 := eth.BlockID{Hash: createHash(16), Number: 16}
 				require.NoError(t, db.SealBlock(bl15.Hash, bl16, 5001))
 				// added 3 entries: seal-checkpoint, then a search-checkpoint, then the canonical hash
-				require.Equal(t, m.entryCount, int64(searchCheckpointFrequency+2))
+				require.Equal(t, m.entryCount, int64(defaultSearchCheckpointFrequency+2))
 				err := db.AddLog(createTruncatedHash(1), bl16, 0, &execMsg)
 				require.NoError(t, err)
 			},
@@ -611,6 +886,796 @@ func TestExecutes(t *testing.T) {
 		})
 }
 
+func TestDerivedFrom(t *testing.T) {
+	l1Block := func(i int) eth.BlockID {
+		return eth.BlockID{Hash: createHash(1000 + i), Number: uint64(100 + i/2)}
+	}
+	for _, opts := range []Options{
+		{SearchCheckpointFrequency: minSearchCheckpointFrequency},
+		{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true},
+		DefaultOptions(),
+	} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d-FullHashes%v", opts.SearchCheckpointFrequency, opts.FullHashes), func(t *testing.T) {
+			execMsg := types.ExecutingMessage{
+				Chain:     33,
+				BlockNum:  22,
+				LogIdx:    99,
+				Timestamp: 948294,
+				Hash:      storedHash(createHash(332299), opts.FullHashes),
+			}
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+						// link before, in between, or after the logs that follow the block, or not at all
+						for j := 0; j < i%4; j++ {
+							if j == i%3 {
+								require.NoError(t, db.AddDerivedFrom(bl, l1Block(i)))
+							}
+							var msg *types.ExecutingMessage
+							if j%2 == 0 {
+								msg = &execMsg
+							}
+							require.NoError(t, db.AddLog(createHash(j), bl, uint32(j), msg))
+						}
+						if i%4 <= i%3 && i != 17 {
+							require.NoError(t, db.AddDerivedFrom(bl, l1Block(i)))
+						}
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						l1, err := db.DerivedFrom(bl)
+						if i%4 <= i%3 && i == 17 {
+							require.ErrorIs(t, err, ErrSkipped)
+							continue
+						}
+						require.NoError(t, err, "block %d", i)
+						expected := l1Block(i)
+						require.Equal(t, eth.BlockID{Hash: truncateL1Hash(expected.Hash), Number: expected.Number}, l1)
+					}
+					_, err := db.DerivedFrom(eth.BlockID{Hash: createHash(20), Number: 20})
+					require.ErrorIs(t, err, ErrFuture)
+					_, err = db.DerivedFrom(eth.BlockID{Hash: createHash(100), Number: 5})
+					require.ErrorIs(t, err, ErrConflict)
+				})
+		})
+	}
+
+	t.Run("NotLinkedYet", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.SealBlock(createHash(0), eth.BlockID{Hash: createHash(1), Number: 1}, 501))
+				require.NoError(t, db.AddLog(createHash(1), eth.BlockID{Hash: createHash(1), Number: 1}, 0, nil))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, err := db.DerivedFrom(eth.BlockID{Hash: createHash(1), Number: 1})
+				require.ErrorIs(t, err, ErrFuture)
+			})
+	})
+
+	t.Run("ErrorWhenNotLastSealedBlock", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.SealBlock(createHash(0), eth.BlockID{Hash: createHash(1), Number: 1}, 501))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				err := db.AddDerivedFrom(eth.BlockID{Hash: createHash(0), Number: 0}, l1Block(0))
+				require.ErrorIs(t, err, ErrConflict)
+				err = db.AddDerivedFrom(eth.BlockID{Hash: createHash(2), Number: 1}, l1Block(0))
+				require.ErrorIs(t, err, ErrConflict)
+			})
+	})
+
+	t.Run("LinkOnce", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				bl := eth.BlockID{Hash: createHash(0), Number: 0}
+				require.NoError(t, db.SealBlock(common.Hash{}, bl, 500))
+				require.NoError(t, db.AddDerivedFrom(bl, l1Block(0)))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				bl := eth.BlockID{Hash: createHash(0), Number: 0}
+				require.NoError(t, db.AddDerivedFrom(bl, l1Block(0)), "linking to the same L1 block again is a no-op")
+				require.EqualValues(t, 3, m.entryCount)
+				require.ErrorIs(t, db.AddDerivedFrom(bl, l1Block(2)), ErrConflict)
+			})
+	})
+}
+
+func TestSafeHeads(t *testing.T) {
+	for _, opts := range []Options{
+		{SearchCheckpointFrequency: minSearchCheckpointFrequency},
+		{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true},
+		DefaultOptions(),
+	} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d-FullHashes%v", opts.SearchCheckpointFrequency, opts.FullHashes), func(t *testing.T) {
+			// index after the entries of each block, including the safe heads recorded with it
+			var blockEnds []entrydb.EntryIdx
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					blockEnds = nil
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+						if i >= 1 {
+							require.NoError(t, db.AddSafeHead(supTypes.Safe, eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}))
+						}
+						for j := 0; j < i%3; j++ {
+							require.NoError(t, db.AddLog(createHash(j), bl, uint32(j), nil))
+						}
+						if i >= 3 {
+							require.NoError(t, db.AddSafeHead(supTypes.CrossSafe, eth.BlockID{Hash: createHash(i - 3), Number: uint64(i - 3)}))
+						}
+						blockEnds = append(blockEnds, db.NextIndex())
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					expected := func(n int) eth.BlockID {
+						return eth.BlockID{Hash: storedHash(createHash(n), opts.FullHashes), Number: uint64(n)}
+					}
+					for i, end := range blockEnds {
+						localSafe, err := db.SafeHeadAt(end, supTypes.Safe)
+						if i < 1 {
+							require.ErrorIs(t, err, ErrFuture)
+						} else {
+							require.NoError(t, err)
+							require.Equal(t, expected(i-1), localSafe)
+						}
+						crossSafe, err := db.SafeHeadAt(end, supTypes.CrossSafe)
+						if i < 3 {
+							require.ErrorIs(t, err, ErrFuture)
+						} else {
+							require.NoError(t, err)
+							require.Equal(t, expected(i-3), crossSafe)
+						}
+					}
+					_, err := db.SafeHeadAt(db.NextIndex()+1, supTypes.Safe)
+					require.ErrorIs(t, err, ErrFuture)
+				})
+		})
+	}
+
+	t.Run("ExcludesHeadAtIndex", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.AddSafeHead(supTypes.Safe, eth.BlockID{Hash: createHash(0), Number: 0}))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, err := db.SafeHeadAt(2, supTypes.Safe)
+				require.ErrorIs(t, err, ErrFuture)
+				head, err := db.SafeHeadAt(3, supTypes.Safe)
+				require.NoError(t, err)
+				require.Equal(t, eth.BlockID{Hash: createTruncatedHash(0), Number: 0}, head)
+			})
+	})
+
+	t.Run("ErrorWhenBlockUnknown", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.SealBlock(createHash(0), eth.BlockID{Hash: createHash(1), Number: 1}, 501))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				err := db.AddSafeHead(supTypes.Safe, eth.BlockID{Hash: createHash(2), Number: 2})
+				require.ErrorIs(t, err, ErrFuture)
+				err = db.AddSafeHead(supTypes.CrossSafe, eth.BlockID{Hash: createHash(2), Number: 1})
+				require.ErrorIs(t, err, ErrConflict)
+				require.EqualValues(t, 4, m.entryCount)
+			})
+	})
+
+	t.Run("ErrorWhenNotSafeLevel", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.Error(t, db.AddSafeHead(supTypes.Unsafe, eth.BlockID{Hash: createHash(0), Number: 0}))
+				_, err := db.SafeHeadAt(db.NextIndex(), supTypes.Finalized)
+				require.Error(t, err)
+			})
+	})
+}
+
+func TestWriteBatch(t *testing.T) {
+	t.Run("WriteBlockWithLogs", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.WriteBatch(func(b Batch) error {
+					bl0 := eth.BlockID{Hash: createHash(0), Number: 0}
+					if err := b.AddLog(createHash(1), bl0, 0, nil); err != nil {
+						return err
+					}
+					if err := b.AddLog(createHash(2), bl0, 1, nil); err != nil {
+						return err
+					}
+					return b.SealBlock(createHash(0), eth.BlockID{Hash: createHash(1), Number: 1}, 501)
+				}))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				requireContains(t, db, 1, 0, createHash(1))
+				requireContains(t, db, 1, 1, createHash(2))
+				n, ok := db.LatestSealedBlockNum()
+				require.True(t, ok)
+				require.EqualValues(t, 1, n)
+			})
+	})
+
+	t.Run("DropFailedBatch", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				bl0 := eth.BlockID{Hash: createHash(0), Number: 0}
+				err := db.WriteBatch(func(b Batch) error {
+					if err := b.AddLog(createHash(1), bl0, 0, nil); err != nil {
+						return err
+					}
+					return b.SealBlock(createHash(5), eth.BlockID{Hash: createHash(1), Number: 1}, 501)
+				})
+				require.ErrorIs(t, err, ErrConflict)
+				require.EqualValues(t, 2, m.entryCount)
+				requireFuture(t, db, 1, 0, createHash(1))
+				// the DB remains usable after the failed batch
+				require.NoError(t, db.AddLog(createHash(1), bl0, 0, nil))
+				requireContains(t, db, 1, 0, createHash(1))
+			})
+	})
+}
+
+func TestIterateRange(t *testing.T) {
+	execMsg := func(i int, fullHashes bool) types.ExecutingMessage {
+		return types.ExecutingMessage{
+			Chain:     uint32(30 + i),
+			BlockNum:  uint64(20 + i),
+			LogIdx:    uint32(90 + i),
+			Timestamp: uint64(948294 + i),
+			Hash:      storedHash(createHash(332299+i), fullHashes),
+		}
+	}
+	// blockLogs returns the (i-1)%3 logs of block i, where log j has (i+j)%3 executing messages
+	blockLogs := func(i int, fullHashes bool) []BlockLog {
+		var logs []BlockLog
+		for j := 0; i > 0 && j < (i-1)%3; j++ {
+			var msgs []types.ExecutingMessage
+			for k := 0; k < (i+j)%3; k++ {
+				msgs = append(msgs, execMsg(k, fullHashes))
+			}
+			logs = append(logs, BlockLog{Hash: storedHash(createHash(100*i+j), fullHashes), ExecMsgs: msgs})
+		}
+		return logs
+	}
+	addLogs := func(t *testing.T, db *DB, i int) {
+		parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+		for j, l := range blockLogs(i, db.FullHashes()) {
+			require.NoError(t, db.AddLogWithExecMsgs(createHash(100*i+j), parent, uint32(j), l.ExecMsgs))
+		}
+	}
+	sealBlock := func(t *testing.T, db *DB, i int) {
+		require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+	}
+	addBlocks := func(t *testing.T, db *DB, to int) {
+		for i := 0; i <= to; i++ {
+			if i > 0 {
+				addLogs(t, db, i)
+			}
+			sealBlock(t, db, i)
+		}
+	}
+	requireNext := func(t *testing.T, db *DB, iter BlockIterator, i int) {
+		block, err := iter.Next()
+		require.NoError(t, err)
+		require.Equal(t, BlockLogs{
+			Block:     eth.BlockID{Hash: storedHash(createHash(i), db.FullHashes()), Number: uint64(i)},
+			Timestamp: 500 + uint64(i),
+			Logs:      blockLogs(i, db.FullHashes()),
+		}, block)
+	}
+
+	for _, opts := range []Options{
+		{SearchCheckpointFrequency: 16},
+		{SearchCheckpointFrequency: 16, FullHashes: true},
+		DefaultOptions(),
+	} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d-FullHashes%v", opts.SearchCheckpointFrequency, opts.FullHashes), func(t *testing.T) {
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					addBlocks(t, db, 20)
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					iter, err := db.IterateRange(0, 20)
+					require.NoError(t, err)
+					for i := 0; i <= 20; i++ {
+						requireNext(t, db, iter, i)
+					}
+					_, err = iter.Next()
+					require.ErrorIs(t, err, io.EOF)
+
+					iter, err = db.IterateRange(7, 11)
+					require.NoError(t, err)
+					for i := 7; i <= 11; i++ {
+						requireNext(t, db, iter, i)
+					}
+					_, err = iter.Next()
+					require.ErrorIs(t, err, io.EOF)
+				})
+		})
+	}
+
+	t.Run("ResumeAfterFuture", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				addBlocks(t, db, 3)
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				iter, err := db.IterateRange(3, 5)
+				require.NoError(t, err)
+				requireNext(t, db, iter, 3)
+				_, err = iter.Next()
+				require.ErrorIs(t, err, ErrFuture)
+				addLogs(t, db, 4)
+				_, err = iter.Next()
+				require.ErrorIs(t, err, ErrFuture, "block is not sealed yet")
+				sealBlock(t, db, 4)
+				requireNext(t, db, iter, 4)
+			})
+	})
+
+	t.Run("ErrorWhenParentUnknown", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				addBlocks(t, db, 3)
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, err := db.IterateRange(5, 6)
+				require.ErrorIs(t, err, ErrFuture)
+			})
+	})
+
+	t.Run("ErrorWhenInvalidRange", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				addBlocks(t, db, 3)
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, err := db.IterateRange(2, 1)
+				require.ErrorContains(t, err, "invalid range")
+			})
+	})
+}
+
+func TestFindSealedBlockByTimestamp(t *testing.T) {
+	// blocks 10 up to and including 40, at two seconds apart, with a varying number of logs
+	addBlocks := func(t *testing.T, db *DB) {
+		for i := 10; i <= 40; i++ {
+			if i > 10 {
+				parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+				for j := 0; j < i%5; j++ {
+					require.NoError(t, db.AddLog(createHash(100*i+j), parent, uint32(j), nil))
+				}
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 1000+2*uint64(i)))
+		}
+	}
+	for _, opts := range []Options{{SearchCheckpointFrequency: 16}, DefaultOptions()} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d", opts.SearchCheckpointFrequency), func(t *testing.T) {
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					addBlocks(t, db)
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 10; i <= 40; i++ {
+						expected := eth.BlockID{Hash: createTruncatedHash(i), Number: uint64(i)}
+						block, blockTime, err := db.FindSealedBlockByTimestamp(1000 + 2*uint64(i))
+						require.NoError(t, err)
+						require.Equal(t, expected, block)
+						require.Equal(t, 1000+2*uint64(i), blockTime)
+						require.LessOrEqual(t, m.entriesReadForSearch, int64(db.checkpointFrequency*2), "should not walk all blocks")
+
+						if i < 40 {
+							// in between two blocks
+							block, _, err = db.FindSealedBlockByTimestamp(1000 + 2*uint64(i) + 1)
+							require.NoError(t, err)
+							require.Equal(t, expected, block)
+						}
+					}
+					_, _, err := db.FindSealedBlockByTimestamp(1000 + 2*10 - 1)
+					require.ErrorIs(t, err, ErrSkipped)
+					_, _, err = db.FindSealedBlockByTimestamp(1000 + 2*40 + 1)
+					require.ErrorIs(t, err, ErrFuture)
+				})
+		})
+	}
+
+	t.Run("ErrFutureWhenEmpty", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, _, err := db.FindSealedBlockByTimestamp(1000)
+				require.ErrorIs(t, err, ErrFuture)
+			})
+	})
+}
+
+func TestSealedBlockAt(t *testing.T) {
+	for _, opts := range []Options{{SearchCheckpointFrequency: 16}, DefaultOptions()} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d", opts.SearchCheckpointFrequency), func(t *testing.T) {
+			// the next entry index after the seal of each block
+			sealed := make(map[int]entrydb.EntryIdx)
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 10; i <= 30; i++ {
+						if i > 10 {
+							parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+							for j := 0; j < i%5; j++ {
+								require.NoError(t, db.AddLog(createHash(100*i+j), parent, uint32(j), nil))
+							}
+						}
+						require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 1000+2*uint64(i)))
+						sealed[i] = db.NextIndex()
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 10; i <= 30; i++ {
+						block, blockTime, err := db.SealedBlockAt(sealed[i])
+						require.NoError(t, err)
+						require.Equal(t, eth.BlockID{Hash: createTruncatedHash(i), Number: uint64(i)}, block)
+						require.Equal(t, 1000+2*uint64(i), blockTime)
+					}
+					_, _, err := db.SealedBlockAt(0)
+					require.ErrorIs(t, err, ErrFuture)
+					_, _, err = db.SealedBlockAt(db.NextIndex() + 1)
+					require.ErrorIs(t, err, ErrFuture)
+				})
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: 16}
+	// addBlocks adds the given blocks, with 2 logs each
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			if i > 0 {
+				parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+				for j := 0; j < 2; j++ {
+					require.NoError(t, db.AddLog(createHash(100*i+j), parent, uint32(j), nil))
+				}
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+	}
+	open := func(t *testing.T, path string) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.SegmentConfig{Size: 50}, DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+	requireBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)})
+			require.NoError(t, err)
+			if i > 0 {
+				requireContains(t, db, uint64(i), 1, createHash(100*i+1))
+			}
+		}
+	}
+	// prune prunes the entries before the given block, as if it was finalized
+	prune := func(t *testing.T, db *DB, finalized int) {
+		next, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(finalized), Number: uint64(finalized)})
+		require.NoError(t, err)
+		require.NoError(t, db.Prune(next))
+	}
+
+	t.Run("PruneBelowFinalized", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 100)
+		prune(t, db, 80)
+		require.Positive(t, db.store.FirstEntryIdx(), "should have pruned entries")
+		requireBlocks(t, db, 80, 100)
+		_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(10), Number: 10})
+		require.ErrorIs(t, err, ErrSkipped)
+		_, err = db.Contains(11, 0, createHash(1100))
+		require.ErrorIs(t, err, ErrSkipped)
+		_, _, err = db.FindSealedBlockByTimestamp(510)
+		require.ErrorIs(t, err, ErrSkipped)
+
+		// blocks after the finalized block can still be rewound and replaced
+		require.NoError(t, db.Rewind(90))
+		addBlocks(t, db, 91, 120)
+		requireBlocks(t, db, 80, 120)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireBlocks(t, db, 80, 120)
+	})
+
+	t.Run("RebuildBloomFilters", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 100)
+		prune(t, db, 80)
+		require.NoError(t, db.Close())
+		require.NoError(t, os.Remove(path+".bloom"))
+
+		db = open(t, path)
+		defer db.Close()
+		requireBlocks(t, db, 80, 100)
+		_, err := db.Contains(90, 0, createHash(7777))
+		require.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("NothingToPrune", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		defer db.Close()
+		addBlocks(t, db, 0, 100)
+		require.NoError(t, db.Prune(0))
+		prune(t, db, 3)
+		require.Zero(t, db.store.FirstEntryIdx())
+		requireBlocks(t, db, 0, 100)
+	})
+}
+
+func TestL1Blocks(t *testing.T) {
+	// l1 returns the hash of the L1 block with the given number, before (fork 0) or after (fork 1) an L1 reorg
+	l1 := func(fork int, n uint64) eth.BlockID {
+		return eth.BlockID{Hash: createHash(1000*(fork+1) + int(n)), Number: n}
+	}
+	stored := func(id eth.BlockID) eth.BlockID {
+		return eth.BlockID{Hash: truncateL1Hash(id.Hash), Number: id.Number}
+	}
+	// registrations of each L2 block: L1 blocks 0 to 5, then a reorg of L1 blocks 3 onwards, skipping L1 block 6
+	registrations := map[int]eth.BlockID{
+		0: l1(0, 0), 2: l1(0, 1), 4: l1(0, 2), 6: l1(0, 3), 8: l1(0, 4), 10: l1(0, 5),
+		12: l1(1, 3), 14: l1(1, 4), 16: l1(1, 5), 18: l1(1, 7),
+	}
+	for _, opts := range []Options{
+		{SearchCheckpointFrequency: minSearchCheckpointFrequency},
+		{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true},
+		DefaultOptions(),
+	} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d-FullHashes%v", opts.SearchCheckpointFrequency, opts.FullHashes), func(t *testing.T) {
+			// index after the entries of each block, including the L1 block registered with it
+			var blockEnds []entrydb.EntryIdx
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					blockEnds = nil
+					for i := 0; i < 20; i++ {
+						bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+						require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+						// register before, in between, or after the logs that follow the block
+						for j := 0; j < i%3; j++ {
+							if id, ok := registrations[i]; ok && j == i%4 {
+								require.NoError(t, db.AddL1Block(id))
+							}
+							require.NoError(t, db.AddLog(createHash(j), bl, uint32(j), nil))
+						}
+						if id, ok := registrations[i]; ok && i%4 >= i%3 {
+							require.NoError(t, db.AddL1Block(id))
+						}
+						blockEnds = append(blockEnds, db.NextIndex())
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					var last eth.BlockID
+					for i, end := range blockEnds {
+						if id, ok := registrations[i]; ok {
+							last = id
+						}
+						known, err := db.L1BlockAt(end)
+						require.NoError(t, err)
+						require.Equal(t, stored(last), known, "L1 block known at block %d", i)
+					}
+					_, err := db.L1BlockAt(db.NextIndex() + 1)
+					require.ErrorIs(t, err, ErrFuture)
+
+					for _, id := range []eth.BlockID{l1(0, 0), l1(0, 1), l1(0, 2), l1(1, 3), l1(1, 4), l1(1, 5), l1(1, 7)} {
+						idx, err := db.ContainsL1Block(id)
+						require.NoError(t, err, "L1 block %d", id.Number)
+						registered, err := db.L1BlockAt(idx + 1)
+						require.NoError(t, err)
+						require.Equal(t, stored(id), registered)
+					}
+					_, err = db.ContainsL1Block(l1(0, 3))
+					require.ErrorIs(t, err, ErrConflict, "should be replaced by the L1 reorg")
+					_, err = db.ContainsL1Block(l1(0, 5))
+					require.ErrorIs(t, err, ErrConflict, "should be replaced by the L1 reorg")
+					_, err = db.ContainsL1Block(l1(1, 6))
+					require.ErrorIs(t, err, ErrSkipped)
+					_, err = db.ContainsL1Block(l1(1, 8))
+					require.ErrorIs(t, err, ErrFuture)
+				})
+		})
+	}
+
+	t.Run("NoneRegistered", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+				require.NoError(t, db.AddLog(createHash(1), eth.BlockID{Hash: createHash(0), Number: 0}, 0, nil))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, err := db.L1BlockAt(db.NextIndex())
+				require.ErrorIs(t, err, ErrFuture)
+				_, err = db.ContainsL1Block(l1(0, 0))
+				require.ErrorIs(t, err, ErrFuture)
+			})
+	})
+
+	t.Run("ErrorWhenNoBlockSealed", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				require.ErrorIs(t, db.AddL1Block(l1(0, 0)), ErrFuture)
+				require.EqualValues(t, 0, db.NextIndex())
+			})
+	})
+}
+
+func TestDepositEvents(t *testing.T) {
+	// isDeposit returns true if log j of block i is emitted by a deposit transaction
+	isDeposit := func(i, j int) bool {
+		return (i+j)%2 == 0
+	}
+	for _, opts := range []Options{
+		{SearchCheckpointFrequency: minSearchCheckpointFrequency},
+		{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true},
+		DefaultOptions(),
+	} {
+		opts := opts
+		t.Run(fmt.Sprintf("Frequency%d-FullHashes%v", opts.SearchCheckpointFrequency, opts.FullHashes), func(t *testing.T) {
+			runDBTestWithOptions(t, opts,
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					require.NoError(t, db.SealBlock(common.Hash{}, eth.BlockID{Hash: createHash(0), Number: 0}, 500))
+					for i := 1; i < 20; i++ {
+						parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+						require.NoError(t, db.WriteBatch(func(b Batch) error {
+							for j := 0; j < 3; j++ {
+								var err error
+								if isDeposit(i, j) {
+									err = b.AddDepositEvent(createHash(100*i+j), parent, uint32(j))
+								} else {
+									err = b.AddLog(createHash(100*i+j), parent, uint32(j), nil)
+								}
+								if err != nil {
+									return err
+								}
+							}
+							return b.SealBlock(parent.Hash, eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i))
+						}))
+					}
+				},
+				func(t *testing.T, db *DB, m *stubMetrics) {
+					for i := 1; i < 20; i++ {
+						for j := 0; j < 3; j++ {
+							requireContains(t, db, uint64(i), uint32(j), createHash(100*i+j))
+							_, err := db.ContainsDeposit(uint64(i), uint32(j), createHash(100*i+j))
+							if isDeposit(i, j) {
+								require.NoError(t, err)
+							} else {
+								require.ErrorIs(t, err, ErrConflict)
+							}
+						}
+					}
+					_, err := db.ContainsDeposit(2, 3, createHash(203))
+					require.ErrorIs(t, err, ErrConflict, "block does not have as many logs")
+					_, err = db.ContainsDeposit(2, 0, createHash(201))
+					require.ErrorContains(t, err, "payload hash mismatch", "hash of other log")
+					_, err = db.ContainsDeposit(20, 0, createHash(2000))
+					require.ErrorIs(t, err, ErrFuture)
+
+					iter, err := db.IterateRange(1, 19)
+					require.NoError(t, err)
+					for i := 1; i < 20; i++ {
+						block, err := iter.Next()
+						require.NoError(t, err)
+						require.Len(t, block.Logs, 3)
+						for j, l := range block.Logs {
+							require.Equal(t, isDeposit(i, j), l.Deposit, "log %d of block %d", j, i)
+							require.Empty(t, l.ExecMsgs)
+						}
+					}
+				})
+		})
+	}
+
+	t.Run("RejectOutOfOrder", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				bl0 := eth.BlockID{Hash: createHash(0), Number: 0}
+				require.NoError(t, db.SealBlock(common.Hash{}, bl0, 500))
+				require.NoError(t, db.AddDepositEvent(createHash(1), bl0, 0))
+				require.ErrorIs(t, db.AddDepositEvent(createHash(2), bl0, 2), ErrLogOutOfOrder)
+				require.ErrorIs(t, db.AddDepositEvent(createHash(2), eth.BlockID{Hash: createHash(1), Number: 1}, 1), ErrLogOutOfOrder)
+				require.ErrorIs(t, db.AddDepositEvent(createHash(2), eth.BlockID{}, 1), ErrLogOutOfOrder)
+				require.NoError(t, db.AddLog(createHash(2), bl0, 1, nil))
+				require.NoError(t, db.SealBlock(bl0.Hash, eth.BlockID{Hash: createHash(1), Number: 1}, 501))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				_, err := db.ContainsDeposit(1, 0, createHash(1))
+				require.NoError(t, err)
+				requireContains(t, db, 1, 1, createHash(2))
+			})
+	})
+}
+
+func TestForkLogContext(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true}
+	bl0 := eth.BlockID{Hash: createHash(0), Number: 0}
+	bl1 := eth.BlockID{Hash: createHash(1), Number: 1}
+	bl2 := eth.BlockID{Hash: createHash(2), Number: 2}
+	execMsg := types.ExecutingMessage{Chain: 4, BlockNum: 10, LogIdx: 3, Timestamp: 100, Hash: createHash(30)}
+	runDBTestWithOptions(t, opts,
+		func(t *testing.T, db *DB, m *stubMetrics) {
+			require.NoError(t, db.SealBlock(common.Hash{}, bl0, 500))
+			require.NoError(t, db.AddLog(createHash(1), bl0, 0, nil))
+			require.NoError(t, db.SealBlock(bl0.Hash, bl1, 501))
+		},
+		func(t *testing.T, db *DB, m *stubMetrics) {
+			idx := db.NextIndex()
+			fork := db.lastEntryContext.Fork()
+			require.NoError(t, fork.ApplyLog(bl1, 0, createHash(10), []types.ExecutingMessage{execMsg}))
+			require.NoError(t, fork.SealBlock(bl1.Hash, bl2, 502))
+			hash, num, ok := fork.SealedBlock()
+			require.True(t, ok)
+			require.Equal(t, bl2, eth.BlockID{Hash: hash, Number: num})
+			require.Greater(t, fork.NextIndex(), idx)
+			require.NotEmpty(t, fork.out)
+
+			// the state of the DB is not affected
+			hash, num, ok = db.lastEntryContext.SealedBlock()
+			require.True(t, ok)
+			require.Equal(t, bl1, eth.BlockID{Hash: hash, Number: num})
+			require.Equal(t, idx, db.NextIndex())
+			require.Empty(t, db.lastEntryContext.out)
+
+			fork.Discard()
+			hash, num, ok = fork.SealedBlock()
+			require.True(t, ok)
+			require.Equal(t, bl1, eth.BlockID{Hash: hash, Number: num})
+			require.Equal(t, idx, fork.NextIndex())
+			require.Empty(t, fork.out)
+
+			// another candidate can be applied to the fork, and to a fork of the fork
+			require.NoError(t, fork.ApplyLog(bl1, 0, createHash(11), nil))
+			nested := fork.Fork()
+			require.NoError(t, nested.ApplyLog(bl1, 1, createHash(12), []types.ExecutingMessage{execMsg}))
+			require.Equal(t, []types.ExecutingMessage{execMsg}, nested.ExecMessages())
+			nested.Discard()
+			logHash, logIdx, ok := nested.InitMessage()
+			require.True(t, ok)
+			require.Equal(t, createHash(11), logHash)
+			require.EqualValues(t, 0, logIdx)
+			require.Empty(t, nested.ExecMessages())
+			fork.Discard()
+			_, _, ok = fork.InitMessage()
+			require.False(t, ok)
+
+			require.Panics(t, func() { db.lastEntryContext.Discard() }, "should only discard forks")
+
+			// the DB can still be written to
+			require.NoError(t, db.AddLog(createHash(20), bl1, 0, nil))
+			require.NoError(t, db.SealBlock(bl1.Hash, bl2, 502))
+			requireContains(t, db, 2, 0, createHash(20))
+		})
+}
+
 func TestGetBlockInfo(t *testing.T) {
 	t.Run("ReturnsErrFutureWhenEmpty", func(t *testing.T) {
 		runDBTest(t,
@@ -653,36 +1718,43 @@ func TestGetBlockInfo(t *testing.T) {
 	})
 }
 
-func requireContains(t *testing.T, db *DB, blockNum uint64, logIdx uint32, logHash common.Hash, execMsg ...types.ExecutingMessage) {
-	require.LessOrEqual(t, len(execMsg), 1, "cannot have multiple executing messages for a single log")
+func requireContains(t *testing.T, db *DB, blockNum uint64, logIdx uint32, logHash common.Hash, execMsgs ...types.ExecutingMessage) {
 	m, ok := db.m.(*stubMetrics)
 	require.True(t, ok, "Did not get the expected metrics type")
-	_, err := db.Contains(blockNum, logIdx, types.TruncateHash(logHash))
+	_, err := db.Contains(blockNum, logIdx, logHash)
 	require.NoErrorf(t, err, "Error searching for log %v in block %v", logIdx, blockNum)
-	require.LessOrEqual(t, m.entriesReadForSearch, int64(searchCheckpointFrequency*2), "Should not need to read more than between two checkpoints")
+	require.LessOrEqual(t, m.entriesReadForSearch, int64(db.checkpointFrequency*2), "Should not need to read more than between two checkpoints")
 	require.NotZero(t, m.entriesReadForSearch, "Must read at least some entries to find the log")
 
 	var expectedExecMsg types.ExecutingMessage
-	if len(execMsg) == 1 {
-		expectedExecMsg = execMsg[0]
+	if len(execMsgs) > 0 {
+		expectedExecMsg = execMsgs[0]
 	}
 	requireExecutingMessage(t, db, blockNum, logIdx, expectedExecMsg)
+
+	_, iter, err := db.findLogInfo(blockNum, logIdx)
+	require.NoError(t, err)
+	if len(execMsgs) == 0 {
+		require.Empty(t, iter.ExecMessages())
+	} else {
+		require.Equal(t, execMsgs, iter.ExecMessages(), "Should return all matching executing messages")
+	}
 }
 
 func requireConflicts(t *testing.T, db *DB, blockNum uint64, logIdx uint32, logHash common.Hash) {
 	m, ok := db.m.(*stubMetrics)
 	require.True(t, ok, "Did not get the expected metrics type")
-	_, err := db.Contains(blockNum, logIdx, types.TruncateHash(logHash))
+	_, err := db.Contains(blockNum, logIdx, logHash)
 	require.ErrorIs(t, err, ErrConflict, "canonical chain must not include this log")
-	require.LessOrEqual(t, m.entriesReadForSearch, int64(searchCheckpointFrequency*2), "Should not need to read more than between two checkpoints")
+	require.LessOrEqual(t, m.entriesReadForSearch, int64(db.checkpointFrequency*2), "Should not need to read more than between two checkpoints")
 }
 
 func requireFuture(t *testing.T, db *DB, blockNum uint64, logIdx uint32, logHash common.Hash) {
 	m, ok := db.m.(*stubMetrics)
 	require.True(t, ok, "Did not get the expected metrics type")
-	_, err := db.Contains(blockNum, logIdx, types.TruncateHash(logHash))
+	_, err := db.Contains(blockNum, logIdx, logHash)
 	require.ErrorIs(t, err, ErrFuture, "canonical chain does not yet include this log")
-	require.LessOrEqual(t, m.entriesReadForSearch, int64(searchCheckpointFrequency*2), "Should not need to read more than between two checkpoints")
+	require.LessOrEqual(t, m.entriesReadForSearch, int64(db.checkpointFrequency*2), "Should not need to read more than between two checkpoints")
 }
 
 func requireExecutingMessage(t *testing.T, db *DB, blockNum uint64, logIdx uint32, execMsg types.ExecutingMessage) {
@@ -697,15 +1769,50 @@ func requireExecutingMessage(t *testing.T, db *DB, blockNum uint64, logIdx uint3
 		require.NotNil(t, actualExecMsg)
 		require.Equal(t, execMsg, *actualExecMsg, "Should return matching executing message")
 	}
-	require.LessOrEqual(t, m.entriesReadForSearch, int64(searchCheckpointFrequency*2), "Should not need to read more than between two checkpoints")
+	require.LessOrEqual(t, m.entriesReadForSearch, int64(db.checkpointFrequency*2), "Should not need to read more than between two checkpoints")
 	require.NotZero(t, m.entriesReadForSearch, "Must read at least some entries to find the log")
 }
 
+// TestCorruption checks that corrupted entries on disk are reported as such,
+// rather than as data that conflicts with the canonical chain.
+func TestCorruption(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	path := filepath.Join(t.TempDir(), "test.db")
+	opts := Options{SearchCheckpointFrequency: 8}
+	db, err := NewFromFile(logger, &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		bl := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+		require.NoError(t, db.SealBlock(createHash(i-1), bl, 500+uint64(i)))
+		if i > 0 {
+			require.NoError(t, db.AddLog(createTruncatedHash(i), bl, 0, nil))
+		}
+	}
+	require.NoError(t, db.Close())
+
+	// Entries 0, 1: block 0. Entries 2, 3: block 1. Entry 4: the log after block 1.
+	segmentPath := entrydb.SegmentPath(path, 0)
+	data, err := os.ReadFile(segmentPath)
+	require.NoError(t, err)
+	data[entrydb.HeaderSize+4*(entrydb.EntrySize+entrydb.ChecksumSize)+10] ^= 0x01
+	require.NoError(t, os.WriteFile(segmentPath, data, 0o644))
+
+	db, err = NewFromFile(logger, &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Contains(2, 0, createTruncatedHash(1))
+	require.ErrorIs(t, err, entrydb.ErrCorrupted)
+	require.NotErrorIs(t, err, ErrConflict)
+	require.ErrorContains(t, err, "entry 4")
+	// data in other checkpoint intervals is unaffected
+	requireContains(t, db, 9, 0, createHash(8))
+}
+
 func TestRecoverOnCreate(t *testing.T) {
 	createDb := func(t *testing.T, store *stubEntryStore) (*DB, *stubMetrics, error) {
 		logger := testlog.Logger(t, log.LvlInfo)
 		m := &stubMetrics{}
-		db, err := NewFromEntryStore(logger, m, store, true)
+		db, err := NewFromEntryStore(logger, m, store, true, DefaultOptions())
 		return db, m, err
 	}
 
@@ -726,7 +1833,7 @@ func TestRecoverOnCreate(t *testing.T) {
 			newSearchCheckpoint(3, 0, 103).encode(),
 			newCanonicalHash(createTruncatedHash(303)).encode(),
 			// open and seal 4
-			newInitiatingEvent(createTruncatedHash(1), false).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
 			newSearchCheckpoint(4, 0, 104).encode(),
 			newCanonicalHash(createTruncatedHash(304)).encode(),
 		)
@@ -744,7 +1851,7 @@ func TestRecoverOnCreate(t *testing.T) {
 			Timestamp: 1288,
 			Hash:      createTruncatedHash(4),
 		}
-		linkEvt, err := newExecutingLink(execMsg)
+		linkEvt, err := newExecutingLink(execMsg, false)
 		require.NoError(t, err)
 		store := storeWithEvents(
 			newSearchCheckpoint(0, 0, 100).encode(),
@@ -753,7 +1860,7 @@ func TestRecoverOnCreate(t *testing.T) {
 			newCanonicalHash(createTruncatedHash(301)).encode(),
 			newSearchCheckpoint(2, 0, 102).encode(),
 			newCanonicalHash(createTruncatedHash(302)).encode(),
-			newInitiatingEvent(createTruncatedHash(1111), true).encode(),
+			newInitiatingEvent(createTruncatedHash(1111), 1).encode(),
 			linkEvt.encode(),
 			newExecutingCheck(execMsg.Hash).encode(),
 			newSearchCheckpoint(3, 0, 103).encode(),
@@ -785,6 +1892,28 @@ func TestRecoverOnCreate(t *testing.T) {
 		require.EqualValues(t, int64(2), m.entryCount)
 	})
 
+	t.Run("TruncateWhenLastEntryCanonicalHashWithoutExtension", func(t *testing.T) {
+		// With full hashes, the seal is only complete once the hash extension of the canonical hash is written.
+		store := storeWithEvents(
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createHash(300)).encode(),
+			newHashExtension(createHash(300)).encode(),
+			newInitiatingEvent(createHash(1), 0).encode(),
+			newHashExtension(createHash(1)).encode(),
+			newSearchCheckpoint(1, 0, 101).encode(),
+			newCanonicalHash(createHash(301)).encode(),
+		)
+		logger := testlog.Logger(t, log.LvlInfo)
+		m := &stubMetrics{}
+		db, err := NewFromEntryStore(logger, m, store, true, Options{SearchCheckpointFrequency: defaultSearchCheckpointFrequency, FullHashes: true})
+		require.NoError(t, err)
+		require.EqualValues(t, int64(3), m.entryCount)
+		h, n, ok := db.lastEntryContext.SealedBlock()
+		require.True(t, ok)
+		require.Equal(t, createHash(300), h)
+		require.Zero(t, n)
+	})
+
 	t.Run("TruncateWhenLastEntryInitEventWithExecMsg", func(t *testing.T) {
 		// An initiating event that claims an executing message,
 		// without said executing message, is dropped.
@@ -792,8 +1921,8 @@ func TestRecoverOnCreate(t *testing.T) {
 			newSearchCheckpoint(0, 0, 100).encode(),
 			newCanonicalHash(createTruncatedHash(344)).encode(),
 			// both pruned because we go back to a seal
-			newInitiatingEvent(createTruncatedHash(0), false).encode(),
-			newInitiatingEvent(createTruncatedHash(1), true).encode(),
+			newInitiatingEvent(createTruncatedHash(0), 0).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 1).encode(),
 		)
 		_, m, err := createDb(t, store)
 		require.NoError(t, err)
@@ -807,7 +1936,7 @@ func TestRecoverOnCreate(t *testing.T) {
 			newSearchCheckpoint(0, 0, 100).encode(),
 			newCanonicalHash(createTruncatedHash(300)).encode(),
 			// pruned because we go back to a seal
-			newInitiatingEvent(createTruncatedHash(0), false).encode(),
+			newInitiatingEvent(createTruncatedHash(0), 0).encode(),
 			newSearchCheckpoint(1, 0, 100).encode(),
 			newCanonicalHash(createTruncatedHash(301)).encode(),
 		)
@@ -824,12 +1953,12 @@ func TestRecoverOnCreate(t *testing.T) {
 			Timestamp: 1288,
 			Hash:      createTruncatedHash(4),
 		}
-		linkEvt, err := newExecutingLink(execMsg)
+		linkEvt, err := newExecutingLink(execMsg, false)
 		require.NoError(t, err)
 		store := storeWithEvents(
 			newSearchCheckpoint(3, 0, 100).encode(),
 			newCanonicalHash(createTruncatedHash(344)).encode(),
-			newInitiatingEvent(createTruncatedHash(1), true).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 1).encode(),
 			linkEvt.encode(),
 		)
 		_, m, err := createDb(t, store)
@@ -838,6 +1967,145 @@ func TestRecoverOnCreate(t *testing.T) {
 	})
 }
 
+func TestRecoverIncompleteWrites(t *testing.T) {
+	createDb := func(t *testing.T, store *stubEntryStore, opts Options) (*DB, *stubMetrics) {
+		m := &stubMetrics{}
+		db, err := NewFromEntryStore(testlog.Logger(t, log.LvlInfo), m, store, false, opts)
+		require.NoError(t, err)
+		return db, m
+	}
+	execMsg := types.ExecutingMessage{Chain: 4, BlockNum: 10, LogIdx: 4, Timestamp: 1288, Hash: createTruncatedHash(4)}
+	linkEvt, err := newExecutingLink(execMsg, false)
+	require.NoError(t, err)
+
+	t.Run("IncompleteLog", func(t *testing.T) {
+		// The complete log after the last seal is kept, the log without its executing check is removed.
+		store := &stubEntryStore{entries: []entrydb.Entry{
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createTruncatedHash(300)).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
+			newInitiatingEvent(createTruncatedHash(2), 1).encode(),
+			linkEvt.encode(),
+		}}
+		db, m := createDb(t, store, DefaultOptions())
+		require.EqualValues(t, 3, m.entryCount)
+		require.EqualValues(t, 1, db.lastEntryContext.logsSince)
+		block0 := eth.BlockID{Hash: createTruncatedHash(300), Number: 0}
+		require.NoError(t, db.AddLog(createTruncatedHash(2), block0, 1, &execMsg))
+		require.NoError(t, db.SealBlock(block0.Hash, eth.BlockID{Hash: createTruncatedHash(301), Number: 1}, 101))
+		requireContains(t, db, 1, 0, createHash(1))
+		requireContains(t, db, 1, 1, createHash(2), execMsg)
+		require.NoError(t, db.Verify())
+	})
+
+	t.Run("IncompleteHashExtension", func(t *testing.T) {
+		store := &stubEntryStore{entries: []entrydb.Entry{
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createHash(300)).encode(),
+			newHashExtension(createHash(300)).encode(),
+			newInitiatingEvent(createHash(1), 0).encode(),
+		}}
+		db, m := createDb(t, store, Options{SearchCheckpointFrequency: defaultSearchCheckpointFrequency, FullHashes: true})
+		require.EqualValues(t, 3, m.entryCount)
+		require.Zero(t, db.lastEntryContext.logsSince)
+		require.NoError(t, db.Verify())
+	})
+
+	t.Run("IncompleteSeal", func(t *testing.T) {
+		// The search checkpoint of a seal without its canonical hash is removed, with the logs before it kept.
+		store := &stubEntryStore{entries: []entrydb.Entry{
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createTruncatedHash(300)).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
+			newSearchCheckpoint(1, 0, 101).encode(),
+		}}
+		db, m := createDb(t, store, DefaultOptions())
+		require.EqualValues(t, 3, m.entryCount)
+		n, ok := db.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.Zero(t, n)
+		require.NoError(t, db.Verify())
+	})
+
+	t.Run("IncompleteCheckpointInterval", func(t *testing.T) {
+		// The incomplete seal starts a new checkpoint interval, so the state is replayed from the interval before.
+		store := &stubEntryStore{entries: []entrydb.Entry{
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createTruncatedHash(300)).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
+			newInitiatingEvent(createTruncatedHash(2), 0).encode(),
+			newInitiatingEvent(createTruncatedHash(3), 0).encode(),
+			newSearchCheckpoint(1, 0, 101).encode(),
+		}}
+		db, m := createDb(t, store, Options{SearchCheckpointFrequency: minSearchCheckpointFrequency})
+		require.EqualValues(t, 5, m.entryCount)
+		require.EqualValues(t, 3, db.lastEntryContext.logsSince)
+		require.NoError(t, db.Verify())
+	})
+
+	t.Run("CorruptedEntry", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		for i := 0; i < 4; i++ {
+			if i > 0 {
+				require.NoError(t, db.AddLog(createTruncatedHash(100+i), eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}, 0, nil))
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+		// The last entry is the canonical hash of block 3, which is corrupted as if it was only partially written.
+		last := db.lastEntryIdx()
+		require.NoError(t, db.Close())
+		segmentPath := entrydb.SegmentPath(path, 0)
+		data, err := os.ReadFile(segmentPath)
+		require.NoError(t, err)
+		data[entrydb.HeaderSize+int(last)*(entrydb.EntrySize+entrydb.ChecksumSize)+10] ^= 0x01
+		require.NoError(t, os.WriteFile(segmentPath, data, 0o644))
+
+		db, err = NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		require.EqualValues(t, last-2, db.lastEntryIdx(), "should remove the incomplete seal of block 3")
+		n, ok := db.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.EqualValues(t, 2, n)
+		require.NoError(t, db.SealBlock(createHash(2), eth.BlockID{Hash: createHash(3), Number: 3}, 503))
+		requireContains(t, db, 3, 0, createHash(103))
+		require.NoError(t, db.Verify())
+	})
+
+	t.Run("CorruptedEntryBeforeIntactEntry", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		for i := 0; i < 4; i++ {
+			if i > 0 {
+				require.NoError(t, db.AddLog(createTruncatedHash(100+i), eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}, 0, nil))
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+		// The search checkpoint of block 3 is corrupted, but the canonical hash after it is intact,
+		// so the corruption was not caused by a partial write, and the data must not be trimmed.
+		last := db.lastEntryIdx()
+		require.NoError(t, db.Close())
+		segmentPath := entrydb.SegmentPath(path, 0)
+		data, err := os.ReadFile(segmentPath)
+		require.NoError(t, err)
+		data[entrydb.HeaderSize+int(last-1)*(entrydb.EntrySize+entrydb.ChecksumSize)+10] ^= 0x01
+		require.NoError(t, os.WriteFile(segmentPath, data, 0o644))
+		// replay the tail, as after an unclean shutdown
+		require.NoError(t, os.Remove(path+".state"))
+
+		_, err = NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.ErrorIs(t, err, ErrDataCorruption)
+		info, err := os.Stat(segmentPath)
+		require.NoError(t, err)
+		require.EqualValues(t, len(data), info.Size(), "should not trim the data")
+	})
+}
+
 func TestRewind(t *testing.T) {
 	t.Run("WhenEmpty", func(t *testing.T) {
 		runDBTest(t, func(t *testing.T, db *DB, m *stubMetrics) {},
@@ -917,24 +2185,24 @@ func TestRewind(t *testing.T) {
 			func(t *testing.T, db *DB, m *stubMetrics) {
 				bl50 := eth.BlockID{Hash: createHash(50), Number: 50}
 				require.NoError(t, db.SealBlock(createHash(49), bl50, 500))
-				for i := uint32(0); m.entryCount < searchCheckpointFrequency; i++ {
+				for i := uint32(0); m.entryCount < defaultSearchCheckpointFrequency; i++ {
 					require.NoError(t, db.AddLog(createTruncatedHash(1), bl50, i, nil))
 				}
 				// The checkpoint is added automatically,
 				// it will be there as soon as it reaches 255 with log events.
 				// Thus add 2 for the checkpoint.
-				require.EqualValues(t, searchCheckpointFrequency+2, m.entryCount)
+				require.EqualValues(t, defaultSearchCheckpointFrequency+2, m.entryCount)
 				bl51 := eth.BlockID{Hash: createHash(51), Number: 51}
 				require.NoError(t, db.SealBlock(bl50.Hash, bl51, 502))
 				require.NoError(t, db.AddLog(createTruncatedHash(1), bl51, 0, nil))
-				require.EqualValues(t, searchCheckpointFrequency+2+3, m.entryCount, "Should have inserted new checkpoint and extra log")
+				require.EqualValues(t, defaultSearchCheckpointFrequency+2+3, m.entryCount, "Should have inserted new checkpoint and extra log")
 				require.NoError(t, db.AddLog(createTruncatedHash(2), bl51, 1, nil))
 				bl52 := eth.BlockID{Hash: createHash(52), Number: 52}
 				require.NoError(t, db.SealBlock(bl51.Hash, bl52, 504))
 				require.NoError(t, db.Rewind(51))
 			},
 			func(t *testing.T, db *DB, m *stubMetrics) {
-				require.EqualValues(t, searchCheckpointFrequency+2+2, m.entryCount, "Should have deleted second checkpoint")
+				require.EqualValues(t, defaultSearchCheckpointFrequency+2+2, m.entryCount, "Should have deleted second checkpoint")
 				requireContains(t, db, 51, 0, createHash(1))
 				requireContains(t, db, 51, 1, createHash(1))
 				requireFuture(t, db, 52, 0, createHash(1))
@@ -1019,11 +2287,58 @@ func TestRewind(t *testing.T) {
 				requireContains(t, db, 17, 0, createHash(42))
 			})
 	})
+
+	t.Run("ReplaceReorgedBlocks", func(t *testing.T) {
+		runDBTest(t,
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				for i := uint32(0); i < 10; i++ {
+					bl := eth.BlockID{Hash: createHash(int(i)), Number: uint64(i)}
+					require.NoError(t, db.SealBlock(createHash(int(i)-1), bl, 500+uint64(i)))
+					require.NoError(t, db.AddLog(createTruncatedHash(1), bl, 0, nil))
+				}
+				bl5 := eth.BlockID{Hash: createHash(5), Number: 5}
+				next, err := db.FindSealedBlock(bl5)
+				require.NoError(t, err)
+				require.NoError(t, db.Rewind(5))
+				require.Equal(t, next, db.NextIndex(), "should truncate to the seal of the block")
+				// build an alternative chain on top of block 5
+				bl6 := eth.BlockID{Hash: createHash(106), Number: 6}
+				require.NoError(t, db.SealBlock(bl5.Hash, bl6, 506))
+				require.NoError(t, db.AddLog(createTruncatedHash(2), bl6, 0, nil))
+				bl7 := eth.BlockID{Hash: createHash(107), Number: 7}
+				require.NoError(t, db.SealBlock(bl6.Hash, bl7, 507))
+			},
+			func(t *testing.T, db *DB, m *stubMetrics) {
+				n, ok := db.LatestSealedBlockNum()
+				require.True(t, ok)
+				require.EqualValues(t, 7, n)
+				requireContains(t, db, 5, 0, createHash(1))
+				// the log after the seal of block 5 was removed, the new block 6 has no logs
+				requireConflicts(t, db, 6, 0, createHash(1))
+				requireContains(t, db, 7, 0, createHash(2))
+				_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(6), Number: 6})
+				require.ErrorIs(t, err, ErrConflict)
+				_, err = db.FindSealedBlock(eth.BlockID{Hash: createHash(107), Number: 7})
+				require.NoError(t, err)
+				requireFuture(t, db, 8, 0, createHash(1))
+			})
+	})
 }
 
 type stubMetrics struct {
 	entryCount           int64
 	entriesReadForSearch int64
+	entriesWritten       map[string]int64
+	paddingRatio         float64
+	searchCheckpoints    int64
+	repeatedCheckpoints  int64
+	blocksSealed         int64
+	flushes              int64
+	fileSize             int64
+	replayDone           int64
+	replayTotal          int64
+	opens                int64
+	restoredOpens        int64
 }
 
 func (s *stubMetrics) RecordDBEntryCount(count int64) {
@@ -1034,6 +2349,48 @@ func (s *stubMetrics) RecordDBSearchEntriesRead(count int64) {
 	s.entriesReadForSearch = count
 }
 
+func (s *stubMetrics) RecordDBEntriesWritten(entryType string, count int64) {
+	if s.entriesWritten == nil {
+		s.entriesWritten = make(map[string]int64)
+	}
+	s.entriesWritten[entryType] += count
+}
+
+func (s *stubMetrics) RecordDBPaddingRatio(ratio float64) {
+	s.paddingRatio = ratio
+}
+
+func (s *stubMetrics) RecordDBSearchCheckpoint(repeat bool) {
+	s.searchCheckpoints++
+	if repeat {
+		s.repeatedCheckpoints++
+	}
+}
+
+func (s *stubMetrics) RecordDBBlockSealed() {
+	s.blocksSealed++
+}
+
+func (s *stubMetrics) RecordDBFlush(duration time.Duration) {
+	s.flushes++
+}
+
+func (s *stubMetrics) RecordDBFileSize(size int64) {
+	s.fileSize = size
+}
+
+func (s *stubMetrics) RecordDBReplayProgress(done int64, total int64) {
+	s.replayDone = done
+	s.replayTotal = total
+}
+
+func (s *stubMetrics) RecordDBOpen(duration time.Duration, restored bool) {
+	s.opens++
+	if restored {
+		s.restoredOpens++
+	}
+}
+
 var _ Metrics = (*stubMetrics)(nil)
 
 type stubEntryStore struct {
@@ -1048,6 +2405,10 @@ func (s *stubEntryStore) LastEntryIdx() entrydb.EntryIdx {
 	return entrydb.EntryIdx(s.Size() - 1)
 }
 
+func (s *stubEntryStore) FirstEntryIdx() entrydb.EntryIdx {
+	return 0
+}
+
 func (s *stubEntryStore) Read(idx entrydb.EntryIdx) (entrydb.Entry, error) {
 	if idx < entrydb.EntryIdx(len(s.entries)) {
 		return s.entries[idx], nil
@@ -1065,6 +2426,10 @@ func (s *stubEntryStore) Truncate(idx entrydb.EntryIdx) error {
 	return nil
 }
 
+func (s *stubEntryStore) Prune(idx entrydb.EntryIdx) error {
+	return nil
+}
+
 func (s *stubEntryStore) Close() error {
 	return nil
 }
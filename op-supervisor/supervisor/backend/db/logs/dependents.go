@@ -0,0 +1,310 @@
+package logs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+const (
+	// dependentsHeaderSize is the size of the header of the dependents file: <uint64 number of covered entries: 8 bytes>
+	dependentsHeaderSize = 8
+	// dependentRecordSize is the size of a dependent as stored:
+	// <uint32 chain: 4 bytes><uint64 block number: 8 bytes><uint32 log index: 4 bytes>
+	// <uint64 executing block number: 8 bytes><uint32 executing log index: 4 bytes>
+	// <uint64 entry index: 8 bytes><checksum: 4 bytes>
+	dependentRecordSize = 40
+)
+
+// Dependent is a log of the DB with an executing message, that depends on the initiating log that the message references.
+type Dependent struct {
+	BlockNum uint64
+	LogIdx   uint32
+}
+
+// dependentKey identifies an initiating log, as referenced by executing messages.
+type dependentKey struct {
+	chain    uint32
+	blockNum uint64
+	logIdx   uint32
+}
+
+// dependentRecord is a dependent of an initiating log,
+// with the index of the last entry of the executing log, to remove the dependent when the entry is removed.
+type dependentRecord struct {
+	target    dependentKey
+	dependent Dependent
+	entryIdx  entrydb.EntryIdx
+}
+
+func (r dependentRecord) encode() []byte {
+	data := make([]byte, 0, dependentRecordSize)
+	data = binary.LittleEndian.AppendUint32(data, r.target.chain)
+	data = binary.LittleEndian.AppendUint64(data, r.target.blockNum)
+	data = binary.LittleEndian.AppendUint32(data, r.target.logIdx)
+	data = binary.LittleEndian.AppendUint64(data, r.dependent.BlockNum)
+	data = binary.LittleEndian.AppendUint32(data, r.dependent.LogIdx)
+	data = binary.LittleEndian.AppendUint64(data, uint64(r.entryIdx))
+	return binary.LittleEndian.AppendUint32(data, crc32.Checksum(data, bloomChecksumTable))
+}
+
+func decodeDependentRecord(data []byte) (dependentRecord, error) {
+	if binary.LittleEndian.Uint32(data[36:40]) != crc32.Checksum(data[:36], bloomChecksumTable) {
+		return dependentRecord{}, fmt.Errorf("%w: dependent checksum mismatch", ErrDataCorruption)
+	}
+	return dependentRecord{
+		target: dependentKey{
+			chain:    binary.LittleEndian.Uint32(data[0:4]),
+			blockNum: binary.LittleEndian.Uint64(data[4:12]),
+			logIdx:   binary.LittleEndian.Uint32(data[12:16]),
+		},
+		dependent: Dependent{
+			BlockNum: binary.LittleEndian.Uint64(data[16:24]),
+			LogIdx:   binary.LittleEndian.Uint32(data[24:28]),
+		},
+		entryIdx: entrydb.EntryIdx(binary.LittleEndian.Uint64(data[28:36])),
+	}, nil
+}
+
+// dependentsIndex maps the initiating logs that executing messages of the DB reference,
+// to the logs of the DB with these executing messages, such that the dependents of a log can be found without a scan.
+// The dependents are stored in a sidecar file next to the DB, and are held in memory.
+// The header of the file is the number of entries of the DB that are covered by the stored dependents.
+// The sidecar file is derived from the DB, and is made consistent with it whenever the state of the DB is initialized.
+type dependentsIndex struct {
+	log  log.Logger
+	path string
+	file *os.File
+	// covered is the number of entries of the DB whose executing messages are indexed
+	covered entrydb.EntryIdx
+	// records are the stored dependents, in the order of the entries of the executing logs
+	records  []dependentRecord
+	byTarget map[dependentKey][]Dependent
+}
+
+func openDependentsIndex(logger log.Logger, path string) (*dependentsIndex, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dependents at %v: %w", path, err)
+	}
+	d := &dependentsIndex{log: logger, path: path, file: file, byTarget: make(map[dependentKey][]Dependent)}
+	if err := d.load(); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to load dependents at %v: %w", path, err), file.Close())
+	}
+	return d, nil
+}
+
+// load reads the stored dependents.
+// Dependents after a corrupted or incomplete record are removed, and are indexed again when the index is synced.
+func (d *dependentsIndex) load() error {
+	data, err := io.ReadAll(d.file)
+	if err != nil {
+		return err
+	}
+	if len(data) < dependentsHeaderSize {
+		return d.truncate(0)
+	}
+	d.covered = entrydb.EntryIdx(binary.LittleEndian.Uint64(data[:dependentsHeaderSize]))
+	for offset := dependentsHeaderSize; offset+dependentRecordSize <= len(data); offset += dependentRecordSize {
+		r, err := decodeDependentRecord(data[offset : offset+dependentRecordSize])
+		if err != nil {
+			d.log.Warn("Ignoring corrupted dependents", "record", len(d.records), "err", err)
+			break
+		}
+		d.records = append(d.records, r)
+		d.byTarget[r.target] = append(d.byTarget[r.target], r.dependent)
+	}
+	// If a record is corrupted, only the entries up to the last valid record are known to be covered.
+	// An incomplete record at the end was written after the header, so it is not covered by the header.
+	if (len(data)-dependentsHeaderSize)/dependentRecordSize > len(d.records) {
+		last := entrydb.EntryIdx(0)
+		if len(d.records) > 0 {
+			last = d.records[len(d.records)-1].entryIdx + 1
+		}
+		d.covered = min(d.covered, last)
+	}
+	return d.truncate(d.covered)
+}
+
+// get returns the dependents of the given initiating log.
+func (d *dependentsIndex) get(key dependentKey) []Dependent {
+	return append([]Dependent(nil), d.byTarget[key]...)
+}
+
+// add stores the given dependent, of a log after the covered entries.
+func (d *dependentsIndex) add(r dependentRecord) error {
+	offset := dependentsHeaderSize + int64(len(d.records))*dependentRecordSize
+	if _, err := d.file.WriteAt(r.encode(), offset); err != nil {
+		return fmt.Errorf("failed to store dependent: %w", err)
+	}
+	d.records = append(d.records, r)
+	d.byTarget[r.target] = append(d.byTarget[r.target], r.dependent)
+	return nil
+}
+
+// setCovered stores the number of entries whose executing messages are indexed.
+func (d *dependentsIndex) setCovered(n entrydb.EntryIdx) error {
+	if _, err := d.file.WriteAt(binary.LittleEndian.AppendUint64(nil, uint64(n)), 0); err != nil {
+		return fmt.Errorf("failed to store covered entries of dependents: %w", err)
+	}
+	d.covered = n
+	return nil
+}
+
+// truncate removes the dependents of the entries at and after the given entry index.
+func (d *dependentsIndex) truncate(n entrydb.EntryIdx) error {
+	count := len(d.records)
+	for count > 0 && d.records[count-1].entryIdx >= n {
+		r := d.records[count-1]
+		deps := d.byTarget[r.target]
+		if len(deps) == 1 {
+			delete(d.byTarget, r.target)
+		} else {
+			d.byTarget[r.target] = deps[:len(deps)-1]
+		}
+		count--
+	}
+	d.records = d.records[:count]
+	if err := d.file.Truncate(dependentsHeaderSize + int64(count)*dependentRecordSize); err != nil {
+		return fmt.Errorf("failed to truncate dependents: %w", err)
+	}
+	return d.setCovered(min(d.covered, n))
+}
+
+// prune removes the dependents of the entries before the given entry index.
+// The remaining dependents are written to a new file, that replaces the sidecar file.
+func (d *dependentsIndex) prune(first entrydb.EntryIdx) error {
+	count := 0
+	for count < len(d.records) && d.records[count].entryIdx < first {
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	tmp, err := os.OpenFile(d.path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create pruned dependents: %w", err)
+	}
+	data := binary.LittleEndian.AppendUint64(make([]byte, 0, dependentsHeaderSize+(len(d.records)-count)*dependentRecordSize), uint64(d.covered))
+	for _, r := range d.records[count:] {
+		data = append(data, r.encode()...)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return errors.Join(fmt.Errorf("failed to write pruned dependents: %w", err), tmp.Close())
+	}
+	if err := tmp.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync pruned dependents: %w", err), tmp.Close())
+	}
+	if err := os.Rename(tmp.Name(), d.path); err != nil {
+		return errors.Join(fmt.Errorf("failed to replace dependents: %w", err), tmp.Close())
+	}
+	if err := d.file.Close(); err != nil {
+		d.log.Warn("Failed to close pruned dependents", "err", err)
+	}
+	d.file = tmp
+	for _, r := range d.records[:count] {
+		deps := d.byTarget[r.target]
+		if len(deps) == 1 {
+			delete(d.byTarget, r.target)
+		} else {
+			d.byTarget[r.target] = deps[1:]
+		}
+	}
+	d.records = append([]dependentRecord(nil), d.records[count:]...)
+	return nil
+}
+
+func (d *dependentsIndex) Close() error {
+	return d.file.Close()
+}
+
+// indexDependents stores the dependents of the executing messages of the log
+// that the given state just completed, which ends at the entry before the next entry of the state.
+func (db *DB) indexDependents(l *logContext) error {
+	_, parent, ok := l.SealedBlock()
+	if !ok {
+		return nil
+	}
+	_, logIdx, ok := l.InitMessage()
+	if !ok {
+		return nil
+	}
+	for _, msg := range l.execMsgs {
+		r := dependentRecord{
+			target:    dependentKey{chain: msg.Chain, blockNum: msg.BlockNum, logIdx: msg.LogIdx},
+			dependent: Dependent{BlockNum: parent + 1, LogIdx: logIdx},
+			entryIdx:  l.nextEntryIndex - 1,
+		}
+		if err := db.dependents.add(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDependents makes the dependents consistent with the entries of the DB:
+// it removes dependents of entries that are no longer in the DB, and indexes the logs after the covered entries.
+func (db *DB) syncDependents() error {
+	if db.dependents == nil {
+		return nil
+	}
+	size := db.lastEntryIdx() + 1
+	if err := db.dependents.truncate(min(db.dependents.covered, size)); err != nil {
+		return err
+	}
+	first := db.firstCheckpoint() * db.checkpointFrequency
+	if err := db.dependents.prune(first); err != nil {
+		return err
+	}
+	from := db.dependents.covered
+	// Replay from the search checkpoint before the first entry that is not covered.
+	start := max((from/db.checkpointFrequency)*db.checkpointFrequency, first)
+	if start < size {
+		iter := db.newIterator(start)
+		iter.current.need.Add(entrydb.FlagCanonicalHash)
+		for {
+			if err := iter.NextExecMsg(); errors.Is(err, ErrFuture) {
+				break
+			} else if err != nil {
+				return fmt.Errorf("failed to index dependents: %w", err)
+			}
+			if iter.NextIndex() <= from {
+				continue
+			}
+			if err := db.indexDependents(&iter.current); err != nil {
+				return err
+			}
+		}
+	}
+	return db.dependents.setCovered(size)
+}
+
+// updateDependents indexes the executing messages among the given entries, that were appended to the DB,
+// on top of the last published state.
+// The entries are not indexed if earlier entries are not covered, e.g. after a failed write.
+// Missing dependents are indexed when the DB is opened; until then, they are not found.
+func (db *DB) updateDependents(start entrydb.EntryIdx, entries []entrydb.Entry) {
+	if db.dependents == nil || db.dependents.covered != start {
+		return
+	}
+	err := db.replayWritten(entries, func(l *logContext, kind ChangeKind) error {
+		if kind != ChangeAppendedLog {
+			return nil
+		}
+		return db.indexDependents(l)
+	})
+	if err != nil {
+		db.log.Warn("Failed to index dependents", "err", err)
+		return
+	}
+	if err := db.dependents.setCovered(start + entrydb.EntryIdx(len(entries))); err != nil {
+		db.log.Warn("Failed to index dependents", "err", err)
+	}
+}
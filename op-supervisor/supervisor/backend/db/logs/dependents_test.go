@@ -0,0 +1,156 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+func TestDependents(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: 16}
+	// execMsg returns an executing message of log 1 of block i, that references log (i % 3) of block (i / 4) of chain 7
+	execMsg := func(i int) types.ExecutingMessage {
+		return types.ExecutingMessage{Chain: 7, BlockNum: uint64(i / 4), LogIdx: uint32(i % 3), Timestamp: 500, Hash: createHash(i)}
+	}
+	// addBlocks adds the given blocks, each with a log, and a log with an executing message
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			block := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+			require.NoError(t, db.WriteBatch(func(b Batch) error {
+				if i > 0 {
+					parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+					if err := b.AddLog(createHash(100*i), parent, 0, nil); err != nil {
+						return err
+					}
+					msg := execMsg(i)
+					if err := b.AddLog(createHash(100*i+1), parent, 1, &msg); err != nil {
+						return err
+					}
+				}
+				return b.SealBlock(createHash(i-1), block, 500+uint64(i))
+			}))
+		}
+	}
+	open := func(t *testing.T, path string) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.SegmentConfig{Size: 50}, DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+	// requireDependents checks the dependents of the logs that the blocks from and up to and including to reference
+	requireDependents := func(t *testing.T, db *DB, from, to int) {
+		expected := make(map[dependentKey][]Dependent)
+		for i := from; i <= to; i++ {
+			msg := execMsg(i)
+			key := dependentKey{chain: msg.Chain, blockNum: msg.BlockNum, logIdx: msg.LogIdx}
+			expected[key] = append(expected[key], Dependent{BlockNum: uint64(i), LogIdx: 1})
+		}
+		for key, deps := range expected {
+			require.Equalf(t, deps, db.Dependents(key.chain, key.blockNum, key.logIdx), "dependents of %v", key)
+		}
+		require.Len(t, db.dependents.byTarget, len(expected))
+		require.Empty(t, db.Dependents(8, 1, 1))
+	}
+
+	t.Run("IndexOnWrite", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 30)
+		requireDependents(t, db, 1, 30)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireDependents(t, db, 1, 30)
+		addBlocks(t, db, 31, 40)
+		requireDependents(t, db, 1, 40)
+	})
+
+	t.Run("Rebuild", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 30)
+		require.NoError(t, db.Close())
+		require.NoError(t, os.Remove(path+".deps"))
+
+		db = open(t, path)
+		defer db.Close()
+		requireDependents(t, db, 1, 30)
+	})
+
+	t.Run("Behind", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 20)
+		require.NoError(t, db.Close())
+		deps, err := os.ReadFile(path + ".deps")
+		require.NoError(t, err)
+
+		db = open(t, path)
+		addBlocks(t, db, 21, 30)
+		require.NoError(t, db.Close())
+		// as if the dependents of the later blocks were lost, with a partially written record
+		require.NoError(t, os.WriteFile(path+".deps", append(deps, 1, 2, 3), 0o644))
+
+		db = open(t, path)
+		defer db.Close()
+		requireDependents(t, db, 1, 30)
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 30)
+		require.NoError(t, db.Close())
+		deps, err := os.ReadFile(path + ".deps")
+		require.NoError(t, err)
+		deps[dependentsHeaderSize+10*dependentRecordSize+3] ^= 0xff
+		require.NoError(t, os.WriteFile(path+".deps", deps, 0o644))
+
+		db = open(t, path)
+		defer db.Close()
+		requireDependents(t, db, 1, 30)
+	})
+
+	t.Run("Rewind", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 30)
+		require.NoError(t, db.Rewind(20))
+		requireDependents(t, db, 1, 20)
+		addBlocks(t, db, 21, 25)
+		requireDependents(t, db, 1, 25)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireDependents(t, db, 1, 25)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path)
+		addBlocks(t, db, 0, 60)
+		next, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(40), Number: 40})
+		require.NoError(t, err)
+		require.NoError(t, db.Prune(next))
+		require.Positive(t, db.store.FirstEntryIdx(), "should have pruned entries")
+		first := db.dependents.records[0].dependent.BlockNum
+		require.Greater(t, first, uint64(1))
+		requireDependents(t, db, int(first), 60)
+		addBlocks(t, db, 61, 65)
+		requireDependents(t, db, int(first), 65)
+		require.NoError(t, db.Close())
+
+		db = open(t, path)
+		defer db.Close()
+		requireDependents(t, db, int(first), 65)
+	})
+}
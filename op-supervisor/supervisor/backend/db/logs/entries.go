@@ -4,10 +4,17 @@ import (
 	"encoding/binary"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
 )
 
+// truncatedHashSize is the number of hash bytes that are stored in the entry that carries the hash.
+// In full-hash mode the remaining bytes are stored in a hash-extension entry that directly follows it.
+const truncatedHashSize = 20
+
 // searchCheckpoint is both a checkpoint for searching, as well as a checkpoint for sealing blocks.
 type searchCheckpoint struct {
 	blockNum uint64
@@ -15,8 +22,14 @@ type searchCheckpoint struct {
 	// There is at least one checkpoint per L2 block with logsSince == 0, i.e. the exact block boundary.
 	logsSince uint32
 	timestamp uint64
+	// invalidated is the number of entries before the checkpoint that were invalidated, see DB.InvalidateBlock.
+	// A checkpoint that re-seals the parent of an invalidated block is preceded by the entries that are skipped.
+	invalidated uint32
 }
 
+// maxInvalidatedEntries is the largest number of entries that a search checkpoint can mark as invalidated.
+const maxInvalidatedEntries = 1<<24 - 1
+
 func newSearchCheckpoint(blockNum uint64, logsSince uint32, timestamp uint64) searchCheckpoint {
 	return searchCheckpoint{
 		blockNum:  blockNum,
@@ -30,28 +43,31 @@ func newSearchCheckpointFromEntry(data entrydb.Entry) (searchCheckpoint, error)
 		return searchCheckpoint{}, fmt.Errorf("%w: attempting to decode search checkpoint but was type %s", ErrDataCorruption, data.Type())
 	}
 	return searchCheckpoint{
-		blockNum:  binary.LittleEndian.Uint64(data[1:9]),
-		logsSince: binary.LittleEndian.Uint32(data[9:13]),
-		timestamp: binary.LittleEndian.Uint64(data[13:21]),
+		blockNum:    binary.LittleEndian.Uint64(data[1:9]),
+		logsSince:   binary.LittleEndian.Uint32(data[9:13]),
+		timestamp:   binary.LittleEndian.Uint64(data[13:21]),
+		invalidated: uint32(data[21]) | uint32(data[22])<<8 | uint32(data[23])<<16,
 	}, nil
 }
 
 // encode creates a checkpoint entry
-// type 0: "search checkpoint" <type><uint64 block number: 8 bytes><uint32 logsSince count: 4 bytes><uint64 timestamp: 8 bytes> = 21 bytes
+// type 0: "search checkpoint" <type><uint64 block number: 8 bytes><uint32 logsSince count: 4 bytes><uint64 timestamp: 8 bytes>
+// <uint24 invalidated entries: 3 bytes> = 24 bytes
 func (s searchCheckpoint) encode() entrydb.Entry {
 	var data entrydb.Entry
 	data[0] = uint8(entrydb.TypeSearchCheckpoint)
 	binary.LittleEndian.PutUint64(data[1:9], s.blockNum)
 	binary.LittleEndian.PutUint32(data[9:13], s.logsSince)
 	binary.LittleEndian.PutUint64(data[13:21], s.timestamp)
+	data[21], data[22], data[23] = byte(s.invalidated), byte(s.invalidated>>8), byte(s.invalidated>>16)
 	return data
 }
 
 type canonicalHash struct {
-	hash types.TruncatedHash
+	hash common.Hash
 }
 
-func newCanonicalHash(hash types.TruncatedHash) canonicalHash {
+func newCanonicalHash(hash common.Hash) canonicalHash {
 	return canonicalHash{hash: hash}
 }
 
@@ -59,21 +75,23 @@ func newCanonicalHashFromEntry(data entrydb.Entry) (canonicalHash, error) {
 	if data.Type() != entrydb.TypeCanonicalHash {
 		return canonicalHash{}, fmt.Errorf("%w: attempting to decode canonical hash but was type %s", ErrDataCorruption, data.Type())
 	}
-	var truncated types.TruncatedHash
-	copy(truncated[:], data[1:21])
+	var truncated common.Hash
+	copy(truncated[:truncatedHashSize], data[1:21])
 	return newCanonicalHash(truncated), nil
 }
 
+// encode creates a canonical hash entry
+// type 1: "canonical hash" <type><parent blockhash truncated: 20 bytes> = 21 bytes
 func (c canonicalHash) encode() entrydb.Entry {
 	var entry entrydb.Entry
 	entry[0] = uint8(entrydb.TypeCanonicalHash)
-	copy(entry[1:21], c.hash[:])
+	copy(entry[1:21], c.hash[:truncatedHashSize])
 	return entry
 }
 
 type initiatingEvent struct {
-	hasExecMsg bool
-	logHash    types.TruncatedHash
+	execMsgCount int
+	logHash      common.Hash
 }
 
 func newInitiatingEventFromEntry(data entrydb.Entry) (initiatingEvent, error) {
@@ -81,86 +99,134 @@ func newInitiatingEventFromEntry(data entrydb.Entry) (initiatingEvent, error) {
 		return initiatingEvent{}, fmt.Errorf("%w: attempting to decode initiating event but was type %s", ErrDataCorruption, data.Type())
 	}
 	flags := data[1]
+	var logHash common.Hash
+	copy(logHash[:truncatedHashSize], data[2:22])
+	execMsgCount := 0
+	if flags&eventFlagHasExecutingMessage != 0 {
+		execMsgCount = 1
+		if flags&eventFlagHasMultipleExecutingMessages != 0 {
+			execMsgCount = int(data[22])
+			if execMsgCount < 2 {
+				return initiatingEvent{}, fmt.Errorf("%w: initiating event flagged with multiple executing messages has count %d", ErrDataCorruption, execMsgCount)
+			}
+		}
+	} else if flags&eventFlagHasMultipleExecutingMessages != 0 {
+		return initiatingEvent{}, fmt.Errorf("%w: initiating event flagged with multiple executing messages but without executing message", ErrDataCorruption)
+	}
 	return initiatingEvent{
-		hasExecMsg: flags&eventFlagHasExecutingMessage != 0,
-		logHash:    types.TruncatedHash(data[2:22]),
+		execMsgCount: execMsgCount,
+		logHash:      logHash,
 	}, nil
 }
 
-func newInitiatingEvent(logHash types.TruncatedHash, hasExecMsg bool) initiatingEvent {
+func newInitiatingEvent(logHash common.Hash, execMsgCount int) initiatingEvent {
 	return initiatingEvent{
-		hasExecMsg: hasExecMsg,
-		logHash:    logHash,
+		execMsgCount: execMsgCount,
+		logHash:      logHash,
 	}
 }
 
 // encode creates an initiating event entry
-// type 2: "initiating event" <type><flags><event-hash: 20 bytes> = 22 bytes
+// type 2: "initiating event" <type><flags><event-hash: 20 bytes><executing message count: 1 byte, only with multiple executing messages> = 23 bytes
 func (i initiatingEvent) encode() entrydb.Entry {
 	var data entrydb.Entry
 	data[0] = uint8(entrydb.TypeInitiatingEvent)
 	flags := byte(0)
-	if i.hasExecMsg {
+	if i.execMsgCount > 0 {
 		flags = flags | eventFlagHasExecutingMessage
 	}
+	if i.execMsgCount > 1 {
+		flags = flags | eventFlagHasMultipleExecutingMessages
+		data[22] = uint8(i.execMsgCount)
+	}
 	data[1] = flags
-	copy(data[2:22], i.logHash[:])
+	copy(data[2:22], i.logHash[:truncatedHashSize])
 	return data
 }
 
+const (
+	// maxLogIdx is the maximum log index of an executing message, as encoded in 3 bytes.
+	maxLogIdx = 1<<24 - 1
+	// maxWideBlockNum is the maximum block number of an executing message, as encoded in 7 bytes with wide log indices.
+	maxWideBlockNum = 1<<56 - 1
+)
+
 type executingLink struct {
 	chain     uint32
 	blockNum  uint64
 	logIdx    uint32
 	timestamp uint64
+	// wide encodes the log index in 4 bytes, see Options.WideLogIndices
+	wide bool
 }
 
-func newExecutingLink(msg types.ExecutingMessage) (executingLink, error) {
-	if msg.LogIdx > 1<<24 {
-		return executingLink{}, fmt.Errorf("log idx is too large (%v)", msg.LogIdx)
+// newExecutingLink creates the executing link of the message,
+// with a log index of 4 bytes if wideLogIndices is set, or 3 bytes otherwise.
+func newExecutingLink(msg types.ExecutingMessage, wideLogIndices bool) (executingLink, error) {
+	if wideLogIndices {
+		if msg.BlockNum > maxWideBlockNum {
+			return executingLink{}, fmt.Errorf("%w: block number %d is too large for wide log indices", ErrOverflow, msg.BlockNum)
+		}
+	} else if msg.LogIdx > maxLogIdx {
+		return executingLink{}, fmt.Errorf("%w: log idx %d is too large, the maximum is %d", ErrOverflow, msg.LogIdx, maxLogIdx)
 	}
 	return executingLink{
 		chain:     msg.Chain,
 		blockNum:  msg.BlockNum,
 		logIdx:    msg.LogIdx,
 		timestamp: msg.Timestamp,
+		wide:      wideLogIndices,
 	}, nil
 }
 
-func newExecutingLinkFromEntry(data entrydb.Entry) (executingLink, error) {
+func newExecutingLinkFromEntry(data entrydb.Entry, wideLogIndices bool) (executingLink, error) {
 	if data.Type() != entrydb.TypeExecutingLink {
 		return executingLink{}, fmt.Errorf("%w: attempting to decode executing link but was type %s", ErrDataCorruption, data.Type())
 	}
-	timestamp := binary.LittleEndian.Uint64(data[16:24])
-	return executingLink{
+	link := executingLink{
 		chain:     binary.LittleEndian.Uint32(data[1:5]),
-		blockNum:  binary.LittleEndian.Uint64(data[5:13]),
-		logIdx:    uint32(data[13]) | uint32(data[14])<<8 | uint32(data[15])<<16,
-		timestamp: timestamp,
-	}, nil
+		timestamp: binary.LittleEndian.Uint64(data[16:24]),
+		wide:      wideLogIndices,
+	}
+	if wideLogIndices {
+		var blockNum [8]byte
+		copy(blockNum[:7], data[5:12])
+		link.blockNum = binary.LittleEndian.Uint64(blockNum[:])
+		link.logIdx = binary.LittleEndian.Uint32(data[12:16])
+	} else {
+		link.blockNum = binary.LittleEndian.Uint64(data[5:13])
+		link.logIdx = uint32(data[13]) | uint32(data[14])<<8 | uint32(data[15])<<16
+	}
+	return link, nil
 }
 
 // encode creates an executing link entry
 // type 3: "executing link" <type><chain: 4 bytes><blocknum: 8 bytes><event index: 3 bytes><uint64 timestamp: 8 bytes> = 24 bytes
+// With wide log indices, the block number takes 7 bytes and the event index 4 bytes.
 func (e executingLink) encode() entrydb.Entry {
 	var entry entrydb.Entry
 	entry[0] = uint8(entrydb.TypeExecutingLink)
 	binary.LittleEndian.PutUint32(entry[1:5], e.chain)
-	binary.LittleEndian.PutUint64(entry[5:13], e.blockNum)
-
-	entry[13] = byte(e.logIdx)
-	entry[14] = byte(e.logIdx >> 8)
-	entry[15] = byte(e.logIdx >> 16)
-
+	if e.wide {
+		var blockNum [8]byte
+		binary.LittleEndian.PutUint64(blockNum[:], e.blockNum)
+		copy(entry[5:12], blockNum[:7])
+		binary.LittleEndian.PutUint32(entry[12:16], e.logIdx)
+	} else {
+		binary.LittleEndian.PutUint64(entry[5:13], e.blockNum)
+		entry[13] = byte(e.logIdx)
+		entry[14] = byte(e.logIdx >> 8)
+		entry[15] = byte(e.logIdx >> 16)
+	}
 	binary.LittleEndian.PutUint64(entry[16:24], e.timestamp)
 	return entry
 }
 
 type executingCheck struct {
-	hash types.TruncatedHash
+	hash common.Hash
 }
 
-func newExecutingCheck(hash types.TruncatedHash) executingCheck {
+func newExecutingCheck(hash common.Hash) executingCheck {
 	return executingCheck{hash: hash}
 }
 
@@ -168,8 +234,8 @@ func newExecutingCheckFromEntry(data entrydb.Entry) (executingCheck, error) {
 	if data.Type() != entrydb.TypeExecutingCheck {
 		return executingCheck{}, fmt.Errorf("%w: attempting to decode executing check but was type %s", ErrDataCorruption, data.Type())
 	}
-	var hash types.TruncatedHash
-	copy(hash[:], data[1:21])
+	var hash common.Hash
+	copy(hash[:truncatedHashSize], data[1:21])
 	return newExecutingCheck(hash), nil
 }
 
@@ -178,7 +244,201 @@ func newExecutingCheckFromEntry(data entrydb.Entry) (executingCheck, error) {
 func (e executingCheck) encode() entrydb.Entry {
 	var entry entrydb.Entry
 	entry[0] = uint8(entrydb.TypeExecutingCheck)
-	copy(entry[1:21], e.hash[:])
+	copy(entry[1:21], e.hash[:truncatedHashSize])
+	return entry
+}
+
+// hashExtension holds the bytes of a hash that do not fit in the entry that carries the hash.
+type hashExtension struct {
+	rest [common.HashLength - truncatedHashSize]byte
+}
+
+func newHashExtension(hash common.Hash) hashExtension {
+	var ext hashExtension
+	copy(ext.rest[:], hash[truncatedHashSize:])
+	return ext
+}
+
+func newHashExtensionFromEntry(data entrydb.Entry) (hashExtension, error) {
+	if data.Type() != entrydb.TypeHashExtension {
+		return hashExtension{}, fmt.Errorf("%w: attempting to decode hash extension but was type %s", ErrDataCorruption, data.Type())
+	}
+	var ext hashExtension
+	copy(ext.rest[:], data[1:13])
+	return ext, nil
+}
+
+// extend completes the truncated hash with the remaining bytes.
+func (e hashExtension) extend(hash common.Hash) common.Hash {
+	copy(hash[truncatedHashSize:], e.rest[:])
+	return hash
+}
+
+// encode creates a hash extension entry
+// type 6: "hash extension" <type><remaining hash bytes: 12 bytes> = 13 bytes
+func (e hashExtension) encode() entrydb.Entry {
+	var entry entrydb.Entry
+	entry[0] = uint8(entrydb.TypeHashExtension)
+	copy(entry[1:13], e.rest[:])
+	return entry
+}
+
+// safety levels of a safe head entry
+const (
+	safeHeadLevelLocalSafe = byte(0)
+	safeHeadLevelCrossSafe = byte(1)
+)
+
+// l1HashSize is the number of bytes of the L1 block hash that are stored in a derived-from or L1 block entry.
+const l1HashSize = 15
+
+// derivedFrom links the last sealed L2 block to the L1 block it was derived from.
+type derivedFrom struct {
+	l1 eth.BlockID
+}
+
+func newDerivedFrom(l1 eth.BlockID) derivedFrom {
+	return derivedFrom{l1: eth.BlockID{Hash: truncateL1Hash(l1.Hash), Number: l1.Number}}
+}
+
+func newDerivedFromFromEntry(data entrydb.Entry) (derivedFrom, error) {
+	if data.Type() != entrydb.TypeDerivedFrom {
+		return derivedFrom{}, fmt.Errorf("%w: attempting to decode derived from but was type %s", ErrDataCorruption, data.Type())
+	}
+	var hash common.Hash
+	copy(hash[:l1HashSize], data[9:24])
+	return derivedFrom{l1: eth.BlockID{Hash: hash, Number: binary.LittleEndian.Uint64(data[1:9])}}, nil
+}
+
+// encode creates a derived from entry
+// type 7: "derived from" <type><uint64 L1 block number: 8 bytes><L1 blockhash truncated: 15 bytes> = 24 bytes
+func (d derivedFrom) encode() entrydb.Entry {
+	var entry entrydb.Entry
+	entry[0] = uint8(entrydb.TypeDerivedFrom)
+	binary.LittleEndian.PutUint64(entry[1:9], d.l1.Number)
+	copy(entry[9:24], d.l1.Hash[:l1HashSize])
+	return entry
+}
+
+// truncateL1Hash returns the part of the L1 block hash that is stored in a derived-from or L1 block entry,
+// with the remaining bytes zeroed.
+func truncateL1Hash(hash common.Hash) common.Hash {
+	clear(hash[l1HashSize:])
+	return hash
+}
+
+// safeHead records that the block with the given number became the local-safe or cross-safe head.
+type safeHead struct {
+	crossSafe bool
+	blockNum  uint64
+}
+
+func newSafeHead(crossSafe bool, blockNum uint64) safeHead {
+	return safeHead{crossSafe: crossSafe, blockNum: blockNum}
+}
+
+func newSafeHeadFromEntry(data entrydb.Entry) (safeHead, error) {
+	if data.Type() != entrydb.TypeSafeHead {
+		return safeHead{}, fmt.Errorf("%w: attempting to decode safe head but was type %s", ErrDataCorruption, data.Type())
+	}
+	if data[1] > safeHeadLevelCrossSafe {
+		return safeHead{}, fmt.Errorf("%w: unknown safe head level %d", ErrDataCorruption, data[1])
+	}
+	return newSafeHead(data[1] == safeHeadLevelCrossSafe, binary.LittleEndian.Uint64(data[2:10])), nil
+}
+
+// encode creates a safe head entry
+// type 8: "safe head" <type><safety level: 1 byte><uint64 block number: 8 bytes> = 10 bytes
+func (s safeHead) encode() entrydb.Entry {
+	var entry entrydb.Entry
+	entry[0] = uint8(entrydb.TypeSafeHead)
+	if s.crossSafe {
+		entry[1] = safeHeadLevelCrossSafe
+	}
+	binary.LittleEndian.PutUint64(entry[2:10], s.blockNum)
+	return entry
+}
+
+// l1Block registers an L1 block, as a kind of initiating event that messages can reference.
+type l1Block struct {
+	l1 eth.BlockID
+}
+
+func newL1Block(l1 eth.BlockID) l1Block {
+	return l1Block{l1: eth.BlockID{Hash: truncateL1Hash(l1.Hash), Number: l1.Number}}
+}
+
+func newL1BlockFromEntry(data entrydb.Entry) (l1Block, error) {
+	if data.Type() != entrydb.TypeL1Block {
+		return l1Block{}, fmt.Errorf("%w: attempting to decode L1 block but was type %s", ErrDataCorruption, data.Type())
+	}
+	var hash common.Hash
+	copy(hash[:l1HashSize], data[9:24])
+	return l1Block{l1: eth.BlockID{Hash: hash, Number: binary.LittleEndian.Uint64(data[1:9])}}, nil
+}
+
+// encode creates an L1 block entry
+// type 9: "L1 block" <type><uint64 L1 block number: 8 bytes><L1 blockhash truncated: 15 bytes> = 24 bytes
+func (b l1Block) encode() entrydb.Entry {
+	var entry entrydb.Entry
+	entry[0] = uint8(entrydb.TypeL1Block)
+	binary.LittleEndian.PutUint64(entry[1:9], b.l1.Number)
+	copy(entry[9:24], b.l1.Hash[:l1HashSize])
+	return entry
+}
+
+// depositEvent is a log emitted by a deposit transaction: an initiating event that cannot carry executing messages.
+type depositEvent struct {
+	logHash common.Hash
+}
+
+func newDepositEvent(logHash common.Hash) depositEvent {
+	return depositEvent{logHash: logHash}
+}
+
+func newDepositEventFromEntry(data entrydb.Entry) (depositEvent, error) {
+	if data.Type() != entrydb.TypeDepositEvent {
+		return depositEvent{}, fmt.Errorf("%w: attempting to decode deposit event but was type %s", ErrDataCorruption, data.Type())
+	}
+	var logHash common.Hash
+	copy(logHash[:truncatedHashSize], data[1:21])
+	return newDepositEvent(logHash), nil
+}
+
+// encode creates a deposit event entry
+// type 10: "deposit event" <type><event-hash: 20 bytes> = 21 bytes
+func (d depositEvent) encode() entrydb.Entry {
+	var entry entrydb.Entry
+	entry[0] = uint8(entrydb.TypeDepositEvent)
+	copy(entry[1:21], d.logHash[:truncatedHashSize])
+	return entry
+}
+
+// invalidatedBlock records that a block, and the blocks after it, were invalidated, e.g. after failing cross-validation.
+// It follows the search checkpoint that re-seals the parent of the invalidated block, see DB.InvalidateBlock.
+type invalidatedBlock struct {
+	hash common.Hash
+}
+
+func newInvalidatedBlock(hash common.Hash) invalidatedBlock {
+	return invalidatedBlock{hash: hash}
+}
+
+func newInvalidatedBlockFromEntry(data entrydb.Entry) (invalidatedBlock, error) {
+	if data.Type() != entrydb.TypeInvalidated {
+		return invalidatedBlock{}, fmt.Errorf("%w: attempting to decode invalidated block but was type %s", ErrDataCorruption, data.Type())
+	}
+	var hash common.Hash
+	copy(hash[:truncatedHashSize], data[1:21])
+	return newInvalidatedBlock(hash), nil
+}
+
+// encode creates an invalidated block entry
+// type 11: "invalidated" <type><invalidated blockhash truncated: 20 bytes> = 21 bytes
+func (b invalidatedBlock) encode() entrydb.Entry {
+	var entry entrydb.Entry
+	entry[0] = uint8(entrydb.TypeInvalidated)
+	copy(entry[1:21], b.hash[:truncatedHashSize])
 	return entry
 }
 
@@ -0,0 +1,151 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+// InvalidatedBlock is a block that was invalidated, together with the blocks after it, see DB.InvalidateBlock.
+type InvalidatedBlock struct {
+	// Block is the invalidated block.
+	// Unless the DB stores full hashes, only the first 20 bytes of the block hash are set.
+	Block eth.BlockID
+	// Start is the index of the first invalidated entry, right after the seal of the parent block.
+	Start entrydb.EntryIdx
+	// End is the index of the search checkpoint after the invalidated entries, that re-seals the parent block.
+	// The entries from End onwards are not invalidated: the replacement of the block is sealed after them.
+	End entrydb.EntryIdx
+}
+
+// InvalidateBlock invalidates the given sealed block, and the blocks after it, e.g. after the block failed cross-validation.
+// Like Rewind, the parent block becomes the last sealed block, on top of which the replacement of the block can be sealed.
+// Unlike Rewind, the entries of the invalidated blocks are not removed:
+// a search checkpoint re-seals the parent block, and is followed by an entry that records the invalidated block.
+// Reads skip the invalidated entries, which remain available for audits, see InvalidatedBlocks.
+// As with Rewind, the data after the seal of the parent block, such as the L1 block it was derived from, is not kept.
+// Returns the invalidated block and entries.
+// returns ErrFuture if the block is not known yet
+// returns ErrConflict if the known block does not match
+// returns ErrSkipped if the parent block was pruned
+// returns ErrOverflow if the invalidated entries are too many to record with a single search checkpoint
+func (db *DB) InvalidateBlock(block eth.BlockID) (InvalidatedBlock, error) {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+	if block.Number == 0 {
+		return InvalidatedBlock{}, errors.New("cannot invalidate block 0, as it has no parent block")
+	}
+	iter, err := db.newIteratorAt(block.Number, 0)
+	if errors.Is(err, ErrFuture) {
+		return InvalidatedBlock{}, fmt.Errorf("block %d is not known yet: %w", block.Number, ErrFuture)
+	} else if err != nil {
+		return InvalidatedBlock{}, fmt.Errorf("failed to find sealed block %d: %w", block.Number, err)
+	}
+	hash, _, ok := iter.SealedBlock()
+	if !ok {
+		panic("expected block")
+	}
+	if storedHash(block.Hash, db.fullHashes) != hash {
+		return InvalidatedBlock{}, fmt.Errorf("queried %s but got %s at number %d: %w", block.Hash, hash, block.Number, ErrConflict)
+	}
+	parent, err := db.newIteratorAt(block.Number-1, 0)
+	if err != nil {
+		return InvalidatedBlock{}, fmt.Errorf("failed to find parent block %d: %w", block.Number-1, err)
+	}
+	parentHash, parentNum, ok := parent.SealedBlock()
+	if !ok {
+		panic("expected block")
+	}
+	// The parent block is re-sealed at the next search checkpoint, such that the invalidated entries,
+	// that the checkpoint records, are found with the checkpoints when the DB is opened.
+	start := parent.NextIndex()
+	next := db.lastEntryContext.NextIndex()
+	end := ((next + db.checkpointFrequency - 1) / db.checkpointFrequency) * db.checkpointFrequency
+	if end-start > maxInvalidatedEntries {
+		return InvalidatedBlock{}, fmt.Errorf("cannot invalidate %d entries, the maximum is %d: %w", end-start, maxInvalidatedEntries, ErrOverflow)
+	}
+	entries := make([]entrydb.Entry, 0, end-next+2*db.lastEntryContext.hashEntryCount()+1)
+	for i := next; i < end; i++ {
+		entries = append(entries, paddingEntry{}.encode())
+	}
+	checkpoint := newSearchCheckpoint(parentNum, 0, parent.current.timestamp)
+	checkpoint.invalidated = uint32(end - start)
+	entries = append(entries, checkpoint.encode(), newCanonicalHash(parentHash).encode())
+	if db.fullHashes {
+		entries = append(entries, newHashExtension(parentHash).encode())
+	}
+	entries = append(entries, newInvalidatedBlock(hash).encode())
+	if db.fullHashes {
+		entries = append(entries, newHashExtension(hash).encode())
+	}
+	// Snapshots that read the invalidated entries are marked as stale,
+	// and the dependents of the invalidated entries are removed, before the entries are skipped.
+	db.rewinds.Add(1)
+	if db.dependents != nil {
+		if err := db.dependents.truncate(start); err != nil {
+			return InvalidatedBlock{}, fmt.Errorf("failed to remove invalidated dependents: %w", err)
+		}
+	}
+	if err := db.store.Append(entries...); err != nil {
+		return InvalidatedBlock{}, fmt.Errorf("failed to append invalidated block: %w", err)
+	}
+	// Use db.init() to index the checkpoint that re-seals the parent block, and to find the new last entry context
+	if err := db.init(false); err != nil {
+		return InvalidatedBlock{}, fmt.Errorf("failed to find new last entry context: %w", err)
+	}
+	db.log.Info("Invalidated block", "block", block, "parent", parentNum, "entries", end-start)
+	db.notifyChanges([]Change{newChange(&db.lastEntryContext, ChangeRewound)})
+	return InvalidatedBlock{Block: eth.BlockID{Hash: hash, Number: block.Number}, Start: start, End: end}, nil
+}
+
+// InvalidatedBlocks returns the blocks that were invalidated, in the order of invalidation, see InvalidateBlock.
+// The entries of a block that was invalidated later may include those of a block that was invalidated earlier.
+// Blocks of which the entries were pruned or rewound are not included.
+func (db *DB) InvalidatedBlocks() ([]InvalidatedBlock, error) {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	if db.checkpoints == nil {
+		return nil, nil
+	}
+	var blocks []InvalidatedBlock
+	for _, r := range db.checkpoints.invalidatedRanges(db.checkpointFrequency) {
+		// The checkpoint is read from the store, as it may be invalidated itself by a later invalidation.
+		entry, err := db.store.Read(r.end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read search checkpoint %d: %w", r.end, err)
+		}
+		checkpoint, err := newSearchCheckpointFromEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode search checkpoint %d: %w", r.end, err)
+		}
+		// The invalidated block is recorded after the canonical hash of the re-sealed parent block.
+		idx := r.end + 1 + db.lastEntryContext.hashEntryCount()
+		entry, err = db.store.Read(idx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read invalidated block %d: %w", idx, err)
+		}
+		b, err := newInvalidatedBlockFromEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode invalidated block %d: %w", idx, err)
+		}
+		if db.fullHashes {
+			entry, err := db.store.Read(idx + 1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read hash extension %d: %w", idx+1, err)
+			}
+			ext, err := newHashExtensionFromEntry(entry)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode hash extension %d: %w", idx+1, err)
+			}
+			b.hash = ext.extend(b.hash)
+		}
+		blocks = append(blocks, InvalidatedBlock{
+			Block: eth.BlockID{Hash: b.hash, Number: checkpoint.blockNum + 1},
+			Start: r.start,
+			End:   r.end,
+		})
+	}
+	return blocks, nil
+}
@@ -0,0 +1,200 @@
+package logs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+	supTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+func TestInvalidateBlock(t *testing.T) {
+	execMsg := types.ExecutingMessage{Chain: 7, BlockNum: 1, LogIdx: 0, Timestamp: 500, Hash: createHash(9)}
+	// chain holds the hashes of the sealed blocks, by number
+	type chain map[uint64]common.Hash
+	// addBlocks adds the given blocks on top of the chain, each with two logs, the second of which executes a message.
+	// The hashes of the blocks and logs are derived from the seed, to replace invalidated blocks with different blocks.
+	addBlocks := func(t *testing.T, db *DB, c chain, from, to int, seed int) {
+		for i := from; i <= to; i++ {
+			block := eth.BlockID{Hash: createHash(seed + i), Number: uint64(i)}
+			require.NoError(t, db.WriteBatch(func(b Batch) error {
+				if i > 0 {
+					parent := eth.BlockID{Hash: c[uint64(i-1)], Number: uint64(i - 1)}
+					if err := b.AddLog(createHash(seed+100*i), parent, 0, nil); err != nil {
+						return err
+					}
+					if err := b.AddLog(createHash(seed+100*i+1), parent, 1, &execMsg); err != nil {
+						return err
+					}
+				}
+				return b.SealBlock(c[uint64(i-1)], block, 500+uint64(i))
+			}))
+			c[uint64(i)] = block.Hash
+		}
+	}
+	requireBlocks := func(t *testing.T, db *DB, c chain, to int) {
+		n, ok := db.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.EqualValues(t, to, n)
+		for i := 0; i <= to; i++ {
+			_, err := db.FindSealedBlock(eth.BlockID{Hash: c[uint64(i)], Number: uint64(i)})
+			require.NoError(t, err)
+			block, _, err := db.FindSealedBlockByTimestamp(500 + uint64(i))
+			require.NoError(t, err)
+			require.Equal(t, storedHash(c[uint64(i)], db.fullHashes), block.Hash)
+		}
+		_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(to + 1), Number: uint64(to + 1)})
+		require.ErrorIs(t, err, ErrFuture)
+		require.NoError(t, db.Verify())
+	}
+	requireLogs := func(t *testing.T, db *DB, from, to int, seed int) {
+		msg := execMsg
+		msg.Hash = storedHash(msg.Hash, db.fullHashes)
+		for i := from; i <= to; i++ {
+			requireContains(t, db, uint64(i), 0, storedHash(createHash(seed+100*i), db.fullHashes))
+			requireContains(t, db, uint64(i), 1, storedHash(createHash(seed+100*i+1), db.fullHashes), msg)
+		}
+	}
+	open := func(t *testing.T, path string, opts Options) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts,
+			entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+
+	for _, fullHashes := range []bool{false, true} {
+		opts := Options{SearchCheckpointFrequency: 16, FullHashes: fullHashes}
+		name := "TruncatedHashes"
+		if fullHashes {
+			name = "FullHashes"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Run("InvalidateAndReplace", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "test.db")
+				db := open(t, path, opts)
+				c := chain{0: createHash(0)}
+				addBlocks(t, db, c, 0, 20, 0)
+				require.Len(t, db.Dependents(execMsg.Chain, execMsg.BlockNum, execMsg.LogIdx), 20)
+				start, err := db.FindSealedBlock(eth.BlockID{Hash: c[9], Number: 9})
+				require.NoError(t, err)
+				next := db.NextIndex()
+
+				invalidated, err := db.InvalidateBlock(eth.BlockID{Hash: c[10], Number: 10})
+				require.NoError(t, err)
+				require.Equal(t, start, invalidated.Start)
+				require.Zero(t, invalidated.End%db.checkpointFrequency)
+				require.GreaterOrEqual(t, invalidated.End, next)
+				require.Equal(t, storedHash(c[10], fullHashes), invalidated.Block.Hash)
+				requireBlocks(t, db, c, 9)
+				requireLogs(t, db, 1, 9, 0)
+				requireFuture(t, db, 10, 0, storedHash(createHash(1000), fullHashes))
+				require.Len(t, db.Dependents(execMsg.Chain, execMsg.BlockNum, execMsg.LogIdx), 9)
+
+				// the replacement blocks are sealed on top of the parent of the invalidated block
+				addBlocks(t, db, c, 10, 25, 5000)
+				requireBlocks(t, db, c, 25)
+				requireLogs(t, db, 1, 9, 0)
+				requireLogs(t, db, 10, 25, 5000)
+				_, err = db.Contains(10, 0, storedHash(createHash(1000), fullHashes))
+				require.ErrorContains(t, err, "hash mismatch", "the invalidated log is replaced")
+				require.Len(t, db.Dependents(execMsg.Chain, execMsg.BlockNum, execMsg.LogIdx), 25)
+				blocks, err := db.InvalidatedBlocks()
+				require.NoError(t, err)
+				require.Equal(t, []InvalidatedBlock{invalidated}, blocks)
+				require.NoError(t, db.Close())
+
+				// the invalidated entries are found again when the DB is opened
+				db = open(t, path, opts)
+				defer db.Close()
+				requireBlocks(t, db, c, 25)
+				requireLogs(t, db, 1, 9, 0)
+				requireLogs(t, db, 10, 25, 5000)
+				blocks, err = db.InvalidatedBlocks()
+				require.NoError(t, err)
+				require.Equal(t, []InvalidatedBlock{invalidated}, blocks)
+				require.Len(t, db.Dependents(execMsg.Chain, execMsg.BlockNum, execMsg.LogIdx), 25)
+			})
+
+			t.Run("InvalidateAgain", func(t *testing.T) {
+				db := open(t, filepath.Join(t.TempDir(), "test.db"), opts)
+				defer db.Close()
+				c := chain{0: createHash(0)}
+				addBlocks(t, db, c, 0, 20, 0)
+				first, err := db.InvalidateBlock(eth.BlockID{Hash: c[15], Number: 15})
+				require.NoError(t, err)
+				addBlocks(t, db, c, 15, 20, 5000)
+				// a block of the replacement chain is invalidated, as well as an earlier block
+				second, err := db.InvalidateBlock(eth.BlockID{Hash: c[18], Number: 18})
+				require.NoError(t, err)
+				third, err := db.InvalidateBlock(eth.BlockID{Hash: c[5], Number: 5})
+				require.NoError(t, err)
+				require.Less(t, third.Start, first.Start)
+				requireBlocks(t, db, c, 4)
+				requireLogs(t, db, 1, 4, 0)
+				addBlocks(t, db, c, 5, 10, 9000)
+				requireBlocks(t, db, c, 10)
+				requireLogs(t, db, 5, 10, 9000)
+				blocks, err := db.InvalidatedBlocks()
+				require.NoError(t, err)
+				require.Equal(t, []InvalidatedBlock{first, second, third}, blocks)
+
+				// rewinding before an invalidated block removes it
+				require.NoError(t, db.Rewind(3))
+				requireBlocks(t, db, c, 3)
+				blocks, err = db.InvalidatedBlocks()
+				require.NoError(t, err)
+				require.Empty(t, blocks)
+			})
+
+			t.Run("L1Blocks", func(t *testing.T) {
+				db := open(t, filepath.Join(t.TempDir(), "test.db"), opts)
+				defer db.Close()
+				c := chain{0: createHash(0)}
+				addBlocks(t, db, c, 0, 5, 0)
+				l1A := eth.BlockID{Hash: createHash(700), Number: 100}
+				require.NoError(t, db.AddL1Block(l1A))
+				addBlocks(t, db, c, 6, 8, 0)
+				l1B := eth.BlockID{Hash: createHash(701), Number: 101}
+				require.NoError(t, db.AddL1Block(l1B))
+				require.NoError(t, db.AddSafeHead(supTypes.Safe, eth.BlockID{Hash: c[8], Number: 8}))
+
+				_, err := db.InvalidateBlock(eth.BlockID{Hash: c[7], Number: 7})
+				require.NoError(t, err)
+				// the L1 block and safe head that were added after the invalidated block are skipped
+				l1, err := db.L1BlockAt(db.NextIndex())
+				require.NoError(t, err)
+				require.Equal(t, newL1Block(l1A).l1, l1)
+				_, err = db.ContainsL1Block(l1B)
+				require.ErrorIs(t, err, ErrFuture)
+				_, err = db.ContainsL1Block(l1A)
+				require.NoError(t, err)
+				_, err = db.SafeHeadAt(db.NextIndex(), supTypes.Safe)
+				require.ErrorIs(t, err, ErrFuture)
+			})
+
+			t.Run("Errors", func(t *testing.T) {
+				db := open(t, filepath.Join(t.TempDir(), "test.db"), opts)
+				defer db.Close()
+				c := chain{0: createHash(0)}
+				addBlocks(t, db, c, 0, 5, 0)
+				_, err := db.InvalidateBlock(eth.BlockID{Hash: createHash(6), Number: 6})
+				require.ErrorIs(t, err, ErrFuture)
+				_, err = db.InvalidateBlock(eth.BlockID{Hash: createHash(1234), Number: 4})
+				require.ErrorIs(t, err, ErrConflict)
+				_, err = db.InvalidateBlock(eth.BlockID{Hash: c[0], Number: 0})
+				require.Error(t, err)
+				requireBlocks(t, db, c, 5)
+				blocks, err := db.InvalidatedBlocks()
+				require.NoError(t, err)
+				require.Empty(t, blocks)
+			})
+		})
+	}
+}
@@ -5,15 +5,21 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
 )
 
 type IteratorState interface {
 	NextIndex() entrydb.EntryIdx
-	SealedBlock() (hash types.TruncatedHash, num uint64, ok bool)
-	InitMessage() (hash types.TruncatedHash, logIndex uint32, ok bool)
+	SealedBlock() (hash common.Hash, num uint64, ok bool)
+	InitMessage() (hash common.Hash, logIndex uint32, ok bool)
+	IsDeposit() bool
 	ExecMessage() *types.ExecutingMessage
+	ExecMessages() []types.ExecutingMessage
+	DerivedFrom() (l1 eth.BlockID, ok bool)
 }
 
 type Iterator interface {
@@ -24,6 +30,30 @@ type Iterator interface {
 	IteratorState
 }
 
+// BlockLogs is a sealed block, with the logs of the block.
+// Unless the DB stores full hashes, only the first 20 bytes of each hash are set.
+type BlockLogs struct {
+	Block     eth.BlockID
+	Timestamp uint64
+	Logs      []BlockLog
+}
+
+// BlockLog is a log of a block, with the executing messages of the log, if any.
+// Deposit is true if the log was emitted by a deposit transaction, which never executes messages.
+type BlockLog struct {
+	Hash     common.Hash
+	ExecMsgs []types.ExecutingMessage
+	Deposit  bool
+}
+
+// BlockIterator iterates over a range of sealed blocks, with their logs.
+type BlockIterator interface {
+	// Next returns the next block of the range.
+	// It returns io.EOF after the last block of the range,
+	// and ErrFuture if the next block is not sealed yet, after which Next may be retried.
+	Next() (BlockLogs, error)
+}
+
 type iterator struct {
 	db          *DB
 	current     logContext
@@ -52,7 +82,7 @@ func (i *iterator) NextInitMsg() error {
 		if err != nil {
 			return err
 		}
-		if typ == entrydb.TypeInitiatingEvent {
+		if typ == entrydb.TypeInitiatingEvent || typ == entrydb.TypeDepositEvent {
 			seenLog = true
 		}
 		if !i.current.hasCompleteBlock() {
@@ -76,7 +106,7 @@ func (i *iterator) NextExecMsg() error {
 		if err != nil {
 			return err
 		}
-		if i.current.execMsg != nil {
+		if len(i.current.execMsgs) > 0 {
 			return nil // found a new executing message!
 		}
 	}
@@ -104,8 +134,10 @@ func (i *iterator) NextBlock() error {
 }
 
 // Read and apply the next entry.
+// Invalidated entries are skipped: the entry after them re-seals the state that they were written on top of.
 func (i *iterator) next() (entrydb.EntryType, error) {
-	index := i.current.nextEntryIndex
+	index := i.db.skipInvalidated(i.current.nextEntryIndex)
+	i.current.nextEntryIndex = index
 	entry, err := i.db.store.Read(index)
 	if err != nil {
 		if errors.Is(err, io.EOF) {
@@ -127,16 +159,88 @@ func (i *iterator) NextIndex() entrydb.EntryIdx {
 
 // SealedBlock returns the sealed block that we are appending logs after, if any is available.
 // I.e. the block is the parent block of the block containing the logs that are currently appending to it.
-func (i *iterator) SealedBlock() (hash types.TruncatedHash, num uint64, ok bool) {
+func (i *iterator) SealedBlock() (hash common.Hash, num uint64, ok bool) {
 	return i.current.SealedBlock()
 }
 
 // InitMessage returns the current initiating message, if any is available.
-func (i *iterator) InitMessage() (hash types.TruncatedHash, logIndex uint32, ok bool) {
+func (i *iterator) InitMessage() (hash common.Hash, logIndex uint32, ok bool) {
 	return i.current.InitMessage()
 }
 
-// ExecMessage returns the current executing message, if any is available.
+// IsDeposit returns true if the current initiating message, if any, was emitted by a deposit transaction.
+func (i *iterator) IsDeposit() bool {
+	return i.current.IsDeposit()
+}
+
+// ExecMessage returns the first executing message of the current log, if any is available.
 func (i *iterator) ExecMessage() *types.ExecutingMessage {
 	return i.current.ExecMessage()
 }
+
+// ExecMessages returns all executing messages of the current log, if any are available.
+func (i *iterator) ExecMessages() []types.ExecutingMessage {
+	return i.current.ExecMessages()
+}
+
+// DerivedFrom returns the L1 block that the sealed block was derived from, if it is known.
+func (i *iterator) DerivedFrom() (l1 eth.BlockID, ok bool) {
+	return i.current.DerivedFrom()
+}
+
+type blockIterator struct {
+	iter *iterator
+	// first is the first block of the range, if it was already sealed when the iterator was created
+	first *BlockLogs
+	// next is the number of the block to return next
+	next uint64
+	// to is the number of the last block of the range
+	to uint64
+	// logs of the next block that were read so far
+	logs []BlockLog
+	// seenLog is true if an initiating event was read, of a log that is not complete yet
+	seenLog bool
+}
+
+func (b *blockIterator) Next() (BlockLogs, error) {
+	if b.first != nil {
+		first := *b.first
+		b.first = nil
+		return first, nil
+	}
+	if b.next > b.to {
+		return BlockLogs{}, io.EOF
+	}
+	for {
+		typ, err := b.iter.next()
+		if err != nil {
+			return BlockLogs{}, err
+		}
+		if typ == entrydb.TypeInitiatingEvent || typ == entrydb.TypeDepositEvent {
+			b.seenLog = true
+		}
+		if !b.iter.current.hasCompleteBlock() {
+			continue // need the full block content
+		}
+		if b.seenLog && !b.iter.current.hasIncompleteLog() {
+			hash, _, _ := b.iter.InitMessage()
+			b.logs = append(b.logs, BlockLog{Hash: hash, ExecMsgs: b.iter.ExecMessages(), Deposit: b.iter.IsDeposit()})
+			b.seenLog = false
+		}
+		hash, num, _ := b.iter.SealedBlock()
+		if num < b.next {
+			continue // still on top of the parent block
+		}
+		if num > b.next {
+			return BlockLogs{}, fmt.Errorf("expected block %d, but found block %d: %w", b.next, num, ErrDataCorruption)
+		}
+		block := BlockLogs{
+			Block:     eth.BlockID{Hash: hash, Number: num},
+			Timestamp: b.iter.current.timestamp,
+			Logs:      b.logs,
+		}
+		b.logs = nil
+		b.next += 1
+		return block, nil
+	}
+}
@@ -0,0 +1,113 @@
+package logs
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+func TestExecutingLinkEncoding(t *testing.T) {
+	for _, wide := range []bool{false, true} {
+		msg := types.ExecutingMessage{Chain: 7, BlockNum: maxWideBlockNum, LogIdx: maxLogIdx, Timestamp: math.MaxUint64}
+		if wide {
+			msg.LogIdx = math.MaxUint32
+		}
+		link, err := newExecutingLink(msg, wide)
+		require.NoError(t, err)
+		decoded, err := newExecutingLinkFromEntry(link.encode(), wide)
+		require.NoError(t, err)
+		require.Equal(t, link, decoded)
+	}
+
+	_, err := newExecutingLink(types.ExecutingMessage{LogIdx: maxLogIdx + 1}, false)
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = newExecutingLink(types.ExecutingMessage{BlockNum: maxWideBlockNum + 1}, true)
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = newExecutingLink(types.ExecutingMessage{BlockNum: math.MaxUint64, LogIdx: maxLogIdx}, false)
+	require.NoError(t, err, "block numbers of 8 bytes fit without wide log indices")
+}
+
+func TestLogLimits(t *testing.T) {
+	open := func(t *testing.T, path string, opts Options) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts,
+			entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		return db
+	}
+	block0 := eth.BlockID{Hash: createHash(0), Number: 0}
+	block1 := eth.BlockID{Hash: createHash(1), Number: 1}
+
+	t.Run("MaxLogsPerBlock", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		opts := DefaultOptions()
+		opts.MaxLogsPerBlock = 3
+		db := open(t, path, opts)
+		require.NoError(t, db.SealBlock(createHash(-1), block0, 500))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, db.AddLog(createHash(100+i), block0, uint32(i), nil))
+		}
+		require.ErrorIs(t, db.AddLog(createHash(103), block0, 3, nil), ErrTooManyLogs)
+		require.ErrorIs(t, db.AddDepositEvent(createHash(103), block0, 3), ErrTooManyLogs)
+		err := db.WriteBatch(func(b Batch) error {
+			return b.AddLog(createHash(103), block0, 3, nil)
+		})
+		require.ErrorIs(t, err, ErrTooManyLogs)
+		require.NoError(t, db.SealBlock(createHash(0), block1, 501))
+		requireContains(t, db, 1, 2, createHash(102))
+		require.NoError(t, db.Close())
+
+		// the limit is not stored, so it can be changed when the DB is opened again
+		db = open(t, path, DefaultOptions())
+		defer db.Close()
+		require.EqualValues(t, math.MaxUint32, db.maxLogsPerBlock)
+		for i := 0; i < 4; i++ {
+			require.NoError(t, db.AddLog(createHash(200+i), block1, uint32(i), nil))
+		}
+	})
+
+	t.Run("LogIdxOverflow", func(t *testing.T) {
+		db := open(t, filepath.Join(t.TempDir(), "test.db"), DefaultOptions())
+		defer db.Close()
+		require.NoError(t, db.SealBlock(createHash(-1), block0, 500))
+		msg := types.ExecutingMessage{Chain: 3, BlockNum: 10, LogIdx: maxLogIdx + 1, Timestamp: 400, Hash: createHash(9)}
+		require.ErrorIs(t, db.AddLog(createHash(100), block0, 0, &msg), ErrOverflow)
+		// the log was not applied, so it can still be added
+		msg.LogIdx = maxLogIdx
+		require.NoError(t, db.AddLog(createHash(100), block0, 0, &msg))
+		require.NoError(t, db.SealBlock(createHash(0), block1, 501))
+		msg.Hash = storedHash(msg.Hash, false)
+		requireContains(t, db, 1, 0, createHash(100), msg)
+	})
+
+	t.Run("WideLogIndices", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db := open(t, path, Options{SearchCheckpointFrequency: defaultSearchCheckpointFrequency, WideLogIndices: true})
+		require.NoError(t, db.SealBlock(createHash(-1), block0, 500))
+		msg := types.ExecutingMessage{Chain: 3, BlockNum: maxWideBlockNum + 1, LogIdx: math.MaxUint32, Timestamp: 400, Hash: createHash(9)}
+		require.ErrorIs(t, db.AddLog(createHash(100), block0, 0, &msg), ErrOverflow)
+		msg.BlockNum = maxWideBlockNum
+		require.NoError(t, db.AddLog(createHash(100), block0, 0, &msg))
+		require.NoError(t, db.SealBlock(createHash(0), block1, 501))
+		require.NoError(t, db.Close())
+
+		h, ok, err := entrydb.ReadHeader(path)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, wideLogIdxHeaderVersion, h[1], "should be stored with the format version of wide log indices")
+
+		db = open(t, path, DefaultOptions())
+		defer db.Close()
+		require.True(t, db.wideLogIndices, "should use the format the database was created with")
+		msg.Hash = storedHash(msg.Hash, false)
+		requireContains(t, db, 1, 0, createHash(100), msg)
+		require.NoError(t, db.Verify())
+	})
+}
@@ -0,0 +1,181 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+// migrationBatchSize is the number of entries that are copied at a time, when a migration converts a database.
+const migrationBatchSize = 4096
+
+// migration upgrades a database of the previous version to its version.
+type migration struct {
+	version     byte
+	description string
+	migrate     func(logger log.Logger, path string, walCfg entrydb.WALConfig) error
+}
+
+// migrations upgrade databases of earlier versions to headerVersion, ordered by version.
+// Version 0 is the format of databases that were created without a header.
+var migrations = []migration{
+	{version: 1, description: "add header and entry checksums", migrate: migrateAddHeader},
+	{version: 2, description: "move entries into segment files", migrate: migrateSegments},
+}
+
+// migrate upgrades the database at the given path to headerVersion, if it was created with an earlier version.
+// A database that does not exist yet is created with the current version, and is not migrated.
+func migrate(logger log.Logger, path string, walCfg entrydb.WALConfig) error {
+	version, ok, err := dbVersion(path)
+	if err != nil || !ok {
+		return err
+	}
+	if version > wideLogIdxHeaderVersion {
+		return fmt.Errorf("database version %d is newer than the supported version %d", version, wideLogIdxHeaderVersion)
+	}
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		logger.Info("Migrating database", "path", path, "from", version, "to", m.version, "migration", m.description)
+		if err := m.migrate(logger, path, walCfg); err != nil {
+			return fmt.Errorf("failed to migrate database from version %d to %d: %w", version, m.version, err)
+		}
+		version = m.version
+	}
+	return nil
+}
+
+// dbVersion returns the version of the database at the given path, or false if there is no database yet.
+func dbVersion(path string) (byte, bool, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, fmt.Errorf("failed to stat database: %w", err)
+	}
+	if info.Size() < entrydb.HeaderSize {
+		// Too small to hold a header or an entry, so it is initialized as a new database.
+		return 0, false, nil
+	}
+	h, ok, err := entrydb.ReadHeader(path)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return 0, true, nil
+	}
+	return h[1], true, nil
+}
+
+// migrateAddHeader converts a database without a header into one with a header and entry checksums.
+// Databases without a header were always created with the default options.
+// The entries are copied into a new file, which then replaces the database,
+// such that the database remains intact if the migration is interrupted.
+func migrateAddHeader(logger log.Logger, path string, walCfg entrydb.WALConfig) error {
+	// Open the database with its write-ahead log, to complete any partially written batch first.
+	src, err := entrydb.NewEntryDBWithWAL(logger, path, entrydb.Header{}, walCfg)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	dstPath := path + ".migrate"
+	// Discard the output of any earlier, interrupted, migration.
+	if err := os.Remove(dstPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Join(fmt.Errorf("failed to remove output of interrupted migration: %w", err), src.Close())
+	}
+	h := DefaultOptions().header()
+	h[1] = 1
+	dst, err := entrydb.NewEntryDB(logger, dstPath, h)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to create migrated database: %w", err), src.Close())
+	}
+	if err := copyEntries(src, dst); err != nil {
+		return errors.Join(err, dst.Close(), src.Close())
+	}
+	if err := dst.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close migrated database: %w", err), src.Close())
+	}
+	// The write-ahead log is reset when closed, and remains valid for the migrated database,
+	// as it holds the same number of entries.
+	if err := src.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	if err := os.Rename(dstPath, path); err != nil {
+		return fmt.Errorf("failed to replace database with migrated database: %w", err)
+	}
+	return nil
+}
+
+// migrateSegments converts a database with all entries in a single file into a database with segment files.
+// The file becomes the first segment, and is then replaced by a file with only the header.
+// The file is linked as the first segment, rather than moved, such that the database remains intact
+// if the migration is interrupted.
+func migrateSegments(logger log.Logger, path string, walCfg entrydb.WALConfig) error {
+	// Open the database with its write-ahead log, to complete any partially written batch first.
+	// The write-ahead log is reset when closed, so no entries are lost when it is removed.
+	src, err := entrydb.NewEntryDBWithWAL(logger, path, entrydb.Header{}, walCfg)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	h, _ := src.Header()
+	if err := src.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	if err := os.Remove(path + ".wal"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove write-ahead log: %w", err)
+	}
+	segmentPath := entrydb.SegmentPath(path, 0)
+	// Discard the first segment of any earlier, interrupted, migration.
+	if err := os.Remove(segmentPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove segment of interrupted migration: %w", err)
+	}
+	if err := os.Link(path, segmentPath); err != nil {
+		return fmt.Errorf("failed to link database as first segment: %w", err)
+	}
+	dstPath := path + ".migrate"
+	if err := os.Remove(dstPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove output of interrupted migration: %w", err)
+	}
+	h[1] = 2
+	dst, err := entrydb.NewEntryDB(logger, dstPath, h)
+	if err != nil {
+		return fmt.Errorf("failed to create migrated database: %w", err)
+	}
+	if err := dst.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync migrated database: %w", err), dst.Close())
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to close migrated database: %w", err)
+	}
+	if err := os.Rename(dstPath, path); err != nil {
+		return fmt.Errorf("failed to replace database with migrated database: %w", err)
+	}
+	return nil
+}
+
+// copyEntries appends all entries of src to dst, and syncs dst.
+func copyEntries(src, dst *entrydb.EntryDB) error {
+	batch := make([]entrydb.Entry, 0, migrationBatchSize)
+	for idx := entrydb.EntryIdx(0); idx <= src.LastEntryIdx(); idx++ {
+		entry, err := src.Read(idx)
+		if err != nil {
+			return fmt.Errorf("failed to read entry %v: %w", idx, err)
+		}
+		batch = append(batch, entry)
+		if len(batch) == migrationBatchSize || idx == src.LastEntryIdx() {
+			if err := dst.Append(batch...); err != nil {
+				return fmt.Errorf("failed to write migrated entries: %w", err)
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := dst.Sync(); err != nil {
+		return fmt.Errorf("failed to sync migrated database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+func TestMigrate(t *testing.T) {
+	// writeWithoutHeader writes a database as created by the version without a header.
+	writeWithoutHeader := func(t *testing.T, path string) {
+		var data []byte
+		for _, entry := range []entrydb.Entry{
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createTruncatedHash(300)).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
+			newInitiatingEvent(createTruncatedHash(2), 0).encode(),
+		} {
+			data = append(data, entry[:]...)
+		}
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+	}
+
+	t.Run("AddHeader", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		writeWithoutHeader(t, path)
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		requireContains(t, db, 1, 0, createHash(1))
+		requireContains(t, db, 1, 1, createHash(2))
+		require.NoError(t, db.Close())
+
+		h, ok, err := entrydb.ReadHeader(path)
+		require.NoError(t, err)
+		require.True(t, ok, "should have added a header")
+		require.Equal(t, headerVersion, h[1])
+		require.True(t, h.Checksums(), "should have added checksums")
+		opts, err := optionsFromHeader(h)
+		require.NoError(t, err)
+		require.Equal(t, DefaultOptions(), opts)
+		_, err = os.Stat(path + ".migrate")
+		require.ErrorIs(t, err, os.ErrNotExist, "should have moved the migrated database into place")
+	})
+
+	t.Run("MoveEntriesIntoSegments", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		// a database of version 1 holds its entries after the header
+		h := Options{SearchCheckpointFrequency: 7}.header()
+		h[1] = 1
+		src, err := entrydb.NewEntryDB(logger, path, h)
+		require.NoError(t, err)
+		require.NoError(t, src.Append(
+			newSearchCheckpoint(0, 0, 100).encode(),
+			newCanonicalHash(createTruncatedHash(300)).encode(),
+			newInitiatingEvent(createTruncatedHash(1), 0).encode(),
+		))
+		require.NoError(t, src.Close())
+		// an earlier migration was interrupted after linking the first segment
+		require.NoError(t, os.WriteFile(entrydb.SegmentPath(path, 0), []byte{0xff, 1, 2, 3}, 0o644))
+
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		require.EqualValues(t, 7, db.checkpointFrequency, "should retain the options of the database")
+		requireContains(t, db, 1, 0, createHash(1))
+		require.NoError(t, db.Close())
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.EqualValues(t, entrydb.HeaderSize, info.Size(), "should have moved the entries into a segment")
+		h, ok, err := entrydb.ReadHeader(path)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, headerVersion, h[1])
+	})
+
+	t.Run("DiscardInterruptedMigration", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		writeWithoutHeader(t, path)
+		// an earlier migration was interrupted while writing the migrated database
+		require.NoError(t, os.WriteFile(path+".migrate", []byte{0xff, 1, 2, 3}, 0o644))
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		defer db.Close()
+		require.EqualValues(t, 4, db.store.Size())
+		requireContains(t, db, 1, 1, createHash(2))
+	})
+
+	t.Run("CurrentVersion", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, migrate(logger, path, entrydb.DefaultWALConfig()))
+		after, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, info.ModTime(), after.ModTime(), "should not migrate a database of the current version")
+	})
+
+	t.Run("ErrorWhenNewerVersion", func(t *testing.T) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		path := filepath.Join(t.TempDir(), "file.db")
+		db, err := NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[1] = wideLogIdxHeaderVersion + 1
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+		_, err = NewFromFile(logger, &stubMetrics{}, path, false, DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.ErrorContains(t, err, "newer than the supported version")
+	})
+}
@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+// ReadOnlyDB is a DB that is opened read-only, such that it can be inspected
+// while the supervisor that writes to it is running, e.g. by an analysis process or a replica.
+// It only exposes the reads of a Snapshot, and the entries that it reads are fixed when it is opened.
+type ReadOnlyDB struct {
+	Snapshot
+}
+
+// OpenReadOnly opens the existing database at the given path read-only, with the options it was created with.
+// The database may be open for writing by another process: the last block is the last block
+// that was completely stored when the database was opened, and later writes are not visible.
+// The database must be opened again to read later writes, or once it was rewound by the writer.
+// The database is not migrated, so it must be of the current version.
+func OpenReadOnly(logger log.Logger, m Metrics, path string) (*ReadOnlyDB, error) {
+	store, err := entrydb.OpenSegmentedDBReadOnly(logger, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DB: %w", err)
+	}
+	h, _ := store.Header()
+	opts, err := optionsFromHeader(h)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to read options of DB: %w", err), store.Close())
+	}
+	ro := &readOnlyStore{store: store, lastEntryIdx: store.LastEntryIdx()}
+	db := &DB{
+		log:                 logger,
+		m:                   m,
+		store:               ro,
+		checkpointFrequency: entrydb.EntryIdx(opts.SearchCheckpointFrequency),
+		fullHashes:          opts.FullHashes,
+		wideLogIndices:      opts.WideLogIndices,
+		checkpoints:         &checkpointIndex{},
+	}
+	// The writer may be in the middle of sealing a block, so entries after the last sealed block are ignored.
+	last, err := db.lastSealedEntryIdx()
+	if err != nil {
+		return nil, errors.Join(err, store.Close())
+	}
+	ro.lastEntryIdx = last
+	if err := db.init(false); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to init database: %w", err), store.Close())
+	}
+	return &ReadOnlyDB{Snapshot{db: db}}, nil
+}
+
+// Verify checks the invariants of the entries of the database, see DB.Verify.
+func (r *ReadOnlyDB) Verify() error {
+	return r.db.Verify()
+}
+
+func (r *ReadOnlyDB) Close() error {
+	return r.db.Close()
+}
+
+// readOnlyStore is a read-only view of the entries of a store, up to and including the last entry of a sealed block.
+type readOnlyStore struct {
+	store        EntryStore
+	lastEntryIdx entrydb.EntryIdx
+}
+
+func (s *readOnlyStore) Size() int64 {
+	return int64(s.lastEntryIdx) + 1
+}
+
+func (s *readOnlyStore) LastEntryIdx() entrydb.EntryIdx {
+	return s.lastEntryIdx
+}
+
+func (s *readOnlyStore) FirstEntryIdx() entrydb.EntryIdx {
+	return s.store.FirstEntryIdx()
+}
+
+func (s *readOnlyStore) Read(idx entrydb.EntryIdx) (entrydb.Entry, error) {
+	if idx > s.lastEntryIdx {
+		return entrydb.Entry{}, io.EOF
+	}
+	return s.store.Read(idx)
+}
+
+func (s *readOnlyStore) Append(entries ...entrydb.Entry) error {
+	return entrydb.ErrReadOnly
+}
+
+func (s *readOnlyStore) Truncate(idx entrydb.EntryIdx) error {
+	return entrydb.ErrReadOnly
+}
+
+func (s *readOnlyStore) Prune(idx entrydb.EntryIdx) error {
+	return entrydb.ErrReadOnly
+}
+
+func (s *readOnlyStore) Close() error {
+	return s.store.Close()
+}
+
+var _ EntryStore = (*readOnlyStore)(nil)
@@ -0,0 +1,93 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+func TestOpenReadOnly(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: 16, FullHashes: true}
+	// addBlocks adds the given blocks, with a log each
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			if i > 0 {
+				parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+				require.NoError(t, db.AddLog(createHash(100*i), parent, 0, nil))
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+	}
+	openReadOnly := func(t *testing.T, path string) *ReadOnlyDB {
+		db, err := OpenReadOnly(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, db.Close())
+		})
+		return db
+	}
+	requireLatest := func(t *testing.T, db *ReadOnlyDB, n int) {
+		latest, ok := db.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.EqualValues(t, n, latest)
+		for i := 0; i <= n; i++ {
+			_, err := db.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)})
+			require.NoError(t, err)
+		}
+		_, err := db.Contains(uint64(n), 0, createHash(100*n))
+		require.NoError(t, err)
+		require.NoError(t, db.Verify())
+	}
+
+	t.Run("AlongsideWriter", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		writer, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.SegmentConfig{Size: 50}, DefaultReplayConfig())
+		require.NoError(t, err)
+		defer writer.Close()
+		addBlocks(t, writer, 0, 30)
+		// a block that is not sealed yet is not visible
+		require.NoError(t, writer.AddLog(createHash(3100), eth.BlockID{Hash: createHash(30), Number: 30}, 0, nil))
+
+		db := openReadOnly(t, path)
+		requireLatest(t, db, 30)
+
+		_, err = NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.SegmentConfig{Size: 50}, DefaultReplayConfig())
+		require.ErrorIs(t, err, entrydb.ErrLocked)
+
+		// later writes are only visible once opened again
+		require.NoError(t, writer.SealBlock(createHash(30), eth.BlockID{Hash: createHash(31), Number: 31}, 531))
+		addBlocks(t, writer, 32, 40)
+		requireLatest(t, db, 30)
+		requireLatest(t, openReadOnly(t, path), 40)
+	})
+
+	t.Run("NoWrites", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		writer, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path, false, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		addBlocks(t, writer, 0, 5)
+		require.NoError(t, writer.Close())
+
+		db := openReadOnly(t, path)
+		requireLatest(t, db, 5)
+		// writes are not exposed, and are refused by the underlying store
+		require.ErrorIs(t, db.db.store.Append(newSearchCheckpoint(6, 0, 506).encode()), entrydb.ErrReadOnly)
+		require.ErrorIs(t, db.db.store.Truncate(0), entrydb.ErrReadOnly)
+		require.ErrorIs(t, db.db.store.(*readOnlyStore).store.Append(newSearchCheckpoint(6, 0, 506).encode()), entrydb.ErrReadOnly)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		_, err := OpenReadOnly(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+		_, err = os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist, "should not create the database")
+	})
+}
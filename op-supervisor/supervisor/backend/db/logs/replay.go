@@ -0,0 +1,286 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+// replayChunkSize is the number of search checkpoint intervals that are replayed between progress reports.
+const replayChunkSize = 256
+
+// replayLogInterval is the minimum time between logs of the progress of a replay.
+const replayLogInterval = 8 * time.Second
+
+// tailStateVersion is the version of the format of the tail-state file.
+const tailStateVersion = 1
+
+// ReplayConfig configures the replay of the entries of the DB when it is opened.
+// The entries of each search checkpoint interval without a stored bloom filter are replayed to build the filter,
+// and the entries after the last search checkpoint are replayed to restore the state after the last entry,
+// unless that state was stored when the DB was last closed.
+type ReplayConfig struct {
+	// Workers is the number of search checkpoint intervals that are replayed in parallel.
+	Workers int
+	// Progress, if not nil, is called after each chunk of replayed intervals,
+	// with the number of intervals that were replayed, and the total number of intervals to replay.
+	Progress func(done int64, total int64)
+}
+
+func DefaultReplayConfig() ReplayConfig {
+	return ReplayConfig{Workers: runtime.GOMAXPROCS(0)}
+}
+
+// replayProgress reports the progress of a replay to the metrics, the progress callback and the log.
+type replayProgress struct {
+	db      *DB
+	total   int64
+	start   time.Time
+	lastLog time.Time
+}
+
+func (db *DB) newReplayProgress(total int64) *replayProgress {
+	now := time.Now()
+	if total > 0 {
+		db.log.Info("Replaying log DB entries", "intervals", total, "workers", max(db.replayCfg.Workers, 1))
+	}
+	return &replayProgress{db: db, total: total, start: now, lastLog: now}
+}
+
+func (p *replayProgress) report(done int64) {
+	p.db.m.RecordDBReplayProgress(done, p.total)
+	if p.db.replayCfg.Progress != nil {
+		p.db.replayCfg.Progress(done, p.total)
+	}
+	if p.total == 0 {
+		return
+	}
+	if done == p.total {
+		p.db.log.Info("Replayed log DB entries", "intervals", p.total, "elapsed", time.Since(p.start))
+	} else if time.Since(p.lastLog) >= replayLogInterval {
+		p.db.log.Info("Replaying log DB entries", "done", done, "total", p.total, "elapsed", time.Since(p.start))
+		p.lastLog = time.Now()
+	}
+}
+
+// buildBlooms builds and stores the bloom filters of the intervals from the given interval,
+// up to but not including the given interval, which must all be complete.
+// The intervals are replayed in chunks, and the intervals of a chunk are replayed in parallel.
+func (db *DB) buildBlooms(from, to int64) error {
+	progress := db.newReplayProgress(to - from)
+	filters := make([]bloomFilter, replayChunkSize)
+	for chunk := from; chunk < to; chunk += replayChunkSize {
+		n := min(replayChunkSize, to-chunk)
+		var g errgroup.Group
+		g.SetLimit(max(db.replayCfg.Workers, 1))
+		for i := int64(0); i < n; i++ {
+			i := i
+			g.Go(func() error {
+				filter, err := db.buildBloom(chunk + i)
+				filters[i] = filter
+				return err
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+		for i := int64(0); i < n; i++ {
+			db.blooms.current = filters[i]
+			db.blooms.store(chunk + i)
+		}
+		progress.report(chunk + n - from)
+	}
+	db.blooms.current = bloomFilter{}
+	progress.report(to - from)
+	return nil
+}
+
+// buildBloom returns the bloom filter of the log hashes of the given complete interval.
+func (db *DB) buildBloom(interval int64) (bloomFilter, error) {
+	var filter bloomFilter
+	start := entrydb.EntryIdx(interval) * db.checkpointFrequency
+	for idx := start; idx < start+db.checkpointFrequency; idx++ {
+		entry, err := db.store.Read(idx)
+		if err != nil {
+			return bloomFilter{}, fmt.Errorf("failed to read entry %v to build bloom filter: %w", idx, err)
+		}
+		if err := filter.addEntry(entry); err != nil {
+			return bloomFilter{}, fmt.Errorf("failed to add entry %v to bloom filter: %w", idx, err)
+		}
+	}
+	return filter, nil
+}
+
+// tailState is the state after the last entry of the DB, as stored when the DB was closed,
+// such that the entries after the last search checkpoint do not have to be replayed when the DB is opened again.
+type tailState struct {
+	Version        uint8            `json:"version"`
+	NextEntryIndex entrydb.EntryIdx `json:"nextEntryIndex"`
+	// LastEntry is the last entry of the DB, to detect that the entries changed after the state was stored.
+	LastEntry hexutil.Bytes `json:"lastEntry"`
+
+	BlockHash   common.Hash              `json:"blockHash"`
+	BlockNum    uint64                   `json:"blockNum"`
+	Timestamp   uint64                   `json:"timestamp"`
+	LogsSince   uint32                   `json:"logsSince"`
+	DerivedFrom eth.BlockID              `json:"derivedFrom"`
+	LogHash     common.Hash              `json:"logHash"`
+	Deposit     bool                     `json:"deposit"`
+	ExecMsgs    []types.ExecutingMessage `json:"execMsgs"`
+	ExecMsgIdx  int                      `json:"execMsgIdx"`
+	Need        entrydb.EntryTypeFlag    `json:"need"`
+	Extending   entrydb.EntryType        `json:"extending"`
+
+	// Bloom is the bloom filter of the last interval, that is not stored with the filters of the complete intervals.
+	Bloom hexutil.Bytes `json:"bloom,omitempty"`
+}
+
+// storeTailState writes the state after the last entry to the tail-state file, if the DB has one.
+// The state is only stored once, as the DB is closed after.
+// Failures are logged, as the state can always be replayed from the entries instead.
+func (db *DB) storeTailState() {
+	db.rwLock.Lock()
+	defer db.rwLock.Unlock()
+	if db.tailStatePath == "" {
+		return
+	}
+	path := db.tailStatePath
+	db.tailStatePath = ""
+	l := &db.lastEntryContext
+	// The data of pending safe heads and L1 blocks is not stored, so the state is replayed instead.
+	if len(l.out) != 0 || l.need.Any(entrydb.FlagSafeHead|entrydb.FlagL1Block) || l.nextEntryIndex == 0 {
+		return
+	}
+	last, err := db.store.Read(l.nextEntryIndex - 1)
+	if err != nil {
+		db.log.Warn("Failed to read last entry to store tail state", "err", err)
+		return
+	}
+	state := tailState{
+		Version:        tailStateVersion,
+		NextEntryIndex: l.nextEntryIndex,
+		LastEntry:      last[:],
+		BlockHash:      l.blockHash,
+		BlockNum:       l.blockNum,
+		Timestamp:      l.timestamp,
+		LogsSince:      l.logsSince,
+		DerivedFrom:    l.derivedFrom,
+		LogHash:        l.logHash,
+		Deposit:        l.deposit,
+		ExecMsgs:       l.execMsgs,
+		ExecMsgIdx:     l.execMsgIdx,
+		Need:           l.need,
+		Extending:      l.extending,
+	}
+	if db.blooms != nil {
+		state.Bloom = db.blooms.current[:]
+	}
+	if err := jsonutil.WriteJSON(state, ioutil.ToAtomicFile(path, 0o644)); err != nil {
+		db.log.Warn("Failed to store tail state", "path", path, "err", err)
+	}
+}
+
+// restoreTailState restores the state after the last entry from the tail-state file,
+// if it was stored when the DB was last closed, and the entries did not change since.
+// The file is removed, such that it cannot be used once the DB is written to.
+// It returns nil if the state is not restored, and must be replayed from the entries instead.
+func (db *DB) restoreTailState(trimToLastSealed bool) *tailState {
+	state, err := jsonutil.LoadJSON[tailState](db.tailStatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if rmErr := os.Remove(db.tailStatePath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		db.log.Warn("Failed to remove tail state", "path", db.tailStatePath, "err", rmErr)
+	}
+	if err != nil {
+		db.log.Warn("Ignoring unreadable tail state", "path", db.tailStatePath, "err", err)
+		return nil
+	}
+	if err := db.checkTailState(state, trimToLastSealed); err != nil {
+		db.log.Info("Replaying tail state", "reason", err)
+		return nil
+	}
+	db.lastEntryContext = logContext{
+		checkpointFrequency: db.checkpointFrequency,
+		fullHashes:          db.fullHashes,
+		wideLogIndices:      db.wideLogIndices,
+		nextEntryIndex:      state.NextEntryIndex,
+		blockHash:           state.BlockHash,
+		blockNum:            state.BlockNum,
+		timestamp:           state.Timestamp,
+		logsSince:           state.LogsSince,
+		derivedFrom:         state.DerivedFrom,
+		logHash:             state.LogHash,
+		deposit:             state.Deposit,
+		execMsgs:            state.ExecMsgs,
+		execMsgIdx:          state.ExecMsgIdx,
+		need:                state.Need,
+		extending:           state.Extending,
+	}
+	db.publishTail()
+	db.updateEntryCountMetric()
+	if err := db.syncCheckpoints(); err != nil {
+		db.log.Warn("Failed to index search checkpoints", "err", err)
+		return nil
+	}
+	db.log.Debug("Restored tail state", "nextEntryIndex", state.NextEntryIndex)
+	return state
+}
+
+// checkTailState returns an error if the tail state does not match the entries of the DB.
+func (db *DB) checkTailState(state *tailState, trimToLastSealed bool) error {
+	if state.Version != tailStateVersion {
+		return fmt.Errorf("unsupported version %d", state.Version)
+	}
+	if state.NextEntryIndex != db.lastEntryIdx()+1 {
+		return fmt.Errorf("stored at entry %d, but DB has %d entries", state.NextEntryIndex, db.lastEntryIdx()+1)
+	}
+	last, err := db.store.Read(db.lastEntryIdx())
+	if err != nil {
+		return fmt.Errorf("failed to read last entry: %w", err)
+	}
+	if string(state.LastEntry) != string(last[:]) {
+		return errors.New("last entry changed")
+	}
+	if state.ExecMsgIdx < 0 || state.ExecMsgIdx > len(state.ExecMsgs) {
+		return fmt.Errorf("invalid executing message index %d of %d", state.ExecMsgIdx, len(state.ExecMsgs))
+	}
+	if state.Need.Any(entrydb.FlagSafeHead | entrydb.FlagL1Block) {
+		return errors.New("pending entries are not stored")
+	}
+	if trimToLastSealed {
+		sealed, err := db.lastSealedEntryIdx()
+		if err != nil {
+			return err
+		}
+		if sealed != db.lastEntryIdx() {
+			return errors.New("entries after the last sealed block are to be trimmed")
+		}
+	}
+	return nil
+}
+
+// restoreBlooms restores the filter of the last interval from the tail state,
+// and returns false if the filters are to be synced with the entries instead.
+func (db *DB) restoreBlooms(state *tailState) bool {
+	if db.blooms == nil || state == nil || len(state.Bloom) != bloomSize {
+		return false
+	}
+	if db.blooms.count != int64(db.lastEntryIdx()/db.checkpointFrequency) {
+		return false
+	}
+	copy(db.blooms.current[:], state.Bloom)
+	return true
+}
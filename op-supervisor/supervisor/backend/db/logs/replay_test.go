@@ -0,0 +1,160 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+func TestReplay(t *testing.T) {
+	opts := Options{SearchCheckpointFrequency: minFullHashesSearchCheckpointFrequency, FullHashes: true}
+	open := func(t *testing.T, path string, trimToLastSealed bool, cfg ReplayConfig) (*DB, *stubMetrics) {
+		m := &stubMetrics{}
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), m, path, trimToLastSealed, opts, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), cfg)
+		require.NoError(t, err)
+		return db, m
+	}
+	// addBlocks adds the blocks from the given block up to and including the given block,
+	// with a deposit event, a log and a log with an executing message each
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			block := eth.BlockID{Hash: createHash(i), Number: uint64(i)}
+			require.NoError(t, db.WriteBatch(func(b Batch) error {
+				if i > 0 {
+					parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+					if err := b.AddDepositEvent(createHash(100*i), parent, 0); err != nil {
+						return err
+					}
+					if err := b.AddLog(createHash(100*i+1), parent, 1, nil); err != nil {
+						return err
+					}
+					execMsg := types.ExecutingMessage{Chain: 3, BlockNum: uint64(i), LogIdx: 1, Timestamp: 500, Hash: createHash(i)}
+					if err := b.AddLog(createHash(100*i+2), parent, 2, &execMsg); err != nil {
+						return err
+					}
+				}
+				return b.SealBlock(createHash(i-1), block, 500+uint64(i))
+			}))
+		}
+	}
+	// requireReplayed checks that the restored state and bloom filter match those replayed from the entries
+	requireReplayed := func(t *testing.T, db *DB) {
+		restored := db.lastEntryContext
+		bloom := db.blooms.current
+		require.NoError(t, db.init(false))
+		require.Equal(t, db.lastEntryContext, restored)
+		require.Equal(t, db.blooms.current, bloom)
+	}
+
+	t.Run("RestoreTailState", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, m := open(t, path, true, DefaultReplayConfig())
+		addBlocks(t, db, 0, 20)
+		require.EqualValues(t, 1, m.opens)
+		require.Zero(t, m.restoredOpens)
+		require.NoError(t, db.Close())
+		require.FileExists(t, path+".state")
+
+		db, m = open(t, path, true, DefaultReplayConfig())
+		defer db.Close()
+		require.EqualValues(t, 1, m.restoredOpens)
+		require.NoFileExists(t, path+".state", "should not be used once the DB is written to")
+		requireReplayed(t, db)
+		addBlocks(t, db, 21, 25)
+		for i := 1; i <= 25; i++ {
+			_, err := db.ContainsDeposit(uint64(i), 0, createHash(100*i))
+			require.NoError(t, err)
+			requireContains(t, db, uint64(i), 1, createHash(100*i+1))
+		}
+	})
+
+	t.Run("StaleTailState", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, _ := open(t, path, false, DefaultReplayConfig())
+		addBlocks(t, db, 0, 20)
+		require.NoError(t, db.Close())
+		stale, err := os.ReadFile(path + ".state")
+		require.NoError(t, err)
+
+		db, _ = open(t, path, false, DefaultReplayConfig())
+		addBlocks(t, db, 21, 22)
+		require.NoError(t, db.Close())
+		require.NoError(t, os.WriteFile(path+".state", stale, 0o644))
+
+		db, m := open(t, path, false, DefaultReplayConfig())
+		defer db.Close()
+		require.Zero(t, m.restoredOpens)
+		latest, ok := db.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.EqualValues(t, 22, latest)
+		requireContains(t, db, 22, 2, createHash(2202), types.ExecutingMessage{Chain: 3, BlockNum: 22, LogIdx: 1, Timestamp: 500, Hash: createHash(22)})
+	})
+
+	t.Run("TrimToLastSealed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, _ := open(t, path, false, DefaultReplayConfig())
+		addBlocks(t, db, 0, 10)
+		// an empty block, such that the log after it is not followed by a search checkpoint,
+		// that would repeat the canonical hash of the last block
+		require.NoError(t, db.SealBlock(createHash(10), eth.BlockID{Hash: createHash(11), Number: 11}, 511))
+		sealed := db.NextIndex()
+		require.Less(t, sealed%db.checkpointFrequency, db.checkpointFrequency-2)
+		require.NoError(t, db.AddLog(createHash(1200), eth.BlockID{Hash: createHash(11), Number: 11}, 0, nil))
+		require.NoError(t, db.Close())
+
+		// the state is restored if the entries after the last sealed block are kept
+		db, m := open(t, path, false, DefaultReplayConfig())
+		require.EqualValues(t, 1, m.restoredOpens)
+		require.Greater(t, db.NextIndex(), sealed)
+		require.NoError(t, db.Close())
+
+		db, m = open(t, path, true, DefaultReplayConfig())
+		defer db.Close()
+		require.Zero(t, m.restoredOpens)
+		require.Equal(t, sealed, db.NextIndex())
+	})
+
+	t.Run("ReplayBloomFilters", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.db")
+		db, _ := open(t, path, false, DefaultReplayConfig())
+		addBlocks(t, db, 0, 500)
+		require.NoError(t, db.Close())
+		blooms, err := os.ReadFile(path + ".bloom")
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(path+".bloom"))
+
+		var mu sync.Mutex
+		var reports [][2]int64
+		db, m := open(t, path, false, ReplayConfig{Workers: 4, Progress: func(done int64, total int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			reports = append(reports, [2]int64{done, total})
+		}})
+		defer db.Close()
+		total := int64(len(blooms) / bloomRecordSize)
+		require.Greater(t, total, int64(replayChunkSize), "should replay multiple chunks")
+		require.Greater(t, len(reports), 2)
+		for i, r := range reports {
+			require.Equal(t, total, r[1])
+			if i > 0 {
+				require.GreaterOrEqual(t, r[0], reports[i-1][0])
+			}
+		}
+		require.Equal(t, [2]int64{total, total}, reports[len(reports)-1])
+		require.Equal(t, total, m.replayDone)
+		require.Equal(t, total, m.replayTotal)
+		rebuilt, err := os.ReadFile(path + ".bloom")
+		require.NoError(t, err)
+		require.Equal(t, blooms, rebuilt)
+		requireReplayed(t, db)
+	})
+}
@@ -0,0 +1,183 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	supTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+// ErrStaleSnapshot happens when a snapshot is read after the DB was rewound past the state of the snapshot.
+// A new snapshot has to be taken to read the DB after the rewind.
+var ErrStaleSnapshot = errors.New("stale snapshot")
+
+var errReadOnlySnapshot = errors.New("snapshot is read-only")
+
+// Snapshot is an immutable view of the DB, as of the last write before the snapshot was taken.
+// Snapshots are read concurrently with writes to the DB, and with each other,
+// such that readers are not blocked while blocks are being sealed.
+// Entries that are written after the snapshot was taken are not visible to it.
+// Reads return ErrStaleSnapshot once the DB is rewound after the snapshot was taken.
+type Snapshot struct {
+	db *DB
+}
+
+// publishTail makes the current state of the DB available to new snapshots.
+// The state is copied, such that later writes do not affect the snapshots that use it.
+// It must be called with the write lock held, after entries were written or the state was initialized.
+func (db *DB) publishTail() {
+	tail := db.lastEntryContext
+	tail.execMsgs = slices.Clone(tail.execMsgs)
+	tail.out = nil
+	db.tail.Store(&tail)
+}
+
+// Snapshot returns an immutable view of the DB, as of the last write.
+// Taking a snapshot does not wait for writes that are in progress.
+func (db *DB) Snapshot() *Snapshot {
+	tail := db.tail.Load()
+	return &Snapshot{db: &DB{
+		log: db.log,
+		m:   db.m,
+		store: &snapshotStore{
+			store:        db.store,
+			lastEntryIdx: tail.nextEntryIndex - 1,
+			rewinds:      db.rewinds.Load(),
+			db:           db,
+		},
+		checkpointFrequency: db.checkpointFrequency,
+		fullHashes:          db.fullHashes,
+		wideLogIndices:      db.wideLogIndices,
+		// The bloom filters follow the latest state of the DB, so they are not used by snapshots.
+		// The checkpoints are only read up to the last entry of the snapshot, so they are shared.
+		checkpoints:      db.checkpoints,
+		lastEntryContext: *tail,
+	}}
+}
+
+// snapshotStore is a read-only view of the entries of a store, up to and including the last entry of a snapshot.
+type snapshotStore struct {
+	store        EntryStore
+	lastEntryIdx entrydb.EntryIdx
+	// rewinds is the number of times the DB was rewound before the snapshot was taken
+	rewinds uint64
+	db      *DB
+}
+
+func (s *snapshotStore) Size() int64 {
+	return int64(s.lastEntryIdx) + 1
+}
+
+func (s *snapshotStore) LastEntryIdx() entrydb.EntryIdx {
+	return s.lastEntryIdx
+}
+
+func (s *snapshotStore) FirstEntryIdx() entrydb.EntryIdx {
+	return s.store.FirstEntryIdx()
+}
+
+func (s *snapshotStore) Read(idx entrydb.EntryIdx) (entrydb.Entry, error) {
+	if idx > s.lastEntryIdx {
+		return entrydb.Entry{}, io.EOF
+	}
+	entry, err := s.store.Read(idx)
+	// The DB is marked as rewound before entries are removed,
+	// so an entry that was read before the DB was marked is the entry of the snapshot.
+	if s.db.rewinds.Load() != s.rewinds {
+		return entrydb.Entry{}, fmt.Errorf("DB was rewound after the snapshot was taken: %w", ErrStaleSnapshot)
+	}
+	return entry, err
+}
+
+func (s *snapshotStore) Append(entries ...entrydb.Entry) error {
+	return errReadOnlySnapshot
+}
+
+func (s *snapshotStore) Truncate(idx entrydb.EntryIdx) error {
+	return errReadOnlySnapshot
+}
+
+func (s *snapshotStore) Prune(idx entrydb.EntryIdx) error {
+	return errReadOnlySnapshot
+}
+
+func (s *snapshotStore) Close() error {
+	return nil
+}
+
+var _ EntryStore = (*snapshotStore)(nil)
+
+// NextIndex returns the index of the entry after the last entry of the snapshot.
+func (s *Snapshot) NextIndex() entrydb.EntryIdx {
+	return s.db.NextIndex()
+}
+
+// LatestSealedBlockNum returns the block number of the block that was last sealed in the snapshot.
+func (s *Snapshot) LatestSealedBlockNum() (n uint64, ok bool) {
+	return s.db.LatestSealedBlockNum()
+}
+
+// FindSealedBlock finds the requested block in the snapshot, see DB.FindSealedBlock.
+func (s *Snapshot) FindSealedBlock(block eth.BlockID) (nextEntry entrydb.EntryIdx, err error) {
+	return s.db.FindSealedBlock(block)
+}
+
+// FindSealedBlockByTimestamp finds the last sealed block in the snapshot with a timestamp at or before the given timestamp,
+// see DB.FindSealedBlockByTimestamp.
+func (s *Snapshot) FindSealedBlockByTimestamp(timestamp uint64) (block eth.BlockID, blockTime uint64, err error) {
+	return s.db.FindSealedBlockByTimestamp(timestamp)
+}
+
+// Get returns the stored hash of the log in the snapshot, see DB.Get.
+func (s *Snapshot) Get(blockNum uint64, logIdx uint32) (common.Hash, error) {
+	return s.db.Get(blockNum, logIdx)
+}
+
+// Contains returns no error iff the specified logHash is recorded in the snapshot, see DB.Contains.
+func (s *Snapshot) Contains(blockNum uint64, logIdx uint32, logHash common.Hash) (entrydb.EntryIdx, error) {
+	return s.db.Contains(blockNum, logIdx, logHash)
+}
+
+// DerivedFrom returns the L1 block that the given sealed block was derived from, see DB.DerivedFrom.
+func (s *Snapshot) DerivedFrom(block eth.BlockID) (l1 eth.BlockID, err error) {
+	return s.db.DerivedFrom(block)
+}
+
+// SafeHeadAt returns the local-safe or cross-safe head, as last recorded before the given entry index,
+// see DB.SafeHeadAt.
+func (s *Snapshot) SafeHeadAt(entryIdx entrydb.EntryIdx, level supTypes.SafetyLevel) (eth.BlockID, error) {
+	return s.db.SafeHeadAt(entryIdx, level)
+}
+
+// L1BlockAt returns the last L1 block that was registered before the given entry index, see DB.L1BlockAt.
+func (s *Snapshot) L1BlockAt(entryIdx entrydb.EntryIdx) (eth.BlockID, error) {
+	return s.db.L1BlockAt(entryIdx)
+}
+
+// ContainsDeposit returns no error iff the specified logHash is recorded in the snapshot,
+// and was emitted by a deposit transaction, see DB.ContainsDeposit.
+func (s *Snapshot) ContainsDeposit(blockNum uint64, logIdx uint32, logHash common.Hash) (entrydb.EntryIdx, error) {
+	return s.db.ContainsDeposit(blockNum, logIdx, logHash)
+}
+
+// ContainsL1Block returns the index of the entry that registered the given L1 block in the snapshot,
+// see DB.ContainsL1Block.
+func (s *Snapshot) ContainsL1Block(l1 eth.BlockID) (entrydb.EntryIdx, error) {
+	return s.db.ContainsL1Block(l1)
+}
+
+// IteratorStartingAt returns an iterator over the entries of the snapshot, see DB.IteratorStartingAt.
+func (s *Snapshot) IteratorStartingAt(i entrydb.EntryIdx) (Iterator, error) {
+	return s.db.IteratorStartingAt(i)
+}
+
+// IterateRange returns an iterator over the sealed blocks of the snapshot, see DB.IterateRange.
+func (s *Snapshot) IterateRange(fromBlock, toBlock uint64) (BlockIterator, error) {
+	return s.db.IterateRange(fromBlock, toBlock)
+}
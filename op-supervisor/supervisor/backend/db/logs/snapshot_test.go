@@ -0,0 +1,165 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+func TestSnapshot(t *testing.T) {
+	// addBlock adds the logs of block i, i%4 of them, and seals the block
+	addBlock := func(db *DB, i int) error {
+		if i > 0 {
+			parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+			for j := 0; j < i%4; j++ {
+				if err := db.AddLog(createHash(100*i+j), parent, uint32(j), nil); err != nil {
+					return err
+				}
+			}
+		}
+		return db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i))
+	}
+	addBlocks := func(t *testing.T, db *DB, from, to int) {
+		for i := from; i <= to; i++ {
+			require.NoError(t, addBlock(db, i))
+		}
+	}
+	// checkSnapshot checks that the snapshot contains exactly the blocks up to and including the given block
+	checkSnapshot := func(snap *Snapshot, head int) error {
+		if n, _ := snap.LatestSealedBlockNum(); n != uint64(head) {
+			return fmt.Errorf("expected head %d, got %d", head, n)
+		}
+		for i := max(head-5, 0); i <= head; i++ {
+			if _, err := snap.FindSealedBlock(eth.BlockID{Hash: createHash(i), Number: uint64(i)}); err != nil {
+				return fmt.Errorf("failed to find block %d: %w", i, err)
+			}
+			for j := 0; i > 0 && j < i%4; j++ {
+				if _, err := snap.Contains(uint64(i), uint32(j), createHash(100*i+j)); err != nil {
+					return fmt.Errorf("failed to find log %d of block %d: %w", j, i, err)
+				}
+			}
+		}
+		if _, err := snap.FindSealedBlock(eth.BlockID{Hash: createHash(head + 1), Number: uint64(head + 1)}); !errors.Is(err, ErrFuture) {
+			return fmt.Errorf("expected block %d to be in the future, got %w", head+1, err)
+		}
+		return nil
+	}
+	open := func(t *testing.T) *DB {
+		db, err := NewFromFile(testlog.Logger(t, log.LvlInfo), &concurrentMetrics{}, filepath.Join(t.TempDir(), "test.db"),
+			false, Options{SearchCheckpointFrequency: 16}, entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), DefaultReplayConfig())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, db.Close())
+		})
+		return db
+	}
+
+	t.Run("IsolatedFromLaterWrites", func(t *testing.T) {
+		db := open(t)
+		addBlocks(t, db, 0, 10)
+		snap := db.Snapshot()
+		addBlocks(t, db, 11, 20)
+		require.NoError(t, checkSnapshot(snap, 10))
+		require.Equal(t, entrydb.EntryIdx(db.store.Size()), db.NextIndex())
+		require.Less(t, snap.NextIndex(), db.NextIndex())
+		_, err := snap.Contains(12, 0, createHash(1200))
+		require.ErrorIs(t, err, ErrFuture)
+		require.NoError(t, checkSnapshot(db.Snapshot(), 20))
+	})
+
+	t.Run("NotBlockedByWrite", func(t *testing.T) {
+		db := open(t)
+		addBlocks(t, db, 0, 10)
+		require.NoError(t, db.WriteBatch(func(b Batch) error {
+			// the write lock is held while the batch is built
+			return checkSnapshot(db.Snapshot(), 10)
+		}))
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		db := open(t)
+		addBlocks(t, db, 0, 3)
+		snap := db.Snapshot()
+		require.ErrorIs(t, addBlock(snap.db, 4), errReadOnlySnapshot)
+		require.NoError(t, checkSnapshot(db.Snapshot(), 3))
+	})
+
+	t.Run("StaleAfterRewind", func(t *testing.T) {
+		db := open(t)
+		addBlocks(t, db, 0, 10)
+		snap := db.Snapshot()
+		// rewinding to the head does not remove any entries
+		require.NoError(t, db.Rewind(10))
+		require.NoError(t, checkSnapshot(snap, 10))
+		require.NoError(t, db.Rewind(5))
+		_, err := snap.FindSealedBlock(eth.BlockID{Hash: createHash(7), Number: 7})
+		require.ErrorIs(t, err, ErrStaleSnapshot)
+		require.NoError(t, checkSnapshot(db.Snapshot(), 5))
+	})
+
+	t.Run("ReadDuringWrite", func(t *testing.T) {
+		db := open(t)
+		addBlocks(t, db, 0, 0)
+		var done atomic.Bool
+		var g errgroup.Group
+		g.Go(func() error {
+			defer done.Store(true)
+			for i := 1; i <= 300; i++ {
+				if err := addBlock(db, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		for r := 0; r < 4; r++ {
+			g.Go(func() error {
+				for !done.Load() {
+					snap := db.Snapshot()
+					head, _ := snap.LatestSealedBlockNum()
+					if err := checkSnapshot(snap, int(head)); err != nil {
+						return fmt.Errorf("inconsistent snapshot at block %d: %w", head, err)
+					}
+				}
+				return nil
+			})
+		}
+		require.NoError(t, g.Wait())
+		require.NoError(t, checkSnapshot(db.Snapshot(), 300))
+	})
+}
+
+// concurrentMetrics is a Metrics implementation that can be used by concurrent readers and writers.
+type concurrentMetrics struct {
+	entryCount           atomic.Int64
+	entriesReadForSearch atomic.Int64
+}
+
+func (m *concurrentMetrics) RecordDBEntryCount(count int64) {
+	m.entryCount.Store(count)
+}
+
+func (m *concurrentMetrics) RecordDBSearchEntriesRead(count int64) {
+	m.entriesReadForSearch.Store(count)
+}
+
+func (m *concurrentMetrics) RecordDBEntriesWritten(entryType string, count int64) {}
+func (m *concurrentMetrics) RecordDBPaddingRatio(ratio float64)                   {}
+func (m *concurrentMetrics) RecordDBSearchCheckpoint(repeat bool)                 {}
+func (m *concurrentMetrics) RecordDBBlockSealed()                                 {}
+func (m *concurrentMetrics) RecordDBFlush(duration time.Duration)                 {}
+func (m *concurrentMetrics) RecordDBFileSize(size int64)                          {}
+func (m *concurrentMetrics) RecordDBReplayProgress(done int64, total int64)       {}
+func (m *concurrentMetrics) RecordDBOpen(duration time.Duration, restored bool)   {}
+
+var _ Metrics = (*concurrentMetrics)(nil)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -17,27 +18,38 @@ import (
 //
 // Rules:
 //
-//		if entry_index % 256 == 0: must be type 0. For easy binary search.
+//		if entry_index % checkpoint_frequency == 0: must be type 0. For easy binary search.
 //		else if end_of_block: also type 0.
 //		else:
 //		    after type 0: type 1
-//		    after type 1: type 2 iff any event and space, otherwise type 0
-//		    after type 2: type 3 iff executing, otherwise type 2 or 0
+//		    after type 1: type 2 or 10 iff any event and space, otherwise type 0
+//		    after type 2: type 3 iff executing, otherwise type 2, 10 or 0
 //		    after type 3: type 4
-//		    after type 4: type 2 iff any event and space, otherwise type 0
+//		    after type 4: type 3 iff more executing messages of the same event, otherwise type 2 or 10 iff any event and space, otherwise type 0
+//		    after type 10: type 2 or 10 iff any event and space, otherwise type 0
 //	     after type 5: any
+//		    type 7, type 8 and type 9 may follow a complete block seal or a complete log, and are followed by what would follow those.
+//
+// In full-hash mode, every type 1, 2, 4 and 10 is directly followed by a type 6, which is followed by what would follow the type 1, 2, 4 or 10.
 //
 // Type 0 can repeat: seal the block, then start a search checkpoint, then a single canonical hash.
-// Type 0 may also be used as padding: type 2 only starts when it will not be interrupted by a search checkpoint.
+// Type 5 is used as padding: type 2 and 10 only start when they will not be interrupted by a search checkpoint,
+// and in full-hash mode the same applies to a type 0 that seals a block.
 //
 // Types (<type> = 1 byte):
 // type 0: "checkpoint" <type><uint64 block number: 8 bytes><uint32 logsSince count: 4 bytes><uint64 timestamp: 8 bytes> = 21 bytes
 // type 1: "canonical hash" <type><parent blockhash truncated: 20 bytes> = 21 bytes
-// type 2: "initiating event" <type><event flags: 1 byte><event-hash: 20 bytes> = 22 bytes
+// type 2: "initiating event" <type><event flags: 1 byte><event-hash: 20 bytes><executing message count: 1 byte, only with multiple executing messages> = 23 bytes
 // type 3: "executing link" <type><chain: 4 bytes><blocknum: 8 bytes><event index: 3 bytes><uint64 timestamp: 8 bytes> = 24 bytes
+// type 3 with wide log indices (database version 3): "executing link" <type><chain: 4 bytes><blocknum: 7 bytes><event index: 4 bytes><uint64 timestamp: 8 bytes> = 24 bytes
 // type 4: "executing check" <type><event-hash: 20 bytes> = 21 bytes
 // type 5: "padding" <type><padding: 23 bytes> = 24 bytes
-// other types: future compat. E.g. for linking to L1, registering block-headers as a kind of initiating-event, tracking safe-head progression, etc.
+// type 6: "hash extension" <type><remaining hash bytes: 12 bytes> = 13 bytes
+// type 7: "derived from" <type><uint64 L1 block number: 8 bytes><L1 blockhash truncated: 15 bytes> = 24 bytes
+// type 8: "safe head" <type><safety level: 1 byte><uint64 block number: 8 bytes> = 10 bytes
+// type 9: "L1 block" <type><uint64 L1 block number: 8 bytes><L1 blockhash truncated: 15 bytes> = 24 bytes
+// type 10: "deposit event" <type><event-hash: 20 bytes> = 21 bytes
+// other types: future compat.
 //
 // Right-pad each entry that is not 24 bytes.
 //
@@ -47,15 +59,39 @@ import (
 //
 // event-flags: each bit represents a boolean value, currently only two are defined
 // * event-flags & 0x01 - true if the initiating event has an executing link that should follow. Allows detecting when the executing link failed to write.
+// * event-flags & 0x02 - true if the initiating event has multiple executing messages, each a type 3 and type 4, that should follow.
+// The number of executing messages is then stored after the event-hash.
 // event-hash: H(origin, timestamp, payloadhash); enough to check identifier matches & payload matches.
+//
+// A derived-from entry links the last sealed L2 block to the L1 block it was derived from.
+// It is written after the seal of the L2 block, and before the seal of the next block.
+//
+// A safe head entry records that the local-safe (level 0) or cross-safe (level 1) head advanced
+// to a block that was sealed before the entry.
+//
+// An L1 block entry registers an L1 block header as a kind of initiating event,
+// such that messages that reference an L1 origin can be checked against the DB,
+// and the last L1 block that was registered before any entry is known.
+// An L1 block with a number at or below that of the previously registered L1 block replaces it, after an L1 reorg.
+//
+// A deposit event is a log that was emitted by a deposit transaction (L1 to L2).
+// It takes the place of an initiating event, and has the next log index of the block like any other log,
+// but deposit transactions cannot execute messages, so it is never followed by executing messages.
 type logContext struct {
+	// number of entries between search checkpoints
+	checkpointFrequency entrydb.EntryIdx
+	// fullHashes stores the remaining bytes of each hash in a hash-extension entry
+	fullHashes bool
+	// wideLogIndices encodes the log index of executing links in 4 bytes, see Options.WideLogIndices
+	wideLogIndices bool
+
 	// next entry index, including the contents of `out`
 	nextEntryIndex entrydb.EntryIdx
 
 	// blockHash of the last sealed block.
 	// A block is not considered sealed until we know its block hash.
 	// While we process logs we keep the parent-block of said logs around as sealed block.
-	blockHash types.TruncatedHash
+	blockHash common.Hash
 	// blockNum of the last sealed block
 	blockNum uint64
 	// timestamp of the last sealed block
@@ -64,64 +100,159 @@ type logContext struct {
 	// number of logs since the last sealed block
 	logsSince uint32
 
+	// L1 block that the last sealed block was derived from, if known.
+	// This is only known if the derived-from entry was processed after the last search checkpoint.
+	derivedFrom eth.BlockID
+
+	// safe head that is yet to be written
+	safeHead safeHead
+
+	// L1 block that is yet to be written
+	l1Block l1Block
+
 	// payload-hash of the log-event that was last processed. (may not be fully processed, see doneLog)
-	logHash types.TruncatedHash
+	logHash common.Hash
+	// deposit is true if the log-event that was last processed was emitted by a deposit transaction
+	deposit bool
 
-	// executing message that might exist for the current log event.
-	// Might be incomplete; if !logDone while we already processed the initiating event,
+	// executing messages that might exist for the current log event.
+	// Might be incomplete; if the log is incomplete while we already processed the initiating event,
 	// then we know an executing message is still coming.
-	execMsg *types.ExecutingMessage
+	execMsgs []types.ExecutingMessage
+	// index of the executing message in execMsgs that is to be processed next
+	execMsgIdx int
 
 	need entrydb.EntryTypeFlag
 
+	// type of the entry with the hash that the next hash-extension entry completes
+	extending entrydb.EntryType
+
 	// buffer of entries not yet in the DB.
 	// This is generated as objects are applied.
 	// E.g. you can build multiple hypothetical blocks with log events on top of the state,
-	// before flushing the entries to a DB.
+	// before flushing the entries to a DB, or on top of a Fork of the state, to discard them later.
 	// However, no entries can be read from the DB while objects are being applied.
 	out []entrydb.Entry
+
+	// forkPoint is the state that this state was forked from, which Discard restores. Nil if not a fork.
+	forkPoint *logContext
 }
 
 type EntryObj interface {
 	encode() entrydb.Entry
 }
 
+// Fork returns a copy of the state, that objects can be applied to without affecting this state,
+// e.g. to apply the logs of a candidate block and query the result, before the block is built.
+// The entries that are generated by the fork are never written to the DB; Discard rolls the fork back.
+func (l *logContext) Fork() *logContext {
+	f := l.clone()
+	f.forkPoint = l.clone()
+	return f
+}
+
+// Discard drops everything that was applied to the fork, and restores the state it was forked from,
+// such that other objects can be applied to the fork instead.
+func (l *logContext) Discard() {
+	if l.forkPoint == nil {
+		panic("can only discard a fork")
+	}
+	point := l.forkPoint
+	*l = *point.clone()
+	l.forkPoint = point
+}
+
+// clone returns a copy of the state that does not share any buffers with it, and is not a fork.
+func (l *logContext) clone() *logContext {
+	c := *l
+	c.execMsgs = slices.Clone(l.execMsgs)
+	c.out = slices.Clone(l.out)
+	c.forkPoint = nil
+	return &c
+}
+
 func (l *logContext) NextIndex() entrydb.EntryIdx {
 	return l.nextEntryIndex
 }
 
 // SealedBlock returns the block that we are building on top of, and if it is sealed.
-func (l *logContext) SealedBlock() (hash types.TruncatedHash, num uint64, ok bool) {
+func (l *logContext) SealedBlock() (hash common.Hash, num uint64, ok bool) {
 	if !l.hasCompleteBlock() {
-		return types.TruncatedHash{}, 0, false
+		return common.Hash{}, 0, false
 	}
 	return l.blockHash, l.blockNum, true
 }
 
 func (l *logContext) hasCompleteBlock() bool {
-	return !l.need.Any(entrydb.FlagCanonicalHash)
+	return !l.need.Any(entrydb.FlagCanonicalHash) && !l.needsExtension(entrydb.TypeCanonicalHash)
 }
 
 func (l *logContext) hasIncompleteLog() bool {
-	return l.need.Any(entrydb.FlagInitiatingEvent | entrydb.FlagExecutingLink | entrydb.FlagExecutingCheck)
+	return l.need.Any(entrydb.FlagInitiatingEvent|entrydb.FlagDepositEvent|entrydb.FlagExecutingLink|entrydb.FlagExecutingCheck) ||
+		l.needsExtension(entrydb.TypeInitiatingEvent) || l.needsExtension(entrydb.TypeDepositEvent) ||
+		l.needsExtension(entrydb.TypeExecutingCheck)
+}
+
+// needsExtension returns true if the hash of the last entry of the given type still needs a hash extension.
+func (l *logContext) needsExtension(typ entrydb.EntryType) bool {
+	return l.need.Any(entrydb.FlagHashExtension) && l.extending == typ
+}
+
+// storedHash returns the part of the hash that is stored: the full hash,
+// or the truncated hash with the remaining bytes zeroed.
+func storedHash(hash common.Hash, fullHashes bool) common.Hash {
+	if !fullHashes {
+		clear(hash[truncatedHashSize:])
+	}
+	return hash
+}
+
+// requireExtension registers that the hash of the entry of the given type, that was just processed,
+// is to be completed with a hash-extension entry, if the full hash is stored.
+func (l *logContext) requireExtension(typ entrydb.EntryType) {
+	if l.fullHashes {
+		l.need.Add(entrydb.FlagHashExtension)
+		l.extending = typ
+	}
 }
 
 func (l *logContext) hasReadableLog() bool {
 	return l.logsSince > 0 && !l.hasIncompleteLog()
 }
 
+// DerivedFrom returns the L1 block that the sealed block was derived from, if it is known.
+func (l *logContext) DerivedFrom() (l1 eth.BlockID, ok bool) {
+	if !l.hasCompleteBlock() || l.derivedFrom == (eth.BlockID{}) {
+		return eth.BlockID{}, false
+	}
+	return l.derivedFrom, true
+}
+
 // InitMessage returns the current initiating message, if any is available.
-func (l *logContext) InitMessage() (hash types.TruncatedHash, logIndex uint32, ok bool) {
+func (l *logContext) InitMessage() (hash common.Hash, logIndex uint32, ok bool) {
 	if !l.hasReadableLog() {
-		return types.TruncatedHash{}, 0, false
+		return common.Hash{}, 0, false
 	}
 	return l.logHash, l.logsSince - 1, true
 }
 
-// ExecMessage returns the current executing message, if any is available.
+// IsDeposit returns true if the current initiating message, if any, was emitted by a deposit transaction.
+func (l *logContext) IsDeposit() bool {
+	return l.hasReadableLog() && l.deposit
+}
+
+// ExecMessage returns the first executing message of the current log, if any is available.
 func (l *logContext) ExecMessage() *types.ExecutingMessage {
-	if l.hasCompleteBlock() && l.hasReadableLog() && l.execMsg != nil {
-		return l.execMsg
+	if msgs := l.ExecMessages(); len(msgs) > 0 {
+		return &msgs[0]
+	}
+	return nil
+}
+
+// ExecMessages returns all executing messages of the current log, if any are available.
+func (l *logContext) ExecMessages() []types.ExecutingMessage {
+	if l.hasCompleteBlock() && l.hasReadableLog() {
+		return l.execMsgs
 	}
 	return nil
 }
@@ -150,14 +281,17 @@ func (l *logContext) processEntry(entry entrydb.Entry) error {
 			return err
 		}
 		l.blockNum = current.blockNum
-		l.blockHash = types.TruncatedHash{}
+		l.blockHash = common.Hash{}
 		l.logsSince = current.logsSince // TODO this is bumping the logsSince?
 		l.timestamp = current.timestamp
+		l.derivedFrom = eth.BlockID{}
 		l.need.Add(entrydb.FlagCanonicalHash)
 		// Log data after the block we are sealing remains to be seen
 		if l.logsSince == 0 {
-			l.logHash = types.TruncatedHash{}
-			l.execMsg = nil
+			l.logHash = common.Hash{}
+			l.deposit = false
+			l.execMsgs = nil
+			l.execMsgIdx = 0
 		}
 	case entrydb.TypeCanonicalHash:
 		if !l.need.Any(entrydb.FlagCanonicalHash) {
@@ -169,6 +303,7 @@ func (l *logContext) processEntry(entry entrydb.Entry) error {
 		}
 		l.blockHash = canonHash.hash
 		l.need.Remove(entrydb.FlagCanonicalHash)
+		l.requireExtension(entrydb.TypeCanonicalHash)
 	case entrydb.TypeInitiatingEvent:
 		if !l.hasCompleteBlock() {
 			return errors.New("did not complete block seal, cannot add log")
@@ -180,28 +315,52 @@ func (l *logContext) processEntry(entry entrydb.Entry) error {
 		if err != nil {
 			return err
 		}
-		l.execMsg = nil // clear the old state
+		l.execMsgs = nil // clear the old state
+		l.execMsgIdx = 0
 		l.logHash = evt.logHash
-		if evt.hasExecMsg {
+		l.deposit = false
+		if evt.execMsgCount > 0 {
+			l.execMsgs = make([]types.ExecutingMessage, evt.execMsgCount)
 			l.need.Add(entrydb.FlagExecutingLink | entrydb.FlagExecutingCheck)
 		} else {
 			l.logsSince += 1
 		}
 		l.need.Remove(entrydb.FlagInitiatingEvent)
+		l.requireExtension(entrydb.TypeInitiatingEvent)
+	case entrydb.TypeDepositEvent:
+		if !l.hasCompleteBlock() {
+			return errors.New("did not complete block seal, cannot add deposit event")
+		}
+		if l.hasIncompleteLog() {
+			return errors.New("cannot process deposit event before last log completes")
+		}
+		evt, err := newDepositEventFromEntry(entry)
+		if err != nil {
+			return err
+		}
+		l.execMsgs = nil // clear the old state
+		l.execMsgIdx = 0
+		l.logHash = evt.logHash
+		l.deposit = true
+		l.logsSince += 1
+		l.requireExtension(entrydb.TypeDepositEvent)
 	case entrydb.TypeExecutingLink:
+		if l.need.Any(entrydb.FlagHashExtension) {
+			return errors.New("need hash extension of initiating event to be applied before the executing link")
+		}
 		if !l.need.Any(entrydb.FlagExecutingLink) {
 			return errors.New("unexpected executing-link")
 		}
-		link, err := newExecutingLinkFromEntry(entry)
+		link, err := newExecutingLinkFromEntry(entry, l.wideLogIndices)
 		if err != nil {
 			return err
 		}
-		l.execMsg = &types.ExecutingMessage{
+		l.execMsgs[l.execMsgIdx] = types.ExecutingMessage{
 			Chain:     link.chain,
 			BlockNum:  link.blockNum,
 			LogIdx:    link.logIdx,
 			Timestamp: link.timestamp,
-			Hash:      types.TruncatedHash{}, // not known yet
+			Hash:      common.Hash{}, // not known yet
 		}
 		l.need.Remove(entrydb.FlagExecutingLink)
 		l.need.Add(entrydb.FlagExecutingCheck)
@@ -216,14 +375,77 @@ func (l *logContext) processEntry(entry entrydb.Entry) error {
 		if err != nil {
 			return err
 		}
-		l.execMsg.Hash = link.hash
+		l.execMsgs[l.execMsgIdx].Hash = link.hash
 		l.need.Remove(entrydb.FlagExecutingCheck)
-		l.logsSince += 1
+		l.requireExtension(entrydb.TypeExecutingCheck)
+		l.nextExecMsg()
+	case entrydb.TypeHashExtension:
+		if !l.need.Any(entrydb.FlagHashExtension) {
+			return errors.New("unexpected hash extension")
+		}
+		ext, err := newHashExtensionFromEntry(entry)
+		if err != nil {
+			return err
+		}
+		switch l.extending {
+		case entrydb.TypeCanonicalHash:
+			l.blockHash = ext.extend(l.blockHash)
+		case entrydb.TypeInitiatingEvent, entrydb.TypeDepositEvent:
+			l.logHash = ext.extend(l.logHash)
+		case entrydb.TypeExecutingCheck:
+			l.execMsgs[l.execMsgIdx-1].Hash = ext.extend(l.execMsgs[l.execMsgIdx-1].Hash)
+		case entrydb.TypeInvalidated:
+			// the hash of an invalidated block is not part of the state
+		default:
+			return fmt.Errorf("cannot extend hash of %s entry", l.extending)
+		}
+		l.need.Remove(entrydb.FlagHashExtension)
+	case entrydb.TypeDerivedFrom:
+		if !l.hasCompleteBlock() {
+			return errors.New("did not complete block seal, cannot link block to L1")
+		}
+		if l.hasIncompleteLog() {
+			return errors.New("cannot link block to L1 before last log completes")
+		}
+		d, err := newDerivedFromFromEntry(entry)
+		if err != nil {
+			return err
+		}
+		l.derivedFrom = d.l1
+	case entrydb.TypeSafeHead:
+		if !l.hasCompleteBlock() {
+			return errors.New("did not complete block seal, cannot add safe head")
+		}
+		if l.hasIncompleteLog() {
+			return errors.New("cannot add safe head before last log completes")
+		}
+		if _, err := newSafeHeadFromEntry(entry); err != nil {
+			return err
+		}
+	case entrydb.TypeL1Block:
+		if !l.hasCompleteBlock() {
+			return errors.New("did not complete block seal, cannot register L1 block")
+		}
+		if l.hasIncompleteLog() {
+			return errors.New("cannot register L1 block before last log completes")
+		}
+		if _, err := newL1BlockFromEntry(entry); err != nil {
+			return err
+		}
+	case entrydb.TypeInvalidated:
+		if !l.hasCompleteBlock() {
+			return errors.New("did not complete re-seal of the parent block, cannot invalidate block")
+		}
+		if l.hasIncompleteLog() {
+			return errors.New("cannot invalidate block before last log completes")
+		}
+		if _, err := newInvalidatedBlockFromEntry(entry); err != nil {
+			return err
+		}
+		l.requireExtension(entrydb.TypeInvalidated)
 	case entrydb.TypePadding:
-		if l.need.Any(entrydb.FlagPadding) {
-			l.need.Remove(entrydb.FlagPadding)
-		} else {
-			l.need.Remove(entrydb.FlagPadding2)
+		if l.need.Any(entrydb.FlagHashExtension) {
+			return errors.New("unexpected padding, need hash extension")
 		}
 	default:
 		return fmt.Errorf("unknown entry type: %s", entry.Type())
@@ -246,10 +468,33 @@ func (l *logContext) appendEntry(obj EntryObj) {
 // This can be done repeatedly until there is no more implied data to extend.
 func (l *logContext) infer() error {
 	// We force-insert a checkpoint whenever we hit the known fixed interval.
-	if l.nextEntryIndex%searchCheckpointFrequency == 0 {
+	if l.nextEntryIndex%l.checkpointFrequency == 0 {
 		l.need.Add(entrydb.FlagSearchCheckpoint)
 	}
+	// The hash extension always directly follows the entry with the hash.
+	// Padding ensures that this never falls on a search checkpoint.
+	if l.need.Any(entrydb.FlagHashExtension) {
+		var hash common.Hash
+		switch l.extending {
+		case entrydb.TypeCanonicalHash:
+			hash = l.blockHash
+		case entrydb.TypeInitiatingEvent, entrydb.TypeDepositEvent:
+			hash = l.logHash
+		case entrydb.TypeExecutingCheck:
+			hash = l.execMsgs[l.execMsgIdx-1].Hash
+		default:
+			return fmt.Errorf("cannot extend hash of %s entry", l.extending)
+		}
+		l.appendEntry(newHashExtension(hash))
+		l.need.Remove(entrydb.FlagHashExtension)
+		return nil
+	}
 	if l.need.Any(entrydb.FlagSearchCheckpoint) {
+		// In full-hash mode, the canonical hash and its extension must fit before the next search checkpoint.
+		if l.fullHashes && l.entriesUntilCheckpoint() < 3 {
+			l.appendEntry(paddingEntry{})
+			return nil
+		}
 		l.appendEntry(newSearchCheckpoint(l.blockNum, l.logsSince, l.timestamp))
 		l.need.Add(entrydb.FlagCanonicalHash) // always follow with a canonical hash
 		l.need.Remove(entrydb.FlagSearchCheckpoint)
@@ -258,41 +503,54 @@ func (l *logContext) infer() error {
 	if l.need.Any(entrydb.FlagCanonicalHash) {
 		l.appendEntry(newCanonicalHash(l.blockHash))
 		l.need.Remove(entrydb.FlagCanonicalHash)
+		l.requireExtension(entrydb.TypeCanonicalHash)
+		return nil
+	}
+	if l.need.Any(entrydb.FlagDerivedFrom) {
+		l.appendEntry(newDerivedFrom(l.derivedFrom))
+		l.need.Remove(entrydb.FlagDerivedFrom)
 		return nil
 	}
-	if l.need.Any(entrydb.FlagPadding) {
-		l.appendEntry(paddingEntry{})
-		l.need.Remove(entrydb.FlagPadding)
+	if l.need.Any(entrydb.FlagSafeHead) {
+		l.appendEntry(l.safeHead)
+		l.need.Remove(entrydb.FlagSafeHead)
 		return nil
 	}
-	if l.need.Any(entrydb.FlagPadding2) {
-		l.appendEntry(paddingEntry{})
-		l.need.Remove(entrydb.FlagPadding2)
+	if l.need.Any(entrydb.FlagL1Block) {
+		l.appendEntry(l.l1Block)
+		l.need.Remove(entrydb.FlagL1Block)
 		return nil
 	}
 	if l.need.Any(entrydb.FlagInitiatingEvent) {
 		// If we are running out of space for log-event data,
-		// write some checkpoints as padding, to pass the checkpoint.
-		if l.execMsg != nil { // takes 3 total. Need to avoid the checkpoint.
-			switch l.nextEntryIndex % searchCheckpointFrequency {
-			case searchCheckpointFrequency - 1:
-				l.need.Add(entrydb.FlagPadding)
-				return nil
-			case searchCheckpointFrequency - 2:
-				l.need.Add(entrydb.FlagPadding | entrydb.FlagPadding2)
-				return nil
-			}
-		}
-		evt := newInitiatingEvent(l.logHash, l.execMsg != nil)
+		// write padding entries, to pass the checkpoint.
+		if l.entriesUntilCheckpoint() < l.logEntryCount(len(l.execMsgs)) {
+			l.appendEntry(paddingEntry{})
+			return nil
+		}
+		evt := newInitiatingEvent(l.logHash, len(l.execMsgs))
 		l.appendEntry(evt)
 		l.need.Remove(entrydb.FlagInitiatingEvent)
-		if l.execMsg == nil {
+		l.requireExtension(entrydb.TypeInitiatingEvent)
+		if len(l.execMsgs) == 0 {
 			l.logsSince += 1
 		}
 		return nil
 	}
+	if l.need.Any(entrydb.FlagDepositEvent) {
+		// like an initiating event without executing messages, the deposit event must not be interrupted
+		if l.entriesUntilCheckpoint() < l.logEntryCount(0) {
+			l.appendEntry(paddingEntry{})
+			return nil
+		}
+		l.appendEntry(newDepositEvent(l.logHash))
+		l.need.Remove(entrydb.FlagDepositEvent)
+		l.requireExtension(entrydb.TypeDepositEvent)
+		l.logsSince += 1
+		return nil
+	}
 	if l.need.Any(entrydb.FlagExecutingLink) {
-		link, err := newExecutingLink(*l.execMsg)
+		link, err := newExecutingLink(l.execMsgs[l.execMsgIdx], l.wideLogIndices)
 		if err != nil {
 			return fmt.Errorf("failed to create executing link: %w", err)
 		}
@@ -301,18 +559,59 @@ func (l *logContext) infer() error {
 		return nil
 	}
 	if l.need.Any(entrydb.FlagExecutingCheck) {
-		l.appendEntry(newExecutingCheck(l.execMsg.Hash))
+		l.appendEntry(newExecutingCheck(l.execMsgs[l.execMsgIdx].Hash))
 		l.need.Remove(entrydb.FlagExecutingCheck)
-		l.logsSince += 1
+		l.requireExtension(entrydb.TypeExecutingCheck)
+		l.nextExecMsg()
 		return nil
 	}
 	return io.EOF
 }
 
+// nextExecMsg moves on to the next executing message of the log, after the executing check of the current one.
+// The log is complete after the last executing message.
+func (l *logContext) nextExecMsg() {
+	l.execMsgIdx += 1
+	if l.execMsgIdx < len(l.execMsgs) {
+		l.need.Add(entrydb.FlagExecutingLink | entrydb.FlagExecutingCheck)
+	} else {
+		l.logsSince += 1
+	}
+}
+
+// entriesUntilCheckpoint returns the number of entries that can be appended before the next search checkpoint.
+func (l *logContext) entriesUntilCheckpoint() entrydb.EntryIdx {
+	return l.checkpointFrequency - l.nextEntryIndex%l.checkpointFrequency
+}
+
+// logEntryCount returns the number of entries of a log with the given number of executing messages.
+// These are written without interruption by a search checkpoint.
+func (l *logContext) logEntryCount(execMsgCount int) entrydb.EntryIdx {
+	hashEntries := l.hashEntryCount()
+	// initiating event, and an executing link and executing check per executing message
+	return hashEntries + entrydb.EntryIdx(execMsgCount)*(1+hashEntries)
+}
+
+// maxLogEntryCount returns the maximum number of entries of a log,
+// such that it fits after the search checkpoint and canonical hash at the start of a checkpoint interval.
+func (l *logContext) maxLogEntryCount() entrydb.EntryIdx {
+	return l.checkpointFrequency - 1 - l.hashEntryCount()
+}
+
+// hashEntryCount returns the number of entries that an entry with a hash takes, including its hash extension.
+func (l *logContext) hashEntryCount() entrydb.EntryIdx {
+	if l.fullHashes {
+		return 2
+	}
+	return 1
+}
+
 // inferFull advances the queued entries held by the log context repeatedly
 // until no more implied entries can be added
 func (l *logContext) inferFull() error {
-	for i := 0; i < 10; i++ {
+	// A single update implies a bounded number of entries, also with the minimum search checkpoint frequency:
+	// padding, a search checkpoint, a canonical hash, and the entries of a log.
+	for i := 0; i < 20; i++ {
 		err := l.infer()
 		if err == nil {
 			continue
@@ -331,12 +630,15 @@ func (l *logContext) forceBlock(upd eth.BlockID, timestamp uint64) error {
 	if l.nextEntryIndex != 0 {
 		return errors.New("can only bootstrap on top of an empty state")
 	}
-	l.blockHash = types.TruncateHash(upd.Hash)
+	l.blockHash = storedHash(upd.Hash, l.fullHashes)
 	l.blockNum = upd.Number
 	l.timestamp = timestamp
 	l.logsSince = 0
-	l.execMsg = nil
-	l.logHash = types.TruncatedHash{}
+	l.derivedFrom = eth.BlockID{}
+	l.execMsgs = nil
+	l.execMsgIdx = 0
+	l.logHash = common.Hash{}
+	l.deposit = false
 	l.need = 0
 	l.out = nil
 	return l.inferFull() // apply to the state as much as possible
@@ -350,29 +652,82 @@ func (l *logContext) SealBlock(parent common.Hash, upd eth.BlockID, timestamp ui
 		if err := l.inferFull(); err != nil { // ensure we can start applying
 			return err
 		}
-		if l.blockHash != types.TruncateHash(parent) {
+		if l.blockHash != storedHash(parent, l.fullHashes) {
 			return fmt.Errorf("%w: cannot apply block %s (parent %s) on top of %s", ErrConflict, upd, parent, l.blockHash)
 		}
-		if l.blockHash != (types.TruncatedHash{}) && l.blockNum+1 != upd.Number {
+		if l.blockHash != (common.Hash{}) && l.blockNum+1 != upd.Number {
 			return fmt.Errorf("%w: cannot apply block %d on top of %d", ErrConflict, upd.Number, l.blockNum)
 		}
 		if l.timestamp > timestamp {
 			return fmt.Errorf("%w: block timestamp %d must be equal or larger than current timestamp %d", ErrConflict, timestamp, l.timestamp)
 		}
 	}
-	l.blockHash = types.TruncateHash(upd.Hash)
+	l.blockHash = storedHash(upd.Hash, l.fullHashes)
 	l.blockNum = upd.Number
 	l.timestamp = timestamp
 	l.logsSince = 0
-	l.execMsg = nil
-	l.logHash = types.TruncatedHash{}
+	l.derivedFrom = eth.BlockID{}
+	l.execMsgs = nil
+	l.execMsgIdx = 0
+	l.logHash = common.Hash{}
+	l.deposit = false
 	l.need.Add(entrydb.FlagSearchCheckpoint)
 	return l.inferFull() // apply to the state as much as possible
 }
 
-// ApplyLog applies a log on top of the current state.
+// ApplyLog applies a log, and the executing messages it carries, if any, on top of the current state.
 // The parent-block that the log comes after must be applied with ApplyBlock first.
-func (l *logContext) ApplyLog(parentBlock eth.BlockID, logIdx uint32, logHash types.TruncatedHash, execMsg *types.ExecutingMessage) error {
+func (l *logContext) ApplyLog(parentBlock eth.BlockID, logIdx uint32, logHash common.Hash, execMsgs []types.ExecutingMessage) error {
+	if len(execMsgs) > maxExecMsgsPerLog {
+		return fmt.Errorf("log has %d executing messages, at most %d are supported", len(execMsgs), maxExecMsgsPerLog)
+	}
+	if n := l.logEntryCount(len(execMsgs)); n > l.maxLogEntryCount() {
+		return fmt.Errorf("log with %d executing messages takes %d entries, which does not fit in %d entries between search checkpoints",
+			len(execMsgs), n, l.maxLogEntryCount())
+	}
+	// check that the executing messages can be encoded, before any of the log is applied
+	for _, msg := range execMsgs {
+		if _, err := newExecutingLink(msg, l.wideLogIndices); err != nil {
+			return fmt.Errorf("cannot encode executing message of log %d: %w", logIdx, err)
+		}
+	}
+	if err := l.checkNextLog(parentBlock, logIdx); err != nil {
+		return err
+	}
+	l.logHash = storedHash(logHash, l.fullHashes)
+	l.deposit = false
+	l.execMsgs = nil
+	l.execMsgIdx = 0
+	if len(execMsgs) > 0 {
+		// copy, to not retain the full hashes of the caller if only the truncated hashes are stored
+		l.execMsgs = make([]types.ExecutingMessage, len(execMsgs))
+		for i, msg := range execMsgs {
+			msg.Hash = storedHash(msg.Hash, l.fullHashes)
+			l.execMsgs[i] = msg
+		}
+		l.need.Add(entrydb.FlagExecutingLink | entrydb.FlagExecutingCheck)
+	}
+	l.need.Add(entrydb.FlagInitiatingEvent)
+	return l.inferFull() // apply to the state as much as possible
+}
+
+// ApplyDepositEvent applies a log that was emitted by a deposit transaction on top of the current state.
+// Deposit transactions cannot execute messages, so the log does not carry any executing messages.
+// The parent-block that the log comes after must be applied with ApplyBlock first.
+func (l *logContext) ApplyDepositEvent(parentBlock eth.BlockID, logIdx uint32, logHash common.Hash) error {
+	if err := l.checkNextLog(parentBlock, logIdx); err != nil {
+		return err
+	}
+	l.logHash = storedHash(logHash, l.fullHashes)
+	l.deposit = true
+	l.execMsgs = nil
+	l.execMsgIdx = 0
+	l.need.Add(entrydb.FlagDepositEvent)
+	return l.inferFull() // apply to the state as much as possible
+}
+
+// checkNextLog checks that a log with the given index, after the given parent-block, can be applied next.
+func (l *logContext) checkNextLog(parentBlock eth.BlockID, logIdx uint32) error {
 	if parentBlock == (eth.BlockID{}) {
 		return fmt.Errorf("genesis does not have logs: %w", ErrLogOutOfOrder)
 	}
@@ -387,7 +742,7 @@ func (l *logContext) ApplyLog(parentBlock eth.BlockID, logIdx uint32, logHash ty
 		}
 	}
 	// check parent block
-	if l.blockHash != types.TruncateHash(parentBlock.Hash) {
+	if l.blockHash != storedHash(parentBlock.Hash, l.fullHashes) {
 		return fmt.Errorf("%w: log builds on top of block %s, but have block %s", ErrLogOutOfOrder, parentBlock, l.blockHash)
 	}
 	if l.blockNum != parentBlock.Number {
@@ -397,11 +752,62 @@ func (l *logContext) ApplyLog(parentBlock eth.BlockID, logIdx uint32, logHash ty
 	if logIdx != l.logsSince {
 		return fmt.Errorf("%w: expected event index %d, cannot append %d", ErrLogOutOfOrder, l.logsSince, logIdx)
 	}
-	l.logHash = logHash
-	l.execMsg = execMsg
-	l.need.Add(entrydb.FlagInitiatingEvent)
-	if execMsg != nil {
-		l.need.Add(entrydb.FlagExecutingLink | entrydb.FlagExecutingCheck)
+	return nil
+}
+
+// ApplyDerivedFrom links the last sealed block to the L1 block it was derived from.
+// The block must be the last sealed block; the link cannot be added once the next block is sealed.
+func (l *logContext) ApplyDerivedFrom(block eth.BlockID, l1 eth.BlockID) error {
+	if err := l.inferFull(); err != nil { // ensure we can start applying
+		return err
+	}
+	if !l.hasCompleteBlock() {
+		return errors.New("cannot link block to L1 before the block is sealed")
+	}
+	if l.blockHash != storedHash(block.Hash, l.fullHashes) || l.blockNum != block.Number {
+		return fmt.Errorf("%w: cannot link block %s to L1, last sealed block is %s", ErrConflict, block,
+			eth.BlockID{Hash: l.blockHash, Number: l.blockNum})
+	}
+	if l.derivedFrom != (eth.BlockID{}) {
+		if l.derivedFrom != newDerivedFrom(l1).l1 {
+			return fmt.Errorf("%w: block %s is already derived from %s, cannot link to %s", ErrConflict, block, l.derivedFrom, l1)
+		}
+		return nil // already linked
+	}
+	l.derivedFrom = newDerivedFrom(l1).l1
+	l.need.Add(entrydb.FlagDerivedFrom)
+	return l.inferFull() // apply to the state as much as possible
+}
+
+// ApplySafeHead records that the local-safe or cross-safe head advanced to the given block number.
+// The block must be sealed already.
+func (l *logContext) ApplySafeHead(crossSafe bool, blockNum uint64) error {
+	if err := l.inferFull(); err != nil { // ensure we can start applying
+		return err
+	}
+	if !l.hasCompleteBlock() {
+		return errors.New("cannot add safe head before the last block is sealed")
+	}
+	if blockNum > l.blockNum {
+		return fmt.Errorf("%w: cannot add safe head %d, last sealed block is %d", ErrFuture, blockNum, l.blockNum)
+	}
+	l.safeHead = newSafeHead(crossSafe, blockNum)
+	l.need.Add(entrydb.FlagSafeHead)
+	return l.inferFull() // apply to the state as much as possible
+}
+
+// ApplyL1Block registers the given L1 block. The last block must be sealed, and its last log complete.
+func (l *logContext) ApplyL1Block(l1 eth.BlockID) error {
+	if l.nextEntryIndex == 0 {
+		return fmt.Errorf("%w: cannot register L1 block before the first block is sealed", ErrFuture)
+	}
+	if err := l.inferFull(); err != nil { // ensure we can start applying
+		return err
+	}
+	if !l.hasCompleteBlock() {
+		return errors.New("cannot register L1 block before the last block is sealed")
 	}
+	l.l1Block = newL1Block(l1)
+	l.need.Add(entrydb.FlagL1Block)
 	return l.inferFull() // apply to the state as much as possible
 }
@@ -0,0 +1,184 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+)
+
+// VerifyError is returned by Verify for the first entry that violates an invariant of the DB.
+type VerifyError struct {
+	// Idx is the index of the entry that violates an invariant.
+	// It is the index after the last entry if the DB ends with incomplete data.
+	Idx entrydb.EntryIdx
+	Err error
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("invalid entry %v: %v", e.Idx, e.Err)
+}
+
+func (e *VerifyError) Unwrap() []error {
+	return []error{ErrDataCorruption, e.Err}
+}
+
+// Verify replays every entry of the DB through a logContext, from the first search checkpoint that was not pruned,
+// and checks the invariants that are not checked when entries are applied one at a time:
+//   - every checkpoint interval starts with a search checkpoint,
+//   - padding only fills the remainder of a checkpoint interval, and does not interrupt a block seal or a log,
+//   - a search checkpoint that repeats the last sealed block holds its logsSince count, timestamp and canonical hash,
+//   - each sealed block is the next block after the previous one, with a timestamp that does not decrease,
+//   - invalidated entries are written on top of a complete block seal, and are skipped:
+//     the search checkpoint after them repeats the block that they were written on top of,
+//   - the DB does not end with an incomplete block seal or log, and ends in the state that is written on top of.
+//
+// Returns a VerifyError for the first entry that violates an invariant.
+// Writes to the DB are blocked while it is verified.
+func (db *DB) Verify() error {
+	db.rwLock.RLock()
+	defer db.rwLock.RUnlock()
+	start := db.firstCheckpoint() * db.checkpointFrequency
+	v := &verifier{state: logContext{
+		checkpointFrequency: db.checkpointFrequency,
+		fullHashes:          db.fullHashes,
+		wideLogIndices:      db.wideLogIndices,
+		nextEntryIndex:      start,
+	}}
+	for idx := start; idx <= db.lastEntryIdx(); idx++ {
+		if next := db.skipInvalidated(idx); next != idx {
+			if err := v.skip(next); err != nil {
+				return &VerifyError{Idx: idx, Err: err}
+			}
+			idx = next
+		}
+		entry, err := db.store.Read(idx)
+		if err != nil {
+			return &VerifyError{Idx: idx, Err: fmt.Errorf("failed to read entry: %w", err)}
+		}
+		if err := v.apply(entry); err != nil {
+			return &VerifyError{Idx: idx, Err: err}
+		}
+	}
+	if !v.started {
+		return nil // no entries, or all entries were pruned
+	}
+	end := db.lastEntryIdx() + 1
+	if !v.state.hasCompleteBlock() || v.state.hasIncompleteLog() {
+		return &VerifyError{Idx: end, Err: errors.New("incomplete block seal or log at the end of the database")}
+	}
+	tail := &db.lastEntryContext
+	if v.state.blockNum != tail.blockNum || v.state.blockHash != tail.blockHash ||
+		v.state.timestamp != tail.timestamp || v.state.logsSince != tail.logsSince {
+		return &VerifyError{Idx: end, Err: fmt.Errorf("entries end at block %d (%s) with %d logs, but the database writes on top of block %d (%s) with %d logs",
+			v.state.blockNum, v.state.blockHash, v.state.logsSince, tail.blockNum, tail.blockHash, tail.logsSince)}
+	}
+	return nil
+}
+
+// verifier replays entries, and checks the invariants between them.
+type verifier struct {
+	state logContext
+	// started is true once the first entry was applied.
+	// The first entry is a search checkpoint that is not checked against the state before it.
+	started bool
+	// padding is true if the last entry was padding.
+	padding bool
+	// repeatedHash is the hash of the block that a search checkpoint repeated,
+	// which the canonical hash after the search checkpoint must match, if checkRepeatedHash is set.
+	repeatedHash      common.Hash
+	checkRepeatedHash bool
+}
+
+func (v *verifier) apply(entry entrydb.Entry) error {
+	idx := v.state.nextEntryIndex
+	atInterval := idx%v.state.checkpointFrequency == 0
+	if atInterval && entry.Type() != entrydb.TypeSearchCheckpoint {
+		return fmt.Errorf("expected search checkpoint at the start of a checkpoint interval, got %s entry", entry.Type())
+	}
+	if v.padding && !atInterval && entry.Type() != entrydb.TypePadding {
+		return fmt.Errorf("padding is followed by %s entry before the end of the checkpoint interval", entry.Type())
+	}
+	switch entry.Type() {
+	case entrydb.TypeSearchCheckpoint:
+		if err := v.checkSearchCheckpoint(entry, atInterval); err != nil {
+			return err
+		}
+	case entrydb.TypePadding:
+		if !v.state.hasCompleteBlock() || v.state.hasIncompleteLog() {
+			return errors.New("padding interrupts a block seal or log")
+		}
+	}
+	if err := v.state.processEntry(entry); err != nil {
+		return err
+	}
+	if v.checkRepeatedHash && v.state.hasCompleteBlock() {
+		if v.state.blockHash != v.repeatedHash {
+			return fmt.Errorf("canonical hash %s of repeated block %d does not match %s", v.state.blockHash, v.state.blockNum, v.repeatedHash)
+		}
+		v.checkRepeatedHash = false
+	}
+	v.padding = entry.Type() == entrydb.TypePadding
+	v.started = true
+	return nil
+}
+
+// skip skips the invalidated entries up to the given index, which is the search checkpoint after them.
+func (v *verifier) skip(next entrydb.EntryIdx) error {
+	if v.started && (!v.state.hasCompleteBlock() || v.state.hasIncompleteLog()) {
+		return errors.New("invalidated entries interrupt a block seal or log")
+	}
+	v.state.nextEntryIndex = next
+	v.padding = false
+	return nil
+}
+
+// checkSearchCheckpoint checks that the search checkpoint either seals the next block,
+// or repeats the last sealed block at the start of a checkpoint interval.
+func (v *verifier) checkSearchCheckpoint(entry entrydb.Entry, atInterval bool) error {
+	current, err := newSearchCheckpointFromEntry(entry)
+	if err != nil {
+		return err
+	}
+	if current.invalidated > 0 && !atInterval {
+		return errors.New("search checkpoint marks invalidated entries outside of the start of a checkpoint interval")
+	}
+	if !v.started {
+		return nil
+	}
+	if v.state.hasIncompleteLog() {
+		return errors.New("search checkpoint interrupts a log")
+	}
+	if v.state.need.Any(entrydb.FlagHashExtension) {
+		return errors.New("search checkpoint interrupts a hash extension")
+	}
+	switch current.blockNum {
+	case v.state.blockNum + 1:
+		if !v.state.hasCompleteBlock() {
+			return fmt.Errorf("block %d is sealed before the seal of block %d is complete", current.blockNum, v.state.blockNum)
+		}
+		if current.logsSince != 0 {
+			return fmt.Errorf("seal of block %d has logsSince %d", current.blockNum, current.logsSince)
+		}
+		if current.timestamp < v.state.timestamp {
+			return fmt.Errorf("timestamp %d of block %d is before timestamp %d of the previous block", current.timestamp, current.blockNum, v.state.timestamp)
+		}
+	case v.state.blockNum:
+		if !atInterval {
+			return fmt.Errorf("search checkpoint repeats block %d outside of the start of a checkpoint interval", current.blockNum)
+		}
+		if current.logsSince != v.state.logsSince {
+			return fmt.Errorf("logsSince %d of repeated block %d does not match the %d logs after the block", current.logsSince, current.blockNum, v.state.logsSince)
+		}
+		if current.timestamp != v.state.timestamp {
+			return fmt.Errorf("timestamp %d of repeated block %d does not match %d", current.timestamp, current.blockNum, v.state.timestamp)
+		}
+		// If the block was sealed by the search checkpoint just before, then its hash is not known yet.
+		v.repeatedHash, v.checkRepeatedHash = v.state.blockHash, v.state.hasCompleteBlock()
+	default:
+		return fmt.Errorf("search checkpoint of block %d does not follow block %d", current.blockNum, v.state.blockNum)
+	}
+	return nil
+}
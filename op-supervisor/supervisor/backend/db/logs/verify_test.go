@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
+)
+
+func TestVerify(t *testing.T) {
+	// create writes blocks 0 to 20, where block i has i%4 logs, and every third log has an executing message
+	create := func(t *testing.T, opts Options) (*DB, *stubEntryStore) {
+		store := &stubEntryStore{}
+		db, err := NewFromEntryStore(testlog.Logger(t, log.LvlInfo), &stubMetrics{}, store, false, opts)
+		require.NoError(t, err)
+		for i := 0; i <= 20; i++ {
+			parent := eth.BlockID{Hash: createHash(i - 1), Number: uint64(i - 1)}
+			for j := 0; i > 0 && j < i%4; j++ {
+				var execMsgs []types.ExecutingMessage
+				if j%3 == 2 {
+					execMsgs = []types.ExecutingMessage{{Chain: 3, BlockNum: 10, LogIdx: 4, Timestamp: 1000, Hash: createHash(7)}}
+				}
+				require.NoError(t, db.AddLogWithExecMsgs(createHash(100*i+j), parent, uint32(j), execMsgs))
+			}
+			require.NoError(t, db.SealBlock(createHash(i-1), eth.BlockID{Hash: createHash(i), Number: uint64(i)}, 500+uint64(i)))
+		}
+		return db, store
+	}
+	// findCheckpoint returns the index of the first search checkpoint, after the first one, that matches the filter
+	findCheckpoint := func(t *testing.T, store *stubEntryStore, filter func(idx entrydb.EntryIdx, cp searchCheckpoint) bool) entrydb.EntryIdx {
+		for i, entry := range store.entries {
+			if entry.Type() != entrydb.TypeSearchCheckpoint || i == 0 {
+				continue
+			}
+			cp, err := newSearchCheckpointFromEntry(entry)
+			require.NoError(t, err)
+			if filter(entrydb.EntryIdx(i), cp) {
+				return entrydb.EntryIdx(i)
+			}
+		}
+		t.Fatal("no matching search checkpoint")
+		return 0
+	}
+	requireInvalid := func(t *testing.T, db *DB, idx entrydb.EntryIdx, msg string) {
+		err := db.Verify()
+		var verifyErr *VerifyError
+		require.ErrorAs(t, err, &verifyErr)
+		require.ErrorIs(t, err, ErrDataCorruption)
+		require.Equal(t, idx, verifyErr.Idx)
+		require.ErrorContains(t, err, msg)
+	}
+
+	for _, opts := range []Options{{SearchCheckpointFrequency: 7}, {SearchCheckpointFrequency: 9, FullHashes: true}} {
+		opts := opts
+		name := "TruncatedHashes"
+		if opts.FullHashes {
+			name = "FullHashes"
+		}
+		t.Run(name, func(t *testing.T) {
+			freq := entrydb.EntryIdx(opts.SearchCheckpointFrequency)
+
+			t.Run("Valid", func(t *testing.T) {
+				db, _ := create(t, opts)
+				require.NoError(t, db.Verify())
+			})
+
+			t.Run("MissingSearchCheckpoint", func(t *testing.T) {
+				db, store := create(t, opts)
+				store.entries[3*freq] = paddingEntry{}.encode()
+				requireInvalid(t, db, 3*freq, "expected search checkpoint")
+			})
+
+			t.Run("LogsSinceMismatch", func(t *testing.T) {
+				db, store := create(t, opts)
+				idx := findCheckpoint(t, store, func(idx entrydb.EntryIdx, cp searchCheckpoint) bool {
+					return idx%freq == 0 && cp.logsSince > 0
+				})
+				cp, err := newSearchCheckpointFromEntry(store.entries[idx])
+				require.NoError(t, err)
+				store.entries[idx] = newSearchCheckpoint(cp.blockNum, cp.logsSince+1, cp.timestamp).encode()
+				requireInvalid(t, db, idx, "logsSince")
+			})
+
+			t.Run("RepeatedHashMismatch", func(t *testing.T) {
+				db, store := create(t, opts)
+				idx := findCheckpoint(t, store, func(idx entrydb.EntryIdx, cp searchCheckpoint) bool {
+					return idx%freq == 0 && cp.logsSince > 0
+				})
+				store.entries[idx+1] = newCanonicalHash(createHash(999)).encode()
+				hashEnd := idx + 1
+				if opts.FullHashes {
+					hashEnd++ // the hash is only complete with the hash extension
+				}
+				requireInvalid(t, db, hashEnd, "does not match")
+			})
+
+			t.Run("SkippedBlock", func(t *testing.T) {
+				db, store := create(t, opts)
+				idx := findCheckpoint(t, store, func(idx entrydb.EntryIdx, cp searchCheckpoint) bool {
+					return idx%freq != 0 && cp.blockNum > 5
+				})
+				cp, err := newSearchCheckpointFromEntry(store.entries[idx])
+				require.NoError(t, err)
+				store.entries[idx] = newSearchCheckpoint(cp.blockNum+1, cp.logsSince, cp.timestamp).encode()
+				requireInvalid(t, db, idx, "does not follow")
+			})
+
+			t.Run("IncompleteEnd", func(t *testing.T) {
+				db, store := create(t, opts)
+				store.entries = append(store.entries, newInitiatingEvent(createHash(5000), 1).encode())
+				requireInvalid(t, db, entrydb.EntryIdx(len(store.entries)), "incomplete")
+			})
+		})
+	}
+}
@@ -6,13 +6,13 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/heads"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
-	backendTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
@@ -79,6 +79,57 @@ func TestHeadsForChain(t *testing.T) {
 	}
 }
 
+// TestHeadsForChainLightVerified tests that the local safe and finalized heads are clipped to the light-verified head,
+// and that the unsafe and cross heads are not affected.
+func TestHeadsForChainLightVerified(t *testing.T) {
+	h := heads.NewHeads()
+	chainID := types.ChainIDFromUInt64(1)
+	h.Put(chainID, heads.ChainHeads{
+		Unsafe:         entrydb.EntryIdx(10),
+		CrossUnsafe:    entrydb.EntryIdx(9),
+		LocalSafe:      entrydb.EntryIdx(8),
+		CrossSafe:      entrydb.EntryIdx(3),
+		LocalFinalized: entrydb.EntryIdx(6),
+		CrossFinalized: entrydb.EntryIdx(2),
+		LightVerified:  entrydb.EntryIdx(7),
+	})
+	chainsDB := NewChainsDB(nil, &stubHeadStorage{h}, testlog.Logger(t, log.LevelDebug))
+
+	// without light verification, the light-verified head is ignored
+	require.Equal(t, entrydb.EntryIdx(8), NewSafetyChecker(Safe, chainsDB).LocalHeadForChain(chainID))
+
+	chainsDB.EnableLightVerification(chainID)
+	require.Equal(t, entrydb.EntryIdx(10), NewSafetyChecker(Unsafe, chainsDB).LocalHeadForChain(chainID))
+	require.Equal(t, entrydb.EntryIdx(7), NewSafetyChecker(Safe, chainsDB).LocalHeadForChain(chainID))
+	require.Equal(t, entrydb.EntryIdx(3), NewSafetyChecker(Safe, chainsDB).CrossHeadForChain(chainID))
+	require.Equal(t, entrydb.EntryIdx(6), NewSafetyChecker(Finalized, chainsDB).LocalHeadForChain(chainID))
+}
+
+// TestHeadsForChainLightVerifiedPerChain tests that only the chains with light verification enabled are clipped
+// to their light-verified head, so a chain without a verifier is still promoted.
+func TestHeadsForChainLightVerifiedPerChain(t *testing.T) {
+	h := heads.NewHeads()
+	verified := types.ChainIDFromUInt64(1)
+	unverified := types.ChainIDFromUInt64(2)
+	for _, chainID := range []types.ChainID{verified, unverified} {
+		h.Put(chainID, heads.ChainHeads{
+			Unsafe:         entrydb.EntryIdx(10),
+			LocalSafe:      entrydb.EntryIdx(8),
+			LocalFinalized: entrydb.EntryIdx(6),
+			LightVerified:  entrydb.EntryIdx(4),
+		})
+	}
+	chainsDB := NewChainsDB(nil, &stubHeadStorage{h}, testlog.Logger(t, log.LevelDebug))
+	chainsDB.EnableLightVerification(verified)
+
+	require.True(t, chainsDB.LightVerification(verified))
+	require.False(t, chainsDB.LightVerification(unverified))
+	require.Equal(t, entrydb.EntryIdx(4), NewSafetyChecker(Safe, chainsDB).LocalHeadForChain(verified))
+	require.Equal(t, entrydb.EntryIdx(4), NewSafetyChecker(Finalized, chainsDB).LocalHeadForChain(verified))
+	require.Equal(t, entrydb.EntryIdx(8), NewSafetyChecker(Safe, chainsDB).LocalHeadForChain(unverified))
+	require.Equal(t, entrydb.EntryIdx(6), NewSafetyChecker(Finalized, chainsDB).LocalHeadForChain(unverified))
+}
+
 func TestCheck(t *testing.T) {
 	h := heads.NewHeads()
 	chainHeads := heads.ChainHeads{
@@ -105,7 +156,7 @@ func TestCheck(t *testing.T) {
 		chainID          types.ChainID
 		blockNum         uint64
 		logIdx           uint32
-		loghash          backendTypes.TruncatedHash
+		loghash          common.Hash
 		containsResponse containsResponse
 		expected         bool
 	}{
@@ -117,7 +168,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(6), nil},
 			true,
 		},
@@ -128,7 +179,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(3), nil},
 			true,
 		},
@@ -139,7 +190,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(1), nil},
 			true,
 		},
@@ -150,7 +201,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(1), logs.ErrConflict},
 			false,
 		},
@@ -161,7 +212,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(100), nil},
 			false,
 		},
@@ -172,7 +223,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(5), nil},
 			false,
 		},
@@ -183,7 +234,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(3), nil},
 			false,
 		},
@@ -194,7 +245,7 @@ func TestCheck(t *testing.T) {
 			types.ChainIDFromUInt64(1),
 			1,
 			1,
-			backendTypes.TruncatedHash{1, 2, 3},
+			common.Hash{1, 2, 3},
 			containsResponse{entrydb.EntryIdx(0), errors.New("error")},
 			false,
 		},
@@ -1,19 +1,32 @@
 package backend
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/holiman/uint256"
+
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
+const (
+	// headsFileName is the name of the file, in the data directory, that holds the heads of all chains.
+	headsFileName = "heads.json"
+	// logDBFileName is the name of the log database file, in the directory of each chain.
+	logDBFileName = "log.db"
+	// compactHeadsFileName is the name of the file, in the directory of each chain, that holds the heads of the chain
+	// while its log database is compacted, until the heads are resolved to the entry indices of the compacted database.
+	compactHeadsFileName = "compact-heads.json"
+)
+
 func prepLogDBPath(chainID types.ChainID, datadir string) (string, error) {
 	dir, err := prepChainDir(chainID, datadir)
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, "log.db"), nil
+	return filepath.Join(dir, logDBFileName), nil
 }
 
 func prepChainDir(chainID types.ChainID, datadir string) (string, error) {
@@ -30,3 +43,25 @@ func prepDataDir(datadir string) error {
 	}
 	return nil
 }
+
+// listLogDBChains returns the chains that have a log database in the data directory.
+func listLogDBChains(datadir string) ([]types.ChainID, error) {
+	dirs, err := os.ReadDir(datadir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory %v: %w", datadir, err)
+	}
+	var chainIDs []types.ChainID
+	for _, dir := range dirs {
+		chainID, err := uint256.FromDecimal(dir.Name())
+		if !dir.IsDir() || err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(datadir, dir.Name(), logDBFileName)); errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		chainIDs = append(chainIDs, types.ChainID(*chainID))
+	}
+	return chainIDs, nil
+}
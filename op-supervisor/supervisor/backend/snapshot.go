@@ -0,0 +1,381 @@
+package backend
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/uint256"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/jsonutil"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/heads"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+const (
+	// snapshotVersion is the version of the snapshot archive format.
+	snapshotVersion = 1
+	// snapshotManifestName is the name of the manifest, the first file of a snapshot archive.
+	snapshotManifestName = "manifest.json"
+)
+
+// snapshotManifest describes the contents of a snapshot archive.
+type snapshotManifest struct {
+	Version uint64          `json:"version"`
+	Heads   *heads.Heads    `json:"heads"`
+	Chains  []snapshotChain `json:"chains"`
+}
+
+// snapshotChain describes the log database of a chain in a snapshot archive.
+type snapshotChain struct {
+	ChainID string `json:"chainID"`
+	// NextIndex is the index of the entry after the last entry of the log database.
+	NextIndex entrydb.EntryIdx `json:"nextIndex"`
+	// LatestBlock is the number of the last sealed block, if any block was sealed.
+	LatestBlock *uint64 `json:"latestBlock,omitempty"`
+	// Files are the files of the log database: the database file, followed by the segment files.
+	Files []snapshotFile `json:"files"`
+}
+
+type snapshotFile struct {
+	// Name is the path of the file in the archive, relative to the data directory.
+	Name string      `json:"name"`
+	Size int64       `json:"size"`
+	Hash common.Hash `json:"sha256"`
+}
+
+// ExportSnapshot writes a snapshot of the data directory to w: the heads, and the log database of each chain.
+// A supervisor that imports the snapshot, with ImportSnapshot, only has to sync the blocks after it.
+// The data directory must not be in use by a running supervisor.
+func ExportSnapshot(logger log.Logger, m Metrics, datadir string, w io.Writer) error {
+	headTracker, err := heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+	if err != nil {
+		return fmt.Errorf("failed to load heads: %w", err)
+	}
+	manifest := &snapshotManifest{
+		Version: snapshotVersion,
+		Heads:   headTracker.Current(),
+	}
+	chainIDs, err := listLogDBChains(datadir)
+	if err != nil {
+		return err
+	}
+	for _, chainID := range chainIDs {
+		chain, err := exportChain(logger, m, datadir, chainID)
+		if err != nil {
+			return fmt.Errorf("failed to export chain %v: %w", chainID, err)
+		}
+		manifest.Chains = append(manifest.Chains, chain)
+	}
+
+	encoder, err := zstd.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	archive := tar.NewWriter(encoder)
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := archive.WriteHeader(&tar.Header{Name: snapshotManifestName, Mode: 0o644, Size: int64(len(data))}); err != nil {
+		return err
+	}
+	if _, err := archive.Write(data); err != nil {
+		return err
+	}
+	for _, chain := range manifest.Chains {
+		for _, f := range chain.Files {
+			if err := writeSnapshotFile(archive, datadir, f); err != nil {
+				return fmt.Errorf("failed to write %v: %w", f.Name, err)
+			}
+		}
+		logger.Info("Exported log database", "chainID", chain.ChainID, "files", len(chain.Files), "entries", chain.NextIndex)
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// exportChain opens and closes the log database of the chain, such that all of its entries are in the segment files,
+// and describes the files of the database.
+func exportChain(logger log.Logger, m Metrics, datadir string, chainID types.ChainID) (snapshotChain, error) {
+	path := filepath.Join(datadir, chainID.String(), logDBFileName)
+	chain := snapshotChain{ChainID: chainID.String()}
+	logDB, err := logs.NewFromFile(logger, newChainMetrics(chainID, m), path, true,
+		logs.DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), logs.DefaultReplayConfig())
+	if err != nil {
+		return snapshotChain{}, fmt.Errorf("failed to open log database: %w", err)
+	}
+	chain.NextIndex = logDB.NextIndex()
+	if n, ok := logDB.LatestSealedBlockNum(); ok {
+		chain.LatestBlock = &n
+	}
+	if err := logDB.Close(); err != nil {
+		return snapshotChain{}, fmt.Errorf("failed to close log database: %w", err)
+	}
+	files, err := entrydb.SegmentedDBFiles(path)
+	if err != nil {
+		return snapshotChain{}, fmt.Errorf("failed to list files of log database: %w", err)
+	}
+	for _, file := range files {
+		f := snapshotFile{Name: snapshotFileName(chainID, file)}
+		f.Size, f.Hash, err = hashFile(file)
+		if err != nil {
+			return snapshotChain{}, err
+		}
+		chain.Files = append(chain.Files, f)
+	}
+	return chain, nil
+}
+
+// snapshotFileName returns the name in a snapshot archive of the file of the given chain.
+func snapshotFileName(chainID types.ChainID, file string) string {
+	return path.Join(chainID.String(), filepath.Base(file))
+}
+
+func hashFile(file string) (int64, common.Hash, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("failed to read %v: %w", file, err)
+	}
+	return size, common.Hash(h.Sum(nil)), nil
+}
+
+func writeSnapshotFile(archive *tar.Writer, datadir string, f snapshotFile) error {
+	file, err := os.Open(filepath.Join(datadir, filepath.FromSlash(f.Name)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := archive.WriteHeader(&tar.Header{Name: f.Name, Mode: 0o644, Size: f.Size}); err != nil {
+		return err
+	}
+	// The file is copied as it was hashed, as the database is not in use.
+	_, err = io.CopyN(archive, file, f.Size)
+	return err
+}
+
+// ImportSnapshot reads a snapshot, as written by ExportSnapshot, from r into the data directory.
+// The data directory must not hold any heads or log databases yet.
+// Each file is checked against the manifest of the snapshot, and each log database is opened,
+// to check that it holds the exported entries, before any database is moved into the data directory.
+func ImportSnapshot(logger log.Logger, m Metrics, datadir string, r io.Reader) error {
+	if err := prepDataDir(datadir); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(datadir, headsFileName)); err == nil {
+		return fmt.Errorf("data directory %v already holds heads", datadir)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	decoder, err := zstd.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer decoder.Close()
+	archive := tar.NewReader(decoder)
+	manifest, err := readSnapshotManifest(archive)
+	if err != nil {
+		return err
+	}
+	expected := make(map[string]snapshotFile)
+	chainIDs := make(map[string]struct{})
+	for _, chain := range manifest.Chains {
+		if _, err := uint256.FromDecimal(chain.ChainID); err != nil {
+			return fmt.Errorf("invalid chain ID %q: %w", chain.ChainID, err)
+		}
+		if _, ok := chainIDs[chain.ChainID]; ok {
+			return fmt.Errorf("snapshot lists chain %v more than once", chain.ChainID)
+		}
+		chainIDs[chain.ChainID] = struct{}{}
+		if _, err := os.Stat(filepath.Join(datadir, chain.ChainID, logDBFileName)); err == nil {
+			return fmt.Errorf("data directory %v already holds a log database for chain %v", datadir, chain.ChainID)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		for _, f := range chain.Files {
+			if !filepath.IsLocal(filepath.FromSlash(f.Name)) || path.Dir(f.Name) != chain.ChainID {
+				return fmt.Errorf("invalid name of file %q of chain %v", f.Name, chain.ChainID)
+			}
+			expected[f.Name] = f
+		}
+	}
+
+	// Files are extracted into a temporary directory and each database is checked there first, such that no partial
+	// database is left behind in the data directory if the snapshot turns out to be incomplete or corrupted.
+	tmpDir, err := os.MkdirTemp(datadir, "snapshot-import-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory for snapshot files: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	for {
+		hdr, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		f, ok := expected[hdr.Name]
+		if !ok || hdr.Typeflag != tar.TypeReg {
+			return fmt.Errorf("unexpected file %q in snapshot", hdr.Name)
+		}
+		delete(expected, hdr.Name)
+		if err := extractSnapshotFile(archive, tmpDir, f); err != nil {
+			return fmt.Errorf("failed to extract %v: %w", f.Name, err)
+		}
+	}
+	for name := range expected {
+		return fmt.Errorf("snapshot is missing file %q", name)
+	}
+
+	for _, chain := range manifest.Chains {
+		if err := checkChain(logger, m, tmpDir, chain); err != nil {
+			return fmt.Errorf("failed to import chain %v: %w", chain.ChainID, err)
+		}
+	}
+	var moved []string
+	for _, chain := range manifest.Chains {
+		files, err := moveChain(datadir, tmpDir, chain)
+		moved = append(moved, files...)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to import chain %v: %w", chain.ChainID, err), removeFiles(moved))
+		}
+	}
+	// The heads are written last, as they refer to the entries of the log databases.
+	if err := jsonutil.WriteJSON(manifest.Heads, ioutil.ToAtomicFile(filepath.Join(datadir, headsFileName), 0o644)); err != nil {
+		return errors.Join(fmt.Errorf("failed to write heads: %w", err), removeFiles(moved))
+	}
+	for _, chain := range manifest.Chains {
+		logger.Info("Imported log database", "chainID", chain.ChainID, "entries", chain.NextIndex)
+	}
+	return nil
+}
+
+func readSnapshotManifest(archive *tar.Reader) (*snapshotManifest, error) {
+	hdr, err := archive.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if hdr.Name != snapshotManifestName {
+		return nil, fmt.Errorf("snapshot starts with %q instead of the manifest", hdr.Name)
+	}
+	var manifest snapshotManifest
+	if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if manifest.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", manifest.Version)
+	}
+	if manifest.Heads == nil {
+		manifest.Heads = heads.NewHeads()
+	}
+	return &manifest, nil
+}
+
+func extractSnapshotFile(archive *tar.Reader, dir string, f snapshotFile) error {
+	path := filepath.Join(dir, filepath.FromSlash(f.Name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, h), archive)
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if size != f.Size || common.Hash(h.Sum(nil)) != f.Hash {
+		return fmt.Errorf("%w: file does not match the manifest", entrydb.ErrCorrupted)
+	}
+	return nil
+}
+
+// checkChain opens the extracted log database of the chain, and checks that it holds the exported entries.
+func checkChain(logger log.Logger, m Metrics, tmpDir string, chain snapshotChain) error {
+	chainID := uint256.MustFromDecimal(chain.ChainID)
+	path := filepath.Join(tmpDir, chain.ChainID, logDBFileName)
+	logDB, err := logs.NewFromFile(logger, newChainMetrics(types.ChainID(*chainID), m), path, false,
+		logs.DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), logs.DefaultReplayConfig())
+	if err != nil {
+		return fmt.Errorf("failed to open log database: %w", err)
+	}
+	next := logDB.NextIndex()
+	latest, ok := logDB.LatestSealedBlockNum()
+	if err := logDB.Close(); err != nil {
+		return fmt.Errorf("failed to close log database: %w", err)
+	}
+	if next != chain.NextIndex || ok != (chain.LatestBlock != nil) || (ok && latest != *chain.LatestBlock) {
+		return fmt.Errorf("%w: log database ends at entry %v, block %v, but the manifest expects entry %v",
+			entrydb.ErrCorrupted, next, latest, chain.NextIndex)
+	}
+	return nil
+}
+
+// moveChain moves the checked log database of the chain, with any files created when it was opened,
+// from the temporary directory into the data directory. It returns the paths of the files moved into place.
+func moveChain(datadir string, tmpDir string, chain snapshotChain) ([]string, error) {
+	chainID := uint256.MustFromDecimal(chain.ChainID)
+	dir, err := prepChainDir(types.ChainID(*chainID), datadir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(tmpDir, chain.ChainID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of log database: %w", err)
+	}
+	// The database file is moved last, as it is what makes the other files a database.
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() != logDBFileName {
+			names = append(names, entry.Name())
+		}
+	}
+	names = append(names, logDBFileName)
+	var moved []string
+	for _, name := range names {
+		to := filepath.Join(dir, name)
+		if err := os.Rename(filepath.Join(tmpDir, chain.ChainID, name), to); err != nil {
+			return moved, fmt.Errorf("failed to move %v into place: %w", name, err)
+		}
+		moved = append(moved, to)
+	}
+	return moved, nil
+}
+
+// removeFiles removes the files that were moved into the data directory, in reverse order.
+func removeFiles(files []string) error {
+	var result error
+	for i := len(files) - 1; i >= 0; i-- {
+		if err := os.RemoveAll(files[i]); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,199 @@
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/metrics"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/heads"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	chainA := types.ChainIDFromUInt64(900)
+	chainB := types.ChainIDFromUInt64(901)
+	export := func(t *testing.T, datadir string) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, ExportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir, &buf))
+		return buf.Bytes()
+	}
+	requireBlocks := func(t *testing.T, datadir string, chainID types.ChainID, n uint64) {
+		path := filepath.Join(datadir, chainID.String(), logDBFileName)
+		logDB, err := logs.NewFromFile(testlog.Logger(t, log.LvlInfo), newChainMetrics(chainID, metrics.NoopMetrics), path, false,
+			logs.DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), logs.DefaultReplayConfig())
+		require.NoError(t, err)
+		defer logDB.Close()
+		latest, ok := logDB.LatestSealedBlockNum()
+		require.True(t, ok)
+		require.Equal(t, n, latest)
+		for i := uint64(0); i <= n; i++ {
+			_, err := logDB.FindSealedBlock(eth.BlockID{Hash: testBlockHash(chainID, i), Number: i})
+			require.NoError(t, err)
+		}
+		_, err = logDB.Contains(n, 0, common.Hash{0xaa, byte(n)})
+		require.NoError(t, err)
+	}
+
+	t.Run("ExportImport", func(t *testing.T) {
+		src := createTestDataDir(t, map[types.ChainID]uint64{chainA: 100, chainB: 30})
+		data := export(t, src)
+
+		dst := filepath.Join(t.TempDir(), "datadir")
+		require.NoError(t, ImportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, dst, bytes.NewReader(data)))
+		requireBlocks(t, dst, chainA, 100)
+		requireBlocks(t, dst, chainB, 30)
+		srcHeads, err := heads.NewHeadTracker(filepath.Join(src, headsFileName))
+		require.NoError(t, err)
+		dstHeads, err := heads.NewHeadTracker(filepath.Join(dst, headsFileName))
+		require.NoError(t, err)
+		require.Equal(t, srcHeads.Current(), dstHeads.Current())
+		entries, err := os.ReadDir(dst)
+		require.NoError(t, err)
+		require.Len(t, entries, 3, "should only hold the heads and a directory per chain")
+
+		err = ImportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, dst, bytes.NewReader(data))
+		require.ErrorContains(t, err, "already holds heads")
+	})
+
+	t.Run("CorruptedFile", func(t *testing.T) {
+		data := export(t, createTestDataDir(t, map[types.ChainID]uint64{chainA: 100}))
+		data = rewriteSnapshot(t, data, func(_ *snapshotManifest, files [][]byte) {
+			files[len(files)-1][entrydb.HeaderSize] ^= 0xff
+		})
+
+		dst := t.TempDir()
+		err := ImportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, dst, bytes.NewReader(data))
+		require.ErrorIs(t, err, entrydb.ErrCorrupted)
+		entries, err := os.ReadDir(dst)
+		require.NoError(t, err)
+		require.Empty(t, entries, "should not leave any files behind")
+	})
+
+	t.Run("CorruptedSecondChain", func(t *testing.T) {
+		data := export(t, createTestDataDir(t, map[types.ChainID]uint64{chainA: 100, chainB: 30}))
+		data = rewriteSnapshot(t, data, func(manifest *snapshotManifest, _ [][]byte) {
+			require.Len(t, manifest.Chains, 2)
+			manifest.Chains[1].NextIndex++
+		})
+
+		dst := t.TempDir()
+		err := ImportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, dst, bytes.NewReader(data))
+		require.ErrorIs(t, err, entrydb.ErrCorrupted)
+		entries, err := os.ReadDir(dst)
+		require.NoError(t, err)
+		require.Empty(t, entries, "should not import the first chain either")
+	})
+
+	t.Run("DuplicateChain", func(t *testing.T) {
+		data := export(t, createTestDataDir(t, map[types.ChainID]uint64{chainA: 100}))
+		data = rewriteSnapshot(t, data, func(manifest *snapshotManifest, _ [][]byte) {
+			manifest.Chains = append(manifest.Chains, manifest.Chains[0])
+		})
+
+		dst := t.TempDir()
+		err := ImportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, dst, bytes.NewReader(data))
+		require.ErrorContains(t, err, "more than once")
+		entries, err := os.ReadDir(dst)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data := export(t, createTestDataDir(t, map[types.ChainID]uint64{chainA: 100}))
+		dst := t.TempDir()
+		err := ImportSnapshot(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, dst, bytes.NewReader(data[:len(data)/2]))
+		require.Error(t, err)
+		_, err = os.Stat(filepath.Join(dst, headsFileName))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// rewriteSnapshot rewrites the snapshot archive, after the manifest and the contents of the files that follow it
+// are modified by fn.
+func rewriteSnapshot(t *testing.T, data []byte, fn func(manifest *snapshotManifest, files [][]byte)) []byte {
+	decoder, err := zstd.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer decoder.Close()
+	in := tar.NewReader(decoder)
+	var files [][]byte
+	var hdrs []*tar.Header
+	for {
+		hdr, err := in.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(in)
+		require.NoError(t, err)
+		hdrs, files = append(hdrs, hdr), append(files, content)
+	}
+	var manifest snapshotManifest
+	require.NoError(t, json.Unmarshal(files[0], &manifest))
+	fn(&manifest, files[1:])
+	files[0], err = json.Marshal(&manifest)
+	require.NoError(t, err)
+	hdrs[0].Size = int64(len(files[0]))
+
+	var buf bytes.Buffer
+	encoder, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	out := tar.NewWriter(encoder)
+	for i, hdr := range hdrs {
+		require.NoError(t, out.WriteHeader(hdr))
+		_, err := out.Write(files[i])
+		require.NoError(t, err)
+	}
+	require.NoError(t, out.Close())
+	require.NoError(t, encoder.Close())
+	return buf.Bytes()
+}
+
+func testBlockHash(chainID types.ChainID, n uint64) common.Hash {
+	id, _ := chainID.ToUInt32()
+	return common.Hash{byte(id), byte(n >> 8), byte(n)}
+}
+
+// createTestDataDir creates a data directory with the given number of blocks, with a log each, for each chain
+func createTestDataDir(t *testing.T, blocks map[types.ChainID]uint64) string {
+	logger := testlog.Logger(t, log.LvlInfo)
+	datadir := t.TempDir()
+	h := heads.NewHeads()
+	for chainID, n := range blocks {
+		path, err := prepLogDBPath(chainID, datadir)
+		require.NoError(t, err)
+		// small segments, some of them compressed, to export multiple segment files
+		logDB, err := logs.NewFromFile(logger, newChainMetrics(chainID, metrics.NoopMetrics), path, false,
+			logs.DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.SegmentConfig{Size: 40, CompressDepth: 1}, logs.DefaultReplayConfig())
+		require.NoError(t, err)
+		for i := uint64(0); i <= n; i++ {
+			if i > 0 {
+				parent := eth.BlockID{Hash: testBlockHash(chainID, i-1), Number: i - 1}
+				require.NoError(t, logDB.AddLog(common.Hash{0xaa, byte(i)}, parent, 0, nil))
+			}
+			require.NoError(t, logDB.SealBlock(testBlockHash(chainID, i-1), eth.BlockID{Hash: testBlockHash(chainID, i), Number: i}, 1000+i))
+		}
+		h.Put(chainID, heads.ChainHeads{Unsafe: logDB.NextIndex() - 1, CrossFinalized: 5})
+		require.NoError(t, logDB.Close())
+	}
+	tracker, err := heads.NewHeadTracker(filepath.Join(datadir, headsFileName))
+	require.NoError(t, err)
+	require.NoError(t, tracker.Apply(heads.OperationFn(func(current *heads.Heads) error {
+		*current = *h
+		return nil
+	})))
+	return datadir
+}
@@ -7,9 +7,11 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/predeploys"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -33,6 +35,7 @@ type Storage interface {
 // interop consolidation. It detects and notifies when reorgs occur.
 type ChainMonitor struct {
 	log         log.Logger
+	client      *sources.L1Client
 	headMonitor *HeadMonitor
 }
 
@@ -62,6 +65,7 @@ func NewChainMonitor(ctx context.Context, logger log.Logger, m Metrics, chainID
 
 	return &ChainMonitor{
 		log:         logger,
+		client:      cl,
 		headMonitor: headMonitor,
 	}, nil
 }
@@ -75,6 +79,27 @@ func (c *ChainMonitor) Stop() error {
 	return c.headMonitor.Stop()
 }
 
+// OutputV0AtBlock returns the output of the canonical block of the chain with the given number.
+// The storage root of the message passer is proven against the state root of the block.
+func (c *ChainMonitor) OutputV0AtBlock(ctx context.Context, number uint64) (*eth.OutputV0, error) {
+	head, err := c.client.InfoByNumber(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+	proof, err := c.client.GetProof(ctx, predeploys.L2ToL1MessagePasserAddr, []common.Hash{}, head.Hash().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message passer proof at block %s: %w", head.Hash(), err)
+	}
+	if err := proof.Verify(head.Root()); err != nil {
+		return nil, fmt.Errorf("invalid message passer proof, state root was %s: %w", head.Root(), err)
+	}
+	return &eth.OutputV0{
+		StateRoot:                eth.Bytes32(head.Root()),
+		MessagePasserStorageRoot: eth.Bytes32(proof.StorageHash),
+		BlockHash:                head.Hash(),
+	}, nil
+}
+
 func newClient(ctx context.Context, logger log.Logger, m caching.Metrics, rpc string, rpcClient client.RPC, pollRate time.Duration, trustRPC bool, kind sources.RPCProviderKind) (*sources.L1Client, error) {
 	c, err := client.NewRPCWithClient(ctx, logger, rpc, rpcClient, pollRate)
 	if err != nil {
@@ -0,0 +1,144 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+// verifyInterval is how often the indexed data is checked against the output source
+const verifyInterval = 10 * time.Second
+
+var ErrVerificationMismatch = errors.New("indexed block does not match verified output")
+
+// OutputSource provides the output roots of a chain, from a source that is independent of the RPC of the chain,
+// e.g. an op-node of another provider.
+type OutputSource interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+}
+
+type LightVerifierStorage interface {
+	LatestBlockNum(chainID types.ChainID) (num uint64, ok bool)
+	FindSealedBlock(chain types.ChainID, block eth.BlockID) (nextEntry entrydb.EntryIdx, err error)
+	SetLightVerified(chain types.ChainID, index entrydb.EntryIdx) error
+}
+
+// LightVerifier cross-checks the indexed blocks of a chain against the output roots of an independent source,
+// and marks the data up to the last matching block as light-verified.
+// Only blocks that the source considers safe are verified, so unsafe reorgs are not flagged as mismatches.
+type LightVerifier struct {
+	log    log.Logger
+	chain  types.ChainID
+	source OutputSource
+	store  LightVerifierStorage
+
+	lastVerified eth.BlockID
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewLightVerifier(logger log.Logger, chain types.ChainID, source OutputSource, store LightVerifierStorage) *LightVerifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LightVerifier{
+		log:    logger.New("chainID", chain),
+		chain:  chain,
+		source: source,
+		store:  store,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (v *LightVerifier) Start() error {
+	v.log.Info("Started light verification")
+	v.wg.Add(1)
+	go v.loop()
+	return nil
+}
+
+func (v *LightVerifier) Stop() error {
+	v.cancel()
+	v.wg.Wait()
+	return nil
+}
+
+func (v *LightVerifier) loop() {
+	defer v.wg.Done()
+	ticker := time.NewTicker(verifyInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-v.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := v.Verify(v.ctx); errors.Is(err, ErrVerificationMismatch) {
+				v.log.Error("Light verification mismatch, holding back promotion past cross-unsafe", "err", err)
+			} else if err != nil {
+				v.log.Warn("Failed light verification", "err", err)
+			}
+		}
+	}
+}
+
+// Verify checks the latest indexed block that is safe according to the source.
+// If the block matches the output root of the source, all data up to and including the block is light-verified.
+// ErrVerificationMismatch is returned if the indexed block differs from the block committed to by the output root.
+func (v *LightVerifier) Verify(ctx context.Context) error {
+	latest, ok := v.store.LatestBlockNum(v.chain)
+	if !ok {
+		return nil
+	}
+	status, err := v.source.SyncStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get sync status of output source: %w", err)
+	}
+	target := min(latest, status.SafeL2.Number)
+	if target <= v.lastVerified.Number && v.lastVerified != (eth.BlockID{}) {
+		if _, err := v.store.FindSealedBlock(v.chain, v.lastVerified); err == nil {
+			return nil
+		}
+		// The light-verified head was clipped by a reorg or rewind, so the replacement blocks must be verified again
+		v.log.Info("Light-verified block is no longer indexed, verifying again", "block", v.lastVerified)
+		v.lastVerified = eth.BlockID{}
+	}
+	output, err := v.source.OutputAtBlock(ctx, target)
+	if err != nil {
+		return fmt.Errorf("failed to get output at block %d: %w", target, err)
+	}
+	if output.BlockRef.Number != target {
+		return fmt.Errorf("output source returned block %d, expected %d", output.BlockRef.Number, target)
+	}
+	root := eth.OutputRoot(&eth.OutputV0{
+		StateRoot:                eth.Bytes32(output.StateRoot),
+		MessagePasserStorageRoot: eth.Bytes32(output.WithdrawalStorageRoot),
+		BlockHash:                output.BlockRef.Hash,
+	})
+	if root != output.OutputRoot {
+		return fmt.Errorf("output source returned output root %s that does not commit to block %s", output.OutputRoot, output.BlockRef)
+	}
+	block := output.BlockRef.ID()
+	index, err := v.store.FindSealedBlock(v.chain, block)
+	if errors.Is(err, logs.ErrConflict) {
+		return fmt.Errorf("%w: output root %s: %w", ErrVerificationMismatch, output.OutputRoot, err)
+	} else if err != nil {
+		return fmt.Errorf("failed to find indexed block %s: %w", block, err)
+	}
+	if err := v.store.SetLightVerified(v.chain, index); err != nil {
+		return err
+	}
+	v.lastVerified = block
+	v.log.Debug("Light-verified block", "block", block, "outputRoot", output.OutputRoot)
+	return nil
+}
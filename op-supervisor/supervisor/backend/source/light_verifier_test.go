@@ -0,0 +1,163 @@
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+func TestLightVerifier(t *testing.T) {
+	chainID := types.ChainIDFromUInt64(123)
+	block := eth.L2BlockRef{Number: 10, Hash: common.Hash{0xaa}}
+	setup := func(t *testing.T) (*LightVerifier, *stubOutputSource, *stubVerifierStorage) {
+		logger := testlog.Logger(t, log.LvlInfo)
+		src := &stubOutputSource{safe: block, output: makeOutput(block)}
+		store := &stubVerifierStorage{latest: 12, sealed: map[eth.BlockID]entrydb.EntryIdx{block.ID(): 42}, verified: -1}
+		return NewLightVerifier(logger, chainID, src, store), src, store
+	}
+
+	t.Run("Verified", func(t *testing.T) {
+		verifier, _, store := setup(t)
+		require.NoError(t, verifier.Verify(context.Background()))
+		require.Equal(t, entrydb.EntryIdx(42), store.verified)
+	})
+
+	t.Run("TargetClippedToIndexed", func(t *testing.T) {
+		verifier, src, store := setup(t)
+		store.latest = 8
+		older := eth.L2BlockRef{Number: 8, Hash: common.Hash{0xbb}}
+		src.output = makeOutput(older)
+		store.sealed[older.ID()] = 30
+		require.NoError(t, verifier.Verify(context.Background()))
+		require.Equal(t, uint64(8), src.requested)
+		require.Equal(t, entrydb.EntryIdx(30), store.verified)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		verifier, _, store := setup(t)
+		store.sealed = nil
+		store.findErr = logs.ErrConflict
+		err := verifier.Verify(context.Background())
+		require.ErrorIs(t, err, ErrVerificationMismatch)
+		require.Equal(t, entrydb.EntryIdx(-1), store.verified)
+	})
+
+	t.Run("NotYetIndexed", func(t *testing.T) {
+		verifier, _, store := setup(t)
+		store.sealed = nil
+		store.findErr = logs.ErrFuture
+		err := verifier.Verify(context.Background())
+		require.ErrorIs(t, err, logs.ErrFuture)
+		require.NotErrorIs(t, err, ErrVerificationMismatch)
+		require.Equal(t, entrydb.EntryIdx(-1), store.verified)
+	})
+
+	t.Run("InvalidOutputRoot", func(t *testing.T) {
+		verifier, src, store := setup(t)
+		src.output.OutputRoot = eth.Bytes32{0x01}
+		require.ErrorContains(t, verifier.Verify(context.Background()), "does not commit to block")
+		require.Equal(t, entrydb.EntryIdx(-1), store.verified)
+	})
+
+	t.Run("AlreadyVerified", func(t *testing.T) {
+		verifier, src, store := setup(t)
+		require.NoError(t, verifier.Verify(context.Background()))
+		src.requested = 0
+		require.NoError(t, verifier.Verify(context.Background()))
+		require.Zero(t, src.requested, "should not verify the same block again")
+		require.Equal(t, entrydb.EntryIdx(42), store.verified)
+	})
+
+	t.Run("Rewind", func(t *testing.T) {
+		verifier, src, store := setup(t)
+		require.NoError(t, verifier.Verify(context.Background()))
+		require.Equal(t, entrydb.EntryIdx(42), store.verified)
+
+		// The chain is rewound and the verified block replaced at the same height
+		replacement := eth.L2BlockRef{Number: 10, Hash: common.Hash{0xcc}}
+		store.latest = 10
+		store.sealed = map[eth.BlockID]entrydb.EntryIdx{replacement.ID(): 40}
+		store.verified = 0
+		src.safe = replacement
+		src.output = makeOutput(replacement)
+		src.requested = 0
+		require.NoError(t, verifier.Verify(context.Background()))
+		require.Equal(t, uint64(10), src.requested)
+		require.Equal(t, entrydb.EntryIdx(40), store.verified)
+	})
+
+	t.Run("NothingIndexed", func(t *testing.T) {
+		verifier, src, store := setup(t)
+		store.latest = 0
+		require.NoError(t, verifier.Verify(context.Background()))
+		require.Zero(t, src.requested)
+		require.Equal(t, entrydb.EntryIdx(-1), store.verified)
+	})
+}
+
+func makeOutput(block eth.L2BlockRef) *eth.OutputResponse {
+	stateRoot := eth.Bytes32{0x01}
+	storageRoot := common.Hash{0x02}
+	return &eth.OutputResponse{
+		Version: eth.OutputVersionV0,
+		OutputRoot: eth.OutputRoot(&eth.OutputV0{
+			StateRoot:                stateRoot,
+			MessagePasserStorageRoot: eth.Bytes32(storageRoot),
+			BlockHash:                block.Hash,
+		}),
+		BlockRef:              block,
+		WithdrawalStorageRoot: storageRoot,
+		StateRoot:             common.Hash(stateRoot),
+	}
+}
+
+type stubOutputSource struct {
+	safe      eth.L2BlockRef
+	output    *eth.OutputResponse
+	requested uint64
+}
+
+func (s *stubOutputSource) SyncStatus(_ context.Context) (*eth.SyncStatus, error) {
+	return &eth.SyncStatus{SafeL2: s.safe}, nil
+}
+
+func (s *stubOutputSource) OutputAtBlock(_ context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	s.requested = blockNum
+	return s.output, nil
+}
+
+type stubVerifierStorage struct {
+	latest   uint64
+	sealed   map[eth.BlockID]entrydb.EntryIdx
+	findErr  error
+	verified entrydb.EntryIdx
+}
+
+func (s *stubVerifierStorage) LatestBlockNum(_ types.ChainID) (uint64, bool) {
+	return s.latest, s.latest > 0
+}
+
+func (s *stubVerifierStorage) FindSealedBlock(_ types.ChainID, block eth.BlockID) (entrydb.EntryIdx, error) {
+	if s.findErr != nil {
+		return 0, s.findErr
+	}
+	index, ok := s.sealed[block]
+	if !ok {
+		return 0, logs.ErrFuture
+	}
+	return index, nil
+}
+
+func (s *stubVerifierStorage) SetLightVerified(_ types.ChainID, index entrydb.EntryIdx) error {
+	s.verified = index
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/predeploys"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/source/contracts"
 	backendTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/types"
 	supTypes "github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -68,19 +70,19 @@ func TestLogProcessor(t *testing.T) {
 			{
 				parent:  block1.ParentID(),
 				logIdx:  0,
-				logHash: logToLogHash(rcpts[0].Logs[0]),
+				logHash: backendTypes.LogToLogHash(rcpts[0].Logs[0]),
 				execMsg: nil,
 			},
 			{
 				parent:  block1.ParentID(),
 				logIdx:  0,
-				logHash: logToLogHash(rcpts[0].Logs[1]),
+				logHash: backendTypes.LogToLogHash(rcpts[0].Logs[1]),
 				execMsg: nil,
 			},
 			{
 				parent:  block1.ParentID(),
 				logIdx:  0,
-				logHash: logToLogHash(rcpts[1].Logs[0]),
+				logHash: backendTypes.LogToLogHash(rcpts[1].Logs[0]),
 				execMsg: nil,
 			},
 		}
@@ -113,7 +115,7 @@ func TestLogProcessor(t *testing.T) {
 			BlockNum:  6,
 			LogIdx:    8,
 			Timestamp: 10,
-			Hash:      backendTypes.TruncatedHash{0xaa},
+			Hash:      common.Hash{0xaa},
 		}
 		store := &stubLogStorage{}
 		processor := newLogProcessor(supTypes.ChainID{4}, store)
@@ -128,7 +130,7 @@ func TestLogProcessor(t *testing.T) {
 			{
 				parent:  block1.ParentID(),
 				logIdx:  0,
-				logHash: logToLogHash(rcpts[0].Logs[0]),
+				logHash: backendTypes.LogToLogHash(rcpts[0].Logs[0]),
 				execMsg: &execMsg,
 			},
 		}
@@ -143,6 +145,55 @@ func TestLogProcessor(t *testing.T) {
 		}
 		require.Equal(t, expectedBlocks, store.seals)
 	})
+
+	t.Run("DepositLogs", func(t *testing.T) {
+		rcpts := ethTypes.Receipts{
+			{
+				Type: ethTypes.DepositTxType,
+				Logs: []*ethTypes.Log{
+					{
+						Address: common.Address{0x11},
+						Topics:  []common.Hash{{0xaa}},
+						Data:    []byte{0xbb},
+					},
+				},
+			},
+			{
+				Logs: []*ethTypes.Log{
+					{
+						Address: common.Address{0x22},
+						Topics:  []common.Hash{{0xcc}},
+						Data:    []byte{0xdd},
+						Index:   1,
+					},
+				},
+			},
+		}
+		store := &stubLogStorage{}
+		processor := newLogProcessor(logProcessorChainID, store)
+		processor.eventDecoder = EventDecoderFn(func(l *ethTypes.Log) (backendTypes.ExecutingMessage, error) {
+			require.Equal(t, rcpts[1].Logs[0], l, "should not decode executing messages of deposits")
+			return backendTypes.ExecutingMessage{}, contracts.ErrEventNotFound
+		})
+
+		err := processor.ProcessLogs(ctx, block1, rcpts)
+		require.NoError(t, err)
+		expected := []storedLog{
+			{
+				parent:  block1.ParentID(),
+				logIdx:  0,
+				logHash: backendTypes.LogToLogHash(rcpts[0].Logs[0]),
+				deposit: true,
+			},
+			{
+				parent:  block1.ParentID(),
+				logIdx:  1,
+				logHash: backendTypes.LogToLogHash(rcpts[1].Logs[0]),
+			},
+		}
+		require.Equal(t, expected, store.logs)
+		require.Len(t, store.seals, 1)
+	})
 }
 
 func TestToLogHash(t *testing.T) {
@@ -179,24 +230,24 @@ func TestToLogHash(t *testing.T) {
 		func(l *ethTypes.Log) { l.Index = 98 },
 		func(l *ethTypes.Log) { l.Removed = true },
 	}
-	refHash := logToLogHash(mkLog())
+	refHash := backendTypes.LogToLogHash(mkLog())
 	// The log hash is stored in the database so test that it matches the actual value.
 	// If this changes, compatibility with existing databases may be affected
-	expectedRefHash := backendTypes.TruncateHash(common.HexToHash("0x4e1dc08fddeb273275f787762cdfe945cf47bb4e80a1fabbc7a825801e81b73f"))
+	expectedRefHash := common.HexToHash("0x4e1dc08fddeb273275f787762cdfe945cf47bb4e80a1fabbc7a825801e81b73f")
 	require.Equal(t, expectedRefHash, refHash, "reference hash changed, check that database compatibility is not broken")
 
 	// Check that the hash is changed when any data it should include changes
 	for i, mod := range relevantMods {
 		l := mkLog()
 		mod(l)
-		hash := logToLogHash(l)
+		hash := backendTypes.LogToLogHash(l)
 		require.NotEqualf(t, refHash, hash, "expected relevant modification %v to affect the hash but it did not", i)
 	}
 	// Check that the hash is not changed when any data it should not include changes
 	for i, mod := range irrelevantMods {
 		l := mkLog()
 		mod(l)
-		hash := logToLogHash(l)
+		hash := backendTypes.LogToLogHash(l)
 		require.Equal(t, refHash, hash, "expected irrelevant modification %v to not affect the hash but it did", i)
 	}
 }
@@ -206,10 +257,14 @@ type stubLogStorage struct {
 	seals []storedSeal
 }
 
-func (s *stubLogStorage) SealBlock(chainID supTypes.ChainID, parentHash common.Hash, block eth.BlockID, timestamp uint64) error {
+func (s *stubLogStorage) WriteBatch(chainID supTypes.ChainID, fn func(b logs.Batch) error) error {
 	if logProcessorChainID != chainID {
 		return fmt.Errorf("chain id mismatch, expected %v but got %v", logProcessorChainID, chainID)
 	}
+	return fn(s)
+}
+
+func (s *stubLogStorage) SealBlock(parentHash common.Hash, block eth.BlockID, timestamp uint64) error {
 	s.seals = append(s.seals, storedSeal{
 		parent:    parentHash,
 		block:     block,
@@ -218,10 +273,7 @@ func (s *stubLogStorage) SealBlock(chainID supTypes.ChainID, parentHash common.H
 	return nil
 }
 
-func (s *stubLogStorage) AddLog(chainID supTypes.ChainID, logHash backendTypes.TruncatedHash, parentBlock eth.BlockID, logIdx uint32, execMsg *backendTypes.ExecutingMessage) error {
-	if logProcessorChainID != chainID {
-		return fmt.Errorf("chain id mismatch, expected %v but got %v", logProcessorChainID, chainID)
-	}
+func (s *stubLogStorage) AddLog(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32, execMsg *backendTypes.ExecutingMessage) error {
 	s.logs = append(s.logs, storedLog{
 		parent:  parentBlock,
 		logIdx:  logIdx,
@@ -231,6 +283,16 @@ func (s *stubLogStorage) AddLog(chainID supTypes.ChainID, logHash backendTypes.T
 	return nil
 }
 
+func (s *stubLogStorage) AddDepositEvent(logHash common.Hash, parentBlock eth.BlockID, logIdx uint32) error {
+	s.logs = append(s.logs, storedLog{
+		parent:  parentBlock,
+		logIdx:  logIdx,
+		logHash: logHash,
+		deposit: true,
+	})
+	return nil
+}
+
 type storedSeal struct {
 	parent    common.Hash
 	block     eth.BlockID
@@ -240,8 +302,9 @@ type storedSeal struct {
 type storedLog struct {
 	parent  eth.BlockID
 	logIdx  uint32
-	logHash backendTypes.TruncatedHash
+	logHash common.Hash
 	execMsg *backendTypes.ExecutingMessage
+	deposit bool
 }
 
 type EventDecoderFn func(*ethTypes.Log) (backendTypes.ExecutingMessage, error)
@@ -1,27 +1,51 @@
 package types
 
 import (
-	"encoding/hex"
-
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
-type TruncatedHash [20]byte
-
-func TruncateHash(hash common.Hash) TruncatedHash {
-	var truncated TruncatedHash
-	copy(truncated[:], hash[0:20])
-	return truncated
-}
-
-func (h TruncatedHash) String() string {
-	return hex.EncodeToString(h[:])
-}
-
 type ExecutingMessage struct {
 	Chain     uint32
 	BlockNum  uint64
 	LogIdx    uint32
 	Timestamp uint64
-	Hash      TruncatedHash
+	// Hash is the hash of the initiating log.
+	// Databases that do not store full hashes only retain the first 20 bytes, with the remainder zeroed.
+	Hash common.Hash
+}
+
+// LogToLogHash transforms a log into a hash that represents the log.
+// it is the concatenation of the log's address and the hash of the log's payload,
+// which is then hashed again. This is the hash that is stored in the log storage.
+// The address is hashed into the payload hash to save space in the log storage,
+// and because they represent paired data.
+func LogToLogHash(l *ethTypes.Log) common.Hash {
+	payloadHash := crypto.Keccak256(LogToMessagePayload(l))
+	return PayloadHashToLogHash(common.Hash(payloadHash), l.Address)
+}
+
+// LogToMessagePayload is the data that is hashed to get the logHash
+// it is the concatenation of the log's topics and data
+// the implementation is based on the interop messaging spec
+func LogToMessagePayload(l *ethTypes.Log) []byte {
+	msg := make([]byte, 0)
+	for _, topic := range l.Topics {
+		msg = append(msg, topic.Bytes()...)
+	}
+	msg = append(msg, l.Data...)
+	return msg
+}
+
+// PayloadHashToLogHash converts the payload hash to the log hash
+// it is the concatenation of the log's address and the hash of the log's payload,
+// which is then hashed. This is the hash that is stored in the log storage.
+// The logHash can then be used to traverse from the executing message
+// to the log the referenced initiating message.
+func PayloadHashToLogHash(payloadHash common.Hash, addr common.Address) common.Hash {
+	msg := make([]byte, 0, 2*common.HashLength)
+	msg = append(msg, addr.Bytes()...)
+	msg = append(msg, payloadHash.Bytes()...)
+	return crypto.Keccak256Hash(msg)
 }
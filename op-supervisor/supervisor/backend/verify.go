@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+)
+
+// VerifyLogDBs verifies the log database of each chain in the data directory, see logs.DB.Verify.
+// All chains are verified, and the errors of the chains with an invalid log database are joined.
+// The databases are opened read-only, so the data directory may be in use by a running supervisor,
+// in which case the entries up to the last block that was sealed when a database was opened are verified.
+func VerifyLogDBs(logger log.Logger, m Metrics, datadir string) error {
+	chainIDs, err := listLogDBChains(datadir)
+	if err != nil {
+		return err
+	}
+	var result error
+	for _, chainID := range chainIDs {
+		path := filepath.Join(datadir, chainID.String(), logDBFileName)
+		logDB, err := logs.OpenReadOnly(logger, newChainMetrics(chainID, m), path)
+		if err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to open log database of chain %v: %w", chainID, err))
+			continue
+		}
+		if err := logDB.Verify(); err != nil {
+			result = errors.Join(result, fmt.Errorf("invalid log database of chain %v: %w", chainID, err))
+		} else {
+			logger.Info("Verified log database", "chainID", chainID, "entries", logDB.NextIndex())
+		}
+		if err := logDB.Close(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close log database of chain %v: %w", chainID, err))
+		}
+	}
+	return result
+}
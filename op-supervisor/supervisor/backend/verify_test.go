@@ -0,0 +1,43 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-supervisor/metrics"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/entrydb"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/backend/db/logs"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+func TestVerifyLogDBs(t *testing.T) {
+	chainA := types.ChainIDFromUInt64(900)
+	chainB := types.ChainIDFromUInt64(901)
+	datadir := createTestDataDir(t, map[types.ChainID]uint64{chainA: 100, chainB: 30})
+	require.NoError(t, VerifyLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir))
+
+	// databases that are open for writing can be verified
+	logDB, err := logs.NewFromFile(testlog.Logger(t, log.LvlInfo), newChainMetrics(chainB, metrics.NoopMetrics),
+		filepath.Join(datadir, chainB.String(), logDBFileName), false, logs.DefaultOptions(), entrydb.DefaultWALConfig(), entrydb.DefaultSegmentConfig(), logs.DefaultReplayConfig())
+	require.NoError(t, err)
+	defer logDB.Close()
+	require.NoError(t, VerifyLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir))
+
+	// corrupt the first segment of chain A, which is only read when all entries are verified
+	path := filepath.Join(datadir, chainA.String(), logDBFileName)
+	files, err := entrydb.SegmentedDBFiles(path)
+	require.NoError(t, err)
+	require.Greater(t, len(files), 2)
+	data, err := os.ReadFile(files[1])
+	require.NoError(t, err)
+	data[entrydb.HeaderSize+5] ^= 0xff
+	require.NoError(t, os.WriteFile(files[1], data, 0o644))
+	err = VerifyLogDBs(testlog.Logger(t, log.LvlInfo), metrics.NoopMetrics, datadir)
+	require.ErrorContains(t, err, "chain 900")
+	require.NotContains(t, err.Error(), "chain 901")
+}
@@ -25,6 +25,11 @@ func Main(version string, fn MainFn) cliapp.LifecycleAction {
 			return nil, err
 		}
 		cfg := flags.ConfigFromCLI(cliCtx, version)
+		depSet, err := flags.LoadDependencySet(cliCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dependency set: %w", err)
+		}
+		cfg.DependencySet = depSet
 		if err := cfg.Check(); err != nil {
 			return nil, fmt.Errorf("invalid CLI flags: %w", err)
 		}
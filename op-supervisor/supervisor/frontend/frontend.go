@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
 )
 
@@ -19,6 +20,8 @@ type QueryBackend interface {
 	CheckMessage(identifier types.Identifier, payloadHash common.Hash) (types.SafetyLevel, error)
 	CheckMessages(messages []types.Message, minSafety types.SafetyLevel) error
 	CheckBlock(chainID *hexutil.U256, blockHash common.Hash, blockNumber hexutil.Uint64) (types.SafetyLevel, error)
+	SyncStatus() (eth.SupervisorSyncStatus, error)
+	SuperRootAtTimestamp(ctx context.Context, timestamp hexutil.Uint64) (eth.SuperRootResponse, error)
 }
 
 type Backend interface {
@@ -49,6 +52,17 @@ func (q *QueryFrontend) CheckBlock(chainID *hexutil.U256, blockHash common.Hash,
 	return q.Supervisor.CheckBlock(chainID, blockHash, blockNumber)
 }
 
+// SyncStatus returns the sync status of the supervisor across all chains in the dependency set.
+func (q *QueryFrontend) SyncStatus() (eth.SupervisorSyncStatus, error) {
+	return q.Supervisor.SyncStatus()
+}
+
+// SuperRootAtTimestamp returns the super root of all chains at the given timestamp,
+// with the output roots of the chains that it commits to.
+func (q *QueryFrontend) SuperRootAtTimestamp(ctx context.Context, timestamp hexutil.Uint64) (eth.SuperRootResponse, error) {
+	return q.Supervisor.SuperRootAtTimestamp(ctx, timestamp)
+}
+
 type AdminFrontend struct {
 	Supervisor Backend
 }
@@ -0,0 +1,91 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
+	"github.com/ethereum-optimism/optimism/op-supervisor/supervisor/types"
+)
+
+type stubQueryBackend struct {
+	status eth.SupervisorSyncStatus
+	roots  map[hexutil.Uint64]eth.SuperRootResponse
+}
+
+func (s *stubQueryBackend) CheckMessage(identifier types.Identifier, payloadHash common.Hash) (types.SafetyLevel, error) {
+	return types.CrossUnsafe, nil
+}
+
+func (s *stubQueryBackend) CheckMessages(messages []types.Message, minSafety types.SafetyLevel) error {
+	return nil
+}
+
+func (s *stubQueryBackend) CheckBlock(chainID *hexutil.U256, blockHash common.Hash, blockNumber hexutil.Uint64) (types.SafetyLevel, error) {
+	return types.CrossSafe, nil
+}
+
+func (s *stubQueryBackend) SyncStatus() (eth.SupervisorSyncStatus, error) {
+	return s.status, nil
+}
+
+func (s *stubQueryBackend) SuperRootAtTimestamp(ctx context.Context, timestamp hexutil.Uint64) (eth.SuperRootResponse, error) {
+	root, ok := s.roots[timestamp]
+	if !ok {
+		return eth.SuperRootResponse{}, errors.New("not found")
+	}
+	return root, nil
+}
+
+// TestSupervisorClient checks that the supervisor client of op-service can use the RPC methods of the query frontend.
+func TestSupervisorClient(t *testing.T) {
+	output := &eth.OutputV0{StateRoot: eth.Bytes32{0x01}, MessagePasserStorageRoot: eth.Bytes32{0x02}, BlockHash: common.Hash{0x03}}
+	root := eth.SuperRootResponse{
+		CrossSafeDerivedFrom: eth.BlockID{Hash: common.Hash{0xaa}, Number: 100},
+		Timestamp:            1000,
+		Chains: []eth.ChainRootInfo{
+			{ChainID: 900, Canonical: eth.OutputRoot(output), Pending: output.Marshal()},
+			{ChainID: 901, Canonical: eth.Bytes32{0x04}, Pending: output.Marshal()},
+		},
+	}
+	root.SuperRoot = eth.SuperRoot(root.Super())
+	backend := &stubQueryBackend{
+		status: eth.SupervisorSyncStatus{
+			MinSyncedL1:        eth.L1BlockRef{Hash: common.Hash{0xbb}, Number: 101},
+			SafeTimestamp:      1000,
+			FinalizedTimestamp: 900,
+		},
+		roots: map[hexutil.Uint64]eth.SuperRootResponse{1000: root},
+	}
+
+	srv := rpc.NewServer()
+	t.Cleanup(srv.Stop)
+	require.NoError(t, srv.RegisterName("supervisor", &QueryFrontend{Supervisor: backend}))
+	cl := sources.NewSupervisorClient(client.NewBaseRPCClient(rpc.DialInProc(srv)))
+	t.Cleanup(cl.Close)
+
+	t.Run("SyncStatus", func(t *testing.T) {
+		status, err := cl.SyncStatus(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, backend.status, status)
+	})
+
+	t.Run("SuperRootAtTimestamp", func(t *testing.T) {
+		resp, err := cl.SuperRootAtTimestamp(context.Background(), 1000)
+		require.NoError(t, err)
+		require.Equal(t, root, resp)
+		require.Equal(t, root.SuperRoot, eth.SuperRoot(resp.Super()))
+
+		_, err = cl.SuperRootAtTimestamp(context.Background(), 1001)
+		require.ErrorContains(t, err, "not found")
+	})
+
+}
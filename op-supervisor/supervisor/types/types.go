@@ -128,6 +128,14 @@ func (id ChainID) String() string {
 	return ((*uint256.Int)(&id)).Dec()
 }
 
+func (id ChainID) ToUInt64() (uint64, error) {
+	v := (*uint256.Int)(&id)
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("ChainID too large for uint64: %v", id)
+	}
+	return v.Uint64(), nil
+}
+
 func (id ChainID) ToUInt32() (uint32, error) {
 	v := (*uint256.Int)(&id)
 	if !v.IsUint64() {
@@ -0,0 +1,244 @@
+package chaindata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Entry is a single block of an export: the full block (header and body), and its receipts.
+// An export is a stream of RLP-encoded entries, of consecutive blocks.
+type Entry struct {
+	Block *types.Block
+	// Receipts are the consensus encodings of the receipts, which include the receipt type,
+	// and the deposit nonce and receipt version of deposit receipts.
+	Receipts [][]byte
+}
+
+// NewEntry creates the entry of the given block and its receipts.
+func NewEntry(block *types.Block, receipts types.Receipts) (*Entry, error) {
+	entry := &Entry{Block: block, Receipts: make([][]byte, len(receipts))}
+	for i, r := range receipts {
+		data, err := r.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode receipt %d of block %d: %w", i, block.NumberU64(), err)
+		}
+		entry.Receipts[i] = data
+	}
+	return entry, nil
+}
+
+// DecodeReceipts decodes the consensus encodings of the receipts of the entry.
+func (e *Entry) DecodeReceipts() (types.Receipts, error) {
+	receipts := make(types.Receipts, len(e.Receipts))
+	for i, data := range e.Receipts {
+		var r types.Receipt
+		if err := r.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("failed to decode receipt %d of block %d: %w", i, e.Block.NumberU64(), err)
+		}
+		receipts[i] = &r
+	}
+	return receipts, nil
+}
+
+// Verify checks that the transactions and receipts of the entry match the roots committed to in the block header.
+func (e *Entry) Verify() error {
+	header := e.Block.Header()
+	if txRoot := types.DeriveSha(e.Block.Transactions(), trie.NewStackTrie(nil)); txRoot != header.TxHash {
+		return fmt.Errorf("block %d %s: transactions root %s does not match header %s",
+			header.Number, e.Block.Hash(), txRoot, header.TxHash)
+	}
+	receipts, err := e.DecodeReceipts()
+	if err != nil {
+		return err
+	}
+	if receiptsRoot := types.DeriveSha(receipts, trie.NewStackTrie(nil)); receiptsRoot != header.ReceiptHash {
+		return fmt.Errorf("block %d %s: receipts root %s does not match header %s",
+			header.Number, e.Block.Hash(), receiptsRoot, header.ReceiptHash)
+	}
+	return nil
+}
+
+// Source provides the blocks and receipts to export.
+type Source interface {
+	BlockByNumber(ctx context.Context, num uint64) (*types.Block, error)
+	Receipts(ctx context.Context, block *types.Block) (types.Receipts, error)
+}
+
+// DBSource reads blocks and receipts from the canonical chain of a geth database.
+type DBSource struct {
+	DB ethdb.Database
+}
+
+var _ Source = (*DBSource)(nil)
+
+func (s *DBSource) BlockByNumber(ctx context.Context, num uint64) (*types.Block, error) {
+	hash := rawdb.ReadCanonicalHash(s.DB, num)
+	if hash == (common.Hash{}) {
+		return nil, fmt.Errorf("no canonical block %d", num)
+	}
+	block := rawdb.ReadBlock(s.DB, hash, num)
+	if block == nil {
+		return nil, fmt.Errorf("missing block %d %s", num, hash)
+	}
+	return block, nil
+}
+
+func (s *DBSource) Receipts(ctx context.Context, block *types.Block) (types.Receipts, error) {
+	receipts := rawdb.ReadRawReceipts(s.DB, block.Hash(), block.NumberU64())
+	if receipts == nil && len(block.Transactions()) > 0 {
+		return nil, fmt.Errorf("missing receipts of block %d %s", block.NumberU64(), block.Hash())
+	}
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return nil, fmt.Errorf("block %d %s has %d transactions but %d receipts", block.NumberU64(), block.Hash(), len(txs), len(receipts))
+	}
+	// The storage encoding of receipts does not include the receipt type, which is the type of the transaction.
+	for i, r := range receipts {
+		r.Type = txs[i].Type()
+	}
+	return receipts, nil
+}
+
+// RPCSource reads blocks and receipts from the RPC of an execution client.
+type RPCSource struct {
+	Client *ethclient.Client
+}
+
+var _ Source = (*RPCSource)(nil)
+
+func (s *RPCSource) BlockByNumber(ctx context.Context, num uint64) (*types.Block, error) {
+	return s.Client.BlockByNumber(ctx, new(big.Int).SetUint64(num))
+}
+
+func (s *RPCSource) Receipts(ctx context.Context, block *types.Block) (types.Receipts, error) {
+	return s.Client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+}
+
+// Export writes the blocks from start to end (inclusive) of the source to the writer.
+// Every block is verified against its receipts and transactions, and to be the child of the previous block.
+func Export(ctx context.Context, lgr log.Logger, src Source, start, end uint64, w io.Writer) error {
+	if start > end {
+		return fmt.Errorf("invalid range: start %d is after end %d", start, end)
+	}
+	var parent common.Hash
+	for num := start; num <= end; num++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block, err := src.BlockByNumber(ctx, num)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", num, err)
+		}
+		if num > start && block.ParentHash() != parent {
+			return fmt.Errorf("block %d %s does not build on exported parent %s", num, block.Hash(), parent)
+		}
+		receipts, err := src.Receipts(ctx, block)
+		if err != nil {
+			return fmt.Errorf("failed to get receipts of block %d: %w", num, err)
+		}
+		entry, err := NewEntry(block, receipts)
+		if err != nil {
+			return err
+		}
+		if err := entry.Verify(); err != nil {
+			return err
+		}
+		if err := rlp.Encode(w, entry); err != nil {
+			return fmt.Errorf("failed to write block %d: %w", num, err)
+		}
+		parent = block.Hash()
+		if num%1000 == 0 {
+			lgr.Info("Exported block", "block", eth.ToBlockID(block), "end", end)
+		}
+	}
+	lgr.Info("Exported blocks", "start", start, "end", end, "last", parent)
+	return nil
+}
+
+// Import reads an export from the reader, and inserts the blocks into the blockchain, in batches of the given size.
+// The blocks are executed: the resulting state and receipts are verified against the block headers,
+// and the exported receipts are verified against the headers as well.
+// The first block must build on a block of the chain, and after import the blocks must be canonical.
+func Import(ctx context.Context, lgr log.Logger, bc *core.BlockChain, r io.Reader, batchSize int) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d", batchSize)
+	}
+	stream := rlp.NewStream(r, 0)
+	var batch types.Blocks
+	var last eth.BlockID
+	count := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		first := batch[0]
+		if parent := bc.GetHeaderByHash(first.ParentHash()); parent == nil {
+			return fmt.Errorf("parent %s of block %d is unknown", first.ParentHash(), first.NumberU64())
+		}
+		if n, err := bc.InsertChain(batch); err != nil {
+			return fmt.Errorf("failed to insert block %d: %w", batch[n].NumberU64(), err)
+		}
+		for _, block := range batch {
+			if canonical := bc.GetCanonicalHash(block.NumberU64()); canonical != block.Hash() {
+				return fmt.Errorf("imported block %d %s is not canonical, have %s", block.NumberU64(), block.Hash(), canonical)
+			}
+		}
+		last = eth.ToBlockID(batch[len(batch)-1])
+		count += len(batch)
+		lgr.Info("Imported blocks", "count", count, "head", last)
+		batch = nil
+		return nil
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var entry Entry
+		if err := stream.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read entry %d: %w", count+len(batch), err)
+		}
+		if err := entry.Verify(); err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			prev := batch[len(batch)-1]
+			if entry.Block.NumberU64() != prev.NumberU64()+1 || entry.Block.ParentHash() != prev.Hash() {
+				return fmt.Errorf("block %d %s does not build on previous block %d %s",
+					entry.Block.NumberU64(), entry.Block.Hash(), prev.NumberU64(), prev.Hash())
+			}
+		} else if last != (eth.BlockID{}) && entry.Block.ParentHash() != last.Hash {
+			return fmt.Errorf("block %d %s does not build on previous block %s", entry.Block.NumberU64(), entry.Block.Hash(), last)
+		}
+		batch = append(batch, entry.Block)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("no blocks to import")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package chaindata
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+func testChainConfig() *params.ChainConfig {
+	zero := uint64(0)
+	canyonDenominator := uint64(250)
+	return &params.ChainConfig{
+		ChainID:                       big.NewInt(901),
+		HomesteadBlock:                big.NewInt(0),
+		EIP150Block:                   big.NewInt(0),
+		EIP155Block:                   big.NewInt(0),
+		EIP158Block:                   big.NewInt(0),
+		ByzantiumBlock:                big.NewInt(0),
+		ConstantinopleBlock:           big.NewInt(0),
+		PetersburgBlock:               big.NewInt(0),
+		IstanbulBlock:                 big.NewInt(0),
+		MuirGlacierBlock:              big.NewInt(0),
+		BerlinBlock:                   big.NewInt(0),
+		LondonBlock:                   big.NewInt(0),
+		ArrowGlacierBlock:             big.NewInt(0),
+		GrayGlacierBlock:              big.NewInt(0),
+		MergeNetsplitBlock:            big.NewInt(0),
+		TerminalTotalDifficulty:       big.NewInt(0),
+		TerminalTotalDifficultyPassed: true,
+		ShanghaiTime:                  &zero,
+		BedrockBlock:                  big.NewInt(0),
+		RegolithTime:                  &zero,
+		CanyonTime:                    &zero,
+		Optimism:                      &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50, EIP1559DenominatorCanyon: &canyonDenominator},
+	}
+}
+
+// TestExportImport exports a chain with deposit and dynamic-fee receipts, and imports it into a fresh chain.
+func TestExportImport(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	genesis := &core.Genesis{
+		Config:   testChainConfig(),
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+		GasLimit: 30_000_000,
+		Alloc: types.GenesisAlloc{
+			sender: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	signer := types.LatestSigner(genesis.Config)
+	rng := rand.New(rand.NewSource(1234))
+	rollupCfg := &rollup.Config{RegolithTime: genesis.Config.RegolithTime, CanyonTime: genesis.Config.CanyonTime}
+	engine := beacon.New(ethash.NewFaker())
+	db, blocks, receipts := core.GenerateChainWithGenesis(genesis, engine, 3, func(i int, gen *core.BlockGen) {
+		// every block starts with the L1 info deposit, which the L1 cost of the other transactions is derived from
+		deposit, err := derive.L1InfoDeposit(rollupCfg, eth.SystemConfig{}, uint64(i), testutils.RandomBlockInfo(rng), gen.Timestamp())
+		require.NoError(t, err)
+		gen.AddTx(types.NewTx(deposit))
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   genesis.Config.ChainID,
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(10 * params.GWei),
+			Gas:       21_000,
+			To:        &common.Address{0xbb},
+			Value:     big.NewInt(1),
+		})
+		require.NoError(t, err)
+		gen.AddTx(tx)
+	})
+	for _, blockReceipts := range receipts {
+		require.Len(t, blockReceipts, 2)
+		require.Equal(t, uint8(types.DepositTxType), blockReceipts[0].Type)
+		require.NotNil(t, blockReceipts[0].DepositNonce)
+		require.Equal(t, uint8(types.DynamicFeeTxType), blockReceipts[1].Type)
+	}
+
+	// write the generated chain to the source database, as the blocks are only generated on top of the genesis
+	for i, block := range blocks {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	}
+
+	logger := testlog.Logger(t, log.LevelInfo)
+	var buf bytes.Buffer
+	require.NoError(t, Export(context.Background(), logger, &DBSource{DB: db}, 1, uint64(len(blocks)), &buf))
+
+	// the exported receipts keep their types
+	stream := rlp.NewStream(bytes.NewReader(buf.Bytes()), 0)
+	for i := range blocks {
+		var entry Entry
+		require.NoError(t, stream.Decode(&entry))
+		decoded, err := entry.DecodeReceipts()
+		require.NoError(t, err)
+		require.Len(t, decoded, 2)
+		require.Equal(t, receipts[i][0].Type, decoded[0].Type)
+		require.Equal(t, *receipts[i][0].DepositNonce, *decoded[0].DepositNonce)
+		require.Equal(t, receipts[i][1].Type, decoded[1].Type)
+	}
+
+	bc, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, engine, vm.Config{}, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(bc.Stop)
+	require.NoError(t, Import(context.Background(), logger, bc, &buf, 2))
+	require.Equal(t, blocks[len(blocks)-1].Hash(), bc.CurrentBlock().Hash())
+}
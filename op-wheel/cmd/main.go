@@ -32,13 +32,14 @@ func main() {
 		return nil
 	}
 	app.Action = func(c *cli.Context) error {
-		return errors.New("see 'cheat' and 'engine' subcommands and --help")
+		return errors.New("see 'cheat', 'engine' and 'chaindata' subcommands and --help")
 	}
 	app.Writer = os.Stdout
 	app.ErrWriter = os.Stderr
 	app.Commands = []*cli.Command{
 		wheel.CheatCmd,
 		wheel.EngineCmd,
+		wheel.ChainDataCmd,
 	}
 
 	err := app.Run(os.Args)
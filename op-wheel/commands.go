@@ -1,6 +1,7 @@
 package wheel
 
 import (
+	"bufio"
 	"context"
 	"encoding"
 	"encoding/json"
@@ -17,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
@@ -29,6 +31,7 @@ import (
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
+	"github.com/ethereum-optimism/optimism/op-wheel/chaindata"
 	"github.com/ethereum-optimism/optimism/op-wheel/cheat"
 	"github.com/ethereum-optimism/optimism/op-wheel/engine"
 )
@@ -667,6 +670,50 @@ var (
 		}),
 	}
 
+	EngineRollbackCmd = &cli.Command{
+		Name: "rollback",
+		Description: "Roll back both the execution client and the op-node to a block by number (destructive!). " +
+			"A running sequencer is stopped first. The block hashes are verified to match before and after the rollback.",
+		Flags: withEngineFlags(
+			&cli.Uint64Flag{
+				Name:     "to",
+				Usage:    "Block number to roll back the chain to",
+				Required: true,
+				EnvVars:  prefixEnvVars("ROLLBACK_TO"),
+			},
+			&cli.StringFlag{
+				Name:     "rollup-rpc",
+				Usage:    "op-node RPC endpoint, with the admin API enabled",
+				Required: true,
+				EnvVars:  prefixEnvVars("ROLLUP_RPC"),
+			},
+			&cli.BoolFlag{
+				Name:    "set-head",
+				Usage:   "Whether to also call debug_setHead when rolling back",
+				EnvVars: prefixEnvVars("ROLLBACK_SET_HEAD"),
+			},
+			&cli.DurationFlag{
+				Name:    "verify-timeout",
+				Usage:   "How long to wait for the op-node to report the rolled back head",
+				Value:   time.Minute,
+				EnvVars: prefixEnvVars("ROLLBACK_VERIFY_TIMEOUT"),
+			},
+		),
+		Action: EngineAction(func(ctx *cli.Context, eng *sources.EngineAPIClient, lgr log.Logger) error {
+			open, err := initOpenEngineRPC(ctx, lgr)
+			if err != nil {
+				return fmt.Errorf("failed to dial open RPC endpoint: %w", err)
+			}
+			rollupRPC, err := client.NewRPC(ctx.Context, lgr, ctx.String("rollup-rpc"))
+			if err != nil {
+				return fmt.Errorf("failed to dial rollup RPC endpoint: %w", err)
+			}
+			node := sources.NewRollupClient(rollupRPC)
+			defer node.Close()
+			return engine.Rollback(ctx.Context, lgr, eng, open, node, ctx.Uint64("to"), ctx.Bool("set-head"), ctx.Duration("verify-timeout"))
+		}),
+	}
+
 	EngineJSONCmd = &cli.Command{
 		Name:        "json",
 		Description: "read json values from remaining args, or STDIN, and use them as RPC params to call the engine RPC method (first arg)",
@@ -695,6 +742,122 @@ var (
 	}
 )
 
+var (
+	ChainDataExportCmd = &cli.Command{
+		Name: "export",
+		Description: "Export a range of blocks, with their receipts, from a Geth database or RPC to a file. " +
+			"The transactions and receipts of each block are verified against the block header.",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:      "data-dir",
+				Usage:     "Geth data dir location to export from. Mutually exclusive with rpc.",
+				TakesFile: true,
+				EnvVars:   prefixEnvVars("EXPORT_DATA_DIR"),
+			},
+			&cli.StringFlag{
+				Name:    "rpc",
+				Usage:   "Execution client RPC endpoint to export from. Mutually exclusive with data-dir.",
+				EnvVars: prefixEnvVars("EXPORT_RPC"),
+			},
+			&cli.Uint64Flag{
+				Name:     "start",
+				Usage:    "First block number to export",
+				Required: true,
+				EnvVars:  prefixEnvVars("EXPORT_START"),
+			},
+			&cli.Uint64Flag{
+				Name:     "end",
+				Usage:    "Last block number to export (inclusive)",
+				Required: true,
+				EnvVars:  prefixEnvVars("EXPORT_END"),
+			},
+			&cli.PathFlag{
+				Name:     "out",
+				Usage:    "File to write the exported blocks to",
+				Required: true,
+				EnvVars:  prefixEnvVars("EXPORT_OUT"),
+			},
+		}, oplog.CLIFlags(envVarPrefix)...),
+		Action: func(ctx *cli.Context) error {
+			lgr := initLogger(ctx)
+			var src chaindata.Source
+			switch dataDir, rpcURL := ctx.String("data-dir"), ctx.String("rpc"); {
+			case dataDir != "" && rpcURL != "":
+				return errors.New("only one of data-dir and rpc may be set")
+			case dataDir != "":
+				db, err := cheat.OpenGethRawDB(dataDir, true)
+				if err != nil {
+					return fmt.Errorf("failed to open raw geth db: %w", err)
+				}
+				defer db.Close()
+				src = &chaindata.DBSource{DB: db}
+			case rpcURL != "":
+				cl, err := ethclient.DialContext(ctx.Context, rpcURL)
+				if err != nil {
+					return fmt.Errorf("failed to dial RPC endpoint %q: %w", rpcURL, err)
+				}
+				defer cl.Close()
+				src = &chaindata.RPCSource{Client: cl}
+			default:
+				return errors.New("either data-dir or rpc must be set")
+			}
+			f, err := os.OpenFile(ctx.Path("out"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+			if err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			defer f.Close()
+			if err := chaindata.Export(ctx.Context, lgr, src, ctx.Uint64("start"), ctx.Uint64("end"), f); err != nil {
+				return err
+			}
+			return f.Sync()
+		},
+	}
+	ChainDataImportCmd = &cli.Command{
+		Name: "import",
+		Description: "Import blocks exported with the export command into a Geth database. " +
+			"The first block must build on a block of the database. The blocks are executed, " +
+			"and their state and receipts, as well as the exported receipts, are verified against the block headers.",
+		Flags: append([]cli.Flag{
+			DataDirFlag,
+			&cli.PathFlag{
+				Name:     "in",
+				Usage:    "File to read the exported blocks from",
+				Required: true,
+				EnvVars:  prefixEnvVars("IMPORT_IN"),
+			},
+			&cli.IntFlag{
+				Name:    "batch-size",
+				Usage:   "Number of blocks to insert at a time",
+				Value:   1000,
+				EnvVars: prefixEnvVars("IMPORT_BATCH_SIZE"),
+			},
+		}, oplog.CLIFlags(envVarPrefix)...),
+		Action: CheatAction(false, func(ctx *cli.Context, ch *cheat.Cheater) error {
+			lgr := initLogger(ctx)
+			defer ch.Close()
+			// stop the blockchain before closing the db, to flush the imported state to disk
+			defer ch.Blockchain.Stop()
+			f, err := os.Open(ctx.Path("in"))
+			if err != nil {
+				return fmt.Errorf("failed to open export file: %w", err)
+			}
+			defer f.Close()
+			return chaindata.Import(ctx.Context, lgr, ch.Blockchain, bufio.NewReader(f), ctx.Int("batch-size"))
+		}),
+	}
+)
+
+var ChainDataCmd = &cli.Command{
+	Name:  "chaindata",
+	Usage: "Commands to export and import chain data between Geth databases.",
+	Description: "Blocks are exported with their receipts from a database or RPC into a file, " +
+		"and imported from that file into another database, to quickly bring up a replica node.",
+	Subcommands: []*cli.Command{
+		ChainDataExportCmd,
+		ChainDataImportCmd,
+	},
+}
+
 var CheatCmd = &cli.Command{
 	Name:  "cheat",
 	Usage: "Cheating commands to modify a Geth database.",
@@ -723,6 +886,7 @@ var EngineCmd = &cli.Command{
 		EngineSetForkchoiceCmd,
 		EngineSetForkchoiceHashCmd,
 		EngineRewindCmd,
+		EngineRollbackCmd,
 		EngineJSONCmd,
 	},
 }
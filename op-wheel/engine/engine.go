@@ -431,6 +431,132 @@ func Rewind(ctx context.Context, lgr log.Logger, client *sources.EngineAPIClient
 	return SetForkchoiceByHash(ctx, client, toFinalized.Hash, toSafe.Hash, toUnsafe.Hash)
 }
 
+// RollupNode is the subset of the op-node RPC used to roll it back together with the execution client.
+type RollupNode interface {
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	SequencerActive(ctx context.Context) (bool, error)
+	StopSequencer(ctx context.Context) (common.Hash, error)
+	ResetDerivationPipeline(ctx context.Context) error
+}
+
+// Rollback rewinds the execution client to the given block, like Rewind, and resets the op-node to it.
+// A running sequencer is stopped first, so the chain doesn't advance while it is rolled back, and left stopped.
+// The execution client and the op-node must agree on the block hash before the rollback. The op-node may continue
+// deriving past the target after the reset, so the rollback is verified once both report an unsafe head at or past
+// the target that builds on the target block.
+func Rollback(ctx context.Context, lgr log.Logger, client *sources.EngineAPIClient, open client.RPC, node RollupNode,
+	to uint64, setHead bool, verifyTimeout time.Duration) error {
+	active, err := node.SequencerActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get sequencer status: %w", err)
+	}
+	if active {
+		stoppedAt, err := node.StopSequencer(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to stop sequencer: %w", err)
+		}
+		lgr.Info("Stopped sequencer", "head", stoppedAt)
+	}
+
+	target, err := canonicalHeader(ctx, open, to)
+	if err != nil {
+		return err
+	}
+	if err := checkRollupNodeBlock(ctx, node, to, target.Hash()); err != nil {
+		return err
+	}
+
+	if err := Rewind(ctx, lgr, client, open, to, setHead); err != nil {
+		return err
+	}
+	if err := node.ResetDerivationPipeline(ctx); err != nil {
+		return fmt.Errorf("failed to reset op-node derivation pipeline: %w", err)
+	}
+
+	latest, err := getHeader(ctx, open, methodEthGetBlockByNumber, "latest")
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if latest == nil || latest.Number.Uint64() < to {
+		return fmt.Errorf("execution client head is behind rollback target %d", to)
+	}
+	if rolledBack, err := canonicalHeader(ctx, open, to); err != nil {
+		return err
+	} else if rolledBack.Hash() != target.Hash() {
+		return fmt.Errorf("execution client block %d %s does not match rollback target %s", to, rolledBack.Hash(), target.Hash())
+	}
+
+	// the op-node resets asynchronously, so wait for it to report an unsafe head that is canonical in the rolled back
+	// execution client, which excludes unsafe heads from before the rollback
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if err := checkRolledBack(ctx, open, node, to, target.Hash()); err != nil {
+			lgr.Info("Waiting for op-node reset", "target", eth.HeaderBlockID(target), "err", err)
+		} else {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("op-node did not reset to rollback target %s: %w", target.Hash(), ctx.Err())
+		}
+	}
+
+	lgr.Info("Rolled back execution client and op-node", "head", eth.HeaderBlockID(target))
+	if active {
+		lgr.Warn("Sequencer was stopped and needs to be restarted with admin_startSequencer", "head", target.Hash())
+	}
+	return nil
+}
+
+// checkRolledBack returns an error unless the op-node reports an unsafe head at or past the target block that is
+// canonical in the execution client, and the op-node block at the target number is the target block.
+func checkRolledBack(ctx context.Context, open client.RPC, node RollupNode, to uint64, target common.Hash) error {
+	status, err := node.SyncStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get op-node sync status: %w", err)
+	}
+	if status.UnsafeL2.Number < to {
+		return fmt.Errorf("op-node unsafe head %s is behind rollback target", status.UnsafeL2)
+	}
+	canonical, err := canonicalHeader(ctx, open, status.UnsafeL2.Number)
+	if err != nil {
+		return err
+	}
+	if canonical.Hash() != status.UnsafeL2.Hash {
+		return fmt.Errorf("op-node unsafe head %s is not canonical in the execution client", status.UnsafeL2)
+	}
+	return checkRollupNodeBlock(ctx, node, to, target)
+}
+
+// checkRollupNodeBlock returns an error unless the op-node block at the given number has the expected hash.
+func checkRollupNodeBlock(ctx context.Context, node RollupNode, num uint64, expected common.Hash) error {
+	output, err := node.OutputAtBlock(ctx, num)
+	if err != nil {
+		return fmt.Errorf("failed to get op-node block %d: %w", num, err)
+	}
+	if output.BlockRef.Hash != expected {
+		return fmt.Errorf("op-node block %d %s does not match execution client block %s", num, output.BlockRef.Hash, expected)
+	}
+	return nil
+}
+
+// canonicalHeader returns the header of the execution client's canonical block at the given number.
+func canonicalHeader(ctx context.Context, open client.RPC, num uint64) (*types.Header, error) {
+	header, err := getHeader(ctx, open, methodEthGetBlockByNumber, hexutil.Uint64(num).String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header %d: %w", num, err)
+	}
+	if header == nil {
+		return nil, fmt.Errorf("execution client has no block %d", num)
+	}
+	return header, nil
+}
+
 func RawJSONInteraction(ctx context.Context, client client.RPC, method string, args []string, input io.Reader, output io.Writer) error {
 	var params []any
 	if input != nil {
@@ -0,0 +1,223 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*fakeEL, *stubRollupNode, *sources.EngineAPIClient) {
+		el := newFakeEL(10, 1)
+		node := &stubRollupNode{el: el, sequencerActive: true, chain: el.canonical, unsafe: el.tip()}
+		client := sources.NewEngineAPIClient(el, testlog.Logger(t, log.LevelInfo), StaticVersionProvider(3))
+		return el, node, client
+	}
+
+	t.Run("RollsBack", func(t *testing.T) {
+		el, node, client := setup(t)
+		target := el.canonical[7].Hash()
+		require.NoError(t, Rollback(ctx, testlog.Logger(t, log.LevelInfo), client, el, node, 7, true, time.Second))
+		require.Equal(t, target, el.tip().Hash)
+		require.True(t, node.reset)
+		require.False(t, node.sequencerActive)
+		require.Equal(t, []string{"sequencerActive", "stopSequencer"}, el.calls[:2], "should stop the sequencer first")
+	})
+
+	t.Run("DerivationAdvancesPastTarget", func(t *testing.T) {
+		el, node, client := setup(t)
+		node.sequencerActive = false
+		target := el.canonical[7].Hash()
+		node.onReset = func() {
+			el.extend(2)
+		}
+		require.NoError(t, Rollback(ctx, testlog.Logger(t, log.LevelInfo), client, el, node, 7, false, time.Second))
+		require.Equal(t, uint64(9), el.tip().Number)
+		require.Equal(t, target, el.canonical[7].Hash())
+		require.NotContains(t, el.calls, "stopSequencer")
+	})
+
+	t.Run("TargetMismatch", func(t *testing.T) {
+		el, node, client := setup(t)
+		node.chain = newFakeEL(10, 2).canonical
+		err := Rollback(ctx, testlog.Logger(t, log.LevelInfo), client, el, node, 7, false, time.Second)
+		require.ErrorContains(t, err, "does not match execution client block")
+		require.Equal(t, uint64(10), el.tip().Number, "should not roll back")
+		require.False(t, node.reset)
+	})
+
+	t.Run("UnknownTarget", func(t *testing.T) {
+		el, node, client := setup(t)
+		err := Rollback(ctx, testlog.Logger(t, log.LevelInfo), client, el, node, 20, false, time.Second)
+		require.ErrorContains(t, err, "no block 20")
+		require.False(t, node.reset)
+	})
+
+	t.Run("StaleUnsafeHead", func(t *testing.T) {
+		el, node, client := setup(t)
+		node.ignoreReset = true
+		err := Rollback(ctx, testlog.Logger(t, log.LevelInfo), client, el, node, 7, false, 10*time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// fakeEL is an execution client with a single canonical chain, rewound by forkchoice updates.
+type fakeEL struct {
+	canonical []*types.Header
+	calls     []string
+	forks     int64
+}
+
+// newFakeEL creates a chain of length blocks after genesis. Chains created with a different seed differ after genesis.
+func newFakeEL(length int, seed int64) *fakeEL {
+	el := &fakeEL{forks: seed * 1000}
+	el.canonical = []*types.Header{{Number: big.NewInt(0)}}
+	el.extend(length)
+	return el
+}
+
+// extend adds blocks to the canonical chain, differing from any previously created blocks.
+func (e *fakeEL) extend(n int) {
+	e.forks++
+	for i := 0; i < n; i++ {
+		parent := e.canonical[len(e.canonical)-1]
+		e.canonical = append(e.canonical, &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Extra:      big.NewInt(e.forks).Bytes(),
+		})
+	}
+}
+
+func (e *fakeEL) tip() eth.L2BlockRef {
+	return headerRef(e.canonical[len(e.canonical)-1])
+}
+
+func (e *fakeEL) Close() {}
+
+func (e *fakeEL) CallContext(_ context.Context, result any, method string, args ...any) error {
+	e.calls = append(e.calls, method)
+	switch method {
+	case methodEthGetBlockByNumber:
+		var header *types.Header
+		switch tag := args[0].(string); tag {
+		case "latest":
+			header = e.canonical[len(e.canonical)-1]
+		case "safe", "finalized":
+			header = e.canonical[0]
+		default:
+			num, err := hexutil.DecodeUint64(tag)
+			if err != nil {
+				return err
+			}
+			if num < uint64(len(e.canonical)) {
+				header = e.canonical[num]
+			}
+		}
+		*result.(**types.Header) = header
+		return nil
+	case methodDebugSetHead:
+		return e.setHead(uint64(args[0].(hexutil.Uint64)))
+	case string(eth.FCUV3):
+		state := args[0].(*eth.ForkchoiceState)
+		for _, header := range e.canonical {
+			if header.Hash() == state.HeadBlockHash {
+				if err := e.setHead(header.Number.Uint64()); err != nil {
+					return err
+				}
+				*result.(*eth.ForkchoiceUpdatedResult) = eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}}
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown head %s", state.HeadBlockHash)
+	default:
+		return fmt.Errorf("unexpected method %s", method)
+	}
+}
+
+func (e *fakeEL) setHead(num uint64) error {
+	if num >= uint64(len(e.canonical)) {
+		return fmt.Errorf("unknown block %d", num)
+	}
+	e.canonical = slices.Clone(e.canonical[:num+1])
+	return nil
+}
+
+func (e *fakeEL) BatchCallContext(_ context.Context, _ []rpc.BatchElem) error {
+	return errors.New("not supported")
+}
+
+func (e *fakeEL) EthSubscribe(_ context.Context, _ any, _ ...any) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+// stubRollupNode is an op-node that follows the chain of the execution client when reset.
+type stubRollupNode struct {
+	el              *fakeEL
+	sequencerActive bool
+	chain           []*types.Header
+	unsafe          eth.L2BlockRef
+	reset           bool
+	// ignoreReset keeps reporting the unsafe head from before the reset
+	ignoreReset bool
+	// onReset is called when the node is reset, before it follows the chain of the execution client
+	onReset func()
+}
+
+var _ RollupNode = (*stubRollupNode)(nil)
+
+func (n *stubRollupNode) OutputAtBlock(_ context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	if blockNum >= uint64(len(n.chain)) {
+		return nil, fmt.Errorf("unknown block %d", blockNum)
+	}
+	return &eth.OutputResponse{BlockRef: headerRef(n.chain[blockNum])}, nil
+}
+
+func (n *stubRollupNode) SyncStatus(_ context.Context) (*eth.SyncStatus, error) {
+	return &eth.SyncStatus{UnsafeL2: n.unsafe}, nil
+}
+
+func (n *stubRollupNode) SequencerActive(_ context.Context) (bool, error) {
+	n.el.calls = append(n.el.calls, "sequencerActive")
+	return n.sequencerActive, nil
+}
+
+func (n *stubRollupNode) StopSequencer(_ context.Context) (common.Hash, error) {
+	n.el.calls = append(n.el.calls, "stopSequencer")
+	n.sequencerActive = false
+	return n.unsafe.Hash, nil
+}
+
+func (n *stubRollupNode) ResetDerivationPipeline(_ context.Context) error {
+	n.reset = true
+	if n.ignoreReset {
+		return nil
+	}
+	if n.onReset != nil {
+		n.onReset()
+	}
+	n.chain = n.el.canonical
+	n.unsafe = n.el.tip()
+	return nil
+}
+
+func headerRef(header *types.Header) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: header.Hash(), Number: header.Number.Uint64(), ParentHash: header.ParentHash}
+}